@@ -0,0 +1,25 @@
+// Command openapi writes the API's OpenAPI document to a file. It is run by
+// go generate in internal/handler.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/auction-cards/backend/internal/handler"
+)
+
+func main() {
+	out := flag.String("o", "api/openapi.json", "output file")
+	flag.Parse()
+
+	spec, err := handler.OpenAPISpecJSON()
+	if err != nil {
+		log.Fatalf("Failed to build OpenAPI spec: %v", err)
+	}
+
+	if err := os.WriteFile(*out, spec, 0o644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *out, err)
+	}
+}
@@ -11,16 +11,27 @@ import (
 
 	"github.com/auction-cards/backend/internal/cache"
 	"github.com/auction-cards/backend/internal/config"
+	"github.com/auction-cards/backend/internal/domain"
 	"github.com/auction-cards/backend/internal/handler"
 	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/pkg/breaker"
 	"github.com/auction-cards/backend/internal/pkg/email"
+	"github.com/auction-cards/backend/internal/pkg/errorrate"
+	"github.com/auction-cards/backend/internal/pkg/grading"
 	"github.com/auction-cards/backend/internal/pkg/jwt"
+	"github.com/auction-cards/backend/internal/pkg/origin"
+	"github.com/auction-cards/backend/internal/pkg/sharelink"
 	"github.com/auction-cards/backend/internal/pkg/storage"
+	"github.com/auction-cards/backend/internal/pkg/unsubscribe"
+	"github.com/auction-cards/backend/internal/pkg/webhook"
+	"github.com/auction-cards/backend/internal/pkg/webpush"
+	"github.com/auction-cards/backend/internal/repository"
 	"github.com/auction-cards/backend/internal/repository/postgres"
 	"github.com/auction-cards/backend/internal/service"
 	"github.com/auction-cards/backend/internal/websocket"
 	"github.com/go-chi/chi/v5"
 	"github.com/joho/godotenv"
+	"github.com/shopspring/decimal"
 )
 
 func init() {
@@ -33,6 +44,12 @@ func init() {
 func main() {
 	// Load configuration
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	domain.FeeRounding = cfg.Money.FeeRounding
+	// Checked by Validate
+	domain.BidIncrementLadder, _ = domain.ParseIncrementLadder(cfg.Money.BidIncrementLadder)
 
 	// Connect to PostgreSQL
 	db, err := postgres.NewDB(cfg.Database.DSN())
@@ -42,13 +59,19 @@ func main() {
 	defer db.Close()
 	log.Println("Connected to PostgreSQL")
 
+	// Circuit breakers, so requests fail fast while a dependency is down
+	redisBreaker := breaker.New("redis", cfg.Breakers.FailureThreshold, cfg.Breakers.Cooldown)
+	s3Breaker := breaker.New("s3", cfg.Breakers.FailureThreshold, cfg.Breakers.Cooldown)
+	emailBreaker := breaker.New("email", cfg.Breakers.FailureThreshold, cfg.Breakers.Cooldown)
+
 	// Connect to Redis
-	redisCache, err := cache.NewRedisCache(cfg.Redis.Addr(), cfg.Redis.Password, cfg.Redis.DB)
+	redisCache, err := cache.NewRedisCache(cfg.Redis.Addr(), cfg.Redis.Password, cfg.Redis.DB, cfg.Breakers.RedisTimeout)
 	if err != nil {
 		log.Printf("Warning: Failed to connect to Redis: %v", err)
 		redisCache = nil
 	} else {
 		defer redisCache.Close()
+		redisCache.UseBreaker(redisBreaker)
 		log.Println("Connected to Redis")
 	}
 
@@ -58,8 +81,11 @@ func main() {
 		AccessKeyID:     cfg.S3.AccessKeyID,
 		SecretAccessKey: cfg.S3.SecretAccessKey,
 		BucketName:      cfg.S3.BucketName,
+		Region:          cfg.S3.Region,
 		UseSSL:          cfg.S3.UseSSL,
 		PublicURL:       cfg.S3.PublicURL,
+		Timeout:         cfg.Breakers.S3Timeout,
+		Breaker:         s3Breaker,
 	})
 	if err != nil {
 		log.Printf("Warning: Failed to connect to S3: %v", err)
@@ -68,8 +94,35 @@ func main() {
 		log.Println("Connected to S3 storage")
 	}
 
+	// Archives of personal data go to their own bucket when one is set,
+	// and are unavailable rather than kept in the main bucket when it's down
+	var piiStore storage.ObjectStore
+	if cfg.S3.PIIBucketName == "" {
+		if s3Storage != nil {
+			piiStore = s3Storage
+		}
+	} else {
+		piiStorage, err := storage.NewS3Storage(&storage.Config{
+			Endpoint:        cfg.S3.Endpoint,
+			AccessKeyID:     cfg.S3.AccessKeyID,
+			SecretAccessKey: cfg.S3.SecretAccessKey,
+			BucketName:      cfg.S3.PIIBucketName,
+			Region:          cfg.S3.PIIRegion,
+			Private:         true,
+			UseSSL:          cfg.S3.UseSSL,
+			Timeout:         cfg.Breakers.S3Timeout,
+			Breaker:         s3Breaker,
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to connect to the PII bucket: %v", err)
+		} else {
+			piiStore = piiStorage
+			log.Println("Connected to the PII bucket")
+		}
+	}
+
 	// Initialize email sender (mock for development)
-	emailSender := email.NewMockSender()
+	emailSender := email.NewGuardedSender(email.NewMockSender(), cfg.Breakers.EmailTimeout, emailBreaker)
 
 	// Initialize JWT manager
 	jwtManager := jwt.NewManager(
@@ -83,8 +136,11 @@ func main() {
 	userRepo := postgres.NewUserRepository(db)
 	oauthRepo := postgres.NewOAuthAccountRepository(db)
 	refreshTokenRepo := postgres.NewRefreshTokenRepository(db)
+	userDeviceRepo := postgres.NewUserDeviceRepository(db)
+	loginEventRepo := postgres.NewLoginEventRepository(db)
 	auctionRepo := postgres.NewAuctionRepository(db)
 	auctionImageRepo := postgres.NewAuctionImageRepository(db)
+	shippingRepo := postgres.NewAuctionShippingRepository(db)
 	bidRepo := postgres.NewBidRepository(db)
 	categoryRepo := postgres.NewCategoryRepository(db)
 	notificationRepo := postgres.NewNotificationRepository(db)
@@ -92,53 +148,211 @@ func main() {
 	ratingRepo := postgres.NewRatingRepository(db)
 	reportRepo := postgres.NewReportRepository(db)
 	messageRepo := postgres.NewMessageRepository(db)
+	deviceKeyRepo := postgres.NewDeviceKeyRepository(db)
+	moderationRepo := postgres.NewModerationRepository(db)
+	orderRepo := postgres.NewOrderRepository(db)
+	escrowRepo := postgres.NewEscrowRepository(db)
+	ledgerRepo := postgres.NewLedgerRepository(db)
+	payoutRepo := postgres.NewPayoutRepository(db)
+	auctionInviteRepo := postgres.NewAuctionInviteRepository(db)
+	listingShareRepo := postgres.NewListingShareRepository(db)
+	factsheetRepo := postgres.NewAuctionFactsheetRepository(db)
+	recommendationRepo := postgres.NewRecommendationRepository(db)
+	curationRepo := postgres.NewCurationRepository(db)
+	priceDropRepo := postgres.NewPriceDropRepository(db)
+	bidApprovalRepo := postgres.NewBidApprovalRepository(db)
+	liveEventRepo := postgres.NewLiveEventRepository(db)
+	secondChanceOfferRepo := postgres.NewSecondChanceOfferRepository(db)
+	auditLogRepo := postgres.NewAuditLogRepository(db)
+	disputeRepo := postgres.NewDisputeRepository(db)
+	auctionStatsRepo := postgres.NewAuctionStatsRepository(db)
+	snipeRepo := postgres.NewSnipeRepository(db)
+	offerRepo := postgres.NewOfferRepository(db)
+	webhookRepo := postgres.NewWebhookRepository(db)
+	userMergeRepo := postgres.NewUserMergeRepository(db)
+	webhookDeliveryRepo := postgres.NewWebhookDeliveryRepository(db)
+	importJobRepo := postgres.NewImportJobRepository(db)
+	dataExportRepo := postgres.NewDataExportRepository(db)
+	accountDeletionRepo := postgres.NewAccountDeletionRepository(db)
+	allowedOriginRepo := postgres.NewAllowedOriginRepository(db)
+	apiUsageRepo := postgres.NewAPIUsageRepository(db)
+	sellerBidAlertRepo := postgres.NewSellerBidAlertRepository(db)
+	bidEventRepo := postgres.NewBidEventRepository(db)
+	statusIncidentRepo := postgres.NewStatusIncidentRepository(db)
+	maintenanceWindowRepo := postgres.NewMaintenanceWindowRepository(db)
+	fraudRepo := postgres.NewFraudRepository(db)
+	quietHoursRepo := postgres.NewQuietHoursRepository(db)
+	notificationPreferenceRepo := postgres.NewNotificationPreferenceRepository(db)
+	userBlockRepo := postgres.NewUserBlockRepository(db)
+	sellerFollowRepo := postgres.NewSellerFollowRepository(db)
+	pushSubscriptionRepo := postgres.NewPushSubscriptionRepository(db)
+	digestRepo := postgres.NewDigestRepository(db)
+	savedSearchRepo := postgres.NewSavedSearchRepository(db)
 
 	// Initialize services
-	frontendURL := cfg.Server.AllowOrigins[0]
+	frontendURL := cfg.Server.FrontendURL
+
+	notificationQueue := service.NewNotificationQueue(cfg.Notifications.QueueSize, cfg.Notifications.Workers)
+	notificationQueue.Start()
+
+	messageHub := websocket.NewMessageHub(redisCache)
+
+	// Web push is off until a VAPID key pair is configured
+	var pushSender webpush.Sender
+	if cfg.Push.VAPIDPublicKey != "" {
+		sender, err := webpush.NewVAPIDSender(
+			cfg.Push.VAPIDPublicKey,
+			cfg.Push.VAPIDPrivateKey,
+			cfg.Push.Subject,
+			cfg.Push.Timeout,
+			cfg.Push.AllowPrivateEndpoints,
+		)
+		if err != nil {
+			log.Fatalf("Failed to initialize push sender: %v", err)
+		}
+		pushSender = sender
+	}
+	pushService := service.NewPushService(pushSubscriptionRepo, pushSender, cfg.Push.MaxPerUser)
 
+	notificationService := service.NewNotificationService(
+		notificationRepo,
+		userRepo,
+		watchlistRepo,
+		sellerBidAlertRepo,
+		quietHoursRepo,
+		notificationPreferenceRepo,
+		digestRepo,
+		emailSender,
+		notificationQueue,
+		messageHub,
+		pushService,
+		unsubscribe.NewSigner(cfg.Notifications.UnsubscribeSecret),
+		frontendURL,
+	)
+
+	webhookService := service.NewWebhookService(
+		webhookRepo,
+		webhookDeliveryRepo,
+		webhook.NewClient(cfg.Webhooks.Timeout, cfg.Webhooks.AllowPrivateTargets),
+		cfg.Webhooks.MaxAttempts,
+		cfg.Webhooks.MaxPerUser,
+	)
+
+	singleSessionRoles := make([]domain.UserRole, 0, len(cfg.Sessions.SingleSessionRoles))
+	for _, role := range cfg.Sessions.SingleSessionRoles {
+		singleSessionRoles = append(singleSessionRoles, domain.UserRole(role))
+	}
 	authService := service.NewAuthService(
 		userRepo,
 		oauthRepo,
 		refreshTokenRepo,
+		userDeviceRepo,
+		loginEventRepo,
+		auditLogRepo,
 		jwtManager,
 		emailSender,
+		notificationService,
+		redisCache,
+		service.SessionPolicy{
+			SingleSessionRoles: singleSessionRoles,
+			CountryHopWindow:   cfg.Sessions.CountryHopWindow,
+		},
 		frontendURL,
 	)
 
-	notificationService := service.NewNotificationService(
-		notificationRepo,
+	certificationService := service.NewCertificationService(
+		auctionRepo,
+		newGradingRegistry(&cfg.Grading),
+		redisCache,
+		cfg.Grading.CacheTTL,
+	)
+
+	auctionInviteService := service.NewAuctionInviteService(
+		auctionRepo,
+		auctionInviteRepo,
 		userRepo,
-		watchlistRepo,
-		emailSender,
+		notificationService,
+		sharelink.NewSigner(cfg.ShareLinks.Secret),
+		redisCache,
+		cfg.ShareLinks.TTL,
 		frontendURL,
 	)
 
+	bidApprovalService := service.NewBidApprovalService(bidApprovalRepo, auctionRepo, userRepo, ratingRepo, notificationService)
+
 	auctionService := service.NewAuctionService(
 		auctionRepo,
 		auctionImageRepo,
+		shippingRepo,
 		categoryRepo,
+		auctionStatsRepo,
+		watchlistRepo,
+		bidRepo,
+		db,
 		s3Storage,
+		redisCache,
+		certificationService,
+		auctionInviteService,
+		bidApprovalService,
 	)
 
-	bidService := service.NewBidService(
-		bidRepo,
-		auctionRepo,
-		nil, // bid transaction not needed with simpler implementation
+	listingShareService := service.NewListingShareService(listingShareRepo, auctionService, frontendURL)
+	recommendationService := service.NewRecommendationService(recommendationRepo, auctionService, redisCache)
+	curationService := service.NewCurationService(curationRepo, auctionRepo, auditLogRepo, db, auctionService, redisCache)
+	priceDropService := service.NewPriceDropService(priceDropRepo, auctionRepo, auctionService, notificationService, db)
+
+	ledgerService := service.NewLedgerService(
+		escrowRepo,
+		ledgerRepo,
+		orderRepo,
+		auditLogRepo,
+		db,
 		notificationService,
-		redisCache,
+		cfg.Orders.EscrowHoldPeriod,
 	)
 
-	userService := service.NewUserService(
-		userRepo,
-		watchlistRepo,
-		ratingRepo,
-		auctionRepo,
+	payoutService, err := service.NewPayoutService(
+		payoutRepo,
+		ledgerService,
+		auditLogRepo,
+		db,
+		notificationService,
+		cfg.Payouts.EncryptionKey,
+		decimal.NewFromInt(int64(cfg.Payouts.MinAmount)),
 	)
+	if err != nil {
+		log.Fatalf("Failed to initialize payout service: %v", err)
+	}
 
-	schedulerService := service.NewSchedulerService(
+	orderService := service.NewOrderService(
+		orderRepo,
+		secondChanceOfferRepo,
 		auctionRepo,
+		auctionImageRepo,
+		shippingRepo,
+		bidRepo,
+		db,
+		notificationService,
+		webhookService,
+		ledgerService,
+		redisCache,
+		cfg.Orders.PaymentWindow,
+		cfg.Orders.SecondChanceWindow,
+	)
+
+	bidService := service.NewBidService(
 		bidRepo,
+		auctionRepo,
+		userRepo,
+		userBlockRepo,
+		auditLogRepo,
+		bidEventRepo,
+		db,
 		notificationService,
+		webhookService,
+		orderService,
+		auctionInviteService,
+		bidApprovalService,
 		redisCache,
 	)
 
@@ -146,50 +360,432 @@ func main() {
 	wsHub := websocket.NewHub(redisCache)
 	go wsHub.Run()
 
-	messageHub := websocket.NewMessageHub(redisCache)
-	go messageHub.Run()
+	// Registers itself to run auctioneers' commands sent to the hub
+	liveEventService := service.NewLiveEventService(liveEventRepo, auctionRepo, bidRepo, orderService, notificationService, db, redisCache, wsHub)
 
-	// Initialize message service
+	// Initialize message service (registers itself for presence and typing
+	// events, so it must exist before the message hub starts)
 	messageService, err := service.NewMessageService(
 		messageRepo,
+		deviceKeyRepo,
+		moderationRepo,
 		userRepo,
+		userBlockRepo,
 		cfg.Messaging.EncryptionKey,
+		cfg.Messaging.RetentionDays,
 		messageHub,
 	)
 	if err != nil {
 		log.Fatalf("Failed to initialize message service: %v", err)
 	}
+	go messageHub.Run()
+
+	adminService := service.NewAdminService(
+		auctionRepo,
+		bidRepo,
+		auditLogRepo,
+		messageRepo,
+		disputeRepo,
+		reportRepo,
+		moderationRepo,
+		userMergeRepo,
+		db,
+		orderService,
+		messageService,
+		notificationService,
+		webhookService,
+		redisCache,
+	)
+
+	disputeService := service.NewDisputeService(
+		disputeRepo,
+		messageRepo,
+		orderRepo,
+		auctionRepo,
+		auditLogRepo,
+		db,
+		notificationService,
+		ledgerService,
+		s3Storage,
+	)
+
+	userService := service.NewUserService(
+		userRepo,
+		watchlistRepo,
+		ratingRepo,
+		auctionRepo,
+	)
+
+	snipeService := service.NewSnipeService(
+		snipeRepo,
+		auctionRepo,
+		bidRepo,
+		watchlistRepo,
+		auditLogRepo,
+		bidService,
+		notificationService,
+	)
+
+	offerService := service.NewOfferService(
+		offerRepo,
+		auctionRepo,
+		bidRepo,
+		auditLogRepo,
+		db,
+		bidService,
+		orderService,
+		notificationService,
+		webhookService,
+		messageHub,
+		redisCache,
+	)
+
+	// Only the instance holding the lease runs scheduled jobs
+	schedulerLeader := service.NewLeaderElector(redisCache, cache.SchedulerLeaseKey, cfg.Scheduler.LeaderLeaseTTL)
+
+	maintenanceService := service.NewMaintenanceService(auctionRepo, maintenanceWindowRepo, db, redisCache)
+
+	bidRateLimit, messageRateLimit := userRateLimits(&cfg.RateLimit)
+	apiUsageService := service.NewAPIUsageService(apiUsageRepo, redisCache, []service.APILimit{
+		{Name: bidRateLimit.Usage, Requests: bidRateLimit.Requests, Window: bidRateLimit.Window, Key: cache.RateLimitKeyBid},
+		{Name: messageRateLimit.Usage, Requests: messageRateLimit.Requests, Window: messageRateLimit.Window, Key: cache.RateLimitKeyMessage},
+	})
+
+	fraudService := service.NewFraudService(fraudRepo)
+
+	importService := service.NewImportService(
+		importJobRepo,
+		categoryRepo,
+		auctionService,
+		cfg.Imports.ImageTimeout,
+		cfg.Imports.AllowPrivateImageURLs,
+		cfg.Imports.MaxRows,
+		cfg.Imports.MaxFileBytes,
+	)
+
+	// Exports and fact sheets are only offered when S3 is reachable
+	privacyService := service.NewPrivacyService(
+		dataExportRepo,
+		accountDeletionRepo,
+		userRepo,
+		auctionRepo,
+		bidRepo,
+		ratingRepo,
+		notificationRepo,
+		auditLogRepo,
+		db,
+		authService,
+		messageService,
+		piiStore,
+		emailSender,
+		cfg.Privacy.ExportLinkTTL,
+		cfg.Privacy.DeletedUserRetention,
+	)
+
+	var factsheetStore storage.ObjectStore
+	if s3Storage != nil {
+		factsheetStore = s3Storage
+	}
+	factsheetService := service.NewFactsheetService(
+		factsheetRepo,
+		auctionRepo,
+		auctionService,
+		factsheetStore,
+		frontendURL,
+	)
+
+	digestService := service.NewDigestService(
+		digestRepo,
+		savedSearchRepo,
+		auctionRepo,
+		userRepo,
+		emailSender,
+		frontendURL,
+	)
+
+	schedulerService := service.NewSchedulerService(
+		auctionRepo,
+		bidRepo,
+		messageRepo,
+		notificationService,
+		webhookService,
+		orderService,
+		snipeService,
+		offerService,
+		importService,
+		privacyService,
+		factsheetService,
+		curationService,
+		priceDropService,
+		ledgerService,
+		maintenanceService,
+		apiUsageService,
+		fraudService,
+		digestService,
+		redisCache,
+		schedulerLeader,
+		cfg.Scheduler.EndBatchSize,
+		cfg.Scheduler.EndWorkers,
+		cfg.Scheduler.EndBatchDelay,
+		cfg.Messaging.RetentionDays,
+	)
+
+	allowedOrigins := origin.NewAllowList(cfg.Server.AllowOrigins)
+	originService := service.NewOriginService(allowedOriginRepo, auditLogRepo, db, allowedOrigins)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService, cfg)
 	auctionHandler := handler.NewAuctionHandler(auctionService)
 	bidHandler := handler.NewBidHandler(bidService)
+	snipeHandler := handler.NewSnipeHandler(snipeService)
+	offerHandler := handler.NewOfferHandler(offerService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	importHandler := handler.NewImportHandler(importService)
+	followService := service.NewFollowService(sellerFollowRepo, userRepo)
+	ebayImportService := service.NewEbayImportService(
+		auctionRepo,
+		watchlistRepo,
+		userRepo,
+		followService,
+		cfg.Imports.MaxRows,
+		cfg.Imports.MaxFileBytes,
+	)
+	privacyHandler := handler.NewPrivacyHandler(privacyService)
+	maintenanceHandler := handler.NewMaintenanceHandler(maintenanceService)
 	userHandler := handler.NewUserHandler(userService, notificationService)
 	adminHandler := handler.NewAdminHandler(
 		userService,
 		auctionService,
+		adminService,
+		notificationService,
+		originService,
 		categoryRepo,
 		reportRepo,
 		auctionRepo,
 		bidRepo,
 	)
-	wsHandler := handler.NewWebSocketHandler(wsHub)
+	wsHandler := handler.NewWebSocketHandler(wsHub, auctionService)
 	messageHandler := handler.NewMessageHandler(messageService)
+	orderHandler := handler.NewOrderHandler(orderService)
+	disputeHandler := handler.NewDisputeHandler(disputeService)
+	ledgerHandler := handler.NewLedgerHandler(ledgerService)
+	payoutHandler := handler.NewPayoutHandler(payoutService)
+	auctionInviteHandler := handler.NewAuctionInviteHandler(auctionInviteService)
+	listingShareHandler := handler.NewListingShareHandler(listingShareService)
+	embedHandler := handler.NewEmbedHandler(service.NewEmbedService(auctionService, frontendURL, cfg.Server.PublicURL))
+	factsheetHandler := handler.NewFactsheetHandler(factsheetService)
+	recommendationHandler := handler.NewRecommendationHandler(recommendationService)
+	curationHandler := handler.NewCurationHandler(curationService)
+	priceDropHandler := handler.NewPriceDropHandler(priceDropService)
+	bidApprovalHandler := handler.NewBidApprovalHandler(bidApprovalService)
+	liveEventHandler := handler.NewLiveEventHandler(liveEventService, wsHub)
 	messageWsHandler := handler.NewMessageWebSocketHandler(messageHub)
 
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
 
+	openAPIHandler, err := handler.NewOpenAPIHandler()
+	if err != nil {
+		log.Fatalf("Failed to build OpenAPI spec: %v", err)
+	}
+
 	// Setup router
+	// Recent error rates by component, for the status page
+	errorRates := errorrate.New(15*time.Minute, time.Minute)
+
+	// Stops admitting bids once shutdown starts
+	shutdown := service.NewShutdownCoordinator()
+
+	r := newRouter(cfg, redisCache, authMiddleware, allowedOrigins, auditLogRepo, maintenanceService, shutdown, errorRates, &routeHandlers{
+		auth:        authHandler,
+		auction:     auctionHandler,
+		bid:         bidHandler,
+		snipe:       snipeHandler,
+		offer:       offerHandler,
+		webhook:     webhookHandler,
+		imports:     importHandler,
+		privacy:     privacyHandler,
+		maintenance: maintenanceHandler,
+		user:        userHandler,
+		admin:       adminHandler,
+		ws:          wsHandler,
+		message:     messageHandler,
+		order:       orderHandler,
+		dispute:     disputeHandler,
+		ledger:      ledgerHandler,
+		payout:      payoutHandler,
+		invite:      auctionInviteHandler,
+		share:       listingShareHandler,
+		factsheet:   factsheetHandler,
+		recommend:   recommendationHandler,
+		curation:    curationHandler,
+		priceDrop:   priceDropHandler,
+		bidApproval: bidApprovalHandler,
+		liveEvent:   liveEventHandler,
+		messageWS:   messageWsHandler,
+		openAPI:     openAPIHandler,
+		health:      handler.NewHealthHandler(db.Pool, redisBreaker, s3Breaker, emailBreaker),
+		apiUsage:    handler.NewAPIUsageHandler(apiUsageService),
+		sellerAlert: handler.NewSellerAlertHandler(service.NewSellerAlertService(sellerBidAlertRepo, auctionRepo)),
+		status:      handler.NewStatusHandler(service.NewStatusService(statusIncidentRepo, db.Pool, errorRates, redisBreaker, s3Breaker)),
+		fraud:       handler.NewFraudHandler(fraudService),
+		block:       handler.NewBlockHandler(service.NewBlockService(userBlockRepo, userRepo)),
+		follow:      handler.NewFollowHandler(followService, ebayImportService),
+		embed:       embedHandler,
+		push:        handler.NewPushHandler(pushService),
+		digest:      handler.NewDigestHandler(digestService, service.NewSavedSearchService(savedSearchRepo)),
+	})
+
+	// Start scheduler
+	schedulerLeader.Start()
+	schedulerService.Start()
+
+	originService.Start()
+	defer originService.Stop()
+
+	// Create server
+	server := &http.Server{
+		Addr:         ":" + cfg.Server.Port,
+		Handler:      r,
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	// Graceful shutdown runs after in-flight bids finish: requests first,
+	// then the scheduler's current batch, then what is left queued for
+	// notifications and websocket clients
+	shutdown.OnShutdown("http server", server.Shutdown)
+	shutdown.OnShutdown("scheduler", schedulerService.Stop)
+	shutdown.OnShutdown("scheduler lease", func(ctx context.Context) error {
+		schedulerLeader.Stop()
+		return nil
+	})
+	shutdown.OnShutdown("notification queue", notificationQueue.Stop)
+	shutdown.OnShutdown("auction websockets", wsHub.Drain)
+	shutdown.OnShutdown("message websockets", messageHub.Drain)
+
+	// Start server
+	log.Printf("Server starting on port %s", cfg.Server.Port)
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err := <-serverErr:
+		log.Fatalf("Server error: %v", err)
+	case <-sigChan:
+	}
+
+	log.Println("Shutting down server...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	report := shutdown.Shutdown(ctx)
+	if abandoned := report.Abandoned(); len(abandoned) > 0 {
+		log.Printf("Server stopped, abandoning %d of %d shutdown steps", len(abandoned), len(report.Steps))
+		return
+	}
+
+	log.Println("Server stopped")
+}
+
+// newGradingRegistry enables every grader that has credentials configured
+func newGradingRegistry(cfg *config.GradingConfig) *grading.Registry {
+	registry := grading.NewRegistry()
+
+	if cfg.PSAToken != "" {
+		registry.Register(grading.NewPSAGrader(cfg.PSAToken))
+	}
+	if cfg.BGSLookupURL != "" {
+		registry.Register(grading.NewBGSGrader(cfg.BGSLookupURL, cfg.BGSAPIKey))
+	}
+
+	return registry
+}
+
+// userRateLimits returns the rate limits kept per signed-in user, with their
+// configured budgets. Users can see their usage of these.
+func userRateLimits(rl *config.RateLimitConfig) (bid, message *middleware.RateLimitConfig) {
+	bid = middleware.BidRateLimitConfig().WithBudget(rl.Bid.Requests, rl.Bid.Window)
+	message = middleware.MessageRateLimitConfig().WithBudget(rl.Messaging.Requests, rl.Messaging.Window)
+	return bid, message
+}
+
+type routeHandlers struct {
+	auth        *handler.AuthHandler
+	auction     *handler.AuctionHandler
+	bid         *handler.BidHandler
+	snipe       *handler.SnipeHandler
+	offer       *handler.OfferHandler
+	webhook     *handler.WebhookHandler
+	imports     *handler.ImportHandler
+	privacy     *handler.PrivacyHandler
+	maintenance *handler.MaintenanceHandler
+	user        *handler.UserHandler
+	admin       *handler.AdminHandler
+	ws          *handler.WebSocketHandler
+	message     *handler.MessageHandler
+	order       *handler.OrderHandler
+	dispute     *handler.DisputeHandler
+	ledger      *handler.LedgerHandler
+	payout      *handler.PayoutHandler
+	invite      *handler.AuctionInviteHandler
+	share       *handler.ListingShareHandler
+	factsheet   *handler.FactsheetHandler
+	recommend   *handler.RecommendationHandler
+	curation    *handler.CurationHandler
+	priceDrop   *handler.PriceDropHandler
+	bidApproval *handler.BidApprovalHandler
+	liveEvent   *handler.LiveEventHandler
+	messageWS   *handler.MessageWebSocketHandler
+	openAPI     *handler.OpenAPIHandler
+	health      *handler.HealthHandler
+	apiUsage    *handler.APIUsageHandler
+	sellerAlert *handler.SellerAlertHandler
+	status      *handler.StatusHandler
+	fraud       *handler.FraudHandler
+	block       *handler.BlockHandler
+	follow      *handler.FollowHandler
+	embed       *handler.EmbedHandler
+	push        *handler.PushHandler
+	digest      *handler.DigestHandler
+}
+
+// newRouter registers every HTTP route. New API routes also need an entry in
+// the handler package's OpenAPI route registry.
+func newRouter(cfg *config.Config, redisCache *cache.RedisCache, authMiddleware *middleware.AuthMiddleware, allowedOrigins *origin.AllowList, auditLog repository.AuditLogRepository, maintenance middleware.MaintenanceChecker, shutdown middleware.ShutdownGate, errorRates *errorrate.Tracker, h *routeHandlers) *chi.Mux {
+	// Rate limit budgets
+	rl := cfg.RateLimit
+	defaultRateLimit := middleware.DefaultRateLimitConfig().WithBudget(rl.Default.Requests, rl.Default.Window)
+	authRateLimit := middleware.AuthRateLimitConfig().WithBudget(rl.Auth.Requests, rl.Auth.Window)
+	tokenRateLimit := middleware.TokenRateLimitConfig().WithBudget(rl.Token.Requests, rl.Token.Window)
+	searchRateLimit := middleware.SearchRateLimitConfig().WithBudget(rl.Search.Requests, rl.Search.Window)
+	embedRateLimit := middleware.EmbedRateLimitConfig().WithBudget(rl.Embed.Requests, rl.Embed.Window)
+	bidRateLimit, messageRateLimit := userRateLimits(&rl)
+
+	// Admin request capture; transcript access is already audited with its
+	// reason by the admin service
+	adminAudit := middleware.DefaultAdminAuditConfig().
+		WithMaxBodyBytes(int64(cfg.AdminAudit.MaxBodyBytes)).
+		WithRoute(http.MethodPost, "/api/admin/conversations/{id}/transcript", middleware.AdminAuditRoute{Skip: true})
+
 	r := chi.NewRouter()
 
 	// Global middleware
 	r.Use(middleware.Logger)
+	r.Use(middleware.ErrorRates(errorRates))
+	r.Use(middleware.ProblemDetails(cfg.Server.ProblemTypeBaseURL))
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.CORS(&middleware.CORSConfig{
-		AllowedOrigins:   cfg.Server.AllowOrigins,
+		Origins:          allowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Requested-With"},
+		ExposedHeaders:   []string{"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After"},
 		AllowCredentials: true,
 	}))
 
@@ -198,163 +794,360 @@ func main() {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
 	})
+	r.Get("/readyz", h.health.Ready)
+	r.Get("/metrics", h.health.Metrics)
+
+	// Listing cards for other sites to embed; the only routes any site may
+	// frame or fetch
+	r.With(middleware.RateLimit(redisCache, embedRateLimit), middleware.Embeddable).Get("/embed/auctions/{id}", h.embed.Auction)
 
 	// API routes
 	r.Route("/api", func(r chi.Router) {
 		// Apply global rate limiting
-		r.Use(middleware.RateLimit(redisCache, middleware.DefaultRateLimitConfig()))
+		r.Use(middleware.RateLimit(redisCache, defaultRateLimit))
+
+		// Read-only during maintenance. Refreshing keeps sessions alive, admins
+		// can still switch it off, plan windows and post status updates, and
+		// batch reads only look like writes.
+		r.Use(middleware.ReadOnlyDuringMaintenance(maintenance, "/api/auth/refresh",
+			"/api/admin/maintenance", "/api/admin/maintenance/",
+			"/api/admin/status-incidents", "/api/admin/status-incidents/",
+			"/api/auctions/batch-get", "/api/users/batch-get"))
+
+		// API documentation
+		r.Get("/openapi.json", h.openAPI.Spec)
+		r.Get("/docs", h.openAPI.Docs)
+
+		r.Get("/maintenance", h.maintenance.GetStatus)
+		r.Get("/maintenance/windows", h.maintenance.ListWindows)
+		r.With(middleware.RateLimit(redisCache, embedRateLimit)).Get("/oembed", h.embed.OEmbed)
+		r.Get("/status", h.status.GetStatus)
 
 		// Auth routes (public)
 		r.Route("/auth", func(r chi.Router) {
-			r.Use(middleware.RateLimit(redisCache, middleware.AuthRateLimitConfig()))
-			r.Post("/register", authHandler.Register)
-			r.Post("/login", authHandler.Login)
-			r.Post("/logout", authHandler.Logout)
-			r.Post("/refresh", authHandler.RefreshToken)
-			r.Post("/verify-email", authHandler.VerifyEmail)
-			r.Post("/forgot-password", authHandler.ForgotPassword)
-			r.Post("/reset-password", authHandler.ResetPassword)
-			r.Get("/google", authHandler.GoogleLogin)
-			r.Get("/google/callback", authHandler.GoogleCallback)
+			r.Use(middleware.RateLimit(redisCache, authRateLimit))
+			r.Post("/register", h.auth.Register)
+			r.Post("/login", h.auth.Login)
+			r.With(middleware.RateLimit(redisCache, tokenRateLimit)).Post("/login/verify", h.auth.VerifyLogin)
+			r.Post("/logout", h.auth.Logout)
+			r.Post("/refresh", h.auth.RefreshToken)
+			r.With(middleware.RateLimit(redisCache, tokenRateLimit)).Post("/verify-email", h.auth.VerifyEmail)
+			r.Post("/resend-verification", h.auth.ResendVerification)
+			r.Post("/forgot-password", h.auth.ForgotPassword)
+			r.With(middleware.RateLimit(redisCache, tokenRateLimit)).Post("/reset-password", h.auth.ResetPassword)
+			r.Get("/{provider}", h.auth.OAuthLogin)
+			r.Get("/{provider}/callback", h.auth.OAuthCallback)
+			r.Post("/{provider}/callback", h.auth.OAuthCallback)
 		})
 
 		// Categories (public)
-		r.Get("/categories", auctionHandler.GetCategories)
-		r.Get("/categories/{slug}", auctionHandler.GetCategoryBySlug)
+		r.Get("/categories", h.auction.GetCategories)
+		r.Get("/categories/{slug}", h.auction.GetCategoryBySlug)
+		r.Get("/categories/{slug}/rules", h.auction.GetListingRules)
 
 		// Auctions (public read, auth write)
 		r.Route("/auctions", func(r chi.Router) {
-			r.With(authMiddleware.OptionalAuth).Get("/", auctionHandler.List)
-			r.With(authMiddleware.OptionalAuth).Get("/{id}", auctionHandler.GetByID)
-			r.Get("/{id}/bids", bidHandler.GetBidsByAuction)
+			r.With(authMiddleware.OptionalAuth, middleware.RateLimit(redisCache, searchRateLimit)).Get("/", h.auction.List)
+			r.With(authMiddleware.OptionalAuth, middleware.RateLimit(redisCache, searchRateLimit)).Get("/facets", h.auction.Facets)
+			r.With(middleware.RateLimit(redisCache, searchRateLimit)).Get("/sold", h.auction.ListSold)
+			r.With(authMiddleware.OptionalAuth, middleware.RateLimit(redisCache, searchRateLimit)).Post("/batch-get", h.auction.BatchGet)
+			r.With(authMiddleware.OptionalAuth).Get("/featured", h.curation.Featured)
+			r.With(authMiddleware.OptionalAuth).Get("/trending", h.curation.Trending)
+			r.With(authMiddleware.OptionalAuth).Get("/{id}", h.auction.GetByID)
+			r.With(authMiddleware.OptionalAuth).Get("/{id}/factsheet.pdf", h.factsheet.Get)
+			r.With(authMiddleware.OptionalAuth, middleware.RateLimit(redisCache, searchRateLimit)).Get("/{id}/similar", h.recommend.Similar)
+			r.With(authMiddleware.OptionalAuth).Get("/{id}/price-history", h.priceDrop.History)
+			r.With(authMiddleware.OptionalAuth).Get("/{id}/bids", h.bid.GetBidsByAuction)
 
 			// Authenticated routes
 			r.Group(func(r chi.Router) {
 				r.Use(authMiddleware.RequireAuth)
-				r.Post("/", auctionHandler.Create)
-				r.Put("/{id}", auctionHandler.Update)
-				r.Delete("/{id}", auctionHandler.Delete)
-				r.Post("/{id}/publish", auctionHandler.Publish)
-				r.Post("/{id}/images", auctionHandler.UploadImage)
-				r.Delete("/{id}/images/{imageId}", auctionHandler.DeleteImage)
-
-				// Bidding with rate limiting
-				r.With(middleware.RateLimit(redisCache, middleware.BidRateLimitConfig())).
-					Post("/{id}/bids", bidHandler.PlaceBid)
-				r.Post("/{id}/buy-now", bidHandler.BuyNow)
+				r.Post("/", h.auction.Create)
+				r.Get("/end-time-suggestion", h.auction.SuggestEndTime)
+				r.Post("/import", h.imports.Create)
+				r.Get("/imports", h.imports.List)
+				r.Get("/imports/{id}", h.imports.Get)
+				r.Get("/imports/{id}/report", h.imports.Report)
+				r.Put("/{id}", h.auction.Update)
+				r.Delete("/{id}", h.auction.Delete)
+				r.Post("/{id}/publish", h.auction.Publish)
+				r.Get("/{id}/stats", h.auction.GetStats)
+				r.Get("/{id}/invites", h.invite.List)
+				r.Post("/{id}/invites", h.invite.Invite)
+				r.Delete("/{id}/invites/{userId}", h.invite.Revoke)
+				r.Post("/{id}/share-link", h.invite.CreateShareLink)
+				r.Delete("/{id}/share-link", h.invite.RevokeShareLinks)
+				r.Post("/{id}/shares", h.share.Create)
+				r.Post("/{id}/images", h.auction.UploadImage)
+				r.Post("/{id}/images/bulk", h.auction.UploadImages)
+				r.Delete("/{id}/images/{imageId}", h.auction.DeleteImage)
+
+				// Bidding with rate limiting; no new bids once shutdown starts,
+				// and bids failing during a maintenance window are retried
+				retryDuringWindow := middleware.RetryDuringMaintenanceWindow(maintenance)
+				r.With(middleware.RateLimit(redisCache, bidRateLimit), middleware.RejectDuringShutdown(shutdown), retryDuringWindow).
+					Post("/{id}/bids", h.bid.PlaceBid)
+				r.With(middleware.RejectDuringShutdown(shutdown), retryDuringWindow).Post("/{id}/buy-now", h.bid.BuyNow)
+				r.Post("/{id}/bid-approvals", h.bidApproval.Request)
+				r.Get("/{id}/bid-approvals", h.bidApproval.List)
+				r.Get("/{id}/bid-approvals/me", h.bidApproval.Mine)
+				r.Put("/{id}/bid-approvals/{userId}", h.bidApproval.Decide)
+				r.With(retryDuringWindow).Post("/{id}/bids/{bidId}/cancel", h.bid.CancelBid)
+				r.Put("/{id}/reserve", h.bid.LowerReserve)
+				r.Put("/{id}/price-drop", h.priceDrop.SetRule)
+				r.Delete("/{id}/price-drop", h.priceDrop.DeleteRule)
+				r.Get("/{id}/seller-alerts", h.sellerAlert.GetAlerts)
+				r.Put("/{id}/seller-alerts", h.sellerAlert.SetAlerts)
+				r.Delete("/{id}/seller-alerts", h.sellerAlert.ResetAlerts)
+				r.Post("/{id}/cancel", h.bid.CancelAuction)
+				r.Post("/{id}/snipe", h.snipe.Schedule)
+				r.Delete("/{id}/snipe", h.snipe.Cancel)
+				r.With(middleware.RateLimit(redisCache, bidRateLimit), middleware.RejectDuringShutdown(shutdown), retryDuringWindow).
+					Post("/{id}/offers", h.offer.MakeOffer)
+				r.Get("/{id}/offers", h.offer.ListForAuction)
 			})
 		})
 
+		// Live events (public read, auth write); auctioneers run them over
+		// the event's WebSocket
+		r.Route("/live-events", func(r chi.Router) {
+			r.Get("/", h.liveEvent.List)
+			r.Get("/{id}", h.liveEvent.Get)
+			r.With(authMiddleware.RequireAuth).Post("/", h.liveEvent.Create)
+		})
+
+		// Short links shared to a listing, resolved for apps to open
+		r.With(authMiddleware.OptionalAuth, middleware.RateLimit(redisCache, searchRateLimit)).Get("/links/{code}", h.share.Resolve)
+
 		// Users
 		r.Route("/users", func(r chi.Router) {
 			// Authenticated routes
 			r.Group(func(r chi.Router) {
 				r.Use(authMiddleware.RequireAuth)
-				r.Get("/me", authHandler.GetMe)
-				r.Put("/me", userHandler.UpdateProfile)
-				r.Get("/me/bids", bidHandler.GetMyBids)
+				r.Get("/me", h.auth.GetMe)
+				r.Put("/me", h.user.UpdateProfile)
+				r.Put("/me/username", h.user.ChooseUsername)
+				r.Put("/me/vacation", h.user.SetVacation)
+				r.Delete("/me/vacation", h.user.EndVacation)
+				r.Get("/me/notification-settings", h.user.GetNotificationSettings)
+				r.Put("/me/notification-settings", h.user.UpdateNotificationSettings)
+				r.Get("/me/blocks", h.block.ListBlocks)
+				r.Post("/me/blocks", h.block.Block)
+				r.Delete("/me/blocks/{userId}", h.block.Unblock)
+				r.Get("/me/following", h.follow.ListFollowing)
+				r.Put("/me/following/{userId}", h.follow.Follow)
+				r.Delete("/me/following/{userId}", h.follow.Unfollow)
+				r.Get("/me/push-subscriptions", h.push.ListSubscriptions)
+				r.Post("/me/push-subscriptions", h.push.Subscribe)
+				r.Delete("/me/push-subscriptions/{id}", h.push.Unsubscribe)
+				r.Get("/me/saved-searches", h.digest.ListSavedSearches)
+				r.Post("/me/saved-searches", h.digest.CreateSavedSearch)
+				r.Delete("/me/saved-searches/{id}", h.digest.DeleteSavedSearch)
+				r.Put("/me/session-policy", h.auth.SetSessionPolicy)
+				r.Put("/me/two-factor", h.auth.SetTwoFactor)
+				r.Get("/me/devices", h.auth.ListDevices)
+				r.Put("/me/devices/{id}", h.auth.UpdateDevice)
+				r.Delete("/me/devices/{id}", h.auth.DeleteDevice)
+				r.Get("/me/login-history", h.auth.ListLoginHistory)
+				r.Post("/me/login-history/{id}/report", h.auth.ReportLogin)
+				r.Get("/me/sign-in-methods", h.auth.GetSignInMethods)
+				r.Post("/me/password", h.auth.SetPassword)
+				// These check the current password, so they share the login budget
+				r.With(middleware.RateLimit(redisCache, authRateLimit)).Post("/me/oauth/{provider}/link", h.auth.LinkOAuth)
+				r.With(middleware.RateLimit(redisCache, authRateLimit)).Post("/me/oauth/confirm-link", h.auth.ConfirmOAuthLink)
+				r.With(middleware.RateLimit(redisCache, authRateLimit)).Delete("/me/oauth/{provider}", h.auth.UnlinkOAuth)
+				r.Get("/me/export", h.privacy.Export)
+				r.Get("/me/balance", h.ledger.GetBalance)
+				r.Get("/me/payout-method", h.payout.GetMethod)
+				r.Put("/me/payout-method", h.payout.SetMethod)
+				r.Get("/me/payouts", h.payout.List)
+				r.Post("/me/payouts", h.payout.Request)
+				r.With(middleware.RateLimit(redisCache, authRateLimit)).Delete("/me", h.privacy.DeleteAccount)
+				r.Get("/me/recommended", h.recommend.Recommended)
+				r.Get("/me/bids", h.bid.GetMyBids)
+				r.Get("/me/snipes", h.snipe.GetMySnipes)
+				r.Get("/me/api-usage", h.apiUsage.GetMyUsage)
+				r.Put("/me/keys", h.message.RegisterDeviceKey)
+				r.Delete("/me/keys/{keyId}", h.message.RevokeDeviceKey)
+				r.Get("/{id}/presence", h.message.GetPresence)
+				r.Get("/{id}/keys", h.message.GetDeviceKeys)
 			})
 
 			// Public user profiles
-			r.Get("/{id}", userHandler.GetPublicProfile)
-			r.Get("/{id}/auctions", userHandler.GetUserAuctions)
-			r.Get("/{id}/ratings", userHandler.GetUserRatings)
+			r.With(middleware.RateLimit(redisCache, searchRateLimit)).Post("/batch-get", h.user.BatchGet)
+			r.Get("/{id}", h.user.GetPublicProfile)
+			r.Get("/{id}/auctions", h.user.GetUserAuctions)
+			r.Get("/{id}/ratings", h.user.GetUserRatings)
 		})
 
 		// Watchlist (authenticated)
 		r.Route("/watchlist", func(r chi.Router) {
 			r.Use(authMiddleware.RequireAuth)
-			r.Get("/", userHandler.GetWatchlist)
-			r.Post("/{auctionId}", userHandler.AddToWatchlist)
-			r.Delete("/{auctionId}", userHandler.RemoveFromWatchlist)
+			r.Get("/", h.user.GetWatchlist)
+			r.Post("/import/ebay", h.follow.ImportEbay)
+			r.Post("/{auctionId}", h.user.AddToWatchlist)
+			r.Delete("/{auctionId}", h.user.RemoveFromWatchlist)
 		})
 
-		// Notifications (authenticated)
+		// Notifications
 		r.Route("/notifications", func(r chi.Router) {
+			// Public: the token from an email's unsubscribe link stands in for signing in
+			r.With(middleware.RateLimit(redisCache, tokenRateLimit)).Post("/unsubscribe", h.user.Unsubscribe)
+
+			// Authenticated routes
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RequireAuth)
+				r.Get("/", h.user.GetNotifications)
+				r.Put("/{id}/read", h.user.MarkNotificationRead)
+				r.Put("/threads/{id}/read", h.user.MarkNotificationThreadRead)
+				r.Get("/quiet-hours", h.user.GetQuietHours)
+				r.Put("/quiet-hours", h.user.SetQuietHours)
+				r.Get("/digest", h.digest.GetPreferences)
+				r.Put("/digest", h.digest.UpdatePreferences)
+				r.Get("/digest/preview", h.digest.Preview)
+				r.Put("/read-all", h.user.MarkAllNotificationsRead)
+			})
+		})
+
+		// Orders (authenticated)
+		r.Route("/orders", func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Get("/", h.order.List)
+			r.Get("/{id}", h.order.GetByID)
+			r.Post("/{id}/mark-paid", h.order.MarkPaid)
+			r.Post("/{id}/confirm-delivery", h.ledger.ConfirmDelivery)
+			r.Get("/{id}/disputes", h.dispute.ListForOrder)
+			r.Post("/{id}/disputes", h.dispute.Open)
+		})
+
+		// Disputes (authenticated)
+		r.Route("/disputes", func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Get("/{id}", h.dispute.GetByID)
+			r.Post("/{id}/messages", h.dispute.AddMessage)
+			r.Post("/{id}/evidence", h.dispute.UploadEvidence)
+		})
+
+		// Second-chance offers (authenticated)
+		r.Route("/second-chance-offers", func(r chi.Router) {
 			r.Use(authMiddleware.RequireAuth)
-			r.Get("/", userHandler.GetNotifications)
-			r.Put("/{id}/read", userHandler.MarkNotificationRead)
-			r.Put("/read-all", userHandler.MarkAllNotificationsRead)
+			r.Get("/", h.order.GetMySecondChanceOffers)
+			r.Post("/{id}/accept", h.order.AcceptSecondChanceOffer)
+			r.Post("/{id}/decline", h.order.DeclineSecondChanceOffer)
+		})
+
+		// Best offers (authenticated)
+		r.Route("/offers", func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Get("/", h.offer.GetMyOffers)
+			r.Post("/{id}/accept", h.offer.Accept)
+			r.Post("/{id}/decline", h.offer.Decline)
+			r.Post("/{id}/counter", h.offer.Counter)
+		})
+
+		// Webhooks (authenticated)
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Get("/", h.webhook.List)
+			r.Post("/", h.webhook.Create)
+			r.Get("/{id}", h.webhook.Get)
+			r.Put("/{id}", h.webhook.Update)
+			r.Delete("/{id}", h.webhook.Delete)
+			r.Post("/{id}/secret", h.webhook.RotateSecret)
+			r.Get("/{id}/deliveries", h.webhook.ListDeliveries)
+			r.Post("/{id}/deliveries/{deliveryId}/retry", h.webhook.RetryDelivery)
 		})
 
 		// Ratings (authenticated)
 		r.Route("/ratings", func(r chi.Router) {
 			r.Use(authMiddleware.RequireAuth)
-			r.Post("/auction/{auctionId}", userHandler.CreateRating)
+			r.Post("/auction/{auctionId}", h.user.CreateRating)
 		})
 
 		// Admin routes
 		r.Route("/admin", func(r chi.Router) {
 			r.Use(authMiddleware.RequireAuth)
 			r.Use(authMiddleware.RequireAdmin)
+			r.Use(middleware.AdminAudit(auditLog, adminAudit))
+			r.Use(middleware.MarkCrossRegion(cfg.Residency.Region, cfg.Sessions.CountryHeader))
 
-			r.Get("/dashboard", adminHandler.GetDashboard)
-			r.Get("/users", adminHandler.ListUsers)
-			r.Put("/users/{id}/ban", adminHandler.BanUser)
-			r.Get("/auctions", adminHandler.ListAuctions)
-			r.Put("/auctions/{id}/status", adminHandler.UpdateAuctionStatus)
-			r.Post("/categories", adminHandler.CreateCategory)
-			r.Put("/categories/{id}", adminHandler.UpdateCategory)
-			r.Delete("/categories/{id}", adminHandler.DeleteCategory)
-			r.Get("/reports", adminHandler.ListReports)
-			r.Put("/reports/{id}", adminHandler.UpdateReport)
+			r.Get("/dashboard", h.admin.GetDashboard)
+			r.Get("/users", h.admin.ListUsers)
+			r.Put("/users/{id}/ban", h.admin.BanUser)
+			r.Delete("/users/{id}", h.privacy.AdminDeleteUser)
+			r.Post("/users/purge-deleted", h.privacy.AdminPurgeDeletedUsers)
+			r.Post("/users/{id}/merge", h.admin.MergeUsers)
+			r.Get("/auctions", h.admin.ListAuctions)
+			r.Put("/auctions/{id}/status", h.admin.UpdateAuctionStatus)
+			r.Post("/auctions/{id}/force-end", h.admin.ForceEndAuction)
+			r.Post("/auctions/{id}/verify-certification", h.admin.VerifyCertification)
+			r.Get("/auctions/{id}/bid-audit", h.bid.AdminGetBidAudit)
+			r.Get("/featured", h.curation.AdminListFeatured)
+			r.Put("/featured/{auctionId}", h.curation.AdminFeature)
+			r.Delete("/featured/{auctionId}", h.curation.AdminUnfeature)
+			r.Post("/categories", h.admin.CreateCategory)
+			r.Put("/categories/{id}", h.admin.UpdateCategory)
+			r.Delete("/categories/{id}", h.admin.DeleteCategory)
+			r.Get("/categories/{id}/translations", h.admin.ListCategoryTranslations)
+			r.Put("/categories/{id}/translations/{lang}", h.admin.SetCategoryTranslation)
+			r.Delete("/categories/{id}/translations/{lang}", h.admin.DeleteCategoryTranslation)
+			r.Get("/categories/{id}/rules", h.admin.GetCategoryListingRules)
+			r.Put("/categories/{id}/rules", h.admin.SetCategoryListingRules)
+			r.Delete("/categories/{id}/rules", h.admin.DeleteCategoryListingRules)
+			r.Get("/reports", h.admin.ListReports)
+			r.Put("/reports/{id}", h.admin.UpdateReport)
+			r.Get("/moderation-queue", h.admin.ListModerationCases)
+			r.Put("/moderation-queue/{id}", h.admin.UpdateModerationCase)
+			r.Get("/audit-logs", h.admin.ListAuditLogs)
+			r.Get("/cors-origins", h.admin.ListAllowedOrigins)
+			r.Post("/cors-origins", h.admin.AllowOrigin)
+			r.Delete("/cors-origins/{id}", h.admin.RevokeOrigin)
+			r.Put("/maintenance", h.maintenance.SetStatus)
+			r.Post("/maintenance/windows", h.maintenance.ScheduleWindow)
+			r.Delete("/maintenance/windows/{id}", h.maintenance.CancelWindow)
+			r.Get("/status-incidents", h.status.AdminListIncidents)
+			r.Post("/status-incidents", h.status.AdminCreateIncident)
+			r.Put("/status-incidents/{id}", h.status.AdminUpdateIncident)
+			r.Delete("/status-incidents/{id}", h.status.AdminDeleteIncident)
+			r.Get("/fraud-flags", h.fraud.AdminListFlags)
+			r.Put("/fraud-flags/{id}", h.fraud.AdminReviewFlag)
+			r.Post("/fraud-checks/run", h.fraud.AdminRunChecks)
+			r.Get("/notification-queue", h.admin.GetNotificationQueueStats)
+			r.Get("/disputes", h.dispute.AdminList)
+			r.Get("/disputes/{id}", h.dispute.GetByID)
+			r.Post("/disputes/{id}/messages", h.dispute.AddMessage)
+			r.Post("/disputes/{id}/resolve", h.dispute.AdminResolve)
+			r.Get("/escrow", h.ledger.AdminListPendingReleases)
+			r.Post("/escrow/{id}/release", h.ledger.AdminRelease)
+			r.Get("/payouts", h.payout.AdminList)
+			r.Post("/payouts/{id}/approve", h.payout.AdminApprove)
+			r.Post("/payouts/{id}/complete", h.payout.AdminComplete)
+			r.Post("/payouts/{id}/reject", h.payout.AdminReject)
+			r.Post("/conversations/{id}/transcript", h.admin.GetConversationTranscript)
 		})
 
 		// Messages (authenticated)
 		r.Route("/messages", func(r chi.Router) {
 			r.Use(authMiddleware.RequireAuth)
-			r.Post("/", messageHandler.SendMessage)
-			r.Get("/unread-count", messageHandler.GetUnreadCount)
+			r.With(middleware.RateLimit(redisCache, messageRateLimit)).Post("/", h.message.SendMessage)
+			r.Get("/unread-count", h.message.GetUnreadCount)
 		})
 
 		// Conversations (authenticated)
 		r.Route("/conversations", func(r chi.Router) {
 			r.Use(authMiddleware.RequireAuth)
-			r.Get("/", messageHandler.GetConversations)
-			r.Get("/{id}", messageHandler.GetConversation)
-			r.Get("/{id}/messages", messageHandler.GetMessages)
-			r.Put("/{id}/read", messageHandler.MarkAsRead)
+			r.Get("/", h.message.GetConversations)
+			r.Get("/{id}", h.message.GetConversation)
+			r.Get("/{id}/messages", h.message.GetMessages)
+			r.Put("/{id}/read", h.message.MarkAsRead)
 		})
 	})
 
 	// WebSocket routes
-	r.With(authMiddleware.OptionalAuth).Get("/ws/auctions/{id}", wsHandler.HandleAuctionWS)
-	r.With(authMiddleware.RequireAuth).Get("/ws/messages", messageWsHandler.HandleMessageWS)
-
-	// Start scheduler
-	schedulerService.Start()
-	defer schedulerService.Stop()
-
-	// Create server
-	server := &http.Server{
-		Addr:         ":" + cfg.Server.Port,
-		Handler:      r,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
-	}
-
-	// Graceful shutdown
-	go func() {
-		sigChan := make(chan os.Signal, 1)
-		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-		<-sigChan
-
-		log.Println("Shutting down server...")
+	r.With(authMiddleware.OptionalAuth).Get("/ws/auctions", h.ws.HandleAuctionsWS)
+	r.With(authMiddleware.OptionalAuth).Get("/ws/auctions/{id}", h.ws.HandleAuctionWS)
+	r.With(authMiddleware.OptionalAuth).Get("/ws/live-events/{id}", h.liveEvent.HandleWS)
+	r.With(authMiddleware.RequireAuth).Get("/ws/messages", h.messageWS.HandleMessageWS)
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-
-		wsHub.Stop()
-		messageHub.Stop()
-		server.Shutdown(ctx)
-	}()
-
-	// Start server
-	log.Printf("Server starting on port %s", cfg.Server.Port)
-	if err := server.ListenAndServe(); err != http.ErrServerClosed {
-		log.Fatalf("Server error: %v", err)
-	}
-
-	log.Println("Server stopped")
+	return r
 }
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/auction-cards/backend/internal/config"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/go-chi/chi/v5"
+)
+
+// Routes that are intentionally left out of the OpenAPI document
+var undocumentedRoutes = map[string]bool{
+	"GET /health":              true,
+	"GET /readyz":              true,
+	"GET /metrics":             true,
+	"GET /api/openapi.json":    true,
+	"GET /api/docs":            true,
+	"GET /ws/auctions":         true,
+	"GET /ws/auctions/{id}":    true,
+	"GET /ws/live-events/{id}": true,
+	"GET /ws/messages":         true,
+}
+
+func TestRouter_OpenAPICoverage(t *testing.T) {
+	router := newRouter(&config.Config{}, nil, nil, nil, nil, nil, nil, nil, &routeHandlers{})
+
+	registered := make(map[string]bool)
+	err := chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		route = strings.ReplaceAll(route, "/*/", "/")
+		if len(route) > 1 {
+			route = strings.TrimSuffix(route, "/")
+		}
+		registered[method+" "+route] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk router: %v", err)
+	}
+
+	documented := make(map[string]bool)
+	for path, item := range handler.OpenAPISpec().Paths {
+		for method := range *item {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	for route := range registered {
+		if !documented[route] && !undocumentedRoutes[route] {
+			t.Errorf("route %s is not in the OpenAPI spec", route)
+		}
+	}
+	for route := range documented {
+		if !registered[route] {
+			t.Errorf("OpenAPI spec documents %s, which is not registered", route)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/auction-cards/backend/internal/pkg/breaker"
+	"github.com/redis/go-redis/v9"
+)
+
+// UseBreaker guards every command and pipeline with b. While it
+// is open commands fail straight away with breaker.ErrOpen, which callers
+// already treat like Redis being unavailable.
+func (c *RedisCache) UseBreaker(b *breaker.Breaker) {
+	c.client.AddHook(breakerHook{breaker: b})
+}
+
+type breakerHook struct {
+	breaker *breaker.Breaker
+}
+
+func (h breakerHook) DialHook(next redis.DialHook) redis.DialHook {
+	// Dialing fails the command that needed the connection, which counts
+	return next
+}
+
+func (h breakerHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		done, err := h.breaker.Allow()
+		if err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		err = next(ctx, cmd)
+		done(isOutage(err))
+		return err
+	}
+}
+
+func (h breakerHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		done, err := h.breaker.Allow()
+		if err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		err = next(ctx, cmds)
+		done(isOutage(err))
+		return err
+	}
+}
+
+// isOutage tells failures of Redis itself from missing keys, errors Redis
+// replied with and callers giving up
+func isOutage(err error) bool {
+	if err == nil || err == redis.Nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var reply redis.Error
+	return !errors.As(err, &reply)
+}
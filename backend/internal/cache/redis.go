@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,11 +16,15 @@ type RedisCache struct {
 	client *redis.Client
 }
 
-func NewRedisCache(addr, password string, db int) (*RedisCache, error) {
+// NewRedisCache connects to Redis. Commands that take longer than timeout
+// fail; zero keeps the client defaults.
+func NewRedisCache(addr, password string, db int, timeout time.Duration) (*RedisCache, error) {
 	client := redis.NewClient(&redis.Options{
-		Addr:     addr,
-		Password: password,
-		DB:       db,
+		Addr:         addr,
+		Password:     password,
+		DB:           db,
+		ReadTimeout:  timeout,
+		WriteTimeout: timeout,
 	})
 
 	// Test connection
@@ -89,16 +95,189 @@ func AuctionChannel(auctionID uuid.UUID) string {
 	return fmt.Sprintf("auction:%s", auctionID.String())
 }
 
+// LiveEventChannel carries a live event's lot changes and the bids on its
+// open lot
+func LiveEventChannel(eventID uuid.UUID) string {
+	return fmt.Sprintf("live:%s", eventID.String())
+}
+
+// Auction end schedule
+
+// AuctionEndsKey is a sorted set of auction IDs scored by end time in unix
+// milliseconds
+const AuctionEndsKey = "auctions:ends"
+
+// popDueScript removes and returns up to ARGV[2] members scored at or before
+// ARGV[1] in one step, so concurrent schedulers never pop the same auction
+var popDueScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+if #due > 0 then
+	redis.call('ZREM', KEYS[1], unpack(due))
+end
+return due`)
+
+// ScheduleAuctionEnd adds the auction to the end schedule, replacing any
+// earlier end time
+func (c *RedisCache) ScheduleAuctionEnd(ctx context.Context, auctionID uuid.UUID, endTime time.Time) error {
+	return c.ScheduleAuctionEnds(ctx, map[uuid.UUID]time.Time{auctionID: endTime})
+}
+
+func (c *RedisCache) ScheduleAuctionEnds(ctx context.Context, ends map[uuid.UUID]time.Time) error {
+	if len(ends) == 0 {
+		return nil
+	}
+
+	members := make([]redis.Z, 0, len(ends))
+	for id, endTime := range ends {
+		members = append(members, redis.Z{Score: float64(endTime.UnixMilli()), Member: id.String()})
+	}
+	return c.client.ZAdd(ctx, AuctionEndsKey, members...).Err()
+}
+
+// PopDueAuctionEnds removes and returns up to limit auctions due to end by now
+func (c *RedisCache) PopDueAuctionEnds(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	members, err := popDueScript.Run(ctx, c.client, []string{AuctionEndsKey}, now.UnixMilli(), limit).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		if id, err := uuid.Parse(member); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// Auction views
+
+// PendingViewsKey is a hash of auction IDs to views not yet written to the
+// database
+const PendingViewsKey = "views:pending"
+
+// ViewSeenKey marks that a viewer, by a hash of who they are, recently
+// viewed an auction
+func ViewSeenKey(auctionID uuid.UUID, viewerHash string) string {
+	return fmt.Sprintf("views:seen:%s:%s", auctionID, viewerHash)
+}
+
+// takeViewsScript empties the hash at KEYS[1] and returns what it held, so
+// views counted meanwhile wait for the next flush instead of being lost
+var takeViewsScript = redis.NewScript(`
+local views = redis.call('HGETALL', KEYS[1])
+redis.call('DEL', KEYS[1])
+return views`)
+
+// RecordView counts a view of the auction unless the same viewer was
+// counted within window, reporting whether it counted
+func (c *RedisCache) RecordView(ctx context.Context, auctionID uuid.UUID, viewerHash string, window time.Duration) (bool, error) {
+	first, err := c.client.SetNX(ctx, ViewSeenKey(auctionID, viewerHash), 1, window).Result()
+	if err != nil || !first {
+		return false, err
+	}
+
+	if err := c.client.HIncrBy(ctx, PendingViewsKey, auctionID.String(), 1).Err(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// TakePendingViews removes and returns the views counted since the last
+// call, by auction
+func (c *RedisCache) TakePendingViews(ctx context.Context) (map[uuid.UUID]int64, error) {
+	fields, err := takeViewsScript.Run(ctx, c.client, []string{PendingViewsKey}).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	views := make(map[uuid.UUID]int64, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		id, err := uuid.Parse(fields[i])
+		if err != nil {
+			continue
+		}
+		count, err := strconv.ParseInt(fields[i+1], 10, 64)
+		if err != nil || count <= 0 {
+			continue
+		}
+		views[id] = count
+	}
+	return views, nil
+}
+
+// AddPendingViews puts views back to be written by a later flush
+func (c *RedisCache) AddPendingViews(ctx context.Context, views map[uuid.UUID]int64) error {
+	if len(views) == 0 {
+		return nil
+	}
+
+	pipe := c.client.Pipeline()
+	for id, count := range views {
+		pipe.HIncrBy(ctx, PendingViewsKey, id.String(), count)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Leases
+
+// renewLeaseScript extends the lease at KEYS[1] if ARGV[1] still holds it
+var renewLeaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0`)
+
+// releaseLeaseScript deletes the lease at KEYS[1] if ARGV[1] still holds it
+var releaseLeaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0`)
+
+// AcquireLease takes or renews the lease at key for owner, reporting whether
+// owner holds it afterwards. Leases lapse after ttl unless renewed.
+func (c *RedisCache) AcquireLease(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	renewed, err := renewLeaseScript.Run(ctx, c.client, []string{key}, owner, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	if renewed == 1 {
+		return true, nil
+	}
+
+	return c.client.SetNX(ctx, key, owner, ttl).Result()
+}
+
+// ReleaseLease gives up the lease at key if owner holds it
+func (c *RedisCache) ReleaseLease(ctx context.Context, key, owner string) error {
+	return releaseLeaseScript.Run(ctx, c.client, []string{key}, owner).Err()
+}
+
+// SchedulerLeaseKey is held by the instance running scheduled jobs
+const SchedulerLeaseKey = "lease:scheduler"
+
 // Rate limiting
-func (c *RedisCache) IncrementRateLimit(ctx context.Context, key string, window time.Duration) (int64, error) {
+
+// IncrementRateLimit counts a request in a fixed window and returns the
+// count so far and the time until the window resets. The expiry is only set
+// by the first request, so steady traffic can't keep a window open forever.
+func (c *RedisCache) IncrementRateLimit(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
 	pipe := c.client.Pipeline()
 	incr := pipe.Incr(ctx, key)
-	pipe.Expire(ctx, key, window)
+	pipe.ExpireNX(ctx, key, window)
+	ttl := pipe.PTTL(ctx, key)
 	_, err := pipe.Exec(ctx)
 	if err != nil {
-		return 0, err
+		return 0, 0, err
+	}
+
+	reset := ttl.Val()
+	if reset <= 0 {
+		reset = window
 	}
-	return incr.Val(), nil
+	return incr.Val(), reset, nil
 }
 
 func (c *RedisCache) GetRateLimit(ctx context.Context, key string) (int64, error) {
@@ -109,6 +288,31 @@ func (c *RedisCache) GetRateLimit(ctx context.Context, key string) (int64, error
 	return val, err
 }
 
+// GetRateLimitWindow returns the count in a rate limit window and the time
+// until it resets, zero when no window is open
+func (c *RedisCache) GetRateLimitWindow(ctx context.Context, key string) (int64, time.Duration, error) {
+	pipe := c.client.Pipeline()
+	get := pipe.Get(ctx, key)
+	ttl := pipe.PTTL(ctx, key)
+	_, err := pipe.Exec(ctx)
+	if err == redis.Nil {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	count, err := get.Int64()
+	if err != nil {
+		return 0, 0, err
+	}
+	reset := ttl.Val()
+	if reset < 0 {
+		reset = 0
+	}
+	return count, reset, nil
+}
+
 // Rate limit key generators
 func RateLimitKeyIP(ip string) string {
 	return fmt.Sprintf("ratelimit:ip:%s", ip)
@@ -126,6 +330,211 @@ func RateLimitKeyBid(userID uuid.UUID) string {
 	return fmt.Sprintf("ratelimit:bid:%s", userID.String())
 }
 
+func RateLimitKeySearch(ip string) string {
+	return fmt.Sprintf("ratelimit:search:%s", ip)
+}
+
+func RateLimitKeyEmbed(ip string) string {
+	return fmt.Sprintf("ratelimit:embed:%s", ip)
+}
+
+func RateLimitKeyMessage(userID uuid.UUID) string {
+	return fmt.Sprintf("ratelimit:message:%s", userID.String())
+}
+
+func RateLimitKeyTokenIP(ip string) string {
+	return fmt.Sprintf("ratelimit:token:ip:%s", ip)
+}
+
+// RateLimitKeyTokenSelector limits guesses against one emailed token, keyed
+// by a hash of its selector
+func RateLimitKeyTokenSelector(kind, selectorHash string) string {
+	return fmt.Sprintf("ratelimit:token:%s:%s", kind, selectorHash)
+}
+
+// API usage
+
+// apiUsageTTL keeps a day's usage counters until the daily rollup has had
+// several chances to copy them to the database
+const apiUsageTTL = 3 * 24 * time.Hour
+
+// APIUsageCount is a user's requests against one rate limit, and how many of
+// them it turned away
+type APIUsageCount struct {
+	Requests    int64
+	RateLimited int64
+}
+
+// APIUsageKey is a hash of a user's usage counters on one day (UTC), two
+// fields per rate limit
+func APIUsageKey(day time.Time, userID uuid.UUID) string {
+	return fmt.Sprintf("apiusage:%s:%s", day.UTC().Format("2006-01-02"), userID)
+}
+
+// APIUsageUsersKey is the set of users with usage counted on one day
+func APIUsageUsersKey(day time.Time) string {
+	return fmt.Sprintf("apiusage:users:%s", day.UTC().Format("2006-01-02"))
+}
+
+// RecordAPIUsage counts a user's request against a rate limit today
+func (c *RedisCache) RecordAPIUsage(ctx context.Context, userID uuid.UUID, limit string, limited bool) error {
+	now := time.Now()
+	key := APIUsageKey(now, userID)
+	usersKey := APIUsageUsersKey(now)
+
+	pipe := c.client.Pipeline()
+	pipe.HIncrBy(ctx, key, limit+":requests", 1)
+	if limited {
+		pipe.HIncrBy(ctx, key, limit+":limited", 1)
+	}
+	pipe.Expire(ctx, key, apiUsageTTL)
+	pipe.SAdd(ctx, usersKey, userID.String())
+	pipe.Expire(ctx, usersKey, apiUsageTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// GetAPIUsage returns a user's usage counters on one day, by rate limit
+func (c *RedisCache) GetAPIUsage(ctx context.Context, day time.Time, userID uuid.UUID) (map[string]APIUsageCount, error) {
+	fields, err := c.client.HGetAll(ctx, APIUsageKey(day, userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	return parseAPIUsage(fields), nil
+}
+
+// GetAPIUsageDay returns every user's usage counters on one day
+func (c *RedisCache) GetAPIUsageDay(ctx context.Context, day time.Time) (map[uuid.UUID]map[string]APIUsageCount, error) {
+	members, err := c.client.SMembers(ctx, APIUsageUsersKey(day)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(members))
+	pipe := c.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, 0, len(members))
+	for _, member := range members {
+		userID, err := uuid.Parse(member)
+		if err != nil {
+			continue
+		}
+		userIDs = append(userIDs, userID)
+		cmds = append(cmds, pipe.HGetAll(ctx, APIUsageKey(day, userID)))
+	}
+	if len(cmds) == 0 {
+		return nil, nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	usage := make(map[uuid.UUID]map[string]APIUsageCount, len(userIDs))
+	for i, userID := range userIDs {
+		if counts := parseAPIUsage(cmds[i].Val()); len(counts) > 0 {
+			usage[userID] = counts
+		}
+	}
+	return usage, nil
+}
+
+func parseAPIUsage(fields map[string]string) map[string]APIUsageCount {
+	counts := make(map[string]APIUsageCount)
+	for field, value := range fields {
+		i := strings.LastIndex(field, ":")
+		if i < 0 {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		limit := field[:i]
+		count := counts[limit]
+		switch field[i+1:] {
+		case "requests":
+			count.Requests = n
+		case "limited":
+			count.RateLimited = n
+		default:
+			continue
+		}
+		counts[limit] = count
+	}
+	return counts
+}
+
+// TokenFailuresKey counts failed token guesses across all users
+const TokenFailuresKey = "security:token_failures"
+
+// CertificationKey caches a grader's answer for a certification number
+func CertificationKey(grader, number string) string {
+	return fmt.Sprintf("certification:%s:%s", grader, number)
+}
+
+// AuctionKey caches an auction's details as stored, before any
+// viewer-specific changes
+func AuctionKey(auctionID uuid.UUID) string {
+	return fmt.Sprintf("auctions:detail:%s", auctionID)
+}
+
+// AuctionListKey caches the first page of a common auction listing, by a
+// hash of its filters
+func AuctionListKey(filterHash string) string {
+	return fmt.Sprintf("auctions:list:%s", filterHash)
+}
+
+// AuctionFacetsKey caches facet counts for a filter set, by its hash
+func AuctionFacetsKey(filterHash string) string {
+	return fmt.Sprintf("auctions:facets:%s", filterHash)
+}
+
+// SoldListingsKey caches a page of past sales for a filter set, by its hash
+func SoldListingsKey(filterHash string) string {
+	return fmt.Sprintf("auctions:sold:%s", filterHash)
+}
+
+// SimilarAuctionsKey caches the candidates for auctions similar to one
+func SimilarAuctionsKey(auctionID uuid.UUID) string {
+	return fmt.Sprintf("recommendations:similar:%s", auctionID)
+}
+
+// RecommendedAuctionsKey caches the candidates recommended to a user
+func RecommendedAuctionsKey(userID uuid.UUID) string {
+	return fmt.Sprintf("recommendations:user:%s", userID)
+}
+
+// TrendingAuctionsKey holds the ranked trending auction IDs, refreshed by
+// the scheduler
+const TrendingAuctionsKey = "auctions:trending"
+
+// OAuthLinkKey holds the user linking a provider login, by OAuth state
+func OAuthLinkKey(state string) string {
+	return fmt.Sprintf("oauth:link:%s", state)
+}
+
+// OAuthPendingLinkKey holds a provider login awaiting confirmation, by the
+// hash of its token
+func OAuthPendingLinkKey(tokenHash string) string {
+	return fmt.Sprintf("oauth:pending_link:%s", tokenHash)
+}
+
+// MaintenanceKey holds the maintenance mode status while it is on
+const MaintenanceKey = "maintenance:status"
+
+// MaintenanceChannel carries maintenance status changes to every instance's
+// WebSocket clients
+const MaintenanceChannel = "maintenance"
+
+// Presence key generators
+func PresenceKey(userID uuid.UUID) string {
+	return fmt.Sprintf("presence:%s", userID.String())
+}
+
+func LastSeenKey(userID uuid.UUID) string {
+	return fmt.Sprintf("presence:last_seen:%s", userID.String())
+}
+
 // Client returns the underlying redis client for advanced operations
 func (c *RedisCache) Client() *redis.Client {
 	return c.client
@@ -1,29 +1,237 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/pkg/origin"
 )
 
 type Config struct {
-	Server    ServerConfig
-	Database  DatabaseConfig
-	Redis     RedisConfig
-	JWT       JWTConfig
-	OAuth     OAuthConfig
-	S3        S3Config
-	Messaging MessagingConfig
+	Server        ServerConfig
+	Database      DatabaseConfig
+	Redis         RedisConfig
+	JWT           JWTConfig
+	OAuth         OAuthConfig
+	S3            S3Config
+	Messaging     MessagingConfig
+	Orders        OrdersConfig
+	Scheduler     SchedulerConfig
+	Notifications NotificationsConfig
+	Push          PushConfig
+	RateLimit     RateLimitConfig
+	AdminAudit    AdminAuditConfig
+	Sessions      SessionsConfig
+	Grading       GradingConfig
+	Webhooks      WebhooksConfig
+	Imports       ImportsConfig
+	Privacy       PrivacyConfig
+	Payouts       PayoutsConfig
+	ShareLinks    ShareLinksConfig
+	Money         MoneyConfig
+	Breakers      BreakersConfig
+	Residency     ResidencyConfig
+}
+
+// ResidencyConfig keeps personal data, the messages and addresses, inside
+// one region. Each store holding it declares which region it is hosted in,
+// and the server refuses to start when one is elsewhere. Regions are keys
+// of domain.ResidencyRegions.
+type ResidencyConfig struct {
+	// Region personal data must stay in; empty turns the controls off
+	Region string
+	// Where the database, which stores messages and addresses, is hosted
+	DatabaseRegion string
+	// Where the S3 bucket and the PII bucket are hosted
+	S3Region    string
+	PIIS3Region string
+}
+
+// BreakersConfig controls the circuit breakers and timeouts around Redis, S3
+// and the email provider
+type BreakersConfig struct {
+	// Consecutive failures that open a breaker
+	FailureThreshold int
+	// How long an open breaker fails calls before trying the dependency again
+	Cooldown time.Duration
+	// Longest a single call to each dependency may take
+	RedisTimeout time.Duration
+	S3Timeout    time.Duration
+	EmailTimeout time.Duration
+}
+
+// MoneyConfig controls how amounts are rounded
+type MoneyConfig struct {
+	// How fees are rounded to whole cents: half_even, half_up or down
+	FeeRounding domain.RoundingMode
+	// Minimum bid increments by price, as below:increment pairs parsed by
+	// domain.ParseIncrementLadder; empty leaves each auction's increment
+	BidIncrementLadder string
+}
+
+// ShareLinksConfig controls links that open private auctions
+type ShareLinksConfig struct {
+	// Secret signing share links; changing it revokes all of them
+	Secret string
+	// How long a new share link works
+	TTL time.Duration
+}
+
+// PayoutsConfig controls seller withdrawals
+type PayoutsConfig struct {
+	// Hex-encoded AES-256 key encrypting stored bank account numbers
+	EncryptionKey string
+	// Smallest amount in dollars a seller may withdraw
+	MinAmount int
+}
+
+// PrivacyConfig controls personal data exports and deleted accounts
+type PrivacyConfig struct {
+	// How long the emailed download link of an export works, at most 7
+	// days; the archive is deleted after that
+	ExportLinkTTL time.Duration
+	// How long an account deleted by an admin keeps its personal data
+	// before it may be purged
+	DeletedUserRetention time.Duration
+}
+
+// ImportsConfig limits bulk listing imports
+type ImportsConfig struct {
+	// Listings one import file may hold
+	MaxRows int
+	// Size of the largest import file accepted
+	MaxFileBytes int64
+	// How long fetching one listing image may take
+	ImageTimeout time.Duration
+	// Allows image URLs on private networks, for local development
+	AllowPrivateImageURLs bool
+}
+
+// WebhooksConfig controls deliveries to user-registered callback URLs
+type WebhooksConfig struct {
+	// How long a receiver gets to answer one delivery
+	Timeout time.Duration
+	// Failed deliveries are retried with exponential backoff up to this
+	// many attempts in total
+	MaxAttempts int
+	// Webhooks one user may register
+	MaxPerUser int
+	// Allows plain http and private network targets, for local development
+	AllowPrivateTargets bool
+}
+
+// GradingConfig holds the credentials for certification lookups. A grader is
+// enabled when its credentials are set.
+type GradingConfig struct {
+	PSAToken string
+	// Beckett has no public API; this is a lookup service URL with "{cert}"
+	// in place of the number
+	BGSLookupURL string
+	BGSAPIKey    string
+	// How long a grader's answer is reused before asking again
+	CacheTTL time.Duration
+}
+
+// SessionsConfig controls concurrent sessions and login location checks
+type SessionsConfig struct {
+	// Roles limited to one active session regardless of user preference
+	SingleSessionRoles []string
+	// Request header carrying the client country, set by the CDN or proxy
+	CountryHeader string
+	// Logins from two countries within this window are flagged
+	CountryHopWindow time.Duration
+}
+
+// AdminAuditConfig limits what is captured from admin write requests
+type AdminAuditConfig struct {
+	MaxBodyBytes int
+}
+
+// RateLimitConfig holds per-route request budgets
+type RateLimitConfig struct {
+	Default   RateLimitBudget
+	Auth      RateLimitBudget
+	Token     RateLimitBudget
+	Bid       RateLimitBudget
+	Search    RateLimitBudget
+	Messaging RateLimitBudget
+	Embed     RateLimitBudget
+}
+
+type RateLimitBudget struct {
+	Requests int
+	Window   time.Duration
+}
+
+type NotificationsConfig struct {
+	QueueSize int
+	Workers   int
+	// Secret signing the unsubscribe links in notification emails; changing
+	// it breaks every link already sent
+	UnsubscribeSecret string
+}
+
+// PushConfig holds the VAPID key pair web push is signed with. Push is
+// enabled when the keys are set; webpush.GenerateKeys makes a pair.
+type PushConfig struct {
+	VAPIDPublicKey  string
+	VAPIDPrivateKey string
+	// Contact push services can reach about abuse, a mailto: or https: URL
+	Subject string
+	// How long a push service gets to accept one push
+	Timeout time.Duration
+	// Browsers one user may subscribe; the oldest is dropped past this
+	MaxPerUser int
+	// Allows plain http and private network endpoints, for local development
+	AllowPrivateEndpoints bool
+}
+
+type SchedulerConfig struct {
+	EndBatchSize  int
+	EndWorkers    int
+	EndBatchDelay time.Duration
+	// How long a crashed leader holds the scheduler before another instance
+	// takes over
+	LeaderLeaseTTL time.Duration
+}
+
+type OrdersConfig struct {
+	PaymentWindow      time.Duration
+	SecondChanceWindow time.Duration
+	// How long a payment stays in escrow when the buyer never confirms delivery
+	EscrowHoldPeriod time.Duration
 }
 
 type MessagingConfig struct {
 	EncryptionKey string
+	// Days messages are kept before deletion; 0 keeps them indefinitely.
+	// Users may choose a shorter window.
+	RetentionDays int
 }
 
 type ServerConfig struct {
-	Port         string
-	Environment  string
+	Port        string
+	Environment string
+	// Origin patterns allowed to make cross-origin requests, e.g.
+	// https://app.example.com or https://*.example.com. Admins can allow
+	// more at runtime.
 	AllowOrigins []string
+	// Base URL of the web app, used for links in emails and OAuth redirects
+	FrontendURL string
+	// Base URL this server is reached at, used in embed code given to other
+	// sites
+	PublicURL string
+	// Prefix of the type URI of problem+json errors; the error code is
+	// appended, e.g. <prefix>/validation-error
+	ProblemTypeBaseURL string
+	// How long shutdown waits for in-flight work before abandoning it
+	ShutdownTimeout time.Duration
 }
 
 type DatabaseConfig struct {
@@ -49,10 +257,26 @@ type JWTConfig struct {
 	RefreshExpiration  time.Duration
 }
 
+// OAuthConfig configures the third-party login providers. A provider is
+// enabled when its client ID is set.
 type OAuthConfig struct {
-	GoogleClientID     string
-	GoogleClientSecret string
-	GoogleRedirectURL  string
+	Google OAuthProviderConfig
+	GitHub OAuthProviderConfig
+	Apple  AppleOAuthConfig
+}
+
+type OAuthProviderConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+type AppleOAuthConfig struct {
+	ClientID    string // Services ID
+	TeamID      string
+	KeyID       string
+	PrivateKey  string // PEM encoded .p8 key
+	RedirectURL string
 }
 
 type S3Config struct {
@@ -60,16 +284,40 @@ type S3Config struct {
 	AccessKeyID     string
 	SecretAccessKey string
 	BucketName      string
+	Region          string
 	UseSSL          bool
 	PublicURL       string
+	// Private bucket for archives of personal data, such as data exports;
+	// empty keeps them in the main bucket
+	PIIBucketName string
+	PIIRegion     string
+}
+
+// Origins allowed when CORS_ORIGINS isn't set. Deployed environments have
+// no default and must be configured explicitly.
+var defaultOrigins = map[string][]string{
+	"development": {"http://localhost:5173", "http://localhost:*", "http://127.0.0.1:*"},
+	"test":        {"http://localhost:*"},
 }
 
 func Load() *Config {
+	environment := getEnv("ENVIRONMENT", "development")
+	origins := getEnvList("CORS_ORIGINS", defaultOrigins[environment])
+	// CORS_ORIGIN is the single-origin setting from before CORS_ORIGINS
+	if legacy := os.Getenv("CORS_ORIGIN"); legacy != "" && os.Getenv("CORS_ORIGINS") == "" {
+		origins = []string{legacy}
+	}
+	frontendURL := strings.TrimSuffix(getEnv("FRONTEND_URL", firstExactOrigin(origins)), "/")
+
 	return &Config{
 		Server: ServerConfig{
-			Port:         getEnv("SERVER_PORT", "8080"),
-			Environment:  getEnv("ENVIRONMENT", "development"),
-			AllowOrigins: []string{getEnv("CORS_ORIGIN", "http://localhost:5173")},
+			Port:               getEnv("SERVER_PORT", "8080"),
+			Environment:        environment,
+			AllowOrigins:       origins,
+			FrontendURL:        frontendURL,
+			PublicURL:          strings.TrimSuffix(getEnv("PUBLIC_URL", "http://localhost:8080"), "/"),
+			ProblemTypeBaseURL: strings.TrimSuffix(getEnv("PROBLEM_TYPE_BASE_URL", frontendURL+"/problems"), "/"),
+			ShutdownTimeout:    time.Duration(getEnvInt("SHUTDOWN_TIMEOUT_SECONDS", 30)) * time.Second,
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -92,24 +340,205 @@ func Load() *Config {
 			RefreshExpiration:  time.Duration(getEnvInt("JWT_REFRESH_EXPIRATION_DAYS", 7)) * 24 * time.Hour,
 		},
 		OAuth: OAuthConfig{
-			GoogleClientID:     getEnv("GOOGLE_CLIENT_ID", ""),
-			GoogleClientSecret: getEnv("GOOGLE_CLIENT_SECRET", ""),
-			GoogleRedirectURL:  getEnv("GOOGLE_REDIRECT_URL", "http://localhost:8080/api/auth/google/callback"),
+			Google: OAuthProviderConfig{
+				ClientID:     getEnv("GOOGLE_CLIENT_ID", ""),
+				ClientSecret: getEnv("GOOGLE_CLIENT_SECRET", ""),
+				RedirectURL:  getEnv("GOOGLE_REDIRECT_URL", "http://localhost:8080/api/auth/google/callback"),
+			},
+			GitHub: OAuthProviderConfig{
+				ClientID:     getEnv("GITHUB_CLIENT_ID", ""),
+				ClientSecret: getEnv("GITHUB_CLIENT_SECRET", ""),
+				RedirectURL:  getEnv("GITHUB_REDIRECT_URL", "http://localhost:8080/api/auth/github/callback"),
+			},
+			Apple: AppleOAuthConfig{
+				ClientID:    getEnv("APPLE_CLIENT_ID", ""),
+				TeamID:      getEnv("APPLE_TEAM_ID", ""),
+				KeyID:       getEnv("APPLE_KEY_ID", ""),
+				PrivateKey:  getEnv("APPLE_PRIVATE_KEY", ""),
+				RedirectURL: getEnv("APPLE_REDIRECT_URL", "http://localhost:8080/api/auth/apple/callback"),
+			},
 		},
 		S3: S3Config{
 			Endpoint:        getEnv("S3_ENDPOINT", "localhost:9000"),
 			AccessKeyID:     getEnv("S3_ACCESS_KEY", "minioadmin"),
 			SecretAccessKey: getEnv("S3_SECRET_KEY", "minioadmin123"),
 			BucketName:      getEnv("S3_BUCKET", "auction-images"),
+			Region:          getEnv("S3_REGION", ""),
 			UseSSL:          getEnvBool("S3_USE_SSL", false),
 			PublicURL:       getEnv("S3_PUBLIC_URL", ""),
+			PIIBucketName:   getEnv("PII_S3_BUCKET", ""),
+			PIIRegion:       getEnv("PII_S3_REGION", ""),
 		},
 		Messaging: MessagingConfig{
 			EncryptionKey: getEnv("MESSAGING_ENCRYPTION_KEY", "a096604c247ad25b619e000b4e3569ad8a669699745f09e470df98e8e98a07b8"),
+			RetentionDays: getEnvInt("MESSAGE_RETENTION_DAYS", 365),
+		},
+		Orders: OrdersConfig{
+			PaymentWindow:      time.Duration(getEnvInt("ORDER_PAYMENT_WINDOW_DAYS", 3)) * 24 * time.Hour,
+			SecondChanceWindow: time.Duration(getEnvInt("SECOND_CHANCE_WINDOW_HOURS", 48)) * time.Hour,
+			EscrowHoldPeriod:   time.Duration(getEnvInt("ESCROW_HOLD_DAYS", 14)) * 24 * time.Hour,
+		},
+		Payouts: PayoutsConfig{
+			EncryptionKey: getEnv("PAYOUT_ENCRYPTION_KEY", "1afd309c7a24106e318b1152805c738c7a54eb7d7323f4609a4edfce1f1b1e09"),
+			MinAmount:     getEnvInt("PAYOUT_MIN_AMOUNT", 10),
+		},
+		Scheduler: SchedulerConfig{
+			EndBatchSize:   getEnvInt("SCHEDULER_END_BATCH_SIZE", 50),
+			EndWorkers:     getEnvInt("SCHEDULER_END_WORKERS", 8),
+			EndBatchDelay:  time.Duration(getEnvInt("SCHEDULER_END_BATCH_DELAY_MS", 500)) * time.Millisecond,
+			LeaderLeaseTTL: time.Duration(getEnvInt("SCHEDULER_LEADER_LEASE_SECONDS", 15)) * time.Second,
+		},
+		Notifications: NotificationsConfig{
+			QueueSize:         getEnvInt("NOTIFICATION_QUEUE_SIZE", 1000),
+			Workers:           getEnvInt("NOTIFICATION_WORKERS", 8),
+			UnsubscribeSecret: getEnv("UNSUBSCRIBE_SECRET", "your-super-secret-unsubscribe-key-change-in-production"),
+		},
+		Push: PushConfig{
+			VAPIDPublicKey:        getEnv("VAPID_PUBLIC_KEY", ""),
+			VAPIDPrivateKey:       getEnv("VAPID_PRIVATE_KEY", ""),
+			Subject:               getEnv("VAPID_SUBJECT", "mailto:support@auction-cards.example"),
+			Timeout:               time.Duration(getEnvInt("PUSH_TIMEOUT_SECONDS", 10)) * time.Second,
+			MaxPerUser:            getEnvInt("PUSH_MAX_SUBSCRIPTIONS_PER_USER", 10),
+			AllowPrivateEndpoints: getEnvBool("PUSH_ALLOW_PRIVATE_ENDPOINTS", false),
+		},
+		RateLimit: RateLimitConfig{
+			Default:   getEnvBudget("DEFAULT", 100, 60),
+			Auth:      getEnvBudget("AUTH", 5, 60),
+			Token:     getEnvBudget("TOKEN", 10, 900),
+			Bid:       getEnvBudget("BID", 10, 60),
+			Search:    getEnvBudget("SEARCH", 30, 60),
+			Messaging: getEnvBudget("MESSAGING", 20, 60),
+			Embed:     getEnvBudget("EMBED", 120, 60),
+		},
+		AdminAudit: AdminAuditConfig{
+			MaxBodyBytes: getEnvInt("ADMIN_AUDIT_MAX_BODY_BYTES", 16<<10),
+		},
+		Sessions: SessionsConfig{
+			SingleSessionRoles: getEnvList("SINGLE_SESSION_ROLES", []string{"admin"}),
+			CountryHeader:      getEnv("GEO_COUNTRY_HEADER", "CF-IPCountry"),
+			CountryHopWindow:   time.Duration(getEnvInt("LOGIN_COUNTRY_HOP_HOURS", 2)) * time.Hour,
+		},
+		Grading: GradingConfig{
+			PSAToken:     getEnv("PSA_API_TOKEN", ""),
+			BGSLookupURL: getEnv("BGS_CERT_LOOKUP_URL", ""),
+			BGSAPIKey:    getEnv("BGS_API_KEY", ""),
+			CacheTTL:     time.Duration(getEnvInt("GRADING_CACHE_HOURS", 24)) * time.Hour,
+		},
+		Webhooks: WebhooksConfig{
+			Timeout:             time.Duration(getEnvInt("WEBHOOK_TIMEOUT_SECONDS", 10)) * time.Second,
+			MaxAttempts:         getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			MaxPerUser:          getEnvInt("WEBHOOK_MAX_PER_USER", 10),
+			AllowPrivateTargets: getEnvBool("WEBHOOK_ALLOW_PRIVATE_TARGETS", false),
+		},
+		Imports: ImportsConfig{
+			MaxRows:               getEnvInt("IMPORT_MAX_ROWS", 500),
+			MaxFileBytes:          int64(getEnvInt("IMPORT_MAX_FILE_MB", 5)) << 20,
+			ImageTimeout:          time.Duration(getEnvInt("IMPORT_IMAGE_TIMEOUT_SECONDS", 15)) * time.Second,
+			AllowPrivateImageURLs: getEnvBool("IMPORT_ALLOW_PRIVATE_IMAGE_URLS", false),
+		},
+		Privacy: PrivacyConfig{
+			ExportLinkTTL:        time.Duration(getEnvInt("DATA_EXPORT_LINK_HOURS", 72)) * time.Hour,
+			DeletedUserRetention: time.Duration(getEnvInt("DELETED_USER_RETENTION_DAYS", 30)) * 24 * time.Hour,
+		},
+		ShareLinks: ShareLinksConfig{
+			Secret: getEnv("SHARE_LINK_SECRET", "your-super-secret-share-link-key-change-in-production"),
+			TTL:    time.Duration(getEnvInt("SHARE_LINK_DAYS", 30)) * 24 * time.Hour,
+		},
+		Money: MoneyConfig{
+			FeeRounding:        domain.RoundingMode(getEnv("FEE_ROUNDING", string(domain.RoundHalfEven))),
+			BidIncrementLadder: getEnv("BID_INCREMENT_LADDER", ""),
+		},
+		Residency: ResidencyConfig{
+			Region:          getEnv("DATA_REGION", ""),
+			DatabaseRegion:  getEnv("DATABASE_DATA_REGION", ""),
+			S3Region:        getEnv("S3_DATA_REGION", ""),
+			PIIS3Region:     getEnv("PII_S3_DATA_REGION", ""),
+		},
+		Breakers: BreakersConfig{
+			FailureThreshold: getEnvInt("BREAKER_FAILURE_THRESHOLD", 5),
+			Cooldown:         time.Duration(getEnvInt("BREAKER_COOLDOWN_SECONDS", 30)) * time.Second,
+			RedisTimeout:     time.Duration(getEnvInt("REDIS_TIMEOUT_MS", 1000)) * time.Millisecond,
+			S3Timeout:        time.Duration(getEnvInt("S3_TIMEOUT_SECONDS", 30)) * time.Second,
+			EmailTimeout:     time.Duration(getEnvInt("EMAIL_TIMEOUT_SECONDS", 10)) * time.Second,
 		},
 	}
 }
 
+// Validate reports configuration that would break the server at runtime, so
+// it can refuse to start instead
+func (c *Config) Validate() error {
+	if len(c.Server.AllowOrigins) == 0 {
+		return errors.New("CORS_ORIGINS must list at least one allowed origin")
+	}
+	for _, pattern := range c.Server.AllowOrigins {
+		p, err := origin.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("CORS_ORIGINS: %w", err)
+		}
+		if p.String() == "*" && c.Server.Environment == "production" {
+			return errors.New("CORS_ORIGINS: \"*\" is not allowed in production since requests carry credentials")
+		}
+	}
+
+	u, err := url.Parse(c.Server.FrontendURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("FRONTEND_URL must be an absolute http(s) URL, got %q", c.Server.FrontendURL)
+	}
+
+	if _, err := domain.ParseRoundingMode(string(c.Money.FeeRounding)); err != nil {
+		return fmt.Errorf("FEE_ROUNDING: %w", err)
+	}
+
+	if _, err := domain.ParseIncrementLadder(c.Money.BidIncrementLadder); err != nil {
+		return fmt.Errorf("BID_INCREMENT_LADDER: %w", err)
+	}
+
+	if (c.Push.VAPIDPublicKey == "") != (c.Push.VAPIDPrivateKey == "") {
+		return errors.New("VAPID_PUBLIC_KEY and VAPID_PRIVATE_KEY must be set together")
+	}
+
+	if err := c.validateResidency(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateResidency refuses setups that would keep personal data in more
+// than one region
+func (c *Config) validateResidency() error {
+	residency := c.Residency
+	if residency.Region == "" {
+		return nil
+	}
+	if _, ok := domain.ResidencyRegions[residency.Region]; !ok {
+		return fmt.Errorf("DATA_REGION: unknown region %q", residency.Region)
+	}
+
+	if residency.DatabaseRegion != residency.Region {
+		return fmt.Errorf("DATABASE_DATA_REGION must be %q since the database stores messages and addresses, got %q", residency.Region, residency.DatabaseRegion)
+	}
+	if c.S3.PIIBucketName == "" {
+		if residency.S3Region != residency.Region {
+			return fmt.Errorf("S3_DATA_REGION must be %q since data exports are stored in the S3 bucket, got %q; set PII_S3_BUCKET to keep them in another bucket", residency.Region, residency.S3Region)
+		}
+	} else if residency.PIIS3Region != residency.Region {
+		return fmt.Errorf("PII_S3_DATA_REGION must be %q, got %q", residency.Region, residency.PIIS3Region)
+	}
+	return nil
+}
+
+// firstExactOrigin picks the first origin without wildcards, which is the
+// best guess for the frontend URL when FRONTEND_URL isn't set
+func firstExactOrigin(origins []string) string {
+	for _, o := range origins {
+		if !strings.Contains(o, "*") {
+			return o
+		}
+	}
+	return ""
+}
+
 func (c *DatabaseConfig) DSN() string {
 	return "postgres://" + c.User + ":" + c.Password + "@" + c.Host + ":" + c.Port + "/" + c.DBName + "?sslmode=" + c.SSLMode
 }
@@ -142,3 +571,27 @@ func getEnvBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// getEnvList reads a comma-separated list, dropping empty entries
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getEnvBudget reads RATE_LIMIT_<NAME>_REQUESTS and RATE_LIMIT_<NAME>_WINDOW_SECONDS
+func getEnvBudget(name string, requests, windowSeconds int) RateLimitBudget {
+	return RateLimitBudget{
+		Requests: getEnvInt("RATE_LIMIT_"+name+"_REQUESTS", requests),
+		Window:   time.Duration(getEnvInt("RATE_LIMIT_"+name+"_WINDOW_SECONDS", windowSeconds)) * time.Second,
+	}
+}
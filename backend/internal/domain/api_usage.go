@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIUsageWindow is how far back the usage dashboard looks
+type APIUsageWindow string
+
+const (
+	APIUsageWindowDay   APIUsageWindow = "1d"
+	APIUsageWindowWeek  APIUsageWindow = "7d"
+	APIUsageWindowMonth APIUsageWindow = "30d"
+)
+
+// Days returns the number of days, today included, the window covers, or 0
+// for an unknown window
+func (w APIUsageWindow) Days() int {
+	switch w {
+	case APIUsageWindowDay:
+		return 1
+	case APIUsageWindowWeek:
+		return 7
+	case APIUsageWindowMonth:
+		return 30
+	}
+	return 0
+}
+
+// APIUsageDay counts a user's requests against one rate limit on one day
+// (UTC), including the ones the limit turned away
+type APIUsageDay struct {
+	UserID      uuid.UUID `json:"-" db:"user_id"`
+	Limit       string    `json:"-" db:"limit_name"`
+	Day         time.Time `json:"day" db:"day"`
+	Requests    int64     `json:"requests" db:"requests"`
+	RateLimited int64     `json:"rate_limited" db:"rate_limited"`
+}
+
+// APILimitUsage is how much of one rate limit a user has used, now and over
+// the dashboard's window
+type APILimitUsage struct {
+	Name          string `json:"name"`
+	Limit         int    `json:"limit"`
+	WindowSeconds int    `json:"window_seconds"`
+
+	// The current rate limit window
+	Used      int64      `json:"used"`
+	Remaining int64      `json:"remaining"`
+	ResetsAt  *time.Time `json:"resets_at,omitempty"`
+
+	// Totals over the dashboard's window, and the same by day, oldest first
+	Requests    int64         `json:"requests"`
+	RateLimited int64         `json:"rate_limited"`
+	Daily       []APIUsageDay `json:"daily"`
+}
+
+// APIUsage is the signed-in user's usage dashboard
+type APIUsage struct {
+	Window APIUsageWindow  `json:"window"`
+	From   time.Time       `json:"from"`
+	Limits []APILimitUsage `json:"limits"`
+}
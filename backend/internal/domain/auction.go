@@ -27,6 +27,58 @@ const (
 	ConditionPoor    ItemCondition = "poor"
 )
 
+type ShippingMethod string
+
+const (
+	ShippingMethodFlatRate   ShippingMethod = "flat_rate"
+	ShippingMethodFree       ShippingMethod = "free"
+	ShippingMethodPickupOnly ShippingMethod = "pickup_only"
+)
+
+// BuyNowRule controls when the buy-now option is withdrawn
+type BuyNowRule string
+
+const (
+	BuyNowAlways          BuyNowRule = "always"
+	BuyNowUntilFirstBid   BuyNowRule = "until_first_bid"
+	BuyNowUntilReserveMet BuyNowRule = "until_reserve_met"
+)
+
+// Grader is a card grading company whose certifications can be verified
+type Grader string
+
+const (
+	GraderPSA Grader = "psa"
+	GraderBGS Grader = "bgs"
+)
+
+type CertificationStatus string
+
+const (
+	CertificationPending     CertificationStatus = "pending"
+	CertificationVerified    CertificationStatus = "verified"
+	CertificationNotFound    CertificationStatus = "not_found"
+	CertificationUnavailable CertificationStatus = "unavailable"
+)
+
+// Certification is the grading slab certification a seller listed, with
+// what the grader has on record for it once verified
+type Certification struct {
+	Grader    Grader              `json:"grader"`
+	Number    string              `json:"cert_number"`
+	Status    CertificationStatus `json:"status"`
+	Grade     *string             `json:"grade,omitempty"`
+	Subject   *string             `json:"subject,omitempty"`
+	Year      *string             `json:"year,omitempty"`
+	CheckedAt *time.Time          `json:"checked_at,omitempty"`
+}
+
+// Verified reports whether the grader confirmed the certification, which
+// listings show as a badge
+func (c *Certification) Verified() bool {
+	return c != nil && c.Status == CertificationVerified
+}
+
 type Auction struct {
 	ID            uuid.UUID       `json:"id" db:"id"`
 	SellerID      uuid.UUID       `json:"seller_id" db:"seller_id"`
@@ -37,6 +89,8 @@ type Auction struct {
 	StartingPrice decimal.Decimal `json:"starting_price" db:"starting_price"`
 	ReservePrice  *decimal.Decimal `json:"reserve_price,omitempty" db:"reserve_price"`
 	BuyNowPrice   *decimal.Decimal `json:"buy_now_price,omitempty" db:"buy_now_price"`
+	BuyNowRule    BuyNowRule      `json:"buy_now_rule" db:"buy_now_rule"`
+	AcceptsOffers bool            `json:"accepts_offers" db:"accepts_offers"`
 	CurrentPrice  decimal.Decimal `json:"current_price" db:"current_price"`
 	BidIncrement  decimal.Decimal `json:"bid_increment" db:"bid_increment"`
 	StartTime     time.Time       `json:"start_time" db:"start_time"`
@@ -45,7 +99,21 @@ type Auction struct {
 	WinnerID      *uuid.UUID      `json:"winner_id,omitempty" db:"winner_id"`
 	WinningBidID  *uuid.UUID      `json:"winning_bid_id,omitempty" db:"winning_bid_id"`
 	ViewsCount    int             `json:"views_count" db:"views_count"`
+	WatchCount    int             `json:"watch_count" db:"watch_count"`
 	BidCount      int             `json:"bid_count" db:"bid_count"`
+	AutoRelist     bool            `json:"auto_relist" db:"auto_relist"`
+	RelistedFromID *uuid.UUID      `json:"relisted_from_id,omitempty" db:"relisted_from_id"`
+	Visibility     AuctionVisibility `json:"visibility" db:"visibility"`
+	// Bidders need the seller's approval, asked for by answering these
+	// questions
+	RequiresBidApproval  bool     `json:"requires_bid_approval" db:"requires_bid_approval"`
+	BidApprovalQuestions []string `json:"bid_approval_questions,omitempty" db:"bid_approval_questions"`
+	// What bidders' accounts must meet to bid
+	BidderRequirements BidderRequirements `json:"bidder_requirements" db:"bidder_requirements"`
+	Currency       Currency        `json:"currency" db:"currency"`
+	// Live event the auction is a lot of, if any
+	LiveEventID    *uuid.UUID      `json:"live_event_id,omitempty" db:"live_event_id"`
+	ShareVersion   int             `json:"-" db:"share_version"`
 	Version       int             `json:"-" db:"version"`
 	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time       `json:"updated_at" db:"updated_at"`
@@ -55,28 +123,192 @@ type Auction struct {
 	Category *Category        `json:"category,omitempty"`
 	Images   []AuctionImage   `json:"images,omitempty"`
 	Winner   *PublicUser      `json:"winner,omitempty"`
+	Shipping []AuctionShipping `json:"shipping_options,omitempty"`
+	// Items of a lot listing; empty for a single item
+	Items    []AuctionItem    `json:"items,omitempty"`
+
+	// Grading certification, if the seller listed one
+	Certification *Certification `json:"certification,omitempty"`
+
+	// Computed: whether buy-now can still be used
+	BuyNowAvailable bool `json:"buy_now_available"`
+
+	// Computed: reserve status, shown even when the amount is hidden
+	HasReserve bool `json:"has_reserve"`
+	ReserveMet bool `json:"reserve_met"`
+
+	// Computed: the lowest bid accepted next
+	MinNextBid decimal.Decimal `json:"min_next_bid"`
+
+	// Estimated, on an active auction's detail: where the price is likely
+	// to end, shown to the seller, and for a viewer who set a proxy max bid
+	// whether it falls short
+	PriceEstimate       *PriceEstimate `json:"price_estimate,omitempty"`
+	MaxBidUnlikelyToWin *bool          `json:"max_bid_unlikely_to_win,omitempty"`
+
+	// Computed, on the detail of an auction that restricts bidders: whether
+	// the signed-in viewer can bid, and what they are missing if not
+	BidEligibility *BidEligibility `json:"bid_eligibility,omitempty"`
+
+	// Viewer state, filled in lists for a signed-in viewer
+	IsWatching   *bool            `json:"is_watching,omitempty"`
+	MyMaxBid     *decimal.Decimal `json:"my_max_bid,omitempty"`
+	AmHighBidder *bool            `json:"am_high_bidder,omitempty"`
+}
+
+// Price returns an amount in the auction's currency
+func (a *Auction) Price(amount decimal.Decimal) Money {
+	return NewMoney(amount, a.Currency)
+}
+
+// IsReserveMet reports whether the current price would sell the item. An
+// auction without a reserve always meets it.
+func (a *Auction) IsReserveMet() bool {
+	if a.ReservePrice == nil {
+		return true
+	}
+	return a.BidCount > 0 && a.CurrentPrice.GreaterThanOrEqual(*a.ReservePrice)
+}
+
+// SetReserveStatus fills the computed reserve fields, and the minimum next
+// bid along with them. The reserve amount is cleared unless revealReserve is
+// set, which callers only do for the seller.
+func (a *Auction) SetReserveStatus(revealReserve bool) {
+	a.HasReserve = a.ReservePrice != nil
+	a.ReserveMet = a.IsReserveMet()
+	a.MinNextBid = a.MinimumBid()
+	if !revealReserve {
+		a.ReservePrice = nil
+	}
+}
+
+// CanBuyNow reports whether the buy-now price is still on offer under the
+// auction's buy-now rule
+func (a *Auction) CanBuyNow() bool {
+	if a.BuyNowPrice == nil {
+		return false
+	}
+
+	switch a.BuyNowRule {
+	case BuyNowAlways:
+		return true
+	case BuyNowUntilReserveMet:
+		if a.BidCount == 0 {
+			return true
+		}
+		return a.ReservePrice != nil && a.CurrentPrice.LessThan(*a.ReservePrice)
+	default:
+		return a.BidCount == 0
+	}
 }
 
 type AuctionImage struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	AuctionID uuid.UUID `json:"auction_id" db:"auction_id"`
-	URL       string    `json:"url" db:"url"`
-	Position  int       `json:"position" db:"position"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID           uuid.UUID `json:"id" db:"id"`
+	AuctionID    uuid.UUID `json:"auction_id" db:"auction_id"`
+	URL          string    `json:"url" db:"url"`
+	ThumbnailURL *string   `json:"thumbnail_url,omitempty" db:"thumbnail_url"`
+	MediumURL    *string   `json:"medium_url,omitempty" db:"medium_url"`
+	LargeURL     *string   `json:"large_url,omitempty" db:"large_url"`
+	Position     int       `json:"position" db:"position"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// ForListing returns the image as served in listings: the thumbnail
+// replaces the original and the larger variants are left out
+func (img AuctionImage) ForListing() AuctionImage {
+	listed := img
+	if img.ThumbnailURL != nil {
+		listed.URL = *img.ThumbnailURL
+	}
+	listed.MediumURL = nil
+	listed.LargeURL = nil
+	return listed
+}
+
+// URLs returns the original and every stored variant
+func (img AuctionImage) URLs() []string {
+	urls := []string{img.URL}
+	for _, variant := range []*string{img.ThumbnailURL, img.MediumURL, img.LargeURL} {
+		if variant != nil {
+			urls = append(urls, *variant)
+		}
+	}
+	return urls
+}
+
+// AuctionItem is one of several items sold together in a lot. Its images
+// are some of the auction's images.
+type AuctionItem struct {
+	ID          uuid.UUID      `json:"id" db:"id"`
+	AuctionID   uuid.UUID      `json:"auction_id" db:"auction_id"`
+	Position    int            `json:"position" db:"position"`
+	Title       string         `json:"title" db:"title"`
+	Condition   *ItemCondition `json:"condition,omitempty" db:"condition"`
+	Description *string        `json:"description,omitempty" db:"description"`
+	ImageIDs    []uuid.UUID    `json:"image_ids"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+}
+
+type AuctionShipping struct {
+	ID        uuid.UUID       `json:"id" db:"id"`
+	AuctionID uuid.UUID       `json:"auction_id" db:"auction_id"`
+	Method    ShippingMethod  `json:"method" db:"method"`
+	Cost      decimal.Decimal `json:"cost" db:"cost"`
+	Regions   []string        `json:"regions" db:"regions"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+
+	// Computed: current price plus shipping cost
+	TotalCost decimal.Decimal `json:"total_cost"`
 }
 
 // Request/Response DTOs
+type AuctionItemRequest struct {
+	Title       string      `json:"title" validate:"required,min=3,max=255"`
+	Condition   *string     `json:"condition" validate:"omitempty,oneof=new like_new good fair poor"`
+	Description *string     `json:"description" validate:"omitempty,max=2000"`
+	ImageIDs    []uuid.UUID `json:"image_ids" validate:"omitempty,max=20"`
+}
+
+type ShippingOptionRequest struct {
+	Method  string   `json:"method" validate:"required,oneof=flat_rate free pickup_only"`
+	Cost    *string  `json:"cost" validate:"omitempty,numeric"`
+	Regions []string `json:"regions" validate:"omitempty,dive,min=2,max=100"`
+}
+
 type CreateAuctionRequest struct {
 	CategoryID    *uuid.UUID `json:"category_id" validate:"omitempty,uuid"`
 	Title         string     `json:"title" validate:"required,min=3,max=255"`
 	Description   *string    `json:"description" validate:"omitempty,max=5000"`
 	Condition     *string    `json:"condition" validate:"omitempty,oneof=new like_new good fair poor"`
+	Currency      *string    `json:"currency" validate:"omitempty,oneof=USD EUR BAM"`
 	StartingPrice string     `json:"starting_price" validate:"required,numeric,gt=0"`
 	ReservePrice  *string    `json:"reserve_price" validate:"omitempty,numeric,gtefield=StartingPrice"`
 	BuyNowPrice   *string    `json:"buy_now_price" validate:"omitempty,numeric,gtefield=StartingPrice"`
+	BuyNowRule    *string    `json:"buy_now_rule" validate:"omitempty,oneof=always until_first_bid until_reserve_met"`
 	BidIncrement  *string    `json:"bid_increment" validate:"omitempty,numeric,gt=0"`
 	StartTime     time.Time  `json:"start_time" validate:"required"`
-	EndTime       time.Time  `json:"end_time" validate:"required,gtfield=StartTime"`
+	// May be left out in a category with a default duration
+	EndTime       time.Time  `json:"end_time" validate:"omitempty,gtfield=StartTime"`
+	AutoRelist    bool       `json:"auto_relist"`
+	AcceptsOffers bool       `json:"accepts_offers"`
+	Visibility    *string    `json:"visibility" validate:"omitempty,oneof=public private"`
+
+	// Require bidders to be approved, optionally answering questions first
+	RequiresBidApproval  bool     `json:"requires_bid_approval"`
+	BidApprovalQuestions []string `json:"bid_approval_questions" validate:"omitempty,max=5,dive,min=3,max=300"`
+
+	// Minimum rating, verified email or account age asked of bidders
+	BidderRequirements *BidderRequirements `json:"bidder_requirements"`
+
+	// Grading certification to verify, e.g. a PSA or BGS cert number
+	CertGrader *string `json:"cert_grader" validate:"required_with=CertNumber,omitempty,oneof=psa bgs"`
+	CertNumber *string `json:"cert_number" validate:"required_with=CertGrader,omitempty,alphanum,min=4,max=20"`
+
+	ShippingOptions []ShippingOptionRequest `json:"shipping_options" validate:"omitempty,max=10,dive"`
+
+	// Items of a lot listing. Their images are uploaded after the auction
+	// is created and attached with an update.
+	Items []AuctionItemRequest `json:"items" validate:"omitempty,max=50,dive"`
 }
 
 type UpdateAuctionRequest struct {
@@ -84,12 +316,52 @@ type UpdateAuctionRequest struct {
 	Title         *string    `json:"title" validate:"omitempty,min=3,max=255"`
 	Description   *string    `json:"description" validate:"omitempty,max=5000"`
 	Condition     *string    `json:"condition" validate:"omitempty,oneof=new like_new good fair poor"`
+	Currency      *string    `json:"currency" validate:"omitempty,oneof=USD EUR BAM"`
 	StartingPrice *string    `json:"starting_price" validate:"omitempty,numeric,gt=0"`
 	ReservePrice  *string    `json:"reserve_price" validate:"omitempty,numeric"`
 	BuyNowPrice   *string    `json:"buy_now_price" validate:"omitempty,numeric"`
+	BuyNowRule    *string    `json:"buy_now_rule" validate:"omitempty,oneof=always until_first_bid until_reserve_met"`
 	BidIncrement  *string    `json:"bid_increment" validate:"omitempty,numeric,gt=0"`
 	StartTime     *time.Time `json:"start_time"`
 	EndTime       *time.Time `json:"end_time"`
+	AutoRelist    *bool      `json:"auto_relist"`
+	AcceptsOffers *bool      `json:"accepts_offers"`
+	Visibility    *string    `json:"visibility" validate:"omitempty,oneof=public private"`
+
+	// Bid approval can only be switched on before bidding starts. The
+	// questions are replaced when present.
+	RequiresBidApproval  *bool    `json:"requires_bid_approval"`
+	BidApprovalQuestions []string `json:"bid_approval_questions" validate:"omitempty,max=5,dive,min=3,max=300"`
+
+	// Replaces the bidder requirements when present. Bids already placed
+	// stand; only new bids are checked.
+	BidderRequirements *BidderRequirements `json:"bidder_requirements"`
+
+	// Replaces the certification when present; an empty number removes it
+	CertGrader *string `json:"cert_grader" validate:"omitempty,oneof=psa bgs"`
+	CertNumber *string `json:"cert_number" validate:"omitempty,alphanum,min=4,max=20"`
+
+	// Replaces all shipping options when present; an empty list clears them
+	ShippingOptions []ShippingOptionRequest `json:"shipping_options" validate:"omitempty,max=10,dive"`
+
+	// Replaces all lot items when present; an empty list clears them
+	Items []AuctionItemRequest `json:"items" validate:"omitempty,max=50,dive"`
+}
+
+type LowerReserveRequest struct {
+	ReservePrice string `json:"reserve_price" validate:"required,numeric"`
+}
+
+type ForceEndOutcome string
+
+const (
+	ForceEndVoid  ForceEndOutcome = "void"
+	ForceEndAward ForceEndOutcome = "award"
+)
+
+type ForceEndAuctionRequest struct {
+	Reason  string `json:"reason" validate:"required,min=3,max=1000"`
+	Outcome string `json:"outcome" validate:"required,oneof=void award"`
 }
 
 type AuctionListParams struct {
@@ -99,9 +371,38 @@ type AuctionListParams struct {
 	Search     *string        `json:"search"`
 	MinPrice   *decimal.Decimal `json:"min_price"`
 	MaxPrice   *decimal.Decimal `json:"max_price"`
-	SortBy     string         `json:"sort_by"` // ending_soon, newest, price_low, price_high, most_bids
+	SortBy     string         `json:"sort_by"` // ending_soon, newest, price_low, price_high, most_bids, discovery
+	// Seed fixes the discovery order, so the same seed pages through the
+	// same shuffle
+	Seed       string         `json:"seed"`
+	CertStatus *CertificationStatus `json:"cert_status"`
+	Condition  *ItemCondition `json:"condition"`
+	// EndsWithin keeps auctions ending no later than this from now
+	EndsWithin *time.Duration `json:"ends_within"`
+	HasBuyNow  *bool          `json:"has_buy_now"`
+	HasReserve *bool          `json:"has_reserve"`
+	FreeShipping bool         `json:"free_shipping"`
+	// MinSellerRating is the lowest average seller rating to include;
+	// sellers without ratings are left out
+	MinSellerRating *float64 `json:"min_seller_rating"`
 	Page       int            `json:"page"`
 	Limit      int            `json:"limit"`
+
+	// Leave out listings of sellers away in hide mode
+	HideVacationing bool `json:"-"`
+	// Leave out private auctions, except those ViewerID sells or was
+	// invited to
+	HidePrivate bool       `json:"-"`
+	ViewerID    *uuid.UUID `json:"-"`
+	// Keep only auctions that started after this, for what is new since a
+	// user last looked
+	StartedAfter *time.Time `json:"-"`
+}
+
+type EndTimeSuggestion struct {
+	RequestedEndTime time.Time `json:"requested_end_time"`
+	SuggestedEndTime time.Time `json:"suggested_end_time"`
+	Adjusted         bool      `json:"adjusted"`
 }
 
 type AuctionListResponse struct {
@@ -109,4 +410,43 @@ type AuctionListResponse struct {
 	TotalCount int       `json:"total_count"`
 	Page       int       `json:"page"`
 	TotalPages int       `json:"total_pages"`
+	Seed       string    `json:"seed,omitempty"`
+}
+
+// PriceFacetBounds are the edges of the price buckets in facet counts. The
+// first bucket starts at zero and the last has no upper bound.
+var PriceFacetBounds = []decimal.Decimal{
+	decimal.NewFromInt(25),
+	decimal.NewFromInt(50),
+	decimal.NewFromInt(100),
+	decimal.NewFromInt(250),
+	decimal.NewFromInt(500),
+	decimal.NewFromInt(1000),
+}
+
+// AuctionFacets counts the auctions matching a filter set. Each facet leaves
+// out its own filter so the other choices can still be offered.
+type AuctionFacets struct {
+	Total        int              `json:"total"`
+	Categories   []CategoryFacet  `json:"categories"`
+	Conditions   []ConditionFacet `json:"conditions"`
+	PriceBuckets []PriceFacet     `json:"price_buckets"`
+}
+
+type CategoryFacet struct {
+	CategoryID uuid.UUID `json:"category_id"`
+	Name       string    `json:"name"`
+	Slug       string    `json:"slug"`
+	Count      int       `json:"count"`
+}
+
+type ConditionFacet struct {
+	Condition ItemCondition `json:"condition"`
+	Count     int           `json:"count"`
+}
+
+type PriceFacet struct {
+	Min   decimal.Decimal  `json:"min"`
+	Max   *decimal.Decimal `json:"max,omitempty"`
+	Count int              `json:"count"`
 }
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audited actions
+const (
+	AuditActionAuctionForceEnd = "auction.force_end"
+	AuditActionAuctionCancel   = "auction.cancel"
+	AuditActionDisputeResolve  = "dispute.resolve"
+	AuditActionEscrowRelease   = "escrow.release"
+	AuditActionPayoutApprove   = "payout.approve"
+	AuditActionPayoutComplete  = "payout.complete"
+	AuditActionPayoutReject    = "payout.reject"
+	AuditActionBidCancel       = "bid.cancel"
+	AuditActionReserveLower    = "auction.reserve_lower"
+	AuditActionSnipeSchedule   = "snipe.schedule"
+	AuditActionSnipeCancel     = "snipe.cancel"
+	AuditActionSnipeExecute    = "snipe.execute"
+	AuditActionTranscriptView  = "conversation.transcript_view"
+	AuditActionModerationClose = "moderation.close"
+	AuditActionOriginAllow     = "cors_origin.allow"
+	AuditActionOriginRevoke    = "cors_origin.revoke"
+	AuditActionAdminRequest    = "admin.request"
+	AuditActionTokenGuessing   = "security.token_guessing"
+	AuditActionOfferAccept     = "offer.accept"
+	AuditActionLoginReport     = "security.login_reported"
+	AuditActionUserMerge       = "user.merge"
+	AuditActionUserDelete      = "user.delete"
+	AuditActionUserPurge       = "user.purge"
+	AuditActionPasswordSet     = "security.password_set"
+	AuditActionOAuthLink       = "security.oauth_linked"
+	AuditActionOAuthUnlink     = "security.oauth_unlinked"
+	AuditActionFeature         = "auction.feature"
+	AuditActionUnfeature       = "auction.unfeature"
+)
+
+// Audited entity types
+const (
+	AuditEntityAuction      = "auction"
+	AuditEntityDispute      = "dispute"
+	AuditEntityOrder        = "order"
+	AuditEntitySnipe        = "snipe"
+	AuditEntityConversation = "conversation"
+	AuditEntityModeration   = "moderation_case"
+	AuditEntityOrigin       = "cors_origin"
+	AuditEntityHTTPRequest  = "http_request"
+	AuditEntityAuthToken    = "auth_token"
+	AuditEntityLoginEvent   = "login_event"
+	AuditEntityUser         = "user"
+	AuditEntityPayout       = "payout"
+)
+
+type AuditLog struct {
+	ID         uuid.UUID              `json:"id" db:"id"`
+	ActorID    *uuid.UUID             `json:"actor_id,omitempty" db:"actor_id"`
+	Action     string                 `json:"action" db:"action"`
+	EntityType string                 `json:"entity_type" db:"entity_type"`
+	EntityID   *uuid.UUID             `json:"entity_id,omitempty" db:"entity_id"`
+	Reason     *string                `json:"reason,omitempty" db:"reason"`
+	Metadata   map[string]interface{} `json:"metadata" db:"metadata"`
+	CreatedAt  time.Time              `json:"created_at" db:"created_at"`
+}
+
+type AuditLogListParams struct {
+	ActorID    *uuid.UUID `json:"actor_id"`
+	Action     *string    `json:"action"`
+	EntityType *string    `json:"entity_type"`
+	EntityID   *uuid.UUID `json:"entity_id"`
+	Page       int        `json:"page"`
+	Limit      int        `json:"limit"`
+}
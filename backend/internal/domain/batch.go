@@ -0,0 +1,32 @@
+package domain
+
+import "github.com/google/uuid"
+
+// MaxBatchGetIDs caps how many entities one batch request may fetch
+const MaxBatchGetIDs = 100
+
+// BatchGetRequest lists the IDs of the entities to fetch. Repeated IDs are
+// fetched once.
+type BatchGetRequest struct {
+	IDs []uuid.UUID `json:"ids" validate:"required,min=1,max=100"`
+}
+
+// AuctionBatchResponse holds the auctions found, in request order, and the
+// IDs of those that don't exist or the viewer may not see
+type AuctionBatchResponse struct {
+	Auctions []Auction   `json:"auctions"`
+	Missing  []uuid.UUID `json:"missing"`
+}
+
+// UserProfile is a user's public profile with their rating summary
+type UserProfile struct {
+	User   *PublicUser        `json:"user"`
+	Rating *UserRatingSummary `json:"rating"`
+}
+
+// UserBatchResponse holds the profiles found, in request order, and the IDs
+// of users that don't exist or are hidden
+type UserBatchResponse struct {
+	Users   []UserProfile `json:"users"`
+	Missing []uuid.UUID   `json:"missing"`
+}
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,6 +17,8 @@ type Bid struct {
 	IsAutoBid  bool            `json:"is_auto_bid" db:"is_auto_bid"`
 	MaxAutoBid *decimal.Decimal `json:"max_auto_bid,omitempty" db:"max_auto_bid"`
 	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+	CancelledAt  *time.Time `json:"cancelled_at,omitempty" db:"cancelled_at"`
+	CancelReason *string    `json:"cancel_reason,omitempty" db:"cancel_reason"`
 
 	// Joined fields
 	Bidder *PublicUser `json:"bidder,omitempty"`
@@ -33,18 +37,37 @@ type BidResponse struct {
 	NewEndTime     *time.Time      `json:"new_end_time,omitempty"`
 }
 
+type CancelBidRequest struct {
+	Reason string `json:"reason" validate:"required,min=5,max=500"`
+}
+
+type CancelAuctionRequest struct {
+	Reason string `json:"reason" validate:"required,min=5,max=1000"`
+}
+
 type BidListParams struct {
 	AuctionID *uuid.UUID `json:"auction_id"`
 	BidderID  *uuid.UUID `json:"bidder_id"`
 	Page      int        `json:"page"`
 	Limit     int        `json:"limit"`
+	// Cursor switches to keyset pagination, ignoring Page
+	Cursor *Cursor `json:"-"`
+}
+
+// BidderStanding is where a bidder stands in one auction: their highest
+// active bid, whether it currently leads, and the highest proxy max they set
+type BidderStanding struct {
+	MaxBid       decimal.Decimal
+	IsHighBidder bool
+	MaxAutoBid   *decimal.Decimal
 }
 
 type BidListResponse struct {
-	Bids       []Bid `json:"bids"`
-	TotalCount int   `json:"total_count"`
-	Page       int   `json:"page"`
-	TotalPages int   `json:"total_pages"`
+	Bids       []Bid  `json:"bids"`
+	TotalCount int    `json:"total_count"`
+	Page       int    `json:"page"`
+	TotalPages int    `json:"total_pages"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // WebSocket messages
@@ -54,7 +77,14 @@ const (
 	WSMessageNewBid          WSMessageType = "new_bid"
 	WSMessageAuctionExtended WSMessageType = "auction_extended"
 	WSMessageAuctionEnded    WSMessageType = "auction_ended"
+	WSMessageBidCancelled    WSMessageType = "bid_cancelled"
+	WSMessageAuctionCancelled WSMessageType = "auction_cancelled"
+	WSMessageReserveMet      WSMessageType = "reserve_met"
+	WSMessageBuyNowRemoved   WSMessageType = "buy_now_removed"
+	WSMessageMaintenance     WSMessageType = "maintenance"
 	WSMessageError           WSMessageType = "error"
+	WSMessageSnapshot        WSMessageType = "snapshot"
+	WSMessageCountdown       WSMessageType = "countdown"
 )
 
 type WSMessage struct {
@@ -62,6 +92,20 @@ type WSMessage struct {
 	Payload interface{}   `json:"payload"`
 }
 
+// Messages a client sends on an /ws/auctions connection to choose which
+// auctions it follows
+type WSClientMessageType string
+
+const (
+	WSClientSubscribe   WSClientMessageType = "subscribe"
+	WSClientUnsubscribe WSClientMessageType = "unsubscribe"
+)
+
+type WSClientMessage struct {
+	Type       WSClientMessageType `json:"type"`
+	AuctionIDs []uuid.UUID         `json:"auction_ids"`
+}
+
 type WSNewBidPayload struct {
 	BidID      uuid.UUID       `json:"bid_id"`
 	AuctionID  uuid.UUID       `json:"auction_id"`
@@ -69,6 +113,8 @@ type WSNewBidPayload struct {
 	BidderName string          `json:"bidder_name"`
 	Amount     decimal.Decimal `json:"amount"`
 	BidCount   int             `json:"bid_count"`
+	ReserveMet bool            `json:"reserve_met"`
+	MinNextBid decimal.Decimal `json:"min_next_bid"`
 	Timestamp  time.Time       `json:"timestamp"`
 }
 
@@ -83,4 +129,64 @@ type WSAuctionEndedPayload struct {
 	WinnerName  *string          `json:"winner_name"`
 	FinalPrice  decimal.Decimal  `json:"final_price"`
 	Status      AuctionStatus    `json:"status"`
+	Forced      bool             `json:"forced,omitempty"`
+	Reason      *string          `json:"reason,omitempty"`
+}
+
+type WSBidCancelledPayload struct {
+	BidID        uuid.UUID       `json:"bid_id"`
+	AuctionID    uuid.UUID       `json:"auction_id"`
+	CurrentPrice    decimal.Decimal `json:"current_price"`
+	BidCount        int             `json:"bid_count"`
+	BuyNowAvailable bool            `json:"buy_now_available"`
+	ReserveMet      bool            `json:"reserve_met"`
+}
+
+type WSAuctionCancelledPayload struct {
+	AuctionID uuid.UUID `json:"auction_id"`
+	Reason    string    `json:"reason"`
+}
+
+type WSReserveMetPayload struct {
+	AuctionID    uuid.UUID       `json:"auction_id"`
+	CurrentPrice decimal.Decimal `json:"current_price"`
+}
+
+type WSBuyNowRemovedPayload struct {
+	AuctionID uuid.UUID `json:"auction_id"`
+}
+
+// WSAuctionSnapshotPayload is the auction's state, sent first to a client
+// that connects so it needn't fetch it separately. Everything sent after it
+// happened no earlier than the snapshot was taken.
+type WSAuctionSnapshotPayload struct {
+	AuctionID       uuid.UUID       `json:"auction_id"`
+	Status          AuctionStatus   `json:"status"`
+	CurrentPrice    decimal.Decimal `json:"current_price"`
+	Currency        Currency        `json:"currency"`
+	BidCount        int             `json:"bid_count"`
+	ReserveMet      bool            `json:"reserve_met"`
+	BuyNowAvailable bool            `json:"buy_now_available"`
+	EndTime         time.Time       `json:"end_time"`
+	// Masked name of the high bidder, the same throughout the auction
+	HighBidder   *string   `json:"high_bidder"`
+	IsHighBidder bool      `json:"is_high_bidder"`
+	ServerTime   time.Time `json:"server_time"`
+}
+
+// WSCountdownPayload keeps clients' countdowns in step with the server's
+// clock, including through anti-snipe extensions
+type WSCountdownPayload struct {
+	AuctionID   uuid.UUID `json:"auction_id"`
+	EndTime     time.Time `json:"end_time"`
+	ServerTime  time.Time `json:"server_time"`
+	RemainingMs int64     `json:"remaining_ms"`
+}
+
+// MaskBidder names a bidder without revealing who they are. The name stays
+// the same within an auction but differs between auctions, so bidders can't
+// be followed across them.
+func MaskBidder(auctionID, bidderID uuid.UUID) string {
+	sum := sha256.Sum256(append(auctionID[:], bidderID[:]...))
+	return "Bidder " + hex.EncodeToString(sum[:2])
 }
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// SellerBidAlerts chooses which bids on a listing its seller is notified
+// of. A listing without any notifies on every bid.
+type SellerBidAlerts struct {
+	AuctionID uuid.UUID `json:"auction_id" db:"auction_id"`
+	// FirstBid notifies on the listing's first bid
+	FirstBid bool `json:"first_bid" db:"first_bid"`
+	// ReserveMet notifies on the bid that meets the reserve
+	ReserveMet bool `json:"reserve_met" db:"reserve_met"`
+	// EveryNBids notifies on every Nth bid; 0 never does
+	EveryNBids int `json:"every_n_bids" db:"every_n_bids"`
+	// PriceAbove notifies on the bid that takes the price past it
+	PriceAbove *decimal.Decimal `json:"price_above,omitempty" db:"price_above"`
+	CreatedAt  *time.Time       `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt  *time.Time       `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// DefaultSellerBidAlerts notifies the seller of every bid, and when the
+// reserve is met
+func DefaultSellerBidAlerts(auctionID uuid.UUID) *SellerBidAlerts {
+	return &SellerBidAlerts{
+		AuctionID:  auctionID,
+		FirstBid:   true,
+		ReserveMet: true,
+		EveryNBids: 1,
+	}
+}
+
+// NotifyBid reports whether the seller is notified of the bid that took the
+// auction from previousPrice to its current price and bid count
+func (a *SellerBidAlerts) NotifyBid(auction *Auction, previousPrice decimal.Decimal) bool {
+	if a.FirstBid && auction.BidCount == 1 {
+		return true
+	}
+	if a.EveryNBids > 0 && auction.BidCount%a.EveryNBids == 0 {
+		return true
+	}
+	if a.PriceAbove != nil && !previousPrice.GreaterThan(*a.PriceAbove) && auction.CurrentPrice.GreaterThan(*a.PriceAbove) {
+		return true
+	}
+	return false
+}
+
+type SetSellerBidAlertsRequest struct {
+	FirstBid   bool    `json:"first_bid"`
+	ReserveMet bool    `json:"reserve_met"`
+	EveryNBids int     `json:"every_n_bids" validate:"min=0,max=1000"`
+	PriceAbove *string `json:"price_above,omitempty"`
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BidApprovalStatus string
+
+const (
+	BidApprovalPending  BidApprovalStatus = "pending"
+	BidApprovalApproved BidApprovalStatus = "approved"
+	BidApprovalDenied   BidApprovalStatus = "denied"
+)
+
+// BidApprovalAnswer is a bidder's answer to one of the seller's questions
+type BidApprovalAnswer struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// BidApproval is a user's request to bid on an auction that requires the
+// seller's approval, and the seller's decision
+type BidApproval struct {
+	AuctionID uuid.UUID           `json:"auction_id" db:"auction_id"`
+	UserID    uuid.UUID           `json:"user_id" db:"user_id"`
+	Status    BidApprovalStatus   `json:"status" db:"status"`
+	Answers   []BidApprovalAnswer `json:"answers" db:"answers"`
+	Note      *string             `json:"note,omitempty" db:"note"`
+	DecidedAt *time.Time          `json:"decided_at,omitempty" db:"decided_at"`
+	CreatedAt time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at" db:"updated_at"`
+
+	// Joined fields
+	Username string `json:"username,omitempty" db:"-"`
+}
+
+type RequestBidApprovalRequest struct {
+	// One answer per question, in the order asked
+	Answers []string `json:"answers" validate:"omitempty,max=5,dive,max=1000"`
+}
+
+type DecideBidApprovalRequest struct {
+	Status string  `json:"status" validate:"required,oneof=approved denied"`
+	Note   *string `json:"note" validate:"omitempty,max=500"`
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// BidEventType is what happened in an auction's bid trail
+type BidEventType string
+
+const (
+	// A bidder placed a bid, possibly with a proxy max
+	BidEventPlaced BidEventType = "placed"
+	// A bid placed for the bidder by one of their snipes
+	BidEventAutoBid BidEventType = "auto_bid"
+	// A bidder bought the item outright
+	BidEventBuyNow BidEventType = "buy_now"
+	// The seller cancelled one bid
+	BidEventCancelled BidEventType = "cancelled"
+	// The seller withdrew the auction, voiding every bid
+	BidEventAllCancelled BidEventType = "all_cancelled"
+	// A late bid extended the auction
+	BidEventExtended BidEventType = "extended"
+)
+
+// BidEvent is one entry in an auction's bid trail. The actor is the bidder
+// for placements and the seller for cancellations. Placements made through
+// the API keep the request's address and user agent.
+type BidEvent struct {
+	ID         uuid.UUID        `json:"id" db:"id"`
+	AuctionID  uuid.UUID        `json:"auction_id" db:"auction_id"`
+	BidID      *uuid.UUID       `json:"bid_id,omitempty" db:"bid_id"`
+	ActorID    *uuid.UUID       `json:"actor_id,omitempty" db:"actor_id"`
+	Type       BidEventType     `json:"type" db:"event_type"`
+	Amount     *decimal.Decimal `json:"amount,omitempty" db:"amount"`
+	MaxAutoBid *decimal.Decimal `json:"max_auto_bid,omitempty" db:"max_auto_bid"`
+	IP         *string          `json:"ip,omitempty" db:"ip"`
+	UserAgent  *string          `json:"user_agent,omitempty" db:"user_agent"`
+	Reason     *string          `json:"reason,omitempty" db:"reason"`
+	// The auction's new end time, for extensions
+	EndTime   *time.Time `json:"end_time,omitempty" db:"end_time"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+
+	// Joined fields
+	ActorUsername *string `json:"actor_username,omitempty"`
+}
+
+// BidClient describes where a bid came from: the request placing it, or the
+// snipe placing it for the bidder
+type BidClient struct {
+	IP        string
+	UserAgent string
+	// Set when one of the bidder's snipes placed the bid
+	Snipe bool
+}
+
+// BidAudit is an auction's full bid trail, oldest first
+type BidAudit struct {
+	AuctionID uuid.UUID  `json:"auction_id"`
+	Events    []BidEvent `json:"events"`
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// IncrementTier is one step of the bid increment ladder: while the price is
+// below Below, bids must go up by at least Increment
+type IncrementTier struct {
+	Below     decimal.Decimal
+	Increment decimal.Decimal
+}
+
+// IncrementLadder grows the minimum bid increment with the price, the way
+// buyers expect from other auction sites. Tiers are in ascending order of
+// Below; above the last bound, its increment still applies.
+type IncrementLadder []IncrementTier
+
+// BidIncrementLadder is the ladder bids follow. Empty leaves each auction's
+// own increment alone.
+var BidIncrementLadder IncrementLadder
+
+// ParseIncrementLadder parses a configured ladder written as comma
+// separated below:increment pairs, e.g. "25:0.50,100:1,1000:10"
+func ParseIncrementLadder(s string) (IncrementLadder, error) {
+	var ladder IncrementLadder
+	for _, step := range strings.Split(s, ",") {
+		step = strings.TrimSpace(step)
+		if step == "" {
+			continue
+		}
+
+		below, increment, ok := strings.Cut(step, ":")
+		if !ok {
+			return nil, fmt.Errorf("tier %q is not below:increment", step)
+		}
+		tier := IncrementTier{}
+		var err error
+		if tier.Below, err = ParseAmount(strings.TrimSpace(below)); err != nil || !tier.Below.IsPositive() {
+			return nil, fmt.Errorf("tier %q has an invalid price", step)
+		}
+		if tier.Increment, err = ParseAmount(strings.TrimSpace(increment)); err != nil || !tier.Increment.IsPositive() {
+			return nil, fmt.Errorf("tier %q has an invalid increment", step)
+		}
+		if n := len(ladder); n > 0 && !tier.Below.GreaterThan(ladder[n-1].Below) {
+			return nil, fmt.Errorf("tier %q is out of order", step)
+		}
+
+		ladder = append(ladder, tier)
+	}
+	return ladder, nil
+}
+
+// Increment returns the ladder's increment at a price, or zero for an
+// empty ladder
+func (l IncrementLadder) Increment(price decimal.Decimal) decimal.Decimal {
+	if len(l) == 0 {
+		return decimal.Zero
+	}
+	for _, tier := range l {
+		if price.LessThan(tier.Below) {
+			return tier.Increment
+		}
+	}
+	return l[len(l)-1].Increment
+}
+
+// MinimumBidAbove returns the lowest bid allowed over a price: the larger
+// of the auction's own increment and the ladder's at that price added to it
+func (a *Auction) MinimumBidAbove(price decimal.Decimal) decimal.Decimal {
+	return price.Add(decimal.Max(a.BidIncrement, BidIncrementLadder.Increment(price)))
+}
+
+// MinimumBid returns the lowest bid the auction accepts next
+func (a *Auction) MinimumBid() decimal.Decimal {
+	return a.MinimumBidAbove(a.CurrentPrice)
+}
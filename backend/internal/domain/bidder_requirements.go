@@ -0,0 +1,75 @@
+package domain
+
+import "time"
+
+// BidderRequirements are what a seller asks of bidders' accounts before they
+// can bid on or buy an auction. Approval by the seller is asked for
+// separately, with RequiresBidApproval.
+type BidderRequirements struct {
+	// Lowest average buyer rating; bidders without buyer ratings don't meet
+	// it
+	MinRating         *float64 `json:"min_rating,omitempty" validate:"omitempty,min=1,max=5"`
+	VerifiedEmail     bool     `json:"verified_email"`
+	MinAccountAgeDays int      `json:"min_account_age_days,omitempty" validate:"min=0,max=3650"`
+}
+
+// Any reports whether the seller set any requirement
+func (r BidderRequirements) Any() bool {
+	return r.MinRating != nil || r.VerifiedEmail || r.MinAccountAgeDays > 0
+}
+
+// BidderRequirement names a requirement a bidder doesn't meet, so clients
+// can explain why they can't bid
+type BidderRequirement string
+
+const (
+	BidderRequirementRating        BidderRequirement = "min_rating"
+	BidderRequirementVerifiedEmail BidderRequirement = "verified_email"
+	BidderRequirementAccountAge    BidderRequirement = "min_account_age"
+	BidderRequirementApproval      BidderRequirement = "approval"
+)
+
+// Err returns the error a bid fails with when the requirement isn't met
+func (r BidderRequirement) Err() error {
+	switch r {
+	case BidderRequirementRating:
+		return ErrBidderRatingTooLow
+	case BidderRequirementVerifiedEmail:
+		return ErrBidderEmailUnverified
+	case BidderRequirementAccountAge:
+		return ErrBidderAccountTooNew
+	default:
+		return ErrBidApprovalRequired
+	}
+}
+
+// BidderProfile is the part of a bidder's account that requirements are
+// checked against
+type BidderProfile struct {
+	EmailVerified    bool
+	AccountCreatedAt time.Time
+	BuyerRating      float64
+	BuyerRatings     int
+}
+
+// Unmet returns the requirements the bidder doesn't meet at now
+func (r BidderRequirements) Unmet(profile BidderProfile, now time.Time) []BidderRequirement {
+	unmet := make([]BidderRequirement, 0)
+	if r.MinRating != nil && (profile.BuyerRatings == 0 || profile.BuyerRating < *r.MinRating) {
+		unmet = append(unmet, BidderRequirementRating)
+	}
+	if r.VerifiedEmail && !profile.EmailVerified {
+		unmet = append(unmet, BidderRequirementVerifiedEmail)
+	}
+	if r.MinAccountAgeDays > 0 && profile.AccountCreatedAt.After(now.AddDate(0, 0, -r.MinAccountAgeDays)) {
+		unmet = append(unmet, BidderRequirementAccountAge)
+	}
+	return unmet
+}
+
+// BidEligibility tells a signed-in viewer whether they can bid on an
+// auction that restricts bidders, and if not, why
+type BidEligibility struct {
+	CanBid bool                `json:"can_bid"`
+	Unmet  []BidderRequirement `json:"unmet"`
+}
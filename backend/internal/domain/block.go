@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserBlock stops BlockedID from bidding on BlockerID's auctions or
+// messaging them. The blocked user isn't told beyond being refused.
+type UserBlock struct {
+	BlockerID uuid.UUID `json:"-" db:"blocker_id"`
+	BlockedID uuid.UUID `json:"blocked_id" db:"blocked_id"`
+	// Kept for the blocker's own reference
+	Reason    *string   `json:"reason,omitempty" db:"reason"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// Joined fields
+	Blocked *PublicUser `json:"blocked,omitempty"`
+}
+
+type BlockUserRequest struct {
+	UserID uuid.UUID `json:"user_id" validate:"required"`
+	Reason string    `json:"reason" validate:"max=500"`
+}
+
+type UserBlockListParams struct {
+	Page  int `json:"page"`
+	Limit int `json:"limit"`
+}
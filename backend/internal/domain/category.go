@@ -14,6 +14,8 @@ type Category struct {
 	Description *string    `json:"description,omitempty" db:"description"`
 	ImageURL    *string    `json:"image_url,omitempty" db:"image_url"`
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	// Language of the translation shown, if the name isn't in DefaultLanguage
+	Language string `json:"language,omitempty"`
 
 	// Computed/joined fields
 	AuctionCount int         `json:"auction_count,omitempty"`
@@ -36,3 +38,32 @@ type UpdateCategoryRequest struct {
 	Description *string    `json:"description" validate:"omitempty,max=500"`
 	ImageURL    *string    `json:"image_url" validate:"omitempty,url,max=500"`
 }
+
+// DefaultLanguage is the language categories are written in. Translations
+// preferred over it are shown instead; past it, the category's own name is.
+const DefaultLanguage = "en"
+
+// CategoryTranslation is a category's name and description in another
+// language, keyed by a BCP 47 tag such as de or pt-BR
+type CategoryTranslation struct {
+	CategoryID  uuid.UUID `json:"category_id" db:"category_id"`
+	Language    string    `json:"language" db:"language"`
+	Name        string    `json:"name" db:"name"`
+	Description *string   `json:"description,omitempty" db:"description"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Translate shows the category in the translation's language, keeping its
+// own description if the translation has none
+func (c *Category) Translate(translation *CategoryTranslation) {
+	c.Name = translation.Name
+	if translation.Description != nil {
+		c.Description = translation.Description
+	}
+	c.Language = translation.Language
+}
+
+type SetCategoryTranslationRequest struct {
+	Name        string  `json:"name" validate:"required,min=2,max=100"`
+	Description *string `json:"description" validate:"omitempty,max=500"`
+}
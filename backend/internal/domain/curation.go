@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultTrendingLimit = 12
+	// MaxTrendingLimit is also how many trending auctions are kept
+	MaxTrendingLimit = 50
+
+	// MaxFeaturedAuctions caps how many auctions can be pinned at once
+	MaxFeaturedAuctions = 50
+)
+
+// FeaturedAuction is an auction an admin pinned to the homepage. Lower
+// positions are shown first.
+type FeaturedAuction struct {
+	AuctionID  uuid.UUID  `json:"auction_id" db:"auction_id"`
+	Position   int        `json:"position" db:"position"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	FeaturedBy *uuid.UUID `json:"featured_by,omitempty" db:"featured_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Joined fields
+	AuctionTitle  string        `json:"auction_title" db:"-"`
+	AuctionStatus AuctionStatus `json:"auction_status" db:"-"`
+	Expired       bool          `json:"expired" db:"-"`
+}
+
+// IsExpired reports whether the pin has run out at now
+func (f *FeaturedAuction) IsExpired(now time.Time) bool {
+	return f.ExpiresAt != nil && !f.ExpiresAt.After(now)
+}
+
+type FeatureAuctionRequest struct {
+	Position  int        `json:"position" validate:"min=0,max=1000"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
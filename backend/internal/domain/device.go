@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Device is a browser or app a user has signed in from. Trusted devices skip
+// the emailed login code of two-factor sign-in.
+type Device struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	UserID      uuid.UUID `json:"-" db:"user_id"`
+	Fingerprint string    `json:"-" db:"fingerprint"` // SHA-256 of the device cookie
+	Name        *string   `json:"name" db:"name"`
+	UserAgent   *string   `json:"user_agent" db:"user_agent"`
+	Trusted     bool      `json:"trusted" db:"trusted"`
+	LastIP      *string   `json:"last_ip" db:"last_ip"`
+	LastSeenAt  time.Time `json:"last_seen_at" db:"last_seen_at"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+
+	// Outstanding login code, if any
+	ChallengeDigest    *string    `json:"-" db:"challenge_digest"`
+	ChallengeExpiresAt *time.Time `json:"-" db:"challenge_expires_at"`
+
+	// Computed: whether this is the device making the request
+	Current bool `json:"current"`
+}
+
+type UpdateDeviceRequest struct {
+	Name    *string `json:"name" validate:"omitempty,min=1,max=100"`
+	Trusted *bool   `json:"trusted"`
+}
+
+type VerifyLoginRequest struct {
+	ChallengeID uuid.UUID `json:"challenge_id" validate:"required"`
+	Code        string    `json:"code" validate:"required,len=6,numeric"`
+	// TrustDevice skips the code on this device from now on
+	TrustDevice bool `json:"trust_device"`
+}
+
+type SetTwoFactorRequest struct {
+	Enabled bool `json:"enabled"`
+}
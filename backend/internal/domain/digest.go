@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DigestFrequency string
+
+const (
+	DigestOff    DigestFrequency = "off"
+	DigestDaily  DigestFrequency = "daily"
+	DigestWeekly DigestFrequency = "weekly"
+)
+
+// DigestPreferences say whether and when a user gets a digest email, and
+// what goes in it. Hour and Weekday, 0 for Sunday, are in their Timezone.
+// Watchers who get watched auctions in their digest don't get an email for
+// each auction about to end.
+type DigestPreferences struct {
+	UserID        uuid.UUID       `json:"-" db:"user_id"`
+	Frequency     DigestFrequency `json:"frequency" db:"frequency"`
+	Hour          int             `json:"hour" db:"send_hour"`
+	Weekday       int             `json:"weekday" db:"send_weekday"`
+	Timezone      string          `json:"timezone" db:"timezone"`
+	WatchedEnding bool            `json:"watched_ending" db:"watched_ending"`
+	SavedSearches bool            `json:"saved_searches" db:"saved_searches"`
+	SellerStats   bool            `json:"seller_stats" db:"seller_stats"`
+	LastSentAt    *time.Time      `json:"last_sent_at,omitempty" db:"last_sent_at"`
+	NextSendAt    *time.Time      `json:"next_send_at,omitempty" db:"next_send_at"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultDigestPreferences is what users who haven't set up a digest get:
+// off, but ready to switch on for Monday mornings
+func DefaultDigestPreferences(userID uuid.UUID) *DigestPreferences {
+	return &DigestPreferences{
+		UserID:        userID,
+		Frequency:     DigestOff,
+		Hour:          8,
+		Weekday:       int(time.Monday),
+		Timezone:      "UTC",
+		WatchedEnding: true,
+		SavedSearches: true,
+		SellerStats:   true,
+	}
+}
+
+// Enabled reports whether the user gets digests at all
+func (p *DigestPreferences) Enabled() bool {
+	return p.Frequency == DigestDaily || p.Frequency == DigestWeekly
+}
+
+// Period is how much time one digest covers
+func (p *DigestPreferences) Period() time.Duration {
+	if p.Frequency == DigestWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// NextAfter returns when the next digest is due after the given time: the
+// next time the clock in the user's timezone strikes their hour, on their
+// weekday for weekly digests
+func (p *DigestPreferences) NextAfter(t time.Time) (time.Time, error) {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return t, fmt.Errorf("%w: %q", ErrInvalidTimezone, p.Timezone)
+	}
+
+	local := t.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), p.Hour, 0, 0, 0, loc)
+	for !next.After(local) || (p.Frequency == DigestWeekly && int(next.Weekday()) != p.Weekday) {
+		next = time.Date(next.Year(), next.Month(), next.Day()+1, p.Hour, 0, 0, 0, loc)
+	}
+	return next, nil
+}
+
+// Validate checks the frequency, hour, weekday and timezone, so the
+// preferences can be saved
+func (p *DigestPreferences) Validate() error {
+	switch p.Frequency {
+	case DigestOff, DigestDaily, DigestWeekly:
+	default:
+		return fmt.Errorf("%w: unknown digest frequency %q", ErrValidation, p.Frequency)
+	}
+	if p.Hour < 0 || p.Hour > 23 || p.Weekday < 0 || p.Weekday > 6 {
+		return fmt.Errorf("%w: digest hour or weekday out of range", ErrValidation)
+	}
+	_, err := p.NextAfter(time.Now())
+	return err
+}
+
+type UpdateDigestPreferencesRequest struct {
+	Frequency     string `json:"frequency" validate:"required,oneof=off daily weekly"`
+	Hour          int    `json:"hour" validate:"min=0,max=23"`
+	Weekday       int    `json:"weekday" validate:"min=0,max=6"`
+	Timezone      string `json:"timezone" validate:"required,max=64"`
+	WatchedEnding bool   `json:"watched_ending"`
+	SavedSearches bool   `json:"saved_searches"`
+	SellerStats   bool   `json:"seller_stats"`
+}
+
+// Digest is what one digest email tells a user about the period from Since
+// to Until. Only the sections they chose are filled in.
+type Digest struct {
+	Since         time.Time            `json:"since"`
+	Until         time.Time            `json:"until"`
+	WatchedEnding []Auction            `json:"watched_ending"`
+	SavedSearches []SavedSearchMatches `json:"saved_searches"`
+	SellerStats   *DigestSellerStats   `json:"seller_stats,omitempty"`
+}
+
+// Empty reports whether there is nothing worth an email
+func (d *Digest) Empty() bool {
+	return len(d.WatchedEnding) == 0 && len(d.SavedSearches) == 0 && (d.SellerStats == nil || d.SellerStats.Empty())
+}
+
+// SavedSearchMatches are the newest listings matching a saved search, of
+// Total listed during the period
+type SavedSearchMatches struct {
+	Search   SavedSearch `json:"search"`
+	Auctions []Auction   `json:"auctions"`
+	Total    int         `json:"total"`
+}
+
+// DigestSellerStats sum up a seller's listings: those active now and ending
+// before the next digest, and what happened to them during the period
+type DigestSellerStats struct {
+	ActiveListings int `json:"active_listings"`
+	EndingSoon     int `json:"ending_soon"`
+	NewBids        int `json:"new_bids"`
+	NewWatchers    int `json:"new_watchers"`
+	Sold           int `json:"sold"`
+	Unsold         int `json:"unsold"`
+}
+
+func (s *DigestSellerStats) Empty() bool {
+	return *s == DigestSellerStats{}
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type DisputeReason string
+
+const (
+	DisputeReasonItemNotReceived DisputeReason = "item_not_received"
+	DisputeReasonNotAsDescribed  DisputeReason = "not_as_described"
+	DisputeReasonPaymentIssue    DisputeReason = "payment_issue"
+	DisputeReasonOther           DisputeReason = "other"
+)
+
+type DisputeStatus string
+
+const (
+	DisputeStatusOpen     DisputeStatus = "open"
+	DisputeStatusResolved DisputeStatus = "resolved"
+)
+
+type DisputeOutcome string
+
+const (
+	DisputeOutcomeRefund  DisputeOutcome = "refund"
+	DisputeOutcomeUphold  DisputeOutcome = "uphold"
+	DisputeOutcomePartial DisputeOutcome = "partial"
+)
+
+type Dispute struct {
+	ID             uuid.UUID        `json:"id" db:"id"`
+	OrderID        uuid.UUID        `json:"order_id" db:"order_id"`
+	AuctionID      uuid.UUID        `json:"auction_id" db:"auction_id"`
+	OpenedBy       uuid.UUID        `json:"opened_by" db:"opened_by"`
+	BuyerID        uuid.UUID        `json:"buyer_id" db:"buyer_id"`
+	SellerID       uuid.UUID        `json:"seller_id" db:"seller_id"`
+	Reason         DisputeReason    `json:"reason" db:"reason"`
+	Description    string           `json:"description" db:"description"`
+	Status         DisputeStatus    `json:"status" db:"status"`
+	Outcome        *DisputeOutcome  `json:"outcome,omitempty" db:"outcome"`
+	RefundAmount   *decimal.Decimal `json:"refund_amount,omitempty" db:"refund_amount"`
+	ResolutionNote *string          `json:"resolution_note,omitempty" db:"resolution_note"`
+	ResolvedBy     *uuid.UUID       `json:"resolved_by,omitempty" db:"resolved_by"`
+	ResolvedAt     *time.Time       `json:"resolved_at,omitempty" db:"resolved_at"`
+	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at" db:"updated_at"`
+
+	// Joined fields
+	Messages []DisputeMessage  `json:"messages,omitempty"`
+	Evidence []DisputeEvidence `json:"evidence,omitempty"`
+}
+
+type DisputeMessage struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	DisputeID uuid.UUID `json:"dispute_id" db:"dispute_id"`
+	SenderID  uuid.UUID `json:"sender_id" db:"sender_id"`
+	IsAdmin   bool      `json:"is_admin" db:"is_admin"`
+	Body      string    `json:"body" db:"body"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type DisputeEvidence struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	DisputeID   uuid.UUID `json:"dispute_id" db:"dispute_id"`
+	UploadedBy  uuid.UUID `json:"uploaded_by" db:"uploaded_by"`
+	URL         string    `json:"url" db:"url"`
+	Description *string   `json:"description,omitempty" db:"description"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// Request/Response DTOs
+type OpenDisputeRequest struct {
+	Reason      string `json:"reason" validate:"required,oneof=item_not_received not_as_described payment_issue other"`
+	Description string `json:"description" validate:"required,min=10,max=5000"`
+}
+
+type DisputeMessageRequest struct {
+	Body string `json:"body" validate:"required,min=1,max=5000"`
+}
+
+type ResolveDisputeRequest struct {
+	Outcome      string  `json:"outcome" validate:"required,oneof=refund uphold partial"`
+	RefundAmount *string `json:"refund_amount" validate:"omitempty,numeric"`
+	Note         *string `json:"note" validate:"omitempty,max=5000"`
+}
+
+type DisputeListParams struct {
+	Status *DisputeStatus `json:"status"`
+	UserID *uuid.UUID     `json:"user_id"`
+	Page   int            `json:"page"`
+	Limit  int            `json:"limit"`
+}
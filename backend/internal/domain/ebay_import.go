@@ -0,0 +1,51 @@
+package domain
+
+import "github.com/google/uuid"
+
+// EbayImportKind is which eBay export a file is, told apart by its columns
+type EbayImportKind string
+
+const (
+	// Watchlist exports list items by title; they become watchlist entries
+	EbayImportWatchlist EbayImportKind = "watchlist"
+	// Saved seller exports list seller usernames; they become follows
+	EbayImportSavedSellers EbayImportKind = "saved_sellers"
+)
+
+type EbayImportRowStatus string
+
+const (
+	EbayImportWatched         EbayImportRowStatus = "watched"
+	EbayImportAlreadyWatched  EbayImportRowStatus = "already_watched"
+	EbayImportFollowed        EbayImportRowStatus = "followed"
+	EbayImportAlreadyFollowed EbayImportRowStatus = "already_followed"
+	EbayImportUnmatched       EbayImportRowStatus = "unmatched"
+)
+
+// EbayImportReport says what became of each row of an eBay export. Items
+// are matched to active listings by how alike their titles are, sellers by
+// username.
+type EbayImportReport struct {
+	Kind      EbayImportKind        `json:"kind"`
+	TotalRows int                   `json:"total_rows"`
+	Added     int                   `json:"added"`
+	Existing  int                   `json:"existing"`
+	Unmatched int                   `json:"unmatched"`
+	Rows      []EbayImportRowResult `json:"rows"`
+}
+
+// EbayImportRowResult is the report entry for one row, numbered from 1 not
+// counting the header. Score is how alike the item's title and the matched
+// listing's are, from 0 to 1.
+type EbayImportRowResult struct {
+	Row        int                 `json:"row"`
+	ItemNumber string              `json:"item_number,omitempty"`
+	Title      string              `json:"title,omitempty"`
+	Seller     string              `json:"seller,omitempty"`
+	Status     EbayImportRowStatus `json:"status"`
+	AuctionID  *uuid.UUID          `json:"auction_id,omitempty"`
+	MatchTitle string              `json:"match_title,omitempty"`
+	Score      float64             `json:"score,omitempty"`
+	SellerID   *uuid.UUID          `json:"seller_id,omitempty"`
+	Reason     string              `json:"reason,omitempty"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuctionEmbed is the listing card other sites embed: what a reader needs
+// to see the auction at a glance, and a link to it
+type AuctionEmbed struct {
+	ID           uuid.UUID     `json:"id"`
+	Title        string        `json:"title"`
+	ImageURL     *string       `json:"image_url,omitempty"`
+	CurrentPrice Money         `json:"current_price"`
+	BidCount     int           `json:"bid_count"`
+	Status       AuctionStatus `json:"status"`
+	EndTime      time.Time     `json:"end_time"`
+	URL          string        `json:"url"`
+}
+
+// OEmbed is an oEmbed "rich" response (https://oembed.com) whose HTML
+// frames an auction's embed card
+type OEmbed struct {
+	Type         string `json:"type"`
+	Version      string `json:"version"`
+	Title        string `json:"title"`
+	ProviderName string `json:"provider_name"`
+	ProviderURL  string `json:"provider_url"`
+	// Seconds consumers may cache the response; the card in the frame
+	// stays live regardless
+	CacheAge int    `json:"cache_age"`
+	HTML     string `json:"html"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+}
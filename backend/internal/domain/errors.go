@@ -21,6 +21,16 @@ var (
 	ErrUsernameExists     = errors.New("username already exists")
 	ErrTokenExpired       = errors.New("token expired")
 	ErrTokenInvalid       = errors.New("token invalid")
+	ErrInvalidCursor      = errors.New("invalid pagination cursor")
+	ErrInvalidAmount      = errors.New("amounts must be numbers in whole cents, sent as strings")
+	ErrInvalidCurrency    = errors.New("unsupported currency")
+	ErrCurrencyLocked     = errors.New("currency cannot be changed once bidding has started")
+	ErrTooManyAttempts    = errors.New("too many attempts")
+	ErrInvalidUsageWindow = errors.New("usage window must be 1d, 7d or 30d")
+	ErrInvalidTimezone    = errors.New("unknown timezone")
+	ErrInvalidQuietHours  = errors.New("quiet hours must start and end at different HH:MM times")
+	ErrInvalidNotificationType = errors.New("unknown notification type")
+	ErrInvalidUnsubscribeToken = errors.New("invalid unsubscribe link")
 
 	// Auction errors
 	ErrAuctionNotActive   = errors.New("auction is not active")
@@ -29,6 +39,124 @@ var (
 	ErrBidTooLow          = errors.New("bid amount too low")
 	ErrAuctionNotDraft    = errors.New("auction is not in draft status")
 	ErrConcurrentBid      = errors.New("concurrent bid detected, please retry")
+	ErrInvalidShipping    = errors.New("invalid shipping option")
+	ErrInvalidItemImage   = errors.New("item images must be images of the auction")
+	ErrBidCancelWindow    = errors.New("bids cannot be cancelled in the final hour")
+	ErrBidCancelLimit     = errors.New("bid cancellation limit reached for this auction")
+	ErrAuctionCancelWindow = errors.New("auctions cannot be cancelled in the final 12 hours")
+	ErrReserveNotLowered  = errors.New("reserve price can only be lowered")
+	ErrReserveTooLow      = errors.New("reserve price cannot be below the starting price")
+	ErrBuyNowUnavailable  = errors.New("buy now is not available for this auction")
+	ErrSnipeTooLow        = errors.New("snipe amount is below the next minimum bid")
+	ErrSnipeTooLate       = errors.New("auction ends too soon to schedule a snipe")
+	ErrAlreadyHighBidder  = errors.New("already the highest bidder")
+	ErrSellerOnVacation   = errors.New("seller is on vacation and not accepting bids")
+
+	// Certification errors
+	ErrCertificationLocked = errors.New("certification cannot be changed once bidding has started")
+	ErrNoCertification     = errors.New("auction has no certification")
+
+	// Best offer errors
+	ErrOffersDisabled   = errors.New("seller is not accepting offers on this auction")
+	ErrOfferTooLow      = errors.New("offer is below the current price")
+	ErrOfferAboveBuyNow = errors.New("offer must be below the buy now price")
+	ErrOfferExists      = errors.New("you already have an open offer on this auction")
+	ErrOfferLimit       = errors.New("offer limit reached for this auction")
+	ErrCounterTooLow    = errors.New("counter offer must be above the buyer's offer")
+
+	// Webhook errors
+	ErrWebhookURL        = errors.New("webhook URL must be a public https address")
+	ErrWebhookLimit      = errors.New("webhook limit reached")
+	ErrDeliveryNotFailed = errors.New("only failed deliveries can be retried")
+
+	// Import errors
+	ErrImportFile = errors.New("invalid import file")
+
+	// Account linking errors
+	ErrReauthRequired     = errors.New("confirm your password or sign in again to continue")
+	ErrPasswordAlreadySet = errors.New("account already has a password")
+	ErrProviderLinked     = errors.New("a login from this provider is already linked")
+	ErrOAuthAccountInUse  = errors.New("this provider login belongs to another account")
+	ErrLastSignInMethod   = errors.New("cannot remove the only way to sign in")
+
+	// Privacy errors
+	ErrExportsUnavailable = errors.New("data exports are not available right now")
+	ErrAccountInUse       = errors.New("finish your active listings, winning bids, orders and disputes before deleting your account")
+
+	// Factsheet errors
+	ErrFactsheetsUnavailable = errors.New("fact sheets are not available right now")
+
+	// Bid approval errors
+	ErrBidApprovalRequired    = errors.New("the seller must approve you before you can bid")
+	ErrBidApprovalNotRequired = errors.New("this auction doesn't require bid approval")
+	ErrBidApprovalLocked      = errors.New("bid approval cannot be required once bidding has started")
+	ErrBidApprovalAnswers     = errors.New("answer each of the seller's questions")
+
+	// Bidder requirement errors
+	ErrBidderRatingTooLow    = errors.New("your buyer rating is below the seller's minimum for this auction")
+	ErrBidderEmailUnverified = errors.New("the seller requires a verified email to bid on this auction")
+	ErrBidderAccountTooNew   = errors.New("your account is too new to bid on this auction")
+
+	// Live event errors
+	ErrLiveEventLot      = errors.New("lots of a live event are opened by its auctioneer")
+	ErrLiveEventLots     = errors.New("lots must be your own draft auctions that aren't in another live event")
+	ErrLiveEventEnded    = errors.New("the live event has ended")
+	ErrLiveLotOpen       = errors.New("the open lot must be hammered first")
+	ErrLiveLotNotOpen    = errors.New("no lot is open")
+	ErrLiveLotNotPending = errors.New("the lot has already been run")
+
+	// Price drop errors
+	ErrPriceDropFloor = errors.New("floor price must be below the buy now price and at least the starting price")
+
+	// Curation errors
+	ErrFeaturedLimit = errors.New("too many auctions are featured")
+
+	// Account merge errors
+	ErrMergeSameUser   = errors.New("an account cannot be merged into itself")
+	ErrMergeInteracted = errors.New("accounts that have traded or messaged with each other cannot be merged")
+
+	// Messaging errors
+	ErrKeyEnvelopesIncomplete = errors.New("message key is not wrapped for every recipient device")
+
+	// Blocking errors
+	ErrCannotBlockSelf    = errors.New("you cannot block yourself")
+	ErrBlockedBySeller    = errors.New("the seller has blocked you from bidding on their auctions")
+	ErrBlockedByRecipient = errors.New("this user is not accepting messages from you")
+
+	// Following errors
+	ErrCannotFollowSelf = errors.New("you cannot follow yourself")
+
+	// Saved search errors
+	ErrSavedSearchLimit = errors.New("saved search limit reached")
+
+	// Listing rule errors
+	ErrInvalidListingRules = errors.New("listing rule minimums must not exceed their maximums and defaults must be within them")
+	ErrListingRules        = errors.New("auction doesn't meet its category's listing rules")
+
+	// Maintenance window errors
+	ErrInvalidMaintenanceWindow = errors.New("maintenance window must end after it starts, last at most a day and not start in the past or overlap another")
+	ErrMaintenanceWindowStarted = errors.New("maintenance window has already started")
+
+	// Push errors
+	ErrPushUnavailable  = errors.New("push notifications are not available right now")
+	ErrPushSubscription = errors.New("push subscription must have a public https endpoint and valid keys")
+
+	// Order errors
+	ErrOrderNotPending    = errors.New("order is not awaiting payment")
+	ErrOfferNotPending    = errors.New("offer is no longer available")
+	ErrEscrowNotHeld      = errors.New("payment is not held in escrow")
+
+	// Payout errors
+	ErrPayoutMethodRequired = errors.New("no payout method registered")
+	ErrInvalidPayoutMethod  = errors.New("invalid payout method")
+	ErrInvalidPayoutAmount  = errors.New("invalid payout amount")
+	ErrInsufficientBalance  = errors.New("insufficient balance")
+	ErrPayoutStatus         = errors.New("payout cannot be changed in its current status")
+
+	// Dispute errors
+	ErrDisputeExists      = errors.New("an open dispute already exists for this order")
+	ErrDisputeClosed      = errors.New("dispute is already resolved")
+	ErrInvalidRefund      = errors.New("invalid refund amount")
 )
 
 // AppError is a custom error type that includes HTTP status code
@@ -72,10 +200,13 @@ type APIError struct {
 }
 
 type APIMeta struct {
-	Page       int `json:"page,omitempty"`
-	Limit      int `json:"limit,omitempty"`
-	TotalCount int `json:"total_count,omitempty"`
-	TotalPages int `json:"total_pages,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+	TotalCount int    `json:"total_count,omitempty"`
+	TotalPages int    `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Seed is sent back with the next page of a discovery listing
+	Seed string `json:"seed,omitempty"`
 }
 
 func SuccessResponse(data interface{}) *APIResponse {
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type EstimateConfidence string
+
+const (
+	EstimateConfidenceLow    EstimateConfidence = "low"
+	EstimateConfidenceMedium EstimateConfidence = "medium"
+	EstimateConfidenceHigh   EstimateConfidence = "high"
+)
+
+const (
+	// Bidding velocity is measured over at most this much recent history
+	estimateVelocityWindow = 24 * time.Hour
+
+	// Share of watchers expected to bid once near the end
+	estimateWatcherBidRate = 0.25
+
+	// Caps the extra bids projected, so a burst of early bidding on a long
+	// auction doesn't run away
+	estimateMaxProjectedBids = 100
+
+	// Fewest comparable sales worth drawing on
+	estimateMinComparables = 3
+)
+
+// PriceEstimate is the range an active auction's price is likely to end in.
+// It is a rough heuristic, not a valuation.
+type PriceEstimate struct {
+	Low        decimal.Decimal    `json:"low"`
+	High       decimal.Decimal    `json:"high"`
+	Confidence EstimateConfidence `json:"confidence"`
+	// Recent comparable sales the estimate drew on
+	Comparables int `json:"comparables"`
+}
+
+// UnlikelyToWin reports whether a max bid falls short of the whole range
+func (e *PriceEstimate) UnlikelyToWin(maxBid decimal.Decimal) bool {
+	return maxBid.LessThan(e.Low)
+}
+
+// EstimateFinalPrice estimates where an auction's price will end from how
+// fast it has been bid on, how many are watching, and what comparable items
+// sold for recently. bids is the auction's bid history, oldest first;
+// comparables may be nil.
+func EstimateFinalPrice(a *Auction, bids []BidPoint, comparables *SoldPriceStats, now time.Time) *PriceEstimate {
+	remaining := max(a.EndTime.Sub(now), 0)
+	duration := a.EndTime.Sub(a.StartTime)
+
+	// Bids per hour lately, carried on to the end, plus watchers closing in
+	window := min(max(now.Sub(a.StartTime), time.Hour), estimateVelocityWindow)
+	recent := 0
+	for _, bid := range bids {
+		if now.Sub(bid.CreatedAt) <= window {
+			recent++
+		}
+	}
+	velocity := float64(recent) / window.Hours()
+	expected := velocity*remaining.Hours() + float64(a.WatchCount)*estimateWatcherBidRate
+	projectedBids := int(math.Min(expected, estimateMaxProjectedBids))
+
+	// Each bid raises the price by at least the minimum increment
+	projected := a.CurrentPrice
+	for range projectedBids {
+		projected = a.MinimumBidAbove(projected)
+	}
+
+	// Comparable sales pull the projection their way, less so the more the
+	// auction's own bidding says
+	mid := projected
+	used := 0
+	if comparables != nil && comparables.Count >= estimateMinComparables {
+		typical := comparables.Median
+		if typical == nil {
+			typical = comparables.Average
+		}
+		if typical != nil {
+			used = comparables.Count
+			weight := decimal.NewFromFloat(estimateMinComparables / float64(estimateMinComparables+a.BidCount))
+			mid = projected.Add(typical.Sub(projected).Mul(weight))
+		}
+	}
+	mid = decimal.Max(mid, a.CurrentPrice)
+
+	// The range narrows as the auction runs down, and is wider without
+	// comparables to go on
+	spread := 0.05
+	if duration > 0 {
+		spread += 0.20 * remaining.Seconds() / duration.Seconds()
+	}
+	if used == 0 {
+		spread += 0.10
+	}
+
+	low := decimal.Max(a.CurrentPrice, mid.Mul(decimal.NewFromFloat(1-spread))).Round(MoneyScale)
+	high := decimal.Max(low, mid.Mul(decimal.NewFromFloat(1+spread))).Round(MoneyScale)
+
+	confidence := EstimateConfidenceMedium
+	switch {
+	case used >= 10 && a.BidCount >= 5:
+		confidence = EstimateConfidenceHigh
+	case used == 0 && a.BidCount < 3:
+		confidence = EstimateConfidenceLow
+	}
+
+	return &PriceEstimate{
+		Low:         low,
+		High:        high,
+		Confidence:  confidence,
+		Comparables: used,
+	}
+}
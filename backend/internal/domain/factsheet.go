@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FactsheetStatus string
+
+const (
+	FactsheetPending    FactsheetStatus = "pending"
+	FactsheetProcessing FactsheetStatus = "processing"
+	FactsheetCompleted  FactsheetStatus = "completed"
+	FactsheetFailed     FactsheetStatus = "failed"
+)
+
+// AuctionFactsheet is the printable one-page PDF of an auction. It is
+// rendered in the background and kept in object storage until the auction
+// changes in a way the sheet shows, which changes its fingerprint.
+type AuctionFactsheet struct {
+	AuctionID   uuid.UUID       `json:"auction_id" db:"auction_id"`
+	Status      FactsheetStatus `json:"status" db:"status"`
+	Fingerprint *string         `json:"-" db:"fingerprint"`
+	ObjectName  *string         `json:"-" db:"object_name"`
+	LeaseUntil  *time.Time      `json:"-" db:"lease_until"`
+	GeneratedAt *time.Time      `json:"generated_at,omitempty" db:"generated_at"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+
+	// Presigned once a sheet matching the auction is stored
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+// Matches reports whether the stored sheet was rendered from an auction
+// with the given fingerprint
+func (f *AuctionFactsheet) Matches(fingerprint string) bool {
+	return f.ObjectName != nil && f.Fingerprint != nil && *f.Fingerprint == fingerprint
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SellerFollow is a user following a seller, like a saved seller on eBay
+type SellerFollow struct {
+	UserID    uuid.UUID `json:"-" db:"user_id"`
+	SellerID  uuid.UUID `json:"seller_id" db:"seller_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// Joined fields
+	Seller *PublicUser `json:"seller,omitempty"`
+}
+
+type SellerFollowListParams struct {
+	Page  int `json:"page"`
+	Limit int `json:"limit"`
+}
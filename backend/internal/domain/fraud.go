@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FraudCheck is the heuristic that raised a fraud flag
+type FraudCheck string
+
+const (
+	// Different bidders on a seller's auctions from one address, or a bidder
+	// on the address the seller signs in from
+	FraudCheckSharedIP FraudCheck = "shared_ip"
+	// A bidder whose bids sellers keep cancelling, the way a shill's are once
+	// they have pushed the price up
+	FraudCheckBidRetraction FraudCheck = "bid_retraction"
+	// A new account bidding far faster than buyers do
+	FraudCheckBidVelocity FraudCheck = "bid_velocity"
+	// A seller's item won by an account sharing their device or address
+	FraudCheckSelfPurchase FraudCheck = "self_purchase"
+)
+
+type FraudFlagStatus string
+
+const (
+	FraudFlagOpen      FraudFlagStatus = "open"
+	FraudFlagConfirmed FraudFlagStatus = "confirmed"
+	FraudFlagDismissed FraudFlagStatus = "dismissed"
+)
+
+// FraudFlag is an entry in the admin fraud review queue. Checks raise the
+// same flag again, keyed by DedupeKey, while it is open; once reviewed the
+// same finding is not raised again.
+type FraudFlag struct {
+	ID    uuid.UUID  `json:"id" db:"id"`
+	Check FraudCheck `json:"check" db:"check_type"`
+	// The account under suspicion
+	SubjectID *uuid.UUID `json:"subject_id,omitempty" db:"subject_id"`
+	// Other accounts involved, such as the seller or linked bidders
+	RelatedUserIDs []uuid.UUID `json:"related_user_ids" db:"related_user_ids"`
+	AuctionID      *uuid.UUID  `json:"auction_id,omitempty" db:"auction_id"`
+	// 0 to 100, higher is more likely fraud
+	Severity   int                    `json:"severity" db:"severity"`
+	Evidence   map[string]interface{} `json:"evidence" db:"evidence"`
+	Status     FraudFlagStatus        `json:"status" db:"status"`
+	DedupeKey  string                 `json:"-" db:"dedupe_key"`
+	ReviewedBy *uuid.UUID             `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewNote *string                `json:"review_note,omitempty" db:"review_note"`
+	// When a check last found this
+	DetectedAt time.Time `json:"detected_at" db:"detected_at"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+
+	// Joined fields
+	SubjectUsername *string `json:"subject_username,omitempty"`
+}
+
+// SharedIPBidding is bidding on one seller's auctions from one address
+type SharedIPBidding struct {
+	SellerID   uuid.UUID
+	IP         string
+	BidderIDs  []uuid.UUID
+	AuctionIDs []uuid.UUID
+	Bids       int
+	// The seller has signed in from the address too
+	SellerUsedIP bool
+}
+
+// BidRetractions is a bidder's recent bids and how many sellers cancelled
+type BidRetractions struct {
+	BidderID  uuid.UUID
+	Placed    int
+	Cancelled int
+	// Distinct sellers who cancelled them
+	Sellers int
+}
+
+// BidVelocity is a new account's bidding over the velocity window
+type BidVelocity struct {
+	UserID           uuid.UUID
+	AccountCreatedAt time.Time
+	Bids             int
+	Auctions         int
+}
+
+// LinkedPurchase is an order whose buyer shares a device or sign-in address
+// with the seller
+type LinkedPurchase struct {
+	AuctionID    uuid.UUID
+	OrderID      uuid.UUID
+	SellerID     uuid.UUID
+	BuyerID      uuid.UUID
+	SharedDevice bool
+	SharedIP     bool
+}
+
+type FraudFlagListParams struct {
+	Status *FraudFlagStatus `json:"status"`
+	Check  *FraudCheck      `json:"check"`
+	Page   int              `json:"page"`
+	Limit  int              `json:"limit"`
+}
+
+type ReviewFraudFlagRequest struct {
+	Status FraudFlagStatus `json:"status" validate:"required,oneof=confirmed dismissed"`
+	Note   string          `json:"note" validate:"max=1000"`
+}
+
+// FraudRunResult is how many flags each check raised or refreshed in a run
+type FraudRunResult struct {
+	Flags     map[FraudCheck]int `json:"flags"`
+	StartedAt time.Time          `json:"started_at"`
+	Duration  string             `json:"duration"`
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImportFormat string
+
+const (
+	ImportFormatCSV  ImportFormat = "csv"
+	ImportFormatJSON ImportFormat = "json"
+)
+
+type ImportJobStatus string
+
+const (
+	ImportJobPending    ImportJobStatus = "pending"
+	ImportJobProcessing ImportJobStatus = "processing"
+	ImportJobCompleted  ImportJobStatus = "completed"
+)
+
+// ImportJob is a bulk listing import. Its rows become draft auctions in the
+// background; the report records what happened to each.
+type ImportJob struct {
+	ID            uuid.UUID         `json:"id" db:"id"`
+	SellerID      uuid.UUID         `json:"seller_id" db:"seller_id"`
+	Format        ImportFormat      `json:"format" db:"format"`
+	Filename      string            `json:"filename" db:"filename"`
+	Status        ImportJobStatus   `json:"status" db:"status"`
+	Rows          []ImportRow       `json:"-" db:"rows"`
+	TotalRows     int               `json:"total_rows" db:"total_rows"`
+	ProcessedRows int               `json:"processed_rows" db:"processed_rows"`
+	CreatedCount  int               `json:"created_count" db:"created_count"`
+	FailedCount   int               `json:"failed_count" db:"failed_count"`
+	Report        []ImportRowResult `json:"-" db:"report"`
+	LeaseUntil    *time.Time        `json:"-" db:"lease_until"`
+	StartedAt     *time.Time        `json:"started_at,omitempty" db:"started_at"`
+	CompletedAt   *time.Time        `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt     time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// ImportRow is one listing of an import file, kept as written so the report
+// can point at values that didn't parse. Times are RFC 3339; the category is
+// given by slug.
+type ImportRow struct {
+	Title         string   `json:"title"`
+	Description   string   `json:"description,omitempty"`
+	Category      string   `json:"category,omitempty"`
+	Condition     string   `json:"condition,omitempty"`
+	StartingPrice string   `json:"starting_price"`
+	ReservePrice  string   `json:"reserve_price,omitempty"`
+	BuyNowPrice   string   `json:"buy_now_price,omitempty"`
+	BidIncrement  string   `json:"bid_increment,omitempty"`
+	StartTime     string   `json:"start_time,omitempty"`
+	EndTime       string   `json:"end_time"`
+	ImageURLs     []string `json:"image_urls,omitempty"`
+}
+
+type ImportRowStatus string
+
+const (
+	ImportRowCreated ImportRowStatus = "created"
+	ImportRowFailed  ImportRowStatus = "failed"
+)
+
+// ImportRowResult is the report entry for one row. Rows are numbered from 1
+// in file order, not counting a CSV header. Warnings, such as an image that
+// couldn't be fetched, don't stop the listing being created.
+type ImportRowResult struct {
+	Row       int             `json:"row"`
+	Title     string          `json:"title"`
+	Status    ImportRowStatus `json:"status"`
+	AuctionID *uuid.UUID      `json:"auction_id,omitempty"`
+	Errors    []string        `json:"errors,omitempty"`
+	Warnings  []string        `json:"warnings,omitempty"`
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuctionVisibility controls who can find and open an auction
+type AuctionVisibility string
+
+const (
+	AuctionPublic AuctionVisibility = "public"
+	// Private auctions are left out of listings and search, and only their
+	// seller, invited users and holders of a share link can open them
+	AuctionPrivate AuctionVisibility = "private"
+)
+
+// AuctionInvite lets a user see a private auction
+type AuctionInvite struct {
+	AuctionID uuid.UUID `json:"auction_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type InviteUserRequest struct {
+	Username string `json:"username" validate:"required"`
+}
+
+// ShareLink grants read access to a private auction until it expires or the
+// seller revokes their share links
+type ShareLink struct {
+	URL       string    `json:"url"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// LedgerAccount is an account money moves between. Buyer payments and
+// refunds are money entering and leaving the platform; escrow holds paid
+// orders until they are released to the seller's balance. Withdrawals move
+// from the seller's balance through the payout accounts until paid out.
+type LedgerAccount string
+
+const (
+	LedgerAccountBuyerPayments    LedgerAccount = "buyer_payments"
+	LedgerAccountEscrow           LedgerAccount = "escrow"
+	LedgerAccountSellerBalance    LedgerAccount = "seller_balance"
+	LedgerAccountBuyerRefunds     LedgerAccount = "buyer_refunds"
+	LedgerAccountPayoutsRequested LedgerAccount = "payouts_requested"
+	LedgerAccountPayoutsApproved  LedgerAccount = "payouts_approved"
+	LedgerAccountPayoutsPaid      LedgerAccount = "payouts_paid"
+)
+
+// LedgerEntry is one side of a double-entry transaction. The entries of a
+// transaction sum to zero; a positive amount is money into the account.
+type LedgerEntry struct {
+	ID            uuid.UUID       `json:"id" db:"id"`
+	TransactionID uuid.UUID       `json:"transaction_id" db:"transaction_id"`
+	Account       LedgerAccount   `json:"account" db:"account"`
+	UserID        *uuid.UUID      `json:"user_id,omitempty" db:"user_id"`
+	OrderID       *uuid.UUID      `json:"order_id,omitempty" db:"order_id"`
+	PayoutID      *uuid.UUID      `json:"payout_id,omitempty" db:"payout_id"`
+	Amount        decimal.Decimal `json:"amount" db:"amount"`
+	Memo          string          `json:"memo" db:"memo"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+}
+
+// Balance is what the platform owes a seller. Pending is still held in
+// escrow for their paid orders; Withdrawing is in payouts not yet paid.
+type Balance struct {
+	UserID      uuid.UUID       `json:"user_id"`
+	Available   decimal.Decimal `json:"available"`
+	Pending     decimal.Decimal `json:"pending"`
+	Withdrawing decimal.Decimal `json:"withdrawing"`
+}
+
+type EscrowStatus string
+
+const (
+	EscrowHeld     EscrowStatus = "held"
+	EscrowReleased EscrowStatus = "released"
+	EscrowRefunded EscrowStatus = "refunded"
+)
+
+// EscrowHold is the payment for an order, held until the buyer confirms
+// delivery or the hold period runs out
+type EscrowHold struct {
+	OrderID             uuid.UUID       `json:"order_id" db:"order_id"`
+	BuyerID             uuid.UUID       `json:"buyer_id" db:"buyer_id"`
+	SellerID            uuid.UUID       `json:"seller_id" db:"seller_id"`
+	Amount              decimal.Decimal `json:"amount" db:"amount"`
+	Refunded            decimal.Decimal `json:"refunded" db:"refunded"`
+	Status              EscrowStatus    `json:"status" db:"status"`
+	ReleaseAt           time.Time       `json:"release_at" db:"release_at"`
+	DeliveryConfirmedAt *time.Time      `json:"delivery_confirmed_at,omitempty" db:"delivery_confirmed_at"`
+	ReleasedAt          *time.Time      `json:"released_at,omitempty" db:"released_at"`
+	CreatedAt           time.Time       `json:"created_at" db:"created_at"`
+
+	// Disputed is set while the order has an open dispute, which blocks
+	// the release
+	Disputed bool `json:"disputed"`
+}
+
+// Remaining is the part of the hold not refunded to the buyer
+func (h *EscrowHold) Remaining() decimal.Decimal {
+	return h.Amount.Sub(h.Refunded)
+}
+
+type ReleaseEscrowRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=1000"`
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// MaxListingDurationHours bounds the durations listing rules may set
+const MaxListingDurationHours = 30 * 24
+
+// CategoryListingRules are the defaults a category gives new drafts and the
+// requirements its auctions must meet to be published, e.g. a minimum number
+// of photos for graded cards or longer durations for bulk lots. Fields left
+// unset keep the site-wide behaviour.
+type CategoryListingRules struct {
+	CategoryID uuid.UUID `json:"category_id" db:"category_id"`
+
+	// Given to drafts that don't set their own end time or increment
+	DefaultDurationHours *int             `json:"default_duration_hours,omitempty" db:"default_duration_hours"`
+	DefaultBidIncrement  *decimal.Decimal `json:"default_bid_increment,omitempty" db:"default_bid_increment"`
+
+	// Checked when the auction is published
+	MinDurationHours *int             `json:"min_duration_hours,omitempty" db:"min_duration_hours"`
+	MaxDurationHours *int             `json:"max_duration_hours,omitempty" db:"max_duration_hours"`
+	MinBidIncrement  *decimal.Decimal `json:"min_bid_increment,omitempty" db:"min_bid_increment"`
+	MinImages        int              `json:"min_images" db:"min_images"`
+
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Validate checks the rules agree with each other: minimums within maximums
+// and defaults within both
+func (r *CategoryListingRules) Validate() error {
+	if r.MinDurationHours != nil && r.MaxDurationHours != nil && *r.MinDurationHours > *r.MaxDurationHours {
+		return ErrInvalidListingRules
+	}
+	if d := r.DefaultDurationHours; d != nil {
+		if (r.MinDurationHours != nil && *d < *r.MinDurationHours) || (r.MaxDurationHours != nil && *d > *r.MaxDurationHours) {
+			return ErrInvalidListingRules
+		}
+	}
+	if r.DefaultBidIncrement != nil && r.MinBidIncrement != nil && r.DefaultBidIncrement.LessThan(*r.MinBidIncrement) {
+		return ErrInvalidListingRules
+	}
+	return nil
+}
+
+// Check returns what about an auction breaks the rules, by field, or nil if
+// it meets them
+func (r *CategoryListingRules) Check(auction *Auction, images int) map[string]string {
+	violations := make(map[string]string)
+
+	duration := auction.EndTime.Sub(auction.StartTime)
+	if r.MinDurationHours != nil && duration < time.Duration(*r.MinDurationHours)*time.Hour {
+		violations["end_time"] = fmt.Sprintf("must be at least %d hours after the start", *r.MinDurationHours)
+	}
+	if r.MaxDurationHours != nil && duration > time.Duration(*r.MaxDurationHours)*time.Hour {
+		violations["end_time"] = fmt.Sprintf("must be at most %d hours after the start", *r.MaxDurationHours)
+	}
+	if r.MinBidIncrement != nil && auction.BidIncrement.LessThan(*r.MinBidIncrement) {
+		violations["bid_increment"] = "must be at least " + auction.Price(*r.MinBidIncrement).String()
+	}
+	if images < r.MinImages {
+		violations["images"] = fmt.Sprintf("at least %d photos are required, %d uploaded", r.MinImages, images)
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return violations
+}
+
+// ListingRulesError is returned when publishing an auction that breaks its
+// category's listing rules. It matches ErrListingRules.
+type ListingRulesError struct {
+	Category   string
+	Violations map[string]string
+}
+
+func (e *ListingRulesError) Error() string {
+	return fmt.Sprintf("auction doesn't meet the listing rules of the %s category", e.Category)
+}
+
+func (e *ListingRulesError) Is(target error) bool {
+	return target == ErrListingRules
+}
+
+type SetCategoryListingRulesRequest struct {
+	DefaultDurationHours *int    `json:"default_duration_hours" validate:"omitempty,min=1,max=720"`
+	DefaultBidIncrement  *string `json:"default_bid_increment" validate:"omitempty,numeric,gt=0"`
+	MinDurationHours     *int    `json:"min_duration_hours" validate:"omitempty,min=1,max=720"`
+	MaxDurationHours     *int    `json:"max_duration_hours" validate:"omitempty,min=1,max=720"`
+	MinBidIncrement      *string `json:"min_bid_increment" validate:"omitempty,numeric,gt=0"`
+	MinImages            int     `json:"min_images" validate:"min=0,max=20"`
+}
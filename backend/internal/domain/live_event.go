@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type LiveEventStatus string
+
+const (
+	LiveEventScheduled LiveEventStatus = "scheduled"
+	LiveEventLive      LiveEventStatus = "live"
+	LiveEventEnded     LiveEventStatus = "ended"
+)
+
+type LiveLotState string
+
+const (
+	LiveLotPending     LiveLotState = "pending"
+	LiveLotOpen        LiveLotState = "open"
+	LiveLotFairWarning LiveLotState = "fair_warning"
+	LiveLotSold        LiveLotState = "sold"
+	LiveLotPassed      LiveLotState = "passed"
+)
+
+// IsActive reports whether the lot is open for bids
+func (s LiveLotState) IsActive() bool {
+	return s == LiveLotOpen || s == LiveLotFairWarning
+}
+
+// LiveEvent is a session in which an auctioneer runs lots one after another
+// in real time
+type LiveEvent struct {
+	ID           uuid.UUID       `json:"id" db:"id"`
+	AuctioneerID uuid.UUID       `json:"auctioneer_id" db:"auctioneer_id"`
+	Title        string          `json:"title" db:"title"`
+	Description  *string         `json:"description,omitempty" db:"description"`
+	StartsAt     time.Time       `json:"starts_at" db:"starts_at"`
+	Status       LiveEventStatus `json:"status" db:"status"`
+	CurrentLotID *uuid.UUID      `json:"current_lot_id,omitempty" db:"current_lot_id"`
+	StartedAt    *time.Time      `json:"started_at,omitempty" db:"started_at"`
+	EndedAt      *time.Time      `json:"ended_at,omitempty" db:"ended_at"`
+	CreatedAt    time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at" db:"updated_at"`
+
+	// Joined fields
+	Lots []LiveLot `json:"lots,omitempty"`
+}
+
+// LiveLot is one of a live event's auctions and how far it has been run
+type LiveLot struct {
+	EventID   uuid.UUID    `json:"event_id" db:"event_id"`
+	AuctionID uuid.UUID    `json:"auction_id" db:"auction_id"`
+	Position  int          `json:"position" db:"position"`
+	State     LiveLotState `json:"state" db:"state"`
+	OpenedAt  *time.Time   `json:"opened_at,omitempty" db:"opened_at"`
+	ClosedAt  *time.Time   `json:"closed_at,omitempty" db:"closed_at"`
+
+	// Joined fields
+	Title        string          `json:"title" db:"-"`
+	CurrentPrice decimal.Decimal `json:"current_price" db:"-"`
+	BidCount     int             `json:"bid_count" db:"-"`
+}
+
+type CreateLiveEventRequest struct {
+	Title       string    `json:"title" validate:"required,min=3,max=200"`
+	Description *string   `json:"description" validate:"omitempty,max=5000"`
+	StartsAt    time.Time `json:"starts_at" validate:"required"`
+	// Draft auctions to run, in order
+	AuctionIDs []uuid.UUID `json:"auction_ids" validate:"required,min=1,max=200"`
+}
+
+// LiveCommandType is an auctioneer's command over /ws/live-events/{id}
+type LiveCommandType string
+
+const (
+	LiveCommandOpenLot     LiveCommandType = "open_lot"
+	LiveCommandFairWarning LiveCommandType = "fair_warning"
+	LiveCommandHammer      LiveCommandType = "hammer"
+)
+
+// LiveCommand is sent by the auctioneer to run the event. Opening a lot
+// takes the next pending lot unless AuctionID picks one; fair warning and
+// hammer apply to the open lot.
+type LiveCommand struct {
+	Type      LiveCommandType `json:"type"`
+	AuctionID *uuid.UUID      `json:"auction_id,omitempty"`
+}
+
+// Live event messages, sent to the event's room alongside the bids on its
+// open lot
+const (
+	WSMessageLotOpened      WSMessageType = "lot_opened"
+	WSMessageFairWarning    WSMessageType = "fair_warning"
+	WSMessageLotHammered    WSMessageType = "lot_hammered"
+	WSMessageLiveEventEnded WSMessageType = "live_event_ended"
+)
+
+type WSLiveLotPayload struct {
+	EventID      uuid.UUID       `json:"event_id"`
+	AuctionID    uuid.UUID       `json:"auction_id"`
+	Position     int             `json:"position"`
+	State        LiveLotState    `json:"state"`
+	CurrentPrice decimal.Decimal `json:"current_price"`
+	WinnerID     *uuid.UUID      `json:"winner_id,omitempty"`
+}
+
+type WSLiveEventEndedPayload struct {
+	EventID uuid.UUID `json:"event_id"`
+}
+
+type WSErrorPayload struct {
+	Message string `json:"message"`
+	// The auction a subscription request failed for
+	AuctionID *uuid.UUID `json:"auction_id,omitempty"`
+}
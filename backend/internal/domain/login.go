@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LoginMethod string
+
+const (
+	LoginMethodPassword LoginMethod = "password"
+	LoginMethodOAuth    LoginMethod = "oauth"
+)
+
+// LoginEvent is one successful sign-in to a user's account
+type LoginEvent struct {
+	ID     uuid.UUID   `json:"id" db:"id"`
+	UserID uuid.UUID   `json:"-" db:"user_id"`
+	Method LoginMethod `json:"method" db:"method"`
+	// Provider names the OAuth provider for OAuth sign-ins
+	Provider  *string    `json:"provider,omitempty" db:"provider"`
+	IP        *string    `json:"ip" db:"ip"`
+	Country   *string    `json:"country" db:"country"` // ISO 3166 code, if known
+	UserAgent *string    `json:"user_agent" db:"user_agent"`
+	DeviceID  *uuid.UUID `json:"device_id,omitempty" db:"device_id"`
+	// ReportedAt is set once the user said the sign-in wasn't theirs
+	ReportedAt *time.Time `json:"reported_at,omitempty" db:"reported_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaintenanceRetryAfter is how long clients are told to wait when
+// maintenance has no expected end
+const MaintenanceRetryAfter = 5 * time.Minute
+
+// MaintenanceStatus is whether the API is in read-only maintenance mode
+type MaintenanceStatus struct {
+	Enabled   bool       `json:"enabled"`
+	Message   string     `json:"message,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	StartedBy *uuid.UUID `json:"started_by,omitempty"`
+	// When maintenance is expected to end; it still only ends when switched off
+	EndsAt *time.Time `json:"ends_at,omitempty"`
+}
+
+// RetryAfter is the number of seconds clients should wait before retrying a
+// write
+func (s *MaintenanceStatus) RetryAfter(now time.Time) int {
+	wait := MaintenanceRetryAfter
+	if s.EndsAt != nil && s.EndsAt.After(now) {
+		wait = s.EndsAt.Sub(now)
+	}
+	return int(math.Ceil(wait.Seconds()))
+}
+
+type SetMaintenanceRequest struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message" validate:"max=500"`
+	// Expected length, used for Retry-After; 0 if unknown
+	DurationMinutes int `json:"duration_minutes" validate:"min=0,max=1440"`
+}
+
+// MaintenanceWindow is planned downtime scheduled ahead, e.g. for database
+// maintenance. The API stays up, but auctions don't end while a window is in
+// effect and the ones that came due are extended by the downtime once it's
+// over.
+type MaintenanceWindow struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Reason    string    `json:"reason" db:"reason"`
+	StartsAt  time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt    time.Time `json:"ends_at" db:"ends_at"`
+	CreatedBy uuid.UUID `json:"created_by" db:"created_by"`
+	// Set once the window is over and its auctions were extended
+	CompletedAt      *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	AuctionsExtended int        `json:"auctions_extended" db:"auctions_extended"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+}
+
+// InEffect reports whether the window has started and not yet been completed
+func (w *MaintenanceWindow) InEffect(now time.Time) bool {
+	return w.CompletedAt == nil && !now.Before(w.StartsAt)
+}
+
+// Downtime is how long the window kept auctions from ending: until it was
+// completed, or as planned while it hasn't been
+func (w *MaintenanceWindow) Downtime() time.Duration {
+	if w.CompletedAt != nil {
+		return w.CompletedAt.Sub(w.StartsAt)
+	}
+	return w.EndsAt.Sub(w.StartsAt)
+}
+
+// RetryAfter is the number of seconds clients should wait for the window to
+// end before retrying. A window past its end is completed within moments.
+func (w *MaintenanceWindow) RetryAfter(now time.Time) int {
+	wait := max(w.EndsAt.Sub(now), 5*time.Second)
+	return int(math.Ceil(wait.Seconds()))
+}
+
+// MaxMaintenanceWindow bounds how long a planned window may last
+const MaxMaintenanceWindow = 24 * time.Hour
+
+type CreateMaintenanceWindowRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+	// Defaults to now
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   time.Time  `json:"ends_at"`
+}
@@ -6,23 +6,42 @@ import (
 	"github.com/google/uuid"
 )
 
+// EncryptionMode says who can read a conversation's messages
+type EncryptionMode string
+
+const (
+	// EncryptionModeServer messages are encrypted at rest with a server key
+	EncryptionModeServer EncryptionMode = "server"
+	// EncryptionModeE2E messages are encrypted by clients and unreadable by
+	// the server
+	EncryptionModeE2E EncryptionMode = "e2e"
+)
+
 // Conversation represents a chat between two users
 type Conversation struct {
-	ID             uuid.UUID  `json:"id" db:"id"`
-	ParticipantOne uuid.UUID  `json:"participant_one" db:"participant_one"`
-	ParticipantTwo uuid.UUID  `json:"participant_two" db:"participant_two"`
-	LastMessageAt  *time.Time `json:"last_message_at" db:"last_message_at"`
-	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	ID             uuid.UUID      `json:"id" db:"id"`
+	ParticipantOne uuid.UUID      `json:"participant_one" db:"participant_one"`
+	ParticipantTwo uuid.UUID      `json:"participant_two" db:"participant_two"`
+	EncryptionMode EncryptionMode `json:"encryption_mode" db:"encryption_mode"`
+	LegalHold      bool           `json:"legal_hold" db:"legal_hold"`
+	LastMessageAt  *time.Time     `json:"last_message_at" db:"last_message_at"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
 }
 
 // ConversationWithDetails includes participant info and unread count
 type ConversationWithDetails struct {
-	ID              uuid.UUID   `json:"id"`
-	OtherUser       *PublicUser `json:"other_user"`
-	LastMessage     *Message    `json:"last_message,omitempty"`
-	LastMessageAt   *time.Time  `json:"last_message_at"`
-	UnreadCount     int         `json:"unread_count"`
-	CreatedAt       time.Time   `json:"created_at"`
+	ID             uuid.UUID      `json:"id"`
+	OtherUser      *PublicUser    `json:"other_user"`
+	EncryptionMode EncryptionMode `json:"encryption_mode"`
+	LastMessage    *Message       `json:"last_message,omitempty"`
+	LastMessageAt  *time.Time     `json:"last_message_at"`
+	UnreadCount    int            `json:"unread_count"`
+	CreatedAt      time.Time      `json:"created_at"`
+
+	// Retention notice: messages older than RetentionDays are deleted unless
+	// the conversation is on legal hold. Nil means they're kept indefinitely.
+	RetentionDays *int `json:"retention_days"`
+	LegalHold     bool `json:"legal_hold"`
 }
 
 // Message represents a single message in a conversation
@@ -34,6 +53,25 @@ type Message struct {
 	ContentNonce     []byte    `json:"-" db:"content_nonce"`
 	Content          string    `json:"content" db:"-"` // Decrypted content, not stored in DB
 	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+
+	// Set instead of Content for end-to-end encrypted messages. KeyEnvelopes
+	// maps device key IDs to the message key wrapped for that device.
+	Ciphertext   []byte               `json:"ciphertext,omitempty" db:"-"`
+	Nonce        []byte               `json:"nonce,omitempty" db:"-"`
+	KeyEnvelopes map[uuid.UUID][]byte `json:"key_envelopes,omitempty" db:"key_envelopes"`
+
+	// Scam categories found in the content, so clients can warn the recipient
+	ModerationFlags []string `json:"moderation_flags,omitempty" db:"moderation_flags"`
+}
+
+// DeviceKey is a public key a user registered for one of their devices
+type DeviceKey struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	DeviceID  string    `json:"device_id" db:"device_id"`
+	PublicKey string    `json:"public_key" db:"public_key"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // MessageWithSender includes sender info
@@ -42,17 +80,38 @@ type MessageWithSender struct {
 	Sender *PublicUser `json:"sender"`
 }
 
-// ConversationReadStatus tracks when a user last read a conversation
+// ConversationReadStatus tracks when a user last read a conversation and how
+// many messages have arrived since
 type ConversationReadStatus struct {
-	ConversationID uuid.UUID `json:"conversation_id" db:"conversation_id"`
-	UserID         uuid.UUID `json:"user_id" db:"user_id"`
-	LastReadAt     time.Time `json:"last_read_at" db:"last_read_at"`
+	ConversationID uuid.UUID  `json:"conversation_id" db:"conversation_id"`
+	UserID         uuid.UUID  `json:"user_id" db:"user_id"`
+	LastReadAt     *time.Time `json:"last_read_at" db:"last_read_at"`
+	UnreadCount    int        `json:"unread_count" db:"unread_count"`
 }
 
 // Request DTOs
+
+// SendMessageRequest sends either plaintext Content, which the server
+// encrypts, or an end-to-end encrypted Ciphertext with its key envelopes
 type SendMessageRequest struct {
-	RecipientID uuid.UUID `json:"recipient_id" validate:"required"`
-	Content     string    `json:"content" validate:"required,min=1,max=5000"`
+	RecipientID  uuid.UUID            `json:"recipient_id" validate:"required"`
+	Content      string               `json:"content" validate:"required_without=Ciphertext,excluded_with=Ciphertext,max=5000"`
+	Ciphertext   []byte               `json:"ciphertext,omitempty" validate:"omitempty,max=16384"`
+	Nonce        []byte               `json:"nonce,omitempty" validate:"required_with=Ciphertext"`
+	KeyEnvelopes map[uuid.UUID][]byte `json:"key_envelopes,omitempty" validate:"required_with=Ciphertext"`
+}
+
+// TranscriptAccessRequest justifies an admin reading a conversation. It
+// must reference an open dispute or report involving both participants.
+type TranscriptAccessRequest struct {
+	DisputeID *uuid.UUID `json:"dispute_id" validate:"required_without=ReportID"`
+	ReportID  *uuid.UUID `json:"report_id" validate:"required_without=DisputeID"`
+	Reason    string     `json:"reason" validate:"required,min=10,max=500"`
+}
+
+type RegisterDeviceKeyRequest struct {
+	DeviceID  string `json:"device_id" validate:"required,max=100"`
+	PublicKey string `json:"public_key" validate:"required,max=4096"`
 }
 
 type GetMessagesRequest struct {
@@ -62,8 +121,11 @@ type GetMessagesRequest struct {
 
 // Response DTOs
 type SendMessageResponse struct {
-	Message        *Message  `json:"message"`
-	ConversationID uuid.UUID `json:"conversation_id"`
+	Message        *Message            `json:"message"`
+	ConversationID uuid.UUID           `json:"conversation_id"`
+	Warnings       []ModerationWarning `json:"warnings,omitempty"`
+	// Sent back on the recipient's behalf while they're on vacation
+	AutoReply *Message `json:"auto_reply,omitempty"`
 }
 
 type ConversationsResponse struct {
@@ -71,11 +133,48 @@ type ConversationsResponse struct {
 }
 
 type MessagesResponse struct {
-	Messages []MessageWithSender `json:"messages"`
+	Messages      []MessageWithSender `json:"messages"`
+	HasMoreBefore bool                `json:"has_more_before"`
+	HasMoreAfter  bool                `json:"has_more_after"`
+	FirstUnreadID *uuid.UUID          `json:"first_unread_id,omitempty"`
+}
+
+// MessageAnchorFirstUnread centres a message window on the first message the
+// user hasn't read yet
+const MessageAnchorFirstUnread = "first_unread"
+
+// MessageWindowParams selects messages by cursor. Before and After page
+// from a message ID, Around loads messages on either side of a point in time.
+type MessageWindowParams struct {
+	Before *uuid.UUID
+	After  *uuid.UUID
+	Around *time.Time
+	Limit  int
+}
+
+// MessageWindow is a page of messages in chronological order
+type MessageWindow struct {
+	Messages      []Message
+	HasMoreBefore bool
+	HasMoreAfter  bool
+}
+
+// ConversationTranscript is a decrypted conversation as shown to an admin
+// reviewing a dispute or report. End-to-end encrypted messages can't be
+// decrypted by the platform and are only counted.
+type ConversationTranscript struct {
+	Conversation   *Conversation       `json:"conversation"`
+	Participants   []*PublicUser       `json:"participants"`
+	Messages       []MessageWithSender `json:"messages"`
+	EncryptedCount int                 `json:"encrypted_count"`
+	// Set when message contents were withheld from an admin outside the
+	// data region
+	Redacted bool `json:"redacted,omitempty"`
 }
 
 type UnreadCountResponse struct {
-	Count int `json:"count"`
+	Count         int               `json:"count"`
+	Conversations map[uuid.UUID]int `json:"conversations"`
 }
 
 // WebSocket message types for real-time messaging
@@ -86,11 +185,44 @@ const (
 	MessageWSTypeMessageRead   MessageWSType = "message_read"
 	MessageWSTypeTypingStarted MessageWSType = "typing_started"
 	MessageWSTypeTypingStopped MessageWSType = "typing_stopped"
+	MessageWSTypeUserOnline    MessageWSType = "user_online"
+	MessageWSTypeUserOffline   MessageWSType = "user_offline"
+	MessageWSTypeOfferUpdated  MessageWSType = "offer_updated"
+	MessageWSTypeMaintenance   MessageWSType = "maintenance"
+	MessageWSTypeOutbid        MessageWSType = "outbid"
 )
 
 type MessageWSPayload struct {
+	Type           MessageWSType      `json:"type"`
+	Message        *Message           `json:"message,omitempty"`
+	ConversationID uuid.UUID          `json:"conversation_id,omitempty"`
+	SenderID       uuid.UUID          `json:"sender_id,omitempty"`
+	UserID         uuid.UUID          `json:"user_id,omitempty"`
+	LastSeenAt     *time.Time         `json:"last_seen_at,omitempty"`
+	Offer          *Offer             `json:"offer,omitempty"`
+	Maintenance    *MaintenanceStatus `json:"maintenance,omitempty"`
+	Outbid         *OutbidPush        `json:"outbid,omitempty"`
+}
+
+// OutbidPush tells a bidder the moment they are outbid. MinimumBid is the
+// least they can bid to lead again, so a client can offer a one-click rebid.
+type OutbidPush struct {
+	AuctionID    uuid.UUID `json:"auction_id"`
+	AuctionTitle string    `json:"auction_title"`
+	Amount       Money     `json:"amount"`
+	MinimumBid   Money     `json:"minimum_bid"`
+	EndTime      time.Time `json:"end_time"`
+}
+
+// MessageWSClientEvent is sent by clients over /ws/messages
+type MessageWSClientEvent struct {
 	Type           MessageWSType `json:"type"`
-	Message        *Message      `json:"message,omitempty"`
-	ConversationID uuid.UUID     `json:"conversation_id,omitempty"`
-	SenderID       uuid.UUID     `json:"sender_id,omitempty"`
+	ConversationID uuid.UUID     `json:"conversation_id"`
+}
+
+// UserPresence is the current online state of a user
+type UserPresence struct {
+	UserID     uuid.UUID  `json:"user_id"`
+	Online     bool       `json:"online"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
 }
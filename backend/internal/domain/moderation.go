@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ModerationCaseStatus is the review state of a moderation queue entry
+type ModerationCaseStatus string
+
+const (
+	ModerationCaseOpen      ModerationCaseStatus = "open"
+	ModerationCaseReviewed  ModerationCaseStatus = "reviewed"
+	ModerationCaseDismissed ModerationCaseStatus = "dismissed"
+)
+
+// ModerationCase puts a user in front of moderators after repeated flagged
+// messages. A user has at most one open case; later offences bump its count.
+type ModerationCase struct {
+	ID           uuid.UUID            `json:"id" db:"id"`
+	UserID       uuid.UUID            `json:"user_id" db:"user_id"`
+	Reason       string               `json:"reason" db:"reason"`
+	OffenseCount int                  `json:"offense_count" db:"offense_count"`
+	Status       ModerationCaseStatus `json:"status" db:"status"`
+	ReviewedBy   *uuid.UUID           `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	CreatedAt    time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at" db:"updated_at"`
+
+	// Joined fields
+	User *PublicUser `json:"user,omitempty"`
+}
+
+// ModerationWarning tells a sender why their message was flagged or altered
+type ModerationWarning struct {
+	Category string `json:"category"`
+	Message  string `json:"message"`
+}
+
+type ModerationCaseListParams struct {
+	Status *ModerationCaseStatus `json:"status"`
+	Page   int                   `json:"page"`
+	Limit  int                   `json:"limit"`
+}
+
+type UpdateModerationCaseRequest struct {
+	Status ModerationCaseStatus `json:"status" validate:"required,oneof=reviewed dismissed"`
+	Note   string               `json:"note" validate:"max=500"`
+}
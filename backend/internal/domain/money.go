@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Currency is the ISO 4217 code an auction is priced in. Balances, escrow
+// and payouts are kept in DefaultCurrency.
+type Currency string
+
+const (
+	CurrencyUSD Currency = "USD"
+	CurrencyEUR Currency = "EUR"
+	CurrencyBAM Currency = "BAM"
+
+	DefaultCurrency = CurrencyUSD
+)
+
+// MoneyScale is the number of decimal places amounts are kept to. Every
+// supported currency has cents.
+const MoneyScale int32 = 2
+
+// Valid reports whether the currency is supported
+func (c Currency) Valid() bool {
+	switch c {
+	case CurrencyUSD, CurrencyEUR, CurrencyBAM:
+		return true
+	}
+	return false
+}
+
+// Format formats an amount in the currency for display, such as "$12.50"
+// or "12.50 KM"
+func (c Currency) Format(amount decimal.Decimal) string {
+	fixed := amount.Abs().StringFixed(MoneyScale)
+	sign := ""
+	if amount.IsNegative() {
+		sign = "-"
+	}
+
+	switch c {
+	case CurrencyUSD:
+		return sign + "$" + fixed
+	case CurrencyEUR:
+		return sign + "€" + fixed
+	case CurrencyBAM:
+		return sign + fixed + " KM"
+	default:
+		return sign + fixed + " " + string(c)
+	}
+}
+
+// RoundingMode says how an amount is rounded to whole cents
+type RoundingMode string
+
+const (
+	// RoundHalfUp rounds halves away from zero, as prices are
+	RoundHalfUp RoundingMode = "half_up"
+	// RoundHalfEven rounds halves to the even cent (banker's rounding), so
+	// rounding many fees doesn't drift in one direction
+	RoundHalfEven RoundingMode = "half_even"
+	// RoundDown drops fractions of a cent, as markdowns do so they never
+	// take off more than promised
+	RoundDown RoundingMode = "down"
+)
+
+// FeeRounding is how fees are rounded to whole cents
+var FeeRounding = RoundHalfEven
+
+// ParseRoundingMode parses a configured rounding mode
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	switch mode := RoundingMode(s); mode {
+	case RoundHalfUp, RoundHalfEven, RoundDown:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown rounding mode %q", s)
+}
+
+// Round rounds the amount to whole cents
+func (m RoundingMode) Round(amount decimal.Decimal) decimal.Decimal {
+	switch m {
+	case RoundHalfEven:
+		return amount.RoundBank(MoneyScale)
+	case RoundDown:
+		return amount.Truncate(MoneyScale)
+	default:
+		return amount.Round(MoneyScale)
+	}
+}
+
+// ParseAmount parses an amount sent by a client. Amounts are strings so
+// they never pass through a float, and may not have fractions of a cent:
+// rounding them would make the amount stored differ from the one sent.
+func ParseAmount(s string) (decimal.Decimal, error) {
+	amount, err := decimal.NewFromString(s)
+	if err != nil || !amount.Equal(amount.Truncate(MoneyScale)) {
+		return decimal.Zero, ErrInvalidAmount
+	}
+	return amount, nil
+}
+
+// Money is an amount in a currency. It serializes as
+// {"amount": "12.50", "currency": "USD"}, the amount a string in whole
+// cents.
+type Money struct {
+	Amount   decimal.Decimal
+	Currency Currency
+}
+
+func NewMoney(amount decimal.Decimal, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Round returns the money rounded to whole cents
+func (m Money) Round(mode RoundingMode) Money {
+	return Money{Amount: mode.Round(m.Amount), Currency: m.Currency}
+}
+
+// Fee returns rate of the money, such as 0.05 for a 5% fee, rounded by
+// FeeRounding
+func (m Money) Fee(rate decimal.Decimal) Money {
+	return NewMoney(m.Amount.Mul(rate), m.Currency).Round(FeeRounding)
+}
+
+// String formats the money for display
+func (m Money) String() string {
+	return m.Currency.Format(m.Amount)
+}
+
+type moneyJSON struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency Currency        `json:"currency"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	amount, err := json.Marshal(m.Amount.StringFixed(MoneyScale))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(moneyJSON{Amount: amount, Currency: m.Currency})
+}
+
+// UnmarshalJSON only takes amounts as strings; a JSON number may already
+// have lost precision in the client
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw moneyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var s string
+	if err := json.Unmarshal(raw.Amount, &s); err != nil {
+		return ErrInvalidAmount
+	}
+	amount, err := ParseAmount(s)
+	if err != nil {
+		return err
+	}
+
+	if !raw.Currency.Valid() {
+		return ErrInvalidCurrency
+	}
+
+	m.Amount = amount
+	m.Currency = raw.Currency
+	return nil
+}
@@ -15,6 +15,31 @@ const (
 	NotificationAuctionEnding NotificationType = "auction_ending"
 	NotificationNewBid        NotificationType = "new_bid"
 	NotificationAuctionSold   NotificationType = "auction_sold"
+	NotificationOrderCancelled    NotificationType = "order_cancelled"
+	NotificationSecondChanceOffer NotificationType = "second_chance_offer"
+	NotificationAuctionRelisted   NotificationType = "auction_relisted"
+	NotificationAuctionCancelled  NotificationType = "auction_cancelled"
+	NotificationDisputeOpened     NotificationType = "dispute_opened"
+	NotificationDisputeMessage    NotificationType = "dispute_message"
+	NotificationDisputeResolved   NotificationType = "dispute_resolved"
+	NotificationBidCancelled      NotificationType = "bid_cancelled"
+	NotificationReserveMet        NotificationType = "reserve_met"
+	NotificationSnipeFailed       NotificationType = "snipe_failed"
+	NotificationTranscriptViewed  NotificationType = "transcript_viewed"
+	NotificationSuspiciousLogin   NotificationType = "suspicious_login"
+	NotificationOfferReceived     NotificationType = "offer_received"
+	NotificationOfferCountered    NotificationType = "offer_countered"
+	NotificationOfferDeclined     NotificationType = "offer_declined"
+	NotificationOfferExpired      NotificationType = "offer_expired"
+	NotificationEscrowReleased    NotificationType = "escrow_released"
+	NotificationPayoutRequested   NotificationType = "payout_requested"
+	NotificationPayoutApproved    NotificationType = "payout_approved"
+	NotificationPayoutCompleted   NotificationType = "payout_completed"
+	NotificationPayoutRejected    NotificationType = "payout_rejected"
+	NotificationAuctionInvite     NotificationType = "auction_invite"
+	NotificationPriceDrop         NotificationType = "price_drop"
+	NotificationBidApprovalRequested NotificationType = "bid_approval_requested"
+	NotificationBidApprovalDecided   NotificationType = "bid_approval_decided"
 )
 
 type Notification struct {
@@ -27,21 +52,68 @@ type Notification struct {
 	IsRead    bool             `json:"is_read" db:"is_read"`
 	CreatedAt time.Time        `json:"created_at" db:"created_at"`
 
+	// Where the notification leads, and what can be done straight from it
+	Link    *string              `json:"link,omitempty" db:"link"`
+	Actions []NotificationAction `json:"actions" db:"actions"`
+
 	// Joined fields
 	Auction *Auction `json:"auction,omitempty"`
 }
 
+// NotificationActionType says what an action does, so clients can render it
+// natively instead of only following its URL
+type NotificationActionType string
+
+const (
+	NotificationActionBid    NotificationActionType = "bid"
+	NotificationActionBuyNow NotificationActionType = "buy_now"
+	NotificationActionPay    NotificationActionType = "pay"
+	NotificationActionRate   NotificationActionType = "rate"
+	NotificationActionOffer  NotificationActionType = "offer"
+)
+
+// NotificationAction is a button on a notification. URL is a canonical deep
+// link into the web app, built on the server so no client has to parse the
+// message to find where it leads.
+type NotificationAction struct {
+	Type  NotificationActionType `json:"type"`
+	Label string                 `json:"label"`
+	URL   string                 `json:"url"`
+	// Prefilled amount, such as the minimum bid for "Bid again"
+	Amount *Money `json:"amount,omitempty"`
+}
+
 type NotificationListParams struct {
 	UserID   uuid.UUID `json:"user_id"`
 	Unread   *bool     `json:"unread"`
 	Page     int       `json:"page"`
 	Limit    int       `json:"limit"`
+	// Grouped lists threads instead of single notifications; Unread then
+	// keeps threads with anything unread
+	Grouped bool `json:"grouped"`
+	// Cursor switches to keyset pagination, ignoring Page
+	Cursor *Cursor `json:"-"`
+}
+
+// NotificationThread groups a user's notifications about one auction, so a
+// run of outbid notices shows as a single entry. A notification not about an
+// auction is a thread of its own.
+type NotificationThread struct {
+	// The auction's ID, or the notification's for one not about an auction
+	ID          uuid.UUID  `json:"id"`
+	AuctionID   *uuid.UUID `json:"auction_id,omitempty"`
+	Count       int        `json:"count"`
+	UnreadCount int        `json:"unread_count"`
+	// The thread's newest notification, saying where things stand
+	Latest Notification `json:"latest"`
 }
 
 type NotificationListResponse struct {
-	Notifications []Notification `json:"notifications"`
-	TotalCount    int            `json:"total_count"`
-	UnreadCount   int            `json:"unread_count"`
-	Page          int            `json:"page"`
-	TotalPages    int            `json:"total_pages"`
+	Notifications []Notification       `json:"notifications"`
+	Threads       []NotificationThread `json:"threads,omitempty"`
+	TotalCount    int                  `json:"total_count"`
+	UnreadCount   int                  `json:"unread_count"`
+	Page          int                  `json:"page"`
+	TotalPages    int                  `json:"total_pages"`
+	NextCursor    string               `json:"next_cursor,omitempty"`
 }
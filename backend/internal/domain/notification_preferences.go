@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationTypes lists every type of notification, in the order the
+// settings show them
+var NotificationTypes = []NotificationType{
+	NotificationOutbid,
+	NotificationAuctionWon,
+	NotificationAuctionLost,
+	NotificationAuctionEnding,
+	NotificationNewBid,
+	NotificationAuctionSold,
+	NotificationOrderCancelled,
+	NotificationSecondChanceOffer,
+	NotificationAuctionRelisted,
+	NotificationAuctionCancelled,
+	NotificationDisputeOpened,
+	NotificationDisputeMessage,
+	NotificationDisputeResolved,
+	NotificationBidCancelled,
+	NotificationReserveMet,
+	NotificationSnipeFailed,
+	NotificationTranscriptViewed,
+	NotificationSuspiciousLogin,
+	NotificationOfferReceived,
+	NotificationOfferCountered,
+	NotificationOfferDeclined,
+	NotificationOfferExpired,
+	NotificationEscrowReleased,
+	NotificationPayoutRequested,
+	NotificationPayoutApproved,
+	NotificationPayoutCompleted,
+	NotificationPayoutRejected,
+	NotificationAuctionInvite,
+	NotificationPriceDrop,
+	NotificationBidApprovalRequested,
+	NotificationBidApprovalDecided,
+}
+
+func (t NotificationType) Valid() bool {
+	return slices.Contains(NotificationTypes, t)
+}
+
+// NotificationChannel is a way a notification reaches the user
+type NotificationChannel string
+
+const (
+	NotificationChannelEmail NotificationChannel = "email"
+	NotificationChannelInApp NotificationChannel = "in_app"
+	// Sent over the user's open WebSocket connections, and as web push to
+	// the browsers they subscribed
+	NotificationChannelPush NotificationChannel = "push"
+)
+
+// NotificationPreference is which channels a user gets one type of
+// notification on. Users without a stored preference get every channel.
+type NotificationPreference struct {
+	Type      NotificationType `json:"type" db:"type"`
+	Email     bool             `json:"email" db:"email"`
+	InApp     bool             `json:"in_app" db:"in_app"`
+	Push      bool             `json:"push" db:"push"`
+	UpdatedAt *time.Time       `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+func DefaultNotificationPreference(notificationType NotificationType) NotificationPreference {
+	return NotificationPreference{Type: notificationType, Email: true, InApp: true, Push: true}
+}
+
+// NotificationSettings are a user's preferences for every notification type
+type NotificationSettings struct {
+	UserID      uuid.UUID                `json:"user_id"`
+	Preferences []NotificationPreference `json:"preferences"`
+}
+
+// UpdateNotificationSettingsRequest changes the channels of the types
+// listed. Channels left out keep their setting.
+type UpdateNotificationSettingsRequest struct {
+	Preferences []NotificationPreferenceUpdate `json:"preferences" validate:"required,min=1,max=50,dive"`
+}
+
+type NotificationPreferenceUpdate struct {
+	Type  string `json:"type" validate:"required"`
+	Email *bool  `json:"email"`
+	InApp *bool  `json:"in_app"`
+	Push  *bool  `json:"push"`
+}
+
+// UnsubscribeRequest carries the token from an email's unsubscribe link
+type UnsubscribeRequest struct {
+	Token string `json:"token" validate:"required,max=200"`
+}
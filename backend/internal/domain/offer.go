@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type OfferStatus string
+
+const (
+	OfferStatusPending   OfferStatus = "pending"
+	OfferStatusCountered OfferStatus = "countered"
+	OfferStatusAccepted  OfferStatus = "accepted"
+	OfferStatusDeclined  OfferStatus = "declined"
+	OfferStatusExpired   OfferStatus = "expired"
+)
+
+// Offer is a buyer's proposal to buy an item outright below the buy-now
+// price. The seller accepts, declines or counters it; a counter goes back to
+// the buyer to accept or decline.
+type Offer struct {
+	ID            uuid.UUID        `json:"id" db:"id"`
+	AuctionID     uuid.UUID        `json:"auction_id" db:"auction_id"`
+	BuyerID       uuid.UUID        `json:"buyer_id" db:"buyer_id"`
+	Amount        decimal.Decimal  `json:"amount" db:"amount"`
+	CounterAmount *decimal.Decimal `json:"counter_amount,omitempty" db:"counter_amount"`
+	Message       *string          `json:"message,omitempty" db:"message"`
+	Status        OfferStatus      `json:"status" db:"status"`
+	BidID         *uuid.UUID       `json:"bid_id,omitempty" db:"bid_id"`
+	ExpiresAt     time.Time        `json:"expires_at" db:"expires_at"`
+	RespondedAt   *time.Time       `json:"responded_at,omitempty" db:"responded_at"`
+	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at" db:"updated_at"`
+
+	// Joined fields
+	Auction *Auction `json:"auction,omitempty"`
+}
+
+// IsOpen reports whether the offer still awaits an answer
+func (o *Offer) IsOpen() bool {
+	return (o.Status == OfferStatusPending || o.Status == OfferStatusCountered) && time.Now().Before(o.ExpiresAt)
+}
+
+// Price is what the item sells for if the offer is accepted: the seller's
+// counter once there is one, the buyer's amount otherwise
+func (o *Offer) Price() decimal.Decimal {
+	if o.CounterAmount != nil {
+		return *o.CounterAmount
+	}
+	return o.Amount
+}
+
+// Request/Response DTOs
+type MakeOfferRequest struct {
+	Amount  string  `json:"amount" validate:"required,numeric,gt=0"`
+	Message *string `json:"message" validate:"omitempty,max=500"`
+}
+
+type CounterOfferRequest struct {
+	Amount string `json:"amount" validate:"required,numeric,gt=0"`
+}
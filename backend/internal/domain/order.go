@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusPendingPayment OrderStatus = "pending_payment"
+	OrderStatusPaid           OrderStatus = "paid"
+	OrderStatusCancelled      OrderStatus = "cancelled"
+)
+
+// Reasons recorded on cancelled orders
+const (
+	OrderCancelReasonUnpaid = "unpaid"
+)
+
+type Order struct {
+	ID           uuid.UUID       `json:"id" db:"id"`
+	AuctionID    uuid.UUID       `json:"auction_id" db:"auction_id"`
+	BuyerID      uuid.UUID       `json:"buyer_id" db:"buyer_id"`
+	SellerID     uuid.UUID       `json:"seller_id" db:"seller_id"`
+	BidID        *uuid.UUID      `json:"bid_id,omitempty" db:"bid_id"`
+	Amount       decimal.Decimal `json:"amount" db:"amount"`
+	Status       OrderStatus     `json:"status" db:"status"`
+	CancelReason *string         `json:"cancel_reason,omitempty" db:"cancel_reason"`
+	PaymentDueAt time.Time       `json:"payment_due_at" db:"payment_due_at"`
+	PaidAt       *time.Time      `json:"paid_at,omitempty" db:"paid_at"`
+	CreatedAt    time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at" db:"updated_at"`
+
+	// Joined fields
+	Auction *Auction `json:"auction,omitempty"`
+}
+
+type OrderListParams struct {
+	UserID uuid.UUID    `json:"user_id"`
+	Role   string       `json:"role"` // buyer, seller, or empty for both
+	Status *OrderStatus `json:"status"`
+	Page   int          `json:"page"`
+	Limit  int          `json:"limit"`
+}
+
+type OrderListResponse struct {
+	Orders     []Order `json:"orders"`
+	TotalCount int     `json:"total_count"`
+	Page       int     `json:"page"`
+	TotalPages int     `json:"total_pages"`
+}
+
+type SecondChanceOfferStatus string
+
+const (
+	SecondChanceOfferPending  SecondChanceOfferStatus = "pending"
+	SecondChanceOfferAccepted SecondChanceOfferStatus = "accepted"
+	SecondChanceOfferDeclined SecondChanceOfferStatus = "declined"
+	SecondChanceOfferExpired  SecondChanceOfferStatus = "expired"
+)
+
+// SecondChanceOffer lets the runner-up buy an item at their highest bid
+// after the original winner failed to pay.
+type SecondChanceOffer struct {
+	ID          uuid.UUID               `json:"id" db:"id"`
+	AuctionID   uuid.UUID               `json:"auction_id" db:"auction_id"`
+	OrderID     uuid.UUID               `json:"order_id" db:"order_id"`
+	BidderID    uuid.UUID               `json:"bidder_id" db:"bidder_id"`
+	BidID       *uuid.UUID              `json:"bid_id,omitempty" db:"bid_id"`
+	Amount      decimal.Decimal         `json:"amount" db:"amount"`
+	Status      SecondChanceOfferStatus `json:"status" db:"status"`
+	ExpiresAt   time.Time               `json:"expires_at" db:"expires_at"`
+	RespondedAt *time.Time              `json:"responded_at,omitempty" db:"responded_at"`
+	CreatedAt   time.Time               `json:"created_at" db:"created_at"`
+
+	// Joined fields
+	Auction *Auction `json:"auction,omitempty"`
+}
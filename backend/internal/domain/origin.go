@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AllowedOrigin is an origin pattern an admin allowed for cross-origin
+// requests, typically a partner app
+type AllowedOrigin struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	Origin      string     `json:"origin" db:"origin"`
+	Description *string    `json:"description,omitempty" db:"description"`
+	CreatedBy   *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+type CreateAllowedOriginRequest struct {
+	Origin      string  `json:"origin" validate:"required,max=255"`
+	Description *string `json:"description" validate:"omitempty,max=200"`
+}
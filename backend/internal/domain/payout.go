@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type PayoutMethodType string
+
+const (
+	PayoutMethodIBAN          PayoutMethodType = "iban"
+	PayoutMethodStripeConnect PayoutMethodType = "stripe_connect"
+)
+
+// PayoutMethod is where a seller's withdrawals are sent. IBANs are stored
+// encrypted; sellers only see the last four characters back.
+type PayoutMethod struct {
+	UserID          uuid.UUID        `json:"user_id" db:"user_id"`
+	Type            PayoutMethodType `json:"type" db:"type"`
+	AccountHolder   string           `json:"account_holder" db:"account_holder"`
+	IBANLast4       *string          `json:"iban_last4,omitempty" db:"iban_last4"`
+	IBANEncrypted   []byte           `json:"-" db:"iban_encrypted"`
+	IBANNonce       []byte           `json:"-" db:"iban_nonce"`
+	StripeAccountID *string          `json:"stripe_account_id,omitempty" db:"stripe_account_id"`
+	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+type SetPayoutMethodRequest struct {
+	Type            string  `json:"type" validate:"required,oneof=iban stripe_connect"`
+	AccountHolder   string  `json:"account_holder" validate:"required,min=2,max=200"`
+	IBAN            *string `json:"iban" validate:"required_if=Type iban,omitempty,min=15,max=42"`
+	StripeAccountID *string `json:"stripe_account_id" validate:"required_if=Type stripe_connect,omitempty,startswith=acct_,max=255"`
+}
+
+type PayoutStatus string
+
+const (
+	PayoutRequested PayoutStatus = "requested"
+	PayoutApproved  PayoutStatus = "approved"
+	PayoutCompleted PayoutStatus = "completed"
+	PayoutRejected  PayoutStatus = "rejected"
+)
+
+// Payout is a seller's withdrawal from their balance. The destination is
+// copied from the payout method when requested, so later changes to the
+// method don't redirect it.
+type Payout struct {
+	ID              uuid.UUID        `json:"id" db:"id"`
+	UserID          uuid.UUID        `json:"user_id" db:"user_id"`
+	Amount          decimal.Decimal  `json:"amount" db:"amount"`
+	Status          PayoutStatus     `json:"status" db:"status"`
+	MethodType      PayoutMethodType `json:"method_type" db:"method_type"`
+	AccountHolder   string           `json:"account_holder" db:"account_holder"`
+	IBANLast4       *string          `json:"iban_last4,omitempty" db:"iban_last4"`
+	IBANEncrypted   []byte           `json:"-" db:"iban_encrypted"`
+	IBANNonce       []byte           `json:"-" db:"iban_nonce"`
+	StripeAccountID *string          `json:"stripe_account_id,omitempty" db:"stripe_account_id"`
+	Reference       *string          `json:"reference,omitempty" db:"reference"`
+	RejectionReason *string          `json:"rejection_reason,omitempty" db:"rejection_reason"`
+	ReviewedBy      *uuid.UUID       `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
+	ApprovedAt      *time.Time       `json:"approved_at,omitempty" db:"approved_at"`
+	CompletedAt     *time.Time       `json:"completed_at,omitempty" db:"completed_at"`
+	RejectedAt      *time.Time       `json:"rejected_at,omitempty" db:"rejected_at"`
+
+	// IBAN is the full account number, filled in for admins paying out
+	IBAN *string `json:"iban,omitempty"`
+}
+
+// LedgerAccount is the account holding the payout's money in its status
+func (p *Payout) LedgerAccount() LedgerAccount {
+	switch p.Status {
+	case PayoutRequested:
+		return LedgerAccountPayoutsRequested
+	case PayoutApproved:
+		return LedgerAccountPayoutsApproved
+	case PayoutCompleted:
+		return LedgerAccountPayoutsPaid
+	default:
+		return LedgerAccountSellerBalance
+	}
+}
+
+type RequestPayoutRequest struct {
+	Amount string `json:"amount" validate:"required,numeric,gt=0"`
+}
+
+type CompletePayoutRequest struct {
+	// Reference identifies the transfer with the bank or Stripe
+	Reference string `json:"reference" validate:"required,min=3,max=255"`
+}
+
+type RejectPayoutRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=1000"`
+}
+
+type PayoutListParams struct {
+	UserID *uuid.UUID    `json:"user_id"`
+	Status *PayoutStatus `json:"status"`
+	Page   int           `json:"page"`
+	Limit  int           `json:"limit"`
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// MaxPriceDropPercent caps how much one markdown takes off
+var MaxPriceDropPercent = decimal.NewFromInt(50)
+
+// PriceDropRule marks a listing's buy-now price down by Percent every
+// IntervalDays until it reaches FloorPrice. NextDropAt is nil once the rule
+// has run its course.
+type PriceDropRule struct {
+	AuctionID    uuid.UUID       `json:"auction_id" db:"auction_id"`
+	Percent      decimal.Decimal `json:"percent" db:"percent"`
+	IntervalDays int             `json:"interval_days" db:"interval_days"`
+	FloorPrice   decimal.Decimal `json:"floor_price" db:"floor_price"`
+	NextDropAt   *time.Time      `json:"next_drop_at,omitempty" db:"next_drop_at"`
+	CreatedAt    time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// Interval returns the time between markdowns
+func (r *PriceDropRule) Interval() time.Duration {
+	return time.Duration(r.IntervalDays) * 24 * time.Hour
+}
+
+// NextPrice returns price marked down once, in whole cents and never below
+// the floor
+func (r *PriceDropRule) NextPrice(price decimal.Decimal) decimal.Decimal {
+	factor := decimal.NewFromInt(1).Sub(r.Percent.Div(decimal.NewFromInt(100)))
+	next := RoundDown.Round(price.Mul(factor))
+	if next.LessThan(r.FloorPrice) {
+		return r.FloorPrice
+	}
+	return next
+}
+
+// PriceDrop is one markdown made to a listing's buy-now price
+type PriceDrop struct {
+	ID        uuid.UUID       `json:"id" db:"id"`
+	AuctionID uuid.UUID       `json:"auction_id" db:"auction_id"`
+	OldPrice  decimal.Decimal `json:"old_price" db:"old_price"`
+	NewPrice  decimal.Decimal `json:"new_price" db:"new_price"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+type SetPriceDropRuleRequest struct {
+	Percent      string `json:"percent" validate:"required,numeric"`
+	IntervalDays int    `json:"interval_days" validate:"required,min=1,max=30"`
+	FloorPrice   string `json:"floor_price" validate:"required,numeric"`
+}
+
+// PriceHistory is a listing's buy-now price and the markdowns that led to
+// it, oldest first. The rule is only shown to the seller.
+type PriceHistory struct {
+	BuyNowPrice *decimal.Decimal `json:"buy_now_price,omitempty"`
+	Drops       []PriceDrop      `json:"drops"`
+	Rule        *PriceDropRule   `json:"rule,omitempty"`
+}
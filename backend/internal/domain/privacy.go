@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DataExportStatus string
+
+const (
+	DataExportPending    DataExportStatus = "pending"
+	DataExportProcessing DataExportStatus = "processing"
+	DataExportCompleted  DataExportStatus = "completed"
+	DataExportFailed     DataExportStatus = "failed"
+	DataExportExpired    DataExportStatus = "expired"
+)
+
+// DataExport is a takeout of everything the user has stored with us. It is
+// assembled in the background into a ZIP archive, which can be downloaded
+// through a presigned link until ExpiresAt.
+type DataExport struct {
+	ID          uuid.UUID        `json:"id" db:"id"`
+	UserID      uuid.UUID        `json:"user_id" db:"user_id"`
+	Status      DataExportStatus `json:"status" db:"status"`
+	ObjectName  *string          `json:"-" db:"object_name"`
+	SizeBytes   *int64           `json:"size_bytes,omitempty" db:"size_bytes"`
+	LeaseUntil  *time.Time       `json:"-" db:"lease_until"`
+	StartedAt   *time.Time       `json:"started_at,omitempty" db:"started_at"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty" db:"completed_at"`
+	ExpiresAt   *time.Time       `json:"expires_at,omitempty" db:"expires_at"`
+	CreatedAt   time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at" db:"updated_at"`
+
+	// Presigned while the archive can still be downloaded
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+// Downloadable reports whether the export's archive is ready and its link
+// hasn't expired
+func (e *DataExport) Downloadable(now time.Time) bool {
+	return e.Status == DataExportCompleted && e.ObjectName != nil && e.ExpiresAt != nil && now.Before(*e.ExpiresAt)
+}
+
+// ExportedProfile is the account as stored, including the contact details
+// the API otherwise keeps to itself
+type ExportedProfile struct {
+	*User
+	Phone   *string `json:"phone"`
+	Address *string `json:"address"`
+}
+
+// ExportedRatings holds the ratings the user received and those they gave
+type ExportedRatings struct {
+	Received []Rating `json:"received"`
+	Given    []Rating `json:"given"`
+}
+
+// AccountObligations counts what still ties an account to other users. An
+// account can only be deleted once they are all settled.
+type AccountObligations struct {
+	ActiveListings int `json:"active_listings"`
+	LeadingBids    int `json:"leading_bids"`
+	OpenOrders     int `json:"open_orders"`
+	OpenDisputes   int `json:"open_disputes"`
+	OpenPayouts    int `json:"open_payouts"`
+}
+
+// Settled reports whether nothing is left open
+func (o *AccountObligations) Settled() bool {
+	return o.ActiveListings == 0 && o.LeadingBids == 0 && o.OpenOrders == 0 && o.OpenDisputes == 0 && o.OpenPayouts == 0
+}
+
+// UserPurgeResult reports how many deleted accounts a purge anonymized
+type UserPurgeResult struct {
+	Purged int `json:"purged"`
+}
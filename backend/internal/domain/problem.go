@@ -0,0 +1,62 @@
+package domain
+
+import "encoding/json"
+
+// ProblemContentType is the media type of RFC 9457 problem details
+const ProblemContentType = "application/problem+json"
+
+// Problem is an error response in the RFC 9457 problem details format, sent
+// instead of the usual envelope to clients that accept it. Code is the same
+// error code as in APIError.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+	// Errors lists the invalid fields of a validation error
+	Errors []ProblemFieldError `json:"errors,omitempty"`
+
+	// Extensions are further members, such as retry_after, sent alongside
+	// the standard ones
+	Extensions map[string]string `json:"-"`
+}
+
+// ProblemFieldError is one invalid field. Pointer is a JSON Pointer into the
+// request body, e.g. "#/title".
+type ProblemFieldError struct {
+	Pointer string `json:"pointer"`
+	Detail  string `json:"detail"`
+}
+
+// problemMembers are the member names extensions may not take
+var problemMembers = map[string]bool{
+	"type": true, "title": true, "status": true, "detail": true,
+	"instance": true, "code": true, "errors": true,
+}
+
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type problem Problem
+	standard, err := json.Marshal(problem(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return standard, err
+	}
+
+	members := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(standard, &members); err != nil {
+		return nil, err
+	}
+	for key, value := range p.Extensions {
+		if problemMembers[key] {
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		members[key] = encoded
+	}
+
+	return json.Marshal(members)
+}
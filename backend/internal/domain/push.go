@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PushSubscription is a browser a user subscribed to web push. The keys are
+// what its push messages are encrypted for.
+type PushSubscription struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	Endpoint  string     `json:"endpoint" db:"endpoint"`
+	P256dh    string     `json:"-" db:"p256dh"`
+	Auth      string     `json:"-" db:"auth"`
+	UserAgent *string    `json:"user_agent,omitempty" db:"user_agent"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Expired reports whether the browser said the subscription would have
+// lapsed by now
+func (s *PushSubscription) Expired(now time.Time) bool {
+	return s.ExpiresAt != nil && !s.ExpiresAt.After(now)
+}
+
+type PushSubscriptionKeys struct {
+	P256dh string `json:"p256dh" validate:"required,max=200"`
+	Auth   string `json:"auth" validate:"required,max=100"`
+}
+
+// CreatePushSubscriptionRequest carries the browser's PushSubscription, with
+// its expirationTime in milliseconds since the epoch as browsers give it
+type CreatePushSubscriptionRequest struct {
+	Endpoint       string               `json:"endpoint" validate:"required,url,max=2000"`
+	ExpirationTime *int64               `json:"expiration_time,omitempty"`
+	Keys           PushSubscriptionKeys `json:"keys" validate:"required"`
+}
+
+// PushSubscriptionsResponse lists a user's subscriptions along with the
+// server key browsers subscribe with
+type PushSubscriptionsResponse struct {
+	PublicKey     string             `json:"public_key"`
+	Subscriptions []PushSubscription `json:"subscriptions"`
+}
+
+// PushPayload is what the service worker receives and shows. Pushes with the
+// same tag replace each other on screen.
+type PushPayload struct {
+	Type      NotificationType `json:"type"`
+	Title     string           `json:"title"`
+	Body      string           `json:"body,omitempty"`
+	URL       string           `json:"url"`
+	Tag       string           `json:"tag"`
+	AuctionID *uuid.UUID       `json:"auction_id,omitempty"`
+}
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuietHoursCritical are the notifications that reach a user during quiet
+// hours when they let critical ones through: each needs them to act, such
+// as paying for an item they won, before the morning may be too late
+var QuietHoursCritical = map[NotificationType]bool{
+	NotificationAuctionWon:        true,
+	NotificationSecondChanceOffer: true,
+}
+
+// QuietHours holds a user's email and push notifications between Start and
+// End, wall clock times in their Timezone, sending what was held as one
+// digest when the quiet hours end. In-app notifications are unaffected.
+type QuietHours struct {
+	UserID        uuid.UUID `json:"-" db:"user_id"`
+	Enabled       bool      `json:"enabled" db:"enabled"`
+	Start         string    `json:"start" db:"start_time"`
+	End           string    `json:"end" db:"end_time"`
+	Timezone      string    `json:"timezone" db:"timezone"`
+	AllowCritical bool      `json:"allow_critical" db:"allow_critical"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultQuietHours is what users who haven't set quiet hours get: off, but
+// filled in with a sensible night for them to switch on
+func DefaultQuietHours(userID uuid.UUID) *QuietHours {
+	return &QuietHours{
+		UserID:        userID,
+		Start:         "22:00",
+		End:           "07:00",
+		Timezone:      "UTC",
+		AllowCritical: true,
+	}
+}
+
+// ParseClock parses an "HH:MM" wall clock time into minutes after midnight
+func ParseClock(clock string) (int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not an HH:MM time", ErrInvalidQuietHours, clock)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ActiveAt reports whether notifications are being held at the given time.
+// Quiet hours that start later in the day than they end run overnight.
+func (q *QuietHours) ActiveAt(t time.Time) bool {
+	if !q.Enabled {
+		return false
+	}
+
+	start, end, local, err := q.resolve(t)
+	if err != nil {
+		return false
+	}
+
+	now := local.Hour()*60 + local.Minute()
+	if start < end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+// EndAfter returns when the quiet hours next end after the given time, which
+// is when held notifications go out
+func (q *QuietHours) EndAfter(t time.Time) time.Time {
+	_, end, local, err := q.resolve(t)
+	if err != nil {
+		return t
+	}
+
+	ends := time.Date(local.Year(), local.Month(), local.Day(), end/60, end%60, 0, 0, local.Location())
+	if !ends.After(local) {
+		ends = time.Date(local.Year(), local.Month(), local.Day()+1, end/60, end%60, 0, 0, local.Location())
+	}
+	return ends
+}
+
+// Holds reports whether a notification of the given type is held at the
+// given time rather than sent
+func (q *QuietHours) Holds(notificationType NotificationType, t time.Time) bool {
+	if q.AllowCritical && QuietHoursCritical[notificationType] {
+		return false
+	}
+	return q.ActiveAt(t)
+}
+
+func (q *QuietHours) resolve(t time.Time) (start, end int, local time.Time, err error) {
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return 0, 0, t, fmt.Errorf("%w: %q", ErrInvalidTimezone, q.Timezone)
+	}
+	if start, err = ParseClock(q.Start); err != nil {
+		return 0, 0, t, err
+	}
+	if end, err = ParseClock(q.End); err != nil {
+		return 0, 0, t, err
+	}
+	if start == end {
+		return 0, 0, t, fmt.Errorf("%w: start and end are the same", ErrInvalidQuietHours)
+	}
+	return start, end, t.In(loc), nil
+}
+
+// Validate checks the times and timezone, so the quiet hours can be saved
+func (q *QuietHours) Validate() error {
+	_, _, _, err := q.resolve(time.Now())
+	return err
+}
+
+type SetQuietHoursRequest struct {
+	Enabled       bool   `json:"enabled"`
+	Start         string `json:"start" validate:"required"`
+	End           string `json:"end" validate:"required"`
+	Timezone      string `json:"timezone" validate:"required,max=64"`
+	AllowCritical bool   `json:"allow_critical"`
+}
+
+// HeldNotification is an email held during quiet hours until ReleaseAt,
+// when it goes out in the user's digest
+type HeldNotification struct {
+	ID        uuid.UUID        `json:"id" db:"id"`
+	UserID    uuid.UUID        `json:"user_id" db:"user_id"`
+	Type      NotificationType `json:"type" db:"notification_type"`
+	Subject   string           `json:"subject" db:"subject"`
+	Body      string           `json:"body" db:"body"`
+	ReleaseAt time.Time        `json:"release_at" db:"release_at"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	DefaultRecommendationLimit = 12
+	// MaxRecommendationLimit is also how many candidates are kept per
+	// auction or user
+	MaxRecommendationLimit = 50
+)
+
+// SimilarAuctionQuery describes the auction to find similar ones for
+type SimilarAuctionQuery struct {
+	AuctionID  uuid.UUID
+	CategoryID *uuid.UUID
+	Condition  *ItemCondition
+	CertGrader *Grader
+	Price      decimal.Decimal
+	// Title words OR'd together as a text search query; empty when the
+	// title has none worth matching
+	TitleQuery string
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "strings"
+
+// ResidencyRegions lists the countries, as ISO 3166-1 alpha-2 codes, that
+// make up each data residency region
+var ResidencyRegions = map[string][]string{
+	// The EU and the rest of the EEA
+	"eu": {
+		"AT", "BE", "BG", "HR", "CY", "CZ", "DK", "EE", "FI", "FR", "DE", "GR", "HU", "IE",
+		"IT", "LV", "LT", "LU", "MT", "NL", "PL", "PT", "RO", "SK", "SI", "ES", "SE",
+		"IS", "LI", "NO",
+	},
+	"uk": {"GB"},
+	"us": {"US"},
+}
+
+// InResidencyRegion reports whether country is in region. An unknown
+// country is never in a region.
+func InResidencyRegion(region, country string) bool {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	for _, c := range ResidencyRegions[region] {
+		if c == country {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact strips the message contents, the fields kept in the data region,
+// leaving who wrote when and what moderation flagged
+func (t *ConversationTranscript) Redact() {
+	for i := range t.Messages {
+		m := &t.Messages[i].Message
+		m.Content = ""
+		m.Ciphertext = nil
+		m.Nonce = nil
+		m.KeyEnvelopes = nil
+	}
+	t.Redacted = true
+}
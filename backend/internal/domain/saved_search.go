@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// MaxSavedSearches is how many searches one user can save
+const MaxSavedSearches = 25
+
+// SavedSearch is a search a user saved so new listings matching it come in
+// their digest
+type SavedSearch struct {
+	ID        uuid.UUID        `json:"id" db:"id"`
+	UserID    uuid.UUID        `json:"-" db:"user_id"`
+	Name      string           `json:"name" db:"name"`
+	Query     SavedSearchQuery `json:"query" db:"query"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+}
+
+// SavedSearchQuery is the part of the auction list filters a search keeps
+type SavedSearchQuery struct {
+	Search       *string          `json:"search,omitempty" validate:"omitempty,max=200"`
+	CategoryID   *uuid.UUID       `json:"category_id,omitempty"`
+	MinPrice     *decimal.Decimal `json:"min_price,omitempty"`
+	MaxPrice     *decimal.Decimal `json:"max_price,omitempty"`
+	Condition    *ItemCondition   `json:"condition,omitempty" validate:"omitempty,oneof=new like_new good fair poor"`
+	HasBuyNow    *bool            `json:"has_buy_now,omitempty"`
+	FreeShipping bool             `json:"free_shipping,omitempty"`
+}
+
+// ListParams lists the active public auctions matching the search, as the
+// user would see them
+func (q *SavedSearchQuery) ListParams(viewerID uuid.UUID) *AuctionListParams {
+	status := AuctionStatusActive
+	return &AuctionListParams{
+		Status:          &status,
+		Search:          q.Search,
+		CategoryID:      q.CategoryID,
+		MinPrice:        q.MinPrice,
+		MaxPrice:        q.MaxPrice,
+		Condition:       q.Condition,
+		HasBuyNow:       q.HasBuyNow,
+		FreeShipping:    q.FreeShipping,
+		SortBy:          "newest",
+		HideVacationing: true,
+		HidePrivate:     true,
+		ViewerID:        &viewerID,
+	}
+}
+
+type CreateSavedSearchRequest struct {
+	Name  string           `json:"name" validate:"required,min=1,max=100"`
+	Query SavedSearchQuery `json:"query"`
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ListingShare is a user's short link to a listing. Visits through it and
+// the bidders it brings in are shown to the seller.
+type ListingShare struct {
+	ID        uuid.UUID `json:"-"`
+	Code      string    `json:"code"`
+	AuctionID uuid.UUID `json:"auction_id"`
+	SharerID  uuid.UUID `json:"sharer_id"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListingShareStats is how one share of a listing performed. A bidder
+// converts for the share they last came through before their first bid.
+type ListingShareStats struct {
+	Code           string    `json:"code"`
+	SharerID       uuid.UUID `json:"sharer_id"`
+	SharerUsername string    `json:"sharer_username"`
+	Clicks         int       `json:"clicks"`
+	Conversions    int       `json:"conversions"`
+	// Whether the winner came through this share
+	LedToSale bool      `json:"led_to_sale"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type DeepLinkType string
+
+const (
+	DeepLinkAuction DeepLinkType = "auction"
+)
+
+// DeepLink is where a short link leads, for apps to open the right screen
+type DeepLink struct {
+	Type      DeepLinkType `json:"type"`
+	AuctionID uuid.UUID    `json:"auction_id"`
+	URL       string       `json:"url"`
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type SnipeStatus string
+
+const (
+	SnipeStatusScheduled SnipeStatus = "scheduled"
+	SnipeStatusExecuting SnipeStatus = "executing"
+	SnipeStatusPlaced    SnipeStatus = "placed"
+	SnipeStatusFailed    SnipeStatus = "failed"
+	SnipeStatusCancelled SnipeStatus = "cancelled"
+)
+
+// Snipe is a bid the platform places on a watcher's behalf shortly before
+// the auction ends
+type Snipe struct {
+	ID            uuid.UUID       `json:"id" db:"id"`
+	AuctionID     uuid.UUID       `json:"auction_id" db:"auction_id"`
+	UserID        uuid.UUID       `json:"user_id" db:"user_id"`
+	MaxAmount     decimal.Decimal `json:"max_amount" db:"max_amount"`
+	LeadSeconds   int             `json:"lead_seconds" db:"lead_seconds"`
+	Status        SnipeStatus     `json:"status" db:"status"`
+	BidID         *uuid.UUID      `json:"bid_id,omitempty" db:"bid_id"`
+	FailureReason *string         `json:"failure_reason,omitempty" db:"failure_reason"`
+	ExecutedAt    *time.Time      `json:"executed_at,omitempty" db:"executed_at"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at" db:"updated_at"`
+
+	// Joined fields
+	Auction *Auction `json:"auction,omitempty"`
+}
+
+// Request/Response DTOs
+type ScheduleSnipeRequest struct {
+	MaxAmount   string `json:"max_amount" validate:"required,numeric,gt=0"`
+	LeadSeconds *int   `json:"lead_seconds" validate:"omitempty,min=2,max=60"`
+}
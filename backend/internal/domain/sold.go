@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// SoldListing is a past sale, for buyers researching prices and sellers
+// checking comparable listings. Buyers aren't shown.
+type SoldListing struct {
+	AuctionID     uuid.UUID       `json:"auction_id"`
+	Title         string          `json:"title"`
+	CategoryID    *uuid.UUID      `json:"category_id,omitempty"`
+	Condition     *ItemCondition  `json:"condition,omitempty"`
+	CertGrader    *Grader         `json:"cert_grader,omitempty"`
+	CertGrade     *string         `json:"cert_grade,omitempty"`
+	StartingPrice decimal.Decimal `json:"starting_price"`
+	SoldPrice     decimal.Decimal `json:"sold_price"`
+	BidCount      int             `json:"bid_count"`
+	SoldAt        time.Time       `json:"sold_at"`
+	Image         *AuctionImage   `json:"image,omitempty"`
+}
+
+// SoldPriceStats summarizes the sale prices of every sale matching the
+// filters, not just the current page
+type SoldPriceStats struct {
+	Count   int              `json:"count"`
+	Average *decimal.Decimal `json:"average,omitempty"`
+	Median  *decimal.Decimal `json:"median,omitempty"`
+	Min     *decimal.Decimal `json:"min,omitempty"`
+	Max     *decimal.Decimal `json:"max,omitempty"`
+}
+
+type SoldListingParams struct {
+	CategoryID *uuid.UUID       `json:"category_id"`
+	Search     *string          `json:"search"`
+	Condition  *ItemCondition   `json:"condition"`
+	SoldFrom   *time.Time       `json:"sold_from"`
+	SoldTo     *time.Time       `json:"sold_to"` // exclusive
+	MinPrice   *decimal.Decimal `json:"min_price"`
+	MaxPrice   *decimal.Decimal `json:"max_price"`
+	SortBy     string           `json:"sort_by"` // recent, price_low, price_high
+	Page       int              `json:"page"`
+	Limit      int              `json:"limit"`
+}
+
+type SoldListingResponse struct {
+	Sales      []SoldListing  `json:"sales"`
+	Stats      SoldPriceStats `json:"stats"`
+	TotalCount int            `json:"total_count"`
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// AuctionStats is the seller-facing analytics for a single auction
+type AuctionStats struct {
+	AuctionID     uuid.UUID    `json:"auction_id"`
+	ViewsCount    int          `json:"views_count"`
+	WatchCount    int          `json:"watch_count"`
+	BidCount      int          `json:"bid_count"`
+	UniqueBidders int          `json:"unique_bidders"`
+	ViewsByDay    []DailyCount `json:"views_by_day"`
+	BidHistory    []BidPoint   `json:"bid_history"`
+
+	// Shares of the listing, most visited first
+	Shares []ListingShareStats `json:"shares"`
+}
+
+type DailyCount struct {
+	Date  time.Time `json:"date"`
+	Count int       `json:"count"`
+}
+
+// BidPoint is one step of the price curve
+type BidPoint struct {
+	Amount    decimal.Decimal `json:"amount"`
+	CreatedAt time.Time       `json:"created_at"`
+}
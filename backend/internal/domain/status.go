@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatusComponent is a part of the service the status page reports on
+type StatusComponent string
+
+const (
+	StatusComponentAPI          StatusComponent = "api"
+	StatusComponentBidding      StatusComponent = "bidding"
+	StatusComponentWebSocket    StatusComponent = "websocket"
+	StatusComponentPayments     StatusComponent = "payments"
+	StatusComponentImageUploads StatusComponent = "image_uploads"
+)
+
+// StatusComponents lists every component, in the order the status page
+// shows them
+var StatusComponents = []StatusComponent{
+	StatusComponentAPI,
+	StatusComponentBidding,
+	StatusComponentWebSocket,
+	StatusComponentPayments,
+	StatusComponentImageUploads,
+}
+
+// ComponentHealth is how a component is doing. Later values are worse.
+type ComponentHealth string
+
+const (
+	ComponentOperational ComponentHealth = "operational"
+	ComponentDegraded    ComponentHealth = "degraded"
+	ComponentOutage      ComponentHealth = "outage"
+)
+
+var componentHealthRank = map[ComponentHealth]int{
+	ComponentOperational: 0,
+	ComponentDegraded:    1,
+	ComponentOutage:      2,
+}
+
+// Worse returns whichever of two healths is worse
+func (h ComponentHealth) Worse(other ComponentHealth) ComponentHealth {
+	if componentHealthRank[other] > componentHealthRank[h] {
+		return other
+	}
+	return h
+}
+
+type IncidentStatus string
+
+const (
+	IncidentInvestigating IncidentStatus = "investigating"
+	IncidentIdentified    IncidentStatus = "identified"
+	IncidentMonitoring    IncidentStatus = "monitoring"
+	IncidentResolved      IncidentStatus = "resolved"
+)
+
+// IncidentImpact is how badly an unresolved incident affects its components
+type IncidentImpact string
+
+const (
+	IncidentImpactMinor IncidentImpact = "minor"
+	IncidentImpactMajor IncidentImpact = "major"
+)
+
+// Health is what an unresolved incident of this impact makes its components
+func (i IncidentImpact) Health() ComponentHealth {
+	if i == IncidentImpactMajor {
+		return ComponentOutage
+	}
+	return ComponentDegraded
+}
+
+// StatusIncidentHistory is how long resolved incidents stay on the status page
+const StatusIncidentHistory = 7 * 24 * time.Hour
+
+// StatusIncident is an incident admins post to the status page
+type StatusIncident struct {
+	ID         uuid.UUID         `json:"id" db:"id"`
+	Title      string            `json:"title" db:"title"`
+	Message    string            `json:"message" db:"message"`
+	Status     IncidentStatus    `json:"status" db:"status"`
+	Impact     IncidentImpact    `json:"impact" db:"impact"`
+	Components []StatusComponent `json:"components" db:"components"`
+	CreatedBy  *uuid.UUID        `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt  time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at" db:"updated_at"`
+	ResolvedAt *time.Time        `json:"resolved_at,omitempty" db:"resolved_at"`
+}
+
+// IsResolved reports whether the incident is over
+func (i *StatusIncident) IsResolved() bool {
+	return i.Status == IncidentResolved
+}
+
+type CreateStatusIncidentRequest struct {
+	Title      string   `json:"title" validate:"required,min=3,max=200"`
+	Message    string   `json:"message" validate:"max=2000"`
+	Status     string   `json:"status" validate:"omitempty,oneof=investigating identified monitoring resolved"`
+	Impact     string   `json:"impact" validate:"required,oneof=minor major"`
+	Components []string `json:"components" validate:"required,min=1,dive,oneof=api bidding websocket payments image_uploads"`
+}
+
+// UpdateStatusIncidentRequest posts an update; fields left out are kept
+type UpdateStatusIncidentRequest struct {
+	Title      *string  `json:"title" validate:"omitempty,min=3,max=200"`
+	Message    *string  `json:"message" validate:"omitempty,max=2000"`
+	Status     *string  `json:"status" validate:"omitempty,oneof=investigating identified monitoring resolved"`
+	Impact     *string  `json:"impact" validate:"omitempty,oneof=minor major"`
+	Components []string `json:"components" validate:"omitempty,min=1,dive,oneof=api bidding websocket payments image_uploads"`
+}
+
+// ComponentStatus is one component's line on the status page
+type ComponentStatus struct {
+	Name   StatusComponent `json:"name"`
+	Status ComponentHealth `json:"status"`
+	// Share of requests that failed with a server error over the window
+	ErrorRate float64 `json:"error_rate"`
+	Requests  int64   `json:"requests"`
+}
+
+// ServiceStatus is the body of GET /api/status
+type ServiceStatus struct {
+	// The worst of the components
+	Status     ComponentHealth   `json:"status"`
+	Components []ComponentStatus `json:"components"`
+	// Unresolved incidents and those resolved in the last week, newest first
+	Incidents []StatusIncident `json:"incidents"`
+	// How far back error rates look
+	ErrorRateWindowSeconds int       `json:"error_rate_window_seconds"`
+	UpdatedAt              time.Time `json:"updated_at"`
+}
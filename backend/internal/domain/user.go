@@ -24,12 +24,20 @@ type User struct {
 	Address                *string    `json:"-" db:"address"`
 	Role                   UserRole   `json:"role" db:"role"`
 	EmailVerified          bool       `json:"email_verified" db:"email_verified"`
-	EmailVerificationToken *string    `json:"-" db:"email_verification_token"`
+	EmailVerificationToken *string    `json:"-" db:"email_verification_token"` // selector + SHA-256 hash
 	PasswordResetToken     *string    `json:"-" db:"password_reset_token"`
 	PasswordResetExpires   *time.Time `json:"-" db:"password_reset_expires"`
 	IsBanned               bool       `json:"is_banned" db:"is_banned"`
+	MessageRetentionDays   *int       `json:"message_retention_days" db:"message_retention_days"`
+	SingleSession          bool       `json:"single_session" db:"single_session"`
+	TwoFactorEnabled       bool       `json:"two_factor_enabled" db:"two_factor_enabled"`
+	UsernameSetupRequired  bool       `json:"username_setup_required" db:"username_setup_required"` // generated at OAuth signup, until chosen
+	IsDeleted              bool       `json:"is_deleted" db:"is_deleted"`
+	DeletedAt              *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 	CreatedAt              time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt              time.Time  `json:"updated_at" db:"updated_at"`
+
+	Vacation VacationSettings `json:"vacation"`
 }
 
 type PublicUser struct {
@@ -38,35 +46,124 @@ type PublicUser struct {
 	AvatarURL *string   `json:"avatar_url"`
 	Bio       *string   `json:"bio"`
 	CreatedAt time.Time `json:"created_at"`
+
+	// Set while the user is away, so buyers can be shown a banner
+	Vacation *VacationNotice `json:"vacation,omitempty"`
+
+	// Set once the account is deleted; see HideIfDeleted
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// DeletedUsername is shown in place of a deleted user's name
+const DeletedUsername = "deleted user"
+
+// HideIfDeleted replaces a deleted user's profile with a placeholder. Only
+// the ID is kept, so their bids, ratings and messages still hang together.
+func (p *PublicUser) HideIfDeleted() {
+	if !p.Deleted {
+		return
+	}
+	p.Username = DeletedUsername
+	p.AvatarURL = nil
+	p.Bio = nil
+	p.Vacation = nil
 }
 
 func (u *User) ToPublic() *PublicUser {
-	return &PublicUser{
+	public := &PublicUser{
 		ID:        u.ID,
 		Username:  u.Username,
 		AvatarURL: u.AvatarURL,
 		Bio:       u.Bio,
 		CreatedAt: u.CreatedAt,
+		Vacation:  u.Vacation.Notice(time.Now()),
+		Deleted:   u.IsDeleted,
+	}
+	public.HideIfDeleted()
+	return public
+}
+
+// VacationListingMode is what happens to a seller's active listings while
+// they're away
+type VacationListingMode string
+
+const (
+	// VacationListingBanner keeps listings running with a vacation banner
+	VacationListingBanner VacationListingMode = "banner"
+	// VacationListingExtend pushes listings that would end during the
+	// vacation past the seller's return
+	VacationListingExtend VacationListingMode = "extend"
+	// VacationListingHide removes listings from search until the seller is back
+	VacationListingHide VacationListingMode = "hide"
+)
+
+// VacationSettings pauses a seller's shop between StartsAt and EndsAt
+type VacationSettings struct {
+	Enabled     bool                `json:"enabled" db:"vacation_mode"`
+	StartsAt    *time.Time          `json:"starts_at" db:"vacation_starts_at"`
+	EndsAt      *time.Time          `json:"ends_at" db:"vacation_ends_at"`
+	ListingMode VacationListingMode `json:"listing_mode" db:"vacation_listing_mode"`
+	BlockBids   bool                `json:"block_bids" db:"vacation_block_bids"`
+	AutoReply   *string             `json:"auto_reply" db:"vacation_auto_reply"`
+}
+
+// ActiveAt reports whether the seller is away at the given time
+func (v *VacationSettings) ActiveAt(t time.Time) bool {
+	return v.Enabled && v.StartsAt != nil && v.EndsAt != nil &&
+		!t.Before(*v.StartsAt) && t.Before(*v.EndsAt)
+}
+
+// Notice returns what buyers get to see of the vacation, or nil if the
+// seller isn't away at the given time
+func (v *VacationSettings) Notice(t time.Time) *VacationNotice {
+	if !v.ActiveAt(t) {
+		return nil
 	}
+	return &VacationNotice{EndsAt: *v.EndsAt, BidsBlocked: v.BlockBids}
+}
+
+type VacationNotice struct {
+	EndsAt      time.Time `json:"ends_at"`
+	BidsBlocked bool      `json:"bids_blocked"`
 }
 
+// OAuthLinkMethod records how a provider login was linked to its user
+type OAuthLinkMethod string
+
+const (
+	// OAuthLinkSignup: the provider login created the user
+	OAuthLinkSignup OAuthLinkMethod = "signup"
+	// OAuthLinkVerifiedEmail: matched to the user by an email the provider
+	// verified
+	OAuthLinkVerifiedEmail OAuthLinkMethod = "verified_email"
+	// OAuthLinkAccountSettings: linked by the signed-in user from settings
+	OAuthLinkAccountSettings OAuthLinkMethod = "account_settings"
+	// OAuthLinkLoginConfirmation: matched by an unverified email, then
+	// confirmed by the user signing in to the account
+	OAuthLinkLoginConfirmation OAuthLinkMethod = "login_confirmation"
+)
+
 type OAuthAccount struct {
-	ID             uuid.UUID  `json:"id" db:"id"`
-	UserID         uuid.UUID  `json:"user_id" db:"user_id"`
-	Provider       string     `json:"provider" db:"provider"`
-	ProviderUserID string     `json:"provider_user_id" db:"provider_user_id"`
-	AccessToken    *string    `json:"-" db:"access_token"`
-	RefreshToken   *string    `json:"-" db:"refresh_token"`
-	ExpiresAt      *time.Time `json:"-" db:"expires_at"`
-	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	ID                    uuid.UUID        `json:"id" db:"id"`
+	UserID                uuid.UUID        `json:"user_id" db:"user_id"`
+	Provider              string           `json:"provider" db:"provider"`
+	ProviderUserID        string           `json:"provider_user_id" db:"provider_user_id"`
+	AccessToken           *string          `json:"-" db:"access_token"`
+	RefreshToken          *string          `json:"-" db:"refresh_token"`
+	ExpiresAt             *time.Time       `json:"-" db:"expires_at"`
+	LinkedVia             *OAuthLinkMethod `json:"linked_via,omitempty" db:"linked_via"`
+	ProviderEmail         *string          `json:"provider_email,omitempty" db:"provider_email"`
+	ProviderEmailVerified *bool            `json:"provider_email_verified,omitempty" db:"provider_email_verified"`
+	CreatedAt             time.Time        `json:"created_at" db:"created_at"`
 }
 
 type RefreshToken struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	UserID    uuid.UUID `json:"user_id" db:"user_id"`
-	TokenHash string    `json:"-" db:"token_hash"`
-	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	DeviceID  *uuid.UUID `json:"device_id,omitempty" db:"device_id"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
 }
 
 // Request/Response DTOs
@@ -84,6 +181,17 @@ type LoginRequest struct {
 type AuthResponse struct {
 	User        *User  `json:"user"`
 	AccessToken string `json:"access_token"`
+
+	// Set instead of the above when the login must be confirmed with the
+	// code emailed to the user
+	TwoFactorRequired bool       `json:"two_factor_required,omitempty"`
+	ChallengeID       *uuid.UUID `json:"challenge_id,omitempty"`
+}
+
+// ChooseUsernameRequest completes an OAuth signup with a username of the
+// user's choice
+type ChooseUsernameRequest struct {
+	Username string `json:"username" validate:"required,min=3,max=50,alphanum"`
 }
 
 type UpdateProfileRequest struct {
@@ -92,12 +200,91 @@ type UpdateProfileRequest struct {
 	Phone     *string `json:"phone" validate:"omitempty,max=20"`
 	Address   *string `json:"address" validate:"omitempty,max=500"`
 	AvatarURL *string `json:"avatar_url" validate:"omitempty,url,max=500"`
+
+	// Days to keep messages before they're deleted; 0 restores the platform default
+	MessageRetentionDays *int `json:"message_retention_days" validate:"omitempty,min=0,max=3650"`
+}
+
+// SetVacationRequest turns on vacation mode. StartsAt defaults to now.
+type SetVacationRequest struct {
+	StartsAt    *time.Time          `json:"starts_at"`
+	EndsAt      time.Time           `json:"ends_at" validate:"required"`
+	ListingMode VacationListingMode `json:"listing_mode" validate:"omitempty,oneof=banner extend hide"`
+	BlockBids   bool                `json:"block_bids"`
+	AutoReply   *string             `json:"auto_reply" validate:"omitempty,max=1000"`
+}
+
+type VacationResponse struct {
+	Vacation VacationSettings `json:"vacation"`
+	// Number of active listings whose end time was pushed past EndsAt
+	ExtendedAuctions int64 `json:"extended_auctions"`
 }
 
+// TokenKind names the single-use tokens emailed to users
+type TokenKind string
+
+const (
+	TokenEmailVerification TokenKind = "email_verification"
+	TokenPasswordReset     TokenKind = "password_reset"
+)
+
+// TokenSelectorLength is how much of an emailed token is used to look it up;
+// the remainder is compared in constant time
+const TokenSelectorLength = 16
+
 type ForgotPasswordRequest struct {
 	Email string `json:"email" validate:"required,email"`
 }
 
+// LoginClient describes where a login came from. Country is an ISO 3166
+// code supplied by the edge proxy and empty when unknown.
+type LoginClient struct {
+	IP      string
+	Country string
+	// DeviceToken is the random value of the client's device cookie
+	DeviceToken string
+	UserAgent   string
+}
+
+type SetSessionPolicyRequest struct {
+	SingleSession bool `json:"single_session"`
+}
+
+type SessionPolicyResponse struct {
+	SingleSession bool `json:"single_session"`
+	// Enforced is set when the user's role requires a single session
+	// regardless of their own choice
+	Enforced        bool  `json:"enforced"`
+	RevokedSessions int64 `json:"revoked_sessions"`
+}
+
+// ReauthRequest confirms a sensitive account change. Users with a password
+// give it; OAuth-only users must have signed in within the last few minutes.
+type ReauthRequest struct {
+	CurrentPassword string `json:"current_password"`
+}
+
+// SetPasswordRequest adds a password to an account created through OAuth
+type SetPasswordRequest struct {
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// SignInMethods lists how a user can sign in and which providers could
+// still be linked
+type SignInMethods struct {
+	HasPassword bool           `json:"has_password"`
+	Linked      []OAuthAccount `json:"linked"`
+	Available   []string       `json:"available"`
+}
+
+type OAuthLinkResponse struct {
+	URL string `json:"url"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
 type ResetPasswordRequest struct {
 	Token    string `json:"token" validate:"required"`
 	Password string `json:"password" validate:"required,min=8,max=72"`
@@ -106,3 +293,23 @@ type ResetPasswordRequest struct {
 type VerifyEmailRequest struct {
 	Token string `json:"token" validate:"required"`
 }
+
+// MergeUsersRequest folds a duplicate account into the primary one named in
+// the URL. With DryRun set nothing is changed and only the report is built.
+type MergeUsersRequest struct {
+	DuplicateID uuid.UUID `json:"duplicate_id" validate:"required"`
+	DryRun      bool      `json:"dry_run"`
+	Reason      string    `json:"reason" validate:"required,min=3,max=1000"`
+}
+
+// UserMergeReport counts, per kind of record, the rows moved from the
+// duplicate to the primary account. Dropped counts rows the primary already
+// had an equivalent of, such as a watchlist entry for the same auction,
+// which are deleted rather than moved.
+type UserMergeReport struct {
+	PrimaryID   uuid.UUID        `json:"primary_id"`
+	DuplicateID uuid.UUID        `json:"duplicate_id"`
+	DryRun      bool             `json:"dry_run"`
+	Moved       map[string]int64 `json:"moved"`
+	Dropped     map[string]int64 `json:"dropped"`
+}
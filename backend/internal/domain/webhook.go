@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type WebhookEvent string
+
+const (
+	WebhookEventAuctionEnded WebhookEvent = "auction.ended"
+	WebhookEventBidPlaced    WebhookEvent = "bid.placed"
+	WebhookEventOrderPaid    WebhookEvent = "order.paid"
+)
+
+// Webhook is a URL notified of events. A user's webhook receives events
+// about their own auctions and orders; a global one receives every event.
+type Webhook struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	URL       string    `json:"url" db:"url"`
+	Secret    string    `json:"-" db:"secret"`
+	Events    []string  `json:"events" db:"events"`
+	Global    bool      `json:"global" db:"global"`
+	Active    bool      `json:"active" db:"active"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Subscribes reports whether the webhook wants the event
+func (w *Webhook) Subscribes(event WebhookEvent) bool {
+	for _, e := range w.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is one event sent, or still to be sent, to a webhook
+type WebhookDelivery struct {
+	ID             uuid.UUID             `json:"id" db:"id"`
+	WebhookID      uuid.UUID             `json:"webhook_id" db:"webhook_id"`
+	Event          WebhookEvent          `json:"event" db:"event"`
+	Payload        json.RawMessage       `json:"payload" db:"payload"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	LastStatusCode *int                  `json:"last_status_code,omitempty" db:"last_status_code"`
+	LastError      *string               `json:"last_error,omitempty" db:"last_error"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at" db:"updated_at"`
+
+	// Joined fields, for sending
+	Webhook *Webhook `json:"-"`
+}
+
+// WebhookPayload is the body of every delivery
+type WebhookPayload struct {
+	ID        uuid.UUID    `json:"id"`
+	Event     WebhookEvent `json:"event"`
+	CreatedAt time.Time    `json:"created_at"`
+	Data      interface{}  `json:"data"`
+}
+
+type WebhookAuctionEndedData struct {
+	AuctionID  uuid.UUID       `json:"auction_id"`
+	SellerID   uuid.UUID       `json:"seller_id"`
+	Title      string          `json:"title"`
+	Status     AuctionStatus   `json:"status"`
+	WinnerID   *uuid.UUID      `json:"winner_id,omitempty"`
+	FinalPrice decimal.Decimal `json:"final_price"`
+	EndedAt    time.Time       `json:"ended_at"`
+}
+
+type WebhookBidPlacedData struct {
+	BidID     uuid.UUID       `json:"bid_id"`
+	AuctionID uuid.UUID       `json:"auction_id"`
+	Amount    decimal.Decimal `json:"amount"`
+	BidCount  int             `json:"bid_count"`
+	PlacedAt  time.Time       `json:"placed_at"`
+}
+
+type WebhookOrderPaidData struct {
+	OrderID   uuid.UUID       `json:"order_id"`
+	AuctionID uuid.UUID       `json:"auction_id"`
+	BuyerID   uuid.UUID       `json:"buyer_id"`
+	SellerID  uuid.UUID       `json:"seller_id"`
+	Amount    decimal.Decimal `json:"amount"`
+	PaidAt    *time.Time      `json:"paid_at"`
+}
+
+// Request/Response DTOs
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" validate:"required,url,max=2000"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=auction.ended bid.placed order.paid"`
+	// Global webhooks receive every user's events; admins only
+	Global bool `json:"global"`
+}
+
+type UpdateWebhookRequest struct {
+	URL    *string  `json:"url" validate:"omitempty,url,max=2000"`
+	Events []string `json:"events" validate:"omitempty,min=1,dive,oneof=auction.ended bid.placed order.paid"`
+	Active *bool    `json:"active"`
+}
+
+// WebhookSecretResponse carries the signing secret, which is only shown when
+// a webhook is created or its secret rotated
+type WebhookSecretResponse struct {
+	*Webhook
+	Secret string `json:"secret"`
+}
+
+type WebhookDeliveryListParams struct {
+	Status *WebhookDeliveryStatus `json:"status"`
+	Page   int                    `json:"page"`
+	Limit  int                    `json:"limit"`
+}
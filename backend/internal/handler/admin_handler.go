@@ -1,37 +1,49 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/middleware"
 	"github.com/auction-cards/backend/internal/repository"
 	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
 )
 
 type AdminHandler struct {
-	userService    *service.UserService
-	auctionService *service.AuctionService
-	categoryRepo   repository.CategoryRepository
-	reportRepo     repository.ReportRepository
-	auctionRepo    repository.AuctionRepository
-	bidRepo        repository.BidRepository
+	userService         *service.UserService
+	auctionService      *service.AuctionService
+	adminService        *service.AdminService
+	notificationService *service.NotificationService
+	originService       *service.OriginService
+	categoryRepo        repository.CategoryRepository
+	reportRepo          repository.ReportRepository
+	auctionRepo         repository.AuctionRepository
+	bidRepo             repository.BidRepository
 }
 
 func NewAdminHandler(
 	userService *service.UserService,
 	auctionService *service.AuctionService,
+	adminService *service.AdminService,
+	notificationService *service.NotificationService,
+	originService *service.OriginService,
 	categoryRepo repository.CategoryRepository,
 	reportRepo repository.ReportRepository,
 	auctionRepo repository.AuctionRepository,
 	bidRepo repository.BidRepository,
 ) *AdminHandler {
 	return &AdminHandler{
-		userService:    userService,
-		auctionService: auctionService,
-		categoryRepo:   categoryRepo,
-		reportRepo:     reportRepo,
-		auctionRepo:    auctionRepo,
-		bidRepo:        bidRepo,
+		userService:         userService,
+		auctionService:      auctionService,
+		adminService:        adminService,
+		notificationService: notificationService,
+		originService:       originService,
+		categoryRepo:        categoryRepo,
+		reportRepo:          reportRepo,
+		auctionRepo:         auctionRepo,
+		bidRepo:             bidRepo,
 	}
 }
 
@@ -115,6 +127,36 @@ func (h *AdminHandler) BanUser(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// MergeUsers folds the duplicate account in the body into the account in
+// the URL, or with dry_run set only reports what would move
+func (h *AdminHandler) MergeUsers(w http.ResponseWriter, r *http.Request) {
+	userID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid user ID")
+		return
+	}
+
+	var req domain.MergeUsersRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	adminID := getUserID(r)
+	report, err := h.adminService.MergeUsers(r.Context(), adminID, userID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
 func (h *AdminHandler) ListAuctions(w http.ResponseWriter, r *http.Request) {
 	params := &domain.AuctionListParams{
 		Page:   getQueryParamInt(r, "page", 1),
@@ -129,7 +171,12 @@ func (h *AdminHandler) ListAuctions(w http.ResponseWriter, r *http.Request) {
 
 	params.Search = getQueryParamString(r, "search")
 
-	result, err := h.auctionService.List(r.Context(), params)
+	if certStatus := r.URL.Query().Get("cert_status"); certStatus != "" {
+		s := domain.CertificationStatus(certStatus)
+		params.CertStatus = &s
+	}
+
+	result, err := h.auctionService.List(r.Context(), params, nil)
 	if err != nil {
 		handleError(w, err)
 		return
@@ -143,6 +190,24 @@ func (h *AdminHandler) ListAuctions(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// VerifyCertification checks an auction's certification with the grader
+// again, e.g. after the grader was unreachable
+func (h *AdminHandler) VerifyCertification(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	cert, err := h.auctionService.VerifyCertification(r.Context(), auctionID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, cert)
+}
+
 func (h *AdminHandler) UpdateAuctionStatus(w http.ResponseWriter, r *http.Request) {
 	auctionID, err := getURLParamUUID(r, "id")
 	if err != nil {
@@ -169,6 +234,104 @@ func (h *AdminHandler) UpdateAuctionStatus(w http.ResponseWriter, r *http.Reques
 	})
 }
 
+func (h *AdminHandler) ForceEndAuction(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	var req domain.ForceEndAuctionRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	adminID := getUserID(r)
+	auction, err := h.adminService.ForceEndAuction(r.Context(), adminID, auctionID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, auction)
+}
+
+// GetConversationTranscript handles POST /api/admin/conversations/{id}/transcript
+func (h *AdminHandler) GetConversationTranscript(w http.ResponseWriter, r *http.Request) {
+	conversationID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid conversation ID")
+		return
+	}
+
+	var req domain.TranscriptAccessRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	adminID := getUserID(r)
+	transcript, err := h.adminService.GetConversationTranscript(r.Context(), adminID, conversationID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if middleware.IsCrossRegion(r.Context()) {
+		transcript.Redact()
+	}
+
+	respondJSON(w, http.StatusOK, transcript)
+}
+
+// Audit log
+
+func (h *AdminHandler) ListAuditLogs(w http.ResponseWriter, r *http.Request) {
+	params := &domain.AuditLogListParams{
+		ActorID:    getQueryParamUUID(r, "actor_id"),
+		Action:     getQueryParamString(r, "action"),
+		EntityType: getQueryParamString(r, "entity_type"),
+		EntityID:   getQueryParamUUID(r, "entity_id"),
+		Page:       getQueryParamInt(r, "page", 1),
+		Limit:      getQueryParamInt(r, "limit", 20),
+	}
+
+	entries, totalCount, err := h.adminService.ListAuditLogs(r.Context(), params)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	totalPages := (totalCount + params.Limit - 1) / params.Limit
+
+	respondJSONWithMeta(w, http.StatusOK, entries, &domain.APIMeta{
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalCount: totalCount,
+		TotalPages: totalPages,
+	})
+}
+
+func (h *AdminHandler) GetNotificationQueueStats(w http.ResponseWriter, r *http.Request) {
+	stats := h.notificationService.QueueStats()
+	if stats == nil {
+		respondError(w, http.StatusNotFound, "NOT_FOUND", "Notification queue is not enabled")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, stats)
+}
+
 // Category management
 
 func (h *AdminHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
@@ -259,6 +422,198 @@ func (h *AdminHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (h *AdminHandler) ListCategoryTranslations(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid category ID")
+		return
+	}
+
+	if _, err := h.categoryRepo.GetByID(r.Context(), categoryID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	translations, err := h.categoryRepo.ListTranslations(r.Context(), categoryID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, translations)
+}
+
+// SetCategoryTranslation adds or replaces a category's name and description
+// in one language
+func (h *AdminHandler) SetCategoryTranslation(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid category ID")
+		return
+	}
+
+	lang, ok := translationLanguage(chi.URLParam(r, "lang"))
+	if !ok {
+		respondError(w, http.StatusBadRequest, "INVALID_LANGUAGE", "Language must be a BCP 47 tag other than "+domain.DefaultLanguage+", e.g. de or pt-BR")
+		return
+	}
+
+	var req domain.SetCategoryTranslationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	if _, err := h.categoryRepo.GetByID(r.Context(), categoryID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	translation := &domain.CategoryTranslation{
+		CategoryID:  categoryID,
+		Language:    lang,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if err := h.categoryRepo.SetTranslation(r.Context(), translation); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, translation)
+}
+
+func (h *AdminHandler) DeleteCategoryTranslation(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid category ID")
+		return
+	}
+
+	lang, ok := translationLanguage(chi.URLParam(r, "lang"))
+	if !ok {
+		respondError(w, http.StatusBadRequest, "INVALID_LANGUAGE", "Language must be a BCP 47 tag other than "+domain.DefaultLanguage+", e.g. de or pt-BR")
+		return
+	}
+
+	if err := h.categoryRepo.DeleteTranslation(r.Context(), categoryID, lang); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Category translation deleted",
+	})
+}
+
+func (h *AdminHandler) GetCategoryListingRules(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid category ID")
+		return
+	}
+
+	if _, err := h.categoryRepo.GetByID(r.Context(), categoryID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	rules, err := h.categoryRepo.GetListingRules(r.Context(), categoryID)
+	if errors.Is(err, domain.ErrNotFound) {
+		respondJSON(w, http.StatusOK, &domain.CategoryListingRules{CategoryID: categoryID})
+		return
+	}
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rules)
+}
+
+// SetCategoryListingRules replaces a category's listing rules. They apply to
+// drafts created and auctions published from now on.
+func (h *AdminHandler) SetCategoryListingRules(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid category ID")
+		return
+	}
+
+	var req domain.SetCategoryListingRulesRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	rules := &domain.CategoryListingRules{
+		CategoryID:           categoryID,
+		DefaultDurationHours: req.DefaultDurationHours,
+		MinDurationHours:     req.MinDurationHours,
+		MaxDurationHours:     req.MaxDurationHours,
+		MinImages:            req.MinImages,
+	}
+	if req.DefaultBidIncrement != nil {
+		increment, err := domain.ParseAmount(*req.DefaultBidIncrement)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		rules.DefaultBidIncrement = &increment
+	}
+	if req.MinBidIncrement != nil {
+		increment, err := domain.ParseAmount(*req.MinBidIncrement)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		rules.MinBidIncrement = &increment
+	}
+	if err := rules.Validate(); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if _, err := h.categoryRepo.GetByID(r.Context(), categoryID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if err := h.categoryRepo.SetListingRules(r.Context(), rules); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rules)
+}
+
+func (h *AdminHandler) DeleteCategoryListingRules(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid category ID")
+		return
+	}
+
+	if err := h.categoryRepo.DeleteListingRules(r.Context(), categoryID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Category listing rules removed",
+	})
+}
+
 // Reports management
 
 func (h *AdminHandler) ListReports(w http.ResponseWriter, r *http.Request) {
@@ -317,6 +672,115 @@ func (h *AdminHandler) UpdateReport(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, report)
 }
 
+// Moderation queue
+
+func (h *AdminHandler) ListModerationCases(w http.ResponseWriter, r *http.Request) {
+	params := &domain.ModerationCaseListParams{
+		Page:  getQueryParamInt(r, "page", 1),
+		Limit: getQueryParamInt(r, "limit", 20),
+	}
+
+	if status := r.URL.Query().Get("status"); status != "" {
+		s := domain.ModerationCaseStatus(status)
+		params.Status = &s
+	}
+
+	cases, totalCount, err := h.adminService.ListModerationCases(r.Context(), params)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	totalPages := (totalCount + params.Limit - 1) / params.Limit
+
+	respondJSONWithMeta(w, http.StatusOK, cases, &domain.APIMeta{
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalCount: totalCount,
+		TotalPages: totalPages,
+	})
+}
+
+func (h *AdminHandler) UpdateModerationCase(w http.ResponseWriter, r *http.Request) {
+	caseID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid moderation case ID")
+		return
+	}
+
+	var req domain.UpdateModerationCaseRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	adminID := getUserID(r)
+	c, err := h.adminService.UpdateModerationCase(r.Context(), adminID, caseID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, c)
+}
+
+// Cross-origin allow list
+
+func (h *AdminHandler) ListAllowedOrigins(w http.ResponseWriter, r *http.Request) {
+	origins, err := h.originService.List(r.Context())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, origins)
+}
+
+func (h *AdminHandler) AllowOrigin(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateAllowedOriginRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	adminID := getUserID(r)
+	allowed, err := h.originService.Allow(r.Context(), adminID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, allowed)
+}
+
+func (h *AdminHandler) RevokeOrigin(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid origin ID")
+		return
+	}
+
+	adminID := getUserID(r)
+	if err := h.originService.Revoke(r.Context(), adminID, id); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Origin revoked",
+	})
+}
+
 func ptrTo[T any](v T) *T {
 	return &v
 }
@@ -0,0 +1,334 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock user merge repository. With ledger set it also moves escrow holds
+// and folds balances as the merge does.
+type mockUserMergeRepo struct {
+	interacted map[[2]uuid.UUID]bool
+	merges     int
+	ledger     *mockLedgerRepo
+}
+
+func (r *mockUserMergeRepo) HaveInteracted(ctx context.Context, a, b uuid.UUID) (bool, error) {
+	return r.interacted[[2]uuid.UUID{a, b}] || r.interacted[[2]uuid.UUID{b, a}], nil
+}
+
+func (r *mockUserMergeRepo) Merge(ctx context.Context, report *domain.UserMergeReport) error {
+	r.merges++
+	report.Moved["auctions"] = 2
+	report.Moved["bids"] = 5
+	report.Dropped["watchlist"] = 1
+
+	if r.ledger == nil {
+		return nil
+	}
+	for _, hold := range r.ledger.escrow.holds {
+		if hold.BuyerID == report.DuplicateID {
+			hold.BuyerID = report.PrimaryID
+			report.Moved["escrow_holds"]++
+		}
+		if hold.SellerID == report.DuplicateID {
+			hold.SellerID = report.PrimaryID
+			report.Moved["escrow_holds"]++
+		}
+	}
+	if available, ok := r.ledger.balances[report.DuplicateID]; ok {
+		r.ledger.balances[report.PrimaryID] = r.ledger.balances[report.PrimaryID].Add(available)
+		delete(r.ledger.balances, report.DuplicateID)
+		report.Moved["balances"]++
+	}
+	return nil
+}
+
+func TestAdminHandler_MergeUsers(t *testing.T) {
+	mergeRepo := &mockUserMergeRepo{interacted: make(map[[2]uuid.UUID]bool)}
+	auditRepo := &mockAuditLogRepo{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	adminService := service.NewAdminService(
+		nil,
+		nil,
+		auditRepo,
+		nil,
+		nil,
+		nil,
+		nil,
+		mergeRepo,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	adminHandler := handler.NewAdminHandler(nil, nil, adminService, nil, nil, nil, nil, nil, nil)
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(authMiddleware.RequireAdmin)
+		r.Post("/api/admin/users/{id}/merge", adminHandler.MergeUsers)
+	})
+
+	adminToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "admin")
+	userToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+
+	primaryID := uuid.New()
+	duplicateID := uuid.New()
+	tradedID := uuid.New()
+	mergeRepo.interacted[[2]uuid.UUID{tradedID, primaryID}] = true
+
+	tests := []struct {
+		name       string
+		body       domain.MergeUsersRequest
+		token      string
+		wantStatus int
+		wantCode   string
+		wantMerges int
+		wantAudits int
+	}{
+		{name: "not an admin", body: domain.MergeUsersRequest{DuplicateID: duplicateID, Reason: "same person"}, token: userToken, wantStatus: http.StatusForbidden},
+		{name: "missing reason", body: domain.MergeUsersRequest{DuplicateID: duplicateID}, token: adminToken, wantStatus: http.StatusBadRequest},
+		{name: "into itself", body: domain.MergeUsersRequest{DuplicateID: primaryID, Reason: "same person"}, token: adminToken, wantStatus: http.StatusBadRequest, wantCode: "MERGE_SAME_USER"},
+		{name: "accounts that traded", body: domain.MergeUsersRequest{DuplicateID: tradedID, Reason: "same person"}, token: adminToken, wantStatus: http.StatusConflict, wantCode: "MERGE_ACCOUNTS_INTERACTED"},
+		{name: "dry run", body: domain.MergeUsersRequest{DuplicateID: duplicateID, Reason: "same person", DryRun: true}, token: adminToken, wantStatus: http.StatusOK, wantMerges: 1},
+		{name: "merge", body: domain.MergeUsersRequest{DuplicateID: duplicateID, Reason: "same person"}, token: adminToken, wantStatus: http.StatusOK, wantMerges: 2, wantAudits: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := makeRequest(t, r, "POST", "/api/admin/users/"+primaryID.String()+"/merge", tt.body, tt.token)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+
+			response := parseResponse(t, rr)
+			if tt.wantCode != "" && (response.Error == nil || response.Error.Code != tt.wantCode) {
+				t.Errorf("expected error code %s, got %+v", tt.wantCode, response.Error)
+			}
+			if mergeRepo.merges != tt.wantMerges {
+				t.Errorf("got %d merges, want %d", mergeRepo.merges, tt.wantMerges)
+			}
+			if len(auditRepo.entries) != tt.wantAudits {
+				t.Errorf("got %d audit entries, want %d", len(auditRepo.entries), tt.wantAudits)
+			}
+
+			if rr.Code == http.StatusOK {
+				report := response.Data.(map[string]interface{})
+				if report["dry_run"] != tt.body.DryRun {
+					t.Errorf("got dry_run %v, want %v", report["dry_run"], tt.body.DryRun)
+				}
+				if moved := report["moved"].(map[string]interface{}); moved["bids"] != float64(5) {
+					t.Errorf("got moved %v, want 5 bids", moved)
+				}
+			}
+		})
+	}
+
+	entry := auditRepo.entries[0]
+	if entry.Action != domain.AuditActionUserMerge || *entry.EntityID != primaryID || entry.Metadata["duplicate_id"] != duplicateID {
+		t.Errorf("unexpected audit entry %+v", entry)
+	}
+}
+
+func TestAdminHandler_MergeUsersWithEscrow(t *testing.T) {
+	escrowRepo := newMockEscrowRepo()
+	ledgerRepo := newMockLedgerRepo(escrowRepo)
+	mergeRepo := &mockUserMergeRepo{interacted: make(map[[2]uuid.UUID]bool), ledger: ledgerRepo}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	ledgerService := service.NewLedgerService(escrowRepo, ledgerRepo, nil, &mockAuditLogRepo{}, &mockTxManager{}, nil, 14*24*time.Hour)
+	adminService := service.NewAdminService(
+		nil,
+		nil,
+		&mockAuditLogRepo{},
+		nil,
+		nil,
+		nil,
+		nil,
+		mergeRepo,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	adminHandler := handler.NewAdminHandler(nil, nil, adminService, nil, nil, nil, nil, nil, nil)
+	ledgerHandler := handler.NewLedgerHandler(ledgerService)
+	r.With(authMiddleware.RequireAuth, authMiddleware.RequireAdmin).Post("/api/admin/users/{id}/merge", adminHandler.MergeUsers)
+	r.With(authMiddleware.RequireAuth).Post("/api/orders/{id}/confirm-delivery", ledgerHandler.ConfirmDelivery)
+	r.With(authMiddleware.RequireAuth).Get("/api/users/me/balance", ledgerHandler.GetBalance)
+
+	primaryID := uuid.New()
+	duplicateID := uuid.New()
+	otherID := uuid.New()
+	adminToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "admin")
+	primaryToken, _ := jwtManager.GenerateAccessToken(primaryID, "user")
+
+	holdPayment := func(buyerID, sellerID uuid.UUID, amount float64) *domain.Order {
+		order := &domain.Order{ID: uuid.New(), BuyerID: buyerID, SellerID: sellerID, Amount: decimal.NewFromFloat(amount), Status: domain.OrderStatusPaid}
+		if _, err := ledgerService.HoldPayment(context.Background(), order); err != nil {
+			t.Fatalf("failed to hold payment: %v", err)
+		}
+		return order
+	}
+
+	// The duplicate already has 30 available, bought one order and sold
+	// another that are both still held
+	released := holdPayment(otherID, duplicateID, 30)
+	if _, err := ledgerService.ConfirmDelivery(context.Background(), released.ID, otherID); err != nil {
+		t.Fatalf("failed to release payment: %v", err)
+	}
+	bought := holdPayment(duplicateID, otherID, 40)
+	sold := holdPayment(otherID, duplicateID, 100)
+	ledgerRepo.balances[primaryID] = decimal.NewFromInt(5)
+
+	rr := makeRequest(t, r, "POST", "/api/admin/users/"+primaryID.String()+"/merge",
+		domain.MergeUsersRequest{DuplicateID: duplicateID, Reason: "same person"}, adminToken)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("merge returned status %v", rr.Code)
+	}
+	moved := parseResponse(t, rr).Data.(map[string]interface{})["moved"].(map[string]interface{})
+	if moved["escrow_holds"] != float64(3) || moved["balances"] != float64(1) {
+		t.Errorf("expected 3 escrow holds and 1 balance moved, got %v", moved)
+	}
+
+	// The primary confirms delivery of what the duplicate bought
+	rr = makeRequest(t, r, "POST", "/api/orders/"+bought.ID.String()+"/confirm-delivery", nil, primaryToken)
+	if rr.Code != http.StatusOK {
+		t.Errorf("confirm delivery returned status %v, want %v", rr.Code, http.StatusOK)
+	}
+
+	// What the duplicate sold is released to the primary
+	escrowRepo.holds[sold.ID].ReleaseAt = time.Now().Add(-time.Minute)
+	ledgerService.ProcessDueReleases(context.Background())
+	if escrowRepo.holds[sold.ID].Status != domain.EscrowReleased {
+		t.Fatalf("expected the sold order's payment to be released, got %s", escrowRepo.holds[sold.ID].Status)
+	}
+
+	rr = makeRequest(t, r, "GET", "/api/users/me/balance", nil, primaryToken)
+	balance := parseResponse(t, rr).Data.(map[string]interface{})
+	if balance["available"] != "135" || balance["pending"] != "0" {
+		t.Errorf("expected 135 available and 0 pending, got %v", balance)
+	}
+	if _, ok := ledgerRepo.balances[duplicateID]; ok {
+		t.Errorf("expected no balance left on the duplicate, got %s", ledgerRepo.balances[duplicateID])
+	}
+}
+
+// racingBidRepo lets a bid in right after the highest bid is read
+type racingBidRepo struct {
+	*lockedBidRepo
+	afterHighest func()
+}
+
+func (r *racingBidRepo) GetHighestBid(ctx context.Context, auctionID uuid.UUID) (*domain.Bid, error) {
+	bid, err := r.lockedBidRepo.GetHighestBid(ctx, auctionID)
+	if hook := r.afterHighest; hook != nil {
+		r.afterHighest = nil
+		hook()
+	}
+	return bid, err
+}
+
+func TestAdminHandler_ForceEndAuctionRacingBid(t *testing.T) {
+	var mu sync.Mutex
+	auctionRepo := &lockedAuctionRepo{mockAuctionRepo: newMockAuctionRepo(), mu: &mu}
+	bidRepo := &racingBidRepo{lockedBidRepo: &lockedBidRepo{mockBidRepo: newMockBidRepo(), mu: &mu}}
+	auditRepo := &mockAuditLogRepo{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	bidService := service.NewBidService(bidRepo, auctionRepo, nil, nil, nil, nil, &mockTxManager{}, nil, nil, nil, nil, nil, nil)
+	adminService := service.NewAdminService(
+		auctionRepo,
+		bidRepo,
+		auditRepo,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	adminHandler := handler.NewAdminHandler(nil, nil, adminService, nil, nil, nil, nil, nil, nil)
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(authMiddleware.RequireAdmin)
+		r.Post("/api/admin/auctions/{id}/force-end", adminHandler.ForceEndAuction)
+	})
+	adminToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "admin")
+
+	auction := &domain.Auction{
+		SellerID:      uuid.New(),
+		Title:         "Force Ended Auction",
+		StartingPrice: decimal.NewFromFloat(100),
+		CurrentPrice:  decimal.NewFromFloat(100),
+		BidIncrement:  decimal.NewFromFloat(5),
+		StartTime:     time.Now().Add(-1 * time.Hour),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+	}
+	auctionRepo.mockAuctionRepo.Create(context.Background(), auction)
+
+	placeBid := func(bidderID uuid.UUID, amount string) {
+		req := &domain.PlaceBidRequest{Amount: amount}
+		if _, err := bidService.PlaceBid(context.Background(), auction.ID, bidderID, req, nil); err != nil {
+			t.Fatalf("placing a bid of %s: %v", amount, err)
+		}
+	}
+	firstBidder, lateBidder := uuid.New(), uuid.New()
+	placeBid(firstBidder, "105")
+	bidRepo.afterHighest = func() { placeBid(lateBidder, "110") }
+
+	rr := makeRequest(t, r, "POST", "/api/admin/auctions/"+auction.ID.String()+"/force-end", domain.ForceEndAuctionRequest{Reason: "shill bidding", Outcome: "award"}, adminToken)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+
+	stored, _ := auctionRepo.GetByID(context.Background(), auction.ID)
+	if stored.Status != domain.AuctionStatusCompleted || stored.WinnerID == nil || *stored.WinnerID != lateBidder {
+		t.Errorf("expected the late bid to win, got status %s and winner %v", stored.Status, stored.WinnerID)
+	}
+	if !stored.CurrentPrice.Equal(decimal.NewFromFloat(110)) || stored.BidCount != 2 {
+		t.Errorf("expected both bids counted at 110, got %d at %s", stored.BidCount, stored.CurrentPrice)
+	}
+	if stored.EndTime.After(time.Now()) {
+		t.Errorf("expected the auction to end now, got %s", stored.EndTime)
+	}
+	if len(auditRepo.entries) != 1 {
+		t.Errorf("got %d audit entries, want 1", len(auditRepo.entries))
+	}
+
+	rr = makeRequest(t, r, "POST", "/api/admin/auctions/"+auction.ID.String()+"/force-end", domain.ForceEndAuctionRequest{Reason: "shill bidding", Outcome: "void"}, adminToken)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected ending an ended auction to fail, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type APIUsageHandler struct {
+	apiUsageService *service.APIUsageService
+}
+
+func NewAPIUsageHandler(apiUsageService *service.APIUsageService) *APIUsageHandler {
+	return &APIUsageHandler{apiUsageService: apiUsageService}
+}
+
+// GetMyUsage shows the signed-in user how much of each per-user rate limit
+// they have used, over a window of 1d, 7d (the default) or 30d
+func (h *APIUsageHandler) GetMyUsage(w http.ResponseWriter, r *http.Request) {
+	window := domain.APIUsageWindow(r.URL.Query().Get("window"))
+	if window == "" {
+		window = domain.APIUsageWindowWeek
+	}
+
+	usage, err := h.apiUsageService.GetUsage(r.Context(), getUserID(r), window)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, usage)
+}
@@ -0,0 +1,228 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/cache"
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Mock API usage repository
+type mockAPIUsageRepo struct {
+	days []domain.APIUsageDay
+}
+
+func (r *mockAPIUsageRepo) SaveDays(ctx context.Context, days []domain.APIUsageDay) error {
+	for _, day := range days {
+		replaced := false
+		for i, saved := range r.days {
+			if saved.UserID == day.UserID && saved.Limit == day.Limit && saved.Day.Equal(day.Day) {
+				r.days[i] = day
+				replaced = true
+			}
+		}
+		if !replaced {
+			r.days = append(r.days, day)
+		}
+	}
+	return nil
+}
+
+func (r *mockAPIUsageRepo) ListByUser(ctx context.Context, userID uuid.UUID, from time.Time) ([]domain.APIUsageDay, error) {
+	days := make([]domain.APIUsageDay, 0)
+	for _, day := range r.days {
+		if day.UserID == userID && !day.Day.Before(from) {
+			days = append(days, day)
+		}
+	}
+	return days, nil
+}
+
+func (r *mockAPIUsageRepo) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+var testAPILimits = []service.APILimit{
+	{Name: "bids", Requests: 2, Window: time.Minute, Key: cache.RateLimitKeyBid},
+	{Name: "messages", Requests: 20, Window: time.Minute, Key: cache.RateLimitKeyMessage},
+}
+
+func getAPIUsage(t *testing.T, r *chi.Mux, query, token string) domain.APIUsage {
+	t.Helper()
+	rr := makeRequest(t, r, "GET", "/api/users/me/api-usage"+query, nil, token)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var usage domain.APIUsage
+	data, _ := json.Marshal(parseResponse(t, rr).Data)
+	if err := json.Unmarshal(data, &usage); err != nil {
+		t.Fatalf("failed to decode usage: %v", err)
+	}
+	return usage
+}
+
+func findLimitUsage(t *testing.T, usage domain.APIUsage, name string) domain.APILimitUsage {
+	t.Helper()
+	for _, limit := range usage.Limits {
+		if limit.Name == name {
+			return limit
+		}
+	}
+	t.Fatalf("no usage for limit %s", name)
+	return domain.APILimitUsage{}
+}
+
+func TestAPIUsageHandler_GetMyUsage(t *testing.T) {
+	userID := uuid.New()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	repo := &mockAPIUsageRepo{days: []domain.APIUsageDay{
+		{UserID: userID, Limit: "bids", Day: today.AddDate(0, 0, -2), Requests: 12, RateLimited: 2},
+		{UserID: userID, Limit: "bids", Day: today.AddDate(0, 0, -10), Requests: 5},
+		{UserID: userID, Limit: "messages", Day: today.AddDate(0, 0, -1), Requests: 3},
+		{UserID: uuid.New(), Limit: "bids", Day: today, Requests: 40},
+	}}
+
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+	usageHandler := handler.NewAPIUsageHandler(service.NewAPIUsageService(repo, nil, testAPILimits))
+
+	r := createTestRouter()
+	r.With(authMiddleware.RequireAuth).Get("/api/users/me/api-usage", usageHandler.GetMyUsage)
+
+	token, _ := jwtManager.GenerateAccessToken(userID, "user")
+
+	t.Run("last 7 days by default", func(t *testing.T) {
+		usage := getAPIUsage(t, r, "", token)
+		if usage.Window != domain.APIUsageWindowWeek || !usage.From.Equal(today.AddDate(0, 0, -6)) {
+			t.Errorf("got window %s from %s", usage.Window, usage.From)
+		}
+
+		bids := findLimitUsage(t, usage, "bids")
+		if bids.Requests != 12 || bids.RateLimited != 2 {
+			t.Errorf("got %d requests, %d rate limited", bids.Requests, bids.RateLimited)
+		}
+		if bids.Limit != 2 || bids.WindowSeconds != 60 || bids.Remaining != 2 {
+			t.Errorf("got limit %d per %ds, %d remaining", bids.Limit, bids.WindowSeconds, bids.Remaining)
+		}
+		if len(bids.Daily) != 7 {
+			t.Fatalf("expected a row for each of 7 days, got %d", len(bids.Daily))
+		}
+		if day := bids.Daily[4]; !day.Day.Equal(today.AddDate(0, 0, -2)) || day.Requests != 12 {
+			t.Errorf("got %d requests on %s", day.Requests, day.Day)
+		}
+		if day := bids.Daily[6]; !day.Day.Equal(today) || day.Requests != 0 {
+			t.Errorf("got %d requests on %s, another user's usage leaked", day.Requests, day.Day)
+		}
+
+		if messages := findLimitUsage(t, usage, "messages"); messages.Requests != 3 {
+			t.Errorf("got %d messages", messages.Requests)
+		}
+	})
+
+	t.Run("last 30 days", func(t *testing.T) {
+		bids := findLimitUsage(t, getAPIUsage(t, r, "?window=30d", token), "bids")
+		if bids.Requests != 17 || len(bids.Daily) != 30 {
+			t.Errorf("got %d requests over %d days", bids.Requests, len(bids.Daily))
+		}
+	})
+
+	t.Run("today", func(t *testing.T) {
+		bids := findLimitUsage(t, getAPIUsage(t, r, "?window=1d", token), "bids")
+		if bids.Requests != 0 || len(bids.Daily) != 1 {
+			t.Errorf("got %d requests over %d days", bids.Requests, len(bids.Daily))
+		}
+	})
+
+	t.Run("unknown window", func(t *testing.T) {
+		rr := makeRequest(t, r, "GET", "/api/users/me/api-usage?window=2d", nil, token)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rr.Code)
+		}
+		if response := parseResponse(t, rr); response.Error == nil || response.Error.Code != "INVALID_WINDOW" {
+			t.Errorf("got error %v", response.Error)
+		}
+	})
+
+	t.Run("signed out", func(t *testing.T) {
+		rr := makeRequest(t, r, "GET", "/api/users/me/api-usage", nil, "")
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", rr.Code)
+		}
+	})
+}
+
+// Counts requests through the rate limiter in the Redis named by
+// TEST_REDIS_ADDR:
+//
+//	TEST_REDIS_ADDR=localhost:6379 go test -run APIUsage ./internal/handler
+func TestAPIUsage_CountedByRateLimiter(t *testing.T) {
+	addr := os.Getenv("TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("TEST_REDIS_ADDR not set")
+	}
+
+	redis, err := cache.NewRedisCache(addr, "", 0, 0)
+	if err != nil {
+		t.Fatalf("failed to connect to redis: %v", err)
+	}
+	t.Cleanup(func() { redis.Close() })
+
+	repo := &mockAPIUsageRepo{}
+	usageService := service.NewAPIUsageService(repo, redis, testAPILimits)
+
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+	bidRateLimit := middleware.BidRateLimitConfig().WithBudget(2, time.Minute)
+
+	r := createTestRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.With(middleware.RateLimit(redis, bidRateLimit)).Post("/api/auctions/{id}/bids", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		})
+		r.Get("/api/users/me/api-usage", handler.NewAPIUsageHandler(usageService).GetMyUsage)
+	})
+
+	userID := uuid.New()
+	token, _ := jwtManager.GenerateAccessToken(userID, "user")
+	for i, want := range []int{http.StatusCreated, http.StatusCreated, http.StatusTooManyRequests} {
+		if rr := makeRequest(t, r, "POST", "/api/auctions/"+uuid.NewString()+"/bids", nil, token); rr.Code != want {
+			t.Fatalf("bid %d: expected %d, got %d", i+1, want, rr.Code)
+		}
+	}
+
+	bids := findLimitUsage(t, getAPIUsage(t, r, "?window=1d", token), "bids")
+	if bids.Used != 3 || bids.Remaining != 0 || bids.ResetsAt == nil {
+		t.Errorf("got %d used, %d remaining, resets at %v", bids.Used, bids.Remaining, bids.ResetsAt)
+	}
+	if bids.Requests != 3 || bids.RateLimited != 1 {
+		t.Errorf("got %d requests, %d rate limited", bids.Requests, bids.RateLimited)
+	}
+
+	// Rolling up the same day twice replaces the first copy
+	for range 2 {
+		if _, err := usageService.RollupDay(context.Background(), time.Now()); err != nil {
+			t.Fatalf("rollup failed: %v", err)
+		}
+	}
+	saved, _ := repo.ListByUser(context.Background(), userID, time.Time{})
+	if len(saved) != 1 || saved[0].Limit != "bids" || saved[0].Requests != 3 || saved[0].RateLimited != 1 {
+		t.Errorf("rolled up %+v", saved)
+	}
+
+	// Today still comes from Redis rather than adding the rollup to it
+	if bids := findLimitUsage(t, getAPIUsage(t, r, "?window=1d", token), "bids"); bids.Requests != 3 {
+		t.Errorf("got %d requests after the rollup", bids.Requests)
+	}
+}
@@ -2,9 +2,12 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/auction-cards/backend/internal/domain"
 	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
 	"github.com/shopspring/decimal"
 )
 
@@ -45,15 +48,42 @@ func (h *AuctionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	auction, err := h.auctionService.GetByID(r.Context(), id, true)
+	auction, err := h.auctionService.GetByID(r.Context(), id, getUserID(r), r.URL.Query().Get("share"))
 	if err != nil {
 		handleError(w, err)
 		return
 	}
+	h.auctionService.RecordView(r.Context(), id, viewerKey(r))
+	h.auctionService.EstimatePrice(r.Context(), auction, getUserID(r))
+	h.auctionService.CheckEligibility(r.Context(), auction, getUserID(r))
+	h.auctionService.TranslateCategory(r.Context(), auction, preferredLanguages(w, r))
 
 	respondJSON(w, http.StatusOK, auction)
 }
 
+// BatchGet fetches up to 100 auctions at once. IDs the viewer can't see are
+// listed as missing rather than failing the request.
+func (h *AuctionHandler) BatchGet(w http.ResponseWriter, r *http.Request) {
+	var req domain.BatchGetRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	result, err := h.auctionService.GetByIDs(r.Context(), req.IDs, getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
 func (h *AuctionHandler) Update(w http.ResponseWriter, r *http.Request) {
 	id, err := getURLParamUUID(r, "id")
 	if err != nil {
@@ -112,11 +142,51 @@ func (h *AuctionHandler) Publish(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, auction)
 }
 
-func (h *AuctionHandler) List(w http.ResponseWriter, r *http.Request) {
+func (h *AuctionHandler) SuggestEndTime(w http.ResponseWriter, r *http.Request) {
+	requested, err := time.Parse(time.RFC3339, r.URL.Query().Get("end_time"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_END_TIME", "end_time must be an RFC 3339 timestamp")
+		return
+	}
+
+	suggested, err := h.auctionService.SuggestEndTime(r.Context(), requested)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, domain.EndTimeSuggestion{
+		RequestedEndTime: requested,
+		SuggestedEndTime: suggested,
+		Adjusted:         !suggested.Equal(requested.Truncate(time.Second)),
+	})
+}
+
+func (h *AuctionHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	userID := getUserID(r)
+	stats, err := h.auctionService.GetStats(r.Context(), id, userID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, stats)
+}
+
+// parseListParams reads the filters shared by List and Facets. It writes
+// the error response and returns false when a parameter is invalid.
+func parseListParams(w http.ResponseWriter, r *http.Request) (*domain.AuctionListParams, bool) {
 	params := &domain.AuctionListParams{
 		Page:   getQueryParamInt(r, "page", 1),
 		Limit:  getQueryParamInt(r, "limit", 20),
 		SortBy: r.URL.Query().Get("sort"),
+		Seed:   r.URL.Query().Get("seed"),
 	}
 
 	if status := r.URL.Query().Get("status"); status != "" {
@@ -141,7 +211,46 @@ func (h *AuctionHandler) List(w http.ResponseWriter, r *http.Request) {
 		params.MaxPrice = &price
 	}
 
-	result, err := h.auctionService.List(r.Context(), params)
+	if endsWithin := r.URL.Query().Get("ends_within"); endsWithin != "" {
+		d, err := time.ParseDuration(endsWithin)
+		if err != nil || d <= 0 {
+			respondError(w, http.StatusBadRequest, "INVALID_ENDS_WITHIN", "ends_within must be a positive duration such as 1h or 24h")
+			return nil, false
+		}
+		params.EndsWithin = &d
+	}
+	if condition := r.URL.Query().Get("condition"); condition != "" {
+		c := domain.ItemCondition(condition)
+		params.Condition = &c
+	}
+	if rating := r.URL.Query().Get("min_seller_rating"); rating != "" {
+		if v, err := strconv.ParseFloat(rating, 64); err == nil {
+			params.MinSellerRating = &v
+		}
+	}
+	params.HasBuyNow = getQueryParamBool(r, "has_buy_now")
+	params.HasReserve = getQueryParamBool(r, "has_reserve")
+	params.FreeShipping = r.URL.Query().Get("free_shipping") == "true"
+
+	// Sellers away in hide mode still see their own listings
+	viewerID := getUserID(r)
+	params.HideVacationing = params.SellerID == nil || *params.SellerID != viewerID
+
+	// Private auctions are only listed for their seller and invited users
+	params.HidePrivate = true
+	params.ViewerID = &viewerID
+
+	return params, true
+}
+
+func (h *AuctionHandler) List(w http.ResponseWriter, r *http.Request) {
+	params, ok := parseListParams(w, r)
+	if !ok {
+		return
+	}
+
+	viewerID := getUserID(r)
+	result, err := h.auctionService.List(r.Context(), params, &viewerID)
 	if err != nil {
 		handleError(w, err)
 		return
@@ -152,9 +261,102 @@ func (h *AuctionHandler) List(w http.ResponseWriter, r *http.Request) {
 		Limit:      params.Limit,
 		TotalCount: result.TotalCount,
 		TotalPages: result.TotalPages,
+		Seed:       result.Seed,
 	})
 }
 
+// ListSold browses past sales with price statistics. Dates may be given as
+// RFC 3339 times or as days, in which case sold_to includes the whole day.
+func (h *AuctionHandler) ListSold(w http.ResponseWriter, r *http.Request) {
+	params := &domain.SoldListingParams{
+		CategoryID: getQueryParamUUID(r, "category_id"),
+		Search:     getQueryParamString(r, "search"),
+		SortBy:     r.URL.Query().Get("sort"),
+		Page:       max(getQueryParamInt(r, "page", 1), 1),
+		Limit:      getQueryParamInt(r, "limit", 20),
+	}
+	if params.Limit <= 0 || params.Limit > 100 {
+		params.Limit = 20
+	}
+
+	if condition := r.URL.Query().Get("condition"); condition != "" {
+		c := domain.ItemCondition(condition)
+		params.Condition = &c
+	}
+	if minPrice := r.URL.Query().Get("min_price"); minPrice != "" {
+		price, _ := decimal.NewFromString(minPrice)
+		params.MinPrice = &price
+	}
+	if maxPrice := r.URL.Query().Get("max_price"); maxPrice != "" {
+		price, _ := decimal.NewFromString(maxPrice)
+		params.MaxPrice = &price
+	}
+
+	if soldFrom := r.URL.Query().Get("sold_from"); soldFrom != "" {
+		t, err := parseDateParam(soldFrom, false)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "INVALID_DATE", "sold_from must be a date such as 2024-05-01 or an RFC 3339 time")
+			return
+		}
+		params.SoldFrom = &t
+	}
+	if soldTo := r.URL.Query().Get("sold_to"); soldTo != "" {
+		t, err := parseDateParam(soldTo, true)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "INVALID_DATE", "sold_to must be a date such as 2024-05-01 or an RFC 3339 time")
+			return
+		}
+		params.SoldTo = &t
+	}
+
+	result, err := h.auctionService.ListSold(r.Context(), params)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSONWithMeta(w, http.StatusOK, result, &domain.APIMeta{
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalCount: result.TotalCount,
+		TotalPages: (result.TotalCount + params.Limit - 1) / params.Limit,
+	})
+}
+
+// parseDateParam reads an RFC 3339 time or a day. A day closing a range
+// is read as the start of the next, so the range includes it.
+func parseDateParam(value string, rangeEnd bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	day, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if rangeEnd {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day, nil
+}
+
+// Facets counts the listings matching the List filters by category,
+// condition and price bucket
+func (h *AuctionHandler) Facets(w http.ResponseWriter, r *http.Request) {
+	params, ok := parseListParams(w, r)
+	if !ok {
+		return
+	}
+
+	facets, err := h.auctionService.Facets(r.Context(), params)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, facets)
+}
+
 func (h *AuctionHandler) UploadImage(w http.ResponseWriter, r *http.Request) {
 	id, err := getURLParamUUID(r, "id")
 	if err != nil {
@@ -187,6 +389,55 @@ func (h *AuctionHandler) UploadImage(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusCreated, image)
 }
 
+func (h *AuctionHandler) UploadImages(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	// Parse multipart form (files beyond 32MB are buffered on disk)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_FORM", "Invalid form data")
+		return
+	}
+
+	headers := r.MultipartForm.File["images"]
+	if len(headers) == 0 {
+		respondError(w, http.StatusBadRequest, "NO_FILE", "No image files provided")
+		return
+	}
+	if len(headers) > service.MaxImagesPerUpload {
+		respondError(w, http.StatusBadRequest, "TOO_MANY_FILES", "Too many image files")
+		return
+	}
+
+	files := make([]service.ImageUpload, 0, len(headers))
+	for _, header := range headers {
+		file, err := header.Open()
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "INVALID_FILE", "Could not read image file")
+			return
+		}
+		defer file.Close()
+
+		files = append(files, service.ImageUpload{
+			Reader:      file,
+			ContentType: header.Header.Get("Content-Type"),
+			Size:        header.Size,
+		})
+	}
+
+	userID := getUserID(r)
+	images, err := h.auctionService.UploadImages(r.Context(), id, userID, files)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, images)
+}
+
 func (h *AuctionHandler) DeleteImage(w http.ResponseWriter, r *http.Request) {
 	auctionID, err := getURLParamUUID(r, "id")
 	if err != nil {
@@ -214,7 +465,7 @@ func (h *AuctionHandler) DeleteImage(w http.ResponseWriter, r *http.Request) {
 // Category handlers
 
 func (h *AuctionHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
-	categories, err := h.auctionService.GetCategories(r.Context())
+	categories, err := h.auctionService.GetCategories(r.Context(), preferredLanguages(w, r))
 	if err != nil {
 		handleError(w, err)
 		return
@@ -230,7 +481,7 @@ func (h *AuctionHandler) GetCategoryBySlug(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	category, err := h.auctionService.GetCategoryBySlug(r.Context(), slug)
+	category, err := h.auctionService.GetCategoryBySlug(r.Context(), slug, preferredLanguages(w, r))
 	if err != nil {
 		handleError(w, err)
 		return
@@ -238,3 +489,15 @@ func (h *AuctionHandler) GetCategoryBySlug(w http.ResponseWriter, r *http.Reques
 
 	respondJSON(w, http.StatusOK, category)
 }
+
+// GetListingRules returns the defaults and publishing requirements of a
+// category, so sellers can see them while drafting
+func (h *AuctionHandler) GetListingRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.auctionService.GetListingRules(r.Context(), chi.URLParam(r, "slug"))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rules)
+}
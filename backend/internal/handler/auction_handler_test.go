@@ -2,7 +2,11 @@ package handler_test
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -17,11 +21,14 @@ import (
 // Mock auction repository
 type mockAuctionRepo struct {
 	auctions map[uuid.UUID]*domain.Auction
+	// invited users by auction, shared with mockAuctionInviteRepo
+	invites map[uuid.UUID]map[uuid.UUID]time.Time
 }
 
 func newMockAuctionRepo() *mockAuctionRepo {
 	return &mockAuctionRepo{
 		auctions: make(map[uuid.UUID]*domain.Auction),
+		invites:  make(map[uuid.UUID]map[uuid.UUID]time.Time),
 	}
 }
 
@@ -47,6 +54,16 @@ func (r *mockAuctionRepo) GetByIDWithDetails(ctx context.Context, id uuid.UUID)
 	return r.GetByID(ctx, id)
 }
 
+func (r *mockAuctionRepo) GetByIDsWithDetails(ctx context.Context, ids []uuid.UUID) ([]domain.Auction, error) {
+	auctions := make([]domain.Auction, 0, len(ids))
+	for _, id := range ids {
+		if auction, ok := r.auctions[id]; ok {
+			auctions = append(auctions, *auction)
+		}
+	}
+	return auctions, nil
+}
+
 func (r *mockAuctionRepo) Update(ctx context.Context, auction *domain.Auction) error {
 	auction.UpdatedAt = time.Now()
 	auction.Version++
@@ -65,6 +82,14 @@ func (r *mockAuctionRepo) UpdateWithVersion(ctx context.Context, auction *domain
 	return r.Update(ctx, auction)
 }
 
+func (r *mockAuctionRepo) UpdateReserveWithVersion(ctx context.Context, auction *domain.Auction, expectedVersion int) error {
+	return r.UpdateWithVersion(ctx, auction, expectedVersion)
+}
+
+func (r *mockAuctionRepo) UpdateBuyNowPriceWithVersion(ctx context.Context, auction *domain.Auction, expectedVersion int) error {
+	return r.UpdateWithVersion(ctx, auction, expectedVersion)
+}
+
 func (r *mockAuctionRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	delete(r.auctions, id)
 	return nil
@@ -79,11 +104,143 @@ func (r *mockAuctionRepo) List(ctx context.Context, params *domain.AuctionListPa
 		if params.SellerID != nil && auction.SellerID != *params.SellerID {
 			continue
 		}
+		if params.CategoryID != nil && (auction.CategoryID == nil || *auction.CategoryID != *params.CategoryID) {
+			continue
+		}
+		if params.MinPrice != nil && auction.CurrentPrice.LessThan(*params.MinPrice) {
+			continue
+		}
+		if params.MaxPrice != nil && auction.CurrentPrice.GreaterThan(*params.MaxPrice) {
+			continue
+		}
+		if params.Condition != nil && (auction.Condition == nil || *auction.Condition != *params.Condition) {
+			continue
+		}
+		if params.EndsWithin != nil && auction.EndTime.After(time.Now().Add(*params.EndsWithin)) {
+			continue
+		}
+		if params.HasBuyNow != nil && auction.CanBuyNow() != *params.HasBuyNow {
+			continue
+		}
+		if params.HasReserve != nil && (auction.ReservePrice != nil) != *params.HasReserve {
+			continue
+		}
+		if params.StartedAfter != nil && !auction.StartTime.After(*params.StartedAfter) {
+			continue
+		}
+		if params.HidePrivate && auction.Visibility == domain.AuctionPrivate {
+			if params.ViewerID == nil || (auction.SellerID != *params.ViewerID && r.invites[auction.ID][*params.ViewerID].IsZero()) {
+				continue
+			}
+		}
 		auctions = append(auctions, *auction)
 	}
 	return auctions, len(auctions), nil
 }
 
+func (r *mockAuctionRepo) GetFacets(ctx context.Context, params *domain.AuctionListParams) (*domain.AuctionFacets, error) {
+	facets := &domain.AuctionFacets{Categories: make([]domain.CategoryFacet, 0), Conditions: make([]domain.ConditionFacet, 0)}
+	matching, total, _ := r.List(ctx, params)
+	facets.Total = total
+
+	categoryParams := *params
+	categoryParams.CategoryID = nil
+	byCategory, _, _ := r.List(ctx, &categoryParams)
+	categoryCounts := make(map[uuid.UUID]int)
+	for _, auction := range byCategory {
+		if auction.CategoryID != nil {
+			categoryCounts[*auction.CategoryID]++
+		}
+	}
+	for id, count := range categoryCounts {
+		facets.Categories = append(facets.Categories, domain.CategoryFacet{CategoryID: id, Count: count})
+	}
+
+	conditionParams := *params
+	conditionParams.Condition = nil
+	byCondition, _, _ := r.List(ctx, &conditionParams)
+	conditionCounts := make(map[domain.ItemCondition]int)
+	for _, auction := range byCondition {
+		if auction.Condition != nil {
+			conditionCounts[*auction.Condition]++
+		}
+	}
+	for condition, count := range conditionCounts {
+		facets.Conditions = append(facets.Conditions, domain.ConditionFacet{Condition: condition, Count: count})
+	}
+
+	min := decimal.Zero
+	for i := 0; i <= len(domain.PriceFacetBounds); i++ {
+		facet := domain.PriceFacet{Min: min}
+		if i < len(domain.PriceFacetBounds) {
+			max := domain.PriceFacetBounds[i]
+			facet.Max = &max
+			min = max
+		}
+		for _, auction := range matching {
+			if !auction.CurrentPrice.LessThan(facet.Min) && (facet.Max == nil || auction.CurrentPrice.LessThan(*facet.Max)) {
+				facet.Count++
+			}
+		}
+		facets.PriceBuckets = append(facets.PriceBuckets, facet)
+	}
+
+	return facets, nil
+}
+
+// ListSold treats completed auctions with a winner as sold when they ended
+func (r *mockAuctionRepo) ListSold(ctx context.Context, params *domain.SoldListingParams) ([]domain.SoldListing, int, error) {
+	sales := make([]domain.SoldListing, 0)
+	for _, auction := range r.auctions {
+		if auction.Status != domain.AuctionStatusCompleted || auction.WinnerID == nil {
+			continue
+		}
+		if params.CategoryID != nil && (auction.CategoryID == nil || *auction.CategoryID != *params.CategoryID) {
+			continue
+		}
+		if params.SoldFrom != nil && auction.EndTime.Before(*params.SoldFrom) {
+			continue
+		}
+		if params.SoldTo != nil && !auction.EndTime.Before(*params.SoldTo) {
+			continue
+		}
+		if params.MinPrice != nil && auction.CurrentPrice.LessThan(*params.MinPrice) {
+			continue
+		}
+		if params.MaxPrice != nil && auction.CurrentPrice.GreaterThan(*params.MaxPrice) {
+			continue
+		}
+		sales = append(sales, domain.SoldListing{
+			AuctionID:  auction.ID,
+			Title:      auction.Title,
+			CategoryID: auction.CategoryID,
+			SoldPrice:  auction.CurrentPrice,
+			BidCount:   auction.BidCount,
+			SoldAt:     auction.EndTime,
+		})
+	}
+	return sales, len(sales), nil
+}
+
+func (r *mockAuctionRepo) GetSoldStats(ctx context.Context, params *domain.SoldListingParams) (*domain.SoldPriceStats, error) {
+	sales, _, _ := r.ListSold(ctx, params)
+	stats := &domain.SoldPriceStats{Count: len(sales)}
+	if len(sales) == 0 {
+		return stats, nil
+	}
+
+	sum := decimal.Zero
+	low, high := sales[0].SoldPrice, sales[0].SoldPrice
+	for _, sale := range sales {
+		sum = sum.Add(sale.SoldPrice)
+		low = decimal.Min(low, sale.SoldPrice)
+		high = decimal.Max(high, sale.SoldPrice)
+	}
+	average := sum.Div(decimal.NewFromInt(int64(len(sales)))).Round(2)
+	stats.Average, stats.Min, stats.Max = &average, &low, &high
+	return stats, nil
+}
+
 func (r *mockAuctionRepo) GetEndingAuctions(ctx context.Context, before int64) ([]domain.Auction, error) {
 	auctions := make([]domain.Auction, 0)
 	for _, auction := range r.auctions {
@@ -94,9 +251,66 @@ func (r *mockAuctionRepo) GetEndingAuctions(ctx context.Context, before int64) (
 	return auctions, nil
 }
 
-func (r *mockAuctionRepo) IncrementViewCount(ctx context.Context, id uuid.UUID) error {
-	if auction, ok := r.auctions[id]; ok {
-		auction.ViewsCount++
+func (r *mockAuctionRepo) GetEndTimeLoad(ctx context.Context, from, to time.Time) (map[int64]int, error) {
+	load := make(map[int64]int)
+	for _, auction := range r.auctions {
+		if auction.Status == domain.AuctionStatusActive && !auction.EndTime.Before(from) && auction.EndTime.Before(to) {
+			load[auction.EndTime.Unix()]++
+		}
+	}
+	return load, nil
+}
+
+func (r *mockAuctionRepo) GetActiveEndTimes(ctx context.Context) (map[uuid.UUID]time.Time, error) {
+	ends := make(map[uuid.UUID]time.Time)
+	for _, auction := range r.auctions {
+		if auction.Status == domain.AuctionStatusActive {
+			ends[auction.ID] = auction.EndTime
+		}
+	}
+	return ends, nil
+}
+
+func (r *mockAuctionRepo) ExtendForVacation(ctx context.Context, sellerID uuid.UUID, from, until, newEnd time.Time) (int64, error) {
+	var extended int64
+	for _, auction := range r.auctions {
+		if auction.SellerID == sellerID && auction.Status == domain.AuctionStatusActive &&
+			!auction.EndTime.Before(from) && auction.EndTime.Before(until) {
+			auction.EndTime = newEnd
+			extended++
+		}
+	}
+	return extended, nil
+}
+
+func (r *mockAuctionRepo) ExtendEnding(ctx context.Context, from, until, newEnd time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, auction := range r.auctions {
+		if auction.Status == domain.AuctionStatusActive && !auction.EndTime.Before(from) && auction.EndTime.Before(until) {
+			auction.EndTime = newEnd
+			ids = append(ids, auction.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *mockAuctionRepo) ShiftEnding(ctx context.Context, from, until time.Time, by time.Duration) (map[uuid.UUID]time.Time, error) {
+	shifted := make(map[uuid.UUID]time.Time)
+	for _, auction := range r.auctions {
+		if auction.Status == domain.AuctionStatusActive && !auction.EndTime.Before(from) && auction.EndTime.Before(until) {
+			auction.EndTime = auction.EndTime.Add(by)
+			auction.Version++
+			shifted[auction.ID] = auction.EndTime
+		}
+	}
+	return shifted, nil
+}
+
+func (r *mockAuctionRepo) AddViews(ctx context.Context, views map[uuid.UUID]int64) error {
+	for id, count := range views {
+		if auction, ok := r.auctions[id]; ok {
+			auction.ViewsCount += int(count)
+		}
 	}
 	return nil
 }
@@ -110,14 +324,96 @@ func (r *mockAuctionRepo) UpdateStatus(ctx context.Context, id uuid.UUID, status
 	return nil
 }
 
-type mockAuctionImageRepo struct{}
+func (r *mockAuctionRepo) CloseIfActive(ctx context.Context, id uuid.UUID, expectedVersion int, status domain.AuctionStatus, winnerID *uuid.UUID, winningBidID *uuid.UUID) (bool, error) {
+	auction, ok := r.auctions[id]
+	if !ok || auction.Status != domain.AuctionStatusActive {
+		return false, nil
+	}
+	if auction.Version != expectedVersion {
+		return false, domain.ErrConcurrentBid
+	}
+	auction.Status = status
+	auction.WinnerID = winnerID
+	auction.WinningBidID = winningBidID
+	auction.Version++
+	return true, nil
+}
+
+func (r *mockAuctionRepo) UpdateCertification(ctx context.Context, id uuid.UUID, cert *domain.Certification) error {
+	if auction, ok := r.auctions[id]; ok && auction.Certification != nil &&
+		auction.Certification.Grader == cert.Grader && auction.Certification.Number == cert.Number {
+		auction.Certification = cert
+	}
+	return nil
+}
+
+func (r *mockAuctionRepo) GetItems(ctx context.Context, auctionID uuid.UUID) ([]domain.AuctionItem, error) {
+	if auction, ok := r.auctions[auctionID]; ok {
+		return auction.Items, nil
+	}
+	return nil, nil
+}
+
+// FindActiveByTitle matches active public auctions sharing any lower-cased
+// word with title
+func (r *mockAuctionRepo) FindActiveByTitle(ctx context.Context, title string, limit int) ([]domain.Auction, error) {
+	words := make(map[string]bool)
+	for _, word := range strings.Fields(strings.ToLower(title)) {
+		words[word] = true
+	}
+
+	auctions := make([]domain.Auction, 0)
+	for _, auction := range r.auctions {
+		if auction.Status != domain.AuctionStatusActive || auction.Visibility == domain.AuctionPrivate {
+			continue
+		}
+		for _, word := range strings.Fields(strings.ToLower(auction.Title)) {
+			if words[word] {
+				auctions = append(auctions, *auction)
+				break
+			}
+		}
+	}
+	if len(auctions) > limit {
+		auctions = auctions[:limit]
+	}
+	return auctions, nil
+}
+
+func (r *mockAuctionRepo) ReplaceItems(ctx context.Context, auctionID uuid.UUID, items []domain.AuctionItem) error {
+	for i := range items {
+		if items[i].ID == uuid.Nil {
+			items[i].ID = uuid.New()
+		}
+		items[i].AuctionID = auctionID
+		items[i].Position = i
+	}
+	if auction, ok := r.auctions[auctionID]; ok {
+		auction.Items = items
+	}
+	return nil
+}
+
+type mockAuctionImageRepo struct {
+	images []domain.AuctionImage
+}
 
 func (r *mockAuctionImageRepo) Create(ctx context.Context, image *domain.AuctionImage) error {
 	return nil
 }
 
 func (r *mockAuctionImageRepo) GetByAuctionID(ctx context.Context, auctionID uuid.UUID) ([]domain.AuctionImage, error) {
-	return nil, nil
+	var images []domain.AuctionImage
+	for _, img := range r.images {
+		if img.AuctionID == auctionID {
+			images = append(images, img)
+		}
+	}
+	return images, nil
+}
+
+func (r *mockAuctionImageRepo) GetFirstImageByAuctionIDs(ctx context.Context, auctionIDs []uuid.UUID) (map[uuid.UUID]domain.AuctionImage, error) {
+	return make(map[uuid.UUID]domain.AuctionImage), nil
 }
 
 func (r *mockAuctionImageRepo) Delete(ctx context.Context, id uuid.UUID) error {
@@ -132,13 +428,70 @@ func (r *mockAuctionImageRepo) UpdatePositions(ctx context.Context, auctionID uu
 	return nil
 }
 
+type mockShippingRepo struct {
+	options map[uuid.UUID][]domain.AuctionShipping
+}
+
+func newMockShippingRepo() *mockShippingRepo {
+	return &mockShippingRepo{
+		options: make(map[uuid.UUID][]domain.AuctionShipping),
+	}
+}
+
+func (r *mockShippingRepo) GetByAuctionID(ctx context.Context, auctionID uuid.UUID) ([]domain.AuctionShipping, error) {
+	return r.options[auctionID], nil
+}
+
+func (r *mockShippingRepo) GetByAuctionIDs(ctx context.Context, auctionIDs []uuid.UUID) (map[uuid.UUID][]domain.AuctionShipping, error) {
+	options := make(map[uuid.UUID][]domain.AuctionShipping)
+	for _, id := range auctionIDs {
+		if opts, ok := r.options[id]; ok {
+			options[id] = opts
+		}
+	}
+	return options, nil
+}
+
+func (r *mockShippingRepo) ReplaceForAuction(ctx context.Context, auctionID uuid.UUID, options []domain.AuctionShipping) error {
+	for i := range options {
+		if options[i].ID == uuid.Nil {
+			options[i].ID = uuid.New()
+		}
+		options[i].AuctionID = auctionID
+	}
+	r.options[auctionID] = options
+	return nil
+}
+
+type mockAuctionStatsRepo struct{}
+
+func (r *mockAuctionStatsRepo) GetViewsByDay(ctx context.Context, auctionID uuid.UUID) ([]domain.DailyCount, error) {
+	return []domain.DailyCount{}, nil
+}
+
+func (r *mockAuctionStatsRepo) GetUniqueBidderCount(ctx context.Context, auctionID uuid.UUID) (int, error) {
+	return 0, nil
+}
+
+func (r *mockAuctionStatsRepo) GetBidHistory(ctx context.Context, auctionID uuid.UUID) ([]domain.BidPoint, error) {
+	return []domain.BidPoint{}, nil
+}
+
+func (r *mockAuctionStatsRepo) GetShareStats(ctx context.Context, auctionID uuid.UUID) ([]domain.ListingShareStats, error) {
+	return []domain.ListingShareStats{}, nil
+}
+
 type mockCategoryRepo struct {
-	categories map[uuid.UUID]*domain.Category
+	categories   map[uuid.UUID]*domain.Category
+	translations map[uuid.UUID]map[string]domain.CategoryTranslation
+	rules        map[uuid.UUID]*domain.CategoryListingRules
 }
 
 func newMockCategoryRepo() *mockCategoryRepo {
 	repo := &mockCategoryRepo{
-		categories: make(map[uuid.UUID]*domain.Category),
+		categories:   make(map[uuid.UUID]*domain.Category),
+		translations: make(map[uuid.UUID]map[string]domain.CategoryTranslation),
+		rules:        make(map[uuid.UUID]*domain.CategoryListingRules),
 	}
 	// Add some default categories
 	cat1 := &domain.Category{
@@ -202,6 +555,67 @@ func (r *mockCategoryRepo) GetWithAuctionCounts(ctx context.Context) ([]domain.C
 	return r.List(ctx)
 }
 
+func (r *mockCategoryRepo) ListTranslations(ctx context.Context, categoryID uuid.UUID) ([]domain.CategoryTranslation, error) {
+	translations := make([]domain.CategoryTranslation, 0)
+	for _, translation := range r.translations[categoryID] {
+		translations = append(translations, translation)
+	}
+	return translations, nil
+}
+
+func (r *mockCategoryRepo) GetTranslations(ctx context.Context, categoryIDs []uuid.UUID, languages []string) ([]domain.CategoryTranslation, error) {
+	translations := make([]domain.CategoryTranslation, 0)
+	for _, id := range categoryIDs {
+		for _, language := range languages {
+			if translation, ok := r.translations[id][language]; ok {
+				translations = append(translations, translation)
+			}
+		}
+	}
+	return translations, nil
+}
+
+func (r *mockCategoryRepo) SetTranslation(ctx context.Context, translation *domain.CategoryTranslation) error {
+	if r.translations[translation.CategoryID] == nil {
+		r.translations[translation.CategoryID] = make(map[string]domain.CategoryTranslation)
+	}
+	translation.UpdatedAt = time.Now()
+	r.translations[translation.CategoryID][translation.Language] = *translation
+	return nil
+}
+
+func (r *mockCategoryRepo) DeleteTranslation(ctx context.Context, categoryID uuid.UUID, language string) error {
+	if _, ok := r.translations[categoryID][language]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.translations[categoryID], language)
+	return nil
+}
+
+func (r *mockCategoryRepo) GetListingRules(ctx context.Context, categoryID uuid.UUID) (*domain.CategoryListingRules, error) {
+	rules, ok := r.rules[categoryID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	found := *rules
+	return &found, nil
+}
+
+func (r *mockCategoryRepo) SetListingRules(ctx context.Context, rules *domain.CategoryListingRules) error {
+	rules.UpdatedAt = time.Now()
+	stored := *rules
+	r.rules[rules.CategoryID] = &stored
+	return nil
+}
+
+func (r *mockCategoryRepo) DeleteListingRules(ctx context.Context, categoryID uuid.UUID) error {
+	if _, ok := r.rules[categoryID]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.rules, categoryID)
+	return nil
+}
+
 func TestAuctionHandler_Create(t *testing.T) {
 	auctionRepo := newMockAuctionRepo()
 	categoryRepo := newMockCategoryRepo()
@@ -211,8 +625,17 @@ func TestAuctionHandler_Create(t *testing.T) {
 	auctionService := service.NewAuctionService(
 		auctionRepo,
 		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
 		categoryRepo,
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
 		nil, // no S3 for tests
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	r := createTestRouter()
@@ -244,6 +667,93 @@ func TestAuctionHandler_Create(t *testing.T) {
 			wantStatus: http.StatusCreated,
 			wantErr:    false,
 		},
+		{
+			name: "auction with shipping options",
+			body: domain.CreateAuctionRequest{
+				Title:         "Test Auction",
+				StartingPrice: "100.00",
+				StartTime:     time.Now().Add(1 * time.Hour),
+				EndTime:       time.Now().Add(24 * time.Hour),
+				ShippingOptions: []domain.ShippingOptionRequest{
+					{Method: "flat_rate", Cost: stringPtr("7.50"), Regions: []string{"US"}},
+					{Method: "pickup_only"},
+				},
+			},
+			token:      token,
+			wantStatus: http.StatusCreated,
+			wantErr:    false,
+		},
+		{
+			name: "flat rate shipping without cost",
+			body: domain.CreateAuctionRequest{
+				Title:         "Test Auction",
+				StartingPrice: "100.00",
+				StartTime:     time.Now().Add(1 * time.Hour),
+				EndTime:       time.Now().Add(24 * time.Hour),
+				ShippingOptions: []domain.ShippingOptionRequest{
+					{Method: "flat_rate"},
+				},
+			},
+			token:      token,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name: "unknown shipping method",
+			body: domain.CreateAuctionRequest{
+				Title:         "Test Auction",
+				StartingPrice: "100.00",
+				StartTime:     time.Now().Add(1 * time.Hour),
+				EndTime:       time.Now().Add(24 * time.Hour),
+				ShippingOptions: []domain.ShippingOptionRequest{
+					{Method: "teleport"},
+				},
+			},
+			token:      token,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name: "graded card with certification",
+			body: domain.CreateAuctionRequest{
+				Title:         "Test Auction",
+				StartingPrice: "100.00",
+				StartTime:     time.Now().Add(1 * time.Hour),
+				EndTime:       time.Now().Add(24 * time.Hour),
+				CertGrader:    stringPtr("psa"),
+				CertNumber:    stringPtr("12345678"),
+			},
+			token:      token,
+			wantStatus: http.StatusCreated,
+			wantErr:    false,
+		},
+		{
+			name: "certification number without grader",
+			body: domain.CreateAuctionRequest{
+				Title:         "Test Auction",
+				StartingPrice: "100.00",
+				StartTime:     time.Now().Add(1 * time.Hour),
+				EndTime:       time.Now().Add(24 * time.Hour),
+				CertNumber:    stringPtr("12345678"),
+			},
+			token:      token,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name: "unsupported grader",
+			body: domain.CreateAuctionRequest{
+				Title:         "Test Auction",
+				StartingPrice: "100.00",
+				StartTime:     time.Now().Add(1 * time.Hour),
+				EndTime:       time.Now().Add(24 * time.Hour),
+				CertGrader:    stringPtr("cgc"),
+				CertNumber:    stringPtr("12345678"),
+			},
+			token:      token,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
 		{
 			name: "missing title",
 			body: domain.CreateAuctionRequest{
@@ -300,9 +810,100 @@ func TestAuctionHandler_Create(t *testing.T) {
 	}
 }
 
-func TestAuctionHandler_List(t *testing.T) {
+func TestAuctionHandler_LotItems(t *testing.T) {
 	auctionRepo := newMockAuctionRepo()
-	categoryRepo := newMockCategoryRepo()
+	imageRepo := &mockAuctionImageRepo{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		imageRepo,
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil, // no S3 for tests
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	auctionHandler := handler.NewAuctionHandler(auctionService)
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions", auctionHandler.Create)
+	r.With(authMiddleware.RequireAuth).Put("/api/auctions/{id}", auctionHandler.Update)
+	r.With(authMiddleware.OptionalAuth).Get("/api/auctions/{id}", auctionHandler.GetByID)
+
+	sellerID := uuid.New()
+	token, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+
+	lot := func(items ...domain.AuctionItemRequest) domain.CreateAuctionRequest {
+		return domain.CreateAuctionRequest{
+			Title:         "Base Set Starter Lot",
+			StartingPrice: "50.00",
+			StartTime:     time.Now().Add(1 * time.Hour),
+			EndTime:       time.Now().Add(24 * time.Hour),
+			Items:         items,
+		}
+	}
+
+	rr := makeRequest(t, r, "POST", "/api/auctions", lot(domain.AuctionItemRequest{Title: "x"}), token)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected an item with a short title to be rejected, got %v", rr.Code)
+	}
+
+	rr = makeRequest(t, r, "POST", "/api/auctions", lot(domain.AuctionItemRequest{Title: "Charizard", ImageIDs: []uuid.UUID{uuid.New()}}), token)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected images of another auction to be rejected, got %v", rr.Code)
+	}
+
+	rr = makeRequest(t, r, "POST", "/api/auctions", lot(
+		domain.AuctionItemRequest{Title: "Charizard", Condition: stringPtr("good")},
+		domain.AuctionItemRequest{Title: "Blastoise"},
+	), token)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusCreated)
+	}
+	auctionID := parseResponse(t, rr).Data.(map[string]interface{})["id"].(string)
+	id, _ := uuid.Parse(auctionID)
+
+	front := domain.AuctionImage{ID: uuid.New(), AuctionID: id}
+	back := domain.AuctionImage{ID: uuid.New(), AuctionID: id}
+	imageRepo.images = []domain.AuctionImage{front, back}
+
+	update := domain.UpdateAuctionRequest{Items: []domain.AuctionItemRequest{
+		{Title: "Charizard", ImageIDs: []uuid.UUID{front.ID}},
+		{Title: "Venusaur", ImageIDs: []uuid.UUID{front.ID, back.ID}},
+	}}
+	rr = makeRequest(t, r, "PUT", "/api/auctions/"+auctionID, update, token)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected an image shared by two items to be rejected, got %v", rr.Code)
+	}
+
+	update.Items[1].ImageIDs = []uuid.UUID{back.ID}
+	rr = makeRequest(t, r, "PUT", "/api/auctions/"+auctionID, update, token)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+
+	rr = makeRequest(t, r, "GET", "/api/auctions/"+auctionID, nil, "")
+	items := parseResponse(t, rr).Data.(map[string]interface{})["items"].([]interface{})
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	second := items[1].(map[string]interface{})
+	if second["title"] != "Venusaur" || second["image_ids"].([]interface{})[0] != back.ID.String() {
+		t.Errorf("expected the second item to show the back image, got %v", second)
+	}
+}
+
+func TestAuctionHandler_List(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	categoryRepo := newMockCategoryRepo()
 
 	// Create some test auctions
 	userID := uuid.New()
@@ -323,7 +924,16 @@ func TestAuctionHandler_List(t *testing.T) {
 	auctionService := service.NewAuctionService(
 		auctionRepo,
 		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
 		categoryRepo,
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
 		nil,
 	)
 
@@ -374,6 +984,512 @@ func TestAuctionHandler_List(t *testing.T) {
 	}
 }
 
+func TestAuctionHandler_ListFilters(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+
+	sellerID := uuid.New()
+	buyNow := decimal.NewFromFloat(150)
+	reserve := decimal.NewFromFloat(120)
+	mint := domain.ConditionNew
+
+	newAuction := func(title string, endsIn time.Duration) *domain.Auction {
+		auction := &domain.Auction{
+			SellerID:      sellerID,
+			Title:         title,
+			StartingPrice: decimal.NewFromFloat(100),
+			CurrentPrice:  decimal.NewFromFloat(100),
+			BidIncrement:  decimal.NewFromFloat(1),
+			StartTime:     time.Now(),
+			EndTime:       time.Now().Add(endsIn),
+			Status:        domain.AuctionStatusActive,
+		}
+		auctionRepo.Create(context.Background(), auction)
+		return auction
+	}
+
+	endingBuyNow := newAuction("Ending with buy now", 30*time.Minute)
+	endingBuyNow.BuyNowPrice = &buyNow
+	endingBuyNow.Condition = &mint
+
+	endingBidOn := newAuction("Ending, buy now gone", 30*time.Minute)
+	endingBidOn.BuyNowPrice = &buyNow
+	endingBidOn.BidCount = 1
+
+	laterBuyNow := newAuction("Later with buy now", 48*time.Hour)
+	laterBuyNow.BuyNowPrice = &buyNow
+	laterBuyNow.ReservePrice = &reserve
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	auctionHandler := handler.NewAuctionHandler(auctionService)
+
+	r.Get("/api/auctions", auctionHandler.List)
+
+	tests := []struct {
+		name        string
+		queryParams string
+		wantStatus  int
+		wantIDs     []uuid.UUID
+	}{
+		{
+			name:        "ending soon with buy now",
+			queryParams: "?ends_within=1h&has_buy_now=true",
+			wantStatus:  http.StatusOK,
+			wantIDs:     []uuid.UUID{endingBuyNow.ID},
+		},
+		{
+			name:        "ending soon without buy now",
+			queryParams: "?ends_within=1h&has_buy_now=false",
+			wantStatus:  http.StatusOK,
+			wantIDs:     []uuid.UUID{endingBidOn.ID},
+		},
+		{
+			name:        "with reserve",
+			queryParams: "?has_reserve=true",
+			wantStatus:  http.StatusOK,
+			wantIDs:     []uuid.UUID{laterBuyNow.ID},
+		},
+		{
+			name:        "by condition",
+			queryParams: "?condition=new",
+			wantStatus:  http.StatusOK,
+			wantIDs:     []uuid.UUID{endingBuyNow.ID},
+		},
+		{
+			name:        "invalid ends_within",
+			queryParams: "?ends_within=soon",
+			wantStatus:  http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := makeRequest(t, r, "GET", "/api/auctions"+tt.queryParams, nil, "")
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			response := parseResponse(t, rr)
+			data, _ := json.Marshal(response.Data)
+			var auctions []domain.Auction
+			json.Unmarshal(data, &auctions)
+
+			if len(auctions) != len(tt.wantIDs) {
+				t.Fatalf("expected %d auctions, got %d", len(tt.wantIDs), len(auctions))
+			}
+			for i, id := range tt.wantIDs {
+				if auctions[i].ID != id {
+					t.Errorf("expected auction %s, got %s", id, auctions[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestAuctionHandler_Facets(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+
+	cardsID := uuid.New()
+	coinsID := uuid.New()
+	mint := domain.ConditionNew
+	used := domain.ConditionGood
+
+	newAuction := func(categoryID uuid.UUID, condition *domain.ItemCondition, price float64) {
+		auctionRepo.Create(context.Background(), &domain.Auction{
+			SellerID:      uuid.New(),
+			CategoryID:    &categoryID,
+			Title:         "Facet auction",
+			Condition:     condition,
+			StartingPrice: decimal.NewFromFloat(price),
+			CurrentPrice:  decimal.NewFromFloat(price),
+			BidIncrement:  decimal.NewFromFloat(1),
+			StartTime:     time.Now(),
+			EndTime:       time.Now().Add(24 * time.Hour),
+			Status:        domain.AuctionStatusActive,
+		})
+	}
+	newAuction(cardsID, &mint, 10)
+	newAuction(cardsID, &used, 60)
+	newAuction(cardsID, nil, 2000)
+	newAuction(coinsID, &mint, 30)
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	auctionHandler := handler.NewAuctionHandler(auctionService)
+
+	r.Get("/api/auctions/facets", auctionHandler.Facets)
+
+	rr := makeRequest(t, r, "GET", "/api/auctions/facets?category_id="+cardsID.String()+"&condition=new", nil, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+
+	var facets domain.AuctionFacets
+	data, _ := json.Marshal(parseResponse(t, rr).Data)
+	if err := json.Unmarshal(data, &facets); err != nil {
+		t.Fatalf("failed to decode facets: %v", err)
+	}
+
+	if facets.Total != 1 {
+		t.Errorf("expected 1 matching auction, got %d", facets.Total)
+	}
+
+	// Category counts ignore the category filter but keep the condition one
+	categories := make(map[uuid.UUID]int)
+	for _, facet := range facets.Categories {
+		categories[facet.CategoryID] = facet.Count
+	}
+	if categories[cardsID] != 1 || categories[coinsID] != 1 {
+		t.Errorf("expected one new auction in each category, got %v", categories)
+	}
+
+	conditions := make(map[domain.ItemCondition]int)
+	for _, facet := range facets.Conditions {
+		conditions[facet.Condition] = facet.Count
+	}
+	if len(conditions) != 2 || conditions[mint] != 1 || conditions[used] != 1 {
+		t.Errorf("expected one new and one good card, got %v", conditions)
+	}
+
+	if len(facets.PriceBuckets) != len(domain.PriceFacetBounds)+1 {
+		t.Fatalf("expected %d price buckets, got %d", len(domain.PriceFacetBounds)+1, len(facets.PriceBuckets))
+	}
+	if facets.PriceBuckets[0].Count != 1 || facets.PriceBuckets[len(facets.PriceBuckets)-1].Max != nil {
+		t.Errorf("expected the new card in the first bucket and an open last bucket, got %v", facets.PriceBuckets)
+	}
+
+	rr = makeRequest(t, r, "GET", "/api/auctions/facets?ends_within=soon", nil, "")
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected invalid filters to be rejected, got %v", rr.Code)
+	}
+}
+
+func TestAuctionHandler_ListSold(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+
+	cardsID := uuid.New()
+	coinsID := uuid.New()
+	winnerID := uuid.New()
+
+	newAuction := func(categoryID uuid.UUID, status domain.AuctionStatus, price float64, endedAt time.Time) *domain.Auction {
+		auction := &domain.Auction{
+			SellerID:      uuid.New(),
+			CategoryID:    &categoryID,
+			Title:         "Sold auction",
+			StartingPrice: decimal.NewFromFloat(1),
+			CurrentPrice:  decimal.NewFromFloat(price),
+			BidIncrement:  decimal.NewFromFloat(1),
+			StartTime:     endedAt.Add(-7 * 24 * time.Hour),
+			EndTime:       endedAt,
+			Status:        status,
+		}
+		if status == domain.AuctionStatusCompleted {
+			auction.WinnerID = &winnerID
+		}
+		auctionRepo.Create(context.Background(), auction)
+		return auction
+	}
+	may := func(day int) time.Time { return time.Date(2024, time.May, day, 15, 0, 0, 0, time.UTC) }
+
+	early := newAuction(cardsID, domain.AuctionStatusCompleted, 40, may(1))
+	late := newAuction(cardsID, domain.AuctionStatusCompleted, 80, may(20))
+	newAuction(coinsID, domain.AuctionStatusCompleted, 500, may(10))
+	newAuction(cardsID, domain.AuctionStatusUnsold, 10, may(10))
+	newAuction(cardsID, domain.AuctionStatusActive, 60, time.Now().Add(time.Hour))
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	auctionHandler := handler.NewAuctionHandler(auctionService)
+
+	r.Get("/api/auctions/sold", auctionHandler.ListSold)
+
+	tests := []struct {
+		name        string
+		queryParams string
+		wantStatus  int
+		wantIDs     []uuid.UUID
+		wantAverage string
+	}{
+		{
+			name:        "category",
+			queryParams: "?category_id=" + cardsID.String(),
+			wantStatus:  http.StatusOK,
+			wantIDs:     []uuid.UUID{early.ID, late.ID},
+			wantAverage: "60",
+		},
+		{
+			name:        "sold_to includes the whole day",
+			queryParams: "?category_id=" + cardsID.String() + "&sold_from=2024-05-01&sold_to=2024-05-20",
+			wantStatus:  http.StatusOK,
+			wantIDs:     []uuid.UUID{early.ID, late.ID},
+			wantAverage: "60",
+		},
+		{
+			name:        "date range",
+			queryParams: "?category_id=" + cardsID.String() + "&sold_from=2024-05-02T00:00:00Z",
+			wantStatus:  http.StatusOK,
+			wantIDs:     []uuid.UUID{late.ID},
+			wantAverage: "80",
+		},
+		{
+			name:        "price",
+			queryParams: "?max_price=50",
+			wantStatus:  http.StatusOK,
+			wantIDs:     []uuid.UUID{early.ID},
+			wantAverage: "40",
+		},
+		{
+			name:        "invalid date",
+			queryParams: "?sold_from=last-week",
+			wantStatus:  http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := makeRequest(t, r, "GET", "/api/auctions/sold"+tt.queryParams, nil, "")
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var result domain.SoldListingResponse
+			data, _ := json.Marshal(parseResponse(t, rr).Data)
+			if err := json.Unmarshal(data, &result); err != nil {
+				t.Fatalf("failed to decode sold listings: %v", err)
+			}
+
+			got := make(map[uuid.UUID]bool)
+			for _, sale := range result.Sales {
+				got[sale.AuctionID] = true
+			}
+			if len(got) != len(tt.wantIDs) {
+				t.Errorf("expected %d sales, got %d", len(tt.wantIDs), len(got))
+			}
+			for _, id := range tt.wantIDs {
+				if !got[id] {
+					t.Errorf("expected sale %s in results", id)
+				}
+			}
+
+			if result.Stats.Count != len(tt.wantIDs) || result.Stats.Average == nil || result.Stats.Average.String() != tt.wantAverage {
+				t.Errorf("expected %d sales averaging %s, got %+v", len(tt.wantIDs), tt.wantAverage, result.Stats)
+			}
+		})
+	}
+}
+
+func TestAuctionHandler_ListDiscoverySeed(t *testing.T) {
+	auctionService := service.NewAuctionService(
+		newMockAuctionRepo(),
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	r := createTestRouter()
+	auctionHandler := handler.NewAuctionHandler(auctionService)
+
+	r.With(authMiddleware.OptionalAuth).Get("/api/auctions", auctionHandler.List)
+
+	viewerID := uuid.New()
+	viewerToken, _ := jwtManager.GenerateAccessToken(viewerID, "user")
+
+	seedOf := func(query, token string) string {
+		rr := makeRequest(t, r, "GET", "/api/auctions"+query, nil, token)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+		response := parseResponse(t, rr)
+		if response.Meta == nil {
+			return ""
+		}
+		return response.Meta.Seed
+	}
+
+	anonymous := seedOf("?sort=discovery", "")
+	if anonymous == "" {
+		t.Fatal("expected a seed for an anonymous discovery listing")
+	}
+	if other := seedOf("?sort=discovery", ""); other == anonymous {
+		t.Error("expected each anonymous session to get its own seed")
+	}
+	if got := seedOf("?sort=discovery&page=2&seed="+anonymous, ""); got != anonymous {
+		t.Errorf("expected seed %q to be kept, got %q", anonymous, got)
+	}
+
+	first := seedOf("?sort=discovery", viewerToken)
+	if second := seedOf("?sort=discovery&page=2", viewerToken); first == "" || first != second {
+		t.Errorf("expected a stable seed for a signed-in viewer, got %q then %q", first, second)
+	}
+
+	if got := seedOf("?sort=newest", ""); got != "" {
+		t.Errorf("expected no seed outside discovery, got %q", got)
+	}
+}
+
+func TestAuctionHandler_ListViewerState(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	watchlistRepo := newMockWatchlistRepo()
+	bidRepo := newMockBidRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	sellerID := uuid.New()
+	viewerID := uuid.New()
+	rivalID := uuid.New()
+
+	newAuction := func(title string) *domain.Auction {
+		auction := &domain.Auction{
+			SellerID:      sellerID,
+			Title:         title,
+			StartingPrice: decimal.NewFromFloat(100),
+			CurrentPrice:  decimal.NewFromFloat(100),
+			BidIncrement:  decimal.NewFromFloat(1),
+			StartTime:     time.Now(),
+			EndTime:       time.Now().Add(24 * time.Hour),
+			Status:        domain.AuctionStatusActive,
+		}
+		auctionRepo.Create(context.Background(), auction)
+		return auction
+	}
+	leading := newAuction("Leading")
+	outbid := newAuction("Outbid")
+	untouched := newAuction("Untouched")
+
+	watchlistRepo.Add(context.Background(), &domain.WatchlistItem{UserID: viewerID, AuctionID: leading.ID})
+	bidRepo.Create(context.Background(), &domain.Bid{AuctionID: leading.ID, BidderID: viewerID, Amount: decimal.NewFromFloat(120)})
+	bidRepo.Create(context.Background(), &domain.Bid{AuctionID: outbid.ID, BidderID: viewerID, Amount: decimal.NewFromFloat(110)})
+	bidRepo.Create(context.Background(), &domain.Bid{AuctionID: outbid.ID, BidderID: rivalID, Amount: decimal.NewFromFloat(130)})
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		watchlistRepo,
+		bidRepo,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	r.With(authMiddleware.OptionalAuth).Get("/api/auctions", handler.NewAuctionHandler(auctionService).List)
+
+	list := func(token string) map[string]map[string]interface{} {
+		rr := makeRequest(t, r, "GET", "/api/auctions", nil, token)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		byTitle := make(map[string]map[string]interface{})
+		for _, item := range parseResponse(t, rr).Data.([]interface{}) {
+			auction := item.(map[string]interface{})
+			byTitle[auction["title"].(string)] = auction
+		}
+		return byTitle
+	}
+
+	// Anonymous viewers get no viewer state
+	for title, auction := range list("") {
+		if _, ok := auction["is_watching"]; ok {
+			t.Errorf("expected no viewer state on %s, got %v", title, auction)
+		}
+	}
+
+	token, _ := jwtManager.GenerateAccessToken(viewerID, "user")
+	auctions := list(token)
+
+	tests := []struct {
+		title        string
+		isWatching   bool
+		myMaxBid     interface{}
+		amHighBidder bool
+	}{
+		{title: leading.Title, isWatching: true, myMaxBid: "120", amHighBidder: true},
+		{title: outbid.Title, isWatching: false, myMaxBid: "110", amHighBidder: false},
+		{title: untouched.Title, isWatching: false, myMaxBid: nil, amHighBidder: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			auction := auctions[tt.title]
+			if auction["is_watching"] != tt.isWatching || auction["my_max_bid"] != tt.myMaxBid || auction["am_high_bidder"] != tt.amHighBidder {
+				t.Errorf("unexpected viewer state: is_watching=%v my_max_bid=%v am_high_bidder=%v",
+					auction["is_watching"], auction["my_max_bid"], auction["am_high_bidder"])
+			}
+		})
+	}
+}
+
 func TestAuctionHandler_GetByID(t *testing.T) {
 	auctionRepo := newMockAuctionRepo()
 	categoryRepo := newMockCategoryRepo()
@@ -395,7 +1511,16 @@ func TestAuctionHandler_GetByID(t *testing.T) {
 	auctionService := service.NewAuctionService(
 		auctionRepo,
 		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
 		categoryRepo,
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
 		nil,
 	)
 
@@ -449,13 +1574,366 @@ func TestAuctionHandler_GetByID(t *testing.T) {
 	}
 }
 
+// bidHistoryStatsRepo serves a fixed bid history
+type bidHistoryStatsRepo struct {
+	mockAuctionStatsRepo
+	bids []domain.BidPoint
+}
+
+func (r *bidHistoryStatsRepo) GetBidHistory(ctx context.Context, auctionID uuid.UUID) ([]domain.BidPoint, error) {
+	return r.bids, nil
+}
+
+func TestAuctionHandler_GetByIDPriceEstimate(t *testing.T) {
+	// Hands out copies, as the database does, so one viewer's estimate
+	// can't leak into another's response
+	auctionRepo := &lockedAuctionRepo{mockAuctionRepo: newMockAuctionRepo(), mu: &sync.Mutex{}}
+	bidRepo := newMockBidRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	categoryID := uuid.New()
+	for _, price := range []float64{300, 320, 340} {
+		winnerID := uuid.New()
+		auctionRepo.Create(context.Background(), &domain.Auction{
+			SellerID:     uuid.New(),
+			CategoryID:   &categoryID,
+			Title:        "Charizard Base Set",
+			CurrentPrice: decimal.NewFromFloat(price),
+			StartTime:    time.Now().Add(-8 * 24 * time.Hour),
+			EndTime:      time.Now().Add(-24 * time.Hour),
+			Status:       domain.AuctionStatusCompleted,
+			WinnerID:     &winnerID,
+		})
+	}
+
+	sellerID := uuid.New()
+	auction := &domain.Auction{
+		SellerID:      sellerID,
+		CategoryID:    &categoryID,
+		Title:         "Charizard Base Set",
+		StartingPrice: decimal.NewFromFloat(90),
+		CurrentPrice:  decimal.NewFromFloat(100),
+		BidIncrement:  decimal.NewFromFloat(1),
+		BidCount:      2,
+		WatchCount:    4,
+		StartTime:     time.Now().Add(-12 * time.Hour),
+		EndTime:       time.Now().Add(12 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+	}
+	auctionRepo.Create(context.Background(), auction)
+
+	proxyBidder, bigProxyBidder, plainBidder := uuid.New(), uuid.New(), uuid.New()
+	bid := func(bidderID uuid.UUID, amount float64, maxAutoBid *decimal.Decimal) {
+		bidRepo.Create(context.Background(), &domain.Bid{
+			ID:         uuid.New(),
+			AuctionID:  auction.ID,
+			BidderID:   bidderID,
+			Amount:     decimal.NewFromFloat(amount),
+			MaxAutoBid: maxAutoBid,
+			CreatedAt:  time.Now(),
+		})
+	}
+	smallMax, bigMax := decimal.NewFromFloat(150), decimal.NewFromFloat(500)
+	bid(proxyBidder, 95, &smallMax)
+	bid(bigProxyBidder, 100, &bigMax)
+	bid(plainBidder, 98, nil)
+
+	statsRepo := &bidHistoryStatsRepo{bids: []domain.BidPoint{
+		{Amount: decimal.NewFromFloat(95), CreatedAt: time.Now().Add(-3 * time.Hour)},
+		{Amount: decimal.NewFromFloat(100), CreatedAt: time.Now().Add(-1 * time.Hour)},
+	}}
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		statsRepo,
+		newMockWatchlistRepo(),
+		bidRepo,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	r.With(authMiddleware.OptionalAuth).Get("/api/auctions/{id}", handler.NewAuctionHandler(auctionService).GetByID)
+
+	getAuction := func(t *testing.T, userID uuid.UUID) map[string]interface{} {
+		t.Helper()
+		token := ""
+		if userID != uuid.Nil {
+			token, _ = jwtManager.GenerateAccessToken(userID, "user")
+		}
+		rr := makeRequest(t, r, "GET", "/api/auctions/"+auction.ID.String(), nil, token)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		data, _ := parseResponse(t, rr).Data.(map[string]interface{})
+		return data
+	}
+
+	t.Run("seller sees the range", func(t *testing.T) {
+		data := getAuction(t, sellerID)
+		estimate, ok := data["price_estimate"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("expected a price estimate, got %v", data["price_estimate"])
+		}
+
+		low, _ := decimal.NewFromString(estimate["low"].(string))
+		high, _ := decimal.NewFromString(estimate["high"].(string))
+		// Comparables selling around 320 pull it well above the current price
+		if !low.GreaterThan(decimal.NewFromFloat(150)) || !high.GreaterThan(low) || high.GreaterThan(decimal.NewFromFloat(340)) {
+			t.Errorf("got range %s to %s", low, high)
+		}
+		if estimate["comparables"] != float64(3) {
+			t.Errorf("expected 3 comparables, got %v", estimate["comparables"])
+		}
+		if _, ok := data["max_bid_unlikely_to_win"]; ok {
+			t.Error("the seller has no max bid to judge")
+		}
+	})
+
+	t.Run("proxy bidders get a hint", func(t *testing.T) {
+		data := getAuction(t, proxyBidder)
+		if data["max_bid_unlikely_to_win"] != true {
+			t.Errorf("expected a max of 150 to be unlikely to win, got %v", data["max_bid_unlikely_to_win"])
+		}
+		if _, ok := data["price_estimate"]; ok {
+			t.Error("bidders must not see the seller's estimate")
+		}
+
+		if data := getAuction(t, bigProxyBidder); data["max_bid_unlikely_to_win"] != false {
+			t.Errorf("expected a max of 500 to be in the running, got %v", data["max_bid_unlikely_to_win"])
+		}
+	})
+
+	t.Run("others see neither", func(t *testing.T) {
+		for name, userID := range map[string]uuid.UUID{"plain bidder": plainBidder, "signed out": uuid.Nil} {
+			data := getAuction(t, userID)
+			_, hasEstimate := data["price_estimate"]
+			_, hasHint := data["max_bid_unlikely_to_win"]
+			if hasEstimate || hasHint {
+				t.Errorf("%s: got estimate %v, hint %v", name, hasEstimate, hasHint)
+			}
+		}
+	})
+}
+
+func TestAuctionHandler_GetStats(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	sellerID := uuid.New()
+	auction := &domain.Auction{
+		SellerID:      sellerID,
+		Title:         "Test Auction",
+		StartingPrice: decimal.NewFromFloat(100),
+		CurrentPrice:  decimal.NewFromFloat(100),
+		BidIncrement:  decimal.NewFromFloat(1),
+		StartTime:     time.Now(),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+		ViewsCount:    12,
+		WatchCount:    3,
+	}
+	auctionRepo.Create(context.Background(), auction)
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	auctionHandler := handler.NewAuctionHandler(auctionService)
+
+	r.With(authMiddleware.RequireAuth).Get("/api/auctions/{id}/stats", auctionHandler.GetStats)
+
+	sellerToken, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+	otherToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+
+	tests := []struct {
+		name       string
+		auctionID  string
+		token      string
+		wantStatus int
+		wantErr    bool
+	}{
+		{
+			name:       "seller gets stats",
+			auctionID:  auction.ID.String(),
+			token:      sellerToken,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:       "other user forbidden",
+			auctionID:  auction.ID.String(),
+			token:      otherToken,
+			wantStatus: http.StatusForbidden,
+			wantErr:    true,
+		},
+		{
+			name:       "non-existent auction",
+			auctionID:  uuid.New().String(),
+			token:      sellerToken,
+			wantStatus: http.StatusNotFound,
+			wantErr:    true,
+		},
+		{
+			name:       "unauthorized",
+			auctionID:  auction.ID.String(),
+			token:      "",
+			wantStatus: http.StatusUnauthorized,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := makeRequest(t, r, "GET", "/api/auctions/"+tt.auctionID+"/stats", nil, tt.token)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+
+			response := parseResponse(t, rr)
+			if tt.wantErr && response.Success {
+				t.Errorf("expected error but got success")
+			}
+			if !tt.wantErr && !response.Success {
+				t.Errorf("expected success but got error: %v", response.Error)
+			}
+		})
+	}
+}
+
+func TestAuctionHandler_SuggestEndTime(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	crowded := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	quiet := crowded.Add(1 * time.Hour)
+	for i := 0; i < service.EndTimeSlotCapacity; i++ {
+		auctionRepo.Create(context.Background(), &domain.Auction{
+			SellerID:      uuid.New(),
+			Title:         "Crowded Auction",
+			StartingPrice: decimal.NewFromFloat(10),
+			CurrentPrice:  decimal.NewFromFloat(10),
+			BidIncrement:  decimal.NewFromFloat(1),
+			StartTime:     time.Now(),
+			EndTime:       crowded,
+			Status:        domain.AuctionStatusActive,
+		})
+	}
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	auctionHandler := handler.NewAuctionHandler(auctionService)
+
+	r.With(authMiddleware.RequireAuth).Get("/api/auctions/end-time-suggestion", auctionHandler.SuggestEndTime)
+
+	token, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+
+	tests := []struct {
+		name         string
+		endTime      string
+		wantStatus   int
+		wantAdjusted bool
+	}{
+		{
+			name:         "quiet second is kept",
+			endTime:      quiet.Format(time.RFC3339),
+			wantStatus:   http.StatusOK,
+			wantAdjusted: false,
+		},
+		{
+			name:         "crowded second is spread",
+			endTime:      crowded.Format(time.RFC3339),
+			wantStatus:   http.StatusOK,
+			wantAdjusted: true,
+		},
+		{
+			name:       "invalid end time",
+			endTime:    "tomorrow",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := makeRequest(t, r, "GET", "/api/auctions/end-time-suggestion?end_time="+url.QueryEscape(tt.endTime), nil, token)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			response := parseResponse(t, rr)
+			data, _ := response.Data.(map[string]interface{})
+			if adjusted, _ := data["adjusted"].(bool); adjusted != tt.wantAdjusted {
+				t.Errorf("expected adjusted=%v, got %v", tt.wantAdjusted, data["adjusted"])
+			}
+			if tt.wantAdjusted {
+				suggested, err := time.Parse(time.RFC3339, data["suggested_end_time"].(string))
+				if err != nil || !suggested.After(crowded) || suggested.After(crowded.Add(service.EndTimeSpreadWindow)) {
+					t.Errorf("suggested end time %v outside spread window", data["suggested_end_time"])
+				}
+			}
+		})
+	}
+}
+
 func TestAuctionHandler_GetCategories(t *testing.T) {
 	categoryRepo := newMockCategoryRepo()
 
 	auctionService := service.NewAuctionService(
 		newMockAuctionRepo(),
 		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
 		categoryRepo,
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
 		nil,
 	)
 
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type AuctionInviteHandler struct {
+	inviteService *service.AuctionInviteService
+}
+
+func NewAuctionInviteHandler(inviteService *service.AuctionInviteService) *AuctionInviteHandler {
+	return &AuctionInviteHandler{inviteService: inviteService}
+}
+
+func (h *AuctionInviteHandler) List(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	invites, err := h.inviteService.List(r.Context(), auctionID, getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, invites)
+}
+
+// Invite lets a user, by username, see the seller's private auction
+func (h *AuctionInviteHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	var req domain.InviteUserRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	invite, err := h.inviteService.Invite(r.Context(), auctionID, getUserID(r), &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, invite)
+}
+
+func (h *AuctionInviteHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	userID, err := getURLParamUUID(r, "userId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid user ID")
+		return
+	}
+
+	if err := h.inviteService.Revoke(r.Context(), auctionID, getUserID(r), userID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Invite revoked"})
+}
+
+// CreateShareLink signs a link anyone can use to open the private auction
+func (h *AuctionInviteHandler) CreateShareLink(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	link, err := h.inviteService.CreateShareLink(r.Context(), auctionID, getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, link)
+}
+
+// RevokeShareLinks stops every share link made for the auction so far from
+// working
+func (h *AuctionInviteHandler) RevokeShareLinks(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	if err := h.inviteService.RevokeShareLinks(r.Context(), auctionID, getUserID(r)); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Share links revoked"})
+}
@@ -0,0 +1,330 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/pkg/sharelink"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock invite repository, keeping invites on the auction repository so its
+// List can see them
+type mockAuctionInviteRepo struct {
+	auctionRepo *mockAuctionRepo
+	userRepo    *mockUserRepo
+}
+
+func (r *mockAuctionInviteRepo) Add(ctx context.Context, invite *domain.AuctionInvite) error {
+	invites, ok := r.auctionRepo.invites[invite.AuctionID]
+	if !ok {
+		invites = make(map[uuid.UUID]time.Time)
+		r.auctionRepo.invites[invite.AuctionID] = invites
+	}
+	if _, ok := invites[invite.UserID]; !ok {
+		invites[invite.UserID] = time.Now()
+	}
+	invite.CreatedAt = invites[invite.UserID]
+	return nil
+}
+
+func (r *mockAuctionInviteRepo) Remove(ctx context.Context, auctionID, userID uuid.UUID) error {
+	if _, ok := r.auctionRepo.invites[auctionID][userID]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.auctionRepo.invites[auctionID], userID)
+	return nil
+}
+
+func (r *mockAuctionInviteRepo) ListByAuction(ctx context.Context, auctionID uuid.UUID) ([]domain.AuctionInvite, error) {
+	invites := make([]domain.AuctionInvite, 0)
+	for userID, createdAt := range r.auctionRepo.invites[auctionID] {
+		invite := domain.AuctionInvite{AuctionID: auctionID, UserID: userID, CreatedAt: createdAt}
+		if user, ok := r.userRepo.users[userID]; ok {
+			invite.Username = user.Username
+		}
+		invites = append(invites, invite)
+	}
+	return invites, nil
+}
+
+func (r *mockAuctionInviteRepo) IsInvited(ctx context.Context, auctionID, userID uuid.UUID) (bool, error) {
+	_, ok := r.auctionRepo.invites[auctionID][userID]
+	return ok, nil
+}
+
+func (r *mockAuctionInviteRepo) RotateShareVersion(ctx context.Context, auctionID uuid.UUID) (int, error) {
+	auction, ok := r.auctionRepo.auctions[auctionID]
+	if !ok {
+		return 0, domain.ErrNotFound
+	}
+	auction.ShareVersion++
+	return auction.ShareVersion, nil
+}
+
+func TestAuctionInviteHandler_PrivateAuction(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	userRepo := newMockUserRepo()
+	inviteRepo := &mockAuctionInviteRepo{auctionRepo: auctionRepo, userRepo: userRepo}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	inviteService := service.NewAuctionInviteService(
+		auctionRepo,
+		inviteRepo,
+		userRepo,
+		nil,
+		sharelink.NewSigner("test-share-secret"),
+		nil,
+		24*time.Hour,
+		"https://cards.example.com",
+	)
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil, // no S3 for tests
+		nil,
+		nil,
+		inviteService,
+		nil,
+	)
+	auctionHandler := handler.NewAuctionHandler(auctionService)
+	inviteHandler := handler.NewAuctionInviteHandler(inviteService)
+
+	r := createTestRouter()
+	r.With(authMiddleware.OptionalAuth).Get("/api/auctions", auctionHandler.List)
+	r.With(authMiddleware.OptionalAuth).Get("/api/auctions/{id}", auctionHandler.GetByID)
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/invites", inviteHandler.Invite)
+	r.With(authMiddleware.RequireAuth).Get("/api/auctions/{id}/invites", inviteHandler.List)
+	r.With(authMiddleware.RequireAuth).Delete("/api/auctions/{id}/invites/{userId}", inviteHandler.Revoke)
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/share-link", inviteHandler.CreateShareLink)
+	r.With(authMiddleware.RequireAuth).Delete("/api/auctions/{id}/share-link", inviteHandler.RevokeShareLinks)
+
+	seller := &domain.User{Username: "seller"}
+	guest := &domain.User{Username: "guest"}
+	stranger := &domain.User{Username: "stranger"}
+	for _, user := range []*domain.User{seller, guest, stranger} {
+		userRepo.Create(context.Background(), user)
+	}
+	sellerToken, _ := jwtManager.GenerateAccessToken(seller.ID, "user")
+	guestToken, _ := jwtManager.GenerateAccessToken(guest.ID, "user")
+	strangerToken, _ := jwtManager.GenerateAccessToken(stranger.ID, "user")
+
+	auction := &domain.Auction{
+		SellerID:      seller.ID,
+		Title:         "Private Collection Card",
+		StartingPrice: decimal.NewFromFloat(100),
+		CurrentPrice:  decimal.NewFromFloat(100),
+		BidIncrement:  decimal.NewFromFloat(1),
+		StartTime:     time.Now(),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+		Visibility:    domain.AuctionPrivate,
+	}
+	auctionRepo.Create(context.Background(), auction)
+	auctionPath := "/api/auctions/" + auction.ID.String()
+
+	getStatus := func(path, token string) int {
+		t.Helper()
+		return makeRequest(t, r, "GET", path, nil, token).Code
+	}
+	listCount := func(token string) int {
+		t.Helper()
+		rr := makeRequest(t, r, "GET", "/api/auctions", nil, token)
+		return len(parseResponse(t, rr).Data.([]interface{}))
+	}
+
+	if got := getStatus(auctionPath, sellerToken); got != http.StatusOK {
+		t.Errorf("expected the seller to see their private auction, got %v", got)
+	}
+	if got := getStatus(auctionPath, guestToken); got != http.StatusNotFound {
+		t.Errorf("expected 404 for an uninvited user, got %v", got)
+	}
+	if got := listCount(guestToken); got != 0 {
+		t.Errorf("expected the private auction to be left out of lists, got %d", got)
+	}
+
+	rr := makeRequest(t, r, "POST", auctionPath+"/invites", domain.InviteUserRequest{Username: "guest"}, strangerToken)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected only the seller to invite, got %v", rr.Code)
+	}
+
+	rr = makeRequest(t, r, "POST", auctionPath+"/invites", domain.InviteUserRequest{Username: "guest"}, sellerToken)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusCreated)
+	}
+
+	if got := getStatus(auctionPath, guestToken); got != http.StatusOK {
+		t.Errorf("expected an invited user to see the auction, got %v", got)
+	}
+	if got := listCount(guestToken); got != 1 {
+		t.Errorf("expected the private auction listed for an invited user, got %d", got)
+	}
+	if got := listCount(strangerToken); got != 0 {
+		t.Errorf("expected the private auction left out for other users, got %d", got)
+	}
+
+	rr = makeRequest(t, r, "GET", auctionPath+"/invites", nil, sellerToken)
+	invites := parseResponse(t, rr).Data.([]interface{})
+	if len(invites) != 1 || invites[0].(map[string]interface{})["username"] != "guest" {
+		t.Errorf("expected guest to be listed as invited, got %v", invites)
+	}
+
+	rr = makeRequest(t, r, "DELETE", auctionPath+"/invites/"+guest.ID.String(), nil, sellerToken)
+	if rr.Code != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	if got := getStatus(auctionPath, guestToken); got != http.StatusNotFound {
+		t.Errorf("expected 404 once the invite was withdrawn, got %v", got)
+	}
+
+	rr = makeRequest(t, r, "POST", auctionPath+"/share-link", nil, sellerToken)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusCreated)
+	}
+	token := parseResponse(t, rr).Data.(map[string]interface{})["token"].(string)
+	sharedPath := auctionPath + "?share=" + url.QueryEscape(token)
+
+	if got := getStatus(sharedPath, ""); got != http.StatusOK {
+		t.Errorf("expected a share link to open the auction, got %v", got)
+	}
+	if got := getStatus(auctionPath+"?share=0.9999999999.forged", ""); got != http.StatusNotFound {
+		t.Errorf("expected a forged share link to be refused, got %v", got)
+	}
+
+	rr = makeRequest(t, r, "DELETE", auctionPath+"/share-link", nil, sellerToken)
+	if rr.Code != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	if got := getStatus(sharedPath, ""); got != http.StatusNotFound {
+		t.Errorf("expected a revoked share link to stop working, got %v", got)
+	}
+}
+
+// Knowing a private auction's ID is not enough to bid on it, buy it, make
+// an offer or read its bids
+func TestAuctionInviteHandler_PrivateAuctionBidding(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	userRepo := newMockUserRepo()
+	bidRepo := newMockBidRepo()
+	inviteRepo := &mockAuctionInviteRepo{auctionRepo: auctionRepo, userRepo: userRepo}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	inviteService := service.NewAuctionInviteService(
+		auctionRepo,
+		inviteRepo,
+		userRepo,
+		nil,
+		sharelink.NewSigner("test-share-secret"),
+		nil,
+		24*time.Hour,
+		"https://cards.example.com",
+	)
+	bidService := service.NewBidService(bidRepo, auctionRepo, nil, nil, nil, nil, &mockTxManager{}, nil, nil, nil, inviteService, nil, nil)
+	offerService := service.NewOfferService(
+		newMockOfferRepo(),
+		auctionRepo,
+		bidRepo,
+		&mockAuditLogRepo{},
+		&mockTxManager{},
+		bidService,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	bidHandler := handler.NewBidHandler(bidService)
+	offerHandler := handler.NewOfferHandler(offerService)
+	inviteHandler := handler.NewAuctionInviteHandler(inviteService)
+
+	r := createTestRouter()
+	r.With(authMiddleware.OptionalAuth).Get("/api/auctions/{id}/bids", bidHandler.GetBidsByAuction)
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/bids", bidHandler.PlaceBid)
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/buy-now", bidHandler.BuyNow)
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/offers", offerHandler.MakeOffer)
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/share-link", inviteHandler.CreateShareLink)
+
+	sellerID, guestID, strangerID := uuid.New(), uuid.New(), uuid.New()
+	sellerToken, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+	guestToken, _ := jwtManager.GenerateAccessToken(guestID, "user")
+	strangerToken, _ := jwtManager.GenerateAccessToken(strangerID, "user")
+
+	buyNowPrice := decimal.NewFromFloat(500)
+	auction := &domain.Auction{
+		SellerID:      sellerID,
+		Title:         "Private Collection Card",
+		StartingPrice: decimal.NewFromFloat(100),
+		CurrentPrice:  decimal.NewFromFloat(100),
+		BuyNowPrice:   &buyNowPrice,
+		BuyNowRule:    domain.BuyNowAlways,
+		AcceptsOffers: true,
+		BidIncrement:  decimal.NewFromFloat(5),
+		StartTime:     time.Now().Add(-1 * time.Hour),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+		Visibility:    domain.AuctionPrivate,
+	}
+	auctionRepo.Create(context.Background(), auction)
+	auctionPath := "/api/auctions/" + auction.ID.String()
+	inviteRepo.Add(context.Background(), &domain.AuctionInvite{AuctionID: auction.ID, UserID: guestID})
+
+	rr := makeRequest(t, r, "POST", auctionPath+"/share-link", nil, sellerToken)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusCreated)
+	}
+	shareToken := parseResponse(t, rr).Data.(map[string]interface{})["token"].(string)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       interface{}
+		token      string
+		wantStatus int
+	}{
+		{name: "uninvited user bids", method: "POST", path: auctionPath + "/bids", body: map[string]string{"amount": "105"}, token: strangerToken, wantStatus: http.StatusNotFound},
+		{name: "uninvited user buys now", method: "POST", path: auctionPath + "/buy-now", token: strangerToken, wantStatus: http.StatusNotFound},
+		{name: "uninvited user makes an offer", method: "POST", path: auctionPath + "/offers", body: domain.MakeOfferRequest{Amount: "200"}, token: strangerToken, wantStatus: http.StatusNotFound},
+		{name: "uninvited user lists bids", method: "GET", path: auctionPath + "/bids", token: strangerToken, wantStatus: http.StatusNotFound},
+		{name: "anonymous user lists bids", method: "GET", path: auctionPath + "/bids", wantStatus: http.StatusNotFound},
+		{name: "share link holder lists bids", method: "GET", path: auctionPath + "/bids?share=" + url.QueryEscape(shareToken), wantStatus: http.StatusOK},
+		{name: "share link does not let a user bid", method: "POST", path: auctionPath + "/bids?share=" + url.QueryEscape(shareToken), body: map[string]string{"amount": "105"}, token: strangerToken, wantStatus: http.StatusNotFound},
+		{name: "seller lists bids", method: "GET", path: auctionPath + "/bids", token: sellerToken, wantStatus: http.StatusOK},
+		{name: "invited user bids", method: "POST", path: auctionPath + "/bids", body: map[string]string{"amount": "105"}, token: guestToken, wantStatus: http.StatusCreated},
+		{name: "invited user lists bids", method: "GET", path: auctionPath + "/bids", token: guestToken, wantStatus: http.StatusOK},
+		{name: "invited user makes an offer", method: "POST", path: auctionPath + "/offers", body: domain.MakeOfferRequest{Amount: "200"}, token: guestToken, wantStatus: http.StatusCreated},
+		{name: "invited user buys now", method: "POST", path: auctionPath + "/buy-now", token: guestToken, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := makeRequest(t, r, tt.method, tt.path, tt.body, tt.token)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v, body %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+		})
+	}
+
+	bids, _, _ := bidRepo.GetByAuctionID(context.Background(), auction.ID, &domain.BidListParams{})
+	for _, bid := range bids {
+		if bid.BidderID != guestID {
+			t.Errorf("expected only the invited user's bids, got one from %s", bid.BidderID)
+		}
+	}
+}
@@ -1,40 +1,35 @@
 package handler
 
 import (
-	"encoding/json"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"log"
 	"net/http"
 	"net/url"
-	"time"
 
 	"github.com/auction-cards/backend/internal/config"
 	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/pkg/oauth"
 	"github.com/auction-cards/backend/internal/service"
-	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 )
 
 type AuthHandler struct {
-	authService  *service.AuthService
-	oauthConfig  *oauth2.Config
-	frontendURL  string
+	authService    *service.AuthService
+	oauthProviders *oauth.Registry
+	frontendURL    string
+	countryHeader  string
 }
 
 func NewAuthHandler(authService *service.AuthService, cfg *config.Config) *AuthHandler {
-	var oauthConfig *oauth2.Config
-	if cfg.OAuth.GoogleClientID != "" {
-		oauthConfig = &oauth2.Config{
-			ClientID:     cfg.OAuth.GoogleClientID,
-			ClientSecret: cfg.OAuth.GoogleClientSecret,
-			RedirectURL:  cfg.OAuth.GoogleRedirectURL,
-			Scopes:       []string{"email", "profile"},
-			Endpoint:     google.Endpoint,
-		}
-	}
-
 	return &AuthHandler{
-		authService:  authService,
-		oauthConfig:  oauthConfig,
-		frontendURL:  cfg.Server.AllowOrigins[0],
+		authService:    authService,
+		oauthProviders: newOAuthRegistry(&cfg.OAuth),
+		frontendURL:    cfg.Server.FrontendURL,
+		countryHeader:  cfg.Sessions.CountryHeader,
 	}
 }
 
@@ -74,18 +69,47 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	authResponse, refreshToken, err := h.authService.Login(r.Context(), &req)
+	authResponse, refreshToken, err := h.authService.Login(r.Context(), &req, h.loginClient(w, r))
 	if err != nil {
 		handleError(w, err)
 		return
 	}
 
+	// The session starts once the emailed code is confirmed
+	if authResponse.TwoFactorRequired {
+		respondJSON(w, http.StatusAccepted, authResponse)
+		return
+	}
+
 	// Set refresh token as httpOnly cookie
 	h.setRefreshTokenCookie(w, refreshToken)
 
 	respondJSON(w, http.StatusOK, authResponse)
 }
 
+func (h *AuthHandler) VerifyLogin(w http.ResponseWriter, r *http.Request) {
+	var req domain.VerifyLoginRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	authResponse, refreshToken, err := h.authService.VerifyLogin(r.Context(), &req, h.loginClient(w, r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	h.setRefreshTokenCookie(w, refreshToken)
+
+	respondJSON(w, http.StatusOK, authResponse)
+}
+
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	refreshToken, err := r.Cookie("refresh_token")
 	if err == nil && refreshToken.Value != "" {
@@ -93,7 +117,7 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Clear the cookie
-	h.clearRefreshTokenCookie(w)
+	clearRefreshTokenCookie(w)
 
 	respondJSON(w, http.StatusOK, map[string]string{
 		"message": "Logged out successfully",
@@ -109,7 +133,7 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 
 	accessToken, err := h.authService.RefreshAccessToken(r.Context(), refreshToken.Value)
 	if err != nil {
-		h.clearRefreshTokenCookie(w)
+		clearRefreshTokenCookie(w)
 		handleError(w, err)
 		return
 	}
@@ -156,6 +180,26 @@ func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	var req domain.ResendVerificationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	// Always return success to prevent email enumeration
+	_ = h.authService.ResendVerification(r.Context(), &req)
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "If the email belongs to an unverified account, a verification link has been sent",
+	})
+}
+
 func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	var req domain.ResetPasswordRequest
 	if err := decodeJSON(r, &req); err != nil {
@@ -178,103 +222,129 @@ func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// Google OAuth handlers
+// OAuth handlers
 
-func (h *AuthHandler) GoogleLogin(w http.ResponseWriter, r *http.Request) {
-	if h.oauthConfig == nil {
-		respondError(w, http.StatusNotImplemented, "NOT_CONFIGURED", "Google OAuth not configured")
+// OAuthLogin handles GET /api/auth/{provider}
+func (h *AuthHandler) OAuthLogin(w http.ResponseWriter, r *http.Request) {
+	provider, err := h.oauthProviders.Get(chi.URLParam(r, "provider"))
+	if err != nil {
+		respondError(w, http.StatusNotImplemented, "NOT_CONFIGURED", "OAuth provider not configured")
 		return
 	}
 
-	state := generateOAuthState()
-	http.SetCookie(w, &http.Cookie{
-		Name:     "oauth_state",
-		Value:    state,
-		Path:     "/",
-		MaxAge:   600,
-		HttpOnly: true,
-		SameSite: http.SameSiteLaxMode,
-	})
+	state, err := generateRandomToken()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	http.SetCookie(w, oauthStateCookie(provider, state, 600))
 
-	url := h.oauthConfig.AuthCodeURL(state)
-	http.Redirect(w, r, url, http.StatusTemporaryRedirect)
+	http.Redirect(w, r, provider.AuthURL(state), http.StatusTemporaryRedirect)
 }
 
-func (h *AuthHandler) GoogleCallback(w http.ResponseWriter, r *http.Request) {
-	if h.oauthConfig == nil {
-		respondError(w, http.StatusNotImplemented, "NOT_CONFIGURED", "Google OAuth not configured")
+// OAuthCallback handles GET and POST /api/auth/{provider}/callback. Providers
+// that use form_post send the state and code in the request body.
+func (h *AuthHandler) OAuthCallback(w http.ResponseWriter, r *http.Request) {
+	provider, err := h.oauthProviders.Get(chi.URLParam(r, "provider"))
+	if err != nil {
+		respondError(w, http.StatusNotImplemented, "NOT_CONFIGURED", "OAuth provider not configured")
 		return
 	}
 
 	// Verify state
 	stateCookie, err := r.Cookie("oauth_state")
-	if err != nil || stateCookie.Value != r.URL.Query().Get("state") {
-		http.Redirect(w, r, h.frontendURL+"/login?error=invalid_state", http.StatusTemporaryRedirect)
+	if err != nil || stateCookie.Value != r.FormValue("state") {
+		h.redirectOAuthError(w, r, "invalid_state")
 		return
 	}
 
 	// Clear state cookie
-	http.SetCookie(w, &http.Cookie{
-		Name:     "oauth_state",
-		Value:    "",
-		Path:     "/",
-		MaxAge:   -1,
-		HttpOnly: true,
-	})
+	http.SetCookie(w, oauthStateCookie(provider, "", -1))
 
-	code := r.URL.Query().Get("code")
+	code := r.FormValue("code")
 	if code == "" {
-		http.Redirect(w, r, h.frontendURL+"/login?error=no_code", http.StatusTemporaryRedirect)
+		h.redirectOAuthError(w, r, "no_code")
 		return
 	}
 
 	// Exchange code for token
-	token, err := h.oauthConfig.Exchange(r.Context(), code)
+	token, err := provider.Exchange(r.Context(), code)
 	if err != nil {
-		http.Redirect(w, r, h.frontendURL+"/login?error=exchange_failed", http.StatusTemporaryRedirect)
+		h.redirectOAuthError(w, r, "exchange_failed")
 		return
 	}
 
-	// Get user info from Google
-	client := h.oauthConfig.Client(r.Context(), token)
-	resp, err := client.Get("https://www.googleapis.com/oauth2/v2/userinfo")
+	profile, err := provider.FetchProfile(r.Context(), token)
 	if err != nil {
-		http.Redirect(w, r, h.frontendURL+"/login?error=userinfo_failed", http.StatusTemporaryRedirect)
+		h.redirectOAuthError(w, r, "userinfo_failed")
 		return
 	}
-	defer resp.Body.Close()
 
-	var googleUser struct {
-		ID      string `json:"id"`
-		Email   string `json:"email"`
-		Name    string `json:"name"`
-		Picture string `json:"picture"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&googleUser); err != nil {
-		http.Redirect(w, r, h.frontendURL+"/login?error=decode_failed", http.StatusTemporaryRedirect)
+	// A flow started from account settings links the login instead
+	if userID, ok := h.authService.TakeOAuthLink(r.Context(), stateCookie.Value); ok {
+		h.linkOAuthCallback(w, r, userID, provider, profile)
 		return
 	}
 
-	// Create or get user
-	user, err := h.authService.GetOrCreateOAuthUser(r.Context(), "google", googleUser.ID, googleUser.Email, googleUser.Name)
+	// Create, link or get user
+	user, err := h.authService.GetOrCreateOAuthUser(r.Context(), provider.Name(), profile)
+	if errors.Is(err, domain.ErrEmailAlreadyExists) {
+		h.requestLinkConfirmation(w, r, provider, profile)
+		return
+	}
 	if err != nil {
-		http.Redirect(w, r, h.frontendURL+"/login?error=create_user_failed", http.StatusTemporaryRedirect)
+		h.redirectOAuthError(w, r, "create_user_failed")
 		return
 	}
 
 	// Generate tokens
-	authResponse, refreshToken, err := h.authService.GenerateTokens(r.Context(), user)
+	authResponse, refreshToken, err := h.authService.GenerateTokens(r.Context(), user, provider.Name(), h.loginClient(w, r))
 	if err != nil {
-		http.Redirect(w, r, h.frontendURL+"/login?error=token_failed", http.StatusTemporaryRedirect)
+		h.redirectOAuthError(w, r, "token_failed")
 		return
 	}
 
 	// Set refresh token cookie
 	h.setRefreshTokenCookie(w, refreshToken)
 
-	// Redirect to frontend with access token
+	// Redirect to frontend with access token. 303 makes the browser follow
+	// with a GET even when the callback itself was a POST.
 	redirectURL := h.frontendURL + "/oauth/callback?access_token=" + url.QueryEscape(authResponse.AccessToken)
-	http.Redirect(w, r, redirectURL, http.StatusTemporaryRedirect)
+	if user.UsernameSetupRequired {
+		// Tells the frontend to ask for a username before anything else
+		redirectURL += "&username_setup=1"
+	}
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
+
+// pendingLinkCookieName holds the token of a provider login waiting to be
+// confirmed. It lives in the browser that signed in with the provider, so
+// the link can't be confirmed from anywhere else.
+const pendingLinkCookieName = "oauth_pending_link"
+
+// requestLinkConfirmation parks a provider login whose unverified email
+// matched an existing account and sends the user to sign in to that
+// account, after which they confirm the link
+func (h *AuthHandler) requestLinkConfirmation(w http.ResponseWriter, r *http.Request, provider oauth.Provider, profile *oauth.Profile) {
+	token, err := h.authService.StashOAuthLink(r.Context(), provider.Name(), profile)
+	if err != nil {
+		h.redirectOAuthError(w, r, "email_unverified")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     pendingLinkCookieName,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   600,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, h.frontendURL+"/login?error=link_required&provider="+url.QueryEscape(provider.Name()), http.StatusSeeOther)
+}
+
+func (h *AuthHandler) redirectOAuthError(w http.ResponseWriter, r *http.Request, code string) {
+	http.Redirect(w, r, h.frontendURL+"/login?error="+code, http.StatusSeeOther)
 }
 
 func (h *AuthHandler) GetMe(w http.ResponseWriter, r *http.Request) {
@@ -289,8 +359,328 @@ func (h *AuthHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, user)
 }
 
+func (h *AuthHandler) SetSessionPolicy(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	var req domain.SetSessionPolicyRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	// The session making the request is kept when the others are revoked
+	resp, err := h.authService.SetSessionPolicy(r.Context(), userID, &req, refreshTokenFromCookie(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, resp)
+}
+
+func (h *AuthHandler) SetTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	var req domain.SetTwoFactorRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	user, err := h.authService.SetTwoFactor(r.Context(), userID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, user)
+}
+
+func (h *AuthHandler) ListDevices(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	var deviceToken string
+	if cookie, err := r.Cookie(deviceCookieName); err == nil {
+		deviceToken = cookie.Value
+	}
+
+	devices, err := h.authService.ListDevices(r.Context(), userID, deviceToken)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, devices)
+}
+
+func (h *AuthHandler) UpdateDevice(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	deviceID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid device ID")
+		return
+	}
+
+	var req domain.UpdateDeviceRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	device, err := h.authService.UpdateDevice(r.Context(), userID, deviceID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, device)
+}
+
+func (h *AuthHandler) DeleteDevice(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	deviceID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid device ID")
+		return
+	}
+
+	if err := h.authService.DeleteDevice(r.Context(), userID, deviceID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Device removed and signed out",
+	})
+}
+
+func (h *AuthHandler) ListLoginHistory(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	events, err := h.authService.ListLoginHistory(r.Context(), userID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, events)
+}
+
+// ReportLogin signs the user out everywhere, this session included, and
+// emails them a link to set a new password
+func (h *AuthHandler) ReportLogin(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	eventID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid login ID")
+		return
+	}
+
+	if err := h.authService.ReportLogin(r.Context(), userID, eventID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	clearRefreshTokenCookie(w)
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "All sessions signed out. Check your email to set a new password.",
+	})
+}
+
+func (h *AuthHandler) GetSignInMethods(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	methods, err := h.authService.ListSignInMethods(r.Context(), userID, h.oauthProviders.Names())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, methods)
+}
+
+// SetPassword adds a password to an OAuth-only account. The user must have
+// signed in within the last few minutes.
+func (h *AuthHandler) SetPassword(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	var req domain.SetPasswordRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	if err := h.authService.SetPassword(r.Context(), userID, &req, refreshTokenFromCookie(r)); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Password set",
+	})
+}
+
+// LinkOAuth starts linking a provider login to the signed-in user. The
+// client sends the browser to the returned URL, and the provider's callback
+// finishes the link instead of signing in.
+func (h *AuthHandler) LinkOAuth(w http.ResponseWriter, r *http.Request) {
+	provider, err := h.oauthProviders.Get(chi.URLParam(r, "provider"))
+	if err != nil {
+		respondError(w, http.StatusNotImplemented, "NOT_CONFIGURED", "OAuth provider not configured")
+		return
+	}
+
+	var req domain.ReauthRequest
+	if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	state, err := generateRandomToken()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if err := h.authService.BeginOAuthLink(r.Context(), getUserID(r), provider.Name(), state, &req, refreshTokenFromCookie(r)); err != nil {
+		handleError(w, err)
+		return
+	}
+	http.SetCookie(w, oauthStateCookie(provider, state, 600))
+
+	respondJSON(w, http.StatusOK, domain.OAuthLinkResponse{URL: provider.AuthURL(state)})
+}
+
+func (h *AuthHandler) UnlinkOAuth(w http.ResponseWriter, r *http.Request) {
+	var req domain.ReauthRequest
+	if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	err := h.authService.UnlinkOAuthAccount(r.Context(), getUserID(r), chi.URLParam(r, "provider"), &req, refreshTokenFromCookie(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Login removed",
+	})
+}
+
+// ConfirmOAuthLink links the provider login parked by the OAuth callback in
+// this browser to the signed-in user
+func (h *AuthHandler) ConfirmOAuthLink(w http.ResponseWriter, r *http.Request) {
+	var req domain.ReauthRequest
+	if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	var token string
+	if cookie, err := r.Cookie(pendingLinkCookieName); err == nil {
+		token = cookie.Value
+	}
+
+	account, err := h.authService.ConfirmOAuthLink(r.Context(), getUserID(r), token, &req, refreshTokenFromCookie(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     pendingLinkCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	respondJSON(w, http.StatusOK, account)
+}
+
+// linkOAuthCallback finishes a link started by LinkOAuth and sends the user
+// back to their account settings
+func (h *AuthHandler) linkOAuthCallback(w http.ResponseWriter, r *http.Request, userID uuid.UUID, provider oauth.Provider, profile *oauth.Profile) {
+	err := h.authService.LinkOAuthAccount(r.Context(), userID, provider.Name(), profile)
+	switch {
+	case errors.Is(err, domain.ErrOAuthAccountInUse):
+		h.redirectLinkResult(w, r, "error", "account_in_use")
+	case errors.Is(err, domain.ErrProviderLinked):
+		h.redirectLinkResult(w, r, "error", "provider_linked")
+	case err != nil:
+		h.redirectLinkResult(w, r, "error", "link_failed")
+	default:
+		h.redirectLinkResult(w, r, "linked", provider.Name())
+	}
+}
+
+func (h *AuthHandler) redirectLinkResult(w http.ResponseWriter, r *http.Request, key, value string) {
+	http.Redirect(w, r, h.frontendURL+"/settings?"+key+"="+url.QueryEscape(value), http.StatusSeeOther)
+}
+
 // Helper methods
 
+// refreshTokenFromCookie returns the session's refresh token, or "" when the
+// request has none
+func refreshTokenFromCookie(r *http.Request) string {
+	if cookie, err := r.Cookie("refresh_token"); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// deviceCookieName holds a random per-browser value identifying the device
+// across sign-ins. Header based fingerprints change with every browser update
+// and are easy to copy, so the cookie is the device's identity.
+const deviceCookieName = "device_id"
+
+// loginClient describes where a login came from, issuing a device cookie if
+// the client has none yet. The country comes from a header set by the CDN or
+// proxy and is empty when it isn't deployed.
+func (h *AuthHandler) loginClient(w http.ResponseWriter, r *http.Request) *domain.LoginClient {
+	client := &domain.LoginClient{
+		IP:        clientAddress(r),
+		UserAgent: r.UserAgent(),
+	}
+	if h.countryHeader != "" {
+		client.Country = r.Header.Get(h.countryHeader)
+	}
+
+	if cookie, err := r.Cookie(deviceCookieName); err == nil && cookie.Value != "" {
+		client.DeviceToken = cookie.Value
+	} else if token, err := generateRandomToken(); err == nil {
+		client.DeviceToken = token
+		http.SetCookie(w, &http.Cookie{
+			Name:     deviceCookieName,
+			Value:    token,
+			Path:     "/",
+			MaxAge:   400 * 24 * 60 * 60, // the most browsers keep a cookie
+			HttpOnly: true,
+			Secure:   false, // Set to true in production with HTTPS
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+
+	return client
+}
+
 func (h *AuthHandler) setRefreshTokenCookie(w http.ResponseWriter, token string) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     "refresh_token",
@@ -303,7 +693,7 @@ func (h *AuthHandler) setRefreshTokenCookie(w http.ResponseWriter, token string)
 	})
 }
 
-func (h *AuthHandler) clearRefreshTokenCookie(w http.ResponseWriter) {
+func clearRefreshTokenCookie(w http.ResponseWriter) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     "refresh_token",
 		Value:    "",
@@ -315,6 +705,52 @@ func (h *AuthHandler) clearRefreshTokenCookie(w http.ResponseWriter) {
 	})
 }
 
-func generateOAuthState() string {
-	return time.Now().Format("20060102150405")
+// generateRandomToken returns 128 random bits for OAuth state and device
+// cookies
+func generateRandomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// oauthStateCookie builds the state cookie. Form-posting providers call back
+// cross-site, which a SameSite=Lax cookie would not survive.
+func oauthStateCookie(provider oauth.Provider, state string, maxAge int) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     "oauth_state",
+		Value:    state,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if fp, ok := provider.(oauth.FormPoster); ok && fp.FormPost() {
+		cookie.SameSite = http.SameSiteNoneMode
+		cookie.Secure = true
+	}
+	return cookie
+}
+
+// newOAuthRegistry enables every provider that has a client ID configured
+func newOAuthRegistry(cfg *config.OAuthConfig) *oauth.Registry {
+	registry := oauth.NewRegistry()
+
+	if cfg.Google.ClientID != "" {
+		registry.Register(oauth.NewGoogleProvider(cfg.Google.ClientID, cfg.Google.ClientSecret, cfg.Google.RedirectURL))
+	}
+	if cfg.GitHub.ClientID != "" {
+		registry.Register(oauth.NewGitHubProvider(cfg.GitHub.ClientID, cfg.GitHub.ClientSecret, cfg.GitHub.RedirectURL))
+	}
+	if cfg.Apple.ClientID != "" {
+		apple, err := oauth.NewAppleProvider(cfg.Apple.ClientID, cfg.Apple.TeamID, cfg.Apple.KeyID, cfg.Apple.PrivateKey, cfg.Apple.RedirectURL)
+		if err != nil {
+			log.Printf("Sign in with Apple disabled: %v", err)
+		} else {
+			registry.Register(apple)
+		}
+	}
+
+	return registry
 }
@@ -0,0 +1,155 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestBatchGet(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	userRepo := newMockUserRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil, // no S3 for tests
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	auctionHandler := handler.NewAuctionHandler(auctionService)
+	userHandler := handler.NewUserHandler(service.NewUserService(userRepo, nil, nil, nil), nil)
+
+	r := createTestRouter()
+	r.With(authMiddleware.OptionalAuth).Post("/api/auctions/batch-get", auctionHandler.BatchGet)
+	r.Post("/api/users/batch-get", userHandler.BatchGet)
+
+	sellerID := uuid.New()
+	sellerToken, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+
+	newAuction := func(status domain.AuctionStatus) uuid.UUID {
+		auction := &domain.Auction{
+			SellerID:      sellerID,
+			Title:         "Test Auction",
+			StartingPrice: decimal.NewFromFloat(100),
+			CurrentPrice:  decimal.NewFromFloat(100),
+			BidIncrement:  decimal.NewFromFloat(1),
+			StartTime:     time.Now(),
+			EndTime:       time.Now().Add(24 * time.Hour),
+			Status:        status,
+		}
+		auctionRepo.Create(context.Background(), auction)
+		return auction.ID
+	}
+	active := newAuction(domain.AuctionStatusActive)
+	draft := newAuction(domain.AuctionStatusDraft)
+	unknown := uuid.New()
+
+	batchIDs := func(t *testing.T, data interface{}, key string) []string {
+		items := data.(map[string]interface{})[key].([]interface{})
+		ids := make([]string, 0, len(items))
+		for _, item := range items {
+			switch item := item.(type) {
+			case string:
+				ids = append(ids, item)
+			case map[string]interface{}:
+				if user, ok := item["user"].(map[string]interface{}); ok {
+					item = user
+				}
+				ids = append(ids, item["id"].(string))
+			}
+		}
+		return ids
+	}
+
+	equal := func(got []string, want ...uuid.UUID) bool {
+		if len(got) != len(want) {
+			return false
+		}
+		for i := range got {
+			if got[i] != want[i].String() {
+				return false
+			}
+		}
+		return true
+	}
+
+	t.Run("drafts are missing for other viewers", func(t *testing.T) {
+		body := map[string]interface{}{"ids": []uuid.UUID{unknown, draft, active, active}}
+		rr := makeRequest(t, r, "POST", "/api/auctions/batch-get", body, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("got status %v, want %v: %s", rr.Code, http.StatusOK, rr.Body.String())
+		}
+
+		data := parseResponse(t, rr).Data
+		if got := batchIDs(t, data, "auctions"); !equal(got, active) {
+			t.Errorf("got auctions %v, want [%v]", got, active)
+		}
+		if got := batchIDs(t, data, "missing"); !equal(got, unknown, draft) {
+			t.Errorf("got missing %v, want [%v %v]", got, unknown, draft)
+		}
+	})
+
+	t.Run("sellers see their drafts in request order", func(t *testing.T) {
+		body := map[string]interface{}{"ids": []uuid.UUID{draft, active}}
+		rr := makeRequest(t, r, "POST", "/api/auctions/batch-get", body, sellerToken)
+
+		data := parseResponse(t, rr).Data
+		if got := batchIDs(t, data, "auctions"); !equal(got, draft, active) {
+			t.Errorf("got auctions %v, want [%v %v]", got, draft, active)
+		}
+	})
+
+	t.Run("rejects empty and oversized batches", func(t *testing.T) {
+		tooMany := make([]uuid.UUID, domain.MaxBatchGetIDs+1)
+		for i := range tooMany {
+			tooMany[i] = uuid.New()
+		}
+
+		for _, ids := range [][]uuid.UUID{{}, tooMany} {
+			rr := makeRequest(t, r, "POST", "/api/auctions/batch-get", map[string]interface{}{"ids": ids}, "")
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("%d IDs: got status %v, want %v", len(ids), rr.Code, http.StatusBadRequest)
+			}
+		}
+	})
+
+	t.Run("banned users are missing", func(t *testing.T) {
+		member := &domain.User{ID: uuid.New(), Email: "member@example.com", Username: "member", Role: domain.RoleUser}
+		banned := &domain.User{ID: uuid.New(), Email: "banned@example.com", Username: "banned", Role: domain.RoleUser, IsBanned: true}
+		userRepo.users[member.ID] = member
+		userRepo.users[banned.ID] = banned
+
+		body := map[string]interface{}{"ids": []uuid.UUID{banned.ID, member.ID, unknown}}
+		rr := makeRequest(t, r, "POST", "/api/users/batch-get", body, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("got status %v, want %v: %s", rr.Code, http.StatusOK, rr.Body.String())
+		}
+
+		data := parseResponse(t, rr).Data
+		if got := batchIDs(t, data, "users"); !equal(got, member.ID) {
+			t.Errorf("got users %v, want [%v]", got, member.ID)
+		}
+		if got := batchIDs(t, data, "missing"); !equal(got, banned.ID, unknown) {
+			t.Errorf("got missing %v, want [%v %v]", got, banned.ID, unknown)
+		}
+	})
+}
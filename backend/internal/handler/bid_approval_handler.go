@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type BidApprovalHandler struct {
+	bidApprovalService *service.BidApprovalService
+}
+
+func NewBidApprovalHandler(bidApprovalService *service.BidApprovalService) *BidApprovalHandler {
+	return &BidApprovalHandler{bidApprovalService: bidApprovalService}
+}
+
+// Request asks the seller for approval to bid on the auction
+func (h *BidApprovalHandler) Request(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	var req domain.RequestBidApprovalRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	approval, err := h.bidApprovalService.Request(r.Context(), auctionID, getUserID(r), &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, approval)
+}
+
+// Mine returns the current user's request to bid on the auction
+func (h *BidApprovalHandler) Mine(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	approval, err := h.bidApprovalService.Get(r.Context(), auctionID, getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, approval)
+}
+
+// List returns the requests to bid on the seller's auction
+func (h *BidApprovalHandler) List(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	var status *domain.BidApprovalStatus
+	if s := getQueryParamString(r, "status"); s != nil {
+		st := domain.BidApprovalStatus(*s)
+		status = &st
+	}
+
+	approvals, err := h.bidApprovalService.List(r.Context(), auctionID, getUserID(r), status)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, approvals)
+}
+
+// Decide approves or denies a user's request to bid
+func (h *BidApprovalHandler) Decide(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	userID, err := getURLParamUUID(r, "userId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid user ID")
+		return
+	}
+
+	var req domain.DecideBidApprovalRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	approval, err := h.bidApprovalService.Decide(r.Context(), auctionID, getUserID(r), userID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, approval)
+}
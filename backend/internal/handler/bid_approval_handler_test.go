@@ -0,0 +1,232 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock bid approval repository keeping requests in memory
+type mockBidApprovalRepo struct {
+	approvals map[[2]uuid.UUID]*domain.BidApproval
+}
+
+func (r *mockBidApprovalRepo) Request(ctx context.Context, approval *domain.BidApproval) error {
+	key := [2]uuid.UUID{approval.AuctionID, approval.UserID}
+	stored := *approval
+	stored.Status = domain.BidApprovalPending
+	stored.UpdatedAt = time.Now()
+	if existing, ok := r.approvals[key]; ok {
+		stored.CreatedAt = existing.CreatedAt
+	} else {
+		stored.CreatedAt = stored.UpdatedAt
+	}
+	r.approvals[key] = &stored
+	return nil
+}
+
+func (r *mockBidApprovalRepo) Get(ctx context.Context, auctionID, userID uuid.UUID) (*domain.BidApproval, error) {
+	approval, ok := r.approvals[[2]uuid.UUID{auctionID, userID}]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	stored := *approval
+	return &stored, nil
+}
+
+func (r *mockBidApprovalRepo) ListByAuction(ctx context.Context, auctionID uuid.UUID, status *domain.BidApprovalStatus) ([]domain.BidApproval, error) {
+	approvals := make([]domain.BidApproval, 0)
+	for key, approval := range r.approvals {
+		if key[0] == auctionID && (status == nil || approval.Status == *status) {
+			approvals = append(approvals, *approval)
+		}
+	}
+	return approvals, nil
+}
+
+func (r *mockBidApprovalRepo) Decide(ctx context.Context, approval *domain.BidApproval) error {
+	key := [2]uuid.UUID{approval.AuctionID, approval.UserID}
+	if _, ok := r.approvals[key]; !ok {
+		return domain.ErrNotFound
+	}
+	now := time.Now()
+	approval.DecidedAt = &now
+	stored := *approval
+	r.approvals[key] = &stored
+	return nil
+}
+
+func (r *mockBidApprovalRepo) IsApproved(ctx context.Context, auctionID, userID uuid.UUID) (bool, error) {
+	approval, ok := r.approvals[[2]uuid.UUID{auctionID, userID}]
+	return ok && approval.Status == domain.BidApprovalApproved, nil
+}
+
+func TestBidApprovalHandler(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	approvalRepo := &mockBidApprovalRepo{approvals: make(map[[2]uuid.UUID]*domain.BidApproval)}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	bidApprovalService := service.NewBidApprovalService(approvalRepo, auctionRepo, nil, nil, nil)
+	bidService := service.NewBidService(
+		newMockBidRepo(),
+		auctionRepo,
+		nil,
+		nil,
+		nil,
+		nil,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		bidApprovalService,
+		nil,
+	)
+	bidApprovalHandler := handler.NewBidApprovalHandler(bidApprovalService)
+	bidHandler := handler.NewBidHandler(bidService)
+
+	r := createTestRouter()
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/bids", bidHandler.PlaceBid)
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/bid-approvals", bidApprovalHandler.Request)
+	r.With(authMiddleware.RequireAuth).Get("/api/auctions/{id}/bid-approvals", bidApprovalHandler.List)
+	r.With(authMiddleware.RequireAuth).Get("/api/auctions/{id}/bid-approvals/me", bidApprovalHandler.Mine)
+	r.With(authMiddleware.RequireAuth).Put("/api/auctions/{id}/bid-approvals/{userId}", bidApprovalHandler.Decide)
+
+	sellerID := uuid.New()
+	bidderID := uuid.New()
+	sellerToken, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+	bidderToken, _ := jwtManager.GenerateAccessToken(bidderID, "user")
+
+	auction := &domain.Auction{
+		SellerID:             sellerID,
+		Title:                "PSA 10 Base Set Charizard",
+		StartingPrice:        decimal.NewFromFloat(1000),
+		CurrentPrice:         decimal.NewFromFloat(1000),
+		BidIncrement:         decimal.NewFromFloat(50),
+		StartTime:            time.Now().Add(-time.Hour),
+		EndTime:              time.Now().Add(24 * time.Hour),
+		Status:               domain.AuctionStatusActive,
+		RequiresBidApproval:  true,
+		BidApprovalQuestions: []string{"How long have you been collecting?"},
+	}
+	auctionRepo.Create(context.Background(), auction)
+	open := &domain.Auction{
+		SellerID:      sellerID,
+		Title:         "Base Set Pikachu",
+		StartingPrice: decimal.NewFromFloat(10),
+		CurrentPrice:  decimal.NewFromFloat(10),
+		BidIncrement:  decimal.NewFromFloat(1),
+		StartTime:     time.Now().Add(-time.Hour),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+	}
+	auctionRepo.Create(context.Background(), open)
+
+	approvalsPath := "/api/auctions/" + auction.ID.String() + "/bid-approvals"
+	decidePath := approvalsPath + "/" + bidderID.String()
+	bidsPath := "/api/auctions/" + auction.ID.String() + "/bids"
+
+	t.Run("bids need approval", func(t *testing.T) {
+		rr := makeRequest(t, r, "POST", bidsPath, domain.PlaceBidRequest{Amount: "1050.00"}, bidderToken)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Run("rejected requests", func(t *testing.T) {
+		cases := []struct {
+			name   string
+			path   string
+			req    domain.RequestBidApprovalRequest
+			token  string
+			status int
+		}{
+			{"unanswered questions", approvalsPath, domain.RequestBidApprovalRequest{}, bidderToken, http.StatusBadRequest},
+			{"seller", approvalsPath, domain.RequestBidApprovalRequest{Answers: []string{"Forever"}}, sellerToken, http.StatusBadRequest},
+			{"approval not required", "/api/auctions/" + open.ID.String() + "/bid-approvals", domain.RequestBidApprovalRequest{}, bidderToken, http.StatusBadRequest},
+		}
+		for _, tc := range cases {
+			rr := makeRequest(t, r, "POST", tc.path, tc.req, tc.token)
+			if rr.Code != tc.status {
+				t.Errorf("%s: got status %v, want %v", tc.name, rr.Code, tc.status)
+			}
+		}
+	})
+
+	t.Run("request", func(t *testing.T) {
+		rr := makeRequest(t, r, "POST", approvalsPath, domain.RequestBidApprovalRequest{Answers: []string{"Since 1999"}}, bidderToken)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusCreated)
+		}
+		approval := parseResponse(t, rr).Data.(map[string]interface{})
+		if approval["status"] != "pending" {
+			t.Errorf("expected a pending request, got %v", approval["status"])
+		}
+		answers := approval["answers"].([]interface{})
+		if len(answers) != 1 || answers[0].(map[string]interface{})["question"] != auction.BidApprovalQuestions[0] {
+			t.Errorf("expected the answer paired with its question, got %v", answers)
+		}
+
+		rr = makeRequest(t, r, "GET", approvalsPath+"/me", nil, bidderToken)
+		if rr.Code != http.StatusOK {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusOK)
+		}
+	})
+
+	t.Run("only the seller lists and decides", func(t *testing.T) {
+		rr := makeRequest(t, r, "GET", approvalsPath, nil, bidderToken)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusForbidden)
+		}
+
+		rr = makeRequest(t, r, "PUT", decidePath, domain.DecideBidApprovalRequest{Status: "approved"}, bidderToken)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusForbidden)
+		}
+
+		rr = makeRequest(t, r, "GET", approvalsPath+"?status=pending", nil, sellerToken)
+		if approvals := parseResponse(t, rr).Data.([]interface{}); len(approvals) != 1 {
+			t.Errorf("expected 1 pending request, got %d", len(approvals))
+		}
+	})
+
+	t.Run("approved bidders can bid", func(t *testing.T) {
+		rr := makeRequest(t, r, "PUT", decidePath, domain.DecideBidApprovalRequest{Status: "approved"}, sellerToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+
+		rr = makeRequest(t, r, "POST", bidsPath, domain.PlaceBidRequest{Amount: "1050.00"}, bidderToken)
+		if rr.Code != http.StatusCreated {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusCreated)
+		}
+	})
+
+	t.Run("decided requests can't be resent", func(t *testing.T) {
+		rr := makeRequest(t, r, "POST", approvalsPath, domain.RequestBidApprovalRequest{Answers: []string{"A while"}}, bidderToken)
+		if rr.Code != http.StatusConflict {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusConflict)
+		}
+	})
+
+	t.Run("denied bidders can't bid", func(t *testing.T) {
+		rr := makeRequest(t, r, "PUT", decidePath, domain.DecideBidApprovalRequest{Status: "denied"}, sellerToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+
+		rr = makeRequest(t, r, "POST", bidsPath, domain.PlaceBidRequest{Amount: "1100.00"}, bidderToken)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusForbidden)
+		}
+	})
+}
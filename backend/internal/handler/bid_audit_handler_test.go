@@ -0,0 +1,231 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock bid event repository
+type mockBidEventRepo struct {
+	mu     sync.Mutex
+	events []domain.BidEvent
+}
+
+func (r *mockBidEventRepo) Create(ctx context.Context, event *domain.BidEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event.ID = uuid.New()
+	event.CreatedAt = time.Now()
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *mockBidEventRepo) ListByAuction(ctx context.Context, auctionID uuid.UUID) ([]domain.BidEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := make([]domain.BidEvent, 0)
+	for _, event := range r.events {
+		if event.AuctionID == auctionID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func TestBidHandler_AdminGetBidAudit(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	eventRepo := &mockBidEventRepo{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	bidService := service.NewBidService(
+		newMockBidRepo(),
+		auctionRepo,
+		nil,
+		nil,
+		&mockAuditLogRepo{},
+		eventRepo,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	bidHandler := handler.NewBidHandler(bidService)
+
+	r := createTestRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Post("/api/auctions/{id}/bids", bidHandler.PlaceBid)
+		r.Post("/api/auctions/{id}/bids/{bidId}/cancel", bidHandler.CancelBid)
+		r.With(authMiddleware.RequireAdmin).Get("/api/admin/auctions/{id}/bid-audit", bidHandler.AdminGetBidAudit)
+	})
+
+	sellerID := uuid.New()
+	sellerToken, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+	bidderToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+	adminToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "admin")
+
+	newAuction := func(endsIn time.Duration) *domain.Auction {
+		auction := &domain.Auction{
+			SellerID:      sellerID,
+			Title:         "Pikachu Illustrator",
+			StartingPrice: decimal.NewFromFloat(100),
+			CurrentPrice:  decimal.NewFromFloat(100),
+			BidIncrement:  decimal.NewFromFloat(5),
+			StartTime:     time.Now().Add(-1 * time.Hour),
+			EndTime:       time.Now().Add(endsIn),
+			Status:        domain.AuctionStatusActive,
+		}
+		auctionRepo.Create(context.Background(), auction)
+		return auction
+	}
+
+	// placeBid bids from a browser behind the proxy
+	placeBid := func(t *testing.T, auction *domain.Auction, body map[string]string) *domain.Bid {
+		t.Helper()
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", "/api/auctions/"+auction.ID.String()+"/bids", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+bidderToken)
+		req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+		req.Header.Set("User-Agent", "Mozilla/5.0 (bid audit test)")
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		var response struct {
+			Data domain.BidResponse `json:"data"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to decode bid: %v", err)
+		}
+		return response.Data.Bid
+	}
+
+	getAudit := func(t *testing.T, auction *domain.Auction) domain.BidAudit {
+		t.Helper()
+		rr := makeRequest(t, r, "GET", "/api/admin/auctions/"+auction.ID.String()+"/bid-audit", nil, adminToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var audit domain.BidAudit
+		data, _ := json.Marshal(parseResponse(t, rr).Data)
+		if err := json.Unmarshal(data, &audit); err != nil {
+			t.Fatalf("failed to decode audit: %v", err)
+		}
+		return audit
+	}
+
+	eventTypes := func(audit domain.BidAudit) []domain.BidEventType {
+		types := make([]domain.BidEventType, 0, len(audit.Events))
+		for _, event := range audit.Events {
+			types = append(types, event.Type)
+		}
+		return types
+	}
+
+	auction := newAuction(24 * time.Hour)
+	bid := placeBid(t, auction, map[string]string{"amount": "110.00", "max_auto_bid": "150.00"})
+	rr := makeRequest(t, r, "POST", "/api/auctions/"+auction.ID.String()+"/bids/"+bid.ID.String()+"/cancel",
+		domain.CancelBidRequest{Reason: "Bidder asked to retract a typo"}, sellerToken)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 cancelling, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	t.Run("placements and cancellations", func(t *testing.T) {
+		audit := getAudit(t, auction)
+		types := eventTypes(audit)
+		if len(types) != 2 || types[0] != domain.BidEventPlaced || types[1] != domain.BidEventCancelled {
+			t.Fatalf("got events %v", types)
+		}
+
+		placed := audit.Events[0]
+		if placed.IP == nil || *placed.IP != "203.0.113.7" {
+			t.Errorf("expected the client's address, got %v", placed.IP)
+		}
+		if placed.UserAgent == nil || *placed.UserAgent != "Mozilla/5.0 (bid audit test)" {
+			t.Errorf("expected the user agent, got %v", placed.UserAgent)
+		}
+		if placed.MaxAutoBid == nil || !placed.MaxAutoBid.Equal(decimal.NewFromFloat(150)) {
+			t.Errorf("expected the proxy max, got %v", placed.MaxAutoBid)
+		}
+
+		cancelled := audit.Events[1]
+		if cancelled.ActorID == nil || *cancelled.ActorID != sellerID || cancelled.Reason == nil {
+			t.Errorf("expected the seller's cancellation with its reason, got %+v", cancelled)
+		}
+		if cancelled.BidID == nil || *cancelled.BidID != bid.ID {
+			t.Errorf("expected the cancelled bid, got %v", cancelled.BidID)
+		}
+	})
+
+	t.Run("extensions", func(t *testing.T) {
+		ending := newAuction(3 * time.Minute)
+		placeBid(t, ending, map[string]string{"amount": "105.00"})
+
+		audit := getAudit(t, ending)
+		types := eventTypes(audit)
+		if len(types) != 2 || types[0] != domain.BidEventPlaced || types[1] != domain.BidEventExtended {
+			t.Fatalf("got events %v", types)
+		}
+		// Three minutes were left, and the bid added two
+		if end := audit.Events[1].EndTime; end == nil || end.Before(time.Now().Add(4*time.Minute)) {
+			t.Errorf("expected the new end time, got %v", end)
+		}
+	})
+
+	t.Run("csv export", func(t *testing.T) {
+		rr := makeRequest(t, r, "GET", "/api/admin/auctions/"+auction.ID.String()+"/bid-audit?format=csv", nil, adminToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if contentType := rr.Header().Get("Content-Type"); contentType != "text/csv; charset=utf-8" {
+			t.Errorf("got content type %s", contentType)
+		}
+
+		rows, err := csv.NewReader(rr.Body).ReadAll()
+		if err != nil {
+			t.Fatalf("invalid csv: %v", err)
+		}
+		if len(rows) != 3 || rows[0][1] != "event" {
+			t.Fatalf("expected a header and 2 events, got %v", rows)
+		}
+		if placed := rows[1]; placed[1] != "placed" || placed[5] != "110.00" || placed[7] != "203.0.113.7" {
+			t.Errorf("got row %v", placed)
+		}
+	})
+
+	t.Run("admins only", func(t *testing.T) {
+		rr := makeRequest(t, r, "GET", "/api/admin/auctions/"+auction.ID.String()+"/bid-audit", nil, sellerToken)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rr.Code)
+		}
+	})
+
+	t.Run("unknown auction", func(t *testing.T) {
+		rr := makeRequest(t, r, "GET", "/api/admin/auctions/"+uuid.NewString()+"/bid-audit", nil, adminToken)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rr.Code)
+		}
+	})
+}
@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"encoding/csv"
 	"net/http"
+	"time"
 
 	"github.com/auction-cards/backend/internal/domain"
 	"github.com/auction-cards/backend/internal/service"
+	"github.com/shopspring/decimal"
 )
 
 type BidHandler struct {
@@ -34,7 +37,7 @@ func (h *BidHandler) PlaceBid(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userID := getUserID(r)
-	response, err := h.bidService.PlaceBid(r.Context(), auctionID, userID, &req)
+	response, err := h.bidService.PlaceBid(r.Context(), auctionID, userID, &req, bidClient(r))
 	if err != nil {
 		handleError(w, err)
 		return
@@ -50,10 +53,18 @@ func (h *BidHandler) GetBidsByAuction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	page := getQueryParamInt(r, "page", 1)
-	limit := getQueryParamInt(r, "limit", 20)
+	params := &domain.BidListParams{
+		Page:  getQueryParamInt(r, "page", 1),
+		Limit: getQueryParamInt(r, "limit", 20),
+	}
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		if params.Cursor, err = domain.DecodeCursor(cursor); err != nil {
+			handleError(w, err)
+			return
+		}
+	}
 
-	result, err := h.bidService.GetBidsByAuction(r.Context(), auctionID, page, limit)
+	result, err := h.bidService.GetBidsByAuction(r.Context(), auctionID, getUserID(r), r.URL.Query().Get("share"), params)
 	if err != nil {
 		handleError(w, err)
 		return
@@ -61,9 +72,10 @@ func (h *BidHandler) GetBidsByAuction(w http.ResponseWriter, r *http.Request) {
 
 	respondJSONWithMeta(w, http.StatusOK, result.Bids, &domain.APIMeta{
 		Page:       result.Page,
-		Limit:      limit,
+		Limit:      params.Limit,
 		TotalCount: result.TotalCount,
 		TotalPages: result.TotalPages,
+		NextCursor: result.NextCursor,
 	})
 }
 
@@ -94,7 +106,7 @@ func (h *BidHandler) BuyNow(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userID := getUserID(r)
-	response, err := h.bidService.BuyNow(r.Context(), auctionID, userID)
+	response, err := h.bidService.BuyNow(r.Context(), auctionID, userID, bidClient(r))
 	if err != nil {
 		handleError(w, err)
 		return
@@ -102,3 +114,168 @@ func (h *BidHandler) BuyNow(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, http.StatusOK, response)
 }
+
+func (h *BidHandler) CancelBid(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	bidID, err := getURLParamUUID(r, "bidId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid bid ID")
+		return
+	}
+
+	var req domain.CancelBidRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	userID := getUserID(r)
+	auction, err := h.bidService.CancelBid(r.Context(), auctionID, bidID, userID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, auction)
+}
+
+func (h *BidHandler) CancelAuction(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	var req domain.CancelAuctionRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	userID := getUserID(r)
+	auction, err := h.bidService.CancelAuction(r.Context(), auctionID, userID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, auction)
+}
+
+func (h *BidHandler) LowerReserve(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	var req domain.LowerReserveRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	userID := getUserID(r)
+	auction, err := h.bidService.LowerReserve(r.Context(), auctionID, userID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, auction)
+}
+
+// AdminGetBidAudit returns an auction's full bid trail, including where
+// each bid was placed from. ?format=csv downloads it as a spreadsheet.
+func (h *BidHandler) AdminGetBidAudit(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	audit, err := h.bidService.GetBidAudit(r.Context(), auctionID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if r.URL.Query().Get("format") != "csv" {
+		respondJSON(w, http.StatusOK, audit)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="auction-`+auctionID.String()+`-bid-audit.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	text := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	amount := func(d *decimal.Decimal) string {
+		if d == nil {
+			return ""
+		}
+		return d.StringFixed(2)
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"time", "event", "bid_id", "actor_id", "actor_username", "amount", "max_auto_bid", "ip", "user_agent", "reason", "end_time"})
+	for _, event := range audit.Events {
+		bidID, actorID, endTime := "", "", ""
+		if event.BidID != nil {
+			bidID = event.BidID.String()
+		}
+		if event.ActorID != nil {
+			actorID = event.ActorID.String()
+		}
+		if event.EndTime != nil {
+			endTime = event.EndTime.UTC().Format(time.RFC3339)
+		}
+		writer.Write([]string{
+			event.CreatedAt.UTC().Format(time.RFC3339Nano),
+			string(event.Type),
+			bidID,
+			actorID,
+			text(event.ActorUsername),
+			amount(event.Amount),
+			amount(event.MaxAutoBid),
+			text(event.IP),
+			text(event.UserAgent),
+			text(event.Reason),
+			endTime,
+		})
+	}
+	writer.Flush()
+}
+
+// bidClient describes the request placing a bid, for the bid trail
+func bidClient(r *http.Request) *domain.BidClient {
+	return &domain.BidClient{
+		IP:        clientAddress(r),
+		UserAgent: r.UserAgent(),
+	}
+}
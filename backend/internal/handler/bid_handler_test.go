@@ -2,7 +2,12 @@ package handler_test
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,18 +15,22 @@ import (
 	"github.com/auction-cards/backend/internal/handler"
 	"github.com/auction-cards/backend/internal/middleware"
 	"github.com/auction-cards/backend/internal/service"
+	ws "github.com/auction-cards/backend/internal/websocket"
 	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	"github.com/shopspring/decimal"
 )
 
 // Mock bid repository
 type mockBidRepo struct {
-	bids map[uuid.UUID]*domain.Bid
+	bids      map[uuid.UUID]*domain.Bid
+	cancelled map[uuid.UUID]int
 }
 
 func newMockBidRepo() *mockBidRepo {
 	return &mockBidRepo{
-		bids: make(map[uuid.UUID]*domain.Bid),
+		bids:      make(map[uuid.UUID]*domain.Bid),
+		cancelled: make(map[uuid.UUID]int),
 	}
 }
 
@@ -53,7 +62,7 @@ func (r *mockBidRepo) GetHighestBid(ctx context.Context, auctionID uuid.UUID) (*
 	return highest, nil
 }
 
-func (r *mockBidRepo) GetByAuctionID(ctx context.Context, auctionID uuid.UUID, page, limit int) ([]domain.Bid, int, error) {
+func (r *mockBidRepo) GetByAuctionID(ctx context.Context, auctionID uuid.UUID, params *domain.BidListParams) ([]domain.Bid, int, error) {
 	bids := make([]domain.Bid, 0)
 	for _, bid := range r.bids {
 		if bid.AuctionID == auctionID {
@@ -95,6 +104,80 @@ func (r *mockBidRepo) GetPreviousHighBidder(ctx context.Context, auctionID uuid.
 	return highest, nil
 }
 
+func (r *mockBidRepo) Cancel(ctx context.Context, id, cancelledBy uuid.UUID, reason string) error {
+	bid, ok := r.bids[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	r.cancelled[bid.AuctionID]++
+	delete(r.bids, id)
+	return nil
+}
+
+func (r *mockBidRepo) CancelAllForAuction(ctx context.Context, auctionID, cancelledBy uuid.UUID, reason string) ([]uuid.UUID, error) {
+	seen := make(map[uuid.UUID]bool)
+	var bidders []uuid.UUID
+	for id, bid := range r.bids {
+		if bid.AuctionID != auctionID {
+			continue
+		}
+		if !seen[bid.BidderID] {
+			seen[bid.BidderID] = true
+			bidders = append(bidders, bid.BidderID)
+		}
+		r.cancelled[auctionID]++
+		delete(r.bids, id)
+	}
+	return bidders, nil
+}
+
+func (r *mockBidRepo) GetCancelledCount(ctx context.Context, auctionID uuid.UUID) (int, error) {
+	return r.cancelled[auctionID], nil
+}
+
+func (r *mockBidRepo) GetStandings(ctx context.Context, bidderID uuid.UUID, auctionIDs []uuid.UUID) (map[uuid.UUID]domain.BidderStanding, error) {
+	standings := make(map[uuid.UUID]domain.BidderStanding)
+	for _, auctionID := range auctionIDs {
+		highest, _ := r.GetHighestBid(ctx, auctionID)
+		for _, bid := range r.bids {
+			if bid.AuctionID != auctionID || bid.BidderID != bidderID {
+				continue
+			}
+			standing := standings[auctionID]
+			if bid.Amount.GreaterThan(standing.MaxBid) {
+				standing.MaxBid = bid.Amount
+			}
+			standing.IsHighBidder = highest != nil && highest.BidderID == bidderID
+			if bid.MaxAutoBid != nil && (standing.MaxAutoBid == nil || bid.MaxAutoBid.GreaterThan(*standing.MaxAutoBid)) {
+				standing.MaxAutoBid = bid.MaxAutoBid
+			}
+			standings[auctionID] = standing
+		}
+	}
+	return standings, nil
+}
+
+// Mock audit log repository
+type mockAuditLogRepo struct {
+	entries []domain.AuditLog
+}
+
+func (r *mockAuditLogRepo) Create(ctx context.Context, entry *domain.AuditLog) error {
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *mockAuditLogRepo) List(ctx context.Context, params *domain.AuditLogListParams) ([]domain.AuditLog, int, error) {
+	return r.entries, len(r.entries), nil
+}
+
+// Mock transaction manager that runs the function without a transaction
+type mockTxManager struct{}
+
+func (m *mockTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
 func TestBidHandler_PlaceBid(t *testing.T) {
 	auctionRepo := newMockAuctionRepo()
 	bidRepo := newMockBidRepo()
@@ -121,7 +204,14 @@ func TestBidHandler_PlaceBid(t *testing.T) {
 		bidRepo,
 		auctionRepo,
 		nil,
-		nil, // no notification service for tests
+		nil,
+		nil,
+		nil,
+		&mockTxManager{},
+		nil,      // no notification service for tests
+		nil,      // no webhooks for tests
+		nil, nil, // no order service for tests
+		nil,
 		nil, // no redis for tests
 	)
 
@@ -162,6 +252,16 @@ func TestBidHandler_PlaceBid(t *testing.T) {
 			wantStatus: http.StatusBadRequest,
 			wantErr:    true,
 		},
+		{
+			name:      "fraction of a cent",
+			auctionID: auction.ID.String(),
+			body: domain.PlaceBidRequest{
+				Amount: "110.005",
+			},
+			token:      bidderToken,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
 		{
 			name:      "seller cannot bid on own auction",
 			auctionID: auction.ID.String(),
@@ -247,6 +347,14 @@ func TestBidHandler_GetBidsByAuction(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	r := createTestRouter()
@@ -254,9 +362,12 @@ func TestBidHandler_GetBidsByAuction(t *testing.T) {
 
 	r.Get("/api/auctions/{id}/bids", bidHandler.GetBidsByAuction)
 
+	cursor := (&domain.Cursor{CreatedAt: time.Now(), ID: uuid.New()}).Encode()
+
 	tests := []struct {
 		name       string
 		auctionID  string
+		query      string
 		wantStatus int
 		wantErr    bool
 	}{
@@ -272,11 +383,25 @@ func TestBidHandler_GetBidsByAuction(t *testing.T) {
 			wantStatus: http.StatusBadRequest,
 			wantErr:    true,
 		},
+		{
+			name:       "page by cursor",
+			auctionID:  auction.ID.String(),
+			query:      "?cursor=" + cursor,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:       "invalid cursor",
+			auctionID:  auction.ID.String(),
+			query:      "?cursor=not-a-cursor",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			rr := makeRequest(t, r, "GET", "/api/auctions/"+tt.auctionID+"/bids", nil, "")
+			rr := makeRequest(t, r, "GET", "/api/auctions/"+tt.auctionID+"/bids"+tt.query, nil, "")
 
 			if rr.Code != tt.wantStatus {
 				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
@@ -329,6 +454,14 @@ func TestBidHandler_GetMyBids(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	r := createTestRouter()
@@ -414,12 +547,36 @@ func TestBidHandler_BuyNow(t *testing.T) {
 	}
 	auctionRepo.Create(context.Background(), auctionNoBuyNow)
 
+	// Create auction whose buy now was withdrawn by the first bid
+	auctionWithBids := &domain.Auction{
+		SellerID:      sellerID,
+		Title:         "Test Auction Already Bid On",
+		StartingPrice: decimal.NewFromFloat(100),
+		CurrentPrice:  decimal.NewFromFloat(120),
+		BuyNowPrice:   &buyNowPrice,
+		BuyNowRule:    domain.BuyNowUntilFirstBid,
+		BidIncrement:  decimal.NewFromFloat(5),
+		StartTime:     time.Now().Add(-1 * time.Hour),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+		BidCount:      1,
+	}
+	auctionRepo.Create(context.Background(), auctionWithBids)
+
 	bidService := service.NewBidService(
 		bidRepo,
 		auctionRepo,
 		nil,
 		nil,
 		nil,
+		nil,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	r := createTestRouter()
@@ -451,6 +608,13 @@ func TestBidHandler_BuyNow(t *testing.T) {
 			wantStatus: http.StatusBadRequest,
 			wantErr:    true,
 		},
+		{
+			name:       "buy now withdrawn after first bid",
+			auctionID:  auctionWithBids.ID.String(),
+			token:      buyerToken,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
 		{
 			name:       "no authentication",
 			auctionID:  auctionNoBuyNow.ID.String(),
@@ -478,3 +642,1589 @@ func TestBidHandler_BuyNow(t *testing.T) {
 		})
 	}
 }
+
+// racingAuctionRepo hands out copies of auctions, as the database does, and
+// runs beforeWrite ahead of the next versioned write, so a test can slip a
+// competing change in between a read and the write based on it
+type racingAuctionRepo struct {
+	*mockAuctionRepo
+	beforeWrite func()
+}
+
+func (r *racingAuctionRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Auction, error) {
+	auction, err := r.mockAuctionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := *auction
+	return &snapshot, nil
+}
+
+func (r *racingAuctionRepo) UpdateWithVersion(ctx context.Context, auction *domain.Auction, expectedVersion int) error {
+	if hook := r.beforeWrite; hook != nil {
+		r.beforeWrite = nil
+		hook()
+	}
+	return r.mockAuctionRepo.UpdateWithVersion(ctx, auction, expectedVersion)
+}
+
+func TestBidHandler_BuyNowRaces(t *testing.T) {
+	auctionRepo := &racingAuctionRepo{mockAuctionRepo: newMockAuctionRepo()}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	bidService := service.NewBidService(
+		newMockBidRepo(),
+		auctionRepo,
+		nil,
+		nil,
+		nil,
+		nil,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/buy-now", handler.NewBidHandler(bidService).BuyNow)
+
+	newAuction := func() *domain.Auction {
+		buyNowPrice := decimal.NewFromFloat(500)
+		auction := &domain.Auction{
+			SellerID:      uuid.New(),
+			Title:         "Racing Buy Now",
+			StartingPrice: decimal.NewFromFloat(100),
+			CurrentPrice:  decimal.NewFromFloat(100),
+			BuyNowPrice:   &buyNowPrice,
+			BidIncrement:  decimal.NewFromFloat(5),
+			StartTime:     time.Now().Add(-1 * time.Hour),
+			EndTime:       time.Now().Add(24 * time.Hour),
+			Status:        domain.AuctionStatusActive,
+		}
+		auctionRepo.Create(context.Background(), auction)
+		return auction
+	}
+
+	buyNow := func(auctionID uuid.UUID, buyerID uuid.UUID) int {
+		token, _ := jwtManager.GenerateAccessToken(buyerID, "user")
+		return makeRequest(t, r, "POST", "/api/auctions/"+auctionID.String()+"/buy-now", nil, token).Code
+	}
+
+	t.Run("two buyers at once", func(t *testing.T) {
+		auction := newAuction()
+		first, second := uuid.New(), uuid.New()
+
+		var secondCode int
+		auctionRepo.beforeWrite = func() {
+			secondCode = buyNow(auction.ID, second)
+		}
+		firstCode := buyNow(auction.ID, first)
+
+		if secondCode != http.StatusOK {
+			t.Errorf("expected the buyer who got there first to win, got %d", secondCode)
+		}
+		if firstCode != http.StatusConflict {
+			t.Errorf("expected the other buyer to be told, got %d", firstCode)
+		}
+
+		stored, _ := auctionRepo.GetByID(context.Background(), auction.ID)
+		if stored.Status != domain.AuctionStatusCompleted || stored.WinnerID == nil || *stored.WinnerID != second {
+			t.Errorf("expected one winner, got status %s and winner %v", stored.Status, stored.WinnerID)
+		}
+	})
+
+	t.Run("scheduler closing the auction", func(t *testing.T) {
+		auction := newAuction()
+
+		auctionRepo.beforeWrite = func() {
+			stored, _ := auctionRepo.GetByID(context.Background(), auction.ID)
+			closed, err := auctionRepo.CloseIfActive(context.Background(), auction.ID, stored.Version, domain.AuctionStatusUnsold, nil, nil)
+			if !closed || err != nil {
+				t.Fatalf("expected the scheduler to close the auction, got %v, %v", closed, err)
+			}
+		}
+		if code := buyNow(auction.ID, uuid.New()); code != http.StatusConflict {
+			t.Errorf("expected the buy-now to lose, got %d", code)
+		}
+
+		stored, _ := auctionRepo.GetByID(context.Background(), auction.ID)
+		if stored.Status != domain.AuctionStatusUnsold || stored.WinnerID != nil {
+			t.Errorf("expected the scheduler's result to stand, got status %s and winner %v", stored.Status, stored.WinnerID)
+		}
+	})
+
+	t.Run("scheduler closing with a stale read", func(t *testing.T) {
+		auction := newAuction()
+		stale, _ := auctionRepo.GetByID(context.Background(), auction.ID)
+
+		if code := buyNow(auction.ID, uuid.New()); code != http.StatusOK {
+			t.Fatalf("buy-now got %d", code)
+		}
+
+		closed, err := auctionRepo.CloseIfActive(context.Background(), auction.ID, stale.Version, domain.AuctionStatusUnsold, nil, nil)
+		if closed || err != nil {
+			t.Errorf("expected the sold auction to stay sold, got %v, %v", closed, err)
+		}
+	})
+}
+
+// lockedAuctionRepo and lockedBidRepo stand in for the database under
+// concurrent bids: they are safe for concurrent use and hand out copies
+type lockedAuctionRepo struct {
+	*mockAuctionRepo
+	mu *sync.Mutex
+}
+
+func (r *lockedAuctionRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Auction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	auction, err := r.mockAuctionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := *auction
+	return &snapshot, nil
+}
+
+func (r *lockedAuctionRepo) GetByIDWithDetails(ctx context.Context, id uuid.UUID) (*domain.Auction, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *lockedAuctionRepo) UpdateWithVersion(ctx context.Context, auction *domain.Auction, expectedVersion int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *auction
+	if err := r.mockAuctionRepo.UpdateWithVersion(ctx, &stored, expectedVersion); err != nil {
+		return err
+	}
+	auction.Version = stored.Version
+	auction.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+type lockedBidRepo struct {
+	*mockBidRepo
+	mu *sync.Mutex
+	// afterCreate runs once a bid is saved, before the auction is updated
+	afterCreate func()
+}
+
+// mockTx records the bids created in a transaction
+type mockTx struct {
+	created []uuid.UUID
+}
+
+type mockTxKey struct{}
+
+func (r *lockedBidRepo) Create(ctx context.Context, bid *domain.Bid) error {
+	r.mu.Lock()
+	stored := *bid
+	err := r.mockBidRepo.Create(ctx, &stored)
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	bid.ID, bid.CreatedAt = stored.ID, stored.CreatedAt
+	if tx, ok := ctx.Value(mockTxKey{}).(*mockTx); ok {
+		tx.created = append(tx.created, bid.ID)
+	}
+	if r.afterCreate != nil {
+		r.afterCreate()
+	}
+	return nil
+}
+
+func (r *lockedBidRepo) GetHighestBid(ctx context.Context, auctionID uuid.UUID) (*domain.Bid, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	bid, err := r.mockBidRepo.GetHighestBid(ctx, auctionID)
+	if bid == nil || err != nil {
+		return nil, err
+	}
+	copied := *bid
+	return &copied, nil
+}
+
+func (r *lockedBidRepo) list(auctionID uuid.UUID) []domain.Bid {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	bids, _, _ := r.mockBidRepo.GetByAuctionID(context.Background(), auctionID, &domain.BidListParams{})
+	return bids
+}
+
+// rollbackTxManager undoes the bids created in a transaction that fails, as
+// the database does
+type rollbackTxManager struct {
+	bids *lockedBidRepo
+}
+
+func (m *rollbackTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx := &mockTx{}
+	err := fn(context.WithValue(ctx, mockTxKey{}, tx))
+	if err != nil {
+		m.bids.mu.Lock()
+		for _, id := range tx.created {
+			delete(m.bids.bids, id)
+		}
+		m.bids.mu.Unlock()
+	}
+	return err
+}
+
+// Many bidders hammer one auction at once. Bids that lose a race must leave
+// nothing behind, so every saved bid is counted on the auction and the price
+// is the highest of them.
+func TestBidHandler_PlaceBidConcurrently(t *testing.T) {
+	var mu sync.Mutex
+	auctionRepo := &lockedAuctionRepo{mockAuctionRepo: newMockAuctionRepo(), mu: &mu}
+	// Other bids get in between saving a bid and raising the price
+	bidRepo := &lockedBidRepo{mockBidRepo: newMockBidRepo(), mu: &mu, afterCreate: func() { time.Sleep(time.Millisecond) }}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	bidService := service.NewBidService(
+		bidRepo,
+		auctionRepo,
+		nil,
+		nil,
+		nil,
+		nil,
+		&rollbackTxManager{bids: bidRepo},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/bids", handler.NewBidHandler(bidService).PlaceBid)
+
+	auction := &domain.Auction{
+		SellerID:      uuid.New(),
+		Title:         "Hammered Auction",
+		StartingPrice: decimal.NewFromFloat(100),
+		CurrentPrice:  decimal.NewFromFloat(100),
+		BidIncrement:  decimal.NewFromFloat(5),
+		StartTime:     time.Now().Add(-1 * time.Hour),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+	}
+	auctionRepo.mockAuctionRepo.Create(context.Background(), auction)
+
+	const bidders, attempts = 20, 10
+	var accepted, conflicts, tooLow, unexpected sync.Map
+	var wg sync.WaitGroup
+	for i := 0; i < bidders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bidderID := uuid.New()
+			token, _ := jwtManager.GenerateAccessToken(bidderID, "user")
+			for j := 0; j < attempts; j++ {
+				current, _ := auctionRepo.GetByID(context.Background(), auction.ID)
+				amount := current.CurrentPrice.Add(current.BidIncrement)
+				rr := makeRequest(t, r, "POST", "/api/auctions/"+auction.ID.String()+"/bids", map[string]string{"amount": amount.StringFixed(2)}, token)
+				switch rr.Code {
+				case http.StatusCreated:
+					accepted.Store(uuid.New(), amount)
+				case http.StatusConflict:
+					conflicts.Store(uuid.New(), true)
+				case http.StatusBadRequest:
+					tooLow.Store(uuid.New(), true)
+				default:
+					unexpected.Store(uuid.New(), rr.Code)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	count := func(m *sync.Map) int {
+		n := 0
+		m.Range(func(_, _ any) bool { n++; return true })
+		return n
+	}
+	if n := count(&unexpected); n > 0 {
+		t.Fatalf("%d bids got an unexpected status", n)
+	}
+	if count(&conflicts) == 0 {
+		t.Error("expected some bids to lose a race")
+	}
+
+	stored, _ := auctionRepo.GetByID(context.Background(), auction.ID)
+	bids := bidRepo.list(auction.ID)
+	if len(bids) != count(&accepted) || stored.BidCount != len(bids) {
+		t.Errorf("%d bids accepted, %d saved and %d counted on the auction", count(&accepted), len(bids), stored.BidCount)
+	}
+
+	highest, _ := bidRepo.GetHighestBid(context.Background(), auction.ID)
+	if highest == nil || !stored.CurrentPrice.Equal(highest.Amount) {
+		t.Errorf("auction at %s but the highest bid is %v", stored.CurrentPrice, highest)
+	}
+	if want := auction.StartingPrice.Add(auction.BidIncrement.Mul(decimal.NewFromInt(int64(len(bids))))); !stored.CurrentPrice.Equal(want) {
+		t.Errorf("expected each bid to raise the price one increment to %s, got %s", want, stored.CurrentPrice)
+	}
+}
+
+func TestBidHandler_CancelBid(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	bidRepo := newMockBidRepo()
+	auditRepo := &mockAuditLogRepo{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	sellerID := uuid.New()
+	bidderID := uuid.New()
+
+	auction := &domain.Auction{
+		SellerID:      sellerID,
+		Title:         "Test Auction",
+		StartingPrice: decimal.NewFromFloat(100),
+		CurrentPrice:  decimal.NewFromFloat(10000),
+		BidIncrement:  decimal.NewFromFloat(5),
+		StartTime:     time.Now().Add(-1 * time.Hour),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+		BidCount:      2,
+	}
+	auctionRepo.Create(context.Background(), auction)
+
+	// Auction in its final hour
+	endingAuction := &domain.Auction{
+		SellerID:      sellerID,
+		Title:         "Ending Auction",
+		StartingPrice: decimal.NewFromFloat(100),
+		CurrentPrice:  decimal.NewFromFloat(120),
+		BidIncrement:  decimal.NewFromFloat(5),
+		StartTime:     time.Now().Add(-24 * time.Hour),
+		EndTime:       time.Now().Add(30 * time.Minute),
+		Status:        domain.AuctionStatusActive,
+		BidCount:      1,
+	}
+	auctionRepo.Create(context.Background(), endingAuction)
+
+	validBid := &domain.Bid{
+		AuctionID: auction.ID,
+		BidderID:  uuid.New(),
+		Amount:    decimal.NewFromFloat(110),
+	}
+	bidRepo.Create(context.Background(), validBid)
+
+	typoBid := &domain.Bid{
+		AuctionID: auction.ID,
+		BidderID:  bidderID,
+		Amount:    decimal.NewFromFloat(10000),
+	}
+	bidRepo.Create(context.Background(), typoBid)
+
+	endingBid := &domain.Bid{
+		AuctionID: endingAuction.ID,
+		BidderID:  bidderID,
+		Amount:    decimal.NewFromFloat(120),
+	}
+	bidRepo.Create(context.Background(), endingBid)
+
+	bidService := service.NewBidService(
+		bidRepo,
+		auctionRepo,
+		nil,
+		nil,
+		auditRepo,
+		nil,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	bidHandler := handler.NewBidHandler(bidService)
+
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/bids/{bidId}/cancel", bidHandler.CancelBid)
+
+	sellerToken, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+	bidderToken, _ := jwtManager.GenerateAccessToken(bidderID, "user")
+	body := domain.CancelBidRequest{Reason: "Bidder typed an extra two zeros"}
+
+	tests := []struct {
+		name       string
+		auctionID  string
+		bidID      string
+		body       interface{}
+		token      string
+		wantStatus int
+		wantErr    bool
+	}{
+		{
+			name:       "bidder cannot cancel",
+			auctionID:  auction.ID.String(),
+			bidID:      typoBid.ID.String(),
+			body:       body,
+			token:      bidderToken,
+			wantStatus: http.StatusForbidden,
+			wantErr:    true,
+		},
+		{
+			name:       "missing reason",
+			auctionID:  auction.ID.String(),
+			bidID:      typoBid.ID.String(),
+			body:       domain.CancelBidRequest{},
+			token:      sellerToken,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "final hour",
+			auctionID:  endingAuction.ID.String(),
+			bidID:      endingBid.ID.String(),
+			body:       body,
+			token:      sellerToken,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "bid from another auction",
+			auctionID:  auction.ID.String(),
+			bidID:      endingBid.ID.String(),
+			body:       body,
+			token:      sellerToken,
+			wantStatus: http.StatusNotFound,
+			wantErr:    true,
+		},
+		{
+			name:       "successful cancellation",
+			auctionID:  auction.ID.String(),
+			bidID:      typoBid.ID.String(),
+			body:       body,
+			token:      sellerToken,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := makeRequest(t, r, "POST", "/api/auctions/"+tt.auctionID+"/bids/"+tt.bidID+"/cancel", tt.body, tt.token)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+
+			response := parseResponse(t, rr)
+			if tt.wantErr && response.Success {
+				t.Errorf("expected error but got success")
+			}
+			if !tt.wantErr && !response.Success {
+				t.Errorf("expected success but got error: %v", response.Error)
+			}
+		})
+	}
+
+	updated, _ := auctionRepo.GetByID(context.Background(), auction.ID)
+	if !updated.CurrentPrice.Equal(decimal.NewFromFloat(110)) {
+		t.Errorf("expected current price to fall back to 110, got %s", updated.CurrentPrice)
+	}
+	if updated.BidCount != 1 {
+		t.Errorf("expected bid count 1, got %d", updated.BidCount)
+	}
+	if len(auditRepo.entries) != 1 || auditRepo.entries[0].Action != domain.AuditActionBidCancel {
+		t.Errorf("expected one bid cancellation audit entry, got %v", auditRepo.entries)
+	}
+}
+
+func TestBidHandler_CancelAuction(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	bidRepo := newMockBidRepo()
+	auditRepo := &mockAuditLogRepo{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	sellerID := uuid.New()
+	bidderID := uuid.New()
+
+	auction := &domain.Auction{
+		SellerID:      sellerID,
+		Title:         "Test Auction",
+		StartingPrice: decimal.NewFromFloat(100),
+		CurrentPrice:  decimal.NewFromFloat(115),
+		BidIncrement:  decimal.NewFromFloat(5),
+		StartTime:     time.Now().Add(-1 * time.Hour),
+		EndTime:       time.Now().Add(48 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+		BidCount:      2,
+	}
+	auctionRepo.Create(context.Background(), auction)
+
+	// Auction in its final 12 hours
+	endingAuction := &domain.Auction{
+		SellerID:      sellerID,
+		Title:         "Ending Auction",
+		StartingPrice: decimal.NewFromFloat(100),
+		CurrentPrice:  decimal.NewFromFloat(100),
+		BidIncrement:  decimal.NewFromFloat(5),
+		StartTime:     time.Now().Add(-24 * time.Hour),
+		EndTime:       time.Now().Add(6 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+	}
+	auctionRepo.Create(context.Background(), endingAuction)
+
+	for _, amount := range []float64{110, 115} {
+		bidRepo.Create(context.Background(), &domain.Bid{
+			AuctionID: auction.ID,
+			BidderID:  bidderID,
+			Amount:    decimal.NewFromFloat(amount),
+		})
+	}
+
+	bidService := service.NewBidService(
+		bidRepo,
+		auctionRepo,
+		nil,
+		nil,
+		auditRepo,
+		nil,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	bidHandler := handler.NewBidHandler(bidService)
+
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/cancel", bidHandler.CancelAuction)
+
+	sellerToken, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+	bidderToken, _ := jwtManager.GenerateAccessToken(bidderID, "user")
+	body := domain.CancelAuctionRequest{Reason: "Card was damaged in storage"}
+
+	tests := []struct {
+		name       string
+		auctionID  string
+		body       interface{}
+		token      string
+		wantStatus int
+		wantErr    bool
+	}{
+		{
+			name:       "bidder cannot cancel",
+			auctionID:  auction.ID.String(),
+			body:       body,
+			token:      bidderToken,
+			wantStatus: http.StatusForbidden,
+			wantErr:    true,
+		},
+		{
+			name:       "missing reason",
+			auctionID:  auction.ID.String(),
+			body:       domain.CancelAuctionRequest{},
+			token:      sellerToken,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "final 12 hours",
+			auctionID:  endingAuction.ID.String(),
+			body:       body,
+			token:      sellerToken,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "successful cancellation",
+			auctionID:  auction.ID.String(),
+			body:       body,
+			token:      sellerToken,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:       "already cancelled",
+			auctionID:  auction.ID.String(),
+			body:       body,
+			token:      sellerToken,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := makeRequest(t, r, "POST", "/api/auctions/"+tt.auctionID+"/cancel", tt.body, tt.token)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+
+			response := parseResponse(t, rr)
+			if tt.wantErr && response.Success {
+				t.Errorf("expected error but got success")
+			}
+			if !tt.wantErr && !response.Success {
+				t.Errorf("expected success but got error: %v", response.Error)
+			}
+		})
+	}
+
+	updated, _ := auctionRepo.GetByID(context.Background(), auction.ID)
+	if updated.Status != domain.AuctionStatusCancelled {
+		t.Errorf("expected auction to be cancelled, got %s", updated.Status)
+	}
+	if updated.BidCount != 0 || !updated.CurrentPrice.Equal(updated.StartingPrice) {
+		t.Errorf("expected bids to be voided, got count %d and price %s", updated.BidCount, updated.CurrentPrice)
+	}
+	if count, _ := bidRepo.GetBidCount(context.Background(), auction.ID); count != 0 {
+		t.Errorf("expected no active bids, got %d", count)
+	}
+	if len(auditRepo.entries) != 1 || auditRepo.entries[0].Action != domain.AuditActionAuctionCancel ||
+		auditRepo.entries[0].Reason == nil || *auditRepo.entries[0].Reason != body.Reason {
+		t.Errorf("expected one auction cancellation audit entry with the reason, got %v", auditRepo.entries)
+	}
+}
+
+func TestBidHandler_LowerReserve(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	bidRepo := newMockBidRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	sellerID := uuid.New()
+	reserve := decimal.NewFromFloat(500)
+
+	auction := &domain.Auction{
+		SellerID:      sellerID,
+		Title:         "Test Auction with Reserve",
+		StartingPrice: decimal.NewFromFloat(100),
+		ReservePrice:  &reserve,
+		CurrentPrice:  decimal.NewFromFloat(300),
+		BidIncrement:  decimal.NewFromFloat(5),
+		StartTime:     time.Now().Add(-1 * time.Hour),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+		BidCount:      1,
+	}
+	auctionRepo.Create(context.Background(), auction)
+
+	bidService := service.NewBidService(
+		bidRepo,
+		auctionRepo,
+		nil,
+		nil,
+		&mockAuditLogRepo{},
+		nil,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	bidHandler := handler.NewBidHandler(bidService)
+
+	r.With(authMiddleware.RequireAuth).Put("/api/auctions/{id}/reserve", bidHandler.LowerReserve)
+
+	sellerToken, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+	otherToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+
+	tests := []struct {
+		name       string
+		body       domain.LowerReserveRequest
+		token      string
+		wantStatus int
+		wantErr    bool
+	}{
+		{
+			name:       "not the seller",
+			body:       domain.LowerReserveRequest{ReservePrice: "250.00"},
+			token:      otherToken,
+			wantStatus: http.StatusForbidden,
+			wantErr:    true,
+		},
+		{
+			name:       "raising is rejected",
+			body:       domain.LowerReserveRequest{ReservePrice: "600.00"},
+			token:      sellerToken,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "below starting price",
+			body:       domain.LowerReserveRequest{ReservePrice: "50.00"},
+			token:      sellerToken,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "successful lowering",
+			body:       domain.LowerReserveRequest{ReservePrice: "250.00"},
+			token:      sellerToken,
+			wantStatus: http.StatusOK,
+			wantErr:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := makeRequest(t, r, "PUT", "/api/auctions/"+auction.ID.String()+"/reserve", tt.body, tt.token)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+
+			response := parseResponse(t, rr)
+			if tt.wantErr && response.Success {
+				t.Errorf("expected error but got success")
+			}
+			if !tt.wantErr && !response.Success {
+				t.Errorf("expected success but got error: %v", response.Error)
+			}
+		})
+	}
+
+	updated, _ := auctionRepo.GetByID(context.Background(), auction.ID)
+	if updated.ReservePrice == nil || !updated.ReservePrice.Equal(decimal.NewFromFloat(250)) {
+		t.Errorf("expected reserve price 250, got %v", updated.ReservePrice)
+	}
+}
+
+// Mock snipe repository
+type mockSnipeRepo struct {
+	auctionRepo *mockAuctionRepo
+	snipes      map[uuid.UUID]*domain.Snipe
+}
+
+func newMockSnipeRepo(auctionRepo *mockAuctionRepo) *mockSnipeRepo {
+	return &mockSnipeRepo{
+		auctionRepo: auctionRepo,
+		snipes:      make(map[uuid.UUID]*domain.Snipe),
+	}
+}
+
+func (r *mockSnipeRepo) Upsert(ctx context.Context, snipe *domain.Snipe) error {
+	snipe.Status = domain.SnipeStatusScheduled
+	if existing, err := r.GetScheduled(ctx, snipe.AuctionID, snipe.UserID); err == nil {
+		snipe.ID = existing.ID
+	} else {
+		snipe.ID = uuid.New()
+	}
+	r.snipes[snipe.ID] = snipe
+	return nil
+}
+
+func (r *mockSnipeRepo) GetScheduled(ctx context.Context, auctionID, userID uuid.UUID) (*domain.Snipe, error) {
+	for _, snipe := range r.snipes {
+		if snipe.AuctionID == auctionID && snipe.UserID == userID && snipe.Status == domain.SnipeStatusScheduled {
+			return snipe, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockSnipeRepo) GetByUser(ctx context.Context, userID uuid.UUID) ([]domain.Snipe, error) {
+	var snipes []domain.Snipe
+	for _, snipe := range r.snipes {
+		if snipe.UserID == userID {
+			snipes = append(snipes, *snipe)
+		}
+	}
+	return snipes, nil
+}
+
+func (r *mockSnipeRepo) GetDue(ctx context.Context, now time.Time) ([]domain.Snipe, error) {
+	var snipes []domain.Snipe
+	for _, snipe := range r.snipes {
+		auction, err := r.auctionRepo.GetByID(ctx, snipe.AuctionID)
+		if err != nil || snipe.Status != domain.SnipeStatusScheduled {
+			continue
+		}
+		if !auction.EndTime.Add(-time.Duration(snipe.LeadSeconds) * time.Second).After(now) {
+			snipes = append(snipes, *snipe)
+		}
+	}
+	return snipes, nil
+}
+
+func (r *mockSnipeRepo) Claim(ctx context.Context, id uuid.UUID) (bool, error) {
+	snipe, ok := r.snipes[id]
+	if !ok || snipe.Status != domain.SnipeStatusScheduled {
+		return false, nil
+	}
+	snipe.Status = domain.SnipeStatusExecuting
+	return true, nil
+}
+
+func (r *mockSnipeRepo) Complete(ctx context.Context, snipe *domain.Snipe) error {
+	stored := *snipe
+	r.snipes[snipe.ID] = &stored
+	return nil
+}
+
+func (r *mockSnipeRepo) Cancel(ctx context.Context, id uuid.UUID) error {
+	snipe, ok := r.snipes[id]
+	if !ok || snipe.Status != domain.SnipeStatusScheduled {
+		return domain.ErrNotFound
+	}
+	snipe.Status = domain.SnipeStatusCancelled
+	return nil
+}
+
+// Mock watchlist repository
+type mockWatchlistRepo struct {
+	items map[uuid.UUID]map[uuid.UUID]bool
+}
+
+func newMockWatchlistRepo() *mockWatchlistRepo {
+	return &mockWatchlistRepo{items: make(map[uuid.UUID]map[uuid.UUID]bool)}
+}
+
+func (r *mockWatchlistRepo) Add(ctx context.Context, item *domain.WatchlistItem) error {
+	if r.items[item.UserID] == nil {
+		r.items[item.UserID] = make(map[uuid.UUID]bool)
+	}
+	r.items[item.UserID][item.AuctionID] = true
+	return nil
+}
+
+func (r *mockWatchlistRepo) Remove(ctx context.Context, userID, auctionID uuid.UUID) error {
+	delete(r.items[userID], auctionID)
+	return nil
+}
+
+func (r *mockWatchlistRepo) GetByUser(ctx context.Context, userID uuid.UUID, page, limit int) ([]domain.WatchlistItem, int, error) {
+	return []domain.WatchlistItem{}, 0, nil
+}
+
+func (r *mockWatchlistRepo) Exists(ctx context.Context, userID, auctionID uuid.UUID) (bool, error) {
+	return r.items[userID][auctionID], nil
+}
+
+func (r *mockWatchlistRepo) GetWatched(ctx context.Context, userID uuid.UUID, auctionIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	watched := make(map[uuid.UUID]bool)
+	for _, auctionID := range auctionIDs {
+		if r.items[userID][auctionID] {
+			watched[auctionID] = true
+		}
+	}
+	return watched, nil
+}
+
+func (r *mockWatchlistRepo) GetWatchersForAuction(ctx context.Context, auctionID uuid.UUID) ([]uuid.UUID, error) {
+	watchers := []uuid.UUID{}
+	for userID, auctions := range r.items {
+		if auctions[auctionID] {
+			watchers = append(watchers, userID)
+		}
+	}
+	return watchers, nil
+}
+
+func TestSnipeHandler_Schedule(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	bidRepo := newMockBidRepo()
+	snipeRepo := newMockSnipeRepo(auctionRepo)
+	watchlistRepo := newMockWatchlistRepo()
+	auditRepo := &mockAuditLogRepo{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	sellerID := uuid.New()
+	bidderID := uuid.New()
+
+	auction := &domain.Auction{
+		SellerID:      sellerID,
+		Title:         "Test Auction",
+		StartingPrice: decimal.NewFromFloat(100),
+		CurrentPrice:  decimal.NewFromFloat(100),
+		BidIncrement:  decimal.NewFromFloat(5),
+		StartTime:     time.Now().Add(-1 * time.Hour),
+		EndTime:       time.Now().Add(1 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+	}
+	auctionRepo.Create(context.Background(), auction)
+
+	endingAuction := &domain.Auction{
+		SellerID:      sellerID,
+		Title:         "Ending Auction",
+		StartingPrice: decimal.NewFromFloat(100),
+		CurrentPrice:  decimal.NewFromFloat(100),
+		BidIncrement:  decimal.NewFromFloat(5),
+		StartTime:     time.Now().Add(-1 * time.Hour),
+		EndTime:       time.Now().Add(3 * time.Second),
+		Status:        domain.AuctionStatusActive,
+	}
+	auctionRepo.Create(context.Background(), endingAuction)
+
+	bidService := service.NewBidService(
+		bidRepo,
+		auctionRepo,
+		nil,
+		nil,
+		auditRepo,
+		nil,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	snipeService := service.NewSnipeService(
+		snipeRepo,
+		auctionRepo,
+		bidRepo,
+		watchlistRepo,
+		auditRepo,
+		bidService,
+		nil,
+	)
+
+	r := createTestRouter()
+	snipeHandler := handler.NewSnipeHandler(snipeService)
+
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/snipe", snipeHandler.Schedule)
+
+	sellerToken, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+	bidderToken, _ := jwtManager.GenerateAccessToken(bidderID, "user")
+	lead := 10
+
+	tests := []struct {
+		name       string
+		auctionID  uuid.UUID
+		body       domain.ScheduleSnipeRequest
+		token      string
+		wantStatus int
+		wantErr    bool
+	}{
+		{
+			name:       "seller cannot snipe own auction",
+			auctionID:  auction.ID,
+			body:       domain.ScheduleSnipeRequest{MaxAmount: "150.00"},
+			token:      sellerToken,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "max below next minimum bid",
+			auctionID:  auction.ID,
+			body:       domain.ScheduleSnipeRequest{MaxAmount: "102.00"},
+			token:      bidderToken,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "lead time out of range",
+			auctionID:  auction.ID,
+			body:       domain.ScheduleSnipeRequest{MaxAmount: "150.00", LeadSeconds: intPtr(600)},
+			token:      bidderToken,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "auction ends inside lead time",
+			auctionID:  endingAuction.ID,
+			body:       domain.ScheduleSnipeRequest{MaxAmount: "150.00", LeadSeconds: &lead},
+			token:      bidderToken,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    true,
+		},
+		{
+			name:       "successful schedule",
+			auctionID:  auction.ID,
+			body:       domain.ScheduleSnipeRequest{MaxAmount: "150.00", LeadSeconds: &lead},
+			token:      bidderToken,
+			wantStatus: http.StatusCreated,
+			wantErr:    false,
+		},
+		{
+			name:       "unauthorized",
+			auctionID:  auction.ID,
+			body:       domain.ScheduleSnipeRequest{MaxAmount: "150.00"},
+			token:      "",
+			wantStatus: http.StatusUnauthorized,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := makeRequest(t, r, "POST", "/api/auctions/"+tt.auctionID.String()+"/snipe", tt.body, tt.token)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+
+			response := parseResponse(t, rr)
+			if tt.wantErr && response.Success {
+				t.Errorf("expected error but got success")
+			}
+			if !tt.wantErr && !response.Success {
+				t.Errorf("expected success but got error: %v", response.Error)
+			}
+		})
+	}
+
+	if watched, _ := watchlistRepo.Exists(context.Background(), bidderID, auction.ID); !watched {
+		t.Errorf("expected auction to be added to the watchlist")
+	}
+
+	// Move the auction inside the snipe's lead time and let it fire
+	originalEnd := time.Now().Add(5 * time.Second)
+	auction.EndTime = originalEnd
+	snipeService.ProcessDue(context.Background())
+	snipeService.ProcessDue(context.Background())
+
+	snipe, err := snipeRepo.GetByUser(context.Background(), bidderID)
+	if err != nil || len(snipe) != 1 {
+		t.Fatalf("expected one snipe, got %v (%v)", len(snipe), err)
+	}
+	if snipe[0].Status != domain.SnipeStatusPlaced || snipe[0].BidID == nil {
+		t.Errorf("expected snipe to be placed, got %s", snipe[0].Status)
+	}
+
+	updated, _ := auctionRepo.GetByID(context.Background(), auction.ID)
+	if !updated.CurrentPrice.Equal(decimal.NewFromFloat(105)) || updated.BidCount != 1 {
+		t.Errorf("expected a single bid at 105, got %s with %d bids", updated.CurrentPrice, updated.BidCount)
+	}
+	if !updated.EndTime.After(originalEnd) {
+		t.Errorf("expected the snipe to trigger the anti-sniping extension")
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestBidHandler_PlaceBidReserveSignal(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	bidRepo := newMockBidRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	reserve := decimal.NewFromFloat(200)
+	auction := &domain.Auction{
+		SellerID:      uuid.New(),
+		Title:         "Test Auction with Reserve",
+		StartingPrice: decimal.NewFromFloat(100),
+		ReservePrice:  &reserve,
+		CurrentPrice:  decimal.NewFromFloat(100),
+		BidIncrement:  decimal.NewFromFloat(5),
+		StartTime:     time.Now().Add(-1 * time.Hour),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+	}
+	auctionRepo.Create(context.Background(), auction)
+
+	bidService := service.NewBidService(
+		bidRepo,
+		auctionRepo,
+		nil,
+		nil,
+		nil,
+		nil,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	bidHandler := handler.NewBidHandler(bidService)
+
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/bids", bidHandler.PlaceBid)
+
+	bidderToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+
+	tests := []struct {
+		name           string
+		amount         string
+		wantReserveMet bool
+	}{
+		{
+			name:           "bid below reserve",
+			amount:         "150.00",
+			wantReserveMet: false,
+		},
+		{
+			name:           "bid meets reserve",
+			amount:         "200.00",
+			wantReserveMet: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := makeRequest(t, r, "POST", "/api/auctions/"+auction.ID.String()+"/bids", domain.PlaceBidRequest{Amount: tt.amount}, bidderToken)
+
+			if rr.Code != http.StatusCreated {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusCreated)
+			}
+
+			response := parseResponse(t, rr)
+			data, _ := response.Data.(map[string]interface{})
+			result, _ := data["auction"].(map[string]interface{})
+
+			if result["has_reserve"] != true {
+				t.Errorf("expected has_reserve to be true")
+			}
+			if result["reserve_met"] != tt.wantReserveMet {
+				t.Errorf("expected reserve_met=%v, got %v", tt.wantReserveMet, result["reserve_met"])
+			}
+			if _, ok := result["reserve_price"]; ok {
+				t.Errorf("reserve price must not be revealed to bidders")
+			}
+		})
+	}
+}
+
+func TestBidHandler_PlaceBidIncrementLadder(t *testing.T) {
+	defer func(ladder domain.IncrementLadder) { domain.BidIncrementLadder = ladder }(domain.BidIncrementLadder)
+	ladder, err := domain.ParseIncrementLadder("25:0.50, 100:1, 1000:10")
+	if err != nil {
+		t.Fatalf("failed to parse ladder: %v", err)
+	}
+	domain.BidIncrementLadder = ladder
+
+	for _, invalid := range []string{"25", "25:0", "lots:1", "100:1,25:0.50", "25:0.001"} {
+		if _, err := domain.ParseIncrementLadder(invalid); err == nil {
+			t.Errorf("expected ladder %q to be rejected", invalid)
+		}
+	}
+
+	auctionRepo := newMockAuctionRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	newAuction := func(price, increment string) *domain.Auction {
+		auction := &domain.Auction{
+			SellerID:      uuid.New(),
+			Title:         "Charizard Base Set",
+			StartingPrice: decimal.RequireFromString(price),
+			CurrentPrice:  decimal.RequireFromString(price),
+			BidIncrement:  decimal.RequireFromString(increment),
+			StartTime:     time.Now().Add(-1 * time.Hour),
+			EndTime:       time.Now().Add(24 * time.Hour),
+			Status:        domain.AuctionStatusActive,
+		}
+		auctionRepo.Create(context.Background(), auction)
+		return auction
+	}
+
+	bidService := service.NewBidService(
+		newMockBidRepo(),
+		auctionRepo,
+		nil,
+		nil,
+		nil,
+		nil,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/bids", handler.NewBidHandler(bidService).PlaceBid)
+
+	bidderToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+
+	// placeBid bids and returns the auction's minimum next bid afterwards
+	placeBid := func(t *testing.T, auction *domain.Auction, amount string, wantStatus int) decimal.Decimal {
+		t.Helper()
+		rr := makeRequest(t, r, "POST", "/api/auctions/"+auction.ID.String()+"/bids", domain.PlaceBidRequest{Amount: amount}, bidderToken)
+		if rr.Code != wantStatus {
+			t.Fatalf("bid of %s: got status %d, want %d", amount, rr.Code, wantStatus)
+		}
+		if wantStatus != http.StatusCreated {
+			return decimal.Zero
+		}
+
+		data, _ := parseResponse(t, rr).Data.(map[string]interface{})
+		result, _ := data["auction"].(map[string]interface{})
+		value, _ := result["min_next_bid"].(string)
+		minNextBid, err := decimal.NewFromString(value)
+		if err != nil {
+			t.Fatalf("bad min_next_bid %v", result["min_next_bid"])
+		}
+		return minNextBid
+	}
+
+	t.Run("increments grow with the price", func(t *testing.T) {
+		auction := newAuction("20.00", "0.50")
+
+		steps := []struct {
+			amount     string
+			wantStatus int
+			wantNext   string
+		}{
+			{amount: "20.25", wantStatus: http.StatusBadRequest},
+			{amount: "20.50", wantStatus: http.StatusCreated, wantNext: "21.00"},
+			{amount: "24.50", wantStatus: http.StatusCreated, wantNext: "25.00"},
+			{amount: "25.00", wantStatus: http.StatusCreated, wantNext: "26.00"},
+			{amount: "25.50", wantStatus: http.StatusBadRequest},
+			{amount: "99.50", wantStatus: http.StatusCreated, wantNext: "100.50"},
+			{amount: "100.50", wantStatus: http.StatusCreated, wantNext: "110.50"},
+			{amount: "105.00", wantStatus: http.StatusBadRequest},
+			{amount: "999.00", wantStatus: http.StatusCreated, wantNext: "1009.00"},
+			// Above the last bound its increment still applies
+			{amount: "1009.00", wantStatus: http.StatusCreated, wantNext: "1019.00"},
+		}
+		for _, step := range steps {
+			next := placeBid(t, auction, step.amount, step.wantStatus)
+			if step.wantNext != "" && !next.Equal(decimal.RequireFromString(step.wantNext)) {
+				t.Errorf("after a bid of %s: got min_next_bid %s, want %s", step.amount, next, step.wantNext)
+			}
+		}
+	})
+
+	t.Run("a larger listing increment wins", func(t *testing.T) {
+		auction := newAuction("200.00", "25.00")
+
+		placeBid(t, auction, "210.00", http.StatusBadRequest)
+		if next := placeBid(t, auction, "225.00", http.StatusCreated); !next.Equal(decimal.RequireFromString("250.00")) {
+			t.Errorf("got min_next_bid %s, want 250.00", next)
+		}
+	})
+}
+
+func TestBidHandler_PlaceBidSellerOnVacation(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	bidRepo := newMockBidRepo()
+	userRepo := newMockUserRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	startsAt := time.Now().Add(-1 * time.Hour)
+	endsAt := time.Now().Add(7 * 24 * time.Hour)
+	seller := &domain.User{
+		Username: "awayseller",
+		Vacation: domain.VacationSettings{
+			Enabled:     true,
+			StartsAt:    &startsAt,
+			EndsAt:      &endsAt,
+			ListingMode: domain.VacationListingBanner,
+		},
+	}
+	userRepo.Create(context.Background(), seller)
+
+	auction := &domain.Auction{
+		SellerID:      seller.ID,
+		Title:         "Test Auction",
+		StartingPrice: decimal.NewFromFloat(100),
+		CurrentPrice:  decimal.NewFromFloat(100),
+		BidIncrement:  decimal.NewFromFloat(5),
+		StartTime:     time.Now().Add(-1 * time.Hour),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+	}
+	auctionRepo.Create(context.Background(), auction)
+
+	bidService := service.NewBidService(
+		bidRepo,
+		auctionRepo,
+		userRepo,
+		nil,
+		nil,
+		nil,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	bidHandler := handler.NewBidHandler(bidService)
+
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/bids", bidHandler.PlaceBid)
+
+	bidderToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+
+	tests := []struct {
+		name       string
+		blockBids  bool
+		amount     string
+		wantStatus int
+	}{
+		{
+			name:       "bids allowed while away",
+			blockBids:  false,
+			amount:     "110.00",
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "bids blocked while away",
+			blockBids:  true,
+			amount:     "120.00",
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seller.Vacation.BlockBids = tt.blockBids
+
+			rr := makeRequest(t, r, "POST", "/api/auctions/"+auction.ID.String()+"/bids", domain.PlaceBidRequest{Amount: tt.amount}, bidderToken)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// Mock notification repository. Notifications are created in the
+// background, so it is safe for concurrent use.
+type mockNotificationRepo struct {
+	mu            sync.Mutex
+	notifications []domain.Notification
+}
+
+func (r *mockNotificationRepo) Create(ctx context.Context, notification *domain.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if notification.ID == uuid.Nil {
+		notification.ID = uuid.New()
+	}
+	if notification.CreatedAt.IsZero() {
+		notification.CreatedAt = time.Now()
+	}
+	r.notifications = append(r.notifications, *notification)
+	return nil
+}
+
+func (r *mockNotificationRepo) CreateBatch(ctx context.Context, notifications []domain.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notifications = append(r.notifications, notifications...)
+	return nil
+}
+
+func (r *mockNotificationRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Notification, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockNotificationRepo) GetByUserID(ctx context.Context, userID uuid.UUID, params *domain.NotificationListParams) ([]domain.Notification, int, int, error) {
+	return nil, 0, 0, nil
+}
+
+func (r *mockNotificationRepo) MarkAsRead(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (r *mockNotificationRepo) GetThreadsByUserID(ctx context.Context, userID uuid.UUID, params *domain.NotificationListParams) ([]domain.NotificationThread, int, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	byThread := make(map[uuid.UUID]*domain.NotificationThread)
+	unreadCount := 0
+	for _, n := range r.notifications {
+		if n.UserID != userID {
+			continue
+		}
+		threadID := n.ID
+		if n.AuctionID != nil {
+			threadID = *n.AuctionID
+		}
+		t, ok := byThread[threadID]
+		if !ok {
+			t = &domain.NotificationThread{ID: threadID, AuctionID: n.AuctionID, Latest: n}
+			byThread[threadID] = t
+		}
+		t.Count++
+		if !n.IsRead {
+			t.UnreadCount++
+			unreadCount++
+		}
+		if n.CreatedAt.After(t.Latest.CreatedAt) {
+			t.Latest = n
+		}
+	}
+
+	threads := make([]domain.NotificationThread, 0, len(byThread))
+	for _, t := range byThread {
+		if params.Unread != nil && *params.Unread && t.UnreadCount == 0 {
+			continue
+		}
+		threads = append(threads, *t)
+	}
+	sort.Slice(threads, func(i, j int) bool {
+		return threads[i].Latest.CreatedAt.After(threads[j].Latest.CreatedAt)
+	})
+
+	total := len(threads)
+	start := min((params.Page-1)*params.Limit, total)
+	end := min(start+params.Limit, total)
+	return threads[start:end], total, unreadCount, nil
+}
+
+func (r *mockNotificationRepo) MarkThreadAsRead(ctx context.Context, userID, threadID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	marked := false
+	for i, n := range r.notifications {
+		if n.UserID == userID && ((n.AuctionID != nil && *n.AuctionID == threadID) || (n.AuctionID == nil && n.ID == threadID)) {
+			r.notifications[i].IsRead = true
+			marked = true
+		}
+	}
+	if !marked {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *mockNotificationRepo) MarkAllAsRead(ctx context.Context, userID uuid.UUID) error {
+	return nil
+}
+
+func (r *mockNotificationRepo) GetUnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	return 0, nil
+}
+
+func (r *mockNotificationRepo) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.notifications)
+}
+
+func TestBidHandler_OutbidPush(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	bidRepo := newMockBidRepo()
+	notificationRepo := &mockNotificationRepo{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	messageHub := ws.NewMessageHub(nil)
+	go messageHub.Run()
+	defer messageHub.Stop()
+
+	notificationService := service.NewNotificationService(
+		notificationRepo,
+		newMockUserRepo(),
+		newMockWatchlistRepo(),
+		nil,
+		nil,
+		nil,
+		nil,
+		&mockEmailSender{},
+		nil,
+		messageHub,
+		nil,
+		nil,
+		"http://localhost",
+	)
+	bidService := service.NewBidService(
+		bidRepo,
+		auctionRepo,
+		nil,
+		nil,
+		nil,
+		nil,
+		&mockTxManager{},
+		notificationService,
+		nil,      // no webhooks for tests
+		nil, nil, // no order service for tests
+		nil,
+		nil, // no redis for tests
+	)
+
+	r := createTestRouter()
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/bids", handler.NewBidHandler(bidService).PlaceBid)
+	r.With(authMiddleware.RequireAuth).Get("/ws/messages", handler.NewMessageWebSocketHandler(messageHub).HandleMessageWS)
+
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	auction := &domain.Auction{
+		SellerID:      uuid.New(),
+		Title:         "Charizard Holo",
+		Currency:      domain.CurrencyEUR,
+		StartingPrice: decimal.NewFromFloat(100),
+		CurrentPrice:  decimal.NewFromFloat(100),
+		BidIncrement:  decimal.NewFromFloat(5),
+		StartTime:     time.Now().Add(-1 * time.Hour),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+	}
+	auctionRepo.Create(context.Background(), auction)
+
+	firstBidderID := uuid.New()
+	firstToken, _ := jwtManager.GenerateAccessToken(firstBidderID, "user")
+	secondToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+firstToken)
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws/messages", header)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	reader := &wsTestReader{conn: conn}
+
+	rr := makeRequest(t, r, "POST", "/api/auctions/"+auction.ID.String()+"/bids", domain.PlaceBidRequest{Amount: "110.00"}, firstToken)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("first bid got status %d", rr.Code)
+	}
+
+	// Wait for the seller's new bid notification, so the first bid's
+	// background work is done with the auction
+	deadline := time.Now().Add(time.Second)
+	for notificationRepo.count() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the seller to be notified of the first bid")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	rr = makeRequest(t, r, "POST", "/api/auctions/"+auction.ID.String()+"/bids", domain.PlaceBidRequest{Amount: "120.00"}, secondToken)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("second bid got status %d", rr.Code)
+	}
+
+	for {
+		var msg domain.MessageWSPayload
+		if err := json.Unmarshal(reader.next(t), &msg); err != nil {
+			t.Fatalf("invalid message: %v", err)
+		}
+		if msg.Type != domain.MessageWSTypeOutbid {
+			continue
+		}
+
+		if msg.Outbid == nil || msg.Outbid.AuctionID != auction.ID {
+			t.Fatalf("got outbid push %+v", msg.Outbid)
+		}
+		if msg.Outbid.Amount.String() != "€120.00" {
+			t.Errorf("got amount %s", msg.Outbid.Amount)
+		}
+		if msg.Outbid.MinimumBid.String() != "€125.00" {
+			t.Errorf("got minimum bid %s", msg.Outbid.MinimumBid)
+		}
+		break
+	}
+}
@@ -0,0 +1,218 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock rating repository serving fixed rating summaries
+type mockRatingRepo struct {
+	summaries map[uuid.UUID]domain.UserRatingSummary
+}
+
+func (r *mockRatingRepo) Create(ctx context.Context, rating *domain.Rating) error {
+	return nil
+}
+
+func (r *mockRatingRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Rating, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockRatingRepo) GetByAuctionAndRater(ctx context.Context, auctionID, raterID uuid.UUID, ratingType domain.RatingType) (*domain.Rating, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockRatingRepo) GetByRatedUser(ctx context.Context, ratedUserID uuid.UUID, params *domain.RatingListParams) ([]domain.Rating, int, error) {
+	return nil, 0, nil
+}
+
+func (r *mockRatingRepo) GetByRater(ctx context.Context, raterID uuid.UUID) ([]domain.Rating, error) {
+	return nil, nil
+}
+
+func (r *mockRatingRepo) GetUserRatingSummary(ctx context.Context, userID uuid.UUID) (*domain.UserRatingSummary, error) {
+	summary := r.summaries[userID]
+	summary.UserID = userID
+	return &summary, nil
+}
+
+func TestBidderRequirements(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	userRepo := newMockUserRepo()
+	ratingRepo := &mockRatingRepo{summaries: make(map[uuid.UUID]domain.UserRatingSummary)}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	bidApprovalService := service.NewBidApprovalService(
+		&mockBidApprovalRepo{approvals: make(map[[2]uuid.UUID]*domain.BidApproval)},
+		auctionRepo,
+		userRepo,
+		ratingRepo,
+		nil,
+	)
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		bidApprovalService,
+	)
+	bidService := service.NewBidService(
+		newMockBidRepo(),
+		auctionRepo,
+		nil,
+		nil,
+		nil,
+		nil,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		bidApprovalService,
+		nil,
+	)
+	auctionHandler := handler.NewAuctionHandler(auctionService)
+	bidHandler := handler.NewBidHandler(bidService)
+
+	r := createTestRouter()
+	r.With(authMiddleware.OptionalAuth).Get("/api/auctions/{id}", auctionHandler.GetByID)
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/bids", bidHandler.PlaceBid)
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/buy-now", bidHandler.BuyNow)
+
+	// Bidders with each shortfall, and one meeting every requirement
+	newcomer := &domain.User{Email: "new@example.com", Username: "newcomer"}
+	lowRated := &domain.User{Email: "low@example.com", Username: "lowrated", EmailVerified: true}
+	unverified := &domain.User{Email: "unverified@example.com", Username: "unverified"}
+	veteran := &domain.User{Email: "veteran@example.com", Username: "veteran", EmailVerified: true}
+	for _, user := range []*domain.User{newcomer, lowRated, unverified, veteran} {
+		userRepo.Create(context.Background(), user)
+	}
+	longAgo := time.Now().AddDate(-2, 0, 0)
+	lowRated.CreatedAt, unverified.CreatedAt, veteran.CreatedAt = longAgo, longAgo, longAgo
+	ratingRepo.summaries[lowRated.ID] = domain.UserRatingSummary{BuyerRating: 3.5, BuyerCount: 4}
+	ratingRepo.summaries[unverified.ID] = domain.UserRatingSummary{BuyerRating: 5, BuyerCount: 2}
+	ratingRepo.summaries[veteran.ID] = domain.UserRatingSummary{BuyerRating: 4.8, BuyerCount: 31}
+
+	token := func(user *domain.User) string {
+		token, _ := jwtManager.GenerateAccessToken(user.ID, "user")
+		return token
+	}
+
+	minRating := 4.5
+	buyNowPrice := decimal.NewFromInt(500)
+	auction := &domain.Auction{
+		SellerID:      uuid.New(),
+		Title:         "PSA 10 Base Set Charizard",
+		StartingPrice: decimal.NewFromInt(100),
+		CurrentPrice:  decimal.NewFromInt(100),
+		BidIncrement:  decimal.NewFromInt(5),
+		BuyNowPrice:   &buyNowPrice,
+		BuyNowRule:    domain.BuyNowAlways,
+		StartTime:     time.Now().Add(-time.Hour),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+		BidderRequirements: domain.BidderRequirements{
+			MinRating:         &minRating,
+			VerifiedEmail:     true,
+			MinAccountAgeDays: 30,
+		},
+	}
+	auctionRepo.Create(context.Background(), auction)
+	auctionPath := "/api/auctions/" + auction.ID.String()
+
+	getAuction := func(t *testing.T, token string) domain.Auction {
+		t.Helper()
+		rr := makeRequest(t, r, "GET", auctionPath, nil, token)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var got domain.Auction
+		data, _ := json.Marshal(parseResponse(t, rr).Data)
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("failed to decode auction: %v", err)
+		}
+		return got
+	}
+
+	t.Run("requirements shown to everyone", func(t *testing.T) {
+		got := getAuction(t, "")
+		requirements := got.BidderRequirements
+		if requirements.MinRating == nil || *requirements.MinRating != 4.5 || !requirements.VerifiedEmail || requirements.MinAccountAgeDays != 30 {
+			t.Errorf("got %+v", requirements)
+		}
+		if got.BidEligibility != nil {
+			t.Errorf("expected no eligibility for a guest, got %+v", got.BidEligibility)
+		}
+	})
+
+	t.Run("viewers see what they are missing", func(t *testing.T) {
+		cases := []struct {
+			user  *domain.User
+			unmet []domain.BidderRequirement
+		}{
+			{newcomer, []domain.BidderRequirement{domain.BidderRequirementRating, domain.BidderRequirementVerifiedEmail, domain.BidderRequirementAccountAge}},
+			{lowRated, []domain.BidderRequirement{domain.BidderRequirementRating}},
+			{unverified, []domain.BidderRequirement{domain.BidderRequirementVerifiedEmail}},
+			{veteran, []domain.BidderRequirement{}},
+		}
+		for _, tc := range cases {
+			eligibility := getAuction(t, token(tc.user)).BidEligibility
+			if eligibility == nil || eligibility.CanBid != (len(tc.unmet) == 0) || !slices.Equal(eligibility.Unmet, tc.unmet) {
+				t.Errorf("%s: got %+v, want unmet %v", tc.user.Username, eligibility, tc.unmet)
+			}
+		}
+	})
+
+	t.Run("ineligible bidders are refused", func(t *testing.T) {
+		cases := []struct {
+			user *domain.User
+			code string
+		}{
+			{newcomer, "BIDDER_RATING_TOO_LOW"},
+			{lowRated, "BIDDER_RATING_TOO_LOW"},
+			{unverified, "BIDDER_EMAIL_UNVERIFIED"},
+		}
+		for _, tc := range cases {
+			rr := makeRequest(t, r, "POST", auctionPath+"/bids", domain.PlaceBidRequest{Amount: "105.00"}, token(tc.user))
+			if rr.Code != http.StatusForbidden {
+				t.Errorf("%s: expected 403, got %d", tc.user.Username, rr.Code)
+				continue
+			}
+			if response := parseResponse(t, rr); response.Error == nil || response.Error.Code != tc.code {
+				t.Errorf("%s: expected %s, got %+v", tc.user.Username, tc.code, response.Error)
+			}
+		}
+
+		rr := makeRequest(t, r, "POST", auctionPath+"/buy-now", nil, token(unverified))
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected buy now refused, got %d", rr.Code)
+		}
+	})
+
+	t.Run("eligible bidders can bid", func(t *testing.T) {
+		rr := makeRequest(t, r, "POST", auctionPath+"/bids", domain.PlaceBidRequest{Amount: "105.00"}, token(veteran))
+		if rr.Code != http.StatusCreated {
+			t.Errorf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type BlockHandler struct {
+	blockService *service.BlockService
+}
+
+func NewBlockHandler(blockService *service.BlockService) *BlockHandler {
+	return &BlockHandler{blockService: blockService}
+}
+
+// ListBlocks lists the users the current user has blocked, newest first
+func (h *BlockHandler) ListBlocks(w http.ResponseWriter, r *http.Request) {
+	params := &domain.UserBlockListParams{
+		Page:  getQueryParamInt(r, "page", 1),
+		Limit: getQueryParamInt(r, "limit", 20),
+	}
+
+	blocks, totalCount, err := h.blockService.ListBlocks(r.Context(), getUserID(r), params)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	totalPages := (totalCount + params.Limit - 1) / params.Limit
+
+	respondJSONWithMeta(w, http.StatusOK, blocks, &domain.APIMeta{
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalCount: totalCount,
+		TotalPages: totalPages,
+	})
+}
+
+// Block stops a user from bidding on the current user's auctions or
+// messaging them
+func (h *BlockHandler) Block(w http.ResponseWriter, r *http.Request) {
+	var req domain.BlockUserRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	block, err := h.blockService.Block(r.Context(), getUserID(r), &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, block)
+}
+
+func (h *BlockHandler) Unblock(w http.ResponseWriter, r *http.Request) {
+	blockedID, err := getURLParamUUID(r, "userId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid user ID")
+		return
+	}
+
+	if err := h.blockService.Unblock(r.Context(), getUserID(r), blockedID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "User unblocked",
+	})
+}
@@ -0,0 +1,223 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock user block repository
+type mockUserBlockRepo struct {
+	mu       sync.Mutex
+	blocks   []domain.UserBlock
+	userRepo *mockUserRepo
+}
+
+func (r *mockUserBlockRepo) Block(ctx context.Context, block *domain.UserBlock) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.blocks {
+		if existing.BlockerID == block.BlockerID && existing.BlockedID == block.BlockedID {
+			block.Reason = existing.Reason
+			block.CreatedAt = existing.CreatedAt
+			return nil
+		}
+	}
+	block.CreatedAt = time.Now()
+	r.blocks = append(r.blocks, *block)
+	return nil
+}
+
+func (r *mockUserBlockRepo) Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, block := range r.blocks {
+		if block.BlockerID == blockerID && block.BlockedID == blockedID {
+			r.blocks = append(r.blocks[:i], r.blocks[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func (r *mockUserBlockRepo) IsBlocked(ctx context.Context, blockerID, blockedID uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, block := range r.blocks {
+		if block.BlockerID == blockerID && block.BlockedID == blockedID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *mockUserBlockRepo) List(ctx context.Context, blockerID uuid.UUID, page, limit int) ([]domain.UserBlock, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	blocks := make([]domain.UserBlock, 0)
+	for _, block := range r.blocks {
+		if block.BlockerID == blockerID {
+			if user, ok := r.userRepo.users[block.BlockedID]; ok {
+				block.Blocked = user.ToPublic()
+			}
+			blocks = append(blocks, block)
+		}
+	}
+	sort.SliceStable(blocks, func(i, j int) bool { return blocks[i].CreatedAt.After(blocks[j].CreatedAt) })
+
+	start := min((page-1)*limit, len(blocks))
+	end := min(start+limit, len(blocks))
+	return blocks[start:end], len(blocks), nil
+}
+
+func TestBlockHandler(t *testing.T) {
+	userRepo := newMockUserRepo()
+	blockRepo := &mockUserBlockRepo{userRepo: userRepo}
+	auctionRepo := newMockAuctionRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	seller := &domain.User{Username: "seller"}
+	troll := &domain.User{Username: "troll"}
+	lurker := &domain.User{Username: "lurker"}
+	for _, user := range []*domain.User{seller, troll, lurker} {
+		userRepo.Create(context.Background(), user)
+	}
+
+	auction := &domain.Auction{
+		SellerID:      seller.ID,
+		Title:         "Test Auction",
+		StartingPrice: decimal.NewFromFloat(100),
+		CurrentPrice:  decimal.NewFromFloat(100),
+		BidIncrement:  decimal.NewFromFloat(5),
+		StartTime:     time.Now().Add(-1 * time.Hour),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+	}
+	auctionRepo.Create(context.Background(), auction)
+
+	bidService := service.NewBidService(
+		newMockBidRepo(),
+		auctionRepo,
+		userRepo,
+		blockRepo,
+		nil,
+		nil,
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	blockHandler := handler.NewBlockHandler(service.NewBlockService(blockRepo, userRepo))
+	bidHandler := handler.NewBidHandler(bidService)
+
+	r := createTestRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Get("/api/users/me/blocks", blockHandler.ListBlocks)
+		r.Post("/api/users/me/blocks", blockHandler.Block)
+		r.Delete("/api/users/me/blocks/{userId}", blockHandler.Unblock)
+		r.Post("/api/auctions/{id}/bids", bidHandler.PlaceBid)
+	})
+
+	sellerToken, _ := jwtManager.GenerateAccessToken(seller.ID, "user")
+	trollToken, _ := jwtManager.GenerateAccessToken(troll.ID, "user")
+
+	listBlocks := func(t *testing.T, query string) ([]domain.UserBlock, *domain.APIMeta) {
+		t.Helper()
+		rr := makeRequest(t, r, "GET", "/api/users/me/blocks"+query, nil, sellerToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		response := parseResponse(t, rr)
+		var blocks []domain.UserBlock
+		data, _ := json.Marshal(response.Data)
+		if err := json.Unmarshal(data, &blocks); err != nil {
+			t.Fatalf("failed to decode blocks: %v", err)
+		}
+		return blocks, response.Meta
+	}
+
+	bid := func(t *testing.T, amount string) int {
+		t.Helper()
+		return makeRequest(t, r, "POST", "/api/auctions/"+auction.ID.String()+"/bids", domain.PlaceBidRequest{Amount: amount}, trollToken).Code
+	}
+
+	t.Run("block", func(t *testing.T) {
+		rr := makeRequest(t, r, "POST", "/api/users/me/blocks", map[string]string{
+			"user_id": troll.ID.String(),
+			"reason":  "Never pays",
+		}, sellerToken)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		// Blocking again keeps the original block
+		makeRequest(t, r, "POST", "/api/users/me/blocks", map[string]string{"user_id": troll.ID.String()}, sellerToken)
+		time.Sleep(time.Millisecond)
+		makeRequest(t, r, "POST", "/api/users/me/blocks", map[string]string{"user_id": lurker.ID.String()}, sellerToken)
+
+		blocks, meta := listBlocks(t, "")
+		if len(blocks) != 2 || meta.TotalCount != 2 {
+			t.Fatalf("got %+v, meta %+v", blocks, meta)
+		}
+		if blocks[0].BlockedID != lurker.ID || blocks[1].Blocked == nil || blocks[1].Blocked.Username != "troll" {
+			t.Errorf("expected newest first with the blocked user, got %+v", blocks)
+		}
+		if blocks[1].Reason == nil || *blocks[1].Reason != "Never pays" {
+			t.Errorf("expected the original reason kept, got %v", blocks[1].Reason)
+		}
+
+		if blocks, meta := listBlocks(t, "?limit=1&page=2"); len(blocks) != 1 || meta.TotalPages != 2 {
+			t.Errorf("got %d blocks, meta %+v", len(blocks), meta)
+		}
+	})
+
+	t.Run("blocked bidder", func(t *testing.T) {
+		if code := bid(t, "110.00"); code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", code)
+		}
+	})
+
+	t.Run("unblock", func(t *testing.T) {
+		rr := makeRequest(t, r, "DELETE", "/api/users/me/blocks/"+troll.ID.String(), nil, sellerToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if code := bid(t, "110.00"); code != http.StatusCreated {
+			t.Errorf("expected the bid accepted once unblocked, got %d", code)
+		}
+
+		rr = makeRequest(t, r, "DELETE", "/api/users/me/blocks/"+troll.ID.String(), nil, sellerToken)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404 unblocking twice, got %d", rr.Code)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		rr := makeRequest(t, r, "POST", "/api/users/me/blocks", map[string]string{"user_id": seller.ID.String()}, sellerToken)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400 blocking yourself, got %d", rr.Code)
+		}
+
+		rr = makeRequest(t, r, "POST", "/api/users/me/blocks", map[string]string{"user_id": uuid.NewString()}, sellerToken)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404 for an unknown user, got %d", rr.Code)
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func TestCategoryListingRules(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	categoryRepo := newMockCategoryRepo()
+	imageRepo := &mockAuctionImageRepo{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		imageRepo,
+		newMockShippingRepo(),
+		categoryRepo,
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	auctionHandler := handler.NewAuctionHandler(auctionService)
+	adminHandler := handler.NewAdminHandler(nil, nil, nil, nil, nil, categoryRepo, nil, nil, nil)
+
+	r := createTestRouter()
+	r.Get("/api/categories/{slug}/rules", auctionHandler.GetListingRules)
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Post("/api/auctions", auctionHandler.Create)
+		r.Post("/api/auctions/{id}/publish", auctionHandler.Publish)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(authMiddleware.RequireAdmin)
+		r.Put("/api/admin/categories/{id}/rules", adminHandler.SetCategoryListingRules)
+		r.Delete("/api/admin/categories/{id}/rules", adminHandler.DeleteCategoryListingRules)
+	})
+
+	adminToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "admin")
+	sellerToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+
+	graded := &domain.Category{ID: uuid.New(), Name: "Graded cards", Slug: "graded"}
+	categoryRepo.categories[graded.ID] = graded
+	rulesPath := "/api/admin/categories/" + graded.ID.String() + "/rules"
+
+	hours := func(h int) *int { return &h }
+	rules := domain.SetCategoryListingRulesRequest{
+		DefaultDurationHours: hours(72),
+		DefaultBidIncrement:  stringPtr("2.50"),
+		MinDurationHours:     hours(24),
+		MaxDurationHours:     hours(240),
+		MinImages:            4,
+	}
+
+	// createDraft creates a draft in the graded category, returning its ID
+	// and the created auction
+	createDraft := func(t *testing.T, req domain.CreateAuctionRequest) (uuid.UUID, map[string]interface{}) {
+		t.Helper()
+		req.Title = "PSA 10 Charizard"
+		req.StartingPrice = "100.00"
+		req.CategoryID = &graded.ID
+		rr := makeRequest(t, r, "POST", "/api/auctions", req, sellerToken)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("creating draft: got status %v, want %v", rr.Code, http.StatusCreated)
+		}
+		auction := parseResponse(t, rr).Data.(map[string]interface{})
+		return uuid.MustParse(auction["id"].(string)), auction
+	}
+
+	t.Run("only admins set rules", func(t *testing.T) {
+		rr := makeRequest(t, r, "PUT", rulesPath, rules, sellerToken)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Run("rejects rules that contradict each other", func(t *testing.T) {
+		for name, req := range map[string]domain.SetCategoryListingRulesRequest{
+			"minimum above maximum": {MinDurationHours: hours(48), MaxDurationHours: hours(24)},
+			"default out of range":  {DefaultDurationHours: hours(12), MinDurationHours: hours(24)},
+			"default increment low": {DefaultBidIncrement: stringPtr("1"), MinBidIncrement: stringPtr("2")},
+		} {
+			rr := makeRequest(t, r, "PUT", rulesPath, req, adminToken)
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("%s: got status %v, want %v", name, rr.Code, http.StatusBadRequest)
+			}
+		}
+	})
+
+	t.Run("sets rules", func(t *testing.T) {
+		rr := makeRequest(t, r, "PUT", rulesPath, rules, adminToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("got status %v, want %v", rr.Code, http.StatusOK)
+		}
+
+		rr = makeRequest(t, r, "GET", "/api/categories/graded/rules", nil, "")
+		got := parseResponse(t, rr).Data.(map[string]interface{})
+		if got["min_images"] != float64(4) || got["default_duration_hours"] != float64(72) {
+			t.Errorf("unexpected rules %v", got)
+		}
+	})
+
+	t.Run("drafts get the category defaults", func(t *testing.T) {
+		startTime := time.Now().Add(time.Hour).Truncate(time.Second)
+		id, auction := createDraft(t, domain.CreateAuctionRequest{StartTime: startTime})
+
+		if auction["bid_increment"] != "2.5" {
+			t.Errorf("got bid increment %v, want 2.50", auction["bid_increment"])
+		}
+		if end := auctionRepo.auctions[id].EndTime; !end.Equal(startTime.Add(72 * time.Hour)) {
+			t.Errorf("got end time %v, want 72 hours after the start", end)
+		}
+
+		// Values the seller sets are kept
+		_, auction = createDraft(t, domain.CreateAuctionRequest{
+			StartTime:    startTime,
+			EndTime:      startTime.Add(48 * time.Hour),
+			BidIncrement: stringPtr("5.00"),
+		})
+		if auction["bid_increment"] != "5" {
+			t.Errorf("got bid increment %v, want the seller's 5.00", auction["bid_increment"])
+		}
+	})
+
+	t.Run("publishing checks the rules", func(t *testing.T) {
+		startTime := time.Now().Add(time.Hour)
+		id, _ := createDraft(t, domain.CreateAuctionRequest{StartTime: startTime, EndTime: startTime.Add(300 * time.Hour)})
+
+		rr := makeRequest(t, r, "POST", "/api/auctions/"+id.String()+"/publish", nil, sellerToken)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("got status %v, want %v", rr.Code, http.StatusBadRequest)
+		}
+		response := parseResponse(t, rr)
+		if response.Error == nil || response.Error.Code != "LISTING_RULES" {
+			t.Fatalf("unexpected error %+v", response.Error)
+		}
+		if response.Error.Details["images"] == "" || response.Error.Details["end_time"] == "" {
+			t.Errorf("got details %v, want images and end_time", response.Error.Details)
+		}
+		if auctionRepo.auctions[id].Status != domain.AuctionStatusDraft {
+			t.Errorf("auction breaking the rules was published")
+		}
+
+		auctionRepo.auctions[id].EndTime = startTime.Add(72 * time.Hour)
+		for i := 0; i < 4; i++ {
+			imageRepo.images = append(imageRepo.images, domain.AuctionImage{ID: uuid.New(), AuctionID: id})
+		}
+		rr = makeRequest(t, r, "POST", "/api/auctions/"+id.String()+"/publish", nil, sellerToken)
+		if rr.Code != http.StatusOK {
+			t.Errorf("after fixing: got status %v, want %v", rr.Code, http.StatusOK)
+		}
+	})
+
+	t.Run("end time is required without a default duration", func(t *testing.T) {
+		rr := makeRequest(t, r, "DELETE", rulesPath, nil, adminToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("got status %v, want %v", rr.Code, http.StatusOK)
+		}
+
+		rr = makeRequest(t, r, "POST", "/api/auctions", domain.CreateAuctionRequest{
+			CategoryID:    &graded.ID,
+			Title:         "PSA 9 Blastoise",
+			StartingPrice: "50.00",
+			StartTime:     time.Now().Add(time.Hour),
+		}, sellerToken)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusBadRequest)
+		}
+	})
+}
@@ -0,0 +1,191 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func TestCategoryTranslations(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	categoryRepo := newMockCategoryRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		categoryRepo,
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	auctionHandler := handler.NewAuctionHandler(auctionService)
+	adminHandler := handler.NewAdminHandler(nil, nil, nil, nil, nil, categoryRepo, nil, nil, nil)
+
+	r := createTestRouter()
+	r.Get("/api/categories", auctionHandler.GetCategories)
+	r.Get("/api/auctions/{id}", auctionHandler.GetByID)
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(authMiddleware.RequireAdmin)
+		r.Get("/api/admin/categories/{id}/translations", adminHandler.ListCategoryTranslations)
+		r.Put("/api/admin/categories/{id}/translations/{lang}", adminHandler.SetCategoryTranslation)
+		r.Delete("/api/admin/categories/{id}/translations/{lang}", adminHandler.DeleteCategoryTranslation)
+	})
+
+	adminToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "admin")
+	userToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+
+	var electronics *domain.Category
+	for _, category := range categoryRepo.categories {
+		if category.Slug == "electronics" {
+			electronics = category
+		}
+	}
+	translationsPath := "/api/admin/categories/" + electronics.ID.String() + "/translations/"
+
+	auction := &domain.Auction{
+		ID:         uuid.New(),
+		SellerID:   uuid.New(),
+		Title:      "Charizard",
+		Status:     domain.AuctionStatusActive,
+		EndTime:    time.Now().Add(time.Hour),
+		CategoryID: &electronics.ID,
+		Category:   electronics,
+	}
+	auctionRepo.auctions[auction.ID] = auction
+
+	// categoryName fetches the categories with the given query and
+	// Accept-Language header, returning the name shown for electronics
+	categoryName := func(t *testing.T, query, acceptLanguage string) string {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/api/categories"+query, nil)
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("got status %v, want %v", rr.Code, http.StatusOK)
+		}
+		categories, _ := parseResponse(t, rr).Data.([]interface{})
+		for _, c := range categories {
+			category := c.(map[string]interface{})
+			if category["slug"] == "electronics" {
+				name, _ := category["name"].(string)
+				return name
+			}
+		}
+		t.Fatal("electronics not listed")
+		return ""
+	}
+
+	t.Run("only admins translate", func(t *testing.T) {
+		rr := makeRequest(t, r, "PUT", translationsPath+"de", domain.SetCategoryTranslationRequest{Name: "Elektronik"}, userToken)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Run("rejects invalid and default languages", func(t *testing.T) {
+		for _, lang := range []string{"e!", "en", "en-GB"} {
+			rr := makeRequest(t, r, "PUT", translationsPath+lang, domain.SetCategoryTranslationRequest{Name: "Electronics"}, adminToken)
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("%q: got status %v, want %v", lang, rr.Code, http.StatusBadRequest)
+			}
+		}
+	})
+
+	t.Run("sets translations", func(t *testing.T) {
+		description := "Elektronische Geräte"
+		for lang, req := range map[string]domain.SetCategoryTranslationRequest{
+			"de":    {Name: "Elektronik", Description: &description},
+			"pt-br": {Name: "Eletrônicos"},
+			"bs":    {Name: "Elektronika"},
+		} {
+			rr := makeRequest(t, r, "PUT", translationsPath+lang, req, adminToken)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("%s: got status %v, want %v", lang, rr.Code, http.StatusOK)
+			}
+		}
+
+		rr := makeRequest(t, r, "GET", "/api/admin/categories/"+electronics.ID.String()+"/translations", nil, adminToken)
+		translations, _ := parseResponse(t, rr).Data.([]interface{})
+		if len(translations) != 3 {
+			t.Errorf("got %d translations, want 3", len(translations))
+		}
+		if _, ok := categoryRepo.translations[electronics.ID]["pt-BR"]; !ok {
+			t.Error("language tag wasn't stored in canonical form")
+		}
+	})
+
+	t.Run("categories follow the preferred language", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			query          string
+			acceptLanguage string
+			want           string
+		}{
+			{"no preference", "", "", "Electronics"},
+			{"accept-language", "", "de-AT, de;q=0.9", "Elektronik"},
+			{"regional tag", "", "pt-BR", "Eletrônicos"},
+			{"weights", "", "fr;q=0.5, bs;q=0.8", "Elektronika"},
+			{"untranslated falls through", "", "fr, de;q=0.7", "Elektronik"},
+			{"default language preferred", "", "en, de;q=0.9", "Electronics"},
+			{"lang overrides the header", "?lang=bs", "de", "Elektronika"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if got := categoryName(t, tt.query, tt.acceptLanguage); got != tt.want {
+					t.Errorf("got %q, want %q", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("auction detail shows the translated category", func(t *testing.T) {
+		rr := makeRequest(t, r, "GET", "/api/auctions/"+auction.ID.String()+"?lang=de", nil, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("got status %v, want %v", rr.Code, http.StatusOK)
+		}
+		detail := parseResponse(t, rr).Data.(map[string]interface{})
+		category, _ := detail["category"].(map[string]interface{})
+		if category["name"] != "Elektronik" || category["description"] != "Elektronische Geräte" || category["language"] != "de" {
+			t.Errorf("unexpected category %v", category)
+		}
+		if electronics.Name != "Electronics" {
+			t.Errorf("stored category renamed to %q", electronics.Name)
+		}
+	})
+
+	t.Run("deletes a translation", func(t *testing.T) {
+		rr := makeRequest(t, r, "DELETE", translationsPath+"de", nil, adminToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("got status %v, want %v", rr.Code, http.StatusOK)
+		}
+		if got := categoryName(t, "?lang=de", ""); got != "Electronics" {
+			t.Errorf("got %q after deleting, want Electronics", got)
+		}
+
+		rr = makeRequest(t, r, "DELETE", translationsPath+"de", nil, adminToken)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("deleting again: got status %v, want %v", rr.Code, http.StatusNotFound)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type CurationHandler struct {
+	curationService *service.CurationService
+}
+
+func NewCurationHandler(curationService *service.CurationService) *CurationHandler {
+	return &CurationHandler{curationService: curationService}
+}
+
+// Featured lists the auctions admins feature on the homepage
+func (h *CurationHandler) Featured(w http.ResponseWriter, r *http.Request) {
+	auctions, err := h.curationService.Featured(r.Context(), getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, auctions)
+}
+
+// Trending lists the auctions with the most recent views, watches and bids
+func (h *CurationHandler) Trending(w http.ResponseWriter, r *http.Request) {
+	limit := getQueryParamInt(r, "limit", domain.DefaultTrendingLimit)
+	auctions, err := h.curationService.Trending(r.Context(), getUserID(r), limit)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, auctions)
+}
+
+func (h *CurationHandler) AdminListFeatured(w http.ResponseWriter, r *http.Request) {
+	featured, err := h.curationService.ListFeatured(r.Context())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, featured)
+}
+
+func (h *CurationHandler) AdminFeature(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "auctionId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	var req domain.FeatureAuctionRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	featured, err := h.curationService.Feature(r.Context(), getUserID(r), auctionID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, featured)
+}
+
+func (h *CurationHandler) AdminUnfeature(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "auctionId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	if err := h.curationService.Unfeature(r.Context(), getUserID(r), auctionID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Auction unfeatured",
+	})
+}
@@ -0,0 +1,223 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock curation repository keeping pins in memory and returning a fixed
+// trending ranking
+type mockCurationRepo struct {
+	featured map[uuid.UUID]domain.FeaturedAuction
+	trending []uuid.UUID
+}
+
+func (r *mockCurationRepo) Feature(ctx context.Context, featured *domain.FeaturedAuction) error {
+	if existing, ok := r.featured[featured.AuctionID]; ok {
+		featured.CreatedAt = existing.CreatedAt
+	} else {
+		featured.CreatedAt = time.Now()
+	}
+	featured.UpdatedAt = time.Now()
+	r.featured[featured.AuctionID] = *featured
+	return nil
+}
+
+func (r *mockCurationRepo) Unfeature(ctx context.Context, auctionID uuid.UUID) error {
+	if _, ok := r.featured[auctionID]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.featured, auctionID)
+	return nil
+}
+
+func (r *mockCurationRepo) ListFeatured(ctx context.Context) ([]domain.FeaturedAuction, error) {
+	featured := make([]domain.FeaturedAuction, 0, len(r.featured))
+	for _, f := range r.featured {
+		featured = append(featured, f)
+	}
+	sort.Slice(featured, func(i, j int) bool {
+		return featured[i].Position < featured[j].Position
+	})
+	return featured, nil
+}
+
+func (r *mockCurationRepo) GetTrending(ctx context.Context, since time.Time, limit int) ([]uuid.UUID, error) {
+	return r.trending, nil
+}
+
+func TestCurationHandler(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	curationRepo := &mockCurationRepo{featured: make(map[uuid.UUID]domain.FeaturedAuction)}
+	auditRepo := &mockAuditLogRepo{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil, // no S3 for tests
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	curationHandler := handler.NewCurationHandler(
+		service.NewCurationService(curationRepo, auctionRepo, auditRepo, &mockTxManager{}, auctionService, nil),
+	)
+
+	r := createTestRouter()
+	r.With(authMiddleware.OptionalAuth).Get("/api/auctions/featured", curationHandler.Featured)
+	r.With(authMiddleware.OptionalAuth).Get("/api/auctions/trending", curationHandler.Trending)
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(authMiddleware.RequireAdmin)
+		r.Get("/api/admin/featured", curationHandler.AdminListFeatured)
+		r.Put("/api/admin/featured/{auctionId}", curationHandler.AdminFeature)
+		r.Delete("/api/admin/featured/{auctionId}", curationHandler.AdminUnfeature)
+	})
+
+	adminToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "admin")
+	userToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+
+	newAuction := func(title string, status domain.AuctionStatus, visibility domain.AuctionVisibility) *domain.Auction {
+		auction := &domain.Auction{
+			SellerID:      uuid.New(),
+			Title:         title,
+			StartingPrice: decimal.NewFromFloat(20),
+			CurrentPrice:  decimal.NewFromFloat(25),
+			BidIncrement:  decimal.NewFromFloat(1),
+			StartTime:     time.Now().Add(-time.Hour),
+			EndTime:       time.Now().Add(24 * time.Hour),
+			Status:        status,
+			Visibility:    visibility,
+		}
+		auctionRepo.Create(context.Background(), auction)
+		return auction
+	}
+
+	first := newAuction("Charizard Base Set", domain.AuctionStatusActive, domain.AuctionPublic)
+	second := newAuction("Blastoise Base Set", domain.AuctionStatusActive, domain.AuctionPublic)
+	private := newAuction("Venusaur Base Set", domain.AuctionStatusActive, domain.AuctionPrivate)
+	draft := newAuction("Pikachu Illustrator", domain.AuctionStatusDraft, domain.AuctionPublic)
+
+	titles := func(path string) []string {
+		t.Helper()
+		rr := makeRequest(t, r, "GET", path, nil, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+		auctions := parseResponse(t, rr).Data.([]interface{})
+		result := make([]string, len(auctions))
+		for i, a := range auctions {
+			result[i] = a.(map[string]interface{})["title"].(string)
+		}
+		return result
+	}
+	feature := func(auction *domain.Auction, req domain.FeatureAuctionRequest, token string) int {
+		t.Helper()
+		return makeRequest(t, r, "PUT", "/api/admin/featured/"+auction.ID.String(), req, token).Code
+	}
+
+	t.Run("only admins feature", func(t *testing.T) {
+		if got := feature(first, domain.FeatureAuctionRequest{}, userToken); got != http.StatusForbidden {
+			t.Errorf("got status %v, want %v", got, http.StatusForbidden)
+		}
+	})
+
+	t.Run("drafts can't be featured", func(t *testing.T) {
+		if got := feature(draft, domain.FeatureAuctionRequest{}, adminToken); got != http.StatusBadRequest {
+			t.Errorf("got status %v, want %v", got, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("expiry must be ahead", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		if got := feature(first, domain.FeatureAuctionRequest{ExpiresAt: &past}, adminToken); got != http.StatusBadRequest {
+			t.Errorf("got status %v, want %v", got, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("featured in position order", func(t *testing.T) {
+		for _, pin := range []struct {
+			auction  *domain.Auction
+			position int
+		}{{first, 2}, {second, 1}, {private, 0}} {
+			if got := feature(pin.auction, domain.FeatureAuctionRequest{Position: pin.position}, adminToken); got != http.StatusOK {
+				t.Fatalf("got status %v, want %v", got, http.StatusOK)
+			}
+		}
+
+		got := titles("/api/auctions/featured")
+		if len(got) != 2 || got[0] != second.Title || got[1] != first.Title {
+			t.Errorf("expected the public pins by position, got %v", got)
+		}
+		if len(auditRepo.entries) != 3 || auditRepo.entries[0].Action != domain.AuditActionFeature {
+			t.Errorf("expected each pin audited, got %v", auditRepo.entries)
+		}
+	})
+
+	t.Run("expired pins are hidden", func(t *testing.T) {
+		pin := curationRepo.featured[second.ID]
+		expired := time.Now().Add(-time.Minute)
+		pin.ExpiresAt = &expired
+		curationRepo.featured[second.ID] = pin
+
+		if got := titles("/api/auctions/featured"); len(got) != 1 || got[0] != first.Title {
+			t.Errorf("expected the expired pin left out, got %v", got)
+		}
+
+		rr := makeRequest(t, r, "GET", "/api/admin/featured", nil, adminToken)
+		pins := parseResponse(t, rr).Data.([]interface{})
+		if len(pins) != 3 {
+			t.Fatalf("expected admins to see every pin, got %d", len(pins))
+		}
+		if pins[1].(map[string]interface{})["expired"] != true {
+			t.Errorf("expected the expired pin flagged, got %v", pins[1])
+		}
+	})
+
+	t.Run("unfeature", func(t *testing.T) {
+		rr := makeRequest(t, r, "DELETE", "/api/admin/featured/"+first.ID.String(), nil, adminToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("got status %v, want %v", rr.Code, http.StatusOK)
+		}
+		if got := titles("/api/auctions/featured"); len(got) != 0 {
+			t.Errorf("expected no featured auctions left, got %v", got)
+		}
+
+		rr = makeRequest(t, r, "DELETE", "/api/admin/featured/"+first.ID.String(), nil, adminToken)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("trending", func(t *testing.T) {
+		curationRepo.trending = []uuid.UUID{second.ID, private.ID, draft.ID, first.ID}
+
+		got := titles("/api/auctions/trending")
+		if len(got) != 2 || got[0] != second.Title || got[1] != first.Title {
+			t.Errorf("expected visible auctions in trending order, got %v", got)
+		}
+		if got := titles("/api/auctions/trending?limit=1"); len(got) != 1 || got[0] != second.Title {
+			t.Errorf("expected the limit to apply, got %v", got)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type DigestHandler struct {
+	digestService      *service.DigestService
+	savedSearchService *service.SavedSearchService
+}
+
+func NewDigestHandler(digestService *service.DigestService, savedSearchService *service.SavedSearchService) *DigestHandler {
+	return &DigestHandler{
+		digestService:      digestService,
+		savedSearchService: savedSearchService,
+	}
+}
+
+func (h *DigestHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	preferences, err := h.digestService.GetPreferences(r.Context(), getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, preferences)
+}
+
+func (h *DigestHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	var req domain.UpdateDigestPreferencesRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	preferences, err := h.digestService.UpdatePreferences(r.Context(), getUserID(r), &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, preferences)
+}
+
+// Preview shows what the current user's digest would hold if it went out
+// now
+func (h *DigestHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	digest, err := h.digestService.Preview(r.Context(), getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, digest)
+}
+
+func (h *DigestHandler) ListSavedSearches(w http.ResponseWriter, r *http.Request) {
+	searches, err := h.savedSearchService.List(r.Context(), getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, searches)
+}
+
+func (h *DigestHandler) CreateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateSavedSearchRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	search, err := h.savedSearchService.Create(r.Context(), getUserID(r), &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, search)
+}
+
+func (h *DigestHandler) DeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	searchID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid saved search ID")
+		return
+	}
+
+	if err := h.savedSearchService.Delete(r.Context(), getUserID(r), searchID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Saved search deleted",
+	})
+}
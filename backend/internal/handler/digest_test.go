@@ -0,0 +1,431 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock digest repository, reading watchlists and listings from the auction
+// and watchlist mocks
+type mockDigestRepo struct {
+	preferences map[uuid.UUID]*domain.DigestPreferences
+	auctions    *mockAuctionRepo
+	watchlist   *mockWatchlistRepo
+}
+
+func newMockDigestRepo(auctions *mockAuctionRepo, watchlist *mockWatchlistRepo) *mockDigestRepo {
+	return &mockDigestRepo{
+		preferences: make(map[uuid.UUID]*domain.DigestPreferences),
+		auctions:    auctions,
+		watchlist:   watchlist,
+	}
+}
+
+func (r *mockDigestRepo) GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.DigestPreferences, error) {
+	if p, ok := r.preferences[userID]; ok {
+		stored := *p
+		return &stored, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockDigestRepo) SavePreferences(ctx context.Context, preferences *domain.DigestPreferences) error {
+	if existing, ok := r.preferences[preferences.UserID]; ok {
+		preferences.LastSentAt = existing.LastSentAt
+	}
+	stored := *preferences
+	r.preferences[preferences.UserID] = &stored
+	return nil
+}
+
+func (r *mockDigestRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.DigestPreferences, error) {
+	due := make([]domain.DigestPreferences, 0)
+	for _, p := range r.preferences {
+		if p.Enabled() && p.NextSendAt != nil && !p.NextSendAt.After(now) && len(due) < limit {
+			leased := now.Add(lease)
+			p.NextSendAt = &leased
+			due = append(due, *p)
+		}
+	}
+	return due, nil
+}
+
+func (r *mockDigestRepo) MarkSent(ctx context.Context, userID uuid.UUID, sentAt, nextAt time.Time) error {
+	p := r.preferences[userID]
+	p.LastSentAt = &sentAt
+	p.NextSendAt = &nextAt
+	return nil
+}
+
+func (r *mockDigestRepo) WatchedEnding(ctx context.Context, userID uuid.UUID, until time.Time, limit int) ([]domain.Auction, error) {
+	auctions := make([]domain.Auction, 0)
+	for auctionID := range r.watchlist.items[userID] {
+		auction, ok := r.auctions.auctions[auctionID]
+		if ok && auction.Status == domain.AuctionStatusActive && auction.EndTime.After(time.Now()) && !auction.EndTime.After(until) {
+			auctions = append(auctions, *auction)
+		}
+	}
+	sort.Slice(auctions, func(i, j int) bool { return auctions[i].EndTime.Before(auctions[j].EndTime) })
+	return auctions[:min(limit, len(auctions))], nil
+}
+
+func (r *mockDigestRepo) SellerStats(ctx context.Context, sellerID uuid.UUID, since, until, endingBy time.Time) (*domain.DigestSellerStats, error) {
+	stats := &domain.DigestSellerStats{}
+	for _, auction := range r.auctions.auctions {
+		if auction.SellerID != sellerID {
+			continue
+		}
+		if auction.Status == domain.AuctionStatusActive {
+			stats.ActiveListings++
+			if !auction.EndTime.After(endingBy) {
+				stats.EndingSoon++
+			}
+		}
+	}
+	return stats, nil
+}
+
+func (r *mockDigestRepo) DigestsWatchedEnding(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	digests := make(map[uuid.UUID]bool)
+	for _, userID := range userIDs {
+		if p, ok := r.preferences[userID]; ok && p.Enabled() && p.WatchedEnding {
+			digests[userID] = true
+		}
+	}
+	return digests, nil
+}
+
+// Mock saved search repository
+type mockSavedSearchRepo struct {
+	searches []domain.SavedSearch
+}
+
+func (r *mockSavedSearchRepo) Create(ctx context.Context, search *domain.SavedSearch) error {
+	search.ID = uuid.New()
+	search.CreatedAt = time.Now()
+	r.searches = append(r.searches, *search)
+	return nil
+}
+
+func (r *mockSavedSearchRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.SavedSearch, error) {
+	searches := make([]domain.SavedSearch, 0)
+	for _, s := range r.searches {
+		if s.UserID == userID {
+			searches = append(searches, s)
+		}
+	}
+	return searches, nil
+}
+
+func (r *mockSavedSearchRepo) CountByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	searches, _ := r.ListByUser(ctx, userID)
+	return len(searches), nil
+}
+
+func (r *mockSavedSearchRepo) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	before := len(r.searches)
+	r.searches = slices.DeleteFunc(r.searches, func(s domain.SavedSearch) bool {
+		return s.ID == id && s.UserID == userID
+	})
+	if len(r.searches) == before {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func TestDigests(t *testing.T) {
+	userRepo := newMockUserRepo()
+	auctionRepo := newMockAuctionRepo()
+	watchlistRepo := newMockWatchlistRepo()
+	digestRepo := newMockDigestRepo(auctionRepo, watchlistRepo)
+	savedSearchRepo := &mockSavedSearchRepo{}
+	emailSender := &mockEmailSender{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	digestService := service.NewDigestService(digestRepo, savedSearchRepo, auctionRepo, userRepo, emailSender, "https://cards.example")
+	digestHandler := handler.NewDigestHandler(digestService, service.NewSavedSearchService(savedSearchRepo))
+
+	r := createTestRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Get("/api/notifications/digest", digestHandler.GetPreferences)
+		r.Put("/api/notifications/digest", digestHandler.UpdatePreferences)
+		r.Get("/api/notifications/digest/preview", digestHandler.Preview)
+		r.Get("/api/users/me/saved-searches", digestHandler.ListSavedSearches)
+		r.Post("/api/users/me/saved-searches", digestHandler.CreateSavedSearch)
+		r.Delete("/api/users/me/saved-searches/{id}", digestHandler.DeleteSavedSearch)
+	})
+
+	collector := &domain.User{Email: "collector@example.com", Username: "collector"}
+	seller := &domain.User{Email: "seller@example.com", Username: "seller"}
+	quiet := &domain.User{Email: "quiet@example.com", Username: "quiet"}
+	for _, u := range []*domain.User{collector, seller, quiet} {
+		userRepo.Create(context.Background(), u)
+	}
+	collectorToken, _ := jwtManager.GenerateAccessToken(collector.ID, "user")
+	sellerToken, _ := jwtManager.GenerateAccessToken(seller.ID, "user")
+	quietToken, _ := jwtManager.GenerateAccessToken(quiet.ID, "user")
+
+	pokemon := uuid.New()
+	newAuction := func(title string, categoryID *uuid.UUID, started, ends time.Duration) *domain.Auction {
+		auction := &domain.Auction{
+			ID:           uuid.New(),
+			SellerID:     seller.ID,
+			CategoryID:   categoryID,
+			Title:        title,
+			CurrentPrice: decimal.NewFromInt(40),
+			BidIncrement: decimal.NewFromInt(1),
+			Currency:     domain.CurrencyUSD,
+			Status:       domain.AuctionStatusActive,
+			Visibility:   domain.AuctionPublic,
+			StartTime:    time.Now().Add(started),
+			EndTime:      time.Now().Add(ends),
+		}
+		auctionRepo.Create(context.Background(), auction)
+		return auction
+	}
+	endingTonight := newAuction("Blastoise Base Set", nil, -5*24*time.Hour, 6*time.Hour)
+	newAuction("Venusaur Base Set", nil, -5*24*time.Hour, 10*24*time.Hour)
+	newAuction("Pikachu Illustrator", &pokemon, -2*time.Hour, 7*24*time.Hour)
+	newAuction("Old Mewtwo", &pokemon, -10*24*time.Hour, 7*24*time.Hour)
+	watchlistRepo.items[collector.ID] = map[uuid.UUID]bool{endingTonight.ID: true}
+
+	setDigest := func(t *testing.T, token string, body map[string]interface{}) (int, *domain.APIResponse, domain.DigestPreferences) {
+		t.Helper()
+		rr := makeRequest(t, r, "PUT", "/api/notifications/digest", body, token)
+		resp := parseResponse(t, rr)
+		var preferences domain.DigestPreferences
+		data, _ := json.Marshal(resp.Data)
+		json.Unmarshal(data, &preferences)
+		return rr.Code, resp, preferences
+	}
+
+	t.Run("off until set", func(t *testing.T) {
+		rr := makeRequest(t, r, "GET", "/api/notifications/digest", nil, collectorToken)
+		var preferences domain.DigestPreferences
+		data, _ := json.Marshal(parseResponse(t, rr).Data)
+		json.Unmarshal(data, &preferences)
+		if rr.Code != http.StatusOK || preferences.Frequency != domain.DigestOff || !preferences.WatchedEnding {
+			t.Errorf("expected the defaults, got %d %+v", rr.Code, preferences)
+		}
+	})
+
+	t.Run("due at the chosen hour in the user's timezone", func(t *testing.T) {
+		code, resp, preferences := setDigest(t, collectorToken, map[string]interface{}{
+			"frequency":      "weekly",
+			"hour":           9,
+			"weekday":        int(time.Saturday),
+			"timezone":       "Asia/Tokyo",
+			"watched_ending": true,
+			"saved_searches": true,
+			"seller_stats":   false,
+		})
+		if code != http.StatusOK || preferences.NextSendAt == nil {
+			t.Fatalf("expected 200 with the next send, got %d: %+v", code, resp.Error)
+		}
+		tokyo, _ := time.LoadLocation("Asia/Tokyo")
+		next := preferences.NextSendAt.In(tokyo)
+		if next.Hour() != 9 || next.Minute() != 0 || next.Weekday() != time.Saturday ||
+			!next.After(time.Now()) || next.After(time.Now().Add(7*24*time.Hour)) {
+			t.Errorf("expected the coming Saturday 09:00 in Tokyo, got %s", next)
+		}
+	})
+
+	t.Run("unknown timezone", func(t *testing.T) {
+		code, resp, _ := setDigest(t, quietToken, map[string]interface{}{
+			"frequency": "daily",
+			"hour":      8,
+			"timezone":  "Mars/Olympus_Mons",
+		})
+		if code != http.StatusBadRequest || resp.Error.Code != "INVALID_TIMEZONE" {
+			t.Errorf("expected INVALID_TIMEZONE, got %d: %+v", code, resp.Error)
+		}
+	})
+
+	t.Run("unknown frequency", func(t *testing.T) {
+		code, _, _ := setDigest(t, quietToken, map[string]interface{}{
+			"frequency": "hourly",
+			"timezone":  "UTC",
+		})
+		if code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", code)
+		}
+	})
+
+	var search domain.SavedSearch
+	t.Run("save a search", func(t *testing.T) {
+		rr := makeRequest(t, r, "POST", "/api/users/me/saved-searches", map[string]interface{}{
+			"name":  "Pokemon",
+			"query": map[string]interface{}{"category_id": pokemon},
+		}, collectorToken)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+		data, _ := json.Marshal(parseResponse(t, rr).Data)
+		json.Unmarshal(data, &search)
+
+		rr = makeRequest(t, r, "GET", "/api/users/me/saved-searches", nil, collectorToken)
+		var searches []domain.SavedSearch
+		data, _ = json.Marshal(parseResponse(t, rr).Data)
+		json.Unmarshal(data, &searches)
+		if len(searches) != 1 || searches[0].Query.CategoryID == nil || *searches[0].Query.CategoryID != pokemon {
+			t.Errorf("expected the saved search, got %+v", searches)
+		}
+	})
+
+	t.Run("bad price range", func(t *testing.T) {
+		rr := makeRequest(t, r, "POST", "/api/users/me/saved-searches", map[string]interface{}{
+			"name":  "Backwards",
+			"query": map[string]interface{}{"min_price": "100", "max_price": "10"},
+		}, collectorToken)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("saved search limit", func(t *testing.T) {
+		for i := 0; i < domain.MaxSavedSearches; i++ {
+			makeRequest(t, r, "POST", "/api/users/me/saved-searches", map[string]interface{}{"name": "Anything"}, quietToken)
+		}
+		rr := makeRequest(t, r, "POST", "/api/users/me/saved-searches", map[string]interface{}{"name": "One more"}, quietToken)
+		if rr.Code != http.StatusBadRequest || parseResponse(t, rr).Error.Code != "SAVED_SEARCH_LIMIT" {
+			t.Errorf("expected SAVED_SEARCH_LIMIT, got %d: %s", rr.Code, rr.Body.String())
+		}
+		savedSearchRepo.searches = slices.DeleteFunc(savedSearchRepo.searches, func(s domain.SavedSearch) bool {
+			return s.UserID == quiet.ID
+		})
+	})
+
+	t.Run("only the owner deletes a saved search", func(t *testing.T) {
+		rr := makeRequest(t, r, "DELETE", "/api/users/me/saved-searches/"+search.ID.String(), nil, sellerToken)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rr.Code)
+		}
+	})
+
+	t.Run("preview", func(t *testing.T) {
+		rr := makeRequest(t, r, "GET", "/api/notifications/digest/preview", nil, collectorToken)
+		var digest domain.Digest
+		data, _ := json.Marshal(parseResponse(t, rr).Data)
+		json.Unmarshal(data, &digest)
+		if len(digest.WatchedEnding) != 1 || digest.WatchedEnding[0].ID != endingTonight.ID {
+			t.Errorf("expected the watched auction ending tonight, got %+v", digest.WatchedEnding)
+		}
+		// Only the Pokemon listing started this week is new
+		if len(digest.SavedSearches) != 1 || digest.SavedSearches[0].Total != 1 ||
+			digest.SavedSearches[0].Auctions[0].Title != "Pikachu Illustrator" {
+			t.Errorf("expected one new match, got %+v", digest.SavedSearches)
+		}
+		if digest.SellerStats != nil {
+			t.Errorf("expected no seller stats, got %+v", digest.SellerStats)
+		}
+	})
+
+	t.Run("one email per user when due", func(t *testing.T) {
+		setDigest(t, sellerToken, map[string]interface{}{
+			"frequency":    "daily",
+			"hour":         7,
+			"timezone":     "Europe/Berlin",
+			"seller_stats": true,
+		})
+		// A digest with nothing in it isn't sent
+		setDigest(t, quietToken, map[string]interface{}{
+			"frequency":      "daily",
+			"hour":           7,
+			"timezone":       "UTC",
+			"watched_ending": true,
+		})
+
+		past := time.Now().Add(-time.Minute)
+		for _, p := range digestRepo.preferences {
+			p.NextSendAt = &past
+		}
+		now := time.Now()
+		sent, err := digestService.SendDue(context.Background(), now, 100)
+		if err != nil || sent != 2 {
+			t.Fatalf("expected 2 digests, got %d: %v", sent, err)
+		}
+		if !slices.Equal(sortedStrings(emailSender.sentEmails), []string{collector.Email, seller.Email}) {
+			t.Fatalf("expected digests to the collector and seller, got %v", emailSender.sentEmails)
+		}
+
+		for i, to := range emailSender.sentEmails {
+			body := emailSender.bodies[i]
+			switch to {
+			case collector.Email:
+				if !strings.Contains(body, "weekly digest") || !strings.Contains(body, `New listings for "Pokemon"`) ||
+					!strings.Contains(body, "Pikachu Illustrator") || strings.Contains(body, "Your listings") {
+					t.Errorf("unexpected collector digest:\n%s", body)
+				}
+			case seller.Email:
+				if !strings.Contains(body, "daily digest") || !strings.Contains(body, "4 active") ||
+					!strings.Contains(body, "https://cards.example/profile?tab=notifications") {
+					t.Errorf("unexpected seller digest:\n%s", body)
+				}
+			}
+		}
+
+		for _, user := range []*domain.User{collector, seller, quiet} {
+			p := digestRepo.preferences[user.ID]
+			if p.LastSentAt == nil || !p.LastSentAt.Equal(now) || !p.NextSendAt.After(now) {
+				t.Errorf("expected %s's digest marked sent, got %+v", user.Username, p)
+			}
+		}
+
+		emailSender.sentEmails, emailSender.bodies = nil, nil
+		if sent, _ := digestService.SendDue(context.Background(), now, 100); sent != 0 {
+			t.Errorf("expected nothing due again, sent %d", sent)
+		}
+	})
+
+	t.Run("watchers with a digest get no ending email", func(t *testing.T) {
+		watchlistRepo.items[quiet.ID] = map[uuid.UUID]bool{endingTonight.ID: true}
+		setDigest(t, quietToken, map[string]interface{}{
+			"frequency": "off",
+			"timezone":  "UTC",
+		})
+
+		notificationSender := &mockEmailSender{}
+		notificationService := service.NewNotificationService(
+			&mockNotificationRepo{},
+			userRepo,
+			watchlistRepo,
+			nil,
+			nil,
+			nil,
+			digestRepo,
+			notificationSender,
+			nil,
+			nil,
+			nil,
+			nil,
+			"https://cards.example",
+		)
+		notificationService.NotifyAuctionEnding(context.Background(), endingTonight)
+
+		if !slices.Equal(notificationSender.sentEmails, []string{quiet.Email}) {
+			t.Errorf("expected only the watcher without a digest emailed, got %v", notificationSender.sentEmails)
+		}
+	})
+}
+
+func sortedStrings(s []string) []string {
+	sorted := slices.Clone(s)
+	slices.Sort(sorted)
+	return sorted
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type DisputeHandler struct {
+	disputeService *service.DisputeService
+}
+
+func NewDisputeHandler(disputeService *service.DisputeService) *DisputeHandler {
+	return &DisputeHandler{disputeService: disputeService}
+}
+
+func (h *DisputeHandler) Open(w http.ResponseWriter, r *http.Request) {
+	orderID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid order ID")
+		return
+	}
+
+	var req domain.OpenDisputeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	userID := getUserID(r)
+	dispute, err := h.disputeService.Open(r.Context(), orderID, userID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, dispute)
+}
+
+func (h *DisputeHandler) ListForOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid order ID")
+		return
+	}
+
+	userID := getUserID(r)
+	disputes, err := h.disputeService.GetByOrder(r.Context(), orderID, userID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, disputes)
+}
+
+func (h *DisputeHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid dispute ID")
+		return
+	}
+
+	userID := getUserID(r)
+	dispute, err := h.disputeService.GetByID(r.Context(), id, userID, isAdmin(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, dispute)
+}
+
+func (h *DisputeHandler) AddMessage(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid dispute ID")
+		return
+	}
+
+	var req domain.DisputeMessageRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	userID := getUserID(r)
+	msg, err := h.disputeService.AddMessage(r.Context(), id, userID, isAdmin(r), &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, msg)
+}
+
+func (h *DisputeHandler) UploadEvidence(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid dispute ID")
+		return
+	}
+
+	// Parse multipart form (max 10MB)
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_FORM", "Invalid form data")
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "NO_FILE", "No evidence file provided")
+		return
+	}
+	defer file.Close()
+
+	contentType := header.Header.Get("Content-Type")
+
+	var description *string
+	if d := r.FormValue("description"); d != "" {
+		description = &d
+	}
+
+	userID := getUserID(r)
+	evidence, err := h.disputeService.UploadEvidence(r.Context(), id, userID, file, contentType, header.Size, description)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, evidence)
+}
+
+// Admin handlers
+
+func (h *DisputeHandler) AdminList(w http.ResponseWriter, r *http.Request) {
+	params := &domain.DisputeListParams{
+		UserID: getQueryParamUUID(r, "user_id"),
+		Page:   getQueryParamInt(r, "page", 1),
+		Limit:  getQueryParamInt(r, "limit", 20),
+	}
+
+	if status := getQueryParamString(r, "status"); status != nil {
+		s := domain.DisputeStatus(*status)
+		params.Status = &s
+	}
+
+	disputes, totalCount, err := h.disputeService.List(r.Context(), params)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	totalPages := (totalCount + params.Limit - 1) / params.Limit
+
+	respondJSONWithMeta(w, http.StatusOK, disputes, &domain.APIMeta{
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalCount: totalCount,
+		TotalPages: totalPages,
+	})
+}
+
+func (h *DisputeHandler) AdminResolve(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid dispute ID")
+		return
+	}
+
+	var req domain.ResolveDisputeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	adminID := getUserID(r)
+	dispute, err := h.disputeService.Resolve(r.Context(), id, adminID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, dispute)
+}
@@ -0,0 +1,393 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock dispute repository
+type mockDisputeRepo struct {
+	disputes map[uuid.UUID]*domain.Dispute
+	messages map[uuid.UUID][]domain.DisputeMessage
+	evidence map[uuid.UUID][]domain.DisputeEvidence
+}
+
+func newMockDisputeRepo() *mockDisputeRepo {
+	return &mockDisputeRepo{
+		disputes: make(map[uuid.UUID]*domain.Dispute),
+		messages: make(map[uuid.UUID][]domain.DisputeMessage),
+		evidence: make(map[uuid.UUID][]domain.DisputeEvidence),
+	}
+}
+
+func (r *mockDisputeRepo) Create(ctx context.Context, dispute *domain.Dispute) error {
+	dispute.ID = uuid.New()
+	dispute.CreatedAt = time.Now()
+	dispute.UpdatedAt = dispute.CreatedAt
+	stored := *dispute
+	r.disputes[dispute.ID] = &stored
+	return nil
+}
+
+func (r *mockDisputeRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Dispute, error) {
+	dispute, ok := r.disputes[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *dispute
+	return &copied, nil
+}
+
+func (r *mockDisputeRepo) GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]domain.Dispute, error) {
+	disputes := make([]domain.Dispute, 0)
+	for _, dispute := range r.disputes {
+		if dispute.OrderID == orderID {
+			disputes = append(disputes, *dispute)
+		}
+	}
+	return disputes, nil
+}
+
+func (r *mockDisputeRepo) List(ctx context.Context, params *domain.DisputeListParams) ([]domain.Dispute, int, error) {
+	disputes := make([]domain.Dispute, 0)
+	for _, dispute := range r.disputes {
+		if params.Status == nil || dispute.Status == *params.Status {
+			disputes = append(disputes, *dispute)
+		}
+	}
+	return disputes, len(disputes), nil
+}
+
+func (r *mockDisputeRepo) Resolve(ctx context.Context, dispute *domain.Dispute) error {
+	stored, ok := r.disputes[dispute.ID]
+	if !ok || stored.Status != domain.DisputeStatusOpen {
+		return domain.ErrDisputeClosed
+	}
+	now := time.Now()
+	dispute.Status = domain.DisputeStatusResolved
+	dispute.ResolvedAt = &now
+	dispute.UpdatedAt = now
+	resolved := *dispute
+	r.disputes[dispute.ID] = &resolved
+	return nil
+}
+
+func (r *mockDisputeRepo) AddMessage(ctx context.Context, msg *domain.DisputeMessage) error {
+	msg.ID = uuid.New()
+	msg.CreatedAt = time.Now()
+	r.messages[msg.DisputeID] = append(r.messages[msg.DisputeID], *msg)
+	return nil
+}
+
+func (r *mockDisputeRepo) GetMessages(ctx context.Context, disputeID uuid.UUID) ([]domain.DisputeMessage, error) {
+	return r.messages[disputeID], nil
+}
+
+func (r *mockDisputeRepo) AddEvidence(ctx context.Context, evidence *domain.DisputeEvidence) error {
+	evidence.ID = uuid.New()
+	evidence.CreatedAt = time.Now()
+	r.evidence[evidence.DisputeID] = append(r.evidence[evidence.DisputeID], *evidence)
+	return nil
+}
+
+func (r *mockDisputeRepo) GetEvidence(ctx context.Context, disputeID uuid.UUID) ([]domain.DisputeEvidence, error) {
+	return r.evidence[disputeID], nil
+}
+
+// Mock order repository
+type mockOrderRepo struct {
+	orders map[uuid.UUID]*domain.Order
+}
+
+func newMockOrderRepo() *mockOrderRepo {
+	return &mockOrderRepo{orders: make(map[uuid.UUID]*domain.Order)}
+}
+
+func (r *mockOrderRepo) Create(ctx context.Context, order *domain.Order) error {
+	if order.ID == uuid.Nil {
+		order.ID = uuid.New()
+	}
+	order.CreatedAt = time.Now()
+	r.orders[order.ID] = order
+	return nil
+}
+
+func (r *mockOrderRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return order, nil
+}
+
+func (r *mockOrderRepo) List(ctx context.Context, params *domain.OrderListParams) ([]domain.Order, int, error) {
+	orders := make([]domain.Order, 0)
+	for _, order := range r.orders {
+		orders = append(orders, *order)
+	}
+	return orders, len(orders), nil
+}
+
+func (r *mockOrderRepo) GetOverdue(ctx context.Context, before time.Time, limit int) ([]domain.Order, error) {
+	return nil, nil
+}
+
+func (r *mockOrderRepo) MarkPaid(ctx context.Context, id uuid.UUID) error {
+	order, ok := r.orders[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	order.Status = domain.OrderStatusPaid
+	return nil
+}
+
+func (r *mockOrderRepo) Cancel(ctx context.Context, id uuid.UUID, reason string) error {
+	order, ok := r.orders[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	order.Status = domain.OrderStatusCancelled
+	return nil
+}
+
+func (r *mockOrderRepo) AddUnpaidStrike(ctx context.Context, userID, orderID uuid.UUID) error {
+	return nil
+}
+
+// Mock message repository, only tracking legal holds
+type mockMessageRepo struct {
+	legalHolds int
+}
+
+func (r *mockMessageRepo) GetOrCreateConversation(ctx context.Context, userOne, userTwo uuid.UUID, mode domain.EncryptionMode) (*domain.Conversation, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockMessageRepo) GetConversationByID(ctx context.Context, id uuid.UUID) (*domain.Conversation, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockMessageRepo) GetConversationsForUser(ctx context.Context, userID uuid.UUID) ([]domain.Conversation, error) {
+	return nil, nil
+}
+
+func (r *mockMessageRepo) GetConversationDetailsForUser(ctx context.Context, userID uuid.UUID) ([]domain.ConversationWithDetails, error) {
+	return nil, nil
+}
+
+func (r *mockMessageRepo) GetConversationDetails(ctx context.Context, conversationID, userID uuid.UUID) (*domain.ConversationWithDetails, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockMessageRepo) CreateMessage(ctx context.Context, msg *domain.Message) error {
+	return nil
+}
+
+func (r *mockMessageRepo) GetMessagesByConversation(ctx context.Context, conversationID uuid.UUID, page, limit int) ([]domain.Message, int, error) {
+	return nil, 0, nil
+}
+
+func (r *mockMessageRepo) GetMessagesWindow(ctx context.Context, conversationID uuid.UUID, params *domain.MessageWindowParams) (*domain.MessageWindow, error) {
+	return &domain.MessageWindow{}, nil
+}
+
+func (r *mockMessageRepo) UpdateReadStatus(ctx context.Context, conversationID, userID uuid.UUID) error {
+	return nil
+}
+
+func (r *mockMessageRepo) GetReadStatus(ctx context.Context, conversationID, userID uuid.UUID) (*domain.ConversationReadStatus, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockMessageRepo) GetTotalUnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	return 0, nil
+}
+
+func (r *mockMessageRepo) GetUnreadCounts(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]int, error) {
+	return nil, nil
+}
+
+func (r *mockMessageRepo) ReconcileUnreadCounts(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (r *mockMessageRepo) DeleteExpiredMessages(ctx context.Context, defaultDays, limit int) (int64, error) {
+	return 0, nil
+}
+
+func (r *mockMessageRepo) SyncLegalHolds(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (r *mockMessageRepo) RefreshLegalHold(ctx context.Context, userOne, userTwo uuid.UUID) error {
+	r.legalHolds++
+	return nil
+}
+
+func (r *mockMessageRepo) CountFlaggedMessages(ctx context.Context, senderID uuid.UUID, since time.Time) (int, error) {
+	return 0, nil
+}
+
+func (r *mockMessageRepo) HasSentSince(ctx context.Context, conversationID, senderID uuid.UUID, since time.Time) (bool, error) {
+	return false, nil
+}
+
+func (r *mockMessageRepo) IsUserInConversation(ctx context.Context, conversationID, userID uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+// A dispute goes from being opened by a party to the order, through the
+// other party's response and an admin joining the thread, to a refund. Each
+// step is only open to those the workflow allows, and nothing moves once
+// the dispute is resolved.
+func TestDisputeHandler_Workflow(t *testing.T) {
+	disputeRepo := newMockDisputeRepo()
+	orderRepo := newMockOrderRepo()
+	messageRepo := &mockMessageRepo{}
+	escrowRepo := newMockEscrowRepo()
+	ledgerRepo := newMockLedgerRepo(escrowRepo)
+	auditRepo := &mockAuditLogRepo{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	ledgerService := service.NewLedgerService(escrowRepo, ledgerRepo, orderRepo, auditRepo, &mockTxManager{}, nil, 14*24*time.Hour)
+	disputeService := service.NewDisputeService(disputeRepo, messageRepo, orderRepo, nil, auditRepo, &mockTxManager{}, nil, ledgerService, nil)
+	disputeHandler := handler.NewDisputeHandler(disputeService)
+
+	r := createTestRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Post("/api/orders/{id}/disputes", disputeHandler.Open)
+		r.Get("/api/orders/{id}/disputes", disputeHandler.ListForOrder)
+		r.Get("/api/disputes/{id}", disputeHandler.GetByID)
+		r.Post("/api/disputes/{id}/messages", disputeHandler.AddMessage)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(authMiddleware.RequireAdmin)
+		r.Post("/api/admin/disputes/{id}/messages", disputeHandler.AddMessage)
+		r.Post("/api/admin/disputes/{id}/resolve", disputeHandler.AdminResolve)
+	})
+
+	buyerID, sellerID, strangerID, adminID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	buyerToken, _ := jwtManager.GenerateAccessToken(buyerID, "user")
+	sellerToken, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+	strangerToken, _ := jwtManager.GenerateAccessToken(strangerID, "user")
+	adminToken, _ := jwtManager.GenerateAccessToken(adminID, "admin")
+
+	order := &domain.Order{
+		AuctionID: uuid.New(),
+		BuyerID:   buyerID,
+		SellerID:  sellerID,
+		Amount:    decimal.NewFromFloat(200),
+		Status:    domain.OrderStatusPaid,
+	}
+	orderRepo.Create(context.Background(), order)
+	if _, err := ledgerService.HoldPayment(context.Background(), order); err != nil {
+		t.Fatalf("failed to hold payment: %v", err)
+	}
+	cancelled := &domain.Order{
+		AuctionID: uuid.New(),
+		BuyerID:   buyerID,
+		SellerID:  sellerID,
+		Amount:    decimal.NewFromFloat(80),
+		Status:    domain.OrderStatusCancelled,
+	}
+	orderRepo.Create(context.Background(), cancelled)
+
+	openBody := domain.OpenDisputeRequest{Reason: "not_as_described", Description: "The card arrived creased down the middle"}
+	message := domain.DisputeMessageRequest{Body: "Photos attached to the listing show it flat"}
+
+	var disputeID string
+	disputePath := func() string { return "/api/disputes/" + disputeID }
+	adminPath := func() string { return "/api/admin/disputes/" + disputeID }
+	orderPath := "/api/orders/" + order.ID.String() + "/disputes"
+
+	steps := []struct {
+		name       string
+		method     string
+		path       func() string
+		body       interface{}
+		token      string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "stranger cannot open", method: "POST", path: func() string { return orderPath }, body: openBody, token: strangerToken, wantStatus: http.StatusForbidden},
+		{name: "no dispute on a cancelled order", method: "POST", path: func() string { return "/api/orders/" + cancelled.ID.String() + "/disputes" }, body: openBody, token: buyerToken, wantStatus: http.StatusBadRequest, wantCode: "ORDER_NOT_PENDING"},
+		{name: "buyer opens", method: "POST", path: func() string { return orderPath }, body: openBody, token: buyerToken, wantStatus: http.StatusCreated},
+		{name: "second open dispute on the order", method: "POST", path: func() string { return orderPath }, body: openBody, token: sellerToken, wantStatus: http.StatusConflict, wantCode: "DISPUTE_EXISTS"},
+		{name: "stranger cannot list the order's disputes", method: "GET", path: func() string { return orderPath }, token: strangerToken, wantStatus: http.StatusForbidden},
+		{name: "stranger cannot view", method: "GET", path: disputePath, token: strangerToken, wantStatus: http.StatusForbidden},
+		{name: "stranger cannot post", method: "POST", path: func() string { return disputePath() + "/messages" }, body: message, token: strangerToken, wantStatus: http.StatusForbidden},
+		{name: "seller responds", method: "POST", path: func() string { return disputePath() + "/messages" }, body: message, token: sellerToken, wantStatus: http.StatusCreated},
+		{name: "buyer replies", method: "POST", path: func() string { return disputePath() + "/messages" }, body: message, token: buyerToken, wantStatus: http.StatusCreated},
+		{name: "buyer cannot escalate through the admin thread", method: "POST", path: func() string { return adminPath() + "/messages" }, body: message, token: buyerToken, wantStatus: http.StatusForbidden},
+		{name: "admin joins the thread", method: "POST", path: func() string { return adminPath() + "/messages" }, body: message, token: adminToken, wantStatus: http.StatusCreated},
+		{name: "buyer cannot resolve", method: "POST", path: func() string { return adminPath() + "/resolve" }, body: domain.ResolveDisputeRequest{Outcome: "refund"}, token: buyerToken, wantStatus: http.StatusForbidden},
+		{name: "seller cannot resolve", method: "POST", path: func() string { return adminPath() + "/resolve" }, body: domain.ResolveDisputeRequest{Outcome: "uphold"}, token: sellerToken, wantStatus: http.StatusForbidden},
+		{name: "partial refund without an amount", method: "POST", path: func() string { return adminPath() + "/resolve" }, body: domain.ResolveDisputeRequest{Outcome: "partial"}, token: adminToken, wantStatus: http.StatusBadRequest, wantCode: "INVALID_REFUND"},
+		{name: "partial refund of the whole order", method: "POST", path: func() string { return adminPath() + "/resolve" }, body: domain.ResolveDisputeRequest{Outcome: "partial", RefundAmount: stringPtr("200")}, token: adminToken, wantStatus: http.StatusBadRequest, wantCode: "INVALID_REFUND"},
+		{name: "admin resolves with a refund", method: "POST", path: func() string { return adminPath() + "/resolve" }, body: domain.ResolveDisputeRequest{Outcome: "refund", Note: stringPtr("Damaged in transit")}, token: adminToken, wantStatus: http.StatusOK},
+		{name: "resolving twice", method: "POST", path: func() string { return adminPath() + "/resolve" }, body: domain.ResolveDisputeRequest{Outcome: "uphold"}, token: adminToken, wantStatus: http.StatusBadRequest, wantCode: "DISPUTE_CLOSED"},
+		{name: "no messages once resolved", method: "POST", path: func() string { return disputePath() + "/messages" }, body: message, token: buyerToken, wantStatus: http.StatusBadRequest, wantCode: "DISPUTE_CLOSED"},
+		{name: "seller still sees the outcome", method: "GET", path: disputePath, token: sellerToken, wantStatus: http.StatusOK},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			rr := makeRequest(t, r, step.method, step.path(), step.body, step.token)
+			if rr.Code != step.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v, body %s", rr.Code, step.wantStatus, rr.Body.String())
+			}
+
+			response := parseResponse(t, rr)
+			if step.wantCode != "" && (response.Error == nil || response.Error.Code != step.wantCode) {
+				t.Errorf("expected error code %s, got %+v", step.wantCode, response.Error)
+			}
+			if step.name == "buyer opens" {
+				disputeID = response.Data.(map[string]interface{})["id"].(string)
+			}
+		})
+	}
+
+	id, _ := uuid.Parse(disputeID)
+	dispute := disputeRepo.disputes[id]
+	if dispute.Status != domain.DisputeStatusResolved || dispute.RefundAmount == nil || !dispute.RefundAmount.Equal(order.Amount) {
+		t.Errorf("expected a full refund, got status %s and refund %v", dispute.Status, dispute.RefundAmount)
+	}
+	if dispute.ResolvedBy == nil || *dispute.ResolvedBy != adminID {
+		t.Errorf("expected the admin to be recorded as resolving, got %v", dispute.ResolvedBy)
+	}
+
+	messages := disputeRepo.messages[id]
+	if len(messages) != 3 || messages[0].IsAdmin || messages[1].IsAdmin || !messages[2].IsAdmin {
+		t.Errorf("expected two party messages and one from the admin, got %+v", messages)
+	}
+
+	if hold := escrowRepo.holds[order.ID]; hold.Status != domain.EscrowRefunded || !hold.Refunded.Equal(order.Amount) {
+		t.Errorf("expected the escrow to be refunded in full, got %s with %s refunded", hold.Status, hold.Refunded)
+	}
+	if messageRepo.legalHolds != 2 {
+		t.Errorf("expected the legal hold placed on open and released on resolve, got %d refreshes", messageRepo.legalHolds)
+	}
+
+	var resolveAudits int
+	for _, entry := range auditRepo.entries {
+		if entry.Action == domain.AuditActionDisputeResolve {
+			resolveAudits++
+		}
+	}
+	if resolveAudits != 1 {
+		t.Errorf("got %d resolve audit entries, want 1", resolveAudits)
+	}
+}
@@ -0,0 +1,197 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock seller follow repository
+type mockSellerFollowRepo struct {
+	follows map[uuid.UUID]map[uuid.UUID]time.Time
+}
+
+func (r *mockSellerFollowRepo) Follow(ctx context.Context, follow *domain.SellerFollow) (bool, error) {
+	if r.follows[follow.UserID] == nil {
+		r.follows[follow.UserID] = make(map[uuid.UUID]time.Time)
+	}
+	if createdAt, ok := r.follows[follow.UserID][follow.SellerID]; ok {
+		follow.CreatedAt = createdAt
+		return false, nil
+	}
+	follow.CreatedAt = time.Now()
+	r.follows[follow.UserID][follow.SellerID] = follow.CreatedAt
+	return true, nil
+}
+
+func (r *mockSellerFollowRepo) Unfollow(ctx context.Context, userID, sellerID uuid.UUID) error {
+	if _, ok := r.follows[userID][sellerID]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.follows[userID], sellerID)
+	return nil
+}
+
+func (r *mockSellerFollowRepo) List(ctx context.Context, userID uuid.UUID, page, limit int) ([]domain.SellerFollow, int, error) {
+	follows := make([]domain.SellerFollow, 0)
+	for sellerID, createdAt := range r.follows[userID] {
+		follows = append(follows, domain.SellerFollow{UserID: userID, SellerID: sellerID, CreatedAt: createdAt})
+	}
+	return follows, len(follows), nil
+}
+
+func TestFollowHandler_ImportEbay(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	userRepo := newMockUserRepo()
+	watchlistRepo := newMockWatchlistRepo()
+	followRepo := &mockSellerFollowRepo{follows: make(map[uuid.UUID]map[uuid.UUID]time.Time)}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	followService := service.NewFollowService(followRepo, userRepo)
+	ebayImportService := service.NewEbayImportService(auctionRepo, watchlistRepo, userRepo, followService, 100, 1<<20)
+	followHandler := handler.NewFollowHandler(followService, ebayImportService)
+
+	r := createTestRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Post("/api/watchlist/import/ebay", followHandler.ImportEbay)
+		r.Get("/api/users/me/following", followHandler.ListFollowing)
+		r.Put("/api/users/me/following/{userId}", followHandler.Follow)
+	})
+
+	user := &domain.User{Email: "migrant@example.com", Username: "migrant"}
+	seller := &domain.User{Email: "seller@example.com", Username: "cardkingdom"}
+	for _, u := range []*domain.User{user, seller} {
+		userRepo.Create(context.Background(), u)
+	}
+	token, _ := jwtManager.GenerateAccessToken(user.ID, "user")
+
+	listing := func(title string, visibility domain.AuctionVisibility) *domain.Auction {
+		auction := &domain.Auction{
+			SellerID:     seller.ID,
+			Title:        title,
+			CurrentPrice: decimal.NewFromInt(50),
+			Status:       domain.AuctionStatusActive,
+			Visibility:   visibility,
+			EndTime:      time.Now().Add(24 * time.Hour),
+		}
+		auctionRepo.Create(context.Background(), auction)
+		return auction
+	}
+	charizard := listing("Charizard 4/102 Base Set Holo PSA 9", domain.AuctionPublic)
+	blastoise := listing("Blastoise 2/102 Base Set Holo", domain.AuctionPublic)
+	listing("Venusaur 15/102 Base Set Holo PSA 10", domain.AuctionPrivate)
+	watchlistRepo.Add(context.Background(), &domain.WatchlistItem{UserID: user.ID, AuctionID: blastoise.ID})
+
+	upload := func(t *testing.T, content string) *httptest.ResponseRecorder {
+		t.Helper()
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, _ := writer.CreateFormFile("file", "ebay.csv")
+		part.Write([]byte(content))
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/api/watchlist/import/ebay", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+	importReport := func(t *testing.T, content string) domain.EbayImportReport {
+		t.Helper()
+		rr := upload(t, content)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var report domain.EbayImportReport
+		data, _ := json.Marshal(parseResponse(t, rr).Data)
+		if err := json.Unmarshal(data, &report); err != nil {
+			t.Fatalf("failed to decode report: %v", err)
+		}
+		return report
+	}
+
+	t.Run("watchlist", func(t *testing.T) {
+		report := importReport(t, "\xef\xbb\xbfItem number,Title,Seller,Current price,Time left\n"+
+			"123456789012,Pokemon Charizard 4/102 Holo Base Set PSA 9 MINT,cardkingdom,$420.00,2d 4h\n"+
+			"223456789012,Blastoise 2/102 Base Set Holo Rare,cardkingdom,$120.00,1d\n"+
+			"323456789012,Venusaur 15/102 Base Set Holo PSA 10,someone,$300.00,3d\n"+
+			"423456789012,Yu-Gi-Oh Dark Magician LOB-005,someone,$80.00,5h\n")
+
+		if report.Kind != domain.EbayImportWatchlist || report.TotalRows != 4 || report.Added != 1 || report.Existing != 1 || report.Unmatched != 2 {
+			t.Fatalf("got %+v", report)
+		}
+
+		matched := report.Rows[0]
+		if matched.Status != domain.EbayImportWatched || matched.AuctionID == nil || *matched.AuctionID != charizard.ID || matched.ItemNumber != "123456789012" {
+			t.Errorf("got %+v", matched)
+		}
+		if matched.Score < 0.7 || matched.Score > 1 {
+			t.Errorf("expected a good score, got %v", matched.Score)
+		}
+		if !watchlistRepo.items[user.ID][charizard.ID] {
+			t.Error("expected charizard on the watchlist")
+		}
+		if report.Rows[1].Status != domain.EbayImportAlreadyWatched {
+			t.Errorf("got %+v", report.Rows[1])
+		}
+
+		// Private listings aren't matched, nor other cards of the same set
+		if report.Rows[2].Status != domain.EbayImportUnmatched || report.Rows[2].Row != 3 {
+			t.Errorf("got %+v", report.Rows[2])
+		}
+	})
+
+	t.Run("saved sellers", func(t *testing.T) {
+		report := importReport(t, "Seller,Feedback score\nCardKingdom,1520\nnobody_here,3\nmigrant,0\n")
+
+		if report.Kind != domain.EbayImportSavedSellers || report.Added != 1 || report.Unmatched != 2 {
+			t.Fatalf("got %+v", report)
+		}
+		if followed := report.Rows[0]; followed.Status != domain.EbayImportFollowed || followed.SellerID == nil || *followed.SellerID != seller.ID {
+			t.Errorf("got %+v", followed)
+		}
+
+		rr := makeRequest(t, r, "GET", "/api/users/me/following", nil, token)
+		if meta := parseResponse(t, rr).Meta; rr.Code != http.StatusOK || meta.TotalCount != 1 {
+			t.Errorf("expected one follow, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		// Importing again changes nothing
+		if again := importReport(t, "Seller\ncardkingdom\n"); again.Existing != 1 || again.Rows[0].Status != domain.EbayImportAlreadyFollowed {
+			t.Errorf("got %+v", again)
+		}
+	})
+
+	t.Run("not an eBay export", func(t *testing.T) {
+		rr := upload(t, "name,price\nfoo,1\n")
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rr.Code)
+		}
+		if response := parseResponse(t, rr); response.Error == nil || response.Error.Code != "INVALID_IMPORT_FILE" {
+			t.Errorf("got %+v", response.Error)
+		}
+	})
+
+	t.Run("cannot follow yourself", func(t *testing.T) {
+		rr := makeRequest(t, r, "PUT", "/api/users/me/following/"+user.ID.String(), nil, token)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type EmbedHandler struct {
+	embedService *service.EmbedService
+}
+
+func NewEmbedHandler(embedService *service.EmbedService) *EmbedHandler {
+	return &EmbedHandler{embedService: embedService}
+}
+
+// embedCacheControl lets browsers and CDNs reuse a card briefly. The price
+// can be that much behind; the countdown runs in the reader's browser.
+const embedCacheControl = "public, max-age=30"
+
+// Auction handles GET /embed/auctions/{id}: the auction's card as a
+// self-contained HTML page for an iframe, or as JSON with ?format=json or
+// an Accept header asking for it
+func (h *EmbedHandler) Auction(w http.ResponseWriter, r *http.Request) {
+	wantsJSON := r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json")
+
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		if wantsJSON {
+			respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		} else {
+			writeEmbedUnavailable(w, http.StatusBadRequest)
+		}
+		return
+	}
+
+	embed, err := h.embedService.Auction(r.Context(), auctionID)
+	if err != nil {
+		if wantsJSON {
+			handleError(w, err)
+		} else if errors.Is(err, domain.ErrNotFound) {
+			writeEmbedUnavailable(w, http.StatusNotFound)
+		} else {
+			writeEmbedUnavailable(w, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Cache-Control", embedCacheControl)
+	w.Header().Add("Vary", "Accept")
+	if wantsJSON {
+		respondJSON(w, http.StatusOK, embed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	embedCardTemplate.Execute(w, struct {
+		*domain.AuctionEmbed
+		Ended     bool
+		OEmbedURL string
+	}{
+		AuctionEmbed: embed,
+		Ended:        embed.Status != domain.AuctionStatusActive,
+		OEmbedURL:    h.embedService.OEmbedURL(embed.ID),
+	})
+}
+
+// OEmbed handles GET /api/oembed, answering for links to auctions. Only the
+// json format is offered; the spec has other formats answered with 501.
+func (h *EmbedHandler) OEmbed(w http.ResponseWriter, r *http.Request) {
+	link := r.URL.Query().Get("url")
+	if link == "" {
+		respondError(w, http.StatusBadRequest, "MISSING_URL", "url is required")
+		return
+	}
+	if format := r.URL.Query().Get("format"); format != "" && format != "json" {
+		respondError(w, http.StatusNotImplemented, "UNSUPPORTED_FORMAT", "Only the json format is supported")
+		return
+	}
+
+	oembed, err := h.embedService.OEmbed(r.Context(), link, getQueryParamInt(r, "maxwidth", 0), getQueryParamInt(r, "maxheight", 0))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	respondJSON(w, http.StatusOK, oembed)
+}
+
+func writeEmbedUnavailable(w http.ResponseWriter, status int) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write([]byte(embedUnavailablePage))
+}
+
+// embedCardTemplate is the embed card. It loads nothing from elsewhere but
+// the listing image, so it works under the route's strict content policy.
+var embedCardTemplate = template.Must(template.New("embed").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="alternate" type="application/json+oembed" href="{{.OEmbedURL}}" title="{{.Title}}">
+<style>
+  html, body { margin: 0; height: 100%; font: 14px/1.4 -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; color: #1f2937; }
+  a.card { display: flex; gap: 12px; height: 100%; box-sizing: border-box; padding: 12px; border: 1px solid #e5e7eb; border-radius: 8px; background: #fff; color: inherit; text-decoration: none; }
+  .image { flex: 0 0 auto; width: 30%; max-width: 136px; border-radius: 4px; background: #f3f4f6 center / cover no-repeat; }
+  .details { display: flex; flex-direction: column; min-width: 0; }
+  .title { font-weight: 600; overflow: hidden; display: -webkit-box; -webkit-line-clamp: 2; -webkit-box-orient: vertical; }
+  .price { margin-top: 8px; font-size: 20px; font-weight: 700; }
+  .meta { color: #6b7280; }
+  .cta { margin-top: auto; color: #2563eb; font-weight: 600; }
+</style>
+</head>
+<body>
+<a class="card" href="{{.URL}}" target="_blank" rel="noopener">
+  {{if .ImageURL}}<div class="image" style="background-image: url('{{.ImageURL}}')"></div>{{end}}
+  <div class="details">
+    <div class="title">{{.Title}}</div>
+    <div class="price">{{.CurrentPrice}}</div>
+    <div class="meta">{{.BidCount}} bid{{if ne .BidCount 1}}s{{end}} &middot; <span id="countdown" data-end="{{.EndTime.UTC.Format "2006-01-02T15:04:05Z07:00"}}">{{if .Ended}}Ended{{else}}Ends {{.EndTime.UTC.Format "Jan 2, 15:04 UTC"}}{{end}}</span></div>
+    <div class="cta">{{if .Ended}}View listing{{else}}Bid now{{end}} &rarr;</div>
+  </div>
+</a>
+{{if not .Ended}}<script>
+(function () {
+  var el = document.getElementById("countdown");
+  var end = Date.parse(el.getAttribute("data-end"));
+  function tick() {
+    var left = Math.max(0, Math.floor((end - Date.now()) / 1000));
+    if (left === 0) { el.textContent = "Ended"; return; }
+    var d = Math.floor(left / 86400), h = Math.floor(left % 86400 / 3600), m = Math.floor(left % 3600 / 60), s = left % 60;
+    el.textContent = (d ? d + "d " : "") + (d || h ? h + "h " : "") + m + "m " + s + "s left";
+    setTimeout(tick, 1000);
+  }
+  tick();
+})();
+</script>{{end}}
+</body>
+</html>
+`))
+
+const embedUnavailablePage = `<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>Listing unavailable</title></head>
+<body style="margin:0;font:14px sans-serif;color:#6b7280;display:flex;align-items:center;justify-content:center;height:100vh">This listing is not available.</body>
+</html>
+`
@@ -0,0 +1,194 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/pkg/origin"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestEmbedHandler(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	embedHandler := handler.NewEmbedHandler(service.NewEmbedService(auctionService, "https://cards.example", "https://api.cards.example"))
+
+	r := createTestRouter()
+	r.Use(middleware.CORS(&middleware.CORSConfig{
+		Origins:          origin.NewAllowList([]string{"https://cards.example"}),
+		AllowCredentials: true,
+	}))
+	r.With(middleware.Embeddable).Get("/embed/auctions/{id}", embedHandler.Auction)
+	r.Get("/api/oembed", embedHandler.OEmbed)
+
+	medium := "https://cdn.cards.example/charizard-medium.jpg"
+	listing := func(title string, status domain.AuctionStatus, visibility domain.AuctionVisibility) *domain.Auction {
+		auction := &domain.Auction{
+			SellerID:     uuid.New(),
+			Title:        title,
+			CurrentPrice: decimal.NewFromInt(420),
+			Currency:     domain.CurrencyUSD,
+			BidCount:     7,
+			Status:       status,
+			Visibility:   visibility,
+			EndTime:      time.Now().Add(3 * time.Hour),
+			Images:       []domain.AuctionImage{{URL: "https://cdn.cards.example/charizard.jpg", MediumURL: &medium}},
+		}
+		auctionRepo.Create(context.Background(), auction)
+		return auction
+	}
+	charizard := listing("Charizard <Base Set> PSA 9", domain.AuctionStatusActive, domain.AuctionPublic)
+	draft := listing("Draft listing", domain.AuctionStatusDraft, domain.AuctionPublic)
+	private := listing("Private listing", domain.AuctionStatusActive, domain.AuctionPrivate)
+
+	get := func(t *testing.T, path string, header http.Header) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest("GET", path, nil)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("html card any site may frame", func(t *testing.T) {
+		rr := get(t, "/embed/auctions/"+charizard.ID.String(), http.Header{"Origin": {"https://blog.example"}})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if got := rr.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/html") {
+			t.Errorf("got content type %q", got)
+		}
+		if rr.Header().Get("Access-Control-Allow-Origin") != "*" || rr.Header().Get("Access-Control-Allow-Credentials") != "" {
+			t.Errorf("expected any origin without credentials, got %v", rr.Header())
+		}
+		if csp := rr.Header().Get("Content-Security-Policy"); !strings.Contains(csp, "frame-ancestors *") {
+			t.Errorf("got CSP %q", csp)
+		}
+		if !strings.Contains(rr.Header().Get("Cache-Control"), "max-age") {
+			t.Errorf("expected a cacheable response, got %q", rr.Header().Get("Cache-Control"))
+		}
+
+		body := rr.Body.String()
+		for _, want := range []string{
+			"Charizard &lt;Base Set&gt; PSA 9",
+			"$420.00",
+			"7 bids",
+			"https://cards.example/auctions/" + charizard.ID.String(),
+			medium,
+			`type="application/json+oembed"`,
+			"data-end=",
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("expected %q in the card", want)
+			}
+		}
+		if strings.Contains(body, "<Base Set>") {
+			t.Error("expected the title escaped")
+		}
+	})
+
+	t.Run("json card", func(t *testing.T) {
+		rr := get(t, "/embed/auctions/"+charizard.ID.String()+"?format=json", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		var embed domain.AuctionEmbed
+		data, _ := json.Marshal(parseResponse(t, rr).Data)
+		if err := json.Unmarshal(data, &embed); err != nil {
+			t.Fatalf("failed to decode card: %v", err)
+		}
+		if embed.ID != charizard.ID || embed.BidCount != 7 || !embed.CurrentPrice.Amount.Equal(decimal.NewFromInt(420)) ||
+			embed.ImageURL == nil || *embed.ImageURL != medium || embed.URL != "https://cards.example/auctions/"+charizard.ID.String() {
+			t.Errorf("got %+v", embed)
+		}
+	})
+
+	t.Run("only public listings", func(t *testing.T) {
+		for _, auction := range []*domain.Auction{draft, private} {
+			if rr := get(t, "/embed/auctions/"+auction.ID.String(), nil); rr.Code != http.StatusNotFound {
+				t.Errorf("%s: expected 404, got %d", auction.Title, rr.Code)
+			}
+		}
+	})
+
+	t.Run("app routes keep their CORS", func(t *testing.T) {
+		rr := get(t, "/api/oembed?url=x", http.Header{"Origin": {"https://blog.example"}})
+		if rr.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Errorf("expected no CORS for other origins, got %q", rr.Header().Get("Access-Control-Allow-Origin"))
+		}
+	})
+
+	oembed := func(t *testing.T, query string) (*httptest.ResponseRecorder, domain.OEmbed) {
+		t.Helper()
+		rr := get(t, "/api/oembed?"+query, nil)
+		var response domain.OEmbed
+		if rr.Code == http.StatusOK {
+			data, _ := json.Marshal(parseResponse(t, rr).Data)
+			json.Unmarshal(data, &response)
+		}
+		return rr, response
+	}
+
+	t.Run("oembed", func(t *testing.T) {
+		page := "https://cards.example/auctions/" + charizard.ID.String()
+		rr, response := oembed(t, "url="+url.QueryEscape(page)+"&maxwidth=300")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if response.Type != "rich" || response.Version != "1.0" || response.Width != 300 || response.Height != 160 {
+			t.Errorf("got %+v", response)
+		}
+		if !strings.Contains(response.HTML, `src="https://api.cards.example/embed/auctions/`+charizard.ID.String()+`"`) || !strings.Contains(response.HTML, `width="300"`) {
+			t.Errorf("got HTML %s", response.HTML)
+		}
+
+		// The embed URL itself is accepted too
+		if rr, _ := oembed(t, "url="+url.QueryEscape("https://api.cards.example/embed/auctions/"+charizard.ID.String())); rr.Code != http.StatusOK {
+			t.Errorf("expected 200 for the embed URL, got %d", rr.Code)
+		}
+	})
+
+	t.Run("oembed refusals", func(t *testing.T) {
+		cases := []struct {
+			query string
+			code  int
+		}{
+			{"url=" + url.QueryEscape("https://elsewhere.example/auctions/"+charizard.ID.String()), http.StatusNotFound},
+			{"url=" + url.QueryEscape("https://cards.example/auctions/"+private.ID.String()), http.StatusNotFound},
+			{"url=" + url.QueryEscape("https://cards.example/auctions/"+charizard.ID.String()) + "&format=xml", http.StatusNotImplemented},
+			{"", http.StatusBadRequest},
+		}
+		for _, tc := range cases {
+			if rr, _ := oembed(t, tc.query); rr.Code != tc.code {
+				t.Errorf("%q: expected %d, got %d", tc.query, tc.code, rr.Code)
+			}
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/service"
+)
+
+// factsheetRetryAfter is how many seconds clients are told to wait before
+// asking again for a sheet that is being rendered
+const factsheetRetryAfter = "5"
+
+type FactsheetHandler struct {
+	factsheetService *service.FactsheetService
+}
+
+func NewFactsheetHandler(factsheetService *service.FactsheetService) *FactsheetHandler {
+	return &FactsheetHandler{factsheetService: factsheetService}
+}
+
+// Get redirects to the auction's printable PDF sheet. While the sheet is
+// rendered it answers 202 with its status, and clients ask again after the
+// Retry-After delay.
+func (h *FactsheetHandler) Get(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	factsheet, err := h.factsheetService.Get(r.Context(), auctionID, getUserID(r), r.URL.Query().Get("share"))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if factsheet.DownloadURL != "" {
+		http.Redirect(w, r, factsheet.DownloadURL, http.StatusFound)
+		return
+	}
+
+	w.Header().Set("Retry-After", factsheetRetryAfter)
+	respondJSON(w, http.StatusAccepted, factsheet)
+}
@@ -0,0 +1,170 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/pkg/storage"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock factsheet repository
+type mockFactsheetRepo struct {
+	factsheets map[uuid.UUID]*domain.AuctionFactsheet
+}
+
+func (r *mockFactsheetRepo) GetByAuction(ctx context.Context, auctionID uuid.UUID) (*domain.AuctionFactsheet, error) {
+	factsheet, ok := r.factsheets[auctionID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *factsheet
+	return &copied, nil
+}
+
+func (r *mockFactsheetRepo) Request(ctx context.Context, auctionID uuid.UUID) (*domain.AuctionFactsheet, error) {
+	factsheet, ok := r.factsheets[auctionID]
+	if !ok {
+		factsheet = &domain.AuctionFactsheet{AuctionID: auctionID, CreatedAt: time.Now()}
+		r.factsheets[auctionID] = factsheet
+	}
+	if factsheet.Status != domain.FactsheetProcessing {
+		factsheet.Status = domain.FactsheetPending
+	}
+	copied := *factsheet
+	return &copied, nil
+}
+
+func (r *mockFactsheetRepo) ClaimNext(ctx context.Context, now, leaseUntil time.Time) (*domain.AuctionFactsheet, error) {
+	for _, factsheet := range r.factsheets {
+		if factsheet.Status == domain.FactsheetPending {
+			factsheet.Status = domain.FactsheetProcessing
+			factsheet.LeaseUntil = &leaseUntil
+			claimed := *factsheet
+			return &claimed, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockFactsheetRepo) Save(ctx context.Context, factsheet *domain.AuctionFactsheet) error {
+	saved := *factsheet
+	r.factsheets[factsheet.AuctionID] = &saved
+	return nil
+}
+
+func TestFactsheetHandler_Get(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	factsheetRepo := &mockFactsheetRepo{factsheets: make(map[uuid.UUID]*domain.AuctionFactsheet)}
+	store := &mockObjectStore{objects: make(map[string][]byte)}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil, // no S3 for tests
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	newRouter := func(store storage.ObjectStore) (*chi.Mux, *service.FactsheetService) {
+		factsheetService := service.NewFactsheetService(factsheetRepo, auctionRepo, auctionService, store, "https://cards.example.com")
+		r := createTestRouter()
+		r.With(authMiddleware.OptionalAuth).Get("/api/auctions/{id}/factsheet.pdf", handler.NewFactsheetHandler(factsheetService).Get)
+		return r, factsheetService
+	}
+	r, factsheetService := newRouter(store)
+
+	description := "Sharp corners, centered (as shown)."
+	auction := &domain.Auction{
+		SellerID:      uuid.New(),
+		Title:         "Vintage Holo Card",
+		Description:   &description,
+		StartingPrice: decimal.NewFromFloat(50),
+		CurrentPrice:  decimal.NewFromFloat(75),
+		BidIncrement:  decimal.NewFromFloat(1),
+		StartTime:     time.Now(),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+		Visibility:    domain.AuctionPublic,
+		BidCount:      3,
+	}
+	auctionRepo.Create(context.Background(), auction)
+	sheetPath := "/api/auctions/" + auction.ID.String() + "/factsheet.pdf"
+
+	rr := makeRequest(t, r, "GET", sheetPath, nil, "")
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusAccepted)
+	}
+	if rr.Header().Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header while the sheet is rendered")
+	}
+	if status := parseResponse(t, rr).Data.(map[string]interface{})["status"]; status != "pending" {
+		t.Errorf("expected the sheet to be queued, got %v", status)
+	}
+
+	factsheetService.ProcessPending(context.Background())
+
+	rr = makeRequest(t, r, "GET", sheetPath, nil, "")
+	if rr.Code != http.StatusFound {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusFound)
+	}
+	location := rr.Header().Get("Location")
+	if !strings.HasPrefix(location, "https://storage.example.com/factsheets/"+auction.ID.String()+"/") {
+		t.Errorf("expected a link to the stored sheet, got %q", location)
+	}
+	if len(store.objects) != 1 {
+		t.Fatalf("expected one stored sheet, got %d", len(store.objects))
+	}
+	for _, data := range store.objects {
+		if !bytes.HasPrefix(data, []byte("%PDF-")) {
+			t.Errorf("expected a PDF, got %q", data[:min(len(data), 16)])
+		}
+	}
+
+	// A bid doesn't make the sheet stale, a new title does
+	auction.CurrentPrice = decimal.NewFromFloat(80)
+	if rr := makeRequest(t, r, "GET", sheetPath, nil, ""); rr.Code != http.StatusFound {
+		t.Errorf("expected the stored sheet to be kept after a bid, got %v", rr.Code)
+	}
+	auction.Title = "Vintage Holo Card, Unlimited"
+	if rr := makeRequest(t, r, "GET", sheetPath, nil, ""); rr.Code != http.StatusAccepted {
+		t.Errorf("expected the sheet to be rendered again after an edit, got %v", rr.Code)
+	}
+
+	factsheetService.ProcessPending(context.Background())
+
+	if rr := makeRequest(t, r, "GET", sheetPath, nil, ""); rr.Code != http.StatusFound || rr.Header().Get("Location") == location {
+		t.Errorf("expected a link to the new sheet, got %v %q", rr.Code, rr.Header().Get("Location"))
+	}
+	if len(store.objects) != 1 {
+		t.Errorf("expected the old sheet to be deleted, got %d stored", len(store.objects))
+	}
+
+	if rr := makeRequest(t, r, "GET", "/api/auctions/"+uuid.New().String()+"/factsheet.pdf", nil, ""); rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown auction, got %v", rr.Code)
+	}
+
+	unavailable, _ := newRouter(nil)
+	if rr := makeRequest(t, unavailable, "GET", sheetPath, nil, ""); rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without object storage, got %v", rr.Code)
+	}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type FollowHandler struct {
+	followService     *service.FollowService
+	ebayImportService *service.EbayImportService
+}
+
+func NewFollowHandler(followService *service.FollowService, ebayImportService *service.EbayImportService) *FollowHandler {
+	return &FollowHandler{
+		followService:     followService,
+		ebayImportService: ebayImportService,
+	}
+}
+
+// ListFollowing lists the sellers the current user follows, newest first
+func (h *FollowHandler) ListFollowing(w http.ResponseWriter, r *http.Request) {
+	params := &domain.SellerFollowListParams{
+		Page:  getQueryParamInt(r, "page", 1),
+		Limit: getQueryParamInt(r, "limit", 20),
+	}
+
+	follows, totalCount, err := h.followService.ListFollowing(r.Context(), getUserID(r), params)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	totalPages := (totalCount + params.Limit - 1) / params.Limit
+
+	respondJSONWithMeta(w, http.StatusOK, follows, &domain.APIMeta{
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalCount: totalCount,
+		TotalPages: totalPages,
+	})
+}
+
+func (h *FollowHandler) Follow(w http.ResponseWriter, r *http.Request) {
+	sellerID, err := getURLParamUUID(r, "userId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid user ID")
+		return
+	}
+
+	follow, err := h.followService.Follow(r.Context(), getUserID(r), sellerID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, follow)
+}
+
+func (h *FollowHandler) Unfollow(w http.ResponseWriter, r *http.Request) {
+	sellerID, err := getURLParamUUID(r, "userId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid user ID")
+		return
+	}
+
+	if err := h.followService.Unfollow(r.Context(), getUserID(r), sellerID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Seller unfollowed",
+	})
+}
+
+// ImportEbay adds the items and sellers saved in an eBay watchlist or saved
+// sellers CSV export, reporting what became of each row
+func (h *FollowHandler) ImportEbay(w http.ResponseWriter, r *http.Request) {
+	// Parse multipart form (files beyond 32MB are buffered on disk)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_FORM", "Invalid form data")
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "NO_FILE", "No import file provided")
+		return
+	}
+	defer file.Close()
+
+	report, err := h.ebayImportService.Import(r.Context(), getUserID(r), file)
+	if errors.Is(err, domain.ErrImportFile) {
+		respondError(w, http.StatusBadRequest, "INVALID_IMPORT_FILE", err.Error())
+		return
+	}
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type FraudHandler struct {
+	fraudService *service.FraudService
+}
+
+func NewFraudHandler(fraudService *service.FraudService) *FraudHandler {
+	return &FraudHandler{fraudService: fraudService}
+}
+
+// AdminListFlags lists the fraud review queue, most severe first
+func (h *FraudHandler) AdminListFlags(w http.ResponseWriter, r *http.Request) {
+	params := &domain.FraudFlagListParams{
+		Page:  getQueryParamInt(r, "page", 1),
+		Limit: getQueryParamInt(r, "limit", 20),
+	}
+
+	if status := r.URL.Query().Get("status"); status != "" {
+		s := domain.FraudFlagStatus(status)
+		params.Status = &s
+	}
+	if check := r.URL.Query().Get("check"); check != "" {
+		c := domain.FraudCheck(check)
+		params.Check = &c
+	}
+
+	flags, totalCount, err := h.fraudService.ListFlags(r.Context(), params)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	totalPages := (totalCount + params.Limit - 1) / params.Limit
+
+	respondJSONWithMeta(w, http.StatusOK, flags, &domain.APIMeta{
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalCount: totalCount,
+		TotalPages: totalPages,
+	})
+}
+
+func (h *FraudHandler) AdminReviewFlag(w http.ResponseWriter, r *http.Request) {
+	flagID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid fraud flag ID")
+		return
+	}
+
+	var req domain.ReviewFraudFlagRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	flag, err := h.fraudService.ReviewFlag(r.Context(), getUserID(r), flagID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, flag)
+}
+
+// AdminRunChecks runs the fraud checks now rather than waiting for the
+// scheduler
+func (h *FraudHandler) AdminRunChecks(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.fraudService.RunChecks(r.Context()))
+}
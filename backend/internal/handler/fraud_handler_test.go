@@ -0,0 +1,292 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Mock fraud repository, finding whatever the test seeds
+type mockFraudRepo struct {
+	mu    sync.Mutex
+	flags []domain.FraudFlag
+
+	sharedIP    []domain.SharedIPBidding
+	retractions []domain.BidRetractions
+	velocity    []domain.BidVelocity
+	purchases   []domain.LinkedPurchase
+}
+
+func (r *mockFraudRepo) SaveFlag(ctx context.Context, flag *domain.FraudFlag) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for i := range r.flags {
+		existing := &r.flags[i]
+		if existing.Check != flag.Check || existing.DedupeKey != flag.DedupeKey {
+			continue
+		}
+		if existing.Status != domain.FraudFlagOpen {
+			return false, nil
+		}
+		existing.Severity = flag.Severity
+		existing.Evidence = flag.Evidence
+		existing.RelatedUserIDs = flag.RelatedUserIDs
+		existing.DetectedAt = now
+		*flag = *existing
+		return true, nil
+	}
+	flag.ID = uuid.New()
+	flag.Status = domain.FraudFlagOpen
+	flag.DetectedAt = now
+	flag.CreatedAt = now
+	flag.UpdatedAt = now
+	r.flags = append(r.flags, *flag)
+	return true, nil
+}
+
+func (r *mockFraudRepo) GetFlag(ctx context.Context, id uuid.UUID) (*domain.FraudFlag, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, flag := range r.flags {
+		if flag.ID == id {
+			return &flag, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockFraudRepo) UpdateFlag(ctx context.Context, flag *domain.FraudFlag) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.flags {
+		if r.flags[i].ID == flag.ID {
+			r.flags[i] = *flag
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func (r *mockFraudRepo) ListFlags(ctx context.Context, params *domain.FraudFlagListParams) ([]domain.FraudFlag, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	matched := make([]domain.FraudFlag, 0)
+	for _, flag := range r.flags {
+		if params.Status != nil && flag.Status != *params.Status {
+			continue
+		}
+		if params.Check != nil && flag.Check != *params.Check {
+			continue
+		}
+		matched = append(matched, flag)
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].Severity > matched[j].Severity })
+
+	start := min((params.Page-1)*params.Limit, len(matched))
+	end := min(start+params.Limit, len(matched))
+	return matched[start:end], len(matched), nil
+}
+
+func (r *mockFraudRepo) FindSharedIPBidding(ctx context.Context, since time.Time) ([]domain.SharedIPBidding, error) {
+	return r.sharedIP, nil
+}
+
+func (r *mockFraudRepo) FindBidRetractions(ctx context.Context, since time.Time, minCancelled int) ([]domain.BidRetractions, error) {
+	return r.retractions, nil
+}
+
+func (r *mockFraudRepo) FindNewAccountVelocity(ctx context.Context, since, createdAfter time.Time, minBids int) ([]domain.BidVelocity, error) {
+	return r.velocity, nil
+}
+
+func (r *mockFraudRepo) FindLinkedPurchases(ctx context.Context, since time.Time) ([]domain.LinkedPurchase, error) {
+	return r.purchases, nil
+}
+
+func TestFraudHandler(t *testing.T) {
+	seller, shill, buyer := uuid.New(), uuid.New(), uuid.New()
+	fraudRepo := &mockFraudRepo{
+		sharedIP: []domain.SharedIPBidding{{
+			SellerID:     seller,
+			IP:           "203.0.113.7",
+			BidderIDs:    []uuid.UUID{shill, uuid.New()},
+			AuctionIDs:   []uuid.UUID{uuid.New()},
+			Bids:         12,
+			SellerUsedIP: true,
+		}},
+		retractions: []domain.BidRetractions{{BidderID: shill, Placed: 5, Cancelled: 4, Sellers: 1}},
+		velocity:    []domain.BidVelocity{{UserID: uuid.New(), AccountCreatedAt: time.Now().Add(-time.Hour), Bids: 45, Auctions: 1}},
+		purchases: []domain.LinkedPurchase{{
+			AuctionID:    uuid.New(),
+			OrderID:      uuid.New(),
+			SellerID:     seller,
+			BuyerID:      buyer,
+			SharedDevice: true,
+		}},
+	}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	fraudHandler := handler.NewFraudHandler(service.NewFraudService(fraudRepo))
+
+	r := createTestRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(authMiddleware.RequireAdmin)
+		r.Get("/api/admin/fraud-flags", fraudHandler.AdminListFlags)
+		r.Put("/api/admin/fraud-flags/{id}", fraudHandler.AdminReviewFlag)
+		r.Post("/api/admin/fraud-checks/run", fraudHandler.AdminRunChecks)
+	})
+
+	adminToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "admin")
+	userToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+
+	runChecks := func(t *testing.T) domain.FraudRunResult {
+		t.Helper()
+		rr := makeRequest(t, r, "POST", "/api/admin/fraud-checks/run", nil, adminToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var result domain.FraudRunResult
+		data, _ := json.Marshal(parseResponse(t, rr).Data)
+		if err := json.Unmarshal(data, &result); err != nil {
+			t.Fatalf("failed to decode run: %v", err)
+		}
+		return result
+	}
+
+	listFlags := func(t *testing.T, query string) ([]domain.FraudFlag, *domain.APIMeta) {
+		t.Helper()
+		rr := makeRequest(t, r, "GET", "/api/admin/fraud-flags"+query, nil, adminToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		response := parseResponse(t, rr)
+		var flags []domain.FraudFlag
+		data, _ := json.Marshal(response.Data)
+		if err := json.Unmarshal(data, &flags); err != nil {
+			t.Fatalf("failed to decode flags: %v", err)
+		}
+		return flags, response.Meta
+	}
+
+	t.Run("run raises flags with severity and evidence", func(t *testing.T) {
+		result := runChecks(t)
+		for _, check := range []domain.FraudCheck{domain.FraudCheckSharedIP, domain.FraudCheckBidRetraction, domain.FraudCheckBidVelocity, domain.FraudCheckSelfPurchase} {
+			if result.Flags[check] != 1 {
+				t.Errorf("expected one %s flag, got %+v", check, result.Flags)
+			}
+		}
+
+		flags, meta := listFlags(t, "")
+		if len(flags) != 4 || meta.TotalCount != 4 {
+			t.Fatalf("got %d flags, meta %+v", len(flags), meta)
+		}
+		for i := 1; i < len(flags); i++ {
+			if flags[i].Severity > flags[i-1].Severity {
+				t.Errorf("expected most severe first, got %d before %d", flags[i-1].Severity, flags[i].Severity)
+			}
+		}
+
+		byCheck := make(map[domain.FraudCheck]domain.FraudFlag)
+		for _, flag := range flags {
+			if flag.Severity < 0 || flag.Severity > 100 {
+				t.Errorf("severity out of range: %+v", flag)
+			}
+			byCheck[flag.Check] = flag
+		}
+
+		shared := byCheck[domain.FraudCheckSharedIP]
+		if shared.SubjectID == nil || *shared.SubjectID != seller || len(shared.RelatedUserIDs) != 2 {
+			t.Errorf("got %+v", shared)
+		}
+		if shared.Evidence["ip"] != "203.0.113.7" || shared.Evidence["seller_used_ip"] != true {
+			t.Errorf("got evidence %+v", shared.Evidence)
+		}
+		// 20 + 15 for the second bidder + 12 bids + 40 for the seller's own address
+		if shared.Severity != 87 {
+			t.Errorf("expected severity 87, got %d", shared.Severity)
+		}
+
+		purchase := byCheck[domain.FraudCheckSelfPurchase]
+		if purchase.AuctionID == nil || len(purchase.RelatedUserIDs) != 1 || purchase.RelatedUserIDs[0] != buyer {
+			t.Errorf("got %+v", purchase)
+		}
+		if velocity := byCheck[domain.FraudCheckBidVelocity]; velocity.Severity != 100 {
+			t.Errorf("expected severity capped at 100, got %d", velocity.Severity)
+		}
+		if retraction := byCheck[domain.FraudCheckBidRetraction]; retraction.Evidence["bids_cancelled"] != float64(4) {
+			t.Errorf("got evidence %+v", retraction.Evidence)
+		}
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		flags, _ := listFlags(t, "?check=self_purchase")
+		if len(flags) != 1 || flags[0].Check != domain.FraudCheckSelfPurchase {
+			t.Errorf("got %+v", flags)
+		}
+		flags, meta := listFlags(t, "?limit=3&page=2")
+		if len(flags) != 1 || meta.TotalPages != 2 {
+			t.Errorf("got %d flags, meta %+v", len(flags), meta)
+		}
+	})
+
+	t.Run("review", func(t *testing.T) {
+		flags, _ := listFlags(t, "?check=shared_ip")
+		flag := flags[0]
+
+		rr := makeRequest(t, r, "PUT", "/api/admin/fraud-flags/"+flag.ID.String(), map[string]string{
+			"status": "dismissed",
+			"note":   "Roommates on the same connection",
+		}, adminToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		reviewed, _ := listFlags(t, "?status=dismissed")
+		if len(reviewed) != 1 || reviewed[0].ReviewedBy == nil || reviewed[0].ReviewNote == nil {
+			t.Fatalf("got %+v", reviewed)
+		}
+
+		// Running again refreshes the open flags but leaves the dismissed one
+		// alone
+		result := runChecks(t)
+		if result.Flags[domain.FraudCheckSharedIP] != 0 || result.Flags[domain.FraudCheckBidRetraction] != 1 {
+			t.Errorf("got %+v", result.Flags)
+		}
+		if open, meta := listFlags(t, "?status=open"); len(open) != 3 || meta.TotalCount != 3 {
+			t.Errorf("expected no duplicate flags, got %d", len(open))
+		}
+	})
+
+	t.Run("invalid review", func(t *testing.T) {
+		rr := makeRequest(t, r, "PUT", "/api/admin/fraud-flags/"+uuid.NewString(), map[string]string{"status": "open"}, adminToken)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400 reopening, got %d", rr.Code)
+		}
+
+		rr = makeRequest(t, r, "PUT", "/api/admin/fraud-flags/"+uuid.NewString(), map[string]string{"status": "confirmed"}, adminToken)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rr.Code)
+		}
+	})
+
+	t.Run("admins only", func(t *testing.T) {
+		rr := makeRequest(t, r, "GET", "/api/admin/fraud-flags", nil, userToken)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rr.Code)
+		}
+	})
+}
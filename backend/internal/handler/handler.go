@@ -3,11 +3,14 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"net"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/auction-cards/backend/internal/domain"
 	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/pkg/breaker"
 	"github.com/auction-cards/backend/internal/pkg/validator"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -30,19 +33,19 @@ func respondJSONWithMeta(w http.ResponseWriter, status int, data interface{}, me
 }
 
 func respondError(w http.ResponseWriter, status int, code, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(domain.ErrorResponse(code, message, nil))
+	middleware.WriteError(w, status, code, message, nil)
 }
 
 func respondValidationError(w http.ResponseWriter, errors map[string]string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusBadRequest)
-	json.NewEncoder(w).Encode(domain.ErrorResponse("VALIDATION_ERROR", "Validation failed", errors))
+	middleware.WriteError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", errors)
 }
 
 func handleError(w http.ResponseWriter, err error) {
+	var rulesErr *domain.ListingRulesError
 	switch {
+	case errors.As(err, &rulesErr):
+		middleware.WriteError(w, http.StatusBadRequest, "LISTING_RULES",
+			"This auction doesn't meet the listing rules of the "+rulesErr.Category+" category", rulesErr.Violations)
 	case errors.Is(err, domain.ErrNotFound):
 		respondError(w, http.StatusNotFound, "NOT_FOUND", "Resource not found")
 	case errors.Is(err, domain.ErrUnauthorized):
@@ -55,6 +58,8 @@ func handleError(w http.ResponseWriter, err error) {
 		respondError(w, http.StatusUnauthorized, "INVALID_CREDENTIALS", "Invalid email or password")
 	case errors.Is(err, domain.ErrUserBanned):
 		respondError(w, http.StatusForbidden, "USER_BANNED", "Account has been suspended")
+	case errors.Is(err, domain.ErrEmailNotVerified):
+		respondError(w, http.StatusForbidden, "EMAIL_NOT_VERIFIED", "Email address has not been verified")
 	case errors.Is(err, domain.ErrEmailAlreadyExists):
 		respondError(w, http.StatusConflict, "EMAIL_EXISTS", "Email already registered")
 	case errors.Is(err, domain.ErrUsernameExists):
@@ -63,6 +68,8 @@ func handleError(w http.ResponseWriter, err error) {
 		respondError(w, http.StatusUnauthorized, "TOKEN_EXPIRED", "Token has expired")
 	case errors.Is(err, domain.ErrTokenInvalid):
 		respondError(w, http.StatusUnauthorized, "TOKEN_INVALID", "Invalid token")
+	case errors.Is(err, domain.ErrTooManyAttempts):
+		respondError(w, http.StatusTooManyRequests, "TOO_MANY_ATTEMPTS", "Too many attempts, please try again later")
 	case errors.Is(err, domain.ErrAuctionNotActive):
 		respondError(w, http.StatusBadRequest, "AUCTION_NOT_ACTIVE", "Auction is not active")
 	case errors.Is(err, domain.ErrAuctionEnded):
@@ -75,8 +82,166 @@ func handleError(w http.ResponseWriter, err error) {
 		respondError(w, http.StatusBadRequest, "AUCTION_NOT_DRAFT", "Can only modify draft auctions")
 	case errors.Is(err, domain.ErrConcurrentBid):
 		respondError(w, http.StatusConflict, "CONCURRENT_BID", "Another bid was placed, please retry")
+	case errors.Is(err, domain.ErrInvalidAmount):
+		respondError(w, http.StatusBadRequest, "INVALID_AMOUNT", "Amounts must be numbers in whole cents, sent as strings")
+	case errors.Is(err, domain.ErrInvalidCurrency):
+		respondError(w, http.StatusBadRequest, "INVALID_CURRENCY", "Unsupported currency")
+	case errors.Is(err, domain.ErrCurrencyLocked):
+		respondError(w, http.StatusBadRequest, "CURRENCY_LOCKED", "Currency cannot be changed once bidding has started")
+	case errors.Is(err, domain.ErrInvalidShipping):
+		respondError(w, http.StatusBadRequest, "INVALID_SHIPPING", "Invalid shipping option")
+	case errors.Is(err, domain.ErrInvalidItemImage):
+		respondError(w, http.StatusBadRequest, "INVALID_ITEM_IMAGE", "Item images must be images of this auction, each used once")
+	case errors.Is(err, domain.ErrOrderNotPending):
+		respondError(w, http.StatusBadRequest, "ORDER_NOT_PENDING", "Order is not awaiting payment")
+	case errors.Is(err, domain.ErrOfferNotPending):
+		respondError(w, http.StatusBadRequest, "OFFER_NOT_PENDING", "Offer is no longer available")
+	case errors.Is(err, domain.ErrDisputeExists):
+		respondError(w, http.StatusConflict, "DISPUTE_EXISTS", "An open dispute already exists for this order")
+	case errors.Is(err, domain.ErrEscrowNotHeld):
+		respondError(w, http.StatusBadRequest, "ESCROW_NOT_HELD", "Payment for this order is not held in escrow")
+	case errors.Is(err, domain.ErrPayoutMethodRequired):
+		respondError(w, http.StatusBadRequest, "PAYOUT_METHOD_REQUIRED", "Add a payout method before requesting a payout")
+	case errors.Is(err, domain.ErrInvalidPayoutMethod):
+		respondError(w, http.StatusBadRequest, "INVALID_PAYOUT_METHOD", "Invalid payout method")
+	case errors.Is(err, domain.ErrInvalidPayoutAmount):
+		respondError(w, http.StatusBadRequest, "INVALID_PAYOUT_AMOUNT", "Invalid payout amount")
+	case errors.Is(err, domain.ErrInsufficientBalance):
+		respondError(w, http.StatusBadRequest, "INSUFFICIENT_BALANCE", "Your available balance is too low for this payout")
+	case errors.Is(err, domain.ErrPayoutStatus):
+		respondError(w, http.StatusConflict, "PAYOUT_STATUS", "The payout cannot be changed in its current status")
+	case errors.Is(err, domain.ErrDisputeClosed):
+		respondError(w, http.StatusBadRequest, "DISPUTE_CLOSED", "Dispute is already resolved")
+	case errors.Is(err, domain.ErrInvalidRefund):
+		respondError(w, http.StatusBadRequest, "INVALID_REFUND", "Invalid refund amount")
+	case errors.Is(err, domain.ErrBidCancelWindow):
+		respondError(w, http.StatusBadRequest, "BID_CANCEL_WINDOW", "Bids cannot be cancelled in the final hour")
+	case errors.Is(err, domain.ErrBidCancelLimit):
+		respondError(w, http.StatusBadRequest, "BID_CANCEL_LIMIT", "Bid cancellation limit reached for this auction")
+	case errors.Is(err, domain.ErrAuctionCancelWindow):
+		respondError(w, http.StatusBadRequest, "AUCTION_CANCEL_WINDOW", "Auctions cannot be cancelled in the final 12 hours")
+	case errors.Is(err, domain.ErrReserveNotLowered):
+		respondError(w, http.StatusBadRequest, "RESERVE_NOT_LOWERED", "Reserve price can only be lowered")
+	case errors.Is(err, domain.ErrReserveTooLow):
+		respondError(w, http.StatusBadRequest, "RESERVE_TOO_LOW", "Reserve price cannot be below the starting price")
+	case errors.Is(err, domain.ErrBuyNowUnavailable):
+		respondError(w, http.StatusBadRequest, "BUY_NOW_UNAVAILABLE", "Buy now is not available for this auction")
+	case errors.Is(err, domain.ErrSnipeTooLow):
+		respondError(w, http.StatusBadRequest, "SNIPE_TOO_LOW", "Snipe amount is below the next minimum bid")
+	case errors.Is(err, domain.ErrSnipeTooLate):
+		respondError(w, http.StatusBadRequest, "SNIPE_TOO_LATE", "Auction ends too soon to schedule a snipe")
+	case errors.Is(err, domain.ErrSellerOnVacation):
+		respondError(w, http.StatusConflict, "SELLER_ON_VACATION", "Seller is on vacation and not accepting bids")
+	case errors.Is(err, domain.ErrCertificationLocked):
+		respondError(w, http.StatusConflict, "CERTIFICATION_LOCKED", "Certification cannot be changed once bidding has started")
+	case errors.Is(err, domain.ErrNoCertification):
+		respondError(w, http.StatusBadRequest, "NO_CERTIFICATION", "Auction has no certification")
+	case errors.Is(err, domain.ErrOffersDisabled):
+		respondError(w, http.StatusBadRequest, "OFFERS_DISABLED", "Seller is not accepting offers on this auction")
+	case errors.Is(err, domain.ErrOfferTooLow):
+		respondError(w, http.StatusBadRequest, "OFFER_TOO_LOW", "Offer is below the current price")
+	case errors.Is(err, domain.ErrOfferAboveBuyNow):
+		respondError(w, http.StatusBadRequest, "OFFER_ABOVE_BUY_NOW", "Offer must be below the buy now price")
+	case errors.Is(err, domain.ErrOfferExists):
+		respondError(w, http.StatusConflict, "OFFER_EXISTS", "You already have an open offer on this auction")
+	case errors.Is(err, domain.ErrOfferLimit):
+		respondError(w, http.StatusBadRequest, "OFFER_LIMIT", "Offer limit reached for this auction")
+	case errors.Is(err, domain.ErrCounterTooLow):
+		respondError(w, http.StatusBadRequest, "COUNTER_TOO_LOW", "Counter offer must be above the buyer's offer")
+	case errors.Is(err, domain.ErrWebhookURL):
+		respondError(w, http.StatusBadRequest, "INVALID_WEBHOOK_URL", "Webhook URL must be a public https address")
+	case errors.Is(err, domain.ErrWebhookLimit):
+		respondError(w, http.StatusBadRequest, "WEBHOOK_LIMIT", "Webhook limit reached")
+	case errors.Is(err, domain.ErrDeliveryNotFailed):
+		respondError(w, http.StatusBadRequest, "DELIVERY_NOT_FAILED", "Only failed deliveries can be retried")
+	case errors.Is(err, domain.ErrReauthRequired):
+		respondError(w, http.StatusForbidden, "REAUTH_REQUIRED", "Confirm your password or sign in again to continue")
+	case errors.Is(err, domain.ErrPasswordAlreadySet):
+		respondError(w, http.StatusConflict, "PASSWORD_ALREADY_SET", "Account already has a password")
+	case errors.Is(err, domain.ErrProviderLinked):
+		respondError(w, http.StatusConflict, "PROVIDER_ALREADY_LINKED", "A login from this provider is already linked")
+	case errors.Is(err, domain.ErrOAuthAccountInUse):
+		respondError(w, http.StatusConflict, "OAUTH_ACCOUNT_IN_USE", "This provider login belongs to another account")
+	case errors.Is(err, domain.ErrLastSignInMethod):
+		respondError(w, http.StatusBadRequest, "LAST_SIGN_IN_METHOD", "Cannot remove the only way to sign in")
+	case errors.Is(err, domain.ErrMergeSameUser):
+		respondError(w, http.StatusBadRequest, "MERGE_SAME_USER", "An account cannot be merged into itself")
+	case errors.Is(err, domain.ErrMergeInteracted):
+		respondError(w, http.StatusConflict, "MERGE_ACCOUNTS_INTERACTED", "Accounts that have traded or messaged with each other cannot be merged")
+	case errors.Is(err, domain.ErrKeyEnvelopesIncomplete):
+		respondError(w, http.StatusBadRequest, "KEY_ENVELOPES_INCOMPLETE", "Message key must be wrapped for every device of the recipient")
+	case errors.Is(err, domain.ErrCannotBlockSelf):
+		respondError(w, http.StatusBadRequest, "CANNOT_BLOCK_SELF", "You cannot block yourself")
+	case errors.Is(err, domain.ErrBlockedBySeller):
+		respondError(w, http.StatusForbidden, "BLOCKED_BY_SELLER", "The seller has blocked you from bidding on their auctions")
+	case errors.Is(err, domain.ErrBlockedByRecipient):
+		respondError(w, http.StatusForbidden, "BLOCKED_BY_RECIPIENT", "This user is not accepting messages from you")
+	case errors.Is(err, domain.ErrCannotFollowSelf):
+		respondError(w, http.StatusBadRequest, "CANNOT_FOLLOW_SELF", "You cannot follow yourself")
+	case errors.Is(err, domain.ErrSavedSearchLimit):
+		respondError(w, http.StatusBadRequest, "SAVED_SEARCH_LIMIT", "Saved search limit reached")
+	case errors.Is(err, domain.ErrInvalidListingRules):
+		respondError(w, http.StatusBadRequest, "INVALID_LISTING_RULES", "Listing rule minimums must not exceed their maximums and defaults must be within them")
+	case errors.Is(err, domain.ErrInvalidMaintenanceWindow):
+		respondError(w, http.StatusBadRequest, "INVALID_MAINTENANCE_WINDOW", "Maintenance window must end after it starts, last at most a day and not start in the past or overlap another")
+	case errors.Is(err, domain.ErrMaintenanceWindowStarted):
+		respondError(w, http.StatusConflict, "MAINTENANCE_WINDOW_STARTED", "Maintenance window has already started")
+	case errors.Is(err, domain.ErrPushSubscription):
+		respondError(w, http.StatusBadRequest, "INVALID_PUSH_SUBSCRIPTION", "Push subscription must have a public https endpoint and valid keys")
+	case errors.Is(err, domain.ErrPushUnavailable):
+		respondError(w, http.StatusServiceUnavailable, "PUSH_UNAVAILABLE", "Push notifications are not available right now")
+	case errors.Is(err, domain.ErrExportsUnavailable):
+		respondError(w, http.StatusServiceUnavailable, "EXPORTS_UNAVAILABLE", "Data exports are not available right now")
+	case errors.Is(err, domain.ErrFactsheetsUnavailable):
+		respondError(w, http.StatusServiceUnavailable, "FACTSHEETS_UNAVAILABLE", "Fact sheets are not available right now")
+	case errors.Is(err, domain.ErrBidApprovalRequired):
+		respondError(w, http.StatusForbidden, "BID_APPROVAL_REQUIRED", "The seller must approve you before you can bid")
+	case errors.Is(err, domain.ErrBidApprovalNotRequired):
+		respondError(w, http.StatusBadRequest, "BID_APPROVAL_NOT_REQUIRED", "This auction doesn't require bid approval")
+	case errors.Is(err, domain.ErrBidApprovalLocked):
+		respondError(w, http.StatusBadRequest, "BID_APPROVAL_LOCKED", "Bid approval cannot be required once bidding has started")
+	case errors.Is(err, domain.ErrBidApprovalAnswers):
+		respondError(w, http.StatusBadRequest, "BID_APPROVAL_ANSWERS", "Answer each of the seller's questions")
+	case errors.Is(err, domain.ErrBidderRatingTooLow):
+		respondError(w, http.StatusForbidden, "BIDDER_RATING_TOO_LOW", "Your buyer rating is below the seller's minimum for this auction")
+	case errors.Is(err, domain.ErrBidderEmailUnverified):
+		respondError(w, http.StatusForbidden, "BIDDER_EMAIL_UNVERIFIED", "The seller requires a verified email to bid on this auction")
+	case errors.Is(err, domain.ErrBidderAccountTooNew):
+		respondError(w, http.StatusForbidden, "BIDDER_ACCOUNT_TOO_NEW", "Your account is too new to bid on this auction")
+	case errors.Is(err, domain.ErrLiveEventLot):
+		respondError(w, http.StatusBadRequest, "LIVE_EVENT_LOT", "Lots of a live event are opened by its auctioneer")
+	case errors.Is(err, domain.ErrLiveEventLots):
+		respondError(w, http.StatusBadRequest, "LIVE_EVENT_LOTS", "Lots must be your own draft auctions that aren't in another live event")
+	case errors.Is(err, domain.ErrLiveEventEnded):
+		respondError(w, http.StatusBadRequest, "LIVE_EVENT_ENDED", "The live event has ended")
+	case errors.Is(err, domain.ErrLiveLotOpen):
+		respondError(w, http.StatusConflict, "LIVE_LOT_OPEN", "The open lot must be hammered first")
+	case errors.Is(err, domain.ErrLiveLotNotOpen):
+		respondError(w, http.StatusConflict, "LIVE_LOT_NOT_OPEN", "No lot is open")
+	case errors.Is(err, domain.ErrLiveLotNotPending):
+		respondError(w, http.StatusConflict, "LIVE_LOT_NOT_PENDING", "The lot has already been run")
+	case errors.Is(err, domain.ErrPriceDropFloor):
+		respondError(w, http.StatusBadRequest, "PRICE_DROP_FLOOR", "Floor price must be below the buy now price and at least the starting price")
+	case errors.Is(err, domain.ErrFeaturedLimit):
+		respondError(w, http.StatusBadRequest, "FEATURED_LIMIT", "Unfeature an auction before featuring another")
+	case errors.Is(err, domain.ErrAccountInUse):
+		respondError(w, http.StatusConflict, "ACCOUNT_IN_USE", "Finish your active listings, winning bids, orders, disputes and payouts before deleting your account")
+	case errors.Is(err, domain.ErrInvalidCursor):
+		respondError(w, http.StatusBadRequest, "INVALID_CURSOR", "Invalid pagination cursor")
+	case errors.Is(err, domain.ErrInvalidUsageWindow):
+		respondError(w, http.StatusBadRequest, "INVALID_WINDOW", "Usage window must be 1d, 7d or 30d")
+	case errors.Is(err, domain.ErrInvalidTimezone):
+		respondError(w, http.StatusBadRequest, "INVALID_TIMEZONE", "Unknown timezone")
+	case errors.Is(err, domain.ErrInvalidQuietHours):
+		respondError(w, http.StatusBadRequest, "INVALID_QUIET_HOURS", "Quiet hours must start and end at different HH:MM times")
+	case errors.Is(err, domain.ErrInvalidNotificationType):
+		respondError(w, http.StatusBadRequest, "INVALID_NOTIFICATION_TYPE", "Unknown notification type")
+	case errors.Is(err, domain.ErrInvalidUnsubscribeToken):
+		respondError(w, http.StatusBadRequest, "INVALID_UNSUBSCRIBE_TOKEN", "This unsubscribe link is invalid")
 	case errors.Is(err, domain.ErrValidation):
 		respondError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data")
+	case errors.Is(err, breaker.ErrOpen):
+		respondError(w, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "This is temporarily unavailable, please try again shortly")
 	default:
 		respondError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "An internal error occurred")
 	}
@@ -117,6 +282,18 @@ func getQueryParamString(r *http.Request, key string) *string {
 	return &val
 }
 
+func getQueryParamBool(r *http.Request, key string) *bool {
+	val := r.URL.Query().Get(key)
+	if val == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return nil
+	}
+	return &b
+}
+
 func getQueryParamUUID(r *http.Request, key string) *uuid.UUID {
 	val := r.URL.Query().Get(key)
 	if val == "" {
@@ -136,3 +313,23 @@ func getUserID(r *http.Request) uuid.UUID {
 func isAdmin(r *http.Request) bool {
 	return middleware.IsAdmin(r.Context())
 }
+
+// viewerKey identifies who is making the request for counting unique
+// views: their account when signed in, otherwise their address
+func viewerKey(r *http.Request) string {
+	if userID := getUserID(r); userID != uuid.Nil {
+		return "user:" + userID.String()
+	}
+	return "ip:" + clientAddress(r)
+}
+
+// clientAddress returns the address of the client making the request: the
+// first hop the proxy reports, without its port
+func clientAddress(r *http.Request) string {
+	ip, _, _ := strings.Cut(middleware.ClientIP(r), ",")
+	ip = strings.TrimSpace(ip)
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return ip
+}
@@ -3,9 +3,14 @@ package handler_test
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,6 +20,7 @@ import (
 	"github.com/auction-cards/backend/internal/middleware"
 	"github.com/auction-cards/backend/internal/pkg/email"
 	"github.com/auction-cards/backend/internal/pkg/jwt"
+	"github.com/auction-cards/backend/internal/pkg/oauth"
 	"github.com/auction-cards/backend/internal/pkg/password"
 	"github.com/auction-cards/backend/internal/service"
 	"github.com/go-chi/chi/v5"
@@ -69,7 +75,8 @@ func parseResponse(t *testing.T, rr *httptest.ResponseRecorder) *domain.APIRespo
 // Mock implementations for testing
 
 type mockUserRepo struct {
-	users map[uuid.UUID]*domain.User
+	users         map[uuid.UUID]*domain.User
+	tokenAttempts map[string]int
 }
 
 func newMockUserRepo() *mockUserRepo {
@@ -95,9 +102,19 @@ func (r *mockUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.User,
 	return nil, domain.ErrNotFound
 }
 
+func (r *mockUserRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.User, error) {
+	users := make([]domain.User, 0, len(ids))
+	for _, id := range ids {
+		if user, ok := r.users[id]; ok {
+			users = append(users, *user)
+		}
+	}
+	return users, nil
+}
+
 func (r *mockUserRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	for _, user := range r.users {
-		if user.Email == email {
+		if user.Email == email && !user.IsDeleted {
 			return user, nil
 		}
 	}
@@ -113,24 +130,46 @@ func (r *mockUserRepo) GetByUsername(ctx context.Context, username string) (*dom
 	return nil, domain.ErrNotFound
 }
 
-func (r *mockUserRepo) GetByVerificationToken(ctx context.Context, token string) (*domain.User, error) {
+func (r *mockUserRepo) GetByVerificationTokenSelector(ctx context.Context, selector string) (*domain.User, error) {
 	for _, user := range r.users {
-		if user.EmailVerificationToken != nil && *user.EmailVerificationToken == token {
+		if user.EmailVerificationToken != nil && strings.HasPrefix(*user.EmailVerificationToken, selector) {
 			return user, nil
 		}
 	}
 	return nil, domain.ErrNotFound
 }
 
-func (r *mockUserRepo) GetByPasswordResetToken(ctx context.Context, token string) (*domain.User, error) {
+func (r *mockUserRepo) GetByPasswordResetTokenSelector(ctx context.Context, selector string) (*domain.User, error) {
 	for _, user := range r.users {
-		if user.PasswordResetToken != nil && *user.PasswordResetToken == token {
+		if user.PasswordResetToken != nil && strings.HasPrefix(*user.PasswordResetToken, selector) {
 			return user, nil
 		}
 	}
 	return nil, domain.ErrNotFound
 }
 
+func (r *mockUserRepo) RecordTokenFailure(ctx context.Context, userID uuid.UUID, kind domain.TokenKind, maxAttempts int) (bool, error) {
+	user, ok := r.users[userID]
+	if !ok {
+		return false, domain.ErrNotFound
+	}
+	if r.tokenAttempts == nil {
+		r.tokenAttempts = make(map[string]int)
+	}
+	key := userID.String() + string(kind)
+	r.tokenAttempts[key]++
+	if r.tokenAttempts[key] < maxAttempts {
+		return false, nil
+	}
+	r.tokenAttempts[key] = 0
+	if kind == domain.TokenPasswordReset {
+		user.PasswordResetToken = nil
+	} else {
+		user.EmailVerificationToken = nil
+	}
+	return true, nil
+}
+
 func (r *mockUserRepo) Update(ctx context.Context, user *domain.User) error {
 	user.UpdatedAt = time.Now()
 	r.users[user.ID] = user
@@ -138,34 +177,71 @@ func (r *mockUserRepo) Update(ctx context.Context, user *domain.User) error {
 }
 
 func (r *mockUserRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	delete(r.users, id)
+	user, ok := r.users[id]
+	if !ok || user.IsDeleted {
+		return domain.ErrNotFound
+	}
+	now := time.Now()
+	user.IsDeleted = true
+	user.DeletedAt = &now
 	return nil
 }
 
 func (r *mockUserRepo) List(ctx context.Context, page, limit int) ([]domain.User, int, error) {
 	users := make([]domain.User, 0)
 	for _, user := range r.users {
-		users = append(users, *user)
+		if !user.IsDeleted {
+			users = append(users, *user)
+		}
 	}
 	return users, len(users), nil
 }
 
+func (r *mockUserRepo) RecordLogin(ctx context.Context, userID uuid.UUID, ip, country string) (*string, *time.Time, error) {
+	return nil, nil, nil
+}
+
 func (r *mockUserRepo) GetRatingSummary(ctx context.Context, userID uuid.UUID) (*domain.UserRatingSummary, error) {
 	return &domain.UserRatingSummary{UserID: userID}, nil
 }
 
-type mockOAuthRepo struct{}
+func (r *mockUserRepo) GetRatingSummaries(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]*domain.UserRatingSummary, error) {
+	summaries := make(map[uuid.UUID]*domain.UserRatingSummary, len(userIDs))
+	for _, id := range userIDs {
+		summaries[id] = &domain.UserRatingSummary{UserID: id}
+	}
+	return summaries, nil
+}
+
+type mockOAuthRepo struct {
+	accounts []domain.OAuthAccount
+}
 
 func (r *mockOAuthRepo) Create(ctx context.Context, account *domain.OAuthAccount) error {
+	if account.ID == uuid.Nil {
+		account.ID = uuid.New()
+	}
+	r.accounts = append(r.accounts, *account)
 	return nil
 }
 
 func (r *mockOAuthRepo) GetByProviderUserID(ctx context.Context, provider, providerUserID string) (*domain.OAuthAccount, error) {
+	for i := range r.accounts {
+		if r.accounts[i].Provider == provider && r.accounts[i].ProviderUserID == providerUserID {
+			return &r.accounts[i], nil
+		}
+	}
 	return nil, domain.ErrNotFound
 }
 
 func (r *mockOAuthRepo) GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.OAuthAccount, error) {
-	return nil, nil
+	var accounts []domain.OAuthAccount
+	for _, account := range r.accounts {
+		if account.UserID == userID {
+			accounts = append(accounts, account)
+		}
+	}
+	return accounts, nil
 }
 
 func (r *mockOAuthRepo) Update(ctx context.Context, account *domain.OAuthAccount) error {
@@ -173,7 +249,13 @@ func (r *mockOAuthRepo) Update(ctx context.Context, account *domain.OAuthAccount
 }
 
 func (r *mockOAuthRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	return nil
+	for i := range r.accounts {
+		if r.accounts[i].ID == id {
+			r.accounts = append(r.accounts[:i], r.accounts[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrNotFound
 }
 
 type mockRefreshTokenRepo struct {
@@ -190,6 +272,9 @@ func (r *mockRefreshTokenRepo) Create(ctx context.Context, token *domain.Refresh
 	if token.ID == uuid.Nil {
 		token.ID = uuid.New()
 	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
 	r.tokens[token.TokenHash] = token
 	return nil
 }
@@ -215,16 +300,169 @@ func (r *mockRefreshTokenRepo) DeleteByUserID(ctx context.Context, userID uuid.U
 	return nil
 }
 
+func (r *mockRefreshTokenRepo) DeleteByUserIDExcept(ctx context.Context, userID uuid.UUID, keepHash string) (int64, error) {
+	var deleted int64
+	for hash, token := range r.tokens {
+		if token.UserID == userID && hash != keepHash {
+			delete(r.tokens, hash)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
 func (r *mockRefreshTokenRepo) DeleteExpired(ctx context.Context) error {
 	return nil
 }
 
 type mockEmailSender struct {
 	sentEmails []string
+	bodies     []string
 }
 
 func (s *mockEmailSender) Send(data *email.EmailData) error {
 	s.sentEmails = append(s.sentEmails, data.To)
+	s.bodies = append(s.bodies, data.Body)
+	return nil
+}
+
+type mockUserDeviceRepo struct {
+	devices  map[uuid.UUID]*domain.Device
+	attempts map[uuid.UUID]int
+}
+
+func newMockUserDeviceRepo() *mockUserDeviceRepo {
+	return &mockUserDeviceRepo{
+		devices:  make(map[uuid.UUID]*domain.Device),
+		attempts: make(map[uuid.UUID]int),
+	}
+}
+
+func (r *mockUserDeviceRepo) Touch(ctx context.Context, device *domain.Device) error {
+	for _, stored := range r.devices {
+		if stored.UserID == device.UserID && stored.Fingerprint == device.Fingerprint {
+			stored.UserAgent = device.UserAgent
+			stored.LastIP = device.LastIP
+			stored.LastSeenAt = time.Now()
+			*device = *stored
+			return nil
+		}
+	}
+	device.ID = uuid.New()
+	device.CreatedAt = time.Now()
+	device.LastSeenAt = device.CreatedAt
+	stored := *device
+	r.devices[device.ID] = &stored
+	return nil
+}
+
+func (r *mockUserDeviceRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Device, error) {
+	if device, ok := r.devices[id]; ok {
+		copied := *device
+		return &copied, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockUserDeviceRepo) ListByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Device, error) {
+	var devices []domain.Device
+	for _, device := range r.devices {
+		if device.UserID == userID {
+			devices = append(devices, *device)
+		}
+	}
+	return devices, nil
+}
+
+func (r *mockUserDeviceRepo) Update(ctx context.Context, device *domain.Device) error {
+	stored, ok := r.devices[device.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	stored.Name = device.Name
+	stored.Trusted = device.Trusted
+	return nil
+}
+
+func (r *mockUserDeviceRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(r.devices, id)
+	return nil
+}
+
+func (r *mockUserDeviceRepo) SetChallenge(ctx context.Context, id uuid.UUID, digest string, expiresAt time.Time) error {
+	device, ok := r.devices[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	device.ChallengeDigest = &digest
+	device.ChallengeExpiresAt = &expiresAt
+	delete(r.attempts, id)
+	return nil
+}
+
+func (r *mockUserDeviceRepo) RecordChallengeFailure(ctx context.Context, id uuid.UUID, maxAttempts int) (bool, error) {
+	device, ok := r.devices[id]
+	if !ok {
+		return false, domain.ErrNotFound
+	}
+	r.attempts[id]++
+	if r.attempts[id] >= maxAttempts {
+		device.ChallengeDigest = nil
+		device.ChallengeExpiresAt = nil
+		delete(r.attempts, id)
+		return true, nil
+	}
+	return false, nil
+}
+
+func (r *mockUserDeviceRepo) ClearChallenge(ctx context.Context, id uuid.UUID) error {
+	if device, ok := r.devices[id]; ok {
+		device.ChallengeDigest = nil
+		device.ChallengeExpiresAt = nil
+	}
+	delete(r.attempts, id)
+	return nil
+}
+
+type mockLoginEventRepo struct {
+	events []*domain.LoginEvent
+}
+
+func (r *mockLoginEventRepo) Create(ctx context.Context, event *domain.LoginEvent) error {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	event.CreatedAt = time.Now()
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *mockLoginEventRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.LoginEvent, error) {
+	for _, event := range r.events {
+		if event.ID == id {
+			return event, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockLoginEventRepo) ListByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]domain.LoginEvent, error) {
+	events := make([]domain.LoginEvent, 0)
+	for i := len(r.events) - 1; i >= 0 && len(events) < limit; i-- {
+		if r.events[i].UserID == userID {
+			events = append(events, *r.events[i])
+		}
+	}
+	return events, nil
+}
+
+func (r *mockLoginEventRepo) MarkReported(ctx context.Context, id uuid.UUID) error {
+	event, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	event.ReportedAt = &now
 	return nil
 }
 
@@ -239,8 +477,14 @@ func TestAuthHandler_Register(t *testing.T) {
 		userRepo,
 		&mockOAuthRepo{},
 		newMockRefreshTokenRepo(),
+		nil,
+		nil,
+		nil,
 		jwtManager,
 		emailSender,
+		nil,
+		nil,
+		service.SessionPolicy{},
 		"http://localhost:5173",
 	)
 
@@ -248,6 +492,7 @@ func TestAuthHandler_Register(t *testing.T) {
 	cfg := &config.Config{
 		Server: config.ServerConfig{
 			AllowOrigins: []string{"http://localhost:5173"},
+			FrontendURL:  "http://localhost:5173",
 		},
 	}
 	authHandler := handler.NewAuthHandler(authService, cfg)
@@ -344,8 +589,14 @@ func TestAuthHandler_Login(t *testing.T) {
 		userRepo,
 		&mockOAuthRepo{},
 		refreshTokenRepo,
+		nil,
+		nil,
+		nil,
 		jwtManager,
 		&mockEmailSender{},
+		nil,
+		nil,
+		service.SessionPolicy{},
 		"http://localhost:5173",
 	)
 
@@ -353,6 +604,7 @@ func TestAuthHandler_Login(t *testing.T) {
 	cfg := &config.Config{
 		Server: config.ServerConfig{
 			AllowOrigins: []string{"http://localhost:5173"},
+			FrontendURL:  "http://localhost:5173",
 		},
 	}
 	authHandler := handler.NewAuthHandler(authService, cfg)
@@ -412,6 +664,437 @@ func TestAuthHandler_Login(t *testing.T) {
 	}
 }
 
+func TestAuthHandler_LoginSingleSession(t *testing.T) {
+	userRepo := newMockUserRepo()
+	refreshTokenRepo := newMockRefreshTokenRepo()
+
+	hashedPassword, err := password.Hash("Admin123!")
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+	users := map[string]*domain.User{
+		"admin": {Email: "admin@example.com", Username: "admin", Role: domain.RoleAdmin},
+		"opted": {Email: "opted@example.com", Username: "opted", Role: domain.RoleUser, SingleSession: true},
+		"user":  {Email: "user@example.com", Username: "user", Role: domain.RoleUser},
+	}
+	for _, user := range users {
+		user.PasswordHash = &hashedPassword
+		user.EmailVerified = true
+		userRepo.Create(context.Background(), user)
+	}
+
+	authService := service.NewAuthService(
+		userRepo,
+		&mockOAuthRepo{},
+		refreshTokenRepo,
+		nil,
+		nil,
+		nil,
+		newTestJWTManager(),
+		&mockEmailSender{},
+		nil,
+		nil,
+		service.SessionPolicy{SingleSessionRoles: []domain.UserRole{domain.RoleAdmin}},
+		"http://localhost:5173",
+	)
+
+	r := createTestRouter()
+	authHandler := handler.NewAuthHandler(authService, &config.Config{})
+	r.Post("/api/auth/login", authHandler.Login)
+
+	sessions := func(userID uuid.UUID) int {
+		n := 0
+		for _, token := range refreshTokenRepo.tokens {
+			if token.UserID == userID {
+				n++
+			}
+		}
+		return n
+	}
+
+	tests := []struct {
+		name         string
+		user         string
+		wantSessions int
+	}{
+		{name: "role limited to one session", user: "admin", wantSessions: 1},
+		{name: "user opted into one session", user: "opted", wantSessions: 1},
+		{name: "concurrent sessions allowed", user: "user", wantSessions: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := users[tt.user]
+			body := domain.LoginRequest{Email: user.Email, Password: "Admin123!"}
+
+			for i := 0; i < 2; i++ {
+				rr := makeRequest(t, r, "POST", "/api/auth/login", body, "")
+				if rr.Code != http.StatusOK {
+					t.Fatalf("login %d returned wrong status code: got %v want %v", i+1, rr.Code, http.StatusOK)
+				}
+			}
+
+			if got := sessions(user.ID); got != tt.wantSessions {
+				t.Errorf("got %d active sessions, want %d", got, tt.wantSessions)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_LoginTwoFactor(t *testing.T) {
+	userRepo := newMockUserRepo()
+	refreshTokenRepo := newMockRefreshTokenRepo()
+	deviceRepo := newMockUserDeviceRepo()
+	emailSender := &mockEmailSender{}
+
+	hashedPassword, err := password.Hash("Admin123!")
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+	testUser := &domain.User{
+		Email:            "test@example.com",
+		Username:         "testuser",
+		PasswordHash:     &hashedPassword,
+		Role:             domain.RoleUser,
+		EmailVerified:    true,
+		TwoFactorEnabled: true,
+	}
+	userRepo.Create(context.Background(), testUser)
+
+	authService := service.NewAuthService(
+		userRepo,
+		&mockOAuthRepo{},
+		refreshTokenRepo,
+		deviceRepo,
+		nil,
+		nil,
+		newTestJWTManager(),
+		emailSender,
+		nil,
+		nil,
+		service.SessionPolicy{},
+		"http://localhost:5173",
+	)
+
+	r := createTestRouter()
+	authHandler := handler.NewAuthHandler(authService, &config.Config{})
+	r.Post("/api/auth/login", authHandler.Login)
+	r.Post("/api/auth/login/verify", authHandler.VerifyLogin)
+
+	send := func(path string, body interface{}, deviceCookie *http.Cookie) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		if deviceCookie != nil {
+			req.AddCookie(deviceCookie)
+		}
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+	login := domain.LoginRequest{Email: testUser.Email, Password: "Admin123!"}
+
+	// An unknown device gets a challenge instead of a session
+	rr := send("/api/auth/login", login, nil)
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("login returned wrong status code: got %v want %v", rr.Code, http.StatusAccepted)
+	}
+	var deviceCookie *http.Cookie
+	for _, cookie := range rr.Result().Cookies() {
+		if cookie.Name == "device_id" {
+			deviceCookie = cookie
+		}
+	}
+	if deviceCookie == nil {
+		t.Fatal("login did not set a device cookie")
+	}
+	if len(refreshTokenRepo.tokens) != 0 {
+		t.Errorf("got %d sessions before the code was entered, want 0", len(refreshTokenRepo.tokens))
+	}
+
+	data := parseResponse(t, rr).Data.(map[string]interface{})
+	challengeID, err := uuid.Parse(data["challenge_id"].(string))
+	if err != nil {
+		t.Fatalf("Failed to parse challenge id: %v", err)
+	}
+
+	if len(emailSender.bodies) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(emailSender.bodies))
+	}
+	code := regexp.MustCompile(`\b\d{6}\b`).FindString(emailSender.bodies[0])
+	if code == "" {
+		t.Fatal("login code email has no code")
+	}
+	wrongCode := "000000"
+	if code == wrongCode {
+		wrongCode = "111111"
+	}
+
+	tests := []struct {
+		name       string
+		code       string
+		cookie     *http.Cookie
+		wantStatus int
+	}{
+		{name: "wrong code", code: wrongCode, cookie: deviceCookie, wantStatus: http.StatusUnauthorized},
+		{name: "code from another device", code: code, cookie: &http.Cookie{Name: "device_id", Value: "other"}, wantStatus: http.StatusUnauthorized},
+		{name: "valid code", code: code, cookie: deviceCookie, wantStatus: http.StatusOK},
+		{name: "code already used", code: code, cookie: deviceCookie, wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := domain.VerifyLoginRequest{ChallengeID: challengeID, Code: tt.code, TrustDevice: true}
+			rr := send("/api/auth/login/verify", body, tt.cookie)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+		})
+	}
+
+	// The device is now trusted, so the next login goes straight through
+	rr = send("/api/auth/login", login, deviceCookie)
+	if rr.Code != http.StatusOK {
+		t.Errorf("login on trusted device returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+}
+
+func TestAuthHandler_LoginHistory(t *testing.T) {
+	userRepo := newMockUserRepo()
+	refreshTokenRepo := newMockRefreshTokenRepo()
+	loginEventRepo := &mockLoginEventRepo{}
+	emailSender := &mockEmailSender{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	hashedPassword, err := password.Hash("Admin123!")
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+	testUser := &domain.User{
+		Email:         "test@example.com",
+		Username:      "testuser",
+		PasswordHash:  &hashedPassword,
+		Role:          domain.RoleUser,
+		EmailVerified: true,
+	}
+	userRepo.Create(context.Background(), testUser)
+
+	authService := service.NewAuthService(
+		userRepo,
+		&mockOAuthRepo{},
+		refreshTokenRepo,
+		newMockUserDeviceRepo(),
+		loginEventRepo,
+		nil,
+		jwtManager,
+		emailSender,
+		nil,
+		nil,
+		service.SessionPolicy{},
+		"http://localhost:5173",
+	)
+
+	r := createTestRouter()
+	authHandler := handler.NewAuthHandler(authService, &config.Config{})
+	r.Post("/api/auth/login", authHandler.Login)
+	r.With(authMiddleware.RequireAuth).Get("/api/users/me/login-history", authHandler.ListLoginHistory)
+	r.With(authMiddleware.RequireAuth).Post("/api/users/me/login-history/{id}/report", authHandler.ReportLogin)
+
+	login := domain.LoginRequest{Email: testUser.Email, Password: "Admin123!"}
+	rr := makeRequest(t, r, "POST", "/api/auth/login", login, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("login returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	token := parseResponse(t, rr).Data.(map[string]interface{})["access_token"].(string)
+
+	rr = makeRequest(t, r, "GET", "/api/users/me/login-history", nil, token)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("login history returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	events := parseResponse(t, rr).Data.([]interface{})
+	if len(events) != 1 {
+		t.Fatalf("got %d login events, want 1", len(events))
+	}
+	event := events[0].(map[string]interface{})
+	if event["method"] != string(domain.LoginMethodPassword) {
+		t.Errorf("login method = %v, want %s", event["method"], domain.LoginMethodPassword)
+	}
+	reportPath := "/api/users/me/login-history/" + event["id"].(string) + "/report"
+
+	otherToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+	rr = makeRequest(t, r, "POST", reportPath, nil, otherToken)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("reporting another user's login: got status %v want %v", rr.Code, http.StatusNotFound)
+	}
+
+	sentBefore := len(emailSender.sentEmails)
+	rr = makeRequest(t, r, "POST", reportPath, nil, token)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("report returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	if len(refreshTokenRepo.tokens) != 0 {
+		t.Errorf("got %d sessions after the report, want 0", len(refreshTokenRepo.tokens))
+	}
+	if len(emailSender.sentEmails) != sentBefore+1 || testUser.PasswordResetToken == nil {
+		t.Errorf("expected a password reset email after the report")
+	}
+	if loginEventRepo.events[0].ReportedAt == nil {
+		t.Errorf("expected the login to be marked as reported")
+	}
+
+	// The old password no longer works until it is reset
+	rr = makeRequest(t, r, "POST", "/api/auth/login", login, "")
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("login after report: got status %v want %v", rr.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAuthHandler_ResetPasswordLockout(t *testing.T) {
+	userRepo := newMockUserRepo()
+	jwtManager := newTestJWTManager()
+
+	resetToken := strings.Repeat("ab", 32)
+	hash := sha256.Sum256([]byte(resetToken))
+	storedToken := resetToken[:domain.TokenSelectorLength] + hex.EncodeToString(hash[:])
+	expires := time.Now().Add(time.Hour)
+	testUser := &domain.User{
+		ID:                   uuid.New(),
+		Email:                "test@example.com",
+		Username:             "testuser",
+		Role:                 domain.RoleUser,
+		EmailVerified:        true,
+		PasswordResetToken:   &storedToken,
+		PasswordResetExpires: &expires,
+	}
+	userRepo.Create(context.Background(), testUser)
+
+	authService := service.NewAuthService(
+		userRepo,
+		&mockOAuthRepo{},
+		newMockRefreshTokenRepo(),
+		nil,
+		nil,
+		nil,
+		jwtManager,
+		&mockEmailSender{},
+		nil,
+		nil,
+		service.SessionPolicy{},
+		"http://localhost:5173",
+	)
+
+	r := createTestRouter()
+	cfg := &config.Config{
+		Server: config.ServerConfig{
+			AllowOrigins: []string{"http://localhost:5173"},
+			FrontendURL:  "http://localhost:5173",
+		},
+	}
+	authHandler := handler.NewAuthHandler(authService, cfg)
+	r.Post("/api/auth/reset-password", authHandler.ResetPassword)
+
+	// Guesses sharing the real token's selector count against it
+	wrongToken := resetToken[:domain.TokenSelectorLength] + strings.Repeat("0", len(resetToken)-domain.TokenSelectorLength)
+	for i := 0; i < 5; i++ {
+		rr := makeRequest(t, r, "POST", "/api/auth/reset-password", domain.ResetPasswordRequest{
+			Token:    wrongToken,
+			Password: "NewPassword123",
+		}, "")
+		if rr.Code != http.StatusUnauthorized {
+			t.Fatalf("guess %d: got status %v want %v", i+1, rr.Code, http.StatusUnauthorized)
+		}
+	}
+
+	// The real token no longer works once the attempt limit is reached
+	rr := makeRequest(t, r, "POST", "/api/auth/reset-password", domain.ResetPasswordRequest{
+		Token:    resetToken,
+		Password: "NewPassword123",
+	}, "")
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("got status %v want %v after lockout", rr.Code, http.StatusUnauthorized)
+	}
+	if testUser.PasswordResetToken != nil {
+		t.Errorf("expected reset token to be cleared")
+	}
+
+	// A fresh token is accepted
+	resetToken = strings.Repeat("cd", 32)
+	hash = sha256.Sum256([]byte(resetToken))
+	storedToken = resetToken[:domain.TokenSelectorLength] + hex.EncodeToString(hash[:])
+	testUser.PasswordResetToken = &storedToken
+
+	rr = makeRequest(t, r, "POST", "/api/auth/reset-password", domain.ResetPasswordRequest{
+		Token:    resetToken,
+		Password: "NewPassword123",
+	}, "")
+	if rr.Code != http.StatusOK {
+		t.Errorf("got status %v want %v for a fresh token", rr.Code, http.StatusOK)
+	}
+}
+
+// Guessing a verification token until it is cleared must not mail the user
+// a new one; they ask for it again themselves
+func TestAuthHandler_VerifyEmailLockout(t *testing.T) {
+	userRepo := newMockUserRepo()
+	emailSender := &mockEmailSender{}
+
+	verificationToken := strings.Repeat("ab", 32)
+	hash := sha256.Sum256([]byte(verificationToken))
+	storedToken := verificationToken[:domain.TokenSelectorLength] + hex.EncodeToString(hash[:])
+	testUser := &domain.User{
+		ID:                     uuid.New(),
+		Email:                  "test@example.com",
+		Username:               "testuser",
+		Role:                   domain.RoleUser,
+		EmailVerificationToken: &storedToken,
+	}
+	userRepo.Create(context.Background(), testUser)
+
+	authService := service.NewAuthService(
+		userRepo,
+		&mockOAuthRepo{},
+		newMockRefreshTokenRepo(),
+		nil,
+		nil,
+		nil,
+		newTestJWTManager(),
+		emailSender,
+		nil,
+		nil,
+		service.SessionPolicy{},
+		"http://localhost:5173",
+	)
+
+	r := createTestRouter()
+	authHandler := handler.NewAuthHandler(authService, &config.Config{})
+	r.Post("/api/auth/verify-email", authHandler.VerifyEmail)
+	r.Post("/api/auth/resend-verification", authHandler.ResendVerification)
+
+	wrongToken := verificationToken[:domain.TokenSelectorLength] + strings.Repeat("0", len(verificationToken)-domain.TokenSelectorLength)
+	for i := 0; i < 5; i++ {
+		rr := makeRequest(t, r, "POST", "/api/auth/verify-email", domain.VerifyEmailRequest{Token: wrongToken}, "")
+		if rr.Code != http.StatusUnauthorized {
+			t.Fatalf("guess %d: got status %v want %v", i+1, rr.Code, http.StatusUnauthorized)
+		}
+	}
+
+	if testUser.EmailVerificationToken != nil {
+		t.Errorf("expected the verification token to be cleared")
+	}
+	if len(emailSender.sentEmails) != 0 {
+		t.Errorf("expected no email on lockout, got %d", len(emailSender.sentEmails))
+	}
+
+	rr := makeRequest(t, r, "POST", "/api/auth/resend-verification", domain.ResendVerificationRequest{Email: testUser.Email}, "")
+	if rr.Code != http.StatusOK {
+		t.Errorf("got status %v want %v for a resend", rr.Code, http.StatusOK)
+	}
+	if len(emailSender.sentEmails) != 1 || testUser.EmailVerificationToken == nil {
+		t.Errorf("expected a new verification link on request, got %d emails", len(emailSender.sentEmails))
+	}
+}
+
 func TestAuthMiddleware(t *testing.T) {
 	jwtManager := newTestJWTManager()
 	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
@@ -461,3 +1144,314 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthHandler_SignInMethods(t *testing.T) {
+	userRepo := newMockUserRepo()
+	oauthRepo := &mockOAuthRepo{}
+	refreshTokenRepo := newMockRefreshTokenRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	// Two OAuth-only users, one with two provider logins
+	oauthUser := &domain.User{Email: "oauth@example.com", Username: "oauthuser", Role: domain.RoleUser, EmailVerified: true}
+	userRepo.Create(context.Background(), oauthUser)
+	singleUser := &domain.User{Email: "single@example.com", Username: "singleuser", Role: domain.RoleUser, EmailVerified: true}
+	userRepo.Create(context.Background(), singleUser)
+	oauthRepo.Create(context.Background(), &domain.OAuthAccount{UserID: oauthUser.ID, Provider: "google", ProviderUserID: "g-1"})
+	oauthRepo.Create(context.Background(), &domain.OAuthAccount{UserID: oauthUser.ID, Provider: "github", ProviderUserID: "gh-1"})
+	oauthRepo.Create(context.Background(), &domain.OAuthAccount{UserID: singleUser.ID, Provider: "google", ProviderUserID: "g-2"})
+
+	// Sessions are identified by the hash of their refresh token
+	session := func(userID uuid.UUID, token string, startedAt time.Time) {
+		hash := sha256.Sum256([]byte(token))
+		refreshTokenRepo.Create(context.Background(), &domain.RefreshToken{
+			UserID:    userID,
+			TokenHash: hex.EncodeToString(hash[:]),
+			ExpiresAt: time.Now().Add(24 * time.Hour),
+			CreatedAt: startedAt,
+		})
+	}
+	session(oauthUser.ID, "fresh-session", time.Now())
+	session(oauthUser.ID, "stale-session", time.Now().Add(-time.Hour))
+	session(singleUser.ID, "single-session", time.Now())
+
+	authService := service.NewAuthService(
+		userRepo,
+		oauthRepo,
+		refreshTokenRepo,
+		nil,
+		nil,
+		nil,
+		jwtManager,
+		&mockEmailSender{},
+		nil,
+		nil,
+		service.SessionPolicy{},
+		"http://localhost:5173",
+	)
+
+	r := createTestRouter()
+	authHandler := handler.NewAuthHandler(authService, &config.Config{})
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Get("/api/users/me/sign-in-methods", authHandler.GetSignInMethods)
+		r.Post("/api/users/me/password", authHandler.SetPassword)
+		r.Delete("/api/users/me/oauth/{provider}", authHandler.UnlinkOAuth)
+	})
+
+	oauthToken, _ := jwtManager.GenerateAccessToken(oauthUser.ID, "user")
+	singleToken, _ := jwtManager.GenerateAccessToken(singleUser.ID, "user")
+
+	request := func(method, path string, body interface{}, token, refreshToken string) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		if refreshToken != "" {
+			req.AddCookie(&http.Cookie{Name: "refresh_token", Value: refreshToken})
+		}
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := request("GET", "/api/users/me/sign-in-methods", nil, oauthToken, "")
+	methods := parseResponse(t, rr).Data.(map[string]interface{})
+	if methods["has_password"] != false || len(methods["linked"].([]interface{})) != 2 {
+		t.Errorf("unexpected sign-in methods %v", methods)
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         interface{}
+		token        string
+		refreshToken string
+		wantStatus   int
+		wantCode     string
+	}{
+		{name: "set password without a session", method: "POST", path: "/api/users/me/password", body: domain.SetPasswordRequest{Password: "NewPass123!"}, token: oauthToken, wantStatus: http.StatusForbidden, wantCode: "REAUTH_REQUIRED"},
+		{name: "set password from an old session", method: "POST", path: "/api/users/me/password", body: domain.SetPasswordRequest{Password: "NewPass123!"}, token: oauthToken, refreshToken: "stale-session", wantStatus: http.StatusForbidden, wantCode: "REAUTH_REQUIRED"},
+		{name: "set password from another user's session", method: "POST", path: "/api/users/me/password", body: domain.SetPasswordRequest{Password: "NewPass123!"}, token: oauthToken, refreshToken: "single-session", wantStatus: http.StatusForbidden, wantCode: "REAUTH_REQUIRED"},
+		{name: "set short password", method: "POST", path: "/api/users/me/password", body: domain.SetPasswordRequest{Password: "short"}, token: oauthToken, refreshToken: "fresh-session", wantStatus: http.StatusBadRequest},
+		{name: "set password", method: "POST", path: "/api/users/me/password", body: domain.SetPasswordRequest{Password: "NewPass123!"}, token: oauthToken, refreshToken: "fresh-session", wantStatus: http.StatusOK},
+		{name: "set password twice", method: "POST", path: "/api/users/me/password", body: domain.SetPasswordRequest{Password: "Other123!"}, token: oauthToken, refreshToken: "fresh-session", wantStatus: http.StatusConflict, wantCode: "PASSWORD_ALREADY_SET"},
+		{name: "unlink with a recent session only", method: "DELETE", path: "/api/users/me/oauth/github", body: domain.ReauthRequest{}, token: oauthToken, refreshToken: "fresh-session", wantStatus: http.StatusForbidden, wantCode: "REAUTH_REQUIRED"},
+		{name: "unlink with wrong password", method: "DELETE", path: "/api/users/me/oauth/github", body: domain.ReauthRequest{CurrentPassword: "Wrong123!"}, token: oauthToken, wantStatus: http.StatusForbidden, wantCode: "REAUTH_REQUIRED"},
+		{name: "unlink unlinked provider", method: "DELETE", path: "/api/users/me/oauth/apple", body: domain.ReauthRequest{CurrentPassword: "NewPass123!"}, token: oauthToken, wantStatus: http.StatusNotFound},
+		{name: "unlink", method: "DELETE", path: "/api/users/me/oauth/github", body: domain.ReauthRequest{CurrentPassword: "NewPass123!"}, token: oauthToken, wantStatus: http.StatusOK},
+		{name: "unlink only sign-in method", method: "DELETE", path: "/api/users/me/oauth/google", body: domain.ReauthRequest{}, token: singleToken, refreshToken: "single-session", wantStatus: http.StatusBadRequest, wantCode: "LAST_SIGN_IN_METHOD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := request(tt.method, tt.path, tt.body, tt.token, tt.refreshToken)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+
+			response := parseResponse(t, rr)
+			if tt.wantCode != "" && (response.Error == nil || response.Error.Code != tt.wantCode) {
+				t.Errorf("expected error code %s, got %+v", tt.wantCode, response.Error)
+			}
+		})
+	}
+
+	rr = request("GET", "/api/users/me/sign-in-methods", nil, oauthToken, "")
+	methods = parseResponse(t, rr).Data.(map[string]interface{})
+	linked := methods["linked"].([]interface{})
+	if methods["has_password"] != true || len(linked) != 1 || linked[0].(map[string]interface{})["provider"] != "google" {
+		t.Errorf("unexpected sign-in methods after changes %v", methods)
+	}
+}
+
+func TestAuthHandler_OAuthAccountMatching(t *testing.T) {
+	userRepo := newMockUserRepo()
+	oauthRepo := &mockOAuthRepo{}
+	refreshTokenRepo := newMockRefreshTokenRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	hashedPassword, err := password.Hash("Admin123!")
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+
+	// Someone registered this address with a password but never verified it
+	squatted := &domain.User{Email: "owner@example.com", Username: "squatter", PasswordHash: &hashedPassword, Role: domain.RoleUser}
+	userRepo.Create(context.Background(), squatted)
+	refreshTokenRepo.Create(context.Background(), &domain.RefreshToken{UserID: squatted.ID, TokenHash: "squatter-session", ExpiresAt: time.Now().Add(time.Hour)})
+
+	verified := &domain.User{Email: "verified@example.com", Username: "verified", PasswordHash: &hashedPassword, Role: domain.RoleUser, EmailVerified: true}
+	userRepo.Create(context.Background(), verified)
+
+	authService := service.NewAuthService(
+		userRepo,
+		oauthRepo,
+		refreshTokenRepo,
+		nil,
+		nil,
+		nil,
+		jwtManager,
+		&mockEmailSender{},
+		nil,
+		nil,
+		service.SessionPolicy{},
+		"http://localhost:5173",
+	)
+
+	r := createTestRouter()
+	authHandler := handler.NewAuthHandler(authService, &config.Config{})
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Get("/api/users/me/sign-in-methods", authHandler.GetSignInMethods)
+		r.Post("/api/users/me/oauth/confirm-link", authHandler.ConfirmOAuthLink)
+	})
+
+	// An unverified provider email never reaches the existing account
+	_, err = authService.GetOrCreateOAuthUser(context.Background(), "github", &oauth.Profile{ID: "gh-1", Email: verified.Email, Name: "intruder"})
+	if !errors.Is(err, domain.ErrEmailAlreadyExists) {
+		t.Errorf("unverified email match: got %v, want ErrEmailAlreadyExists", err)
+	}
+	if accounts, _ := oauthRepo.GetByUserID(context.Background(), verified.ID); len(accounts) != 0 {
+		t.Errorf("unverified email match linked %d logins", len(accounts))
+	}
+
+	// Without a login parked in this browser there is nothing to confirm
+	verifiedToken, _ := jwtManager.GenerateAccessToken(verified.ID, "user")
+	rr := makeRequest(t, r, "POST", "/api/users/me/oauth/confirm-link", domain.ReauthRequest{CurrentPassword: "Admin123!"}, verifiedToken)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("confirm without a pending link: got status %v want %v", rr.Code, http.StatusNotFound)
+	}
+
+	// A verified provider email claims the unverified account from whoever
+	// registered it
+	user, err := authService.GetOrCreateOAuthUser(context.Background(), "google", &oauth.Profile{ID: "g-1", Email: squatted.Email, EmailVerified: true, Name: "owner"})
+	if err != nil {
+		t.Fatalf("verified email match: %v", err)
+	}
+	if user.ID != squatted.ID || user.PasswordHash != nil || !user.EmailVerified {
+		t.Errorf("claimed account: password cleared %v, email verified %v", user.PasswordHash == nil, user.EmailVerified)
+	}
+	if _, err := refreshTokenRepo.GetByTokenHash(context.Background(), "squatter-session"); !errors.Is(err, domain.ErrNotFound) {
+		t.Error("claimed account kept the previous session")
+	}
+
+	// Signing up through a provider records where the link came from
+	newcomer, err := authService.GetOrCreateOAuthUser(context.Background(), "google", &oauth.Profile{ID: "g-2", Email: "new@example.com", EmailVerified: true, Name: "newcomer"})
+	if err != nil {
+		t.Fatalf("sign up: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		userID    uuid.UUID
+		wantVia   domain.OAuthLinkMethod
+		wantEmail string
+	}{
+		{name: "claimed by verified email", userID: squatted.ID, wantVia: domain.OAuthLinkVerifiedEmail, wantEmail: squatted.Email},
+		{name: "signed up", userID: newcomer.ID, wantVia: domain.OAuthLinkSignup, wantEmail: "new@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, _ := jwtManager.GenerateAccessToken(tt.userID, "user")
+			rr := makeRequest(t, r, "GET", "/api/users/me/sign-in-methods", nil, token)
+			linked := parseResponse(t, rr).Data.(map[string]interface{})["linked"].([]interface{})
+			if len(linked) != 1 {
+				t.Fatalf("got %d linked logins, want 1", len(linked))
+			}
+			account := linked[0].(map[string]interface{})
+			if account["linked_via"] != string(tt.wantVia) || account["provider_email"] != tt.wantEmail || account["provider_email_verified"] != true {
+				t.Errorf("unexpected link provenance %v", account)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_OAuthUsernames(t *testing.T) {
+	userRepo := newMockUserRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	taken := &domain.User{Email: "jose@example.com", Username: "josemuller", Role: domain.RoleUser}
+	userRepo.Create(context.Background(), taken)
+
+	authService := service.NewAuthService(
+		userRepo,
+		&mockOAuthRepo{},
+		newMockRefreshTokenRepo(),
+		nil,
+		nil,
+		nil,
+		jwtManager,
+		&mockEmailSender{},
+		nil,
+		nil,
+		service.SessionPolicy{},
+		"http://localhost:5173",
+	)
+	userService := service.NewUserService(userRepo, nil, nil, nil)
+
+	r := createTestRouter()
+	userHandler := handler.NewUserHandler(userService, nil)
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Put("/api/users/me/username", userHandler.ChooseUsername)
+	})
+
+	tests := []struct {
+		name    string
+		profile oauth.Profile
+		want    string
+	}{
+		{name: "display name is slugified", profile: oauth.Profile{ID: "g-1", Email: "anna@example.com", Name: "Anna-Lena Schmidt"}, want: `^annalenaschmidt$`},
+		{name: "taken name gets a numeric suffix", profile: oauth.Profile{ID: "g-2", Email: "jm@example.com", Name: "José Müller"}, want: `^josemuller[0-9]{2}$`},
+		{name: "falls back to the email", profile: oauth.Profile{ID: "g-3", Email: "jane.doe@example.com", Name: "李"}, want: `^janedoe$`},
+		{name: "falls back to a placeholder", profile: oauth.Profile{ID: "g-4", Email: "x@example.com", Name: "  "}, want: `^user$`},
+	}
+
+	var signedUp *domain.User
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := authService.GetOrCreateOAuthUser(context.Background(), "google", &tt.profile)
+			if err != nil {
+				t.Fatalf("sign up: %v", err)
+			}
+			if !regexp.MustCompile(tt.want).MatchString(user.Username) {
+				t.Errorf("got username %q, want %s", user.Username, tt.want)
+			}
+			if !user.UsernameSetupRequired {
+				t.Error("generated username not flagged for setup")
+			}
+			signedUp = user
+		})
+	}
+
+	token, _ := jwtManager.GenerateAccessToken(signedUp.ID, "user")
+
+	choices := []struct {
+		name       string
+		username   string
+		wantStatus int
+	}{
+		{name: "invalid characters", username: "jane doe", wantStatus: http.StatusBadRequest},
+		{name: "too short", username: "jd", wantStatus: http.StatusBadRequest},
+		{name: "taken", username: taken.Username, wantStatus: http.StatusConflict},
+		{name: "chosen", username: "JaneD", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range choices {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := makeRequest(t, r, "PUT", "/api/users/me/username", domain.ChooseUsernameRequest{Username: tt.username}, token)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+		})
+	}
+
+	if signedUp.Username != "JaneD" || signedUp.UsernameSetupRequired {
+		t.Errorf("got username %q with setup required %v", signedUp.Username, signedUp.UsernameSetupRequired)
+	}
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/auction-cards/backend/internal/pkg/breaker"
+)
+
+// Pinger checks that a dependency is reachable
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type HealthHandler struct {
+	db       Pinger
+	breakers []*breaker.Breaker
+}
+
+func NewHealthHandler(db Pinger, breakers ...*breaker.Breaker) *HealthHandler {
+	return &HealthHandler{db: db, breakers: breakers}
+}
+
+// Readiness is the body of GET /readyz
+type Readiness struct {
+	// ready, degraded while a breaker isn't closed, or unavailable
+	Status   string          `json:"status"`
+	Database string          `json:"database"`
+	Breakers []breaker.Stats `json:"breakers"`
+}
+
+// Ready handles GET /readyz. Only an unreachable database takes the server
+// out of rotation; an open breaker leaves it ready but degraded, since
+// everything behind a breaker falls back without it.
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	readiness := Readiness{Status: "ready", Database: "up", Breakers: []breaker.Stats{}}
+	status := http.StatusOK
+
+	for _, b := range h.breakers {
+		stats := b.Stats()
+		if stats.State != breaker.StateClosed {
+			readiness.Status = "degraded"
+		}
+		readiness.Breakers = append(readiness.Breakers, stats)
+	}
+
+	if h.db != nil {
+		ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+		defer cancel()
+		if err := h.db.Ping(ctx); err != nil {
+			readiness.Status = "unavailable"
+			readiness.Database = "down"
+			status = http.StatusServiceUnavailable
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(readiness)
+}
+
+// breakerStates numbers breaker states for the state gauge
+var breakerStates = map[breaker.State]int{
+	breaker.StateClosed:   0,
+	breaker.StateHalfOpen: 1,
+	breaker.StateOpen:     2,
+}
+
+// Metrics handles GET /metrics in the Prometheus text format
+func (h *HealthHandler) Metrics(w http.ResponseWriter, r *http.Request) {
+	stats := make([]breaker.Stats, len(h.breakers))
+	for i, b := range h.breakers {
+		stats[i] = b.Stats()
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	metric := func(name, kind, help string, value func(breaker.Stats) int64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+		for _, s := range stats {
+			fmt.Fprintf(w, "%s{name=%q} %d\n", name, s.Name, value(s))
+		}
+	}
+	metric("circuit_breaker_state", "gauge", "Breaker state: 0 closed, 1 half-open, 2 open.",
+		func(s breaker.Stats) int64 { return int64(breakerStates[s.State]) })
+	metric("circuit_breaker_consecutive_failures", "gauge", "Failed calls since the last success.",
+		func(s breaker.Stats) int64 { return int64(s.ConsecutiveFailures) })
+	metric("circuit_breaker_trips_total", "counter", "Times the breaker has opened.",
+		func(s breaker.Stats) int64 { return s.Trips })
+	metric("circuit_breaker_rejected_total", "counter", "Calls failed without trying the dependency.",
+		func(s breaker.Stats) int64 { return s.Rejected })
+}
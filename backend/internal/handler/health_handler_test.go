@@ -0,0 +1,125 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/pkg/breaker"
+)
+
+type mockPinger struct {
+	err error
+}
+
+func (p *mockPinger) Ping(ctx context.Context) error {
+	return p.err
+}
+
+func TestHealthHandler_Readiness(t *testing.T) {
+	db := &mockPinger{}
+	redisBreaker := breaker.New("redis", 3, 50*time.Millisecond)
+	emailBreaker := breaker.New("email", 3, time.Minute)
+
+	r := createTestRouter()
+	h := handler.NewHealthHandler(db, redisBreaker, emailBreaker)
+	r.Get("/readyz", h.Ready)
+	r.Get("/metrics", h.Metrics)
+
+	ready := func(t *testing.T, wantCode int) handler.Readiness {
+		t.Helper()
+		rr := makeRequest(t, r, "GET", "/readyz", nil, "")
+		if rr.Code != wantCode {
+			t.Fatalf("expected %d, got %d: %s", wantCode, rr.Code, rr.Body.String())
+		}
+		var readiness handler.Readiness
+		if err := json.NewDecoder(rr.Body).Decode(&readiness); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		return readiness
+	}
+
+	outage := errors.New("connection refused")
+	fail := func() error { return outage }
+
+	t.Run("all dependencies up", func(t *testing.T) {
+		readiness := ready(t, http.StatusOK)
+		if readiness.Status != "ready" || len(readiness.Breakers) != 2 {
+			t.Errorf("got %+v", readiness)
+		}
+	})
+
+	t.Run("breaker opens after consecutive failures", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			if err := redisBreaker.Do(fail); err != outage {
+				t.Fatalf("call %d: expected the dependency's error, got %v", i, err)
+			}
+		}
+
+		called := false
+		err := redisBreaker.Do(func() error { called = true; return nil })
+		if !errors.Is(err, breaker.ErrOpen) || called {
+			t.Fatalf("expected the call to fail fast, got %v", err)
+		}
+
+		readiness := ready(t, http.StatusOK)
+		if readiness.Status != "degraded" {
+			t.Errorf("expected degraded, got %s", readiness.Status)
+		}
+		if stats := readiness.Breakers[0]; stats.State != breaker.StateOpen || stats.Trips != 1 || stats.Rejected != 1 || stats.OpenedAt == nil {
+			t.Errorf("got %+v", stats)
+		}
+
+		rr := makeRequest(t, r, "GET", "/metrics", nil, "")
+		for _, line := range []string{
+			`circuit_breaker_state{name="redis"} 2`,
+			`circuit_breaker_state{name="email"} 0`,
+			`circuit_breaker_trips_total{name="redis"} 1`,
+			`circuit_breaker_rejected_total{name="redis"} 1`,
+		} {
+			if !strings.Contains(rr.Body.String(), line+"\n") {
+				t.Errorf("metrics missing %q:\n%s", line, rr.Body.String())
+			}
+		}
+	})
+
+	t.Run("failed trial call opens it again", func(t *testing.T) {
+		time.Sleep(60 * time.Millisecond)
+
+		if err := redisBreaker.Do(fail); err != outage {
+			t.Fatalf("expected a trial call, got %v", err)
+		}
+		if err := redisBreaker.Do(fail); !errors.Is(err, breaker.ErrOpen) {
+			t.Fatalf("expected the breaker to be open again, got %v", err)
+		}
+		if stats := redisBreaker.Stats(); stats.Trips != 2 {
+			t.Errorf("expected 2 trips, got %d", stats.Trips)
+		}
+	})
+
+	t.Run("successful trial call closes it", func(t *testing.T) {
+		time.Sleep(60 * time.Millisecond)
+
+		if err := redisBreaker.Do(func() error { return nil }); err != nil {
+			t.Fatalf("expected a trial call, got %v", err)
+		}
+		if readiness := ready(t, http.StatusOK); readiness.Status != "ready" {
+			t.Errorf("expected ready, got %s", readiness.Status)
+		}
+	})
+
+	t.Run("database down", func(t *testing.T) {
+		db.err = outage
+		defer func() { db.err = nil }()
+
+		readiness := ready(t, http.StatusServiceUnavailable)
+		if readiness.Status != "unavailable" || readiness.Database != "down" {
+			t.Errorf("got %+v", readiness)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type ImportHandler struct {
+	importService *service.ImportService
+}
+
+func NewImportHandler(importService *service.ImportService) *ImportHandler {
+	return &ImportHandler{importService: importService}
+}
+
+// Create accepts an import file and queues it. The listings are created in
+// the background; the job's status and report show how it went.
+func (h *ImportHandler) Create(w http.ResponseWriter, r *http.Request) {
+	// Parse multipart form (files beyond 32MB are buffered on disk)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_FORM", "Invalid form data")
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "NO_FILE", "No import file provided")
+		return
+	}
+	defer file.Close()
+
+	job, err := h.importService.Create(r.Context(), getUserID(r), header.Filename, r.FormValue("format"), file)
+	if errors.Is(err, domain.ErrImportFile) {
+		respondError(w, http.StatusBadRequest, "INVALID_IMPORT_FILE", err.Error())
+		return
+	}
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+func (h *ImportHandler) List(w http.ResponseWriter, r *http.Request) {
+	jobs, err := h.importService.List(r.Context(), getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, jobs)
+}
+
+func (h *ImportHandler) Get(w http.ResponseWriter, r *http.Request) {
+	jobID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid import ID")
+		return
+	}
+
+	job, err := h.importService.Get(r.Context(), getUserID(r), jobID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, job)
+}
+
+// Report downloads the per-row report, as CSV unless ?format=json. While the
+// job runs it covers the rows processed so far.
+func (h *ImportHandler) Report(w http.ResponseWriter, r *http.Request) {
+	jobID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid import ID")
+		return
+	}
+
+	job, err := h.importService.Get(r.Context(), getUserID(r), jobID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "json" {
+		respondJSON(w, http.StatusOK, job.Report)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="import-`+job.ID.String()+`-report.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"row", "title", "status", "auction_id", "errors", "warnings"})
+	for _, result := range job.Report {
+		auctionID := ""
+		if result.AuctionID != nil {
+			auctionID = result.AuctionID.String()
+		}
+		writer.Write([]string{
+			strconv.Itoa(result.Row),
+			result.Title,
+			string(result.Status),
+			auctionID,
+			strings.Join(result.Errors, "; "),
+			strings.Join(result.Warnings, "; "),
+		})
+	}
+	writer.Flush()
+}
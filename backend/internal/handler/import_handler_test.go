@@ -0,0 +1,278 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Mock import job repository
+type mockImportJobRepo struct {
+	jobs  map[uuid.UUID]*domain.ImportJob
+	order []uuid.UUID
+}
+
+func newMockImportJobRepo() *mockImportJobRepo {
+	return &mockImportJobRepo{
+		jobs: make(map[uuid.UUID]*domain.ImportJob),
+	}
+}
+
+func (r *mockImportJobRepo) Create(ctx context.Context, job *domain.ImportJob) error {
+	if job.ID == uuid.Nil {
+		job.ID = uuid.New()
+	}
+	job.TotalRows = len(job.Rows)
+	job.CreatedAt = time.Now()
+	job.UpdatedAt = job.CreatedAt
+	stored := *job
+	r.jobs[job.ID] = &stored
+	r.order = append(r.order, job.ID)
+	return nil
+}
+
+func (r *mockImportJobRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.ImportJob, error) {
+	if job, ok := r.jobs[id]; ok {
+		copied := *job
+		copied.Rows = nil
+		return &copied, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockImportJobRepo) ListBySeller(ctx context.Context, sellerID uuid.UUID, limit int) ([]domain.ImportJob, error) {
+	jobs := make([]domain.ImportJob, 0)
+	for i := len(r.order) - 1; i >= 0 && len(jobs) < limit; i-- {
+		if job := r.jobs[r.order[i]]; job.SellerID == sellerID {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs, nil
+}
+
+func (r *mockImportJobRepo) ClaimNext(ctx context.Context, now, leaseUntil time.Time) (*domain.ImportJob, error) {
+	for _, id := range r.order {
+		job := r.jobs[id]
+		if job.Status == domain.ImportJobPending ||
+			(job.Status == domain.ImportJobProcessing && job.LeaseUntil != nil && !job.LeaseUntil.After(now)) {
+			job.Status = domain.ImportJobProcessing
+			job.LeaseUntil = &leaseUntil
+			if job.StartedAt == nil {
+				job.StartedAt = &now
+			}
+			copied := *job
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockImportJobRepo) SaveProgress(ctx context.Context, job *domain.ImportJob) error {
+	stored := r.jobs[job.ID]
+	stored.Status = job.Status
+	stored.ProcessedRows = job.ProcessedRows
+	stored.CreatedCount = job.CreatedCount
+	stored.FailedCount = job.FailedCount
+	stored.Report = append([]domain.ImportRowResult(nil), job.Report...)
+	stored.LeaseUntil = job.LeaseUntil
+	stored.CompletedAt = job.CompletedAt
+	return nil
+}
+
+func uploadImportFile(t *testing.T, r *chi.Mux, filename, content, token string) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatalf("Failed to build form: %v", err)
+	}
+	part.Write([]byte(content))
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/api/auctions/import", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestImportHandler(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	categoryRepo := newMockCategoryRepo()
+	importRepo := newMockImportJobRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		categoryRepo,
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil, // no S3 for tests
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	importService := service.NewImportService(importRepo, categoryRepo, auctionService, time.Second, false, 3, 1<<20)
+	importHandler := handler.NewImportHandler(importService)
+
+	r := createTestRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Post("/api/auctions/import", importHandler.Create)
+		r.Get("/api/auctions/imports", importHandler.List)
+		r.Get("/api/auctions/imports/{id}", importHandler.Get)
+		r.Get("/api/auctions/imports/{id}/report", importHandler.Report)
+	})
+
+	sellerID := uuid.New()
+	token, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+	otherToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+
+	// Images on the loopback interface are refused like any private address
+	imageServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("\x89PNG\r\n\x1a\n"))
+	}))
+	defer imageServer.Close()
+
+	file := "\xef\xbb\xbfTitle,Category,Condition,Starting Price,Buy Now Price,End Time,Image URLs\n" +
+		"Charizard Holo,electronics,Like new,25.00,,2030-01-02T15:04:05Z," + imageServer.URL + "/a.png\n" +
+		"Pikachu,no-such-category,good,abc,,2030-01-02T15:04:05Z,\n" +
+		"Blastoise,,,10,,tomorrow,\n"
+
+	var jobID string
+	t.Run("queues a CSV import", func(t *testing.T) {
+		rr := uploadImportFile(t, r, "cards.csv", file, token)
+		if rr.Code != http.StatusAccepted {
+			t.Fatalf("got status %v, want %v: %s", rr.Code, http.StatusAccepted, rr.Body.String())
+		}
+
+		job := parseResponse(t, rr).Data.(map[string]interface{})
+		if job["status"] != "pending" || job["format"] != "csv" || job["total_rows"] != float64(3) {
+			t.Errorf("unexpected job %v", job)
+		}
+		jobID = job["id"].(string)
+	})
+
+	t.Run("rejects unreadable files", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			filename string
+			content  string
+		}{
+			{"unknown column", "cards.csv", "title,colour\nCharizard,red\n"},
+			{"too many rows", "cards.csv", "title\na\nb\nc\nd\n"},
+			{"no rows", "cards.csv", "title,starting_price\n"},
+			{"not an array", "cards.json", `{"title": "Charizard"}`},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rr := uploadImportFile(t, r, tt.filename, tt.content, token)
+				if rr.Code != http.StatusBadRequest {
+					t.Fatalf("got status %v, want %v", rr.Code, http.StatusBadRequest)
+				}
+				if response := parseResponse(t, rr); response.Error.Code != "INVALID_IMPORT_FILE" {
+					t.Errorf("got error %v, want INVALID_IMPORT_FILE", response.Error.Code)
+				}
+			})
+		}
+	})
+
+	t.Run("worker creates valid rows and reports the rest", func(t *testing.T) {
+		importService.ProcessPending(context.Background())
+
+		rr := makeRequest(t, r, "GET", "/api/auctions/imports/"+jobID, nil, token)
+		job := parseResponse(t, rr).Data.(map[string]interface{})
+		if job["status"] != "completed" || job["created_count"] != float64(1) || job["failed_count"] != float64(2) {
+			t.Errorf("unexpected job %v", job)
+		}
+
+		if len(auctionRepo.auctions) != 1 {
+			t.Fatalf("got %d auctions, want 1", len(auctionRepo.auctions))
+		}
+		for _, auction := range auctionRepo.auctions {
+			if auction.SellerID != sellerID || auction.Status != domain.AuctionStatusDraft || auction.CategoryID == nil {
+				t.Errorf("unexpected auction %+v", auction)
+			}
+			if auction.Condition == nil || *auction.Condition != domain.ItemCondition("like_new") {
+				t.Errorf("got condition %v, want like_new", auction.Condition)
+			}
+		}
+	})
+
+	t.Run("report downloads as CSV", func(t *testing.T) {
+		rr := makeRequest(t, r, "GET", "/api/auctions/imports/"+jobID+"/report", nil, token)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("got status %v, want %v", rr.Code, http.StatusOK)
+		}
+		if disposition := rr.Header().Get("Content-Disposition"); !strings.Contains(disposition, "attachment") {
+			t.Errorf("got Content-Disposition %q", disposition)
+		}
+
+		records, err := csv.NewReader(rr.Body).ReadAll()
+		if err != nil {
+			t.Fatalf("Failed to parse report: %v", err)
+		}
+		if len(records) != 4 {
+			t.Fatalf("got %d report lines, want 4", len(records))
+		}
+
+		created, unknownCategory, badTime := records[1], records[2], records[3]
+		if created[2] != "created" || created[3] == "" || !strings.Contains(created[5], "private address") {
+			t.Errorf("unexpected report line %v", created)
+		}
+		if unknownCategory[2] != "failed" || !strings.Contains(unknownCategory[4], "no-such-category") ||
+			!strings.Contains(unknownCategory[4], "starting_price") {
+			t.Errorf("unexpected report line %v", unknownCategory)
+		}
+		if badTime[2] != "failed" || !strings.Contains(badTime[4], "end_time") {
+			t.Errorf("unexpected report line %v", badTime)
+		}
+	})
+
+	t.Run("JSON imports", func(t *testing.T) {
+		rr := uploadImportFile(t, r, "cards.json",
+			`[{"title": "Mewtwo", "starting_price": 12.5, "end_time": "2030-01-02T15:04:05Z", "image_urls": []}]`, token)
+		if rr.Code != http.StatusAccepted {
+			t.Fatalf("got status %v, want %v: %s", rr.Code, http.StatusAccepted, rr.Body.String())
+		}
+
+		importService.ProcessPending(context.Background())
+		if len(auctionRepo.auctions) != 2 {
+			t.Errorf("got %d auctions, want 2", len(auctionRepo.auctions))
+		}
+	})
+
+	t.Run("imports are private to the seller", func(t *testing.T) {
+		rr := makeRequest(t, r, "GET", "/api/auctions/imports/"+jobID+"/report", nil, otherToken)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusNotFound)
+		}
+
+		rr = makeRequest(t, r, "GET", "/api/auctions/imports", nil, token)
+		if jobs := parseResponse(t, rr).Data.([]interface{}); len(jobs) != 2 {
+			t.Errorf("got %d imports, want 2", len(jobs))
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"golang.org/x/text/language"
+)
+
+// maxPreferredLanguages bounds how many languages of a request are tried
+const maxPreferredLanguages = 10
+
+// preferredLanguages returns the languages to show categories in, most
+// preferred first: the lang query parameter if set, otherwise the
+// Accept-Language header. A regional tag is followed by its base language,
+// and the list stops at DefaultLanguage, since categories are written in it.
+// The response is marked as varying by Accept-Language.
+func preferredLanguages(w http.ResponseWriter, r *http.Request) []string {
+	w.Header().Add("Vary", "Accept-Language")
+
+	var tags []language.Tag
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		if tag, err := language.Parse(lang); err == nil {
+			tags = []language.Tag{tag}
+		}
+	} else {
+		// Tags come sorted by weight; a malformed header is ignored
+		tags, _, _ = language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	}
+
+	languages := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	add := func(tag string) {
+		if !seen[tag] && len(languages) < maxPreferredLanguages {
+			seen[tag] = true
+			languages = append(languages, tag)
+		}
+	}
+	for _, tag := range tags {
+		if tag == language.Und {
+			continue
+		}
+		base, _ := tag.Base()
+		if base.String() == domain.DefaultLanguage {
+			break
+		}
+		add(tag.String())
+		add(base.String())
+	}
+	return languages
+}
+
+// translationLanguage returns the canonical form of a language tag given for
+// a translation, reporting false for tags that aren't valid or are in
+// DefaultLanguage
+func translationLanguage(lang string) (string, bool) {
+	tag, err := language.Parse(lang)
+	if err != nil || tag == language.Und {
+		return "", false
+	}
+	if base, _ := tag.Base(); base.String() == domain.DefaultLanguage {
+		return "", false
+	}
+	return tag.String(), true
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type LedgerHandler struct {
+	ledgerService *service.LedgerService
+}
+
+func NewLedgerHandler(ledgerService *service.LedgerService) *LedgerHandler {
+	return &LedgerHandler{ledgerService: ledgerService}
+}
+
+// ConfirmDelivery lets the buyer confirm the item arrived, releasing the
+// held payment to the seller
+func (h *LedgerHandler) ConfirmDelivery(w http.ResponseWriter, r *http.Request) {
+	orderID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid order ID")
+		return
+	}
+
+	hold, err := h.ledgerService.ConfirmDelivery(r.Context(), orderID, getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, hold)
+}
+
+func (h *LedgerHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
+	balance, err := h.ledgerService.GetBalance(r.Context(), getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, balance)
+}
+
+// AdminListPendingReleases lists payments held in escrow, soonest release
+// first
+func (h *LedgerHandler) AdminListPendingReleases(w http.ResponseWriter, r *http.Request) {
+	page := max(getQueryParamInt(r, "page", 1), 1)
+	limit := getQueryParamInt(r, "limit", 20)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	holds, totalCount, err := h.ledgerService.ListPendingReleases(r.Context(), page, limit)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSONWithMeta(w, http.StatusOK, holds, &domain.APIMeta{
+		Page:       page,
+		Limit:      limit,
+		TotalCount: totalCount,
+		TotalPages: (totalCount + limit - 1) / limit,
+	})
+}
+
+// AdminRelease pays out a held payment before its release time
+func (h *LedgerHandler) AdminRelease(w http.ResponseWriter, r *http.Request) {
+	orderID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid order ID")
+		return
+	}
+
+	var req domain.ReleaseEscrowRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	hold, err := h.ledgerService.ReleaseByAdmin(r.Context(), getUserID(r), orderID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, hold)
+}
@@ -0,0 +1,260 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock escrow repository
+type mockEscrowRepo struct {
+	holds map[uuid.UUID]*domain.EscrowHold
+}
+
+func newMockEscrowRepo() *mockEscrowRepo {
+	return &mockEscrowRepo{holds: make(map[uuid.UUID]*domain.EscrowHold)}
+}
+
+func (r *mockEscrowRepo) Create(ctx context.Context, hold *domain.EscrowHold) error {
+	hold.CreatedAt = time.Now()
+	stored := *hold
+	r.holds[hold.OrderID] = &stored
+	return nil
+}
+
+func (r *mockEscrowRepo) GetByOrder(ctx context.Context, orderID uuid.UUID) (*domain.EscrowHold, error) {
+	hold, ok := r.holds[orderID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *hold
+	return &copied, nil
+}
+
+func (r *mockEscrowRepo) ListHeld(ctx context.Context, page, limit int) ([]domain.EscrowHold, int, error) {
+	holds := make([]domain.EscrowHold, 0)
+	for _, hold := range r.holds {
+		if hold.Status == domain.EscrowHeld {
+			holds = append(holds, *hold)
+		}
+	}
+	return holds, len(holds), nil
+}
+
+func (r *mockEscrowRepo) GetDue(ctx context.Context, now time.Time, limit int) ([]domain.EscrowHold, error) {
+	holds := make([]domain.EscrowHold, 0)
+	for _, hold := range r.holds {
+		if hold.Status == domain.EscrowHeld && !hold.ReleaseAt.After(now) && !hold.Disputed {
+			holds = append(holds, *hold)
+		}
+	}
+	return holds, nil
+}
+
+func (r *mockEscrowRepo) ConfirmDelivery(ctx context.Context, orderID uuid.UUID) error {
+	hold, ok := r.holds[orderID]
+	if !ok || hold.Status != domain.EscrowHeld || hold.DeliveryConfirmedAt != nil {
+		return domain.ErrEscrowNotHeld
+	}
+	now := time.Now()
+	hold.DeliveryConfirmedAt = &now
+	return nil
+}
+
+func (r *mockEscrowRepo) Release(ctx context.Context, orderID uuid.UUID) error {
+	hold, ok := r.holds[orderID]
+	if !ok || hold.Status != domain.EscrowHeld {
+		return domain.ErrEscrowNotHeld
+	}
+	now := time.Now()
+	hold.Status = domain.EscrowReleased
+	hold.ReleasedAt = &now
+	return nil
+}
+
+func (r *mockEscrowRepo) AddRefund(ctx context.Context, orderID uuid.UUID, amount decimal.Decimal) error {
+	hold, ok := r.holds[orderID]
+	if !ok || hold.Status != domain.EscrowHeld || hold.Refunded.Add(amount).GreaterThan(hold.Amount) {
+		return domain.ErrEscrowNotHeld
+	}
+	hold.Refunded = hold.Refunded.Add(amount)
+	if hold.Refunded.Equal(hold.Amount) {
+		hold.Status = domain.EscrowRefunded
+	}
+	return nil
+}
+
+// Mock ledger repository
+type mockLedgerRepo struct {
+	entries  []domain.LedgerEntry
+	balances map[uuid.UUID]decimal.Decimal
+	escrow   *mockEscrowRepo
+}
+
+func newMockLedgerRepo(escrow *mockEscrowRepo) *mockLedgerRepo {
+	return &mockLedgerRepo{balances: make(map[uuid.UUID]decimal.Decimal), escrow: escrow}
+}
+
+func (r *mockLedgerRepo) CreateEntries(ctx context.Context, entries []domain.LedgerEntry) error {
+	r.entries = append(r.entries, entries...)
+	return nil
+}
+
+func (r *mockLedgerRepo) AdjustBalance(ctx context.Context, userID uuid.UUID, delta decimal.Decimal) error {
+	r.balances[userID] = r.balances[userID].Add(delta)
+	return nil
+}
+
+func (r *mockLedgerRepo) LockBalance(ctx context.Context, userID uuid.UUID) (decimal.Decimal, error) {
+	return r.balances[userID], nil
+}
+
+func (r *mockLedgerRepo) GetBalance(ctx context.Context, userID uuid.UUID) (*domain.Balance, error) {
+	balance := &domain.Balance{UserID: userID, Available: r.balances[userID]}
+	for _, hold := range r.escrow.holds {
+		if hold.SellerID == userID && hold.Status == domain.EscrowHeld {
+			balance.Pending = balance.Pending.Add(hold.Remaining())
+		}
+	}
+	return balance, nil
+}
+
+func TestLedgerHandler_ConfirmDelivery(t *testing.T) {
+	escrowRepo := newMockEscrowRepo()
+	ledgerRepo := newMockLedgerRepo(escrowRepo)
+	auditRepo := &mockAuditLogRepo{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	ledgerService := service.NewLedgerService(escrowRepo, ledgerRepo, nil, auditRepo, &mockTxManager{}, nil, 14*24*time.Hour)
+
+	buyerID := uuid.New()
+	sellerID := uuid.New()
+	adminID := uuid.New()
+
+	newPaidOrder := func(amount float64) *domain.Order {
+		order := &domain.Order{
+			ID:       uuid.New(),
+			BuyerID:  buyerID,
+			SellerID: sellerID,
+			Amount:   decimal.NewFromFloat(amount),
+			Status:   domain.OrderStatusPaid,
+		}
+		if _, err := ledgerService.HoldPayment(context.Background(), order); err != nil {
+			t.Fatalf("failed to hold payment: %v", err)
+		}
+		return order
+	}
+	delivered := newPaidOrder(200)
+	disputed := newPaidOrder(50)
+	escrowRepo.holds[disputed.ID].Disputed = true
+	early := newPaidOrder(75)
+
+	r := createTestRouter()
+	ledgerHandler := handler.NewLedgerHandler(ledgerService)
+
+	r.With(authMiddleware.RequireAuth).Post("/api/orders/{id}/confirm-delivery", ledgerHandler.ConfirmDelivery)
+	r.With(authMiddleware.RequireAuth).Get("/api/users/me/balance", ledgerHandler.GetBalance)
+	r.With(authMiddleware.RequireAuth).Post("/api/admin/escrow/{id}/release", ledgerHandler.AdminRelease)
+
+	buyerToken, _ := jwtManager.GenerateAccessToken(buyerID, "user")
+	sellerToken, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+	adminToken, _ := jwtManager.GenerateAccessToken(adminID, "admin")
+
+	// Nothing is available to the seller while payments are held
+	rr := makeRequest(t, r, "GET", "/api/users/me/balance", nil, sellerToken)
+	balance := parseResponse(t, rr).Data.(map[string]interface{})
+	if balance["available"] != "0" || balance["pending"] != "325" {
+		t.Errorf("expected 0 available and 325 pending, got %v", balance)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       interface{}
+		token      string
+		wantStatus int
+	}{
+		{
+			name:       "seller cannot confirm delivery",
+			method:     "POST",
+			path:       "/api/orders/" + delivered.ID.String() + "/confirm-delivery",
+			token:      sellerToken,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "buyer confirms delivery",
+			method:     "POST",
+			path:       "/api/orders/" + delivered.ID.String() + "/confirm-delivery",
+			token:      buyerToken,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "already released",
+			method:     "POST",
+			path:       "/api/orders/" + delivered.ID.String() + "/confirm-delivery",
+			token:      buyerToken,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "open dispute blocks release",
+			method:     "POST",
+			path:       "/api/orders/" + disputed.ID.String() + "/confirm-delivery",
+			token:      buyerToken,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "admin release needs a reason",
+			method:     "POST",
+			path:       "/api/admin/escrow/" + early.ID.String() + "/release",
+			body:       domain.ReleaseEscrowRequest{},
+			token:      adminToken,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "admin releases early",
+			method:     "POST",
+			path:       "/api/admin/escrow/" + early.ID.String() + "/release",
+			body:       domain.ReleaseEscrowRequest{Reason: "Tracking shows delivered"},
+			token:      adminToken,
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := makeRequest(t, r, tt.method, tt.path, tt.body, tt.token)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+		})
+	}
+
+	rr = makeRequest(t, r, "GET", "/api/users/me/balance", nil, sellerToken)
+	balance = parseResponse(t, rr).Data.(map[string]interface{})
+	if balance["available"] != "275" || balance["pending"] != "50" {
+		t.Errorf("expected 275 available and 50 pending, got %v", balance)
+	}
+
+	sum := decimal.Zero
+	for _, entry := range ledgerRepo.entries {
+		sum = sum.Add(entry.Amount)
+	}
+	if !sum.IsZero() || len(ledgerRepo.entries) != 10 {
+		t.Errorf("expected 10 balanced ledger entries, got %d summing to %s", len(ledgerRepo.entries), sum)
+	}
+
+	if len(auditRepo.entries) != 1 || auditRepo.entries[0].Action != domain.AuditActionEscrowRelease {
+		t.Errorf("expected one escrow release audit entry, got %v", auditRepo.entries)
+	}
+}
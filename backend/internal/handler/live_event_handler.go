@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	ws "github.com/auction-cards/backend/internal/websocket"
+	"github.com/google/uuid"
+)
+
+type LiveEventHandler struct {
+	liveEventService *service.LiveEventService
+	hub              *ws.Hub
+}
+
+func NewLiveEventHandler(liveEventService *service.LiveEventService, hub *ws.Hub) *LiveEventHandler {
+	return &LiveEventHandler{liveEventService: liveEventService, hub: hub}
+}
+
+// Create schedules a live event running the user's draft auctions as lots
+func (h *LiveEventHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateLiveEventRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	event, err := h.liveEventService.Create(r.Context(), getUserID(r), &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, event)
+}
+
+// List returns the live events that are scheduled or running
+func (h *LiveEventHandler) List(w http.ResponseWriter, r *http.Request) {
+	events, err := h.liveEventService.List(r.Context())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, events)
+}
+
+// Get returns a live event with its lots
+func (h *LiveEventHandler) Get(w http.ResponseWriter, r *http.Request) {
+	eventID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid live event ID")
+		return
+	}
+
+	event, err := h.liveEventService.Get(r.Context(), eventID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, event)
+}
+
+// HandleWS joins the event's room. The auctioneer's connection also takes
+// their commands to run the event.
+func (h *LiveEventHandler) HandleWS(w http.ResponseWriter, r *http.Request) {
+	eventID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		http.Error(w, "Invalid live event ID", http.StatusBadRequest)
+		return
+	}
+
+	event, err := h.liveEventService.Get(r.Context(), eventID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	// Get user ID if authenticated (optional)
+	userID := middleware.GetUserID(r.Context())
+	control := userID != uuid.Nil && userID == event.AuctioneerID
+	if userID == uuid.Nil {
+		userID = uuid.New() // Generate anonymous ID for non-authenticated users
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+
+	client := ws.NewLiveEventClient(h.hub, conn, eventID, userID, control)
+
+	h.hub.Register(eventID, client)
+
+	go client.WritePump()
+	go client.ReadPump()
+}
@@ -0,0 +1,314 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock live event repository keeping events and lots in memory, marking
+// lots on the mock auction repository
+type mockLiveEventRepo struct {
+	auctionRepo *mockAuctionRepo
+	events      map[uuid.UUID]*domain.LiveEvent
+	lots        map[uuid.UUID]*domain.LiveLot
+}
+
+func (r *mockLiveEventRepo) Create(ctx context.Context, event *domain.LiveEvent) error {
+	event.ID = uuid.New()
+	event.CreatedAt = time.Now()
+	event.UpdatedAt = event.CreatedAt
+	stored := *event
+	r.events[event.ID] = &stored
+	return nil
+}
+
+func (r *mockLiveEventRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.LiveEvent, error) {
+	event, ok := r.events[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	stored := *event
+	return &stored, nil
+}
+
+func (r *mockLiveEventRepo) ListUpcoming(ctx context.Context) ([]domain.LiveEvent, error) {
+	events := make([]domain.LiveEvent, 0)
+	for _, event := range r.events {
+		if event.Status != domain.LiveEventEnded {
+			events = append(events, *event)
+		}
+	}
+	return events, nil
+}
+
+func (r *mockLiveEventRepo) Update(ctx context.Context, event *domain.LiveEvent) error {
+	if _, ok := r.events[event.ID]; !ok {
+		return domain.ErrNotFound
+	}
+	event.UpdatedAt = time.Now()
+	stored := *event
+	r.events[event.ID] = &stored
+	return nil
+}
+
+func (r *mockLiveEventRepo) AddLots(ctx context.Context, eventID uuid.UUID, auctionIDs []uuid.UUID) error {
+	for i, auctionID := range auctionIDs {
+		r.lots[auctionID] = &domain.LiveLot{EventID: eventID, AuctionID: auctionID, Position: i + 1, State: domain.LiveLotPending}
+		id := eventID
+		r.auctionRepo.auctions[auctionID].LiveEventID = &id
+	}
+	return nil
+}
+
+func (r *mockLiveEventRepo) ListLots(ctx context.Context, eventID uuid.UUID) ([]domain.LiveLot, error) {
+	lots := make([]domain.LiveLot, 0)
+	for _, lot := range r.lots {
+		if lot.EventID == eventID {
+			stored := *lot
+			auction := r.auctionRepo.auctions[lot.AuctionID]
+			stored.Title = auction.Title
+			stored.CurrentPrice = auction.CurrentPrice
+			stored.BidCount = auction.BidCount
+			lots = append(lots, stored)
+		}
+	}
+	sort.Slice(lots, func(i, j int) bool { return lots[i].Position < lots[j].Position })
+	return lots, nil
+}
+
+func (r *mockLiveEventRepo) UpdateLot(ctx context.Context, lot *domain.LiveLot) error {
+	if _, ok := r.lots[lot.AuctionID]; !ok {
+		return domain.ErrNotFound
+	}
+	stored := *lot
+	r.lots[lot.AuctionID] = &stored
+	return nil
+}
+
+func TestLiveEventHandler(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	bidRepo := newMockBidRepo()
+	liveEventRepo := &mockLiveEventRepo{
+		auctionRepo: auctionRepo,
+		events:      make(map[uuid.UUID]*domain.LiveEvent),
+		lots:        make(map[uuid.UUID]*domain.LiveLot),
+	}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		bidRepo,
+		&mockTxManager{},
+		nil, // no S3 for tests
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	bidService := service.NewBidService(bidRepo, auctionRepo, nil, nil, nil, nil, &mockTxManager{}, nil, nil, nil, nil, nil, nil)
+	liveEventService := service.NewLiveEventService(liveEventRepo, auctionRepo, bidRepo, nil, nil, &mockTxManager{}, nil, nil)
+	liveEventHandler := handler.NewLiveEventHandler(liveEventService, nil)
+	auctionHandler := handler.NewAuctionHandler(auctionService)
+	bidHandler := handler.NewBidHandler(bidService)
+
+	r := createTestRouter()
+	r.With(authMiddleware.RequireAuth).Post("/api/live-events", liveEventHandler.Create)
+	r.Get("/api/live-events", liveEventHandler.List)
+	r.Get("/api/live-events/{id}", liveEventHandler.Get)
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/publish", auctionHandler.Publish)
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/bids", bidHandler.PlaceBid)
+
+	auctioneerID := uuid.New()
+	bidderID := uuid.New()
+	auctioneerToken, _ := jwtManager.GenerateAccessToken(auctioneerID, "user")
+	bidderToken, _ := jwtManager.GenerateAccessToken(bidderID, "user")
+
+	newLot := func(title string, sellerID uuid.UUID, reserve *decimal.Decimal) *domain.Auction {
+		auction := &domain.Auction{
+			SellerID:      sellerID,
+			Title:         title,
+			StartingPrice: decimal.NewFromFloat(10),
+			CurrentPrice:  decimal.NewFromFloat(10),
+			ReservePrice:  reserve,
+			BidIncrement:  decimal.NewFromFloat(1),
+			StartTime:     time.Now().Add(24 * time.Hour),
+			EndTime:       time.Now().Add(48 * time.Hour),
+			Status:        domain.AuctionStatusDraft,
+		}
+		auctionRepo.Create(context.Background(), auction)
+		return auction
+	}
+	reserve := decimal.NewFromFloat(500)
+	first := newLot("1st Edition Charizard", auctioneerID, nil)
+	second := newLot("Shadowless Blastoise", auctioneerID, &reserve)
+	third := newLot("Base Set Venusaur", auctioneerID, nil)
+	stranger := newLot("Someone else's card", uuid.New(), nil)
+
+	startsAt := time.Now().Add(time.Hour)
+	var event *domain.LiveEvent
+
+	bid := func(auction *domain.Auction, amount string) int {
+		t.Helper()
+		path := "/api/auctions/" + auction.ID.String() + "/bids"
+		return makeRequest(t, r, "POST", path, domain.PlaceBidRequest{Amount: amount}, bidderToken).Code
+	}
+
+	t.Run("rejected events", func(t *testing.T) {
+		cases := []struct {
+			name   string
+			req    domain.CreateLiveEventRequest
+			status int
+		}{
+			{"no lots", domain.CreateLiveEventRequest{Title: "Vintage Night", StartsAt: startsAt}, http.StatusBadRequest},
+			{"in the past", domain.CreateLiveEventRequest{Title: "Vintage Night", StartsAt: time.Now().Add(-time.Hour), AuctionIDs: []uuid.UUID{first.ID}}, http.StatusBadRequest},
+			{"someone else's auction", domain.CreateLiveEventRequest{Title: "Vintage Night", StartsAt: startsAt, AuctionIDs: []uuid.UUID{first.ID, stranger.ID}}, http.StatusBadRequest},
+			{"repeated lot", domain.CreateLiveEventRequest{Title: "Vintage Night", StartsAt: startsAt, AuctionIDs: []uuid.UUID{first.ID, first.ID}}, http.StatusBadRequest},
+		}
+		for _, tc := range cases {
+			rr := makeRequest(t, r, "POST", "/api/live-events", tc.req, auctioneerToken)
+			if rr.Code != tc.status {
+				t.Errorf("%s: got status %v, want %v", tc.name, rr.Code, tc.status)
+			}
+		}
+	})
+
+	t.Run("create", func(t *testing.T) {
+		req := domain.CreateLiveEventRequest{
+			Title:      "Vintage Night",
+			StartsAt:   startsAt,
+			AuctionIDs: []uuid.UUID{first.ID, second.ID, third.ID},
+		}
+		rr := makeRequest(t, r, "POST", "/api/live-events", req, auctioneerToken)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusCreated)
+		}
+		created := parseResponse(t, rr).Data.(map[string]interface{})
+		if lots := created["lots"].([]interface{}); len(lots) != 3 {
+			t.Fatalf("expected 3 lots, got %d", len(lots))
+		}
+
+		eventID, _ := uuid.Parse(created["id"].(string))
+		event = liveEventRepo.events[eventID]
+
+		rr = makeRequest(t, r, "GET", "/api/live-events/"+eventID.String(), nil, "")
+		if rr.Code != http.StatusOK {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusOK)
+		}
+
+		rr = makeRequest(t, r, "GET", "/api/live-events", nil, "")
+		if events := parseResponse(t, rr).Data.([]interface{}); len(events) != 1 {
+			t.Errorf("expected 1 upcoming event, got %d", len(events))
+		}
+	})
+
+	t.Run("lots can't be published or bid on before they open", func(t *testing.T) {
+		rr := makeRequest(t, r, "POST", "/api/auctions/"+first.ID.String()+"/publish", nil, auctioneerToken)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusBadRequest)
+		}
+
+		if code := bid(first, "15.00"); code != http.StatusBadRequest {
+			t.Errorf("got status %v, want %v", code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("only the auctioneer runs the event", func(t *testing.T) {
+		err := liveEventService.OnLiveCommand(event.ID, bidderID, &domain.LiveCommand{Type: domain.LiveCommandOpenLot})
+		if err != domain.ErrForbidden {
+			t.Errorf("expected ErrForbidden, got %v", err)
+		}
+
+		err = liveEventService.OnLiveCommand(event.ID, auctioneerID, &domain.LiveCommand{Type: domain.LiveCommandHammer})
+		if err != domain.ErrLiveLotNotOpen {
+			t.Errorf("expected ErrLiveLotNotOpen before a lot opens, got %v", err)
+		}
+	})
+
+	t.Run("open, warn and hammer", func(t *testing.T) {
+		if err := liveEventService.OnLiveCommand(event.ID, auctioneerID, &domain.LiveCommand{Type: domain.LiveCommandOpenLot}); err != nil {
+			t.Fatalf("failed to open lot: %v", err)
+		}
+		if first.Status != domain.AuctionStatusActive || liveEventRepo.events[event.ID].Status != domain.LiveEventLive {
+			t.Fatalf("expected the first lot open and the event live, got %s and %s", first.Status, liveEventRepo.events[event.ID].Status)
+		}
+
+		err := liveEventService.OnLiveCommand(event.ID, auctioneerID, &domain.LiveCommand{Type: domain.LiveCommandOpenLot})
+		if err != domain.ErrLiveLotOpen {
+			t.Errorf("expected ErrLiveLotOpen with a lot open, got %v", err)
+		}
+
+		if code := bid(first, "25.00"); code != http.StatusCreated {
+			t.Fatalf("got status %v, want %v", code, http.StatusCreated)
+		}
+
+		if err := liveEventService.OnLiveCommand(event.ID, auctioneerID, &domain.LiveCommand{Type: domain.LiveCommandFairWarning}); err != nil {
+			t.Fatalf("failed to give fair warning: %v", err)
+		}
+		if state := liveEventRepo.lots[first.ID].State; state != domain.LiveLotFairWarning {
+			t.Errorf("expected fair warning, got %s", state)
+		}
+
+		if err := liveEventService.OnLiveCommand(event.ID, auctioneerID, &domain.LiveCommand{Type: domain.LiveCommandHammer}); err != nil {
+			t.Fatalf("failed to hammer lot: %v", err)
+		}
+		if first.Status != domain.AuctionStatusCompleted || first.WinnerID == nil || *first.WinnerID != bidderID {
+			t.Errorf("expected the first lot sold to the bidder, got %s", first.Status)
+		}
+		if state := liveEventRepo.lots[first.ID].State; state != domain.LiveLotSold {
+			t.Errorf("expected the first lot sold, got %s", state)
+		}
+	})
+
+	t.Run("moves on to the next lot", func(t *testing.T) {
+		current := liveEventRepo.events[event.ID].CurrentLotID
+		if current == nil || *current != second.ID || second.Status != domain.AuctionStatusActive {
+			t.Fatalf("expected the second lot open after the hammer, got %v", current)
+		}
+
+		if code := bid(second, "50.00"); code != http.StatusCreated {
+			t.Fatalf("got status %v, want %v", code, http.StatusCreated)
+		}
+
+		if _, err := liveEventService.Hammer(context.Background(), event.ID, auctioneerID); err != nil {
+			t.Fatalf("failed to hammer lot: %v", err)
+		}
+		if second.Status != domain.AuctionStatusUnsold || liveEventRepo.lots[second.ID].State != domain.LiveLotPassed {
+			t.Errorf("expected the second lot passed below its reserve, got %s", second.Status)
+		}
+	})
+
+	t.Run("ends after the last lot", func(t *testing.T) {
+		if _, err := liveEventService.Hammer(context.Background(), event.ID, auctioneerID); err != nil {
+			t.Fatalf("failed to hammer lot: %v", err)
+		}
+		if third.Status != domain.AuctionStatusUnsold {
+			t.Errorf("expected the unbid last lot unsold, got %s", third.Status)
+		}
+
+		stored := liveEventRepo.events[event.ID]
+		if stored.Status != domain.LiveEventEnded || stored.CurrentLotID != nil {
+			t.Errorf("expected the event ended, got %s", stored.Status)
+		}
+
+		err := liveEventService.OnLiveCommand(event.ID, auctioneerID, &domain.LiveCommand{Type: domain.LiveCommandOpenLot})
+		if err != domain.ErrLiveEventEnded {
+			t.Errorf("expected ErrLiveEventEnded, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type MaintenanceHandler struct {
+	maintenanceService *service.MaintenanceService
+}
+
+func NewMaintenanceHandler(maintenanceService *service.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{maintenanceService: maintenanceService}
+}
+
+// GetStatus lets clients check for maintenance before offering actions that
+// write
+func (h *MaintenanceHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.maintenanceService.Status(r.Context()))
+}
+
+func (h *MaintenanceHandler) SetStatus(w http.ResponseWriter, r *http.Request) {
+	var req domain.SetMaintenanceRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	status, err := h.maintenanceService.Set(r.Context(), getUserID(r), &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, status)
+}
+
+// ListWindows returns the planned maintenance windows not yet over, so
+// clients can warn bidders ahead of time
+func (h *MaintenanceHandler) ListWindows(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.maintenanceService.Windows(r.Context()))
+}
+
+func (h *MaintenanceHandler) ScheduleWindow(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateMaintenanceWindowRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	window, err := h.maintenanceService.ScheduleWindow(r.Context(), getUserID(r), &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, window)
+}
+
+func (h *MaintenanceHandler) CancelWindow(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid maintenance window ID")
+		return
+	}
+
+	if err := h.maintenanceService.CancelWindow(r.Context(), id); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Maintenance window cancelled"})
+}
@@ -0,0 +1,332 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func TestMaintenanceMode(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	maintenanceService := service.NewMaintenanceService(auctionRepo, nil, &mockTxManager{}, nil)
+	maintenanceHandler := handler.NewMaintenanceHandler(maintenanceService)
+
+	writes := 0
+	r := createTestRouter()
+	r.Use(middleware.ReadOnlyDuringMaintenance(maintenanceService, "/api/admin/maintenance"))
+	r.Get("/api/maintenance", maintenanceHandler.GetStatus)
+	r.Post("/api/things", func(w http.ResponseWriter, r *http.Request) {
+		writes++
+		w.WriteHeader(http.StatusCreated)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(authMiddleware.RequireAdmin)
+		r.Put("/api/admin/maintenance", maintenanceHandler.SetStatus)
+	})
+
+	adminToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "admin")
+	userToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+
+	endingSoon := &domain.Auction{ID: uuid.New(), Status: domain.AuctionStatusActive, EndTime: time.Now().Add(time.Minute)}
+	endingLater := &domain.Auction{ID: uuid.New(), Status: domain.AuctionStatusActive, EndTime: time.Now().Add(2 * time.Hour)}
+	auctionRepo.auctions[endingSoon.ID] = endingSoon
+	auctionRepo.auctions[endingLater.ID] = endingLater
+	laterEnd := endingLater.EndTime
+
+	enable := domain.SetMaintenanceRequest{Enabled: true, Message: "Upgrading the database", DurationMinutes: 10}
+
+	t.Run("only admins switch it", func(t *testing.T) {
+		rr := makeRequest(t, r, "PUT", "/api/admin/maintenance", enable, userToken)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Run("writes pass while off", func(t *testing.T) {
+		rr := makeRequest(t, r, "POST", "/api/things", nil, "")
+		if rr.Code != http.StatusCreated || writes != 1 {
+			t.Errorf("got status %v after %d writes, want %v", rr.Code, writes, http.StatusCreated)
+		}
+	})
+
+	t.Run("switch on", func(t *testing.T) {
+		rr := makeRequest(t, r, "PUT", "/api/admin/maintenance", enable, adminToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("got status %v, want %v", rr.Code, http.StatusOK)
+		}
+
+		rr = makeRequest(t, r, "GET", "/api/maintenance", nil, "")
+		status := parseResponse(t, rr).Data.(map[string]interface{})
+		if status["enabled"] != true || status["message"] != enable.Message {
+			t.Errorf("unexpected status %v", status)
+		}
+	})
+
+	t.Run("writes are rejected while on", func(t *testing.T) {
+		rr := makeRequest(t, r, "POST", "/api/things", nil, "")
+		if rr.Code != http.StatusServiceUnavailable {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusServiceUnavailable)
+		}
+		if retryAfter := rr.Header().Get("Retry-After"); retryAfter != "600" {
+			t.Errorf("got Retry-After %q, want 600", retryAfter)
+		}
+		response := parseResponse(t, rr)
+		if response.Error == nil || response.Error.Code != "MAINTENANCE" || response.Error.Message != enable.Message {
+			t.Errorf("unexpected error %+v", response.Error)
+		}
+		if writes != 1 {
+			t.Errorf("got %d writes, want 1", writes)
+		}
+	})
+
+	t.Run("switch off extends auctions that came due", func(t *testing.T) {
+		rr := makeRequest(t, r, "PUT", "/api/admin/maintenance", domain.SetMaintenanceRequest{Enabled: false}, adminToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("got status %v, want %v", rr.Code, http.StatusOK)
+		}
+
+		if until := time.Until(endingSoon.EndTime); until < 14*time.Minute {
+			t.Errorf("auction ending during maintenance has %v left, want about 15m", until)
+		}
+		if !endingLater.EndTime.Equal(laterEnd) {
+			t.Errorf("auction ending later moved to %v", endingLater.EndTime)
+		}
+
+		rr = makeRequest(t, r, "POST", "/api/things", nil, "")
+		if rr.Code != http.StatusCreated {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusCreated)
+		}
+	})
+}
+
+type mockMaintenanceWindowRepo struct {
+	windows map[uuid.UUID]*domain.MaintenanceWindow
+}
+
+func newMockMaintenanceWindowRepo() *mockMaintenanceWindowRepo {
+	return &mockMaintenanceWindowRepo{windows: make(map[uuid.UUID]*domain.MaintenanceWindow)}
+}
+
+func (r *mockMaintenanceWindowRepo) Create(ctx context.Context, window *domain.MaintenanceWindow) error {
+	window.ID = uuid.New()
+	window.CreatedAt = time.Now()
+	stored := *window
+	r.windows[window.ID] = &stored
+	return nil
+}
+
+func (r *mockMaintenanceWindowRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.MaintenanceWindow, error) {
+	window, ok := r.windows[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	found := *window
+	return &found, nil
+}
+
+func (r *mockMaintenanceWindowRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, ok := r.windows[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.windows, id)
+	return nil
+}
+
+func (r *mockMaintenanceWindowRepo) ListOpen(ctx context.Context) ([]domain.MaintenanceWindow, error) {
+	windows := make([]domain.MaintenanceWindow, 0)
+	for _, window := range r.windows {
+		if window.CompletedAt == nil {
+			windows = append(windows, *window)
+		}
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].StartsAt.Before(windows[j].StartsAt) })
+	return windows, nil
+}
+
+func (r *mockMaintenanceWindowRepo) Overlaps(ctx context.Context, startsAt, endsAt time.Time) (bool, error) {
+	for _, window := range r.windows {
+		if window.CompletedAt == nil && window.StartsAt.Before(endsAt) && window.EndsAt.After(startsAt) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *mockMaintenanceWindowRepo) Complete(ctx context.Context, id uuid.UUID, completedAt time.Time) error {
+	window, ok := r.windows[id]
+	if !ok || window.CompletedAt != nil {
+		return domain.ErrNotFound
+	}
+	window.CompletedAt = &completedAt
+	return nil
+}
+
+func (r *mockMaintenanceWindowRepo) SetAuctionsExtended(ctx context.Context, id uuid.UUID, count int) error {
+	window, ok := r.windows[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	window.AuctionsExtended = count
+	return nil
+}
+
+func TestMaintenanceWindows(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	windowRepo := newMockMaintenanceWindowRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	maintenanceService := service.NewMaintenanceService(auctionRepo, windowRepo, &mockTxManager{}, nil)
+	maintenanceHandler := handler.NewMaintenanceHandler(maintenanceService)
+
+	r := createTestRouter()
+	r.Get("/api/maintenance/windows", maintenanceHandler.ListWindows)
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RetryDuringMaintenanceWindow(maintenanceService))
+		r.Post("/api/bids/failing", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		r.Post("/api/bids/too-low", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		})
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(authMiddleware.RequireAdmin)
+		r.Post("/api/admin/maintenance/windows", maintenanceHandler.ScheduleWindow)
+		r.Delete("/api/admin/maintenance/windows/{id}", maintenanceHandler.CancelWindow)
+	})
+
+	adminToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "admin")
+
+	schedule := func(startsAt *time.Time, endsAt time.Time) (int, map[string]interface{}) {
+		t.Helper()
+		rr := makeRequest(t, r, "POST", "/api/admin/maintenance/windows", domain.CreateMaintenanceWindowRequest{
+			Reason:   "Database upgrade",
+			StartsAt: startsAt,
+			EndsAt:   endsAt,
+		}, adminToken)
+		window, _ := parseResponse(t, rr).Data.(map[string]interface{})
+		return rr.Code, window
+	}
+
+	now := time.Now()
+	endingDuring := &domain.Auction{ID: uuid.New(), Status: domain.AuctionStatusActive, EndTime: now.Add(10 * time.Minute)}
+	endingAfter := &domain.Auction{ID: uuid.New(), Status: domain.AuctionStatusActive, EndTime: now.Add(3 * time.Hour)}
+	auctionRepo.auctions[endingDuring.ID] = endingDuring
+	auctionRepo.auctions[endingAfter.ID] = endingAfter
+	duringEnd, afterEnd := endingDuring.EndTime, endingAfter.EndTime
+
+	t.Run("rejects windows in the past or ending before they start", func(t *testing.T) {
+		past := now.Add(-time.Hour)
+		if code, _ := schedule(&past, now.Add(time.Hour)); code != http.StatusBadRequest {
+			t.Errorf("past start: got status %v, want %v", code, http.StatusBadRequest)
+		}
+		later := now.Add(time.Hour)
+		if code, _ := schedule(&later, later); code != http.StatusBadRequest {
+			t.Errorf("empty window: got status %v, want %v", code, http.StatusBadRequest)
+		}
+	})
+
+	var plannedID string
+	t.Run("plans a window and lists it", func(t *testing.T) {
+		startsAt := now.Add(2 * time.Hour)
+		code, window := schedule(&startsAt, startsAt.Add(time.Hour))
+		if code != http.StatusCreated {
+			t.Fatalf("got status %v, want %v", code, http.StatusCreated)
+		}
+		plannedID, _ = window["id"].(string)
+
+		rr := makeRequest(t, r, "GET", "/api/maintenance/windows", nil, "")
+		windows, _ := parseResponse(t, rr).Data.([]interface{})
+		if len(windows) != 1 {
+			t.Errorf("got %d windows, want 1", len(windows))
+		}
+	})
+
+	t.Run("rejects overlapping windows", func(t *testing.T) {
+		startsAt := now.Add(150 * time.Minute)
+		if code, _ := schedule(&startsAt, startsAt.Add(time.Hour)); code != http.StatusBadRequest {
+			t.Errorf("got status %v, want %v", code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("cancels a window that hasn't started", func(t *testing.T) {
+		rr := makeRequest(t, r, "DELETE", "/api/admin/maintenance/windows/"+plannedID, nil, adminToken)
+		if rr.Code != http.StatusOK {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusOK)
+		}
+	})
+
+	var currentID string
+	t.Run("bid failures are retried while a window is in effect", func(t *testing.T) {
+		rr := makeRequest(t, r, "POST", "/api/bids/failing", nil, "")
+		if rr.Code != http.StatusInternalServerError {
+			t.Fatalf("before the window: got status %v, want %v", rr.Code, http.StatusInternalServerError)
+		}
+
+		code, window := schedule(nil, now.Add(30*time.Minute))
+		if code != http.StatusCreated {
+			t.Fatalf("got status %v, want %v", code, http.StatusCreated)
+		}
+		currentID, _ = window["id"].(string)
+
+		rr = makeRequest(t, r, "POST", "/api/bids/failing", nil, "")
+		if rr.Code != http.StatusServiceUnavailable {
+			t.Fatalf("got status %v, want %v", rr.Code, http.StatusServiceUnavailable)
+		}
+		if retryAfter, _ := strconv.Atoi(rr.Header().Get("Retry-After")); retryAfter < 1700 || retryAfter > 1800 {
+			t.Errorf("got Retry-After %q, want about 1800", rr.Header().Get("Retry-After"))
+		}
+		if response := parseResponse(t, rr); response.Error == nil || response.Error.Code != "MAINTENANCE_WINDOW" {
+			t.Errorf("unexpected error %+v", response.Error)
+		}
+
+		rr = makeRequest(t, r, "POST", "/api/bids/too-low", nil, "")
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("client errors: got status %v, want %v", rr.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("a window in effect can't be cancelled", func(t *testing.T) {
+		rr := makeRequest(t, r, "DELETE", "/api/admin/maintenance/windows/"+currentID, nil, adminToken)
+		if rr.Code != http.StatusConflict {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusConflict)
+		}
+	})
+
+	t.Run("finishing extends auctions by the downtime", func(t *testing.T) {
+		finishedAt := now.Add(31 * time.Minute)
+		maintenanceService.FinishWindows(context.Background(), finishedAt)
+
+		window, _ := windowRepo.GetByID(context.Background(), uuid.MustParse(currentID))
+		if window.CompletedAt == nil || window.AuctionsExtended != 1 {
+			t.Fatalf("window not completed: %+v", window)
+		}
+		if got := endingDuring.EndTime.Sub(duringEnd); got != window.Downtime() {
+			t.Errorf("auction ending during the window moved by %v, want %v", got, window.Downtime())
+		}
+		if !endingAfter.EndTime.Equal(afterEnd) {
+			t.Errorf("auction ending after the window moved to %v", endingAfter.EndTime)
+		}
+
+		rr := makeRequest(t, r, "POST", "/api/bids/failing", nil, "")
+		if rr.Code != http.StatusInternalServerError {
+			t.Errorf("after the window: got status %v, want %v", rr.Code, http.StatusInternalServerError)
+		}
+	})
+}
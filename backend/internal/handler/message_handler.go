@@ -31,16 +31,13 @@ func (h *MessageHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userID := getUserID(r)
-	msg, conversationID, err := h.messageService.SendMessage(r.Context(), userID, &req)
+	resp, err := h.messageService.SendMessage(r.Context(), userID, &req)
 	if err != nil {
 		handleError(w, err)
 		return
 	}
 
-	respondJSON(w, http.StatusCreated, &domain.SendMessageResponse{
-		Message:        msg,
-		ConversationID: conversationID,
-	})
+	respondJSON(w, http.StatusCreated, resp)
 }
 
 // GetConversations handles GET /api/conversations
@@ -59,6 +56,10 @@ func (h *MessageHandler) GetConversations(w http.ResponseWriter, r *http.Request
 }
 
 // GetMessages handles GET /api/conversations/{id}/messages
+//
+// Passing before or after (a message ID) or anchor (first_unread or an
+// RFC3339 time) switches to cursor pagination, oldest first. Otherwise
+// messages are paged by offset, newest first.
 func (h *MessageHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
 	conversationID, err := getURLParamUUID(r, "id")
 	if err != nil {
@@ -67,8 +68,41 @@ func (h *MessageHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userID := getUserID(r)
-	page := getQueryParamInt(r, "page", 1)
 	limit := getQueryParamInt(r, "limit", 50)
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	query := r.URL.Query()
+	if query.Has("before") || query.Has("after") || query.Has("anchor") {
+		params := &domain.MessageWindowParams{
+			Before: getQueryParamUUID(r, "before"),
+			After:  getQueryParamUUID(r, "after"),
+			Limit:  limit,
+		}
+		if (query.Has("before") && params.Before == nil) || (query.Has("after") && params.After == nil) {
+			respondError(w, http.StatusBadRequest, "INVALID_CURSOR", "Invalid message cursor")
+			return
+		}
+		if params.Before != nil && params.After != nil {
+			respondError(w, http.StatusBadRequest, "INVALID_CURSOR", "Only one of before and after can be given")
+			return
+		}
+
+		resp, err := h.messageService.GetMessagesWindow(r.Context(), userID, conversationID, params, query.Get("anchor"))
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+
+		respondJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	page := getQueryParamInt(r, "page", 1)
+	if page < 1 {
+		page = 1
+	}
 
 	messages, totalCount, err := h.messageService.GetMessages(r.Context(), userID, conversationID, page, limit)
 	if err != nil {
@@ -79,7 +113,9 @@ func (h *MessageHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
 	totalPages := (totalCount + limit - 1) / limit
 
 	respondJSONWithMeta(w, http.StatusOK, &domain.MessagesResponse{
-		Messages: messages,
+		Messages:      messages,
+		HasMoreBefore: page < totalPages,
+		HasMoreAfter:  page > 1,
 	}, &domain.APIMeta{
 		Page:       page,
 		Limit:      limit,
@@ -112,15 +148,13 @@ func (h *MessageHandler) MarkAsRead(w http.ResponseWriter, r *http.Request) {
 func (h *MessageHandler) GetUnreadCount(w http.ResponseWriter, r *http.Request) {
 	userID := getUserID(r)
 
-	count, err := h.messageService.GetUnreadCount(r.Context(), userID)
+	counts, err := h.messageService.GetUnreadCount(r.Context(), userID)
 	if err != nil {
 		handleError(w, err)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, &domain.UnreadCountResponse{
-		Count: count,
-	})
+	respondJSON(w, http.StatusOK, counts)
 }
 
 // GetConversation handles GET /api/conversations/{id}
@@ -141,3 +175,79 @@ func (h *MessageHandler) GetConversation(w http.ResponseWriter, r *http.Request)
 
 	respondJSON(w, http.StatusOK, conversation)
 }
+
+// GetPresence handles GET /api/users/{id}/presence
+func (h *MessageHandler) GetPresence(w http.ResponseWriter, r *http.Request) {
+	userID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid user ID")
+		return
+	}
+
+	presence, err := h.messageService.GetPresence(r.Context(), userID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, presence)
+}
+
+// RegisterDeviceKey handles PUT /api/users/me/keys
+func (h *MessageHandler) RegisterDeviceKey(w http.ResponseWriter, r *http.Request) {
+	var req domain.RegisterDeviceKeyRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	userID := getUserID(r)
+	key, err := h.messageService.RegisterDeviceKey(r.Context(), userID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, key)
+}
+
+// RevokeDeviceKey handles DELETE /api/users/me/keys/{keyId}
+func (h *MessageHandler) RevokeDeviceKey(w http.ResponseWriter, r *http.Request) {
+	keyID, err := getURLParamUUID(r, "keyId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid key ID")
+		return
+	}
+
+	userID := getUserID(r)
+	if err := h.messageService.RevokeDeviceKey(r.Context(), userID, keyID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Device key revoked",
+	})
+}
+
+// GetDeviceKeys handles GET /api/users/{id}/keys
+func (h *MessageHandler) GetDeviceKeys(w http.ResponseWriter, r *http.Request) {
+	userID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid user ID")
+		return
+	}
+
+	keys, err := h.messageService.GetDeviceKeys(r.Context(), userID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, keys)
+}
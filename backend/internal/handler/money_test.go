@@ -0,0 +1,232 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestMoney_JSON(t *testing.T) {
+	money := domain.NewMoney(decimal.RequireFromString("12.5"), domain.CurrencyEUR)
+
+	data, err := json.Marshal(money)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if string(data) != `{"amount":"12.50","currency":"EUR"}` {
+		t.Errorf("got %s", data)
+	}
+
+	var decoded domain.Money
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if !decoded.Amount.Equal(money.Amount) || decoded.Currency != money.Currency {
+		t.Errorf("round trip gave %v", decoded)
+	}
+
+	// Far more digits than a float64 holds survive the round trip
+	large := domain.NewMoney(decimal.RequireFromString("90071992547409.93"), domain.CurrencyUSD)
+	data, _ = json.Marshal(large)
+	if err := json.Unmarshal(data, &decoded); err != nil || !decoded.Amount.Equal(large.Amount) {
+		t.Errorf("large amount round trip gave %v (%v)", decoded.Amount, err)
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want error
+	}{
+		{"number amount", `{"amount":12.5,"currency":"USD"}`, domain.ErrInvalidAmount},
+		{"fraction of a cent", `{"amount":"12.505","currency":"USD"}`, domain.ErrInvalidAmount},
+		{"not a number", `{"amount":"twelve","currency":"USD"}`, domain.ErrInvalidAmount},
+		{"unsupported currency", `{"amount":"12.50","currency":"GBP"}`, domain.ErrInvalidCurrency},
+		{"missing currency", `{"amount":"12.50"}`, domain.ErrInvalidCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m domain.Money
+			if err := json.Unmarshal([]byte(tt.body), &m); !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_Rounding(t *testing.T) {
+	tests := []struct {
+		mode   domain.RoundingMode
+		amount string
+		want   string
+	}{
+		{domain.RoundHalfEven, "0.125", "0.12"},
+		{domain.RoundHalfEven, "0.135", "0.14"},
+		{domain.RoundHalfEven, "-0.125", "-0.12"},
+		{domain.RoundHalfUp, "0.125", "0.13"},
+		{domain.RoundHalfUp, "-0.125", "-0.13"},
+		{domain.RoundDown, "0.129", "0.12"},
+		{domain.RoundDown, "-0.129", "-0.12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode)+" "+tt.amount, func(t *testing.T) {
+			got := tt.mode.Round(decimal.RequireFromString(tt.amount))
+			if got.StringFixed(2) != tt.want {
+				t.Errorf("got %s, want %s", got.StringFixed(2), tt.want)
+			}
+		})
+	}
+
+	if _, err := domain.ParseRoundingMode("ceiling"); err == nil {
+		t.Error("expected an unknown rounding mode to be rejected")
+	}
+}
+
+func TestMoney_Fee(t *testing.T) {
+	defer func(mode domain.RoundingMode) { domain.FeeRounding = mode }(domain.FeeRounding)
+
+	price := domain.NewMoney(decimal.RequireFromString("12.50"), domain.CurrencyBAM)
+	rate := decimal.RequireFromString("0.01")
+
+	domain.FeeRounding = domain.RoundHalfEven
+	if fee := price.Fee(rate); fee.String() != "0.12 KM" {
+		t.Errorf("half even fee: got %s", fee)
+	}
+
+	domain.FeeRounding = domain.RoundHalfUp
+	if fee := price.Fee(rate); fee.String() != "0.13 KM" {
+		t.Errorf("half up fee: got %s", fee)
+	}
+}
+
+func TestMoney_Format(t *testing.T) {
+	tests := []struct {
+		money domain.Money
+		want  string
+	}{
+		{domain.NewMoney(decimal.RequireFromString("1234.5"), domain.CurrencyUSD), "$1234.50"},
+		{domain.NewMoney(decimal.RequireFromString("7"), domain.CurrencyEUR), "€7.00"},
+		{domain.NewMoney(decimal.RequireFromString("12.5"), domain.CurrencyBAM), "12.50 KM"},
+		{domain.NewMoney(decimal.RequireFromString("-3.2"), domain.CurrencyUSD), "-$3.20"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestAuctionHandler_Currency(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil, // no S3 for tests
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	auctionHandler := handler.NewAuctionHandler(auctionService)
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions", auctionHandler.Create)
+	r.With(authMiddleware.RequireAuth).Put("/api/auctions/{id}", auctionHandler.Update)
+
+	sellerID := uuid.New()
+	token, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+
+	newRequest := func(currency *string, price string) domain.CreateAuctionRequest {
+		return domain.CreateAuctionRequest{
+			Title:         "Test Auction",
+			Currency:      currency,
+			StartingPrice: price,
+			StartTime:     time.Now().Add(1 * time.Hour),
+			EndTime:       time.Now().Add(24 * time.Hour),
+		}
+	}
+
+	t.Run("defaults to USD", func(t *testing.T) {
+		rr := makeRequest(t, r, "POST", "/api/auctions", newRequest(nil, "10.00"), token)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("got status %d", rr.Code)
+		}
+		data := parseResponse(t, rr).Data.(map[string]interface{})
+		if data["currency"] != "USD" {
+			t.Errorf("got currency %v", data["currency"])
+		}
+	})
+
+	t.Run("priced in BAM", func(t *testing.T) {
+		rr := makeRequest(t, r, "POST", "/api/auctions", newRequest(stringPtr("BAM"), "10.00"), token)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("got status %d", rr.Code)
+		}
+		data := parseResponse(t, rr).Data.(map[string]interface{})
+		if data["currency"] != "BAM" {
+			t.Errorf("got currency %v", data["currency"])
+		}
+	})
+
+	t.Run("unsupported currency", func(t *testing.T) {
+		rr := makeRequest(t, r, "POST", "/api/auctions", newRequest(stringPtr("GBP"), "10.00"), token)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("got status %d", rr.Code)
+		}
+	})
+
+	t.Run("fraction of a cent", func(t *testing.T) {
+		rr := makeRequest(t, r, "POST", "/api/auctions", newRequest(nil, "10.005"), token)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("got status %d", rr.Code)
+		}
+		if response := parseResponse(t, rr); response.Error == nil || response.Error.Code != "INVALID_AMOUNT" {
+			t.Errorf("got error %v", response.Error)
+		}
+	})
+
+	t.Run("currency locked once bid on", func(t *testing.T) {
+		auction := &domain.Auction{
+			SellerID:      sellerID,
+			Title:         "Bid On",
+			Currency:      domain.CurrencyUSD,
+			StartingPrice: decimal.NewFromInt(10),
+			CurrentPrice:  decimal.NewFromInt(15),
+			BidCount:      2,
+			StartTime:     time.Now().Add(-1 * time.Hour),
+			EndTime:       time.Now().Add(24 * time.Hour),
+			Status:        domain.AuctionStatusActive,
+		}
+		auctionRepo.Create(context.Background(), auction)
+
+		body := domain.UpdateAuctionRequest{Currency: stringPtr("EUR")}
+		rr := makeRequest(t, r, "PUT", "/api/auctions/"+auction.ID.String(), body, token)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("got status %d", rr.Code)
+		}
+		if response := parseResponse(t, rr); response.Error == nil || response.Error.Code != "CURRENCY_LOCKED" {
+			t.Errorf("got error %v", response.Error)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestUserHandler_NotificationActions(t *testing.T) {
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	notificationService := service.NewNotificationService(
+		&mockNotificationRepo{},
+		newMockUserRepo(),
+		newMockWatchlistRepo(),
+		nil,
+		nil,
+		nil,
+		nil,
+		&mockEmailSender{},
+		nil,
+		nil,
+		nil,
+		nil,
+		"https://cards.example",
+	)
+	userHandler := handler.NewUserHandler(nil, notificationService)
+
+	r := createTestRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Get("/api/notifications", userHandler.GetNotifications)
+	})
+
+	latest := func(t *testing.T, userID uuid.UUID) domain.Notification {
+		t.Helper()
+		token, _ := jwtManager.GenerateAccessToken(userID, "user")
+		rr := makeRequest(t, r, "GET", "/api/notifications?grouped=true", nil, token)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var list struct {
+			Threads []domain.NotificationThread `json:"threads"`
+		}
+		data, _ := json.Marshal(parseResponse(t, rr).Data)
+		if err := json.Unmarshal(data, &list); err != nil || len(list.Threads) != 1 {
+			t.Fatalf("expected one thread, got %s", data)
+		}
+		return list.Threads[0].Latest
+	}
+
+	auction := &domain.Auction{
+		ID:           uuid.New(),
+		Title:        "Charizard",
+		CurrentPrice: decimal.NewFromInt(120),
+		Currency:     domain.CurrencyUSD,
+	}
+	auctionURL := "https://cards.example/auctions/" + auction.ID.String()
+
+	t.Run("outbid offers to bid again", func(t *testing.T) {
+		bidder := uuid.New()
+		notificationService.NotifyOutbid(context.Background(), bidder, auction, decimal.NewFromInt(130))
+
+		notification := latest(t, bidder)
+		if notification.Link == nil || *notification.Link != auctionURL {
+			t.Errorf("expected the auction link, got %v", notification.Link)
+		}
+		if len(notification.Actions) != 1 {
+			t.Fatalf("got actions %+v", notification.Actions)
+		}
+		action := notification.Actions[0]
+		minimum := auction.MinimumBidAbove(decimal.NewFromInt(130))
+		if action.Type != domain.NotificationActionBid || action.Amount == nil || !action.Amount.Amount.Equal(minimum) {
+			t.Errorf("expected a bid of %s, got %+v", minimum, action)
+		}
+		if action.URL != auctionURL+"?bid="+minimum.StringFixed(domain.MoneyScale) {
+			t.Errorf("got URL %q", action.URL)
+		}
+	})
+
+	t.Run("winner can pay and rate", func(t *testing.T) {
+		winner := uuid.New()
+		notificationService.NotifyAuctionWon(context.Background(), winner, auction)
+
+		notification := latest(t, winner)
+		payURL := "https://cards.example/my-purchases?auction=" + auction.ID.String() + "&action=pay"
+		if notification.Link == nil || *notification.Link != payURL {
+			t.Errorf("expected the purchase link, got %v", notification.Link)
+		}
+		if len(notification.Actions) != 2 ||
+			notification.Actions[0].Type != domain.NotificationActionPay || notification.Actions[0].URL != payURL ||
+			notification.Actions[1].Type != domain.NotificationActionRate {
+			t.Errorf("got actions %+v", notification.Actions)
+		}
+	})
+
+	t.Run("link without an auction", func(t *testing.T) {
+		seller := uuid.New()
+		notificationService.NotifyPayout(context.Background(), &domain.Payout{
+			UserID: seller,
+			Amount: decimal.NewFromInt(50),
+			Status: domain.PayoutApproved,
+		})
+
+		notification := latest(t, seller)
+		if notification.Link == nil || *notification.Link != "https://cards.example/profile?tab=payouts" {
+			t.Errorf("expected the payouts link, got %v", notification.Link)
+		}
+		if len(notification.Actions) != 0 {
+			t.Errorf("expected no actions, got %+v", notification.Actions)
+		}
+	})
+}
@@ -0,0 +1,222 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/pkg/unsubscribe"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock notification preference repository keyed by user and type
+type mockNotificationPreferenceRepo struct {
+	preferences map[uuid.UUID]map[domain.NotificationType]domain.NotificationPreference
+}
+
+func (r *mockNotificationPreferenceRepo) GetByUser(ctx context.Context, userID uuid.UUID) ([]domain.NotificationPreference, error) {
+	preferences := make([]domain.NotificationPreference, 0)
+	for _, preference := range r.preferences[userID] {
+		preferences = append(preferences, preference)
+	}
+	return preferences, nil
+}
+
+func (r *mockNotificationPreferenceRepo) Save(ctx context.Context, userID uuid.UUID, preferences []domain.NotificationPreference) error {
+	if r.preferences[userID] == nil {
+		r.preferences[userID] = make(map[domain.NotificationType]domain.NotificationPreference)
+	}
+	for _, preference := range preferences {
+		r.preferences[userID][preference.Type] = preference
+	}
+	return nil
+}
+
+func (r *mockNotificationPreferenceRepo) OptedOut(ctx context.Context, userIDs []uuid.UUID, notificationType domain.NotificationType, channel domain.NotificationChannel) (map[uuid.UUID]bool, error) {
+	optedOut := make(map[uuid.UUID]bool)
+	for _, userID := range userIDs {
+		preference, ok := r.preferences[userID][notificationType]
+		if !ok {
+			continue
+		}
+		switch channel {
+		case domain.NotificationChannelEmail:
+			optedOut[userID] = !preference.Email
+		case domain.NotificationChannelInApp:
+			optedOut[userID] = !preference.InApp
+		case domain.NotificationChannelPush:
+			optedOut[userID] = !preference.Push
+		}
+	}
+	return optedOut, nil
+}
+
+func TestUserHandler_NotificationPreferences(t *testing.T) {
+	notificationRepo := &mockNotificationRepo{}
+	userRepo := newMockUserRepo()
+	emailSender := &mockEmailSender{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	notificationService := service.NewNotificationService(
+		notificationRepo,
+		userRepo,
+		newMockWatchlistRepo(),
+		nil,
+		nil,
+		&mockNotificationPreferenceRepo{preferences: make(map[uuid.UUID]map[domain.NotificationType]domain.NotificationPreference)},
+		nil,
+		emailSender,
+		nil,
+		nil,
+		nil,
+		unsubscribe.NewSigner("test-unsubscribe-secret"),
+		"https://cards.example",
+	)
+	userHandler := handler.NewUserHandler(nil, notificationService)
+
+	r := createTestRouter()
+	r.Post("/api/notifications/unsubscribe", userHandler.Unsubscribe)
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Get("/api/users/me/notification-settings", userHandler.GetNotificationSettings)
+		r.Put("/api/users/me/notification-settings", userHandler.UpdateNotificationSettings)
+	})
+
+	user := &domain.User{Email: "bidder@example.com", Username: "bidder"}
+	userRepo.Create(context.Background(), user)
+	token, _ := jwtManager.GenerateAccessToken(user.ID, "user")
+
+	auction := &domain.Auction{
+		ID:           uuid.New(),
+		Title:        "Charizard",
+		CurrentPrice: decimal.NewFromInt(120),
+		Currency:     domain.CurrencyUSD,
+	}
+
+	preferenceOf := func(t *testing.T, settings domain.NotificationSettings, notificationType domain.NotificationType) domain.NotificationPreference {
+		t.Helper()
+		for _, preference := range settings.Preferences {
+			if preference.Type == notificationType {
+				return preference
+			}
+		}
+		t.Fatalf("no preference for %s in %+v", notificationType, settings)
+		return domain.NotificationPreference{}
+	}
+
+	settingsFrom := func(t *testing.T, method string, body interface{}) domain.NotificationSettings {
+		t.Helper()
+		rr := makeRequest(t, r, method, "/api/users/me/notification-settings", body, token)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var settings domain.NotificationSettings
+		data, _ := json.Marshal(parseResponse(t, rr).Data)
+		if err := json.Unmarshal(data, &settings); err != nil {
+			t.Fatalf("failed to decode settings: %v", err)
+		}
+		return settings
+	}
+
+	t.Run("every type on by default", func(t *testing.T) {
+		settings := settingsFrom(t, "GET", nil)
+		if len(settings.Preferences) != len(domain.NotificationTypes) {
+			t.Fatalf("expected %d types, got %d", len(domain.NotificationTypes), len(settings.Preferences))
+		}
+		for _, preference := range settings.Preferences {
+			if !preference.Email || !preference.InApp || !preference.Push {
+				t.Errorf("expected %s on everywhere, got %+v", preference.Type, preference)
+			}
+		}
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		rr := makeRequest(t, r, "PUT", "/api/users/me/notification-settings", map[string]interface{}{
+			"preferences": []map[string]interface{}{{"type": "carrier_pigeon", "email": false}},
+		}, token)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rr.Code)
+		}
+		if response := parseResponse(t, rr); response.Error == nil || response.Error.Code != "INVALID_NOTIFICATION_TYPE" {
+			t.Errorf("got %+v", response.Error)
+		}
+	})
+
+	t.Run("email off keeps in-app", func(t *testing.T) {
+		settings := settingsFrom(t, "PUT", map[string]interface{}{
+			"preferences": []map[string]interface{}{{"type": "outbid", "email": false}},
+		})
+		if outbid := preferenceOf(t, settings, domain.NotificationOutbid); outbid.Email || !outbid.InApp || !outbid.Push {
+			t.Fatalf("expected only email off, got %+v", outbid)
+		}
+
+		notificationService.NotifyOutbid(context.Background(), user.ID, auction, decimal.NewFromInt(130))
+		if len(emailSender.sentEmails) != 0 {
+			t.Errorf("expected no email, got %v", emailSender.sentEmails)
+		}
+		if len(notificationRepo.notifications) != 1 {
+			t.Errorf("expected the in-app notification, got %d", len(notificationRepo.notifications))
+		}
+	})
+
+	t.Run("in-app off keeps email", func(t *testing.T) {
+		settingsFrom(t, "PUT", map[string]interface{}{
+			"preferences": []map[string]interface{}{{"type": "auction_won", "in_app": false}},
+		})
+
+		stored := len(notificationRepo.notifications)
+		notificationService.NotifyAuctionWon(context.Background(), user.ID, auction)
+		if len(notificationRepo.notifications) != stored {
+			t.Errorf("expected no in-app notification")
+		}
+		if len(emailSender.sentEmails) != 1 {
+			t.Errorf("expected the email, got %v", emailSender.sentEmails)
+		}
+	})
+
+	t.Run("unsubscribe link", func(t *testing.T) {
+		if len(emailSender.bodies) == 0 {
+			t.Fatal("expected an email to unsubscribe from")
+		}
+		link := regexp.MustCompile(`Unsubscribe: (\S+)`).FindStringSubmatch(emailSender.bodies[len(emailSender.bodies)-1])
+		if link == nil {
+			t.Fatalf("expected an unsubscribe link in %q", emailSender.bodies[len(emailSender.bodies)-1])
+		}
+		parsed, err := url.Parse(link[1])
+		if err != nil || parsed.Path != "/unsubscribe" {
+			t.Fatalf("got link %q", link[1])
+		}
+
+		// No sign-in needed
+		rr := makeRequest(t, r, "POST", "/api/notifications/unsubscribe", domain.UnsubscribeRequest{Token: parsed.Query().Get("token")}, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		won := preferenceOf(t, settingsFrom(t, "GET", nil), domain.NotificationAuctionWon)
+		if won.Email || won.InApp || !won.Push {
+			t.Errorf("expected auction won emails off too, got %+v", won)
+		}
+	})
+
+	t.Run("tampered token", func(t *testing.T) {
+		forged := user.ID.String() + ".outbid.0000"
+		rr := makeRequest(t, r, "POST", "/api/notifications/unsubscribe", domain.UnsubscribeRequest{Token: forged}, "")
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rr.Code)
+		}
+		if response := parseResponse(t, rr); response.Error == nil || response.Error.Code != "INVALID_UNSUBSCRIBE_TOKEN" {
+			t.Errorf("got %+v", response.Error)
+		}
+	})
+}
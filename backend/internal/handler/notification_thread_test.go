@@ -0,0 +1,146 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func TestUserHandler_NotificationThreads(t *testing.T) {
+	notificationRepo := &mockNotificationRepo{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	notificationService := service.NewNotificationService(
+		notificationRepo,
+		newMockUserRepo(),
+		newMockWatchlistRepo(),
+		nil,
+		nil,
+		nil,
+		nil,
+		&mockEmailSender{},
+		nil,
+		nil,
+		nil,
+		nil,
+		"http://localhost",
+	)
+	userHandler := handler.NewUserHandler(nil, notificationService)
+
+	r := createTestRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Get("/api/notifications", userHandler.GetNotifications)
+		r.Put("/api/notifications/threads/{id}/read", userHandler.MarkNotificationThreadRead)
+	})
+
+	userID := uuid.New()
+	token, _ := jwtManager.GenerateAccessToken(userID, "user")
+	otherToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+
+	// Three outbid notices on one auction, a win on another, and a login
+	// alert about no auction, oldest first
+	charizard, blastoise := uuid.New(), uuid.New()
+	start := time.Now().Add(-time.Hour)
+	seed := []domain.Notification{
+		{Type: domain.NotificationOutbid, Title: "You've been outbid on Charizard", AuctionID: &charizard},
+		{Type: domain.NotificationOutbid, Title: "You've been outbid on Charizard", AuctionID: &charizard},
+		{Type: domain.NotificationAuctionWon, Title: "You won Blastoise", AuctionID: &blastoise},
+		{Type: domain.NotificationSuspiciousLogin, Title: "New sign-in from Sarajevo"},
+		{Type: domain.NotificationOutbid, Title: "You've been outbid again on Charizard", AuctionID: &charizard},
+	}
+	for i := range seed {
+		seed[i].UserID = userID
+		seed[i].CreatedAt = start.Add(time.Duration(i) * time.Minute)
+		notificationRepo.Create(context.Background(), &seed[i])
+	}
+	loginAlert := seed[3]
+
+	type threadList struct {
+		Threads     []domain.NotificationThread `json:"threads"`
+		UnreadCount int                         `json:"unread_count"`
+	}
+	listThreads := func(t *testing.T, query string) (threadList, *domain.APIMeta) {
+		t.Helper()
+		rr := makeRequest(t, r, "GET", "/api/notifications?grouped=true"+query, nil, token)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		response := parseResponse(t, rr)
+		var list threadList
+		data, _ := json.Marshal(response.Data)
+		if err := json.Unmarshal(data, &list); err != nil {
+			t.Fatalf("failed to decode threads: %v", err)
+		}
+		return list, response.Meta
+	}
+
+	t.Run("one thread per auction", func(t *testing.T) {
+		list, meta := listThreads(t, "")
+		if len(list.Threads) != 3 || list.UnreadCount != 5 || meta.TotalCount != 3 {
+			t.Fatalf("got %+v, meta %+v", list, meta)
+		}
+
+		// Newest activity first
+		latest := list.Threads[0]
+		if latest.ID != charizard || latest.Count != 3 || latest.UnreadCount != 3 {
+			t.Errorf("got %+v", latest)
+		}
+		if latest.Latest.Title != "You've been outbid again on Charizard" {
+			t.Errorf("expected the newest notice as the thread's state, got %q", latest.Latest.Title)
+		}
+		if list.Threads[1].ID != loginAlert.ID || list.Threads[1].AuctionID != nil || list.Threads[1].Count != 1 {
+			t.Errorf("expected the login alert as a thread of its own, got %+v", list.Threads[1])
+		}
+	})
+
+	t.Run("pages", func(t *testing.T) {
+		list, meta := listThreads(t, "&limit=2&page=2")
+		if len(list.Threads) != 1 || list.Threads[0].ID != blastoise || meta.TotalPages != 2 {
+			t.Errorf("got %+v, meta %+v", list, meta)
+		}
+	})
+
+	t.Run("mark thread read", func(t *testing.T) {
+		rr := makeRequest(t, r, "PUT", "/api/notifications/threads/"+charizard.String()+"/read", nil, token)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		list, _ := listThreads(t, "&unread=true")
+		if len(list.Threads) != 2 || list.UnreadCount != 2 {
+			t.Fatalf("expected the auction's thread read, got %+v", list)
+		}
+		for _, thread := range list.Threads {
+			if thread.ID == charizard {
+				t.Errorf("expected no unread charizard thread, got %+v", thread)
+			}
+		}
+
+		// A notification not about an auction is marked read by its own ID
+		rr = makeRequest(t, r, "PUT", "/api/notifications/threads/"+loginAlert.ID.String()+"/read", nil, token)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if list, _ := listThreads(t, "&unread=true"); len(list.Threads) != 1 || list.Threads[0].ID != blastoise {
+			t.Errorf("got %+v", list)
+		}
+	})
+
+	t.Run("another user's thread", func(t *testing.T) {
+		rr := makeRequest(t, r, "PUT", "/api/notifications/threads/"+blastoise.String()+"/read", nil, otherToken)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rr.Code)
+		}
+	})
+}
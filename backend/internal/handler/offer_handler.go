@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type OfferHandler struct {
+	offerService *service.OfferService
+}
+
+func NewOfferHandler(offerService *service.OfferService) *OfferHandler {
+	return &OfferHandler{offerService: offerService}
+}
+
+func (h *OfferHandler) MakeOffer(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	var req domain.MakeOfferRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	userID := getUserID(r)
+	offer, err := h.offerService.MakeOffer(r.Context(), auctionID, userID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, offer)
+}
+
+func (h *OfferHandler) ListForAuction(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	userID := getUserID(r)
+	offers, err := h.offerService.ListForAuction(r.Context(), auctionID, userID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, offers)
+}
+
+func (h *OfferHandler) GetMyOffers(w http.ResponseWriter, r *http.Request) {
+	var status *domain.OfferStatus
+	if s := getQueryParamString(r, "status"); s != nil {
+		st := domain.OfferStatus(*s)
+		status = &st
+	}
+
+	userID := getUserID(r)
+	offers, err := h.offerService.GetMyOffers(r.Context(), userID, status)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, offers)
+}
+
+func (h *OfferHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid offer ID")
+		return
+	}
+
+	userID := getUserID(r)
+	offer, err := h.offerService.Accept(r.Context(), id, userID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, offer)
+}
+
+func (h *OfferHandler) Decline(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid offer ID")
+		return
+	}
+
+	userID := getUserID(r)
+	offer, err := h.offerService.Decline(r.Context(), id, userID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, offer)
+}
+
+func (h *OfferHandler) Counter(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid offer ID")
+		return
+	}
+
+	var req domain.CounterOfferRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	userID := getUserID(r)
+	offer, err := h.offerService.Counter(r.Context(), id, userID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, offer)
+}
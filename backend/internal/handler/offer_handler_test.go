@@ -0,0 +1,341 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock offer repository
+type mockOfferRepo struct {
+	offers map[uuid.UUID]*domain.Offer
+}
+
+func newMockOfferRepo() *mockOfferRepo {
+	return &mockOfferRepo{
+		offers: make(map[uuid.UUID]*domain.Offer),
+	}
+}
+
+func (r *mockOfferRepo) Create(ctx context.Context, offer *domain.Offer) error {
+	for _, existing := range r.offers {
+		if existing.AuctionID == offer.AuctionID && existing.BuyerID == offer.BuyerID &&
+			(existing.Status == domain.OfferStatusPending || existing.Status == domain.OfferStatusCountered) {
+			return domain.ErrOfferExists
+		}
+	}
+	if offer.ID == uuid.Nil {
+		offer.ID = uuid.New()
+	}
+	offer.CreatedAt = time.Now()
+	offer.UpdatedAt = offer.CreatedAt
+	stored := *offer
+	r.offers[offer.ID] = &stored
+	return nil
+}
+
+func (r *mockOfferRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Offer, error) {
+	if offer, ok := r.offers[id]; ok {
+		copied := *offer
+		return &copied, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockOfferRepo) GetByAuction(ctx context.Context, auctionID uuid.UUID, buyerID *uuid.UUID) ([]domain.Offer, error) {
+	offers := make([]domain.Offer, 0)
+	for _, offer := range r.offers {
+		if offer.AuctionID == auctionID && (buyerID == nil || offer.BuyerID == *buyerID) {
+			offers = append(offers, *offer)
+		}
+	}
+	return offers, nil
+}
+
+func (r *mockOfferRepo) GetByBuyer(ctx context.Context, buyerID uuid.UUID, status *domain.OfferStatus) ([]domain.Offer, error) {
+	offers := make([]domain.Offer, 0)
+	for _, offer := range r.offers {
+		if offer.BuyerID == buyerID && (status == nil || offer.Status == *status) {
+			offers = append(offers, *offer)
+		}
+	}
+	return offers, nil
+}
+
+func (r *mockOfferRepo) CountByBuyer(ctx context.Context, auctionID, buyerID uuid.UUID) (int, error) {
+	count := 0
+	for _, offer := range r.offers {
+		if offer.AuctionID == auctionID && offer.BuyerID == buyerID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *mockOfferRepo) Counter(ctx context.Context, offer *domain.Offer) error {
+	stored, ok := r.offers[offer.ID]
+	if !ok || stored.Status != domain.OfferStatusPending {
+		return domain.ErrOfferNotPending
+	}
+	stored.Status = domain.OfferStatusCountered
+	stored.CounterAmount = offer.CounterAmount
+	stored.ExpiresAt = offer.ExpiresAt
+	*offer = *stored
+	return nil
+}
+
+func (r *mockOfferRepo) Respond(ctx context.Context, offer *domain.Offer, from domain.OfferStatus) error {
+	stored, ok := r.offers[offer.ID]
+	if !ok || stored.Status != from {
+		return domain.ErrOfferNotPending
+	}
+	now := time.Now()
+	stored.Status = offer.Status
+	stored.BidID = offer.BidID
+	stored.RespondedAt = &now
+	*offer = *stored
+	return nil
+}
+
+func (r *mockOfferRepo) ExpireOpen(ctx context.Context, auctionID uuid.UUID) ([]domain.Offer, error) {
+	expired := make([]domain.Offer, 0)
+	for _, offer := range r.offers {
+		if offer.AuctionID == auctionID &&
+			(offer.Status == domain.OfferStatusPending || offer.Status == domain.OfferStatusCountered) {
+			offer.Status = domain.OfferStatusExpired
+			expired = append(expired, *offer)
+		}
+	}
+	return expired, nil
+}
+
+func (r *mockOfferRepo) ExpireStale(ctx context.Context, now time.Time, limit int) ([]domain.Offer, error) {
+	expired := make([]domain.Offer, 0)
+	for _, offer := range r.offers {
+		if (offer.Status == domain.OfferStatusPending || offer.Status == domain.OfferStatusCountered) &&
+			!offer.ExpiresAt.After(now) && len(expired) < limit {
+			offer.Status = domain.OfferStatusExpired
+			expired = append(expired, *offer)
+		}
+	}
+	return expired, nil
+}
+
+func parseOffer(t *testing.T, response *domain.APIResponse) domain.Offer {
+	data, err := json.Marshal(response.Data)
+	if err != nil {
+		t.Fatalf("Failed to encode response data: %v", err)
+	}
+	var offer domain.Offer
+	if err := json.Unmarshal(data, &offer); err != nil {
+		t.Fatalf("Failed to parse offer: %v", err)
+	}
+	return offer
+}
+
+func TestOfferHandler_MakeOffer(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	offerRepo := newMockOfferRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	sellerID := uuid.New()
+	buyerID := uuid.New()
+	buyNowPrice := decimal.NewFromFloat(500)
+
+	auction := &domain.Auction{
+		SellerID:      sellerID,
+		Title:         "Test Auction Taking Offers",
+		StartingPrice: decimal.NewFromFloat(100),
+		CurrentPrice:  decimal.NewFromFloat(150),
+		BuyNowPrice:   &buyNowPrice,
+		BuyNowRule:    domain.BuyNowAlways,
+		AcceptsOffers: true,
+		BidIncrement:  decimal.NewFromFloat(5),
+		StartTime:     time.Now().Add(-1 * time.Hour),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+		BidCount:      2,
+	}
+	auctionRepo.Create(context.Background(), auction)
+
+	noOffers := &domain.Auction{
+		SellerID:      sellerID,
+		Title:         "Test Auction Without Offers",
+		StartingPrice: decimal.NewFromFloat(100),
+		CurrentPrice:  decimal.NewFromFloat(100),
+		BidIncrement:  decimal.NewFromFloat(5),
+		StartTime:     time.Now().Add(-1 * time.Hour),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+	}
+	auctionRepo.Create(context.Background(), noOffers)
+
+	offerService := service.NewOfferService(
+		offerRepo,
+		auctionRepo,
+		newMockBidRepo(),
+		&mockAuditLogRepo{},
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	offerHandler := handler.NewOfferHandler(offerService)
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/offers", offerHandler.MakeOffer)
+
+	buyerToken, _ := jwtManager.GenerateAccessToken(buyerID, "user")
+	sellerToken, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+
+	tests := []struct {
+		name       string
+		auctionID  uuid.UUID
+		token      string
+		amount     string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "offer on auction without offers", auctionID: noOffers.ID, token: buyerToken, amount: "200", wantStatus: http.StatusBadRequest, wantCode: "OFFERS_DISABLED"},
+		{name: "seller cannot make an offer", auctionID: auction.ID, token: sellerToken, amount: "200", wantStatus: http.StatusBadRequest},
+		{name: "offer not above the high bid", auctionID: auction.ID, token: buyerToken, amount: "150", wantStatus: http.StatusBadRequest, wantCode: "OFFER_TOO_LOW"},
+		{name: "offer at the buy now price", auctionID: auction.ID, token: buyerToken, amount: "500", wantStatus: http.StatusBadRequest, wantCode: "OFFER_ABOVE_BUY_NOW"},
+		{name: "valid offer", auctionID: auction.ID, token: buyerToken, amount: "300", wantStatus: http.StatusCreated},
+		{name: "second open offer", auctionID: auction.ID, token: buyerToken, amount: "320", wantStatus: http.StatusConflict, wantCode: "OFFER_EXISTS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := domain.MakeOfferRequest{Amount: tt.amount}
+			rr := makeRequest(t, r, "POST", "/api/auctions/"+tt.auctionID.String()+"/offers", body, tt.token)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+
+			response := parseResponse(t, rr)
+			if tt.wantCode != "" && (response.Error == nil || response.Error.Code != tt.wantCode) {
+				t.Errorf("expected error code %s, got %+v", tt.wantCode, response.Error)
+			}
+		})
+	}
+}
+
+func TestOfferHandler_CounterAndAccept(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	bidRepo := newMockBidRepo()
+	offerRepo := newMockOfferRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	sellerID := uuid.New()
+	buyerID := uuid.New()
+	otherBuyerID := uuid.New()
+
+	auction := &domain.Auction{
+		SellerID:      sellerID,
+		Title:         "Test Auction Taking Offers",
+		StartingPrice: decimal.NewFromFloat(100),
+		CurrentPrice:  decimal.NewFromFloat(100),
+		AcceptsOffers: true,
+		BidIncrement:  decimal.NewFromFloat(5),
+		StartTime:     time.Now().Add(-1 * time.Hour),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+	}
+	auctionRepo.Create(context.Background(), auction)
+
+	offerService := service.NewOfferService(
+		offerRepo,
+		auctionRepo,
+		bidRepo,
+		&mockAuditLogRepo{},
+		&mockTxManager{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	r := createTestRouter()
+	offerHandler := handler.NewOfferHandler(offerService)
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Post("/api/auctions/{id}/offers", offerHandler.MakeOffer)
+		r.Post("/api/offers/{id}/accept", offerHandler.Accept)
+		r.Post("/api/offers/{id}/counter", offerHandler.Counter)
+	})
+
+	buyerToken, _ := jwtManager.GenerateAccessToken(buyerID, "user")
+	otherBuyerToken, _ := jwtManager.GenerateAccessToken(otherBuyerID, "user")
+	sellerToken, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+
+	rr := makeRequest(t, r, "POST", "/api/auctions/"+auction.ID.String()+"/offers", domain.MakeOfferRequest{Amount: "150"}, buyerToken)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("make offer returned wrong status code: got %v want %v", rr.Code, http.StatusCreated)
+	}
+	offer := parseOffer(t, parseResponse(t, rr))
+
+	rr = makeRequest(t, r, "POST", "/api/auctions/"+auction.ID.String()+"/offers", domain.MakeOfferRequest{Amount: "120"}, otherBuyerToken)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("make second offer returned wrong status code: got %v want %v", rr.Code, http.StatusCreated)
+	}
+	otherOffer := parseOffer(t, parseResponse(t, rr))
+
+	offerPath := "/api/offers/" + offer.ID.String()
+
+	tests := []struct {
+		name       string
+		path       string
+		body       interface{}
+		token      string
+		wantStatus int
+	}{
+		{name: "buyer cannot counter", path: offerPath + "/counter", body: domain.CounterOfferRequest{Amount: "200"}, token: buyerToken, wantStatus: http.StatusForbidden},
+		{name: "counter below the offer", path: offerPath + "/counter", body: domain.CounterOfferRequest{Amount: "140"}, token: sellerToken, wantStatus: http.StatusBadRequest},
+		{name: "buyer cannot accept own pending offer", path: offerPath + "/accept", token: buyerToken, wantStatus: http.StatusBadRequest},
+		{name: "seller counters", path: offerPath + "/counter", body: domain.CounterOfferRequest{Amount: "180"}, token: sellerToken, wantStatus: http.StatusOK},
+		{name: "seller cannot accept own counter", path: offerPath + "/accept", token: sellerToken, wantStatus: http.StatusBadRequest},
+		{name: "other buyer cannot accept", path: offerPath + "/accept", token: otherBuyerToken, wantStatus: http.StatusForbidden},
+		{name: "buyer accepts counter", path: offerPath + "/accept", token: buyerToken, wantStatus: http.StatusOK},
+		{name: "accepting twice", path: offerPath + "/accept", token: buyerToken, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := makeRequest(t, r, "POST", tt.path, tt.body, tt.token)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+		})
+	}
+
+	if auction.Status != domain.AuctionStatusCompleted {
+		t.Errorf("auction status = %s, want %s", auction.Status, domain.AuctionStatusCompleted)
+	}
+	if auction.WinnerID == nil || *auction.WinnerID != buyerID {
+		t.Errorf("auction winner = %v, want %v", auction.WinnerID, buyerID)
+	}
+	if !auction.CurrentPrice.Equal(decimal.NewFromFloat(180)) {
+		t.Errorf("auction price = %s, want 180", auction.CurrentPrice)
+	}
+	if got := offerRepo.offers[otherOffer.ID].Status; got != domain.OfferStatusExpired {
+		t.Errorf("other offer status = %s, want %s", got, domain.OfferStatusExpired)
+	}
+}
@@ -0,0 +1,500 @@
+package handler
+
+//go:generate go run ../../cmd/openapi -o ../../api/openapi.json
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/pkg/openapi"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+// apiRoute documents one route of the HTTP API. Every route registered on
+// the router must have an entry here; the router test enforces it.
+type apiRoute struct {
+	method    string
+	path      string
+	tag       string
+	summary   string
+	auth      bool
+	query     []string
+	request   interface{}
+	multipart bool
+	response  interface{}
+	status    int
+	paginated bool
+}
+
+var messageResponse = struct {
+	Message string `json:"message"`
+}{}
+
+var pageQuery = []string{"page", "limit"}
+
+var apiRoutes = []apiRoute{
+	// Maintenance
+	{method: "GET", path: "/api/maintenance", tag: "maintenance", summary: "Check whether the API is read-only for maintenance", response: domain.MaintenanceStatus{}},
+	{method: "GET", path: "/api/maintenance/windows", tag: "maintenance", summary: "List planned maintenance windows not yet over", response: []domain.MaintenanceWindow{}},
+	{method: "GET", path: "/api/oembed", tag: "embeds", summary: "oEmbed for a link to an auction page or embed card; answers with an iframe of the card. Only format=json is supported", query: []string{"url", "format", "maxwidth", "maxheight"}, response: domain.OEmbed{}},
+	{method: "GET", path: "/embed/auctions/{id}", tag: "embeds", summary: "A public auction's card for other sites: self-contained HTML for an iframe, or JSON with format=json. Any origin may frame or fetch it", query: []string{"format"}, response: domain.AuctionEmbed{}},
+	{method: "GET", path: "/api/status", tag: "maintenance", summary: "Get the health of each part of the service and recent incidents, for a status page", response: domain.ServiceStatus{}},
+
+	// Auth
+	{method: "POST", path: "/api/auth/register", tag: "auth", summary: "Register a new account", request: domain.RegisterRequest{}, status: http.StatusCreated,
+		response: struct {
+			Message string             `json:"message"`
+			User    *domain.PublicUser `json:"user"`
+		}{}},
+	{method: "POST", path: "/api/auth/login", tag: "auth", summary: "Log in with email and password", request: domain.LoginRequest{}, response: domain.AuthResponse{}},
+	{method: "POST", path: "/api/auth/login/verify", tag: "auth", summary: "Finish logging in on an untrusted device with the emailed code", request: domain.VerifyLoginRequest{}, response: domain.AuthResponse{}},
+	{method: "POST", path: "/api/auth/logout", tag: "auth", summary: "Log out and revoke the refresh token", response: messageResponse},
+	{method: "POST", path: "/api/auth/refresh", tag: "auth", summary: "Exchange the refresh token cookie for an access token",
+		response: struct {
+			AccessToken string `json:"access_token"`
+		}{}},
+	{method: "POST", path: "/api/auth/verify-email", tag: "auth", summary: "Verify an email address", request: domain.VerifyEmailRequest{}, response: messageResponse},
+	{method: "POST", path: "/api/auth/resend-verification", tag: "auth", summary: "Request a new email verification link", request: domain.ResendVerificationRequest{}, response: messageResponse},
+	{method: "POST", path: "/api/auth/forgot-password", tag: "auth", summary: "Request a password reset email", request: domain.ForgotPasswordRequest{}, response: messageResponse},
+	{method: "POST", path: "/api/auth/reset-password", tag: "auth", summary: "Reset a password with a reset token", request: domain.ResetPasswordRequest{}, response: messageResponse},
+	{method: "GET", path: "/api/auth/{provider}", tag: "auth", summary: "Start OAuth login with google, github or apple", status: http.StatusTemporaryRedirect},
+	{method: "GET", path: "/api/auth/{provider}/callback", tag: "auth", summary: "OAuth callback", query: []string{"state", "code"}, status: http.StatusSeeOther},
+	{method: "POST", path: "/api/auth/{provider}/callback", tag: "auth", summary: "OAuth callback for providers that use form_post", status: http.StatusSeeOther},
+
+	// Categories
+	{method: "GET", path: "/api/categories", tag: "categories", summary: "List categories, translated per lang or Accept-Language", query: []string{"lang"}, response: []domain.Category{}},
+	{method: "GET", path: "/api/categories/{slug}", tag: "categories", summary: "Get a category by slug, translated per lang or Accept-Language", query: []string{"lang"}, response: domain.Category{}},
+	{method: "GET", path: "/api/categories/{slug}/rules", tag: "categories", summary: "Get the defaults a category gives new drafts and what its auctions need to be published", response: domain.CategoryListingRules{}},
+
+	// Auctions
+	{method: "GET", path: "/api/auctions", tag: "auctions", summary: "Search auctions", paginated: true, response: []domain.Auction{},
+		query: []string{"page", "limit", "sort", "status", "category_id", "seller_id", "search", "min_price", "max_price",
+			"ends_within", "has_buy_now", "condition", "has_reserve", "free_shipping", "min_seller_rating", "seed"}},
+	{method: "GET", path: "/api/auctions/facets", tag: "auctions", summary: "Count matching auctions by category, condition and price", response: domain.AuctionFacets{},
+		query: []string{"status", "category_id", "seller_id", "search", "min_price", "max_price",
+			"ends_within", "has_buy_now", "condition", "has_reserve", "free_shipping", "min_seller_rating"}},
+	{method: "GET", path: "/api/auctions/sold", tag: "auctions", summary: "Browse past sales with price statistics; sold_to given as a day includes that day", paginated: true, response: domain.SoldListingResponse{},
+		query: []string{"page", "limit", "sort", "category_id", "search", "condition", "sold_from", "sold_to", "min_price", "max_price"}},
+	{method: "POST", path: "/api/auctions", tag: "auctions", summary: "Create a draft auction", auth: true, request: domain.CreateAuctionRequest{}, response: domain.Auction{}, status: http.StatusCreated},
+	{method: "GET", path: "/api/auctions/end-time-suggestion", tag: "auctions", summary: "Suggest a less busy end time", auth: true, query: []string{"end_time"}, response: domain.EndTimeSuggestion{}},
+	{method: "POST", path: "/api/auctions/import", tag: "auctions", summary: "Import draft auctions from a CSV or JSON file; processed in the background", auth: true, multipart: true, status: http.StatusAccepted, response: domain.ImportJob{},
+		request: struct {
+			File   openapi.File `json:"file" validate:"required"`
+			Format string       `json:"format,omitempty" validate:"omitempty,oneof=csv json"`
+		}{}},
+	{method: "GET", path: "/api/auctions/imports", tag: "auctions", summary: "List the current user's recent imports", auth: true, response: []domain.ImportJob{}},
+	{method: "GET", path: "/api/auctions/imports/{id}", tag: "auctions", summary: "Get an import's progress", auth: true, response: domain.ImportJob{}},
+	{method: "GET", path: "/api/auctions/imports/{id}/report", tag: "auctions", summary: "Download an import's per-row report as CSV, or JSON with format=json", auth: true, query: []string{"format"}, response: []domain.ImportRowResult{}},
+	{method: "POST", path: "/api/auctions/batch-get", tag: "auctions", summary: "Get up to 100 auctions", request: domain.BatchGetRequest{}, response: domain.AuctionBatchResponse{}},
+	{method: "GET", path: "/api/auctions/featured", tag: "auctions", summary: "List the auctions featured on the homepage, in order", response: []domain.Auction{}},
+	{method: "GET", path: "/api/auctions/trending", tag: "auctions", summary: "List the auctions with the most views, watches and bids in the last day", query: []string{"limit"}, response: []domain.Auction{}},
+	{method: "GET", path: "/api/auctions/{id}", tag: "auctions", summary: "Get an auction; private auctions need an invite or a share link token", query: []string{"share", "lang"}, response: domain.Auction{}},
+	{method: "PUT", path: "/api/auctions/{id}", tag: "auctions", summary: "Update an auction", auth: true, request: domain.UpdateAuctionRequest{}, response: domain.Auction{}},
+	{method: "DELETE", path: "/api/auctions/{id}", tag: "auctions", summary: "Delete an auction", auth: true, response: messageResponse},
+	{method: "POST", path: "/api/auctions/{id}/publish", tag: "auctions", summary: "Publish a draft auction", auth: true, response: domain.Auction{}},
+	{method: "GET", path: "/api/auctions/{id}/factsheet.pdf", tag: "auctions", summary: "Get a printable PDF fact sheet; redirects to it once rendered and answers 202 until then", query: []string{"share"}, response: domain.AuctionFactsheet{}, status: http.StatusAccepted},
+	{method: "GET", path: "/api/auctions/{id}/similar", tag: "auctions", summary: "List active auctions similar to an auction", query: []string{"limit", "share"}, response: []domain.Auction{}},
+	{method: "GET", path: "/api/auctions/{id}/price-history", tag: "auctions", summary: "List the buy-now price drops of an auction; its seller also gets the drop rule", query: []string{"share"}, response: domain.PriceHistory{}},
+	{method: "GET", path: "/api/auctions/{id}/stats", tag: "auctions", summary: "Get seller statistics for an auction", auth: true, response: domain.AuctionStats{}},
+	{method: "GET", path: "/api/auctions/{id}/invites", tag: "auctions", summary: "List the users invited to a private auction", auth: true, response: []domain.AuctionInvite{}},
+	{method: "POST", path: "/api/auctions/{id}/invites", tag: "auctions", summary: "Invite a user to a private auction", auth: true, request: domain.InviteUserRequest{}, response: domain.AuctionInvite{}, status: http.StatusCreated},
+	{method: "DELETE", path: "/api/auctions/{id}/invites/{userId}", tag: "auctions", summary: "Withdraw a user's invite", auth: true, response: messageResponse},
+	{method: "POST", path: "/api/auctions/{id}/share-link", tag: "auctions", summary: "Create a signed link that opens a private auction", auth: true, response: domain.ShareLink{}, status: http.StatusCreated},
+	{method: "DELETE", path: "/api/auctions/{id}/share-link", tag: "auctions", summary: "Revoke every share link of an auction", auth: true, response: messageResponse},
+	{method: "POST", path: "/api/auctions/{id}/shares", tag: "auctions", summary: "Get the current user's short link for sharing an auction", auth: true, response: domain.ListingShare{}},
+	{method: "POST", path: "/api/auctions/{id}/images", tag: "auctions", summary: "Upload an auction image", auth: true, multipart: true, status: http.StatusCreated, response: domain.AuctionImage{},
+		request: struct {
+			Image openapi.File `json:"image" validate:"required"`
+		}{}},
+	{method: "POST", path: "/api/auctions/{id}/images/bulk", tag: "auctions", summary: "Upload several auction images", auth: true, multipart: true, status: http.StatusCreated, response: []domain.AuctionImage{},
+		request: struct {
+			Images []openapi.File `json:"images" validate:"required"`
+		}{}},
+	{method: "DELETE", path: "/api/auctions/{id}/images/{imageId}", tag: "auctions", summary: "Delete an auction image", auth: true, response: messageResponse},
+
+	// Bids
+	{method: "GET", path: "/api/auctions/{id}/bids", tag: "bids", summary: "List bids on an auction; private auctions need an invite or a share link token", query: []string{"page", "limit", "cursor", "share"}, paginated: true, response: []domain.Bid{}},
+	{method: "POST", path: "/api/auctions/{id}/bids", tag: "bids", summary: "Place a bid", auth: true, request: domain.PlaceBidRequest{}, response: domain.BidResponse{}, status: http.StatusCreated},
+	{method: "POST", path: "/api/auctions/{id}/buy-now", tag: "bids", summary: "Buy an auction at its buy-now price", auth: true, response: domain.BidResponse{}},
+	{method: "POST", path: "/api/auctions/{id}/bid-approvals", tag: "bids", summary: "Ask the seller for approval to bid, answering their questions", auth: true, request: domain.RequestBidApprovalRequest{}, response: domain.BidApproval{}, status: http.StatusCreated},
+	{method: "GET", path: "/api/auctions/{id}/bid-approvals", tag: "bids", summary: "List requests to bid on the seller's auction", auth: true, query: []string{"status"}, response: []domain.BidApproval{}},
+	{method: "GET", path: "/api/auctions/{id}/bid-approvals/me", tag: "bids", summary: "Get the current user's request to bid", auth: true, response: domain.BidApproval{}},
+	{method: "PUT", path: "/api/auctions/{id}/bid-approvals/{userId}", tag: "bids", summary: "Approve or deny a user's request to bid", auth: true, request: domain.DecideBidApprovalRequest{}, response: domain.BidApproval{}},
+	{method: "POST", path: "/api/auctions/{id}/bids/{bidId}/cancel", tag: "bids", summary: "Cancel a bid", auth: true, request: domain.CancelBidRequest{}, response: domain.Auction{}},
+	{method: "POST", path: "/api/auctions/{id}/cancel", tag: "bids", summary: "Cancel an auction and void its bids (not in the final 12 hours)", auth: true, request: domain.CancelAuctionRequest{}, response: domain.Auction{}},
+	{method: "PUT", path: "/api/auctions/{id}/price-drop", tag: "auctions", summary: "Mark the buy-now price down by a percentage every few days until a floor price", auth: true, request: domain.SetPriceDropRuleRequest{}, response: domain.PriceDropRule{}},
+	{method: "DELETE", path: "/api/auctions/{id}/price-drop", tag: "auctions", summary: "Stop scheduled buy-now price drops", auth: true, response: messageResponse},
+	{method: "GET", path: "/api/auctions/{id}/seller-alerts", tag: "auctions", summary: "Get which bids on the seller's listing they are notified of", auth: true, response: domain.SellerBidAlerts{}},
+	{method: "PUT", path: "/api/auctions/{id}/seller-alerts", tag: "auctions", summary: "Choose to be notified on the first bid, when the reserve is met, every N bids or when the price passes an amount", auth: true, request: domain.SetSellerBidAlertsRequest{}, response: domain.SellerBidAlerts{}},
+	{method: "DELETE", path: "/api/auctions/{id}/seller-alerts", tag: "auctions", summary: "Go back to being notified of every bid", auth: true, response: domain.SellerBidAlerts{}},
+	{method: "PUT", path: "/api/auctions/{id}/reserve", tag: "bids", summary: "Lower the reserve price", auth: true, request: domain.LowerReserveRequest{}, response: domain.Auction{}},
+	{method: "POST", path: "/api/auctions/{id}/snipe", tag: "bids", summary: "Schedule a last-second bid", auth: true, request: domain.ScheduleSnipeRequest{}, response: domain.Snipe{}, status: http.StatusCreated},
+	{method: "DELETE", path: "/api/auctions/{id}/snipe", tag: "bids", summary: "Cancel a scheduled bid", auth: true, response: messageResponse},
+	{method: "POST", path: "/api/auctions/{id}/offers", tag: "offers", summary: "Make an offer", auth: true, request: domain.MakeOfferRequest{}, response: domain.Offer{}, status: http.StatusCreated},
+	{method: "GET", path: "/api/auctions/{id}/offers", tag: "offers", summary: "List offers on an auction; buyers only see their own", auth: true, response: []domain.Offer{}},
+
+	// Live events
+	{method: "POST", path: "/api/live-events", tag: "live-events", summary: "Schedule a live event running the user's draft auctions as lots", auth: true, request: domain.CreateLiveEventRequest{}, response: domain.LiveEvent{}, status: http.StatusCreated},
+	{method: "GET", path: "/api/live-events", tag: "live-events", summary: "List live events that are scheduled or running", response: []domain.LiveEvent{}},
+	{method: "GET", path: "/api/live-events/{id}", tag: "live-events", summary: "Get a live event with its lots", response: domain.LiveEvent{}},
+
+	// Short links
+	{method: "GET", path: "/api/links/{code}", tag: "auctions", summary: "Resolve a short link to the screen it opens, counting the visit", response: domain.DeepLink{}},
+
+	// Users
+	{method: "GET", path: "/api/users/me", tag: "users", summary: "Get the current user", auth: true, response: domain.User{}},
+	{method: "PUT", path: "/api/users/me", tag: "users", summary: "Update the current user's profile", auth: true, request: domain.UpdateProfileRequest{}, response: domain.User{}},
+	{method: "PUT", path: "/api/users/me/username", tag: "users", summary: "Choose a username, completing a provider signup", auth: true, request: domain.ChooseUsernameRequest{}, response: domain.User{}},
+	{method: "PUT", path: "/api/users/me/vacation", tag: "users", summary: "Turn on vacation mode", auth: true, request: domain.SetVacationRequest{}, response: domain.VacationResponse{}},
+	{method: "DELETE", path: "/api/users/me/vacation", tag: "users", summary: "Turn off vacation mode", auth: true, response: messageResponse},
+	{method: "GET", path: "/api/users/me/notification-settings", tag: "users", summary: "Get whether the current user gets each notification type by email, in the app and by push", auth: true, response: domain.NotificationSettings{}},
+	{method: "PUT", path: "/api/users/me/notification-settings", tag: "users", summary: "Switch notification channels on or off per type; channels left out are kept", auth: true, request: domain.UpdateNotificationSettingsRequest{}, response: domain.NotificationSettings{}},
+	{method: "GET", path: "/api/users/me/blocks", tag: "users", summary: "List the users the current user has blocked, newest first", auth: true, query: []string{"page", "limit"}, paginated: true, response: []domain.UserBlock{}},
+	{method: "POST", path: "/api/users/me/blocks", tag: "users", summary: "Block a user from bidding on the current user's auctions or messaging them", auth: true, request: domain.BlockUserRequest{}, response: domain.UserBlock{}, status: http.StatusCreated},
+	{method: "DELETE", path: "/api/users/me/blocks/{userId}", tag: "users", summary: "Unblock a user", auth: true, response: messageResponse},
+	{method: "GET", path: "/api/users/me/following", tag: "users", summary: "List the sellers the current user follows, newest first", auth: true, query: pageQuery, paginated: true, response: []domain.SellerFollow{}},
+	{method: "PUT", path: "/api/users/me/following/{userId}", tag: "users", summary: "Follow a seller", auth: true, response: domain.SellerFollow{}},
+	{method: "DELETE", path: "/api/users/me/following/{userId}", tag: "users", summary: "Stop following a seller", auth: true, response: messageResponse},
+	{method: "GET", path: "/api/users/me/push-subscriptions", tag: "users", summary: "List the browsers the current user subscribed to web push, with the VAPID public key to subscribe with", auth: true, response: domain.PushSubscriptionsResponse{}},
+	{method: "POST", path: "/api/users/me/push-subscriptions", tag: "users", summary: "Subscribe a browser to web push for outbid, ending soon and won notifications", auth: true, request: domain.CreatePushSubscriptionRequest{}, response: domain.PushSubscription{}, status: http.StatusCreated},
+	{method: "DELETE", path: "/api/users/me/push-subscriptions/{id}", tag: "users", summary: "Unsubscribe a browser from web push", auth: true, response: messageResponse},
+	{method: "GET", path: "/api/users/me/saved-searches", tag: "users", summary: "List the current user's saved searches, whose new listings come in their digest", auth: true, response: []domain.SavedSearch{}},
+	{method: "POST", path: "/api/users/me/saved-searches", tag: "users", summary: "Save a search so new listings matching it come in the user's digest", auth: true, request: domain.CreateSavedSearchRequest{}, response: domain.SavedSearch{}, status: http.StatusCreated},
+	{method: "DELETE", path: "/api/users/me/saved-searches/{id}", tag: "users", summary: "Delete a saved search", auth: true, response: messageResponse},
+	{method: "PUT", path: "/api/users/me/session-policy", tag: "users", summary: "Limit the current user to one active session", auth: true, request: domain.SetSessionPolicyRequest{}, response: domain.SessionPolicyResponse{}},
+	{method: "PUT", path: "/api/users/me/two-factor", tag: "users", summary: "Require an emailed code when logging in on an untrusted device", auth: true, request: domain.SetTwoFactorRequest{}, response: domain.User{}},
+	{method: "GET", path: "/api/users/me/devices", tag: "users", summary: "List devices the current user has signed in from", auth: true, response: []domain.Device{}},
+	{method: "PUT", path: "/api/users/me/devices/{id}", tag: "users", summary: "Rename or trust a device", auth: true, request: domain.UpdateDeviceRequest{}, response: domain.Device{}},
+	{method: "DELETE", path: "/api/users/me/devices/{id}", tag: "users", summary: "Forget a device and sign it out", auth: true, response: messageResponse},
+	{method: "GET", path: "/api/users/me/login-history", tag: "users", summary: "List the current user's recent sign-ins", auth: true, response: []domain.LoginEvent{}},
+	{method: "POST", path: "/api/users/me/login-history/{id}/report", tag: "users", summary: "Report a sign-in as not yours, revoking sessions and requiring a password reset", auth: true, response: messageResponse},
+	{method: "GET", path: "/api/users/me/sign-in-methods", tag: "users", summary: "List the current user's password and linked provider logins", auth: true, response: domain.SignInMethods{}},
+	{method: "POST", path: "/api/users/me/password", tag: "users", summary: "Add a password to an OAuth-only account", auth: true, request: domain.SetPasswordRequest{}, response: messageResponse},
+	{method: "POST", path: "/api/users/me/oauth/{provider}/link", tag: "users", summary: "Start linking a provider login; returns the provider URL to visit", auth: true, request: domain.ReauthRequest{}, response: domain.OAuthLinkResponse{}},
+	{method: "DELETE", path: "/api/users/me/oauth/{provider}", tag: "users", summary: "Remove a linked provider login", auth: true, request: domain.ReauthRequest{}, response: messageResponse},
+	{method: "POST", path: "/api/users/me/oauth/confirm-link", tag: "users", summary: "Confirm linking the provider login that matched this account by an unverified email", auth: true, request: domain.ReauthRequest{}, response: domain.OAuthAccount{}},
+	{method: "GET", path: "/api/users/me/balance", tag: "users", summary: "Get the current user's seller balance, payments still held in escrow and payouts in progress", auth: true, response: domain.Balance{}},
+	{method: "GET", path: "/api/users/me/payout-method", tag: "users", summary: "Get where the current user's payouts are sent", auth: true, response: domain.PayoutMethod{}},
+	{method: "PUT", path: "/api/users/me/payout-method", tag: "users", summary: "Set an IBAN or Stripe Connect account for payouts", auth: true, request: domain.SetPayoutMethodRequest{}, response: domain.PayoutMethod{}},
+	{method: "GET", path: "/api/users/me/payouts", tag: "users", summary: "List the current user's payouts, newest first", auth: true, query: pageQuery, paginated: true, response: []domain.Payout{}},
+	{method: "POST", path: "/api/users/me/payouts", tag: "users", summary: "Request a payout from the available balance", auth: true, request: domain.RequestPayoutRequest{}, response: domain.Payout{}, status: http.StatusCreated},
+	{method: "GET", path: "/api/users/me/export", tag: "users", summary: "Request a copy of the current user's data; 202 until the archive is ready, then 200 with a download link that is also emailed", auth: true, status: http.StatusAccepted, response: domain.DataExport{}},
+	{method: "DELETE", path: "/api/users/me", tag: "users", summary: "Delete the current user's account, anonymizing what other users still need", auth: true, request: domain.ReauthRequest{}, response: messageResponse},
+	{method: "GET", path: "/api/users/me/recommended", tag: "users", summary: "List active auctions recommended from the current user's bids and watchlist", auth: true, query: []string{"limit"}, response: []domain.Auction{}},
+	{method: "GET", path: "/api/users/me/bids", tag: "users", summary: "List the current user's bids", auth: true, query: pageQuery, paginated: true, response: []domain.Bid{}},
+	{method: "GET", path: "/api/users/me/snipes", tag: "users", summary: "List the current user's scheduled bids", auth: true, response: []domain.Snipe{}},
+	{method: "GET", path: "/api/users/me/api-usage", tag: "users", summary: "Get how much of each per-user rate limit the current user has used, now and by day over a window of 1d, 7d or 30d", auth: true, query: []string{"window"}, response: domain.APIUsage{}},
+	{method: "POST", path: "/api/users/batch-get", tag: "users", summary: "Get up to 100 public profiles", request: domain.BatchGetRequest{}, response: domain.UserBatchResponse{}},
+	{method: "GET", path: "/api/users/{id}", tag: "users", summary: "Get a public profile", response: domain.UserProfile{}},
+	{method: "GET", path: "/api/users/{id}/auctions", tag: "users", summary: "List a user's auctions", query: pageQuery, paginated: true, response: []domain.Auction{}},
+	{method: "GET", path: "/api/users/{id}/ratings", tag: "users", summary: "List a user's ratings", query: []string{"page", "limit", "type"}, paginated: true, response: []domain.Rating{}},
+	{method: "GET", path: "/api/users/{id}/presence", tag: "messaging", summary: "Get whether a user is online", auth: true, response: domain.UserPresence{}},
+	{method: "PUT", path: "/api/users/me/keys", tag: "messaging", summary: "Register a device public key for E2E messaging", auth: true, request: domain.RegisterDeviceKeyRequest{}, response: domain.DeviceKey{}},
+	{method: "DELETE", path: "/api/users/me/keys/{keyId}", tag: "messaging", summary: "Revoke a device key", auth: true, response: messageResponse},
+	{method: "GET", path: "/api/users/{id}/keys", tag: "messaging", summary: "List a user's device public keys", auth: true, response: []domain.DeviceKey{}},
+
+	// Watchlist
+	{method: "GET", path: "/api/watchlist", tag: "watchlist", summary: "List watched auctions", auth: true, query: pageQuery, paginated: true, response: []domain.WatchlistItem{}},
+	{method: "POST", path: "/api/watchlist/import/ebay", tag: "watchlist", summary: "Import an eBay watchlist CSV export, watching the active listings most like its items, or a saved sellers export, following users with the same usernames; returns a report per row", auth: true, multipart: true, response: domain.EbayImportReport{},
+		request: struct {
+			File openapi.File `json:"file" validate:"required"`
+		}{}},
+	{method: "POST", path: "/api/watchlist/{auctionId}", tag: "watchlist", summary: "Watch an auction", auth: true, response: messageResponse, status: http.StatusCreated},
+	{method: "DELETE", path: "/api/watchlist/{auctionId}", tag: "watchlist", summary: "Stop watching an auction", auth: true, response: messageResponse},
+
+	// Notifications
+	{method: "GET", path: "/api/notifications", tag: "notifications", summary: "List notifications, or with grouped=true one thread per auction with its newest notification and counts", auth: true, query: []string{"page", "limit", "unread", "cursor", "grouped"}, paginated: true,
+		response: struct {
+			Notifications []domain.Notification       `json:"notifications,omitempty"`
+			Threads       []domain.NotificationThread `json:"threads,omitempty"`
+			UnreadCount   int                         `json:"unread_count"`
+		}{}},
+	{method: "PUT", path: "/api/notifications/{id}/read", tag: "notifications", summary: "Mark a notification read", auth: true, response: messageResponse},
+	{method: "PUT", path: "/api/notifications/threads/{id}/read", tag: "notifications", summary: "Mark every notification in a thread read", auth: true, response: messageResponse},
+	{method: "GET", path: "/api/notifications/quiet-hours", tag: "notifications", summary: "Get the hours, in the user's timezone, when their email and push notifications are held for a morning digest", auth: true, response: domain.QuietHours{}},
+	{method: "PUT", path: "/api/notifications/quiet-hours", tag: "notifications", summary: "Set quiet hours, and whether critical notifications such as a won auction break through", auth: true, request: domain.SetQuietHoursRequest{}, response: domain.QuietHours{}},
+	{method: "GET", path: "/api/notifications/digest", tag: "notifications", summary: "Get whether and when, in the user's timezone, they get a daily or weekly digest email, and what goes in it", auth: true, response: domain.DigestPreferences{}},
+	{method: "PUT", path: "/api/notifications/digest", tag: "notifications", summary: "Set the digest's frequency, hour, weekday and sections; watchers who get watched auctions in it stop getting an email for each about to end", auth: true, request: domain.UpdateDigestPreferencesRequest{}, response: domain.DigestPreferences{}},
+	{method: "GET", path: "/api/notifications/digest/preview", tag: "notifications", summary: "Show what the current user's digest would hold if it went out now", auth: true, response: domain.Digest{}},
+	{method: "PUT", path: "/api/notifications/read-all", tag: "notifications", summary: "Mark all notifications read", auth: true, response: messageResponse},
+	{method: "POST", path: "/api/notifications/unsubscribe", tag: "notifications", summary: "Stop one type of email with the token from its unsubscribe link, without signing in", request: domain.UnsubscribeRequest{}, response: domain.NotificationPreference{}},
+
+	// Orders
+	{method: "GET", path: "/api/orders", tag: "orders", summary: "List orders", auth: true, query: []string{"page", "limit", "role", "status"}, paginated: true, response: []domain.Order{}},
+	{method: "GET", path: "/api/orders/{id}", tag: "orders", summary: "Get an order", auth: true, response: domain.Order{}},
+	{method: "POST", path: "/api/orders/{id}/mark-paid", tag: "orders", summary: "Mark an order paid; the payment is held in escrow", auth: true, response: domain.Order{}},
+	{method: "POST", path: "/api/orders/{id}/confirm-delivery", tag: "orders", summary: "Confirm delivery as the buyer, releasing the held payment to the seller", auth: true, response: domain.EscrowHold{}},
+	{method: "GET", path: "/api/orders/{id}/disputes", tag: "disputes", summary: "List disputes for an order", auth: true, response: []domain.Dispute{}},
+	{method: "POST", path: "/api/orders/{id}/disputes", tag: "disputes", summary: "Open a dispute", auth: true, request: domain.OpenDisputeRequest{}, response: domain.Dispute{}, status: http.StatusCreated},
+	{method: "GET", path: "/api/second-chance-offers", tag: "orders", summary: "List second-chance offers", auth: true, query: []string{"status"}, response: []domain.SecondChanceOffer{}},
+	{method: "POST", path: "/api/second-chance-offers/{id}/accept", tag: "orders", summary: "Accept a second-chance offer", auth: true, response: domain.Order{}, status: http.StatusCreated},
+	{method: "POST", path: "/api/second-chance-offers/{id}/decline", tag: "orders", summary: "Decline a second-chance offer", auth: true, response: messageResponse},
+
+	// Best offers
+	{method: "GET", path: "/api/offers", tag: "offers", summary: "List offers the current user made", auth: true, query: []string{"status"}, response: []domain.Offer{}},
+	{method: "POST", path: "/api/offers/{id}/accept", tag: "offers", summary: "Accept an offer or counter, ending the auction", auth: true, response: domain.Offer{}},
+	{method: "POST", path: "/api/offers/{id}/decline", tag: "offers", summary: "Decline an offer or counter", auth: true, response: domain.Offer{}},
+	{method: "POST", path: "/api/offers/{id}/counter", tag: "offers", summary: "Counter an offer", auth: true, request: domain.CounterOfferRequest{}, response: domain.Offer{}},
+
+	{method: "GET", path: "/api/webhooks", tag: "webhooks", summary: "List the current user's webhooks", auth: true, response: []domain.Webhook{}},
+	{method: "POST", path: "/api/webhooks", tag: "webhooks", summary: "Register a webhook; the signing secret is only returned here", auth: true, request: domain.CreateWebhookRequest{}, response: domain.WebhookSecretResponse{}, status: http.StatusCreated},
+	{method: "GET", path: "/api/webhooks/{id}", tag: "webhooks", summary: "Get a webhook", auth: true, response: domain.Webhook{}},
+	{method: "PUT", path: "/api/webhooks/{id}", tag: "webhooks", summary: "Change a webhook's URL or events, or pause it", auth: true, request: domain.UpdateWebhookRequest{}, response: domain.Webhook{}},
+	{method: "DELETE", path: "/api/webhooks/{id}", tag: "webhooks", summary: "Delete a webhook", auth: true, response: messageResponse},
+	{method: "POST", path: "/api/webhooks/{id}/secret", tag: "webhooks", summary: "Rotate a webhook's signing secret", auth: true, response: domain.WebhookSecretResponse{}},
+	{method: "GET", path: "/api/webhooks/{id}/deliveries", tag: "webhooks", summary: "List a webhook's deliveries, e.g. the failed ones", auth: true, query: []string{"page", "limit", "status"}, paginated: true, response: []domain.WebhookDelivery{}},
+	{method: "POST", path: "/api/webhooks/{id}/deliveries/{deliveryId}/retry", tag: "webhooks", summary: "Send a failed delivery again", auth: true, response: messageResponse},
+
+	// Disputes
+	{method: "GET", path: "/api/disputes/{id}", tag: "disputes", summary: "Get a dispute", auth: true, response: domain.Dispute{}},
+	{method: "POST", path: "/api/disputes/{id}/messages", tag: "disputes", summary: "Add a message to a dispute", auth: true, request: domain.DisputeMessageRequest{}, response: domain.DisputeMessage{}, status: http.StatusCreated},
+	{method: "POST", path: "/api/disputes/{id}/evidence", tag: "disputes", summary: "Upload dispute evidence", auth: true, multipart: true, response: domain.DisputeEvidence{}, status: http.StatusCreated,
+		request: struct {
+			File        openapi.File `json:"file" validate:"required"`
+			Description string       `json:"description,omitempty"`
+		}{}},
+
+	// Ratings
+	{method: "POST", path: "/api/ratings/auction/{auctionId}", tag: "users", summary: "Rate the other party of an auction", auth: true, request: domain.CreateRatingRequest{}, response: domain.Rating{}, status: http.StatusCreated},
+
+	// Messaging
+	{method: "POST", path: "/api/messages", tag: "messaging", summary: "Send a message", auth: true, request: domain.SendMessageRequest{}, response: domain.SendMessageResponse{}, status: http.StatusCreated},
+	{method: "GET", path: "/api/messages/unread-count", tag: "messaging", summary: "Get unread message counts", auth: true, response: domain.UnreadCountResponse{}},
+	{method: "GET", path: "/api/conversations", tag: "messaging", summary: "List conversations", auth: true, response: domain.ConversationsResponse{}},
+	{method: "GET", path: "/api/conversations/{id}", tag: "messaging", summary: "Get a conversation", auth: true, response: domain.ConversationWithDetails{}},
+	{method: "GET", path: "/api/conversations/{id}/messages", tag: "messaging", summary: "List messages in a conversation", auth: true,
+		query: []string{"page", "limit", "before", "after", "anchor"}, paginated: true, response: domain.MessagesResponse{}},
+	{method: "PUT", path: "/api/conversations/{id}/read", tag: "messaging", summary: "Mark a conversation read", auth: true, response: messageResponse},
+
+	// Admin
+	{method: "GET", path: "/api/admin/dashboard", tag: "admin", summary: "Get dashboard totals", auth: true, response: map[string]interface{}{}},
+	{method: "GET", path: "/api/admin/users", tag: "admin", summary: "List users", auth: true, query: pageQuery, paginated: true, response: []domain.User{}},
+	{method: "PUT", path: "/api/admin/users/{id}/ban", tag: "admin", summary: "Ban or unban a user", auth: true, response: messageResponse,
+		request: struct {
+			Ban bool `json:"ban"`
+		}{}},
+	{method: "DELETE", path: "/api/admin/users/{id}", tag: "admin", summary: "Delete a user; they can no longer sign in and show as a deleted user, with their data kept until purged", auth: true, response: messageResponse},
+	{method: "POST", path: "/api/admin/users/purge-deleted", tag: "admin", summary: "Anonymize users deleted longer ago than the retention period", auth: true, response: domain.UserPurgeResult{}},
+	{method: "POST", path: "/api/admin/users/{id}/merge", tag: "admin", summary: "Merge a duplicate account into this one", auth: true, request: domain.MergeUsersRequest{}, response: domain.UserMergeReport{}},
+	{method: "GET", path: "/api/admin/auctions", tag: "admin", summary: "List all auctions", auth: true, query: []string{"page", "limit", "sort", "status", "search", "cert_status"}, paginated: true, response: []domain.Auction{}},
+	{method: "PUT", path: "/api/admin/auctions/{id}/status", tag: "admin", summary: "Change an auction's status", auth: true, response: messageResponse,
+		request: struct {
+			Status string `json:"status"`
+		}{}},
+	{method: "POST", path: "/api/admin/auctions/{id}/force-end", tag: "admin", summary: "End an auction early", auth: true, request: domain.ForceEndAuctionRequest{}, response: domain.Auction{}},
+	{method: "GET", path: "/api/admin/auctions/{id}/bid-audit", tag: "admin", summary: "Get an auction's full bid trail with where each bid came from, or CSV with format=csv", auth: true, query: []string{"format"}, response: domain.BidAudit{}},
+	{method: "POST", path: "/api/admin/auctions/{id}/verify-certification", tag: "admin", summary: "Verify an auction's grading certification again", auth: true, response: domain.Certification{}},
+	{method: "POST", path: "/api/admin/categories", tag: "admin", summary: "Create a category", auth: true, request: domain.CreateCategoryRequest{}, response: domain.Category{}, status: http.StatusCreated},
+	{method: "PUT", path: "/api/admin/categories/{id}", tag: "admin", summary: "Update a category", auth: true, request: domain.UpdateCategoryRequest{}, response: domain.Category{}},
+	{method: "DELETE", path: "/api/admin/categories/{id}", tag: "admin", summary: "Delete a category", auth: true, response: messageResponse},
+	{method: "GET", path: "/api/admin/categories/{id}/translations", tag: "admin", summary: "List a category's translations", auth: true, response: []domain.CategoryTranslation{}},
+	{method: "PUT", path: "/api/admin/categories/{id}/translations/{lang}", tag: "admin", summary: "Add or replace a category's name and description in a language", auth: true, request: domain.SetCategoryTranslationRequest{}, response: domain.CategoryTranslation{}},
+	{method: "DELETE", path: "/api/admin/categories/{id}/translations/{lang}", tag: "admin", summary: "Remove a category's translation", auth: true, response: messageResponse},
+	{method: "GET", path: "/api/admin/categories/{id}/rules", tag: "admin", summary: "Get a category's listing rules", auth: true, response: domain.CategoryListingRules{}},
+	{method: "PUT", path: "/api/admin/categories/{id}/rules", tag: "admin", summary: "Replace a category's listing defaults and publishing requirements", auth: true, request: domain.SetCategoryListingRulesRequest{}, response: domain.CategoryListingRules{}},
+	{method: "DELETE", path: "/api/admin/categories/{id}/rules", tag: "admin", summary: "Remove a category's listing rules", auth: true, response: messageResponse},
+	{method: "GET", path: "/api/admin/reports", tag: "admin", summary: "List reported listings", auth: true, query: []string{"page", "limit", "status"}, paginated: true, response: []domain.ReportedListing{}},
+	{method: "PUT", path: "/api/admin/reports/{id}", tag: "admin", summary: "Update a report", auth: true, request: domain.UpdateReportRequest{}, response: domain.ReportedListing{}},
+	{method: "GET", path: "/api/admin/moderation-queue", tag: "admin", summary: "List users escalated for flagged messages", auth: true, query: []string{"page", "limit", "status"}, paginated: true, response: []domain.ModerationCase{}},
+	{method: "PUT", path: "/api/admin/moderation-queue/{id}", tag: "admin", summary: "Close a moderation case", auth: true, request: domain.UpdateModerationCaseRequest{}, response: domain.ModerationCase{}},
+	{method: "GET", path: "/api/admin/featured", tag: "admin", summary: "List featured auctions, expired ones included", auth: true, response: []domain.FeaturedAuction{}},
+	{method: "PUT", path: "/api/admin/featured/{auctionId}", tag: "admin", summary: "Feature an active auction on the homepage, or change its position and expiry", auth: true, request: domain.FeatureAuctionRequest{}, response: domain.FeaturedAuction{}},
+	{method: "DELETE", path: "/api/admin/featured/{auctionId}", tag: "admin", summary: "Stop featuring an auction", auth: true, response: messageResponse},
+	{method: "GET", path: "/api/admin/audit-logs", tag: "admin", summary: "List audit log entries", auth: true,
+		query: []string{"page", "limit", "actor_id", "action", "entity_type", "entity_id"}, paginated: true, response: []domain.AuditLog{}},
+	{method: "GET", path: "/api/admin/cors-origins", tag: "admin", summary: "List admin-managed CORS origins", auth: true, response: []domain.AllowedOrigin{}},
+	{method: "POST", path: "/api/admin/cors-origins", tag: "admin", summary: "Allow a CORS origin pattern", auth: true, request: domain.CreateAllowedOriginRequest{}, response: domain.AllowedOrigin{}, status: http.StatusCreated},
+	{method: "DELETE", path: "/api/admin/cors-origins/{id}", tag: "admin", summary: "Revoke an admin-managed CORS origin", auth: true, response: messageResponse},
+	{method: "PUT", path: "/api/admin/maintenance", tag: "admin", summary: "Switch read-only maintenance mode on or off", auth: true, request: domain.SetMaintenanceRequest{}, response: domain.MaintenanceStatus{}},
+	{method: "POST", path: "/api/admin/maintenance/windows", tag: "admin", summary: "Plan a maintenance window; auctions coming due during it are extended by its downtime", auth: true, request: domain.CreateMaintenanceWindowRequest{}, response: domain.MaintenanceWindow{}, status: http.StatusCreated},
+	{method: "DELETE", path: "/api/admin/maintenance/windows/{id}", tag: "admin", summary: "Cancel a maintenance window that hasn't started", auth: true, response: messageResponse},
+	{method: "GET", path: "/api/admin/status-incidents", tag: "admin", summary: "List status page incidents, unresolved or resolved in the last 90 days", auth: true, response: []domain.StatusIncident{}},
+	{method: "POST", path: "/api/admin/status-incidents", tag: "admin", summary: "Post an incident to the status page", auth: true, request: domain.CreateStatusIncidentRequest{}, response: domain.StatusIncident{}, status: http.StatusCreated},
+	{method: "PUT", path: "/api/admin/status-incidents/{id}", tag: "admin", summary: "Update a status page incident, resolving or reopening it", auth: true, request: domain.UpdateStatusIncidentRequest{}, response: domain.StatusIncident{}},
+	{method: "DELETE", path: "/api/admin/status-incidents/{id}", tag: "admin", summary: "Remove a status page incident posted by mistake", auth: true, response: messageResponse},
+	{method: "GET", path: "/api/admin/fraud-flags", tag: "admin", summary: "List the fraud review queue, most severe first", auth: true, query: []string{"page", "limit", "status", "check"}, paginated: true, response: []domain.FraudFlag{}},
+	{method: "PUT", path: "/api/admin/fraud-flags/{id}", tag: "admin", summary: "Confirm or dismiss a fraud flag", auth: true, request: domain.ReviewFraudFlagRequest{}, response: domain.FraudFlag{}},
+	{method: "POST", path: "/api/admin/fraud-checks/run", tag: "admin", summary: "Run the fraud checks now instead of waiting for the hourly run", auth: true, response: domain.FraudRunResult{}},
+	{method: "GET", path: "/api/admin/notification-queue", tag: "admin", summary: "Get notification queue statistics", auth: true, response: service.NotificationQueueStats{}},
+	{method: "GET", path: "/api/admin/escrow", tag: "admin", summary: "List payments held in escrow, soonest release first", auth: true, query: pageQuery, paginated: true, response: []domain.EscrowHold{}},
+	{method: "POST", path: "/api/admin/escrow/{id}/release", tag: "admin", summary: "Release an order's held payment to the seller early", auth: true, request: domain.ReleaseEscrowRequest{}, response: domain.EscrowHold{}},
+	{method: "GET", path: "/api/admin/payouts", tag: "admin", summary: "List payouts with their full destination, newest first", auth: true, query: []string{"page", "limit", "status", "user_id"}, paginated: true, response: []domain.Payout{}},
+	{method: "POST", path: "/api/admin/payouts/{id}/approve", tag: "admin", summary: "Approve a requested payout", auth: true, response: domain.Payout{}},
+	{method: "POST", path: "/api/admin/payouts/{id}/complete", tag: "admin", summary: "Record that an approved payout was sent", auth: true, request: domain.CompletePayoutRequest{}, response: domain.Payout{}},
+	{method: "POST", path: "/api/admin/payouts/{id}/reject", tag: "admin", summary: "Reject a payout, returning the amount to the seller's balance", auth: true, request: domain.RejectPayoutRequest{}, response: domain.Payout{}},
+	{method: "GET", path: "/api/admin/disputes", tag: "admin", summary: "List disputes", auth: true, query: []string{"page", "limit", "status", "user_id"}, paginated: true, response: []domain.Dispute{}},
+	{method: "GET", path: "/api/admin/disputes/{id}", tag: "admin", summary: "Get a dispute", auth: true, response: domain.Dispute{}},
+	{method: "POST", path: "/api/admin/disputes/{id}/messages", tag: "admin", summary: "Add an admin message to a dispute", auth: true, request: domain.DisputeMessageRequest{}, response: domain.DisputeMessage{}, status: http.StatusCreated},
+	{method: "POST", path: "/api/admin/disputes/{id}/resolve", tag: "admin", summary: "Resolve a dispute", auth: true, request: domain.ResolveDisputeRequest{}, response: domain.Dispute{}},
+	{method: "POST", path: "/api/admin/conversations/{id}/transcript", tag: "admin", summary: "Read a conversation under an open dispute or report (audited)", auth: true,
+		request: domain.TranscriptAccessRequest{}, response: domain.ConversationTranscript{}},
+}
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// OpenAPISpec builds the OpenAPI document for every documented route
+func OpenAPISpec() *openapi.Document {
+	reflector := openapi.NewReflector()
+	errorRef := &openapi.Schema{Ref: "#/components/schemas/ErrorResponse"}
+	problemRef := reflector.SchemaOf(domain.Problem{})
+
+	doc := &openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: openapi.Info{
+			Title:   "Auction Cards API",
+			Version: "1.0.0",
+		},
+		Paths: make(map[string]*openapi.PathItem),
+	}
+
+	for _, route := range apiRoutes {
+		op := &openapi.Operation{
+			Tags:    []string{route.tag},
+			Summary: route.summary,
+			Responses: map[string]*openapi.Response{
+				"default": {
+					Description: "Error; clients that accept application/problem+json get problem details",
+					Content: map[string]*openapi.MediaType{
+						"application/json":        {Schema: errorRef},
+						domain.ProblemContentType: {Schema: problemRef},
+					},
+				},
+			},
+		}
+
+		for _, match := range pathParamPattern.FindAllStringSubmatch(route.path, -1) {
+			schema := &openapi.Schema{Type: "string", Format: "uuid"}
+			if match[1] == "slug" {
+				schema.Format = ""
+			}
+			op.Parameters = append(op.Parameters, openapi.Parameter{Name: match[1], In: "path", Required: true, Schema: schema})
+		}
+		for _, name := range route.query {
+			op.Parameters = append(op.Parameters, openapi.Parameter{Name: name, In: "query", Schema: &openapi.Schema{Type: "string"}})
+		}
+
+		if route.request != nil {
+			body := &openapi.RequestBody{Required: true, Content: jsonContent(reflector.SchemaOf(route.request))}
+			if route.multipart {
+				body.Content = map[string]*openapi.MediaType{
+					"multipart/form-data": {Schema: reflector.SchemaOf(route.request)},
+				}
+			}
+			op.RequestBody = body
+		}
+
+		status := route.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		resp := &openapi.Response{Description: http.StatusText(status)}
+		if route.response != nil {
+			envelope := &openapi.Schema{
+				Type:     "object",
+				Required: []string{"success"},
+				Properties: map[string]*openapi.Schema{
+					"success": {Type: "boolean"},
+					"data":    reflector.SchemaOf(route.response),
+				},
+			}
+			if route.paginated {
+				envelope.Properties["meta"] = reflector.SchemaOf(domain.APIMeta{})
+			}
+			resp.Content = jsonContent(envelope)
+		}
+		op.Responses[strconv.Itoa(status)] = resp
+
+		if route.auth {
+			op.Security = []map[string][]string{{"bearerAuth": {}}}
+		}
+
+		item, ok := doc.Paths[route.path]
+		if !ok {
+			item = &openapi.PathItem{}
+			doc.Paths[route.path] = item
+		}
+		(*item)[strings.ToLower(route.method)] = op
+	}
+
+	schemas := reflector.Schemas()
+	schemas["ErrorResponse"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"success", "error"},
+		Properties: map[string]*openapi.Schema{
+			"success": {Type: "boolean"},
+			"error":   reflector.SchemaOf(domain.APIError{}),
+		},
+	}
+
+	doc.Components = openapi.Components{
+		Schemas: schemas,
+		SecuritySchemes: map[string]*openapi.SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		},
+	}
+
+	return doc
+}
+
+// OpenAPISpecJSON returns the OpenAPI document as indented JSON, the same
+// bytes that are committed to api/openapi.json
+func OpenAPISpecJSON() ([]byte, error) {
+	data, err := json.MarshalIndent(OpenAPISpec(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func jsonContent(schema *openapi.Schema) map[string]*openapi.MediaType {
+	return map[string]*openapi.MediaType{
+		"application/json": {Schema: schema},
+	}
+}
+
+type OpenAPIHandler struct {
+	spec []byte
+}
+
+func NewOpenAPIHandler() (*OpenAPIHandler, error) {
+	spec, err := OpenAPISpecJSON()
+	if err != nil {
+		return nil, err
+	}
+	return &OpenAPIHandler{spec: spec}, nil
+}
+
+// Spec handles GET /api/openapi.json
+func (h *OpenAPIHandler) Spec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(h.spec)
+}
+
+// Docs handles GET /api/docs
+func (h *OpenAPIHandler) Docs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(swaggerUIPage))
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Auction Cards API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/api/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
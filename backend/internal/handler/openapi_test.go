@@ -0,0 +1,44 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/auction-cards/backend/internal/handler"
+)
+
+func TestOpenAPISpec_UpToDate(t *testing.T) {
+	committed, err := os.ReadFile("../../api/openapi.json")
+	if err != nil {
+		t.Fatalf("failed to read committed spec: %v", err)
+	}
+
+	generated, err := handler.OpenAPISpecJSON()
+	if err != nil {
+		t.Fatalf("failed to build spec: %v", err)
+	}
+
+	if string(committed) != string(generated) {
+		t.Error("api/openapi.json is out of date, run go generate ./internal/handler")
+	}
+}
+
+func TestOpenAPIHandler_Spec(t *testing.T) {
+	h, err := handler.NewOpenAPIHandler()
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+	rr := httptest.NewRecorder()
+	h.Spec(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected application/json, got %s", ct)
+	}
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type OrderHandler struct {
+	orderService *service.OrderService
+}
+
+func NewOrderHandler(orderService *service.OrderService) *OrderHandler {
+	return &OrderHandler{orderService: orderService}
+}
+
+func (h *OrderHandler) List(w http.ResponseWriter, r *http.Request) {
+	params := &domain.OrderListParams{
+		Page:  getQueryParamInt(r, "page", 1),
+		Limit: getQueryParamInt(r, "limit", 20),
+	}
+
+	if role := getQueryParamString(r, "role"); role != nil {
+		params.Role = *role
+	}
+	if status := getQueryParamString(r, "status"); status != nil {
+		s := domain.OrderStatus(*status)
+		params.Status = &s
+	}
+
+	userID := getUserID(r)
+	result, err := h.orderService.List(r.Context(), userID, params)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSONWithMeta(w, http.StatusOK, result.Orders, &domain.APIMeta{
+		Page:       result.Page,
+		Limit:      params.Limit,
+		TotalCount: result.TotalCount,
+		TotalPages: result.TotalPages,
+	})
+}
+
+func (h *OrderHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid order ID")
+		return
+	}
+
+	userID := getUserID(r)
+	order, err := h.orderService.GetByID(r.Context(), id, userID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, order)
+}
+
+func (h *OrderHandler) MarkPaid(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid order ID")
+		return
+	}
+
+	userID := getUserID(r)
+	order, err := h.orderService.MarkPaid(r.Context(), id, userID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, order)
+}
+
+func (h *OrderHandler) GetMySecondChanceOffers(w http.ResponseWriter, r *http.Request) {
+	var status *domain.SecondChanceOfferStatus
+	if s := getQueryParamString(r, "status"); s != nil {
+		st := domain.SecondChanceOfferStatus(*s)
+		status = &st
+	}
+
+	userID := getUserID(r)
+	offers, err := h.orderService.GetMyOffers(r.Context(), userID, status)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, offers)
+}
+
+func (h *OrderHandler) AcceptSecondChanceOffer(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid offer ID")
+		return
+	}
+
+	userID := getUserID(r)
+	order, err := h.orderService.AcceptOffer(r.Context(), id, userID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, order)
+}
+
+func (h *OrderHandler) DeclineSecondChanceOffer(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid offer ID")
+		return
+	}
+
+	userID := getUserID(r)
+	if err := h.orderService.DeclineOffer(r.Context(), id, userID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Offer declined",
+	})
+}
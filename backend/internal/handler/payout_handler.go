@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type PayoutHandler struct {
+	payoutService *service.PayoutService
+}
+
+func NewPayoutHandler(payoutService *service.PayoutService) *PayoutHandler {
+	return &PayoutHandler{payoutService: payoutService}
+}
+
+func (h *PayoutHandler) GetMethod(w http.ResponseWriter, r *http.Request) {
+	method, err := h.payoutService.GetMethod(r.Context(), getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, method)
+}
+
+// SetMethod registers the bank account or Stripe Connect account payouts
+// are sent to
+func (h *PayoutHandler) SetMethod(w http.ResponseWriter, r *http.Request) {
+	var req domain.SetPayoutMethodRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	method, err := h.payoutService.SetMethod(r.Context(), getUserID(r), &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, method)
+}
+
+func (h *PayoutHandler) List(w http.ResponseWriter, r *http.Request) {
+	page := max(getQueryParamInt(r, "page", 1), 1)
+	limit := getQueryParamInt(r, "limit", 20)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	payouts, totalCount, err := h.payoutService.ListForUser(r.Context(), getUserID(r), page, limit)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSONWithMeta(w, http.StatusOK, payouts, &domain.APIMeta{
+		Page:       page,
+		Limit:      limit,
+		TotalCount: totalCount,
+		TotalPages: (totalCount + limit - 1) / limit,
+	})
+}
+
+// Request withdraws part of the seller's available balance
+func (h *PayoutHandler) Request(w http.ResponseWriter, r *http.Request) {
+	var req domain.RequestPayoutRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	payout, err := h.payoutService.RequestPayout(r.Context(), getUserID(r), &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, payout)
+}
+
+// AdminList lists payouts, newest first, with their full destination
+func (h *PayoutHandler) AdminList(w http.ResponseWriter, r *http.Request) {
+	params := &domain.PayoutListParams{
+		UserID: getQueryParamUUID(r, "user_id"),
+		Page:   max(getQueryParamInt(r, "page", 1), 1),
+		Limit:  getQueryParamInt(r, "limit", 20),
+	}
+	if params.Limit <= 0 || params.Limit > 100 {
+		params.Limit = 20
+	}
+
+	if status := getQueryParamString(r, "status"); status != nil {
+		s := domain.PayoutStatus(*status)
+		params.Status = &s
+	}
+
+	payouts, totalCount, err := h.payoutService.AdminList(r.Context(), params)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSONWithMeta(w, http.StatusOK, payouts, &domain.APIMeta{
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalCount: totalCount,
+		TotalPages: (totalCount + params.Limit - 1) / params.Limit,
+	})
+}
+
+func (h *PayoutHandler) AdminApprove(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid payout ID")
+		return
+	}
+
+	payout, err := h.payoutService.Approve(r.Context(), getUserID(r), id)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, payout)
+}
+
+// AdminComplete records that an approved payout was sent
+func (h *PayoutHandler) AdminComplete(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid payout ID")
+		return
+	}
+
+	var req domain.CompletePayoutRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	payout, err := h.payoutService.Complete(r.Context(), getUserID(r), id, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, payout)
+}
+
+// AdminReject turns down a payout, returning the amount to the seller's
+// balance
+func (h *PayoutHandler) AdminReject(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid payout ID")
+		return
+	}
+
+	var req domain.RejectPayoutRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	payout, err := h.payoutService.Reject(r.Context(), getUserID(r), id, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, payout)
+}
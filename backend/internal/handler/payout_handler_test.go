@@ -0,0 +1,238 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const testPayoutKey = "5f0c6a3e9b1d47a28c3e6f9a0b2d4c6e8f1a3b5c7d9e0f2a4b6c8d0e2f4a6b8c"
+
+// Mock payout repository
+type mockPayoutRepo struct {
+	methods map[uuid.UUID]*domain.PayoutMethod
+	payouts map[uuid.UUID]*domain.Payout
+}
+
+func newMockPayoutRepo() *mockPayoutRepo {
+	return &mockPayoutRepo{
+		methods: make(map[uuid.UUID]*domain.PayoutMethod),
+		payouts: make(map[uuid.UUID]*domain.Payout),
+	}
+}
+
+func (r *mockPayoutRepo) GetMethod(ctx context.Context, userID uuid.UUID) (*domain.PayoutMethod, error) {
+	method, ok := r.methods[userID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *method
+	return &copied, nil
+}
+
+func (r *mockPayoutRepo) UpsertMethod(ctx context.Context, method *domain.PayoutMethod) error {
+	method.UpdatedAt = time.Now()
+	stored := *method
+	r.methods[method.UserID] = &stored
+	return nil
+}
+
+func (r *mockPayoutRepo) Create(ctx context.Context, payout *domain.Payout) error {
+	payout.CreatedAt = time.Now()
+	stored := *payout
+	r.payouts[payout.ID] = &stored
+	return nil
+}
+
+func (r *mockPayoutRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Payout, error) {
+	payout, ok := r.payouts[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *payout
+	return &copied, nil
+}
+
+func (r *mockPayoutRepo) List(ctx context.Context, params *domain.PayoutListParams) ([]domain.Payout, int, error) {
+	payouts := make([]domain.Payout, 0)
+	for _, payout := range r.payouts {
+		if params.UserID != nil && payout.UserID != *params.UserID {
+			continue
+		}
+		if params.Status != nil && payout.Status != *params.Status {
+			continue
+		}
+		payouts = append(payouts, *payout)
+	}
+	return payouts, len(payouts), nil
+}
+
+func (r *mockPayoutRepo) Transition(ctx context.Context, payout *domain.Payout, from domain.PayoutStatus) error {
+	stored, ok := r.payouts[payout.ID]
+	if !ok || stored.Status != from {
+		return domain.ErrPayoutStatus
+	}
+	updated := *payout
+	r.payouts[payout.ID] = &updated
+	return nil
+}
+
+func TestPayoutHandler_Flow(t *testing.T) {
+	escrowRepo := newMockEscrowRepo()
+	ledgerRepo := newMockLedgerRepo(escrowRepo)
+	payoutRepo := newMockPayoutRepo()
+	auditRepo := &mockAuditLogRepo{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	ledgerService := service.NewLedgerService(escrowRepo, ledgerRepo, nil, auditRepo, &mockTxManager{}, nil, 14*24*time.Hour)
+	payoutService, err := service.NewPayoutService(payoutRepo, ledgerService, auditRepo, &mockTxManager{}, nil, testPayoutKey, decimal.NewFromInt(10))
+	if err != nil {
+		t.Fatalf("failed to create payout service: %v", err)
+	}
+
+	sellerID := uuid.New()
+	adminID := uuid.New()
+	ledgerRepo.balances[sellerID] = decimal.NewFromInt(100)
+
+	r := createTestRouter()
+	payoutHandler := handler.NewPayoutHandler(payoutService)
+
+	r.With(authMiddleware.RequireAuth).Put("/api/users/me/payout-method", payoutHandler.SetMethod)
+	r.With(authMiddleware.RequireAuth).Post("/api/users/me/payouts", payoutHandler.Request)
+	r.With(authMiddleware.RequireAuth).Post("/api/admin/payouts/{id}/approve", payoutHandler.AdminApprove)
+	r.With(authMiddleware.RequireAuth).Post("/api/admin/payouts/{id}/complete", payoutHandler.AdminComplete)
+	r.With(authMiddleware.RequireAuth).Post("/api/admin/payouts/{id}/reject", payoutHandler.AdminReject)
+
+	sellerToken, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+	adminToken, _ := jwtManager.GenerateAccessToken(adminID, "admin")
+
+	// No payouts without somewhere to send them
+	rr := makeRequest(t, r, "POST", "/api/users/me/payouts", domain.RequestPayoutRequest{Amount: "50"}, sellerToken)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected a payout method to be required, got %v", rr.Code)
+	}
+
+	badIBAN := "DE89 3704 0044 0532 0130 01"
+	rr = makeRequest(t, r, "PUT", "/api/users/me/payout-method", domain.SetPayoutMethodRequest{Type: "iban", AccountHolder: "Card Seller", IBAN: &badIBAN}, sellerToken)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected an IBAN with bad check digits to be rejected, got %v", rr.Code)
+	}
+
+	iban := "DE89 3704 0044 0532 0130 00"
+	rr = makeRequest(t, r, "PUT", "/api/users/me/payout-method", domain.SetPayoutMethodRequest{Type: "iban", AccountHolder: "Card Seller", IBAN: &iban}, sellerToken)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	method := parseResponse(t, rr).Data.(map[string]interface{})
+	if method["iban_last4"] != "3000" || method["iban"] != nil {
+		t.Errorf("expected only the last four IBAN characters, got %v", method)
+	}
+
+	requestPayout := func(amount string, wantStatus int) uuid.UUID {
+		t.Helper()
+		rr := makeRequest(t, r, "POST", "/api/users/me/payouts", domain.RequestPayoutRequest{Amount: amount}, sellerToken)
+		if rr.Code != wantStatus {
+			t.Fatalf("requesting %s: got status %v want %v", amount, rr.Code, wantStatus)
+		}
+		if wantStatus != http.StatusCreated {
+			return uuid.Nil
+		}
+		id, _ := uuid.Parse(parseResponse(t, rr).Data.(map[string]interface{})["id"].(string))
+		return id
+	}
+
+	requestPayout("5", http.StatusBadRequest)
+	requestPayout("500", http.StatusBadRequest)
+	paid := requestPayout("60", http.StatusCreated)
+	rejected := requestPayout("30", http.StatusCreated)
+	requestPayout("20", http.StatusBadRequest)
+
+	tests := []struct {
+		name       string
+		path       string
+		body       interface{}
+		token      string
+		wantStatus int
+	}{
+		{
+			name:       "cannot complete before approval",
+			path:       "/api/admin/payouts/" + paid.String() + "/complete",
+			body:       domain.CompletePayoutRequest{Reference: "SEPA-123"},
+			token:      adminToken,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "approve",
+			path:       "/api/admin/payouts/" + paid.String() + "/approve",
+			token:      adminToken,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "complete needs a reference",
+			path:       "/api/admin/payouts/" + paid.String() + "/complete",
+			body:       domain.CompletePayoutRequest{},
+			token:      adminToken,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "complete",
+			path:       "/api/admin/payouts/" + paid.String() + "/complete",
+			body:       domain.CompletePayoutRequest{Reference: "SEPA-123"},
+			token:      adminToken,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "cannot reject a sent payout",
+			path:       "/api/admin/payouts/" + paid.String() + "/reject",
+			body:       domain.RejectPayoutRequest{Reason: "Too late"},
+			token:      adminToken,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "reject",
+			path:       "/api/admin/payouts/" + rejected.String() + "/reject",
+			body:       domain.RejectPayoutRequest{Reason: "Account name does not match"},
+			token:      adminToken,
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := makeRequest(t, r, "POST", tt.path, tt.body, tt.token)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+		})
+	}
+
+	if got := ledgerRepo.balances[sellerID]; !got.Equal(decimal.NewFromInt(40)) {
+		t.Errorf("expected 40 left after paying out 60, got %s", got)
+	}
+
+	if stored := payoutRepo.payouts[paid]; stored.Status != domain.PayoutCompleted || stored.Reference == nil {
+		t.Errorf("expected the payout to be completed with its reference, got %+v", stored)
+	}
+
+	sum := decimal.Zero
+	for _, entry := range ledgerRepo.entries {
+		sum = sum.Add(entry.Amount)
+	}
+	if !sum.IsZero() || len(ledgerRepo.entries) != 10 {
+		t.Errorf("expected 10 balanced ledger entries, got %d summing to %s", len(ledgerRepo.entries), sum)
+	}
+
+	if len(auditRepo.entries) != 3 {
+		t.Errorf("expected 3 audited payout reviews, got %d", len(auditRepo.entries))
+	}
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type PriceDropHandler struct {
+	priceDropService *service.PriceDropService
+}
+
+func NewPriceDropHandler(priceDropService *service.PriceDropService) *PriceDropHandler {
+	return &PriceDropHandler{priceDropService: priceDropService}
+}
+
+// SetRule sets up scheduled markdowns of the auction's buy-now price
+func (h *PriceDropHandler) SetRule(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	var req domain.SetPriceDropRuleRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	rule, err := h.priceDropService.SetRule(r.Context(), auctionID, getUserID(r), &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rule)
+}
+
+// DeleteRule stops the auction's scheduled markdowns
+func (h *PriceDropHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	if err := h.priceDropService.DeleteRule(r.Context(), auctionID, getUserID(r)); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Price drops stopped",
+	})
+}
+
+// History lists the markdowns of the auction's buy-now price
+func (h *PriceDropHandler) History(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	history, err := h.priceDropService.History(r.Context(), auctionID, getUserID(r), r.URL.Query().Get("share"))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, history)
+}
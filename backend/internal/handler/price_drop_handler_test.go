@@ -0,0 +1,257 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock price drop repository keeping rules and drops in memory
+type mockPriceDropRepo struct {
+	rules map[uuid.UUID]*domain.PriceDropRule
+	drops []domain.PriceDrop
+}
+
+func (r *mockPriceDropRepo) GetRule(ctx context.Context, auctionID uuid.UUID) (*domain.PriceDropRule, error) {
+	rule, ok := r.rules[auctionID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	stored := *rule
+	return &stored, nil
+}
+
+func (r *mockPriceDropRepo) SaveRule(ctx context.Context, rule *domain.PriceDropRule) error {
+	stored := *rule
+	r.rules[rule.AuctionID] = &stored
+	return nil
+}
+
+func (r *mockPriceDropRepo) DeleteRule(ctx context.Context, auctionID uuid.UUID) error {
+	if _, ok := r.rules[auctionID]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.rules, auctionID)
+	return nil
+}
+
+func (r *mockPriceDropRepo) ClaimDue(ctx context.Context, now time.Time) (*domain.PriceDropRule, error) {
+	for _, rule := range r.rules {
+		if rule.NextDropAt != nil && !rule.NextDropAt.After(now) {
+			claimed := *rule
+			return &claimed, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockPriceDropRepo) CreateDrop(ctx context.Context, drop *domain.PriceDrop) error {
+	drop.ID = uuid.New()
+	drop.CreatedAt = time.Now()
+	r.drops = append(r.drops, *drop)
+	return nil
+}
+
+func (r *mockPriceDropRepo) ListDrops(ctx context.Context, auctionID uuid.UUID) ([]domain.PriceDrop, error) {
+	drops := make([]domain.PriceDrop, 0)
+	for _, drop := range r.drops {
+		if drop.AuctionID == auctionID {
+			drops = append(drops, drop)
+		}
+	}
+	return drops, nil
+}
+
+func TestPriceDropHandler(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	priceDropRepo := &mockPriceDropRepo{rules: make(map[uuid.UUID]*domain.PriceDropRule)}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil, // no S3 for tests
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	priceDropService := service.NewPriceDropService(priceDropRepo, auctionRepo, auctionService, nil, &mockTxManager{})
+	priceDropHandler := handler.NewPriceDropHandler(priceDropService)
+
+	r := createTestRouter()
+	r.With(authMiddleware.OptionalAuth).Get("/api/auctions/{id}/price-history", priceDropHandler.History)
+	r.With(authMiddleware.RequireAuth).Put("/api/auctions/{id}/price-drop", priceDropHandler.SetRule)
+	r.With(authMiddleware.RequireAuth).Delete("/api/auctions/{id}/price-drop", priceDropHandler.DeleteRule)
+
+	sellerID := uuid.New()
+	sellerToken, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+	strangerToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+
+	buyNow := decimal.NewFromFloat(100)
+	auction := &domain.Auction{
+		SellerID:      sellerID,
+		Title:         "Sealed Booster Box",
+		StartingPrice: decimal.NewFromFloat(50),
+		CurrentPrice:  decimal.NewFromFloat(50),
+		BuyNowPrice:   &buyNow,
+		BuyNowRule:    domain.BuyNowAlways,
+		BidIncrement:  decimal.NewFromFloat(1),
+		StartTime:     time.Now().Add(-time.Hour),
+		EndTime:       time.Now().Add(30 * 24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+	}
+	auctionRepo.Create(context.Background(), auction)
+	noBuyNow := &domain.Auction{
+		SellerID:      sellerID,
+		Title:         "Graded Charizard",
+		StartingPrice: decimal.NewFromFloat(50),
+		CurrentPrice:  decimal.NewFromFloat(50),
+		BidIncrement:  decimal.NewFromFloat(1),
+		StartTime:     time.Now().Add(-time.Hour),
+		EndTime:       time.Now().Add(30 * 24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+	}
+	auctionRepo.Create(context.Background(), noBuyNow)
+
+	rulePath := "/api/auctions/" + auction.ID.String() + "/price-drop"
+	historyPath := "/api/auctions/" + auction.ID.String() + "/price-history"
+	rule := domain.SetPriceDropRuleRequest{Percent: "10", IntervalDays: 3, FloorPrice: "80"}
+
+	// Brings the next markdown due and runs the scheduler's pass
+	runDue := func() {
+		t.Helper()
+		past := time.Now().Add(-time.Minute)
+		if stored, ok := priceDropRepo.rules[auction.ID]; ok && stored.NextDropAt != nil {
+			stored.NextDropAt = &past
+		}
+		priceDropService.ProcessDue(context.Background())
+	}
+
+	t.Run("rejected rules", func(t *testing.T) {
+		cases := []struct {
+			name   string
+			path   string
+			req    domain.SetPriceDropRuleRequest
+			token  string
+			status int
+		}{
+			{"not the seller", rulePath, rule, strangerToken, http.StatusForbidden},
+			{"floor above buy-now", rulePath, domain.SetPriceDropRuleRequest{Percent: "10", IntervalDays: 3, FloorPrice: "100"}, sellerToken, http.StatusBadRequest},
+			{"floor below starting price", rulePath, domain.SetPriceDropRuleRequest{Percent: "10", IntervalDays: 3, FloorPrice: "40"}, sellerToken, http.StatusBadRequest},
+			{"percent too high", rulePath, domain.SetPriceDropRuleRequest{Percent: "75", IntervalDays: 3, FloorPrice: "80"}, sellerToken, http.StatusBadRequest},
+			{"no buy-now", "/api/auctions/" + noBuyNow.ID.String() + "/price-drop", rule, sellerToken, http.StatusBadRequest},
+		}
+		for _, tc := range cases {
+			rr := makeRequest(t, r, "PUT", tc.path, tc.req, tc.token)
+			if rr.Code != tc.status {
+				t.Errorf("%s: got status %v, want %v", tc.name, rr.Code, tc.status)
+			}
+		}
+	})
+
+	t.Run("set rule", func(t *testing.T) {
+		rr := makeRequest(t, r, "PUT", rulePath, rule, sellerToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+
+		stored := priceDropRepo.rules[auction.ID]
+		if stored == nil || stored.NextDropAt == nil || stored.NextDropAt.Before(time.Now().Add(71*time.Hour)) {
+			t.Fatalf("expected the first drop three days out, got %+v", stored)
+		}
+
+		priceDropService.ProcessDue(context.Background())
+		if !auction.BuyNowPrice.Equal(buyNow) {
+			t.Errorf("expected no drop before it is due, got %s", auction.BuyNowPrice)
+		}
+	})
+
+	t.Run("drops down to the floor", func(t *testing.T) {
+		for _, want := range []string{"90.00", "81.00", "80.00"} {
+			runDue()
+			if got := auction.BuyNowPrice.StringFixed(2); got != want {
+				t.Fatalf("expected buy-now at %s, got %s", want, got)
+			}
+		}
+
+		if next := priceDropRepo.rules[auction.ID].NextDropAt; next != nil {
+			t.Errorf("expected the rule to finish at the floor, next drop at %v", next)
+		}
+
+		runDue()
+		if len(priceDropRepo.drops) != 3 {
+			t.Errorf("expected 3 drops, got %d", len(priceDropRepo.drops))
+		}
+	})
+
+	t.Run("history", func(t *testing.T) {
+		rr := makeRequest(t, r, "GET", historyPath, nil, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+		history := parseResponse(t, rr).Data.(map[string]interface{})
+		drops := history["drops"].([]interface{})
+		if len(drops) != 3 || drops[0].(map[string]interface{})["old_price"] != "100" {
+			t.Errorf("expected the drops oldest first, got %v", drops)
+		}
+		if _, ok := history["rule"]; ok {
+			t.Errorf("expected the rule hidden from buyers, got %v", history["rule"])
+		}
+
+		rr = makeRequest(t, r, "GET", historyPath, nil, sellerToken)
+		if _, ok := parseResponse(t, rr).Data.(map[string]interface{})["rule"]; !ok {
+			t.Error("expected the seller to see their rule")
+		}
+	})
+
+	t.Run("stops below the high bid", func(t *testing.T) {
+		restored := decimal.NewFromFloat(100)
+		auction.BuyNowPrice = &restored
+		auction.CurrentPrice = decimal.NewFromFloat(95)
+		auction.BidCount = 1
+
+		rr := makeRequest(t, r, "PUT", rulePath, domain.SetPriceDropRuleRequest{Percent: "10", IntervalDays: 1, FloorPrice: "60"}, sellerToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+
+		runDue()
+		if !auction.BuyNowPrice.Equal(restored) {
+			t.Errorf("expected buy-now kept above the high bid, got %s", auction.BuyNowPrice)
+		}
+		if next := priceDropRepo.rules[auction.ID].NextDropAt; next != nil {
+			t.Errorf("expected the rule to stop, next drop at %v", next)
+		}
+	})
+
+	t.Run("delete rule", func(t *testing.T) {
+		rr := makeRequest(t, r, "DELETE", rulePath, nil, strangerToken)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusForbidden)
+		}
+
+		rr = makeRequest(t, r, "DELETE", rulePath, nil, sellerToken)
+		if rr.Code != http.StatusOK {
+			t.Errorf("got status %v, want %v", rr.Code, http.StatusOK)
+		}
+		if _, ok := priceDropRepo.rules[auction.ID]; ok {
+			t.Error("expected the rule removed")
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type PrivacyHandler struct {
+	privacyService *service.PrivacyService
+}
+
+func NewPrivacyHandler(privacyService *service.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{privacyService: privacyService}
+}
+
+// Export returns the user's data export, queueing one if there is none to
+// wait for or download. It answers 202 until the archive is ready; the
+// download link is then emailed and included here too.
+func (h *PrivacyHandler) Export(w http.ResponseWriter, r *http.Request) {
+	export, err := h.privacyService.RequestExport(r.Context(), getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	status := http.StatusAccepted
+	if export.Status == domain.DataExportCompleted {
+		status = http.StatusOK
+	}
+	respondJSON(w, status, export)
+}
+
+// DeleteAccount erases the user's personal data and signs them out. Users
+// with a password must give it; others must have signed in recently.
+func (h *PrivacyHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	var req domain.ReauthRequest
+	if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	err := h.privacyService.DeleteAccount(r.Context(), getUserID(r), &req, refreshTokenFromCookie(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	clearRefreshTokenCookie(w)
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Account deleted",
+	})
+}
+
+// AdminDeleteUser soft-deletes an account. Its data is kept until purged.
+func (h *PrivacyHandler) AdminDeleteUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid user ID")
+		return
+	}
+
+	if err := h.privacyService.DeleteUser(r.Context(), getUserID(r), userID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "User deleted",
+	})
+}
+
+// AdminPurgeDeletedUsers anonymizes accounts deleted longer ago than the
+// retention period
+func (h *PrivacyHandler) AdminPurgeDeletedUsers(w http.ResponseWriter, r *http.Request) {
+	result, err := h.privacyService.PurgeDeletedUsers(r.Context(), getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,407 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/config"
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/pkg/password"
+	"github.com/auction-cards/backend/internal/pkg/storage"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Mock data export repository
+type mockDataExportRepo struct {
+	exports []*domain.DataExport
+}
+
+func (r *mockDataExportRepo) Create(ctx context.Context, export *domain.DataExport) error {
+	export.ID = uuid.New()
+	export.Status = domain.DataExportPending
+	export.CreatedAt = time.Now()
+	r.exports = append(r.exports, export)
+	return nil
+}
+
+func (r *mockDataExportRepo) GetLatestByUser(ctx context.Context, userID uuid.UUID) (*domain.DataExport, error) {
+	for i := len(r.exports) - 1; i >= 0; i-- {
+		if r.exports[i].UserID == userID {
+			export := *r.exports[i]
+			return &export, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockDataExportRepo) ClaimNext(ctx context.Context, now, leaseUntil time.Time) (*domain.DataExport, error) {
+	for _, export := range r.exports {
+		if export.Status == domain.DataExportPending {
+			export.Status = domain.DataExportProcessing
+			export.LeaseUntil = &leaseUntil
+			claimed := *export
+			return &claimed, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockDataExportRepo) ListExpired(ctx context.Context, now time.Time, limit int) ([]domain.DataExport, error) {
+	var expired []domain.DataExport
+	for _, export := range r.exports {
+		if export.Status == domain.DataExportCompleted && export.ExpiresAt != nil && !export.ExpiresAt.After(now) {
+			expired = append(expired, *export)
+		}
+	}
+	return expired, nil
+}
+
+func (r *mockDataExportRepo) Save(ctx context.Context, export *domain.DataExport) error {
+	for i, existing := range r.exports {
+		if existing.ID == export.ID {
+			saved := *export
+			r.exports[i] = &saved
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+// Mock account deletion repository
+type mockAccountDeletionRepo struct {
+	obligations domain.AccountObligations
+	userRepo    *mockUserRepo
+	purged      []uuid.UUID
+}
+
+func (r *mockAccountDeletionRepo) GetObligations(ctx context.Context, userID uuid.UUID) (*domain.AccountObligations, error) {
+	obligations := r.obligations
+	return &obligations, nil
+}
+
+func (r *mockAccountDeletionRepo) Anonymize(ctx context.Context, userID uuid.UUID, username string) (map[string]int64, error) {
+	user, err := r.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Email = "deleted+" + userID.String() + "@deleted.invalid"
+	user.Username = username
+	user.PasswordHash = nil
+	user.IsDeleted = true
+	r.purged = append(r.purged, userID)
+	return map[string]int64{"watchlist": 2}, nil
+}
+
+func (r *mockAccountDeletionRepo) ListPurgeable(ctx context.Context, deletedBefore time.Time, limit int) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0)
+	for _, user := range r.userRepo.users {
+		if user.IsDeleted && user.DeletedAt != nil && !user.DeletedAt.After(deletedBefore) && !slices.Contains(r.purged, user.ID) {
+			ids = append(ids, user.ID)
+		}
+	}
+	return ids, nil
+}
+
+// Mock object store that keeps objects in memory
+type mockObjectStore struct {
+	objects map[string][]byte
+}
+
+func (s *mockObjectStore) PutObject(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	s.objects[objectName] = data
+	return nil
+}
+
+func (s *mockObjectStore) RemoveObject(ctx context.Context, objectName string) error {
+	delete(s.objects, objectName)
+	return nil
+}
+
+func (s *mockObjectStore) GetPresignedURL(ctx context.Context, objectName string, expiry time.Duration) (string, error) {
+	return "https://storage.example.com/" + objectName + "?expires=" + expiry.String(), nil
+}
+
+func TestPrivacyHandler_Export(t *testing.T) {
+	exportRepo := &mockDataExportRepo{}
+	store := &mockObjectStore{objects: make(map[string][]byte)}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	newRouter := func(privacyService *service.PrivacyService) *chi.Mux {
+		r := createTestRouter()
+		r.With(authMiddleware.RequireAuth).Get("/api/users/me/export", handler.NewPrivacyHandler(privacyService).Export)
+		return r
+	}
+	newPrivacyService := func(store storage.ObjectStore) *service.PrivacyService {
+		return service.NewPrivacyService(exportRepo, nil, nil, nil, nil, nil, nil, nil, &mockTxManager{}, nil, nil, store, &mockEmailSender{}, time.Hour, 0)
+	}
+
+	userID := uuid.New()
+	token, _ := jwtManager.GenerateAccessToken(userID, "user")
+
+	t.Run("unavailable without storage", func(t *testing.T) {
+		rr := makeRequest(t, newRouter(newPrivacyService(nil)), "GET", "/api/users/me/export", nil, token)
+		if rr.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected 503, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	privacyService := newPrivacyService(store)
+	r := newRouter(privacyService)
+
+	rr := makeRequest(t, r, "GET", "/api/users/me/export", nil, token)
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rr.Code, rr.Body.String())
+	}
+	queued := parseResponse(t, rr).Data.(map[string]interface{})
+	if queued["status"] != "pending" {
+		t.Errorf("expected a pending export, got %v", queued)
+	}
+
+	// Asking again while it's queued doesn't queue another
+	rr = makeRequest(t, r, "GET", "/api/users/me/export", nil, token)
+	again := parseResponse(t, rr).Data.(map[string]interface{})
+	if rr.Code != http.StatusAccepted || again["id"] != queued["id"] || len(exportRepo.exports) != 1 {
+		t.Errorf("expected the queued export again, got %d %v", rr.Code, again)
+	}
+
+	// Once assembled, the export comes with a download link
+	objectName := "exports/" + userID.String() + "/archive.zip"
+	store.objects[objectName] = []byte("zip")
+	completedAt := time.Now()
+	expiresAt := completedAt.Add(time.Hour)
+	export := exportRepo.exports[0]
+	export.Status = domain.DataExportCompleted
+	export.ObjectName = &objectName
+	export.CompletedAt = &completedAt
+	export.ExpiresAt = &expiresAt
+
+	rr = makeRequest(t, r, "GET", "/api/users/me/export", nil, token)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	ready := parseResponse(t, rr).Data.(map[string]interface{})
+	if ready["id"] != queued["id"] || ready["download_url"] == nil || ready["object_name"] != nil {
+		t.Errorf("expected the completed export with a link, got %v", ready)
+	}
+
+	// Expired archives are deleted and a new export is queued on request
+	expired := time.Now().Add(-time.Minute)
+	export.ExpiresAt = &expired
+	privacyService.PurgeExpiredExports(context.Background())
+	if _, ok := store.objects[objectName]; ok || exportRepo.exports[0].Status != domain.DataExportExpired {
+		t.Errorf("expected the expired archive to be deleted, got %v", exportRepo.exports[0].Status)
+	}
+
+	rr = makeRequest(t, r, "GET", "/api/users/me/export", nil, token)
+	fresh := parseResponse(t, rr).Data.(map[string]interface{})
+	if rr.Code != http.StatusAccepted || fresh["id"] == queued["id"] {
+		t.Errorf("expected a new export, got %d %v", rr.Code, fresh)
+	}
+}
+
+func TestPrivacyHandler_DeleteAccount(t *testing.T) {
+	userRepo := newMockUserRepo()
+	auditRepo := &mockAuditLogRepo{}
+	deletionRepo := &mockAccountDeletionRepo{userRepo: userRepo}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	hashedPassword, err := password.Hash("Seller123!")
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	user := &domain.User{Email: "seller@example.com", Username: "seller", PasswordHash: &hashedPassword, Role: domain.RoleUser, EmailVerified: true}
+	userRepo.Create(context.Background(), user)
+
+	authService := service.NewAuthService(
+		userRepo,
+		&mockOAuthRepo{},
+		newMockRefreshTokenRepo(),
+		nil,
+		nil,
+		nil,
+		jwtManager,
+		&mockEmailSender{},
+		nil,
+		nil,
+		service.SessionPolicy{},
+		"http://localhost:5173",
+	)
+	privacyService := service.NewPrivacyService(nil, deletionRepo, userRepo, nil, nil, nil, nil, auditRepo, &mockTxManager{}, authService, nil, nil, &mockEmailSender{}, time.Hour, 0)
+
+	r := createTestRouter()
+	r.With(authMiddleware.RequireAuth).Delete("/api/users/me", handler.NewPrivacyHandler(privacyService).DeleteAccount)
+
+	token, _ := jwtManager.GenerateAccessToken(user.ID, "user")
+	request := func(body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("DELETE", "/api/users/me", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+
+	tests := []struct {
+		name        string
+		body        interface{}
+		obligations domain.AccountObligations
+		wantStatus  int
+		wantCode    string
+	}{
+		{name: "without password", body: domain.ReauthRequest{}, wantStatus: http.StatusForbidden, wantCode: "REAUTH_REQUIRED"},
+		{name: "wrong password", body: domain.ReauthRequest{CurrentPassword: "Wrong123!"}, wantStatus: http.StatusForbidden, wantCode: "REAUTH_REQUIRED"},
+		{name: "with a running listing", body: domain.ReauthRequest{CurrentPassword: "Seller123!"}, obligations: domain.AccountObligations{ActiveListings: 1}, wantStatus: http.StatusConflict, wantCode: "ACCOUNT_IN_USE"},
+		{name: "while winning an auction", body: domain.ReauthRequest{CurrentPassword: "Seller123!"}, obligations: domain.AccountObligations{LeadingBids: 1}, wantStatus: http.StatusConflict, wantCode: "ACCOUNT_IN_USE"},
+		{name: "delete", body: domain.ReauthRequest{CurrentPassword: "Seller123!"}, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deletionRepo.obligations = tt.obligations
+			rr := request(tt.body)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+
+			response := parseResponse(t, rr)
+			if tt.wantCode != "" && (response.Error == nil || response.Error.Code != tt.wantCode) {
+				t.Errorf("expected error code %s, got %+v", tt.wantCode, response.Error)
+			}
+		})
+	}
+
+	deleted, _ := userRepo.GetByID(context.Background(), user.ID)
+	if deleted.Email == "seller@example.com" || deleted.Username == "seller" || deleted.PasswordHash != nil {
+		t.Errorf("expected the account to be anonymized, got %s %s", deleted.Email, deleted.Username)
+	}
+	if len(auditRepo.entries) != 1 || auditRepo.entries[0].Action != domain.AuditActionUserDelete {
+		t.Errorf("expected one deletion audit entry, got %+v", auditRepo.entries)
+	}
+}
+
+func TestPrivacyHandler_AdminDeleteUser(t *testing.T) {
+	userRepo := newMockUserRepo()
+	refreshTokenRepo := newMockRefreshTokenRepo()
+	auditRepo := &mockAuditLogRepo{}
+	deletionRepo := &mockAccountDeletionRepo{userRepo: userRepo}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	hashedPassword, err := password.Hash("Member123!")
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	user := &domain.User{Email: "member@example.com", Username: "member", PasswordHash: &hashedPassword, Role: domain.RoleUser, EmailVerified: true}
+	userRepo.Create(context.Background(), user)
+	admin := &domain.User{Email: "admin@example.com", Username: "admin", Role: domain.RoleAdmin, EmailVerified: true}
+	userRepo.Create(context.Background(), admin)
+
+	authService := service.NewAuthService(
+		userRepo,
+		&mockOAuthRepo{},
+		refreshTokenRepo,
+		nil,
+		nil,
+		nil,
+		jwtManager,
+		&mockEmailSender{},
+		nil,
+		nil,
+		service.SessionPolicy{},
+		"http://localhost:5173",
+	)
+	newPrivacyService := func(purgeAfter time.Duration) *service.PrivacyService {
+		return service.NewPrivacyService(nil, deletionRepo, userRepo, nil, nil, nil, nil, auditRepo, &mockTxManager{}, authService, nil, nil, &mockEmailSender{}, time.Hour, purgeAfter)
+	}
+	newRouter := func(privacyService *service.PrivacyService) *chi.Mux {
+		privacyHandler := handler.NewPrivacyHandler(privacyService)
+		userHandler := handler.NewUserHandler(service.NewUserService(userRepo, nil, nil, nil), nil)
+
+		r := createTestRouter()
+		r.Post("/api/auth/login", handler.NewAuthHandler(authService, &config.Config{}).Login)
+		r.Get("/api/users/{id}", userHandler.GetPublicProfile)
+		r.Group(func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Use(authMiddleware.RequireAdmin)
+			r.Delete("/api/admin/users/{id}", privacyHandler.AdminDeleteUser)
+			r.Post("/api/admin/users/purge-deleted", privacyHandler.AdminPurgeDeletedUsers)
+		})
+		return r
+	}
+	r := newRouter(newPrivacyService(30 * 24 * time.Hour))
+	adminToken, _ := jwtManager.GenerateAccessToken(admin.ID, "admin")
+	login := domain.LoginRequest{Email: "member@example.com", Password: "Member123!"}
+
+	if rr := makeRequest(t, r, "POST", "/api/auth/login", login, ""); rr.Code != http.StatusOK {
+		t.Fatalf("expected the member to sign in, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	deletionRepo.obligations = domain.AccountObligations{OpenOrders: 1}
+	if rr := makeRequest(t, r, "DELETE", "/api/admin/users/"+user.ID.String(), nil, adminToken); rr.Code != http.StatusConflict {
+		t.Errorf("expected a user with an open order to be kept, got %d", rr.Code)
+	}
+
+	deletionRepo.obligations = domain.AccountObligations{}
+	if rr := makeRequest(t, r, "DELETE", "/api/admin/users/"+user.ID.String(), nil, adminToken); rr.Code != http.StatusOK {
+		t.Fatalf("expected the user to be deleted, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := makeRequest(t, r, "DELETE", "/api/admin/users/"+user.ID.String(), nil, adminToken); rr.Code != http.StatusNotFound {
+		t.Errorf("expected deleting twice to 404, got %d", rr.Code)
+	}
+
+	// The account can't sign in and has no profile, but is still there
+	if rr := makeRequest(t, r, "POST", "/api/auth/login", login, ""); rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected a deleted user not to sign in, got %d", rr.Code)
+	}
+	if rr := makeRequest(t, r, "GET", "/api/users/"+user.ID.String(), nil, ""); rr.Code != http.StatusNotFound {
+		t.Errorf("expected no profile for a deleted user, got %d", rr.Code)
+	}
+	if len(refreshTokenRepo.tokens) != 0 {
+		t.Errorf("expected the user's sessions to be revoked, got %d", len(refreshTokenRepo.tokens))
+	}
+	public := userRepo.users[user.ID].ToPublic()
+	if public.Username != domain.DeletedUsername || !public.Deleted {
+		t.Errorf("expected the user to show as deleted, got %+v", public)
+	}
+
+	// Nothing is purged within the retention period
+	rr := makeRequest(t, r, "POST", "/api/admin/users/purge-deleted", nil, adminToken)
+	if purged := parseResponse(t, rr).Data.(map[string]interface{})["purged"]; purged != float64(0) {
+		t.Errorf("expected nothing purged yet, got %v", purged)
+	}
+
+	r = newRouter(newPrivacyService(0))
+	rr = makeRequest(t, r, "POST", "/api/admin/users/purge-deleted", nil, adminToken)
+	if purged := parseResponse(t, rr).Data.(map[string]interface{})["purged"]; purged != float64(1) {
+		t.Errorf("expected one user purged, got %v", purged)
+	}
+	if userRepo.users[user.ID].Email == "member@example.com" {
+		t.Error("expected the purged user's email to be erased")
+	}
+
+	actions := make([]string, 0, len(auditRepo.entries))
+	for _, entry := range auditRepo.entries {
+		actions = append(actions, entry.Action)
+	}
+	if !slices.Equal(actions, []string{domain.AuditActionUserDelete, domain.AuditActionUserPurge}) {
+		t.Errorf("unexpected audit trail %v", actions)
+	}
+}
@@ -0,0 +1,140 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func TestProblemDetails(t *testing.T) {
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	auctionService := service.NewAuctionService(
+		newMockAuctionRepo(),
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil, // no S3 for tests
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	auctionHandler := handler.NewAuctionHandler(auctionService)
+
+	r := chi.NewRouter()
+	r.Use(middleware.ProblemDetails("https://example.com/problems/"))
+	r.Use(middleware.Recoverer)
+	r.With(authMiddleware.OptionalAuth).Get("/api/auctions/{id}", auctionHandler.GetByID)
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions", auctionHandler.Create)
+	r.Get("/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	token, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+
+	request := func(method, path, accept string, body interface{}) *httptest.ResponseRecorder {
+		var reqBody bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&reqBody).Encode(body)
+		}
+		req := httptest.NewRequest(method, path, &reqBody)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+
+	decodeProblem := func(t *testing.T, rr *httptest.ResponseRecorder) map[string]interface{} {
+		if ct := rr.Header().Get("Content-Type"); ct != "application/problem+json" {
+			t.Fatalf("got Content-Type %q, want application/problem+json", ct)
+		}
+		var problem map[string]interface{}
+		if err := json.NewDecoder(rr.Body).Decode(&problem); err != nil {
+			t.Fatalf("Failed to parse problem: %v", err)
+		}
+		return problem
+	}
+
+	missingAuction := "/api/auctions/" + uuid.New().String()
+
+	t.Run("envelope by default", func(t *testing.T) {
+		for _, accept := range []string{"", "application/json", "application/json, application/problem+json;q=0.5"} {
+			rr := request("GET", missingAuction, accept, nil)
+			if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Accept %q: got Content-Type %q, want application/json", accept, ct)
+			}
+			response := parseResponse(t, rr)
+			if response.Success || response.Error == nil || response.Error.Code != "NOT_FOUND" {
+				t.Errorf("Accept %q: unexpected response %+v", accept, response)
+			}
+		}
+	})
+
+	t.Run("problem when preferred", func(t *testing.T) {
+		rr := request("GET", missingAuction, "application/problem+json, application/json", nil)
+		if rr.Code != http.StatusNotFound {
+			t.Fatalf("got status %v, want %v", rr.Code, http.StatusNotFound)
+		}
+		if vary := rr.Header().Get("Vary"); vary != "Accept" {
+			t.Errorf("got Vary %q, want Accept", vary)
+		}
+
+		problem := decodeProblem(t, rr)
+		if problem["type"] != "https://example.com/problems/not-found" || problem["title"] != "Not found" ||
+			problem["status"] != float64(http.StatusNotFound) || problem["code"] != "NOT_FOUND" {
+			t.Errorf("unexpected problem %v", problem)
+		}
+		if instance, _ := problem["instance"].(string); !strings.HasPrefix(instance, "urn:uuid:") {
+			t.Errorf("got instance %q, want a urn:uuid", instance)
+		}
+	})
+
+	t.Run("validation errors point at fields", func(t *testing.T) {
+		rr := request("POST", "/api/auctions", "application/problem+json", map[string]string{})
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("got status %v, want %v", rr.Code, http.StatusBadRequest)
+		}
+
+		problem := decodeProblem(t, rr)
+		if problem["type"] != "https://example.com/problems/validation-error" {
+			t.Errorf("got type %v", problem["type"])
+		}
+		fieldErrors, _ := problem["errors"].([]interface{})
+		pointers := make(map[string]bool)
+		for _, fieldError := range fieldErrors {
+			pointers[fieldError.(map[string]interface{})["pointer"].(string)] = true
+		}
+		if !pointers["#/title"] || !pointers["#/starting_price"] {
+			t.Errorf("unexpected errors %v", fieldErrors)
+		}
+	})
+
+	t.Run("recovered panics", func(t *testing.T) {
+		rr := request("GET", "/panic", "application/problem+json", nil)
+		if rr.Code != http.StatusInternalServerError {
+			t.Fatalf("got status %v, want %v", rr.Code, http.StatusInternalServerError)
+		}
+		if problem := decodeProblem(t, rr); problem["code"] != "INTERNAL_ERROR" {
+			t.Errorf("unexpected problem %v", problem)
+		}
+	})
+}
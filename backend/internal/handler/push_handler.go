@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type PushHandler struct {
+	pushService *service.PushService
+}
+
+func NewPushHandler(pushService *service.PushService) *PushHandler {
+	return &PushHandler{pushService: pushService}
+}
+
+// ListSubscriptions lists the browsers the current user subscribed to web
+// push, along with the server key to subscribe more with
+func (h *PushHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.pushService.List(r.Context(), getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// Subscribe stores the subscription the browser's PushManager returned
+func (h *PushHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreatePushSubscriptionRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	subscription, err := h.pushService.Subscribe(r.Context(), getUserID(r), r.UserAgent(), &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, subscription)
+}
+
+func (h *PushHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid subscription ID")
+		return
+	}
+
+	if err := h.pushService.Unsubscribe(r.Context(), getUserID(r), subscriptionID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Push subscription removed",
+	})
+}
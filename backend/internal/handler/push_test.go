@@ -0,0 +1,390 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/pkg/webpush"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock push subscription repository
+type mockPushSubscriptionRepo struct {
+	mu            sync.Mutex
+	subscriptions []domain.PushSubscription
+}
+
+func (r *mockPushSubscriptionRepo) Save(ctx context.Context, subscription *domain.PushSubscription, keep int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscriptions = slices.DeleteFunc(r.subscriptions, func(s domain.PushSubscription) bool {
+		return s.Endpoint == subscription.Endpoint
+	})
+	subscription.ID = uuid.New()
+	subscription.CreatedAt = time.Now()
+	r.subscriptions = append(r.subscriptions, *subscription)
+	return nil
+}
+
+func (r *mockPushSubscriptionRepo) ListByUsers(ctx context.Context, userIDs []uuid.UUID) ([]domain.PushSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	subscriptions := make([]domain.PushSubscription, 0)
+	for _, s := range r.subscriptions {
+		if slices.Contains(userIDs, s.UserID) {
+			subscriptions = append(subscriptions, s)
+		}
+	}
+	return subscriptions, nil
+}
+
+func (r *mockPushSubscriptionRepo) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	before := len(r.subscriptions)
+	r.subscriptions = slices.DeleteFunc(r.subscriptions, func(s domain.PushSubscription) bool {
+		return s.ID == id && s.UserID == userID
+	})
+	if len(r.subscriptions) == before {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *mockPushSubscriptionRepo) DeleteByIDs(ctx context.Context, ids []uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscriptions = slices.DeleteFunc(r.subscriptions, func(s domain.PushSubscription) bool {
+		return slices.Contains(ids, s.ID)
+	})
+	return nil
+}
+
+// pushBrowser is a browser's side of a push subscription, able to decrypt
+// what is pushed to it
+type pushBrowser struct {
+	key  *ecdh.PrivateKey
+	auth []byte
+}
+
+func newPushBrowser(t *testing.T) *pushBrowser {
+	t.Helper()
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := make([]byte, 16)
+	rand.Read(auth)
+	return &pushBrowser{key: key, auth: auth}
+}
+
+func (b *pushBrowser) subscription(endpoint string) map[string]interface{} {
+	return map[string]interface{}{
+		"endpoint": endpoint,
+		"keys": map[string]string{
+			"p256dh": base64.RawURLEncoding.EncodeToString(b.key.PublicKey().Bytes()),
+			"auth":   base64.RawURLEncoding.EncodeToString(b.auth),
+		},
+	}
+}
+
+// decrypt opens an aes128gcm body as RFC 8291 describes
+func (b *pushBrowser) decrypt(t *testing.T, body []byte) domain.PushPayload {
+	t.Helper()
+	salt, idLen := body[:16], int(body[20])
+	asPublic, ciphertext := body[21:21+idLen], body[21+idLen:]
+
+	serverKey, err := ecdh.P256().NewPublicKey(asPublic)
+	if err != nil {
+		t.Fatalf("bad server key: %v", err)
+	}
+	shared, _ := b.key.ECDH(serverKey)
+	prkKey, _ := hkdf.Extract(sha256.New, shared, b.auth)
+	ikm, _ := hkdf.Expand(sha256.New, prkKey, "WebPush: info\x00"+string(b.key.PublicKey().Bytes())+string(asPublic), 32)
+	prk, _ := hkdf.Extract(sha256.New, ikm, salt)
+	cek, _ := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	nonce, _ := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		t.Fatalf("failed to decrypt push: %v", err)
+	}
+
+	var payload domain.PushPayload
+	if err := json.Unmarshal(bytes.TrimSuffix(plaintext, []byte{0x02}), &payload); err != nil {
+		t.Fatalf("failed to decode push: %v", err)
+	}
+	return payload
+}
+
+// pushDelivery is one request the fake push service received
+type pushDelivery struct {
+	path   string
+	header http.Header
+	body   []byte
+}
+
+func TestPushSubscriptions(t *testing.T) {
+	userRepo := newMockUserRepo()
+	watchlistRepo := newMockWatchlistRepo()
+	subscriptionRepo := &mockPushSubscriptionRepo{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	// A push service that has forgotten every subscription under /gone
+	var (
+		mu         sync.Mutex
+		deliveries []pushDelivery
+	)
+	pushServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		deliveries = append(deliveries, pushDelivery{path: r.URL.Path, header: r.Header, body: body})
+		mu.Unlock()
+		if strings.HasPrefix(r.URL.Path, "/gone") {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer pushServer.Close()
+	delivered := func() []pushDelivery {
+		mu.Lock()
+		defer mu.Unlock()
+		sent := deliveries
+		deliveries = nil
+		return sent
+	}
+
+	publicKey, privateKey, _ := webpush.GenerateKeys()
+	sender, err := webpush.NewVAPIDSender(publicKey, privateKey, "mailto:ops@cards.example", 5*time.Second, true)
+	if err != nil {
+		t.Fatalf("failed to create sender: %v", err)
+	}
+	pushService := service.NewPushService(subscriptionRepo, sender, 10)
+	notificationService := service.NewNotificationService(
+		&mockNotificationRepo{},
+		userRepo,
+		watchlistRepo,
+		nil,
+		nil,
+		&mockNotificationPreferenceRepo{preferences: make(map[uuid.UUID]map[domain.NotificationType]domain.NotificationPreference)},
+		nil,
+		&mockEmailSender{},
+		nil,
+		nil,
+		pushService,
+		nil,
+		"https://cards.example",
+	)
+	pushHandler := handler.NewPushHandler(pushService)
+	userHandler := handler.NewUserHandler(nil, notificationService)
+
+	r := createTestRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Get("/api/users/me/push-subscriptions", pushHandler.ListSubscriptions)
+		r.Post("/api/users/me/push-subscriptions", pushHandler.Subscribe)
+		r.Delete("/api/users/me/push-subscriptions/{id}", pushHandler.Unsubscribe)
+		r.Put("/api/users/me/notification-settings", userHandler.UpdateNotificationSettings)
+	})
+
+	bidder := &domain.User{Email: "bidder@example.com", Username: "bidder"}
+	watcher := &domain.User{Email: "watcher@example.com", Username: "watcher"}
+	for _, u := range []*domain.User{bidder, watcher} {
+		userRepo.Create(context.Background(), u)
+	}
+	bidderToken, _ := jwtManager.GenerateAccessToken(bidder.ID, "user")
+	watcherToken, _ := jwtManager.GenerateAccessToken(watcher.ID, "user")
+
+	auction := &domain.Auction{
+		ID:           uuid.New(),
+		Title:        "Charizard",
+		CurrentPrice: decimal.NewFromInt(120),
+		BidIncrement: decimal.NewFromInt(5),
+		Currency:     domain.CurrencyUSD,
+		EndTime:      time.Now().Add(30 * time.Minute),
+	}
+
+	subscribe := func(t *testing.T, token string, body map[string]interface{}) domain.PushSubscription {
+		t.Helper()
+		rr := makeRequest(t, r, "POST", "/api/users/me/push-subscriptions", body, token)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var subscription domain.PushSubscription
+		data, _ := json.Marshal(parseResponse(t, rr).Data)
+		json.Unmarshal(data, &subscription)
+		return subscription
+	}
+
+	laptop := newPushBrowser(t)
+	phone := newPushBrowser(t)
+	watcherBrowser := newPushBrowser(t)
+	laptopSub := subscribe(t, bidderToken, laptop.subscription(pushServer.URL+"/laptop"))
+	subscribe(t, bidderToken, phone.subscription(pushServer.URL+"/gone/phone"))
+	subscribe(t, watcherToken, watcherBrowser.subscription(pushServer.URL+"/watcher"))
+
+	t.Run("list gives the server key", func(t *testing.T) {
+		rr := makeRequest(t, r, "GET", "/api/users/me/push-subscriptions", nil, bidderToken)
+		var resp domain.PushSubscriptionsResponse
+		data, _ := json.Marshal(parseResponse(t, rr).Data)
+		json.Unmarshal(data, &resp)
+		if resp.PublicKey != publicKey || len(resp.Subscriptions) != 2 {
+			t.Errorf("got %+v", resp)
+		}
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		body := laptop.subscription(pushServer.URL + "/other")
+		body["keys"] = map[string]string{"p256dh": "bm90IGEga2V5", "auth": "c2hvcnQ"}
+		rr := makeRequest(t, r, "POST", "/api/users/me/push-subscriptions", body, bidderToken)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rr.Code)
+		}
+		if response := parseResponse(t, rr); response.Error == nil || response.Error.Code != "INVALID_PUSH_SUBSCRIPTION" {
+			t.Errorf("got %+v", response.Error)
+		}
+	})
+
+	t.Run("outbid push, gone browsers pruned", func(t *testing.T) {
+		notificationService.NotifyOutbid(context.Background(), bidder.ID, auction, decimal.NewFromInt(130))
+
+		sent := delivered()
+		if len(sent) != 2 {
+			t.Fatalf("expected a push to each of the bidder's browsers, got %d", len(sent))
+		}
+		var push *pushDelivery
+		for i := range sent {
+			if sent[i].path == "/laptop" {
+				push = &sent[i]
+			}
+		}
+		if push == nil {
+			t.Fatal("expected a push to the laptop")
+		}
+		if push.header.Get("Content-Encoding") != "aes128gcm" || push.header.Get("Urgency") != "high" {
+			t.Errorf("got headers %v", push.header)
+		}
+		if auth := push.header.Get("Authorization"); !strings.HasPrefix(auth, "vapid t=") || !strings.HasSuffix(auth, ", k="+publicKey) {
+			t.Errorf("got authorization %q", auth)
+		}
+
+		payload := laptop.decrypt(t, push.body)
+		if payload.Type != domain.NotificationOutbid || !strings.Contains(payload.Body, "$130.00") ||
+			payload.URL != "https://cards.example/auctions/"+auction.ID.String()+"?bid=135.00" ||
+			payload.AuctionID == nil || *payload.AuctionID != auction.ID {
+			t.Errorf("got %+v", payload)
+		}
+
+		subscriptions, _ := subscriptionRepo.ListByUsers(context.Background(), []uuid.UUID{bidder.ID})
+		if len(subscriptions) != 1 || subscriptions[0].ID != laptopSub.ID {
+			t.Errorf("expected only the laptop left, got %+v", subscriptions)
+		}
+	})
+
+	t.Run("ending soon goes to every watcher", func(t *testing.T) {
+		for _, u := range []*domain.User{bidder, watcher} {
+			watchlistRepo.Add(context.Background(), &domain.WatchlistItem{UserID: u.ID, AuctionID: auction.ID})
+		}
+		notificationService.NotifyAuctionEnding(context.Background(), auction)
+
+		sent := delivered()
+		if len(sent) != 2 {
+			t.Fatalf("expected a push to each watcher, got %d", len(sent))
+		}
+		for _, push := range sent {
+			browser := laptop
+			if push.path == "/watcher" {
+				browser = watcherBrowser
+			}
+			if payload := browser.decrypt(t, push.body); payload.Type != domain.NotificationAuctionEnding {
+				t.Errorf("got %+v", payload)
+			}
+		}
+	})
+
+	t.Run("push switched off for a type", func(t *testing.T) {
+		rr := makeRequest(t, r, "PUT", "/api/users/me/notification-settings", map[string]interface{}{
+			"preferences": []map[string]interface{}{{"type": "auction_won", "push": false}},
+		}, bidderToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+
+		notificationService.NotifyAuctionWon(context.Background(), bidder.ID, auction)
+		if sent := delivered(); len(sent) != 0 {
+			t.Errorf("expected no push, got %d", len(sent))
+		}
+
+		notificationService.NotifyAuctionWon(context.Background(), watcher.ID, auction)
+		sent := delivered()
+		if len(sent) != 1 {
+			t.Fatalf("expected the winner's push, got %d", len(sent))
+		}
+		if payload := watcherBrowser.decrypt(t, sent[0].body); payload.Type != domain.NotificationAuctionWon || !strings.Contains(payload.URL, "action=pay") {
+			t.Errorf("got %+v", payload)
+		}
+	})
+
+	t.Run("expired subscriptions pruned unsent", func(t *testing.T) {
+		body := newPushBrowser(t).subscription(pushServer.URL + "/expired")
+		body["expiration_time"] = time.Now().Add(-time.Minute).UnixMilli()
+		subscribe(t, watcherToken, body)
+
+		notificationService.NotifyAuctionWon(context.Background(), watcher.ID, auction)
+		for _, push := range delivered() {
+			if push.path == "/expired" {
+				t.Error("expected nothing sent to the expired subscription")
+			}
+		}
+		if subscriptions, _ := subscriptionRepo.ListByUsers(context.Background(), []uuid.UUID{watcher.ID}); len(subscriptions) != 1 {
+			t.Errorf("expected the expired subscription pruned, got %d", len(subscriptions))
+		}
+	})
+
+	t.Run("unsubscribe", func(t *testing.T) {
+		path := "/api/users/me/push-subscriptions/" + laptopSub.ID.String()
+		if rr := makeRequest(t, r, "DELETE", path, nil, watcherToken); rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404 for another user's subscription, got %d", rr.Code)
+		}
+		if rr := makeRequest(t, r, "DELETE", path, nil, bidderToken); rr.Code != http.StatusOK {
+			t.Errorf("expected 200, got %d", rr.Code)
+		}
+	})
+
+	t.Run("push not configured", func(t *testing.T) {
+		disabled := handler.NewPushHandler(service.NewPushService(subscriptionRepo, nil, 10))
+		r := createTestRouter()
+		r.With(authMiddleware.RequireAuth).Post("/api/users/me/push-subscriptions", disabled.Subscribe)
+
+		rr := makeRequest(t, r, "POST", "/api/users/me/push-subscriptions", laptop.subscription(pushServer.URL+"/laptop"), bidderToken)
+		if rr.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected 503, got %d", rr.Code)
+		}
+	})
+}
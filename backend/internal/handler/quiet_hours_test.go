@@ -0,0 +1,217 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock quiet hours repository
+type mockQuietHoursRepo struct {
+	mu         sync.Mutex
+	quietHours map[uuid.UUID]domain.QuietHours
+	held       []domain.HeldNotification
+}
+
+func (r *mockQuietHoursRepo) Get(ctx context.Context, userID uuid.UUID) (*domain.QuietHours, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	quietHours, ok := r.quietHours[userID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &quietHours, nil
+}
+
+func (r *mockQuietHoursRepo) Save(ctx context.Context, quietHours *domain.QuietHours) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	quietHours.UpdatedAt = time.Now()
+	r.quietHours[quietHours.UserID] = *quietHours
+	return nil
+}
+
+func (r *mockQuietHoursRepo) Hold(ctx context.Context, held *domain.HeldNotification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	held.ID = uuid.New()
+	held.CreatedAt = time.Now()
+	r.held = append(r.held, *held)
+	return nil
+}
+
+func (r *mockQuietHoursRepo) ReleaseDue(ctx context.Context, before time.Time, limit int) ([]domain.HeldNotification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	due, kept := make([]domain.HeldNotification, 0), make([]domain.HeldNotification, 0)
+	for _, held := range r.held {
+		if !held.ReleaseAt.After(before) && len(due) < limit {
+			due = append(due, held)
+		} else {
+			kept = append(kept, held)
+		}
+	}
+	r.held = kept
+	return due, nil
+}
+
+// dueNow brings every held notification's release forward, as if the quiet
+// hours had ended
+func (r *mockQuietHoursRepo) dueNow() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.held {
+		r.held[i].ReleaseAt = time.Now().Add(-time.Second)
+	}
+}
+
+func TestUserHandler_QuietHours(t *testing.T) {
+	quietHoursRepo := &mockQuietHoursRepo{quietHours: make(map[uuid.UUID]domain.QuietHours)}
+	userRepo := newMockUserRepo()
+	emailSender := &mockEmailSender{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	notificationService := service.NewNotificationService(
+		&mockNotificationRepo{},
+		userRepo,
+		newMockWatchlistRepo(),
+		nil,
+		quietHoursRepo,
+		nil,
+		nil,
+		emailSender,
+		nil,
+		nil,
+		nil,
+		nil,
+		"http://localhost",
+	)
+	userHandler := handler.NewUserHandler(nil, notificationService)
+
+	r := createTestRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Get("/api/notifications/quiet-hours", userHandler.GetQuietHours)
+		r.Put("/api/notifications/quiet-hours", userHandler.SetQuietHours)
+	})
+
+	sleeper := &domain.User{Email: "sleeper@example.com", Username: "sleeper"}
+	userRepo.Create(context.Background(), sleeper)
+	token, _ := jwtManager.GenerateAccessToken(sleeper.ID, "user")
+
+	auction := &domain.Auction{ID: uuid.New(), Title: "Charizard", CurrentPrice: decimal.NewFromInt(120)}
+
+	// Quiet hours around the current time in Tokyo, so they are on now
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	local := time.Now().In(tokyo)
+	quietNow := map[string]interface{}{
+		"enabled":        true,
+		"start":          local.Add(-time.Hour).Format("15:04"),
+		"end":            local.Add(time.Hour).Format("15:04"),
+		"timezone":       "Asia/Tokyo",
+		"allow_critical": true,
+	}
+
+	t.Run("off by default", func(t *testing.T) {
+		rr := makeRequest(t, r, "GET", "/api/notifications/quiet-hours", nil, token)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var quietHours domain.QuietHours
+		data, _ := json.Marshal(parseResponse(t, rr).Data)
+		json.Unmarshal(data, &quietHours)
+		if quietHours.Enabled || quietHours.Start != "22:00" || quietHours.End != "07:00" {
+			t.Errorf("got %+v", quietHours)
+		}
+
+		notificationService.NotifyOutbid(context.Background(), sleeper.ID, auction, decimal.NewFromInt(130))
+		if len(emailSender.sentEmails) != 1 {
+			t.Errorf("expected the email sent, got %d", len(emailSender.sentEmails))
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, body := range map[string]map[string]interface{}{
+			"unknown timezone": {"start": "22:00", "end": "07:00", "timezone": "Mars/Olympus_Mons"},
+			"bad time":         {"start": "10pm", "end": "07:00", "timezone": "UTC"},
+			"empty window":     {"start": "07:00", "end": "07:00", "timezone": "UTC"},
+		} {
+			rr := makeRequest(t, r, "PUT", "/api/notifications/quiet-hours", body, token)
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", name, rr.Code)
+			}
+		}
+	})
+
+	t.Run("held and sent as a digest", func(t *testing.T) {
+		rr := makeRequest(t, r, "PUT", "/api/notifications/quiet-hours", quietNow, token)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		emailSender.sentEmails, emailSender.bodies = nil, nil
+
+		ctx := context.Background()
+		notificationService.NotifyOutbid(ctx, sleeper.ID, auction, decimal.NewFromInt(140))
+		notificationService.NotifyAuctionLost(ctx, sleeper.ID, auction)
+		if len(emailSender.sentEmails) != 0 {
+			t.Fatalf("expected nothing sent during quiet hours, got %d", len(emailSender.sentEmails))
+		}
+		if len(quietHoursRepo.held) != 2 {
+			t.Fatalf("expected 2 held, got %d", len(quietHoursRepo.held))
+		}
+		if releaseAt := quietHoursRepo.held[0].ReleaseAt; releaseAt.Sub(time.Now()) > time.Hour+time.Minute || !releaseAt.After(time.Now()) {
+			t.Errorf("expected release when the quiet hours end, got %s", releaseAt)
+		}
+
+		// Critical notifications break through
+		notificationService.NotifyAuctionWon(ctx, sleeper.ID, auction)
+		if len(emailSender.sentEmails) != 1 {
+			t.Fatalf("expected the win sent, got %d", len(emailSender.sentEmails))
+		}
+
+		// Nothing is due until the quiet hours end
+		if sent, _ := notificationService.SendQuietHoursDigests(ctx, 100); sent != 0 {
+			t.Errorf("expected no digest yet, got %d", sent)
+		}
+
+		quietHoursRepo.dueNow()
+		sent, err := notificationService.SendQuietHoursDigests(ctx, 100)
+		if err != nil || sent != 1 {
+			t.Fatalf("expected one digest, got %d, %v", sent, err)
+		}
+		digest := emailSender.bodies[len(emailSender.bodies)-1]
+		if !strings.Contains(digest, "outbid on Charizard") || !strings.Contains(digest, "Auction ended: Charizard") {
+			t.Errorf("expected both held emails in the digest, got %q", digest)
+		}
+		if len(quietHoursRepo.held) != 0 {
+			t.Errorf("expected nothing left held, got %d", len(quietHoursRepo.held))
+		}
+	})
+
+	t.Run("critical held when not let through", func(t *testing.T) {
+		quietNow["allow_critical"] = false
+		rr := makeRequest(t, r, "PUT", "/api/notifications/quiet-hours", quietNow, token)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		emailSender.sentEmails = nil
+
+		notificationService.NotifyAuctionWon(context.Background(), sleeper.ID, auction)
+		if len(emailSender.sentEmails) != 0 || len(quietHoursRepo.held) != 1 {
+			t.Errorf("expected the win held, got %d sent, %d held", len(emailSender.sentEmails), len(quietHoursRepo.held))
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type RecommendationHandler struct {
+	recommendationService *service.RecommendationService
+}
+
+func NewRecommendationHandler(recommendationService *service.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{recommendationService: recommendationService}
+}
+
+// Similar lists active auctions related to the given one
+func (h *RecommendationHandler) Similar(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	limit := getQueryParamInt(r, "limit", domain.DefaultRecommendationLimit)
+	auctions, err := h.recommendationService.Similar(r.Context(), auctionID, getUserID(r), r.URL.Query().Get("share"), limit)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, auctions)
+}
+
+// Recommended lists active auctions the current user may like
+func (h *RecommendationHandler) Recommended(w http.ResponseWriter, r *http.Request) {
+	limit := getQueryParamInt(r, "limit", domain.DefaultRecommendationLimit)
+	auctions, err := h.recommendationService.Recommended(r.Context(), getUserID(r), limit)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, auctions)
+}
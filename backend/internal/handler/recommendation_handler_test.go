@@ -0,0 +1,131 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock recommendation repository returning fixed candidates
+type mockRecommendationRepo struct {
+	similar     []uuid.UUID
+	recommended map[uuid.UUID][]uuid.UUID
+	lastQuery   *domain.SimilarAuctionQuery
+}
+
+func (r *mockRecommendationRepo) GetSimilarCandidates(ctx context.Context, query *domain.SimilarAuctionQuery, limit int) ([]uuid.UUID, error) {
+	r.lastQuery = query
+	return r.similar, nil
+}
+
+func (r *mockRecommendationRepo) GetRecommendedCandidates(ctx context.Context, userID uuid.UUID, limit int) ([]uuid.UUID, error) {
+	return r.recommended[userID], nil
+}
+
+func TestRecommendationHandler(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	recommendationRepo := &mockRecommendationRepo{recommended: make(map[uuid.UUID][]uuid.UUID)}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil, // no S3 for tests
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	recommendationHandler := handler.NewRecommendationHandler(
+		service.NewRecommendationService(recommendationRepo, auctionService, nil),
+	)
+
+	r := createTestRouter()
+	r.With(authMiddleware.OptionalAuth).Get("/api/auctions/{id}/similar", recommendationHandler.Similar)
+	r.With(authMiddleware.RequireAuth).Get("/api/users/me/recommended", recommendationHandler.Recommended)
+
+	categoryID := uuid.New()
+	newAuction := func(title string, status domain.AuctionStatus, visibility domain.AuctionVisibility) *domain.Auction {
+		auction := &domain.Auction{
+			SellerID:      uuid.New(),
+			CategoryID:    &categoryID,
+			Title:         title,
+			StartingPrice: decimal.NewFromFloat(20),
+			CurrentPrice:  decimal.NewFromFloat(25),
+			BidIncrement:  decimal.NewFromFloat(1),
+			StartTime:     time.Now().Add(-time.Hour),
+			EndTime:       time.Now().Add(24 * time.Hour),
+			Status:        status,
+			Visibility:    visibility,
+		}
+		auctionRepo.Create(context.Background(), auction)
+		return auction
+	}
+
+	source := newAuction("Charizard Base Set Holo, of 1999", domain.AuctionStatusActive, domain.AuctionPublic)
+	ended := newAuction("Charizard Jungle", domain.AuctionStatusCompleted, domain.AuctionPublic)
+	hidden := newAuction("Charizard Fossil", domain.AuctionStatusActive, domain.AuctionPrivate)
+	first := newAuction("Charizard Base Set", domain.AuctionStatusActive, domain.AuctionPublic)
+	second := newAuction("Blastoise Base Set", domain.AuctionStatusActive, domain.AuctionPublic)
+	recommendationRepo.similar = []uuid.UUID{ended.ID, hidden.ID, first.ID, second.ID}
+
+	similarPath := "/api/auctions/" + source.ID.String() + "/similar"
+	rr := makeRequest(t, r, "GET", similarPath, nil, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	auctions := parseResponse(t, rr).Data.([]interface{})
+	if len(auctions) != 2 ||
+		auctions[0].(map[string]interface{})["id"] != first.ID.String() ||
+		auctions[1].(map[string]interface{})["id"] != second.ID.String() {
+		t.Errorf("expected only the open public candidates in order, got %v", auctions)
+	}
+
+	query := recommendationRepo.lastQuery
+	if query == nil || query.TitleQuery != "charizard | base | set | holo | 1999" || *query.CategoryID != categoryID {
+		t.Errorf("unexpected similar query %+v", query)
+	}
+
+	rr = makeRequest(t, r, "GET", similarPath+"?limit=1", nil, "")
+	if got := len(parseResponse(t, rr).Data.([]interface{})); got != 1 {
+		t.Errorf("expected the limit to apply, got %d auctions", got)
+	}
+
+	rr = makeRequest(t, r, "GET", "/api/auctions/"+hidden.ID.String()+"/similar", nil, "")
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a private auction the viewer can't see, got %v", rr.Code)
+	}
+
+	rr = makeRequest(t, r, "GET", "/api/users/me/recommended", nil, "")
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected recommendations to need sign in, got %v", rr.Code)
+	}
+
+	userID := uuid.New()
+	token, _ := jwtManager.GenerateAccessToken(userID, "user")
+	recommendationRepo.recommended[userID] = []uuid.UUID{second.ID, ended.ID}
+
+	rr = makeRequest(t, r, "GET", "/api/users/me/recommended", nil, token)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	auctions = parseResponse(t, rr).Data.([]interface{})
+	if len(auctions) != 1 || auctions[0].(map[string]interface{})["id"] != second.ID.String() {
+		t.Errorf("expected the user's open candidates, got %v", auctions)
+	}
+}
@@ -0,0 +1,75 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/google/uuid"
+)
+
+func TestResidency_RedactsTranscriptsOutsideRegion(t *testing.T) {
+	transcriptHandler := func(w http.ResponseWriter, r *http.Request) {
+		transcript := &domain.ConversationTranscript{
+			Messages: []domain.MessageWithSender{{
+				Message: domain.Message{
+					ID:              uuid.New(),
+					Content:         "My address is 1 Rue de Rivoli, Paris",
+					ModerationFlags: []string{"off_platform"},
+				},
+			}},
+		}
+		if middleware.IsCrossRegion(r.Context()) {
+			transcript.Redact()
+		}
+		json.NewEncoder(w).Encode(transcript)
+	}
+
+	tests := []struct {
+		name         string
+		region       string
+		country      string
+		wantRedacted bool
+	}{
+		{name: "admin in the region", region: "eu", country: "FR"},
+		{name: "admin in the EEA", region: "eu", country: "no"},
+		{name: "admin outside the region", region: "eu", country: "US", wantRedacted: true},
+		{name: "admin without a country", region: "eu", wantRedacted: true},
+		{name: "no data region", country: "US"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := createTestRouter()
+			r.With(middleware.MarkCrossRegion(tt.region, "CF-IPCountry")).Post("/transcript", transcriptHandler)
+
+			req := httptest.NewRequest("POST", "/transcript", nil)
+			if tt.country != "" {
+				req.Header.Set("CF-IPCountry", tt.country)
+			}
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			var transcript domain.ConversationTranscript
+			if err := json.NewDecoder(rr.Body).Decode(&transcript); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			msg := transcript.Messages[0]
+			if transcript.Redacted != tt.wantRedacted {
+				t.Errorf("got redacted %v, want %v", transcript.Redacted, tt.wantRedacted)
+			}
+			if tt.wantRedacted && msg.Content != "" {
+				t.Errorf("expected the content to be stripped, got %q", msg.Content)
+			}
+			if !tt.wantRedacted && msg.Content == "" {
+				t.Error("expected the content to be kept")
+			}
+			if len(msg.ModerationFlags) != 1 {
+				t.Errorf("expected moderation flags to be kept, got %v", msg.ModerationFlags)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type SellerAlertHandler struct {
+	sellerAlertService *service.SellerAlertService
+}
+
+func NewSellerAlertHandler(sellerAlertService *service.SellerAlertService) *SellerAlertHandler {
+	return &SellerAlertHandler{sellerAlertService: sellerAlertService}
+}
+
+// GetAlerts shows which bids on the listing its seller is notified of
+func (h *SellerAlertHandler) GetAlerts(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	alerts, err := h.sellerAlertService.GetAlerts(r.Context(), auctionID, getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, alerts)
+}
+
+// SetAlerts chooses which bids on the listing its seller is notified of
+func (h *SellerAlertHandler) SetAlerts(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	var req domain.SetSellerBidAlertsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	alerts, err := h.sellerAlertService.SetAlerts(r.Context(), auctionID, getUserID(r), &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, alerts)
+}
+
+// ResetAlerts goes back to notifying the seller of every bid
+func (h *SellerAlertHandler) ResetAlerts(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	alerts, err := h.sellerAlertService.ResetAlerts(r.Context(), auctionID, getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, alerts)
+}
@@ -0,0 +1,230 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock seller bid alert repository
+type mockSellerBidAlertRepo struct {
+	mu     sync.Mutex
+	alerts map[uuid.UUID]domain.SellerBidAlerts
+}
+
+func (r *mockSellerBidAlertRepo) Get(ctx context.Context, auctionID uuid.UUID) (*domain.SellerBidAlerts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	alerts, ok := r.alerts[auctionID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &alerts, nil
+}
+
+func (r *mockSellerBidAlertRepo) Save(ctx context.Context, alerts *domain.SellerBidAlerts) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	alerts.CreatedAt, alerts.UpdatedAt = &now, &now
+	r.alerts[alerts.AuctionID] = *alerts
+	return nil
+}
+
+func (r *mockSellerBidAlertRepo) Delete(ctx context.Context, auctionID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.alerts, auctionID)
+	return nil
+}
+
+func TestSellerAlertHandler(t *testing.T) {
+	var mu sync.Mutex
+	auctionRepo := &lockedAuctionRepo{mockAuctionRepo: newMockAuctionRepo(), mu: &mu}
+	alertRepo := &mockSellerBidAlertRepo{alerts: make(map[uuid.UUID]domain.SellerBidAlerts)}
+	notificationRepo := &mockNotificationRepo{}
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	notificationService := service.NewNotificationService(
+		notificationRepo,
+		newMockUserRepo(),
+		newMockWatchlistRepo(),
+		alertRepo,
+		nil,
+		nil,
+		nil,
+		&mockEmailSender{},
+		nil,
+		nil,
+		nil,
+		nil,
+		"http://localhost",
+	)
+	bidService := service.NewBidService(
+		newMockBidRepo(),
+		auctionRepo,
+		nil,
+		nil,
+		nil,
+		nil,
+		&mockTxManager{},
+		notificationService,
+		nil,      // no webhooks for tests
+		nil, nil, // no order service for tests
+		nil,
+		nil, // no redis for tests
+	)
+	alertHandler := handler.NewSellerAlertHandler(service.NewSellerAlertService(alertRepo, auctionRepo))
+
+	r := createTestRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Post("/api/auctions/{id}/bids", handler.NewBidHandler(bidService).PlaceBid)
+		r.Get("/api/auctions/{id}/seller-alerts", alertHandler.GetAlerts)
+		r.Put("/api/auctions/{id}/seller-alerts", alertHandler.SetAlerts)
+		r.Delete("/api/auctions/{id}/seller-alerts", alertHandler.ResetAlerts)
+	})
+
+	sellerID := uuid.New()
+	sellerToken, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+
+	newAuction := func() *domain.Auction {
+		reservePrice := decimal.NewFromFloat(135)
+		auction := &domain.Auction{
+			SellerID:      sellerID,
+			Title:         "Blastoise Holo",
+			StartingPrice: decimal.NewFromFloat(100),
+			CurrentPrice:  decimal.NewFromFloat(100),
+			ReservePrice:  &reservePrice,
+			BidIncrement:  decimal.NewFromFloat(5),
+			StartTime:     time.Now().Add(-1 * time.Hour),
+			EndTime:       time.Now().Add(24 * time.Hour),
+			Status:        domain.AuctionStatusActive,
+		}
+		auctionRepo.mockAuctionRepo.Create(context.Background(), auction)
+		return auction
+	}
+
+	alertsPath := func(auction *domain.Auction) string {
+		return "/api/auctions/" + auction.ID.String() + "/seller-alerts"
+	}
+
+	// sellerNotifications counts the seller's notifications of a type on the
+	// auction once the background sends have settled
+	sellerNotifications := func(auction *domain.Auction, notificationType domain.NotificationType, want int) int {
+		count := func() int {
+			notificationRepo.mu.Lock()
+			defer notificationRepo.mu.Unlock()
+			n := 0
+			for _, notification := range notificationRepo.notifications {
+				if notification.UserID == sellerID && notification.Type == notificationType &&
+					notification.AuctionID != nil && *notification.AuctionID == auction.ID {
+					n++
+				}
+			}
+			return n
+		}
+
+		deadline := time.Now().Add(time.Second)
+		for count() < want && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		time.Sleep(20 * time.Millisecond)
+		return count()
+	}
+
+	placeBids := func(t *testing.T, auction *domain.Auction, amounts ...int) {
+		t.Helper()
+		for _, amount := range amounts {
+			token, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+			rr := makeRequest(t, r, "POST", "/api/auctions/"+auction.ID.String()+"/bids", domain.PlaceBidRequest{Amount: fmt.Sprintf("%d.00", amount)}, token)
+			if rr.Code != http.StatusCreated {
+				t.Fatalf("bid of %d got status %d", amount, rr.Code)
+			}
+		}
+	}
+
+	t.Run("every bid by default", func(t *testing.T) {
+		auction := newAuction()
+
+		rr := makeRequest(t, r, "GET", alertsPath(auction), nil, sellerToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		data := parseResponse(t, rr).Data.(map[string]interface{})
+		if data["every_n_bids"] != float64(1) || data["first_bid"] != true || data["reserve_met"] != true {
+			t.Errorf("got defaults %v", data)
+		}
+
+		placeBids(t, auction, 110, 120, 130, 140)
+		if n := sellerNotifications(auction, domain.NotificationNewBid, 4); n != 4 {
+			t.Errorf("expected a notification per bid, got %d", n)
+		}
+		if n := sellerNotifications(auction, domain.NotificationReserveMet, 1); n != 1 {
+			t.Errorf("expected to hear the reserve was met, got %d", n)
+		}
+	})
+
+	t.Run("thresholds", func(t *testing.T) {
+		auction := newAuction()
+
+		rr := makeRequest(t, r, "PUT", alertsPath(auction), map[string]interface{}{
+			"first_bid":    true,
+			"reserve_met":  false,
+			"every_n_bids": 5,
+			"price_above":  "150.00",
+		}, sellerToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		// The first bid, the fifth, and the one taking the price past 150
+		placeBids(t, auction, 110, 120, 130, 140, 150, 160, 170)
+		if n := sellerNotifications(auction, domain.NotificationNewBid, 3); n != 3 {
+			t.Errorf("expected 3 notifications, got %d", n)
+		}
+		if n := sellerNotifications(auction, domain.NotificationReserveMet, 0); n != 0 {
+			t.Errorf("expected no reserve notification, got %d", n)
+		}
+
+		// Resetting goes back to every bid
+		if rr := makeRequest(t, r, "DELETE", alertsPath(auction), nil, sellerToken); rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		placeBids(t, auction, 180)
+		if n := sellerNotifications(auction, domain.NotificationNewBid, 4); n != 4 {
+			t.Errorf("expected the next bid to notify, got %d", n)
+		}
+	})
+
+	t.Run("invalid price", func(t *testing.T) {
+		rr := makeRequest(t, r, "PUT", alertsPath(newAuction()), map[string]interface{}{"price_above": "lots"}, sellerToken)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rr.Code)
+		}
+	})
+
+	t.Run("another user's listing", func(t *testing.T) {
+		auction := newAuction()
+		otherToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+
+		if rr := makeRequest(t, r, "GET", alertsPath(auction), nil, otherToken); rr.Code != http.StatusForbidden {
+			t.Errorf("expected 403 reading, got %d", rr.Code)
+		}
+		if rr := makeRequest(t, r, "PUT", alertsPath(auction), map[string]interface{}{"every_n_bids": 0}, otherToken); rr.Code != http.StatusForbidden {
+			t.Errorf("expected 403 setting, got %d", rr.Code)
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type ListingShareHandler struct {
+	shareService *service.ListingShareService
+}
+
+func NewListingShareHandler(shareService *service.ListingShareService) *ListingShareHandler {
+	return &ListingShareHandler{shareService: shareService}
+}
+
+// Create returns the user's short link for sharing the auction, the same one
+// each time they ask
+func (h *ListingShareHandler) Create(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	share, err := h.shareService.Create(r.Context(), auctionID, getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, share)
+}
+
+// Resolve tells apps where a short link leads and counts the visit
+func (h *ListingShareHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	link, err := h.shareService.Resolve(r.Context(), chi.URLParam(r, "code"), getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, link)
+}
@@ -0,0 +1,157 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock listing share repository
+type mockListingShareRepo struct {
+	shares map[string]*domain.ListingShare
+	clicks map[uuid.UUID][]*uuid.UUID
+}
+
+func newMockListingShareRepo() *mockListingShareRepo {
+	return &mockListingShareRepo{
+		shares: make(map[string]*domain.ListingShare),
+		clicks: make(map[uuid.UUID][]*uuid.UUID),
+	}
+}
+
+func (r *mockListingShareRepo) GetOrCreate(ctx context.Context, share *domain.ListingShare) error {
+	for _, existing := range r.shares {
+		if existing.AuctionID == share.AuctionID && existing.SharerID == share.SharerID {
+			share.ID, share.Code, share.CreatedAt = existing.ID, existing.Code, existing.CreatedAt
+			return nil
+		}
+	}
+	if _, ok := r.shares[share.Code]; ok {
+		return domain.ErrConflict
+	}
+
+	share.ID = uuid.New()
+	share.CreatedAt = time.Now()
+	stored := *share
+	r.shares[share.Code] = &stored
+	return nil
+}
+
+func (r *mockListingShareRepo) GetByCode(ctx context.Context, code string) (*domain.ListingShare, error) {
+	share, ok := r.shares[code]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return share, nil
+}
+
+func (r *mockListingShareRepo) RecordClick(ctx context.Context, shareID uuid.UUID, userID *uuid.UUID) error {
+	r.clicks[shareID] = append(r.clicks[shareID], userID)
+	return nil
+}
+
+func TestListingShareHandler(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	userRepo := newMockUserRepo()
+	shareRepo := newMockListingShareRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil, // no S3 for tests
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	shareHandler := handler.NewListingShareHandler(
+		service.NewListingShareService(shareRepo, auctionService, "https://cards.example.com"),
+	)
+
+	r := createTestRouter()
+	r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/shares", shareHandler.Create)
+	r.With(authMiddleware.OptionalAuth).Get("/api/links/{code}", shareHandler.Resolve)
+
+	sharer := &domain.User{Username: "sharer"}
+	visitor := &domain.User{Username: "visitor"}
+	for _, user := range []*domain.User{sharer, visitor} {
+		userRepo.Create(context.Background(), user)
+	}
+	sharerToken, _ := jwtManager.GenerateAccessToken(sharer.ID, "user")
+	visitorToken, _ := jwtManager.GenerateAccessToken(visitor.ID, "user")
+
+	auction := &domain.Auction{
+		SellerID:      uuid.New(),
+		Title:         "Shared Holo Card",
+		StartingPrice: decimal.NewFromFloat(10),
+		CurrentPrice:  decimal.NewFromFloat(10),
+		BidIncrement:  decimal.NewFromFloat(1),
+		StartTime:     time.Now(),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+		Visibility:    domain.AuctionPublic,
+	}
+	auctionRepo.Create(context.Background(), auction)
+	sharesPath := "/api/auctions/" + auction.ID.String() + "/shares"
+
+	rr := makeRequest(t, r, "POST", sharesPath, nil, "")
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected sharing to need sign in, got %v", rr.Code)
+	}
+
+	rr = makeRequest(t, r, "POST", sharesPath, nil, sharerToken)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	share := parseResponse(t, rr).Data.(map[string]interface{})
+	code := share["code"].(string)
+	if share["url"] != "https://cards.example.com/s/"+code {
+		t.Errorf("unexpected share url %v", share["url"])
+	}
+
+	rr = makeRequest(t, r, "POST", sharesPath, nil, sharerToken)
+	if got := parseResponse(t, rr).Data.(map[string]interface{})["code"]; got != code {
+		t.Errorf("expected sharing again to return the same code, got %v", got)
+	}
+
+	rr = makeRequest(t, r, "GET", "/api/links/"+code, nil, visitorToken)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	link := parseResponse(t, rr).Data.(map[string]interface{})
+	if link["type"] != "auction" || link["auction_id"] != auction.ID.String() {
+		t.Errorf("expected the link to open the auction, got %v", link)
+	}
+
+	makeRequest(t, r, "GET", "/api/links/"+code, nil, "")
+	makeRequest(t, r, "GET", "/api/links/"+code, nil, sharerToken)
+
+	clicks := shareRepo.clicks[shareRepo.shares[code].ID]
+	if len(clicks) != 2 {
+		t.Fatalf("expected 2 clicks without the sharer's own, got %d", len(clicks))
+	}
+	if clicks[0] == nil || *clicks[0] != visitor.ID || clicks[1] != nil {
+		t.Errorf("expected the signed in visit attributed and the anonymous one not, got %v", clicks)
+	}
+
+	rr = makeRequest(t, r, "GET", "/api/links/unknown1", nil, "")
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown code, got %v", rr.Code)
+	}
+}
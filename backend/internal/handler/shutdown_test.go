@@ -0,0 +1,146 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// Soak test: bids keep arriving from many clients while the server shuts
+// down. Every bid admitted must finish before the later steps run, and none
+// may be admitted once shutdown has started.
+func TestShutdown_DrainsInFlightBids(t *testing.T) {
+	coordinator := service.NewShutdownCoordinator()
+
+	var admitted, finished atomic.Int64
+	r := chi.NewRouter()
+	r.With(middleware.RejectDuringShutdown(coordinator)).Post("/bids", func(w http.ResponseWriter, r *http.Request) {
+		admitted.Add(1)
+		time.Sleep(2 * time.Millisecond)
+		finished.Add(1)
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	var admittedAtStep, finishedAtStep int64
+	coordinator.OnShutdown("notification queue", func(ctx context.Context) error {
+		admittedAtStep = admitted.Load()
+		finishedAtStep = finished.Load()
+		return nil
+	})
+
+	var stop atomic.Bool
+	var rejected, unexpected atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !stop.Load() {
+				rr := makeRequest(t, r, "POST", "/bids", nil, "")
+				switch rr.Code {
+				case http.StatusCreated:
+				case http.StatusServiceUnavailable:
+					rejected.Add(1)
+				default:
+					unexpected.Add(1)
+				}
+			}
+		}()
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	report := coordinator.Shutdown(ctx)
+	admittedAfter := admitted.Load()
+
+	time.Sleep(20 * time.Millisecond)
+	stop.Store(true)
+	wg.Wait()
+
+	if abandoned := report.Abandoned(); len(abandoned) > 0 {
+		t.Fatalf("expected a clean shutdown, abandoned %v", abandoned)
+	}
+	if admittedAtStep == 0 || admittedAtStep != finishedAtStep {
+		t.Errorf("%d bids admitted but %d finished when the next step ran", admittedAtStep, finishedAtStep)
+	}
+	if admitted.Load() != admittedAfter {
+		t.Errorf("%d bids admitted after shutdown started", admitted.Load()-admittedAfter)
+	}
+	if rejected.Load() == 0 {
+		t.Error("expected bids arriving during shutdown to be rejected")
+	}
+	if unexpected.Load() > 0 {
+		t.Errorf("%d requests got an unexpected status", unexpected.Load())
+	}
+
+	rr := makeRequest(t, r, "POST", "/bids", nil, "")
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 after shutdown, got %d", rr.Code)
+	}
+	if response := parseResponse(t, rr); response.Error == nil || response.Error.Code != "SHUTTING_DOWN" {
+		t.Errorf("got error %v", response.Error)
+	}
+	if rr.Header().Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header")
+	}
+}
+
+func TestShutdown_ReportsAbandonedWork(t *testing.T) {
+	coordinator := service.NewShutdownCoordinator()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	r := chi.NewRouter()
+	r.With(middleware.RejectDuringShutdown(coordinator)).Post("/bids", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	var stepRan atomic.Bool
+	coordinator.OnShutdown("scheduler", func(ctx context.Context) error {
+		stepRan.Store(true)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		makeRequest(t, r, "POST", "/bids", nil, "")
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	report := coordinator.Shutdown(ctx)
+
+	close(release)
+	wg.Wait()
+
+	if !stepRan.Load() {
+		t.Error("expected later steps to run after the deadline")
+	}
+
+	abandoned := report.Abandoned()
+	if len(abandoned) != 2 {
+		t.Fatalf("expected 2 abandoned steps, got %v", abandoned)
+	}
+	if abandoned[0].Name != "in-flight bids" || !strings.Contains(abandoned[0].Err.Error(), "1 bids still in flight") {
+		t.Errorf("got %s: %v", abandoned[0].Name, abandoned[0].Err)
+	}
+	if abandoned[1].Name != "scheduler" {
+		t.Errorf("got %s", abandoned[1].Name)
+	}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type SnipeHandler struct {
+	snipeService *service.SnipeService
+}
+
+func NewSnipeHandler(snipeService *service.SnipeService) *SnipeHandler {
+	return &SnipeHandler{snipeService: snipeService}
+}
+
+func (h *SnipeHandler) Schedule(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	var req domain.ScheduleSnipeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	userID := getUserID(r)
+	snipe, err := h.snipeService.Schedule(r.Context(), auctionID, userID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, snipe)
+}
+
+func (h *SnipeHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	auctionID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid auction ID")
+		return
+	}
+
+	userID := getUserID(r)
+	if err := h.snipeService.Cancel(r.Context(), auctionID, userID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Snipe cancelled",
+	})
+}
+
+func (h *SnipeHandler) GetMySnipes(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	snipes, err := h.snipeService.GetMySnipes(r.Context(), userID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, snipes)
+}
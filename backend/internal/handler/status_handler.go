@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/service"
+)
+
+type StatusHandler struct {
+	statusService *service.StatusService
+}
+
+func NewStatusHandler(statusService *service.StatusService) *StatusHandler {
+	return &StatusHandler{statusService: statusService}
+}
+
+// GetStatus serves what a public status page shows. It is cached, here and
+// by whoever sits in front of the API, for as long as the service keeps it.
+func (h *StatusHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=30")
+	respondJSON(w, http.StatusOK, h.statusService.Status(r.Context()))
+}
+
+func (h *StatusHandler) AdminListIncidents(w http.ResponseWriter, r *http.Request) {
+	incidents, err := h.statusService.ListIncidents(r.Context())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, incidents)
+}
+
+func (h *StatusHandler) AdminCreateIncident(w http.ResponseWriter, r *http.Request) {
+	var req domain.CreateStatusIncidentRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	incident, err := h.statusService.CreateIncident(r.Context(), getUserID(r), &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, incident)
+}
+
+func (h *StatusHandler) AdminUpdateIncident(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid incident ID")
+		return
+	}
+
+	var req domain.UpdateStatusIncidentRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	incident, err := h.statusService.UpdateIncident(r.Context(), id, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, incident)
+}
+
+func (h *StatusHandler) AdminDeleteIncident(w http.ResponseWriter, r *http.Request) {
+	id, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid incident ID")
+		return
+	}
+
+	if err := h.statusService.DeleteIncident(r.Context(), id); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Incident deleted"})
+}
@@ -0,0 +1,255 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/pkg/breaker"
+	"github.com/auction-cards/backend/internal/pkg/errorrate"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Mock status incident repository
+type mockStatusIncidentRepo struct {
+	mu        sync.Mutex
+	incidents []domain.StatusIncident
+}
+
+func (r *mockStatusIncidentRepo) Create(ctx context.Context, incident *domain.StatusIncident) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	incident.ID = uuid.New()
+	incident.CreatedAt = time.Now()
+	incident.UpdatedAt = incident.CreatedAt
+	r.incidents = append(r.incidents, *incident)
+	return nil
+}
+
+func (r *mockStatusIncidentRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.StatusIncident, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, incident := range r.incidents {
+		if incident.ID == id {
+			return &incident, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockStatusIncidentRepo) Update(ctx context.Context, incident *domain.StatusIncident) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.incidents {
+		if r.incidents[i].ID == incident.ID {
+			incident.UpdatedAt = time.Now()
+			r.incidents[i] = *incident
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func (r *mockStatusIncidentRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.incidents {
+		if r.incidents[i].ID == id {
+			r.incidents = append(r.incidents[:i], r.incidents[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func (r *mockStatusIncidentRepo) ListRecent(ctx context.Context, resolvedSince time.Time) ([]domain.StatusIncident, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	incidents := make([]domain.StatusIncident, 0)
+	for i := len(r.incidents) - 1; i >= 0; i-- {
+		incident := r.incidents[i]
+		if incident.ResolvedAt == nil || !incident.ResolvedAt.Before(resolvedSince) {
+			incidents = append(incidents, incident)
+		}
+	}
+	return incidents, nil
+}
+
+func TestStatusHandler(t *testing.T) {
+	incidentRepo := &mockStatusIncidentRepo{}
+	s3Breaker := breaker.New("s3", 1, time.Minute)
+	errorRates := errorrate.New(15*time.Minute, time.Minute)
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	statusHandler := handler.NewStatusHandler(service.NewStatusService(incidentRepo, &mockPinger{}, errorRates, nil, s3Breaker))
+
+	r := chi.NewRouter()
+	r.Use(middleware.ErrorRates(errorRates))
+	r.Get("/api/status", statusHandler.GetStatus)
+	r.Post("/api/auctions/{id}/bids", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Use(authMiddleware.RequireAdmin)
+		r.Get("/api/admin/status-incidents", statusHandler.AdminListIncidents)
+		r.Post("/api/admin/status-incidents", statusHandler.AdminCreateIncident)
+		r.Put("/api/admin/status-incidents/{id}", statusHandler.AdminUpdateIncident)
+		r.Delete("/api/admin/status-incidents/{id}", statusHandler.AdminDeleteIncident)
+	})
+
+	adminToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "admin")
+	userToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+
+	getStatus := func(t *testing.T) domain.ServiceStatus {
+		t.Helper()
+		rr := makeRequest(t, r, "GET", "/api/status", nil, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if cacheControl := rr.Header().Get("Cache-Control"); cacheControl != "public, max-age=30" {
+			t.Errorf("got Cache-Control %q", cacheControl)
+		}
+		var status domain.ServiceStatus
+		data, _ := json.Marshal(parseResponse(t, rr).Data)
+		if err := json.Unmarshal(data, &status); err != nil {
+			t.Fatalf("failed to decode status: %v", err)
+		}
+		return status
+	}
+
+	componentHealth := func(status domain.ServiceStatus) map[domain.StatusComponent]domain.ComponentHealth {
+		health := make(map[domain.StatusComponent]domain.ComponentHealth)
+		for _, component := range status.Components {
+			health[component.Name] = component.Status
+		}
+		return health
+	}
+
+	createIncident := func(t *testing.T, body map[string]interface{}) domain.StatusIncident {
+		t.Helper()
+		rr := makeRequest(t, r, "POST", "/api/admin/status-incidents", body, adminToken)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var incident domain.StatusIncident
+		data, _ := json.Marshal(parseResponse(t, rr).Data)
+		if err := json.Unmarshal(data, &incident); err != nil {
+			t.Fatalf("failed to decode incident: %v", err)
+		}
+		return incident
+	}
+
+	t.Run("all operational", func(t *testing.T) {
+		status := getStatus(t)
+		if status.Status != domain.ComponentOperational || len(status.Components) != len(domain.StatusComponents) {
+			t.Fatalf("got %+v", status)
+		}
+		if len(status.Incidents) != 0 || status.ErrorRateWindowSeconds != 900 {
+			t.Errorf("got %+v", status)
+		}
+	})
+
+	t.Run("failures, dependencies and incidents", func(t *testing.T) {
+		for range 20 {
+			makeRequest(t, r, "POST", "/api/auctions/"+uuid.NewString()+"/bids", nil, "")
+		}
+		s3Breaker.Do(func() error { return errors.New("connection refused") })
+
+		// Posting an incident refreshes the status straight away
+		incident := createIncident(t, map[string]interface{}{
+			"title":      "Payments delayed",
+			"message":    "Payment confirmations are slow to arrive",
+			"impact":     "major",
+			"components": []string{"payments", "payments"},
+		})
+		if incident.Status != domain.IncidentInvestigating || len(incident.Components) != 1 {
+			t.Errorf("got %+v", incident)
+		}
+
+		status := getStatus(t)
+		health := componentHealth(status)
+		if health[domain.StatusComponentBidding] != domain.ComponentOutage {
+			t.Errorf("expected bidding down after every bid failed, got %s", health[domain.StatusComponentBidding])
+		}
+		if health[domain.StatusComponentImageUploads] != domain.ComponentOutage {
+			t.Errorf("expected uploads down with storage unreachable, got %s", health[domain.StatusComponentImageUploads])
+		}
+		if health[domain.StatusComponentPayments] != domain.ComponentOutage {
+			t.Errorf("expected payments down for the incident, got %s", health[domain.StatusComponentPayments])
+		}
+		if health[domain.StatusComponentWebSocket] != domain.ComponentOperational {
+			t.Errorf("expected websockets unaffected, got %s", health[domain.StatusComponentWebSocket])
+		}
+		if status.Status != domain.ComponentOutage || len(status.Incidents) != 1 {
+			t.Errorf("got %+v", status)
+		}
+		for _, component := range status.Components {
+			if component.Name == domain.StatusComponentBidding && (component.Requests != 20 || component.ErrorRate != 1) {
+				t.Errorf("got bidding %+v", component)
+			}
+		}
+
+		// Resolving it leaves it listed but no longer counting
+		rr := makeRequest(t, r, "PUT", "/api/admin/status-incidents/"+incident.ID.String(), map[string]string{
+			"status":  "resolved",
+			"message": "Confirmations are back to normal",
+		}, adminToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		status = getStatus(t)
+		if health := componentHealth(status); health[domain.StatusComponentPayments] != domain.ComponentOperational {
+			t.Errorf("expected payments back, got %s", health[domain.StatusComponentPayments])
+		}
+		if len(status.Incidents) != 1 || status.Incidents[0].ResolvedAt == nil || status.Incidents[0].Message != "Confirmations are back to normal" {
+			t.Errorf("got incidents %+v", status.Incidents)
+		}
+
+		rr = makeRequest(t, r, "DELETE", "/api/admin/status-incidents/"+incident.ID.String(), nil, adminToken)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200 deleting, got %d", rr.Code)
+		}
+		if status := getStatus(t); len(status.Incidents) != 0 {
+			t.Errorf("expected the incident gone, got %+v", status.Incidents)
+		}
+	})
+
+	t.Run("invalid incidents", func(t *testing.T) {
+		rr := makeRequest(t, r, "POST", "/api/admin/status-incidents", map[string]interface{}{
+			"title":      "Search is down",
+			"impact":     "major",
+			"components": []string{"search"},
+		}, adminToken)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected 400 for an unknown component, got %d", rr.Code)
+		}
+
+		rr = makeRequest(t, r, "PUT", "/api/admin/status-incidents/"+uuid.NewString(), map[string]string{"status": "resolved"}, adminToken)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rr.Code)
+		}
+	})
+
+	t.Run("admins only", func(t *testing.T) {
+		rr := makeRequest(t, r, "POST", "/api/admin/status-incidents", map[string]interface{}{
+			"title":      "Bidding is slow",
+			"impact":     "minor",
+			"components": []string{"bidding"},
+		}, userToken)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rr.Code)
+		}
+	})
+}
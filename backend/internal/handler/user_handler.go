@@ -53,6 +53,61 @@ func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, user)
 }
 
+func (h *UserHandler) ChooseUsername(w http.ResponseWriter, r *http.Request) {
+	var req domain.ChooseUsernameRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	user, err := h.userService.ChooseUsername(r.Context(), getUserID(r), &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, user)
+}
+
+func (h *UserHandler) SetVacation(w http.ResponseWriter, r *http.Request) {
+	var req domain.SetVacationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	userID := getUserID(r)
+	resp, err := h.userService.SetVacation(r.Context(), userID, &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, resp)
+}
+
+func (h *UserHandler) EndVacation(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	if err := h.userService.EndVacation(r.Context(), userID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Vacation mode turned off",
+	})
+}
+
 func (h *UserHandler) GetPublicProfile(w http.ResponseWriter, r *http.Request) {
 	userID, err := getURLParamUUID(r, "id")
 	if err != nil {
@@ -66,10 +121,30 @@ func (h *UserHandler) GetPublicProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"user":   profile,
-		"rating": ratingSummary,
-	})
+	respondJSON(w, http.StatusOK, domain.UserProfile{User: profile, Rating: ratingSummary})
+}
+
+// BatchGet fetches up to 100 public profiles at once. Unknown and banned
+// users are listed as missing rather than failing the request.
+func (h *UserHandler) BatchGet(w http.ResponseWriter, r *http.Request) {
+	var req domain.BatchGetRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	result, err := h.userService.GetPublicProfiles(r.Context(), req.IDs)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
 }
 
 func (h *UserHandler) GetUserAuctions(w http.ResponseWriter, r *http.Request) {
@@ -198,6 +273,15 @@ func (h *UserHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
 		b := true
 		params.Unread = &b
 	}
+	params.Grouped = r.URL.Query().Get("grouped") == "true"
+
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		var err error
+		if params.Cursor, err = domain.DecodeCursor(cursor); err != nil {
+			handleError(w, err)
+			return
+		}
+	}
 
 	result, err := h.notificationService.GetUserNotifications(r.Context(), userID, params)
 	if err != nil {
@@ -205,14 +289,23 @@ func (h *UserHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondJSONWithMeta(w, http.StatusOK, map[string]interface{}{
+	data := map[string]interface{}{
 		"notifications": result.Notifications,
 		"unread_count":  result.UnreadCount,
-	}, &domain.APIMeta{
+	}
+	if params.Grouped {
+		data = map[string]interface{}{
+			"threads":      result.Threads,
+			"unread_count": result.UnreadCount,
+		}
+	}
+
+	respondJSONWithMeta(w, http.StatusOK, data, &domain.APIMeta{
 		Page:       result.Page,
 		Limit:      params.Limit,
 		TotalCount: result.TotalCount,
 		TotalPages: result.TotalPages,
+		NextCursor: result.NextCursor,
 	})
 }
 
@@ -234,6 +327,114 @@ func (h *UserHandler) MarkNotificationRead(w http.ResponseWriter, r *http.Reques
 	})
 }
 
+// MarkNotificationThreadRead marks every notification in a thread read
+func (h *UserHandler) MarkNotificationThreadRead(w http.ResponseWriter, r *http.Request) {
+	threadID, err := getURLParamUUID(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid thread ID")
+		return
+	}
+
+	userID := getUserID(r)
+	if err := h.notificationService.MarkThreadAsRead(r.Context(), userID, threadID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Thread marked as read",
+	})
+}
+
+// GetQuietHours returns when the user's email and push notifications are
+// held, or the defaults, switched off, when they haven't set any
+func (h *UserHandler) GetQuietHours(w http.ResponseWriter, r *http.Request) {
+	quietHours, err := h.notificationService.GetQuietHours(r.Context(), getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, quietHours)
+}
+
+func (h *UserHandler) SetQuietHours(w http.ResponseWriter, r *http.Request) {
+	var req domain.SetQuietHoursRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	quietHours, err := h.notificationService.SetQuietHours(r.Context(), getUserID(r), &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, quietHours)
+}
+
+// GetNotificationSettings returns, for every notification type, whether the
+// user gets it by email, in the app and by push
+func (h *UserHandler) GetNotificationSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.notificationService.GetNotificationSettings(r.Context(), getUserID(r))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
+
+func (h *UserHandler) UpdateNotificationSettings(w http.ResponseWriter, r *http.Request) {
+	var req domain.UpdateNotificationSettingsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	settings, err := h.notificationService.UpdateNotificationSettings(r.Context(), getUserID(r), &req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
+
+// Unsubscribe switches off one type of email with the token from an email's
+// unsubscribe link, without signing in
+func (h *UserHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	var req domain.UnsubscribeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid request body")
+		return
+	}
+
+	if errors := validateRequest(&req); errors != nil {
+		respondValidationError(w, errors)
+		return
+	}
+
+	preference, err := h.notificationService.Unsubscribe(r.Context(), req.Token)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, preference)
+}
+
 func (h *UserHandler) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
 	userID := getUserID(r)
 	if err := h.notificationService.MarkAllAsRead(r.Context(), userID); err != nil {
@@ -0,0 +1,438 @@
+package handler_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/pkg/webhook"
+	"github.com/auction-cards/backend/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Mock webhook repositories
+type mockWebhookRepo struct {
+	webhooks map[uuid.UUID]*domain.Webhook
+}
+
+func newMockWebhookRepo() *mockWebhookRepo {
+	return &mockWebhookRepo{
+		webhooks: make(map[uuid.UUID]*domain.Webhook),
+	}
+}
+
+func (r *mockWebhookRepo) Create(ctx context.Context, webhook *domain.Webhook) error {
+	if webhook.ID == uuid.Nil {
+		webhook.ID = uuid.New()
+	}
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = webhook.CreatedAt
+	stored := *webhook
+	r.webhooks[webhook.ID] = &stored
+	return nil
+}
+
+func (r *mockWebhookRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Webhook, error) {
+	if webhook, ok := r.webhooks[id]; ok {
+		copied := *webhook
+		return &copied, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockWebhookRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.Webhook, error) {
+	webhooks := make([]domain.Webhook, 0)
+	for _, webhook := range r.webhooks {
+		if webhook.UserID == userID {
+			webhooks = append(webhooks, *webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (r *mockWebhookRepo) CountByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	webhooks, _ := r.ListByUser(ctx, userID)
+	return len(webhooks), nil
+}
+
+func (r *mockWebhookRepo) ListSubscribed(ctx context.Context, event domain.WebhookEvent, userIDs []uuid.UUID) ([]domain.Webhook, error) {
+	webhooks := make([]domain.Webhook, 0)
+	for _, webhook := range r.webhooks {
+		if !webhook.Active || !webhook.Subscribes(event) {
+			continue
+		}
+		owned := webhook.Global
+		for _, id := range userIDs {
+			owned = owned || webhook.UserID == id
+		}
+		if owned {
+			webhooks = append(webhooks, *webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (r *mockWebhookRepo) Update(ctx context.Context, webhook *domain.Webhook) error {
+	if _, ok := r.webhooks[webhook.ID]; !ok {
+		return domain.ErrNotFound
+	}
+	stored := *webhook
+	r.webhooks[webhook.ID] = &stored
+	return nil
+}
+
+func (r *mockWebhookRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, ok := r.webhooks[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.webhooks, id)
+	return nil
+}
+
+type mockWebhookDeliveryRepo struct {
+	mu         sync.Mutex
+	webhooks   *mockWebhookRepo
+	deliveries map[uuid.UUID]*domain.WebhookDelivery
+}
+
+func newMockWebhookDeliveryRepo(webhooks *mockWebhookRepo) *mockWebhookDeliveryRepo {
+	return &mockWebhookDeliveryRepo{
+		webhooks:   webhooks,
+		deliveries: make(map[uuid.UUID]*domain.WebhookDelivery),
+	}
+}
+
+func (r *mockWebhookDeliveryRepo) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+	if delivery.Status == "" {
+		delivery.Status = domain.WebhookDeliveryPending
+	}
+	delivery.CreatedAt = time.Now()
+	stored := *delivery
+	r.deliveries[delivery.ID] = &stored
+	return nil
+}
+
+func (r *mockWebhookDeliveryRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if delivery, ok := r.deliveries[id]; ok {
+		copied := *delivery
+		return &copied, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockWebhookDeliveryRepo) ListByWebhook(ctx context.Context, webhookID uuid.UUID, params *domain.WebhookDeliveryListParams) ([]domain.WebhookDelivery, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for _, delivery := range r.deliveries {
+		if delivery.WebhookID == webhookID && (params.Status == nil || delivery.Status == *params.Status) {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	return deliveries, len(deliveries), nil
+}
+
+func (r *mockWebhookDeliveryRepo) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for _, delivery := range r.deliveries {
+		if delivery.Status != domain.WebhookDeliveryPending || delivery.NextAttemptAt.After(now) || len(deliveries) >= limit {
+			continue
+		}
+		delivery.NextAttemptAt = &leaseUntil
+		claimed := *delivery
+		claimed.Webhook = r.webhooks.webhooks[delivery.WebhookID]
+		deliveries = append(deliveries, claimed)
+	}
+	return deliveries, nil
+}
+
+func (r *mockWebhookDeliveryRepo) RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *delivery
+	stored.Webhook = nil
+	r.deliveries[delivery.ID] = &stored
+	return nil
+}
+
+func (r *mockWebhookDeliveryRepo) Retry(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery, ok := r.deliveries[id]
+	if !ok || delivery.Status != domain.WebhookDeliveryFailed {
+		return domain.ErrDeliveryNotFailed
+	}
+	now := time.Now()
+	delivery.Status = domain.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = &now
+	return nil
+}
+
+func (r *mockWebhookDeliveryRepo) DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return 0, nil
+}
+
+// makeDue lets every pending delivery be sent right away
+func (r *mockWebhookDeliveryRepo) makeDue() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	past := time.Now().Add(-time.Second)
+	for _, delivery := range r.deliveries {
+		if delivery.Status == domain.WebhookDeliveryPending {
+			delivery.NextAttemptAt = &past
+		}
+	}
+}
+
+func TestWebhookHandler_Create(t *testing.T) {
+	webhookRepo := newMockWebhookRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	webhookService := service.NewWebhookService(
+		webhookRepo,
+		newMockWebhookDeliveryRepo(webhookRepo),
+		webhook.NewClient(time.Second, false),
+		3,
+		10,
+	)
+
+	r := createTestRouter()
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Get("/api/webhooks", webhookHandler.List)
+		r.Post("/api/webhooks", webhookHandler.Create)
+	})
+
+	userToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+	adminToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "admin")
+
+	tests := []struct {
+		name       string
+		body       domain.CreateWebhookRequest
+		token      string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "unknown event", body: domain.CreateWebhookRequest{URL: "https://hooks.example.com/in", Events: []string{"auction.created"}}, token: userToken, wantStatus: http.StatusBadRequest},
+		{name: "plain http", body: domain.CreateWebhookRequest{URL: "http://hooks.example.com/in", Events: []string{"bid.placed"}}, token: userToken, wantStatus: http.StatusBadRequest, wantCode: "INVALID_WEBHOOK_URL"},
+		{name: "loopback address", body: domain.CreateWebhookRequest{URL: "https://127.0.0.1/in", Events: []string{"bid.placed"}}, token: userToken, wantStatus: http.StatusBadRequest, wantCode: "INVALID_WEBHOOK_URL"},
+		{name: "private address", body: domain.CreateWebhookRequest{URL: "https://10.1.2.3/in", Events: []string{"bid.placed"}}, token: userToken, wantStatus: http.StatusBadRequest, wantCode: "INVALID_WEBHOOK_URL"},
+		{name: "global webhook by a user", body: domain.CreateWebhookRequest{URL: "https://hooks.example.com/in", Events: []string{"bid.placed"}, Global: true}, token: userToken, wantStatus: http.StatusForbidden},
+		{name: "global webhook by an admin", body: domain.CreateWebhookRequest{URL: "https://hooks.example.com/all", Events: []string{"order.paid"}, Global: true}, token: adminToken, wantStatus: http.StatusCreated},
+		{name: "valid webhook", body: domain.CreateWebhookRequest{URL: "https://hooks.example.com/in", Events: []string{"bid.placed", "auction.ended", "bid.placed"}}, token: userToken, wantStatus: http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := makeRequest(t, r, "POST", "/api/webhooks", tt.body, tt.token)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+
+			response := parseResponse(t, rr)
+			if tt.wantCode != "" && (response.Error == nil || response.Error.Code != tt.wantCode) {
+				t.Errorf("expected error code %s, got %+v", tt.wantCode, response.Error)
+			}
+			if rr.Code == http.StatusCreated {
+				data := response.Data.(map[string]interface{})
+				if secret, _ := data["secret"].(string); len(secret) != 64 {
+					t.Errorf("expected a 64 character secret on creation, got %q", secret)
+				}
+			}
+		})
+	}
+
+	// The secret is only shown on creation, and repeated events are dropped
+	rr := makeRequest(t, r, "GET", "/api/webhooks", nil, userToken)
+	hooks := parseResponse(t, rr).Data.([]interface{})
+	if len(hooks) != 1 {
+		t.Fatalf("got %d webhooks, want 1", len(hooks))
+	}
+	hook := hooks[0].(map[string]interface{})
+	if _, ok := hook["secret"]; ok {
+		t.Error("listed webhook exposes its secret")
+	}
+	if events := hook["events"].([]interface{}); len(events) != 2 {
+		t.Errorf("got events %v, want 2 unique events", events)
+	}
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	bidRepo := newMockBidRepo()
+	webhookRepo := newMockWebhookRepo()
+	deliveryRepo := newMockWebhookDeliveryRepo(webhookRepo)
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	sellerID := uuid.New()
+	bidderID := uuid.New()
+
+	auction := &domain.Auction{
+		SellerID:      sellerID,
+		Title:         "Test Auction",
+		StartingPrice: decimal.NewFromFloat(100),
+		CurrentPrice:  decimal.NewFromFloat(100),
+		BidIncrement:  decimal.NewFromFloat(5),
+		StartTime:     time.Now().Add(-1 * time.Hour),
+		EndTime:       time.Now().Add(24 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+	}
+	auctionRepo.Create(context.Background(), auction)
+
+	// The receiver checks every signature and answers with receiverStatus
+	var mu sync.Mutex
+	receiverStatus := http.StatusOK
+	var received []string
+	var secret string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get("X-Webhook-Timestamp"), 10, 64)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("X-Webhook-Signature") != webhook.Sign(secret, timestamp, body) {
+			t.Errorf("delivery %s has a bad signature", r.Header.Get("X-Webhook-Delivery"))
+		}
+		received = append(received, r.Header.Get("X-Webhook-Event"))
+		w.WriteHeader(receiverStatus)
+	}))
+	defer receiver.Close()
+
+	webhookService := service.NewWebhookService(
+		webhookRepo,
+		deliveryRepo,
+		webhook.NewClient(5*time.Second, true),
+		2,
+		10,
+	)
+	bidService := service.NewBidService(
+		bidRepo,
+		auctionRepo,
+		nil,
+		nil,
+		nil,
+		nil,
+		&mockTxManager{},
+		nil,
+		webhookService,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	created, err := webhookService.Create(context.Background(), sellerID, false, &domain.CreateWebhookRequest{
+		URL:    receiver.URL,
+		Events: []string{string(domain.WebhookEventBidPlaced)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create webhook: %v", err)
+	}
+	secret = created.Secret
+
+	r := createTestRouter()
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	r.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		r.Get("/api/webhooks/{id}/deliveries", webhookHandler.ListDeliveries)
+		r.Post("/api/webhooks/{id}/deliveries/{deliveryId}/retry", webhookHandler.RetryDelivery)
+	})
+	sellerToken, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+	bidderToken, _ := jwtManager.GenerateAccessToken(bidderID, "user")
+	deliveriesPath := "/api/webhooks/" + created.ID.String() + "/deliveries"
+
+	// A bid is delivered to the seller's webhook
+	if _, err := bidService.PlaceBid(context.Background(), auction.ID, bidderID, &domain.PlaceBidRequest{Amount: "110"}, nil); err != nil {
+		t.Fatalf("Failed to place bid: %v", err)
+	}
+	webhookService.ProcessDeliveries(context.Background())
+
+	if len(received) != 1 || received[0] != string(domain.WebhookEventBidPlaced) {
+		t.Fatalf("receiver got %v, want one bid.placed delivery", received)
+	}
+
+	// A failing receiver is retried until the attempts run out
+	mu.Lock()
+	receiverStatus = http.StatusInternalServerError
+	mu.Unlock()
+	if _, err := bidService.PlaceBid(context.Background(), auction.ID, bidderID, &domain.PlaceBidRequest{Amount: "120"}, nil); err != nil {
+		t.Fatalf("Failed to place bid: %v", err)
+	}
+	webhookService.ProcessDeliveries(context.Background())
+	webhookService.ProcessDeliveries(context.Background())
+	if len(received) != 2 {
+		t.Errorf("receiver got %d deliveries, want 2 before the retry is due", len(received))
+	}
+	deliveryRepo.makeDue()
+	webhookService.ProcessDeliveries(context.Background())
+	if len(received) != 3 {
+		t.Errorf("receiver got %d deliveries, want 3 after the retry", len(received))
+	}
+
+	rr := makeRequest(t, r, "GET", deliveriesPath+"?status=failed", nil, sellerToken)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list deliveries returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	failed := parseResponse(t, rr).Data.([]interface{})
+	if len(failed) != 1 {
+		t.Fatalf("got %d failed deliveries, want 1", len(failed))
+	}
+	delivery := failed[0].(map[string]interface{})
+	if delivery["attempts"].(float64) != 2 || delivery["last_status_code"].(float64) != http.StatusInternalServerError {
+		t.Errorf("failed delivery = %v, want 2 attempts ending in a 500", delivery)
+	}
+	retryPath := deliveriesPath + "/" + delivery["id"].(string) + "/retry"
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+	}{
+		{name: "other user's webhook", token: bidderToken, wantStatus: http.StatusNotFound},
+		{name: "retry failed delivery", token: sellerToken, wantStatus: http.StatusOK},
+		{name: "retry pending delivery", token: sellerToken, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := makeRequest(t, r, "POST", retryPath, nil, tt.token)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+		})
+	}
+
+	// The retried delivery goes through once the receiver recovers
+	mu.Lock()
+	receiverStatus = http.StatusNoContent
+	mu.Unlock()
+	webhookService.ProcessDeliveries(context.Background())
+
+	rr = makeRequest(t, r, "GET", deliveriesPath+"?status=succeeded", nil, sellerToken)
+	if succeeded := parseResponse(t, rr).Data.([]interface{}); len(succeeded) != 2 {
+		t.Errorf("got %d succeeded deliveries, want 2", len(succeeded))
+	}
+}
@@ -0,0 +1,235 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/cache"
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	ws "github.com/auction-cards/backend/internal/websocket"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+)
+
+// testInstance is one server of a test cluster
+type testInstance struct {
+	url        string
+	messageHub *ws.MessageHub
+}
+
+// Runs two instances sharing their repositories and the Redis named by
+// TEST_REDIS_ADDR:
+//
+//	TEST_REDIS_ADDR=localhost:6379 go test -run Cluster ./internal/handler
+func TestWebSocketCluster_CrossInstanceDelivery(t *testing.T) {
+	addr := os.Getenv("TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("TEST_REDIS_ADDR not set")
+	}
+
+	auctionRepo := newMockAuctionRepo()
+	bidRepo := newMockBidRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	instances := make([]*testInstance, 2)
+	for i := range instances {
+		redis, err := cache.NewRedisCache(addr, "", 0, 0)
+		if err != nil {
+			t.Fatalf("failed to connect to redis: %v", err)
+		}
+
+		auctionService := service.NewAuctionService(
+			auctionRepo,
+			&mockAuctionImageRepo{},
+			newMockShippingRepo(),
+			newMockCategoryRepo(),
+			&mockAuctionStatsRepo{},
+			newMockWatchlistRepo(),
+			bidRepo,
+			&mockTxManager{},
+			nil, // no S3 for tests
+			nil,
+			nil,
+			nil,
+			nil,
+		)
+		bidService := service.NewBidService(
+			bidRepo,
+			auctionRepo,
+			nil,
+			nil,
+			nil,
+			nil,
+			&mockTxManager{},
+			nil,      // no notification service for tests
+			nil,      // no webhooks for tests
+			nil, nil, // no order service for tests
+			nil,
+			redis,
+		)
+
+		hub := ws.NewHub(redis)
+		messageHub := ws.NewMessageHub(redis)
+		go hub.Run()
+		go messageHub.Run()
+
+		r := createTestRouter()
+		wsHandler := handler.NewWebSocketHandler(hub, auctionService)
+		r.With(authMiddleware.OptionalAuth).Get("/ws/auctions", wsHandler.HandleAuctionsWS)
+		r.With(authMiddleware.OptionalAuth).Get("/ws/auctions/{id}", wsHandler.HandleAuctionWS)
+		r.With(authMiddleware.RequireAuth).Get("/ws/messages", handler.NewMessageWebSocketHandler(messageHub).HandleMessageWS)
+		r.With(authMiddleware.RequireAuth).Post("/api/auctions/{id}/bids", handler.NewBidHandler(bidService).PlaceBid)
+
+		server := httptest.NewServer(r)
+		t.Cleanup(func() {
+			server.Close()
+			hub.Stop()
+			messageHub.Stop()
+			redis.Close()
+		})
+
+		instances[i] = &testInstance{url: server.URL, messageHub: messageHub}
+	}
+
+	// Give the Redis subscribers time to subscribe
+	time.Sleep(200 * time.Millisecond)
+
+	connect := func(t *testing.T, url, token string) (*websocket.Conn, *wsTestReader) {
+		t.Helper()
+		header := http.Header{}
+		if token != "" {
+			header.Set("Authorization", "Bearer "+token)
+		}
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(url, "http"), header)
+		if err != nil {
+			t.Fatalf("dial failed: %v", err)
+		}
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		t.Cleanup(func() { conn.Close() })
+		return conn, &wsTestReader{conn: conn}
+	}
+
+	// readUntil skips countdown ticks and the like until a message of type
+	// arrives
+	readUntil := func(t *testing.T, reader *wsTestReader, messageType string) map[string]interface{} {
+		t.Helper()
+		for {
+			if msg := reader.read(t); msg.Type == messageType {
+				return msg.Payload
+			}
+		}
+	}
+
+	placeBid := func(t *testing.T, url string, auctionID uuid.UUID, amount string) {
+		t.Helper()
+		bidderToken, _ := jwtManager.GenerateAccessToken(uuid.New(), "user")
+		body := `{"amount":"` + amount + `"}`
+		req, _ := http.NewRequest("POST", url+"/api/auctions/"+auctionID.String()+"/bids", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+bidderToken)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("bid failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("bid got status %d", resp.StatusCode)
+		}
+	}
+
+	newAuction := func() *domain.Auction {
+		auction := &domain.Auction{
+			SellerID:      uuid.New(),
+			Title:         "Clustered Auction",
+			StartingPrice: decimal.NewFromFloat(100),
+			CurrentPrice:  decimal.NewFromFloat(100),
+			BidIncrement:  decimal.NewFromFloat(5),
+			StartTime:     time.Now().Add(-1 * time.Hour),
+			EndTime:       time.Now().Add(24 * time.Hour),
+			Status:        domain.AuctionStatusActive,
+		}
+		auctionRepo.Create(context.Background(), auction)
+		return auction
+	}
+
+	t.Run("bid placed on another instance", func(t *testing.T) {
+		auction := newAuction()
+
+		conn, reader := connect(t, instances[0].url+"/ws/auctions/"+auction.ID.String(), "")
+		readUntil(t, reader, string(domain.WSMessageSnapshot))
+
+		placeBid(t, instances[1].url, auction.ID, "110.00")
+
+		payload := readUntil(t, reader, string(domain.WSMessageNewBid))
+		if payload["amount"] != "110" {
+			t.Errorf("got amount %v", payload["amount"])
+		}
+
+		// A client reconnecting to the other instance picks up where it was
+		conn.Close()
+		_, reader = connect(t, instances[1].url+"/ws/auctions/"+auction.ID.String(), "")
+		snapshot := readUntil(t, reader, string(domain.WSMessageSnapshot))
+		if snapshot["current_price"] != "110" {
+			t.Errorf("got current price %v", snapshot["current_price"])
+		}
+	})
+
+	t.Run("subscribed on another instance", func(t *testing.T) {
+		auction := newAuction()
+
+		conn, reader := connect(t, instances[0].url+"/ws/auctions", "")
+		if err := conn.WriteJSON(domain.WSClientMessage{Type: domain.WSClientSubscribe, AuctionIDs: []uuid.UUID{auction.ID}}); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		readUntil(t, reader, string(domain.WSMessageSnapshot))
+
+		placeBid(t, instances[1].url, auction.ID, "110.00")
+
+		payload := readUntil(t, reader, string(domain.WSMessageNewBid))
+		if payload["auction_id"] != auction.ID.String() {
+			t.Errorf("got auction %v", payload["auction_id"])
+		}
+	})
+
+	t.Run("message sent from another instance", func(t *testing.T) {
+		userID := uuid.New()
+		token, _ := jwtManager.GenerateAccessToken(userID, "user")
+
+		conn, _ := connect(t, instances[0].url+"/ws/messages", token)
+
+		// Presence is shared, so the other instance sees the user online
+		deadline := time.Now().Add(time.Second)
+		for !instances[1].messageHub.IsUserOnline(userID) {
+			if time.Now().After(deadline) {
+				t.Fatal("expected the user to be online on every instance")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		conversationID := uuid.New()
+		instances[1].messageHub.SendToUser(userID, domain.MessageWSPayload{
+			Type:           domain.MessageWSTypeTypingStarted,
+			ConversationID: conversationID,
+		})
+
+		for {
+			var msg domain.MessageWSPayload
+			if err := conn.ReadJSON(&msg); err != nil {
+				t.Fatalf("waiting for the message: %v", err)
+			}
+			if msg.ConversationID == conversationID {
+				break
+			}
+		}
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/auction-cards/backend/internal/domain"
 	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
 	ws "github.com/auction-cards/backend/internal/websocket"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
@@ -21,13 +23,16 @@ var upgrader = websocket.Upgrader{
 }
 
 type WebSocketHandler struct {
-	hub *ws.Hub
+	hub            *ws.Hub
+	auctionService *service.AuctionService
 }
 
-func NewWebSocketHandler(hub *ws.Hub) *WebSocketHandler {
-	return &WebSocketHandler{hub: hub}
+func NewWebSocketHandler(hub *ws.Hub, auctionService *service.AuctionService) *WebSocketHandler {
+	return &WebSocketHandler{hub: hub, auctionService: auctionService}
 }
 
+// HandleAuctionWS joins the auction's room. The client is first sent a
+// snapshot of the auction, then its updates and countdown ticks.
 func (h *WebSocketHandler) HandleAuctionWS(w http.ResponseWriter, r *http.Request) {
 	auctionID, err := getURLParamUUID(r, "id")
 	if err != nil {
@@ -36,7 +41,16 @@ func (h *WebSocketHandler) HandleAuctionWS(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Get user ID if authenticated (optional)
-	userID := middleware.GetUserID(r.Context())
+	viewerID := middleware.GetUserID(r.Context())
+	shareToken := r.URL.Query().Get("share")
+
+	// Private auctions' rooms are only open to those who may see them
+	if _, err := h.auctionService.GetByID(r.Context(), auctionID, viewerID, shareToken); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	userID := viewerID
 	if userID == uuid.Nil {
 		userID = uuid.New() // Generate anonymous ID for non-authenticated users
 	}
@@ -52,7 +66,45 @@ func (h *WebSocketHandler) HandleAuctionWS(w http.ResponseWriter, r *http.Reques
 	// Register client
 	h.hub.Register(auctionID, client)
 
+	// The snapshot is taken after registering, so updates queued meanwhile
+	// follow it rather than being missed
+	snapshot, err := h.auctionService.Snapshot(r.Context(), auctionID, viewerID, shareToken)
+	if err == nil {
+		err = client.WriteFirst(domain.WSMessage{Type: domain.WSMessageSnapshot, Payload: snapshot})
+	}
+	if err != nil {
+		log.Printf("Error sending snapshot of auction %s: %v", auctionID, err)
+		h.hub.Unregister(client)
+		conn.Close()
+		return
+	}
+	h.hub.SetEndTime(auctionID, snapshot.EndTime)
+
 	// Start client goroutines
 	go client.WritePump()
 	go client.ReadPump()
 }
+
+// HandleAuctionsWS opens a connection that follows many auctions, such as
+// those of a listing grid. The client sends
+//
+//	{"type": "subscribe", "auction_ids": ["..."]}
+//
+// and is sent each auction's snapshot, then its updates and countdown
+// ticks, until it unsubscribes likewise. Auctions the viewer may not see
+// are answered with an error naming the auction.
+func (h *WebSocketHandler) HandleAuctionsWS(w http.ResponseWriter, r *http.Request) {
+	viewerID := middleware.GetUserID(r.Context())
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+
+	client := ws.NewSubscriberClient(h.hub, conn, viewerID, h.auctionService)
+	h.hub.Register(uuid.Nil, client)
+
+	go client.WritePump()
+	go client.ReadPump()
+}
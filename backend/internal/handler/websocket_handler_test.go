@@ -0,0 +1,371 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/handler"
+	"github.com/auction-cards/backend/internal/middleware"
+	"github.com/auction-cards/backend/internal/service"
+	ws "github.com/auction-cards/backend/internal/websocket"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+)
+
+type wsTestMessage struct {
+	Type    string                 `json:"type"`
+	Payload map[string]interface{} `json:"payload"`
+}
+
+// wsTestReader reads hub messages one at a time. The hub sends messages
+// queued together in one frame, separated by newlines.
+type wsTestReader struct {
+	conn    *websocket.Conn
+	pending [][]byte
+}
+
+func (r *wsTestReader) next(t *testing.T) []byte {
+	t.Helper()
+	for len(r.pending) == 0 {
+		_, data, err := r.conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		r.pending = bytes.Split(bytes.TrimSpace(data), []byte{'\n'})
+	}
+
+	data := r.pending[0]
+	r.pending = r.pending[1:]
+	return data
+}
+
+func (r *wsTestReader) read(t *testing.T) wsTestMessage {
+	t.Helper()
+	data := r.next(t)
+
+	var msg wsTestMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.Fatalf("invalid message %s: %v", data, err)
+	}
+	return msg
+}
+
+func TestWebSocketHandler_SnapshotAndCountdown(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	bidRepo := newMockBidRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		bidRepo,
+		&mockTxManager{},
+		nil, // no S3 for tests
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	hub := ws.NewHub(nil)
+	hub.SetCountdownInterval(20 * time.Millisecond)
+	go hub.Run()
+	defer hub.Stop()
+
+	r := createTestRouter()
+	wsHandler := handler.NewWebSocketHandler(hub, auctionService)
+	r.With(authMiddleware.OptionalAuth).Get("/ws/auctions/{id}", wsHandler.HandleAuctionWS)
+
+	server := httptest.NewServer(r)
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/auctions/"
+
+	bidderID := uuid.New()
+	auction := &domain.Auction{
+		SellerID:      uuid.New(),
+		Title:         "Live Auction",
+		Currency:      domain.CurrencyEUR,
+		StartingPrice: decimal.NewFromInt(10),
+		CurrentPrice:  decimal.NewFromInt(25),
+		BidCount:      1,
+		StartTime:     time.Now().Add(-1 * time.Hour),
+		EndTime:       time.Now().Add(1 * time.Hour),
+		Status:        domain.AuctionStatusActive,
+		Visibility:    domain.AuctionPublic,
+	}
+	auctionRepo.Create(context.Background(), auction)
+	bidRepo.Create(context.Background(), &domain.Bid{
+		AuctionID: auction.ID,
+		BidderID:  bidderID,
+		Amount:    decimal.NewFromInt(25),
+	})
+
+	connect := func(t *testing.T, auctionID uuid.UUID, token string) (*websocket.Conn, *wsTestReader) {
+		t.Helper()
+		header := http.Header{}
+		if token != "" {
+			header.Set("Authorization", "Bearer "+token)
+		}
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL+auctionID.String(), header)
+		if err != nil {
+			t.Fatalf("dial failed: %v", err)
+		}
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		return conn, &wsTestReader{conn: conn}
+	}
+
+	t.Run("anonymous viewer", func(t *testing.T) {
+		conn, reader := connect(t, auction.ID, "")
+		defer conn.Close()
+
+		msg := reader.read(t)
+		if msg.Type != string(domain.WSMessageSnapshot) {
+			t.Fatalf("expected a snapshot first, got %q", msg.Type)
+		}
+		if msg.Payload["current_price"] != "25" || msg.Payload["currency"] != "EUR" {
+			t.Errorf("got price %v %v", msg.Payload["current_price"], msg.Payload["currency"])
+		}
+		if msg.Payload["high_bidder"] != domain.MaskBidder(auction.ID, bidderID) {
+			t.Errorf("got high bidder %v", msg.Payload["high_bidder"])
+		}
+		if strings.Contains(msg.Payload["high_bidder"].(string), bidderID.String()) {
+			t.Error("expected the high bidder to be masked")
+		}
+		if msg.Payload["is_high_bidder"] != false {
+			t.Error("expected an anonymous viewer not to be the high bidder")
+		}
+
+		msg = reader.read(t)
+		if msg.Type != string(domain.WSMessageCountdown) {
+			t.Fatalf("expected a countdown, got %q", msg.Type)
+		}
+		if remaining, _ := msg.Payload["remaining_ms"].(float64); remaining <= 0 {
+			t.Errorf("got remaining_ms %v", msg.Payload["remaining_ms"])
+		}
+	})
+
+	t.Run("high bidder", func(t *testing.T) {
+		token, _ := jwtManager.GenerateAccessToken(bidderID, "user")
+		conn, reader := connect(t, auction.ID, token)
+		defer conn.Close()
+
+		msg := reader.read(t)
+		if msg.Type != string(domain.WSMessageSnapshot) || msg.Payload["is_high_bidder"] != true {
+			t.Errorf("expected the bidder to learn they lead, got %v", msg.Payload)
+		}
+	})
+
+	t.Run("private auction", func(t *testing.T) {
+		private := &domain.Auction{
+			SellerID:   uuid.New(),
+			Title:      "Invite Only",
+			StartTime:  time.Now().Add(-1 * time.Hour),
+			EndTime:    time.Now().Add(1 * time.Hour),
+			Status:     domain.AuctionStatusActive,
+			Visibility: domain.AuctionPrivate,
+		}
+		auctionRepo.Create(context.Background(), private)
+
+		_, resp, err := websocket.DefaultDialer.Dial(wsURL+private.ID.String(), nil)
+		if err == nil {
+			t.Fatal("expected the upgrade to be refused")
+		}
+		if resp == nil || resp.StatusCode != http.StatusNotFound {
+			t.Errorf("expected 404, got %v", resp)
+		}
+	})
+}
+
+func TestWebSocketHandler_Subscriptions(t *testing.T) {
+	auctionRepo := newMockAuctionRepo()
+	jwtManager := newTestJWTManager()
+	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+
+	auctionService := service.NewAuctionService(
+		auctionRepo,
+		&mockAuctionImageRepo{},
+		newMockShippingRepo(),
+		newMockCategoryRepo(),
+		&mockAuctionStatsRepo{},
+		newMockWatchlistRepo(),
+		newMockBidRepo(),
+		&mockTxManager{},
+		nil, // no S3 for tests
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	hub := ws.NewHub(nil)
+	hub.SetCountdownInterval(20 * time.Millisecond)
+	go hub.Run()
+	defer hub.Stop()
+
+	r := createTestRouter()
+	wsHandler := handler.NewWebSocketHandler(hub, auctionService)
+	r.With(authMiddleware.OptionalAuth).Get("/ws/auctions", wsHandler.HandleAuctionsWS)
+
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sellerID := uuid.New()
+	newAuction := func(visibility domain.AuctionVisibility) *domain.Auction {
+		auction := &domain.Auction{
+			SellerID:     sellerID,
+			Title:        "Grid Auction",
+			CurrentPrice: decimal.NewFromInt(10),
+			StartTime:    time.Now().Add(-1 * time.Hour),
+			EndTime:      time.Now().Add(1 * time.Hour),
+			Status:       domain.AuctionStatusActive,
+			Visibility:   visibility,
+		}
+		auctionRepo.Create(context.Background(), auction)
+		return auction
+	}
+	first, second, third := newAuction(domain.AuctionPublic), newAuction(domain.AuctionPublic), newAuction(domain.AuctionPublic)
+	private := newAuction(domain.AuctionPrivate)
+	missing := uuid.New()
+
+	connect := func(t *testing.T, token string) (*websocket.Conn, *wsTestReader) {
+		t.Helper()
+		header := http.Header{}
+		if token != "" {
+			header.Set("Authorization", "Bearer "+token)
+		}
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws/auctions", header)
+		if err != nil {
+			t.Fatalf("dial failed: %v", err)
+		}
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		return conn, &wsTestReader{conn: conn}
+	}
+
+	send := func(t *testing.T, conn *websocket.Conn, messageType domain.WSClientMessageType, ids ...uuid.UUID) {
+		t.Helper()
+		if err := conn.WriteJSON(domain.WSClientMessage{Type: messageType, AuctionIDs: ids}); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+
+	// read reads messages until done says to stop
+	read := func(t *testing.T, reader *wsTestReader, done func(wsTestMessage) bool) {
+		t.Helper()
+		for !done(reader.read(t)) {
+		}
+	}
+
+	t.Run("follows many auctions", func(t *testing.T) {
+		conn, reader := connect(t, "")
+		defer conn.Close()
+
+		send(t, conn, domain.WSClientSubscribe, first.ID, second.ID, private.ID, missing)
+
+		snapshots := map[string]bool{}
+		errs := map[string]bool{}
+		read(t, reader, func(msg wsTestMessage) bool {
+			switch domain.WSMessageType(msg.Type) {
+			case domain.WSMessageSnapshot:
+				snapshots[msg.Payload["auction_id"].(string)] = true
+			case domain.WSMessageError:
+				errs[msg.Payload["auction_id"].(string)] = true
+			}
+			return len(snapshots)+len(errs) == 4
+		})
+
+		if !snapshots[first.ID.String()] || !snapshots[second.ID.String()] {
+			t.Errorf("expected snapshots of both public auctions, got %v", snapshots)
+		}
+		if !errs[private.ID.String()] || !errs[missing.String()] {
+			t.Errorf("expected the private and missing auctions to be refused, got %v", errs)
+		}
+
+		// Countdowns follow for each subscribed auction
+		counted := map[string]bool{}
+		read(t, reader, func(msg wsTestMessage) bool {
+			if domain.WSMessageType(msg.Type) == domain.WSMessageCountdown {
+				counted[msg.Payload["auction_id"].(string)] = true
+			}
+			return len(counted) == 2
+		})
+		if counted[private.ID.String()] {
+			t.Error("expected no countdown for the private auction")
+		}
+
+		// Once the third auction's snapshot arrives the unsubscribe has
+		// been handled, so the first auction's countdowns stop
+		send(t, conn, domain.WSClientUnsubscribe, first.ID)
+		send(t, conn, domain.WSClientSubscribe, third.ID)
+		read(t, reader, func(msg wsTestMessage) bool {
+			return domain.WSMessageType(msg.Type) == domain.WSMessageSnapshot && msg.Payload["auction_id"] == third.ID.String()
+		})
+
+		seen := 0
+		read(t, reader, func(msg wsTestMessage) bool {
+			if domain.WSMessageType(msg.Type) != domain.WSMessageCountdown {
+				return false
+			}
+			if msg.Payload["auction_id"] == first.ID.String() {
+				t.Error("got a countdown for an auction after unsubscribing")
+			}
+			seen++
+			return seen == 6
+		})
+	})
+
+	t.Run("seller follows their private auction", func(t *testing.T) {
+		token, _ := jwtManager.GenerateAccessToken(sellerID, "user")
+		conn, reader := connect(t, token)
+		defer conn.Close()
+
+		send(t, conn, domain.WSClientSubscribe, private.ID)
+		read(t, reader, func(msg wsTestMessage) bool {
+			if domain.WSMessageType(msg.Type) == domain.WSMessageError {
+				t.Fatalf("got error %v", msg.Payload)
+			}
+			return domain.WSMessageType(msg.Type) == domain.WSMessageSnapshot
+		})
+	})
+
+	t.Run("subscription limit", func(t *testing.T) {
+		ids := make([]uuid.UUID, 101)
+		for i := range ids {
+			ids[i] = newAuction(domain.AuctionPublic).ID
+		}
+
+		conn, reader := connect(t, "")
+		defer conn.Close()
+
+		send(t, conn, domain.WSClientSubscribe, ids...)
+
+		snapshots := 0
+		read(t, reader, func(msg wsTestMessage) bool {
+			switch domain.WSMessageType(msg.Type) {
+			case domain.WSMessageSnapshot:
+				snapshots++
+			case domain.WSMessageError:
+				if msg.Payload["auction_id"] != ids[100].String() {
+					t.Errorf("expected the last auction to be refused, got %v", msg.Payload)
+				}
+				return true
+			}
+			return false
+		})
+		if snapshots != 100 {
+			t.Errorf("got %d snapshots before the limit", snapshots)
+		}
+	})
+}
@@ -0,0 +1,242 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const redactedValue = "[REDACTED]"
+
+// AdminAuditConfig controls how admin write requests are recorded in the
+// audit log
+type AdminAuditConfig struct {
+	// Largest body recorded; bigger bodies are logged by size only
+	MaxBodyBytes int64
+	// Field names containing any of these (case-insensitive) are redacted
+	RedactFields []string
+	// Per-route overrides keyed by method and full route pattern, e.g.
+	// "PUT /api/admin/users/{id}/ban"
+	Routes map[string]AdminAuditRoute
+}
+
+type AdminAuditRoute struct {
+	// Skip records nothing, e.g. for routes the service already audits
+	Skip bool
+	// OmitBody records the request without its body
+	OmitBody bool
+	// MaxBodyBytes overrides the default limit when positive
+	MaxBodyBytes int64
+	// RedactFields adds to the default redacted fields
+	RedactFields []string
+}
+
+func DefaultAdminAuditConfig() *AdminAuditConfig {
+	return &AdminAuditConfig{
+		MaxBodyBytes: 16 << 10,
+		RedactFields: []string{"password", "secret", "token", "authorization", "api_key", "private_key", "credential"},
+	}
+}
+
+// WithMaxBodyBytes overrides the default body limit when positive
+func (c *AdminAuditConfig) WithMaxBodyBytes(n int64) *AdminAuditConfig {
+	if n > 0 {
+		c.MaxBodyBytes = n
+	}
+	return c
+}
+
+// WithRoute sets the override for a method and full route pattern
+func (c *AdminAuditConfig) WithRoute(method, pattern string, route AdminAuditRoute) *AdminAuditConfig {
+	if c.Routes == nil {
+		c.Routes = make(map[string]AdminAuditRoute)
+	}
+	c.Routes[method+" "+pattern] = route
+	return c
+}
+
+// bufferLimit is the most any route may record, so bodies can be read before
+// routing has resolved which override applies
+func (c *AdminAuditConfig) bufferLimit() int64 {
+	limit := c.MaxBodyBytes
+	for _, route := range c.Routes {
+		if route.MaxBodyBytes > limit {
+			limit = route.MaxBodyBytes
+		}
+	}
+	return limit
+}
+
+// AdminAudit records every write request made through the admin API along
+// with its sanitized body and the response status. Audit failures are logged
+// and never fail the request.
+func AdminAudit(auditRepo repository.AuditLogRepository, config *AdminAuditConfig) func(http.Handler) http.Handler {
+	if config == nil {
+		config = DefaultAdminAuditConfig()
+	}
+	bufferLimit := config.bufferLimit()
+
+	return func(next http.Handler) http.Handler {
+		if auditRepo == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isWriteMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Read just enough to know whether the body fits, then hand the
+			// handler the full, unconsumed stream
+			var body []byte
+			if r.Body != nil && r.Body != http.NoBody {
+				var err error
+				body, err = io.ReadAll(io.LimitReader(r.Body, bufferLimit+1))
+				if err != nil {
+					respondError(w, http.StatusBadRequest, "INVALID_REQUEST", "Failed to read request body")
+					return
+				}
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+			}
+
+			wrapped := &responseWriter{
+				ResponseWriter: w,
+				status:         http.StatusOK,
+			}
+
+			next.ServeHTTP(wrapped, r)
+
+			pattern := chi.RouteContext(r.Context()).RoutePattern()
+			route := config.Routes[r.Method+" "+pattern]
+			if route.Skip {
+				return
+			}
+
+			metadata := map[string]interface{}{
+				"method": r.Method,
+				"path":   r.URL.Path,
+				"route":  pattern,
+				"status": wrapped.status,
+			}
+			if len(body) > 0 && !route.OmitBody {
+				limit := config.MaxBodyBytes
+				if route.MaxBodyBytes > 0 {
+					limit = route.MaxBodyBytes
+				}
+				redact := append(append([]string{}, config.RedactFields...), route.RedactFields...)
+				addAuditBody(metadata, r, body, limit, redact)
+			}
+
+			var actorID *uuid.UUID
+			if id := GetUserID(r.Context()); id != uuid.Nil {
+				actorID = &id
+			}
+
+			entry := &domain.AuditLog{
+				ActorID:    actorID,
+				Action:     domain.AuditActionAdminRequest,
+				EntityType: domain.AuditEntityHTTPRequest,
+				Metadata:   metadata,
+			}
+			// The client may already be gone; the record is still wanted
+			if err := auditRepo.Create(context.WithoutCancel(r.Context()), entry); err != nil {
+				log.Printf("Error recording admin request audit for %s %s: %v", r.Method, r.URL.Path, err)
+			}
+		})
+	}
+}
+
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// addAuditBody stores the body in the metadata if it is JSON within the size
+// limit. Anything else is recorded by size only, since partial or opaque
+// content can't be checked for secrets.
+func addAuditBody(metadata map[string]interface{}, r *http.Request, body []byte, limit int64, redact []string) {
+	size := r.ContentLength
+	if size < 0 {
+		size = int64(len(body))
+	}
+	metadata["body_size"] = size
+
+	if int64(len(body)) > limit {
+		metadata["body_truncated"] = true
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		metadata["content_type"] = mediaType
+		return
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		metadata["body_invalid"] = true
+		return
+	}
+	metadata["body"] = redactValue(parsed, redact)
+}
+
+// redactValue replaces values of secret-looking fields and bearer tokens
+func redactValue(value interface{}, redact []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSecretField(key, redact) {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redactValue(field, redact)
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i], redact)
+		}
+		return v
+	case string:
+		if looksLikeToken(v) {
+			return redactedValue
+		}
+	}
+	return value
+}
+
+func isSecretField(key string, redact []string) bool {
+	key = strings.ToLower(key)
+	for _, field := range redact {
+		if strings.Contains(key, strings.ToLower(field)) {
+			return true
+		}
+	}
+	return false
+}
+
+// looksLikeToken catches credentials pasted into free-text fields
+func looksLikeToken(s string) bool {
+	if strings.HasPrefix(strings.ToLower(s), "bearer ") {
+		return true
+	}
+	// JWTs are three base64url segments and start with an encoded '{"'
+	return strings.HasPrefix(s, "eyJ") && strings.Count(s, ".") == 2 && !strings.ContainsAny(s, " \n")
+}
@@ -3,19 +3,22 @@ package middleware
 import (
 	"net/http"
 	"strings"
+
+	"github.com/auction-cards/backend/internal/pkg/origin"
 )
 
 type CORSConfig struct {
-	AllowedOrigins   []string
+	Origins          *origin.AllowList
 	AllowedMethods   []string
 	AllowedHeaders   []string
+	ExposedHeaders   []string
 	AllowCredentials bool
 	MaxAge           int
 }
 
 func DefaultCORSConfig() *CORSConfig {
 	return &CORSConfig{
-		AllowedOrigins:   []string{"http://localhost:5173"},
+		Origins:          origin.NewAllowList([]string{"http://localhost:5173"}),
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Requested-With"},
 		AllowCredentials: true,
@@ -30,25 +33,23 @@ func CORS(config *CORSConfig) func(http.Handler) http.Handler {
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			origin := r.Header.Get("Origin")
+			requestOrigin := r.Header.Get("Origin")
 
-			// Check if origin is allowed
-			allowed := false
-			for _, allowedOrigin := range config.AllowedOrigins {
-				if allowedOrigin == "*" || allowedOrigin == origin {
-					allowed = true
-					break
-				}
-			}
+			// The response depends on the origin, so caches must key on it
+			w.Header().Add("Vary", "Origin")
 
-			if allowed {
-				w.Header().Set("Access-Control-Allow-Origin", origin)
+			if config.Origins.Allowed(requestOrigin) {
+				w.Header().Set("Access-Control-Allow-Origin", requestOrigin)
 			}
 
 			if config.AllowCredentials {
 				w.Header().Set("Access-Control-Allow-Credentials", "true")
 			}
 
+			if len(config.ExposedHeaders) > 0 {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(config.ExposedHeaders, ", "))
+			}
+
 			// Handle preflight request
 			if r.Method == "OPTIONS" {
 				w.Header().Set("Access-Control-Allow-Methods", strings.Join(config.AllowedMethods, ", "))
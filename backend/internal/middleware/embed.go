@@ -0,0 +1,17 @@
+package middleware
+
+import "net/http"
+
+// Embeddable lets any site load the route's responses, in an iframe or by
+// script, in place of the app's own CORS and framing rules. Responses go to
+// anyone, so credentials are never allowed; only use it on public data.
+func Embeddable(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Del("Access-Control-Allow-Credentials")
+		w.Header().Set("Content-Security-Policy", "default-src 'none'; img-src https: http: data:; style-src 'unsafe-inline'; script-src 'unsafe-inline'; frame-ancestors *")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/pkg/errorrate"
+	"github.com/go-chi/chi/v5"
+)
+
+// ErrorRates counts each request toward the status page components its route
+// belongs to, and whether it failed with a server error
+func ErrorRates(tracker *errorrate.Tracker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			wrapped := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(wrapped, r)
+
+			rctx := chi.RouteContext(r.Context())
+			if rctx == nil {
+				return
+			}
+			components := statusComponents(r.Method, rctx.RoutePattern())
+			tracker.Record(wrapped.status >= http.StatusInternalServerError, components...)
+		})
+	}
+}
+
+// statusComponents maps a route to the components it exercises. Every API
+// route counts toward the API; health checks and the like count toward none.
+func statusComponents(method, pattern string) []string {
+	if strings.HasPrefix(pattern, "/ws/") {
+		return []string{string(domain.StatusComponentWebSocket)}
+	}
+	if !strings.HasPrefix(pattern, "/api/") {
+		return nil
+	}
+
+	components := []string{string(domain.StatusComponentAPI)}
+	switch {
+	case method == http.MethodPost && (pattern == "/api/auctions/{id}/bids" || pattern == "/api/auctions/{id}/buy-now"):
+		components = append(components, string(domain.StatusComponentBidding))
+	case strings.HasPrefix(pattern, "/api/orders/") || strings.HasPrefix(pattern, "/api/users/me/payout"):
+		components = append(components, string(domain.StatusComponentPayments))
+	case method == http.MethodPost && (strings.HasPrefix(pattern, "/api/auctions/{id}/images") || pattern == "/api/disputes/{id}/evidence"):
+		components = append(components, string(domain.StatusComponentImageUploads))
+	}
+	return components
+}
@@ -1,8 +1,10 @@
 package middleware
 
 import (
+	"bufio"
 	"encoding/json"
 	"log"
+	"net"
 	"net/http"
 	"time"
 
@@ -26,6 +28,18 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return size, err
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Hijack hands the connection over for a WebSocket, which the upgrader
+// needs the writer itself to support
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	rw.status = http.StatusSwitchingProtocols
+	return http.NewResponseController(rw.ResponseWriter).Hijack()
+}
+
 func Logger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
@@ -65,11 +79,12 @@ func Recoverer(next http.Handler) http.Handler {
 
 // Helper function to send error responses
 func respondError(w http.ResponseWriter, status int, code, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
+	respondErrorWithDetails(w, status, code, message, nil)
+}
 
-	response := domain.ErrorResponse(code, message, nil)
-	json.NewEncoder(w).Encode(response)
+// Helper function to send error responses with extra details
+func respondErrorWithDetails(w http.ResponseWriter, status int, code, message string, details map[string]string) {
+	WriteError(w, status, code, message, details)
 }
 
 // Helper function to send success responses
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+)
+
+// MaintenanceChecker reports whether the API is in maintenance mode or a
+// planned maintenance window
+type MaintenanceChecker interface {
+	Status(ctx context.Context) *domain.MaintenanceStatus
+	// Window returns the maintenance window in effect, or nil
+	Window(ctx context.Context) *domain.MaintenanceWindow
+}
+
+// ReadOnlyDuringMaintenance rejects requests that may write with 503 while
+// maintenance mode is on. GET, HEAD and OPTIONS requests pass, as do requests
+// to the exempt paths, such as the switch itself. An exempt path ending in a
+// slash exempts everything under it.
+func ReadOnlyDuringMaintenance(checker MaintenanceChecker, exemptPaths ...string) func(http.Handler) http.Handler {
+	exempt := make(map[string]bool, len(exemptPaths))
+	var exemptPrefixes []string
+	for _, path := range exemptPaths {
+		if strings.HasSuffix(path, "/") {
+			exemptPrefixes = append(exemptPrefixes, path)
+			continue
+		}
+		exempt[path] = true
+	}
+	isExempt := func(path string) bool {
+		if exempt[path] {
+			return true
+		}
+		for _, prefix := range exemptPrefixes {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+			if isExempt(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			status := checker.Status(r.Context())
+			if !status.Enabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			message := status.Message
+			if message == "" {
+				message = "The site is read-only for maintenance, please try again later"
+			}
+			retryAfter := strconv.Itoa(status.RetryAfter(time.Now()))
+			w.Header().Set("Retry-After", retryAfter)
+			respondErrorWithDetails(w, http.StatusServiceUnavailable, "MAINTENANCE", message,
+				map[string]string{"retry_after": retryAfter})
+		})
+	}
+}
+
+// RetryDuringMaintenanceWindow turns server errors into 503 with Retry-After
+// while a planned maintenance window is in effect, so clients retry a bid the
+// database couldn't take once it's back instead of reporting it failed.
+// Anything else the handler answers, such as a bid being too low, passes.
+// Without a checker every response passes.
+func RetryDuringMaintenanceWindow(checker MaintenanceChecker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if checker == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mw := &maintenanceWindowWriter{ResponseWriter: w, ctx: r.Context(), checker: checker}
+			next.ServeHTTP(mw, r)
+			if mw.window == nil {
+				return
+			}
+
+			retryAfter := strconv.Itoa(mw.window.RetryAfter(time.Now()))
+			w.Header().Set("Retry-After", retryAfter)
+			respondErrorWithDetails(w, http.StatusServiceUnavailable, "MAINTENANCE_WINDOW",
+				"Bidding is paused for planned maintenance, please try again shortly",
+				map[string]string{"retry_after": retryAfter})
+		})
+	}
+}
+
+// maintenanceWindowWriter holds back a server error response written during a
+// maintenance window, keeping the window for the response sent instead
+type maintenanceWindowWriter struct {
+	http.ResponseWriter
+	ctx     context.Context
+	checker MaintenanceChecker
+	wrote   bool
+	window  *domain.MaintenanceWindow
+}
+
+func (mw *maintenanceWindowWriter) WriteHeader(status int) {
+	if mw.wrote {
+		return
+	}
+	mw.wrote = true
+	if status >= http.StatusInternalServerError {
+		mw.window = mw.checker.Window(mw.ctx)
+		if mw.window != nil {
+			return
+		}
+	}
+	mw.ResponseWriter.WriteHeader(status)
+}
+
+func (mw *maintenanceWindowWriter) Write(b []byte) (int, error) {
+	if !mw.wrote {
+		mw.WriteHeader(http.StatusOK)
+	}
+	if mw.window != nil {
+		return len(b), nil
+	}
+	return mw.ResponseWriter.Write(b)
+}
+
+// Unwrap lets WriteError find the client's preferred error format
+func (mw *maintenanceWindowWriter) Unwrap() http.ResponseWriter {
+	return mw.ResponseWriter
+}
@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+// validationErrorCode is the code whose details name invalid fields, sent as
+// the errors member of a problem
+const validationErrorCode = "VALIDATION_ERROR"
+
+// problemWriter marks a response whose client asked for problem details
+type problemWriter struct {
+	http.ResponseWriter
+	typeBaseURL string
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to
+// hijack the connection for a WebSocket
+func (pw *problemWriter) Unwrap() http.ResponseWriter {
+	return pw.ResponseWriter
+}
+
+// ProblemDetails sends errors as RFC 9457 application/problem+json to clients
+// that prefer it in their Accept header; everyone else keeps the usual
+// envelope. Each error code gets the type URI <typeBaseURL>/<code>, e.g.
+// /validation-error.
+func ProblemDetails(typeBaseURL string) func(http.Handler) http.Handler {
+	typeBaseURL = strings.TrimSuffix(typeBaseURL, "/")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if prefersProblem(r.Header.Get("Accept")) {
+				w = &problemWriter{ResponseWriter: w, typeBaseURL: typeBaseURL}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// prefersProblem reports whether an Accept header ranks problem+json at
+// least as high as plain JSON
+func prefersProblem(accept string) bool {
+	var problemQ, jsonQ float64
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		switch mediaType {
+		case domain.ProblemContentType:
+			problemQ = max(problemQ, q)
+		case "application/json":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+
+	return problemQ > 0 && problemQ >= jsonQ
+}
+
+// WriteError sends an error response, as problem details if the client asked
+// for them and in the usual envelope otherwise. Details of a validation error
+// name the invalid fields; any other details become extension members.
+func WriteError(w http.ResponseWriter, status int, code, message string, details map[string]string) {
+	w.Header().Add("Vary", "Accept")
+
+	pw := findProblemWriter(w)
+	if pw == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(domain.ErrorResponse(code, message, details))
+		return
+	}
+
+	problem := domain.Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   message,
+		Instance: "urn:uuid:" + uuid.New().String(),
+		Code:     code,
+	}
+	if pw.typeBaseURL != "" {
+		problem.Type = pw.typeBaseURL + "/" + strings.ReplaceAll(strings.ToLower(code), "_", "-")
+		problem.Title = problemTitle(code)
+	}
+	if code == validationErrorCode {
+		problem.Errors = problemFieldErrors(details)
+	} else {
+		problem.Extensions = details
+	}
+
+	// The instance lets a reported failure be found in the logs
+	if status >= http.StatusInternalServerError {
+		log.Printf("Problem %s: %d %s", problem.Instance, status, code)
+	}
+
+	w.Header().Set("Content-Type", domain.ProblemContentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+func findProblemWriter(w http.ResponseWriter) *problemWriter {
+	for {
+		if pw, ok := w.(*problemWriter); ok {
+			return pw
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return nil
+		}
+		w = unwrapper.Unwrap()
+	}
+}
+
+// problemTitle turns an error code into a title, e.g. "Validation error"
+func problemTitle(code string) string {
+	title := strings.ReplaceAll(strings.ToLower(code), "_", " ")
+	if title == "" {
+		return title
+	}
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+func problemFieldErrors(details map[string]string) []domain.ProblemFieldError {
+	fields := make([]string, 0, len(details))
+	for field := range details {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	errors := make([]domain.ProblemFieldError, 0, len(fields))
+	for _, field := range fields {
+		errors = append(errors, domain.ProblemFieldError{
+			Pointer: "#/" + field,
+			Detail:  details[field],
+		})
+	}
+	return errors
+}
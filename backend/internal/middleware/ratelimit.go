@@ -1,16 +1,24 @@
 package middleware
 
 import (
+	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/auction-cards/backend/internal/cache"
+	"github.com/google/uuid"
 )
 
 type RateLimitConfig struct {
 	Requests int
 	Window   time.Duration
 	KeyFunc  func(r *http.Request) string
+	// Usage names the limit on signed-in users' usage dashboard. Requests
+	// are only counted there when it is set.
+	Usage string
 }
 
 func DefaultRateLimitConfig() *RateLimitConfig {
@@ -18,7 +26,7 @@ func DefaultRateLimitConfig() *RateLimitConfig {
 		Requests: 100,
 		Window:   time.Minute,
 		KeyFunc: func(r *http.Request) string {
-			return cache.RateLimitKeyIP(getClientIP(r))
+			return cache.RateLimitKeyIP(ClientIP(r))
 		},
 	}
 }
@@ -28,7 +36,19 @@ func AuthRateLimitConfig() *RateLimitConfig {
 		Requests: 5,
 		Window:   time.Minute,
 		KeyFunc: func(r *http.Request) string {
-			return cache.RateLimitKeyAuth(getClientIP(r))
+			return cache.RateLimitKeyAuth(ClientIP(r))
+		},
+	}
+}
+
+// TokenRateLimitConfig limits guesses at emailed verification and reset
+// tokens per client
+func TokenRateLimitConfig() *RateLimitConfig {
+	return &RateLimitConfig{
+		Requests: 10,
+		Window:   15 * time.Minute,
+		KeyFunc: func(r *http.Request) string {
+			return cache.RateLimitKeyTokenIP(ClientIP(r))
 		},
 	}
 }
@@ -41,31 +61,107 @@ func BidRateLimitConfig() *RateLimitConfig {
 			userID := GetUserID(r.Context())
 			return cache.RateLimitKeyBid(userID)
 		},
+		Usage: "bids",
+	}
+}
+
+func SearchRateLimitConfig() *RateLimitConfig {
+	return &RateLimitConfig{
+		Requests: 30,
+		Window:   time.Minute,
+		KeyFunc: func(r *http.Request) string {
+			return cache.RateLimitKeySearch(ClientIP(r))
+		},
+	}
+}
+
+func MessageRateLimitConfig() *RateLimitConfig {
+	return &RateLimitConfig{
+		Requests: 20,
+		Window:   time.Minute,
+		KeyFunc: func(r *http.Request) string {
+			userID := GetUserID(r.Context())
+			return cache.RateLimitKeyMessage(userID)
+		},
+		Usage: "messages",
 	}
 }
 
+// EmbedRateLimitConfig limits embed and oEmbed requests per client. Embeds
+// are loaded by other sites' readers, so the budget is larger than for the
+// API, but kept apart from it.
+func EmbedRateLimitConfig() *RateLimitConfig {
+	return &RateLimitConfig{
+		Requests: 120,
+		Window:   time.Minute,
+		KeyFunc: func(r *http.Request) string {
+			return cache.RateLimitKeyEmbed(ClientIP(r))
+		},
+	}
+}
+
+// WithBudget overrides the request budget and window, keeping the defaults
+// for any value that isn't positive.
+func (c *RateLimitConfig) WithBudget(requests int, window time.Duration) *RateLimitConfig {
+	if requests > 0 {
+		c.Requests = requests
+	}
+	if window > 0 {
+		c.Window = window
+	}
+	return c
+}
+
 func RateLimit(redisCache *cache.RedisCache, config *RateLimitConfig) func(http.Handler) http.Handler {
 	if config == nil {
 		config = DefaultRateLimitConfig()
 	}
 
+	// Used whenever Redis is unavailable so limits still apply, albeit per
+	// instance rather than across the cluster.
+	fallback := newMemoryLimiter()
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if redisCache == nil {
-				next.ServeHTTP(w, r)
-				return
+			key := config.KeyFunc(r)
+
+			var count int64
+			var reset time.Duration
+			var err error
+			if redisCache != nil {
+				count, reset, err = redisCache.IncrementRateLimit(r.Context(), key, config.Window)
+			}
+			if redisCache == nil || err != nil {
+				count, reset = fallback.increment(key, config.Window)
 			}
 
-			key := config.KeyFunc(r)
-			count, err := redisCache.IncrementRateLimit(r.Context(), key, config.Window)
-			if err != nil {
-				// On error, allow the request
-				next.ServeHTTP(w, r)
-				return
+			remaining := int64(config.Requests) - count
+			if remaining < 0 {
+				remaining = 0
 			}
 
-			if count > int64(config.Requests) {
-				respondError(w, http.StatusTooManyRequests, "RATE_LIMITED", "Too many requests, please try again later")
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(config.Requests))
+			w.Header().Set("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(reset).Unix(), 10))
+
+			limited := count > int64(config.Requests)
+			// Usage is counted alongside the limit, so not while Redis is down
+			if config.Usage != "" && redisCache != nil && err == nil {
+				if userID := GetUserID(r.Context()); userID != uuid.Nil {
+					if err := redisCache.RecordAPIUsage(r.Context(), userID, config.Usage, limited); err != nil {
+						log.Printf("Failed to record API usage: %v", err)
+					}
+				}
+			}
+
+			if limited {
+				retryAfter := int(math.Ceil(reset.Seconds()))
+				if retryAfter < 1 {
+					retryAfter = 1
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				respondErrorWithDetails(w, http.StatusTooManyRequests, "RATE_LIMITED", "Too many requests, please try again later",
+					map[string]string{"retry_after": strconv.Itoa(retryAfter)})
 				return
 			}
 
@@ -74,7 +170,56 @@ func RateLimit(redisCache *cache.RedisCache, config *RateLimitConfig) func(http.
 	}
 }
 
-func getClientIP(r *http.Request) string {
+// memoryLimiter is an in-process fixed-window counter mirroring the Redis
+// implementation.
+type memoryLimiter struct {
+	mu        sync.Mutex
+	windows   map[string]*memoryWindow
+	lastSweep time.Time
+}
+
+type memoryWindow struct {
+	count   int64
+	resetAt time.Time
+}
+
+func newMemoryLimiter() *memoryLimiter {
+	return &memoryLimiter{
+		windows:   make(map[string]*memoryWindow),
+		lastSweep: time.Now(),
+	}
+}
+
+func (l *memoryLimiter) increment(key string, window time.Duration) (int64, time.Duration) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Drop expired windows periodically so the map doesn't grow unbounded
+	if now.Sub(l.lastSweep) > time.Minute {
+		for k, w := range l.windows {
+			if !now.Before(w.resetAt) {
+				delete(l.windows, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	w, ok := l.windows[key]
+	if !ok || !now.Before(w.resetAt) {
+		w = &memoryWindow{resetAt: now.Add(window)}
+		l.windows[key] = w
+	}
+	w.count++
+
+	return w.count, w.resetAt.Sub(now)
+}
+
+// ClientIP returns the address the request came from, preferring the headers
+// set by the reverse proxy. X-Forwarded-For is returned as sent, which may
+// list several addresses.
+func ClientIP(r *http.Request) string {
 	// Check X-Forwarded-For header first
 	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
 		return xff
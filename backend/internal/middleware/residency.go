@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/auction-cards/backend/internal/domain"
+)
+
+const crossRegionKey contextKey = "cross_region"
+
+// MarkCrossRegion marks requests made from outside the data region, by the
+// country in countryHeader, so handlers can strip the fields that must stay
+// in it. Requests without a country count as outside. Without a region
+// nothing is marked.
+func MarkCrossRegion(region, countryHeader string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if region == "" {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !domain.InResidencyRegion(region, r.Header.Get(countryHeader)) {
+				r = r.WithContext(context.WithValue(r.Context(), crossRegionKey, true))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// IsCrossRegion reports whether the request was made from outside the data
+// region
+func IsCrossRegion(ctx context.Context) bool {
+	crossRegion, _ := ctx.Value(crossRegionKey).(bool)
+	return crossRegion
+}
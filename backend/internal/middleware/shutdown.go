@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// ShutdownGate admits work until the server starts shutting down
+type ShutdownGate interface {
+	Begin() (done func(), ok bool)
+}
+
+// RejectDuringShutdown rejects requests with 503 once the server has started
+// shutting down, and tracks the ones it admits so shutdown waits for them.
+// Without a gate every request passes.
+func RejectDuringShutdown(gate ShutdownGate) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if gate == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			done, ok := gate.Begin()
+			if !ok {
+				w.Header().Set("Retry-After", "5")
+				respondError(w, http.StatusServiceUnavailable, "SHUTTING_DOWN", "The server is restarting, please try again in a few seconds")
+				return
+			}
+			defer done()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
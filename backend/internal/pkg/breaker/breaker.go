@@ -0,0 +1,172 @@
+// Package breaker stops calling a dependency that keeps failing, so requests
+// fail fast instead of waiting on its timeouts, and lets a single call
+// through now and then to find out when it has recovered.
+package breaker
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned instead of calling a dependency whose breaker is open
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State of a breaker
+type State string
+
+const (
+	// StateClosed lets every call through
+	StateClosed State = "closed"
+	// StateOpen rejects every call until the cooldown has passed
+	StateOpen State = "open"
+	// StateHalfOpen lets one trial call through to decide whether to close
+	StateHalfOpen State = "half_open"
+)
+
+// Stats is a snapshot of a breaker
+type Stats struct {
+	Name                string     `json:"name"`
+	State               State      `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	Trips               int64      `json:"trips"`
+	Rejected            int64      `json:"rejected"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+}
+
+// Breaker opens after a number of consecutive failures and stays open for a
+// cooldown, after which one trial call decides whether it closes again
+type Breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	trial    bool
+	trips    int64
+	rejected int64
+}
+
+// New creates a closed breaker. It opens after threshold consecutive
+// failures, at least one.
+func New(name string, threshold int, cooldown time.Duration) *Breaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &Breaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+		state:     StateClosed,
+	}
+}
+
+// Name of the dependency the breaker guards
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Allow asks to make a call. When allowed, the caller must report whether
+// the call failed through done; otherwise the error is ErrOpen.
+func (b *Breaker) Allow() (done func(failed bool), err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			b.rejected++
+			return nil, ErrOpen
+		}
+		b.state = StateHalfOpen
+		log.Printf("Circuit breaker %s half-open, trying %s again", b.name, b.name)
+	case StateHalfOpen:
+		if b.trial {
+			b.rejected++
+			return nil, ErrOpen
+		}
+	}
+
+	trial := b.state == StateHalfOpen
+	b.trial = trial
+	return func(failed bool) { b.record(trial, failed) }, nil
+}
+
+// Do calls fn unless the breaker is open, counting any error as a failure
+func (b *Breaker) Do(fn func() error) error {
+	done, err := b.Allow()
+	if err != nil {
+		return err
+	}
+	err = fn()
+	done(err != nil)
+	return err
+}
+
+func (b *Breaker) record(trial, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if trial {
+		b.trial = false
+	}
+
+	if !failed {
+		if b.state == StateHalfOpen && trial {
+			log.Printf("Circuit breaker %s closed, %s has recovered", b.name, b.name)
+			b.state = StateClosed
+		}
+		if b.state == StateClosed {
+			b.failures = 0
+		}
+		return
+	}
+
+	switch {
+	case b.state == StateHalfOpen && trial:
+		b.open()
+	case b.state == StateClosed:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.open()
+		}
+	}
+}
+
+func (b *Breaker) open() {
+	b.state = StateOpen
+	b.openedAt = time.Now()
+	b.trips++
+	log.Printf("Circuit breaker %s open after %d consecutive failures, retrying in %s", b.name, b.failures, b.cooldown)
+}
+
+// State reports the breaker's state. An open breaker whose cooldown has
+// passed still reports open until the next call tries the dependency.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Stats snapshots the breaker
+func (b *Breaker) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := Stats{
+		Name:                b.name,
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		Trips:               b.trips,
+		Rejected:            b.rejected,
+	}
+	if b.state != StateClosed {
+		openedAt := b.openedAt
+		stats.OpenedAt = &openedAt
+	}
+	return stats
+}
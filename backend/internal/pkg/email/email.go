@@ -3,6 +3,8 @@ package email
 import (
 	"fmt"
 	"log"
+	"strings"
+	"time"
 )
 
 type EmailType string
@@ -15,6 +17,11 @@ const (
 	EmailAuctionLost   EmailType = "auction_lost"
 	EmailAuctionEnding EmailType = "auction_ending"
 	EmailNewBid        EmailType = "new_bid"
+	EmailSecondChance  EmailType = "second_chance_offer"
+	EmailLoginCode     EmailType = "login_code"
+	EmailDataExport    EmailType = "data_export"
+	EmailDigest        EmailType = "digest"
+	EmailActivityDigest EmailType = "activity_digest"
 )
 
 type EmailData struct {
@@ -23,6 +30,20 @@ type EmailData struct {
 	Body        string
 	Type        EmailType
 	TemplateData map[string]interface{}
+	// Link turning this kind of email off, for the footer and the
+	// List-Unsubscribe header
+	UnsubscribeURL string
+}
+
+// WithUnsubscribe adds the unsubscribe link to the email and its footer
+func (d *EmailData) WithUnsubscribe(url, settingsURL string) *EmailData {
+	d.UnsubscribeURL = url
+	d.Body += fmt.Sprintf(`
+----------------------------------------
+Don't want these emails? Unsubscribe: %s
+Choose which notifications you get: %s
+`, url, settingsURL)
+	return d
 }
 
 type Sender interface {
@@ -92,6 +113,40 @@ If you did not request a password reset, please ignore this email.
 	}
 }
 
+func NewLoginCodeEmail(to, code string, validFor time.Duration) *EmailData {
+	return &EmailData{
+		To:      to,
+		Subject: "Your sign-in code",
+		Type:    EmailLoginCode,
+		Body: fmt.Sprintf(`
+Someone signed in to your account from a device we don't recognize.
+
+Enter this code to finish signing in:
+
+%s
+
+This code will expire in %d minutes.
+
+If this wasn't you, change your password right away.
+`, code, int(validFor.Minutes())),
+	}
+}
+
+func NewDataExportEmail(to, downloadURL string, validFor time.Duration) *EmailData {
+	return &EmailData{
+		To:      to,
+		Subject: "Your data export is ready",
+		Type:    EmailDataExport,
+		Body: fmt.Sprintf(`
+The copy of your data you asked for is ready to download:
+
+%s
+
+This link will expire in %d hours. You can request a new export at any time.
+`, downloadURL, int(validFor.Hours())),
+	}
+}
+
 func NewOutbidEmail(to, auctionTitle, newBidAmount, auctionURL string) *EmailData {
 	return &EmailData{
 		To:      to,
@@ -180,3 +235,73 @@ View your auction:
 `, auctionTitle, bidAmount, bidderName, auctionURL),
 	}
 }
+
+func NewSecondChanceOfferEmail(to, auctionTitle, offerAmount, expiresIn, auctionURL string) *EmailData {
+	return &EmailData{
+		To:      to,
+		Subject: fmt.Sprintf("Second chance to buy %s", auctionTitle),
+		Type:    EmailSecondChance,
+		Body: fmt.Sprintf(`
+Good news! The winner of an auction you bid on did not complete payment.
+
+Item: %s
+Your price: %s
+
+You can buy the item at your highest bid. This offer expires in %s:
+%s
+`, auctionTitle, offerAmount, expiresIn, auctionURL),
+	}
+}
+
+// DigestItem is one email held during quiet hours
+type DigestItem struct {
+	Subject string
+	Body    string
+}
+
+// NewDigestEmail gathers the emails held during the user's quiet hours into
+// one, oldest first
+func NewDigestEmail(to string, items []DigestItem) *EmailData {
+	var body strings.Builder
+	fmt.Fprintf(&body, "\nHere's what happened during your quiet hours (%d notifications):\n", len(items))
+	for _, item := range items {
+		fmt.Fprintf(&body, "\n----------------------------------------\n%s\n%s", item.Subject, item.Body)
+	}
+
+	return &EmailData{
+		To:      to,
+		Subject: fmt.Sprintf("%d notifications from your quiet hours", len(items)),
+		Type:    EmailDigest,
+		Body:    body.String(),
+	}
+}
+
+// DigestSection is one part of an activity digest, a line per item
+type DigestSection struct {
+	Title string
+	Lines []string
+}
+
+// NewActivityDigestEmail sums up what happened since the user's last daily
+// or weekly digest, with a link to change or stop them
+func NewActivityDigestEmail(to, period string, sections []DigestSection, settingsURL string) *EmailData {
+	var body strings.Builder
+	fmt.Fprintf(&body, "\nHere's your %s digest.\n", period)
+	for _, section := range sections {
+		fmt.Fprintf(&body, "\n%s\n", section.Title)
+		for _, line := range section.Lines {
+			fmt.Fprintf(&body, "  - %s\n", line)
+		}
+	}
+	fmt.Fprintf(&body, `
+----------------------------------------
+Change or stop these digests: %s
+`, settingsURL)
+
+	return &EmailData{
+		To:      to,
+		Subject: fmt.Sprintf("Your %s auction digest", period),
+		Type:    EmailActivityDigest,
+		Body:    body.String(),
+	}
+}
@@ -0,0 +1,47 @@
+package email
+
+import (
+	"errors"
+	"time"
+
+	"github.com/auction-cards/backend/internal/pkg/breaker"
+)
+
+// ErrTimeout is returned when the provider takes longer than allowed
+var ErrTimeout = errors.New("email provider timed out")
+
+// GuardedSender sends through a provider within a timeout and a breaker, so
+// a slow or failing provider can't hold up the requests sending email.
+// While the breaker is open emails are dropped with breaker.ErrOpen; the
+// in-app notifications they accompany are still delivered.
+type GuardedSender struct {
+	sender  Sender
+	timeout time.Duration
+	breaker *breaker.Breaker
+}
+
+func NewGuardedSender(sender Sender, timeout time.Duration, b *breaker.Breaker) *GuardedSender {
+	return &GuardedSender{sender: sender, timeout: timeout, breaker: b}
+}
+
+func (s *GuardedSender) Send(data *EmailData) error {
+	return s.breaker.Do(func() error {
+		if s.timeout <= 0 {
+			return s.sender.Send(data)
+		}
+
+		// The provider can't be cancelled, so a late send still finishes in
+		// the background
+		result := make(chan error, 1)
+		go func() { result <- s.sender.Send(data) }()
+
+		timer := time.NewTimer(s.timeout)
+		defer timer.Stop()
+		select {
+		case err := <-result:
+			return err
+		case <-timer.C:
+			return ErrTimeout
+		}
+	})
+}
@@ -0,0 +1,107 @@
+// Package errorrate counts requests and server errors per component over a
+// sliding window, so recent error rates can be reported without a metrics
+// backend. Counts are kept in memory and only cover this instance.
+package errorrate
+
+import (
+	"sync"
+	"time"
+)
+
+// Rate is a component's request and error counts over the window
+type Rate struct {
+	Requests int64 `json:"requests"`
+	Errors   int64 `json:"errors"`
+}
+
+// Ratio returns the share of requests that failed, or zero without requests
+func (r Rate) Ratio() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Requests)
+}
+
+type bucket struct {
+	start  time.Time
+	counts map[string]Rate
+}
+
+// Tracker keeps counts in buckets of a fixed width, dropping those older
+// than the window
+type Tracker struct {
+	window time.Duration
+	width  time.Duration
+
+	mu      sync.Mutex
+	buckets []bucket
+}
+
+// New creates a tracker reporting over window, counted in buckets of width
+func New(window, width time.Duration) *Tracker {
+	return &Tracker{window: window, width: width}
+}
+
+// Window is how far back the tracker reports
+func (t *Tracker) Window() time.Duration {
+	return t.window
+}
+
+// Record counts one request to each of the components
+func (t *Tracker) Record(failed bool, components ...string) {
+	if t == nil || len(components) == 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	start := time.Now().Truncate(t.width)
+	if n := len(t.buckets); n == 0 || !t.buckets[n-1].start.Equal(start) {
+		t.buckets = append(t.buckets, bucket{start: start, counts: make(map[string]Rate)})
+		t.prune(start)
+	}
+
+	counts := t.buckets[len(t.buckets)-1].counts
+	for _, component := range components {
+		rate := counts[component]
+		rate.Requests++
+		if failed {
+			rate.Errors++
+		}
+		counts[component] = rate
+	}
+}
+
+// Rates returns each component's counts over the window
+func (t *Tracker) Rates() map[string]Rate {
+	rates := make(map[string]Rate)
+	if t == nil {
+		return rates
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.prune(time.Now().Truncate(t.width))
+	for _, b := range t.buckets {
+		for component, count := range b.counts {
+			rate := rates[component]
+			rate.Requests += count.Requests
+			rate.Errors += count.Errors
+			rates[component] = rate
+		}
+	}
+	return rates
+}
+
+// prune drops buckets that started before the window ending with the
+// bucket at current
+func (t *Tracker) prune(current time.Time) {
+	cutoff := current.Add(-t.window + t.width)
+	drop := 0
+	for drop < len(t.buckets) && t.buckets[drop].start.Before(cutoff) {
+		drop++
+	}
+	t.buckets = t.buckets[drop:]
+}
@@ -0,0 +1,54 @@
+package grading
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// BGSGrader looks up Beckett certifications. Beckett has no public API, so
+// it calls a lookup service at a configured URL template with "{cert}" in
+// place of the number, which answers with
+//
+//	{"cert_number": "...", "grade": "...", "subject": "...", "year": "..."}
+type BGSGrader struct {
+	lookupURL string
+	apiKey    string
+}
+
+func NewBGSGrader(lookupURL, apiKey string) *BGSGrader {
+	return &BGSGrader{lookupURL: lookupURL, apiKey: apiKey}
+}
+
+func (g *BGSGrader) Name() string {
+	return "bgs"
+}
+
+func (g *BGSGrader) Lookup(ctx context.Context, number string) (*Cert, error) {
+	var resp struct {
+		CertNumber string `json:"cert_number"`
+		Grade      string `json:"grade"`
+		Subject    string `json:"subject"`
+		Year       string `json:"year"`
+	}
+
+	var authorization string
+	if g.apiKey != "" {
+		authorization = "Bearer " + g.apiKey
+	}
+	lookupURL := strings.ReplaceAll(g.lookupURL, "{cert}", url.PathEscape(number))
+	if err := getJSON(ctx, lookupURL, authorization, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.CertNumber == "" {
+		return nil, ErrCertNotFound
+	}
+
+	return &Cert{
+		Number:  resp.CertNumber,
+		Grade:   resp.Grade,
+		Subject: resp.Subject,
+		Year:    resp.Year,
+	}, nil
+}
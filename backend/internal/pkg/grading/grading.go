@@ -0,0 +1,103 @@
+// Package grading looks up certification numbers with the card grading
+// companies so listings can show the grade on record.
+package grading
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+var (
+	ErrUnknownGrader = errors.New("unknown grader")
+	ErrCertNotFound  = errors.New("certification not found")
+)
+
+// Cert is what the grader has on record for a certification number
+type Cert struct {
+	Number  string
+	Grade   string
+	Subject string
+	Year    string
+}
+
+type Grader interface {
+	// Name is the grader's identifier in listings, e.g. "psa"
+	Name() string
+	// Lookup fetches the certification, returning ErrCertNotFound if the
+	// grader has no record of it
+	Lookup(ctx context.Context, number string) (*Cert, error)
+}
+
+// Registry holds the configured graders by name
+type Registry struct {
+	graders map[string]Grader
+}
+
+func NewRegistry(graders ...Grader) *Registry {
+	r := &Registry{graders: make(map[string]Grader)}
+	for _, g := range graders {
+		r.Register(g)
+	}
+	return r
+}
+
+func (r *Registry) Register(g Grader) {
+	r.graders[g.Name()] = g
+}
+
+func (r *Registry) Get(name string) (Grader, error) {
+	g, ok := r.graders[name]
+	if !ok {
+		return nil, ErrUnknownGrader
+	}
+	return g, nil
+}
+
+// Names lists the configured graders in alphabetical order
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.graders))
+	for name := range r.graders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// getJSON fetches url with the given authorization header, mapping a 404 to
+// ErrCertNotFound
+func getJSON(ctx context.Context, url, authorization string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrCertNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch %s: status %d", url, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", url, err)
+	}
+
+	return nil
+}
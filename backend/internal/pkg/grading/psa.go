@@ -0,0 +1,60 @@
+package grading
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+const psaCertURL = "https://api.psacard.com/publicapi/cert/GetByCertNumber/"
+
+// PSAGrader uses PSA's public cert API, which needs an access token from a
+// PSA account
+type PSAGrader struct {
+	token string
+}
+
+func NewPSAGrader(token string) *PSAGrader {
+	return &PSAGrader{token: token}
+}
+
+func (g *PSAGrader) Name() string {
+	return "psa"
+}
+
+func (g *PSAGrader) Lookup(ctx context.Context, number string) (*Cert, error) {
+	var resp struct {
+		PSACert struct {
+			CertNumber string `json:"CertNumber"`
+			Year       string `json:"Year"`
+			Brand      string `json:"Brand"`
+			Subject    string `json:"Subject"`
+			CardNumber string `json:"CardNumber"`
+			CardGrade  string `json:"CardGrade"`
+		} `json:"PSACert"`
+	}
+	if err := getJSON(ctx, psaCertURL+url.PathEscape(number), "bearer "+g.token, &resp); err != nil {
+		return nil, err
+	}
+
+	// Unknown numbers come back as a 200 with an empty cert
+	cert := resp.PSACert
+	if cert.CertNumber == "" {
+		return nil, ErrCertNotFound
+	}
+
+	subject := cert.Subject
+	if cert.Brand != "" {
+		subject = cert.Brand + " " + subject
+	}
+	if cert.CardNumber != "" {
+		subject += " #" + cert.CardNumber
+	}
+
+	return &Cert{
+		Number:  cert.CertNumber,
+		Grade:   cert.CardGrade,
+		Subject: strings.TrimSpace(subject),
+		Year:    cert.Year,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package imageproc
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"io"
+
+	"github.com/disintegration/imaging"
+	_ "golang.org/x/image/webp" // register WebP decoder
+)
+
+var ErrInvalidImage = errors.New("invalid image data")
+
+// Size names a resized variant of an uploaded image
+type Size string
+
+const (
+	SizeThumbnail Size = "thumbnail"
+	SizeMedium    Size = "medium"
+	SizeLarge     Size = "large"
+)
+
+// maxDimensions is the bounding box each variant is fitted into
+var maxDimensions = map[Size]int{
+	SizeThumbnail: 320,
+	SizeMedium:    800,
+	SizeLarge:     1600,
+}
+
+// Sizes lists the variants generated for every upload, smallest first
+var Sizes = []Size{SizeThumbnail, SizeMedium, SizeLarge}
+
+const jpegQuality = 85
+
+// Variant is an encoded, resized copy of an image
+type Variant struct {
+	Size        Size
+	Data        []byte
+	ContentType string
+}
+
+// GenerateVariants decodes an image and produces every size in Sizes.
+// Images are never upscaled; PNG and GIF sources are re-encoded as PNG to
+// keep transparency, everything else as JPEG.
+func GenerateVariants(r io.Reader, contentType string) ([]Variant, error) {
+	src, err := imaging.Decode(r, imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	format, outType := imaging.JPEG, "image/jpeg"
+	if contentType == "image/png" || contentType == "image/gif" {
+		format, outType = imaging.PNG, "image/png"
+	}
+
+	variants := make([]Variant, 0, len(Sizes))
+	for _, size := range Sizes {
+		var buf bytes.Buffer
+		if err := imaging.Encode(&buf, fit(src, maxDimensions[size]), format, imaging.JPEGQuality(jpegQuality)); err != nil {
+			return nil, err
+		}
+
+		variants = append(variants, Variant{
+			Size:        size,
+			Data:        buf.Bytes(),
+			ContentType: outType,
+		})
+	}
+
+	return variants, nil
+}
+
+func fit(src image.Image, max int) image.Image {
+	bounds := src.Bounds()
+	if bounds.Dx() <= max && bounds.Dy() <= max {
+		return src
+	}
+	return imaging.Fit(src, max, max, imaging.Lanczos)
+}
@@ -0,0 +1,161 @@
+// Package moderation scans user-written text for profanity and the usual
+// marketplace scams: suspicious payment links and requests to pay outside
+// the platform.
+package moderation
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+type Category string
+
+const (
+	CategoryProfanity   Category = "profanity"
+	CategoryScamLink    Category = "scam_link"
+	CategoryOffPlatform Category = "off_platform_payment"
+)
+
+// Finding is one suspicious passage in the scanned text
+type Finding struct {
+	Category Category
+	Match    string
+}
+
+type Result struct {
+	Findings []Finding
+	// Censored is the text with profanity masked
+	Censored string
+}
+
+// Has reports whether any finding is in the category
+func (r *Result) Has(category Category) bool {
+	for _, f := range r.Findings {
+		if f.Category == category {
+			return true
+		}
+	}
+	return false
+}
+
+// Categories lists the distinct categories found, in order of first match
+func (r *Result) Categories() []Category {
+	var categories []Category
+	for _, f := range r.Findings {
+		if !containsCategory(categories, f.Category) {
+			categories = append(categories, f.Category)
+		}
+	}
+	return categories
+}
+
+// IsScam reports whether the text looks like an attempt to defraud the
+// recipient, as opposed to being merely rude
+func (r *Result) IsScam() bool {
+	return r.Has(CategoryScamLink) || r.Has(CategoryOffPlatform)
+}
+
+var (
+	profanityPattern = regexp.MustCompile(`(?i)\b(?:motherfuck(?:er|ing)?s?|fuck(?:ing|er|ed)?s?|bullshit|shit(?:ty|head)?s?|bitch(?:es)?|bastards?|assholes?|cunts?|dick(?:head)?s?|wankers?|twats?|pricks?|sluts?|whores?)\b`)
+
+	linkPattern = regexp.MustCompile(`(?i)\b(?:https?://[^\s<>"]+|www\.[^\s<>"]+|[a-z0-9-]+(?:\.[a-z0-9-]+)*\.(?:com|net|org|io|me|app|co|ly|gd|link|xyz|top|info|biz|shop|site|online)(?:/[^\s<>"]*)?)`)
+
+	offPlatformPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(?:pay|paid|send|sent|transfer|wire)\b[^.!?\n]{0,40}\b(?:paypal|venmo|zelle|cash ?app|western union|moneygram|bank transfer|wire transfer|crypto|bitcoin|btc|eth|usdt|gift ?cards?|friends (?:and|&) family)\b`),
+		regexp.MustCompile(`(?i)\b(?:outside|off)(?: of)? (?:the |this )?(?:site|platform|app|auction|website)\b`),
+		regexp.MustCompile(`(?i)\b(?:contact|text|message|reach|add) me (?:on|at|via) (?:whats ?app|telegram|signal|wechat)\b`),
+	}
+)
+
+// Hosts that collect payments or hide where a link goes. Buyers are never
+// asked to pay through them on the platform.
+var suspiciousHosts = []string{
+	"paypal.me", "cash.app", "venmo.com", "revolut.me", "wise.com", "zellepay.com",
+	"bit.ly", "tinyurl.com", "t.co", "goo.gl", "is.gd", "cutt.ly", "rb.gy", "shorturl.at",
+	"wa.me", "t.me",
+}
+
+// Brands that phishing domains imitate, e.g. paypal-secure-checkout.com.
+// A brand must be a whole label of the host or a hyphenated part of one, so
+// pineapple.com is not taken for apple.
+var impersonatedBrands = map[string]string{
+	"paypal": "paypal.com",
+	"stripe": "stripe.com",
+	"venmo":  "venmo.com",
+	"escrow": "escrow.com",
+	"ebay":   "ebay.com",
+	"apple":  "apple.com",
+}
+
+// Scan checks text for profanity, scam links and off-platform payment
+// requests
+func Scan(text string) *Result {
+	result := &Result{Censored: text}
+
+	for _, match := range profanityPattern.FindAllString(text, -1) {
+		result.Findings = append(result.Findings, Finding{Category: CategoryProfanity, Match: match})
+	}
+	result.Censored = profanityPattern.ReplaceAllStringFunc(text, mask)
+
+	for _, link := range linkPattern.FindAllString(text, -1) {
+		if isSuspiciousLink(link) {
+			result.Findings = append(result.Findings, Finding{Category: CategoryScamLink, Match: link})
+		}
+	}
+
+	for _, pattern := range offPlatformPatterns {
+		for _, match := range pattern.FindAllString(text, -1) {
+			result.Findings = append(result.Findings, Finding{Category: CategoryOffPlatform, Match: match})
+		}
+	}
+
+	return result
+}
+
+func isSuspiciousLink(link string) bool {
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+
+	for _, h := range suspiciousHosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+
+	for _, token := range strings.FieldsFunc(host, isHostSeparator) {
+		official, ok := impersonatedBrands[token]
+		if ok && host != official && !strings.HasSuffix(host, "."+official) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isHostSeparator(r rune) bool {
+	return r == '.' || r == '-'
+}
+
+// mask keeps the first letter of a word and stars out the rest
+func mask(word string) string {
+	if len(word) <= 1 {
+		return word
+	}
+	return word[:1] + strings.Repeat("*", len(word)-1)
+}
+
+func containsCategory(categories []Category, c Category) bool {
+	for _, existing := range categories {
+		if existing == c {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package moderation_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/auction-cards/backend/internal/pkg/moderation"
+)
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		name           string
+		text           string
+		wantCategories []moderation.Category
+		wantCensored   string
+	}{
+		{name: "clean text", text: "Lovely card, ships in a top loader.", wantCategories: nil},
+		{name: "profanity is masked", text: "This shit is bullshit", wantCategories: []moderation.Category{moderation.CategoryProfanity}, wantCensored: "This s*** is b*******"},
+		{name: "profanity inside a word", text: "Shipped from Scunthorpe", wantCategories: nil},
+
+		{name: "payment link", text: "my shop: paypal.me/cardguy", wantCategories: []moderation.Category{moderation.CategoryScamLink}},
+		{name: "subdomain of a payment host", text: "https://checkout.cash.app/x", wantCategories: []moderation.Category{moderation.CategoryScamLink}},
+		{name: "link shortener", text: "photos here: bit.ly/3xYz", wantCategories: []moderation.Category{moderation.CategoryScamLink}},
+		{name: "messenger link", text: "https://t.me/cards", wantCategories: []moderation.Category{moderation.CategoryScamLink}},
+
+		{name: "brand with a hyphenated suffix", text: "verify at paypal-secure.com", wantCategories: []moderation.Category{moderation.CategoryScamLink}},
+		{name: "brand as a subdomain", text: "https://paypal.example.net/login", wantCategories: []moderation.Category{moderation.CategoryScamLink}},
+		{name: "brand after a hyphen", text: "www.secure-ebay-checkout.shop", wantCategories: []moderation.Category{moderation.CategoryScamLink}},
+		{name: "official brand domain", text: "see https://www.paypal.com/help", wantCategories: nil},
+		{name: "official brand subdomain", text: "https://pages.ebay.com/help", wantCategories: nil},
+
+		{name: "brand inside a longer word", text: "pineapple.com", wantCategories: nil},
+		{name: "brand at the end of a word", text: "snapple.com", wantCategories: nil},
+		{name: "brand at the start of a word", text: "escrowlaw.org", wantCategories: nil},
+		{name: "ordinary link", text: "grading info at https://www.psacard.com", wantCategories: nil},
+
+		{name: "pay by an outside service", text: "Just send the money via Zelle", wantCategories: []moderation.Category{moderation.CategoryOffPlatform}},
+		{name: "friends and family", text: "pay with PayPal friends and family please", wantCategories: []moderation.Category{moderation.CategoryOffPlatform}},
+		{name: "deal off the platform", text: "Let's do this off the site, cheaper for both", wantCategories: []moderation.Category{moderation.CategoryOffPlatform}},
+		{name: "move to a messenger", text: "contact me on WhatsApp", wantCategories: []moderation.Category{moderation.CategoryOffPlatform}},
+		{name: "payment mentioned without a request", text: "I paid promptly as always", wantCategories: nil},
+
+		{name: "several categories", text: "damn bastard, pay via bitcoin at bit.ly/x", wantCategories: []moderation.Category{moderation.CategoryProfanity, moderation.CategoryScamLink, moderation.CategoryOffPlatform}, wantCensored: "damn b******, pay via bitcoin at bit.ly/x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := moderation.Scan(tt.text)
+
+			if got := result.Categories(); !reflect.DeepEqual(got, tt.wantCategories) {
+				t.Errorf("got categories %v, want %v (findings %+v)", got, tt.wantCategories, result.Findings)
+			}
+
+			wantCensored := tt.wantCensored
+			if wantCensored == "" {
+				wantCensored = tt.text
+			}
+			if result.Censored != wantCensored {
+				t.Errorf("got censored %q, want %q", result.Censored, wantCensored)
+			}
+		})
+	}
+}
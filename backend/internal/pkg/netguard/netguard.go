@@ -0,0 +1,37 @@
+// Package netguard keeps requests to user-supplied URLs from reaching the
+// server's own network
+package netguard
+
+import (
+	"errors"
+	"net"
+	"syscall"
+)
+
+var ErrPrivateAddress = errors.New("address is not publicly routable")
+
+// Control refuses connections to addresses that aren't publicly routable.
+// Set as a net.Dialer's Control it runs on every dial, after DNS resolution,
+// so a hostname can't be re-pointed at a private address once checked.
+func Control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !IsPublicIP(ip) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+// IsPublicIP reports whether ip is routable on the internet
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() ||
+		carrierGradeNAT.Contains(ip))
+}
+
+// carrierGradeNAT (RFC 6598) is shared address space that isn't covered by
+// net.IP.IsPrivate
+var carrierGradeNAT = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
@@ -0,0 +1,137 @@
+package oauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+const appleIssuer = "https://appleid.apple.com"
+
+var appleEndpoint = oauth2.Endpoint{
+	AuthURL:   "https://appleid.apple.com/auth/authorize",
+	TokenURL:  "https://appleid.apple.com/auth/token",
+	AuthStyle: oauth2.AuthStyleInParams,
+}
+
+// AppleProvider implements Sign in with Apple. Apple has no client secret;
+// each token request is signed with the team's private key instead.
+type AppleProvider struct {
+	config     *oauth2.Config
+	teamID     string
+	keyID      string
+	privateKey *ecdsa.PrivateKey
+}
+
+// NewAppleProvider takes the services ID as clientID and the PEM encoded
+// .p8 key downloaded from the developer portal. Escaped newlines in the key
+// are accepted so it can be passed through a single-line env var.
+func NewAppleProvider(clientID, teamID, keyID, privateKeyPEM, redirectURL string) (*AppleProvider, error) {
+	privateKeyPEM = strings.ReplaceAll(privateKeyPEM, `\n`, "\n")
+	key, err := jwt.ParseECPrivateKeyFromPEM([]byte(privateKeyPEM))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse apple private key: %w", err)
+	}
+
+	return &AppleProvider{
+		config: &oauth2.Config{
+			ClientID:    clientID,
+			RedirectURL: redirectURL,
+			Scopes:      []string{"name", "email"},
+			Endpoint:    appleEndpoint,
+		},
+		teamID:     teamID,
+		keyID:      keyID,
+		privateKey: key,
+	}, nil
+}
+
+func (p *AppleProvider) Name() string {
+	return "apple"
+}
+
+// FormPost is always true: Apple requires form_post when scopes are requested
+func (p *AppleProvider) FormPost() bool {
+	return true
+}
+
+func (p *AppleProvider) AuthURL(state string) string {
+	return p.config.AuthCodeURL(state, oauth2.SetAuthURLParam("response_mode", "form_post"))
+}
+
+func (p *AppleProvider) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	secret, err := p.clientSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	config := *p.config
+	config.ClientSecret = secret
+	return config.Exchange(ctx, code)
+}
+
+// FetchProfile reads the identity from the ID token returned with the access
+// token. It came straight from Apple's token endpoint over TLS, so only the
+// issuer and audience are checked, not the signature.
+func (p *AppleProvider) FetchProfile(ctx context.Context, token *oauth2.Token) (*Profile, error) {
+	idToken, ok := token.Extra("id_token").(string)
+	if !ok || idToken == "" {
+		return nil, errors.New("apple token response has no id_token")
+	}
+
+	var claims struct {
+		Email         string      `json:"email"`
+		EmailVerified interface{} `json:"email_verified"`
+		jwt.RegisteredClaims
+	}
+	if _, _, err := jwt.NewParser().ParseUnverified(idToken, &claims); err != nil {
+		return nil, fmt.Errorf("failed to parse apple id token: %w", err)
+	}
+
+	if claims.Issuer != appleIssuer || !slices.Contains(claims.Audience, p.config.ClientID) {
+		return nil, errors.New("apple id token was not issued for this client")
+	}
+
+	// Apple sends email_verified as either a boolean or the string "true"
+	verified := claims.EmailVerified == true || claims.EmailVerified == "true"
+
+	// Apple only shares the user's name on the first sign in, so fall back
+	// to the email's local part
+	return &Profile{
+		ID:            claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: verified,
+		Name:          emailLocalPart(claims.Email),
+	}, nil
+}
+
+// clientSecret signs the short-lived JWT Apple accepts as client secret
+func (p *AppleProvider) clientSecret() (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+		Issuer:    p.teamID,
+		Subject:   p.config.ClientID,
+		Audience:  jwt.ClaimStrings{appleIssuer},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+	})
+	token.Header["kid"] = p.keyID
+
+	secret, err := token.SignedString(p.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign apple client secret: %w", err)
+	}
+	return secret, nil
+}
+
+func emailLocalPart(email string) string {
+	local, _, _ := strings.Cut(email, "@")
+	return local
+}
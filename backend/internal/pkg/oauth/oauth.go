@@ -0,0 +1,74 @@
+// Package oauth implements the third-party login providers behind a common
+// interface so handlers can serve any of them by name.
+package oauth
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"golang.org/x/oauth2"
+)
+
+var ErrUnknownProvider = errors.New("unknown oauth provider")
+
+// Profile is the identity a provider vouches for
+type Profile struct {
+	ID            string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type Provider interface {
+	// Name is the provider's identifier in routes and linked accounts
+	Name() string
+	// AuthURL returns the consent page URL the user is redirected to
+	AuthURL(state string) string
+	// Exchange trades the callback code for a token
+	Exchange(ctx context.Context, code string) (*oauth2.Token, error)
+	// FetchProfile looks up the signed in user with the token
+	FetchProfile(ctx context.Context, token *oauth2.Token) (*Profile, error)
+}
+
+// FormPoster is implemented by providers that POST the callback from their
+// own origin rather than redirecting, so the state cookie must be sent on
+// cross-site requests
+type FormPoster interface {
+	FormPost() bool
+}
+
+// Registry holds the configured providers by name
+type Registry struct {
+	providers map[string]Provider
+}
+
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{providers: make(map[string]Provider)}
+	for _, p := range providers {
+		r.Register(p)
+	}
+	return r
+}
+
+func (r *Registry) Register(p Provider) {
+	r.providers[p.Name()] = p
+}
+
+func (r *Registry) Get(name string) (Provider, error) {
+	p, ok := r.providers[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return p, nil
+}
+
+// Names lists the configured providers in alphabetical order
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,169 @@
+// Package origin matches request origins against allow-list patterns such
+// as https://app.example.com, https://*.example.com or http://localhost:*.
+package origin
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+var ErrInvalidPattern = errors.New("invalid origin pattern")
+
+// Pattern is a compiled origin pattern. A host starting with "*." matches
+// any subdomain but not the domain itself, and a "*" port matches any port.
+// The pattern "*" matches every origin.
+type Pattern struct {
+	any       bool
+	scheme    string
+	host      string
+	subdomain bool
+	port      string
+}
+
+// Compile parses an origin pattern
+func Compile(pattern string) (Pattern, error) {
+	pattern = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(pattern), "/"))
+	if pattern == "*" {
+		return Pattern{any: true}, nil
+	}
+
+	scheme, host, port, err := split(pattern)
+	if err != nil {
+		return Pattern{}, fmt.Errorf("%w %q: %v", ErrInvalidPattern, pattern, err)
+	}
+
+	p := Pattern{scheme: scheme, host: host, port: port}
+	if strings.HasPrefix(host, "*.") {
+		p.subdomain = true
+		p.host = host[2:]
+	}
+	if p.host == "" || strings.Contains(p.host, "*") {
+		return Pattern{}, fmt.Errorf("%w %q: wildcards are only allowed as the leftmost label", ErrInvalidPattern, pattern)
+	}
+	if p.port != "" && p.port != "*" && strings.Trim(p.port, "0123456789") != "" {
+		return Pattern{}, fmt.Errorf("%w %q: bad port", ErrInvalidPattern, pattern)
+	}
+
+	return p, nil
+}
+
+// Match reports whether the Origin header value is allowed by the pattern
+func (p Pattern) Match(origin string) bool {
+	if p.any {
+		return origin != ""
+	}
+
+	scheme, host, port, err := split(strings.ToLower(origin))
+	if err != nil || strings.Contains(host, "*") {
+		return false
+	}
+
+	if scheme != p.scheme {
+		return false
+	}
+	if p.port != "*" && port != p.port {
+		return false
+	}
+	if p.subdomain {
+		return strings.HasSuffix(host, "."+p.host)
+	}
+	return host == p.host
+}
+
+func (p Pattern) String() string {
+	if p.any {
+		return "*"
+	}
+	s := p.scheme + "://"
+	if p.subdomain {
+		s += "*."
+	}
+	s += p.host
+	if p.port != "" {
+		s += ":" + p.port
+	}
+	return s
+}
+
+// split breaks scheme://host[:port] into its parts
+func split(s string) (scheme, host, port string, err error) {
+	scheme, hostport, ok := strings.Cut(s, "://")
+	if !ok {
+		return "", "", "", errors.New("missing scheme")
+	}
+	if scheme != "http" && scheme != "https" {
+		return "", "", "", errors.New("scheme must be http or https")
+	}
+	if hostport == "" || strings.ContainsAny(hostport, "/?#@ ") {
+		return "", "", "", errors.New("must be scheme://host[:port] without a path")
+	}
+
+	host = hostport
+	if strings.LastIndex(hostport, ":") > strings.LastIndex(hostport, "]") {
+		host, port, err = net.SplitHostPort(hostport)
+		if err != nil {
+			return "", "", "", err
+		}
+	}
+
+	return scheme, host, port, nil
+}
+
+// AllowList holds the static patterns from configuration and a managed set
+// that can be replaced at runtime. Patterns that don't compile are ignored,
+// so validate them with Compile first.
+type AllowList struct {
+	mu      sync.RWMutex
+	static  []Pattern
+	managed []Pattern
+}
+
+func NewAllowList(static []string) *AllowList {
+	return &AllowList{static: compileAll(static)}
+}
+
+// SetManaged replaces the runtime-managed patterns
+func (l *AllowList) SetManaged(patterns []string) {
+	compiled := compileAll(patterns)
+
+	l.mu.Lock()
+	l.managed = compiled
+	l.mu.Unlock()
+}
+
+// Allowed reports whether any pattern matches the origin. A nil list
+// allows nothing.
+func (l *AllowList) Allowed(origin string) bool {
+	if l == nil || origin == "" {
+		return false
+	}
+
+	for _, p := range l.static {
+		if p.Match(origin) {
+			return true
+		}
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, p := range l.managed {
+		if p.Match(origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func compileAll(patterns []string) []Pattern {
+	compiled := make([]Pattern, 0, len(patterns))
+	for _, s := range patterns {
+		if p, err := Compile(s); err == nil {
+			compiled = append(compiled, p)
+		}
+	}
+	return compiled
+}
@@ -0,0 +1,200 @@
+// Package pdf writes simple one-page PDF documents: text in the standard
+// Helvetica fonts, filled rectangles and JPEG images. Coordinates are in
+// points from the top left corner of the page, and text is positioned by
+// its baseline.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// A4 page size in points
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+const jpegQuality = 85
+
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+var fontNames = map[Font]string{
+	Helvetica:     "Helvetica",
+	HelveticaBold: "Helvetica-Bold",
+}
+
+type embeddedImage struct {
+	data          []byte
+	width, height int
+}
+
+// Document is a single page being drawn
+type Document struct {
+	width, height float64
+	content       bytes.Buffer
+	images        []embeddedImage
+}
+
+func New(width, height float64) *Document {
+	return &Document{width: width, height: height}
+}
+
+// SetGray sets the color for what is drawn next, from 0 (black) to 1
+// (white)
+func (d *Document) SetGray(level float64) {
+	fmt.Fprintf(&d.content, "%.3f g\n", level)
+}
+
+// Text draws a single line of text with its baseline at y. Characters
+// Windows-1252 lacks are drawn as question marks.
+func (d *Document) Text(x, y float64, font Font, size float64, text string) {
+	fmt.Fprintf(&d.content, "BT /F%d %.2f Tf %.2f %.2f Td (%s) Tj ET\n",
+		font+1, size, x, d.height-y, escape(encode(text)))
+}
+
+// Rect fills a rectangle whose top left corner is at (x, y)
+func (d *Document) Rect(x, y, width, height float64) {
+	fmt.Fprintf(&d.content, "%.2f %.2f %.2f %.2f re f\n", x, d.height-y-height, width, height)
+}
+
+// Image draws img stretched over the rectangle whose top left corner is at
+// (x, y). Transparent areas are drawn on white.
+func (d *Document) Image(img image.Image, x, y, width, height float64) error {
+	bounds := img.Bounds()
+	flat := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, bounds.Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	d.images = append(d.images, embeddedImage{data: buf.Bytes(), width: bounds.Dx(), height: bounds.Dy()})
+	fmt.Fprintf(&d.content, "q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n",
+		width, height, x, d.height-y-height, len(d.images))
+	return nil
+}
+
+// Bytes returns the finished PDF file
+func (d *Document) Bytes() ([]byte, error) {
+	var content bytes.Buffer
+	zw := zlib.NewWriter(&content)
+	if _, err := zw.Write(d.content.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	// Objects 1 and 2 are the catalog and page tree, 3 the page, 4 its
+	// content, then the fonts and the images
+	fontBase := 5
+	imageBase := fontBase + len(fontNames)
+
+	var resources strings.Builder
+	resources.WriteString("/Font <<")
+	for font := Helvetica; int(font) < len(fontNames); font++ {
+		fmt.Fprintf(&resources, " /F%d %d 0 R", font+1, fontBase+int(font))
+	}
+	resources.WriteString(" >>")
+	if len(d.images) > 0 {
+		resources.WriteString(" /XObject <<")
+		for i := range d.images {
+			fmt.Fprintf(&resources, " /Im%d %d 0 R", i+1, imageBase+i)
+		}
+		resources.WriteString(" >>")
+	}
+
+	w := &writer{}
+	w.buf.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+	w.object("<< /Type /Catalog /Pages 2 0 R >>", nil)
+	w.object("<< /Type /Pages /Kids [3 0 R] /Count 1 >>", nil)
+	w.object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << %s >> /Contents 4 0 R >>",
+		d.width, d.height, resources.String()), nil)
+	w.object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>", content.Len()), content.Bytes())
+	for font := Helvetica; int(font) < len(fontNames); font++ {
+		w.object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", fontNames[font]), nil)
+	}
+	for _, img := range d.images {
+		w.object(fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>",
+			img.width, img.height, len(img.data)), img.data)
+	}
+
+	return w.finish(), nil
+}
+
+// writer lays out numbered objects and the cross-reference table
+type writer struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+func (w *writer) object(dict string, stream []byte) {
+	w.offsets = append(w.offsets, w.buf.Len())
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\n", len(w.offsets), dict)
+	if stream != nil {
+		w.buf.WriteString("stream\n")
+		w.buf.Write(stream)
+		w.buf.WriteString("\nendstream\n")
+	}
+	w.buf.WriteString("endobj\n")
+}
+
+func (w *writer) finish() []byte {
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, offset := range w.offsets {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(w.offsets)+1, xref)
+	return w.buf.Bytes()
+}
+
+// encode converts text to Windows-1252, which the fonts are set up with
+func encode(text string) []byte {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			r = ' '
+		}
+		b, ok := charmap.Windows1252.EncodeRune(r)
+		if !ok {
+			b = '?'
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+// escape writes encoded text as the inside of a PDF string literal
+func escape(text []byte) string {
+	var sb strings.Builder
+	for _, b := range text {
+		switch {
+		case b == '(' || b == ')' || b == '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(b)
+		case b < 0x20 || b > 0x7E:
+			fmt.Fprintf(&sb, "\\%03o", b)
+		default:
+			sb.WriteByte(b)
+		}
+	}
+	return sb.String()
+}
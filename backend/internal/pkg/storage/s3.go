@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
@@ -9,17 +10,28 @@ import (
 	"strings"
 	"time"
 
+	"github.com/auction-cards/backend/internal/pkg/breaker"
 	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 )
 
+// ObjectStore keeps objects under names chosen by the caller, such as data
+// export archives, which are handed out through presigned links only
+type ObjectStore interface {
+	PutObject(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) error
+	RemoveObject(ctx context.Context, objectName string) error
+	GetPresignedURL(ctx context.Context, objectName string, expiry time.Duration) (string, error)
+}
+
 type S3Storage struct {
 	client     *minio.Client
 	bucketName string
 	endpoint   string
 	publicURL  string
 	useSSL     bool
+	timeout    time.Duration
+	breaker    *breaker.Breaker
 }
 
 type Config struct {
@@ -27,14 +39,19 @@ type Config struct {
 	AccessKeyID     string
 	SecretAccessKey string
 	BucketName      string
+	Region          string // Optional: region the bucket is created in
+	Private         bool   // Don't open a new bucket for public reads
 	UseSSL          bool
-	PublicURL       string // Optional: public URL for serving files (e.g., Cloudflare R2 dev URL)
+	PublicURL       string           // Optional: public URL for serving files (e.g., Cloudflare R2 dev URL)
+	Timeout         time.Duration    // Optional: limit on each upload or delete
+	Breaker         *breaker.Breaker // Optional: fails uploads and deletes fast while S3 is down
 }
 
 func NewS3Storage(cfg *Config) (*S3Storage, error) {
 	client, err := minio.New(cfg.Endpoint, &minio.Options{
 		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
 		Secure: cfg.UseSSL,
+		Region: cfg.Region,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create minio client: %w", err)
@@ -46,27 +63,32 @@ func NewS3Storage(cfg *Config) (*S3Storage, error) {
 		endpoint:   cfg.Endpoint,
 		publicURL:  cfg.PublicURL,
 		useSSL:     cfg.UseSSL,
+		timeout:    cfg.Timeout,
+		breaker:    cfg.Breaker,
 	}
 
 	// Ensure bucket exists
-	if err := storage.ensureBucket(context.Background()); err != nil {
+	if err := storage.ensureBucket(context.Background(), cfg.Region, !cfg.Private); err != nil {
 		return nil, err
 	}
 
 	return storage, nil
 }
 
-func (s *S3Storage) ensureBucket(ctx context.Context) error {
+func (s *S3Storage) ensureBucket(ctx context.Context, region string, public bool) error {
 	exists, err := s.client.BucketExists(ctx, s.bucketName)
 	if err != nil {
 		return fmt.Errorf("failed to check bucket existence: %w", err)
 	}
 
 	if !exists {
-		err = s.client.MakeBucket(ctx, s.bucketName, minio.MakeBucketOptions{})
+		err = s.client.MakeBucket(ctx, s.bucketName, minio.MakeBucketOptions{Region: region})
 		if err != nil {
 			return fmt.Errorf("failed to create bucket: %w", err)
 		}
+		if !public {
+			return nil
+		}
 
 		// Try to set bucket policy for public read access
 		// Note: This may fail on Cloudflare R2 (doesn't support SetBucketPolicy)
@@ -100,8 +122,11 @@ func (s *S3Storage) Upload(ctx context.Context, reader io.Reader, contentType st
 	filename := fmt.Sprintf("%s/%s%s", folder, uuid.New().String(), ext)
 
 	// Upload file
-	_, err := s.client.PutObject(ctx, s.bucketName, filename, reader, size, minio.PutObjectOptions{
-		ContentType: contentType,
+	err := s.call(ctx, func(ctx context.Context) error {
+		_, err := s.client.PutObject(ctx, s.bucketName, filename, reader, size, minio.PutObjectOptions{
+			ContentType: contentType,
+		})
+		return err
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to upload file: %w", err)
@@ -118,7 +143,9 @@ func (s *S3Storage) Delete(ctx context.Context, fileURL string) error {
 		return err
 	}
 
-	err = s.client.RemoveObject(ctx, s.bucketName, objectName, minio.RemoveObjectOptions{})
+	err = s.call(ctx, func(ctx context.Context) error {
+		return s.client.RemoveObject(ctx, s.bucketName, objectName, minio.RemoveObjectOptions{})
+	})
 	if err != nil {
 		return fmt.Errorf("failed to delete file: %w", err)
 	}
@@ -126,6 +153,54 @@ func (s *S3Storage) Delete(ctx context.Context, fileURL string) error {
 	return nil
 }
 
+// PutObject stores an object under the given name
+func (s *S3Storage) PutObject(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) error {
+	err := s.call(ctx, func(ctx context.Context) error {
+		_, err := s.client.PutObject(ctx, s.bucketName, objectName, reader, size, minio.PutObjectOptions{
+			ContentType: contentType,
+		})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	return nil
+}
+
+// RemoveObject deletes the object with the given name
+func (s *S3Storage) RemoveObject(ctx context.Context, objectName string) error {
+	err := s.call(ctx, func(ctx context.Context) error {
+		return s.client.RemoveObject(ctx, s.bucketName, objectName, minio.RemoveObjectOptions{})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	return nil
+}
+
+// call runs a request to S3 within the configured timeout and breaker.
+// Errors S3 replied with, such as a missing object, don't count against
+// the breaker.
+func (s *S3Storage) call(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	if s.breaker == nil {
+		return fn(ctx)
+	}
+
+	done, err := s.breaker.Allow()
+	if err != nil {
+		return err
+	}
+	err = fn(ctx)
+	var reply minio.ErrorResponse
+	done(err != nil && !errors.As(err, &reply) && !errors.Is(err, context.Canceled))
+	return err
+}
+
 func (s *S3Storage) GetPublicURL(objectName string) string {
 	// If a public URL is configured (e.g., Cloudflare R2 dev URL), use it
 	if s.publicURL != "" {
@@ -168,6 +243,8 @@ func getExtensionFromContentType(contentType string) string {
 		return ".gif"
 	case "image/webp":
 		return ".webp"
+	case "application/pdf":
+		return ".pdf"
 	default:
 		return ""
 	}
@@ -184,6 +261,11 @@ func ValidateImageContentType(contentType string) bool {
 	return allowedTypes[contentType]
 }
 
+// ValidateEvidenceContentType checks if the content type is allowed as dispute evidence
+func ValidateEvidenceContentType(contentType string) bool {
+	return ValidateImageContentType(contentType) || contentType == "application/pdf"
+}
+
 // MaxImageSize is the maximum allowed image size (10MB)
 const MaxImageSize = 10 * 1024 * 1024
 
@@ -196,3 +278,21 @@ func GetImageFolder(auctionID uuid.UUID) string {
 func GetAvatarFolder(userID uuid.UUID) string {
 	return path.Join("avatars", userID.String())
 }
+
+// GetDisputeEvidenceFolder returns the folder path for dispute evidence
+func GetDisputeEvidenceFolder(disputeID uuid.UUID) string {
+	return path.Join("disputes", disputeID.String())
+}
+
+// GetExportFolder returns the folder path for a user's data exports. Their
+// object names are random, as the bucket may allow public reads.
+func GetExportFolder(userID uuid.UUID) string {
+	return path.Join("exports", userID.String())
+}
+
+// GetFactsheetFolder returns the folder path for an auction's fact sheets.
+// Their object names are random, as private auctions' sheets are only handed
+// out through presigned links.
+func GetFactsheetFolder(auctionID uuid.UUID) string {
+	return path.Join("factsheets", auctionID.String())
+}
@@ -0,0 +1,59 @@
+// Package username derives valid usernames from names given by OAuth
+// providers
+package username
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Usernames are 3 to 50 ASCII letters and digits, as at registration
+const (
+	MinLength = 3
+	MaxLength = 50
+)
+
+// Fallback is used when neither the name nor the email yields a username
+const Fallback = "user"
+
+// Normalize reduces a name to a lowercase username by folding accents and
+// dropping everything but ASCII letters and digits, so "José Müller" becomes
+// "josemuller". The result may be shorter than MinLength, even empty.
+func Normalize(name string) string {
+	var b strings.Builder
+	for _, r := range norm.NFKD.String(name) {
+		r = unicode.ToLower(r)
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+		if b.Len() == MaxLength {
+			break
+		}
+	}
+	return b.String()
+}
+
+// Base picks the username to try first: the normalized display name, else
+// the normalized local part of the email, else Fallback
+func Base(displayName, email string) string {
+	if base := Normalize(displayName); len(base) >= MinLength {
+		return base
+	}
+	local, _, _ := strings.Cut(email, "@")
+	if base := Normalize(local); len(base) >= MinLength {
+		return base
+	}
+	return Fallback
+}
+
+// WithSuffix appends n to base, shortening base so the result still fits
+func WithSuffix(base string, n int) string {
+	suffix := strconv.Itoa(n)
+	if len(base)+len(suffix) > MaxLength {
+		base = base[:MaxLength-len(suffix)]
+	}
+	return base + suffix
+}
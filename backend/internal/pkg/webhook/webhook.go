@@ -0,0 +1,157 @@
+// Package webhook signs and sends event deliveries to user-registered URLs.
+//
+// Each delivery is a JSON POST carrying these headers:
+//
+//	X-Webhook-Event:     the event name, e.g. "bid.placed"
+//	X-Webhook-Delivery:  the delivery ID, stable across retries
+//	X-Webhook-Timestamp: Unix seconds when this attempt was signed
+//	X-Webhook-Signature: "sha256=" + hex HMAC-SHA256 of "<timestamp>.<body>"
+//
+// Receivers should recompute the signature with their secret, compare it in
+// constant time and reject stale timestamps to stop replays.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/auction-cards/backend/internal/pkg/netguard"
+)
+
+var ErrForbiddenTarget = errors.New("webhook URL must be a public http(s) address")
+
+// maxResponseBytes is how much of a receiver's response is read before the
+// connection is dropped
+const maxResponseBytes = 64 << 10
+
+// Sign returns the signature header value for body sent at timestamp
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Client posts deliveries. Unless private targets are allowed it refuses to
+// connect to loopback, private and link-local addresses, checked on every
+// dial so a hostname can't be re-pointed at them after registration.
+type Client struct {
+	http         *http.Client
+	allowPrivate bool
+}
+
+func NewClient(timeout time.Duration, allowPrivate bool) *Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, c syscall.RawConn) error {
+			if err := netguard.Control(network, address, c); err != nil {
+				return ErrForbiddenTarget
+			}
+			return nil
+		}
+	}
+
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+		MaxIdleConnsPerHost:   2,
+		IdleConnTimeout:       90 * time.Second,
+	}
+
+	return &Client{
+		http: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			// A redirect could lead anywhere; receivers must answer directly
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		allowPrivate: allowPrivate,
+	}
+}
+
+// ValidateURL checks a URL before it is registered. Hostnames are checked
+// again when connecting, since what they resolve to can change.
+func (c *Client) ValidateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.User != nil {
+		return ErrForbiddenTarget
+	}
+
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if !c.allowPrivate {
+			return ErrForbiddenTarget
+		}
+	default:
+		return ErrForbiddenTarget
+	}
+
+	if c.allowPrivate {
+		return nil
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !netguard.IsPublicIP(ip) {
+		return ErrForbiddenTarget
+	}
+	if u.Hostname() == "localhost" {
+		return ErrForbiddenTarget
+	}
+
+	return nil
+}
+
+// Delivery is one signed POST to a receiver
+type Delivery struct {
+	ID     string
+	URL    string
+	Secret string
+	Event  string
+	Body   []byte
+}
+
+// Send posts the delivery and returns the response status. A non-2xx status
+// is returned as an error along with the status code.
+func (c *Client) Send(ctx context.Context, d *Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "AuctionCards-Webhooks/1.0")
+	req.Header.Set("X-Webhook-Event", d.Event)
+	req.Header.Set("X-Webhook-Delivery", d.ID)
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", Sign(d.Secret, timestamp, d.Body))
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver responded %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
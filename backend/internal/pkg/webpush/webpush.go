@@ -0,0 +1,378 @@
+// Package webpush sends Web Push messages (RFC 8030) to browser push
+// services, encrypted for the subscription (RFC 8291) and signed with the
+// server's VAPID key (RFC 8292).
+//
+// The VAPID key pair is a P-256 key: the public key is the 65 byte
+// uncompressed point and the private key the 32 byte scalar, both unpadded
+// base64url as browsers expect. GenerateKeys makes a new pair.
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/auction-cards/backend/internal/pkg/netguard"
+)
+
+var (
+	// ErrGone is returned when the push service no longer knows the
+	// subscription; it should be deleted
+	ErrGone = errors.New("push subscription has expired or been removed")
+	// ErrForbiddenEndpoint is returned for endpoints that aren't public
+	// https addresses
+	ErrForbiddenEndpoint = errors.New("push endpoint must be a public https address")
+	// ErrInvalidKeys is returned for subscription keys that aren't a P-256
+	// public key and a 16 byte auth secret
+	ErrInvalidKeys = errors.New("push subscription keys are invalid")
+)
+
+const (
+	// recordSize is the aes128gcm record size; payloads always fit one
+	// record
+	recordSize = 4096
+	// MaxPayload is the largest payload push services must accept, less the
+	// encryption overhead
+	MaxPayload = 3993
+
+	// tokenTTL is how long a VAPID token is valid. Push services refuse
+	// tokens valid for more than 24 hours.
+	tokenTTL = 12 * time.Hour
+
+	maxResponseBytes = 64 << 10
+)
+
+type Urgency string
+
+const (
+	UrgencyLow    Urgency = "low"
+	UrgencyNormal Urgency = "normal"
+	UrgencyHigh   Urgency = "high"
+)
+
+// Subscription is what the browser's PushManager.subscribe returns. The keys
+// are base64url, padded or not.
+type Subscription struct {
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
+
+// Message is one push. A push service holds it for up to TTL while the
+// browser is offline; a later message with the same Topic, at most 32
+// base64url characters, replaces it.
+type Message struct {
+	Payload []byte
+	TTL     time.Duration
+	Urgency Urgency
+	Topic   string
+}
+
+// Sender delivers messages to the subscriptions browsers made with its
+// public key
+type Sender interface {
+	PublicKey() string
+	// ValidateEndpoint checks an endpoint before a subscription is stored
+	ValidateEndpoint(endpoint string) error
+	// Send delivers the message to one subscription, returning ErrGone when
+	// the subscription should be deleted
+	Send(ctx context.Context, sub *Subscription, msg *Message) error
+}
+
+// GenerateKeys returns a new VAPID key pair
+func GenerateKeys() (publicKey, privateKey string, err error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return encode(key.PublicKey().Bytes()), encode(key.Bytes()), nil
+}
+
+// VAPIDSender sends to the push services browsers subscribe with. Unless
+// private endpoints are allowed it refuses to connect to loopback, private
+// and link-local addresses, checked on every dial.
+type VAPIDSender struct {
+	key          *ecdsa.PrivateKey
+	publicKey    string
+	subject      string
+	http         *http.Client
+	allowPrivate bool
+}
+
+// NewVAPIDSender returns a sender signing with the key pair. The subject is
+// a mailto: or https: contact push services can reach about abuse.
+func NewVAPIDSender(publicKey, privateKey, subject string, timeout time.Duration, allowPrivate bool) (*VAPIDSender, error) {
+	d, err := decode(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	key, err := ecdsa.ParseRawPrivateKey(elliptic.P256(), d)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	public, err := key.PublicKey.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	if encode(public) != trimPadding(publicKey) {
+		return nil, errors.New("VAPID public key does not match the private key")
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, c syscall.RawConn) error {
+			if err := netguard.Control(network, address, c); err != nil {
+				return ErrForbiddenEndpoint
+			}
+			return nil
+		}
+	}
+
+	return &VAPIDSender{
+		key:       key,
+		publicKey: encode(public),
+		subject:   subject,
+		http: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				Proxy:                 nil,
+				DialContext:           dialer.DialContext,
+				TLSHandshakeTimeout:   timeout,
+				ResponseHeaderTimeout: timeout,
+				MaxIdleConnsPerHost:   8,
+				IdleConnTimeout:       90 * time.Second,
+			},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		allowPrivate: allowPrivate,
+	}, nil
+}
+
+// PublicKey is the applicationServerKey browsers subscribe with
+func (s *VAPIDSender) PublicKey() string {
+	return s.publicKey
+}
+
+// ValidateEndpoint checks an endpoint before a subscription is stored.
+// Hostnames are checked again when connecting.
+func (s *VAPIDSender) ValidateEndpoint(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.User != nil {
+		return ErrForbiddenEndpoint
+	}
+	if s.allowPrivate {
+		if u.Scheme != "https" && u.Scheme != "http" {
+			return ErrForbiddenEndpoint
+		}
+		return nil
+	}
+
+	if u.Scheme != "https" || u.Hostname() == "localhost" {
+		return ErrForbiddenEndpoint
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !netguard.IsPublicIP(ip) {
+		return ErrForbiddenEndpoint
+	}
+	return nil
+}
+
+// ValidateKeys checks the subscription's keys can be encrypted for
+func ValidateKeys(sub *Subscription) error {
+	_, _, err := subscriptionKeys(sub)
+	return err
+}
+
+func (s *VAPIDSender) Send(ctx context.Context, sub *Subscription, msg *Message) error {
+	if len(msg.Payload) > MaxPayload {
+		return fmt.Errorf("push payload is %d bytes, over %d", len(msg.Payload), MaxPayload)
+	}
+	u, err := url.Parse(sub.Endpoint)
+	if err != nil {
+		return ErrForbiddenEndpoint
+	}
+
+	body, err := seal(sub, msg.Payload)
+	if err != nil {
+		return err
+	}
+	token, err := s.token(u.Scheme + "://" + u.Host)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(int(msg.TTL.Seconds())))
+	req.Header.Set("Authorization", "vapid t="+token+", k="+s.publicKey)
+	if msg.Urgency != "" {
+		req.Header.Set("Urgency", string(msg.Urgency))
+	}
+	if msg.Topic != "" {
+		req.Header.Set("Topic", msg.Topic)
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrGone
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("push service returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// token signs a VAPID JWT for the push service's origin
+func (s *VAPIDSender) token(audience string) (string, error) {
+	header := encode([]byte(`{"typ":"JWT","alg":"ES256"}`))
+	claims, err := json.Marshal(map[string]interface{}{
+		"aud": audience,
+		"exp": time.Now().Add(tokenTTL).Unix(),
+		"sub": s.subject,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signed := header + "." + encode(claims)
+	digest := sha256.Sum256([]byte(signed))
+	r, sig, err := ecdsa.Sign(rand.Reader, s.key, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	// ES256 signatures are r and s as fixed 32 byte big-endian integers
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	sig.FillBytes(signature[32:])
+	return signed + "." + encode(signature), nil
+}
+
+// seal returns the aes128gcm body carrying payload to the subscription,
+// encrypted with a new key and salt
+func seal(sub *Subscription, payload []byte) ([]byte, error) {
+	ephemeral, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return encrypt(sub, payload, ephemeral, salt)
+}
+
+func encrypt(sub *Subscription, payload []byte, ephemeral *ecdh.PrivateKey, salt []byte) ([]byte, error) {
+	uaPublic, authSecret, err := subscriptionKeys(sub)
+	if err != nil {
+		return nil, err
+	}
+	sharedSecret, err := ephemeral.ECDH(uaPublic)
+	if err != nil {
+		return nil, ErrInvalidKeys
+	}
+	asPublic := ephemeral.PublicKey().Bytes()
+
+	// The input key mixes the shared secret with the auth secret, bound to
+	// both public keys
+	prkKey, err := hkdf.Extract(sha256.New, sharedSecret, authSecret)
+	if err != nil {
+		return nil, err
+	}
+	keyInfo := "WebPush: info\x00" + string(uaPublic.Bytes()) + string(asPublic)
+	ikm, err := hkdf.Expand(sha256.New, prkKey, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// One record, ended by the last record delimiter
+	plaintext := append(append(make([]byte, 0, len(payload)+1), payload...), 0x02)
+
+	body := make([]byte, 0, 16+4+1+len(asPublic)+len(plaintext)+gcm.Overhead())
+	body = append(body, salt...)
+	body = binary.BigEndian.AppendUint32(body, recordSize)
+	body = append(body, byte(len(asPublic)))
+	body = append(body, asPublic...)
+	return gcm.Seal(body, nonce, plaintext, nil), nil
+}
+
+func subscriptionKeys(sub *Subscription) (*ecdh.PublicKey, []byte, error) {
+	p256dh, err := decode(sub.P256dh)
+	if err != nil {
+		return nil, nil, ErrInvalidKeys
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(p256dh)
+	if err != nil {
+		return nil, nil, ErrInvalidKeys
+	}
+	authSecret, err := decode(sub.Auth)
+	if err != nil || len(authSecret) != 16 {
+		return nil, nil, ErrInvalidKeys
+	}
+	return uaPublic, authSecret, nil
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(trimPadding(s))
+}
+
+func trimPadding(s string) string {
+	return strings.TrimRight(s, "=")
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/auction-cards/backend/internal/domain"
 	"github.com/google/uuid"
@@ -11,14 +12,18 @@ import (
 type UserRepository interface {
 	Create(ctx context.Context, user *domain.User) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.User, error)
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 	GetByUsername(ctx context.Context, username string) (*domain.User, error)
-	GetByVerificationToken(ctx context.Context, token string) (*domain.User, error)
-	GetByPasswordResetToken(ctx context.Context, token string) (*domain.User, error)
+	GetByVerificationTokenSelector(ctx context.Context, selector string) (*domain.User, error)
+	GetByPasswordResetTokenSelector(ctx context.Context, selector string) (*domain.User, error)
+	RecordTokenFailure(ctx context.Context, userID uuid.UUID, kind domain.TokenKind, maxAttempts int) (bool, error)
+	RecordLogin(ctx context.Context, userID uuid.UUID, ip, country string) (*string, *time.Time, error)
 	Update(ctx context.Context, user *domain.User) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context, page, limit int) ([]domain.User, int, error)
 	GetRatingSummary(ctx context.Context, userID uuid.UUID) (*domain.UserRatingSummary, error)
+	GetRatingSummaries(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]*domain.UserRatingSummary, error)
 }
 
 type OAuthAccountRepository interface {
@@ -34,20 +39,126 @@ type RefreshTokenRepository interface {
 	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
 	DeleteByTokenHash(ctx context.Context, tokenHash string) error
 	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+	DeleteByUserIDExcept(ctx context.Context, userID uuid.UUID, keepHash string) (int64, error)
 	DeleteExpired(ctx context.Context) error
 }
 
+type UserDeviceRepository interface {
+	Touch(ctx context.Context, device *domain.Device) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Device, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Device, error)
+	Update(ctx context.Context, device *domain.Device) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	SetChallenge(ctx context.Context, id uuid.UUID, digest string, expiresAt time.Time) error
+	RecordChallengeFailure(ctx context.Context, id uuid.UUID, maxAttempts int) (bool, error)
+	ClearChallenge(ctx context.Context, id uuid.UUID) error
+}
+
+type LoginEventRepository interface {
+	Create(ctx context.Context, event *domain.LoginEvent) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.LoginEvent, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]domain.LoginEvent, error)
+	MarkReported(ctx context.Context, id uuid.UUID) error
+}
+
+// AccountDeletionRepository erases a user's personal data while keeping the
+// records other users rely on. Anonymize must run inside a transaction.
+type AccountDeletionRepository interface {
+	GetObligations(ctx context.Context, userID uuid.UUID) (*domain.AccountObligations, error)
+	Anonymize(ctx context.Context, userID uuid.UUID, username string) (map[string]int64, error)
+	ListPurgeable(ctx context.Context, deletedBefore time.Time, limit int) ([]uuid.UUID, error)
+}
+
+// UserMergeRepository folds a duplicate account into a primary one. Merge
+// must run inside a transaction.
+type UserMergeRepository interface {
+	HaveInteracted(ctx context.Context, a, b uuid.UUID) (bool, error)
+	Merge(ctx context.Context, report *domain.UserMergeReport) error
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *domain.Webhook) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Webhook, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.Webhook, error)
+	CountByUser(ctx context.Context, userID uuid.UUID) (int, error)
+	ListSubscribed(ctx context.Context, event domain.WebhookEvent, userIDs []uuid.UUID) ([]domain.Webhook, error)
+	Update(ctx context.Context, webhook *domain.Webhook) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type WebhookDeliveryRepository interface {
+	Create(ctx context.Context, delivery *domain.WebhookDelivery) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error)
+	ListByWebhook(ctx context.Context, webhookID uuid.UUID, params *domain.WebhookDeliveryListParams) ([]domain.WebhookDelivery, int, error)
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error
+	Retry(ctx context.Context, id uuid.UUID) error
+	DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+// ImportJobRepository stores bulk listing imports. GetByID loads the report
+// but not the rows, which only the worker claiming the job needs.
+type ImportJobRepository interface {
+	Create(ctx context.Context, job *domain.ImportJob) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ImportJob, error)
+	ListBySeller(ctx context.Context, sellerID uuid.UUID, limit int) ([]domain.ImportJob, error)
+	ClaimNext(ctx context.Context, now, leaseUntil time.Time) (*domain.ImportJob, error)
+	SaveProgress(ctx context.Context, job *domain.ImportJob) error
+}
+
+// DataExportRepository stores personal data exports
+type DataExportRepository interface {
+	Create(ctx context.Context, export *domain.DataExport) error
+	GetLatestByUser(ctx context.Context, userID uuid.UUID) (*domain.DataExport, error)
+	ClaimNext(ctx context.Context, now, leaseUntil time.Time) (*domain.DataExport, error)
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]domain.DataExport, error)
+	Save(ctx context.Context, export *domain.DataExport) error
+}
+
+type AuctionFactsheetRepository interface {
+	GetByAuction(ctx context.Context, auctionID uuid.UUID) (*domain.AuctionFactsheet, error)
+	Request(ctx context.Context, auctionID uuid.UUID) (*domain.AuctionFactsheet, error)
+	ClaimNext(ctx context.Context, now, leaseUntil time.Time) (*domain.AuctionFactsheet, error)
+	Save(ctx context.Context, factsheet *domain.AuctionFactsheet) error
+}
+
 type AuctionRepository interface {
 	Create(ctx context.Context, auction *domain.Auction) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Auction, error)
 	GetByIDWithDetails(ctx context.Context, id uuid.UUID) (*domain.Auction, error)
+	GetByIDsWithDetails(ctx context.Context, ids []uuid.UUID) ([]domain.Auction, error)
 	Update(ctx context.Context, auction *domain.Auction) error
 	UpdateWithVersion(ctx context.Context, auction *domain.Auction, expectedVersion int) error
+	UpdateReserveWithVersion(ctx context.Context, auction *domain.Auction, expectedVersion int) error
+	UpdateBuyNowPriceWithVersion(ctx context.Context, auction *domain.Auction, expectedVersion int) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context, params *domain.AuctionListParams) ([]domain.Auction, int, error)
+	GetFacets(ctx context.Context, params *domain.AuctionListParams) (*domain.AuctionFacets, error)
+	ListSold(ctx context.Context, params *domain.SoldListingParams) ([]domain.SoldListing, int, error)
+	GetSoldStats(ctx context.Context, params *domain.SoldListingParams) (*domain.SoldPriceStats, error)
 	GetEndingAuctions(ctx context.Context, before int64) ([]domain.Auction, error)
-	IncrementViewCount(ctx context.Context, id uuid.UUID) error
+	GetEndTimeLoad(ctx context.Context, from, to time.Time) (map[int64]int, error)
+	GetActiveEndTimes(ctx context.Context) (map[uuid.UUID]time.Time, error)
+	ExtendForVacation(ctx context.Context, sellerID uuid.UUID, from, until, newEnd time.Time) (int64, error)
+	ExtendEnding(ctx context.Context, from, until, newEnd time.Time) ([]uuid.UUID, error)
+	ShiftEnding(ctx context.Context, from, until time.Time, by time.Duration) (map[uuid.UUID]time.Time, error)
+	AddViews(ctx context.Context, views map[uuid.UUID]int64) error
 	UpdateStatus(ctx context.Context, id uuid.UUID, status domain.AuctionStatus, winnerID *uuid.UUID, winningBidID *uuid.UUID) error
+	CloseIfActive(ctx context.Context, id uuid.UUID, expectedVersion int, status domain.AuctionStatus, winnerID *uuid.UUID, winningBidID *uuid.UUID) (bool, error)
+	UpdateCertification(ctx context.Context, id uuid.UUID, cert *domain.Certification) error
+	GetItems(ctx context.Context, auctionID uuid.UUID) ([]domain.AuctionItem, error)
+	ReplaceItems(ctx context.Context, auctionID uuid.UUID, items []domain.AuctionItem) error
+	// FindActiveByTitle returns active public auctions whose titles share
+	// words with title, most alike first
+	FindActiveByTitle(ctx context.Context, title string, limit int) ([]domain.Auction, error)
+}
+
+type AuctionInviteRepository interface {
+	Add(ctx context.Context, invite *domain.AuctionInvite) error
+	Remove(ctx context.Context, auctionID, userID uuid.UUID) error
+	ListByAuction(ctx context.Context, auctionID uuid.UUID) ([]domain.AuctionInvite, error)
+	IsInvited(ctx context.Context, auctionID, userID uuid.UUID) (bool, error)
+	RotateShareVersion(ctx context.Context, auctionID uuid.UUID) (int, error)
 }
 
 type AuctionImageRepository interface {
@@ -59,14 +170,24 @@ type AuctionImageRepository interface {
 	UpdatePositions(ctx context.Context, auctionID uuid.UUID, positions map[uuid.UUID]int) error
 }
 
+type AuctionShippingRepository interface {
+	GetByAuctionID(ctx context.Context, auctionID uuid.UUID) ([]domain.AuctionShipping, error)
+	GetByAuctionIDs(ctx context.Context, auctionIDs []uuid.UUID) (map[uuid.UUID][]domain.AuctionShipping, error)
+	ReplaceForAuction(ctx context.Context, auctionID uuid.UUID, options []domain.AuctionShipping) error
+}
+
 type BidRepository interface {
 	Create(ctx context.Context, bid *domain.Bid) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Bid, error)
 	GetHighestBid(ctx context.Context, auctionID uuid.UUID) (*domain.Bid, error)
-	GetByAuctionID(ctx context.Context, auctionID uuid.UUID, page, limit int) ([]domain.Bid, int, error)
+	GetByAuctionID(ctx context.Context, auctionID uuid.UUID, params *domain.BidListParams) ([]domain.Bid, int, error)
 	GetByBidderID(ctx context.Context, bidderID uuid.UUID, page, limit int) ([]domain.Bid, int, error)
 	GetBidCount(ctx context.Context, auctionID uuid.UUID) (int, error)
 	GetPreviousHighBidder(ctx context.Context, auctionID uuid.UUID, excludeBidderID uuid.UUID) (*domain.Bid, error)
+	Cancel(ctx context.Context, id, cancelledBy uuid.UUID, reason string) error
+	CancelAllForAuction(ctx context.Context, auctionID, cancelledBy uuid.UUID, reason string) ([]uuid.UUID, error)
+	GetCancelledCount(ctx context.Context, auctionID uuid.UUID) (int, error)
+	GetStandings(ctx context.Context, bidderID uuid.UUID, auctionIDs []uuid.UUID) (map[uuid.UUID]domain.BidderStanding, error)
 }
 
 type CategoryRepository interface {
@@ -77,6 +198,18 @@ type CategoryRepository interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context) ([]domain.Category, error)
 	GetWithAuctionCounts(ctx context.Context) ([]domain.Category, error)
+	ListTranslations(ctx context.Context, categoryID uuid.UUID) ([]domain.CategoryTranslation, error)
+	// GetTranslations returns the translations of the categories into any of
+	// the languages
+	GetTranslations(ctx context.Context, categoryIDs []uuid.UUID, languages []string) ([]domain.CategoryTranslation, error)
+	// SetTranslation adds or replaces the category's translation into its
+	// language
+	SetTranslation(ctx context.Context, translation *domain.CategoryTranslation) error
+	DeleteTranslation(ctx context.Context, categoryID uuid.UUID, language string) error
+	// GetListingRules returns ErrNotFound for a category without rules
+	GetListingRules(ctx context.Context, categoryID uuid.UUID) (*domain.CategoryListingRules, error)
+	SetListingRules(ctx context.Context, rules *domain.CategoryListingRules) error
+	DeleteListingRules(ctx context.Context, categoryID uuid.UUID) error
 }
 
 type WatchlistRepository interface {
@@ -84,15 +217,227 @@ type WatchlistRepository interface {
 	Remove(ctx context.Context, userID, auctionID uuid.UUID) error
 	GetByUser(ctx context.Context, userID uuid.UUID, page, limit int) ([]domain.WatchlistItem, int, error)
 	Exists(ctx context.Context, userID, auctionID uuid.UUID) (bool, error)
+	GetWatched(ctx context.Context, userID uuid.UUID, auctionIDs []uuid.UUID) (map[uuid.UUID]bool, error)
 	GetWatchersForAuction(ctx context.Context, auctionID uuid.UUID) ([]uuid.UUID, error)
 }
 
+type AuctionStatsRepository interface {
+	GetViewsByDay(ctx context.Context, auctionID uuid.UUID) ([]domain.DailyCount, error)
+	GetUniqueBidderCount(ctx context.Context, auctionID uuid.UUID) (int, error)
+	GetBidHistory(ctx context.Context, auctionID uuid.UUID) ([]domain.BidPoint, error)
+	GetShareStats(ctx context.Context, auctionID uuid.UUID) ([]domain.ListingShareStats, error)
+}
+
+type RecommendationRepository interface {
+	GetSimilarCandidates(ctx context.Context, query *domain.SimilarAuctionQuery, limit int) ([]uuid.UUID, error)
+	GetRecommendedCandidates(ctx context.Context, userID uuid.UUID, limit int) ([]uuid.UUID, error)
+}
+
+type BidApprovalRepository interface {
+	// Request creates a pending request, or replaces the answers of one
+	// still pending. It returns ErrConflict once the seller decided.
+	Request(ctx context.Context, approval *domain.BidApproval) error
+	Get(ctx context.Context, auctionID, userID uuid.UUID) (*domain.BidApproval, error)
+	ListByAuction(ctx context.Context, auctionID uuid.UUID, status *domain.BidApprovalStatus) ([]domain.BidApproval, error)
+	Decide(ctx context.Context, approval *domain.BidApproval) error
+	IsApproved(ctx context.Context, auctionID, userID uuid.UUID) (bool, error)
+}
+
+type LiveEventRepository interface {
+	Create(ctx context.Context, event *domain.LiveEvent) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.LiveEvent, error)
+	// ListUpcoming returns the events not yet ended, soonest first
+	ListUpcoming(ctx context.Context) ([]domain.LiveEvent, error)
+	Update(ctx context.Context, event *domain.LiveEvent) error
+	// AddLots makes the auctions the event's lots, in the order given
+	AddLots(ctx context.Context, eventID uuid.UUID, auctionIDs []uuid.UUID) error
+	ListLots(ctx context.Context, eventID uuid.UUID) ([]domain.LiveLot, error)
+	UpdateLot(ctx context.Context, lot *domain.LiveLot) error
+}
+
+type PriceDropRepository interface {
+	GetRule(ctx context.Context, auctionID uuid.UUID) (*domain.PriceDropRule, error)
+	SaveRule(ctx context.Context, rule *domain.PriceDropRule) error
+	DeleteRule(ctx context.Context, auctionID uuid.UUID) error
+	// ClaimDue locks a rule whose next markdown is due, for the rest of the
+	// transaction
+	ClaimDue(ctx context.Context, now time.Time) (*domain.PriceDropRule, error)
+	CreateDrop(ctx context.Context, drop *domain.PriceDrop) error
+	ListDrops(ctx context.Context, auctionID uuid.UUID) ([]domain.PriceDrop, error)
+}
+
+type APIUsageRepository interface {
+	// SaveDays writes daily usage, replacing what was saved for the same
+	// user, limit and day
+	SaveDays(ctx context.Context, days []domain.APIUsageDay) error
+	ListByUser(ctx context.Context, userID uuid.UUID, from time.Time) ([]domain.APIUsageDay, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type BidEventRepository interface {
+	Create(ctx context.Context, event *domain.BidEvent) error
+	// ListByAuction returns the auction's bid trail, oldest first
+	ListByAuction(ctx context.Context, auctionID uuid.UUID) ([]domain.BidEvent, error)
+}
+
+type MaintenanceWindowRepository interface {
+	Create(ctx context.Context, window *domain.MaintenanceWindow) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.MaintenanceWindow, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListOpen returns the windows not yet completed, soonest first
+	ListOpen(ctx context.Context) ([]domain.MaintenanceWindow, error)
+	// Overlaps reports whether an open window overlaps the given times
+	Overlaps(ctx context.Context, startsAt, endsAt time.Time) (bool, error)
+	// Complete marks the window completed at the given time. It returns
+	// ErrNotFound if the window was already completed.
+	Complete(ctx context.Context, id uuid.UUID, completedAt time.Time) error
+	// SetAuctionsExtended records how many auctions the window held back
+	SetAuctionsExtended(ctx context.Context, id uuid.UUID, count int) error
+}
+
+type StatusIncidentRepository interface {
+	Create(ctx context.Context, incident *domain.StatusIncident) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.StatusIncident, error)
+	Update(ctx context.Context, incident *domain.StatusIncident) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListRecent returns unresolved incidents and those resolved since the
+	// given time, newest first
+	ListRecent(ctx context.Context, resolvedSince time.Time) ([]domain.StatusIncident, error)
+}
+
+type FraudRepository interface {
+	// SaveFlag raises a flag, or refreshes the open flag with its check and
+	// dedupe key. It reports false when that flag was already reviewed.
+	SaveFlag(ctx context.Context, flag *domain.FraudFlag) (bool, error)
+	GetFlag(ctx context.Context, id uuid.UUID) (*domain.FraudFlag, error)
+	UpdateFlag(ctx context.Context, flag *domain.FraudFlag) error
+	// ListFlags returns flags by severity, most recently detected first
+	ListFlags(ctx context.Context, params *domain.FraudFlagListParams) ([]domain.FraudFlag, int, error)
+
+	// The checks' queries over bidding and orders since the given time
+	FindSharedIPBidding(ctx context.Context, since time.Time) ([]domain.SharedIPBidding, error)
+	FindBidRetractions(ctx context.Context, since time.Time, minCancelled int) ([]domain.BidRetractions, error)
+	FindNewAccountVelocity(ctx context.Context, since, createdAfter time.Time, minBids int) ([]domain.BidVelocity, error)
+	FindLinkedPurchases(ctx context.Context, since time.Time) ([]domain.LinkedPurchase, error)
+}
+
+type UserBlockRepository interface {
+	// Block blocks the user, keeping the original block if they already are
+	Block(ctx context.Context, block *domain.UserBlock) error
+	Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	IsBlocked(ctx context.Context, blockerID, blockedID uuid.UUID) (bool, error)
+	// List returns the users blocked by blockerID, newest first
+	List(ctx context.Context, blockerID uuid.UUID, page, limit int) ([]domain.UserBlock, int, error)
+}
+
+type SellerFollowRepository interface {
+	// Follow follows the seller, reporting false if the user already did
+	Follow(ctx context.Context, follow *domain.SellerFollow) (bool, error)
+	Unfollow(ctx context.Context, userID, sellerID uuid.UUID) error
+	// List returns the sellers userID follows, newest first
+	List(ctx context.Context, userID uuid.UUID, page, limit int) ([]domain.SellerFollow, int, error)
+}
+
+type PushSubscriptionRepository interface {
+	// Save stores the subscription, taking its endpoint over from whoever
+	// had it, and drops the user's oldest beyond their newest keep
+	Save(ctx context.Context, subscription *domain.PushSubscription, keep int) error
+	// ListByUsers returns the subscriptions of each of the users, newest
+	// first
+	ListByUsers(ctx context.Context, userIDs []uuid.UUID) ([]domain.PushSubscription, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	// DeleteByIDs removes subscriptions found to be gone or expired
+	DeleteByIDs(ctx context.Context, ids []uuid.UUID) error
+}
+
+type QuietHoursRepository interface {
+	Get(ctx context.Context, userID uuid.UUID) (*domain.QuietHours, error)
+	// Save creates the user's quiet hours or replaces them
+	Save(ctx context.Context, quietHours *domain.QuietHours) error
+	Hold(ctx context.Context, held *domain.HeldNotification) error
+	// ReleaseDue removes and returns up to limit held notifications due by
+	// the given time, oldest first
+	ReleaseDue(ctx context.Context, before time.Time, limit int) ([]domain.HeldNotification, error)
+}
+
+type DigestRepository interface {
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.DigestPreferences, error)
+	// SavePreferences creates the user's digest preferences or replaces them
+	SavePreferences(ctx context.Context, preferences *domain.DigestPreferences) error
+	// ClaimDue returns up to limit preferences whose digest is due by now,
+	// putting their next send off by lease so no other run picks them up
+	// while this one sends
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.DigestPreferences, error)
+	// MarkSent records that the user's digests cover up to sentAt, and
+	// when the next is due
+	MarkSent(ctx context.Context, userID uuid.UUID, sentAt, nextAt time.Time) error
+	// WatchedEnding returns the active auctions on the user's watchlist
+	// ending by until, soonest first
+	WatchedEnding(ctx context.Context, userID uuid.UUID, until time.Time, limit int) ([]domain.Auction, error)
+	// SellerStats sums up the seller's listings: those active now and
+	// ending by endingBy, and what happened between since and until
+	SellerStats(ctx context.Context, sellerID uuid.UUID, since, until, endingBy time.Time) (*domain.DigestSellerStats, error)
+	// DigestsWatchedEnding returns which of the users get watched auctions
+	// about to end in their digest
+	DigestsWatchedEnding(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+}
+
+type SavedSearchRepository interface {
+	Create(ctx context.Context, search *domain.SavedSearch) error
+	// ListByUser returns the user's saved searches, newest first
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.SavedSearch, error)
+	CountByUser(ctx context.Context, userID uuid.UUID) (int, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type NotificationPreferenceRepository interface {
+	// GetByUser returns the preferences the user stored; other types use
+	// the defaults
+	GetByUser(ctx context.Context, userID uuid.UUID) ([]domain.NotificationPreference, error)
+	// Save creates or replaces the user's preferences for the types given
+	Save(ctx context.Context, userID uuid.UUID, preferences []domain.NotificationPreference) error
+	// OptedOut returns which of the users switched the channel off for the
+	// type
+	OptedOut(ctx context.Context, userIDs []uuid.UUID, notificationType domain.NotificationType, channel domain.NotificationChannel) (map[uuid.UUID]bool, error)
+}
+
+type SellerBidAlertRepository interface {
+	Get(ctx context.Context, auctionID uuid.UUID) (*domain.SellerBidAlerts, error)
+	// Save creates the listing's alerts or replaces them
+	Save(ctx context.Context, alerts *domain.SellerBidAlerts) error
+	Delete(ctx context.Context, auctionID uuid.UUID) error
+}
+
+type CurationRepository interface {
+	// Feature pins an auction, or updates its position and expiry if it
+	// already is
+	Feature(ctx context.Context, featured *domain.FeaturedAuction) error
+	Unfeature(ctx context.Context, auctionID uuid.UUID) error
+	// ListFeatured returns pins by position, including expired ones
+	ListFeatured(ctx context.Context) ([]domain.FeaturedAuction, error)
+	// GetTrending ranks public active auctions by their views, watches and
+	// bids since the given time
+	GetTrending(ctx context.Context, since time.Time, limit int) ([]uuid.UUID, error)
+}
+
+type ListingShareRepository interface {
+	GetOrCreate(ctx context.Context, share *domain.ListingShare) error
+	GetByCode(ctx context.Context, code string) (*domain.ListingShare, error)
+	RecordClick(ctx context.Context, shareID uuid.UUID, userID *uuid.UUID) error
+}
+
 type NotificationRepository interface {
 	Create(ctx context.Context, notification *domain.Notification) error
 	CreateBatch(ctx context.Context, notifications []domain.Notification) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Notification, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID, params *domain.NotificationListParams) ([]domain.Notification, int, int, error)
+	// GetThreadsByUserID lists a user's notification threads by their newest
+	// notification, newest first, with the thread and unread counts
+	GetThreadsByUserID(ctx context.Context, userID uuid.UUID, params *domain.NotificationListParams) ([]domain.NotificationThread, int, int, error)
 	MarkAsRead(ctx context.Context, id uuid.UUID) error
+	// MarkThreadAsRead marks every notification in one of the user's threads
+	// read, returning ErrNotFound if the thread has none
+	MarkThreadAsRead(ctx context.Context, userID, threadID uuid.UUID) error
 	MarkAllAsRead(ctx context.Context, userID uuid.UUID) error
 	GetUnreadCount(ctx context.Context, userID uuid.UUID) (int, error)
 }
@@ -102,6 +447,7 @@ type RatingRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Rating, error)
 	GetByAuctionAndRater(ctx context.Context, auctionID, raterID uuid.UUID, ratingType domain.RatingType) (*domain.Rating, error)
 	GetByRatedUser(ctx context.Context, ratedUserID uuid.UUID, params *domain.RatingListParams) ([]domain.Rating, int, error)
+	GetByRater(ctx context.Context, raterID uuid.UUID) ([]domain.Rating, error)
 	GetUserRatingSummary(ctx context.Context, userID uuid.UUID) (*domain.UserRatingSummary, error)
 }
 
@@ -113,19 +459,134 @@ type ReportRepository interface {
 }
 
 type MessageRepository interface {
-	GetOrCreateConversation(ctx context.Context, userOne, userTwo uuid.UUID) (*domain.Conversation, error)
+	GetOrCreateConversation(ctx context.Context, userOne, userTwo uuid.UUID, mode domain.EncryptionMode) (*domain.Conversation, error)
 	GetConversationByID(ctx context.Context, id uuid.UUID) (*domain.Conversation, error)
 	GetConversationsForUser(ctx context.Context, userID uuid.UUID) ([]domain.Conversation, error)
+	GetConversationDetailsForUser(ctx context.Context, userID uuid.UUID) ([]domain.ConversationWithDetails, error)
+	GetConversationDetails(ctx context.Context, conversationID, userID uuid.UUID) (*domain.ConversationWithDetails, error)
 	CreateMessage(ctx context.Context, msg *domain.Message) error
 	GetMessagesByConversation(ctx context.Context, conversationID uuid.UUID, page, limit int) ([]domain.Message, int, error)
-	GetLastMessage(ctx context.Context, conversationID uuid.UUID) (*domain.Message, error)
+	GetMessagesWindow(ctx context.Context, conversationID uuid.UUID, params *domain.MessageWindowParams) (*domain.MessageWindow, error)
 	UpdateReadStatus(ctx context.Context, conversationID, userID uuid.UUID) error
 	GetReadStatus(ctx context.Context, conversationID, userID uuid.UUID) (*domain.ConversationReadStatus, error)
-	GetUnreadCountForConversation(ctx context.Context, conversationID, userID uuid.UUID) (int, error)
 	GetTotalUnreadCount(ctx context.Context, userID uuid.UUID) (int, error)
+	GetUnreadCounts(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]int, error)
+	ReconcileUnreadCounts(ctx context.Context) (int64, error)
+	DeleteExpiredMessages(ctx context.Context, defaultDays, limit int) (int64, error)
+	SyncLegalHolds(ctx context.Context) (int64, error)
+	RefreshLegalHold(ctx context.Context, userOne, userTwo uuid.UUID) error
+	CountFlaggedMessages(ctx context.Context, senderID uuid.UUID, since time.Time) (int, error)
+	HasSentSince(ctx context.Context, conversationID, senderID uuid.UUID, since time.Time) (bool, error)
 	IsUserInConversation(ctx context.Context, conversationID, userID uuid.UUID) (bool, error)
 }
 
+type DeviceKeyRepository interface {
+	Upsert(ctx context.Context, key *domain.DeviceKey) error
+	GetByUser(ctx context.Context, userID uuid.UUID) ([]domain.DeviceKey, error)
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+}
+
+type ModerationRepository interface {
+	Escalate(ctx context.Context, userID uuid.UUID, reason string, offenseCount int) (*domain.ModerationCase, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ModerationCase, error)
+	List(ctx context.Context, params *domain.ModerationCaseListParams) ([]domain.ModerationCase, int, error)
+	Update(ctx context.Context, c *domain.ModerationCase) error
+}
+
+type AllowedOriginRepository interface {
+	Create(ctx context.Context, origin *domain.AllowedOrigin) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.AllowedOrigin, error)
+	List(ctx context.Context) ([]domain.AllowedOrigin, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type OrderRepository interface {
+	Create(ctx context.Context, order *domain.Order) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Order, error)
+	List(ctx context.Context, params *domain.OrderListParams) ([]domain.Order, int, error)
+	GetOverdue(ctx context.Context, before time.Time, limit int) ([]domain.Order, error)
+	MarkPaid(ctx context.Context, id uuid.UUID) error
+	Cancel(ctx context.Context, id uuid.UUID, reason string) error
+	AddUnpaidStrike(ctx context.Context, userID, orderID uuid.UUID) error
+}
+
+// EscrowRepository stores payments held for orders
+type EscrowRepository interface {
+	Create(ctx context.Context, hold *domain.EscrowHold) error
+	GetByOrder(ctx context.Context, orderID uuid.UUID) (*domain.EscrowHold, error)
+	ListHeld(ctx context.Context, page, limit int) ([]domain.EscrowHold, int, error)
+	GetDue(ctx context.Context, now time.Time, limit int) ([]domain.EscrowHold, error)
+	ConfirmDelivery(ctx context.Context, orderID uuid.UUID) error
+	Release(ctx context.Context, orderID uuid.UUID) error
+	AddRefund(ctx context.Context, orderID uuid.UUID, amount decimal.Decimal) error
+}
+
+// LedgerRepository records double-entry transactions and seller balances
+type LedgerRepository interface {
+	CreateEntries(ctx context.Context, entries []domain.LedgerEntry) error
+	AdjustBalance(ctx context.Context, userID uuid.UUID, delta decimal.Decimal) error
+	LockBalance(ctx context.Context, userID uuid.UUID) (decimal.Decimal, error)
+	GetBalance(ctx context.Context, userID uuid.UUID) (*domain.Balance, error)
+}
+
+// PayoutRepository stores seller payout methods and withdrawals
+type PayoutRepository interface {
+	GetMethod(ctx context.Context, userID uuid.UUID) (*domain.PayoutMethod, error)
+	UpsertMethod(ctx context.Context, method *domain.PayoutMethod) error
+	Create(ctx context.Context, payout *domain.Payout) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Payout, error)
+	List(ctx context.Context, params *domain.PayoutListParams) ([]domain.Payout, int, error)
+	Transition(ctx context.Context, payout *domain.Payout, from domain.PayoutStatus) error
+}
+
+type SecondChanceOfferRepository interface {
+	Create(ctx context.Context, offer *domain.SecondChanceOffer) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.SecondChanceOffer, error)
+	GetByBidder(ctx context.Context, bidderID uuid.UUID, status *domain.SecondChanceOfferStatus) ([]domain.SecondChanceOffer, error)
+	ExistsForAuction(ctx context.Context, auctionID uuid.UUID) (bool, error)
+	GetExpired(ctx context.Context, before time.Time, limit int) ([]domain.SecondChanceOffer, error)
+	Respond(ctx context.Context, id uuid.UUID, status domain.SecondChanceOfferStatus) error
+}
+
+type DisputeRepository interface {
+	Create(ctx context.Context, dispute *domain.Dispute) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Dispute, error)
+	GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]domain.Dispute, error)
+	List(ctx context.Context, params *domain.DisputeListParams) ([]domain.Dispute, int, error)
+	Resolve(ctx context.Context, dispute *domain.Dispute) error
+	AddMessage(ctx context.Context, msg *domain.DisputeMessage) error
+	GetMessages(ctx context.Context, disputeID uuid.UUID) ([]domain.DisputeMessage, error)
+	AddEvidence(ctx context.Context, evidence *domain.DisputeEvidence) error
+	GetEvidence(ctx context.Context, disputeID uuid.UUID) ([]domain.DisputeEvidence, error)
+}
+
+type SnipeRepository interface {
+	Upsert(ctx context.Context, snipe *domain.Snipe) error
+	GetScheduled(ctx context.Context, auctionID, userID uuid.UUID) (*domain.Snipe, error)
+	GetByUser(ctx context.Context, userID uuid.UUID) ([]domain.Snipe, error)
+	GetDue(ctx context.Context, now time.Time) ([]domain.Snipe, error)
+	Claim(ctx context.Context, id uuid.UUID) (bool, error)
+	Complete(ctx context.Context, snipe *domain.Snipe) error
+	Cancel(ctx context.Context, id uuid.UUID) error
+}
+
+type OfferRepository interface {
+	Create(ctx context.Context, offer *domain.Offer) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Offer, error)
+	GetByAuction(ctx context.Context, auctionID uuid.UUID, buyerID *uuid.UUID) ([]domain.Offer, error)
+	GetByBuyer(ctx context.Context, buyerID uuid.UUID, status *domain.OfferStatus) ([]domain.Offer, error)
+	CountByBuyer(ctx context.Context, auctionID, buyerID uuid.UUID) (int, error)
+	Counter(ctx context.Context, offer *domain.Offer) error
+	Respond(ctx context.Context, offer *domain.Offer, from domain.OfferStatus) error
+	ExpireOpen(ctx context.Context, auctionID uuid.UUID) ([]domain.Offer, error)
+	ExpireStale(ctx context.Context, now time.Time, limit int) ([]domain.Offer, error)
+}
+
+type AuditLogRepository interface {
+	Create(ctx context.Context, entry *domain.AuditLog) error
+	List(ctx context.Context, params *domain.AuditLogListParams) ([]domain.AuditLog, int, error)
+}
+
 // Transaction support
 type TxManager interface {
 	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
@@ -138,9 +599,7 @@ type PlaceBidResult struct {
 	AuctionExtended bool
 	NewEndTime      *int64
 	PreviousBidder  *uuid.UUID
-}
-
-// BidTransaction handles atomic bid placement
-type BidTransaction interface {
-	PlaceBid(ctx context.Context, auctionID, bidderID uuid.UUID, amount decimal.Decimal, maxAutoBid *decimal.Decimal) (*PlaceBidResult, error)
+	PreviousPrice   decimal.Decimal
+	BuyNowRemoved   bool
+	ReserveMet      bool // this bid met the reserve for the first time
 }
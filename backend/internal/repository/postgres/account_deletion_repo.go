@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+// deletionStep is one statement of an account deletion, run with $1 as the
+// account. Its affected rows are reported under key; steps without a key
+// are not reported.
+type deletionStep struct {
+	key   string
+	query string
+}
+
+// deletionSteps erase what is only the user's own and close what was still
+// pending. Bids, ratings, orders, disputes and messages stay for the other
+// users involved, attached to the account that anonymizeUser scrubs last.
+var deletionSteps = []deletionStep{
+	{key: "draft_auctions", query: `DELETE FROM auctions WHERE seller_id = $1 AND status = 'draft'`},
+	{key: "rating_comments", query: `UPDATE ratings SET comment = NULL WHERE rater_id = $1 AND comment IS NOT NULL`},
+
+	{key: "offers", query: `
+		UPDATE offers SET status = 'expired', responded_at = NOW()
+		WHERE buyer_id = $1 AND status IN ('pending', 'countered')`},
+	{key: "snipes", query: `UPDATE snipes SET status = 'cancelled' WHERE user_id = $1 AND status = 'scheduled'`},
+	{key: "second_chance_offers", query: `
+		UPDATE second_chance_offers SET status = 'declined'
+		WHERE bidder_id = $1 AND status = 'pending'`},
+
+	// Deleting fires the watch_count trigger, so counts stay right
+	{key: "watchlist", query: `DELETE FROM watchlist WHERE user_id = $1`},
+	{key: "notifications", query: `DELETE FROM notifications WHERE user_id = $1`},
+	{key: "import_jobs", query: `DELETE FROM import_jobs WHERE seller_id = $1`},
+	{key: "webhooks", query: `DELETE FROM webhooks WHERE user_id = $1`},
+	{key: "device_keys", query: `DELETE FROM device_keys WHERE user_id = $1`},
+	{key: "oauth_accounts", query: `DELETE FROM oauth_accounts WHERE user_id = $1`},
+	{key: "payout_methods", query: `DELETE FROM payout_methods WHERE user_id = $1`},
+	{key: "login_events", query: `DELETE FROM login_events WHERE user_id = $1`},
+	{key: "bid_event_clients", query: `
+		UPDATE bid_events SET ip = NULL, user_agent = NULL
+		WHERE actor_id = $1 AND (ip IS NOT NULL OR user_agent IS NOT NULL)`},
+	{query: `DELETE FROM refresh_tokens WHERE user_id = $1`},
+	{key: "devices", query: `DELETE FROM user_devices WHERE user_id = $1`},
+
+	// Finished archives are removed from storage when their link expires,
+	// which is now; unfinished ones are never built
+	{key: "data_exports", query: `
+		UPDATE data_exports
+		SET status = CASE WHEN status = 'completed' THEN status ELSE 'failed' END,
+		    expires_at = CASE WHEN status = 'completed' THEN NOW() ELSE expires_at END
+		WHERE user_id = $1 AND status IN ('pending', 'processing', 'completed')`},
+}
+
+// anonymizeUser leaves the account as a deleted placeholder named $2 that
+// nobody can sign in to, marking it purged
+const anonymizeUser = `
+	UPDATE users SET
+		is_deleted = TRUE,
+		deleted_at = COALESCE(deleted_at, NOW()),
+		purged_at = NOW(),
+		email = 'deleted+' || id || '@deleted.invalid',
+		username = $2,
+		password_hash = NULL,
+		avatar_url = NULL,
+		bio = NULL,
+		phone = NULL,
+		address = NULL,
+		email_verified = FALSE,
+		email_verification_token = NULL,
+		password_reset_token = NULL,
+		password_reset_expires = NULL,
+		last_login_ip = NULL,
+		last_login_country = NULL,
+		last_login_at = NULL,
+		two_factor_enabled = FALSE,
+		username_setup_required = FALSE,
+		vacation_mode = FALSE,
+		vacation_starts_at = NULL,
+		vacation_ends_at = NULL,
+		vacation_auto_reply = NULL
+	WHERE id = $1`
+
+type AccountDeletionRepository struct {
+	db *DB
+}
+
+func NewAccountDeletionRepository(db *DB) *AccountDeletionRepository {
+	return &AccountDeletionRepository{db: db}
+}
+
+// GetObligations counts the user's running listings, auctions they are
+// winning, orders awaiting payment or held in escrow and open disputes, on
+// either side, and payouts not yet sent
+func (r *AccountDeletionRepository) GetObligations(ctx context.Context, userID uuid.UUID) (*domain.AccountObligations, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM auctions WHERE seller_id = $1 AND status = 'active'),
+			(SELECT COUNT(*) FROM auctions a
+				WHERE a.status = 'active'
+					AND (
+						SELECT b.bidder_id FROM bids b
+						WHERE b.auction_id = a.id AND b.cancelled_at IS NULL
+						ORDER BY b.amount DESC, b.created_at ASC
+						LIMIT 1
+					) = $1),
+			(SELECT COUNT(*) FROM orders o
+				WHERE (o.buyer_id = $1 OR o.seller_id = $1)
+					AND (o.status = 'pending_payment' OR EXISTS (
+						SELECT 1 FROM escrow_holds e WHERE e.order_id = o.id AND e.status = 'held'
+					))),
+			(SELECT COUNT(*) FROM disputes
+				WHERE (buyer_id = $1 OR seller_id = $1) AND status = 'open'),
+			(SELECT COUNT(*) FROM payouts WHERE user_id = $1 AND status IN ('requested', 'approved'))`
+
+	q := r.db.GetQuerier(ctx)
+	o := &domain.AccountObligations{}
+	err := q.QueryRow(ctx, query, userID).Scan(&o.ActiveListings, &o.LeadingBids, &o.OpenOrders, &o.OpenDisputes, &o.OpenPayouts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count account obligations: %w", err)
+	}
+
+	return o, nil
+}
+
+// Anonymize erases the user's personal data and renames the account to
+// username, returning how many rows each step touched. It must run in a
+// transaction.
+func (r *AccountDeletionRepository) Anonymize(ctx context.Context, userID uuid.UUID, username string) (map[string]int64, error) {
+	q := r.db.GetQuerier(ctx)
+
+	// Lock the account so nothing is written to it mid-deletion
+	var locked int
+	err := q.QueryRow(ctx, `SELECT COUNT(*) FROM (SELECT id FROM users WHERE id = $1 FOR UPDATE) u`, userID).Scan(&locked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock account: %w", err)
+	}
+	if locked != 1 {
+		return nil, domain.ErrNotFound
+	}
+
+	counts := make(map[string]int64)
+	for _, step := range deletionSteps {
+		result, err := q.Exec(ctx, step.query, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete %s: %w", step.key, err)
+		}
+		if step.key != "" {
+			counts[step.key] += result.RowsAffected()
+		}
+	}
+
+	if _, err := q.Exec(ctx, anonymizeUser, userID, username); err != nil {
+		return nil, fmt.Errorf("failed to anonymize user: %w", err)
+	}
+
+	return counts, nil
+}
+
+// ListPurgeable returns accounts deleted before deletedBefore whose personal
+// data hasn't been purged yet, longest deleted first
+func (r *AccountDeletionRepository) ListPurgeable(ctx context.Context, deletedBefore time.Time, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT id FROM users
+		WHERE is_deleted AND purged_at IS NULL AND deleted_at <= $1
+		ORDER BY deleted_at
+		LIMIT $2`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, deletedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list purgeable users: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan user id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+type APIUsageRepository struct {
+	db *DB
+}
+
+func NewAPIUsageRepository(db *DB) *APIUsageRepository {
+	return &APIUsageRepository{db: db}
+}
+
+// SaveDays writes daily usage, replacing whatever was saved for the same
+// user, limit and day. Usage of users deleted since is dropped.
+func (r *APIUsageRepository) SaveDays(ctx context.Context, days []domain.APIUsageDay) error {
+	if len(days) == 0 {
+		return nil
+	}
+
+	userIDs := make([]uuid.UUID, len(days))
+	limits := make([]string, len(days))
+	dates := make([]time.Time, len(days))
+	requests := make([]int64, len(days))
+	rateLimited := make([]int64, len(days))
+	for i, day := range days {
+		userIDs[i] = day.UserID
+		limits[i] = day.Limit
+		dates[i] = day.Day
+		requests[i] = day.Requests
+		rateLimited[i] = day.RateLimited
+	}
+
+	query := `
+		INSERT INTO api_usage_daily (user_id, limit_name, day, requests, rate_limited)
+		SELECT v.user_id, v.limit_name, v.day, v.requests, v.rate_limited
+		FROM unnest($1::uuid[], $2::text[], $3::date[], $4::bigint[], $5::bigint[])
+			AS v(user_id, limit_name, day, requests, rate_limited)
+		JOIN users u ON u.id = v.user_id
+		ON CONFLICT (user_id, limit_name, day) DO UPDATE SET
+			requests = EXCLUDED.requests,
+			rate_limited = EXCLUDED.rate_limited`
+
+	q := r.db.GetQuerier(ctx)
+	_, err := q.Exec(ctx, query, userIDs, limits, dates, requests, rateLimited)
+	if err != nil {
+		return fmt.Errorf("failed to save api usage: %w", err)
+	}
+
+	return nil
+}
+
+// ListByUser returns a user's daily usage from a day on, oldest first
+func (r *APIUsageRepository) ListByUser(ctx context.Context, userID uuid.UUID, from time.Time) ([]domain.APIUsageDay, error) {
+	query := `
+		SELECT user_id, limit_name, day, requests, rate_limited
+		FROM api_usage_daily
+		WHERE user_id = $1 AND day >= $2::date
+		ORDER BY day, limit_name`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, userID, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api usage: %w", err)
+	}
+	defer rows.Close()
+
+	days := make([]domain.APIUsageDay, 0)
+	for rows.Next() {
+		var day domain.APIUsageDay
+		if err := rows.Scan(&day.UserID, &day.Limit, &day.Day, &day.Requests, &day.RateLimited); err != nil {
+			return nil, fmt.Errorf("failed to scan api usage: %w", err)
+		}
+		days = append(days, day)
+	}
+
+	return days, rows.Err()
+}
+
+// DeleteBefore drops daily usage older than a day
+func (r *APIUsageRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, `DELETE FROM api_usage_daily WHERE day < $1::date`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete api usage: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type AuctionInviteRepository struct {
+	db *DB
+}
+
+func NewAuctionInviteRepository(db *DB) *AuctionInviteRepository {
+	return &AuctionInviteRepository{db: db}
+}
+
+// Add invites a user, keeping the original invite if they already have one
+func (r *AuctionInviteRepository) Add(ctx context.Context, invite *domain.AuctionInvite) error {
+	query := `
+		INSERT INTO auction_invites (auction_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (auction_id, user_id) DO UPDATE SET auction_id = EXCLUDED.auction_id
+		RETURNING created_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query, invite.AuctionID, invite.UserID).Scan(&invite.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add invite: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuctionInviteRepository) Remove(ctx context.Context, auctionID, userID uuid.UUID) error {
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, `DELETE FROM auction_invites WHERE auction_id = $1 AND user_id = $2`, auctionID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove invite: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *AuctionInviteRepository) ListByAuction(ctx context.Context, auctionID uuid.UUID) ([]domain.AuctionInvite, error) {
+	query := `
+		SELECT i.auction_id, i.user_id, u.username, i.created_at
+		FROM auction_invites i
+		JOIN users u ON u.id = i.user_id
+		WHERE i.auction_id = $1
+		ORDER BY i.created_at`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invites: %w", err)
+	}
+	defer rows.Close()
+
+	invites := make([]domain.AuctionInvite, 0)
+	for rows.Next() {
+		var invite domain.AuctionInvite
+		if err := rows.Scan(&invite.AuctionID, &invite.UserID, &invite.Username, &invite.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan invite: %w", err)
+		}
+		invites = append(invites, invite)
+	}
+
+	return invites, rows.Err()
+}
+
+func (r *AuctionInviteRepository) IsInvited(ctx context.Context, auctionID, userID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM auction_invites WHERE auction_id = $1 AND user_id = $2)`
+
+	q := r.db.GetQuerier(ctx)
+	var invited bool
+	if err := q.QueryRow(ctx, query, auctionID, userID).Scan(&invited); err != nil {
+		return false, fmt.Errorf("failed to check invite: %w", err)
+	}
+
+	return invited, nil
+}
+
+// RotateShareVersion bumps the auction's share version, invalidating every
+// share link signed for an earlier one, and returns the new version
+func (r *AuctionInviteRepository) RotateShareVersion(ctx context.Context, auctionID uuid.UUID) (int, error) {
+	query := `UPDATE auctions SET share_version = share_version + 1 WHERE id = $1 RETURNING share_version`
+
+	q := r.db.GetQuerier(ctx)
+	var version int
+	err := q.QueryRow(ctx, query, auctionID).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, domain.ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to rotate share version: %w", err)
+	}
+
+	return version, nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/auction-cards/backend/internal/domain"
 	"github.com/google/uuid"
@@ -12,6 +13,92 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+// auctionColumns is the column list matched by scanAuction
+const auctionColumns = `id, seller_id, category_id, title, description, condition, starting_price,
+	reserve_price, buy_now_price, buy_now_rule, accepts_offers, current_price, bid_increment, start_time, end_time,
+	status, winner_id, winning_bid_id, views_count, watch_count, bid_count, auto_relist, relisted_from_id,
+	visibility, share_version, version, created_at, updated_at,
+	cert_grader, cert_number, cert_status, cert_grade, cert_subject, cert_year, cert_checked_at,
+	requires_bid_approval, bid_approval_questions, live_event_id, currency, bidder_requirements`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAuction(row rowScanner, auction *domain.Auction) error {
+	var cert domain.Certification
+	var certGrader *domain.Grader
+	var certNumber *string
+	var certStatus *domain.CertificationStatus
+
+	err := row.Scan(
+		&auction.ID,
+		&auction.SellerID,
+		&auction.CategoryID,
+		&auction.Title,
+		&auction.Description,
+		&auction.Condition,
+		&auction.StartingPrice,
+		&auction.ReservePrice,
+		&auction.BuyNowPrice,
+		&auction.BuyNowRule,
+		&auction.AcceptsOffers,
+		&auction.CurrentPrice,
+		&auction.BidIncrement,
+		&auction.StartTime,
+		&auction.EndTime,
+		&auction.Status,
+		&auction.WinnerID,
+		&auction.WinningBidID,
+		&auction.ViewsCount,
+		&auction.WatchCount,
+		&auction.BidCount,
+		&auction.AutoRelist,
+		&auction.RelistedFromID,
+		&auction.Visibility,
+		&auction.ShareVersion,
+		&auction.Version,
+		&auction.CreatedAt,
+		&auction.UpdatedAt,
+		&certGrader,
+		&certNumber,
+		&certStatus,
+		&cert.Grade,
+		&cert.Subject,
+		&cert.Year,
+		&cert.CheckedAt,
+		&auction.RequiresBidApproval,
+		&auction.BidApprovalQuestions,
+		&auction.LiveEventID,
+		&auction.Currency,
+		&auction.BidderRequirements,
+	)
+	if err != nil {
+		return err
+	}
+
+	if certGrader != nil && certNumber != nil {
+		cert.Grader = *certGrader
+		cert.Number = *certNumber
+		cert.Status = domain.CertificationPending
+		if certStatus != nil {
+			cert.Status = *certStatus
+		}
+		auction.Certification = &cert
+	}
+
+	return nil
+}
+
+// certificationArgs returns the values of the cert_* columns in the order of
+// auctionColumns, all NULL without a certification
+func certificationArgs(cert *domain.Certification) []interface{} {
+	if cert == nil {
+		return make([]interface{}, 7)
+	}
+	return []interface{}{cert.Grader, cert.Number, cert.Status, cert.Grade, cert.Subject, cert.Year, cert.CheckedAt}
+}
+
 type AuctionRepository struct {
 	db *DB
 }
@@ -23,17 +110,25 @@ func NewAuctionRepository(db *DB) *AuctionRepository {
 func (r *AuctionRepository) Create(ctx context.Context, auction *domain.Auction) error {
 	query := `
 		INSERT INTO auctions (id, seller_id, category_id, title, description, condition, starting_price,
-		                      reserve_price, buy_now_price, current_price, bid_increment, start_time,
-		                      end_time, status)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		                      reserve_price, buy_now_price, buy_now_rule, current_price, bid_increment,
+		                      start_time, end_time, status, auto_relist, relisted_from_id, accepts_offers,
+		                      cert_grader, cert_number, cert_status, cert_grade, cert_subject, cert_year, cert_checked_at,
+		                      visibility, requires_bid_approval, bid_approval_questions, currency, bidder_requirements)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18,
+		        $19, $20, $21, $22, $23, $24, $25, $26, $27, COALESCE($28::text[], '{}'), $29, $30)
 		RETURNING created_at, updated_at, version`
 
 	if auction.ID == uuid.Nil {
 		auction.ID = uuid.New()
 	}
+	if auction.Visibility == "" {
+		auction.Visibility = domain.AuctionPublic
+	}
+	if auction.Currency == "" {
+		auction.Currency = domain.DefaultCurrency
+	}
 
-	q := r.db.GetQuerier(ctx)
-	err := q.QueryRow(ctx, query,
+	args := []interface{}{
 		auction.ID,
 		auction.SellerID,
 		auction.CategoryID,
@@ -43,12 +138,21 @@ func (r *AuctionRepository) Create(ctx context.Context, auction *domain.Auction)
 		auction.StartingPrice,
 		auction.ReservePrice,
 		auction.BuyNowPrice,
+		auction.BuyNowRule,
 		auction.CurrentPrice,
 		auction.BidIncrement,
 		auction.StartTime,
 		auction.EndTime,
 		auction.Status,
-	).Scan(&auction.CreatedAt, &auction.UpdatedAt, &auction.Version)
+		auction.AutoRelist,
+		auction.RelistedFromID,
+		auction.AcceptsOffers,
+	}
+	args = append(args, certificationArgs(auction.Certification)...)
+	args = append(args, auction.Visibility, auction.RequiresBidApproval, auction.BidApprovalQuestions, auction.Currency, auction.BidderRequirements)
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query, args...).Scan(&auction.CreatedAt, &auction.UpdatedAt, &auction.Version)
 
 	if err != nil {
 		return fmt.Errorf("failed to create auction: %w", err)
@@ -58,38 +162,11 @@ func (r *AuctionRepository) Create(ctx context.Context, auction *domain.Auction)
 }
 
 func (r *AuctionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Auction, error) {
-	query := `
-		SELECT id, seller_id, category_id, title, description, condition, starting_price,
-		       reserve_price, buy_now_price, current_price, bid_increment, start_time, end_time,
-		       status, winner_id, winning_bid_id, views_count, bid_count, version, created_at, updated_at
-		FROM auctions
-		WHERE id = $1`
+	query := `SELECT ` + auctionColumns + ` FROM auctions WHERE id = $1`
 
 	q := r.db.GetQuerier(ctx)
 	auction := &domain.Auction{}
-	err := q.QueryRow(ctx, query, id).Scan(
-		&auction.ID,
-		&auction.SellerID,
-		&auction.CategoryID,
-		&auction.Title,
-		&auction.Description,
-		&auction.Condition,
-		&auction.StartingPrice,
-		&auction.ReservePrice,
-		&auction.BuyNowPrice,
-		&auction.CurrentPrice,
-		&auction.BidIncrement,
-		&auction.StartTime,
-		&auction.EndTime,
-		&auction.Status,
-		&auction.WinnerID,
-		&auction.WinningBidID,
-		&auction.ViewsCount,
-		&auction.BidCount,
-		&auction.Version,
-		&auction.CreatedAt,
-		&auction.UpdatedAt,
-	)
+	err := scanAuction(q.QueryRow(ctx, query, id), auction)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, domain.ErrNotFound
@@ -111,12 +188,18 @@ func (r *AuctionRepository) GetByIDWithDetails(ctx context.Context, id uuid.UUID
 	q := r.db.GetQuerier(ctx)
 
 	// Get seller
-	sellerQuery := `SELECT id, username, avatar_url, bio, created_at FROM users WHERE id = $1`
+	sellerQuery := `SELECT id, username, avatar_url, bio, created_at,
+		vacation_mode, vacation_starts_at, vacation_ends_at, vacation_block_bids, is_deleted
+		FROM users WHERE id = $1`
 	seller := &domain.PublicUser{}
+	var vacation domain.VacationSettings
 	err = q.QueryRow(ctx, sellerQuery, auction.SellerID).Scan(
 		&seller.ID, &seller.Username, &seller.AvatarURL, &seller.Bio, &seller.CreatedAt,
+		&vacation.Enabled, &vacation.StartsAt, &vacation.EndsAt, &vacation.BlockBids, &seller.Deleted,
 	)
 	if err == nil {
+		seller.Vacation = vacation.Notice(time.Now())
+		seller.HideIfDeleted()
 		auction.Seller = seller
 	}
 
@@ -134,25 +217,38 @@ func (r *AuctionRepository) GetByIDWithDetails(ctx context.Context, id uuid.UUID
 	}
 
 	// Get images
-	imagesQuery := `SELECT id, auction_id, url, position, created_at FROM auction_images WHERE auction_id = $1 ORDER BY position`
+	imagesQuery := `
+		SELECT id, auction_id, url, thumbnail_url, medium_url, large_url, position, created_at
+		FROM auction_images WHERE auction_id = $1 ORDER BY position`
 	rows, err := q.Query(ctx, imagesQuery, id)
 	if err == nil {
 		defer rows.Close()
 		for rows.Next() {
 			var img domain.AuctionImage
-			if err := rows.Scan(&img.ID, &img.AuctionID, &img.URL, &img.Position, &img.CreatedAt); err == nil {
+			if err := rows.Scan(
+				&img.ID, &img.AuctionID, &img.URL, &img.ThumbnailURL, &img.MediumURL, &img.LargeURL,
+				&img.Position, &img.CreatedAt,
+			); err == nil {
 				auction.Images = append(auction.Images, img)
 			}
 		}
 	}
 
+	// Get lot items
+	if items, err := r.GetItems(ctx, id); err == nil && len(items) > 0 {
+		auction.Items = items
+	}
+
 	// Get winner if exists
 	if auction.WinnerID != nil {
 		winner := &domain.PublicUser{}
+		var winnerVacation domain.VacationSettings
 		err = q.QueryRow(ctx, sellerQuery, *auction.WinnerID).Scan(
 			&winner.ID, &winner.Username, &winner.AvatarURL, &winner.Bio, &winner.CreatedAt,
+			&winnerVacation.Enabled, &winnerVacation.StartsAt, &winnerVacation.EndsAt, &winnerVacation.BlockBids, &winner.Deleted,
 		)
 		if err == nil {
+			winner.HideIfDeleted()
 			auction.Winner = winner
 		}
 	}
@@ -160,18 +256,159 @@ func (r *AuctionRepository) GetByIDWithDetails(ctx context.Context, id uuid.UUID
 	return auction, nil
 }
 
+// GetByIDsWithDetails loads the auctions with the given IDs, with the same
+// details as GetByIDWithDetails, in one query per kind of detail. IDs that
+// don't exist are skipped; the order is unspecified.
+func (r *AuctionRepository) GetByIDsWithDetails(ctx context.Context, ids []uuid.UUID) ([]domain.Auction, error) {
+	q := r.db.GetQuerier(ctx)
+
+	rows, err := q.Query(ctx, `SELECT `+auctionColumns+` FROM auctions WHERE id = ANY($1)`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auctions: %w", err)
+	}
+	defer rows.Close()
+
+	auctions := make([]domain.Auction, 0, len(ids))
+	for rows.Next() {
+		var auction domain.Auction
+		if err := scanAuction(rows, &auction); err != nil {
+			return nil, fmt.Errorf("failed to scan auction: %w", err)
+		}
+		auctions = append(auctions, auction)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get auctions: %w", err)
+	}
+	if len(auctions) == 0 {
+		return auctions, nil
+	}
+
+	auctionIDs := make([]uuid.UUID, 0, len(auctions))
+	var userIDs, categoryIDs []uuid.UUID
+	for _, auction := range auctions {
+		auctionIDs = append(auctionIDs, auction.ID)
+		userIDs = append(userIDs, auction.SellerID)
+		if auction.WinnerID != nil {
+			userIDs = append(userIDs, *auction.WinnerID)
+		}
+		if auction.CategoryID != nil {
+			categoryIDs = append(categoryIDs, *auction.CategoryID)
+		}
+	}
+
+	// Sellers and winners
+	users := make(map[uuid.UUID]*domain.PublicUser)
+	userRows, err := q.Query(ctx, `SELECT id, username, avatar_url, bio, created_at,
+		vacation_mode, vacation_starts_at, vacation_ends_at, vacation_block_bids, is_deleted
+		FROM users WHERE id = ANY($1)`, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auction users: %w", err)
+	}
+	defer userRows.Close()
+	now := time.Now()
+	for userRows.Next() {
+		user := &domain.PublicUser{}
+		var vacation domain.VacationSettings
+		if err := userRows.Scan(
+			&user.ID, &user.Username, &user.AvatarURL, &user.Bio, &user.CreatedAt,
+			&vacation.Enabled, &vacation.StartsAt, &vacation.EndsAt, &vacation.BlockBids, &user.Deleted,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan auction user: %w", err)
+		}
+		user.Vacation = vacation.Notice(now)
+		user.HideIfDeleted()
+		users[user.ID] = user
+	}
+
+	categories := make(map[uuid.UUID]*domain.Category)
+	if len(categoryIDs) > 0 {
+		categoryRows, err := q.Query(ctx, `SELECT id, name, slug, parent_id, description, image_url, created_at
+			FROM categories WHERE id = ANY($1)`, categoryIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get auction categories: %w", err)
+		}
+		defer categoryRows.Close()
+		for categoryRows.Next() {
+			category := &domain.Category{}
+			if err := categoryRows.Scan(
+				&category.ID, &category.Name, &category.Slug, &category.ParentID,
+				&category.Description, &category.ImageURL, &category.CreatedAt,
+			); err != nil {
+				return nil, fmt.Errorf("failed to scan auction category: %w", err)
+			}
+			categories[category.ID] = category
+		}
+	}
+
+	images := make(map[uuid.UUID][]domain.AuctionImage)
+	imageRows, err := q.Query(ctx, `
+		SELECT id, auction_id, url, thumbnail_url, medium_url, large_url, position, created_at
+		FROM auction_images WHERE auction_id = ANY($1) ORDER BY auction_id, position`, auctionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auction images: %w", err)
+	}
+	defer imageRows.Close()
+	for imageRows.Next() {
+		var img domain.AuctionImage
+		if err := imageRows.Scan(
+			&img.ID, &img.AuctionID, &img.URL, &img.ThumbnailURL, &img.MediumURL, &img.LargeURL,
+			&img.Position, &img.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan auction image: %w", err)
+		}
+		images[img.AuctionID] = append(images[img.AuctionID], img)
+	}
+
+	items, err := r.getItems(ctx, `WHERE i.auction_id = ANY($1)`, auctionIDs)
+	if err != nil {
+		return nil, err
+	}
+	itemsByAuction := make(map[uuid.UUID][]domain.AuctionItem)
+	for _, item := range items {
+		itemsByAuction[item.AuctionID] = append(itemsByAuction[item.AuctionID], item)
+	}
+
+	for i := range auctions {
+		auction := &auctions[i]
+		auction.Seller = users[auction.SellerID]
+		if auction.WinnerID != nil {
+			auction.Winner = users[*auction.WinnerID]
+		}
+		if auction.CategoryID != nil {
+			auction.Category = categories[*auction.CategoryID]
+		}
+		auction.Images = images[auction.ID]
+		auction.Items = itemsByAuction[auction.ID]
+	}
+
+	return auctions, nil
+}
+
+// Update saves the auction. Verification results are written separately by
+// UpdateCertification and are only replaced here when the certification
+// itself changes, so a save racing a lookup doesn't undo it.
 func (r *AuctionRepository) Update(ctx context.Context, auction *domain.Auction) error {
+	const sameCert = `cert_grader IS NOT DISTINCT FROM $20 AND cert_number IS NOT DISTINCT FROM $21`
+
 	query := `
 		UPDATE auctions
 		SET category_id = $2, title = $3, description = $4, condition = $5, starting_price = $6,
 		    reserve_price = $7, buy_now_price = $8, current_price = $9, bid_increment = $10,
 		    start_time = $11, end_time = $12, status = $13, winner_id = $14, winning_bid_id = $15,
-		    bid_count = $16, version = version + 1
+		    bid_count = $16, auto_relist = $17, buy_now_rule = $18, accepts_offers = $19,
+		    cert_grader = $20, cert_number = $21,
+		    cert_status = CASE WHEN ` + sameCert + ` THEN cert_status ELSE $22 END,
+		    cert_grade = CASE WHEN ` + sameCert + ` THEN cert_grade ELSE $23 END,
+		    cert_subject = CASE WHEN ` + sameCert + ` THEN cert_subject ELSE $24 END,
+		    cert_year = CASE WHEN ` + sameCert + ` THEN cert_year ELSE $25 END,
+		    cert_checked_at = CASE WHEN ` + sameCert + ` THEN cert_checked_at ELSE $26 END,
+		    visibility = $27, requires_bid_approval = $28, bid_approval_questions = COALESCE($29::text[], '{}'),
+		    currency = $30, bidder_requirements = $31,
+		    version = version + 1
 		WHERE id = $1
 		RETURNING updated_at, version`
 
-	q := r.db.GetQuerier(ctx)
-	err := q.QueryRow(ctx, query,
+	args := []interface{}{
 		auction.ID,
 		auction.CategoryID,
 		auction.Title,
@@ -188,7 +425,15 @@ func (r *AuctionRepository) Update(ctx context.Context, auction *domain.Auction)
 		auction.WinnerID,
 		auction.WinningBidID,
 		auction.BidCount,
-	).Scan(&auction.UpdatedAt, &auction.Version)
+		auction.AutoRelist,
+		auction.BuyNowRule,
+		auction.AcceptsOffers,
+	}
+	args = append(args, certificationArgs(auction.Certification)...)
+	args = append(args, auction.Visibility, auction.RequiresBidApproval, auction.BidApprovalQuestions, auction.Currency, auction.BidderRequirements)
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query, args...).Scan(&auction.UpdatedAt, &auction.Version)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		return domain.ErrNotFound
@@ -226,6 +471,54 @@ func (r *AuctionRepository) UpdateWithVersion(ctx context.Context, auction *doma
 	return nil
 }
 
+func (r *AuctionRepository) UpdateReserveWithVersion(ctx context.Context, auction *domain.Auction, expectedVersion int) error {
+	query := `
+		UPDATE auctions
+		SET reserve_price = $2, version = version + 1
+		WHERE id = $1 AND version = $3
+		RETURNING updated_at, version`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		auction.ID,
+		auction.ReservePrice,
+		expectedVersion,
+	).Scan(&auction.UpdatedAt, &auction.Version)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrConcurrentBid
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update reserve price: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuctionRepository) UpdateBuyNowPriceWithVersion(ctx context.Context, auction *domain.Auction, expectedVersion int) error {
+	query := `
+		UPDATE auctions
+		SET buy_now_price = $2, version = version + 1
+		WHERE id = $1 AND version = $3
+		RETURNING updated_at, version`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		auction.ID,
+		auction.BuyNowPrice,
+		expectedVersion,
+	).Scan(&auction.UpdatedAt, &auction.Version)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrConcurrentBid
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update buy now price: %w", err)
+	}
+
+	return nil
+}
+
 func (r *AuctionRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM auctions WHERE id = $1`
 
@@ -242,8 +535,25 @@ func (r *AuctionRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-func (r *AuctionRepository) List(ctx context.Context, params *domain.AuctionListParams) ([]domain.Auction, int, error) {
-	baseQuery := `FROM auctions a`
+// buyNowAvailableCondition mirrors Auction.CanBuyNow
+const buyNowAvailableCondition = `(a.buy_now_price IS NOT NULL AND (
+	a.buy_now_rule = 'always' OR a.bid_count = 0 OR
+	(a.buy_now_rule = 'until_reserve_met' AND a.reserve_price IS NOT NULL AND a.current_price < a.reserve_price)
+))`
+
+// searchCondition matches the search text in the given argument against an
+// auction's title and description and the titles of its lot items
+func searchCondition(arg int) string {
+	return fmt.Sprintf(`(to_tsvector('english', a.title || ' ' || COALESCE(a.description, '')) @@ plainto_tsquery('english', $%[1]d)
+		OR EXISTS (
+			SELECT 1 FROM auction_items it
+			WHERE it.auction_id = a.id AND to_tsvector('english', it.title) @@ plainto_tsquery('english', $%[1]d)
+		))`, arg)
+}
+
+// auctionListWhere builds the WHERE clause for the list filters, returning
+// it with its arguments
+func auctionListWhere(params *domain.AuctionListParams) (string, []interface{}) {
 	whereConditions := []string{}
 	args := []interface{}{}
 	argIndex := 1
@@ -267,55 +577,355 @@ func (r *AuctionRepository) List(ctx context.Context, params *domain.AuctionList
 	}
 
 	if params.Search != nil && *params.Search != "" {
-		whereConditions = append(whereConditions, fmt.Sprintf("to_tsvector('english', a.title || ' ' || COALESCE(a.description, '')) @@ plainto_tsquery('english', $%d)", argIndex))
+		whereConditions = append(whereConditions, searchCondition(argIndex))
+		args = append(args, *params.Search)
+		argIndex++
+	}
+
+	if params.MinPrice != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.current_price >= $%d", argIndex))
+		args = append(args, *params.MinPrice)
+		argIndex++
+	}
+
+	if params.MaxPrice != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.current_price <= $%d", argIndex))
+		args = append(args, *params.MaxPrice)
+		argIndex++
+	}
+
+	if params.CertStatus != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.cert_status = $%d", argIndex))
+		args = append(args, *params.CertStatus)
+		argIndex++
+	}
+
+	if params.Condition != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.condition = $%d", argIndex))
+		args = append(args, *params.Condition)
+		argIndex++
+	}
+
+	if params.EndsWithin != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.end_time <= $%d", argIndex))
+		args = append(args, time.Now().Add(*params.EndsWithin))
+		argIndex++
+	}
+
+	if params.HasBuyNow != nil {
+		if *params.HasBuyNow {
+			whereConditions = append(whereConditions, buyNowAvailableCondition)
+		} else {
+			whereConditions = append(whereConditions, "NOT "+buyNowAvailableCondition)
+		}
+	}
+
+	if params.HasReserve != nil {
+		if *params.HasReserve {
+			whereConditions = append(whereConditions, "a.reserve_price IS NOT NULL")
+		} else {
+			whereConditions = append(whereConditions, "a.reserve_price IS NULL")
+		}
+	}
+
+	if params.FreeShipping {
+		whereConditions = append(whereConditions, `EXISTS (
+			SELECT 1 FROM auction_shipping sh WHERE sh.auction_id = a.id AND sh.method = 'free'
+		)`)
+	}
+
+	if params.MinSellerRating != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf(`(
+			SELECT AVG(rt.rating) FROM ratings rt WHERE rt.rated_user_id = a.seller_id AND rt.type = 'seller'
+		) >= $%d`, argIndex))
+		args = append(args, *params.MinSellerRating)
+		argIndex++
+	}
+
+	if params.HideVacationing {
+		whereConditions = append(whereConditions, `NOT EXISTS (
+			SELECT 1 FROM users s
+			WHERE s.id = a.seller_id AND s.vacation_mode AND s.vacation_listing_mode = 'hide'
+				AND NOW() >= s.vacation_starts_at AND NOW() < s.vacation_ends_at
+		)`)
+	}
+
+	if params.HidePrivate {
+		if params.ViewerID != nil && *params.ViewerID != uuid.Nil {
+			whereConditions = append(whereConditions, fmt.Sprintf(`(a.visibility = 'public' OR a.seller_id = $%d OR EXISTS (
+				SELECT 1 FROM auction_invites ai WHERE ai.auction_id = a.id AND ai.user_id = $%d
+			))`, argIndex, argIndex))
+			args = append(args, *params.ViewerID)
+			argIndex++
+		} else {
+			whereConditions = append(whereConditions, "a.visibility = 'public'")
+		}
+	}
+
+	if params.StartedAfter != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.start_time > $%d", argIndex))
+		args = append(args, *params.StartedAfter)
+		argIndex++
+	}
+
+	whereClause := ""
+	if len(whereConditions) > 0 {
+		whereClause = " WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	return whereClause, args
+}
+
+func (r *AuctionRepository) List(ctx context.Context, params *domain.AuctionListParams) ([]domain.Auction, int, error) {
+	baseQuery := `FROM auctions a`
+	whereClause, args := auctionListWhere(params)
+	argIndex := len(args) + 1
+
+	// Count query
+	countQuery := "SELECT COUNT(*) " + baseQuery + whereClause
+	q := r.db.GetQuerier(ctx)
+
+	var totalCount int
+	if err := q.QueryRow(ctx, countQuery, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count auctions: %w", err)
+	}
+
+	// Sort
+	orderBy := " ORDER BY "
+	switch params.SortBy {
+	case "ending_soon":
+		orderBy += "a.end_time ASC"
+	case "newest":
+		orderBy += "a.created_at DESC"
+	case "price_low":
+		orderBy += "a.current_price ASC"
+	case "price_high":
+		orderBy += "a.current_price DESC"
+	case "most_bids":
+		orderBy += "a.bid_count DESC"
+	case "discovery":
+		// Hashing with the seed shuffles listings regardless of popularity,
+		// the same way for every page
+		orderBy += fmt.Sprintf("md5(a.id::text || $%d), a.id", argIndex)
+		args = append(args, params.Seed)
+		argIndex++
+	default:
+		orderBy += "a.created_at DESC"
+	}
+
+	// Pagination
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	page := params.Page
+	if page <= 0 {
+		page = 1
+	}
+	offset := (page - 1) * limit
+
+	args = append(args, limit, offset)
+	listQuery := fmt.Sprintf(`SELECT %s %s%s%s LIMIT $%d OFFSET $%d`,
+		auctionColumns, baseQuery, whereClause, orderBy, argIndex, argIndex+1)
+
+	rows, err := q.Query(ctx, listQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list auctions: %w", err)
+	}
+	defer rows.Close()
+
+	auctions := make([]domain.Auction, 0)
+	for rows.Next() {
+		var auction domain.Auction
+		err := scanAuction(rows, &auction)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan auction: %w", err)
+		}
+		auctions = append(auctions, auction)
+	}
+
+	return auctions, totalCount, nil
+}
+
+// GetFacets counts the auctions matching the filters by category, condition
+// and price bucket. Each facet is counted without its own filter.
+func (r *AuctionRepository) GetFacets(ctx context.Context, params *domain.AuctionListParams) (*domain.AuctionFacets, error) {
+	q := r.db.GetQuerier(ctx)
+	facets := &domain.AuctionFacets{
+		Categories:   make([]domain.CategoryFacet, 0),
+		Conditions:   make([]domain.ConditionFacet, 0),
+		PriceBuckets: make([]domain.PriceFacet, 0, len(domain.PriceFacetBounds)+1),
+	}
+
+	whereClause, args := auctionListWhere(params)
+	if err := q.QueryRow(ctx, "SELECT COUNT(*) FROM auctions a"+whereClause, args...).Scan(&facets.Total); err != nil {
+		return nil, fmt.Errorf("failed to count auctions: %w", err)
+	}
+
+	categoryParams := *params
+	categoryParams.CategoryID = nil
+	whereClause, args = auctionListWhere(&categoryParams)
+	rows, err := q.Query(ctx, `
+		SELECT c.id, c.name, c.slug, COUNT(*)
+		FROM auctions a
+		JOIN categories c ON c.id = a.category_id`+whereClause+`
+		GROUP BY c.id, c.name, c.slug
+		ORDER BY COUNT(*) DESC, c.name ASC`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count auctions by category: %w", err)
+	}
+	for rows.Next() {
+		var facet domain.CategoryFacet
+		if err := rows.Scan(&facet.CategoryID, &facet.Name, &facet.Slug, &facet.Count); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan category facet: %w", err)
+		}
+		facets.Categories = append(facets.Categories, facet)
+	}
+	rows.Close()
+
+	conditionParams := *params
+	conditionParams.Condition = nil
+	whereClause, args = auctionListWhere(&conditionParams)
+	rows, err = q.Query(ctx, `
+		SELECT a.condition, COUNT(*)
+		FROM auctions a`+whereClause+`
+		GROUP BY a.condition
+		ORDER BY COUNT(*) DESC`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count auctions by condition: %w", err)
+	}
+	for rows.Next() {
+		var condition *domain.ItemCondition
+		var count int
+		if err := rows.Scan(&condition, &count); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan condition facet: %w", err)
+		}
+		if condition != nil {
+			facets.Conditions = append(facets.Conditions, domain.ConditionFacet{Condition: *condition, Count: count})
+		}
+	}
+	rows.Close()
+
+	// width_bucket puts prices below the first bound in bucket 0 and those
+	// at or above the last in bucket len(bounds)
+	bounds := make([]string, len(domain.PriceFacetBounds))
+	for i, bound := range domain.PriceFacetBounds {
+		bounds[i] = bound.String()
+	}
+	priceParams := *params
+	priceParams.MinPrice, priceParams.MaxPrice = nil, nil
+	whereClause, args = auctionListWhere(&priceParams)
+	rows, err = q.Query(ctx, `
+		SELECT width_bucket(a.current_price, ARRAY[`+strings.Join(bounds, ", ")+`]::numeric[]), COUNT(*)
+		FROM auctions a`+whereClause+`
+		GROUP BY 1`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count auctions by price: %w", err)
+	}
+	counts := make(map[int]int)
+	for rows.Next() {
+		var bucket, count int
+		if err := rows.Scan(&bucket, &count); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan price facet: %w", err)
+		}
+		counts[bucket] = count
+	}
+	rows.Close()
+
+	min := decimal.Zero
+	for i := 0; i <= len(domain.PriceFacetBounds); i++ {
+		facet := domain.PriceFacet{Min: min, Count: counts[i]}
+		if i < len(domain.PriceFacetBounds) {
+			max := domain.PriceFacetBounds[i]
+			facet.Max = &max
+			min = max
+		}
+		facets.PriceBuckets = append(facets.PriceBuckets, facet)
+	}
+
+	return facets, rows.Err()
+}
+
+// soldFrom joins each sold auction to its order; sales whose order was
+// cancelled never happened
+const soldFrom = ` FROM orders o JOIN auctions a ON a.id = o.auction_id`
+
+// soldWhere builds the WHERE clause for the sold listing filters, returning
+// it with its arguments
+func soldWhere(params *domain.SoldListingParams) (string, []interface{}) {
+	whereConditions := []string{"o.status <> 'cancelled'", "a.visibility = 'public'"}
+	args := []interface{}{}
+	argIndex := 1
+
+	if params.CategoryID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.category_id = $%d", argIndex))
+		args = append(args, *params.CategoryID)
+		argIndex++
+	}
+
+	if params.Search != nil && *params.Search != "" {
+		whereConditions = append(whereConditions, searchCondition(argIndex))
 		args = append(args, *params.Search)
 		argIndex++
 	}
 
+	if params.Condition != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.condition = $%d", argIndex))
+		args = append(args, *params.Condition)
+		argIndex++
+	}
+
+	if params.SoldFrom != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("o.created_at >= $%d", argIndex))
+		args = append(args, *params.SoldFrom)
+		argIndex++
+	}
+
+	if params.SoldTo != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("o.created_at < $%d", argIndex))
+		args = append(args, *params.SoldTo)
+		argIndex++
+	}
+
 	if params.MinPrice != nil {
-		whereConditions = append(whereConditions, fmt.Sprintf("a.current_price >= $%d", argIndex))
+		whereConditions = append(whereConditions, fmt.Sprintf("o.amount >= $%d", argIndex))
 		args = append(args, *params.MinPrice)
 		argIndex++
 	}
 
 	if params.MaxPrice != nil {
-		whereConditions = append(whereConditions, fmt.Sprintf("a.current_price <= $%d", argIndex))
+		whereConditions = append(whereConditions, fmt.Sprintf("o.amount <= $%d", argIndex))
 		args = append(args, *params.MaxPrice)
-		argIndex++
 	}
 
-	whereClause := ""
-	if len(whereConditions) > 0 {
-		whereClause = " WHERE " + strings.Join(whereConditions, " AND ")
-	}
+	return " WHERE " + strings.Join(whereConditions, " AND "), args
+}
 
-	// Count query
-	countQuery := "SELECT COUNT(*) " + baseQuery + whereClause
-	q := r.db.GetQuerier(ctx)
+// ListSold returns past sales matching the filters, most recent first by
+// default
+func (r *AuctionRepository) ListSold(ctx context.Context, params *domain.SoldListingParams) ([]domain.SoldListing, int, error) {
+	whereClause, args := soldWhere(params)
+	argIndex := len(args) + 1
 
+	q := r.db.GetQuerier(ctx)
 	var totalCount int
-	if err := q.QueryRow(ctx, countQuery, args...).Scan(&totalCount); err != nil {
-		return nil, 0, fmt.Errorf("failed to count auctions: %w", err)
+	if err := q.QueryRow(ctx, "SELECT COUNT(*)"+soldFrom+whereClause, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count sold auctions: %w", err)
 	}
 
-	// Sort
 	orderBy := " ORDER BY "
 	switch params.SortBy {
-	case "ending_soon":
-		orderBy += "a.end_time ASC"
-	case "newest":
-		orderBy += "a.created_at DESC"
 	case "price_low":
-		orderBy += "a.current_price ASC"
+		orderBy += "o.amount ASC, o.created_at DESC"
 	case "price_high":
-		orderBy += "a.current_price DESC"
-	case "most_bids":
-		orderBy += "a.bid_count DESC"
+		orderBy += "o.amount DESC, o.created_at DESC"
 	default:
-		orderBy += "a.created_at DESC"
+		orderBy += "o.created_at DESC"
 	}
 
-	// Pagination
 	limit := params.Limit
 	if limit <= 0 {
 		limit = 20
@@ -328,58 +938,245 @@ func (r *AuctionRepository) List(ctx context.Context, params *domain.AuctionList
 
 	args = append(args, limit, offset)
 	listQuery := fmt.Sprintf(`
-		SELECT a.id, a.seller_id, a.category_id, a.title, a.description, a.condition, a.starting_price,
-		       a.reserve_price, a.buy_now_price, a.current_price, a.bid_increment, a.start_time, a.end_time,
-		       a.status, a.winner_id, a.winning_bid_id, a.views_count, a.bid_count, a.version, a.created_at, a.updated_at
-		%s%s%s LIMIT $%d OFFSET $%d`, baseQuery, whereClause, orderBy, argIndex, argIndex+1)
+		SELECT a.id, a.title, a.category_id, a.condition,
+			CASE WHEN a.cert_status = 'verified' THEN a.cert_grader END,
+			CASE WHEN a.cert_status = 'verified' THEN a.cert_grade END,
+			a.starting_price, o.amount, a.bid_count, o.created_at
+		%s%s%s LIMIT $%d OFFSET $%d`,
+		soldFrom, whereClause, orderBy, argIndex, argIndex+1)
 
 	rows, err := q.Query(ctx, listQuery, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list auctions: %w", err)
+		return nil, 0, fmt.Errorf("failed to list sold auctions: %w", err)
 	}
 	defer rows.Close()
 
-	auctions := make([]domain.Auction, 0)
+	sales := make([]domain.SoldListing, 0)
 	for rows.Next() {
-		var auction domain.Auction
+		var sale domain.SoldListing
 		err := rows.Scan(
-			&auction.ID,
-			&auction.SellerID,
-			&auction.CategoryID,
-			&auction.Title,
-			&auction.Description,
-			&auction.Condition,
-			&auction.StartingPrice,
-			&auction.ReservePrice,
-			&auction.BuyNowPrice,
-			&auction.CurrentPrice,
-			&auction.BidIncrement,
-			&auction.StartTime,
-			&auction.EndTime,
-			&auction.Status,
-			&auction.WinnerID,
-			&auction.WinningBidID,
-			&auction.ViewsCount,
-			&auction.BidCount,
-			&auction.Version,
-			&auction.CreatedAt,
-			&auction.UpdatedAt,
+			&sale.AuctionID,
+			&sale.Title,
+			&sale.CategoryID,
+			&sale.Condition,
+			&sale.CertGrader,
+			&sale.CertGrade,
+			&sale.StartingPrice,
+			&sale.SoldPrice,
+			&sale.BidCount,
+			&sale.SoldAt,
 		)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan auction: %w", err)
+			return nil, 0, fmt.Errorf("failed to scan sold auction: %w", err)
 		}
-		auctions = append(auctions, auction)
+		sales = append(sales, sale)
 	}
 
-	return auctions, totalCount, nil
+	return sales, totalCount, rows.Err()
 }
 
-func (r *AuctionRepository) GetEndingAuctions(ctx context.Context, beforeUnix int64) ([]domain.Auction, error) {
+// GetSoldStats summarizes the sale prices of every sale matching the
+// filters
+func (r *AuctionRepository) GetSoldStats(ctx context.Context, params *domain.SoldListingParams) (*domain.SoldPriceStats, error) {
+	whereClause, args := soldWhere(params)
 	query := `
-		SELECT id, seller_id, category_id, title, description, condition, starting_price,
-		       reserve_price, buy_now_price, current_price, bid_increment, start_time, end_time,
-		       status, winner_id, winning_bid_id, views_count, bid_count, version, created_at, updated_at
-		FROM auctions
+		SELECT COUNT(*), ROUND(AVG(o.amount), 2),
+			ROUND(percentile_cont(0.5) WITHIN GROUP (ORDER BY o.amount)::numeric, 2),
+			MIN(o.amount), MAX(o.amount)` + soldFrom + whereClause
+
+	q := r.db.GetQuerier(ctx)
+	stats := &domain.SoldPriceStats{}
+	err := q.QueryRow(ctx, query, args...).Scan(&stats.Count, &stats.Average, &stats.Median, &stats.Min, &stats.Max)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sold price stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// UpdateCertification stores the outcome of verifying the auction's
+// certification. Nothing is written if the seller has changed the
+// certification since it was looked up.
+func (r *AuctionRepository) UpdateCertification(ctx context.Context, id uuid.UUID, cert *domain.Certification) error {
+	query := `
+		UPDATE auctions
+		SET cert_status = $4, cert_grade = $5, cert_subject = $6, cert_year = $7, cert_checked_at = $8
+		WHERE id = $1 AND cert_grader = $2 AND cert_number = $3`
+
+	q := r.db.GetQuerier(ctx)
+	_, err := q.Exec(ctx, query, id, cert.Grader, cert.Number, cert.Status, cert.Grade, cert.Subject, cert.Year, cert.CheckedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update auction certification: %w", err)
+	}
+
+	return nil
+}
+
+// GetItems returns the items of a lot listing in order, each with the IDs
+// of its images
+func (r *AuctionRepository) GetItems(ctx context.Context, auctionID uuid.UUID) ([]domain.AuctionItem, error) {
+	return r.getItems(ctx, `WHERE i.auction_id = $1`, auctionID)
+}
+
+func (r *AuctionRepository) getItems(ctx context.Context, where string, args ...interface{}) ([]domain.AuctionItem, error) {
+	query := `
+		SELECT i.id, i.auction_id, i.position, i.title, i.condition, i.description, i.created_at,
+		       ARRAY(SELECT img.id FROM auction_images img WHERE img.item_id = i.id ORDER BY img.position)
+		FROM auction_items i ` + where + `
+		ORDER BY i.auction_id, i.position`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auction items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]domain.AuctionItem, 0)
+	for rows.Next() {
+		var item domain.AuctionItem
+		if err := rows.Scan(
+			&item.ID, &item.AuctionID, &item.Position, &item.Title, &item.Condition, &item.Description,
+			&item.CreatedAt, &item.ImageIDs,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan auction item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// ReplaceItems swaps the full set of lot items for an auction and points
+// their images at them, joining the caller's transaction if there is one
+func (r *AuctionRepository) ReplaceItems(ctx context.Context, auctionID uuid.UUID, items []domain.AuctionItem) error {
+	if GetTx(ctx) == nil {
+		return r.db.WithTx(ctx, func(txCtx context.Context) error {
+			return r.ReplaceItems(txCtx, auctionID, items)
+		})
+	}
+
+	q := r.db.GetQuerier(ctx)
+
+	// Images of removed items are kept, unattached
+	if _, err := q.Exec(ctx, `DELETE FROM auction_items WHERE auction_id = $1`, auctionID); err != nil {
+		return fmt.Errorf("failed to clear auction items: %w", err)
+	}
+
+	query := `
+		INSERT INTO auction_items (id, auction_id, position, title, condition, description)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at`
+
+	for i := range items {
+		item := &items[i]
+		if item.ID == uuid.Nil {
+			item.ID = uuid.New()
+		}
+		item.AuctionID = auctionID
+		item.Position = i
+
+		err := q.QueryRow(ctx, query, item.ID, item.AuctionID, item.Position, item.Title, item.Condition, item.Description).
+			Scan(&item.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create auction item: %w", err)
+		}
+
+		if len(item.ImageIDs) > 0 {
+			_, err := q.Exec(ctx, `UPDATE auction_images SET item_id = $1 WHERE auction_id = $2 AND id = ANY($3)`,
+				item.ID, auctionID, item.ImageIDs)
+			if err != nil {
+				return fmt.Errorf("failed to attach item images: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ExtendForVacation moves the end time of the seller's active auctions that
+// would end between from and until to newEnd, returning how many moved
+func (r *AuctionRepository) ExtendForVacation(ctx context.Context, sellerID uuid.UUID, from, until, newEnd time.Time) (int64, error) {
+	query := `
+		UPDATE auctions
+		SET end_time = $4, version = version + 1
+		WHERE seller_id = $1 AND status = 'active' AND end_time >= $2 AND end_time < $3`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, sellerID, from, until, newEnd)
+	if err != nil {
+		return 0, fmt.Errorf("failed to extend auctions: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// ExtendEnding moves the end time of every active auction that would end
+// between from and until to newEnd, returning the IDs of those moved
+func (r *AuctionRepository) ExtendEnding(ctx context.Context, from, until, newEnd time.Time) ([]uuid.UUID, error) {
+	query := `
+		UPDATE auctions
+		SET end_time = $3, version = version + 1
+		WHERE status = 'active' AND end_time >= $1 AND end_time < $2
+		RETURNING id`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, from, until, newEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extend auctions: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan extended auction: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to extend auctions: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ShiftEnding moves the end time of every active auction that would end
+// between from and until back by the given duration, returning their new
+// end times
+func (r *AuctionRepository) ShiftEnding(ctx context.Context, from, until time.Time, by time.Duration) (map[uuid.UUID]time.Time, error) {
+	query := `
+		UPDATE auctions
+		SET end_time = end_time + make_interval(secs => $3), version = version + 1
+		WHERE status = 'active' AND end_time >= $1 AND end_time < $2
+		RETURNING id, end_time`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, from, until, by.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to shift auctions: %w", err)
+	}
+	defer rows.Close()
+
+	shifted := make(map[uuid.UUID]time.Time)
+	for rows.Next() {
+		var id uuid.UUID
+		var endTime time.Time
+		if err := rows.Scan(&id, &endTime); err != nil {
+			return nil, fmt.Errorf("failed to scan shifted auction: %w", err)
+		}
+		shifted[id] = endTime
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to shift auctions: %w", err)
+	}
+
+	return shifted, nil
+}
+
+func (r *AuctionRepository) GetEndingAuctions(ctx context.Context, beforeUnix int64) ([]domain.Auction, error) {
+	query := `SELECT ` + auctionColumns + ` FROM auctions
 		WHERE status = 'active' AND end_time <= to_timestamp($1)`
 
 	q := r.db.GetQuerier(ctx)
@@ -392,29 +1189,7 @@ func (r *AuctionRepository) GetEndingAuctions(ctx context.Context, beforeUnix in
 	auctions := make([]domain.Auction, 0)
 	for rows.Next() {
 		var auction domain.Auction
-		err := rows.Scan(
-			&auction.ID,
-			&auction.SellerID,
-			&auction.CategoryID,
-			&auction.Title,
-			&auction.Description,
-			&auction.Condition,
-			&auction.StartingPrice,
-			&auction.ReservePrice,
-			&auction.BuyNowPrice,
-			&auction.CurrentPrice,
-			&auction.BidIncrement,
-			&auction.StartTime,
-			&auction.EndTime,
-			&auction.Status,
-			&auction.WinnerID,
-			&auction.WinningBidID,
-			&auction.ViewsCount,
-			&auction.BidCount,
-			&auction.Version,
-			&auction.CreatedAt,
-			&auction.UpdatedAt,
-		)
+		err := scanAuction(rows, &auction)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan auction: %w", err)
 		}
@@ -424,13 +1199,117 @@ func (r *AuctionRepository) GetEndingAuctions(ctx context.Context, beforeUnix in
 	return auctions, nil
 }
 
-func (r *AuctionRepository) IncrementViewCount(ctx context.Context, id uuid.UUID) error {
-	query := `UPDATE auctions SET views_count = views_count + 1 WHERE id = $1`
+// FindActiveByTitle returns active public auctions whose titles share any
+// word with title, best ranked first. Titles copied from other sites rarely
+// match word for word, so the words are ORed rather than ANDed as in search.
+func (r *AuctionRepository) FindActiveByTitle(ctx context.Context, title string, limit int) ([]domain.Auction, error) {
+	query := `SELECT ` + auctionColumns + ` FROM auctions,
+		replace(plainto_tsquery('english', $1)::text, '&', '|')::tsquery AS words
+		WHERE status = 'active' AND visibility = 'public'
+		  AND to_tsvector('english', title) @@ words
+		ORDER BY ts_rank(to_tsvector('english', title), words) DESC, end_time
+		LIMIT $2`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, title, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find auctions by title: %w", err)
+	}
+	defer rows.Close()
+
+	auctions := make([]domain.Auction, 0)
+	for rows.Next() {
+		var auction domain.Auction
+		if err := scanAuction(rows, &auction); err != nil {
+			return nil, fmt.Errorf("failed to scan auction: %w", err)
+		}
+		auctions = append(auctions, auction)
+	}
+
+	return auctions, rows.Err()
+}
+
+// GetEndTimeLoad counts active auctions ending in each second of [from, to),
+// keyed by unix time. Seconds without endings are omitted.
+func (r *AuctionRepository) GetEndTimeLoad(ctx context.Context, from, to time.Time) (map[int64]int, error) {
+	query := `
+		SELECT EXTRACT(EPOCH FROM date_trunc('second', end_time))::bigint AS slot, COUNT(*)
+		FROM auctions
+		WHERE status = 'active' AND end_time >= $1 AND end_time < $2
+		GROUP BY slot`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get end time load: %w", err)
+	}
+	defer rows.Close()
+
+	load := make(map[int64]int)
+	for rows.Next() {
+		var slot int64
+		var count int
+		if err := rows.Scan(&slot, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan end time load: %w", err)
+		}
+		load[slot] = count
+	}
+
+	return load, nil
+}
+
+// GetActiveEndTimes returns the end time of every active auction
+func (r *AuctionRepository) GetActiveEndTimes(ctx context.Context) (map[uuid.UUID]time.Time, error) {
+	query := `SELECT id, end_time FROM auctions WHERE status = 'active'`
 
 	q := r.db.GetQuerier(ctx)
-	_, err := q.Exec(ctx, query, id)
+	rows, err := q.Query(ctx, query)
 	if err != nil {
-		return fmt.Errorf("failed to increment view count: %w", err)
+		return nil, fmt.Errorf("failed to get active end times: %w", err)
+	}
+	defer rows.Close()
+
+	ends := make(map[uuid.UUID]time.Time)
+	for rows.Next() {
+		var id uuid.UUID
+		var endTime time.Time
+		if err := rows.Scan(&id, &endTime); err != nil {
+			return nil, fmt.Errorf("failed to scan end time: %w", err)
+		}
+		ends[id] = endTime
+	}
+
+	return ends, nil
+}
+
+// AddViews adds counted views to auctions, recording one row per view for
+// the daily view statistics. Views of deleted auctions are dropped.
+func (r *AuctionRepository) AddViews(ctx context.Context, views map[uuid.UUID]int64) error {
+	if len(views) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(views))
+	counts := make([]int64, 0, len(views))
+	for id, count := range views {
+		ids = append(ids, id)
+		counts = append(counts, count)
+	}
+
+	query := `
+		WITH viewed AS (
+			UPDATE auctions a SET views_count = a.views_count + v.count
+			FROM unnest($1::uuid[], $2::bigint[]) AS v(id, count)
+			WHERE a.id = v.id
+			RETURNING a.id, v.count
+		)
+		INSERT INTO auction_views (auction_id)
+		SELECT viewed.id FROM viewed, generate_series(1, viewed.count)`
+
+	q := r.db.GetQuerier(ctx)
+	_, err := q.Exec(ctx, query, ids, counts)
+	if err != nil {
+		return fmt.Errorf("failed to add views: %w", err)
 	}
 
 	return nil
@@ -455,6 +1334,41 @@ func (r *AuctionRepository) UpdateStatus(ctx context.Context, id uuid.UUID, stat
 	return nil
 }
 
+// CloseIfActive sets the final status of an auction that is still active
+// and still at expectedVersion, reporting whether it did. Closing twice is a
+// no-op, so concurrent schedulers can't both settle the same auction. An
+// auction that is active but changed since it was read, by a bid or an
+// extension, fails with ErrConcurrentBid. Closing bumps the version, so
+// writes based on the auction before it closed fail too.
+func (r *AuctionRepository) CloseIfActive(ctx context.Context, id uuid.UUID, expectedVersion int, status domain.AuctionStatus, winnerID *uuid.UUID, winningBidID *uuid.UUID) (bool, error) {
+	query := `
+		UPDATE auctions
+		SET status = $2, winner_id = $3, winning_bid_id = $4, version = version + 1
+		WHERE id = $1 AND status = 'active' AND version = $5`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, id, status, winnerID, winningBidID, expectedVersion)
+	if err != nil {
+		return false, fmt.Errorf("failed to close auction: %w", err)
+	}
+	if result.RowsAffected() > 0 {
+		return true, nil
+	}
+
+	var active bool
+	err = q.QueryRow(ctx, `SELECT status = 'active' FROM auctions WHERE id = $1`, id).Scan(&active)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to close auction: %w", err)
+	}
+	if active {
+		return false, domain.ErrConcurrentBid
+	}
+	return false, nil
+}
+
 // AuctionImageRepository
 type AuctionImageRepository struct {
 	db *DB
@@ -466,8 +1380,8 @@ func NewAuctionImageRepository(db *DB) *AuctionImageRepository {
 
 func (r *AuctionImageRepository) Create(ctx context.Context, image *domain.AuctionImage) error {
 	query := `
-		INSERT INTO auction_images (id, auction_id, url, position)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO auction_images (id, auction_id, url, thumbnail_url, medium_url, large_url, position)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING created_at`
 
 	if image.ID == uuid.Nil {
@@ -475,7 +1389,15 @@ func (r *AuctionImageRepository) Create(ctx context.Context, image *domain.Aucti
 	}
 
 	q := r.db.GetQuerier(ctx)
-	err := q.QueryRow(ctx, query, image.ID, image.AuctionID, image.URL, image.Position).Scan(&image.CreatedAt)
+	err := q.QueryRow(ctx, query,
+		image.ID,
+		image.AuctionID,
+		image.URL,
+		image.ThumbnailURL,
+		image.MediumURL,
+		image.LargeURL,
+		image.Position,
+	).Scan(&image.CreatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create auction image: %w", err)
 	}
@@ -484,7 +1406,9 @@ func (r *AuctionImageRepository) Create(ctx context.Context, image *domain.Aucti
 }
 
 func (r *AuctionImageRepository) GetByAuctionID(ctx context.Context, auctionID uuid.UUID) ([]domain.AuctionImage, error) {
-	query := `SELECT id, auction_id, url, position, created_at FROM auction_images WHERE auction_id = $1 ORDER BY position`
+	query := `
+		SELECT id, auction_id, url, thumbnail_url, medium_url, large_url, position, created_at
+		FROM auction_images WHERE auction_id = $1 ORDER BY position`
 
 	q := r.db.GetQuerier(ctx)
 	rows, err := q.Query(ctx, query, auctionID)
@@ -496,7 +1420,10 @@ func (r *AuctionImageRepository) GetByAuctionID(ctx context.Context, auctionID u
 	images := make([]domain.AuctionImage, 0)
 	for rows.Next() {
 		var img domain.AuctionImage
-		if err := rows.Scan(&img.ID, &img.AuctionID, &img.URL, &img.Position, &img.CreatedAt); err != nil {
+		if err := rows.Scan(
+			&img.ID, &img.AuctionID, &img.URL, &img.ThumbnailURL, &img.MediumURL, &img.LargeURL,
+			&img.Position, &img.CreatedAt,
+		); err != nil {
 			return nil, fmt.Errorf("failed to scan image: %w", err)
 		}
 		images = append(images, img)
@@ -512,7 +1439,7 @@ func (r *AuctionImageRepository) GetFirstImageByAuctionIDs(ctx context.Context,
 
 	// Build query with DISTINCT ON to get first image per auction
 	query := `
-		SELECT DISTINCT ON (auction_id) id, auction_id, url, position, created_at
+		SELECT DISTINCT ON (auction_id) id, auction_id, url, thumbnail_url, medium_url, large_url, position, created_at
 		FROM auction_images
 		WHERE auction_id = ANY($1)
 		ORDER BY auction_id, position ASC`
@@ -527,7 +1454,10 @@ func (r *AuctionImageRepository) GetFirstImageByAuctionIDs(ctx context.Context,
 	images := make(map[uuid.UUID]domain.AuctionImage)
 	for rows.Next() {
 		var img domain.AuctionImage
-		if err := rows.Scan(&img.ID, &img.AuctionID, &img.URL, &img.Position, &img.CreatedAt); err != nil {
+		if err := rows.Scan(
+			&img.ID, &img.AuctionID, &img.URL, &img.ThumbnailURL, &img.MediumURL, &img.LargeURL,
+			&img.Position, &img.CreatedAt,
+		); err != nil {
 			return nil, fmt.Errorf("failed to scan image: %w", err)
 		}
 		images[img.AuctionID] = img
@@ -607,12 +1537,15 @@ func (r *BidRepository) Create(ctx context.Context, bid *domain.Bid) error {
 }
 
 func (r *BidRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Bid, error) {
-	query := `SELECT id, auction_id, bidder_id, amount, is_auto_bid, max_auto_bid, created_at FROM bids WHERE id = $1`
+	query := `
+		SELECT id, auction_id, bidder_id, amount, is_auto_bid, max_auto_bid, created_at, cancelled_at, cancel_reason
+		FROM bids WHERE id = $1`
 
 	q := r.db.GetQuerier(ctx)
 	bid := &domain.Bid{}
 	err := q.QueryRow(ctx, query, id).Scan(
 		&bid.ID, &bid.AuctionID, &bid.BidderID, &bid.Amount, &bid.IsAutoBid, &bid.MaxAutoBid, &bid.CreatedAt,
+		&bid.CancelledAt, &bid.CancelReason,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -629,7 +1562,7 @@ func (r *BidRepository) GetHighestBid(ctx context.Context, auctionID uuid.UUID)
 	query := `
 		SELECT id, auction_id, bidder_id, amount, is_auto_bid, max_auto_bid, created_at
 		FROM bids
-		WHERE auction_id = $1
+		WHERE auction_id = $1 AND cancelled_at IS NULL
 		ORDER BY amount DESC, created_at ASC
 		LIMIT 1`
 
@@ -649,26 +1582,43 @@ func (r *BidRepository) GetHighestBid(ctx context.Context, auctionID uuid.UUID)
 	return bid, nil
 }
 
-func (r *BidRepository) GetByAuctionID(ctx context.Context, auctionID uuid.UUID, page, limit int) ([]domain.Bid, int, error) {
-	countQuery := `SELECT COUNT(*) FROM bids WHERE auction_id = $1`
+// GetByAuctionID lists active bids, newest first. With a cursor it seeks
+// past the cursor instead of counting and offsetting, and the returned total
+// count is zero.
+func (r *BidRepository) GetByAuctionID(ctx context.Context, auctionID uuid.UUID, params *domain.BidListParams) ([]domain.Bid, int, error) {
+	countQuery := `SELECT COUNT(*) FROM bids WHERE auction_id = $1 AND cancelled_at IS NULL`
 	listQuery := `
 		SELECT b.id, b.auction_id, b.bidder_id, b.amount, b.is_auto_bid, b.max_auto_bid, b.created_at,
-		       u.id, u.username, u.avatar_url, u.bio, u.created_at
+		       u.id, u.username, u.avatar_url, u.bio, u.created_at, u.is_deleted
 		FROM bids b
 		JOIN users u ON b.bidder_id = u.id
-		WHERE b.auction_id = $1
-		ORDER BY b.created_at DESC
+		WHERE b.auction_id = $1 AND b.cancelled_at IS NULL
+		ORDER BY b.created_at DESC, b.id DESC
 		LIMIT $2 OFFSET $3`
+	keysetQuery := `
+		SELECT b.id, b.auction_id, b.bidder_id, b.amount, b.is_auto_bid, b.max_auto_bid, b.created_at,
+		       u.id, u.username, u.avatar_url, u.bio, u.created_at, u.is_deleted
+		FROM bids b
+		JOIN users u ON b.bidder_id = u.id
+		WHERE b.auction_id = $1 AND b.cancelled_at IS NULL AND (b.created_at, b.id) < ($3, $4)
+		ORDER BY b.created_at DESC, b.id DESC
+		LIMIT $2`
 
 	q := r.db.GetQuerier(ctx)
 
 	var totalCount int
-	if err := q.QueryRow(ctx, countQuery, auctionID).Scan(&totalCount); err != nil {
-		return nil, 0, fmt.Errorf("failed to count bids: %w", err)
-	}
+	var rows pgx.Rows
+	var err error
+	if params.Cursor != nil {
+		rows, err = q.Query(ctx, keysetQuery, auctionID, params.Limit, params.Cursor.CreatedAt, params.Cursor.ID)
+	} else {
+		if err := q.QueryRow(ctx, countQuery, auctionID).Scan(&totalCount); err != nil {
+			return nil, 0, fmt.Errorf("failed to count bids: %w", err)
+		}
 
-	offset := (page - 1) * limit
-	rows, err := q.Query(ctx, listQuery, auctionID, limit, offset)
+		offset := (params.Page - 1) * params.Limit
+		rows, err = q.Query(ctx, listQuery, auctionID, params.Limit, offset)
+	}
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to list bids: %w", err)
 	}
@@ -680,11 +1630,12 @@ func (r *BidRepository) GetByAuctionID(ctx context.Context, auctionID uuid.UUID,
 		bidder := &domain.PublicUser{}
 		err := rows.Scan(
 			&bid.ID, &bid.AuctionID, &bid.BidderID, &bid.Amount, &bid.IsAutoBid, &bid.MaxAutoBid, &bid.CreatedAt,
-			&bidder.ID, &bidder.Username, &bidder.AvatarURL, &bidder.Bio, &bidder.CreatedAt,
+			&bidder.ID, &bidder.Username, &bidder.AvatarURL, &bidder.Bio, &bidder.CreatedAt, &bidder.Deleted,
 		)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan bid: %w", err)
 		}
+		bidder.HideIfDeleted()
 		bid.Bidder = bidder
 		bids = append(bids, bid)
 	}
@@ -695,7 +1646,7 @@ func (r *BidRepository) GetByAuctionID(ctx context.Context, auctionID uuid.UUID,
 func (r *BidRepository) GetByBidderID(ctx context.Context, bidderID uuid.UUID, page, limit int) ([]domain.Bid, int, error) {
 	countQuery := `SELECT COUNT(*) FROM bids WHERE bidder_id = $1`
 	listQuery := `
-		SELECT id, auction_id, bidder_id, amount, is_auto_bid, max_auto_bid, created_at
+		SELECT id, auction_id, bidder_id, amount, is_auto_bid, max_auto_bid, created_at, cancelled_at, cancel_reason
 		FROM bids
 		WHERE bidder_id = $1
 		ORDER BY created_at DESC
@@ -720,6 +1671,7 @@ func (r *BidRepository) GetByBidderID(ctx context.Context, bidderID uuid.UUID, p
 		var bid domain.Bid
 		err := rows.Scan(
 			&bid.ID, &bid.AuctionID, &bid.BidderID, &bid.Amount, &bid.IsAutoBid, &bid.MaxAutoBid, &bid.CreatedAt,
+			&bid.CancelledAt, &bid.CancelReason,
 		)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan bid: %w", err)
@@ -731,7 +1683,7 @@ func (r *BidRepository) GetByBidderID(ctx context.Context, bidderID uuid.UUID, p
 }
 
 func (r *BidRepository) GetBidCount(ctx context.Context, auctionID uuid.UUID) (int, error) {
-	query := `SELECT COUNT(*) FROM bids WHERE auction_id = $1`
+	query := `SELECT COUNT(*) FROM bids WHERE auction_id = $1 AND cancelled_at IS NULL`
 
 	q := r.db.GetQuerier(ctx)
 	var count int
@@ -746,7 +1698,7 @@ func (r *BidRepository) GetPreviousHighBidder(ctx context.Context, auctionID uui
 	query := `
 		SELECT id, auction_id, bidder_id, amount, is_auto_bid, max_auto_bid, created_at
 		FROM bids
-		WHERE auction_id = $1 AND bidder_id != $2
+		WHERE auction_id = $1 AND bidder_id != $2 AND cancelled_at IS NULL
 		ORDER BY amount DESC, created_at ASC
 		LIMIT 1`
 
@@ -766,120 +1718,109 @@ func (r *BidRepository) GetPreviousHighBidder(ctx context.Context, auctionID uui
 	return bid, nil
 }
 
-// BidTransaction implements atomic bid placement
-type BidTransaction struct {
-	db          *DB
-	auctionRepo *AuctionRepository
-	bidRepo     *BidRepository
-}
+// Cancel marks a bid as cancelled so it no longer counts towards the auction
+func (r *BidRepository) Cancel(ctx context.Context, id, cancelledBy uuid.UUID, reason string) error {
+	query := `
+		UPDATE bids
+		SET cancelled_at = NOW(), cancelled_by = $2, cancel_reason = $3
+		WHERE id = $1 AND cancelled_at IS NULL`
 
-func NewBidTransaction(db *DB, auctionRepo *AuctionRepository, bidRepo *BidRepository) *BidTransaction {
-	return &BidTransaction{
-		db:          db,
-		auctionRepo: auctionRepo,
-		bidRepo:     bidRepo,
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, id, cancelledBy, reason)
+	if err != nil {
+		return fmt.Errorf("failed to cancel bid: %w", err)
 	}
-}
-
-func (t *BidTransaction) PlaceBid(ctx context.Context, auctionID, bidderID uuid.UUID, amount decimal.Decimal, maxAutoBid *decimal.Decimal) (*PlaceBidResult, error) {
-	var result *PlaceBidResult
 
-	err := t.db.WithTx(ctx, func(txCtx context.Context) error {
-		// Get auction with lock
-		auction, err := t.auctionRepo.GetByID(txCtx, auctionID)
-		if err != nil {
-			return err
-		}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
 
-		// Validate auction is active
-		if auction.Status != domain.AuctionStatusActive {
-			return domain.ErrAuctionNotActive
-		}
+	return nil
+}
 
-		// Check auction hasn't ended
-		if auction.EndTime.Unix() < getCurrentUnixTime() {
-			return domain.ErrAuctionEnded
-		}
+// CancelAllForAuction voids every remaining bid on an auction and returns the
+// distinct bidders affected
+func (r *BidRepository) CancelAllForAuction(ctx context.Context, auctionID, cancelledBy uuid.UUID, reason string) ([]uuid.UUID, error) {
+	query := `
+		UPDATE bids
+		SET cancelled_at = NOW(), cancelled_by = $2, cancel_reason = $3
+		WHERE auction_id = $1 AND cancelled_at IS NULL
+		RETURNING bidder_id`
 
-		// Validate not self-bidding
-		if auction.SellerID == bidderID {
-			return domain.ErrSelfBidding
-		}
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, auctionID, cancelledBy, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel bids: %w", err)
+	}
+	defer rows.Close()
 
-		// Validate bid amount
-		minBid := auction.CurrentPrice.Add(auction.BidIncrement)
-		if amount.LessThan(minBid) {
-			return domain.ErrBidTooLow
+	seen := make(map[uuid.UUID]bool)
+	var bidders []uuid.UUID
+	for rows.Next() {
+		var bidderID uuid.UUID
+		if err := rows.Scan(&bidderID); err != nil {
+			return nil, fmt.Errorf("failed to scan cancelled bid: %w", err)
 		}
-
-		// Get previous high bidder for outbid notification
-		prevBid, _ := t.bidRepo.GetHighestBid(txCtx, auctionID)
-		var prevBidderID *uuid.UUID
-		if prevBid != nil && prevBid.BidderID != bidderID {
-			prevBidderID = &prevBid.BidderID
+		if !seen[bidderID] {
+			seen[bidderID] = true
+			bidders = append(bidders, bidderID)
 		}
+	}
 
-		// Create bid
-		bid := &domain.Bid{
-			AuctionID:  auctionID,
-			BidderID:   bidderID,
-			Amount:     amount,
-			IsAutoBid:  maxAutoBid != nil,
-			MaxAutoBid: maxAutoBid,
-		}
-		if err := t.bidRepo.Create(txCtx, bid); err != nil {
-			return err
-		}
+	return bidders, rows.Err()
+}
 
-		// Check for anti-sniping (bid in last 5 minutes)
-		auctionExtended := false
-		var newEndTime *int64
-		fiveMinutesFromNow := getCurrentUnixTime() + 300
-		if auction.EndTime.Unix() < fiveMinutesFromNow {
-			// Extend by 2 minutes
-			extendedTime := auction.EndTime.Add(2 * 60 * 1000000000) // 2 minutes in nanoseconds
-			auction.EndTime = extendedTime
-			auctionExtended = true
-			endTimeUnix := extendedTime.Unix()
-			newEndTime = &endTimeUnix
-		}
+func (r *BidRepository) GetCancelledCount(ctx context.Context, auctionID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM bids WHERE auction_id = $1 AND cancelled_at IS NOT NULL`
 
-		// Update auction
-		auction.CurrentPrice = amount
-		auction.BidCount++
-		expectedVersion := auction.Version
+	q := r.db.GetQuerier(ctx)
+	var count int
+	if err := q.QueryRow(ctx, query, auctionID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count cancelled bids: %w", err)
+	}
 
-		if err := t.auctionRepo.UpdateWithVersion(txCtx, auction, expectedVersion); err != nil {
-			return err
-		}
+	return count, nil
+}
 
-		result = &PlaceBidResult{
-			Bid:             bid,
-			Auction:         auction,
-			AuctionExtended: auctionExtended,
-			NewEndTime:      newEndTime,
-			PreviousBidder:  prevBidderID,
-		}
+// GetStandings returns the bidder's highest active bid in each of the given
+// auctions they bid on, and whether it is the auction's leading bid
+func (r *BidRepository) GetStandings(ctx context.Context, bidderID uuid.UUID, auctionIDs []uuid.UUID) (map[uuid.UUID]domain.BidderStanding, error) {
+	if len(auctionIDs) == 0 {
+		return make(map[uuid.UUID]domain.BidderStanding), nil
+	}
 
-		return nil
-	})
+	query := `
+		WITH mine AS (
+			SELECT auction_id, MAX(amount) AS max_bid, MAX(max_auto_bid) AS max_auto_bid
+			FROM bids
+			WHERE bidder_id = $1 AND auction_id = ANY($2) AND cancelled_at IS NULL
+			GROUP BY auction_id
+		)
+		SELECT m.auction_id, m.max_bid, top.bidder_id = $1, m.max_auto_bid
+		FROM mine m
+		CROSS JOIN LATERAL (
+			SELECT bidder_id FROM bids
+			WHERE auction_id = m.auction_id AND cancelled_at IS NULL
+			ORDER BY amount DESC, created_at ASC
+			LIMIT 1
+		) top`
 
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, bidderID, auctionIDs)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to get bidder standings: %w", err)
 	}
+	defer rows.Close()
 
-	return result, nil
-}
-
-func getCurrentUnixTime() int64 {
-	return 0 // Will be replaced with actual time in service
-}
+	standings := make(map[uuid.UUID]domain.BidderStanding)
+	for rows.Next() {
+		var auctionID uuid.UUID
+		var standing domain.BidderStanding
+		if err := rows.Scan(&auctionID, &standing.MaxBid, &standing.IsHighBidder, &standing.MaxAutoBid); err != nil {
+			return nil, fmt.Errorf("failed to scan bidder standing: %w", err)
+		}
+		standings[auctionID] = standing
+	}
 
-// PlaceBidResult for the repository package
-type PlaceBidResult struct {
-	Bid             *domain.Bid
-	Auction         *domain.Auction
-	AuctionExtended bool
-	NewEndTime      *int64
-	PreviousBidder  *uuid.UUID
+	return standings, nil
 }
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+type AuditLogRepository struct {
+	db *DB
+}
+
+func NewAuditLogRepository(db *DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+func (r *AuditLogRepository) Create(ctx context.Context, entry *domain.AuditLog) error {
+	query := `
+		INSERT INTO audit_logs (id, actor_id, action, entity_type, entity_id, reason, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at`
+
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.Metadata == nil {
+		entry.Metadata = map[string]interface{}{}
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		entry.ID,
+		entry.ActorID,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		entry.Reason,
+		entry.Metadata,
+	).Scan(&entry.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuditLogRepository) List(ctx context.Context, params *domain.AuditLogListParams) ([]domain.AuditLog, int, error) {
+	whereConditions := []string{}
+	args := []interface{}{}
+	argIndex := 1
+
+	if params.ActorID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("actor_id = $%d", argIndex))
+		args = append(args, *params.ActorID)
+		argIndex++
+	}
+
+	if params.Action != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("action = $%d", argIndex))
+		args = append(args, *params.Action)
+		argIndex++
+	}
+
+	if params.EntityType != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("entity_type = $%d", argIndex))
+		args = append(args, *params.EntityType)
+		argIndex++
+	}
+
+	if params.EntityID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("entity_id = $%d", argIndex))
+		args = append(args, *params.EntityID)
+		argIndex++
+	}
+
+	whereClause := ""
+	if len(whereConditions) > 0 {
+		whereClause = " WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	q := r.db.GetQuerier(ctx)
+	var totalCount int
+	if err := q.QueryRow(ctx, "SELECT COUNT(*) FROM audit_logs"+whereClause, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit logs: %w", err)
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	page := params.Page
+	if page <= 0 {
+		page = 1
+	}
+	offset := (page - 1) * limit
+
+	args = append(args, limit, offset)
+	listQuery := fmt.Sprintf(`
+		SELECT id, actor_id, action, entity_type, entity_id, reason, metadata, created_at
+		FROM audit_logs%s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
+
+	rows, err := q.Query(ctx, listQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]domain.AuditLog, 0)
+	for rows.Next() {
+		var entry domain.AuditLog
+		err := rows.Scan(
+			&entry.ID,
+			&entry.ActorID,
+			&entry.Action,
+			&entry.EntityType,
+			&entry.EntityID,
+			&entry.Reason,
+			&entry.Metadata,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan audit log: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, totalCount, nil
+}
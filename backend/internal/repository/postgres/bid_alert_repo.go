@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type SellerBidAlertRepository struct {
+	db *DB
+}
+
+func NewSellerBidAlertRepository(db *DB) *SellerBidAlertRepository {
+	return &SellerBidAlertRepository{db: db}
+}
+
+func (r *SellerBidAlertRepository) Get(ctx context.Context, auctionID uuid.UUID) (*domain.SellerBidAlerts, error) {
+	query := `
+		SELECT auction_id, first_bid, reserve_met, every_n_bids, price_above, created_at, updated_at
+		FROM seller_bid_alerts
+		WHERE auction_id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	alerts := &domain.SellerBidAlerts{}
+	err := q.QueryRow(ctx, query, auctionID).Scan(
+		&alerts.AuctionID,
+		&alerts.FirstBid,
+		&alerts.ReserveMet,
+		&alerts.EveryNBids,
+		&alerts.PriceAbove,
+		&alerts.CreatedAt,
+		&alerts.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seller bid alerts: %w", err)
+	}
+
+	return alerts, nil
+}
+
+// Save creates the listing's alerts or replaces them
+func (r *SellerBidAlertRepository) Save(ctx context.Context, alerts *domain.SellerBidAlerts) error {
+	query := `
+		INSERT INTO seller_bid_alerts (auction_id, first_bid, reserve_met, every_n_bids, price_above)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (auction_id) DO UPDATE SET
+			first_bid = EXCLUDED.first_bid,
+			reserve_met = EXCLUDED.reserve_met,
+			every_n_bids = EXCLUDED.every_n_bids,
+			price_above = EXCLUDED.price_above
+		RETURNING created_at, updated_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		alerts.AuctionID,
+		alerts.FirstBid,
+		alerts.ReserveMet,
+		alerts.EveryNBids,
+		alerts.PriceAbove,
+	).Scan(&alerts.CreatedAt, &alerts.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save seller bid alerts: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SellerBidAlertRepository) Delete(ctx context.Context, auctionID uuid.UUID) error {
+	q := r.db.GetQuerier(ctx)
+	if _, err := q.Exec(ctx, `DELETE FROM seller_bid_alerts WHERE auction_id = $1`, auctionID); err != nil {
+		return fmt.Errorf("failed to delete seller bid alerts: %w", err)
+	}
+
+	return nil
+}
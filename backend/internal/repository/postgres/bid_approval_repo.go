@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const bidApprovalColumns = `b.auction_id, b.user_id, b.status, b.answers, b.note, b.decided_at, b.created_at, b.updated_at,
+	u.username`
+
+func scanBidApproval(row rowScanner, approval *domain.BidApproval) error {
+	return row.Scan(
+		&approval.AuctionID,
+		&approval.UserID,
+		&approval.Status,
+		&approval.Answers,
+		&approval.Note,
+		&approval.DecidedAt,
+		&approval.CreatedAt,
+		&approval.UpdatedAt,
+		&approval.Username,
+	)
+}
+
+type BidApprovalRepository struct {
+	db *DB
+}
+
+func NewBidApprovalRepository(db *DB) *BidApprovalRepository {
+	return &BidApprovalRepository{db: db}
+}
+
+func (r *BidApprovalRepository) Request(ctx context.Context, approval *domain.BidApproval) error {
+	query := `
+		INSERT INTO bid_approvals (auction_id, user_id, status, answers)
+		VALUES ($1, $2, 'pending', $3)
+		ON CONFLICT (auction_id, user_id) DO UPDATE SET answers = EXCLUDED.answers
+		WHERE bid_approvals.status = 'pending'
+		RETURNING status, created_at, updated_at`
+
+	if approval.Answers == nil {
+		approval.Answers = []domain.BidApprovalAnswer{}
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query, approval.AuctionID, approval.UserID, approval.Answers).
+		Scan(&approval.Status, &approval.CreatedAt, &approval.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("failed to request bid approval: %w", err)
+	}
+
+	return nil
+}
+
+func (r *BidApprovalRepository) Get(ctx context.Context, auctionID, userID uuid.UUID) (*domain.BidApproval, error) {
+	query := `
+		SELECT ` + bidApprovalColumns + `
+		FROM bid_approvals b
+		JOIN users u ON u.id = b.user_id
+		WHERE b.auction_id = $1 AND b.user_id = $2`
+
+	q := r.db.GetQuerier(ctx)
+	approval := &domain.BidApproval{}
+	err := scanBidApproval(q.QueryRow(ctx, query, auctionID, userID), approval)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bid approval: %w", err)
+	}
+
+	return approval, nil
+}
+
+// ListByAuction returns the auction's requests, oldest first, optionally
+// only those with the given status
+func (r *BidApprovalRepository) ListByAuction(ctx context.Context, auctionID uuid.UUID, status *domain.BidApprovalStatus) ([]domain.BidApproval, error) {
+	query := `
+		SELECT ` + bidApprovalColumns + `
+		FROM bid_approvals b
+		JOIN users u ON u.id = b.user_id
+		WHERE b.auction_id = $1 AND ($2::varchar IS NULL OR b.status = $2)
+		ORDER BY b.created_at`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, auctionID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bid approvals: %w", err)
+	}
+	defer rows.Close()
+
+	approvals := make([]domain.BidApproval, 0)
+	for rows.Next() {
+		var approval domain.BidApproval
+		if err := scanBidApproval(rows, &approval); err != nil {
+			return nil, fmt.Errorf("failed to scan bid approval: %w", err)
+		}
+		approvals = append(approvals, approval)
+	}
+
+	return approvals, rows.Err()
+}
+
+// Decide saves the seller's decision on a request
+func (r *BidApprovalRepository) Decide(ctx context.Context, approval *domain.BidApproval) error {
+	query := `
+		UPDATE bid_approvals
+		SET status = $3, note = $4, decided_at = NOW()
+		WHERE auction_id = $1 AND user_id = $2
+		RETURNING decided_at, updated_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		approval.AuctionID,
+		approval.UserID,
+		approval.Status,
+		approval.Note,
+	).Scan(&approval.DecidedAt, &approval.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decide bid approval: %w", err)
+	}
+
+	return nil
+}
+
+func (r *BidApprovalRepository) IsApproved(ctx context.Context, auctionID, userID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM bid_approvals WHERE auction_id = $1 AND user_id = $2 AND status = 'approved')`
+
+	q := r.db.GetQuerier(ctx)
+	var approved bool
+	if err := q.QueryRow(ctx, query, auctionID, userID).Scan(&approved); err != nil {
+		return false, fmt.Errorf("failed to check bid approval: %w", err)
+	}
+
+	return approved, nil
+}
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+type BidEventRepository struct {
+	db *DB
+}
+
+func NewBidEventRepository(db *DB) *BidEventRepository {
+	return &BidEventRepository{db: db}
+}
+
+func (r *BidEventRepository) Create(ctx context.Context, event *domain.BidEvent) error {
+	query := `
+		INSERT INTO bid_events (auction_id, bid_id, actor_id, event_type, amount, max_auto_bid, ip, user_agent, reason, end_time)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		event.AuctionID,
+		event.BidID,
+		event.ActorID,
+		event.Type,
+		event.Amount,
+		event.MaxAutoBid,
+		event.IP,
+		event.UserAgent,
+		event.Reason,
+		event.EndTime,
+	).Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create bid event: %w", err)
+	}
+
+	return nil
+}
+
+// ListByAuction returns the auction's bid trail, oldest first, with each
+// actor's username
+func (r *BidEventRepository) ListByAuction(ctx context.Context, auctionID uuid.UUID) ([]domain.BidEvent, error) {
+	query := `
+		SELECT e.id, e.auction_id, e.bid_id, e.actor_id, e.event_type, e.amount, e.max_auto_bid,
+			e.ip, e.user_agent, e.reason, e.end_time, e.created_at, u.username
+		FROM bid_events e
+		LEFT JOIN users u ON u.id = e.actor_id
+		WHERE e.auction_id = $1
+		ORDER BY e.created_at, e.id`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bid events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]domain.BidEvent, 0)
+	for rows.Next() {
+		var event domain.BidEvent
+		if err := rows.Scan(
+			&event.ID,
+			&event.AuctionID,
+			&event.BidID,
+			&event.ActorID,
+			&event.Type,
+			&event.Amount,
+			&event.MaxAutoBid,
+			&event.IP,
+			&event.UserAgent,
+			&event.Reason,
+			&event.EndTime,
+			&event.CreatedAt,
+			&event.ActorUsername,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan bid event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
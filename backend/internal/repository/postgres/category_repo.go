@@ -206,3 +206,157 @@ func (r *CategoryRepository) GetWithAuctionCounts(ctx context.Context) ([]domain
 
 	return categories, nil
 }
+
+func (r *CategoryRepository) ListTranslations(ctx context.Context, categoryID uuid.UUID) ([]domain.CategoryTranslation, error) {
+	query := `
+		SELECT category_id, language, name, description, updated_at
+		FROM category_translations
+		WHERE category_id = $1
+		ORDER BY language`
+
+	return r.queryTranslations(ctx, query, categoryID)
+}
+
+func (r *CategoryRepository) GetTranslations(ctx context.Context, categoryIDs []uuid.UUID, languages []string) ([]domain.CategoryTranslation, error) {
+	query := `
+		SELECT category_id, language, name, description, updated_at
+		FROM category_translations
+		WHERE category_id = ANY($1) AND language = ANY($2)`
+
+	return r.queryTranslations(ctx, query, categoryIDs, languages)
+}
+
+func (r *CategoryRepository) queryTranslations(ctx context.Context, query string, args ...interface{}) ([]domain.CategoryTranslation, error) {
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category translations: %w", err)
+	}
+	defer rows.Close()
+
+	translations := make([]domain.CategoryTranslation, 0)
+	for rows.Next() {
+		var t domain.CategoryTranslation
+		if err := rows.Scan(&t.CategoryID, &t.Language, &t.Name, &t.Description, &t.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan category translation: %w", err)
+		}
+		translations = append(translations, t)
+	}
+
+	return translations, rows.Err()
+}
+
+func (r *CategoryRepository) SetTranslation(ctx context.Context, translation *domain.CategoryTranslation) error {
+	query := `
+		INSERT INTO category_translations (category_id, language, name, description)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (category_id, language) DO UPDATE
+		SET name = EXCLUDED.name, description = EXCLUDED.description
+		RETURNING updated_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		translation.CategoryID,
+		translation.Language,
+		translation.Name,
+		translation.Description,
+	).Scan(&translation.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to set category translation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CategoryRepository) DeleteTranslation(ctx context.Context, categoryID uuid.UUID, language string) error {
+	query := `DELETE FROM category_translations WHERE category_id = $1 AND language = $2`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, categoryID, language)
+	if err != nil {
+		return fmt.Errorf("failed to delete category translation: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *CategoryRepository) GetListingRules(ctx context.Context, categoryID uuid.UUID) (*domain.CategoryListingRules, error) {
+	query := `
+		SELECT category_id, default_duration_hours, default_bid_increment,
+		       min_duration_hours, max_duration_hours, min_bid_increment, min_images, updated_at
+		FROM category_listing_rules
+		WHERE category_id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	rules := &domain.CategoryListingRules{}
+	err := q.QueryRow(ctx, query, categoryID).Scan(
+		&rules.CategoryID,
+		&rules.DefaultDurationHours,
+		&rules.DefaultBidIncrement,
+		&rules.MinDurationHours,
+		&rules.MaxDurationHours,
+		&rules.MinBidIncrement,
+		&rules.MinImages,
+		&rules.UpdatedAt,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category listing rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (r *CategoryRepository) SetListingRules(ctx context.Context, rules *domain.CategoryListingRules) error {
+	query := `
+		INSERT INTO category_listing_rules (category_id, default_duration_hours, default_bid_increment,
+			min_duration_hours, max_duration_hours, min_bid_increment, min_images)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (category_id) DO UPDATE
+		SET default_duration_hours = EXCLUDED.default_duration_hours,
+			default_bid_increment = EXCLUDED.default_bid_increment,
+			min_duration_hours = EXCLUDED.min_duration_hours,
+			max_duration_hours = EXCLUDED.max_duration_hours,
+			min_bid_increment = EXCLUDED.min_bid_increment,
+			min_images = EXCLUDED.min_images
+		RETURNING updated_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		rules.CategoryID,
+		rules.DefaultDurationHours,
+		rules.DefaultBidIncrement,
+		rules.MinDurationHours,
+		rules.MaxDurationHours,
+		rules.MinBidIncrement,
+		rules.MinImages,
+	).Scan(&rules.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to set category listing rules: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CategoryRepository) DeleteListingRules(ctx context.Context, categoryID uuid.UUID) error {
+	query := `DELETE FROM category_listing_rules WHERE category_id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, categoryID)
+	if err != nil {
+		return fmt.Errorf("failed to delete category listing rules: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
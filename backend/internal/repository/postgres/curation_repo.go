@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+type CurationRepository struct {
+	db *DB
+}
+
+func NewCurationRepository(db *DB) *CurationRepository {
+	return &CurationRepository{db: db}
+}
+
+func (r *CurationRepository) Feature(ctx context.Context, featured *domain.FeaturedAuction) error {
+	query := `
+		INSERT INTO featured_auctions (auction_id, position, expires_at, featured_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (auction_id) DO UPDATE SET
+			position = EXCLUDED.position,
+			expires_at = EXCLUDED.expires_at,
+			featured_by = EXCLUDED.featured_by
+		RETURNING created_at, updated_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		featured.AuctionID,
+		featured.Position,
+		featured.ExpiresAt,
+		featured.FeaturedBy,
+	).Scan(&featured.CreatedAt, &featured.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to feature auction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CurationRepository) Unfeature(ctx context.Context, auctionID uuid.UUID) error {
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, `DELETE FROM featured_auctions WHERE auction_id = $1`, auctionID)
+	if err != nil {
+		return fmt.Errorf("failed to unfeature auction: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *CurationRepository) ListFeatured(ctx context.Context) ([]domain.FeaturedAuction, error) {
+	query := `
+		SELECT f.auction_id, f.position, f.expires_at, f.featured_by, f.created_at, f.updated_at,
+			a.title, a.status
+		FROM featured_auctions f
+		JOIN auctions a ON a.id = f.auction_id
+		ORDER BY f.position, f.created_at`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list featured auctions: %w", err)
+	}
+	defer rows.Close()
+
+	featured := make([]domain.FeaturedAuction, 0)
+	for rows.Next() {
+		var f domain.FeaturedAuction
+		if err := rows.Scan(
+			&f.AuctionID,
+			&f.Position,
+			&f.ExpiresAt,
+			&f.FeaturedBy,
+			&f.CreatedAt,
+			&f.UpdatedAt,
+			&f.AuctionTitle,
+			&f.AuctionStatus,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan featured auction: %w", err)
+		}
+		featured = append(featured, f)
+	}
+
+	return featured, rows.Err()
+}
+
+// GetTrending scores auctions by their activity since the given time: a
+// view counts 1, a watch 3 and a bid 5. Auctions without any are left out.
+func (r *CurationRepository) GetTrending(ctx context.Context, since time.Time, limit int) ([]uuid.UUID, error) {
+	query := `
+		WITH activity AS (
+			SELECT auction_id, 1 AS weight FROM auction_views WHERE viewed_at > $1
+			UNION ALL
+			SELECT auction_id, 3 FROM watchlist WHERE created_at > $1
+			UNION ALL
+			SELECT auction_id, 5 FROM bids WHERE created_at > $1 AND cancelled_at IS NULL
+		),
+		scores AS (
+			SELECT auction_id, SUM(weight) AS score
+			FROM activity
+			GROUP BY auction_id
+		)
+		SELECT a.id
+		FROM scores sc
+		JOIN auctions a ON a.id = sc.auction_id
+		WHERE ` + recommendableConditions + `
+		ORDER BY sc.score DESC, a.end_time ASC
+		LIMIT $2`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trending auctions: %w", err)
+	}
+
+	return collectIDs(rows)
+}
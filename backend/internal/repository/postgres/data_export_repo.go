@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const dataExportColumns = `id, user_id, status, object_name, size_bytes, lease_until, started_at,
+	completed_at, expires_at, created_at, updated_at`
+
+func scanDataExport(row rowScanner, e *domain.DataExport) error {
+	return row.Scan(
+		&e.ID,
+		&e.UserID,
+		&e.Status,
+		&e.ObjectName,
+		&e.SizeBytes,
+		&e.LeaseUntil,
+		&e.StartedAt,
+		&e.CompletedAt,
+		&e.ExpiresAt,
+		&e.CreatedAt,
+		&e.UpdatedAt,
+	)
+}
+
+type DataExportRepository struct {
+	db *DB
+}
+
+func NewDataExportRepository(db *DB) *DataExportRepository {
+	return &DataExportRepository{db: db}
+}
+
+func (r *DataExportRepository) Create(ctx context.Context, export *domain.DataExport) error {
+	query := `
+		INSERT INTO data_exports (id, user_id, status)
+		VALUES ($1, $2, $3)
+		RETURNING created_at, updated_at`
+
+	if export.ID == uuid.Nil {
+		export.ID = uuid.New()
+	}
+	if export.Status == "" {
+		export.Status = domain.DataExportPending
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query, export.ID, export.UserID, export.Status).Scan(&export.CreatedAt, &export.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create data export: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatestByUser returns the user's most recent export
+func (r *DataExportRepository) GetLatestByUser(ctx context.Context, userID uuid.UUID) (*domain.DataExport, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	q := r.db.GetQuerier(ctx)
+	export := &domain.DataExport{}
+	err := scanDataExport(q.QueryRow(ctx, query, userID), export)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data export: %w", err)
+	}
+
+	return export, nil
+}
+
+// ClaimNext takes the oldest export waiting to be assembled, or whose
+// worker's lease ran out, and holds it until leaseUntil. It returns
+// ErrNotFound when there is nothing to do.
+func (r *DataExportRepository) ClaimNext(ctx context.Context, now, leaseUntil time.Time) (*domain.DataExport, error) {
+	query := `
+		UPDATE data_exports
+		SET status = 'processing', lease_until = $2, started_at = COALESCE(started_at, $1)
+		WHERE id = (
+			SELECT id FROM data_exports
+			WHERE status = 'pending' OR (status = 'processing' AND lease_until <= $1)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + dataExportColumns
+
+	q := r.db.GetQuerier(ctx)
+	export := &domain.DataExport{}
+	err := scanDataExport(q.QueryRow(ctx, query, now, leaseUntil), export)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim data export: %w", err)
+	}
+
+	return export, nil
+}
+
+// ListExpired returns completed exports whose link has expired, oldest
+// first, so their archives can be deleted
+func (r *DataExportRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]domain.DataExport, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports
+		WHERE status = 'completed' AND expires_at <= $1
+		ORDER BY expires_at
+		LIMIT $2`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired data exports: %w", err)
+	}
+	defer rows.Close()
+
+	exports := make([]domain.DataExport, 0)
+	for rows.Next() {
+		var export domain.DataExport
+		if err := scanDataExport(rows, &export); err != nil {
+			return nil, fmt.Errorf("failed to scan data export: %w", err)
+		}
+		exports = append(exports, export)
+	}
+
+	return exports, nil
+}
+
+// Save saves the status, archive and lease of an export
+func (r *DataExportRepository) Save(ctx context.Context, export *domain.DataExport) error {
+	query := `
+		UPDATE data_exports
+		SET status = $2, object_name = $3, size_bytes = $4, lease_until = $5,
+		    completed_at = $6, expires_at = $7
+		WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	_, err := q.Exec(ctx, query,
+		export.ID,
+		export.Status,
+		export.ObjectName,
+		export.SizeBytes,
+		export.LeaseUntil,
+		export.CompletedAt,
+		export.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save data export: %w", err)
+	}
+
+	return nil
+}
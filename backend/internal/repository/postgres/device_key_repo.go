@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+type DeviceKeyRepository struct {
+	db *DB
+}
+
+func NewDeviceKeyRepository(db *DB) *DeviceKeyRepository {
+	return &DeviceKeyRepository{db: db}
+}
+
+// Upsert registers a device's public key, replacing the key previously
+// registered for the same device
+func (r *DeviceKeyRepository) Upsert(ctx context.Context, key *domain.DeviceKey) error {
+	query := `
+		INSERT INTO device_keys (id, user_id, device_id, public_key)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, device_id)
+		DO UPDATE SET public_key = EXCLUDED.public_key, updated_at = NOW()
+		RETURNING id, created_at, updated_at`
+
+	if key.ID == uuid.Nil {
+		key.ID = uuid.New()
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		key.ID,
+		key.UserID,
+		key.DeviceID,
+		key.PublicKey,
+	).Scan(&key.ID, &key.CreatedAt, &key.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to register device key: %w", err)
+	}
+
+	return nil
+}
+
+// GetByUser returns every device key registered by a user, oldest first
+func (r *DeviceKeyRepository) GetByUser(ctx context.Context, userID uuid.UUID) ([]domain.DeviceKey, error) {
+	query := `
+		SELECT id, user_id, device_id, public_key, created_at, updated_at
+		FROM device_keys
+		WHERE user_id = $1
+		ORDER BY created_at ASC`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]domain.DeviceKey, 0)
+	for rows.Next() {
+		var key domain.DeviceKey
+		err := rows.Scan(
+			&key.ID,
+			&key.UserID,
+			&key.DeviceID,
+			&key.PublicKey,
+			&key.CreatedAt,
+			&key.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan device key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Delete revokes one of the user's device keys
+func (r *DeviceKeyRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	query := `DELETE FROM device_keys WHERE id = $1 AND user_id = $2`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete device key: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
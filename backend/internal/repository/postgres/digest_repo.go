@@ -0,0 +1,236 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const digestPreferenceColumns = `user_id, frequency, send_hour, send_weekday, timezone,
+	watched_ending, saved_searches, seller_stats, last_sent_at, next_send_at, created_at, updated_at`
+
+func scanDigestPreferences(row rowScanner, p *domain.DigestPreferences) error {
+	return row.Scan(
+		&p.UserID,
+		&p.Frequency,
+		&p.Hour,
+		&p.Weekday,
+		&p.Timezone,
+		&p.WatchedEnding,
+		&p.SavedSearches,
+		&p.SellerStats,
+		&p.LastSentAt,
+		&p.NextSendAt,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+}
+
+type DigestRepository struct {
+	db *DB
+}
+
+func NewDigestRepository(db *DB) *DigestRepository {
+	return &DigestRepository{db: db}
+}
+
+func (r *DigestRepository) GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.DigestPreferences, error) {
+	query := `SELECT ` + digestPreferenceColumns + ` FROM digest_preferences WHERE user_id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	preferences := &domain.DigestPreferences{}
+	err := scanDigestPreferences(q.QueryRow(ctx, query, userID), preferences)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest preferences: %w", err)
+	}
+
+	return preferences, nil
+}
+
+// SavePreferences creates the user's digest preferences or replaces them.
+// When the digest was last sent is kept.
+func (r *DigestRepository) SavePreferences(ctx context.Context, preferences *domain.DigestPreferences) error {
+	query := `
+		INSERT INTO digest_preferences (user_id, frequency, send_hour, send_weekday, timezone,
+			watched_ending, saved_searches, seller_stats, next_send_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id) DO UPDATE SET
+			frequency = EXCLUDED.frequency,
+			send_hour = EXCLUDED.send_hour,
+			send_weekday = EXCLUDED.send_weekday,
+			timezone = EXCLUDED.timezone,
+			watched_ending = EXCLUDED.watched_ending,
+			saved_searches = EXCLUDED.saved_searches,
+			seller_stats = EXCLUDED.seller_stats,
+			next_send_at = EXCLUDED.next_send_at
+		RETURNING last_sent_at, created_at, updated_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		preferences.UserID,
+		preferences.Frequency,
+		preferences.Hour,
+		preferences.Weekday,
+		preferences.Timezone,
+		preferences.WatchedEnding,
+		preferences.SavedSearches,
+		preferences.SellerStats,
+		preferences.NextSendAt,
+	).Scan(&preferences.LastSentAt, &preferences.CreatedAt, &preferences.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save digest preferences: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDue returns up to limit preferences whose digest is due by now,
+// putting their next send off by lease so no other run picks them up while
+// this one sends. Rows locked by another instance are skipped.
+func (r *DigestRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.DigestPreferences, error) {
+	query := `
+		UPDATE digest_preferences SET next_send_at = $2
+		WHERE user_id IN (
+			SELECT user_id FROM digest_preferences
+			WHERE frequency <> 'off' AND next_send_at <= $1
+			ORDER BY next_send_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + digestPreferenceColumns
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due digests: %w", err)
+	}
+	defer rows.Close()
+
+	due := make([]domain.DigestPreferences, 0)
+	for rows.Next() {
+		var p domain.DigestPreferences
+		if err := scanDigestPreferences(rows, &p); err != nil {
+			return nil, fmt.Errorf("failed to scan digest preferences: %w", err)
+		}
+		due = append(due, p)
+	}
+
+	return due, rows.Err()
+}
+
+// MarkSent records that the user's digests cover up to sentAt, and when the
+// next is due
+func (r *DigestRepository) MarkSent(ctx context.Context, userID uuid.UUID, sentAt, nextAt time.Time) error {
+	q := r.db.GetQuerier(ctx)
+	_, err := q.Exec(ctx, `
+		UPDATE digest_preferences SET last_sent_at = $2, next_send_at = $3
+		WHERE user_id = $1`, userID, sentAt, nextAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark digest sent: %w", err)
+	}
+
+	return nil
+}
+
+// WatchedEnding returns the active auctions on the user's watchlist ending
+// by until, soonest first
+func (r *DigestRepository) WatchedEnding(ctx context.Context, userID uuid.UUID, until time.Time, limit int) ([]domain.Auction, error) {
+	query := `SELECT ` + auctionColumns + ` FROM auctions
+		WHERE id IN (SELECT auction_id FROM watchlist WHERE user_id = $1)
+		  AND status = 'active' AND end_time > NOW() AND end_time <= $2
+		ORDER BY end_time
+		LIMIT $3`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, userID, until, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watched auctions ending: %w", err)
+	}
+	defer rows.Close()
+
+	auctions := make([]domain.Auction, 0)
+	for rows.Next() {
+		var auction domain.Auction
+		if err := scanAuction(rows, &auction); err != nil {
+			return nil, fmt.Errorf("failed to scan auction: %w", err)
+		}
+		auctions = append(auctions, auction)
+	}
+
+	return auctions, rows.Err()
+}
+
+// SellerStats sums up the seller's listings: those active now and ending by
+// endingBy, and the bids, watchers and endings between since and until.
+// Cancelled bids aren't counted.
+func (r *DigestRepository) SellerStats(ctx context.Context, sellerID uuid.UUID, since, until, endingBy time.Time) (*domain.DigestSellerStats, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE a.status = 'active'),
+			COUNT(*) FILTER (WHERE a.status = 'active' AND a.end_time <= $4),
+			COALESCE((
+				SELECT COUNT(*) FROM bids b JOIN auctions ba ON ba.id = b.auction_id
+				WHERE ba.seller_id = $1 AND b.cancelled_at IS NULL
+				  AND b.created_at > $2 AND b.created_at <= $3
+			), 0),
+			COALESCE((
+				SELECT COUNT(*) FROM watchlist w JOIN auctions wa ON wa.id = w.auction_id
+				WHERE wa.seller_id = $1 AND w.created_at > $2 AND w.created_at <= $3
+			), 0),
+			COUNT(*) FILTER (WHERE a.status = 'completed' AND a.winner_id IS NOT NULL
+				AND a.end_time > $2 AND a.end_time <= $3),
+			COUNT(*) FILTER (WHERE (a.status = 'unsold' OR (a.status = 'completed' AND a.winner_id IS NULL))
+				AND a.end_time > $2 AND a.end_time <= $3)
+		FROM auctions a
+		WHERE a.seller_id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	stats := &domain.DigestSellerStats{}
+	err := q.QueryRow(ctx, query, sellerID, since, until, endingBy).Scan(
+		&stats.ActiveListings,
+		&stats.EndingSoon,
+		&stats.NewBids,
+		&stats.NewWatchers,
+		&stats.Sold,
+		&stats.Unsold,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seller digest stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// DigestsWatchedEnding returns which of the users get watched auctions about
+// to end in their digest
+func (r *DigestRepository) DigestsWatchedEnding(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	query := `
+		SELECT user_id FROM digest_preferences
+		WHERE user_id = ANY($1) AND frequency <> 'off' AND watched_ending`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest preferences: %w", err)
+	}
+	defer rows.Close()
+
+	digests := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan digest preference: %w", err)
+		}
+		digests[userID] = true
+	}
+
+	return digests, rows.Err()
+}
@@ -0,0 +1,301 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const disputeColumns = `id, order_id, auction_id, opened_by, buyer_id, seller_id, reason, description,
+	status, outcome, refund_amount, resolution_note, resolved_by, resolved_at, created_at, updated_at`
+
+func scanDispute(row rowScanner, d *domain.Dispute) error {
+	return row.Scan(
+		&d.ID,
+		&d.OrderID,
+		&d.AuctionID,
+		&d.OpenedBy,
+		&d.BuyerID,
+		&d.SellerID,
+		&d.Reason,
+		&d.Description,
+		&d.Status,
+		&d.Outcome,
+		&d.RefundAmount,
+		&d.ResolutionNote,
+		&d.ResolvedBy,
+		&d.ResolvedAt,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	)
+}
+
+type DisputeRepository struct {
+	db *DB
+}
+
+func NewDisputeRepository(db *DB) *DisputeRepository {
+	return &DisputeRepository{db: db}
+}
+
+func (r *DisputeRepository) Create(ctx context.Context, dispute *domain.Dispute) error {
+	query := `
+		INSERT INTO disputes (id, order_id, auction_id, opened_by, buyer_id, seller_id, reason, description, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at, updated_at`
+
+	if dispute.ID == uuid.Nil {
+		dispute.ID = uuid.New()
+	}
+	if dispute.Status == "" {
+		dispute.Status = domain.DisputeStatusOpen
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		dispute.ID,
+		dispute.OrderID,
+		dispute.AuctionID,
+		dispute.OpenedBy,
+		dispute.BuyerID,
+		dispute.SellerID,
+		dispute.Reason,
+		dispute.Description,
+		dispute.Status,
+	).Scan(&dispute.CreatedAt, &dispute.UpdatedAt)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrDisputeExists
+		}
+		return fmt.Errorf("failed to create dispute: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DisputeRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Dispute, error) {
+	query := `SELECT ` + disputeColumns + ` FROM disputes WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	dispute := &domain.Dispute{}
+	err := scanDispute(q.QueryRow(ctx, query, id), dispute)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dispute: %w", err)
+	}
+
+	return dispute, nil
+}
+
+func (r *DisputeRepository) GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]domain.Dispute, error) {
+	query := `SELECT ` + disputeColumns + ` FROM disputes WHERE order_id = $1 ORDER BY created_at DESC`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disputes: %w", err)
+	}
+	defer rows.Close()
+
+	disputes := make([]domain.Dispute, 0)
+	for rows.Next() {
+		var dispute domain.Dispute
+		if err := scanDispute(rows, &dispute); err != nil {
+			return nil, fmt.Errorf("failed to scan dispute: %w", err)
+		}
+		disputes = append(disputes, dispute)
+	}
+
+	return disputes, nil
+}
+
+func (r *DisputeRepository) List(ctx context.Context, params *domain.DisputeListParams) ([]domain.Dispute, int, error) {
+	whereConditions := []string{}
+	args := []interface{}{}
+	argIndex := 1
+
+	if params.Status != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("status = $%d", argIndex))
+		args = append(args, *params.Status)
+		argIndex++
+	}
+
+	if params.UserID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("(buyer_id = $%d OR seller_id = $%d)", argIndex, argIndex))
+		args = append(args, *params.UserID)
+		argIndex++
+	}
+
+	whereClause := ""
+	if len(whereConditions) > 0 {
+		whereClause = " WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	q := r.db.GetQuerier(ctx)
+	var totalCount int
+	if err := q.QueryRow(ctx, "SELECT COUNT(*) FROM disputes"+whereClause, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count disputes: %w", err)
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	page := params.Page
+	if page <= 0 {
+		page = 1
+	}
+	offset := (page - 1) * limit
+
+	args = append(args, limit, offset)
+	listQuery := fmt.Sprintf(`SELECT %s FROM disputes%s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
+		disputeColumns, whereClause, argIndex, argIndex+1)
+
+	rows, err := q.Query(ctx, listQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list disputes: %w", err)
+	}
+	defer rows.Close()
+
+	disputes := make([]domain.Dispute, 0)
+	for rows.Next() {
+		var dispute domain.Dispute
+		if err := scanDispute(rows, &dispute); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan dispute: %w", err)
+		}
+		disputes = append(disputes, dispute)
+	}
+
+	return disputes, totalCount, nil
+}
+
+// Resolve stores the admin decision on an open dispute
+func (r *DisputeRepository) Resolve(ctx context.Context, dispute *domain.Dispute) error {
+	query := `
+		UPDATE disputes
+		SET status = 'resolved', outcome = $2, refund_amount = $3, resolution_note = $4,
+		    resolved_by = $5, resolved_at = NOW()
+		WHERE id = $1 AND status = 'open'
+		RETURNING status, resolved_at, updated_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		dispute.ID,
+		dispute.Outcome,
+		dispute.RefundAmount,
+		dispute.ResolutionNote,
+		dispute.ResolvedBy,
+	).Scan(&dispute.Status, &dispute.ResolvedAt, &dispute.UpdatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrDisputeClosed
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resolve dispute: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DisputeRepository) AddMessage(ctx context.Context, msg *domain.DisputeMessage) error {
+	query := `
+		INSERT INTO dispute_messages (id, dispute_id, sender_id, is_admin, body)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at`
+
+	if msg.ID == uuid.Nil {
+		msg.ID = uuid.New()
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query, msg.ID, msg.DisputeID, msg.SenderID, msg.IsAdmin, msg.Body).Scan(&msg.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create dispute message: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DisputeRepository) GetMessages(ctx context.Context, disputeID uuid.UUID) ([]domain.DisputeMessage, error) {
+	query := `
+		SELECT id, dispute_id, sender_id, is_admin, body, created_at
+		FROM dispute_messages
+		WHERE dispute_id = $1
+		ORDER BY created_at ASC`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, disputeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dispute messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := make([]domain.DisputeMessage, 0)
+	for rows.Next() {
+		var msg domain.DisputeMessage
+		if err := rows.Scan(&msg.ID, &msg.DisputeID, &msg.SenderID, &msg.IsAdmin, &msg.Body, &msg.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan dispute message: %w", err)
+		}
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+func (r *DisputeRepository) AddEvidence(ctx context.Context, evidence *domain.DisputeEvidence) error {
+	query := `
+		INSERT INTO dispute_evidence (id, dispute_id, uploaded_by, url, description)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at`
+
+	if evidence.ID == uuid.Nil {
+		evidence.ID = uuid.New()
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		evidence.ID, evidence.DisputeID, evidence.UploadedBy, evidence.URL, evidence.Description,
+	).Scan(&evidence.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create dispute evidence: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DisputeRepository) GetEvidence(ctx context.Context, disputeID uuid.UUID) ([]domain.DisputeEvidence, error) {
+	query := `
+		SELECT id, dispute_id, uploaded_by, url, description, created_at
+		FROM dispute_evidence
+		WHERE dispute_id = $1
+		ORDER BY created_at ASC`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, disputeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dispute evidence: %w", err)
+	}
+	defer rows.Close()
+
+	evidence := make([]domain.DisputeEvidence, 0)
+	for rows.Next() {
+		var e domain.DisputeEvidence
+		if err := rows.Scan(&e.ID, &e.DisputeID, &e.UploadedBy, &e.URL, &e.Description, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan dispute evidence: %w", err)
+		}
+		evidence = append(evidence, e)
+	}
+
+	return evidence, nil
+}
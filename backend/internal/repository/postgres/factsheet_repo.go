@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const factsheetColumns = `auction_id, status, fingerprint, object_name, lease_until, generated_at,
+	created_at, updated_at`
+
+func scanFactsheet(row rowScanner, f *domain.AuctionFactsheet) error {
+	return row.Scan(
+		&f.AuctionID,
+		&f.Status,
+		&f.Fingerprint,
+		&f.ObjectName,
+		&f.LeaseUntil,
+		&f.GeneratedAt,
+		&f.CreatedAt,
+		&f.UpdatedAt,
+	)
+}
+
+type AuctionFactsheetRepository struct {
+	db *DB
+}
+
+func NewAuctionFactsheetRepository(db *DB) *AuctionFactsheetRepository {
+	return &AuctionFactsheetRepository{db: db}
+}
+
+func (r *AuctionFactsheetRepository) GetByAuction(ctx context.Context, auctionID uuid.UUID) (*domain.AuctionFactsheet, error) {
+	query := `SELECT ` + factsheetColumns + ` FROM auction_factsheets WHERE auction_id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	factsheet := &domain.AuctionFactsheet{}
+	err := scanFactsheet(q.QueryRow(ctx, query, auctionID), factsheet)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get factsheet: %w", err)
+	}
+
+	return factsheet, nil
+}
+
+// Request queues the auction's sheet to be rendered, unless it already is
+// waiting or being rendered. The stored sheet is kept until it is replaced.
+func (r *AuctionFactsheetRepository) Request(ctx context.Context, auctionID uuid.UUID) (*domain.AuctionFactsheet, error) {
+	query := `
+		INSERT INTO auction_factsheets (auction_id, status)
+		VALUES ($1, 'pending')
+		ON CONFLICT (auction_id) DO UPDATE
+		SET status = CASE WHEN auction_factsheets.status IN ('pending', 'processing')
+			THEN auction_factsheets.status ELSE 'pending' END
+		RETURNING ` + factsheetColumns
+
+	q := r.db.GetQuerier(ctx)
+	factsheet := &domain.AuctionFactsheet{}
+	if err := scanFactsheet(q.QueryRow(ctx, query, auctionID), factsheet); err != nil {
+		return nil, fmt.Errorf("failed to request factsheet: %w", err)
+	}
+
+	return factsheet, nil
+}
+
+// ClaimNext takes the longest waiting sheet, or one whose worker's lease
+// ran out, and holds it until leaseUntil. It returns ErrNotFound when there
+// is nothing to do.
+func (r *AuctionFactsheetRepository) ClaimNext(ctx context.Context, now, leaseUntil time.Time) (*domain.AuctionFactsheet, error) {
+	query := `
+		UPDATE auction_factsheets
+		SET status = 'processing', lease_until = $2
+		WHERE auction_id = (
+			SELECT auction_id FROM auction_factsheets
+			WHERE status = 'pending' OR (status = 'processing' AND lease_until <= $1)
+			ORDER BY updated_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + factsheetColumns
+
+	q := r.db.GetQuerier(ctx)
+	factsheet := &domain.AuctionFactsheet{}
+	err := scanFactsheet(q.QueryRow(ctx, query, now, leaseUntil), factsheet)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim factsheet: %w", err)
+	}
+
+	return factsheet, nil
+}
+
+// Save saves the status, stored sheet and lease of a factsheet
+func (r *AuctionFactsheetRepository) Save(ctx context.Context, factsheet *domain.AuctionFactsheet) error {
+	query := `
+		UPDATE auction_factsheets
+		SET status = $2, fingerprint = $3, object_name = $4, lease_until = $5, generated_at = $6
+		WHERE auction_id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	_, err := q.Exec(ctx, query,
+		factsheet.AuctionID,
+		factsheet.Status,
+		factsheet.Fingerprint,
+		factsheet.ObjectName,
+		factsheet.LeaseUntil,
+		factsheet.GeneratedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save factsheet: %w", err)
+	}
+
+	return nil
+}
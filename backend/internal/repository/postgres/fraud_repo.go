@@ -0,0 +1,335 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type FraudRepository struct {
+	db *DB
+}
+
+func NewFraudRepository(db *DB) *FraudRepository {
+	return &FraudRepository{db: db}
+}
+
+// SaveFlag inserts the flag, or refreshes the open one for the same finding
+// with the latest severity and evidence. A reviewed flag is left alone.
+func (r *FraudRepository) SaveFlag(ctx context.Context, flag *domain.FraudFlag) (bool, error) {
+	query := `
+		INSERT INTO fraud_flags (check_type, subject_id, related_user_ids, auction_id, severity, evidence, dedupe_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (check_type, dedupe_key) DO UPDATE SET
+			related_user_ids = EXCLUDED.related_user_ids,
+			severity = EXCLUDED.severity,
+			evidence = EXCLUDED.evidence,
+			detected_at = NOW()
+		WHERE fraud_flags.status = 'open'
+		RETURNING id, status, detected_at, created_at, updated_at`
+
+	if flag.RelatedUserIDs == nil {
+		flag.RelatedUserIDs = []uuid.UUID{}
+	}
+	if flag.Evidence == nil {
+		flag.Evidence = map[string]interface{}{}
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		flag.Check,
+		flag.SubjectID,
+		flag.RelatedUserIDs,
+		flag.AuctionID,
+		flag.Severity,
+		flag.Evidence,
+		flag.DedupeKey,
+	).Scan(&flag.ID, &flag.Status, &flag.DetectedAt, &flag.CreatedAt, &flag.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to save fraud flag: %w", err)
+	}
+
+	return true, nil
+}
+
+const fraudFlagColumns = `f.id, f.check_type, f.subject_id, f.related_user_ids, f.auction_id, f.severity, f.evidence,
+	f.status, f.dedupe_key, f.reviewed_by, f.review_note, f.detected_at, f.created_at, f.updated_at, u.username`
+
+func (r *FraudRepository) GetFlag(ctx context.Context, id uuid.UUID) (*domain.FraudFlag, error) {
+	query := `
+		SELECT ` + fraudFlagColumns + `
+		FROM fraud_flags f
+		LEFT JOIN users u ON u.id = f.subject_id
+		WHERE f.id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	flag, err := scanFraudFlag(q.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fraud flag: %w", err)
+	}
+
+	return flag, nil
+}
+
+func (r *FraudRepository) UpdateFlag(ctx context.Context, flag *domain.FraudFlag) error {
+	query := `
+		UPDATE fraud_flags SET status = $2, reviewed_by = $3, review_note = $4
+		WHERE id = $1
+		RETURNING updated_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query, flag.ID, flag.Status, flag.ReviewedBy, flag.ReviewNote).Scan(&flag.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update fraud flag: %w", err)
+	}
+
+	return nil
+}
+
+func (r *FraudRepository) ListFlags(ctx context.Context, params *domain.FraudFlagListParams) ([]domain.FraudFlag, int, error) {
+	whereClause := "WHERE TRUE"
+	args := []interface{}{}
+	argIndex := 1
+
+	if params.Status != nil {
+		whereClause += fmt.Sprintf(" AND f.status = $%d", argIndex)
+		args = append(args, *params.Status)
+		argIndex++
+	}
+	if params.Check != nil {
+		whereClause += fmt.Sprintf(" AND f.check_type = $%d", argIndex)
+		args = append(args, *params.Check)
+		argIndex++
+	}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM fraud_flags f %s", whereClause)
+
+	q := r.db.GetQuerier(ctx)
+	var totalCount int
+	if err := q.QueryRow(ctx, countQuery, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count fraud flags: %w", err)
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	page := params.Page
+	if page <= 0 {
+		page = 1
+	}
+	offset := (page - 1) * limit
+
+	args = append(args, limit, offset)
+	listQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM fraud_flags f
+		LEFT JOIN users u ON u.id = f.subject_id
+		%s
+		ORDER BY f.severity DESC, f.detected_at DESC
+		LIMIT $%d OFFSET $%d`, fraudFlagColumns, whereClause, argIndex, argIndex+1)
+
+	rows, err := q.Query(ctx, listQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list fraud flags: %w", err)
+	}
+	defer rows.Close()
+
+	flags := make([]domain.FraudFlag, 0)
+	for rows.Next() {
+		flag, err := scanFraudFlag(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan fraud flag: %w", err)
+		}
+		flags = append(flags, *flag)
+	}
+
+	return flags, totalCount, rows.Err()
+}
+
+func scanFraudFlag(row pgx.Row) (*domain.FraudFlag, error) {
+	flag := &domain.FraudFlag{}
+	err := row.Scan(
+		&flag.ID,
+		&flag.Check,
+		&flag.SubjectID,
+		&flag.RelatedUserIDs,
+		&flag.AuctionID,
+		&flag.Severity,
+		&flag.Evidence,
+		&flag.Status,
+		&flag.DedupeKey,
+		&flag.ReviewedBy,
+		&flag.ReviewNote,
+		&flag.DetectedAt,
+		&flag.CreatedAt,
+		&flag.UpdatedAt,
+		&flag.SubjectUsername,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return flag, nil
+}
+
+// FindSharedIPBidding groups recent bids on each seller's auctions by the
+// address they came from, keeping addresses several bidders used or the
+// seller signs in from
+func (r *FraudRepository) FindSharedIPBidding(ctx context.Context, since time.Time) ([]domain.SharedIPBidding, error) {
+	query := `
+		SELECT seller_id, ip, bidders, auctions, bids, seller_used_ip
+		FROM (
+			SELECT a.seller_id, e.ip,
+				ARRAY_AGG(DISTINCT e.actor_id) AS bidders,
+				ARRAY_AGG(DISTINCT e.auction_id) AS auctions,
+				COUNT(*) AS bids,
+				EXISTS (
+					SELECT 1 FROM login_events l WHERE l.user_id = a.seller_id AND l.ip = e.ip
+				) AS seller_used_ip
+			FROM bid_events e
+			JOIN auctions a ON a.id = e.auction_id
+			WHERE e.event_type IN ('placed', 'buy_now')
+				AND e.ip IS NOT NULL
+				AND e.actor_id IS NOT NULL
+				AND e.actor_id <> a.seller_id
+				AND e.created_at >= $1
+			GROUP BY a.seller_id, e.ip
+		) shared
+		WHERE CARDINALITY(bidders) >= 2 OR seller_used_ip`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find shared ip bidding: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]domain.SharedIPBidding, 0)
+	for rows.Next() {
+		var s domain.SharedIPBidding
+		if err := rows.Scan(&s.SellerID, &s.IP, &s.BidderIDs, &s.AuctionIDs, &s.Bids, &s.SellerUsedIP); err != nil {
+			return nil, fmt.Errorf("failed to scan shared ip bidding: %w", err)
+		}
+		results = append(results, s)
+	}
+
+	return results, rows.Err()
+}
+
+// FindBidRetractions returns bidders with at least minCancelled of their
+// recent bids cancelled
+func (r *FraudRepository) FindBidRetractions(ctx context.Context, since time.Time, minCancelled int) ([]domain.BidRetractions, error) {
+	query := `
+		SELECT b.bidder_id,
+			COUNT(*) AS placed,
+			COUNT(*) FILTER (WHERE b.cancelled_at IS NOT NULL) AS cancelled,
+			COUNT(DISTINCT a.seller_id) FILTER (WHERE b.cancelled_at IS NOT NULL) AS sellers
+		FROM bids b
+		JOIN auctions a ON a.id = b.auction_id
+		WHERE b.created_at >= $1
+		GROUP BY b.bidder_id
+		HAVING COUNT(*) FILTER (WHERE b.cancelled_at IS NOT NULL) >= $2`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, since, minCancelled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bid retractions: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]domain.BidRetractions, 0)
+	for rows.Next() {
+		var b domain.BidRetractions
+		if err := rows.Scan(&b.BidderID, &b.Placed, &b.Cancelled, &b.Sellers); err != nil {
+			return nil, fmt.Errorf("failed to scan bid retractions: %w", err)
+		}
+		results = append(results, b)
+	}
+
+	return results, rows.Err()
+}
+
+// FindNewAccountVelocity returns accounts created after createdAfter that
+// placed at least minBids bids since the given time
+func (r *FraudRepository) FindNewAccountVelocity(ctx context.Context, since, createdAfter time.Time, minBids int) ([]domain.BidVelocity, error) {
+	query := `
+		SELECT u.id, u.created_at, COUNT(b.id) AS bids, COUNT(DISTINCT b.auction_id) AS auctions
+		FROM users u
+		JOIN bids b ON b.bidder_id = u.id AND b.created_at >= $1
+		WHERE u.created_at >= $2
+		GROUP BY u.id, u.created_at
+		HAVING COUNT(b.id) >= $3`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, since, createdAfter, minBids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bid velocity: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]domain.BidVelocity, 0)
+	for rows.Next() {
+		var v domain.BidVelocity
+		if err := rows.Scan(&v.UserID, &v.AccountCreatedAt, &v.Bids, &v.Auctions); err != nil {
+			return nil, fmt.Errorf("failed to scan bid velocity: %w", err)
+		}
+		results = append(results, v)
+	}
+
+	return results, rows.Err()
+}
+
+// FindLinkedPurchases returns recent orders whose buyer has signed in from
+// the seller's device or address
+func (r *FraudRepository) FindLinkedPurchases(ctx context.Context, since time.Time) ([]domain.LinkedPurchase, error) {
+	query := `
+		SELECT auction_id, id, seller_id, buyer_id, shared_device, shared_ip
+		FROM (
+			SELECT o.auction_id, o.id, o.seller_id, o.buyer_id,
+				EXISTS (
+					SELECT 1 FROM user_devices sd
+					JOIN user_devices bd ON bd.fingerprint = sd.fingerprint
+					WHERE sd.user_id = o.seller_id AND bd.user_id = o.buyer_id
+				) AS shared_device,
+				EXISTS (
+					SELECT 1 FROM login_events sl
+					JOIN login_events bl ON bl.ip = sl.ip
+					WHERE sl.user_id = o.seller_id AND bl.user_id = o.buyer_id AND sl.ip IS NOT NULL
+				) AS shared_ip
+			FROM orders o
+			WHERE o.created_at >= $1 AND o.status <> 'cancelled'
+		) linked
+		WHERE shared_device OR shared_ip`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find linked purchases: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]domain.LinkedPurchase, 0)
+	for rows.Next() {
+		var p domain.LinkedPurchase
+		if err := rows.Scan(&p.AuctionID, &p.OrderID, &p.SellerID, &p.BuyerID, &p.SharedDevice, &p.SharedIP); err != nil {
+			return nil, fmt.Errorf("failed to scan linked purchase: %w", err)
+		}
+		results = append(results, p)
+	}
+
+	return results, rows.Err()
+}
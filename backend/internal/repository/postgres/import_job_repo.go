@@ -0,0 +1,172 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const importJobColumns = `id, seller_id, format, filename, status, total_rows, processed_rows,
+	created_count, failed_count, lease_until, started_at, completed_at, created_at, updated_at`
+
+func scanImportJob(row rowScanner, j *domain.ImportJob, extra ...interface{}) error {
+	dest := []interface{}{
+		&j.ID,
+		&j.SellerID,
+		&j.Format,
+		&j.Filename,
+		&j.Status,
+		&j.TotalRows,
+		&j.ProcessedRows,
+		&j.CreatedCount,
+		&j.FailedCount,
+		&j.LeaseUntil,
+		&j.StartedAt,
+		&j.CompletedAt,
+		&j.CreatedAt,
+		&j.UpdatedAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
+
+type ImportJobRepository struct {
+	db *DB
+}
+
+func NewImportJobRepository(db *DB) *ImportJobRepository {
+	return &ImportJobRepository{db: db}
+}
+
+func (r *ImportJobRepository) Create(ctx context.Context, job *domain.ImportJob) error {
+	query := `
+		INSERT INTO import_jobs (id, seller_id, format, filename, status, rows, total_rows)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at, updated_at`
+
+	if job.ID == uuid.Nil {
+		job.ID = uuid.New()
+	}
+	if job.Status == "" {
+		job.Status = domain.ImportJobPending
+	}
+	job.TotalRows = len(job.Rows)
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		job.ID,
+		job.SellerID,
+		job.Format,
+		job.Filename,
+		job.Status,
+		job.Rows,
+		job.TotalRows,
+	).Scan(&job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create import job: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ImportJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ImportJob, error) {
+	query := `SELECT ` + importJobColumns + `, report FROM import_jobs WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	job := &domain.ImportJob{}
+	err := scanImportJob(q.QueryRow(ctx, query, id), job, &job.Report)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import job: %w", err)
+	}
+
+	return job, nil
+}
+
+// ListBySeller returns the seller's most recent imports, newest first
+func (r *ImportJobRepository) ListBySeller(ctx context.Context, sellerID uuid.UUID, limit int) ([]domain.ImportJob, error) {
+	query := `SELECT ` + importJobColumns + ` FROM import_jobs
+		WHERE seller_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, sellerID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list import jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]domain.ImportJob, 0)
+	for rows.Next() {
+		var job domain.ImportJob
+		if err := scanImportJob(rows, &job); err != nil {
+			return nil, fmt.Errorf("failed to scan import job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// ClaimNext takes the oldest job waiting to be processed, or whose worker's
+// lease ran out, with its rows and the report so far, and holds it until
+// leaseUntil. It returns ErrNotFound when there is nothing to do.
+func (r *ImportJobRepository) ClaimNext(ctx context.Context, now, leaseUntil time.Time) (*domain.ImportJob, error) {
+	query := `
+		UPDATE import_jobs
+		SET status = 'processing', lease_until = $2, started_at = COALESCE(started_at, $1)
+		WHERE id = (
+			SELECT id FROM import_jobs
+			WHERE status = 'pending' OR (status = 'processing' AND lease_until <= $1)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + importJobColumns + `, rows, report`
+
+	q := r.db.GetQuerier(ctx)
+	job := &domain.ImportJob{}
+	err := scanImportJob(q.QueryRow(ctx, query, now, leaseUntil), job, &job.Rows, &job.Report)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim import job: %w", err)
+	}
+
+	return job, nil
+}
+
+// SaveProgress saves the counts, report, lease and status of a job being
+// processed
+func (r *ImportJobRepository) SaveProgress(ctx context.Context, job *domain.ImportJob) error {
+	query := `
+		UPDATE import_jobs
+		SET status = $2, processed_rows = $3, created_count = $4, failed_count = $5,
+		    report = $6, lease_until = $7, completed_at = $8
+		WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	_, err := q.Exec(ctx, query,
+		job.ID,
+		job.Status,
+		job.ProcessedRows,
+		job.CreatedCount,
+		job.FailedCount,
+		job.Report,
+		job.LeaseUntil,
+		job.CompletedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save import job progress: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,286 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+const escrowHoldColumns = `e.order_id, e.buyer_id, e.seller_id, e.amount, e.refunded, e.status, e.release_at,
+	e.delivery_confirmed_at, e.released_at, e.created_at,
+	EXISTS (SELECT 1 FROM disputes d WHERE d.order_id = e.order_id AND d.status = 'open')`
+
+func scanEscrowHold(row rowScanner, h *domain.EscrowHold) error {
+	return row.Scan(
+		&h.OrderID,
+		&h.BuyerID,
+		&h.SellerID,
+		&h.Amount,
+		&h.Refunded,
+		&h.Status,
+		&h.ReleaseAt,
+		&h.DeliveryConfirmedAt,
+		&h.ReleasedAt,
+		&h.CreatedAt,
+		&h.Disputed,
+	)
+}
+
+type EscrowRepository struct {
+	db *DB
+}
+
+func NewEscrowRepository(db *DB) *EscrowRepository {
+	return &EscrowRepository{db: db}
+}
+
+func (r *EscrowRepository) Create(ctx context.Context, hold *domain.EscrowHold) error {
+	query := `
+		INSERT INTO escrow_holds (order_id, buyer_id, seller_id, amount, status, release_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at`
+
+	if hold.Status == "" {
+		hold.Status = domain.EscrowHeld
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		hold.OrderID,
+		hold.BuyerID,
+		hold.SellerID,
+		hold.Amount,
+		hold.Status,
+		hold.ReleaseAt,
+	).Scan(&hold.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create escrow hold: %w", err)
+	}
+
+	return nil
+}
+
+func (r *EscrowRepository) GetByOrder(ctx context.Context, orderID uuid.UUID) (*domain.EscrowHold, error) {
+	query := `SELECT ` + escrowHoldColumns + ` FROM escrow_holds e WHERE e.order_id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	hold := &domain.EscrowHold{}
+	err := scanEscrowHold(q.QueryRow(ctx, query, orderID), hold)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get escrow hold: %w", err)
+	}
+
+	return hold, nil
+}
+
+// ListHeld returns holds awaiting release, soonest first
+func (r *EscrowRepository) ListHeld(ctx context.Context, page, limit int) ([]domain.EscrowHold, int, error) {
+	q := r.db.GetQuerier(ctx)
+
+	var totalCount int
+	if err := q.QueryRow(ctx, `SELECT COUNT(*) FROM escrow_holds WHERE status = 'held'`).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count escrow holds: %w", err)
+	}
+
+	query := `SELECT ` + escrowHoldColumns + ` FROM escrow_holds e
+		WHERE e.status = 'held'
+		ORDER BY e.release_at ASC
+		LIMIT $1 OFFSET $2`
+
+	holds, err := r.query(ctx, query, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return holds, totalCount, nil
+}
+
+// GetDue returns holds whose release time has passed, leaving out orders
+// with an open dispute
+func (r *EscrowRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]domain.EscrowHold, error) {
+	query := `SELECT ` + escrowHoldColumns + ` FROM escrow_holds e
+		WHERE e.status = 'held' AND e.release_at <= $1
+			AND NOT EXISTS (SELECT 1 FROM disputes d WHERE d.order_id = e.order_id AND d.status = 'open')
+		ORDER BY e.release_at ASC
+		LIMIT $2`
+
+	return r.query(ctx, query, now, limit)
+}
+
+func (r *EscrowRepository) query(ctx context.Context, query string, args ...interface{}) ([]domain.EscrowHold, error) {
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list escrow holds: %w", err)
+	}
+	defer rows.Close()
+
+	holds := make([]domain.EscrowHold, 0)
+	for rows.Next() {
+		var hold domain.EscrowHold
+		if err := scanEscrowHold(rows, &hold); err != nil {
+			return nil, fmt.Errorf("failed to scan escrow hold: %w", err)
+		}
+		holds = append(holds, hold)
+	}
+
+	return holds, rows.Err()
+}
+
+func (r *EscrowRepository) ConfirmDelivery(ctx context.Context, orderID uuid.UUID) error {
+	query := `
+		UPDATE escrow_holds SET delivery_confirmed_at = NOW()
+		WHERE order_id = $1 AND status = 'held' AND delivery_confirmed_at IS NULL`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to confirm delivery: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEscrowNotHeld
+	}
+
+	return nil
+}
+
+func (r *EscrowRepository) Release(ctx context.Context, orderID uuid.UUID) error {
+	query := `
+		UPDATE escrow_holds SET status = 'released', released_at = NOW()
+		WHERE order_id = $1 AND status = 'held'`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to release escrow hold: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEscrowNotHeld
+	}
+
+	return nil
+}
+
+// AddRefund records part of a held payment going back to the buyer. A hold
+// refunded in full is closed.
+func (r *EscrowRepository) AddRefund(ctx context.Context, orderID uuid.UUID, amount decimal.Decimal) error {
+	query := `
+		UPDATE escrow_holds
+		SET refunded = refunded + $2,
+		    status = CASE WHEN refunded + $2 >= amount THEN 'refunded' ELSE status END
+		WHERE order_id = $1 AND status = 'held' AND refunded + $2 <= amount`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, orderID, amount)
+	if err != nil {
+		return fmt.Errorf("failed to refund escrow hold: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEscrowNotHeld
+	}
+
+	return nil
+}
+
+type LedgerRepository struct {
+	db *DB
+}
+
+func NewLedgerRepository(db *DB) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// CreateEntries records the entries of one transaction
+func (r *LedgerRepository) CreateEntries(ctx context.Context, entries []domain.LedgerEntry) error {
+	query := `
+		INSERT INTO ledger_entries (id, transaction_id, account, user_id, order_id, payout_id, amount, memo)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at`
+
+	q := r.db.GetQuerier(ctx)
+	for i := range entries {
+		entry := &entries[i]
+		if entry.ID == uuid.Nil {
+			entry.ID = uuid.New()
+		}
+
+		err := q.QueryRow(ctx, query,
+			entry.ID,
+			entry.TransactionID,
+			entry.Account,
+			entry.UserID,
+			entry.OrderID,
+			entry.PayoutID,
+			entry.Amount,
+			entry.Memo,
+		).Scan(&entry.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create ledger entry: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *LedgerRepository) AdjustBalance(ctx context.Context, userID uuid.UUID, delta decimal.Decimal) error {
+	query := `
+		INSERT INTO balances (user_id, available)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET available = balances.available + EXCLUDED.available, updated_at = NOW()`
+
+	q := r.db.GetQuerier(ctx)
+	if _, err := q.Exec(ctx, query, userID, delta); err != nil {
+		return fmt.Errorf("failed to adjust balance: %w", err)
+	}
+
+	return nil
+}
+
+// LockBalance returns the user's available balance, locking it until the
+// transaction ends
+func (r *LedgerRepository) LockBalance(ctx context.Context, userID uuid.UUID) (decimal.Decimal, error) {
+	query := `SELECT available FROM balances WHERE user_id = $1 FOR UPDATE`
+
+	q := r.db.GetQuerier(ctx)
+	var available decimal.Decimal
+	err := q.QueryRow(ctx, query, userID).Scan(&available)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return decimal.Zero, nil
+	}
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to lock balance: %w", err)
+	}
+
+	return available, nil
+}
+
+// GetBalance returns the user's available balance, what is still held in
+// escrow for them as seller and what they are withdrawing
+func (r *LedgerRepository) GetBalance(ctx context.Context, userID uuid.UUID) (*domain.Balance, error) {
+	query := `
+		SELECT
+			COALESCE((SELECT available FROM balances WHERE user_id = $1), 0),
+			COALESCE((SELECT SUM(amount - refunded) FROM escrow_holds WHERE seller_id = $1 AND status = 'held'), 0),
+			COALESCE((SELECT SUM(amount) FROM payouts WHERE user_id = $1 AND status IN ('requested', 'approved')), 0)`
+
+	q := r.db.GetQuerier(ctx)
+	balance := &domain.Balance{UserID: userID}
+	if err := q.QueryRow(ctx, query, userID).Scan(&balance.Available, &balance.Pending, &balance.Withdrawing); err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	return balance, nil
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type ListingShareRepository struct {
+	db *DB
+}
+
+func NewListingShareRepository(db *DB) *ListingShareRepository {
+	return &ListingShareRepository{db: db}
+}
+
+// GetOrCreate stores the share under share.Code, or loads the sharer's
+// existing share of the listing if they already have one. A code taken by
+// another share gives ErrConflict.
+func (r *ListingShareRepository) GetOrCreate(ctx context.Context, share *domain.ListingShare) error {
+	query := `
+		INSERT INTO listing_shares (code, auction_id, sharer_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (auction_id, sharer_id) DO UPDATE SET auction_id = EXCLUDED.auction_id
+		RETURNING id, code, created_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query, share.Code, share.AuctionID, share.SharerID).
+		Scan(&share.ID, &share.Code, &share.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrConflict
+		}
+		return fmt.Errorf("failed to create listing share: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ListingShareRepository) GetByCode(ctx context.Context, code string) (*domain.ListingShare, error) {
+	query := `SELECT id, code, auction_id, sharer_id, created_at FROM listing_shares WHERE code = $1`
+
+	q := r.db.GetQuerier(ctx)
+	var share domain.ListingShare
+	err := q.QueryRow(ctx, query, code).Scan(&share.ID, &share.Code, &share.AuctionID, &share.SharerID, &share.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get listing share: %w", err)
+	}
+
+	return &share, nil
+}
+
+// RecordClick logs a visit through the share; userID is nil for visitors
+// who aren't signed in
+func (r *ListingShareRepository) RecordClick(ctx context.Context, shareID uuid.UUID, userID *uuid.UUID) error {
+	q := r.db.GetQuerier(ctx)
+	_, err := q.Exec(ctx, `INSERT INTO listing_share_clicks (share_id, user_id) VALUES ($1, $2)`, shareID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to record share click: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const liveEventColumns = `id, auctioneer_id, title, description, starts_at, status, current_lot_id,
+	started_at, ended_at, created_at, updated_at`
+
+func scanLiveEvent(row rowScanner, event *domain.LiveEvent) error {
+	return row.Scan(
+		&event.ID,
+		&event.AuctioneerID,
+		&event.Title,
+		&event.Description,
+		&event.StartsAt,
+		&event.Status,
+		&event.CurrentLotID,
+		&event.StartedAt,
+		&event.EndedAt,
+		&event.CreatedAt,
+		&event.UpdatedAt,
+	)
+}
+
+type LiveEventRepository struct {
+	db *DB
+}
+
+func NewLiveEventRepository(db *DB) *LiveEventRepository {
+	return &LiveEventRepository{db: db}
+}
+
+func (r *LiveEventRepository) Create(ctx context.Context, event *domain.LiveEvent) error {
+	query := `
+		INSERT INTO live_events (auctioneer_id, title, description, starts_at, status)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		event.AuctioneerID,
+		event.Title,
+		event.Description,
+		event.StartsAt,
+		event.Status,
+	).Scan(&event.ID, &event.CreatedAt, &event.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create live event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *LiveEventRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.LiveEvent, error) {
+	query := `SELECT ` + liveEventColumns + ` FROM live_events WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	event := &domain.LiveEvent{}
+	err := scanLiveEvent(q.QueryRow(ctx, query, id), event)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get live event: %w", err)
+	}
+
+	return event, nil
+}
+
+func (r *LiveEventRepository) ListUpcoming(ctx context.Context) ([]domain.LiveEvent, error) {
+	query := `
+		SELECT ` + liveEventColumns + `
+		FROM live_events
+		WHERE status <> 'ended'
+		ORDER BY starts_at`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list live events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]domain.LiveEvent, 0)
+	for rows.Next() {
+		var event domain.LiveEvent
+		if err := scanLiveEvent(rows, &event); err != nil {
+			return nil, fmt.Errorf("failed to scan live event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *LiveEventRepository) Update(ctx context.Context, event *domain.LiveEvent) error {
+	query := `
+		UPDATE live_events
+		SET status = $2, current_lot_id = $3, started_at = $4, ended_at = $5
+		WHERE id = $1
+		RETURNING updated_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		event.ID,
+		event.Status,
+		event.CurrentLotID,
+		event.StartedAt,
+		event.EndedAt,
+	).Scan(&event.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update live event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *LiveEventRepository) AddLots(ctx context.Context, eventID uuid.UUID, auctionIDs []uuid.UUID) error {
+	q := r.db.GetQuerier(ctx)
+
+	_, err := q.Exec(ctx, `
+		INSERT INTO live_event_lots (auction_id, event_id, position)
+		SELECT lot.auction_id, $1, lot.position
+		FROM unnest($2::uuid[]) WITH ORDINALITY AS lot(auction_id, position)`,
+		eventID, auctionIDs)
+	if err != nil {
+		return fmt.Errorf("failed to add live event lots: %w", err)
+	}
+
+	_, err = q.Exec(ctx, `UPDATE auctions SET live_event_id = $1 WHERE id = ANY($2::uuid[])`, eventID, auctionIDs)
+	if err != nil {
+		return fmt.Errorf("failed to mark live event lots: %w", err)
+	}
+
+	return nil
+}
+
+// ListLots returns the event's lots in running order
+func (r *LiveEventRepository) ListLots(ctx context.Context, eventID uuid.UUID) ([]domain.LiveLot, error) {
+	query := `
+		SELECT l.event_id, l.auction_id, l.position, l.state, l.opened_at, l.closed_at,
+		       a.title, a.current_price, a.bid_count
+		FROM live_event_lots l
+		JOIN auctions a ON a.id = l.auction_id
+		WHERE l.event_id = $1
+		ORDER BY l.position`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list live event lots: %w", err)
+	}
+	defer rows.Close()
+
+	lots := make([]domain.LiveLot, 0)
+	for rows.Next() {
+		var lot domain.LiveLot
+		err := rows.Scan(
+			&lot.EventID,
+			&lot.AuctionID,
+			&lot.Position,
+			&lot.State,
+			&lot.OpenedAt,
+			&lot.ClosedAt,
+			&lot.Title,
+			&lot.CurrentPrice,
+			&lot.BidCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan live event lot: %w", err)
+		}
+		lots = append(lots, lot)
+	}
+
+	return lots, rows.Err()
+}
+
+func (r *LiveEventRepository) UpdateLot(ctx context.Context, lot *domain.LiveLot) error {
+	query := `
+		UPDATE live_event_lots
+		SET state = $2, opened_at = $3, closed_at = $4
+		WHERE auction_id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, lot.AuctionID, lot.State, lot.OpenedAt, lot.ClosedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update live event lot: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
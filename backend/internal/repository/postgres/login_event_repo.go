@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const loginEventColumns = `id, user_id, method, provider, ip, country, user_agent, device_id, reported_at, created_at`
+
+func scanLoginEvent(row rowScanner, event *domain.LoginEvent) error {
+	return row.Scan(
+		&event.ID,
+		&event.UserID,
+		&event.Method,
+		&event.Provider,
+		&event.IP,
+		&event.Country,
+		&event.UserAgent,
+		&event.DeviceID,
+		&event.ReportedAt,
+		&event.CreatedAt,
+	)
+}
+
+type LoginEventRepository struct {
+	db *DB
+}
+
+func NewLoginEventRepository(db *DB) *LoginEventRepository {
+	return &LoginEventRepository{db: db}
+}
+
+func (r *LoginEventRepository) Create(ctx context.Context, event *domain.LoginEvent) error {
+	query := `
+		INSERT INTO login_events (id, user_id, method, provider, ip, country, user_agent, device_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at`
+
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		event.ID,
+		event.UserID,
+		event.Method,
+		event.Provider,
+		event.IP,
+		event.Country,
+		event.UserAgent,
+		event.DeviceID,
+	).Scan(&event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create login event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *LoginEventRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.LoginEvent, error) {
+	query := `SELECT ` + loginEventColumns + ` FROM login_events WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	event := &domain.LoginEvent{}
+	err := scanLoginEvent(q.QueryRow(ctx, query, id), event)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login event: %w", err)
+	}
+
+	return event, nil
+}
+
+// ListByUserID returns the user's most recent sign-ins, newest first
+func (r *LoginEventRepository) ListByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]domain.LoginEvent, error) {
+	query := `SELECT ` + loginEventColumns + ` FROM login_events
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list login events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]domain.LoginEvent, 0)
+	for rows.Next() {
+		var event domain.LoginEvent
+		if err := scanLoginEvent(rows, &event); err != nil {
+			return nil, fmt.Errorf("failed to scan login event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// MarkReported records that the user disowned the sign-in
+func (r *LoginEventRepository) MarkReported(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE login_events SET reported_at = COALESCE(reported_at, NOW()) WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to report login event: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
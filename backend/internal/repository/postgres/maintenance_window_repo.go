@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type MaintenanceWindowRepository struct {
+	db *DB
+}
+
+func NewMaintenanceWindowRepository(db *DB) *MaintenanceWindowRepository {
+	return &MaintenanceWindowRepository{db: db}
+}
+
+const maintenanceWindowColumns = `id, reason, starts_at, ends_at, created_by, completed_at, auctions_extended, created_at`
+
+func (r *MaintenanceWindowRepository) Create(ctx context.Context, window *domain.MaintenanceWindow) error {
+	query := `
+		INSERT INTO maintenance_windows (reason, starts_at, ends_at, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query, window.Reason, window.StartsAt, window.EndsAt, window.CreatedBy).
+		Scan(&window.ID, &window.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create maintenance window: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MaintenanceWindowRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.MaintenanceWindow, error) {
+	query := `SELECT ` + maintenanceWindowColumns + ` FROM maintenance_windows WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	window, err := scanMaintenanceWindow(q.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance window: %w", err)
+	}
+
+	return window, nil
+}
+
+func (r *MaintenanceWindowRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, `DELETE FROM maintenance_windows WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete maintenance window: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *MaintenanceWindowRepository) ListOpen(ctx context.Context) ([]domain.MaintenanceWindow, error) {
+	query := `
+		SELECT ` + maintenanceWindowColumns + `
+		FROM maintenance_windows
+		WHERE completed_at IS NULL
+		ORDER BY starts_at`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	windows := make([]domain.MaintenanceWindow, 0)
+	for rows.Next() {
+		window, err := scanMaintenanceWindow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance window: %w", err)
+		}
+		windows = append(windows, *window)
+	}
+
+	return windows, rows.Err()
+}
+
+func (r *MaintenanceWindowRepository) Overlaps(ctx context.Context, startsAt, endsAt time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM maintenance_windows
+			WHERE completed_at IS NULL AND starts_at < $2 AND ends_at > $1
+		)`
+
+	var overlaps bool
+	q := r.db.GetQuerier(ctx)
+	if err := q.QueryRow(ctx, query, startsAt, endsAt).Scan(&overlaps); err != nil {
+		return false, fmt.Errorf("failed to check maintenance window overlap: %w", err)
+	}
+
+	return overlaps, nil
+}
+
+func (r *MaintenanceWindowRepository) Complete(ctx context.Context, id uuid.UUID, completedAt time.Time) error {
+	query := `
+		UPDATE maintenance_windows SET completed_at = $2
+		WHERE id = $1 AND completed_at IS NULL`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, id, completedAt)
+	if err != nil {
+		return fmt.Errorf("failed to complete maintenance window: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *MaintenanceWindowRepository) SetAuctionsExtended(ctx context.Context, id uuid.UUID, count int) error {
+	query := `UPDATE maintenance_windows SET auctions_extended = $2 WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	if _, err := q.Exec(ctx, query, id, count); err != nil {
+		return fmt.Errorf("failed to record extended auctions: %w", err)
+	}
+
+	return nil
+}
+
+func scanMaintenanceWindow(row pgx.Row) (*domain.MaintenanceWindow, error) {
+	window := &domain.MaintenanceWindow{}
+	err := row.Scan(
+		&window.ID,
+		&window.Reason,
+		&window.StartsAt,
+		&window.EndsAt,
+		&window.CreatedBy,
+		&window.CompletedAt,
+		&window.AuctionsExtended,
+		&window.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return window, nil
+}
@@ -19,8 +19,9 @@ func NewMessageRepository(db *DB) *MessageRepository {
 	return &MessageRepository{db: db}
 }
 
-// GetOrCreateConversation returns an existing conversation or creates a new one
-func (r *MessageRepository) GetOrCreateConversation(ctx context.Context, userOne, userTwo uuid.UUID) (*domain.Conversation, error) {
+// GetOrCreateConversation returns the pair's conversation in the given
+// encryption mode, creating it if needed
+func (r *MessageRepository) GetOrCreateConversation(ctx context.Context, userOne, userTwo uuid.UUID, mode domain.EncryptionMode) (*domain.Conversation, error) {
 	// Ensure consistent ordering (participant_one < participant_two)
 	participantOne, participantTwo := userOne, userTwo
 	if participantOne.String() > participantTwo.String() {
@@ -32,14 +33,16 @@ func (r *MessageRepository) GetOrCreateConversation(ctx context.Context, userOne
 	// Try to get existing conversation
 	conv := &domain.Conversation{}
 	query := `
-		SELECT id, participant_one, participant_two, last_message_at, created_at
+		SELECT id, participant_one, participant_two, encryption_mode, legal_hold, last_message_at, created_at
 		FROM conversations
-		WHERE participant_one = $1 AND participant_two = $2`
+		WHERE participant_one = $1 AND participant_two = $2 AND encryption_mode = $3`
 
-	err := q.QueryRow(ctx, query, participantOne, participantTwo).Scan(
+	err := q.QueryRow(ctx, query, participantOne, participantTwo, mode).Scan(
 		&conv.ID,
 		&conv.ParticipantOne,
 		&conv.ParticipantTwo,
+		&conv.EncryptionMode,
+		&conv.LegalHold,
 		&conv.LastMessageAt,
 		&conv.CreatedAt,
 	)
@@ -57,14 +60,15 @@ func (r *MessageRepository) GetOrCreateConversation(ctx context.Context, userOne
 		ID:             uuid.New(),
 		ParticipantOne: participantOne,
 		ParticipantTwo: participantTwo,
+		EncryptionMode: mode,
 	}
 
 	insertQuery := `
-		INSERT INTO conversations (id, participant_one, participant_two)
-		VALUES ($1, $2, $3)
+		INSERT INTO conversations (id, participant_one, participant_two, encryption_mode)
+		VALUES ($1, $2, $3, $4)
 		RETURNING created_at`
 
-	err = q.QueryRow(ctx, insertQuery, conv.ID, conv.ParticipantOne, conv.ParticipantTwo).Scan(&conv.CreatedAt)
+	err = q.QueryRow(ctx, insertQuery, conv.ID, conv.ParticipantOne, conv.ParticipantTwo, conv.EncryptionMode).Scan(&conv.CreatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create conversation: %w", err)
 	}
@@ -75,7 +79,7 @@ func (r *MessageRepository) GetOrCreateConversation(ctx context.Context, userOne
 // GetConversationByID retrieves a conversation by ID
 func (r *MessageRepository) GetConversationByID(ctx context.Context, id uuid.UUID) (*domain.Conversation, error) {
 	query := `
-		SELECT id, participant_one, participant_two, last_message_at, created_at
+		SELECT id, participant_one, participant_two, encryption_mode, legal_hold, last_message_at, created_at
 		FROM conversations
 		WHERE id = $1`
 
@@ -85,6 +89,8 @@ func (r *MessageRepository) GetConversationByID(ctx context.Context, id uuid.UUI
 		&conv.ID,
 		&conv.ParticipantOne,
 		&conv.ParticipantTwo,
+		&conv.EncryptionMode,
+		&conv.LegalHold,
 		&conv.LastMessageAt,
 		&conv.CreatedAt,
 	)
@@ -102,7 +108,7 @@ func (r *MessageRepository) GetConversationByID(ctx context.Context, id uuid.UUI
 // GetConversationsForUser retrieves all conversations for a user
 func (r *MessageRepository) GetConversationsForUser(ctx context.Context, userID uuid.UUID) ([]domain.Conversation, error) {
 	query := `
-		SELECT id, participant_one, participant_two, last_message_at, created_at
+		SELECT id, participant_one, participant_two, encryption_mode, legal_hold, last_message_at, created_at
 		FROM conversations
 		WHERE participant_one = $1 OR participant_two = $1
 		ORDER BY COALESCE(last_message_at, created_at) DESC`
@@ -121,6 +127,8 @@ func (r *MessageRepository) GetConversationsForUser(ctx context.Context, userID
 			&conv.ID,
 			&conv.ParticipantOne,
 			&conv.ParticipantTwo,
+			&conv.EncryptionMode,
+			&conv.LegalHold,
 			&conv.LastMessageAt,
 			&conv.CreatedAt,
 		)
@@ -133,38 +141,185 @@ func (r *MessageRepository) GetConversationsForUser(ctx context.Context, userID
 	return conversations, nil
 }
 
-// CreateMessage creates a new message
-func (r *MessageRepository) CreateMessage(ctx context.Context, msg *domain.Message) error {
-	if msg.ID == uuid.Nil {
-		msg.ID = uuid.New()
+// conversationDetailsSelect joins a conversation with the other participant,
+// its last message and the unread count for the user in $1
+const conversationDetailsSelect = `
+	SELECT c.id, c.encryption_mode, c.legal_hold, c.last_message_at, c.created_at,
+		LEAST(me.message_retention_days, u.message_retention_days),
+		u.id, u.username, u.avatar_url, u.bio, u.created_at, u.is_deleted,
+		lm.id, lm.sender_id, lm.content_encrypted, lm.content_nonce, lm.key_envelopes, lm.moderation_flags, lm.created_at,
+		COALESCE(rs.unread_count, 0)
+	FROM conversations c
+	JOIN users u ON u.id = CASE WHEN c.participant_one = $1 THEN c.participant_two ELSE c.participant_one END
+	JOIN users me ON me.id = $1
+	LEFT JOIN LATERAL (
+		SELECT m.id, m.sender_id, m.content_encrypted, m.content_nonce, m.key_envelopes, m.moderation_flags, m.created_at
+		FROM messages m
+		WHERE m.conversation_id = c.id
+		ORDER BY m.created_at DESC
+		LIMIT 1
+	) lm ON true
+	LEFT JOIN conversation_read_status rs ON rs.conversation_id = c.id AND rs.user_id = $1
+	WHERE (c.participant_one = $1 OR c.participant_two = $1)`
+
+func scanConversationDetails(row rowScanner, conv *domain.ConversationWithDetails) error {
+	var other domain.PublicUser
+	var msgID, msgSenderID *uuid.UUID
+	var msgContent, msgNonce []byte
+	var msgEnvelopes map[uuid.UUID][]byte
+	var msgFlags []string
+	var msgCreatedAt *time.Time
+
+	err := row.Scan(
+		&conv.ID,
+		&conv.EncryptionMode,
+		&conv.LegalHold,
+		&conv.LastMessageAt,
+		&conv.CreatedAt,
+		&conv.RetentionDays,
+		&other.ID,
+		&other.Username,
+		&other.AvatarURL,
+		&other.Bio,
+		&other.CreatedAt,
+		&other.Deleted,
+		&msgID,
+		&msgSenderID,
+		&msgContent,
+		&msgNonce,
+		&msgEnvelopes,
+		&msgFlags,
+		&msgCreatedAt,
+		&conv.UnreadCount,
+	)
+	if err != nil {
+		return err
 	}
 
-	query := `
-		INSERT INTO messages (id, conversation_id, sender_id, content_encrypted, content_nonce)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING created_at`
+	other.HideIfDeleted()
+	conv.OtherUser = &other
+	if msgID != nil {
+		conv.LastMessage = &domain.Message{
+			ID:               *msgID,
+			ConversationID:   conv.ID,
+			SenderID:         *msgSenderID,
+			ContentEncrypted: msgContent,
+			ContentNonce:     msgNonce,
+			KeyEnvelopes:     msgEnvelopes,
+			ModerationFlags:  msgFlags,
+			CreatedAt:        *msgCreatedAt,
+		}
+	}
+	return nil
+}
 
-	q := r.db.GetQuerier(ctx)
-	err := q.QueryRow(ctx, query,
-		msg.ID,
-		msg.ConversationID,
-		msg.SenderID,
-		msg.ContentEncrypted,
-		msg.ContentNonce,
-	).Scan(&msg.CreatedAt)
+// GetConversationDetailsForUser retrieves all conversations for a user along
+// with the other participant, the last message and the unread count in a
+// single query. The last message is returned still encrypted, and
+// RetentionDays holds the shorter of the participants' own retention
+// settings, without the platform default applied.
+func (r *MessageRepository) GetConversationDetailsForUser(ctx context.Context, userID uuid.UUID) ([]domain.ConversationWithDetails, error) {
+	query := conversationDetailsSelect + `
+		ORDER BY COALESCE(c.last_message_at, c.created_at) DESC`
 
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, userID)
 	if err != nil {
-		return fmt.Errorf("failed to create message: %w", err)
+		return nil, fmt.Errorf("failed to get conversations: %w", err)
+	}
+	defer rows.Close()
+
+	conversations := make([]domain.ConversationWithDetails, 0)
+	for rows.Next() {
+		var conv domain.ConversationWithDetails
+		if err := scanConversationDetails(rows, &conv); err != nil {
+			return nil, fmt.Errorf("failed to scan conversation: %w", err)
+		}
+		conversations = append(conversations, conv)
 	}
 
-	// Update conversation's last_message_at
-	updateQuery := `UPDATE conversations SET last_message_at = $2 WHERE id = $1`
-	_, err = q.Exec(ctx, updateQuery, msg.ConversationID, msg.CreatedAt)
+	return conversations, rows.Err()
+}
+
+// GetConversationDetails retrieves one of the user's conversations the way
+// GetConversationDetailsForUser does
+func (r *MessageRepository) GetConversationDetails(ctx context.Context, conversationID, userID uuid.UUID) (*domain.ConversationWithDetails, error) {
+	query := conversationDetailsSelect + ` AND c.id = $2`
+
+	q := r.db.GetQuerier(ctx)
+	conv := &domain.ConversationWithDetails{}
+	err := scanConversationDetails(q.QueryRow(ctx, query, userID, conversationID), conv)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
 	if err != nil {
-		return fmt.Errorf("failed to update conversation: %w", err)
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
 	}
 
-	return nil
+	return conv, nil
+}
+
+// CreateMessage creates a new message and bumps the recipient's unread
+// counter in the same transaction
+func (r *MessageRepository) CreateMessage(ctx context.Context, msg *domain.Message) error {
+	if msg.ID == uuid.Nil {
+		msg.ID = uuid.New()
+	}
+
+	create := func(ctx context.Context) error {
+		query := `
+			INSERT INTO messages (id, conversation_id, sender_id, content_encrypted, content_nonce, key_envelopes, moderation_flags)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING created_at`
+
+		// Server-encrypted messages have no envelopes; store NULL rather than {}
+		var envelopes interface{}
+		if len(msg.KeyEnvelopes) > 0 {
+			envelopes = msg.KeyEnvelopes
+		}
+
+		q := r.db.GetQuerier(ctx)
+		err := q.QueryRow(ctx, query,
+			msg.ID,
+			msg.ConversationID,
+			msg.SenderID,
+			msg.ContentEncrypted,
+			msg.ContentNonce,
+			envelopes,
+			msg.ModerationFlags,
+		).Scan(&msg.CreatedAt)
+
+		if err != nil {
+			return fmt.Errorf("failed to create message: %w", err)
+		}
+
+		// Update conversation's last_message_at
+		updateQuery := `UPDATE conversations SET last_message_at = $2 WHERE id = $1`
+		_, err = q.Exec(ctx, updateQuery, msg.ConversationID, msg.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to update conversation: %w", err)
+		}
+
+		counterQuery := `
+			INSERT INTO conversation_read_status (conversation_id, user_id, last_read_at, unread_count)
+			SELECT id, CASE WHEN participant_one = $2 THEN participant_two ELSE participant_one END, NULL, 1
+			FROM conversations
+			WHERE id = $1
+			ON CONFLICT (conversation_id, user_id)
+			DO UPDATE SET unread_count = conversation_read_status.unread_count + 1`
+
+		_, err = q.Exec(ctx, counterQuery, msg.ConversationID, msg.SenderID)
+		if err != nil {
+			return fmt.Errorf("failed to update unread count: %w", err)
+		}
+
+		return nil
+	}
+
+	if GetTx(ctx) != nil {
+		return create(ctx)
+	}
+	return r.db.WithTx(ctx, create)
 }
 
 // GetMessagesByConversation retrieves messages for a conversation
@@ -186,7 +341,7 @@ func (r *MessageRepository) GetMessagesByConversation(ctx context.Context, conve
 
 	offset := (page - 1) * limit
 	query := `
-		SELECT id, conversation_id, sender_id, content_encrypted, content_nonce, created_at
+		SELECT id, conversation_id, sender_id, content_encrypted, content_nonce, key_envelopes, moderation_flags, created_at
 		FROM messages
 		WHERE conversation_id = $1
 		ORDER BY created_at DESC
@@ -207,6 +362,8 @@ func (r *MessageRepository) GetMessagesByConversation(ctx context.Context, conve
 			&msg.SenderID,
 			&msg.ContentEncrypted,
 			&msg.ContentNonce,
+			&msg.KeyEnvelopes,
+			&msg.ModerationFlags,
 			&msg.CreatedAt,
 		)
 		if err != nil {
@@ -218,43 +375,146 @@ func (r *MessageRepository) GetMessagesByConversation(ctx context.Context, conve
 	return messages, totalCount, nil
 }
 
-// GetLastMessage retrieves the last message in a conversation
-func (r *MessageRepository) GetLastMessage(ctx context.Context, conversationID uuid.UUID) (*domain.Message, error) {
-	query := `
-		SELECT id, conversation_id, sender_id, content_encrypted, content_nonce, created_at
+const messageColumns = `id, conversation_id, sender_id, content_encrypted, content_nonce, key_envelopes, moderation_flags, created_at`
+
+// GetMessagesWindow retrieves a page of messages by cursor, oldest first. One
+// extra row is fetched in each direction to tell whether more messages exist.
+func (r *MessageRepository) GetMessagesWindow(ctx context.Context, conversationID uuid.UUID, params *domain.MessageWindowParams) (*domain.MessageWindow, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	olderQuery := `
+		SELECT ` + messageColumns + `
 		FROM messages
-		WHERE conversation_id = $1
-		ORDER BY created_at DESC
-		LIMIT 1`
+		WHERE conversation_id = $1 AND (created_at, id) < (SELECT created_at, id FROM messages WHERE id = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3`
 
-	q := r.db.GetQuerier(ctx)
-	msg := &domain.Message{}
-	err := q.QueryRow(ctx, query, conversationID).Scan(
-		&msg.ID,
-		&msg.ConversationID,
-		&msg.SenderID,
-		&msg.ContentEncrypted,
-		&msg.ContentNonce,
-		&msg.CreatedAt,
-	)
+	newerQuery := `
+		SELECT ` + messageColumns + `
+		FROM messages
+		WHERE conversation_id = $1 AND (created_at, id) > (SELECT created_at, id FROM messages WHERE id = $2)
+		ORDER BY created_at ASC, id ASC
+		LIMIT $3`
 
-	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, nil // No messages yet
+	window := &domain.MessageWindow{}
+
+	switch {
+	case params.Before != nil:
+		older, err := r.queryMessages(ctx, olderQuery, conversationID, *params.Before, limit+1)
+		if err != nil {
+			return nil, err
+		}
+		window.HasMoreBefore = len(older) > limit
+		window.HasMoreAfter = true
+		window.Messages = reverseMessages(truncateMessages(older, limit))
+
+	case params.After != nil:
+		newer, err := r.queryMessages(ctx, newerQuery, conversationID, *params.After, limit+1)
+		if err != nil {
+			return nil, err
+		}
+		window.HasMoreBefore = true
+		window.HasMoreAfter = len(newer) > limit
+		window.Messages = truncateMessages(newer, limit)
+
+	case params.Around != nil:
+		olderLimit := limit / 2
+		newerLimit := limit - olderLimit
+
+		older, err := r.queryMessages(ctx, `
+			SELECT `+messageColumns+`
+			FROM messages
+			WHERE conversation_id = $1 AND created_at <= $2
+			ORDER BY created_at DESC, id DESC
+			LIMIT $3`, conversationID, *params.Around, olderLimit+1)
+		if err != nil {
+			return nil, err
+		}
+		newer, err := r.queryMessages(ctx, `
+			SELECT `+messageColumns+`
+			FROM messages
+			WHERE conversation_id = $1 AND created_at > $2
+			ORDER BY created_at ASC, id ASC
+			LIMIT $3`, conversationID, *params.Around, newerLimit+1)
+		if err != nil {
+			return nil, err
+		}
+		window.HasMoreBefore = len(older) > olderLimit
+		window.HasMoreAfter = len(newer) > newerLimit
+		window.Messages = append(reverseMessages(truncateMessages(older, olderLimit)), truncateMessages(newer, newerLimit)...)
+
+	default:
+		latest, err := r.queryMessages(ctx, `
+			SELECT `+messageColumns+`
+			FROM messages
+			WHERE conversation_id = $1
+			ORDER BY created_at DESC, id DESC
+			LIMIT $2`, conversationID, limit+1)
+		if err != nil {
+			return nil, err
+		}
+		window.HasMoreBefore = len(latest) > limit
+		window.Messages = reverseMessages(truncateMessages(latest, limit))
 	}
+
+	return window, nil
+}
+
+func (r *MessageRepository) queryMessages(ctx context.Context, query string, args ...interface{}) ([]domain.Message, error) {
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get last message: %w", err)
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := make([]domain.Message, 0)
+	for rows.Next() {
+		var msg domain.Message
+		err := rows.Scan(
+			&msg.ID,
+			&msg.ConversationID,
+			&msg.SenderID,
+			&msg.ContentEncrypted,
+			&msg.ContentNonce,
+			&msg.KeyEnvelopes,
+			&msg.ModerationFlags,
+			&msg.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan message: %w", err)
+		}
+		messages = append(messages, msg)
 	}
 
-	return msg, nil
+	return messages, rows.Err()
+}
+
+func truncateMessages(messages []domain.Message, limit int) []domain.Message {
+	if len(messages) > limit {
+		return messages[:limit]
+	}
+	return messages
 }
 
-// UpdateReadStatus updates the read status for a user in a conversation
+func reverseMessages(messages []domain.Message) []domain.Message {
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages
+}
+
+// UpdateReadStatus updates the read status for a user in a conversation and
+// clears their unread counter
 func (r *MessageRepository) UpdateReadStatus(ctx context.Context, conversationID, userID uuid.UUID) error {
 	query := `
-		INSERT INTO conversation_read_status (conversation_id, user_id, last_read_at)
-		VALUES ($1, $2, $3)
+		INSERT INTO conversation_read_status (conversation_id, user_id, last_read_at, unread_count)
+		VALUES ($1, $2, $3, 0)
 		ON CONFLICT (conversation_id, user_id)
-		DO UPDATE SET last_read_at = EXCLUDED.last_read_at`
+		DO UPDATE SET last_read_at = EXCLUDED.last_read_at, unread_count = 0`
 
 	q := r.db.GetQuerier(ctx)
 	_, err := q.Exec(ctx, query, conversationID, userID, time.Now())
@@ -268,7 +528,7 @@ func (r *MessageRepository) UpdateReadStatus(ctx context.Context, conversationID
 // GetReadStatus retrieves the read status for a user in a conversation
 func (r *MessageRepository) GetReadStatus(ctx context.Context, conversationID, userID uuid.UUID) (*domain.ConversationReadStatus, error) {
 	query := `
-		SELECT conversation_id, user_id, last_read_at
+		SELECT conversation_id, user_id, last_read_at, unread_count
 		FROM conversation_read_status
 		WHERE conversation_id = $1 AND user_id = $2`
 
@@ -278,6 +538,7 @@ func (r *MessageRepository) GetReadStatus(ctx context.Context, conversationID, u
 		&status.ConversationID,
 		&status.UserID,
 		&status.LastReadAt,
+		&status.UnreadCount,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -290,49 +551,186 @@ func (r *MessageRepository) GetReadStatus(ctx context.Context, conversationID, u
 	return status, nil
 }
 
-// GetUnreadCountForConversation returns the count of unread messages in a conversation
-func (r *MessageRepository) GetUnreadCountForConversation(ctx context.Context, conversationID, userID uuid.UUID) (int, error) {
+// GetTotalUnreadCount returns the total count of unread messages across all conversations
+func (r *MessageRepository) GetTotalUnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
 	query := `
-		SELECT COUNT(*)
-		FROM messages m
-		WHERE m.conversation_id = $1
-			AND m.sender_id != $2
-			AND m.created_at > COALESCE(
-				(SELECT last_read_at FROM conversation_read_status WHERE conversation_id = $1 AND user_id = $2),
-				'1970-01-01'::timestamp
-			)`
+		SELECT COALESCE(SUM(unread_count), 0)
+		FROM conversation_read_status
+		WHERE user_id = $1 AND unread_count > 0`
 
 	q := r.db.GetQuerier(ctx)
 	var count int
-	if err := q.QueryRow(ctx, query, conversationID, userID).Scan(&count); err != nil {
-		return 0, fmt.Errorf("failed to get unread count: %w", err)
+	if err := q.QueryRow(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to get total unread count: %w", err)
 	}
 
 	return count, nil
 }
 
-// GetTotalUnreadCount returns the total count of unread messages across all conversations
-func (r *MessageRepository) GetTotalUnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+// GetUnreadCounts returns the unread counter of every conversation that has
+// unread messages for the user
+func (r *MessageRepository) GetUnreadCounts(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]int, error) {
 	query := `
-		SELECT COUNT(*)
-		FROM messages m
-		JOIN conversations c ON m.conversation_id = c.id
-		WHERE (c.participant_one = $1 OR c.participant_two = $1)
-			AND m.sender_id != $1
-			AND m.created_at > COALESCE(
-				(SELECT last_read_at FROM conversation_read_status WHERE conversation_id = m.conversation_id AND user_id = $1),
-				'1970-01-01'::timestamp
-			)`
+		SELECT conversation_id, unread_count
+		FROM conversation_read_status
+		WHERE user_id = $1 AND unread_count > 0`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unread counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var conversationID uuid.UUID
+		var count int
+		if err := rows.Scan(&conversationID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan unread count: %w", err)
+		}
+		counts[conversationID] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// ReconcileUnreadCounts recounts unread messages from the messages table and
+// corrects any counters that have drifted. Returns the number of corrected rows.
+func (r *MessageRepository) ReconcileUnreadCounts(ctx context.Context) (int64, error) {
+	query := `
+		WITH actual AS (
+			SELECT rs.conversation_id, rs.user_id, COUNT(m.id) AS unread_count
+			FROM conversation_read_status rs
+			LEFT JOIN messages m ON m.conversation_id = rs.conversation_id
+				AND m.sender_id != rs.user_id
+				AND m.created_at > COALESCE(rs.last_read_at, '-infinity'::timestamptz)
+			GROUP BY rs.conversation_id, rs.user_id
+		)
+		UPDATE conversation_read_status rs
+		SET unread_count = actual.unread_count
+		FROM actual
+		WHERE rs.conversation_id = actual.conversation_id
+			AND rs.user_id = actual.user_id
+			AND rs.unread_count != actual.unread_count`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reconcile unread counts: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// DeleteExpiredMessages hard-deletes up to limit messages older than their
+// conversation's retention window: the shortest of defaultDays and both
+// participants' own settings, where a non-positive defaultDays means no
+// platform limit. Conversations on legal hold are skipped.
+func (r *MessageRepository) DeleteExpiredMessages(ctx context.Context, defaultDays, limit int) (int64, error) {
+	query := `
+		DELETE FROM messages
+		WHERE id IN (
+			SELECT m.id
+			FROM messages m
+			JOIN conversations c ON c.id = m.conversation_id
+			JOIN users p1 ON p1.id = c.participant_one
+			JOIN users p2 ON p2.id = c.participant_two
+			WHERE NOT c.legal_hold
+				AND m.created_at < NOW() - make_interval(days => LEAST(NULLIF($1, 0), p1.message_retention_days, p2.message_retention_days))
+			LIMIT $2
+		)`
+
+	if defaultDays < 0 {
+		defaultDays = 0
+	}
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, defaultDays, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired messages: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// legalHoldCondition is true while the conversation's participants are the
+// buyer and seller of an open dispute
+const legalHoldCondition = `EXISTS (
+	SELECT 1 FROM disputes d
+	WHERE d.status = 'open'
+		AND ((d.buyer_id = c.participant_one AND d.seller_id = c.participant_two)
+			OR (d.buyer_id = c.participant_two AND d.seller_id = c.participant_one))
+)`
+
+// SyncLegalHolds sets or clears the legal hold flag of every conversation
+// to match the open disputes. Returns the number of changed conversations.
+func (r *MessageRepository) SyncLegalHolds(ctx context.Context) (int64, error) {
+	query := `
+		UPDATE conversations c
+		SET legal_hold = ` + legalHoldCondition + `
+		WHERE legal_hold != ` + legalHoldCondition
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sync legal holds: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// RefreshLegalHold recomputes the legal hold flag of the conversations
+// between two users
+func (r *MessageRepository) RefreshLegalHold(ctx context.Context, userOne, userTwo uuid.UUID) error {
+	query := `
+		UPDATE conversations c
+		SET legal_hold = ` + legalHoldCondition + `
+		WHERE (participant_one = $1 AND participant_two = $2)
+			OR (participant_one = $2 AND participant_two = $1)`
+
+	q := r.db.GetQuerier(ctx)
+	if _, err := q.Exec(ctx, query, userOne, userTwo); err != nil {
+		return fmt.Errorf("failed to refresh legal hold: %w", err)
+	}
+
+	return nil
+}
+
+// CountFlaggedMessages counts the messages a user sent since the given time
+// that the moderation scanner flagged
+func (r *MessageRepository) CountFlaggedMessages(ctx context.Context, senderID uuid.UUID, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM messages
+		WHERE sender_id = $1 AND created_at >= $2 AND moderation_flags IS NOT NULL`
 
 	q := r.db.GetQuerier(ctx)
 	var count int
-	if err := q.QueryRow(ctx, query, userID).Scan(&count); err != nil {
-		return 0, fmt.Errorf("failed to get total unread count: %w", err)
+	if err := q.QueryRow(ctx, query, senderID, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count flagged messages: %w", err)
 	}
 
 	return count, nil
 }
 
+// HasSentSince reports whether a user sent a message in the conversation at
+// or after the given time
+func (r *MessageRepository) HasSentSince(ctx context.Context, conversationID, senderID uuid.UUID, since time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM messages
+			WHERE conversation_id = $1 AND sender_id = $2 AND created_at >= $3
+		)`
+
+	q := r.db.GetQuerier(ctx)
+	var exists bool
+	if err := q.QueryRow(ctx, query, conversationID, senderID, since).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check sent messages: %w", err)
+	}
+
+	return exists, nil
+}
+
 // IsUserInConversation checks if a user is a participant in a conversation
 func (r *MessageRepository) IsUserInConversation(ctx context.Context, conversationID, userID uuid.UUID) (bool, error) {
 	query := `
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type ModerationRepository struct {
+	db *DB
+}
+
+func NewModerationRepository(db *DB) *ModerationRepository {
+	return &ModerationRepository{db: db}
+}
+
+// Escalate opens a moderation case for the user, or updates the reason and
+// offense count of the case already open
+func (r *ModerationRepository) Escalate(ctx context.Context, userID uuid.UUID, reason string, offenseCount int) (*domain.ModerationCase, error) {
+	query := `
+		INSERT INTO moderation_queue (id, user_id, reason, offense_count)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) WHERE status = 'open'
+		DO UPDATE SET reason = EXCLUDED.reason, offense_count = EXCLUDED.offense_count, updated_at = NOW()
+		RETURNING id, user_id, reason, offense_count, status, reviewed_by, created_at, updated_at`
+
+	q := r.db.GetQuerier(ctx)
+	c := &domain.ModerationCase{}
+	err := q.QueryRow(ctx, query, uuid.New(), userID, reason, offenseCount).Scan(
+		&c.ID,
+		&c.UserID,
+		&c.Reason,
+		&c.OffenseCount,
+		&c.Status,
+		&c.ReviewedBy,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to escalate user: %w", err)
+	}
+
+	return c, nil
+}
+
+func (r *ModerationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ModerationCase, error) {
+	query := `
+		SELECT id, user_id, reason, offense_count, status, reviewed_by, created_at, updated_at
+		FROM moderation_queue
+		WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	c := &domain.ModerationCase{}
+	err := q.QueryRow(ctx, query, id).Scan(
+		&c.ID,
+		&c.UserID,
+		&c.Reason,
+		&c.OffenseCount,
+		&c.Status,
+		&c.ReviewedBy,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get moderation case: %w", err)
+	}
+
+	return c, nil
+}
+
+func (r *ModerationRepository) Update(ctx context.Context, c *domain.ModerationCase) error {
+	query := `
+		UPDATE moderation_queue
+		SET status = $2, reviewed_by = $3, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query, c.ID, c.Status, c.ReviewedBy).Scan(&c.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update moderation case: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ModerationRepository) List(ctx context.Context, params *domain.ModerationCaseListParams) ([]domain.ModerationCase, int, error) {
+	whereClause := ""
+	args := []interface{}{}
+	argIndex := 1
+
+	if params.Status != nil {
+		whereClause = fmt.Sprintf("WHERE mq.status = $%d", argIndex)
+		args = append(args, *params.Status)
+		argIndex++
+	}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM moderation_queue mq %s", whereClause)
+
+	q := r.db.GetQuerier(ctx)
+	var totalCount int
+	if err := q.QueryRow(ctx, countQuery, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count moderation cases: %w", err)
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	page := params.Page
+	if page <= 0 {
+		page = 1
+	}
+	offset := (page - 1) * limit
+
+	args = append(args, limit, offset)
+	listQuery := fmt.Sprintf(`
+		SELECT mq.id, mq.user_id, mq.reason, mq.offense_count, mq.status, mq.reviewed_by, mq.created_at, mq.updated_at,
+			u.id, u.username, u.avatar_url, u.bio, u.created_at
+		FROM moderation_queue mq
+		JOIN users u ON u.id = mq.user_id
+		%s
+		ORDER BY mq.offense_count DESC, mq.updated_at DESC
+		LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
+
+	rows, err := q.Query(ctx, listQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list moderation cases: %w", err)
+	}
+	defer rows.Close()
+
+	cases := make([]domain.ModerationCase, 0)
+	for rows.Next() {
+		var c domain.ModerationCase
+		var user domain.PublicUser
+		err := rows.Scan(
+			&c.ID,
+			&c.UserID,
+			&c.Reason,
+			&c.OffenseCount,
+			&c.Status,
+			&c.ReviewedBy,
+			&c.CreatedAt,
+			&c.UpdatedAt,
+			&user.ID,
+			&user.Username,
+			&user.AvatarURL,
+			&user.Bio,
+			&user.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan moderation case: %w", err)
+		}
+		c.User = &user
+		cases = append(cases, c)
+	}
+
+	return cases, totalCount, rows.Err()
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+type NotificationPreferenceRepository struct {
+	db *DB
+}
+
+func NewNotificationPreferenceRepository(db *DB) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{db: db}
+}
+
+// notificationChannelColumns maps each channel to its column, so only known
+// columns reach the query
+var notificationChannelColumns = map[domain.NotificationChannel]string{
+	domain.NotificationChannelEmail: "email",
+	domain.NotificationChannelInApp: "in_app",
+	domain.NotificationChannelPush:  "push",
+}
+
+func (r *NotificationPreferenceRepository) GetByUser(ctx context.Context, userID uuid.UUID) ([]domain.NotificationPreference, error) {
+	query := `
+		SELECT type, email, in_app, push, updated_at
+		FROM notification_preferences
+		WHERE user_id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	defer rows.Close()
+
+	preferences := make([]domain.NotificationPreference, 0)
+	for rows.Next() {
+		var preference domain.NotificationPreference
+		if err := rows.Scan(&preference.Type, &preference.Email, &preference.InApp, &preference.Push, &preference.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notification preference: %w", err)
+		}
+		preferences = append(preferences, preference)
+	}
+
+	return preferences, rows.Err()
+}
+
+// Save creates or replaces the user's preferences for the types given
+func (r *NotificationPreferenceRepository) Save(ctx context.Context, userID uuid.UUID, preferences []domain.NotificationPreference) error {
+	query := `
+		INSERT INTO notification_preferences (user_id, type, email, in_app, push)
+		SELECT $1, * FROM unnest($2::text[], $3::boolean[], $4::boolean[], $5::boolean[])
+		ON CONFLICT (user_id, type) DO UPDATE SET
+			email = EXCLUDED.email,
+			in_app = EXCLUDED.in_app,
+			push = EXCLUDED.push`
+
+	types := make([]string, len(preferences))
+	email := make([]bool, len(preferences))
+	inApp := make([]bool, len(preferences))
+	push := make([]bool, len(preferences))
+	for i, preference := range preferences {
+		types[i] = string(preference.Type)
+		email[i] = preference.Email
+		inApp[i] = preference.InApp
+		push[i] = preference.Push
+	}
+
+	q := r.db.GetQuerier(ctx)
+	if _, err := q.Exec(ctx, query, userID, types, email, inApp, push); err != nil {
+		return fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+
+	return nil
+}
+
+// OptedOut returns which of the users switched the channel off for the type
+func (r *NotificationPreferenceRepository) OptedOut(ctx context.Context, userIDs []uuid.UUID, notificationType domain.NotificationType, channel domain.NotificationChannel) (map[uuid.UUID]bool, error) {
+	column, ok := notificationChannelColumns[channel]
+	if !ok {
+		return nil, fmt.Errorf("unknown notification channel %q", channel)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT user_id
+		FROM notification_preferences
+		WHERE type = $1 AND user_id = ANY($2) AND NOT %s`, column)
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, notificationType, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification opt-outs: %w", err)
+	}
+	defer rows.Close()
+
+	optedOut := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan notification opt-out: %w", err)
+		}
+		optedOut[userID] = true
+	}
+
+	return optedOut, rows.Err()
+}
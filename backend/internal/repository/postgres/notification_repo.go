@@ -20,13 +20,16 @@ func NewNotificationRepository(db *DB) *NotificationRepository {
 
 func (r *NotificationRepository) Create(ctx context.Context, notification *domain.Notification) error {
 	query := `
-		INSERT INTO notifications (id, user_id, type, title, message, auction_id)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO notifications (id, user_id, type, title, message, auction_id, link, actions)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING created_at`
 
 	if notification.ID == uuid.Nil {
 		notification.ID = uuid.New()
 	}
+	if notification.Actions == nil {
+		notification.Actions = []domain.NotificationAction{}
+	}
 
 	q := r.db.GetQuerier(ctx)
 	err := q.QueryRow(ctx, query,
@@ -36,6 +39,8 @@ func (r *NotificationRepository) Create(ctx context.Context, notification *domai
 		notification.Title,
 		notification.Message,
 		notification.AuctionID,
+		notification.Link,
+		notification.Actions,
 	).Scan(&notification.CreatedAt)
 
 	if err != nil {
@@ -45,32 +50,45 @@ func (r *NotificationRepository) Create(ctx context.Context, notification *domai
 	return nil
 }
 
+var notificationCopyColumns = []string{"id", "user_id", "type", "title", "message", "auction_id", "link", "actions"}
+
+// CreateBatch stores notifications with a single COPY. Outside an existing
+// transaction it opens one, so a batch is stored all-or-nothing.
 func (r *NotificationRepository) CreateBatch(ctx context.Context, notifications []domain.Notification) error {
 	if len(notifications) == 0 {
 		return nil
 	}
 
-	query := `
-		INSERT INTO notifications (id, user_id, type, title, message, auction_id)
-		VALUES ($1, $2, $3, $4, $5, $6)`
-
-	q := r.db.GetQuerier(ctx)
-	for _, n := range notifications {
+	rows := make([][]interface{}, len(notifications))
+	for i := range notifications {
+		n := &notifications[i]
 		if n.ID == uuid.Nil {
 			n.ID = uuid.New()
 		}
-		_, err := q.Exec(ctx, query, n.ID, n.UserID, n.Type, n.Title, n.Message, n.AuctionID)
+		if n.Actions == nil {
+			n.Actions = []domain.NotificationAction{}
+		}
+		rows[i] = []interface{}{n.ID, n.UserID, string(n.Type), n.Title, n.Message, n.AuctionID, n.Link, n.Actions}
+	}
+
+	copyRows := func(ctx context.Context) error {
+		q := r.db.GetQuerier(ctx)
+		_, err := q.CopyFrom(ctx, pgx.Identifier{"notifications"}, notificationCopyColumns, pgx.CopyFromRows(rows))
 		if err != nil {
-			return fmt.Errorf("failed to create notification: %w", err)
+			return fmt.Errorf("failed to create notifications: %w", err)
 		}
+		return nil
 	}
 
-	return nil
+	if GetTx(ctx) != nil {
+		return copyRows(ctx)
+	}
+	return r.db.WithTx(ctx, copyRows)
 }
 
 func (r *NotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Notification, error) {
 	query := `
-		SELECT id, user_id, type, title, message, auction_id, is_read, created_at
+		SELECT id, user_id, type, title, message, auction_id, link, actions, is_read, created_at
 		FROM notifications
 		WHERE id = $1`
 
@@ -83,6 +101,8 @@ func (r *NotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*do
 		&n.Title,
 		&n.Message,
 		&n.AuctionID,
+		&n.Link,
+		&n.Actions,
 		&n.IsRead,
 		&n.CreatedAt,
 	)
@@ -97,6 +117,8 @@ func (r *NotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*do
 	return n, nil
 }
 
+// GetByUserID lists a user's notifications, newest first. With a cursor it
+// seeks past the cursor and skips the total count, which is returned as zero.
 func (r *NotificationRepository) GetByUserID(ctx context.Context, userID uuid.UUID, params *domain.NotificationListParams) ([]domain.Notification, int, int, error) {
 	whereClause := "WHERE user_id = $1"
 	args := []interface{}{userID}
@@ -112,8 +134,10 @@ func (r *NotificationRepository) GetByUserID(ctx context.Context, userID uuid.UU
 	q := r.db.GetQuerier(ctx)
 
 	var totalCount, unreadCount int
-	if err := q.QueryRow(ctx, countQuery, args...).Scan(&totalCount); err != nil {
-		return nil, 0, 0, fmt.Errorf("failed to count notifications: %w", err)
+	if params.Cursor == nil {
+		if err := q.QueryRow(ctx, countQuery, args...).Scan(&totalCount); err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to count notifications: %w", err)
+		}
 	}
 	if err := q.QueryRow(ctx, unreadQuery, userID).Scan(&unreadCount); err != nil {
 		return nil, 0, 0, fmt.Errorf("failed to count unread notifications: %w", err)
@@ -123,19 +147,29 @@ func (r *NotificationRepository) GetByUserID(ctx context.Context, userID uuid.UU
 	if limit <= 0 {
 		limit = 20
 	}
-	page := params.Page
-	if page <= 0 {
-		page = 1
+
+	var pageClause string
+	if params.Cursor != nil {
+		whereClause += fmt.Sprintf(" AND (created_at, id) < ($%d, $%d)", argIndex, argIndex+1)
+		args = append(args, params.Cursor.CreatedAt, params.Cursor.ID, limit)
+		pageClause = fmt.Sprintf("LIMIT $%d", argIndex+2)
+	} else {
+		page := params.Page
+		if page <= 0 {
+			page = 1
+		}
+		offset := (page - 1) * limit
+
+		args = append(args, limit, offset)
+		pageClause = fmt.Sprintf("LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
 	}
-	offset := (page - 1) * limit
 
-	args = append(args, limit, offset)
 	listQuery := fmt.Sprintf(`
-		SELECT id, user_id, type, title, message, auction_id, is_read, created_at
+		SELECT id, user_id, type, title, message, auction_id, link, actions, is_read, created_at
 		FROM notifications
 		%s
-		ORDER BY created_at DESC
-		LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
+		ORDER BY created_at DESC, id DESC
+		%s`, whereClause, pageClause)
 
 	rows, err := q.Query(ctx, listQuery, args...)
 	if err != nil {
@@ -153,6 +187,8 @@ func (r *NotificationRepository) GetByUserID(ctx context.Context, userID uuid.UU
 			&n.Title,
 			&n.Message,
 			&n.AuctionID,
+			&n.Link,
+			&n.Actions,
 			&n.IsRead,
 			&n.CreatedAt,
 		)
@@ -165,6 +201,108 @@ func (r *NotificationRepository) GetByUserID(ctx context.Context, userID uuid.UU
 	return notifications, totalCount, unreadCount, nil
 }
 
+// GetThreadsByUserID lists a user's notification threads: one per auction,
+// and one for each notification not about an auction. Threads are ordered by
+// their newest notification, which the cursor seeks past.
+func (r *NotificationRepository) GetThreadsByUserID(ctx context.Context, userID uuid.UUID, params *domain.NotificationListParams) ([]domain.NotificationThread, int, int, error) {
+	threadsQuery := `
+		WITH threads AS (
+			SELECT COALESCE(auction_id, id) AS thread_id,
+				COUNT(*) AS count,
+				COUNT(*) FILTER (WHERE is_read = FALSE) AS unread_count,
+				(ARRAY_AGG(id ORDER BY created_at DESC, id DESC))[1] AS latest_id
+			FROM notifications
+			WHERE user_id = $1
+			GROUP BY COALESCE(auction_id, id)
+		)`
+	whereClause := "WHERE TRUE"
+	args := []interface{}{userID}
+	argIndex := 2
+
+	if params.Unread != nil && *params.Unread {
+		whereClause += " AND t.unread_count > 0"
+	}
+
+	countQuery := fmt.Sprintf("%s SELECT COUNT(*) FROM threads t %s", threadsQuery, whereClause)
+	unreadQuery := "SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND is_read = FALSE"
+
+	q := r.db.GetQuerier(ctx)
+
+	var totalCount, unreadCount int
+	if params.Cursor == nil {
+		if err := q.QueryRow(ctx, countQuery, args...).Scan(&totalCount); err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to count notification threads: %w", err)
+		}
+	}
+	if err := q.QueryRow(ctx, unreadQuery, userID).Scan(&unreadCount); err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	var pageClause string
+	if params.Cursor != nil {
+		whereClause += fmt.Sprintf(" AND (n.created_at, n.id) < ($%d, $%d)", argIndex, argIndex+1)
+		args = append(args, params.Cursor.CreatedAt, params.Cursor.ID, limit)
+		pageClause = fmt.Sprintf("LIMIT $%d", argIndex+2)
+	} else {
+		page := params.Page
+		if page <= 0 {
+			page = 1
+		}
+		offset := (page - 1) * limit
+
+		args = append(args, limit, offset)
+		pageClause = fmt.Sprintf("LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+	}
+
+	listQuery := fmt.Sprintf(`%s
+		SELECT t.thread_id, t.count, t.unread_count,
+			n.id, n.user_id, n.type, n.title, n.message, n.auction_id, n.link, n.actions, n.is_read, n.created_at
+		FROM threads t
+		JOIN notifications n ON n.id = t.latest_id
+		%s
+		ORDER BY n.created_at DESC, n.id DESC
+		%s`, threadsQuery, whereClause, pageClause)
+
+	rows, err := q.Query(ctx, listQuery, args...)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to list notification threads: %w", err)
+	}
+	defer rows.Close()
+
+	threads := make([]domain.NotificationThread, 0)
+	for rows.Next() {
+		var t domain.NotificationThread
+		n := &t.Latest
+		err := rows.Scan(
+			&t.ID,
+			&t.Count,
+			&t.UnreadCount,
+			&n.ID,
+			&n.UserID,
+			&n.Type,
+			&n.Title,
+			&n.Message,
+			&n.AuctionID,
+			&n.Link,
+			&n.Actions,
+			&n.IsRead,
+			&n.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to scan notification thread: %w", err)
+		}
+		t.AuctionID = n.AuctionID
+		threads = append(threads, t)
+	}
+
+	return threads, totalCount, unreadCount, rows.Err()
+}
+
 func (r *NotificationRepository) MarkAsRead(ctx context.Context, id uuid.UUID) error {
 	query := `UPDATE notifications SET is_read = TRUE WHERE id = $1`
 
@@ -193,6 +331,26 @@ func (r *NotificationRepository) MarkAllAsRead(ctx context.Context, userID uuid.
 	return nil
 }
 
+// MarkThreadAsRead marks a thread read: every notification about the auction
+// with the thread's ID, or the single notification it names
+func (r *NotificationRepository) MarkThreadAsRead(ctx context.Context, userID, threadID uuid.UUID) error {
+	query := `
+		UPDATE notifications SET is_read = TRUE
+		WHERE user_id = $1 AND (auction_id = $2 OR (auction_id IS NULL AND id = $2))`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, userID, threadID)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification thread as read: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
 func (r *NotificationRepository) GetUnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
 	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND is_read = FALSE`
 
@@ -269,7 +427,7 @@ func (r *WatchlistRepository) GetByUser(ctx context.Context, userID uuid.UUID, p
 		       a.id, a.seller_id, a.category_id, a.title, a.description, a.condition,
 		       a.starting_price, a.reserve_price, a.buy_now_price, a.current_price,
 		       a.bid_increment, a.start_time, a.end_time, a.status, a.winner_id,
-		       a.winning_bid_id, a.views_count, a.bid_count, a.version, a.created_at, a.updated_at
+		       a.winning_bid_id, a.views_count, a.watch_count, a.bid_count, a.currency, a.version, a.created_at, a.updated_at
 		FROM watchlist w
 		JOIN auctions a ON w.auction_id = a.id
 		WHERE w.user_id = $1
@@ -292,8 +450,8 @@ func (r *WatchlistRepository) GetByUser(ctx context.Context, userID uuid.UUID, p
 			&auction.Description, &auction.Condition, &auction.StartingPrice,
 			&auction.ReservePrice, &auction.BuyNowPrice, &auction.CurrentPrice,
 			&auction.BidIncrement, &auction.StartTime, &auction.EndTime, &auction.Status,
-			&auction.WinnerID, &auction.WinningBidID, &auction.ViewsCount, &auction.BidCount,
-			&auction.Version, &auction.CreatedAt, &auction.UpdatedAt,
+			&auction.WinnerID, &auction.WinningBidID, &auction.ViewsCount, &auction.WatchCount, &auction.BidCount,
+			&auction.Currency, &auction.Version, &auction.CreatedAt, &auction.UpdatedAt,
 		)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan watchlist item: %w", err)
@@ -317,6 +475,33 @@ func (r *WatchlistRepository) Exists(ctx context.Context, userID, auctionID uuid
 	return exists, nil
 }
 
+// GetWatched reports which of the given auctions the user watches
+func (r *WatchlistRepository) GetWatched(ctx context.Context, userID uuid.UUID, auctionIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	watched := make(map[uuid.UUID]bool)
+	if len(auctionIDs) == 0 {
+		return watched, nil
+	}
+
+	query := `SELECT auction_id FROM watchlist WHERE user_id = $1 AND auction_id = ANY($2)`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, userID, auctionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check watchlist: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var auctionID uuid.UUID
+		if err := rows.Scan(&auctionID); err != nil {
+			return nil, fmt.Errorf("failed to scan watchlist: %w", err)
+		}
+		watched[auctionID] = true
+	}
+
+	return watched, nil
+}
+
 func (r *WatchlistRepository) GetWatchersForAuction(ctx context.Context, auctionID uuid.UUID) ([]uuid.UUID, error) {
 	query := `SELECT user_id FROM watchlist WHERE auction_id = $1`
 
@@ -466,7 +651,7 @@ func (r *RatingRepository) GetByRatedUser(ctx context.Context, ratedUserID uuid.
 	args = append(args, limit, offset)
 	listQuery := fmt.Sprintf(`
 		SELECT r.id, r.auction_id, r.rater_id, r.rated_user_id, r.rating, r.comment, r.type, r.created_at,
-		       u.id, u.username, u.avatar_url, u.bio, u.created_at
+		       u.id, u.username, u.avatar_url, u.bio, u.created_at, u.is_deleted
 		FROM ratings r
 		JOIN users u ON r.rater_id = u.id
 		%s
@@ -486,11 +671,12 @@ func (r *RatingRepository) GetByRatedUser(ctx context.Context, ratedUserID uuid.
 		err := rows.Scan(
 			&rating.ID, &rating.AuctionID, &rating.RaterID, &rating.RatedUserID,
 			&rating.Rating, &rating.Comment, &rating.Type, &rating.CreatedAt,
-			&rater.ID, &rater.Username, &rater.AvatarURL, &rater.Bio, &rater.CreatedAt,
+			&rater.ID, &rater.Username, &rater.AvatarURL, &rater.Bio, &rater.CreatedAt, &rater.Deleted,
 		)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan rating: %w", err)
 		}
+		rater.HideIfDeleted()
 		rating.Rater = rater
 		ratings = append(ratings, rating)
 	}
@@ -498,6 +684,44 @@ func (r *RatingRepository) GetByRatedUser(ctx context.Context, ratedUserID uuid.
 	return ratings, totalCount, nil
 }
 
+// GetByRater returns every rating the user gave, newest first, with the
+// rated user
+func (r *RatingRepository) GetByRater(ctx context.Context, raterID uuid.UUID) ([]domain.Rating, error) {
+	query := `
+		SELECT r.id, r.auction_id, r.rater_id, r.rated_user_id, r.rating, r.comment, r.type, r.created_at,
+		       u.id, u.username, u.avatar_url, u.bio, u.created_at, u.is_deleted
+		FROM ratings r
+		JOIN users u ON r.rated_user_id = u.id
+		WHERE r.rater_id = $1
+		ORDER BY r.created_at DESC`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, raterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list given ratings: %w", err)
+	}
+	defer rows.Close()
+
+	ratings := make([]domain.Rating, 0)
+	for rows.Next() {
+		var rating domain.Rating
+		rated := &domain.PublicUser{}
+		err := rows.Scan(
+			&rating.ID, &rating.AuctionID, &rating.RaterID, &rating.RatedUserID,
+			&rating.Rating, &rating.Comment, &rating.Type, &rating.CreatedAt,
+			&rated.ID, &rated.Username, &rated.AvatarURL, &rated.Bio, &rated.CreatedAt, &rated.Deleted,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rating: %w", err)
+		}
+		rated.HideIfDeleted()
+		rating.RatedUser = rated
+		ratings = append(ratings, rating)
+	}
+
+	return ratings, nil
+}
+
 func (r *RatingRepository) GetUserRatingSummary(ctx context.Context, userID uuid.UUID) (*domain.UserRatingSummary, error) {
 	query := `
 		SELECT
@@ -673,6 +897,25 @@ func (r *ReportRepository) List(ctx context.Context, params *domain.ReportListPa
 }
 
 // OAuthAccountRepository
+const oauthAccountColumns = `id, user_id, provider, provider_user_id, access_token, refresh_token, expires_at,
+	linked_via, provider_email, provider_email_verified, created_at`
+
+func scanOAuthAccount(row rowScanner, account *domain.OAuthAccount) error {
+	return row.Scan(
+		&account.ID,
+		&account.UserID,
+		&account.Provider,
+		&account.ProviderUserID,
+		&account.AccessToken,
+		&account.RefreshToken,
+		&account.ExpiresAt,
+		&account.LinkedVia,
+		&account.ProviderEmail,
+		&account.ProviderEmailVerified,
+		&account.CreatedAt,
+	)
+}
+
 type OAuthAccountRepository struct {
 	db *DB
 }
@@ -683,8 +926,9 @@ func NewOAuthAccountRepository(db *DB) *OAuthAccountRepository {
 
 func (r *OAuthAccountRepository) Create(ctx context.Context, account *domain.OAuthAccount) error {
 	query := `
-		INSERT INTO oauth_accounts (id, user_id, provider, provider_user_id, access_token, refresh_token, expires_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO oauth_accounts (id, user_id, provider, provider_user_id, access_token, refresh_token, expires_at,
+			linked_via, provider_email, provider_email_verified)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING created_at`
 
 	if account.ID == uuid.Nil {
@@ -700,6 +944,9 @@ func (r *OAuthAccountRepository) Create(ctx context.Context, account *domain.OAu
 		account.AccessToken,
 		account.RefreshToken,
 		account.ExpiresAt,
+		account.LinkedVia,
+		account.ProviderEmail,
+		account.ProviderEmailVerified,
 	).Scan(&account.CreatedAt)
 
 	if err != nil {
@@ -710,23 +957,11 @@ func (r *OAuthAccountRepository) Create(ctx context.Context, account *domain.OAu
 }
 
 func (r *OAuthAccountRepository) GetByProviderUserID(ctx context.Context, provider, providerUserID string) (*domain.OAuthAccount, error) {
-	query := `
-		SELECT id, user_id, provider, provider_user_id, access_token, refresh_token, expires_at, created_at
-		FROM oauth_accounts
-		WHERE provider = $1 AND provider_user_id = $2`
+	query := `SELECT ` + oauthAccountColumns + ` FROM oauth_accounts WHERE provider = $1 AND provider_user_id = $2`
 
 	q := r.db.GetQuerier(ctx)
 	account := &domain.OAuthAccount{}
-	err := q.QueryRow(ctx, query, provider, providerUserID).Scan(
-		&account.ID,
-		&account.UserID,
-		&account.Provider,
-		&account.ProviderUserID,
-		&account.AccessToken,
-		&account.RefreshToken,
-		&account.ExpiresAt,
-		&account.CreatedAt,
-	)
+	err := scanOAuthAccount(q.QueryRow(ctx, query, provider, providerUserID), account)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, domain.ErrNotFound
@@ -739,10 +974,7 @@ func (r *OAuthAccountRepository) GetByProviderUserID(ctx context.Context, provid
 }
 
 func (r *OAuthAccountRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.OAuthAccount, error) {
-	query := `
-		SELECT id, user_id, provider, provider_user_id, access_token, refresh_token, expires_at, created_at
-		FROM oauth_accounts
-		WHERE user_id = $1`
+	query := `SELECT ` + oauthAccountColumns + ` FROM oauth_accounts WHERE user_id = $1 ORDER BY created_at`
 
 	q := r.db.GetQuerier(ctx)
 	rows, err := q.Query(ctx, query, userID)
@@ -754,17 +986,7 @@ func (r *OAuthAccountRepository) GetByUserID(ctx context.Context, userID uuid.UU
 	accounts := make([]domain.OAuthAccount, 0)
 	for rows.Next() {
 		var account domain.OAuthAccount
-		err := rows.Scan(
-			&account.ID,
-			&account.UserID,
-			&account.Provider,
-			&account.ProviderUserID,
-			&account.AccessToken,
-			&account.RefreshToken,
-			&account.ExpiresAt,
-			&account.CreatedAt,
-		)
-		if err != nil {
+		if err := scanOAuthAccount(rows, &account); err != nil {
 			return nil, fmt.Errorf("failed to scan oauth account: %w", err)
 		}
 		accounts = append(accounts, account)
@@ -819,8 +1041,8 @@ func NewRefreshTokenRepository(db *DB) *RefreshTokenRepository {
 
 func (r *RefreshTokenRepository) Create(ctx context.Context, token *domain.RefreshToken) error {
 	query := `
-		INSERT INTO refresh_tokens (id, user_id, token_hash, expires_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO refresh_tokens (id, user_id, token_hash, expires_at, device_id)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING created_at`
 
 	if token.ID == uuid.Nil {
@@ -833,6 +1055,7 @@ func (r *RefreshTokenRepository) Create(ctx context.Context, token *domain.Refre
 		token.UserID,
 		token.TokenHash,
 		token.ExpiresAt,
+		token.DeviceID,
 	).Scan(&token.CreatedAt)
 
 	if err != nil {
@@ -844,7 +1067,7 @@ func (r *RefreshTokenRepository) Create(ctx context.Context, token *domain.Refre
 
 func (r *RefreshTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
 	query := `
-		SELECT id, user_id, token_hash, expires_at, created_at
+		SELECT id, user_id, token_hash, expires_at, device_id, created_at
 		FROM refresh_tokens
 		WHERE token_hash = $1 AND expires_at > NOW()`
 
@@ -855,6 +1078,7 @@ func (r *RefreshTokenRepository) GetByTokenHash(ctx context.Context, tokenHash s
 		&token.UserID,
 		&token.TokenHash,
 		&token.ExpiresAt,
+		&token.DeviceID,
 		&token.CreatedAt,
 	)
 
@@ -892,6 +1116,20 @@ func (r *RefreshTokenRepository) DeleteByUserID(ctx context.Context, userID uuid
 	return nil
 }
 
+// DeleteByUserIDExcept revokes every session of the user but the one with
+// keepHash, returning how many were revoked
+func (r *RefreshTokenRepository) DeleteByUserIDExcept(ctx context.Context, userID uuid.UUID, keepHash string) (int64, error) {
+	query := `DELETE FROM refresh_tokens WHERE user_id = $1 AND token_hash <> $2`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, userID, keepHash)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete refresh tokens: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
 func (r *RefreshTokenRepository) DeleteExpired(ctx context.Context) error {
 	query := `DELETE FROM refresh_tokens WHERE expires_at < NOW()`
 
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Benchmarks run against a migrated database named by TEST_DATABASE_URL:
+//
+//	TEST_DATABASE_URL=postgres://... go test -run '^$' -bench Notification ./internal/repository/postgres
+func newBenchDB(b *testing.B) (*DB, uuid.UUID) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		b.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := NewDB(dsn)
+	if err != nil {
+		b.Fatalf("failed to connect: %v", err)
+	}
+
+	userID := uuid.New()
+	_, err = db.Pool.Exec(context.Background(),
+		`INSERT INTO users (id, email, username) VALUES ($1, $2, $3)`,
+		userID, userID.String()+"@bench.test", "bench_"+userID.String()[:8])
+	if err != nil {
+		b.Fatalf("failed to create user: %v", err)
+	}
+
+	b.Cleanup(func() {
+		_, _ = db.Pool.Exec(context.Background(), `DELETE FROM users WHERE id = $1`, userID)
+		db.Close()
+	})
+
+	return db, userID
+}
+
+func benchNotifications(userID uuid.UUID, n int) []domain.Notification {
+	notifications := make([]domain.Notification, n)
+	for i := range notifications {
+		notifications[i] = domain.Notification{
+			UserID: userID,
+			Type:   domain.NotificationAuctionEnding,
+			Title:  "Auction ending soon: Benchmark",
+		}
+	}
+	return notifications
+}
+
+// createPerRow is the old CreateBatch, one INSERT round trip per notification
+func createPerRow(ctx context.Context, db *DB, notifications []domain.Notification) error {
+	query := `
+		INSERT INTO notifications (id, user_id, type, title, message, auction_id)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	for _, n := range notifications {
+		if _, err := db.Pool.Exec(ctx, query, uuid.New(), n.UserID, n.Type, n.Title, n.Message, n.AuctionID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func BenchmarkNotificationCreateBatch(b *testing.B) {
+	db, userID := newBenchDB(b)
+	repo := NewNotificationRepository(db)
+	ctx := context.Background()
+
+	for _, size := range []int{100, 1000, 5000, 10000} {
+		b.Run(fmt.Sprintf("copy/%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := repo.CreateBatch(ctx, benchNotifications(userID, size)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("per_row/%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := createPerRow(ctx, db, benchNotifications(userID, size)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const offerColumns = `id, auction_id, buyer_id, amount, counter_amount, message, status, bid_id,
+	expires_at, responded_at, created_at, updated_at`
+
+func scanOffer(row rowScanner, o *domain.Offer) error {
+	return row.Scan(
+		&o.ID,
+		&o.AuctionID,
+		&o.BuyerID,
+		&o.Amount,
+		&o.CounterAmount,
+		&o.Message,
+		&o.Status,
+		&o.BidID,
+		&o.ExpiresAt,
+		&o.RespondedAt,
+		&o.CreatedAt,
+		&o.UpdatedAt,
+	)
+}
+
+type OfferRepository struct {
+	db *DB
+}
+
+func NewOfferRepository(db *DB) *OfferRepository {
+	return &OfferRepository{db: db}
+}
+
+func (r *OfferRepository) Create(ctx context.Context, offer *domain.Offer) error {
+	query := `
+		INSERT INTO offers (id, auction_id, buyer_id, amount, message, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at, updated_at`
+
+	if offer.ID == uuid.Nil {
+		offer.ID = uuid.New()
+	}
+	if offer.Status == "" {
+		offer.Status = domain.OfferStatusPending
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		offer.ID,
+		offer.AuctionID,
+		offer.BuyerID,
+		offer.Amount,
+		offer.Message,
+		offer.Status,
+		offer.ExpiresAt,
+	).Scan(&offer.CreatedAt, &offer.UpdatedAt)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrOfferExists
+		}
+		return fmt.Errorf("failed to create offer: %w", err)
+	}
+
+	return nil
+}
+
+func (r *OfferRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Offer, error) {
+	query := `SELECT ` + offerColumns + ` FROM offers WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	offer := &domain.Offer{}
+	err := scanOffer(q.QueryRow(ctx, query, id), offer)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get offer: %w", err)
+	}
+
+	return offer, nil
+}
+
+// GetByAuction returns the offers on an auction, newest first, limited to
+// one buyer's when buyerID is set
+func (r *OfferRepository) GetByAuction(ctx context.Context, auctionID uuid.UUID, buyerID *uuid.UUID) ([]domain.Offer, error) {
+	query := `SELECT ` + offerColumns + ` FROM offers
+		WHERE auction_id = $1 AND ($2::uuid IS NULL OR buyer_id = $2)
+		ORDER BY created_at DESC`
+
+	return r.query(ctx, query, auctionID, buyerID)
+}
+
+func (r *OfferRepository) GetByBuyer(ctx context.Context, buyerID uuid.UUID, status *domain.OfferStatus) ([]domain.Offer, error) {
+	query := `SELECT ` + offerColumns + ` FROM offers
+		WHERE buyer_id = $1 AND ($2::varchar IS NULL OR status = $2)
+		ORDER BY created_at DESC
+		LIMIT 100`
+
+	return r.query(ctx, query, buyerID, status)
+}
+
+// CountByBuyer returns how many offers the buyer has made on the auction
+func (r *OfferRepository) CountByBuyer(ctx context.Context, auctionID, buyerID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM offers WHERE auction_id = $1 AND buyer_id = $2`
+
+	q := r.db.GetQuerier(ctx)
+	var count int
+	if err := q.QueryRow(ctx, query, auctionID, buyerID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count offers: %w", err)
+	}
+
+	return count, nil
+}
+
+// Counter records the seller's counter to a pending offer and gives the
+// buyer until offer.ExpiresAt to answer
+func (r *OfferRepository) Counter(ctx context.Context, offer *domain.Offer) error {
+	query := `
+		UPDATE offers
+		SET status = 'countered', counter_amount = $2, expires_at = $3, responded_at = NOW()
+		WHERE id = $1 AND status = 'pending' AND expires_at > NOW()
+		RETURNING ` + offerColumns
+
+	q := r.db.GetQuerier(ctx)
+	err := scanOffer(q.QueryRow(ctx, query, offer.ID, offer.CounterAmount, offer.ExpiresAt), offer)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrOfferNotPending
+	}
+	if err != nil {
+		return fmt.Errorf("failed to counter offer: %w", err)
+	}
+
+	return nil
+}
+
+// Respond moves an unexpired offer from one status to another, linking the
+// bid it was accepted as if there is one. It returns ErrOfferNotPending when
+// the offer was already answered or has lapsed.
+func (r *OfferRepository) Respond(ctx context.Context, offer *domain.Offer, from domain.OfferStatus) error {
+	query := `
+		UPDATE offers
+		SET status = $3, bid_id = $4, responded_at = NOW()
+		WHERE id = $1 AND status = $2 AND expires_at > NOW()
+		RETURNING ` + offerColumns
+
+	q := r.db.GetQuerier(ctx)
+	err := scanOffer(q.QueryRow(ctx, query, offer.ID, from, offer.Status, offer.BidID), offer)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrOfferNotPending
+	}
+	if err != nil {
+		return fmt.Errorf("failed to respond to offer: %w", err)
+	}
+
+	return nil
+}
+
+// ExpireOpen expires every open offer on an auction, e.g. once another
+// offer was accepted, and returns them
+func (r *OfferRepository) ExpireOpen(ctx context.Context, auctionID uuid.UUID) ([]domain.Offer, error) {
+	query := `
+		UPDATE offers SET status = 'expired'
+		WHERE auction_id = $1 AND status IN ('pending', 'countered')
+		RETURNING ` + offerColumns
+
+	return r.query(ctx, query, auctionID)
+}
+
+// ExpireStale expires up to limit open offers that ran out of time or whose
+// auction is no longer active, and returns them
+func (r *OfferRepository) ExpireStale(ctx context.Context, now time.Time, limit int) ([]domain.Offer, error) {
+	query := `
+		UPDATE offers SET status = 'expired'
+		WHERE id IN (
+			SELECT o.id FROM offers o
+			JOIN auctions a ON o.auction_id = a.id
+			WHERE o.status IN ('pending', 'countered')
+			  AND (o.expires_at <= $1 OR a.status <> 'active')
+			ORDER BY o.expires_at
+			LIMIT $2
+			FOR UPDATE OF o SKIP LOCKED
+		)
+		RETURNING ` + offerColumns
+
+	return r.query(ctx, query, now, limit)
+}
+
+func (r *OfferRepository) query(ctx context.Context, query string, args ...interface{}) ([]domain.Offer, error) {
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get offers: %w", err)
+	}
+	defer rows.Close()
+
+	offers := make([]domain.Offer, 0)
+	for rows.Next() {
+		var offer domain.Offer
+		if err := scanOffer(rows, &offer); err != nil {
+			return nil, fmt.Errorf("failed to scan offer: %w", err)
+		}
+		offers = append(offers, offer)
+	}
+
+	return offers, nil
+}
@@ -0,0 +1,385 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const orderColumns = `id, auction_id, buyer_id, seller_id, bid_id, amount, status, cancel_reason,
+	payment_due_at, paid_at, created_at, updated_at`
+
+func scanOrder(row rowScanner, order *domain.Order) error {
+	return row.Scan(
+		&order.ID,
+		&order.AuctionID,
+		&order.BuyerID,
+		&order.SellerID,
+		&order.BidID,
+		&order.Amount,
+		&order.Status,
+		&order.CancelReason,
+		&order.PaymentDueAt,
+		&order.PaidAt,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	)
+}
+
+type OrderRepository struct {
+	db *DB
+}
+
+func NewOrderRepository(db *DB) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+func (r *OrderRepository) Create(ctx context.Context, order *domain.Order) error {
+	query := `
+		INSERT INTO orders (id, auction_id, buyer_id, seller_id, bid_id, amount, status, payment_due_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at, updated_at`
+
+	if order.ID == uuid.Nil {
+		order.ID = uuid.New()
+	}
+	if order.Status == "" {
+		order.Status = domain.OrderStatusPendingPayment
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		order.ID,
+		order.AuctionID,
+		order.BuyerID,
+		order.SellerID,
+		order.BidID,
+		order.Amount,
+		order.Status,
+		order.PaymentDueAt,
+	).Scan(&order.CreatedAt, &order.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create order: %w", err)
+	}
+
+	return nil
+}
+
+func (r *OrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	order := &domain.Order{}
+	err := scanOrder(q.QueryRow(ctx, query, id), order)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	return order, nil
+}
+
+func (r *OrderRepository) List(ctx context.Context, params *domain.OrderListParams) ([]domain.Order, int, error) {
+	whereConditions := []string{}
+	args := []interface{}{params.UserID}
+	argIndex := 2
+
+	switch params.Role {
+	case "buyer":
+		whereConditions = append(whereConditions, "buyer_id = $1")
+	case "seller":
+		whereConditions = append(whereConditions, "seller_id = $1")
+	default:
+		whereConditions = append(whereConditions, "(buyer_id = $1 OR seller_id = $1)")
+	}
+
+	if params.Status != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("status = $%d", argIndex))
+		args = append(args, *params.Status)
+		argIndex++
+	}
+
+	whereClause := " WHERE " + strings.Join(whereConditions, " AND ")
+
+	q := r.db.GetQuerier(ctx)
+	var totalCount int
+	if err := q.QueryRow(ctx, "SELECT COUNT(*) FROM orders"+whereClause, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	page := params.Page
+	if page <= 0 {
+		page = 1
+	}
+	offset := (page - 1) * limit
+
+	args = append(args, limit, offset)
+	listQuery := fmt.Sprintf(`SELECT %s FROM orders%s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
+		orderColumns, whereClause, argIndex, argIndex+1)
+
+	rows, err := q.Query(ctx, listQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := make([]domain.Order, 0)
+	for rows.Next() {
+		var order domain.Order
+		if err := scanOrder(rows, &order); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan order: %w", err)
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, totalCount, nil
+}
+
+// GetOverdue returns pending orders whose payment deadline has passed
+func (r *OrderRepository) GetOverdue(ctx context.Context, before time.Time, limit int) ([]domain.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders
+		WHERE status = 'pending_payment' AND payment_due_at <= $1
+		ORDER BY payment_due_at
+		LIMIT $2`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overdue orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := make([]domain.Order, 0)
+	for rows.Next() {
+		var order domain.Order
+		if err := scanOrder(rows, &order); err != nil {
+			return nil, fmt.Errorf("failed to scan order: %w", err)
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+func (r *OrderRepository) MarkPaid(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE orders SET status = 'paid', paid_at = NOW()
+		WHERE id = $1 AND status = 'pending_payment'`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark order paid: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrOrderNotPending
+	}
+
+	return nil
+}
+
+func (r *OrderRepository) Cancel(ctx context.Context, id uuid.UUID, reason string) error {
+	query := `
+		UPDATE orders SET status = 'cancelled', cancel_reason = $2
+		WHERE id = $1 AND status = 'pending_payment'`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, id, reason)
+	if err != nil {
+		return fmt.Errorf("failed to cancel order: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrOrderNotPending
+	}
+
+	return nil
+}
+
+func (r *OrderRepository) AddUnpaidStrike(ctx context.Context, userID, orderID uuid.UUID) error {
+	query := `
+		INSERT INTO unpaid_strikes (user_id, order_id)
+		VALUES ($1, $2)
+		ON CONFLICT (order_id) DO NOTHING`
+
+	q := r.db.GetQuerier(ctx)
+	_, err := q.Exec(ctx, query, userID, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to record unpaid strike: %w", err)
+	}
+
+	return nil
+}
+
+// SecondChanceOfferRepository
+type SecondChanceOfferRepository struct {
+	db *DB
+}
+
+func NewSecondChanceOfferRepository(db *DB) *SecondChanceOfferRepository {
+	return &SecondChanceOfferRepository{db: db}
+}
+
+const secondChanceOfferColumns = `id, auction_id, order_id, bidder_id, bid_id, amount, status,
+	expires_at, responded_at, created_at`
+
+func scanSecondChanceOffer(row rowScanner, offer *domain.SecondChanceOffer) error {
+	return row.Scan(
+		&offer.ID,
+		&offer.AuctionID,
+		&offer.OrderID,
+		&offer.BidderID,
+		&offer.BidID,
+		&offer.Amount,
+		&offer.Status,
+		&offer.ExpiresAt,
+		&offer.RespondedAt,
+		&offer.CreatedAt,
+	)
+}
+
+func (r *SecondChanceOfferRepository) Create(ctx context.Context, offer *domain.SecondChanceOffer) error {
+	query := `
+		INSERT INTO second_chance_offers (id, auction_id, order_id, bidder_id, bid_id, amount, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at`
+
+	if offer.ID == uuid.Nil {
+		offer.ID = uuid.New()
+	}
+	if offer.Status == "" {
+		offer.Status = domain.SecondChanceOfferPending
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		offer.ID,
+		offer.AuctionID,
+		offer.OrderID,
+		offer.BidderID,
+		offer.BidID,
+		offer.Amount,
+		offer.Status,
+		offer.ExpiresAt,
+	).Scan(&offer.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create second chance offer: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SecondChanceOfferRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.SecondChanceOffer, error) {
+	query := `SELECT ` + secondChanceOfferColumns + ` FROM second_chance_offers WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	offer := &domain.SecondChanceOffer{}
+	err := scanSecondChanceOffer(q.QueryRow(ctx, query, id), offer)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get second chance offer: %w", err)
+	}
+
+	return offer, nil
+}
+
+func (r *SecondChanceOfferRepository) GetByBidder(ctx context.Context, bidderID uuid.UUID, status *domain.SecondChanceOfferStatus) ([]domain.SecondChanceOffer, error) {
+	query := `SELECT ` + secondChanceOfferColumns + ` FROM second_chance_offers
+		WHERE bidder_id = $1 AND ($2::varchar IS NULL OR status = $2)
+		ORDER BY created_at DESC`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, bidderID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get second chance offers: %w", err)
+	}
+	defer rows.Close()
+
+	offers := make([]domain.SecondChanceOffer, 0)
+	for rows.Next() {
+		var offer domain.SecondChanceOffer
+		if err := scanSecondChanceOffer(rows, &offer); err != nil {
+			return nil, fmt.Errorf("failed to scan second chance offer: %w", err)
+		}
+		offers = append(offers, offer)
+	}
+
+	return offers, nil
+}
+
+func (r *SecondChanceOfferRepository) ExistsForAuction(ctx context.Context, auctionID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM second_chance_offers WHERE auction_id = $1)`
+
+	q := r.db.GetQuerier(ctx)
+	var exists bool
+	if err := q.QueryRow(ctx, query, auctionID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check second chance offers: %w", err)
+	}
+
+	return exists, nil
+}
+
+// GetExpired returns pending offers whose response window has passed
+func (r *SecondChanceOfferRepository) GetExpired(ctx context.Context, before time.Time, limit int) ([]domain.SecondChanceOffer, error) {
+	query := `SELECT ` + secondChanceOfferColumns + ` FROM second_chance_offers
+		WHERE status = 'pending' AND expires_at <= $1
+		ORDER BY expires_at
+		LIMIT $2`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired offers: %w", err)
+	}
+	defer rows.Close()
+
+	offers := make([]domain.SecondChanceOffer, 0)
+	for rows.Next() {
+		var offer domain.SecondChanceOffer
+		if err := scanSecondChanceOffer(rows, &offer); err != nil {
+			return nil, fmt.Errorf("failed to scan second chance offer: %w", err)
+		}
+		offers = append(offers, offer)
+	}
+
+	return offers, nil
+}
+
+// Respond moves a pending offer to a final status. It returns
+// ErrOfferNotPending if the offer was already answered or expired.
+func (r *SecondChanceOfferRepository) Respond(ctx context.Context, id uuid.UUID, status domain.SecondChanceOfferStatus) error {
+	query := `
+		UPDATE second_chance_offers SET status = $2, responded_at = NOW()
+		WHERE id = $1 AND status = 'pending'`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, id, status)
+	if err != nil {
+		return fmt.Errorf("failed to update second chance offer: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrOfferNotPending
+	}
+
+	return nil
+}
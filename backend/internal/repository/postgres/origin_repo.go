@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type AllowedOriginRepository struct {
+	db *DB
+}
+
+func NewAllowedOriginRepository(db *DB) *AllowedOriginRepository {
+	return &AllowedOriginRepository{db: db}
+}
+
+func (r *AllowedOriginRepository) Create(ctx context.Context, origin *domain.AllowedOrigin) error {
+	query := `
+		INSERT INTO allowed_origins (id, origin, description, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at`
+
+	if origin.ID == uuid.Nil {
+		origin.ID = uuid.New()
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		origin.ID,
+		origin.Origin,
+		origin.Description,
+		origin.CreatedBy,
+	).Scan(&origin.CreatedAt)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrConflict
+		}
+		return fmt.Errorf("failed to create allowed origin: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AllowedOriginRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AllowedOrigin, error) {
+	query := `SELECT id, origin, description, created_by, created_at FROM allowed_origins WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	origin := &domain.AllowedOrigin{}
+	err := q.QueryRow(ctx, query, id).Scan(
+		&origin.ID,
+		&origin.Origin,
+		&origin.Description,
+		&origin.CreatedBy,
+		&origin.CreatedAt,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allowed origin: %w", err)
+	}
+
+	return origin, nil
+}
+
+func (r *AllowedOriginRepository) List(ctx context.Context) ([]domain.AllowedOrigin, error) {
+	query := `SELECT id, origin, description, created_by, created_at FROM allowed_origins ORDER BY origin`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list allowed origins: %w", err)
+	}
+	defer rows.Close()
+
+	origins := make([]domain.AllowedOrigin, 0)
+	for rows.Next() {
+		var origin domain.AllowedOrigin
+		err := rows.Scan(
+			&origin.ID,
+			&origin.Origin,
+			&origin.Description,
+			&origin.CreatedBy,
+			&origin.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan allowed origin: %w", err)
+		}
+		origins = append(origins, origin)
+	}
+
+	return origins, rows.Err()
+}
+
+func (r *AllowedOriginRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM allowed_origins WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete allowed origin: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
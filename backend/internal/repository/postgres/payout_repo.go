@@ -0,0 +1,245 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const payoutColumns = `id, user_id, amount, status, method_type, account_holder, iban_last4, iban_encrypted, iban_nonce,
+	stripe_account_id, reference, rejection_reason, reviewed_by, created_at, approved_at, completed_at, rejected_at`
+
+func scanPayout(row rowScanner, p *domain.Payout) error {
+	return row.Scan(
+		&p.ID,
+		&p.UserID,
+		&p.Amount,
+		&p.Status,
+		&p.MethodType,
+		&p.AccountHolder,
+		&p.IBANLast4,
+		&p.IBANEncrypted,
+		&p.IBANNonce,
+		&p.StripeAccountID,
+		&p.Reference,
+		&p.RejectionReason,
+		&p.ReviewedBy,
+		&p.CreatedAt,
+		&p.ApprovedAt,
+		&p.CompletedAt,
+		&p.RejectedAt,
+	)
+}
+
+type PayoutRepository struct {
+	db *DB
+}
+
+func NewPayoutRepository(db *DB) *PayoutRepository {
+	return &PayoutRepository{db: db}
+}
+
+func (r *PayoutRepository) GetMethod(ctx context.Context, userID uuid.UUID) (*domain.PayoutMethod, error) {
+	query := `
+		SELECT user_id, type, account_holder, iban_last4, iban_encrypted, iban_nonce, stripe_account_id, created_at, updated_at
+		FROM payout_methods WHERE user_id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	m := &domain.PayoutMethod{}
+	err := q.QueryRow(ctx, query, userID).Scan(
+		&m.UserID,
+		&m.Type,
+		&m.AccountHolder,
+		&m.IBANLast4,
+		&m.IBANEncrypted,
+		&m.IBANNonce,
+		&m.StripeAccountID,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payout method: %w", err)
+	}
+
+	return m, nil
+}
+
+// UpsertMethod replaces the user's payout method
+func (r *PayoutRepository) UpsertMethod(ctx context.Context, method *domain.PayoutMethod) error {
+	query := `
+		INSERT INTO payout_methods (user_id, type, account_holder, iban_last4, iban_encrypted, iban_nonce, stripe_account_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id) DO UPDATE
+		SET type = EXCLUDED.type, account_holder = EXCLUDED.account_holder, iban_last4 = EXCLUDED.iban_last4,
+		    iban_encrypted = EXCLUDED.iban_encrypted, iban_nonce = EXCLUDED.iban_nonce,
+		    stripe_account_id = EXCLUDED.stripe_account_id, updated_at = NOW()
+		RETURNING created_at, updated_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		method.UserID,
+		method.Type,
+		method.AccountHolder,
+		method.IBANLast4,
+		method.IBANEncrypted,
+		method.IBANNonce,
+		method.StripeAccountID,
+	).Scan(&method.CreatedAt, &method.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save payout method: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PayoutRepository) Create(ctx context.Context, payout *domain.Payout) error {
+	query := `
+		INSERT INTO payouts (id, user_id, amount, status, method_type, account_holder, iban_last4, iban_encrypted, iban_nonce, stripe_account_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING created_at`
+
+	if payout.ID == uuid.Nil {
+		payout.ID = uuid.New()
+	}
+	if payout.Status == "" {
+		payout.Status = domain.PayoutRequested
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		payout.ID,
+		payout.UserID,
+		payout.Amount,
+		payout.Status,
+		payout.MethodType,
+		payout.AccountHolder,
+		payout.IBANLast4,
+		payout.IBANEncrypted,
+		payout.IBANNonce,
+		payout.StripeAccountID,
+	).Scan(&payout.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create payout: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PayoutRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Payout, error) {
+	query := `SELECT ` + payoutColumns + ` FROM payouts WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	payout := &domain.Payout{}
+	err := scanPayout(q.QueryRow(ctx, query, id), payout)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payout: %w", err)
+	}
+
+	return payout, nil
+}
+
+func (r *PayoutRepository) List(ctx context.Context, params *domain.PayoutListParams) ([]domain.Payout, int, error) {
+	whereConditions := []string{}
+	args := []interface{}{}
+	argIndex := 1
+
+	if params.UserID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("user_id = $%d", argIndex))
+		args = append(args, *params.UserID)
+		argIndex++
+	}
+
+	if params.Status != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("status = $%d", argIndex))
+		args = append(args, *params.Status)
+		argIndex++
+	}
+
+	whereClause := ""
+	if len(whereConditions) > 0 {
+		whereClause = " WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	q := r.db.GetQuerier(ctx)
+	var totalCount int
+	if err := q.QueryRow(ctx, "SELECT COUNT(*) FROM payouts"+whereClause, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count payouts: %w", err)
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	page := params.Page
+	if page <= 0 {
+		page = 1
+	}
+	offset := (page - 1) * limit
+
+	args = append(args, limit, offset)
+	listQuery := fmt.Sprintf(`SELECT %s FROM payouts%s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
+		payoutColumns, whereClause, argIndex, argIndex+1)
+
+	rows, err := q.Query(ctx, listQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list payouts: %w", err)
+	}
+	defer rows.Close()
+
+	payouts := make([]domain.Payout, 0)
+	for rows.Next() {
+		var payout domain.Payout
+		if err := scanPayout(rows, &payout); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan payout: %w", err)
+		}
+		payouts = append(payouts, payout)
+	}
+
+	return payouts, totalCount, rows.Err()
+}
+
+// Transition moves a payout on from the given status, recording who
+// reviewed it and when. It fails with ErrPayoutStatus if the payout has
+// moved on already.
+func (r *PayoutRepository) Transition(ctx context.Context, payout *domain.Payout, from domain.PayoutStatus) error {
+	query := `
+		UPDATE payouts
+		SET status = $3,
+		    reviewed_by = $4,
+		    reference = COALESCE($5, reference),
+		    rejection_reason = COALESCE($6, rejection_reason),
+		    approved_at = CASE WHEN $3 = 'approved' THEN NOW() ELSE approved_at END,
+		    completed_at = CASE WHEN $3 = 'completed' THEN NOW() ELSE completed_at END,
+		    rejected_at = CASE WHEN $3 = 'rejected' THEN NOW() ELSE rejected_at END
+		WHERE id = $1 AND status = $2
+		RETURNING approved_at, completed_at, rejected_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		payout.ID,
+		from,
+		payout.Status,
+		payout.ReviewedBy,
+		payout.Reference,
+		payout.RejectionReason,
+	).Scan(&payout.ApprovedAt, &payout.CompletedAt, &payout.RejectedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrPayoutStatus
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update payout: %w", err)
+	}
+
+	return nil
+}
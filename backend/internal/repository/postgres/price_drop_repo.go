@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const priceDropRuleColumns = `auction_id, percent, interval_days, floor_price, next_drop_at, created_at, updated_at`
+
+func scanPriceDropRule(row rowScanner, rule *domain.PriceDropRule) error {
+	return row.Scan(
+		&rule.AuctionID,
+		&rule.Percent,
+		&rule.IntervalDays,
+		&rule.FloorPrice,
+		&rule.NextDropAt,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+}
+
+type PriceDropRepository struct {
+	db *DB
+}
+
+func NewPriceDropRepository(db *DB) *PriceDropRepository {
+	return &PriceDropRepository{db: db}
+}
+
+func (r *PriceDropRepository) GetRule(ctx context.Context, auctionID uuid.UUID) (*domain.PriceDropRule, error) {
+	query := `SELECT ` + priceDropRuleColumns + ` FROM price_drop_rules WHERE auction_id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	rule := &domain.PriceDropRule{}
+	err := scanPriceDropRule(q.QueryRow(ctx, query, auctionID), rule)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price drop rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// SaveRule creates the auction's rule or replaces it
+func (r *PriceDropRepository) SaveRule(ctx context.Context, rule *domain.PriceDropRule) error {
+	query := `
+		INSERT INTO price_drop_rules (auction_id, percent, interval_days, floor_price, next_drop_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (auction_id) DO UPDATE SET
+			percent = EXCLUDED.percent,
+			interval_days = EXCLUDED.interval_days,
+			floor_price = EXCLUDED.floor_price,
+			next_drop_at = EXCLUDED.next_drop_at
+		RETURNING created_at, updated_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		rule.AuctionID,
+		rule.Percent,
+		rule.IntervalDays,
+		rule.FloorPrice,
+		rule.NextDropAt,
+	).Scan(&rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save price drop rule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PriceDropRepository) DeleteRule(ctx context.Context, auctionID uuid.UUID) error {
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, `DELETE FROM price_drop_rules WHERE auction_id = $1`, auctionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete price drop rule: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// ClaimDue returns the rule whose markdown has been due longest, locked so
+// other instances skip it. It returns ErrNotFound when none is due.
+func (r *PriceDropRepository) ClaimDue(ctx context.Context, now time.Time) (*domain.PriceDropRule, error) {
+	query := `
+		SELECT ` + priceDropRuleColumns + `
+		FROM price_drop_rules
+		WHERE next_drop_at <= $1
+		ORDER BY next_drop_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED`
+
+	q := r.db.GetQuerier(ctx)
+	rule := &domain.PriceDropRule{}
+	err := scanPriceDropRule(q.QueryRow(ctx, query, now), rule)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim price drop rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+func (r *PriceDropRepository) CreateDrop(ctx context.Context, drop *domain.PriceDrop) error {
+	query := `
+		INSERT INTO auction_price_drops (id, auction_id, old_price, new_price)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at`
+
+	if drop.ID == uuid.Nil {
+		drop.ID = uuid.New()
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		drop.ID,
+		drop.AuctionID,
+		drop.OldPrice,
+		drop.NewPrice,
+	).Scan(&drop.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create price drop: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PriceDropRepository) ListDrops(ctx context.Context, auctionID uuid.UUID) ([]domain.PriceDrop, error) {
+	query := `
+		SELECT id, auction_id, old_price, new_price, created_at
+		FROM auction_price_drops
+		WHERE auction_id = $1
+		ORDER BY created_at`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list price drops: %w", err)
+	}
+	defer rows.Close()
+
+	drops := make([]domain.PriceDrop, 0)
+	for rows.Next() {
+		var drop domain.PriceDrop
+		if err := rows.Scan(
+			&drop.ID,
+			&drop.AuctionID,
+			&drop.OldPrice,
+			&drop.NewPrice,
+			&drop.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan price drop: %w", err)
+		}
+		drops = append(drops, drop)
+	}
+
+	return drops, rows.Err()
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+const pushSubscriptionColumns = `id, user_id, endpoint, p256dh, auth, user_agent, expires_at, created_at`
+
+type PushSubscriptionRepository struct {
+	db *DB
+}
+
+func NewPushSubscriptionRepository(db *DB) *PushSubscriptionRepository {
+	return &PushSubscriptionRepository{db: db}
+}
+
+// Save stores the subscription, taking its endpoint over from whoever had
+// it, and drops the user's oldest beyond their newest keep
+func (r *PushSubscriptionRepository) Save(ctx context.Context, subscription *domain.PushSubscription, keep int) error {
+	query := `
+		INSERT INTO push_subscriptions (id, user_id, endpoint, p256dh, auth, user_agent, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (endpoint) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			p256dh = EXCLUDED.p256dh,
+			auth = EXCLUDED.auth,
+			user_agent = EXCLUDED.user_agent,
+			expires_at = EXCLUDED.expires_at,
+			created_at = NOW()
+		RETURNING id, created_at`
+
+	if subscription.ID == uuid.Nil {
+		subscription.ID = uuid.New()
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		subscription.ID,
+		subscription.UserID,
+		subscription.Endpoint,
+		subscription.P256dh,
+		subscription.Auth,
+		subscription.UserAgent,
+		subscription.ExpiresAt,
+	).Scan(&subscription.ID, &subscription.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save push subscription: %w", err)
+	}
+
+	if keep > 0 {
+		trim := `
+			DELETE FROM push_subscriptions
+			WHERE user_id = $1 AND id NOT IN (
+				SELECT id FROM push_subscriptions
+				WHERE user_id = $1
+				ORDER BY created_at DESC
+				LIMIT $2
+			)`
+		if _, err := q.Exec(ctx, trim, subscription.UserID, keep); err != nil {
+			return fmt.Errorf("failed to trim push subscriptions: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ListByUsers returns the subscriptions of each of the users, newest first
+func (r *PushSubscriptionRepository) ListByUsers(ctx context.Context, userIDs []uuid.UUID) ([]domain.PushSubscription, error) {
+	query := `
+		SELECT ` + pushSubscriptionColumns + `
+		FROM push_subscriptions
+		WHERE user_id = ANY($1)
+		ORDER BY created_at DESC`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list push subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]domain.PushSubscription, 0)
+	for rows.Next() {
+		var s domain.PushSubscription
+		if err := rows.Scan(&s.ID, &s.UserID, &s.Endpoint, &s.P256dh, &s.Auth, &s.UserAgent, &s.ExpiresAt, &s.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan push subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, s)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func (r *PushSubscriptionRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, `DELETE FROM push_subscriptions WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete push subscription: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteByIDs removes subscriptions found to be gone or expired
+func (r *PushSubscriptionRepository) DeleteByIDs(ctx context.Context, ids []uuid.UUID) error {
+	q := r.db.GetQuerier(ctx)
+	if _, err := q.Exec(ctx, `DELETE FROM push_subscriptions WHERE id = ANY($1)`, ids); err != nil {
+		return fmt.Errorf("failed to prune push subscriptions: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type QuietHoursRepository struct {
+	db *DB
+}
+
+func NewQuietHoursRepository(db *DB) *QuietHoursRepository {
+	return &QuietHoursRepository{db: db}
+}
+
+func (r *QuietHoursRepository) Get(ctx context.Context, userID uuid.UUID) (*domain.QuietHours, error) {
+	query := `
+		SELECT user_id, enabled, start_time, end_time, timezone, allow_critical, created_at, updated_at
+		FROM notification_quiet_hours
+		WHERE user_id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	quietHours := &domain.QuietHours{}
+	err := q.QueryRow(ctx, query, userID).Scan(
+		&quietHours.UserID,
+		&quietHours.Enabled,
+		&quietHours.Start,
+		&quietHours.End,
+		&quietHours.Timezone,
+		&quietHours.AllowCritical,
+		&quietHours.CreatedAt,
+		&quietHours.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiet hours: %w", err)
+	}
+
+	return quietHours, nil
+}
+
+// Save creates the user's quiet hours or replaces them
+func (r *QuietHoursRepository) Save(ctx context.Context, quietHours *domain.QuietHours) error {
+	query := `
+		INSERT INTO notification_quiet_hours (user_id, enabled, start_time, end_time, timezone, allow_critical)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			start_time = EXCLUDED.start_time,
+			end_time = EXCLUDED.end_time,
+			timezone = EXCLUDED.timezone,
+			allow_critical = EXCLUDED.allow_critical
+		RETURNING created_at, updated_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		quietHours.UserID,
+		quietHours.Enabled,
+		quietHours.Start,
+		quietHours.End,
+		quietHours.Timezone,
+		quietHours.AllowCritical,
+	).Scan(&quietHours.CreatedAt, &quietHours.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save quiet hours: %w", err)
+	}
+
+	return nil
+}
+
+func (r *QuietHoursRepository) Hold(ctx context.Context, held *domain.HeldNotification) error {
+	query := `
+		INSERT INTO held_notifications (user_id, notification_type, subject, body, release_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		held.UserID,
+		held.Type,
+		held.Subject,
+		held.Body,
+		held.ReleaseAt,
+	).Scan(&held.ID, &held.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to hold notification: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseDue removes and returns up to limit held notifications due by the
+// given time, oldest first. Rows locked by another instance are skipped, so
+// each is sent once.
+func (r *QuietHoursRepository) ReleaseDue(ctx context.Context, before time.Time, limit int) ([]domain.HeldNotification, error) {
+	query := `
+		DELETE FROM held_notifications
+		WHERE id IN (
+			SELECT id FROM held_notifications
+			WHERE release_at <= $1
+			ORDER BY created_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, user_id, notification_type, subject, body, release_at, created_at`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to release held notifications: %w", err)
+	}
+	defer rows.Close()
+
+	held := make([]domain.HeldNotification, 0)
+	for rows.Next() {
+		var h domain.HeldNotification
+		if err := rows.Scan(&h.ID, &h.UserID, &h.Type, &h.Subject, &h.Body, &h.ReleaseAt, &h.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan held notification: %w", err)
+		}
+		held = append(held, h)
+	}
+
+	// DELETE ... RETURNING has no order, so restore it
+	sort.Slice(held, func(i, j int) bool { return held[i].CreatedAt.Before(held[j].CreatedAt) })
+	return held, nil
+}
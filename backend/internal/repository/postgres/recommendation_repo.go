@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// recommendableConditions keeps recommendations to public auctions open
+// for bids whose seller isn't hiding their listings for a vacation
+const recommendableConditions = `a.status = 'active' AND a.visibility = 'public' AND a.end_time > NOW()
+	AND NOT EXISTS (
+		SELECT 1 FROM users s
+		WHERE s.id = a.seller_id AND s.vacation_mode AND s.vacation_listing_mode = 'hide'
+			AND NOW() >= s.vacation_starts_at AND NOW() < s.vacation_ends_at
+	)`
+
+type RecommendationRepository struct {
+	db *DB
+}
+
+func NewRecommendationRepository(db *DB) *RecommendationRepository {
+	return &RecommendationRepository{db: db}
+}
+
+// GetSimilarCandidates ranks auctions in the same category or sharing
+// title words with the query's auction. The category counts most, then the
+// title, how close the price is, and a matching condition or grader.
+func (r *RecommendationRepository) GetSimilarCandidates(ctx context.Context, query *domain.SimilarAuctionQuery, limit int) ([]uuid.UUID, error) {
+	sql := `
+		SELECT a.id
+		FROM auctions a
+		WHERE a.id <> $1 AND ` + recommendableConditions + `
+			AND (a.category_id = $2 OR ($6 <> '' AND to_tsvector('english', a.title) @@ to_tsquery('english', $6)))
+		ORDER BY
+			(CASE WHEN a.category_id = $2 THEN 3 ELSE 0 END)
+			+ (CASE WHEN $6 <> '' THEN 4 * ts_rank(to_tsvector('english', a.title), to_tsquery('english', $6)) ELSE 0 END)
+			+ 2 * GREATEST(0, 1 - ABS(a.current_price - $5) / GREATEST($5, 1))::float8
+			+ (CASE WHEN a.condition = $3 THEN 1 ELSE 0 END)
+			+ (CASE WHEN a.cert_grader = $4 THEN 1 ELSE 0 END) DESC,
+			a.end_time ASC
+		LIMIT $7`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, sql,
+		query.AuctionID,
+		query.CategoryID,
+		query.Condition,
+		query.CertGrader,
+		query.Price,
+		query.TitleQuery,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get similar auctions: %w", err)
+	}
+
+	return collectIDs(rows)
+}
+
+// GetRecommendedCandidates ranks auctions for a user by the categories and
+// prices of what they bid on in the last 90 days and watch, with bids
+// counting double, then by popularity. Users without history get the most
+// popular auctions. Their own auctions and ones they already bid on or
+// watch are left out.
+func (r *RecommendationRepository) GetRecommendedCandidates(ctx context.Context, userID uuid.UUID, limit int) ([]uuid.UUID, error) {
+	sql := `
+		WITH interest AS (
+			SELECT a.category_id, a.current_price, 2 AS weight
+			FROM bids b
+			JOIN auctions a ON a.id = b.auction_id
+			WHERE b.bidder_id = $1 AND b.cancelled_at IS NULL AND b.created_at > NOW() - INTERVAL '90 days'
+			UNION ALL
+			SELECT a.category_id, a.current_price, 1
+			FROM watchlist w
+			JOIN auctions a ON a.id = w.auction_id
+			WHERE w.user_id = $1
+		),
+		categories AS (
+			SELECT category_id, SUM(weight)::float8 / (SELECT SUM(weight) FROM interest) AS affinity
+			FROM interest
+			WHERE category_id IS NOT NULL
+			GROUP BY category_id
+		),
+		price AS (
+			SELECT percentile_cont(0.5) WITHIN GROUP (ORDER BY current_price) AS median FROM interest
+		)
+		SELECT a.id
+		FROM auctions a
+		LEFT JOIN categories c ON c.category_id = a.category_id
+		CROSS JOIN price p
+		WHERE ` + recommendableConditions + `
+			AND a.seller_id <> $1
+			AND NOT EXISTS (SELECT 1 FROM watchlist w WHERE w.user_id = $1 AND w.auction_id = a.id)
+			AND NOT EXISTS (SELECT 1 FROM bids b WHERE b.bidder_id = $1 AND b.auction_id = a.id)
+		ORDER BY
+			3 * COALESCE(c.affinity, 0)
+			+ (CASE WHEN p.median IS NULL THEN 0
+				ELSE 2 * GREATEST(0, 1 - ABS(a.current_price::float8 - p.median) / GREATEST(p.median, 1)) END)
+			+ 0.5 * LN(1 + a.watch_count + a.bid_count) DESC,
+			a.end_time ASC
+		LIMIT $2`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, sql, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recommended auctions: %w", err)
+	}
+
+	return collectIDs(rows)
+}
+
+func collectIDs(rows pgx.Rows) ([]uuid.UUID, error) {
+	defer rows.Close()
+
+	ids := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan auction ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
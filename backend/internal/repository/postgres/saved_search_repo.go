@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+type SavedSearchRepository struct {
+	db *DB
+}
+
+func NewSavedSearchRepository(db *DB) *SavedSearchRepository {
+	return &SavedSearchRepository{db: db}
+}
+
+func (r *SavedSearchRepository) Create(ctx context.Context, search *domain.SavedSearch) error {
+	query := `
+		INSERT INTO saved_searches (id, user_id, name, query)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at`
+
+	if search.ID == uuid.Nil {
+		search.ID = uuid.New()
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query, search.ID, search.UserID, search.Name, search.Query).Scan(&search.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create saved search: %w", err)
+	}
+
+	return nil
+}
+
+// ListByUser returns the user's saved searches, newest first
+func (r *SavedSearchRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.SavedSearch, error) {
+	query := `
+		SELECT id, user_id, name, query, created_at
+		FROM saved_searches
+		WHERE user_id = $1
+		ORDER BY created_at DESC`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved searches: %w", err)
+	}
+	defer rows.Close()
+
+	searches := make([]domain.SavedSearch, 0)
+	for rows.Next() {
+		var s domain.SavedSearch
+		if err := rows.Scan(&s.ID, &s.UserID, &s.Name, &s.Query, &s.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan saved search: %w", err)
+		}
+		searches = append(searches, s)
+	}
+
+	return searches, rows.Err()
+}
+
+func (r *SavedSearchRepository) CountByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	q := r.db.GetQuerier(ctx)
+	if err := q.QueryRow(ctx, `SELECT COUNT(*) FROM saved_searches WHERE user_id = $1`, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count saved searches: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *SavedSearchRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, `DELETE FROM saved_searches WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
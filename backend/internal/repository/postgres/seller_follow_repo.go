@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type SellerFollowRepository struct {
+	db *DB
+}
+
+func NewSellerFollowRepository(db *DB) *SellerFollowRepository {
+	return &SellerFollowRepository{db: db}
+}
+
+// Follow follows the seller, reporting false if the user already did
+func (r *SellerFollowRepository) Follow(ctx context.Context, follow *domain.SellerFollow) (bool, error) {
+	query := `
+		INSERT INTO seller_follows (user_id, seller_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, seller_id) DO NOTHING
+		RETURNING created_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query, follow.UserID, follow.SellerID).Scan(&follow.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to follow seller: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *SellerFollowRepository) Unfollow(ctx context.Context, userID, sellerID uuid.UUID) error {
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, `DELETE FROM seller_follows WHERE user_id = $1 AND seller_id = $2`, userID, sellerID)
+	if err != nil {
+		return fmt.Errorf("failed to unfollow seller: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// List returns the sellers userID follows, newest first
+func (r *SellerFollowRepository) List(ctx context.Context, userID uuid.UUID, page, limit int) ([]domain.SellerFollow, int, error) {
+	countQuery := `SELECT COUNT(*) FROM seller_follows WHERE user_id = $1`
+	listQuery := `
+		SELECT f.user_id, f.seller_id, f.created_at,
+		       u.id, u.username, u.avatar_url, u.bio, u.created_at, u.is_deleted
+		FROM seller_follows f
+		JOIN users u ON u.id = f.seller_id
+		WHERE f.user_id = $1
+		ORDER BY f.created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	q := r.db.GetQuerier(ctx)
+
+	var totalCount int
+	if err := q.QueryRow(ctx, countQuery, userID).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count seller follows: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	rows, err := q.Query(ctx, listQuery, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list seller follows: %w", err)
+	}
+	defer rows.Close()
+
+	follows := make([]domain.SellerFollow, 0)
+	for rows.Next() {
+		var follow domain.SellerFollow
+		seller := &domain.PublicUser{}
+		err := rows.Scan(
+			&follow.UserID,
+			&follow.SellerID,
+			&follow.CreatedAt,
+			&seller.ID,
+			&seller.Username,
+			&seller.AvatarURL,
+			&seller.Bio,
+			&seller.CreatedAt,
+			&seller.Deleted,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan seller follow: %w", err)
+		}
+		seller.HideIfDeleted()
+		follow.Seller = seller
+		follows = append(follows, follow)
+	}
+
+	return follows, totalCount, nil
+}
@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+// AuctionShippingRepository
+type AuctionShippingRepository struct {
+	db *DB
+}
+
+func NewAuctionShippingRepository(db *DB) *AuctionShippingRepository {
+	return &AuctionShippingRepository{db: db}
+}
+
+func (r *AuctionShippingRepository) GetByAuctionID(ctx context.Context, auctionID uuid.UUID) ([]domain.AuctionShipping, error) {
+	query := `
+		SELECT id, auction_id, method, cost, regions, created_at
+		FROM auction_shipping
+		WHERE auction_id = $1
+		ORDER BY cost, created_at`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipping options: %w", err)
+	}
+	defer rows.Close()
+
+	options := make([]domain.AuctionShipping, 0)
+	for rows.Next() {
+		var opt domain.AuctionShipping
+		if err := rows.Scan(&opt.ID, &opt.AuctionID, &opt.Method, &opt.Cost, &opt.Regions, &opt.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan shipping option: %w", err)
+		}
+		options = append(options, opt)
+	}
+
+	return options, nil
+}
+
+// GetByAuctionIDs returns the shipping options of several auctions at once,
+// keyed by auction
+func (r *AuctionShippingRepository) GetByAuctionIDs(ctx context.Context, auctionIDs []uuid.UUID) (map[uuid.UUID][]domain.AuctionShipping, error) {
+	query := `
+		SELECT id, auction_id, method, cost, regions, created_at
+		FROM auction_shipping
+		WHERE auction_id = ANY($1)
+		ORDER BY cost, created_at`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, auctionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipping options: %w", err)
+	}
+	defer rows.Close()
+
+	options := make(map[uuid.UUID][]domain.AuctionShipping)
+	for rows.Next() {
+		var opt domain.AuctionShipping
+		if err := rows.Scan(&opt.ID, &opt.AuctionID, &opt.Method, &opt.Cost, &opt.Regions, &opt.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan shipping option: %w", err)
+		}
+		options[opt.AuctionID] = append(options[opt.AuctionID], opt)
+	}
+
+	return options, nil
+}
+
+// ReplaceForAuction swaps the full set of shipping options for an auction
+// atomically, joining the caller's transaction if there is one.
+func (r *AuctionShippingRepository) ReplaceForAuction(ctx context.Context, auctionID uuid.UUID, options []domain.AuctionShipping) error {
+	if GetTx(ctx) == nil {
+		return r.db.WithTx(ctx, func(txCtx context.Context) error {
+			return r.ReplaceForAuction(txCtx, auctionID, options)
+		})
+	}
+
+	q := r.db.GetQuerier(ctx)
+
+	if _, err := q.Exec(ctx, `DELETE FROM auction_shipping WHERE auction_id = $1`, auctionID); err != nil {
+		return fmt.Errorf("failed to clear shipping options: %w", err)
+	}
+
+	query := `
+		INSERT INTO auction_shipping (id, auction_id, method, cost, regions)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at`
+
+	for i := range options {
+		opt := &options[i]
+		if opt.ID == uuid.Nil {
+			opt.ID = uuid.New()
+		}
+		opt.AuctionID = auctionID
+		if opt.Regions == nil {
+			opt.Regions = []string{}
+		}
+
+		err := q.QueryRow(ctx, query, opt.ID, opt.AuctionID, opt.Method, opt.Cost, opt.Regions).Scan(&opt.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create shipping option: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const snipeColumns = `id, auction_id, user_id, max_amount, lead_seconds, status, bid_id,
+	failure_reason, executed_at, created_at, updated_at`
+
+func scanSnipe(row rowScanner, s *domain.Snipe) error {
+	return row.Scan(
+		&s.ID,
+		&s.AuctionID,
+		&s.UserID,
+		&s.MaxAmount,
+		&s.LeadSeconds,
+		&s.Status,
+		&s.BidID,
+		&s.FailureReason,
+		&s.ExecutedAt,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+}
+
+type SnipeRepository struct {
+	db *DB
+}
+
+func NewSnipeRepository(db *DB) *SnipeRepository {
+	return &SnipeRepository{db: db}
+}
+
+// Upsert schedules a snipe, replacing the amount and lead time of the user's
+// pending snipe on the same auction if there is one
+func (r *SnipeRepository) Upsert(ctx context.Context, snipe *domain.Snipe) error {
+	query := `
+		INSERT INTO snipes (id, auction_id, user_id, max_amount, lead_seconds, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (auction_id, user_id) WHERE status = 'scheduled'
+		DO UPDATE SET max_amount = EXCLUDED.max_amount, lead_seconds = EXCLUDED.lead_seconds
+		RETURNING id, created_at, updated_at`
+
+	if snipe.ID == uuid.Nil {
+		snipe.ID = uuid.New()
+	}
+	snipe.Status = domain.SnipeStatusScheduled
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		snipe.ID,
+		snipe.AuctionID,
+		snipe.UserID,
+		snipe.MaxAmount,
+		snipe.LeadSeconds,
+		snipe.Status,
+	).Scan(&snipe.ID, &snipe.CreatedAt, &snipe.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to schedule snipe: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SnipeRepository) GetScheduled(ctx context.Context, auctionID, userID uuid.UUID) (*domain.Snipe, error) {
+	query := `SELECT ` + snipeColumns + ` FROM snipes
+		WHERE auction_id = $1 AND user_id = $2 AND status = 'scheduled'`
+
+	q := r.db.GetQuerier(ctx)
+	snipe := &domain.Snipe{}
+	err := scanSnipe(q.QueryRow(ctx, query, auctionID, userID), snipe)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snipe: %w", err)
+	}
+
+	return snipe, nil
+}
+
+func (r *SnipeRepository) GetByUser(ctx context.Context, userID uuid.UUID) ([]domain.Snipe, error) {
+	query := `SELECT ` + snipeColumns + ` FROM snipes WHERE user_id = $1 ORDER BY created_at DESC LIMIT 100`
+
+	return r.query(ctx, query, userID)
+}
+
+// GetDue returns scheduled snipes whose auction ends within their lead time.
+// The due time follows the auction's current end time, so anti-sniping
+// extensions push pending snipes back automatically.
+func (r *SnipeRepository) GetDue(ctx context.Context, now time.Time) ([]domain.Snipe, error) {
+	query := `
+		SELECT s.id, s.auction_id, s.user_id, s.max_amount, s.lead_seconds, s.status, s.bid_id,
+		       s.failure_reason, s.executed_at, s.created_at, s.updated_at
+		FROM snipes s
+		JOIN auctions a ON s.auction_id = a.id
+		WHERE s.status = 'scheduled'
+		  AND a.end_time - make_interval(secs => s.lead_seconds) <= $1
+		ORDER BY a.end_time ASC`
+
+	return r.query(ctx, query, now)
+}
+
+func (r *SnipeRepository) query(ctx context.Context, query string, args ...interface{}) ([]domain.Snipe, error) {
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snipes: %w", err)
+	}
+	defer rows.Close()
+
+	snipes := make([]domain.Snipe, 0)
+	for rows.Next() {
+		var snipe domain.Snipe
+		if err := scanSnipe(rows, &snipe); err != nil {
+			return nil, fmt.Errorf("failed to scan snipe: %w", err)
+		}
+		snipes = append(snipes, snipe)
+	}
+
+	return snipes, nil
+}
+
+// Claim moves a scheduled snipe to executing. It returns false when another
+// worker already claimed it, so each snipe fires at most once.
+func (r *SnipeRepository) Claim(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `UPDATE snipes SET status = 'executing' WHERE id = $1 AND status = 'scheduled'`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim snipe: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+// Complete records the outcome of an executing snipe
+func (r *SnipeRepository) Complete(ctx context.Context, snipe *domain.Snipe) error {
+	query := `
+		UPDATE snipes
+		SET status = $2, bid_id = $3, failure_reason = $4, executed_at = $5
+		WHERE id = $1 AND status = 'executing'`
+
+	q := r.db.GetQuerier(ctx)
+	_, err := q.Exec(ctx, query, snipe.ID, snipe.Status, snipe.BidID, snipe.FailureReason, snipe.ExecutedAt)
+	if err != nil {
+		return fmt.Errorf("failed to complete snipe: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SnipeRepository) Cancel(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE snipes SET status = 'cancelled' WHERE id = $1 AND status = 'scheduled'`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to cancel snipe: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
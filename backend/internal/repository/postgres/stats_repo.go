@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+type AuctionStatsRepository struct {
+	db *DB
+}
+
+func NewAuctionStatsRepository(db *DB) *AuctionStatsRepository {
+	return &AuctionStatsRepository{db: db}
+}
+
+func (r *AuctionStatsRepository) GetViewsByDay(ctx context.Context, auctionID uuid.UUID) ([]domain.DailyCount, error) {
+	query := `
+		SELECT date_trunc('day', viewed_at) AS day, COUNT(*)
+		FROM auction_views
+		WHERE auction_id = $1
+		GROUP BY day
+		ORDER BY day`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get views by day: %w", err)
+	}
+	defer rows.Close()
+
+	days := make([]domain.DailyCount, 0)
+	for rows.Next() {
+		var day domain.DailyCount
+		if err := rows.Scan(&day.Date, &day.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan views by day: %w", err)
+		}
+		days = append(days, day)
+	}
+
+	return days, nil
+}
+
+func (r *AuctionStatsRepository) GetUniqueBidderCount(ctx context.Context, auctionID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(DISTINCT bidder_id) FROM bids WHERE auction_id = $1 AND cancelled_at IS NULL`
+
+	q := r.db.GetQuerier(ctx)
+	var count int
+	if err := q.QueryRow(ctx, query, auctionID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unique bidders: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *AuctionStatsRepository) GetBidHistory(ctx context.Context, auctionID uuid.UUID) ([]domain.BidPoint, error) {
+	query := `
+		SELECT amount, created_at
+		FROM bids
+		WHERE auction_id = $1 AND cancelled_at IS NULL
+		ORDER BY created_at ASC`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bid history: %w", err)
+	}
+	defer rows.Close()
+
+	points := make([]domain.BidPoint, 0)
+	for rows.Next() {
+		var point domain.BidPoint
+		if err := rows.Scan(&point.Amount, &point.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan bid point: %w", err)
+		}
+		points = append(points, point)
+	}
+
+	return points, nil
+}
+
+// GetShareStats counts visits and converted bidders for each share of the
+// auction. A bidder is credited to the share they last came through before
+// their first bid that still stands.
+func (r *AuctionStatsRepository) GetShareStats(ctx context.Context, auctionID uuid.UUID) ([]domain.ListingShareStats, error) {
+	query := `
+		WITH first_bids AS (
+			SELECT bidder_id, MIN(created_at) AS first_bid_at
+			FROM bids
+			WHERE auction_id = $1 AND cancelled_at IS NULL
+			GROUP BY bidder_id
+		),
+		attributed AS (
+			SELECT DISTINCT ON (fb.bidder_id) fb.bidder_id, c.share_id
+			FROM first_bids fb
+			JOIN listing_share_clicks c ON c.user_id = fb.bidder_id AND c.created_at <= fb.first_bid_at
+			JOIN listing_shares s ON s.id = c.share_id AND s.auction_id = $1
+			ORDER BY fb.bidder_id, c.created_at DESC
+		)
+		SELECT s.code, s.sharer_id, u.username,
+			(SELECT COUNT(*) FROM listing_share_clicks c WHERE c.share_id = s.id) AS clicks,
+			(SELECT COUNT(*) FROM attributed ab WHERE ab.share_id = s.id),
+			EXISTS (
+				SELECT 1 FROM attributed ab
+				JOIN auctions a ON a.id = s.auction_id
+				WHERE ab.share_id = s.id AND ab.bidder_id = a.winner_id
+			),
+			s.created_at
+		FROM listing_shares s
+		JOIN users u ON u.id = s.sharer_id
+		WHERE s.auction_id = $1
+		ORDER BY clicks DESC, s.created_at`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share stats: %w", err)
+	}
+	defer rows.Close()
+
+	shares := make([]domain.ListingShareStats, 0)
+	for rows.Next() {
+		var share domain.ListingShareStats
+		if err := rows.Scan(
+			&share.Code, &share.SharerID, &share.SharerUsername,
+			&share.Clicks, &share.Conversions, &share.LedToSale, &share.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan share stats: %w", err)
+		}
+		shares = append(shares, share)
+	}
+
+	return shares, rows.Err()
+}
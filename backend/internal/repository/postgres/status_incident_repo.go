@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type StatusIncidentRepository struct {
+	db *DB
+}
+
+func NewStatusIncidentRepository(db *DB) *StatusIncidentRepository {
+	return &StatusIncidentRepository{db: db}
+}
+
+const statusIncidentColumns = `id, title, message, status, impact, components, created_by, created_at, updated_at, resolved_at`
+
+func (r *StatusIncidentRepository) Create(ctx context.Context, incident *domain.StatusIncident) error {
+	query := `
+		INSERT INTO status_incidents (title, message, status, impact, components, created_by, resolved_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		incident.Title,
+		incident.Message,
+		incident.Status,
+		incident.Impact,
+		componentNames(incident.Components),
+		incident.CreatedBy,
+		incident.ResolvedAt,
+	).Scan(&incident.ID, &incident.CreatedAt, &incident.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create status incident: %w", err)
+	}
+
+	return nil
+}
+
+func (r *StatusIncidentRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.StatusIncident, error) {
+	query := `SELECT ` + statusIncidentColumns + ` FROM status_incidents WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	incident, err := scanStatusIncident(q.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status incident: %w", err)
+	}
+
+	return incident, nil
+}
+
+func (r *StatusIncidentRepository) Update(ctx context.Context, incident *domain.StatusIncident) error {
+	query := `
+		UPDATE status_incidents
+		SET title = $2, message = $3, status = $4, impact = $5, components = $6, resolved_at = $7, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		incident.ID,
+		incident.Title,
+		incident.Message,
+		incident.Status,
+		incident.Impact,
+		componentNames(incident.Components),
+		incident.ResolvedAt,
+	).Scan(&incident.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update status incident: %w", err)
+	}
+
+	return nil
+}
+
+func (r *StatusIncidentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM status_incidents WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete status incident: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *StatusIncidentRepository) ListRecent(ctx context.Context, resolvedSince time.Time) ([]domain.StatusIncident, error) {
+	query := `
+		SELECT ` + statusIncidentColumns + `
+		FROM status_incidents
+		WHERE resolved_at IS NULL OR resolved_at >= $1
+		ORDER BY created_at DESC`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, resolvedSince)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list status incidents: %w", err)
+	}
+	defer rows.Close()
+
+	incidents := make([]domain.StatusIncident, 0)
+	for rows.Next() {
+		incident, err := scanStatusIncident(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan status incident: %w", err)
+		}
+		incidents = append(incidents, *incident)
+	}
+
+	return incidents, rows.Err()
+}
+
+func scanStatusIncident(row pgx.Row) (*domain.StatusIncident, error) {
+	incident := &domain.StatusIncident{}
+	var components []string
+	err := row.Scan(
+		&incident.ID,
+		&incident.Title,
+		&incident.Message,
+		&incident.Status,
+		&incident.Impact,
+		&components,
+		&incident.CreatedBy,
+		&incident.CreatedAt,
+		&incident.UpdatedAt,
+		&incident.ResolvedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	incident.Components = make([]domain.StatusComponent, len(components))
+	for i, component := range components {
+		incident.Components[i] = domain.StatusComponent(component)
+	}
+	return incident, nil
+}
+
+func componentNames(components []domain.StatusComponent) []string {
+	names := make([]string, len(components))
+	for i, component := range components {
+		names[i] = string(component)
+	}
+	return names
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+type UserBlockRepository struct {
+	db *DB
+}
+
+func NewUserBlockRepository(db *DB) *UserBlockRepository {
+	return &UserBlockRepository{db: db}
+}
+
+// Block blocks the user, keeping the original block if they already are
+func (r *UserBlockRepository) Block(ctx context.Context, block *domain.UserBlock) error {
+	query := `
+		INSERT INTO user_blocks (blocker_id, blocked_id, reason)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (blocker_id, blocked_id) DO UPDATE SET blocker_id = EXCLUDED.blocker_id
+		RETURNING reason, created_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query, block.BlockerID, block.BlockedID, block.Reason).Scan(&block.Reason, &block.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to block user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UserBlockRepository) Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, `DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2`, blockerID, blockedID)
+	if err != nil {
+		return fmt.Errorf("failed to unblock user: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *UserBlockRepository) IsBlocked(ctx context.Context, blockerID, blockedID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2)`
+
+	q := r.db.GetQuerier(ctx)
+	var blocked bool
+	if err := q.QueryRow(ctx, query, blockerID, blockedID).Scan(&blocked); err != nil {
+		return false, fmt.Errorf("failed to check user block: %w", err)
+	}
+
+	return blocked, nil
+}
+
+// List returns the users blocked by blockerID, newest first
+func (r *UserBlockRepository) List(ctx context.Context, blockerID uuid.UUID, page, limit int) ([]domain.UserBlock, int, error) {
+	countQuery := `SELECT COUNT(*) FROM user_blocks WHERE blocker_id = $1`
+	listQuery := `
+		SELECT b.blocker_id, b.blocked_id, b.reason, b.created_at,
+		       u.id, u.username, u.avatar_url, u.bio, u.created_at, u.is_deleted
+		FROM user_blocks b
+		JOIN users u ON u.id = b.blocked_id
+		WHERE b.blocker_id = $1
+		ORDER BY b.created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	q := r.db.GetQuerier(ctx)
+
+	var totalCount int
+	if err := q.QueryRow(ctx, countQuery, blockerID).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count user blocks: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	rows, err := q.Query(ctx, listQuery, blockerID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list user blocks: %w", err)
+	}
+	defer rows.Close()
+
+	blocks := make([]domain.UserBlock, 0)
+	for rows.Next() {
+		var block domain.UserBlock
+		blocked := &domain.PublicUser{}
+		err := rows.Scan(
+			&block.BlockerID,
+			&block.BlockedID,
+			&block.Reason,
+			&block.CreatedAt,
+			&blocked.ID,
+			&blocked.Username,
+			&blocked.AvatarURL,
+			&blocked.Bio,
+			&blocked.CreatedAt,
+			&blocked.Deleted,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan user block: %w", err)
+		}
+		blocked.HideIfDeleted()
+		block.Blocked = blocked
+		blocks = append(blocks, block)
+	}
+
+	return blocks, totalCount, nil
+}
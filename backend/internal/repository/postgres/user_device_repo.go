@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const deviceColumns = `id, user_id, fingerprint, name, user_agent, trusted, last_ip, last_seen_at, created_at,
+	challenge_digest, challenge_expires_at`
+
+func scanDevice(row rowScanner, device *domain.Device) error {
+	return row.Scan(
+		&device.ID,
+		&device.UserID,
+		&device.Fingerprint,
+		&device.Name,
+		&device.UserAgent,
+		&device.Trusted,
+		&device.LastIP,
+		&device.LastSeenAt,
+		&device.CreatedAt,
+		&device.ChallengeDigest,
+		&device.ChallengeExpiresAt,
+	)
+}
+
+type UserDeviceRepository struct {
+	db *DB
+}
+
+func NewUserDeviceRepository(db *DB) *UserDeviceRepository {
+	return &UserDeviceRepository{db: db}
+}
+
+// Touch records a sign-in from the device, adding it on first sight, and
+// fills in the stored fields
+func (r *UserDeviceRepository) Touch(ctx context.Context, device *domain.Device) error {
+	query := `
+		INSERT INTO user_devices (id, user_id, fingerprint, user_agent, last_ip)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, fingerprint) DO UPDATE
+		SET user_agent = EXCLUDED.user_agent, last_ip = EXCLUDED.last_ip, last_seen_at = NOW()
+		RETURNING ` + deviceColumns
+
+	if device.ID == uuid.Nil {
+		device.ID = uuid.New()
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := scanDevice(q.QueryRow(ctx, query,
+		device.ID,
+		device.UserID,
+		device.Fingerprint,
+		device.UserAgent,
+		device.LastIP,
+	), device)
+	if err != nil {
+		return fmt.Errorf("failed to record device: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UserDeviceRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Device, error) {
+	query := `SELECT ` + deviceColumns + ` FROM user_devices WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	device := &domain.Device{}
+	err := scanDevice(q.QueryRow(ctx, query, id), device)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device: %w", err)
+	}
+
+	return device, nil
+}
+
+func (r *UserDeviceRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Device, error) {
+	query := `SELECT ` + deviceColumns + ` FROM user_devices WHERE user_id = $1 ORDER BY last_seen_at DESC`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+	defer rows.Close()
+
+	devices := make([]domain.Device, 0)
+	for rows.Next() {
+		var device domain.Device
+		if err := scanDevice(rows, &device); err != nil {
+			return nil, fmt.Errorf("failed to scan device: %w", err)
+		}
+		devices = append(devices, device)
+	}
+
+	return devices, nil
+}
+
+// Update saves the user-editable fields: the name and whether it is trusted
+func (r *UserDeviceRepository) Update(ctx context.Context, device *domain.Device) error {
+	query := `UPDATE user_devices SET name = $2, trusted = $3 WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, device.ID, device.Name, device.Trusted)
+	if err != nil {
+		return fmt.Errorf("failed to update device: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete forgets the device, which also revokes its sessions
+func (r *UserDeviceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM user_devices WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete device: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// SetChallenge stores a new login code digest for the device, replacing any
+// earlier one and its failed attempts
+func (r *UserDeviceRepository) SetChallenge(ctx context.Context, id uuid.UUID, digest string, expiresAt time.Time) error {
+	query := `
+		UPDATE user_devices
+		SET challenge_digest = $2, challenge_expires_at = $3, challenge_attempts = 0
+		WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	if _, err := q.Exec(ctx, query, id, digest, expiresAt); err != nil {
+		return fmt.Errorf("failed to set device challenge: %w", err)
+	}
+
+	return nil
+}
+
+// RecordChallengeFailure counts a wrong login code, clearing the challenge
+// once maxAttempts is reached. It reports whether the challenge was cleared.
+func (r *UserDeviceRepository) RecordChallengeFailure(ctx context.Context, id uuid.UUID, maxAttempts int) (bool, error) {
+	query := `
+		UPDATE user_devices
+		SET challenge_digest = CASE WHEN challenge_attempts + 1 >= $2 THEN NULL ELSE challenge_digest END,
+		    challenge_expires_at = CASE WHEN challenge_attempts + 1 >= $2 THEN NULL ELSE challenge_expires_at END,
+		    challenge_attempts = CASE WHEN challenge_attempts + 1 >= $2 THEN 0 ELSE challenge_attempts + 1 END
+		WHERE id = $1
+		RETURNING challenge_digest IS NULL`
+
+	q := r.db.GetQuerier(ctx)
+	var cleared bool
+	err := q.QueryRow(ctx, query, id, maxAttempts).Scan(&cleared)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, domain.ErrNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to record device challenge failure: %w", err)
+	}
+
+	return cleared, nil
+}
+
+func (r *UserDeviceRepository) ClearChallenge(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE user_devices
+		SET challenge_digest = NULL, challenge_expires_at = NULL, challenge_attempts = 0
+		WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	if _, err := q.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to clear device challenge: %w", err)
+	}
+
+	return nil
+}
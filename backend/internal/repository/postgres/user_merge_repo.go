@@ -0,0 +1,339 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+// mergeStep is one statement of an account merge, run with $1 as the
+// primary and $2 as the duplicate account. Its affected rows are added to
+// the report under key, in Dropped when dropped is set; steps without a key
+// are not reported.
+type mergeStep struct {
+	key     string
+	dropped bool
+	query   string
+}
+
+// mergeSteps run in order. Rows that would collide with one the primary
+// already has are dropped or closed before the bulk moves.
+var mergeSteps = []mergeStep{
+	{key: "auctions", query: `UPDATE auctions SET seller_id = $1 WHERE seller_id = $2`},
+	{key: "auctions_won", query: `UPDATE auctions SET winner_id = $1 WHERE winner_id = $2`},
+	{key: "import_jobs", query: `UPDATE import_jobs SET seller_id = $1 WHERE seller_id = $2`},
+
+	{key: "bids", query: `UPDATE bids SET bidder_id = $1 WHERE bidder_id = $2`},
+	{query: `UPDATE bids SET cancelled_by = $1 WHERE cancelled_by = $2`},
+
+	// The primary's own request for an auction wins over the duplicate's
+	{key: "bid_approvals", query: `
+		INSERT INTO bid_approvals (auction_id, user_id, status, answers, note, decided_at, created_at, updated_at)
+		SELECT auction_id, $1, status, answers, note, decided_at, created_at, updated_at
+		FROM bid_approvals WHERE user_id = $2
+		ON CONFLICT (auction_id, user_id) DO NOTHING`},
+	{query: `DELETE FROM bid_approvals WHERE user_id = $2`},
+
+	{key: "ratings", dropped: true, query: `
+		DELETE FROM ratings r
+		WHERE r.rater_id = $2
+			AND EXISTS (
+				SELECT 1 FROM ratings p
+				WHERE p.rater_id = $1 AND p.auction_id = r.auction_id AND p.type = r.type
+			)`},
+	{key: "ratings", query: `UPDATE ratings SET rater_id = $1 WHERE rater_id = $2`},
+	{key: "ratings_received", query: `UPDATE ratings SET rated_user_id = $1 WHERE rated_user_id = $2`},
+
+	// Deleting fires the watch_count trigger, so counts stay right
+	{key: "watchlist", dropped: true, query: `
+		DELETE FROM watchlist w
+		WHERE w.user_id = $2
+			AND EXISTS (SELECT 1 FROM watchlist p WHERE p.user_id = $1 AND p.auction_id = w.auction_id)`},
+	{key: "watchlist", query: `UPDATE watchlist SET user_id = $1 WHERE user_id = $2`},
+
+	{key: "auction_invites", dropped: true, query: `
+		DELETE FROM auction_invites i
+		WHERE i.user_id = $2
+			AND EXISTS (SELECT 1 FROM auction_invites p WHERE p.user_id = $1 AND p.auction_id = i.auction_id)`},
+	{key: "auction_invites", query: `UPDATE auction_invites SET user_id = $1 WHERE user_id = $2`},
+
+	// Where both shared a listing, the duplicate's visits are counted under
+	// the primary's share before its own is dropped
+	{query: `
+		UPDATE listing_share_clicks c SET share_id = p.id
+		FROM listing_shares d
+		JOIN listing_shares p ON p.auction_id = d.auction_id AND p.sharer_id = $1
+		WHERE c.share_id = d.id AND d.sharer_id = $2`},
+	{key: "listing_shares", dropped: true, query: `
+		DELETE FROM listing_shares s
+		WHERE s.sharer_id = $2
+			AND EXISTS (SELECT 1 FROM listing_shares p WHERE p.sharer_id = $1 AND p.auction_id = s.auction_id)`},
+	{key: "listing_shares", query: `UPDATE listing_shares SET sharer_id = $1 WHERE sharer_id = $2`},
+	{query: `UPDATE listing_share_clicks SET user_id = $1 WHERE user_id = $2`},
+
+	{key: "orders", query: `UPDATE orders SET buyer_id = $1 WHERE buyer_id = $2`},
+	{key: "orders", query: `UPDATE orders SET seller_id = $1 WHERE seller_id = $2`},
+	{key: "unpaid_strikes", query: `UPDATE unpaid_strikes SET user_id = $1 WHERE user_id = $2`},
+	{key: "second_chance_offers", query: `UPDATE second_chance_offers SET bidder_id = $1 WHERE bidder_id = $2`},
+
+	// Held payments follow their orders, so the primary confirms delivery
+	// and is credited on release
+	{key: "escrow_holds", query: `UPDATE escrow_holds SET buyer_id = $1 WHERE buyer_id = $2`},
+	{key: "escrow_holds", query: `UPDATE escrow_holds SET seller_id = $1 WHERE seller_id = $2`},
+	{key: "ledger_entries", query: `UPDATE ledger_entries SET user_id = $1 WHERE user_id = $2`},
+	{key: "balances", query: `
+		INSERT INTO balances (user_id, available)
+		SELECT $1, available FROM balances WHERE user_id = $2
+		ON CONFLICT (user_id) DO UPDATE
+		SET available = balances.available + EXCLUDED.available, updated_at = NOW()`},
+	{query: `DELETE FROM balances WHERE user_id = $2`},
+
+	{key: "payouts", query: `UPDATE payouts SET user_id = $1 WHERE user_id = $2`},
+	{query: `UPDATE payouts SET reviewed_by = $1 WHERE reviewed_by = $2`},
+	// The primary keeps its own payout method if it has one
+	{key: "payout_methods", dropped: true, query: `
+		DELETE FROM payout_methods
+		WHERE user_id = $2 AND EXISTS (SELECT 1 FROM payout_methods WHERE user_id = $1)`},
+	{key: "payout_methods", query: `UPDATE payout_methods SET user_id = $1 WHERE user_id = $2`},
+
+	{key: "offers", dropped: true, query: `
+		UPDATE offers o SET status = 'expired', responded_at = NOW()
+		WHERE o.buyer_id = $2 AND o.status IN ('pending', 'countered')
+			AND EXISTS (
+				SELECT 1 FROM offers p
+				WHERE p.buyer_id = $1 AND p.auction_id = o.auction_id AND p.status IN ('pending', 'countered')
+			)`},
+	{key: "offers", query: `UPDATE offers SET buyer_id = $1 WHERE buyer_id = $2`},
+
+	{key: "snipes", dropped: true, query: `
+		UPDATE snipes s SET status = 'cancelled'
+		WHERE s.user_id = $2 AND s.status = 'scheduled'
+			AND EXISTS (
+				SELECT 1 FROM snipes p
+				WHERE p.user_id = $1 AND p.auction_id = s.auction_id AND p.status = 'scheduled'
+			)`},
+	{key: "snipes", query: `UPDATE snipes SET user_id = $1 WHERE user_id = $2`},
+
+	{key: "disputes", query: `UPDATE disputes SET buyer_id = $1 WHERE buyer_id = $2`},
+	{key: "disputes", query: `UPDATE disputes SET seller_id = $1 WHERE seller_id = $2`},
+	{query: `UPDATE disputes SET opened_by = $1 WHERE opened_by = $2`},
+	{query: `UPDATE disputes SET resolved_by = $1 WHERE resolved_by = $2`},
+	{key: "dispute_messages", query: `UPDATE dispute_messages SET sender_id = $1 WHERE sender_id = $2`},
+	{key: "dispute_evidence", query: `UPDATE dispute_evidence SET uploaded_by = $1 WHERE uploaded_by = $2`},
+
+	// Conversations the primary also has were folded in before these run
+	{key: "conversations", query: `
+		UPDATE conversations SET
+			participant_one = LEAST(
+				CASE WHEN participant_one = $2 THEN $1 ELSE participant_one END,
+				CASE WHEN participant_two = $2 THEN $1 ELSE participant_two END),
+			participant_two = GREATEST(
+				CASE WHEN participant_one = $2 THEN $1 ELSE participant_one END,
+				CASE WHEN participant_two = $2 THEN $1 ELSE participant_two END)
+		WHERE $2 IN (participant_one, participant_two)`},
+	{query: `UPDATE conversation_read_status SET user_id = $1 WHERE user_id = $2`},
+	{key: "messages", query: `UPDATE messages SET sender_id = $1 WHERE sender_id = $2`},
+
+	{key: "device_keys", dropped: true, query: `
+		DELETE FROM device_keys d
+		WHERE d.user_id = $2
+			AND EXISTS (SELECT 1 FROM device_keys p WHERE p.user_id = $1 AND p.device_id = d.device_id)`},
+	{key: "device_keys", query: `UPDATE device_keys SET user_id = $1 WHERE user_id = $2`},
+	{key: "push_subscriptions", query: `UPDATE push_subscriptions SET user_id = $1 WHERE user_id = $2`},
+
+	{key: "oauth_accounts", query: `UPDATE oauth_accounts SET user_id = $1 WHERE user_id = $2`},
+	{key: "notifications", query: `UPDATE notifications SET user_id = $1 WHERE user_id = $2`},
+	// The primary keeps its own preferences, taking the duplicate's only
+	// when it has none
+	{key: "notification_preferences", query: `
+		UPDATE notification_preferences SET user_id = $1
+		WHERE user_id = $2 AND NOT EXISTS (SELECT 1 FROM notification_preferences WHERE user_id = $1)`},
+	{key: "notification_preferences", dropped: true, query: `DELETE FROM notification_preferences WHERE user_id = $2`},
+	{key: "reports", query: `UPDATE reported_listings SET reporter_id = $1 WHERE reporter_id = $2`},
+
+	// A block between the two accounts would become the primary blocking
+	// itself
+	{key: "user_blocks", dropped: true, query: `
+		DELETE FROM user_blocks
+		WHERE (blocker_id = $2 AND blocked_id = $1) OR (blocker_id = $1 AND blocked_id = $2)`},
+	{key: "user_blocks", dropped: true, query: `
+		DELETE FROM user_blocks b
+		WHERE b.blocker_id = $2
+			AND EXISTS (SELECT 1 FROM user_blocks p WHERE p.blocker_id = $1 AND p.blocked_id = b.blocked_id)`},
+	{key: "user_blocks", query: `UPDATE user_blocks SET blocker_id = $1 WHERE blocker_id = $2`},
+	{key: "user_blocks_received", dropped: true, query: `
+		DELETE FROM user_blocks b
+		WHERE b.blocked_id = $2
+			AND EXISTS (SELECT 1 FROM user_blocks p WHERE p.blocked_id = $1 AND p.blocker_id = b.blocker_id)`},
+	{key: "user_blocks_received", query: `UPDATE user_blocks SET blocked_id = $1 WHERE blocked_id = $2`},
+
+	// Follows are copied over, skipping ones the primary already has and
+	// follows between the two accounts, then the duplicate's are deleted
+	{key: "seller_follows", query: `
+		INSERT INTO seller_follows (user_id, seller_id, created_at)
+		SELECT $1, seller_id, created_at FROM seller_follows
+		WHERE user_id = $2 AND seller_id <> $1
+		ON CONFLICT (user_id, seller_id) DO NOTHING`},
+	{query: `DELETE FROM seller_follows WHERE user_id = $2`},
+	{key: "followers", query: `
+		INSERT INTO seller_follows (user_id, seller_id, created_at)
+		SELECT user_id, $1, created_at FROM seller_follows
+		WHERE seller_id = $2 AND user_id <> $1
+		ON CONFLICT (user_id, seller_id) DO NOTHING`},
+	{query: `DELETE FROM seller_follows WHERE seller_id = $2`},
+
+	// The duplicate stays behind, signed out and banned, with its security
+	// records: devices, login history, webhooks and moderation cases
+	{query: `DELETE FROM refresh_tokens WHERE user_id = $2`},
+	{query: `UPDATE users SET is_banned = TRUE WHERE id = $2`},
+}
+
+type UserMergeRepository struct {
+	db *DB
+}
+
+func NewUserMergeRepository(db *DB) *UserMergeRepository {
+	return &UserMergeRepository{db: db}
+}
+
+// HaveInteracted reports whether the two accounts ever bid on, made offers
+// on or bought each other's listings, or have a conversation together
+func (r *UserMergeRepository) HaveInteracted(ctx context.Context, a, b uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM bids x JOIN auctions au ON au.id = x.auction_id
+			WHERE (au.seller_id = $1 AND x.bidder_id = $2) OR (au.seller_id = $2 AND x.bidder_id = $1)
+		) OR EXISTS (
+			SELECT 1 FROM offers x JOIN auctions au ON au.id = x.auction_id
+			WHERE (au.seller_id = $1 AND x.buyer_id = $2) OR (au.seller_id = $2 AND x.buyer_id = $1)
+		) OR EXISTS (
+			SELECT 1 FROM orders
+			WHERE (buyer_id = $1 AND seller_id = $2) OR (buyer_id = $2 AND seller_id = $1)
+		) OR EXISTS (
+			SELECT 1 FROM conversations
+			WHERE participant_one = LEAST($1::uuid, $2::uuid) AND participant_two = GREATEST($1::uuid, $2::uuid)
+		)`
+
+	q := r.db.GetQuerier(ctx)
+	var interacted bool
+	if err := q.QueryRow(ctx, query, a, b).Scan(&interacted); err != nil {
+		return false, fmt.Errorf("failed to check account interactions: %w", err)
+	}
+
+	return interacted, nil
+}
+
+// Merge moves the duplicate's records onto the primary account and counts
+// them in the report. It must run in a transaction.
+func (r *UserMergeRepository) Merge(ctx context.Context, report *domain.UserMergeReport) error {
+	q := r.db.GetQuerier(ctx)
+	primaryID, duplicateID := report.PrimaryID, report.DuplicateID
+
+	// Lock both accounts so nothing is written to the duplicate mid-merge
+	var locked int
+	err := q.QueryRow(ctx, `
+		SELECT COUNT(*) FROM (SELECT id FROM users WHERE id IN ($1, $2) FOR UPDATE) u`,
+		primaryID, duplicateID,
+	).Scan(&locked)
+	if err != nil {
+		return fmt.Errorf("failed to lock accounts: %w", err)
+	}
+	if locked != 2 {
+		return domain.ErrNotFound
+	}
+
+	if err := r.foldConversations(ctx, report); err != nil {
+		return err
+	}
+
+	for _, step := range mergeSteps {
+		result, err := q.Exec(ctx, step.query, primaryID, duplicateID)
+		if err != nil {
+			return fmt.Errorf("failed to merge %s: %w", step.key, err)
+		}
+		if step.key == "" {
+			continue
+		}
+		counts := report.Moved
+		if step.dropped {
+			counts = report.Dropped
+		}
+		counts[step.key] += result.RowsAffected()
+	}
+
+	return nil
+}
+
+// foldConversations moves the messages of each duplicate conversation with
+// someone the primary already talks to, in the same encryption mode, into
+// the primary's conversation and deletes the emptied one
+func (r *UserMergeRepository) foldConversations(ctx context.Context, report *domain.UserMergeReport) error {
+	query := `
+		SELECT d.id, p.id
+		FROM conversations d
+		JOIN conversations p ON p.encryption_mode = d.encryption_mode
+			AND $1 IN (p.participant_one, p.participant_two)
+			AND CASE WHEN p.participant_one = $1 THEN p.participant_two ELSE p.participant_one END
+				= CASE WHEN d.participant_one = $2 THEN d.participant_two ELSE d.participant_one END
+		WHERE $2 IN (d.participant_one, d.participant_two)`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, report.PrimaryID, report.DuplicateID)
+	if err != nil {
+		return fmt.Errorf("failed to find shared conversations: %w", err)
+	}
+
+	type fold struct{ from, into uuid.UUID }
+	var folds []fold
+	for rows.Next() {
+		var f fold
+		if err := rows.Scan(&f.from, &f.into); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan shared conversation: %w", err)
+		}
+		folds = append(folds, f)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to find shared conversations: %w", err)
+	}
+
+	for _, f := range folds {
+		if _, err := q.Exec(ctx, `UPDATE messages SET conversation_id = $1 WHERE conversation_id = $2`, f.into, f.from); err != nil {
+			return fmt.Errorf("failed to move conversation messages: %w", err)
+		}
+
+		// Carry unread counts over, the duplicate's under the primary
+		_, err := q.Exec(ctx, `
+			INSERT INTO conversation_read_status (conversation_id, user_id, last_read_at, unread_count)
+			SELECT $1, CASE WHEN user_id = $4 THEN $3 ELSE user_id END, last_read_at, unread_count
+			FROM conversation_read_status
+			WHERE conversation_id = $2
+			ON CONFLICT (conversation_id, user_id) DO UPDATE
+			SET unread_count = conversation_read_status.unread_count + EXCLUDED.unread_count`,
+			f.into, f.from, report.PrimaryID, report.DuplicateID)
+		if err != nil {
+			return fmt.Errorf("failed to merge read status: %w", err)
+		}
+
+		_, err = q.Exec(ctx, `
+			UPDATE conversations p
+			SET last_message_at = GREATEST(p.last_message_at, d.last_message_at),
+				legal_hold = p.legal_hold OR d.legal_hold
+			FROM conversations d
+			WHERE p.id = $1 AND d.id = $2`,
+			f.into, f.from)
+		if err != nil {
+			return fmt.Errorf("failed to update conversation: %w", err)
+		}
+
+		if _, err := q.Exec(ctx, `DELETE FROM conversations WHERE id = $1`, f.from); err != nil {
+			return fmt.Errorf("failed to delete merged conversation: %w", err)
+		}
+		report.Dropped["conversations"]++
+	}
+
+	return nil
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/auction-cards/backend/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type UserRepository struct {
@@ -20,9 +22,10 @@ func NewUserRepository(db *DB) *UserRepository {
 
 func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	query := `
-		INSERT INTO users (id, email, username, password_hash, avatar_url, bio, phone, address, role, email_verified, email_verification_token)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-		RETURNING created_at, updated_at`
+		INSERT INTO users (id, email, username, password_hash, avatar_url, bio, phone, address, role, email_verified, email_verification_token,
+		                   username_setup_required)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING vacation_listing_mode, created_at, updated_at`
 
 	if user.ID == uuid.Nil {
 		user.ID = uuid.New()
@@ -41,20 +44,41 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 		user.Role,
 		user.EmailVerified,
 		user.EmailVerificationToken,
-	).Scan(&user.CreatedAt, &user.UpdatedAt)
+		user.UsernameSetupRequired,
+	).Scan(&user.Vacation.ListingMode, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
+		if err := uniqueUserError(err); err != nil {
+			return err
+		}
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 
 	return nil
 }
 
+// uniqueUserError maps a violation of the unique email or username
+// constraint to its domain error, or returns nil
+func uniqueUserError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		return nil
+	}
+	switch pgErr.ConstraintName {
+	case "users_email_key":
+		return domain.ErrEmailAlreadyExists
+	case "users_username_key":
+		return domain.ErrUsernameExists
+	}
+	return nil
+}
+
 func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	query := `
 		SELECT id, email, username, password_hash, avatar_url, bio, phone, address, role,
 		       email_verified, email_verification_token, password_reset_token, password_reset_expires,
-		       is_banned, created_at, updated_at
+		       is_banned, message_retention_days, vacation_mode, vacation_starts_at, vacation_ends_at,
+		       vacation_listing_mode, vacation_block_bids, vacation_auto_reply, single_session, two_factor_enabled, username_setup_required, is_deleted, deleted_at, created_at, updated_at
 		FROM users
 		WHERE id = $1`
 
@@ -75,6 +99,18 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Use
 		&user.PasswordResetToken,
 		&user.PasswordResetExpires,
 		&user.IsBanned,
+		&user.MessageRetentionDays,
+		&user.Vacation.Enabled,
+		&user.Vacation.StartsAt,
+		&user.Vacation.EndsAt,
+		&user.Vacation.ListingMode,
+		&user.Vacation.BlockBids,
+		&user.Vacation.AutoReply,
+		&user.SingleSession,
+		&user.TwoFactorEnabled,
+		&user.UsernameSetupRequired,
+		&user.IsDeleted,
+		&user.DeletedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -89,13 +125,75 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Use
 	return user, nil
 }
 
+// GetByIDs returns the users with the given IDs, skipping any that don't
+// exist; the order is unspecified
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.User, error) {
+	query := `
+		SELECT id, email, username, password_hash, avatar_url, bio, phone, address, role,
+		       email_verified, email_verification_token, password_reset_token, password_reset_expires,
+		       is_banned, message_retention_days, vacation_mode, vacation_starts_at, vacation_ends_at,
+		       vacation_listing_mode, vacation_block_bids, vacation_auto_reply, single_session, two_factor_enabled, username_setup_required, is_deleted, deleted_at, created_at, updated_at
+		FROM users
+		WHERE id = ANY($1)`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users by id: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]domain.User, 0, len(ids))
+	for rows.Next() {
+		var user domain.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Email,
+			&user.Username,
+			&user.PasswordHash,
+			&user.AvatarURL,
+			&user.Bio,
+			&user.Phone,
+			&user.Address,
+			&user.Role,
+			&user.EmailVerified,
+			&user.EmailVerificationToken,
+			&user.PasswordResetToken,
+			&user.PasswordResetExpires,
+			&user.IsBanned,
+			&user.MessageRetentionDays,
+			&user.Vacation.Enabled,
+			&user.Vacation.StartsAt,
+			&user.Vacation.EndsAt,
+			&user.Vacation.ListingMode,
+			&user.Vacation.BlockBids,
+			&user.Vacation.AutoReply,
+			&user.SingleSession,
+			&user.TwoFactorEnabled,
+			&user.UsernameSetupRequired,
+			&user.IsDeleted,
+			&user.DeletedAt,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// GetByEmail finds the account signed up with email, skipping deleted ones
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	query := `
 		SELECT id, email, username, password_hash, avatar_url, bio, phone, address, role,
 		       email_verified, email_verification_token, password_reset_token, password_reset_expires,
-		       is_banned, created_at, updated_at
+		       is_banned, message_retention_days, vacation_mode, vacation_starts_at, vacation_ends_at,
+		       vacation_listing_mode, vacation_block_bids, vacation_auto_reply, single_session, two_factor_enabled, username_setup_required, is_deleted, deleted_at, created_at, updated_at
 		FROM users
-		WHERE email = $1`
+		WHERE email = $1 AND NOT is_deleted`
 
 	q := r.db.GetQuerier(ctx)
 	user := &domain.User{}
@@ -114,6 +212,18 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.
 		&user.PasswordResetToken,
 		&user.PasswordResetExpires,
 		&user.IsBanned,
+		&user.MessageRetentionDays,
+		&user.Vacation.Enabled,
+		&user.Vacation.StartsAt,
+		&user.Vacation.EndsAt,
+		&user.Vacation.ListingMode,
+		&user.Vacation.BlockBids,
+		&user.Vacation.AutoReply,
+		&user.SingleSession,
+		&user.TwoFactorEnabled,
+		&user.UsernameSetupRequired,
+		&user.IsDeleted,
+		&user.DeletedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -132,7 +242,8 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*d
 	query := `
 		SELECT id, email, username, password_hash, avatar_url, bio, phone, address, role,
 		       email_verified, email_verification_token, password_reset_token, password_reset_expires,
-		       is_banned, created_at, updated_at
+		       is_banned, message_retention_days, vacation_mode, vacation_starts_at, vacation_ends_at,
+		       vacation_listing_mode, vacation_block_bids, vacation_auto_reply, single_session, two_factor_enabled, username_setup_required, is_deleted, deleted_at, created_at, updated_at
 		FROM users
 		WHERE username = $1`
 
@@ -153,6 +264,18 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*d
 		&user.PasswordResetToken,
 		&user.PasswordResetExpires,
 		&user.IsBanned,
+		&user.MessageRetentionDays,
+		&user.Vacation.Enabled,
+		&user.Vacation.StartsAt,
+		&user.Vacation.EndsAt,
+		&user.Vacation.ListingMode,
+		&user.Vacation.BlockBids,
+		&user.Vacation.AutoReply,
+		&user.SingleSession,
+		&user.TwoFactorEnabled,
+		&user.UsernameSetupRequired,
+		&user.IsDeleted,
+		&user.DeletedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -167,17 +290,20 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*d
 	return user, nil
 }
 
-func (r *UserRepository) GetByVerificationToken(ctx context.Context, token string) (*domain.User, error) {
+// GetByVerificationTokenSelector finds the user whose verification token
+// starts with the selector. The caller must still compare the full token.
+func (r *UserRepository) GetByVerificationTokenSelector(ctx context.Context, selector string) (*domain.User, error) {
 	query := `
 		SELECT id, email, username, password_hash, avatar_url, bio, phone, address, role,
 		       email_verified, email_verification_token, password_reset_token, password_reset_expires,
-		       is_banned, created_at, updated_at
+		       is_banned, message_retention_days, vacation_mode, vacation_starts_at, vacation_ends_at,
+		       vacation_listing_mode, vacation_block_bids, vacation_auto_reply, single_session, two_factor_enabled, username_setup_required, is_deleted, deleted_at, created_at, updated_at
 		FROM users
-		WHERE email_verification_token = $1`
+		WHERE LEFT(email_verification_token, 16) = $1 AND NOT is_deleted`
 
 	q := r.db.GetQuerier(ctx)
 	user := &domain.User{}
-	err := q.QueryRow(ctx, query, token).Scan(
+	err := q.QueryRow(ctx, query, selector).Scan(
 		&user.ID,
 		&user.Email,
 		&user.Username,
@@ -192,6 +318,18 @@ func (r *UserRepository) GetByVerificationToken(ctx context.Context, token strin
 		&user.PasswordResetToken,
 		&user.PasswordResetExpires,
 		&user.IsBanned,
+		&user.MessageRetentionDays,
+		&user.Vacation.Enabled,
+		&user.Vacation.StartsAt,
+		&user.Vacation.EndsAt,
+		&user.Vacation.ListingMode,
+		&user.Vacation.BlockBids,
+		&user.Vacation.AutoReply,
+		&user.SingleSession,
+		&user.TwoFactorEnabled,
+		&user.UsernameSetupRequired,
+		&user.IsDeleted,
+		&user.DeletedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -206,17 +344,20 @@ func (r *UserRepository) GetByVerificationToken(ctx context.Context, token strin
 	return user, nil
 }
 
-func (r *UserRepository) GetByPasswordResetToken(ctx context.Context, token string) (*domain.User, error) {
+// GetByPasswordResetTokenSelector finds the user whose unexpired reset token
+// starts with the selector. The caller must still compare the full token.
+func (r *UserRepository) GetByPasswordResetTokenSelector(ctx context.Context, selector string) (*domain.User, error) {
 	query := `
 		SELECT id, email, username, password_hash, avatar_url, bio, phone, address, role,
 		       email_verified, email_verification_token, password_reset_token, password_reset_expires,
-		       is_banned, created_at, updated_at
+		       is_banned, message_retention_days, vacation_mode, vacation_starts_at, vacation_ends_at,
+		       vacation_listing_mode, vacation_block_bids, vacation_auto_reply, single_session, two_factor_enabled, username_setup_required, is_deleted, deleted_at, created_at, updated_at
 		FROM users
-		WHERE password_reset_token = $1 AND password_reset_expires > NOW()`
+		WHERE LEFT(password_reset_token, 16) = $1 AND password_reset_expires > NOW() AND NOT is_deleted`
 
 	q := r.db.GetQuerier(ctx)
 	user := &domain.User{}
-	err := q.QueryRow(ctx, query, token).Scan(
+	err := q.QueryRow(ctx, query, selector).Scan(
 		&user.ID,
 		&user.Email,
 		&user.Username,
@@ -231,6 +372,18 @@ func (r *UserRepository) GetByPasswordResetToken(ctx context.Context, token stri
 		&user.PasswordResetToken,
 		&user.PasswordResetExpires,
 		&user.IsBanned,
+		&user.MessageRetentionDays,
+		&user.Vacation.Enabled,
+		&user.Vacation.StartsAt,
+		&user.Vacation.EndsAt,
+		&user.Vacation.ListingMode,
+		&user.Vacation.BlockBids,
+		&user.Vacation.AutoReply,
+		&user.SingleSession,
+		&user.TwoFactorEnabled,
+		&user.UsernameSetupRequired,
+		&user.IsDeleted,
+		&user.DeletedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -250,7 +403,14 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 		UPDATE users
 		SET email = $2, username = $3, password_hash = $4, avatar_url = $5, bio = $6,
 		    phone = $7, address = $8, role = $9, email_verified = $10, email_verification_token = $11,
-		    password_reset_token = $12, password_reset_expires = $13, is_banned = $14
+		    password_reset_token = $12, password_reset_expires = $13, is_banned = $14,
+		    message_retention_days = $15, vacation_mode = $16, vacation_starts_at = $17, vacation_ends_at = $18,
+		    vacation_listing_mode = $19, vacation_block_bids = $20, vacation_auto_reply = $21, single_session = $22,
+		    two_factor_enabled = $23, username_setup_required = $24,
+		    email_verification_attempts = CASE WHEN email_verification_token IS DISTINCT FROM $11
+		        THEN 0 ELSE email_verification_attempts END,
+		    password_reset_attempts = CASE WHEN password_reset_token IS DISTINCT FROM $12
+		        THEN 0 ELSE password_reset_attempts END
 		WHERE id = $1
 		RETURNING updated_at`
 
@@ -270,11 +430,24 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 		user.PasswordResetToken,
 		user.PasswordResetExpires,
 		user.IsBanned,
+		user.MessageRetentionDays,
+		user.Vacation.Enabled,
+		user.Vacation.StartsAt,
+		user.Vacation.EndsAt,
+		user.Vacation.ListingMode,
+		user.Vacation.BlockBids,
+		user.Vacation.AutoReply,
+		user.SingleSession,
+		user.TwoFactorEnabled,
+		user.UsernameSetupRequired,
 	).Scan(&user.UpdatedAt)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		return domain.ErrNotFound
 	}
+	if err := uniqueUserError(err); err != nil {
+		return err
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
@@ -282,8 +455,72 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 	return nil
 }
 
+// RecordLogin stores where the user just logged in from and returns the
+// country and time of their previous login, if known
+func (r *UserRepository) RecordLogin(ctx context.Context, userID uuid.UUID, ip, country string) (*string, *time.Time, error) {
+	query := `
+		WITH previous AS (
+			SELECT last_login_country, last_login_at FROM users WHERE id = $1 FOR UPDATE
+		)
+		UPDATE users
+		SET last_login_ip = NULLIF($2, ''), last_login_country = NULLIF($3, ''), last_login_at = NOW()
+		WHERE id = $1
+		RETURNING (SELECT last_login_country FROM previous), (SELECT last_login_at FROM previous)`
+
+	q := r.db.GetQuerier(ctx)
+	var prevCountry *string
+	var prevAt *time.Time
+	err := q.QueryRow(ctx, query, userID, ip, country).Scan(&prevCountry, &prevAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to record login: %w", err)
+	}
+
+	return prevCountry, prevAt, nil
+}
+
+// tokenAttemptColumns maps each token kind to its token and attempt columns
+var tokenAttemptColumns = map[domain.TokenKind][2]string{
+	domain.TokenEmailVerification: {"email_verification_token", "email_verification_attempts"},
+	domain.TokenPasswordReset:     {"password_reset_token", "password_reset_attempts"},
+}
+
+// RecordTokenFailure counts a wrong guess against the user's token, clearing
+// the token once maxAttempts is reached. It reports whether it was cleared.
+func (r *UserRepository) RecordTokenFailure(ctx context.Context, userID uuid.UUID, kind domain.TokenKind, maxAttempts int) (bool, error) {
+	columns, ok := tokenAttemptColumns[kind]
+	if !ok {
+		return false, fmt.Errorf("unknown token kind %q", kind)
+	}
+	token, attempts := columns[0], columns[1]
+
+	query := `
+		UPDATE users
+		SET ` + token + ` = CASE WHEN ` + attempts + ` + 1 >= $2 THEN NULL ELSE ` + token + ` END,
+		    ` + attempts + ` = CASE WHEN ` + attempts + ` + 1 >= $2 THEN 0 ELSE ` + attempts + ` + 1 END
+		WHERE id = $1
+		RETURNING ` + token + ` IS NULL`
+
+	q := r.db.GetQuerier(ctx)
+	var cleared bool
+	err := q.QueryRow(ctx, query, userID, maxAttempts).Scan(&cleared)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, domain.ErrNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to record token failure: %w", err)
+	}
+
+	return cleared, nil
+}
+
+// Delete soft-deletes the user: the account can no longer sign in and is
+// shown as a deleted user, but the row stays for everything referencing it
+// until it is purged
 func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM users WHERE id = $1`
+	query := `UPDATE users SET is_deleted = TRUE, deleted_at = NOW() WHERE id = $1 AND NOT is_deleted`
 
 	q := r.db.GetQuerier(ctx)
 	result, err := q.Exec(ctx, query, id)
@@ -298,13 +535,16 @@ func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// List pages through accounts that haven't been deleted, newest first
 func (r *UserRepository) List(ctx context.Context, page, limit int) ([]domain.User, int, error) {
-	countQuery := `SELECT COUNT(*) FROM users`
+	countQuery := `SELECT COUNT(*) FROM users WHERE NOT is_deleted`
 	listQuery := `
 		SELECT id, email, username, password_hash, avatar_url, bio, phone, address, role,
 		       email_verified, email_verification_token, password_reset_token, password_reset_expires,
-		       is_banned, created_at, updated_at
+		       is_banned, message_retention_days, vacation_mode, vacation_starts_at, vacation_ends_at,
+		       vacation_listing_mode, vacation_block_bids, vacation_auto_reply, single_session, two_factor_enabled, username_setup_required, is_deleted, deleted_at, created_at, updated_at
 		FROM users
+		WHERE NOT is_deleted
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2`
 
@@ -340,6 +580,18 @@ func (r *UserRepository) List(ctx context.Context, page, limit int) ([]domain.Us
 			&user.PasswordResetToken,
 			&user.PasswordResetExpires,
 			&user.IsBanned,
+			&user.MessageRetentionDays,
+			&user.Vacation.Enabled,
+			&user.Vacation.StartsAt,
+			&user.Vacation.EndsAt,
+			&user.Vacation.ListingMode,
+			&user.Vacation.BlockBids,
+			&user.Vacation.AutoReply,
+			&user.SingleSession,
+			&user.TwoFactorEnabled,
+			&user.UsernameSetupRequired,
+			&user.IsDeleted,
+			&user.DeletedAt,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -382,3 +634,46 @@ func (r *UserRepository) GetRatingSummary(ctx context.Context, userID uuid.UUID)
 
 	return summary, nil
 }
+
+// GetRatingSummaries returns the rating summary of each user, including
+// users without ratings
+func (r *UserRepository) GetRatingSummaries(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]*domain.UserRatingSummary, error) {
+	query := `
+		SELECT
+			u.id as user_id,
+			COALESCE(AVG(r.rating)::float, 0) as average_rating,
+			COUNT(r.rating) as total_ratings,
+			COALESCE(AVG(CASE WHEN r.type = 'seller' THEN r.rating END)::float, 0) as seller_rating,
+			COUNT(CASE WHEN r.type = 'seller' THEN 1 END) as seller_count,
+			COALESCE(AVG(CASE WHEN r.type = 'buyer' THEN r.rating END)::float, 0) as buyer_rating,
+			COUNT(CASE WHEN r.type = 'buyer' THEN 1 END) as buyer_count
+		FROM unnest($1::uuid[]) AS u(id)
+		LEFT JOIN ratings r ON r.rated_user_id = u.id
+		GROUP BY u.id`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rating summaries: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := make(map[uuid.UUID]*domain.UserRatingSummary, len(userIDs))
+	for rows.Next() {
+		summary := &domain.UserRatingSummary{}
+		if err := rows.Scan(
+			&summary.UserID,
+			&summary.AverageRating,
+			&summary.TotalRatings,
+			&summary.SellerRating,
+			&summary.SellerCount,
+			&summary.BuyerRating,
+			&summary.BuyerCount,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan rating summary: %w", err)
+		}
+		summaries[summary.UserID] = summary
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,396 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const webhookColumns = `id, user_id, url, secret, events, global, active, created_at, updated_at`
+
+func scanWebhook(row rowScanner, w *domain.Webhook) error {
+	return row.Scan(
+		&w.ID,
+		&w.UserID,
+		&w.URL,
+		&w.Secret,
+		&w.Events,
+		&w.Global,
+		&w.Active,
+		&w.CreatedAt,
+		&w.UpdatedAt,
+	)
+}
+
+type WebhookRepository struct {
+	db *DB
+}
+
+func NewWebhookRepository(db *DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, webhook *domain.Webhook) error {
+	query := `
+		INSERT INTO webhooks (id, user_id, url, secret, events, global, active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at, updated_at`
+
+	if webhook.ID == uuid.Nil {
+		webhook.ID = uuid.New()
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		webhook.ID,
+		webhook.UserID,
+		webhook.URL,
+		webhook.Secret,
+		webhook.Events,
+		webhook.Global,
+		webhook.Active,
+	).Scan(&webhook.CreatedAt, &webhook.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	webhook := &domain.Webhook{}
+	err := scanWebhook(q.QueryRow(ctx, query, id), webhook)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+func (r *WebhookRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE user_id = $1 ORDER BY created_at DESC`
+
+	return r.query(ctx, query, userID)
+}
+
+func (r *WebhookRepository) CountByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM webhooks WHERE user_id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	var count int
+	if err := q.QueryRow(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count webhooks: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListSubscribed returns the active webhooks wanting the event: those owned
+// by one of userIDs plus every global one
+func (r *WebhookRepository) ListSubscribed(ctx context.Context, event domain.WebhookEvent, userIDs []uuid.UUID) ([]domain.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks
+		WHERE active AND $1 = ANY(events) AND (global OR user_id = ANY($2))`
+
+	return r.query(ctx, query, string(event), userIDs)
+}
+
+// Update saves the URL, events, active flag and secret
+func (r *WebhookRepository) Update(ctx context.Context, webhook *domain.Webhook) error {
+	query := `
+		UPDATE webhooks SET url = $2, events = $3, active = $4, secret = $5
+		WHERE id = $1
+		RETURNING updated_at`
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		webhook.ID,
+		webhook.URL,
+		webhook.Events,
+		webhook.Active,
+		webhook.Secret,
+	).Scan(&webhook.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM webhooks WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) query(ctx context.Context, query string, args ...interface{}) ([]domain.Webhook, error) {
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]domain.Webhook, 0)
+	for rows.Next() {
+		var webhook domain.Webhook
+		if err := scanWebhook(rows, &webhook); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, nil
+}
+
+const webhookDeliveryColumns = `id, webhook_id, event, payload, status, attempts, next_attempt_at,
+	last_status_code, last_error, delivered_at, created_at, updated_at`
+
+func scanWebhookDelivery(row rowScanner, d *domain.WebhookDelivery, extra ...interface{}) error {
+	dest := []interface{}{
+		&d.ID,
+		&d.WebhookID,
+		&d.Event,
+		&d.Payload,
+		&d.Status,
+		&d.Attempts,
+		&d.NextAttemptAt,
+		&d.LastStatusCode,
+		&d.LastError,
+		&d.DeliveredAt,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
+
+type WebhookDeliveryRepository struct {
+	db *DB
+}
+
+func NewWebhookDeliveryRepository(db *DB) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{db: db}
+}
+
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (id, webhook_id, event, payload, status, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, updated_at`
+
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+	if delivery.Status == "" {
+		delivery.Status = domain.WebhookDeliveryPending
+	}
+
+	q := r.db.GetQuerier(ctx)
+	err := q.QueryRow(ctx, query,
+		delivery.ID,
+		delivery.WebhookID,
+		delivery.Event,
+		delivery.Payload,
+		delivery.Status,
+		delivery.NextAttemptAt,
+	).Scan(&delivery.CreatedAt, &delivery.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	delivery := &domain.WebhookDelivery{}
+	err := scanWebhookDelivery(q.QueryRow(ctx, query, id), delivery)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// ListByWebhook returns a page of the webhook's deliveries, newest first
+func (r *WebhookDeliveryRepository) ListByWebhook(ctx context.Context, webhookID uuid.UUID, params *domain.WebhookDeliveryListParams) ([]domain.WebhookDelivery, int, error) {
+	var status *string
+	if params.Status != nil {
+		s := string(*params.Status)
+		status = &s
+	}
+
+	q := r.db.GetQuerier(ctx)
+	var totalCount int
+	countQuery := `SELECT COUNT(*) FROM webhook_deliveries
+		WHERE webhook_id = $1 AND ($2::varchar IS NULL OR status = $2)`
+	if err := q.QueryRow(ctx, countQuery, webhookID, status).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	page := params.Page
+	if page <= 0 {
+		page = 1
+	}
+	offset := (page - 1) * limit
+
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries
+		WHERE webhook_id = $1 AND ($2::varchar IS NULL OR status = $2)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4`
+
+	rows, err := q.Query(ctx, query, webhookID, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		if err := scanWebhookDelivery(rows, &delivery); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, totalCount, nil
+}
+
+// ClaimDue returns up to limit pending deliveries due by now, with their
+// webhook, and pushes their next attempt to leaseUntil so another worker
+// doesn't send them at the same time
+func (r *WebhookDeliveryRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries d SET next_attempt_at = $2
+		FROM webhooks w
+		WHERE w.id = d.webhook_id AND d.id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts, d.next_attempt_at,
+			d.last_status_code, d.last_error, d.delivered_at, d.created_at, d.updated_at,
+			w.url, w.secret, w.active`
+
+	q := r.db.GetQuerier(ctx)
+	rows, err := q.Query(ctx, query, now, leaseUntil, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		webhook := &domain.Webhook{}
+		if err := scanWebhookDelivery(rows, &delivery, &webhook.URL, &webhook.Secret, &webhook.Active); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		webhook.ID = delivery.WebhookID
+		delivery.Webhook = webhook
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+// RecordAttempt saves the outcome of sending the delivery
+func (r *WebhookDeliveryRepository) RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_status_code = $5,
+		    last_error = $6, delivered_at = $7
+		WHERE id = $1`
+
+	q := r.db.GetQuerier(ctx)
+	_, err := q.Exec(ctx, query,
+		delivery.ID,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastStatusCode,
+		delivery.LastError,
+		delivery.DeliveredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery attempt: %w", err)
+	}
+
+	return nil
+}
+
+// Retry queues a failed delivery to be sent again now, with a fresh set of
+// attempts. It returns ErrDeliveryNotFailed unless the delivery has failed.
+func (r *WebhookDeliveryRepository) Retry(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW()
+		WHERE id = $1 AND status = 'failed'`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to retry webhook delivery: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrDeliveryNotFailed
+	}
+
+	return nil
+}
+
+// DeleteBefore removes up to limit deliveries created before the cutoff and
+// returns how many were deleted
+func (r *WebhookDeliveryRepository) DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	query := `
+		DELETE FROM webhook_deliveries
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE created_at < $1 AND status <> 'pending'
+			LIMIT $2
+		)`
+
+	q := r.db.GetQuerier(ctx)
+	result, err := q.Exec(ctx, query, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,436 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/auction-cards/backend/internal/cache"
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+type AdminService struct {
+	auctionRepo     repository.AuctionRepository
+	bidRepo         repository.BidRepository
+	auditRepo       repository.AuditLogRepository
+	messageRepo     repository.MessageRepository
+	disputeRepo     repository.DisputeRepository
+	reportRepo      repository.ReportRepository
+	moderationRepo  repository.ModerationRepository
+	userMergeRepo   repository.UserMergeRepository
+	txManager       repository.TxManager
+	orderSvc        *OrderService
+	messageSvc      *MessageService
+	notificationSvc *NotificationService
+	webhookSvc      *WebhookService
+	cache           *cache.RedisCache
+}
+
+func NewAdminService(
+	auctionRepo repository.AuctionRepository,
+	bidRepo repository.BidRepository,
+	auditRepo repository.AuditLogRepository,
+	messageRepo repository.MessageRepository,
+	disputeRepo repository.DisputeRepository,
+	reportRepo repository.ReportRepository,
+	moderationRepo repository.ModerationRepository,
+	userMergeRepo repository.UserMergeRepository,
+	txManager repository.TxManager,
+	orderSvc *OrderService,
+	messageSvc *MessageService,
+	notificationSvc *NotificationService,
+	webhookSvc *WebhookService,
+	cache *cache.RedisCache,
+) *AdminService {
+	return &AdminService{
+		auctionRepo:     auctionRepo,
+		bidRepo:         bidRepo,
+		auditRepo:       auditRepo,
+		messageRepo:     messageRepo,
+		disputeRepo:     disputeRepo,
+		reportRepo:      reportRepo,
+		moderationRepo:  moderationRepo,
+		userMergeRepo:   userMergeRepo,
+		txManager:       txManager,
+		orderSvc:        orderSvc,
+		messageSvc:      messageSvc,
+		notificationSvc: notificationSvc,
+		webhookSvc:      webhookSvc,
+		cache:           cache,
+	}
+}
+
+// ForceEndAuction ends an active auction immediately. With the void outcome
+// the auction is cancelled and no one wins; with award the current high
+// bidder wins regardless of the reserve.
+func (s *AdminService) ForceEndAuction(ctx context.Context, adminID, auctionID uuid.UUID, req *domain.ForceEndAuctionRequest) (*domain.Auction, error) {
+	outcome := domain.ForceEndOutcome(req.Outcome)
+
+	// A bid landing while the auction is ended means reading it again
+	var auction *domain.Auction
+	for attempt := 1; ; attempt++ {
+		var err error
+		auction, err = s.forceEnd(ctx, adminID, auctionID, outcome, req.Reason)
+		if errors.Is(err, domain.ErrConcurrentBid) && attempt < closeAuctionAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		break
+	}
+	invalidateAuction(ctx, s.cache, auction.ID)
+
+	// Publish auction ended message
+	if s.cache != nil {
+		message := domain.WSMessage{
+			Type: domain.WSMessageAuctionEnded,
+			Payload: domain.WSAuctionEndedPayload{
+				AuctionID:  auction.ID,
+				WinnerID:   auction.WinnerID,
+				FinalPrice: auction.CurrentPrice,
+				Status:     auction.Status,
+				Forced:     true,
+				Reason:     &req.Reason,
+			},
+		}
+		_ = s.cache.Publish(ctx, cache.AuctionChannel(auction.ID), message)
+	}
+
+	if s.webhookSvc != nil {
+		s.webhookSvc.PublishAuctionEnded(ctx, auction)
+	}
+
+	if s.notificationSvc != nil {
+		ended := *auction
+		go s.notifyForceEnd(context.Background(), &ended, req.Reason)
+	}
+
+	return auction, nil
+}
+
+// forceEnd closes the auction as it was read, failing with ErrConcurrentBid
+// if a bid changed it in the meantime
+func (s *AdminService) forceEnd(ctx context.Context, adminID, auctionID uuid.UUID, outcome domain.ForceEndOutcome, reason string) (*domain.Auction, error) {
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.Status != domain.AuctionStatusActive {
+		return nil, domain.ErrAuctionNotActive
+	}
+
+	var highestBid *domain.Bid
+	if outcome == domain.ForceEndAward {
+		highestBid, err = s.bidRepo.GetHighestBid(ctx, auctionID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	previousStatus := auction.Status
+	status := domain.AuctionStatusUnsold
+	var winnerID, winningBidID *uuid.UUID
+	switch {
+	case outcome == domain.ForceEndVoid:
+		status = domain.AuctionStatusCancelled
+	case highestBid != nil:
+		status = domain.AuctionStatusCompleted
+		winnerID = &highestBid.BidderID
+		winningBidID = &highestBid.ID
+	}
+
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		// Closing bumps the version the end time is then written against
+		closedVersion := auction.Version + 1
+		closed, err := s.auctionRepo.CloseIfActive(txCtx, auction.ID, auction.Version, status, winnerID, winningBidID)
+		if err != nil {
+			return err
+		}
+		if !closed {
+			return domain.ErrAuctionNotActive
+		}
+
+		auction.Status = status
+		auction.WinnerID = winnerID
+		auction.WinningBidID = winningBidID
+		if highestBid != nil {
+			auction.CurrentPrice = highestBid.Amount
+		}
+		auction.EndTime = time.Now()
+		if err := s.auctionRepo.UpdateWithVersion(txCtx, auction, closedVersion); err != nil {
+			return err
+		}
+
+		if auction.Status == domain.AuctionStatusCompleted && s.orderSvc != nil {
+			if _, err := s.orderSvc.CreateForWinner(txCtx, auction, *auction.WinnerID, auction.WinningBidID); err != nil {
+				return err
+			}
+		}
+
+		return s.auditRepo.Create(txCtx, &domain.AuditLog{
+			ActorID:    &adminID,
+			Action:     domain.AuditActionAuctionForceEnd,
+			EntityType: domain.AuditEntityAuction,
+			EntityID:   &auction.ID,
+			Reason:     &reason,
+			Metadata: map[string]interface{}{
+				"outcome":         outcome,
+				"previous_status": previousStatus,
+				"new_status":      auction.Status,
+				"winner_id":       auction.WinnerID,
+				"final_price":     auction.CurrentPrice.StringFixed(2),
+				"bid_count":       auction.BidCount,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return auction, nil
+}
+
+func (s *AdminService) notifyForceEnd(ctx context.Context, auction *domain.Auction, reason string) {
+	bids, _, err := s.bidRepo.GetByAuctionID(ctx, auction.ID, &domain.BidListParams{Page: 1, Limit: 1000})
+	if err != nil {
+		return
+	}
+
+	if auction.Status == domain.AuctionStatusCompleted && auction.WinnerID != nil {
+		s.notificationSvc.NotifyAuctionWon(ctx, *auction.WinnerID, auction)
+		s.notificationSvc.NotifyAuctionSold(ctx, auction.SellerID, auction, *auction.WinnerID)
+	} else {
+		s.notificationSvc.NotifyAuctionCancelled(ctx, auction.SellerID, auction, reason)
+	}
+
+	notified := make(map[uuid.UUID]bool)
+	if auction.WinnerID != nil {
+		notified[*auction.WinnerID] = true
+	}
+
+	for _, bid := range bids {
+		if notified[bid.BidderID] {
+			continue
+		}
+		notified[bid.BidderID] = true
+
+		if auction.Status == domain.AuctionStatusCompleted {
+			s.notificationSvc.NotifyAuctionLost(ctx, bid.BidderID, auction)
+		} else {
+			s.notificationSvc.NotifyAuctionCancelled(ctx, bid.BidderID, auction, reason)
+		}
+	}
+}
+
+// GetConversationTranscript decrypts a conversation for an admin. Access is
+// only granted while the conversation's participants are the buyer and
+// seller of an open dispute, or the reporter and seller of an unresolved
+// report. Every access is audited and both participants are told.
+func (s *AdminService) GetConversationTranscript(ctx context.Context, adminID, conversationID uuid.UUID, req *domain.TranscriptAccessRequest) (*domain.ConversationTranscript, error) {
+	conv, err := s.messageRepo.GetConversationByID(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var auctionID uuid.UUID
+	var partyA, partyB uuid.UUID
+	metadata := map[string]interface{}{}
+
+	if req.DisputeID != nil {
+		dispute, err := s.disputeRepo.GetByID(ctx, *req.DisputeID)
+		if err != nil {
+			return nil, err
+		}
+		if dispute.Status != domain.DisputeStatusOpen {
+			return nil, domain.ErrDisputeClosed
+		}
+		auctionID, partyA, partyB = dispute.AuctionID, dispute.BuyerID, dispute.SellerID
+		metadata["dispute_id"] = dispute.ID
+	} else {
+		report, err := s.reportRepo.GetByID(ctx, *req.ReportID)
+		if err != nil {
+			return nil, err
+		}
+		if report.Status == domain.ReportStatusResolved {
+			return nil, domain.ErrForbidden
+		}
+		auction, err := s.auctionRepo.GetByID(ctx, report.AuctionID)
+		if err != nil {
+			return nil, err
+		}
+		auctionID, partyA, partyB = auction.ID, report.ReporterID, auction.SellerID
+		metadata["report_id"] = report.ID
+	}
+
+	isPair := (conv.ParticipantOne == partyA && conv.ParticipantTwo == partyB) ||
+		(conv.ParticipantOne == partyB && conv.ParticipantTwo == partyA)
+	if !isPair {
+		return nil, domain.ErrForbidden
+	}
+
+	// Record the access before anything is decrypted so a failed audit
+	// write means no access
+	metadata["auction_id"] = auctionID
+	err = s.auditRepo.Create(ctx, &domain.AuditLog{
+		ActorID:    &adminID,
+		Action:     domain.AuditActionTranscriptView,
+		EntityType: domain.AuditEntityConversation,
+		EntityID:   &conv.ID,
+		Reason:     &req.Reason,
+		Metadata:   metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	transcript, err := s.messageSvc.transcript(ctx, conv)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.notificationSvc != nil {
+		go s.notifyTranscriptViewed(context.Background(), auctionID, transcript.Participants)
+	}
+
+	return transcript, nil
+}
+
+func (s *AdminService) notifyTranscriptViewed(ctx context.Context, auctionID uuid.UUID, participants []*domain.PublicUser) {
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return
+	}
+
+	for i, user := range participants {
+		other := participants[1-i]
+		s.notificationSvc.NotifyTranscriptViewed(ctx, user.ID, auction, other.Username)
+	}
+}
+
+// ListModerationCases returns the users escalated for repeated flagged
+// messages, worst offenders first
+func (s *AdminService) ListModerationCases(ctx context.Context, params *domain.ModerationCaseListParams) ([]domain.ModerationCase, int, error) {
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+
+	return s.moderationRepo.List(ctx, params)
+}
+
+// UpdateModerationCase closes an open moderation case as reviewed or
+// dismissed and records the decision in the audit log
+func (s *AdminService) UpdateModerationCase(ctx context.Context, adminID, caseID uuid.UUID, req *domain.UpdateModerationCaseRequest) (*domain.ModerationCase, error) {
+	c, err := s.moderationRepo.GetByID(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status != domain.ModerationCaseOpen {
+		return nil, domain.ErrConflict
+	}
+
+	c.Status = req.Status
+	c.ReviewedBy = &adminID
+
+	var reason *string
+	if req.Note != "" {
+		reason = &req.Note
+	}
+
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.moderationRepo.Update(txCtx, c); err != nil {
+			return err
+		}
+
+		return s.auditRepo.Create(txCtx, &domain.AuditLog{
+			ActorID:    &adminID,
+			Action:     domain.AuditActionModerationClose,
+			EntityType: domain.AuditEntityModeration,
+			EntityID:   &c.ID,
+			Reason:     reason,
+			Metadata: map[string]interface{}{
+				"user_id":       c.UserID,
+				"status":        c.Status,
+				"offense_count": c.OffenseCount,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// errMergeDryRun rolls back a dry-run merge once its report is built
+var errMergeDryRun = errors.New("dry run")
+
+// MergeUsers moves the auctions, bids, ratings, watchlist, messages, orders
+// and related records of a duplicate account onto the primary account in
+// one transaction, then bans the duplicate. A dry run performs the same
+// merge and rolls it back, so its report matches what a real merge would
+// do at that moment.
+func (s *AdminService) MergeUsers(ctx context.Context, adminID, primaryID uuid.UUID, req *domain.MergeUsersRequest) (*domain.UserMergeReport, error) {
+	if primaryID == req.DuplicateID {
+		return nil, domain.ErrMergeSameUser
+	}
+
+	report := &domain.UserMergeReport{
+		PrimaryID:   primaryID,
+		DuplicateID: req.DuplicateID,
+		DryRun:      req.DryRun,
+		Moved:       map[string]int64{},
+		Dropped:     map[string]int64{},
+	}
+
+	err := s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		interacted, err := s.userMergeRepo.HaveInteracted(txCtx, primaryID, req.DuplicateID)
+		if err != nil {
+			return err
+		}
+		if interacted {
+			return domain.ErrMergeInteracted
+		}
+
+		if err := s.userMergeRepo.Merge(txCtx, report); err != nil {
+			return err
+		}
+		if req.DryRun {
+			return errMergeDryRun
+		}
+
+		return s.auditRepo.Create(txCtx, &domain.AuditLog{
+			ActorID:    &adminID,
+			Action:     domain.AuditActionUserMerge,
+			EntityType: domain.AuditEntityUser,
+			EntityID:   &primaryID,
+			Reason:     &req.Reason,
+			Metadata: map[string]interface{}{
+				"duplicate_id": req.DuplicateID,
+				"moved":        report.Moved,
+				"dropped":      report.Dropped,
+			},
+		})
+	})
+	if err != nil && !errors.Is(err, errMergeDryRun) {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (s *AdminService) ListAuditLogs(ctx context.Context, params *domain.AuditLogListParams) ([]domain.AuditLog, int, error) {
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+
+	return s.auditRepo.List(ctx, params)
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/auction-cards/backend/internal/cache"
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// apiUsageRollupInterval is how often the scheduler copies the previous
+	// day's usage counters from Redis to the database
+	apiUsageRollupInterval = time.Hour
+
+	// apiUsageRetention is how long daily usage is kept
+	apiUsageRetention = 90 * 24 * time.Hour
+)
+
+// APILimit is a per-user rate limit shown on the usage dashboard
+type APILimit struct {
+	Name     string
+	Requests int
+	Window   time.Duration
+	// Key returns the limiter's Redis key for a user
+	Key func(userID uuid.UUID) string
+}
+
+// APIUsageService reports signed-in users' usage of the per-user rate
+// limits. The limiter counts usage in Redis by day; a daily rollup copies
+// finished days to the database, so today always comes live from Redis.
+type APIUsageService struct {
+	usageRepo repository.APIUsageRepository
+	cache     *cache.RedisCache
+	limits    []APILimit
+}
+
+func NewAPIUsageService(
+	usageRepo repository.APIUsageRepository,
+	cache *cache.RedisCache,
+	limits []APILimit,
+) *APIUsageService {
+	return &APIUsageService{
+		usageRepo: usageRepo,
+		cache:     cache,
+		limits:    limits,
+	}
+}
+
+// GetUsage reports the user's usage of each limit: what is left of the
+// current rate limit window, and requests made and turned away by day over
+// the dashboard's window
+func (s *APIUsageService) GetUsage(ctx context.Context, userID uuid.UUID, window domain.APIUsageWindow) (*domain.APIUsage, error) {
+	days := window.Days()
+	if days == 0 {
+		return nil, domain.ErrInvalidUsageWindow
+	}
+
+	now := time.Now().UTC()
+	today := now.Truncate(24 * time.Hour)
+	from := today.AddDate(0, 0, -(days - 1))
+
+	saved, err := s.usageRepo.ListByUser(ctx, userID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	// By limit, then by day
+	counted := make(map[string]map[string]domain.APIUsageDay)
+	count := func(day domain.APIUsageDay) {
+		if counted[day.Limit] == nil {
+			counted[day.Limit] = make(map[string]domain.APIUsageDay)
+		}
+		counted[day.Limit][day.Day.Format(time.DateOnly)] = day
+	}
+	for _, day := range saved {
+		count(day)
+	}
+
+	// Today is still being counted, so Redis has the latest
+	if s.cache != nil {
+		live, err := s.cache.GetAPIUsage(ctx, today, userID)
+		if err != nil {
+			log.Printf("Failed to get today's API usage for user %s: %v", userID, err)
+		}
+		for limit, c := range live {
+			count(domain.APIUsageDay{Limit: limit, Day: today, Requests: c.Requests, RateLimited: c.RateLimited})
+		}
+	}
+
+	usage := &domain.APIUsage{
+		Window: window,
+		From:   from,
+		Limits: make([]domain.APILimitUsage, 0, len(s.limits)),
+	}
+	for _, limit := range s.limits {
+		limitUsage := domain.APILimitUsage{
+			Name:          limit.Name,
+			Limit:         limit.Requests,
+			WindowSeconds: int(limit.Window.Seconds()),
+			Remaining:     int64(limit.Requests),
+			Daily:         make([]domain.APIUsageDay, 0, days),
+		}
+
+		for day := from; !day.After(today); day = day.AddDate(0, 0, 1) {
+			c := counted[limit.Name][day.Format(time.DateOnly)]
+			limitUsage.Requests += c.Requests
+			limitUsage.RateLimited += c.RateLimited
+			limitUsage.Daily = append(limitUsage.Daily, domain.APIUsageDay{
+				Day:         day,
+				Requests:    c.Requests,
+				RateLimited: c.RateLimited,
+			})
+		}
+
+		if s.cache != nil {
+			used, reset, err := s.cache.GetRateLimitWindow(ctx, limit.Key(userID))
+			if err != nil {
+				log.Printf("Failed to get %s rate limit for user %s: %v", limit.Name, userID, err)
+			} else {
+				limitUsage.Used = used
+				limitUsage.Remaining = max(int64(limit.Requests)-used, 0)
+				if reset > 0 {
+					resetsAt := now.Add(reset)
+					limitUsage.ResetsAt = &resetsAt
+				}
+			}
+		}
+
+		usage.Limits = append(usage.Limits, limitUsage)
+	}
+
+	return usage, nil
+}
+
+// RollupDay copies every user's usage counters for a day from Redis to the
+// database. Counters are copied whole, so rolling up a day again is harmless.
+func (s *APIUsageService) RollupDay(ctx context.Context, day time.Time) (int, error) {
+	if s.cache == nil {
+		return 0, nil
+	}
+
+	day = day.UTC().Truncate(24 * time.Hour)
+	counts, err := s.cache.GetAPIUsageDay(ctx, day)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read api usage counters: %w", err)
+	}
+
+	days := make([]domain.APIUsageDay, 0, len(counts))
+	for userID, limits := range counts {
+		for limit, c := range limits {
+			days = append(days, domain.APIUsageDay{
+				UserID:      userID,
+				Limit:       limit,
+				Day:         day,
+				Requests:    c.Requests,
+				RateLimited: c.RateLimited,
+			})
+		}
+	}
+
+	if err := s.usageRepo.SaveDays(ctx, days); err != nil {
+		return 0, err
+	}
+	return len(days), nil
+}
+
+// Rollup rolls up yesterday, the latest finished day, and drops usage past
+// its retention
+func (s *APIUsageService) Rollup(ctx context.Context) {
+	yesterday := time.Now().UTC().AddDate(0, 0, -1)
+	if _, err := s.RollupDay(ctx, yesterday); err != nil {
+		log.Printf("Failed to roll up API usage for %s: %v", yesterday.Format(time.DateOnly), err)
+	}
+
+	deleted, err := s.usageRepo.DeleteBefore(ctx, time.Now().Add(-apiUsageRetention))
+	if err != nil {
+		log.Printf("Failed to delete old API usage: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Deleted %d days of API usage past retention", deleted)
+	}
+}
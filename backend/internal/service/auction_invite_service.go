@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/auction-cards/backend/internal/cache"
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/pkg/sharelink"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// AuctionInviteService manages who may see a private auction: users the
+// seller invited and anyone holding one of its share links
+type AuctionInviteService struct {
+	auctionRepo     repository.AuctionRepository
+	inviteRepo      repository.AuctionInviteRepository
+	userRepo        repository.UserRepository
+	notificationSvc *NotificationService
+	signer          *sharelink.Signer
+	cache           *cache.RedisCache
+	linkTTL         time.Duration
+	baseURL         string
+}
+
+func NewAuctionInviteService(
+	auctionRepo repository.AuctionRepository,
+	inviteRepo repository.AuctionInviteRepository,
+	userRepo repository.UserRepository,
+	notificationSvc *NotificationService,
+	signer *sharelink.Signer,
+	cache *cache.RedisCache,
+	linkTTL time.Duration,
+	baseURL string,
+) *AuctionInviteService {
+	return &AuctionInviteService{
+		auctionRepo:     auctionRepo,
+		inviteRepo:      inviteRepo,
+		userRepo:        userRepo,
+		notificationSvc: notificationSvc,
+		signer:          signer,
+		cache:           cache,
+		linkTTL:         linkTTL,
+		baseURL:         baseURL,
+	}
+}
+
+func (s *AuctionInviteService) List(ctx context.Context, auctionID, sellerID uuid.UUID) ([]domain.AuctionInvite, error) {
+	if _, err := s.sellerAuction(ctx, auctionID, sellerID); err != nil {
+		return nil, err
+	}
+
+	return s.inviteRepo.ListByAuction(ctx, auctionID)
+}
+
+// Invite lets the user with the given username see the auction, notifying
+// them. Inviting someone twice keeps the first invite.
+func (s *AuctionInviteService) Invite(ctx context.Context, auctionID, sellerID uuid.UUID, req *domain.InviteUserRequest) (*domain.AuctionInvite, error) {
+	auction, err := s.sellerAuction(ctx, auctionID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByUsername(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsDeleted {
+		return nil, domain.ErrNotFound
+	}
+	if user.ID == sellerID {
+		return nil, domain.ErrBadRequest
+	}
+
+	invite := &domain.AuctionInvite{AuctionID: auctionID, UserID: user.ID, Username: user.Username}
+	if err := s.inviteRepo.Add(ctx, invite); err != nil {
+		return nil, err
+	}
+
+	if s.notificationSvc != nil {
+		go s.notificationSvc.NotifyAuctionInvite(context.Background(), user.ID, auction)
+	}
+
+	return invite, nil
+}
+
+func (s *AuctionInviteService) Revoke(ctx context.Context, auctionID, sellerID, userID uuid.UUID) error {
+	if _, err := s.sellerAuction(ctx, auctionID, sellerID); err != nil {
+		return err
+	}
+
+	return s.inviteRepo.Remove(ctx, auctionID, userID)
+}
+
+// CreateShareLink signs a link granting read access to the auction until
+// it expires or the seller revokes their links
+func (s *AuctionInviteService) CreateShareLink(ctx context.Context, auctionID, sellerID uuid.UUID) (*domain.ShareLink, error) {
+	auction, err := s.sellerAuction(ctx, auctionID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.linkTTL).Truncate(time.Second)
+	token := s.signer.Sign(auction.ID, auction.ShareVersion, expiresAt)
+
+	return &domain.ShareLink{
+		URL:       fmt.Sprintf("%s/auctions/%s?share=%s", s.baseURL, auction.ID, token),
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// RevokeShareLinks invalidates every share link made for the auction so far
+func (s *AuctionInviteService) RevokeShareLinks(ctx context.Context, auctionID, sellerID uuid.UUID) error {
+	if _, err := s.sellerAuction(ctx, auctionID, sellerID); err != nil {
+		return err
+	}
+
+	if _, err := s.inviteRepo.RotateShareVersion(ctx, auctionID); err != nil {
+		return err
+	}
+	// The cached auction still has the old share version
+	invalidateAuction(ctx, s.cache, auctionID)
+
+	return nil
+}
+
+// CanView reports whether a viewer other than the seller may see a private
+// auction, by invite or with a valid share token. Pass uuid.Nil for
+// anonymous viewers and an empty token when none was given.
+func (s *AuctionInviteService) CanView(ctx context.Context, auction *domain.Auction, viewerID uuid.UUID, shareToken string) bool {
+	if shareToken != "" && s.signer.Verify(shareToken, auction.ID, auction.ShareVersion) == nil {
+		return true
+	}
+
+	if viewerID == uuid.Nil {
+		return false
+	}
+	invited, err := s.inviteRepo.IsInvited(ctx, auction.ID, viewerID)
+	return err == nil && invited
+}
+
+// sellerAuction loads an auction for managing its access, which only its
+// seller may do
+func (s *AuctionInviteService) sellerAuction(ctx context.Context, auctionID, sellerID uuid.UUID) (*domain.Auction, error) {
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.SellerID != sellerID {
+		return nil, domain.ErrForbidden
+	}
+
+	return auction, nil
+}
@@ -1,43 +1,109 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
+	"log"
+	"math/rand"
 	"time"
 
+	"github.com/auction-cards/backend/internal/cache"
 	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/pkg/imageproc"
 	"github.com/auction-cards/backend/internal/pkg/storage"
 	"github.com/auction-cards/backend/internal/repository"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
 
+const (
+	// EndTimeSlotCapacity is how many auctions may end in the same second
+	// before publishing moves a new auction's end time
+	EndTimeSlotCapacity = 20
+
+	// EndTimeSpreadWindow bounds how far an end time may be pushed back
+	EndTimeSpreadWindow = 10 * time.Minute
+
+	// facetsCacheTTL is how long facet counts for a filter set are reused
+	facetsCacheTTL = 30 * time.Second
+
+	// soldCacheTTL is how long a page of past sales is reused; sales only
+	// accumulate, so a little staleness is harmless
+	soldCacheTTL = 5 * time.Minute
+
+	// viewDedupWindow is how long repeat views by the same viewer count
+	// once
+	viewDedupWindow = 30 * time.Minute
+
+	// auctionCacheTTL bounds how long a cached auction can outlive a change
+	// that didn't invalidate it, such as a bulk end time extension
+	auctionCacheTTL = 30 * time.Second
+
+	// listCacheTTL is how long the first page of a common listing is
+	// reused; new bids and listings show up on it within this
+	listCacheTTL = 15 * time.Second
+)
+
 type AuctionService struct {
 	auctionRepo      repository.AuctionRepository
 	auctionImageRepo repository.AuctionImageRepository
+	shippingRepo     repository.AuctionShippingRepository
 	categoryRepo     repository.CategoryRepository
+	statsRepo        repository.AuctionStatsRepository
+	watchlistRepo    repository.WatchlistRepository
+	bidRepo          repository.BidRepository
+	txManager        repository.TxManager
 	storage          *storage.S3Storage
+	cache            *cache.RedisCache
+	certificationSvc *CertificationService
+	inviteSvc        *AuctionInviteService
+	bidApprovalSvc   *BidApprovalService
 }
 
 func NewAuctionService(
 	auctionRepo repository.AuctionRepository,
 	auctionImageRepo repository.AuctionImageRepository,
+	shippingRepo repository.AuctionShippingRepository,
 	categoryRepo repository.CategoryRepository,
+	statsRepo repository.AuctionStatsRepository,
+	watchlistRepo repository.WatchlistRepository,
+	bidRepo repository.BidRepository,
+	txManager repository.TxManager,
 	storage *storage.S3Storage,
+	cache *cache.RedisCache,
+	certificationSvc *CertificationService,
+	inviteSvc *AuctionInviteService,
+	bidApprovalSvc *BidApprovalService,
 ) *AuctionService {
 	return &AuctionService{
 		auctionRepo:      auctionRepo,
 		auctionImageRepo: auctionImageRepo,
+		shippingRepo:     shippingRepo,
 		categoryRepo:     categoryRepo,
+		statsRepo:        statsRepo,
+		watchlistRepo:    watchlistRepo,
+		bidRepo:          bidRepo,
+		txManager:        txManager,
 		storage:          storage,
+		cache:            cache,
+		certificationSvc: certificationSvc,
+		inviteSvc:        inviteSvc,
+		bidApprovalSvc:   bidApprovalSvc,
 	}
 }
 
 func (s *AuctionService) Create(ctx context.Context, sellerID uuid.UUID, req *domain.CreateAuctionRequest) (*domain.Auction, error) {
-	startingPrice, err := decimal.NewFromString(req.StartingPrice)
+	startingPrice, err := domain.ParseAmount(req.StartingPrice)
 	if err != nil {
-		return nil, domain.ErrBadRequest
+		return nil, err
+	}
+
+	rules, err := s.listingRules(ctx, req.CategoryID)
+	if err != nil {
+		return nil, err
 	}
 
 	auction := &domain.Auction{
@@ -50,7 +116,27 @@ func (s *AuctionService) Create(ctx context.Context, sellerID uuid.UUID, req *do
 		StartTime:     req.StartTime,
 		EndTime:       req.EndTime,
 		Status:        domain.AuctionStatusDraft,
-		BidIncrement:  decimal.NewFromFloat(1.00),
+		BidIncrement:  defaultBidIncrement(startingPrice),
+		BuyNowRule:    domain.BuyNowUntilFirstBid,
+		AutoRelist:    req.AutoRelist,
+		AcceptsOffers: req.AcceptsOffers,
+		Visibility:    domain.AuctionPublic,
+		Currency:      domain.DefaultCurrency,
+
+		RequiresBidApproval:  req.RequiresBidApproval,
+		BidApprovalQuestions: req.BidApprovalQuestions,
+	}
+
+	if req.BidderRequirements != nil {
+		auction.BidderRequirements = *req.BidderRequirements
+	}
+
+	if req.Visibility != nil {
+		auction.Visibility = domain.AuctionVisibility(*req.Visibility)
+	}
+
+	if req.Currency != nil {
+		auction.Currency = domain.Currency(*req.Currency)
 	}
 
 	if req.Condition != nil {
@@ -59,40 +145,428 @@ func (s *AuctionService) Create(ctx context.Context, sellerID uuid.UUID, req *do
 	}
 
 	if req.ReservePrice != nil {
-		reservePrice, _ := decimal.NewFromString(*req.ReservePrice)
+		reservePrice, err := domain.ParseAmount(*req.ReservePrice)
+		if err != nil {
+			return nil, err
+		}
 		auction.ReservePrice = &reservePrice
 	}
 
 	if req.BuyNowPrice != nil {
-		buyNowPrice, _ := decimal.NewFromString(*req.BuyNowPrice)
+		buyNowPrice, err := domain.ParseAmount(*req.BuyNowPrice)
+		if err != nil {
+			return nil, err
+		}
 		auction.BuyNowPrice = &buyNowPrice
 	}
 
 	if req.BidIncrement != nil {
-		bidIncrement, _ := decimal.NewFromString(*req.BidIncrement)
+		bidIncrement, err := domain.ParseAmount(*req.BidIncrement)
+		if err != nil {
+			return nil, err
+		}
 		auction.BidIncrement = bidIncrement
+	} else if rules != nil {
+		switch {
+		case rules.DefaultBidIncrement != nil:
+			auction.BidIncrement = *rules.DefaultBidIncrement
+		case rules.MinBidIncrement != nil && auction.BidIncrement.LessThan(*rules.MinBidIncrement):
+			auction.BidIncrement = *rules.MinBidIncrement
+		}
 	}
 
-	if err := s.auctionRepo.Create(ctx, auction); err != nil {
+	// The end time may be left to the category's default duration
+	if auction.EndTime.IsZero() {
+		if rules == nil || rules.DefaultDurationHours == nil {
+			return nil, domain.ErrValidation
+		}
+		auction.EndTime = auction.StartTime.Add(time.Duration(*rules.DefaultDurationHours) * time.Hour)
+	}
+
+	if req.BuyNowRule != nil {
+		auction.BuyNowRule = domain.BuyNowRule(*req.BuyNowRule)
+	}
+
+	if req.CertGrader != nil && req.CertNumber != nil {
+		auction.Certification = newCertification(*req.CertGrader, *req.CertNumber)
+	}
+
+	shipping, err := buildShippingOptions(req.ShippingOptions)
+	if err != nil {
 		return nil, err
 	}
 
+	// A new auction has no images yet for its items to show
+	items, err := buildItems(req.Items, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The auction is saved with its items and shipping options or not at all
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.auctionRepo.Create(txCtx, auction); err != nil {
+			return err
+		}
+
+		if len(items) > 0 {
+			if err := s.auctionRepo.ReplaceItems(txCtx, auction.ID, items); err != nil {
+				return err
+			}
+			auction.Items = items
+		}
+
+		if len(shipping) > 0 {
+			if err := s.shippingRepo.ReplaceForAuction(txCtx, auction.ID, shipping); err != nil {
+				return err
+			}
+			auction.Shipping = shipping
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	applyShippingTotals(auction)
+
+	s.certificationSvc.VerifyInBackground(auction)
+
+	auction.SetReserveStatus(true)
+
 	return auction, nil
 }
 
-func (s *AuctionService) GetByID(ctx context.Context, id uuid.UUID, incrementViews bool) (*domain.Auction, error) {
+// GetByID loads an auction for display. The reserve amount is only included
+// when viewerID is the seller; pass uuid.Nil for anonymous viewers. Private
+// auctions are not found unless the viewer may see them or shareToken is a
+// valid share link for them.
+func (s *AuctionService) GetByID(ctx context.Context, id, viewerID uuid.UUID, shareToken string) (*domain.Auction, error) {
+	auction, err := s.loadAuction(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.canView(ctx, auction, viewerID, shareToken) {
+		return nil, domain.ErrNotFound
+	}
+
+	auction.BuyNowAvailable = auction.CanBuyNow()
+	auction.SetReserveStatus(auction.SellerID == viewerID)
+	applyShippingTotals(auction)
+
+	return auction, nil
+}
+
+// Snapshot returns the auction's live state for its websocket room. The
+// high bidder is masked; viewerID, if set, learns whether it is them.
+func (s *AuctionService) Snapshot(ctx context.Context, id, viewerID uuid.UUID, shareToken string) (*domain.WSAuctionSnapshotPayload, error) {
+	auction, err := s.GetByID(ctx, id, viewerID, shareToken)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &domain.WSAuctionSnapshotPayload{
+		AuctionID:       auction.ID,
+		Status:          auction.Status,
+		CurrentPrice:    auction.CurrentPrice,
+		Currency:        auction.Currency,
+		BidCount:        auction.BidCount,
+		ReserveMet:      auction.ReserveMet,
+		BuyNowAvailable: auction.BuyNowAvailable,
+		EndTime:         auction.EndTime,
+		ServerTime:      time.Now(),
+	}
+
+	if auction.BidCount > 0 {
+		highest, err := s.bidRepo.GetHighestBid(ctx, auction.ID)
+		if err != nil {
+			return nil, err
+		}
+		if highest != nil {
+			name := domain.MaskBidder(auction.ID, highest.BidderID)
+			snapshot.HighBidder = &name
+			snapshot.IsHighBidder = viewerID != uuid.Nil && highest.BidderID == viewerID
+		}
+	}
+
+	return snapshot, nil
+}
+
+// RecordView counts a view of the auction's page by viewer, which
+// identifies who is viewing, such as by account or address. Repeat views
+// within viewDedupWindow count once. Views are buffered in Redis and
+// written to the database by the scheduler; without Redis each view is
+// written as it happens.
+func (s *AuctionService) RecordView(ctx context.Context, auctionID uuid.UUID, viewer string) {
+	if s.cache == nil {
+		_ = s.auctionRepo.AddViews(ctx, map[uuid.UUID]int64{auctionID: 1})
+		return
+	}
+
+	// Viewers are hashed so Redis holds no addresses
+	if _, err := s.cache.RecordView(ctx, auctionID, hashToken(viewer), viewDedupWindow); err != nil {
+		log.Printf("Error recording view of auction %s: %v", auctionID, err)
+	}
+}
+
+// comparableSalesWindow is how far back sales count as comparables for a
+// price estimate
+const comparableSalesWindow = 90 * 24 * time.Hour
+
+// EstimatePrice fills the price estimate on an active auction's detail: the
+// range itself for the seller, and for a viewer who set a proxy max bid
+// whether it is unlikely to win. Like viewer state it is best effort; a
+// failed lookup leaves the fields out.
+func (s *AuctionService) EstimatePrice(ctx context.Context, auction *domain.Auction, viewerID uuid.UUID) {
+	if auction.Status != domain.AuctionStatusActive || viewerID == uuid.Nil {
+		return
+	}
+
+	isSeller := auction.SellerID == viewerID
+	var maxAutoBid *decimal.Decimal
+	if !isSeller {
+		if s.bidRepo == nil {
+			return
+		}
+		standings, err := s.bidRepo.GetStandings(ctx, viewerID, []uuid.UUID{auction.ID})
+		if err != nil {
+			log.Printf("Error getting standing of %s in auction %s: %v", viewerID, auction.ID, err)
+			return
+		}
+		maxAutoBid = standings[auction.ID].MaxAutoBid
+		if maxAutoBid == nil {
+			return
+		}
+	}
+
+	bids, err := s.statsRepo.GetBidHistory(ctx, auction.ID)
+	if err != nil {
+		log.Printf("Error getting bid history of auction %s: %v", auction.ID, err)
+		return
+	}
+
+	// Sales in the same category and condition; without a category nothing
+	// sold is comparable enough
+	var comparables *domain.SoldPriceStats
+	if auction.CategoryID != nil {
+		soldFrom := time.Now().Add(-comparableSalesWindow)
+		comparables, err = s.auctionRepo.GetSoldStats(ctx, &domain.SoldListingParams{
+			CategoryID: auction.CategoryID,
+			Condition:  auction.Condition,
+			SoldFrom:   &soldFrom,
+		})
+		if err != nil {
+			log.Printf("Error getting comparable sales for auction %s: %v", auction.ID, err)
+			comparables = nil
+		}
+	}
+
+	estimate := domain.EstimateFinalPrice(auction, bids, comparables, time.Now())
+	if isSeller {
+		auction.PriceEstimate = estimate
+		return
+	}
+	unlikely := estimate.UnlikelyToWin(*maxAutoBid)
+	auction.MaxBidUnlikelyToWin = &unlikely
+}
+
+// CheckEligibility fills whether the viewer can bid on an auction that
+// restricts bidders. Like viewer state it is best effort; a failed lookup
+// leaves it out.
+func (s *AuctionService) CheckEligibility(ctx context.Context, auction *domain.Auction, viewerID uuid.UUID) {
+	if s.bidApprovalSvc == nil || viewerID == uuid.Nil || auction.SellerID == viewerID {
+		return
+	}
+
+	eligibility, err := s.bidApprovalSvc.Eligibility(ctx, auction, viewerID)
+	if err != nil {
+		log.Printf("Error checking eligibility of %s to bid on auction %s: %v", viewerID, auction.ID, err)
+		return
+	}
+	auction.BidEligibility = eligibility
+}
+
+// cachedAuction is an auction as cached, with the fields its JSON leaves out
+type cachedAuction struct {
+	Auction      *domain.Auction `json:"auction"`
+	Version      int             `json:"version"`
+	ShareVersion int             `json:"share_version"`
+}
+
+// loadAuction returns the auction with its details and shipping options as
+// stored, from the cache when it has them. Hot auctions are read far more
+// often than they change, so every change invalidates the cached copy
+// rather than reads going to the database.
+func (s *AuctionService) loadAuction(ctx context.Context, id uuid.UUID) (*domain.Auction, error) {
+	key := cache.AuctionKey(id)
+	if s.cache != nil {
+		var cached cachedAuction
+		if err := s.cache.GetJSON(ctx, key, &cached); err == nil && cached.Auction != nil {
+			cached.Auction.Version = cached.Version
+			cached.Auction.ShareVersion = cached.ShareVersion
+			return cached.Auction, nil
+		}
+	}
+
 	auction, err := s.auctionRepo.GetByIDWithDetails(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	if incrementViews {
-		_ = s.auctionRepo.IncrementViewCount(ctx, id)
+	if shipping, err := s.shippingRepo.GetByAuctionID(ctx, id); err == nil {
+		auction.Shipping = shipping
+	}
+
+	if s.cache != nil {
+		_ = s.cache.SetJSON(ctx, key, cachedAuction{
+			Auction:      auction,
+			Version:      auction.Version,
+			ShareVersion: auction.ShareVersion,
+		}, auctionCacheTTL)
 	}
 
 	return auction, nil
 }
 
+// invalidateAuction drops the cached copy of an auction after it changes.
+// Call it once the change is committed, or a read in between could cache
+// the old version again. Failures are only logged since the copy expires
+// on its own.
+func invalidateAuction(ctx context.Context, redisCache *cache.RedisCache, auctionID uuid.UUID) {
+	if redisCache == nil {
+		return
+	}
+	if err := redisCache.Delete(ctx, cache.AuctionKey(auctionID)); err != nil {
+		log.Printf("Error invalidating cached auction %s: %v", auctionID, err)
+	}
+}
+
+// GetByIDs loads several auctions for display, in request order, by the
+// same rules as GetByID except that views aren't counted and share links
+// don't apply. Drafts are only returned to their seller; other viewers get
+// them listed as missing, like IDs that don't exist.
+func (s *AuctionService) GetByIDs(ctx context.Context, ids []uuid.UUID, viewerID uuid.UUID) (*domain.AuctionBatchResponse, error) {
+	ids = uniqueIDs(ids)
+	if len(ids) > domain.MaxBatchGetIDs {
+		return nil, domain.ErrValidation
+	}
+
+	auctions, err := s.auctionRepo.GetByIDsWithDetails(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[uuid.UUID]*domain.Auction, len(auctions))
+	for i := range auctions {
+		auction := &auctions[i]
+		if auction.Status == domain.AuctionStatusDraft && auction.SellerID != viewerID {
+			continue
+		}
+		if !s.canView(ctx, auction, viewerID, "") {
+			continue
+		}
+		found[auction.ID] = auction
+	}
+
+	shipping := make(map[uuid.UUID][]domain.AuctionShipping)
+	if len(found) > 0 {
+		visible := make([]uuid.UUID, 0, len(found))
+		for id := range found {
+			visible = append(visible, id)
+		}
+		if options, err := s.shippingRepo.GetByAuctionIDs(ctx, visible); err == nil {
+			shipping = options
+		}
+	}
+
+	resp := &domain.AuctionBatchResponse{
+		Auctions: make([]domain.Auction, 0, len(found)),
+		Missing:  make([]uuid.UUID, 0),
+	}
+	for _, id := range ids {
+		auction, ok := found[id]
+		if !ok {
+			resp.Missing = append(resp.Missing, id)
+			continue
+		}
+
+		auction.BuyNowAvailable = auction.CanBuyNow()
+		auction.SetReserveStatus(auction.SellerID == viewerID)
+		if options, ok := shipping[id]; ok {
+			auction.Shipping = options
+			applyShippingTotals(auction)
+		}
+		resp.Auctions = append(resp.Auctions, *auction)
+	}
+
+	return resp, nil
+}
+
+// canView reports whether viewerID may see the auction. Anyone may see a
+// public auction; a private one is limited to its seller, invited users and
+// holders of a share link.
+func (s *AuctionService) canView(ctx context.Context, auction *domain.Auction, viewerID uuid.UUID, shareToken string) bool {
+	if auction.Visibility != domain.AuctionPrivate || auction.SellerID == viewerID {
+		return true
+	}
+	return s.inviteSvc != nil && s.inviteSvc.CanView(ctx, auction, viewerID, shareToken)
+}
+
+// uniqueIDs returns ids without repeats, keeping the first of each
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// GetStats returns view, watcher and bidding analytics for the seller's auction
+func (s *AuctionService) GetStats(ctx context.Context, id, sellerID uuid.UUID) (*domain.AuctionStats, error) {
+	auction, err := s.auctionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.SellerID != sellerID {
+		return nil, domain.ErrForbidden
+	}
+
+	viewsByDay, err := s.statsRepo.GetViewsByDay(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	uniqueBidders, err := s.statsRepo.GetUniqueBidderCount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	bidHistory, err := s.statsRepo.GetBidHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := s.statsRepo.GetShareStats(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AuctionStats{
+		AuctionID:     auction.ID,
+		ViewsCount:    auction.ViewsCount,
+		WatchCount:    auction.WatchCount,
+		BidCount:      auction.BidCount,
+		UniqueBidders: uniqueBidders,
+		ViewsByDay:    viewsByDay,
+		BidHistory:    bidHistory,
+		Shares:        shares,
+	}, nil
+}
+
 func (s *AuctionService) Update(ctx context.Context, id, sellerID uuid.UUID, req *domain.UpdateAuctionRequest) (*domain.Auction, error) {
 	auction, err := s.auctionRepo.GetByID(ctx, id)
 	if err != nil {
@@ -117,37 +591,212 @@ func (s *AuctionService) Update(ctx context.Context, id, sellerID uuid.UUID, req
 		condition := domain.ItemCondition(*req.Condition)
 		auction.Condition = &condition
 	}
+	if req.Currency != nil && domain.Currency(*req.Currency) != auction.Currency {
+		if auction.BidCount > 0 {
+			return nil, domain.ErrCurrencyLocked
+		}
+		auction.Currency = domain.Currency(*req.Currency)
+	}
 	if req.StartingPrice != nil {
-		price, _ := decimal.NewFromString(*req.StartingPrice)
+		price, err := domain.ParseAmount(*req.StartingPrice)
+		if err != nil {
+			return nil, err
+		}
 		auction.StartingPrice = price
 		auction.CurrentPrice = price
 	}
 	if req.ReservePrice != nil {
-		price, _ := decimal.NewFromString(*req.ReservePrice)
+		price, err := domain.ParseAmount(*req.ReservePrice)
+		if err != nil {
+			return nil, err
+		}
+		// Bidders rely on the reserve once bidding is open, so it may only go down
+		if auction.Status == domain.AuctionStatusActive && (auction.ReservePrice == nil || price.GreaterThan(*auction.ReservePrice)) {
+			return nil, domain.ErrReserveNotLowered
+		}
 		auction.ReservePrice = &price
 	}
 	if req.BuyNowPrice != nil {
-		price, _ := decimal.NewFromString(*req.BuyNowPrice)
+		price, err := domain.ParseAmount(*req.BuyNowPrice)
+		if err != nil {
+			return nil, err
+		}
 		auction.BuyNowPrice = &price
 	}
 	if req.BidIncrement != nil {
-		increment, _ := decimal.NewFromString(*req.BidIncrement)
+		increment, err := domain.ParseAmount(*req.BidIncrement)
+		if err != nil {
+			return nil, err
+		}
 		auction.BidIncrement = increment
 	}
+	if req.BuyNowRule != nil {
+		auction.BuyNowRule = domain.BuyNowRule(*req.BuyNowRule)
+	}
 	if req.StartTime != nil {
 		auction.StartTime = *req.StartTime
 	}
 	if req.EndTime != nil {
 		auction.EndTime = *req.EndTime
 	}
+	if req.AutoRelist != nil {
+		auction.AutoRelist = *req.AutoRelist
+	}
+	if req.AcceptsOffers != nil {
+		auction.AcceptsOffers = *req.AcceptsOffers
+	}
+	if req.Visibility != nil {
+		auction.Visibility = domain.AuctionVisibility(*req.Visibility)
+	}
+	if req.RequiresBidApproval != nil {
+		// Bidders who already bid weren't asked for approval
+		if *req.RequiresBidApproval && !auction.RequiresBidApproval && auction.BidCount > 0 {
+			return nil, domain.ErrBidApprovalLocked
+		}
+		auction.RequiresBidApproval = *req.RequiresBidApproval
+	}
+	if req.BidApprovalQuestions != nil {
+		auction.BidApprovalQuestions = req.BidApprovalQuestions
+	}
+	if req.BidderRequirements != nil {
+		auction.BidderRequirements = *req.BidderRequirements
+	}
 
-	if err := s.auctionRepo.Update(ctx, auction); err != nil {
+	certChanged, err := applyCertificationUpdate(auction, req.CertGrader, req.CertNumber)
+	if err != nil {
 		return nil, err
 	}
 
+	var shipping []domain.AuctionShipping
+	if req.ShippingOptions != nil {
+		shipping, err = buildShippingOptions(req.ShippingOptions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var items []domain.AuctionItem
+	if req.Items != nil {
+		images, err := s.auctionImageRepo.GetByAuctionID(ctx, auction.ID)
+		if err != nil {
+			return nil, err
+		}
+		items, err = buildItems(req.Items, images)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The auction is saved with its items and shipping options or not at all
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.auctionRepo.Update(txCtx, auction); err != nil {
+			return err
+		}
+
+		var err error
+		if req.ShippingOptions != nil {
+			if err := s.shippingRepo.ReplaceForAuction(txCtx, auction.ID, shipping); err != nil {
+				return err
+			}
+			auction.Shipping = shipping
+		} else if auction.Shipping, err = s.shippingRepo.GetByAuctionID(txCtx, auction.ID); err != nil {
+			return err
+		}
+
+		if req.Items != nil {
+			if err := s.auctionRepo.ReplaceItems(txCtx, auction.ID, items); err != nil {
+				return err
+			}
+			auction.Items = items
+		} else if auction.Items, err = s.auctionRepo.GetItems(txCtx, auction.ID); err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	scheduleAuctionEnd(ctx, s.cache, auction)
+	invalidateAuction(ctx, s.cache, auction.ID)
+	applyShippingTotals(auction)
+
+	if certChanged {
+		s.certificationSvc.VerifyInBackground(auction)
+	}
+
+	auction.SetReserveStatus(true)
+
 	return auction, nil
 }
 
+// VerifyCertification checks the auction's certification with the grader
+// again, e.g. after the grader was unreachable
+func (s *AuctionService) VerifyCertification(ctx context.Context, id uuid.UUID) (*domain.Certification, error) {
+	auction, err := s.auctionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.certificationSvc == nil {
+		return nil, domain.ErrInternalServer
+	}
+	return s.certificationSvc.Verify(ctx, auction)
+}
+
+func newCertification(grader, number string) *domain.Certification {
+	return &domain.Certification{
+		Grader: domain.Grader(grader),
+		Number: number,
+		Status: domain.CertificationPending,
+	}
+}
+
+// applyCertificationUpdate replaces or removes the auction's certification,
+// reporting whether it changed. Bidders rely on it, so it is fixed once the
+// first bid is in.
+func applyCertificationUpdate(auction *domain.Auction, grader, number *string) (bool, error) {
+	if grader != nil && *grader == "" {
+		grader = nil
+	}
+	if grader == nil && number == nil {
+		return false, nil
+	}
+
+	current := auction.Certification
+	var updated *domain.Certification
+	if number == nil || *number != "" {
+		// Either half may be sent alone to correct just that half
+		if current == nil && (grader == nil || number == nil) {
+			return false, domain.ErrBadRequest
+		}
+		updated = &domain.Certification{Status: domain.CertificationPending}
+		if current != nil {
+			updated.Grader, updated.Number = current.Grader, current.Number
+		}
+		if grader != nil {
+			updated.Grader = domain.Grader(*grader)
+		}
+		if number != nil {
+			updated.Number = *number
+		}
+	}
+
+	switch {
+	case current == nil && updated == nil:
+		return false, nil
+	case current != nil && updated != nil && current.Grader == updated.Grader && current.Number == updated.Number:
+		return false, nil
+	}
+
+	if auction.BidCount > 0 {
+		return false, domain.ErrCertificationLocked
+	}
+	auction.Certification = updated
+
+	return true, nil
+}
+
 func (s *AuctionService) Delete(ctx context.Context, id, sellerID uuid.UUID) error {
 	auction, err := s.auctionRepo.GetByID(ctx, id)
 	if err != nil {
@@ -161,11 +810,16 @@ func (s *AuctionService) Delete(ctx context.Context, id, sellerID uuid.UUID) err
 
 	// Delete images from storage
 	images, _ := s.auctionImageRepo.GetByAuctionID(ctx, id)
-	for _, img := range images {
-		_ = s.storage.Delete(ctx, img.URL)
+	for i := range images {
+		s.deleteImageFiles(ctx, &images[i])
+	}
+
+	if err := s.auctionRepo.Delete(ctx, id); err != nil {
+		return err
 	}
+	invalidateAuction(ctx, s.cache, id)
 
-	return s.auctionRepo.Delete(ctx, id)
+	return nil
 }
 
 func (s *AuctionService) Publish(ctx context.Context, id, sellerID uuid.UUID) (*domain.Auction, error) {
@@ -184,42 +838,97 @@ func (s *AuctionService) Publish(ctx context.Context, id, sellerID uuid.UUID) (*
 		return nil, domain.ErrAuctionNotDraft
 	}
 
+	// A live event's lots open when the auctioneer calls them
+	if auction.LiveEventID != nil {
+		return nil, domain.ErrLiveEventLot
+	}
+
 	// Validate auction has required data
 	if auction.StartTime.Before(time.Now()) {
 		// If start time is in the past, set to now
 		auction.StartTime = time.Now()
 	}
 
+	if err := s.checkListingRules(ctx, auction); err != nil {
+		return nil, err
+	}
+
+	// Spread end times so crowded seconds don't spike the scheduler
+	endTime, err := s.SuggestEndTime(ctx, auction.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	auction.EndTime = endTime
+
 	auction.Status = domain.AuctionStatusActive
 
 	if err := s.auctionRepo.Update(ctx, auction); err != nil {
 		return nil, err
 	}
+	scheduleAuctionEnd(ctx, s.cache, auction)
+	invalidateAuction(ctx, s.cache, auction.ID)
+
+	auction.SetReserveStatus(true)
 
 	return auction, nil
 }
 
-func (s *AuctionService) List(ctx context.Context, params *domain.AuctionListParams) (*domain.AuctionListResponse, error) {
-	auctions, totalCount, err := s.auctionRepo.List(ctx, params)
+// SuggestEndTime returns the desired end time if its second has room, or
+// else a random one of the least crowded seconds within EndTimeSpreadWindow
+// after it. Picking randomly among ties keeps concurrent publishes from
+// piling onto the same replacement slot.
+func (s *AuctionService) SuggestEndTime(ctx context.Context, desired time.Time) (time.Time, error) {
+	desired = desired.Truncate(time.Second)
+
+	load, err := s.auctionRepo.GetEndTimeLoad(ctx, desired, desired.Add(EndTimeSpreadWindow))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if load[desired.Unix()] < EndTimeSlotCapacity {
+		return desired, nil
+	}
+
+	best := -1
+	var candidates []int64
+	for offset := int64(0); offset < int64(EndTimeSpreadWindow/time.Second); offset++ {
+		slot := desired.Unix() + offset
+		count := load[slot]
+		switch {
+		case best < 0 || count < best:
+			best = count
+			candidates = []int64{slot}
+		case count == best:
+			candidates = append(candidates, slot)
+		}
+	}
+
+	return time.Unix(candidates[rand.Intn(len(candidates))], 0), nil
+}
+
+// List returns a page of auctions. Reserve amounts are hidden except on the
+// viewer's own auctions; a nil viewerID is for admin callers and reveals all.
+func (s *AuctionService) List(ctx context.Context, params *domain.AuctionListParams, viewerID *uuid.UUID) (*domain.AuctionListResponse, error) {
+	if params.SortBy == "discovery" && params.Seed == "" {
+		params.Seed = discoverySeed(viewerID)
+	}
+
+	auctions, totalCount, err := s.listPage(ctx, params, isCommonListing(params, viewerID))
 	if err != nil {
 		return nil, err
 	}
 
-	// Fetch first image for each auction
-	if len(auctions) > 0 {
+	for i := range auctions {
+		auctions[i].BuyNowAvailable = auctions[i].CanBuyNow()
+		auctions[i].SetReserveStatus(viewerID == nil || auctions[i].SellerID == *viewerID)
+	}
+
+	if len(auctions) > 0 && viewerID != nil && *viewerID != uuid.Nil {
 		auctionIDs := make([]uuid.UUID, len(auctions))
 		for i, a := range auctions {
 			auctionIDs[i] = a.ID
 		}
-
-		images, err := s.auctionImageRepo.GetFirstImageByAuctionIDs(ctx, auctionIDs)
-		if err == nil {
-			for i := range auctions {
-				if img, ok := images[auctions[i].ID]; ok {
-					auctions[i].Images = []domain.AuctionImage{img}
-				}
-			}
-		}
+		s.setViewerState(ctx, auctions, auctionIDs, *viewerID)
 	}
 
 	limit := params.Limit
@@ -234,10 +943,242 @@ func (s *AuctionService) List(ctx context.Context, params *domain.AuctionListPar
 		TotalCount: totalCount,
 		Page:       params.Page,
 		TotalPages: totalPages,
+		Seed:       params.Seed,
 	}, nil
 }
 
+// cachedAuctionList is a page of auctions as cached, before any
+// viewer-specific changes
+type cachedAuctionList struct {
+	Auctions   []domain.Auction `json:"auctions"`
+	TotalCount int              `json:"total_count"`
+}
+
+// listPage fetches a page of auctions with the first image of each. Pages
+// of common listings are cached briefly and not invalidated, since they
+// change with every bid anywhere on them.
+func (s *AuctionService) listPage(ctx context.Context, params *domain.AuctionListParams, common bool) ([]domain.Auction, int, error) {
+	var key string
+	if common && s.cache != nil {
+		key = cache.AuctionListKey(listFilterHash(params))
+		var cached *cachedAuctionList
+		if err := s.cache.GetJSON(ctx, key, &cached); err == nil && cached != nil {
+			return cached.Auctions, cached.TotalCount, nil
+		}
+	}
+
+	auctions, totalCount, err := s.auctionRepo.List(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Fetch first image for each auction
+	if len(auctions) > 0 {
+		auctionIDs := make([]uuid.UUID, len(auctions))
+		for i, a := range auctions {
+			auctionIDs[i] = a.ID
+		}
+
+		images, err := s.auctionImageRepo.GetFirstImageByAuctionIDs(ctx, auctionIDs)
+		if err == nil {
+			for i := range auctions {
+				if img, ok := images[auctions[i].ID]; ok {
+					auctions[i].Images = []domain.AuctionImage{img.ForListing()}
+				}
+			}
+		}
+	}
+
+	if key != "" {
+		_ = s.cache.SetJSON(ctx, key, cachedAuctionList{Auctions: auctions, TotalCount: totalCount}, listCacheTTL)
+	}
+
+	return auctions, totalCount, nil
+}
+
+// isCommonListing reports whether a listing is the kind most visitors load:
+// the first page of active auctions, in one category or all of them, seen
+// by someone not signed in. Those look the same to every such visitor, so
+// they can be cached.
+func isCommonListing(params *domain.AuctionListParams, viewerID *uuid.UUID) bool {
+	if viewerID == nil || *viewerID != uuid.Nil || (params.ViewerID != nil && *params.ViewerID != uuid.Nil) {
+		return false
+	}
+	if params.Page > 1 || params.SortBy == "discovery" {
+		return false
+	}
+	if params.Status == nil || *params.Status != domain.AuctionStatusActive {
+		return false
+	}
+
+	return params.SellerID == nil && params.Search == nil && params.MinPrice == nil && params.MaxPrice == nil &&
+		params.CertStatus == nil && params.Condition == nil && params.EndsWithin == nil &&
+		params.HasBuyNow == nil && params.HasReserve == nil && !params.FreeShipping && params.MinSellerRating == nil
+}
+
+// listFilterHash identifies a common listing by what can vary between them
+func listFilterHash(params *domain.AuctionListParams) string {
+	data, _ := json.Marshal(struct {
+		CategoryID      *uuid.UUID
+		SortBy          string
+		Limit           int
+		HideVacationing bool
+		HidePrivate     bool
+	}{params.CategoryID, params.SortBy, params.Limit, params.HideVacationing, params.HidePrivate})
+	return hashToken(string(data))
+}
+
+// Facets counts the auctions matching the filters by category, condition
+// and price bucket. Counts are cached briefly per filter set, so they leave
+// out private auctions for every viewer.
+func (s *AuctionService) Facets(ctx context.Context, params *domain.AuctionListParams) (*domain.AuctionFacets, error) {
+	params.ViewerID = nil
+
+	key := cache.AuctionFacetsKey(facetsFilterHash(params))
+	if s.cache != nil {
+		var cached *domain.AuctionFacets
+		if err := s.cache.GetJSON(ctx, key, &cached); err == nil && cached != nil {
+			return cached, nil
+		}
+	}
+
+	facets, err := s.auctionRepo.GetFacets(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cache != nil {
+		_ = s.cache.SetJSON(ctx, key, facets, facetsCacheTTL)
+	}
+
+	return facets, nil
+}
+
+// facetsFilterHash identifies a filter set, leaving out paging and ordering
+func facetsFilterHash(params *domain.AuctionListParams) string {
+	filters := *params
+	filters.Page, filters.Limit, filters.SortBy, filters.Seed = 0, 0, "", ""
+
+	data, _ := json.Marshal(struct {
+		Filters         domain.AuctionListParams
+		HideVacationing bool
+		HidePrivate     bool
+	}{filters, params.HideVacationing, params.HidePrivate})
+	return hashToken(string(data))
+}
+
+// ListSold returns a page of past sales matching the filters with price
+// statistics across all of them. Results are cached per filter set.
+func (s *AuctionService) ListSold(ctx context.Context, params *domain.SoldListingParams) (*domain.SoldListingResponse, error) {
+	data, _ := json.Marshal(params)
+	key := cache.SoldListingsKey(hashToken(string(data)))
+	if s.cache != nil {
+		var cached *domain.SoldListingResponse
+		if err := s.cache.GetJSON(ctx, key, &cached); err == nil && cached != nil {
+			return cached, nil
+		}
+	}
+
+	sales, totalCount, err := s.auctionRepo.ListSold(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.auctionRepo.GetSoldStats(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sales) > 0 {
+		auctionIDs := make([]uuid.UUID, len(sales))
+		for i, sale := range sales {
+			auctionIDs[i] = sale.AuctionID
+		}
+
+		images, err := s.auctionImageRepo.GetFirstImageByAuctionIDs(ctx, auctionIDs)
+		if err == nil {
+			for i := range sales {
+				if img, ok := images[sales[i].AuctionID]; ok {
+					listing := img.ForListing()
+					sales[i].Image = &listing
+				}
+			}
+		}
+	}
+
+	result := &domain.SoldListingResponse{Sales: sales, Stats: *stats, TotalCount: totalCount}
+	if s.cache != nil {
+		_ = s.cache.SetJSON(ctx, key, result, soldCacheTTL)
+	}
+
+	return result, nil
+}
+
+// discoverySeed picks a discovery order for a client that sent no seed.
+// Signed-in viewers keep theirs for the day; anyone else gets a new one to
+// send back with later pages.
+func discoverySeed(viewerID *uuid.UUID) string {
+	if viewerID != nil && *viewerID != uuid.Nil {
+		return viewerID.String() + ":" + time.Now().UTC().Format("2006-01-02")
+	}
+	return uuid.NewString()
+}
+
+// setViewerState marks which auctions the viewer watches and where they
+// stand in those they bid on. Like listing images it is best effort: a
+// failed lookup leaves the fields out rather than failing the list.
+func (s *AuctionService) setViewerState(ctx context.Context, auctions []domain.Auction, auctionIDs []uuid.UUID, viewerID uuid.UUID) {
+	if s.watchlistRepo != nil {
+		watched, err := s.watchlistRepo.GetWatched(ctx, viewerID, auctionIDs)
+		if err == nil {
+			for i := range auctions {
+				isWatching := watched[auctions[i].ID]
+				auctions[i].IsWatching = &isWatching
+			}
+		}
+	}
+
+	if s.bidRepo != nil {
+		standings, err := s.bidRepo.GetStandings(ctx, viewerID, auctionIDs)
+		if err == nil {
+			for i := range auctions {
+				standing, ok := standings[auctions[i].ID]
+				amHighBidder := ok && standing.IsHighBidder
+				auctions[i].AmHighBidder = &amHighBidder
+				if ok {
+					maxBid := standing.MaxBid
+					auctions[i].MyMaxBid = &maxBid
+				}
+			}
+		}
+	}
+}
+
+// MaxImagesPerUpload caps how many files a single bulk upload may contain
+const MaxImagesPerUpload = 10
+
+// ImageUpload is one file of an image upload request
+type ImageUpload struct {
+	Reader      io.Reader
+	ContentType string
+	Size        int64
+}
+
 func (s *AuctionService) UploadImage(ctx context.Context, auctionID, sellerID uuid.UUID, reader io.Reader, contentType string, size int64) (*domain.AuctionImage, error) {
+	images, err := s.UploadImages(ctx, auctionID, sellerID, []ImageUpload{{Reader: reader, ContentType: contentType, Size: size}})
+	if err != nil {
+		return nil, err
+	}
+	return &images[0], nil
+}
+
+// UploadImages stores each file together with its resized variants. Either
+// every image is saved or none are.
+func (s *AuctionService) UploadImages(ctx context.Context, auctionID, sellerID uuid.UUID, files []ImageUpload) ([]domain.AuctionImage, error) {
+	if len(files) == 0 || len(files) > MaxImagesPerUpload {
+		return nil, domain.ErrValidation
+	}
+
 	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
 	if err != nil {
 		return nil, err
@@ -253,43 +1194,104 @@ func (s *AuctionService) UploadImage(ctx context.Context, auctionID, sellerID uu
 		return nil, domain.ErrAuctionNotDraft
 	}
 
-	// Validate content type
-	if !storage.ValidateImageContentType(contentType) {
-		return nil, errors.New("invalid image type")
+	for _, file := range files {
+		// Validate content type
+		if !storage.ValidateImageContentType(file.ContentType) {
+			return nil, errors.New("invalid image type")
+		}
+
+		// Validate size
+		if file.Size > storage.MaxImageSize {
+			return nil, errors.New("image too large")
+		}
+	}
+
+	// Get current image count for position
+	existing, _ := s.auctionImageRepo.GetByAuctionID(ctx, auctionID)
+	position := len(existing)
+
+	images := make([]domain.AuctionImage, 0, len(files))
+	for i, file := range files {
+		image, err := s.storeImage(ctx, auctionID, file, position+i)
+		if err != nil {
+			for _, stored := range images {
+				s.removeImage(ctx, &stored)
+			}
+			return nil, err
+		}
+		images = append(images, *image)
 	}
+	invalidateAuction(ctx, s.cache, auctionID)
 
-	// Validate size
-	if size > storage.MaxImageSize {
+	return images, nil
+}
+
+// storeImage uploads the original and its variants, then saves the record
+func (s *AuctionService) storeImage(ctx context.Context, auctionID uuid.UUID, file ImageUpload, position int) (*domain.AuctionImage, error) {
+	data, err := io.ReadAll(io.LimitReader(file.Reader, storage.MaxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > storage.MaxImageSize {
 		return nil, errors.New("image too large")
 	}
 
-	// Get current image count for position
-	images, _ := s.auctionImageRepo.GetByAuctionID(ctx, auctionID)
-	position := len(images)
+	variants, err := imageproc.GenerateVariants(bytes.NewReader(data), file.ContentType)
+	if err != nil {
+		return nil, domain.ErrValidation
+	}
 
 	// Upload to S3
 	folder := storage.GetImageFolder(auctionID)
-	url, err := s.storage.Upload(ctx, reader, contentType, size, folder)
+	url, err := s.storage.Upload(ctx, bytes.NewReader(data), file.ContentType, int64(len(data)), folder)
 	if err != nil {
 		return nil, err
 	}
 
-	// Save to database
 	image := &domain.AuctionImage{
 		AuctionID: auctionID,
 		URL:       url,
 		Position:  position,
 	}
 
+	for _, variant := range variants {
+		variantURL, err := s.storage.Upload(ctx, bytes.NewReader(variant.Data), variant.ContentType, int64(len(variant.Data)), folder)
+		if err != nil {
+			s.deleteImageFiles(ctx, image)
+			return nil, err
+		}
+
+		switch variant.Size {
+		case imageproc.SizeThumbnail:
+			image.ThumbnailURL = &variantURL
+		case imageproc.SizeMedium:
+			image.MediumURL = &variantURL
+		case imageproc.SizeLarge:
+			image.LargeURL = &variantURL
+		}
+	}
+
+	// Save to database
 	if err := s.auctionImageRepo.Create(ctx, image); err != nil {
-		// Try to delete uploaded file
-		_ = s.storage.Delete(ctx, url)
+		// Try to delete uploaded files
+		s.deleteImageFiles(ctx, image)
 		return nil, err
 	}
 
 	return image, nil
 }
 
+func (s *AuctionService) removeImage(ctx context.Context, image *domain.AuctionImage) {
+	s.deleteImageFiles(ctx, image)
+	_ = s.auctionImageRepo.Delete(ctx, image.ID)
+}
+
+func (s *AuctionService) deleteImageFiles(ctx context.Context, image *domain.AuctionImage) {
+	for _, url := range image.URLs() {
+		_ = s.storage.Delete(ctx, url)
+	}
+}
+
 func (s *AuctionService) DeleteImage(ctx context.Context, auctionID, imageID, sellerID uuid.UUID) error {
 	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
 	if err != nil {
@@ -325,18 +1327,234 @@ func (s *AuctionService) DeleteImage(ctx context.Context, auctionID, imageID, se
 	}
 
 	// Delete from storage
-	_ = s.storage.Delete(ctx, imageToDelete.URL)
+	s.deleteImageFiles(ctx, imageToDelete)
 
 	// Delete from database
-	return s.auctionImageRepo.Delete(ctx, imageID)
+	if err := s.auctionImageRepo.Delete(ctx, imageID); err != nil {
+		return err
+	}
+	invalidateAuction(ctx, s.cache, auctionID)
+
+	return nil
 }
 
-func (s *AuctionService) GetCategories(ctx context.Context) ([]domain.Category, error) {
-	return s.categoryRepo.GetWithAuctionCounts(ctx)
+// GetCategories lists the categories with their active auction counts, each
+// in the first of the languages it has a translation into
+func (s *AuctionService) GetCategories(ctx context.Context, languages []string) ([]domain.Category, error) {
+	categories, err := s.categoryRepo.GetWithAuctionCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	translating := make([]*domain.Category, len(categories))
+	for i := range categories {
+		translating[i] = &categories[i]
+	}
+	if err := s.translateCategories(ctx, languages, translating...); err != nil {
+		return nil, err
+	}
+	return categories, nil
 }
 
-func (s *AuctionService) GetCategoryBySlug(ctx context.Context, slug string) (*domain.Category, error) {
-	return s.categoryRepo.GetBySlug(ctx, slug)
+func (s *AuctionService) GetCategoryBySlug(ctx context.Context, slug string, languages []string) (*domain.Category, error) {
+	category, err := s.categoryRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.translateCategories(ctx, languages, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// TranslateCategory shows an auction's category in the first of the
+// languages it has a translation into. Like viewer state it is best effort;
+// a failed lookup leaves the category's own name.
+func (s *AuctionService) TranslateCategory(ctx context.Context, auction *domain.Auction, languages []string) {
+	if auction.Category == nil || len(languages) == 0 {
+		return
+	}
+
+	// The loaded auction's category may be shared with the cache
+	category := *auction.Category
+	if err := s.translateCategories(ctx, languages, &category); err != nil {
+		log.Printf("Error translating category of auction %s: %v", auction.ID, err)
+		return
+	}
+	auction.Category = &category
+}
+
+// translateCategories applies to each category its translation into the
+// earliest of the languages that has one
+func (s *AuctionService) translateCategories(ctx context.Context, languages []string, categories ...*domain.Category) error {
+	if len(languages) == 0 || len(categories) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(categories))
+	for i, category := range categories {
+		ids[i] = category.ID
+	}
+	translations, err := s.categoryRepo.GetTranslations(ctx, ids, languages)
+	if err != nil {
+		return err
+	}
+
+	rank := make(map[string]int, len(languages))
+	for i, language := range languages {
+		rank[language] = i
+	}
+	best := make(map[uuid.UUID]*domain.CategoryTranslation, len(translations))
+	for i := range translations {
+		translation := &translations[i]
+		if current, ok := best[translation.CategoryID]; !ok || rank[translation.Language] < rank[current.Language] {
+			best[translation.CategoryID] = translation
+		}
+	}
+
+	for _, category := range categories {
+		if translation, ok := best[category.ID]; ok {
+			category.Translate(translation)
+		}
+	}
+	return nil
+}
+
+// GetListingRules returns the listing rules of the category with the slug,
+// empty if it has none
+func (s *AuctionService) GetListingRules(ctx context.Context, slug string) (*domain.CategoryListingRules, error) {
+	category, err := s.categoryRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.listingRules(ctx, &category.ID)
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		rules = &domain.CategoryListingRules{CategoryID: category.ID}
+	}
+	return rules, nil
+}
+
+// listingRules returns the category's listing rules, or nil when there is no
+// category or it has none
+func (s *AuctionService) listingRules(ctx context.Context, categoryID *uuid.UUID) (*domain.CategoryListingRules, error) {
+	if categoryID == nil {
+		return nil, nil
+	}
+
+	rules, err := s.categoryRepo.GetListingRules(ctx, *categoryID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	return rules, err
+}
+
+// checkListingRules returns a ListingRulesError if the auction doesn't meet
+// its category's listing rules
+func (s *AuctionService) checkListingRules(ctx context.Context, auction *domain.Auction) error {
+	rules, err := s.listingRules(ctx, auction.CategoryID)
+	if err != nil || rules == nil {
+		return err
+	}
+
+	images, err := s.auctionImageRepo.GetByAuctionID(ctx, auction.ID)
+	if err != nil {
+		return err
+	}
+	violations := rules.Check(auction, len(images))
+	if violations == nil {
+		return nil
+	}
+
+	category, err := s.categoryRepo.GetByID(ctx, *auction.CategoryID)
+	if err != nil {
+		return err
+	}
+	return &domain.ListingRulesError{Category: category.Name, Violations: violations}
+}
+
+// buildShippingOptions converts request shipping options into domain values.
+// Free and pickup-only options always carry a zero cost.
+func buildShippingOptions(reqs []domain.ShippingOptionRequest) ([]domain.AuctionShipping, error) {
+	options := make([]domain.AuctionShipping, 0, len(reqs))
+	for _, req := range reqs {
+		opt := domain.AuctionShipping{
+			Method:  domain.ShippingMethod(req.Method),
+			Cost:    decimal.Zero,
+			Regions: req.Regions,
+		}
+
+		switch opt.Method {
+		case domain.ShippingMethodFlatRate:
+			if req.Cost == nil {
+				return nil, domain.ErrInvalidShipping
+			}
+			cost, err := domain.ParseAmount(*req.Cost)
+			if err != nil || cost.IsNegative() {
+				return nil, domain.ErrInvalidShipping
+			}
+			opt.Cost = cost
+		case domain.ShippingMethodFree, domain.ShippingMethodPickupOnly:
+		default:
+			return nil, domain.ErrInvalidShipping
+		}
+
+		options = append(options, opt)
+	}
+	return options, nil
+}
+
+// buildItems turns lot item requests into items. Every image they reference
+// must be one of the auction's images, used by one item at most.
+func buildItems(reqs []domain.AuctionItemRequest, images []domain.AuctionImage) ([]domain.AuctionItem, error) {
+	available := make(map[uuid.UUID]bool, len(images))
+	for _, img := range images {
+		available[img.ID] = true
+	}
+
+	items := make([]domain.AuctionItem, 0, len(reqs))
+	for _, req := range reqs {
+		item := domain.AuctionItem{
+			Title:       req.Title,
+			Description: req.Description,
+			ImageIDs:    make([]uuid.UUID, 0, len(req.ImageIDs)),
+		}
+		if req.Condition != nil {
+			condition := domain.ItemCondition(*req.Condition)
+			item.Condition = &condition
+		}
+
+		for _, imageID := range req.ImageIDs {
+			if !available[imageID] {
+				return nil, domain.ErrInvalidItemImage
+			}
+			available[imageID] = false
+			item.ImageIDs = append(item.ImageIDs, imageID)
+		}
+
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// defaultBidIncrement is the increment of a listing that doesn't set one:
+// the increment ladder's at the starting price, or 1.00 without a ladder
+func defaultBidIncrement(startingPrice decimal.Decimal) decimal.Decimal {
+	if increment := domain.BidIncrementLadder.Increment(startingPrice); increment.IsPositive() {
+		return increment
+	}
+	return decimal.NewFromFloat(1.00)
+}
+
+// applyShippingTotals fills in the total a buyer would pay at the current price
+func applyShippingTotals(auction *domain.Auction) {
+	for i := range auction.Shipping {
+		auction.Shipping[i].TotalCost = auction.CurrentPrice.Add(auction.Shipping[i].Cost)
+	}
 }
 
 // Admin methods
@@ -347,5 +1565,11 @@ func (s *AuctionService) AdminUpdateStatus(ctx context.Context, id uuid.UUID, st
 	}
 
 	auction.Status = status
-	return s.auctionRepo.Update(ctx, auction)
+	if err := s.auctionRepo.Update(ctx, auction); err != nil {
+		return err
+	}
+	scheduleAuctionEnd(ctx, s.cache, auction)
+	invalidateAuction(ctx, s.cache, auction.ID)
+
+	return nil
 }
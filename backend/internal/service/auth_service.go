@@ -4,24 +4,79 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/hex"
 	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
 	"time"
 
+	"github.com/auction-cards/backend/internal/cache"
 	"github.com/auction-cards/backend/internal/domain"
 	"github.com/auction-cards/backend/internal/pkg/email"
 	"github.com/auction-cards/backend/internal/pkg/jwt"
+	"github.com/auction-cards/backend/internal/pkg/oauth"
 	"github.com/auction-cards/backend/internal/pkg/password"
+	"github.com/auction-cards/backend/internal/pkg/username"
 	"github.com/auction-cards/backend/internal/repository"
 	"github.com/google/uuid"
 )
 
+const (
+	// maxTokenAttempts wrong guesses against a user's token replace it
+	maxTokenAttempts = 5
+
+	// maxSelectorGuesses bounds lookups of one token selector per
+	// tokenGuessWindow, whoever makes them
+	maxSelectorGuesses = 10
+	tokenGuessWindow   = time.Hour
+
+	// tokenGuessAlertThreshold failed guesses across all users within
+	// tokenGuessAlertWindow raise a security alert
+	tokenGuessAlertThreshold = 100
+	tokenGuessAlertWindow    = 10 * time.Minute
+
+	// loginCodeTTL bounds how long an emailed login code stays valid, and
+	// maxLoginCodeAttempts wrong guesses void it
+	loginCodeTTL         = 10 * time.Minute
+	maxLoginCodeAttempts = 5
+
+	// loginHistoryLimit is how many recent sign-ins the user is shown
+	loginHistoryLimit = 50
+
+	// reauthWindow is how recently an OAuth-only user must have signed in
+	// to change how they sign in, and oauthLinkTTL how long they then have
+	// to finish linking a provider
+	reauthWindow = 10 * time.Minute
+	oauthLinkTTL = 10 * time.Minute
+
+	// oauthUsernameMaxDigits bounds the numeric suffix tried on generated
+	// usernames that are taken
+	oauthUsernameMaxDigits = 8
+)
+
+// SessionPolicy limits concurrent sessions and flags unusual logins
+type SessionPolicy struct {
+	// Roles always limited to one active session
+	SingleSessionRoles []domain.UserRole
+	// Logins from two countries within this window are reported to the user
+	CountryHopWindow time.Duration
+}
+
 type AuthService struct {
 	userRepo         repository.UserRepository
 	oauthRepo        repository.OAuthAccountRepository
 	refreshTokenRepo repository.RefreshTokenRepository
+	deviceRepo       repository.UserDeviceRepository
+	loginEventRepo   repository.LoginEventRepository
+	auditRepo        repository.AuditLogRepository
 	jwtManager       *jwt.Manager
 	emailSender      email.Sender
+	notificationSvc  *NotificationService
+	cache            *cache.RedisCache
+	sessionPolicy    SessionPolicy
 	baseURL          string
 }
 
@@ -29,16 +84,28 @@ func NewAuthService(
 	userRepo repository.UserRepository,
 	oauthRepo repository.OAuthAccountRepository,
 	refreshTokenRepo repository.RefreshTokenRepository,
+	deviceRepo repository.UserDeviceRepository,
+	loginEventRepo repository.LoginEventRepository,
+	auditRepo repository.AuditLogRepository,
 	jwtManager *jwt.Manager,
 	emailSender email.Sender,
+	notificationSvc *NotificationService,
+	cache *cache.RedisCache,
+	sessionPolicy SessionPolicy,
 	baseURL string,
 ) *AuthService {
 	return &AuthService{
 		userRepo:         userRepo,
 		oauthRepo:        oauthRepo,
 		refreshTokenRepo: refreshTokenRepo,
+		deviceRepo:       deviceRepo,
+		loginEventRepo:   loginEventRepo,
+		auditRepo:        auditRepo,
 		jwtManager:       jwtManager,
 		emailSender:      emailSender,
+		notificationSvc:  notificationSvc,
+		cache:            cache,
+		sessionPolicy:    sessionPolicy,
 		baseURL:          baseURL,
 	}
 }
@@ -70,6 +137,7 @@ func (s *AuthService) Register(ctx context.Context, req *domain.RegisterRequest)
 
 	// Generate verification token
 	verificationToken := generateToken()
+	verificationDigest := tokenDigest(verificationToken)
 
 	// Create user
 	user := &domain.User{
@@ -78,7 +146,7 @@ func (s *AuthService) Register(ctx context.Context, req *domain.RegisterRequest)
 		PasswordHash:           &hashedPassword,
 		Role:                   domain.RoleUser,
 		EmailVerified:          false,
-		EmailVerificationToken: &verificationToken,
+		EmailVerificationToken: &verificationDigest,
 	}
 
 	if err := s.userRepo.Create(ctx, user); err != nil {
@@ -92,7 +160,7 @@ func (s *AuthService) Register(ctx context.Context, req *domain.RegisterRequest)
 	return user, nil
 }
 
-func (s *AuthService) Login(ctx context.Context, req *domain.LoginRequest) (*domain.AuthResponse, string, error) {
+func (s *AuthService) Login(ctx context.Context, req *domain.LoginRequest, client *domain.LoginClient) (*domain.AuthResponse, string, error) {
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
@@ -116,31 +184,73 @@ func (s *AuthService) Login(ctx context.Context, req *domain.LoginRequest) (*dom
 		return nil, "", domain.ErrUserBanned
 	}
 
-	// Generate tokens
-	accessToken, err := s.jwtManager.GenerateAccessToken(user.ID, string(user.Role))
+	device, err := s.recordDevice(ctx, user, client)
 	if err != nil {
 		return nil, "", err
 	}
 
-	refreshToken, expiresAt, err := s.jwtManager.GenerateRefreshToken(user.ID)
+	// Two-factor sign-in only asks for a code on devices not yet trusted
+	if user.TwoFactorEnabled && (device == nil || !device.Trusted) {
+		return s.challengeLogin(ctx, user, device)
+	}
+
+	return s.startSession(ctx, user, client, device, domain.LoginMethodPassword, "")
+}
+
+// VerifyLogin completes a two-factor sign-in with the code emailed by Login.
+// The code only works from the device it was sent for.
+func (s *AuthService) VerifyLogin(ctx context.Context, req *domain.VerifyLoginRequest, client *domain.LoginClient) (*domain.AuthResponse, string, error) {
+	if s.deviceRepo == nil || client == nil || client.DeviceToken == "" {
+		return nil, "", domain.ErrTokenInvalid
+	}
+
+	device, err := s.deviceRepo.GetByID(ctx, req.ChallengeID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, "", domain.ErrTokenInvalid
+	}
 	if err != nil {
 		return nil, "", err
 	}
 
-	// Store refresh token
-	tokenHash := hashToken(refreshToken)
-	if err := s.refreshTokenRepo.Create(ctx, &domain.RefreshToken{
-		UserID:    user.ID,
-		TokenHash: tokenHash,
-		ExpiresAt: expiresAt,
-	}); err != nil {
+	if subtle.ConstantTimeCompare([]byte(device.Fingerprint), []byte(hashToken(client.DeviceToken))) != 1 ||
+		device.ChallengeDigest == nil || device.ChallengeExpiresAt == nil {
+		return nil, "", domain.ErrTokenInvalid
+	}
+	if time.Now().After(*device.ChallengeExpiresAt) {
+		return nil, "", domain.ErrTokenExpired
+	}
+
+	if subtle.ConstantTimeCompare([]byte(*device.ChallengeDigest), []byte(hashToken(req.Code))) != 1 {
+		cleared, err := s.deviceRepo.RecordChallengeFailure(ctx, device.ID, maxLoginCodeAttempts)
+		if err != nil {
+			log.Printf("Error recording login code failure for device %s: %v", device.ID, err)
+		} else if cleared {
+			// The user has to sign in with their password again for a new code
+			return nil, "", domain.ErrTooManyAttempts
+		}
+		return nil, "", domain.ErrTokenInvalid
+	}
+
+	if err := s.deviceRepo.ClearChallenge(ctx, device.ID); err != nil {
 		return nil, "", err
 	}
 
-	return &domain.AuthResponse{
-		User:        user,
-		AccessToken: accessToken,
-	}, refreshToken, nil
+	if req.TrustDevice {
+		device.Trusted = true
+		if err := s.deviceRepo.Update(ctx, device); err != nil {
+			return nil, "", err
+		}
+	}
+
+	user, err := s.userRepo.GetByID(ctx, device.UserID)
+	if err != nil {
+		return nil, "", err
+	}
+	if user.IsBanned {
+		return nil, "", domain.ErrUserBanned
+	}
+
+	return s.startSession(ctx, user, client, device, domain.LoginMethodPassword, "")
 }
 
 func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
@@ -171,6 +281,9 @@ func (s *AuthService) RefreshAccessToken(ctx context.Context, refreshToken strin
 	if user.IsBanned {
 		return "", domain.ErrUserBanned
 	}
+	if user.IsDeleted {
+		return "", domain.ErrTokenInvalid
+	}
 
 	// Generate new access token
 	accessToken, err := s.jwtManager.GenerateAccessToken(userID, string(user.Role))
@@ -182,9 +295,9 @@ func (s *AuthService) RefreshAccessToken(ctx context.Context, refreshToken strin
 }
 
 func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
-	user, err := s.userRepo.GetByVerificationToken(ctx, token)
+	user, err := s.lookupToken(ctx, domain.TokenEmailVerification, token)
 	if err != nil {
-		return domain.ErrTokenInvalid
+		return err
 	}
 
 	user.EmailVerified = true
@@ -200,11 +313,17 @@ func (s *AuthService) ForgotPassword(ctx context.Context, req *domain.ForgotPass
 		return nil
 	}
 
-	// Generate reset token
+	return s.sendPasswordReset(ctx, user)
+}
+
+// sendPasswordReset saves a new reset token on the user, along with any
+// other pending changes, and emails the reset link
+func (s *AuthService) sendPasswordReset(ctx context.Context, user *domain.User) error {
 	resetToken := generateToken()
+	resetDigest := tokenDigest(resetToken)
 	expires := time.Now().Add(1 * time.Hour)
 
-	user.PasswordResetToken = &resetToken
+	user.PasswordResetToken = &resetDigest
 	user.PasswordResetExpires = &expires
 
 	if err := s.userRepo.Update(ctx, user); err != nil {
@@ -218,10 +337,23 @@ func (s *AuthService) ForgotPassword(ctx context.Context, req *domain.ForgotPass
 	return nil
 }
 
+// ResendVerification emails a new verification link to an unverified user,
+// replacing the previous one. Unknown and verified emails are ignored so the
+// response doesn't reveal which accounts exist.
+func (s *AuthService) ResendVerification(ctx context.Context, req *domain.ResendVerificationRequest) error {
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil || user.EmailVerified {
+		return nil
+	}
+
+	s.reissueVerificationToken(ctx, user)
+	return nil
+}
+
 func (s *AuthService) ResetPassword(ctx context.Context, req *domain.ResetPasswordRequest) error {
-	user, err := s.userRepo.GetByPasswordResetToken(ctx, req.Token)
+	user, err := s.lookupToken(ctx, domain.TokenPasswordReset, req.Token)
 	if err != nil {
-		return domain.ErrTokenInvalid
+		return err
 	}
 
 	// Hash new password
@@ -242,9 +374,145 @@ func (s *AuthService) ResetPassword(ctx context.Context, req *domain.ResetPasswo
 	return s.refreshTokenRepo.DeleteByUserID(ctx, user.ID)
 }
 
-func (s *AuthService) GetOrCreateOAuthUser(ctx context.Context, provider, providerUserID, email, username string) (*domain.User, error) {
+// lookupToken returns the user holding an emailed token. The token is found
+// by its selector and its digest compared in constant time, so lookups don't
+// leak how much of a guess matched. Wrong guesses count against the token,
+// which is replaced after maxTokenAttempts.
+func (s *AuthService) lookupToken(ctx context.Context, kind domain.TokenKind, token string) (*domain.User, error) {
+	if len(token) <= domain.TokenSelectorLength {
+		s.recordTokenGuessFailure(ctx, kind)
+		return nil, domain.ErrTokenInvalid
+	}
+	selector := token[:domain.TokenSelectorLength]
+
+	if err := s.checkSelectorGuesses(ctx, kind, selector); err != nil {
+		return nil, err
+	}
+
+	var user *domain.User
+	var err error
+	var stored *string
+	switch kind {
+	case domain.TokenEmailVerification:
+		user, err = s.userRepo.GetByVerificationTokenSelector(ctx, selector)
+		if err == nil {
+			stored = user.EmailVerificationToken
+		}
+	case domain.TokenPasswordReset:
+		user, err = s.userRepo.GetByPasswordResetTokenSelector(ctx, selector)
+		if err == nil {
+			stored = user.PasswordResetToken
+		}
+	}
+	if errors.Is(err, domain.ErrNotFound) {
+		s.recordTokenGuessFailure(ctx, kind)
+		return nil, domain.ErrTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if stored == nil || subtle.ConstantTimeCompare([]byte(*stored), []byte(tokenDigest(token))) != 1 {
+		s.recordTokenGuessFailure(ctx, kind)
+
+		cleared, err := s.userRepo.RecordTokenFailure(ctx, user.ID, kind, maxTokenAttempts)
+		if err != nil {
+			log.Printf("Error recording %s token failure for user %s: %v", kind, user.ID, err)
+		} else if cleared {
+			// Not reissued here, or every lockout would mail the guesser's
+			// target a new link; the user can ask for one again
+			log.Printf("Invalidated %s token for user %s after %d failed attempts", kind, user.ID, maxTokenAttempts)
+		}
+		return nil, domain.ErrTokenInvalid
+	}
+
+	return user, nil
+}
+
+// checkSelectorGuesses limits lookups of one selector across all clients,
+// so a leaked token prefix can't be brute-forced from many addresses
+func (s *AuthService) checkSelectorGuesses(ctx context.Context, kind domain.TokenKind, selector string) error {
+	if s.cache == nil {
+		return nil
+	}
+
+	key := cache.RateLimitKeyTokenSelector(string(kind), hashToken(selector))
+	count, _, err := s.cache.IncrementRateLimit(ctx, key, tokenGuessWindow)
+	if err != nil {
+		log.Printf("Error counting token selector guesses: %v", err)
+		return nil
+	}
+	if count > maxSelectorGuesses {
+		return domain.ErrTooManyAttempts
+	}
+
+	return nil
+}
+
+// recordTokenGuessFailure counts a failed guess across all users and raises
+// an alert the first time the count crosses tokenGuessAlertThreshold within
+// a window
+func (s *AuthService) recordTokenGuessFailure(ctx context.Context, kind domain.TokenKind) {
+	if s.cache == nil {
+		return
+	}
+
+	count, _, err := s.cache.IncrementRateLimit(ctx, cache.TokenFailuresKey, tokenGuessAlertWindow)
+	if err != nil {
+		log.Printf("Error counting failed token guesses: %v", err)
+		return
+	}
+	if count != tokenGuessAlertThreshold {
+		return
+	}
+
+	log.Printf("SECURITY ALERT: %d failed token guesses within %s, latest against %s tokens",
+		count, tokenGuessAlertWindow, kind)
+
+	if s.auditRepo == nil {
+		return
+	}
+	reason := "anomalous volume of failed token guesses"
+	if err := s.auditRepo.Create(ctx, &domain.AuditLog{
+		Action:     domain.AuditActionTokenGuessing,
+		EntityType: domain.AuditEntityAuthToken,
+		Reason:     &reason,
+		Metadata: map[string]interface{}{
+			"failed_guesses": count,
+			"window_seconds": int(tokenGuessAlertWindow.Seconds()),
+			"token_kind":     kind,
+		},
+	}); err != nil {
+		log.Printf("Error recording token guessing alert: %v", err)
+	}
+}
+
+// reissueVerificationToken replaces the user's verification token and emails
+// the new link
+func (s *AuthService) reissueVerificationToken(ctx context.Context, user *domain.User) {
+	verificationToken := generateToken()
+	verificationDigest := tokenDigest(verificationToken)
+	user.EmailVerificationToken = &verificationDigest
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		log.Printf("Error reissuing verification token for user %s: %v", user.ID, err)
+		return
+	}
+
+	emailData := email.NewVerificationEmail(user.Email, verificationToken, s.baseURL)
+	_ = s.emailSender.Send(emailData)
+}
+
+// GetOrCreateOAuthUser returns the user linked to the provider account.
+// Linked logins are matched by the provider's user ID only, so a later
+// change of email on either side can't move a login to another user. An
+// unlinked login is linked to the user with the same email only when the
+// provider verified it; otherwise ErrEmailAlreadyExists is returned and the
+// user must confirm the link from a signed-in session. With no user for the
+// email, a new one is created.
+func (s *AuthService) GetOrCreateOAuthUser(ctx context.Context, provider string, profile *oauth.Profile) (*domain.User, error) {
 	// Check if OAuth account exists
-	oauthAccount, err := s.oauthRepo.GetByProviderUserID(ctx, provider, providerUserID)
+	oauthAccount, err := s.oauthRepo.GetByProviderUserID(ctx, provider, profile.ID)
 	if err == nil {
 		// Get existing user
 		return s.userRepo.GetByID(ctx, oauthAccount.UserID)
@@ -254,39 +522,172 @@ func (s *AuthService) GetOrCreateOAuthUser(ctx context.Context, provider, provid
 		return nil, err
 	}
 
+	if profile.Email == "" {
+		return nil, domain.ErrValidation
+	}
+
 	// Check if user with email exists
-	user, err := s.userRepo.GetByEmail(ctx, email)
+	via := domain.OAuthLinkVerifiedEmail
+	user, err := s.userRepo.GetByEmail(ctx, profile.Email)
 	if errors.Is(err, domain.ErrNotFound) {
-		// Create new user
-		user = &domain.User{
-			Email:         email,
-			Username:      username,
-			Role:          domain.RoleUser,
-			EmailVerified: true, // OAuth emails are pre-verified
-		}
-
-		if err := s.userRepo.Create(ctx, user); err != nil {
+		user, err = s.createOAuthUser(ctx, profile)
+		if err != nil {
 			return nil, err
 		}
+		via = domain.OAuthLinkSignup
 	} else if err != nil {
 		return nil, err
+	} else if !profile.EmailVerified {
+		// Anyone can claim an address the provider hasn't verified, so it
+		// must not grant access to the existing account
+		return nil, domain.ErrEmailAlreadyExists
 	}
 
-	// Create OAuth account link
-	oauthAccount = &domain.OAuthAccount{
-		UserID:         user.ID,
-		Provider:       provider,
-		ProviderUserID: providerUserID,
+	if _, err := s.linkOAuthAccount(ctx, user.ID, provider, profile, via); err != nil {
+		return nil, err
 	}
 
-	if err := s.oauthRepo.Create(ctx, oauthAccount); err != nil {
-		return nil, err
+	if via == domain.OAuthLinkVerifiedEmail && !user.EmailVerified {
+		if err := s.claimUnverifiedAccount(ctx, user); err != nil {
+			return nil, err
+		}
 	}
 
 	return user, nil
 }
 
-func (s *AuthService) GenerateTokens(ctx context.Context, user *domain.User) (*domain.AuthResponse, string, error) {
+// createOAuthUser signs up the owner of a provider login. Their username is
+// derived from the profile, with a random numeric suffix while taken, and
+// they're asked to choose their own.
+func (s *AuthService) createOAuthUser(ctx context.Context, profile *oauth.Profile) (*domain.User, error) {
+	user := &domain.User{
+		Email:                 profile.Email,
+		Role:                  domain.RoleUser,
+		EmailVerified:         profile.EmailVerified,
+		UsernameSetupRequired: true,
+	}
+
+	base := username.Base(profile.Name, profile.Email)
+	candidate := base
+	for digits := 2; digits <= oauthUsernameMaxDigits; digits++ {
+		_, err := s.userRepo.GetByUsername(ctx, candidate)
+		if errors.Is(err, domain.ErrNotFound) {
+			user.Username = candidate
+			err = s.userRepo.Create(ctx, user)
+			if err == nil {
+				return user, nil
+			}
+			// Another signup may have taken it since the lookup
+			if !errors.Is(err, domain.ErrUsernameExists) {
+				return nil, err
+			}
+		} else if err != nil {
+			return nil, err
+		}
+
+		// Widen the range each time so crowded names still resolve quickly
+		low := int64(1)
+		for i := 1; i < digits; i++ {
+			low *= 10
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(9*low))
+		if err != nil {
+			return nil, err
+		}
+		candidate = username.WithSuffix(base, int(low+n.Int64()))
+	}
+
+	return nil, domain.ErrUsernameExists
+}
+
+// claimUnverifiedAccount hands an account whose email was never verified to
+// the owner of that email, as proven by the provider. Whoever registered it
+// may not own the address, so their password and sessions stop working.
+func (s *AuthService) claimUnverifiedAccount(ctx context.Context, user *domain.User) error {
+	if err := s.refreshTokenRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	user.PasswordHash = nil
+	user.EmailVerified = true
+	user.EmailVerificationToken = nil
+	return s.userRepo.Update(ctx, user)
+}
+
+// pendingOAuthLink is a provider login waiting for the owner of the account
+// with its email to confirm the link
+type pendingOAuthLink struct {
+	Provider string         `json:"provider"`
+	Profile  *oauth.Profile `json:"profile"`
+}
+
+// StashOAuthLink holds a provider login that matched an account by an
+// unverified email, returning the token that ConfirmOAuthLink takes
+func (s *AuthService) StashOAuthLink(ctx context.Context, provider string, profile *oauth.Profile) (string, error) {
+	if s.cache == nil {
+		return "", fmt.Errorf("confirming provider logins requires the cache")
+	}
+
+	token := generateToken()
+	pending := &pendingOAuthLink{Provider: provider, Profile: profile}
+	if err := s.cache.SetJSON(ctx, cache.OAuthPendingLinkKey(hashToken(token)), pending, oauthLinkTTL); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// ConfirmOAuthLink links a stashed provider login to the signed-in user
+// after they re-authenticate. The login must have matched this user's email;
+// ErrNotFound means there is no such login waiting.
+func (s *AuthService) ConfirmOAuthLink(ctx context.Context, userID uuid.UUID, token string, req *domain.ReauthRequest, refreshToken string) (*domain.OAuthAccount, error) {
+	if s.cache == nil || token == "" {
+		return nil, domain.ErrNotFound
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.reauthenticate(ctx, user, req, refreshToken); err != nil {
+		return nil, err
+	}
+
+	key := cache.OAuthPendingLinkKey(hashToken(token))
+	var pending *pendingOAuthLink
+	if err := s.cache.GetJSON(ctx, key, &pending); err != nil {
+		return nil, err
+	}
+	if pending == nil || pending.Profile == nil || !strings.EqualFold(pending.Profile.Email, user.Email) {
+		return nil, domain.ErrNotFound
+	}
+	_ = s.cache.Delete(ctx, key)
+
+	return s.linkOAuthAccount(ctx, userID, pending.Provider, pending.Profile, domain.OAuthLinkLoginConfirmation)
+}
+
+// GenerateTokens starts a session for a user the caller has already
+// authenticated through an OAuth provider. Two-factor sign-in is not asked
+// for here: whoever controls the provider account also controls the email
+// the code would go to.
+func (s *AuthService) GenerateTokens(ctx context.Context, user *domain.User, provider string, client *domain.LoginClient) (*domain.AuthResponse, string, error) {
+	device, err := s.recordDevice(ctx, user, client)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return s.startSession(ctx, user, client, device, domain.LoginMethodOAuth, provider)
+}
+
+// startSession issues tokens tied to the device. Users limited to a single
+// session have their other sessions revoked, and a login from a new country
+// soon after the previous one is reported to the user. Deleted accounts
+// can't sign in, whichever way they authenticated.
+func (s *AuthService) startSession(ctx context.Context, user *domain.User, client *domain.LoginClient, device *domain.Device, method domain.LoginMethod, provider string) (*domain.AuthResponse, string, error) {
+	if user.IsDeleted {
+		return nil, "", domain.ErrInvalidCredentials
+	}
+
 	accessToken, err := s.jwtManager.GenerateAccessToken(user.ID, string(user.Role))
 	if err != nil {
 		return nil, "", err
@@ -297,13 +698,28 @@ func (s *AuthService) GenerateTokens(ctx context.Context, user *domain.User) (*d
 		return nil, "", err
 	}
 
+	s.checkLoginLocation(ctx, user, client)
+	s.recordLoginEvent(ctx, user, client, device, method, provider)
+
+	// Access tokens already issued stay valid until they expire, but the
+	// other sessions can no longer refresh them
+	if s.requiresSingleSession(user) {
+		if err := s.refreshTokenRepo.DeleteByUserID(ctx, user.ID); err != nil {
+			return nil, "", err
+		}
+	}
+
 	// Store refresh token
 	tokenHash := hashToken(refreshToken)
-	if err := s.refreshTokenRepo.Create(ctx, &domain.RefreshToken{
+	token := &domain.RefreshToken{
 		UserID:    user.ID,
 		TokenHash: tokenHash,
 		ExpiresAt: expiresAt,
-	}); err != nil {
+	}
+	if device != nil {
+		token.DeviceID = &device.ID
+	}
+	if err := s.refreshTokenRepo.Create(ctx, token); err != nil {
 		return nil, "", err
 	}
 
@@ -313,6 +729,583 @@ func (s *AuthService) GenerateTokens(ctx context.Context, user *domain.User) (*d
 	}, refreshToken, nil
 }
 
+// recordDevice notes the sign-in against the client's device, returning nil
+// when the client has no device cookie
+func (s *AuthService) recordDevice(ctx context.Context, user *domain.User, client *domain.LoginClient) (*domain.Device, error) {
+	if s.deviceRepo == nil || client == nil || client.DeviceToken == "" {
+		return nil, nil
+	}
+
+	device := &domain.Device{
+		UserID:      user.ID,
+		Fingerprint: hashToken(client.DeviceToken),
+	}
+	if client.UserAgent != "" {
+		userAgent := client.UserAgent
+		if len(userAgent) > 500 {
+			userAgent = userAgent[:500]
+		}
+		device.UserAgent = &userAgent
+	}
+	if client.IP != "" {
+		device.LastIP = &client.IP
+	}
+
+	if err := s.deviceRepo.Touch(ctx, device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// recordLoginEvent adds the sign-in to the user's login history. A failure
+// is logged rather than failing the sign-in.
+func (s *AuthService) recordLoginEvent(ctx context.Context, user *domain.User, client *domain.LoginClient, device *domain.Device, method domain.LoginMethod, provider string) {
+	if s.loginEventRepo == nil {
+		return
+	}
+
+	event := &domain.LoginEvent{
+		UserID: user.ID,
+		Method: method,
+	}
+	if provider != "" {
+		event.Provider = &provider
+	}
+	if client != nil {
+		if client.IP != "" {
+			event.IP = &client.IP
+		}
+		if country := normalizeCountry(client.Country); country != "" {
+			event.Country = &country
+		}
+	}
+	if device != nil {
+		event.DeviceID = &device.ID
+		event.UserAgent = device.UserAgent
+	}
+
+	if err := s.loginEventRepo.Create(ctx, event); err != nil {
+		log.Printf("Error recording login event for user %s: %v", user.ID, err)
+	}
+}
+
+// challengeLogin emails a one-time code for the device instead of starting
+// a session. The device ID doubles as the challenge ID.
+func (s *AuthService) challengeLogin(ctx context.Context, user *domain.User, device *domain.Device) (*domain.AuthResponse, string, error) {
+	if device == nil {
+		// There is nothing to bind the code to, so the login can't complete
+		return nil, "", domain.ErrBadRequest
+	}
+
+	code, err := generateLoginCode()
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.deviceRepo.SetChallenge(ctx, device.ID, hashToken(code), time.Now().Add(loginCodeTTL)); err != nil {
+		return nil, "", err
+	}
+
+	_ = s.emailSender.Send(email.NewLoginCodeEmail(user.Email, code, loginCodeTTL))
+
+	return &domain.AuthResponse{
+		TwoFactorRequired: true,
+		ChallengeID:       &device.ID,
+	}, "", nil
+}
+
+// SetTwoFactor turns two-factor sign-in on or off. Codes go to the user's
+// email, so it must be verified first.
+func (s *AuthService) SetTwoFactor(ctx context.Context, userID uuid.UUID, req *domain.SetTwoFactorRequest) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Enabled && !user.EmailVerified {
+		return nil, domain.ErrEmailNotVerified
+	}
+
+	user.TwoFactorEnabled = req.Enabled
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ListDevices returns the devices the user has signed in from, most recent
+// first, marking the one holding currentDeviceToken
+func (s *AuthService) ListDevices(ctx context.Context, userID uuid.UUID, currentDeviceToken string) ([]domain.Device, error) {
+	devices, err := s.deviceRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if currentDeviceToken != "" {
+		fingerprint := hashToken(currentDeviceToken)
+		for i := range devices {
+			devices[i].Current = devices[i].Fingerprint == fingerprint
+		}
+	}
+
+	return devices, nil
+}
+
+// UpdateDevice renames the device or changes whether it is trusted
+func (s *AuthService) UpdateDevice(ctx context.Context, userID, deviceID uuid.UUID, req *domain.UpdateDeviceRequest) (*domain.Device, error) {
+	device, err := s.getOwnDevice(ctx, userID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		device.Name = &name
+	}
+	if req.Trusted != nil {
+		device.Trusted = *req.Trusted
+	}
+
+	if err := s.deviceRepo.Update(ctx, device); err != nil {
+		return nil, err
+	}
+
+	return device, nil
+}
+
+// DeleteDevice forgets the device and signs it out. Its next sign-in counts
+// as an unrecognized device.
+func (s *AuthService) DeleteDevice(ctx context.Context, userID, deviceID uuid.UUID) error {
+	if _, err := s.getOwnDevice(ctx, userID, deviceID); err != nil {
+		return err
+	}
+
+	return s.deviceRepo.Delete(ctx, deviceID)
+}
+
+// getOwnDevice loads one of the user's devices. Other users' devices are
+// reported as not found so their IDs can't be probed.
+func (s *AuthService) getOwnDevice(ctx context.Context, userID, deviceID uuid.UUID) (*domain.Device, error) {
+	device, err := s.deviceRepo.GetByID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if device.UserID != userID {
+		return nil, domain.ErrNotFound
+	}
+	return device, nil
+}
+
+// ListLoginHistory returns the user's most recent sign-ins, newest first
+func (s *AuthService) ListLoginHistory(ctx context.Context, userID uuid.UUID) ([]domain.LoginEvent, error) {
+	return s.loginEventRepo.ListByUserID(ctx, userID, loginHistoryLimit)
+}
+
+// ReportLogin handles a user saying a sign-in wasn't theirs. Every session
+// is revoked, the device it came from loses its trust and the password stops
+// working until it is reset through the link emailed to the user.
+func (s *AuthService) ReportLogin(ctx context.Context, userID, eventID uuid.UUID) error {
+	event, err := s.loginEventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if event.UserID != userID {
+		return domain.ErrNotFound
+	}
+
+	if err := s.refreshTokenRepo.DeleteByUserID(ctx, userID); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = nil
+	if err := s.sendPasswordReset(ctx, user); err != nil {
+		return err
+	}
+
+	if event.DeviceID != nil && s.deviceRepo != nil {
+		device, err := s.deviceRepo.GetByID(ctx, *event.DeviceID)
+		if err == nil && device.Trusted {
+			device.Trusted = false
+			err = s.deviceRepo.Update(ctx, device)
+		}
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			log.Printf("Error untrusting device %s: %v", *event.DeviceID, err)
+		}
+	}
+
+	if err := s.loginEventRepo.MarkReported(ctx, eventID); err != nil {
+		return err
+	}
+
+	if s.auditRepo != nil {
+		if err := s.auditRepo.Create(ctx, &domain.AuditLog{
+			ActorID:    &userID,
+			Action:     domain.AuditActionLoginReport,
+			EntityType: domain.AuditEntityLoginEvent,
+			EntityID:   &eventID,
+			Metadata: map[string]interface{}{
+				"ip":      event.IP,
+				"country": event.Country,
+				"method":  event.Method,
+			},
+		}); err != nil {
+			log.Printf("Error recording reported login %s: %v", eventID, err)
+		}
+	}
+
+	return nil
+}
+
+// ListSignInMethods returns the user's password status and linked provider
+// logins. Available lists the configured providers not linked yet.
+func (s *AuthService) ListSignInMethods(ctx context.Context, userID uuid.UUID, providers []string) (*domain.SignInMethods, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.oauthRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	methods := &domain.SignInMethods{
+		HasPassword: user.PasswordHash != nil,
+		Linked:      accounts,
+		Available:   []string{},
+	}
+	if methods.Linked == nil {
+		methods.Linked = []domain.OAuthAccount{}
+	}
+	for _, provider := range providers {
+		if findOAuthAccount(accounts, provider) == nil {
+			methods.Available = append(methods.Available, provider)
+		}
+	}
+
+	return methods, nil
+}
+
+// SetPassword adds a password to an account that only signs in through
+// OAuth, so the user isn't locked out when the provider is
+func (s *AuthService) SetPassword(ctx context.Context, userID uuid.UUID, req *domain.SetPasswordRequest, refreshToken string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.PasswordHash != nil {
+		return domain.ErrPasswordAlreadySet
+	}
+
+	if err := s.reauthenticate(ctx, user, &domain.ReauthRequest{}, refreshToken); err != nil {
+		return err
+	}
+
+	hashedPassword, err := password.Hash(req.Password)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = &hashedPassword
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	s.auditSignInChange(ctx, userID, domain.AuditActionPasswordSet, nil)
+	return nil
+}
+
+// BeginOAuthLink records that the OAuth flow started with state links the
+// provider login to the user rather than signing in
+func (s *AuthService) BeginOAuthLink(ctx context.Context, userID uuid.UUID, provider, state string, req *domain.ReauthRequest, refreshToken string) error {
+	if s.cache == nil {
+		return fmt.Errorf("linking provider logins requires the cache")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.reauthenticate(ctx, user, req, refreshToken); err != nil {
+		return err
+	}
+
+	accounts, err := s.oauthRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if findOAuthAccount(accounts, provider) != nil {
+		return domain.ErrProviderLinked
+	}
+
+	return s.cache.Set(ctx, cache.OAuthLinkKey(state), userID.String(), oauthLinkTTL)
+}
+
+// TakeOAuthLink returns the user who started the OAuth flow with state to
+// link a provider login, if one did. The link can only be taken once.
+func (s *AuthService) TakeOAuthLink(ctx context.Context, state string) (uuid.UUID, bool) {
+	if s.cache == nil || state == "" {
+		return uuid.Nil, false
+	}
+
+	key := cache.OAuthLinkKey(state)
+	value, err := s.cache.Get(ctx, key)
+	if err != nil || value == "" {
+		return uuid.Nil, false
+	}
+	_ = s.cache.Delete(ctx, key)
+
+	userID, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// LinkOAuthAccount attaches the provider login to the user from their
+// account settings. Linking a login the user already has is a no-op.
+func (s *AuthService) LinkOAuthAccount(ctx context.Context, userID uuid.UUID, provider string, profile *oauth.Profile) error {
+	_, err := s.linkOAuthAccount(ctx, userID, provider, profile, domain.OAuthLinkAccountSettings)
+	return err
+}
+
+// linkOAuthAccount attaches the provider login to the user, recording how
+// the link was made and the email the provider reported
+func (s *AuthService) linkOAuthAccount(ctx context.Context, userID uuid.UUID, provider string, profile *oauth.Profile, via domain.OAuthLinkMethod) (*domain.OAuthAccount, error) {
+	existing, err := s.oauthRepo.GetByProviderUserID(ctx, provider, profile.ID)
+	if err == nil {
+		if existing.UserID == userID {
+			return existing, nil
+		}
+		return nil, domain.ErrOAuthAccountInUse
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	accounts, err := s.oauthRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if findOAuthAccount(accounts, provider) != nil {
+		return nil, domain.ErrProviderLinked
+	}
+
+	account := &domain.OAuthAccount{
+		UserID:                userID,
+		Provider:              provider,
+		ProviderUserID:        profile.ID,
+		LinkedVia:             &via,
+		ProviderEmailVerified: &profile.EmailVerified,
+	}
+	if profile.Email != "" {
+		account.ProviderEmail = &profile.Email
+	}
+	if err := s.oauthRepo.Create(ctx, account); err != nil {
+		return nil, err
+	}
+
+	if via != domain.OAuthLinkSignup {
+		s.auditSignInChange(ctx, userID, domain.AuditActionOAuthLink, map[string]interface{}{
+			"provider":                provider,
+			"linked_via":              via,
+			"provider_email":          profile.Email,
+			"provider_email_verified": profile.EmailVerified,
+		})
+	}
+	return account, nil
+}
+
+// UnlinkOAuthAccount removes the user's login from the provider, unless it
+// is the only way left to sign in
+func (s *AuthService) UnlinkOAuthAccount(ctx context.Context, userID uuid.UUID, provider string, req *domain.ReauthRequest, refreshToken string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.reauthenticate(ctx, user, req, refreshToken); err != nil {
+		return err
+	}
+
+	accounts, err := s.oauthRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	account := findOAuthAccount(accounts, provider)
+	if account == nil {
+		return domain.ErrNotFound
+	}
+	if user.PasswordHash == nil && len(accounts) == 1 {
+		return domain.ErrLastSignInMethod
+	}
+
+	if err := s.oauthRepo.Delete(ctx, account.ID); err != nil {
+		return err
+	}
+
+	s.auditSignInChange(ctx, userID, domain.AuditActionOAuthUnlink, map[string]interface{}{
+		"provider": provider,
+	})
+	return nil
+}
+
+// ConfirmPresence checks the user is present before an action that can't be
+// undone, the same way as before a change to how they sign in
+func (s *AuthService) ConfirmPresence(ctx context.Context, userID uuid.UUID, req *domain.ReauthRequest, refreshToken string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.reauthenticate(ctx, user, req, refreshToken)
+}
+
+// RevokeSessions signs the user out everywhere. Access tokens already issued
+// last until they expire.
+func (s *AuthService) RevokeSessions(ctx context.Context, userID uuid.UUID) error {
+	return s.refreshTokenRepo.DeleteByUserID(ctx, userID)
+}
+
+// reauthenticate confirms the user is present before a change to how they
+// sign in. Users with a password must give it; anyone else must be using a
+// session that started within reauthWindow.
+func (s *AuthService) reauthenticate(ctx context.Context, user *domain.User, req *domain.ReauthRequest, refreshToken string) error {
+	if user.PasswordHash != nil {
+		if req.CurrentPassword == "" || !password.Verify(req.CurrentPassword, *user.PasswordHash) {
+			return domain.ErrReauthRequired
+		}
+		return nil
+	}
+
+	if refreshToken == "" {
+		return domain.ErrReauthRequired
+	}
+	token, err := s.refreshTokenRepo.GetByTokenHash(ctx, hashToken(refreshToken))
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.ErrReauthRequired
+	}
+	if err != nil {
+		return err
+	}
+	if token.UserID != user.ID || time.Since(token.CreatedAt) > reauthWindow {
+		return domain.ErrReauthRequired
+	}
+
+	return nil
+}
+
+func (s *AuthService) auditSignInChange(ctx context.Context, userID uuid.UUID, action string, metadata map[string]interface{}) {
+	if s.auditRepo == nil {
+		return
+	}
+
+	if err := s.auditRepo.Create(ctx, &domain.AuditLog{
+		ActorID:    &userID,
+		Action:     action,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   &userID,
+		Metadata:   metadata,
+	}); err != nil {
+		log.Printf("Error recording %s for user %s: %v", action, userID, err)
+	}
+}
+
+func findOAuthAccount(accounts []domain.OAuthAccount, provider string) *domain.OAuthAccount {
+	for i := range accounts {
+		if accounts[i].Provider == provider {
+			return &accounts[i]
+		}
+	}
+	return nil
+}
+
+// SetSessionPolicy turns single-session mode on or off for the user. Turning
+// it on revokes every other session, keeping the one making the request.
+func (s *AuthService) SetSessionPolicy(ctx context.Context, userID uuid.UUID, req *domain.SetSessionPolicyRequest, currentRefreshToken string) (*domain.SessionPolicyResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.SingleSession = req.SingleSession
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	resp := &domain.SessionPolicyResponse{
+		SingleSession: user.SingleSession,
+		Enforced:      s.roleRequiresSingleSession(user.Role),
+	}
+	if s.requiresSingleSession(user) && currentRefreshToken != "" {
+		revoked, err := s.refreshTokenRepo.DeleteByUserIDExcept(ctx, user.ID, hashToken(currentRefreshToken))
+		if err != nil {
+			return nil, err
+		}
+		resp.RevokedSessions = revoked
+	}
+
+	return resp, nil
+}
+
+func (s *AuthService) requiresSingleSession(user *domain.User) bool {
+	return user.SingleSession || s.roleRequiresSingleSession(user.Role)
+}
+
+func (s *AuthService) roleRequiresSingleSession(role domain.UserRole) bool {
+	for _, r := range s.sessionPolicy.SingleSessionRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// checkLoginLocation records where the login came from and notifies the user
+// when it is a different country from a login within CountryHopWindow, a
+// common sign of a stolen password
+func (s *AuthService) checkLoginLocation(ctx context.Context, user *domain.User, client *domain.LoginClient) {
+	if client == nil {
+		return
+	}
+	country := normalizeCountry(client.Country)
+
+	prevCountry, prevAt, err := s.userRepo.RecordLogin(ctx, user.ID, client.IP, country)
+	if err != nil {
+		log.Printf("Error recording login for user %s: %v", user.ID, err)
+		return
+	}
+
+	if country == "" || prevCountry == nil || prevAt == nil || *prevCountry == country {
+		return
+	}
+	if s.sessionPolicy.CountryHopWindow <= 0 || time.Since(*prevAt) > s.sessionPolicy.CountryHopWindow {
+		return
+	}
+
+	log.Printf("Login for user %s from %s within %s of a login from %s",
+		user.ID, country, time.Since(*prevAt).Round(time.Minute), *prevCountry)
+	if s.notificationSvc != nil {
+		s.notificationSvc.NotifySuspiciousLogin(ctx, user.ID, *prevCountry, country)
+	}
+}
+
+// normalizeCountry returns an upper case ISO 3166 alpha-2 code, or "" for
+// missing values and the placeholders proxies use for unknown or Tor traffic
+func normalizeCountry(country string) string {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if len(country) != 2 || country == "XX" || country == "T1" {
+		return ""
+	}
+	for _, c := range country {
+		if c < 'A' || c > 'Z' {
+			return ""
+		}
+	}
+	return country
+}
+
 func (s *AuthService) ValidateAccessToken(tokenString string) (*jwt.Claims, error) {
 	return s.jwtManager.ValidateAccessToken(tokenString)
 }
@@ -328,7 +1321,22 @@ func generateToken() string {
 	return hex.EncodeToString(b)
 }
 
+// generateLoginCode returns a random six digit code
+func generateLoginCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
 func hashToken(token string) string {
 	hash := sha256.Sum256([]byte(token))
 	return hex.EncodeToString(hash[:])
 }
+
+// tokenDigest is how emailed tokens are stored: the selector used to look
+// the token up, followed by a hash of the whole token
+func tokenDigest(token string) string {
+	return token[:domain.TokenSelectorLength] + hashToken(token)
+}
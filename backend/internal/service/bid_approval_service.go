@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// BidApprovalService decides who can bid on auctions whose seller restricts
+// bidders. Sellers can require pre-bid approval: users ask to bid, answering
+// the seller's questions, and the seller approves or denies them. Sellers
+// can also set requirements on bidders' accounts, checked as they bid.
+type BidApprovalService struct {
+	approvalRepo    repository.BidApprovalRepository
+	auctionRepo     repository.AuctionRepository
+	userRepo        repository.UserRepository
+	ratingRepo      repository.RatingRepository
+	notificationSvc *NotificationService
+}
+
+func NewBidApprovalService(
+	approvalRepo repository.BidApprovalRepository,
+	auctionRepo repository.AuctionRepository,
+	userRepo repository.UserRepository,
+	ratingRepo repository.RatingRepository,
+	notificationSvc *NotificationService,
+) *BidApprovalService {
+	return &BidApprovalService{
+		approvalRepo:    approvalRepo,
+		auctionRepo:     auctionRepo,
+		userRepo:        userRepo,
+		ratingRepo:      ratingRepo,
+		notificationSvc: notificationSvc,
+	}
+}
+
+// Request asks the seller to approve the user as a bidder. A pending
+// request can be sent again with new answers; a decided one can't.
+func (s *BidApprovalService) Request(ctx context.Context, auctionID, userID uuid.UUID, req *domain.RequestBidApprovalRequest) (*domain.BidApproval, error) {
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !auction.RequiresBidApproval {
+		return nil, domain.ErrBidApprovalNotRequired
+	}
+
+	if auction.SellerID == userID {
+		return nil, domain.ErrSelfBidding
+	}
+
+	if auction.Status != domain.AuctionStatusDraft && auction.Status != domain.AuctionStatusActive {
+		return nil, domain.ErrAuctionNotActive
+	}
+
+	if time.Now().After(auction.EndTime) {
+		return nil, domain.ErrAuctionEnded
+	}
+
+	if len(req.Answers) != len(auction.BidApprovalQuestions) {
+		return nil, domain.ErrBidApprovalAnswers
+	}
+
+	answers := make([]domain.BidApprovalAnswer, len(req.Answers))
+	for i, question := range auction.BidApprovalQuestions {
+		answers[i] = domain.BidApprovalAnswer{Question: question, Answer: req.Answers[i]}
+	}
+
+	existing, err := s.approvalRepo.Get(ctx, auctionID, userID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	if existing != nil && existing.Status != domain.BidApprovalPending {
+		return nil, domain.ErrConflict
+	}
+
+	approval := &domain.BidApproval{
+		AuctionID: auctionID,
+		UserID:    userID,
+		Answers:   answers,
+	}
+	if err := s.approvalRepo.Request(ctx, approval); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.approvalRepo.Get(ctx, auctionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only tell the seller about new requests, not edited answers
+	if s.notificationSvc != nil && existing == nil {
+		go s.notificationSvc.NotifyBidApprovalRequested(context.Background(), auction.SellerID, auction, stored.Username)
+	}
+
+	return stored, nil
+}
+
+// Get returns the user's own request
+func (s *BidApprovalService) Get(ctx context.Context, auctionID, userID uuid.UUID) (*domain.BidApproval, error) {
+	return s.approvalRepo.Get(ctx, auctionID, userID)
+}
+
+// List returns the requests to bid on the seller's auction
+func (s *BidApprovalService) List(ctx context.Context, auctionID, sellerID uuid.UUID, status *domain.BidApprovalStatus) ([]domain.BidApproval, error) {
+	if err := s.checkSeller(ctx, auctionID, sellerID); err != nil {
+		return nil, err
+	}
+
+	return s.approvalRepo.ListByAuction(ctx, auctionID, status)
+}
+
+// Decide approves or denies a user's request. Sellers can change their
+// mind later; denying an approved bidder keeps the bids they placed.
+func (s *BidApprovalService) Decide(ctx context.Context, auctionID, sellerID, userID uuid.UUID, req *domain.DecideBidApprovalRequest) (*domain.BidApproval, error) {
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.SellerID != sellerID {
+		return nil, domain.ErrForbidden
+	}
+
+	approval, err := s.approvalRepo.Get(ctx, auctionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := domain.BidApprovalStatus(req.Status)
+	if approval.Status == status {
+		return approval, nil
+	}
+
+	approval.Status = status
+	approval.Note = req.Note
+	if err := s.approvalRepo.Decide(ctx, approval); err != nil {
+		return nil, err
+	}
+
+	if s.notificationSvc != nil {
+		decided := *approval
+		go s.notificationSvc.NotifyBidApprovalDecided(context.Background(), &decided, auction)
+	}
+
+	return approval, nil
+}
+
+// CheckCanBid returns the error for the first of the auction's requirements
+// the bidder doesn't meet, ErrBidApprovalRequired if it is approval
+func (s *BidApprovalService) CheckCanBid(ctx context.Context, auction *domain.Auction, bidderID uuid.UUID) error {
+	eligibility, err := s.Eligibility(ctx, auction, bidderID)
+	if err != nil {
+		return err
+	}
+	if eligibility != nil && !eligibility.CanBid {
+		return eligibility.Unmet[0].Err()
+	}
+
+	return nil
+}
+
+// Eligibility lists the requirements of the auction the user doesn't meet,
+// or returns nil when the auction doesn't restrict bidders
+func (s *BidApprovalService) Eligibility(ctx context.Context, auction *domain.Auction, userID uuid.UUID) (*domain.BidEligibility, error) {
+	if !auction.RequiresBidApproval && !auction.BidderRequirements.Any() {
+		return nil, nil
+	}
+
+	unmet := make([]domain.BidderRequirement, 0)
+	if auction.BidderRequirements.Any() {
+		profile, err := s.bidderProfile(ctx, userID, auction.BidderRequirements)
+		if err != nil {
+			return nil, err
+		}
+		unmet = auction.BidderRequirements.Unmet(profile, time.Now())
+	}
+
+	if auction.RequiresBidApproval {
+		approved, err := s.approvalRepo.IsApproved(ctx, auction.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !approved {
+			unmet = append(unmet, domain.BidderRequirementApproval)
+		}
+	}
+
+	return &domain.BidEligibility{CanBid: len(unmet) == 0, Unmet: unmet}, nil
+}
+
+// bidderProfile looks up what the requirements are checked against, only
+// loading ratings when there is a minimum
+func (s *BidApprovalService) bidderProfile(ctx context.Context, userID uuid.UUID, requirements domain.BidderRequirements) (domain.BidderProfile, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domain.BidderProfile{}, err
+	}
+
+	profile := domain.BidderProfile{
+		EmailVerified:    user.EmailVerified,
+		AccountCreatedAt: user.CreatedAt,
+	}
+	if requirements.MinRating != nil {
+		summary, err := s.ratingRepo.GetUserRatingSummary(ctx, userID)
+		if err != nil {
+			return domain.BidderProfile{}, err
+		}
+		profile.BuyerRating = summary.BuyerRating
+		profile.BuyerRatings = summary.BuyerCount
+	}
+
+	return profile, nil
+}
+
+func (s *BidApprovalService) checkSeller(ctx context.Context, auctionID, sellerID uuid.UUID) error {
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return err
+	}
+
+	if auction.SellerID != sellerID {
+		return domain.ErrForbidden
+	}
+
+	return nil
+}
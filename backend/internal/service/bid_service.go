@@ -2,75 +2,122 @@ package service
 
 import (
 	"context"
+	"net"
 	"time"
 
 	"github.com/auction-cards/backend/internal/cache"
 	"github.com/auction-cards/backend/internal/domain"
 	"github.com/auction-cards/backend/internal/repository"
-	"github.com/auction-cards/backend/internal/repository/postgres"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
 
 const (
-	AntiSnipingWindow   = 5 * time.Minute  // Extend if bid in last 5 minutes
-	AntiSnipingExtend   = 2 * time.Minute  // Extend by 2 minutes
+	AntiSnipingWindow = 5 * time.Minute // Extend if bid in last 5 minutes
+	AntiSnipingExtend = 2 * time.Minute // Extend by 2 minutes
+
+	BidCancelCutoff         = 1 * time.Hour // No seller cancellations in the final hour
+	MaxBidCancelsPerAuction = 3
+
+	AuctionCancelCutoff = 12 * time.Hour // Sellers cannot withdraw a listing this close to the end
 )
 
 type BidService struct {
 	bidRepo         repository.BidRepository
 	auctionRepo     repository.AuctionRepository
-	bidTransaction  *postgres.BidTransaction
+	userRepo        repository.UserRepository
+	blockRepo       repository.UserBlockRepository
+	auditRepo       repository.AuditLogRepository
+	bidEventRepo    repository.BidEventRepository
+	txManager       repository.TxManager
 	notificationSvc *NotificationService
+	webhookSvc      *WebhookService
+	orderSvc        *OrderService
+	inviteSvc       *AuctionInviteService
+	bidApprovalSvc  *BidApprovalService
 	cache           *cache.RedisCache
 }
 
 func NewBidService(
 	bidRepo repository.BidRepository,
 	auctionRepo repository.AuctionRepository,
-	bidTransaction *postgres.BidTransaction,
+	userRepo repository.UserRepository,
+	blockRepo repository.UserBlockRepository,
+	auditRepo repository.AuditLogRepository,
+	bidEventRepo repository.BidEventRepository,
+	txManager repository.TxManager,
 	notificationSvc *NotificationService,
+	webhookSvc *WebhookService,
+	orderSvc *OrderService,
+	inviteSvc *AuctionInviteService,
+	bidApprovalSvc *BidApprovalService,
 	cache *cache.RedisCache,
 ) *BidService {
 	return &BidService{
 		bidRepo:         bidRepo,
 		auctionRepo:     auctionRepo,
-		bidTransaction:  bidTransaction,
+		userRepo:        userRepo,
+		blockRepo:       blockRepo,
+		auditRepo:       auditRepo,
+		bidEventRepo:    bidEventRepo,
+		txManager:       txManager,
 		notificationSvc: notificationSvc,
+		webhookSvc:      webhookSvc,
+		orderSvc:        orderSvc,
+		inviteSvc:       inviteSvc,
+		bidApprovalSvc:  bidApprovalSvc,
 		cache:           cache,
 	}
 }
 
-func (s *BidService) PlaceBid(ctx context.Context, auctionID, bidderID uuid.UUID, req *domain.PlaceBidRequest) (*domain.BidResponse, error) {
-	amount, err := decimal.NewFromString(req.Amount)
+func (s *BidService) PlaceBid(ctx context.Context, auctionID, bidderID uuid.UUID, req *domain.PlaceBidRequest, client *domain.BidClient) (*domain.BidResponse, error) {
+	amount, err := domain.ParseAmount(req.Amount)
 	if err != nil {
-		return nil, domain.ErrBadRequest
+		return nil, err
 	}
 
 	var maxAutoBid *decimal.Decimal
 	if req.MaxAutoBid != nil {
-		max, err := decimal.NewFromString(*req.MaxAutoBid)
+		max, err := domain.ParseAmount(*req.MaxAutoBid)
 		if err != nil {
-			return nil, domain.ErrBadRequest
+			return nil, err
 		}
 		maxAutoBid = &max
 	}
 
-	// Use transaction for atomic bid placement
-	result, err := s.placeBidWithTransaction(ctx, auctionID, bidderID, amount, maxAutoBid)
+	// The bid and the auction's new price are saved together or not at all
+	var result *repository.PlaceBidResult
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		var err error
+		result, err = s.placeBid(txCtx, auctionID, bidderID, amount, maxAutoBid)
+		if err != nil {
+			return err
+		}
+		return s.recordPlacement(txCtx, result, client)
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	invalidateAuction(ctx, s.cache, auctionID)
+
 	// Publish bid to Redis for WebSocket broadcast
 	s.publishBidUpdate(ctx, result)
 
+	if s.webhookSvc != nil {
+		s.webhookSvc.PublishBidPlaced(ctx, result.Auction, result.Bid)
+	}
+
 	// Send notifications asynchronously
 	go s.sendBidNotifications(context.Background(), result, bidderID)
 
+	// Copy so the notification goroutine keeps the full auction
+	auction := *result.Auction
+	auction.SetReserveStatus(false)
+
 	response := &domain.BidResponse{
 		Bid:             result.Bid,
-		Auction:         result.Auction,
+		Auction:         &auction,
 		AuctionExtended: result.AuctionExtended,
 	}
 
@@ -82,13 +129,21 @@ func (s *BidService) PlaceBid(ctx context.Context, auctionID, bidderID uuid.UUID
 	return response, nil
 }
 
-func (s *BidService) placeBidWithTransaction(ctx context.Context, auctionID, bidderID uuid.UUID, amount decimal.Decimal, maxAutoBid *decimal.Decimal) (*postgres.PlaceBidResult, error) {
+// placeBid validates and saves a bid, raising the auction's price. It must
+// run in a transaction: when another bid has changed the auction first, the
+// version check fails after the bid is saved, and only rolling back removes
+// it.
+func (s *BidService) placeBid(ctx context.Context, auctionID, bidderID uuid.UUID, amount decimal.Decimal, maxAutoBid *decimal.Decimal) (*repository.PlaceBidResult, error) {
 	// Get auction first to validate
 	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.checkCanSee(ctx, auction, bidderID, ""); err != nil {
+		return nil, err
+	}
+
 	// Validate auction is active
 	if auction.Status != domain.AuctionStatusActive {
 		return nil, domain.ErrAuctionNotActive
@@ -104,9 +159,20 @@ func (s *BidService) placeBidWithTransaction(ctx context.Context, auctionID, bid
 		return nil, domain.ErrSelfBidding
 	}
 
+	if err := s.checkSellerAcceptingBids(ctx, auction.SellerID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNotBlocked(ctx, auction.SellerID, bidderID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkBidApproval(ctx, auction, bidderID); err != nil {
+		return nil, err
+	}
+
 	// Validate bid amount
-	minBid := auction.CurrentPrice.Add(auction.BidIncrement)
-	if amount.LessThan(minBid) {
+	if amount.LessThan(auction.MinimumBid()) {
 		return nil, domain.ErrBidTooLow
 	}
 
@@ -142,8 +208,12 @@ func (s *BidService) placeBidWithTransaction(ctx context.Context, auctionID, bid
 	}
 
 	// Update auction
+	hadBuyNow := auction.CanBuyNow()
+	wasReserveMet := auction.IsReserveMet()
+	previousPrice := auction.CurrentPrice
 	auction.CurrentPrice = amount
 	auction.BidCount++
+	auction.BuyNowAvailable = auction.CanBuyNow()
 	expectedVersion := auction.Version
 
 	// Save bid
@@ -156,16 +226,147 @@ func (s *BidService) placeBidWithTransaction(ctx context.Context, auctionID, bid
 		return nil, err
 	}
 
-	return &postgres.PlaceBidResult{
+	return &repository.PlaceBidResult{
 		Bid:             bid,
 		Auction:         auction,
 		AuctionExtended: auctionExtended,
 		NewEndTime:      newEndTime,
 		PreviousBidder:  prevBidderID,
+		PreviousPrice:   previousPrice,
+		BuyNowRemoved:   hadBuyNow && !auction.BuyNowAvailable,
+		ReserveMet:      !wasReserveMet && auction.IsReserveMet(),
 	}, nil
 }
 
-func (s *BidService) publishBidUpdate(ctx context.Context, result *postgres.PlaceBidResult) {
+// recordPlacement adds a placed bid to the auction's trail, with the
+// extension it caused if any
+func (s *BidService) recordPlacement(ctx context.Context, result *repository.PlaceBidResult, client *domain.BidClient) error {
+	bid := result.Bid
+	eventType := domain.BidEventPlaced
+	if client != nil && client.Snipe {
+		eventType = domain.BidEventAutoBid
+	}
+
+	if err := s.recordBidEvent(ctx, &domain.BidEvent{
+		AuctionID:  bid.AuctionID,
+		BidID:      &bid.ID,
+		ActorID:    &bid.BidderID,
+		Type:       eventType,
+		Amount:     &bid.Amount,
+		MaxAutoBid: bid.MaxAutoBid,
+	}, client); err != nil {
+		return err
+	}
+
+	if !result.AuctionExtended {
+		return nil
+	}
+	return s.recordBidEvent(ctx, &domain.BidEvent{
+		AuctionID: bid.AuctionID,
+		BidID:     &bid.ID,
+		Type:      domain.BidEventExtended,
+		EndTime:   &result.Auction.EndTime,
+	}, nil)
+}
+
+// recordBidEvent adds an event to the auction's bid trail with the client's
+// address and user agent. It runs in the transaction making the change, so
+// the trail can't miss what happened.
+func (s *BidService) recordBidEvent(ctx context.Context, event *domain.BidEvent, client *domain.BidClient) error {
+	if s.bidEventRepo == nil {
+		return nil
+	}
+
+	if client != nil {
+		if ip := net.ParseIP(client.IP); ip != nil {
+			address := ip.String()
+			event.IP = &address
+		}
+		if client.UserAgent != "" {
+			userAgent := client.UserAgent
+			if len(userAgent) > 500 {
+				userAgent = userAgent[:500]
+			}
+			event.UserAgent = &userAgent
+		}
+	}
+
+	return s.bidEventRepo.Create(ctx, event)
+}
+
+// GetBidAudit returns an auction's full bid trail for admins
+func (s *BidService) GetBidAudit(ctx context.Context, auctionID uuid.UUID) (*domain.BidAudit, error) {
+	if _, err := s.auctionRepo.GetByID(ctx, auctionID); err != nil {
+		return nil, err
+	}
+
+	events, err := s.bidEventRepo.ListByAuction(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.BidAudit{AuctionID: auctionID, Events: events}, nil
+}
+
+// checkCanSee hides a private auction from everyone but its seller, invited
+// users and, where a token is passed, holders of its share link. A share
+// link only grants read access, so bidding passes no token.
+func (s *BidService) checkCanSee(ctx context.Context, auction *domain.Auction, userID uuid.UUID, shareToken string) error {
+	if auction.Visibility != domain.AuctionPrivate || auction.SellerID == userID {
+		return nil
+	}
+	if s.inviteSvc != nil && s.inviteSvc.CanView(ctx, auction, userID, shareToken) {
+		return nil
+	}
+	return domain.ErrNotFound
+}
+
+// checkSellerAcceptingBids rejects bids while the seller is on vacation with
+// bidding blocked
+func (s *BidService) checkSellerAcceptingBids(ctx context.Context, sellerID uuid.UUID) error {
+	if s.userRepo == nil {
+		return nil
+	}
+
+	seller, err := s.userRepo.GetByID(ctx, sellerID)
+	if err != nil {
+		return err
+	}
+	if seller.Vacation.BlockBids && seller.Vacation.ActiveAt(time.Now()) {
+		return domain.ErrSellerOnVacation
+	}
+
+	return nil
+}
+
+// checkNotBlocked rejects bids from users the seller has blocked
+func (s *BidService) checkNotBlocked(ctx context.Context, sellerID, bidderID uuid.UUID) error {
+	if s.blockRepo == nil {
+		return nil
+	}
+
+	blocked, err := s.blockRepo.IsBlocked(ctx, sellerID, bidderID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return domain.ErrBlockedBySeller
+	}
+
+	return nil
+}
+
+// checkBidApproval rejects bids from users the seller hasn't approved on
+// auctions that require approval
+func (s *BidService) checkBidApproval(ctx context.Context, auction *domain.Auction, bidderID uuid.UUID) error {
+	if s.bidApprovalSvc == nil {
+		return nil
+	}
+
+	return s.bidApprovalSvc.CheckCanBid(ctx, auction, bidderID)
+}
+
+func (s *BidService) publishBidUpdate(ctx context.Context, result *repository.PlaceBidResult) {
 	if s.cache == nil {
 		return
 	}
@@ -178,13 +379,22 @@ func (s *BidService) publishBidUpdate(ctx context.Context, result *postgres.Plac
 			BidderID:   result.Bid.BidderID,
 			Amount:     result.Bid.Amount,
 			BidCount:   result.Auction.BidCount,
+			ReserveMet: result.Auction.IsReserveMet(),
+			MinNextBid: result.Auction.MinimumBid(),
 			Timestamp:  result.Bid.CreatedAt,
 		},
 	}
 
 	_ = s.cache.Publish(ctx, cache.AuctionChannel(result.Auction.ID), message)
 
+	// Bids on a live event's lot also go to the event's room
+	if result.Auction.LiveEventID != nil {
+		_ = s.cache.Publish(ctx, cache.LiveEventChannel(*result.Auction.LiveEventID), message)
+	}
+
 	if result.AuctionExtended && result.NewEndTime != nil {
+		scheduleAuctionEnd(ctx, s.cache, result.Auction)
+
 		extendMessage := domain.WSMessage{
 			Type: domain.WSMessageAuctionExtended,
 			Payload: domain.WSAuctionExtendedPayload{
@@ -194,9 +404,36 @@ func (s *BidService) publishBidUpdate(ctx context.Context, result *postgres.Plac
 		}
 		_ = s.cache.Publish(ctx, cache.AuctionChannel(result.Auction.ID), extendMessage)
 	}
+
+	if result.BuyNowRemoved {
+		s.publishBuyNowRemoved(ctx, result.Auction.ID)
+	}
+
+	if result.ReserveMet {
+		s.publishReserveMet(ctx, result.Auction)
+	}
+}
+
+func (s *BidService) publishReserveMet(ctx context.Context, auction *domain.Auction) {
+	message := domain.WSMessage{
+		Type: domain.WSMessageReserveMet,
+		Payload: domain.WSReserveMetPayload{
+			AuctionID:    auction.ID,
+			CurrentPrice: auction.CurrentPrice,
+		},
+	}
+	_ = s.cache.Publish(ctx, cache.AuctionChannel(auction.ID), message)
+}
+
+func (s *BidService) publishBuyNowRemoved(ctx context.Context, auctionID uuid.UUID) {
+	message := domain.WSMessage{
+		Type:    domain.WSMessageBuyNowRemoved,
+		Payload: domain.WSBuyNowRemovedPayload{AuctionID: auctionID},
+	}
+	_ = s.cache.Publish(ctx, cache.AuctionChannel(auctionID), message)
 }
 
-func (s *BidService) sendBidNotifications(ctx context.Context, result *postgres.PlaceBidResult, bidderID uuid.UUID) {
+func (s *BidService) sendBidNotifications(ctx context.Context, result *repository.PlaceBidResult, bidderID uuid.UUID) {
 	if s.notificationSvc == nil {
 		return
 	}
@@ -206,31 +443,72 @@ func (s *BidService) sendBidNotifications(ctx context.Context, result *postgres.
 		s.notificationSvc.NotifyOutbid(ctx, *result.PreviousBidder, result.Auction, result.Bid.Amount)
 	}
 
-	// Notify seller of new bid
-	s.notificationSvc.NotifyNewBid(ctx, result.Auction.SellerID, result.Auction, result.Bid.Amount, bidderID)
+	// Notify the seller, as their alerts for the listing ask
+	s.notificationSvc.NotifySellerOfBid(ctx, result.Auction, result.Bid.Amount, result.PreviousPrice, bidderID, result.ReserveMet)
 }
 
-func (s *BidService) GetBidsByAuction(ctx context.Context, auctionID uuid.UUID, page, limit int) (*domain.BidListResponse, error) {
-	if page <= 0 {
-		page = 1
+// GetBidsByAuction pages through an auction's bids by page number or, when
+// params.Cursor is set, by cursor. Either way NextCursor is set while more
+// bids remain. The bids of a private auction are only listed for those who
+// may see it.
+func (s *BidService) GetBidsByAuction(ctx context.Context, auctionID, viewerID uuid.UUID, shareToken string, params *domain.BidListParams) (*domain.BidListResponse, error) {
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, err
 	}
-	if limit <= 0 {
-		limit = 20
+	if err := s.checkCanSee(ctx, auction, viewerID, shareToken); err != nil {
+		return nil, err
 	}
 
-	bids, totalCount, err := s.bidRepo.GetByAuctionID(ctx, auctionID, page, limit)
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+
+	if params.Cursor != nil {
+		// Fetch one extra bid to tell whether there is another page
+		query := *params
+		query.Limit++
+		bids, _, err := s.bidRepo.GetByAuctionID(ctx, auctionID, &query)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := &domain.BidListResponse{Bids: bids}
+		if len(bids) > params.Limit {
+			resp.Bids = bids[:params.Limit]
+			resp.NextCursor = bidCursor(resp.Bids)
+		}
+		return resp, nil
+	}
+
+	bids, totalCount, err := s.bidRepo.GetByAuctionID(ctx, auctionID, params)
 	if err != nil {
 		return nil, err
 	}
 
-	totalPages := (totalCount + limit - 1) / limit
+	totalPages := (totalCount + params.Limit - 1) / params.Limit
 
-	return &domain.BidListResponse{
+	resp := &domain.BidListResponse{
 		Bids:       bids,
 		TotalCount: totalCount,
-		Page:       page,
+		Page:       params.Page,
 		TotalPages: totalPages,
-	}, nil
+	}
+	if params.Page < totalPages {
+		resp.NextCursor = bidCursor(bids)
+	}
+	return resp, nil
+}
+
+func bidCursor(bids []domain.Bid) string {
+	if len(bids) == 0 {
+		return ""
+	}
+	last := bids[len(bids)-1]
+	return (&domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}).Encode()
 }
 
 func (s *BidService) GetBidsByUser(ctx context.Context, userID uuid.UUID, page, limit int) (*domain.BidListResponse, error) {
@@ -256,12 +534,16 @@ func (s *BidService) GetBidsByUser(ctx context.Context, userID uuid.UUID, page,
 	}, nil
 }
 
-func (s *BidService) BuyNow(ctx context.Context, auctionID, buyerID uuid.UUID) (*domain.BidResponse, error) {
+func (s *BidService) BuyNow(ctx context.Context, auctionID, buyerID uuid.UUID, client *domain.BidClient) (*domain.BidResponse, error) {
 	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.checkCanSee(ctx, auction, buyerID, ""); err != nil {
+		return nil, err
+	}
+
 	// Validate auction is active
 	if auction.Status != domain.AuctionStatusActive {
 		return nil, domain.ErrAuctionNotActive
@@ -277,9 +559,21 @@ func (s *BidService) BuyNow(ctx context.Context, auctionID, buyerID uuid.UUID) (
 		return nil, domain.ErrSelfBidding
 	}
 
-	// Check if Buy Now is available
-	if auction.BuyNowPrice == nil {
-		return nil, domain.ErrBadRequest
+	if err := s.checkSellerAcceptingBids(ctx, auction.SellerID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNotBlocked(ctx, auction.SellerID, buyerID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkBidApproval(ctx, auction, buyerID); err != nil {
+		return nil, err
+	}
+
+	// Check if Buy Now is still on offer
+	if !auction.CanBuyNow() {
+		return nil, domain.ErrBuyNowUnavailable
 	}
 
 	// Create bid at buy now price
@@ -290,22 +584,49 @@ func (s *BidService) BuyNow(ctx context.Context, auctionID, buyerID uuid.UUID) (
 		Amount:    *auction.BuyNowPrice,
 		CreatedAt: time.Now(),
 	}
+	expectedVersion := auction.Version
 
-	if err := s.bidRepo.Create(ctx, bid); err != nil {
-		return nil, err
-	}
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.bidRepo.Create(txCtx, bid); err != nil {
+			return err
+		}
+		if err := s.recordBidEvent(txCtx, &domain.BidEvent{
+			AuctionID: auctionID,
+			BidID:     &bid.ID,
+			ActorID:   &buyerID,
+			Type:      domain.BidEventBuyNow,
+			Amount:    &bid.Amount,
+		}, client); err != nil {
+			return err
+		}
 
-	// End auction immediately
-	auction.Status = domain.AuctionStatusCompleted
-	auction.CurrentPrice = *auction.BuyNowPrice
-	auction.WinnerID = &buyerID
-	auction.WinningBidID = &bid.ID
-	auction.EndTime = time.Now()
-	auction.BidCount++
+		// End auction immediately. The version check fails if a bid, another
+		// buy-now or the scheduler closing the auction got there first.
+		auction.CurrentPrice = *auction.BuyNowPrice
+		auction.BidCount++
+		auction.EndTime = time.Now()
+		if err := s.auctionRepo.UpdateWithVersion(txCtx, auction, expectedVersion); err != nil {
+			return err
+		}
+
+		auction.Status = domain.AuctionStatusCompleted
+		auction.WinnerID = &buyerID
+		auction.WinningBidID = &bid.ID
+		if err := s.auctionRepo.UpdateStatus(txCtx, auction.ID, auction.Status, auction.WinnerID, auction.WinningBidID); err != nil {
+			return err
+		}
 
-	if err := s.auctionRepo.Update(ctx, auction); err != nil {
+		if s.orderSvc != nil {
+			if _, err := s.orderSvc.CreateForWinner(txCtx, auction, buyerID, &bid.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
+	invalidateAuction(ctx, s.cache, auction.ID)
 
 	// Publish auction ended
 	if s.cache != nil {
@@ -321,6 +642,10 @@ func (s *BidService) BuyNow(ctx context.Context, auctionID, buyerID uuid.UUID) (
 		_ = s.cache.Publish(ctx, cache.AuctionChannel(auction.ID), message)
 	}
 
+	if s.webhookSvc != nil {
+		s.webhookSvc.PublishAuctionEnded(ctx, auction)
+	}
+
 	// Send notifications
 	if s.notificationSvc != nil {
 		go func() {
@@ -329,8 +654,319 @@ func (s *BidService) BuyNow(ctx context.Context, auctionID, buyerID uuid.UUID) (
 		}()
 	}
 
+	response := *auction
+	response.SetReserveStatus(false)
+
 	return &domain.BidResponse{
 		Bid:     bid,
-		Auction: auction,
+		Auction: &response,
 	}, nil
 }
+
+// CancelBid lets the seller remove an erroneous bid from their own auction.
+// The auction's price and bid count are recomputed from the remaining bids.
+func (s *BidService) CancelBid(ctx context.Context, auctionID, bidID, sellerID uuid.UUID, req *domain.CancelBidRequest) (*domain.Auction, error) {
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.SellerID != sellerID {
+		return nil, domain.ErrForbidden
+	}
+
+	if auction.Status != domain.AuctionStatusActive {
+		return nil, domain.ErrAuctionNotActive
+	}
+
+	if time.Until(auction.EndTime) < BidCancelCutoff {
+		return nil, domain.ErrBidCancelWindow
+	}
+
+	bid, err := s.bidRepo.GetByID(ctx, bidID)
+	if err != nil {
+		return nil, err
+	}
+
+	if bid.AuctionID != auctionID || bid.CancelledAt != nil {
+		return nil, domain.ErrNotFound
+	}
+
+	cancelled, err := s.bidRepo.GetCancelledCount(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if cancelled >= MaxBidCancelsPerAuction {
+		return nil, domain.ErrBidCancelLimit
+	}
+
+	previousPrice := auction.CurrentPrice
+	expectedVersion := auction.Version
+
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.bidRepo.Cancel(txCtx, bid.ID, sellerID, req.Reason); err != nil {
+			return err
+		}
+		if err := s.recordBidEvent(txCtx, &domain.BidEvent{
+			AuctionID: auctionID,
+			BidID:     &bid.ID,
+			ActorID:   &sellerID,
+			Type:      domain.BidEventCancelled,
+			Amount:    &bid.Amount,
+			Reason:    &req.Reason,
+		}, nil); err != nil {
+			return err
+		}
+
+		highest, err := s.bidRepo.GetHighestBid(txCtx, auctionID)
+		if err != nil {
+			return err
+		}
+
+		bidCount, err := s.bidRepo.GetBidCount(txCtx, auctionID)
+		if err != nil {
+			return err
+		}
+
+		auction.CurrentPrice = auction.StartingPrice
+		if highest != nil {
+			auction.CurrentPrice = highest.Amount
+		}
+		auction.BidCount = bidCount
+
+		if err := s.auctionRepo.UpdateWithVersion(txCtx, auction, expectedVersion); err != nil {
+			return err
+		}
+
+		return s.auditRepo.Create(txCtx, &domain.AuditLog{
+			ActorID:    &sellerID,
+			Action:     domain.AuditActionBidCancel,
+			EntityType: domain.AuditEntityAuction,
+			EntityID:   &auction.ID,
+			Reason:     &req.Reason,
+			Metadata: map[string]interface{}{
+				"bid_id":         bid.ID,
+				"bidder_id":      bid.BidderID,
+				"amount":         bid.Amount.StringFixed(2),
+				"previous_price": previousPrice.StringFixed(2),
+				"new_price":      auction.CurrentPrice.StringFixed(2),
+				"bid_count":      auction.BidCount,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateAuction(ctx, s.cache, auction.ID)
+
+	if s.cache != nil {
+		message := domain.WSMessage{
+			Type: domain.WSMessageBidCancelled,
+			Payload: domain.WSBidCancelledPayload{
+				BidID:           bid.ID,
+				AuctionID:       auction.ID,
+				CurrentPrice:    auction.CurrentPrice,
+				BidCount:        auction.BidCount,
+				BuyNowAvailable: auction.CanBuyNow(),
+				ReserveMet:      auction.IsReserveMet(),
+			},
+		}
+		_ = s.cache.Publish(ctx, cache.AuctionChannel(auction.ID), message)
+	}
+
+	if s.notificationSvc != nil {
+		updated := *auction
+		go s.notificationSvc.NotifyBidCancelled(context.Background(), bid.BidderID, &updated, bid.Amount, req.Reason)
+	}
+
+	auction.SetReserveStatus(true)
+
+	return auction, nil
+}
+
+// CancelAuction lets the seller withdraw an active auction. Every bid is
+// voided, and bidders and watchers are told why. The reason is kept in the
+// audit log for admins.
+func (s *BidService) CancelAuction(ctx context.Context, auctionID, sellerID uuid.UUID, req *domain.CancelAuctionRequest) (*domain.Auction, error) {
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.SellerID != sellerID {
+		return nil, domain.ErrForbidden
+	}
+
+	if auction.Status != domain.AuctionStatusActive {
+		return nil, domain.ErrAuctionNotActive
+	}
+
+	if time.Until(auction.EndTime) < AuctionCancelCutoff {
+		return nil, domain.ErrAuctionCancelWindow
+	}
+
+	previousPrice := auction.CurrentPrice
+	previousBidCount := auction.BidCount
+	expectedVersion := auction.Version
+
+	var bidders []uuid.UUID
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		bidders, err = s.bidRepo.CancelAllForAuction(txCtx, auctionID, sellerID, req.Reason)
+		if err != nil {
+			return err
+		}
+		if previousBidCount > 0 {
+			if err := s.recordBidEvent(txCtx, &domain.BidEvent{
+				AuctionID: auctionID,
+				ActorID:   &sellerID,
+				Type:      domain.BidEventAllCancelled,
+				Amount:    &previousPrice,
+				Reason:    &req.Reason,
+			}, nil); err != nil {
+				return err
+			}
+		}
+
+		auction.EndTime = time.Now()
+		auction.CurrentPrice = auction.StartingPrice
+		auction.BidCount = 0
+
+		if err := s.auctionRepo.UpdateWithVersion(txCtx, auction, expectedVersion); err != nil {
+			return err
+		}
+
+		closed, err := s.auctionRepo.CloseIfActive(txCtx, auctionID, auction.Version, domain.AuctionStatusCancelled, nil, nil)
+		if err != nil {
+			return err
+		}
+		if !closed {
+			return domain.ErrAuctionNotActive
+		}
+		auction.Status = domain.AuctionStatusCancelled
+
+		return s.auditRepo.Create(txCtx, &domain.AuditLog{
+			ActorID:    &sellerID,
+			Action:     domain.AuditActionAuctionCancel,
+			EntityType: domain.AuditEntityAuction,
+			EntityID:   &auction.ID,
+			Reason:     &req.Reason,
+			Metadata: map[string]interface{}{
+				"previous_price": previousPrice.StringFixed(2),
+				"bid_count":      previousBidCount,
+				"bidders_voided": len(bidders),
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateAuction(ctx, s.cache, auction.ID)
+
+	if s.cache != nil {
+		message := domain.WSMessage{
+			Type: domain.WSMessageAuctionCancelled,
+			Payload: domain.WSAuctionCancelledPayload{
+				AuctionID: auction.ID,
+				Reason:    req.Reason,
+			},
+		}
+		_ = s.cache.Publish(ctx, cache.AuctionChannel(auction.ID), message)
+	}
+
+	if s.notificationSvc != nil {
+		cancelled := *auction
+		go s.notificationSvc.NotifySellerCancelled(context.Background(), &cancelled, bidders, req.Reason)
+	}
+
+	auction.SetReserveStatus(true)
+
+	return auction, nil
+}
+
+// LowerReserve reduces the reserve price on an active auction. If the current
+// high bid now meets the reserve, watchers and the high bidder are told.
+func (s *BidService) LowerReserve(ctx context.Context, auctionID, sellerID uuid.UUID, req *domain.LowerReserveRequest) (*domain.Auction, error) {
+	reserve, err := domain.ParseAmount(req.ReservePrice)
+	if err != nil {
+		return nil, err
+	}
+
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.SellerID != sellerID {
+		return nil, domain.ErrForbidden
+	}
+
+	if auction.Status != domain.AuctionStatusActive {
+		return nil, domain.ErrAuctionNotActive
+	}
+
+	if time.Now().After(auction.EndTime) {
+		return nil, domain.ErrAuctionEnded
+	}
+
+	if auction.ReservePrice == nil || !reserve.LessThan(*auction.ReservePrice) {
+		return nil, domain.ErrReserveNotLowered
+	}
+
+	if reserve.LessThan(auction.StartingPrice) {
+		return nil, domain.ErrReserveTooLow
+	}
+
+	previousReserve := *auction.ReservePrice
+	hadBuyNow := auction.CanBuyNow()
+	wasMet := auction.IsReserveMet()
+	expectedVersion := auction.Version
+	auction.ReservePrice = &reserve
+
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.auctionRepo.UpdateReserveWithVersion(txCtx, auction, expectedVersion); err != nil {
+			return err
+		}
+
+		return s.auditRepo.Create(txCtx, &domain.AuditLog{
+			ActorID:    &sellerID,
+			Action:     domain.AuditActionReserveLower,
+			EntityType: domain.AuditEntityAuction,
+			EntityID:   &auction.ID,
+			Metadata: map[string]interface{}{
+				"previous_reserve": previousReserve.StringFixed(2),
+				"new_reserve":      reserve.StringFixed(2),
+				"current_price":    auction.CurrentPrice.StringFixed(2),
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateAuction(ctx, s.cache, auction.ID)
+
+	if wasMet || !auction.IsReserveMet() {
+		auction.SetReserveStatus(true)
+		return auction, nil
+	}
+
+	if s.cache != nil {
+		if hadBuyNow && !auction.CanBuyNow() {
+			s.publishBuyNowRemoved(ctx, auction.ID)
+		}
+
+		s.publishReserveMet(ctx, auction)
+	}
+
+	if s.notificationSvc != nil {
+		highest, err := s.bidRepo.GetHighestBid(ctx, auctionID)
+		if err == nil && highest != nil {
+			updated := *auction
+			go s.notificationSvc.NotifyReserveMet(context.Background(), highest.BidderID, &updated)
+		}
+	}
+
+	auction.SetReserveStatus(true)
+
+	return auction, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// BlockService manages the users each user has blocked. The bid and message
+// services enforce the blocks.
+type BlockService struct {
+	blockRepo repository.UserBlockRepository
+	userRepo  repository.UserRepository
+}
+
+func NewBlockService(blockRepo repository.UserBlockRepository, userRepo repository.UserRepository) *BlockService {
+	return &BlockService{
+		blockRepo: blockRepo,
+		userRepo:  userRepo,
+	}
+}
+
+func (s *BlockService) Block(ctx context.Context, blockerID uuid.UUID, req *domain.BlockUserRequest) (*domain.UserBlock, error) {
+	if req.UserID == blockerID {
+		return nil, domain.ErrCannotBlockSelf
+	}
+
+	blocked, err := s.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	block := &domain.UserBlock{
+		BlockerID: blockerID,
+		BlockedID: blocked.ID,
+	}
+	if req.Reason != "" {
+		block.Reason = &req.Reason
+	}
+
+	if err := s.blockRepo.Block(ctx, block); err != nil {
+		return nil, err
+	}
+
+	block.Blocked = blocked.ToPublic()
+	block.Blocked.HideIfDeleted()
+	return block, nil
+}
+
+func (s *BlockService) Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	return s.blockRepo.Unblock(ctx, blockerID, blockedID)
+}
+
+func (s *BlockService) ListBlocks(ctx context.Context, blockerID uuid.UUID, params *domain.UserBlockListParams) ([]domain.UserBlock, int, error) {
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 || params.Limit > 100 {
+		params.Limit = 20
+	}
+	return s.blockRepo.List(ctx, blockerID, params.Page, params.Limit)
+}
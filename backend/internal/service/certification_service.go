@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/auction-cards/backend/internal/cache"
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/pkg/grading"
+	"github.com/auction-cards/backend/internal/repository"
+)
+
+// certNotFoundTTL is kept short because a freshly graded card can take a
+// while to show up in the grader's records
+const certNotFoundTTL = time.Hour
+
+// certLookup is a grader's answer as cached in Redis
+type certLookup struct {
+	Found bool          `json:"found"`
+	Cert  *grading.Cert `json:"cert,omitempty"`
+}
+
+// CertificationService checks the grading certifications listed on auctions
+// against the graders' records
+type CertificationService struct {
+	auctionRepo repository.AuctionRepository
+	graders     *grading.Registry
+	cache       *cache.RedisCache
+	cacheTTL    time.Duration
+}
+
+func NewCertificationService(
+	auctionRepo repository.AuctionRepository,
+	graders *grading.Registry,
+	cache *cache.RedisCache,
+	cacheTTL time.Duration,
+) *CertificationService {
+	return &CertificationService{
+		auctionRepo: auctionRepo,
+		graders:     graders,
+		cache:       cache,
+		cacheTTL:    cacheTTL,
+	}
+}
+
+// Verify looks up the auction's certification and stores the outcome on the
+// auction. When the grader can't be reached the certification is marked
+// unavailable so it can be retried.
+func (s *CertificationService) Verify(ctx context.Context, auction *domain.Auction) (*domain.Certification, error) {
+	if auction.Certification == nil {
+		return nil, domain.ErrNoCertification
+	}
+
+	cert := *auction.Certification
+	cert.Grade, cert.Subject, cert.Year = nil, nil, nil
+
+	found, err := s.lookup(ctx, cert.Grader, cert.Number)
+	switch {
+	case err == nil:
+		cert.Status = domain.CertificationVerified
+		cert.Grade = optionalString(found.Grade)
+		cert.Subject = optionalString(found.Subject)
+		cert.Year = optionalString(found.Year)
+	case errors.Is(err, grading.ErrCertNotFound):
+		cert.Status = domain.CertificationNotFound
+	default:
+		log.Printf("Error looking up %s certification %s: %v", cert.Grader, cert.Number, err)
+		cert.Status = domain.CertificationUnavailable
+	}
+	now := time.Now()
+	cert.CheckedAt = &now
+
+	if err := s.auctionRepo.UpdateCertification(ctx, auction.ID, &cert); err != nil {
+		return nil, err
+	}
+	invalidateAuction(ctx, s.cache, auction.ID)
+	auction.Certification = &cert
+
+	return &cert, nil
+}
+
+// VerifyInBackground verifies the auction's certification without holding up
+// the request that listed it
+func (s *CertificationService) VerifyInBackground(auction *domain.Auction) {
+	if s == nil || auction.Certification == nil {
+		return
+	}
+
+	listed := *auction
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if _, err := s.Verify(ctx, &listed); err != nil {
+			log.Printf("Error verifying certification for auction %s: %v", listed.ID, err)
+		}
+	}()
+}
+
+// lookup asks the grader for the certification, answering from the cache
+// when the same number was checked recently. Failed lookups aren't cached.
+func (s *CertificationService) lookup(ctx context.Context, grader domain.Grader, number string) (*grading.Cert, error) {
+	key := cache.CertificationKey(string(grader), number)
+	if s.cache != nil {
+		var cached *certLookup
+		if err := s.cache.GetJSON(ctx, key, &cached); err == nil && cached != nil {
+			if !cached.Found {
+				return nil, grading.ErrCertNotFound
+			}
+			return cached.Cert, nil
+		}
+	}
+
+	g, err := s.graders.Get(string(grader))
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := g.Lookup(ctx, number)
+	if err != nil && !errors.Is(err, grading.ErrCertNotFound) {
+		return nil, err
+	}
+
+	if s.cache != nil {
+		entry, ttl := &certLookup{Found: true, Cert: cert}, s.cacheTTL
+		if err != nil {
+			entry, ttl = &certLookup{}, certNotFoundTTL
+		}
+		if cacheErr := s.cache.SetJSON(ctx, key, entry, ttl); cacheErr != nil {
+			log.Printf("Error caching %s certification %s: %v", grader, number, cacheErr)
+		}
+	}
+
+	return cert, err
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
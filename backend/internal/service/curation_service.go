@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/auction-cards/backend/internal/cache"
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// trendingWindow is how far back views, watches and bids count toward
+	// trending scores
+	trendingWindow = 24 * time.Hour
+
+	// trendingRefreshInterval is how often the scheduler recomputes the
+	// trending auctions
+	trendingRefreshInterval = 5 * time.Minute
+
+	// trendingCacheTTL outlasts a few refreshes, so a missed one doesn't
+	// leave the homepage computing scores on every request
+	trendingCacheTTL = 3 * trendingRefreshInterval
+)
+
+// CurationService picks the auctions shown on the homepage: ones admins
+// feature, and ones trending by recent activity. Trending auctions are
+// ranked periodically and cached in Redis.
+type CurationService struct {
+	curationRepo repository.CurationRepository
+	auctionRepo  repository.AuctionRepository
+	auditRepo    repository.AuditLogRepository
+	txManager    repository.TxManager
+	auctionSvc   *AuctionService
+	cache        *cache.RedisCache
+}
+
+func NewCurationService(
+	curationRepo repository.CurationRepository,
+	auctionRepo repository.AuctionRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager repository.TxManager,
+	auctionSvc *AuctionService,
+	cache *cache.RedisCache,
+) *CurationService {
+	return &CurationService{
+		curationRepo: curationRepo,
+		auctionRepo:  auctionRepo,
+		auditRepo:    auditRepo,
+		txManager:    txManager,
+		auctionSvc:   auctionSvc,
+		cache:        cache,
+	}
+}
+
+// Featured returns the featured auctions the viewer can see that are still
+// open for bids, in position order. Expired pins are left out.
+func (s *CurationService) Featured(ctx context.Context, viewerID uuid.UUID) ([]domain.Auction, error) {
+	featured, err := s.curationRepo.ListFeatured(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	ids := make([]uuid.UUID, 0, len(featured))
+	for i := range featured {
+		if !featured[i].IsExpired(now) {
+			ids = append(ids, featured[i].AuctionID)
+		}
+	}
+
+	return s.load(ctx, ids, viewerID, len(ids))
+}
+
+// Trending returns up to limit auctions with the most views, watches and
+// bids lately
+func (s *CurationService) Trending(ctx context.Context, viewerID uuid.UUID, limit int) ([]domain.Auction, error) {
+	if limit <= 0 || limit > domain.MaxTrendingLimit {
+		limit = domain.DefaultTrendingLimit
+	}
+
+	var ids []uuid.UUID
+	if s.cache != nil {
+		if err := s.cache.GetJSON(ctx, cache.TrendingAuctionsKey, &ids); err != nil {
+			ids = nil
+		}
+	}
+	if ids == nil {
+		var err error
+		if ids, err = s.RefreshTrending(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.load(ctx, ids, viewerID, limit)
+}
+
+// RefreshTrending ranks the trending auctions again and caches them
+func (s *CurationService) RefreshTrending(ctx context.Context) ([]uuid.UUID, error) {
+	ids, err := s.curationRepo.GetTrending(ctx, time.Now().Add(-trendingWindow), domain.MaxTrendingLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cache != nil {
+		if err := s.cache.SetJSON(ctx, cache.TrendingAuctionsKey, ids, trendingCacheTTL); err != nil {
+			log.Printf("Error caching trending auctions: %v", err)
+		}
+	}
+
+	return ids, nil
+}
+
+// ListFeatured returns every pin for admins, expired ones included
+func (s *CurationService) ListFeatured(ctx context.Context) ([]domain.FeaturedAuction, error) {
+	featured, err := s.curationRepo.ListFeatured(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range featured {
+		featured[i].Expired = featured[i].IsExpired(now)
+	}
+
+	return featured, nil
+}
+
+// Feature pins an auction to the homepage, or moves an existing pin. Only
+// active auctions can be featured, and no more than MaxFeaturedAuctions at
+// a time.
+func (s *CurationService) Feature(ctx context.Context, adminID, auctionID uuid.UUID, req *domain.FeatureAuctionRequest) (*domain.FeaturedAuction, error) {
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, domain.ErrValidation
+	}
+
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if auction.Status != domain.AuctionStatusActive {
+		return nil, domain.ErrAuctionNotActive
+	}
+
+	current, err := s.curationRepo.ListFeatured(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pinned := false
+	active := 0
+	now := time.Now()
+	for i := range current {
+		if current[i].AuctionID == auctionID {
+			pinned = true
+		} else if !current[i].IsExpired(now) {
+			active++
+		}
+	}
+	if !pinned && active >= domain.MaxFeaturedAuctions {
+		return nil, domain.ErrFeaturedLimit
+	}
+
+	featured := &domain.FeaturedAuction{
+		AuctionID:     auctionID,
+		Position:      req.Position,
+		ExpiresAt:     req.ExpiresAt,
+		FeaturedBy:    &adminID,
+		AuctionTitle:  auction.Title,
+		AuctionStatus: auction.Status,
+	}
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.curationRepo.Feature(txCtx, featured); err != nil {
+			return err
+		}
+		return s.auditRepo.Create(txCtx, &domain.AuditLog{
+			ActorID:    &adminID,
+			Action:     domain.AuditActionFeature,
+			EntityType: domain.AuditEntityAuction,
+			EntityID:   &auctionID,
+			Metadata: map[string]interface{}{
+				"position":   featured.Position,
+				"expires_at": featured.ExpiresAt,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return featured, nil
+}
+
+// Unfeature removes an auction's pin
+func (s *CurationService) Unfeature(ctx context.Context, adminID, auctionID uuid.UUID) error {
+	return s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.curationRepo.Unfeature(txCtx, auctionID); err != nil {
+			return err
+		}
+		return s.auditRepo.Create(txCtx, &domain.AuditLog{
+			ActorID:    &adminID,
+			Action:     domain.AuditActionUnfeature,
+			EntityType: domain.AuditEntityAuction,
+			EntityID:   &auctionID,
+		})
+	})
+}
+
+// load fetches auctions in order, keeping the first limit the viewer can
+// see that are still open for bids
+func (s *CurationService) load(ctx context.Context, ids []uuid.UUID, viewerID uuid.UUID, limit int) ([]domain.Auction, error) {
+	auctions := make([]domain.Auction, 0, limit)
+	if len(ids) == 0 || limit == 0 {
+		return auctions, nil
+	}
+
+	batch, err := s.auctionSvc.GetByIDs(ctx, ids, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, auction := range batch.Auctions {
+		if auction.Status != domain.AuctionStatusActive || !auction.EndTime.After(now) {
+			continue
+		}
+		auctions = append(auctions, auction)
+		if len(auctions) == limit {
+			break
+		}
+	}
+
+	return auctions, nil
+}
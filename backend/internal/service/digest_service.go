@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/pkg/email"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// digestInterval is how often the scheduler looks for digests due,
+	// sending up to digestBatch at a time
+	digestInterval = time.Minute
+	digestBatch    = 200
+	// digestLease is how long a claimed digest is kept from other runs. One
+	// that fails to send is tried again once it runs out.
+	digestLease = 15 * time.Minute
+
+	// digestWatchedLimit and digestSearchMatches cap how many auctions a
+	// digest lists for watched auctions and for each saved search
+	digestWatchedLimit  = 20
+	digestSearchMatches = 5
+)
+
+// DigestService sends users one daily or weekly email summing up their
+// watched auctions about to end, new listings matching their saved searches
+// and how their own listings are doing, in place of an email for each
+type DigestService struct {
+	digestRepo      repository.DigestRepository
+	savedSearchRepo repository.SavedSearchRepository
+	auctionRepo     repository.AuctionRepository
+	userRepo        repository.UserRepository
+	emailSender     email.Sender
+	links           notificationLinks
+}
+
+func NewDigestService(
+	digestRepo repository.DigestRepository,
+	savedSearchRepo repository.SavedSearchRepository,
+	auctionRepo repository.AuctionRepository,
+	userRepo repository.UserRepository,
+	emailSender email.Sender,
+	baseURL string,
+) *DigestService {
+	return &DigestService{
+		digestRepo:      digestRepo,
+		savedSearchRepo: savedSearchRepo,
+		auctionRepo:     auctionRepo,
+		userRepo:        userRepo,
+		emailSender:     emailSender,
+		links:           notificationLinks{baseURL: baseURL},
+	}
+}
+
+// GetPreferences returns the user's digest preferences, or the defaults,
+// switched off, when they haven't set any
+func (s *DigestService) GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.DigestPreferences, error) {
+	preferences, err := s.digestRepo.GetPreferences(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.DefaultDigestPreferences(userID), nil
+	}
+	return preferences, err
+}
+
+// UpdatePreferences replaces the user's digest preferences. The next digest
+// is due at the next hour they chose.
+func (s *DigestService) UpdatePreferences(ctx context.Context, userID uuid.UUID, req *domain.UpdateDigestPreferencesRequest) (*domain.DigestPreferences, error) {
+	preferences := &domain.DigestPreferences{
+		UserID:        userID,
+		Frequency:     domain.DigestFrequency(req.Frequency),
+		Hour:          req.Hour,
+		Weekday:       req.Weekday,
+		Timezone:      req.Timezone,
+		WatchedEnding: req.WatchedEnding,
+		SavedSearches: req.SavedSearches,
+		SellerStats:   req.SellerStats,
+	}
+	if err := preferences.Validate(); err != nil {
+		return nil, err
+	}
+
+	if preferences.Enabled() {
+		next, err := preferences.NextAfter(time.Now())
+		if err != nil {
+			return nil, err
+		}
+		preferences.NextSendAt = &next
+	}
+
+	if err := s.digestRepo.SavePreferences(ctx, preferences); err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
+
+// Preview compiles the user's digest as it would go out now
+func (s *DigestService) Preview(ctx context.Context, userID uuid.UUID) (*domain.Digest, error) {
+	preferences, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.compile(ctx, preferences, time.Now())
+}
+
+// compile gathers what happened since the last digest, going back one
+// period at most, and what ends before the next
+func (s *DigestService) compile(ctx context.Context, preferences *domain.DigestPreferences, now time.Time) (*domain.Digest, error) {
+	period := preferences.Period()
+	since := now.Add(-period)
+	if preferences.LastSentAt != nil && preferences.LastSentAt.After(since) {
+		since = *preferences.LastSentAt
+	}
+	next := now.Add(period)
+
+	digest := &domain.Digest{
+		Since:         since,
+		Until:         now,
+		WatchedEnding: make([]domain.Auction, 0),
+		SavedSearches: make([]domain.SavedSearchMatches, 0),
+	}
+
+	if preferences.WatchedEnding {
+		watched, err := s.digestRepo.WatchedEnding(ctx, preferences.UserID, next, digestWatchedLimit)
+		if err != nil {
+			return nil, err
+		}
+		digest.WatchedEnding = watched
+	}
+
+	if preferences.SavedSearches {
+		searches, err := s.savedSearchRepo.ListByUser(ctx, preferences.UserID)
+		if err != nil {
+			return nil, err
+		}
+		for _, search := range searches {
+			params := search.Query.ListParams(preferences.UserID)
+			params.StartedAfter = &since
+			params.Page = 1
+			params.Limit = digestSearchMatches
+
+			auctions, total, err := s.auctionRepo.List(ctx, params)
+			if err != nil {
+				return nil, err
+			}
+			if total > 0 {
+				digest.SavedSearches = append(digest.SavedSearches, domain.SavedSearchMatches{
+					Search:   search,
+					Auctions: auctions,
+					Total:    total,
+				})
+			}
+		}
+	}
+
+	if preferences.SellerStats {
+		stats, err := s.digestRepo.SellerStats(ctx, preferences.UserID, since, now, next)
+		if err != nil {
+			return nil, err
+		}
+		if !stats.Empty() {
+			digest.SellerStats = stats
+		}
+	}
+
+	return digest, nil
+}
+
+// SendDue sends the digests due by now and returns how many went out.
+// Digests with nothing in them aren't sent but still count as the user's
+// last, and one that fails is tried again once its claim runs out.
+func (s *DigestService) SendDue(ctx context.Context, now time.Time, batchSize int) (int, error) {
+	due, err := s.digestRepo.ClaimDue(ctx, now, digestLease, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range due {
+		preferences := &due[i]
+		next, err := preferences.NextAfter(now)
+		if err != nil {
+			log.Printf("Sending digests for user %s daily from now: %v", preferences.UserID, err)
+			next = now.Add(preferences.Period())
+		}
+
+		emailed, err := s.send(ctx, preferences, now)
+		if err != nil {
+			log.Printf("Failed to send digest to user %s: %v", preferences.UserID, err)
+			continue
+		}
+		if emailed {
+			sent++
+		}
+
+		if err := s.digestRepo.MarkSent(ctx, preferences.UserID, now, next); err != nil {
+			log.Printf("Failed to mark digest sent for user %s: %v", preferences.UserID, err)
+		}
+	}
+	return sent, nil
+}
+
+// send emails the user their digest, reporting false when there was nothing
+// to send
+func (s *DigestService) send(ctx context.Context, preferences *domain.DigestPreferences, now time.Time) (bool, error) {
+	user, err := s.userRepo.GetByID(ctx, preferences.UserID)
+	if err != nil {
+		return false, err
+	}
+	if user.IsDeleted {
+		return false, nil
+	}
+
+	digest, err := s.compile(ctx, preferences, now)
+	if err != nil {
+		return false, err
+	}
+	if digest.Empty() {
+		return false, nil
+	}
+
+	data := email.NewActivityDigestEmail(
+		user.Email,
+		string(preferences.Frequency),
+		s.sections(digest, preferences),
+		s.links.profile("notifications"),
+	)
+	if err := s.emailSender.Send(data); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// sections lays the digest out for its email, with times in the user's
+// timezone
+func (s *DigestService) sections(digest *domain.Digest, preferences *domain.DigestPreferences) []email.DigestSection {
+	loc, err := time.LoadLocation(preferences.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	sections := make([]email.DigestSection, 0, 2+len(digest.SavedSearches))
+	if len(digest.WatchedEnding) > 0 {
+		section := email.DigestSection{Title: "Watched auctions ending soon"}
+		for _, auction := range digest.WatchedEnding {
+			section.Lines = append(section.Lines, fmt.Sprintf("%s: %s, ends %s\n    %s",
+				auction.Title,
+				auction.Price(auction.CurrentPrice),
+				auction.EndTime.In(loc).Format("Mon Jan 2 15:04 MST"),
+				s.links.auction(auction.ID),
+			))
+		}
+		sections = append(sections, section)
+	}
+
+	for _, matches := range digest.SavedSearches {
+		section := email.DigestSection{Title: fmt.Sprintf("New listings for %q", matches.Search.Name)}
+		for _, auction := range matches.Auctions {
+			section.Lines = append(section.Lines, fmt.Sprintf("%s: %s\n    %s",
+				auction.Title,
+				auction.Price(auction.CurrentPrice),
+				s.links.auction(auction.ID),
+			))
+		}
+		if more := matches.Total - len(matches.Auctions); more > 0 {
+			section.Lines = append(section.Lines, fmt.Sprintf("and %d more", more))
+		}
+		sections = append(sections, section)
+	}
+
+	if stats := digest.SellerStats; stats != nil {
+		sections = append(sections, email.DigestSection{
+			Title: "Your listings",
+			Lines: []string{
+				fmt.Sprintf("%d active, %d ending before your next digest", stats.ActiveListings, stats.EndingSoon),
+				fmt.Sprintf("%d new bids and %d new watchers", stats.NewBids, stats.NewWatchers),
+				fmt.Sprintf("%d sold and %d ended unsold", stats.Sold, stats.Unsold),
+			},
+		})
+	}
+
+	return sections
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/pkg/storage"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type DisputeService struct {
+	disputeRepo     repository.DisputeRepository
+	messageRepo     repository.MessageRepository
+	orderRepo       repository.OrderRepository
+	auctionRepo     repository.AuctionRepository
+	auditRepo       repository.AuditLogRepository
+	txManager       repository.TxManager
+	notificationSvc *NotificationService
+	ledgerSvc       *LedgerService
+	storage         *storage.S3Storage
+}
+
+func NewDisputeService(
+	disputeRepo repository.DisputeRepository,
+	messageRepo repository.MessageRepository,
+	orderRepo repository.OrderRepository,
+	auctionRepo repository.AuctionRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager repository.TxManager,
+	notificationSvc *NotificationService,
+	ledgerSvc *LedgerService,
+	storage *storage.S3Storage,
+) *DisputeService {
+	return &DisputeService{
+		disputeRepo:     disputeRepo,
+		messageRepo:     messageRepo,
+		orderRepo:       orderRepo,
+		auctionRepo:     auctionRepo,
+		auditRepo:       auditRepo,
+		txManager:       txManager,
+		notificationSvc: notificationSvc,
+		ledgerSvc:       ledgerSvc,
+		storage:         storage,
+	}
+}
+
+// Open starts a dispute on an order. Either party to the order may open one.
+func (s *DisputeService) Open(ctx context.Context, orderID, userID uuid.UUID, req *domain.OpenDisputeRequest) (*domain.Dispute, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.BuyerID != userID && order.SellerID != userID {
+		return nil, domain.ErrForbidden
+	}
+
+	if order.Status == domain.OrderStatusCancelled {
+		return nil, domain.ErrOrderNotPending
+	}
+
+	existing, err := s.disputeRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range existing {
+		if d.Status == domain.DisputeStatusOpen {
+			return nil, domain.ErrDisputeExists
+		}
+	}
+
+	dispute := &domain.Dispute{
+		OrderID:     order.ID,
+		AuctionID:   order.AuctionID,
+		OpenedBy:    userID,
+		BuyerID:     order.BuyerID,
+		SellerID:    order.SellerID,
+		Reason:      domain.DisputeReason(req.Reason),
+		Description: req.Description,
+		Status:      domain.DisputeStatusOpen,
+	}
+
+	if err := s.disputeRepo.Create(ctx, dispute); err != nil {
+		return nil, err
+	}
+
+	// Put the parties' conversations on legal hold right away rather than
+	// waiting for the next retention run
+	if err := s.messageRepo.RefreshLegalHold(ctx, dispute.BuyerID, dispute.SellerID); err != nil {
+		log.Printf("Failed to place legal hold for dispute %s: %v", dispute.ID, err)
+	}
+
+	if s.notificationSvc != nil {
+		go s.notifyParties(context.Background(), dispute, userID, s.notificationSvc.NotifyDisputeOpened)
+	}
+
+	return dispute, nil
+}
+
+// GetByID returns a dispute with its messages and evidence. Admins may view
+// any dispute; otherwise only the buyer and seller can.
+func (s *DisputeService) GetByID(ctx context.Context, id, userID uuid.UUID, isAdmin bool) (*domain.Dispute, error) {
+	dispute, err := s.disputeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isAdmin && !isDisputeParty(dispute, userID) {
+		return nil, domain.ErrForbidden
+	}
+
+	dispute.Messages, err = s.disputeRepo.GetMessages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	dispute.Evidence, err = s.disputeRepo.GetEvidence(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dispute, nil
+}
+
+func (s *DisputeService) GetByOrder(ctx context.Context, orderID, userID uuid.UUID) ([]domain.Dispute, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.BuyerID != userID && order.SellerID != userID {
+		return nil, domain.ErrForbidden
+	}
+
+	return s.disputeRepo.GetByOrderID(ctx, orderID)
+}
+
+func (s *DisputeService) List(ctx context.Context, params *domain.DisputeListParams) ([]domain.Dispute, int, error) {
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+
+	return s.disputeRepo.List(ctx, params)
+}
+
+// AddMessage posts to the dispute thread and notifies everyone else on it
+func (s *DisputeService) AddMessage(ctx context.Context, disputeID, userID uuid.UUID, isAdmin bool, req *domain.DisputeMessageRequest) (*domain.DisputeMessage, error) {
+	dispute, err := s.disputeRepo.GetByID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isAdmin && !isDisputeParty(dispute, userID) {
+		return nil, domain.ErrForbidden
+	}
+
+	if dispute.Status != domain.DisputeStatusOpen {
+		return nil, domain.ErrDisputeClosed
+	}
+
+	msg := &domain.DisputeMessage{
+		DisputeID: disputeID,
+		SenderID:  userID,
+		IsAdmin:   isAdmin && !isDisputeParty(dispute, userID),
+		Body:      req.Body,
+	}
+
+	if err := s.disputeRepo.AddMessage(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	if s.notificationSvc != nil {
+		go s.notifyParties(context.Background(), dispute, userID, s.notificationSvc.NotifyDisputeMessage)
+	}
+
+	return msg, nil
+}
+
+func (s *DisputeService) UploadEvidence(ctx context.Context, disputeID, userID uuid.UUID, reader io.Reader, contentType string, size int64, description *string) (*domain.DisputeEvidence, error) {
+	dispute, err := s.disputeRepo.GetByID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isDisputeParty(dispute, userID) {
+		return nil, domain.ErrForbidden
+	}
+
+	if dispute.Status != domain.DisputeStatusOpen {
+		return nil, domain.ErrDisputeClosed
+	}
+
+	// Validate content type
+	if !storage.ValidateEvidenceContentType(contentType) {
+		return nil, errors.New("invalid evidence type")
+	}
+
+	// Validate size
+	if size > storage.MaxImageSize {
+		return nil, errors.New("evidence file too large")
+	}
+
+	folder := storage.GetDisputeEvidenceFolder(disputeID)
+	url, err := s.storage.Upload(ctx, reader, contentType, size, folder)
+	if err != nil {
+		return nil, err
+	}
+
+	evidence := &domain.DisputeEvidence{
+		DisputeID:   disputeID,
+		UploadedBy:  userID,
+		URL:         url,
+		Description: description,
+	}
+
+	if err := s.disputeRepo.AddEvidence(ctx, evidence); err != nil {
+		// Try to delete uploaded file
+		_ = s.storage.Delete(ctx, url)
+		return nil, err
+	}
+
+	return evidence, nil
+}
+
+// Resolve records the admin outcome. Refunds cover the full order amount,
+// partial refunds need an amount below the order total, and upholding the
+// sale refunds nothing. Refunds are paid back out of escrow.
+func (s *DisputeService) Resolve(ctx context.Context, disputeID, adminID uuid.UUID, req *domain.ResolveDisputeRequest) (*domain.Dispute, error) {
+	dispute, err := s.disputeRepo.GetByID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dispute.Status != domain.DisputeStatusOpen {
+		return nil, domain.ErrDisputeClosed
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, dispute.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	outcome := domain.DisputeOutcome(req.Outcome)
+	var refund *decimal.Decimal
+
+	switch outcome {
+	case domain.DisputeOutcomeRefund:
+		amount := order.Amount
+		refund = &amount
+	case domain.DisputeOutcomePartial:
+		if req.RefundAmount == nil {
+			return nil, domain.ErrInvalidRefund
+		}
+		amount, err := domain.ParseAmount(*req.RefundAmount)
+		if err != nil || !amount.IsPositive() || amount.GreaterThanOrEqual(order.Amount) {
+			return nil, domain.ErrInvalidRefund
+		}
+		refund = &amount
+	}
+
+	dispute.Outcome = &outcome
+	dispute.RefundAmount = refund
+	dispute.ResolutionNote = req.Note
+	dispute.ResolvedBy = &adminID
+
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.disputeRepo.Resolve(txCtx, dispute); err != nil {
+			return err
+		}
+
+		if refund != nil && s.ledgerSvc != nil {
+			if err := s.ledgerSvc.Refund(txCtx, order, *refund); err != nil {
+				return err
+			}
+		}
+
+		metadata := map[string]interface{}{
+			"order_id": dispute.OrderID,
+			"outcome":  outcome,
+		}
+		if refund != nil {
+			metadata["refund_amount"] = refund.StringFixed(2)
+		}
+
+		return s.auditRepo.Create(txCtx, &domain.AuditLog{
+			ActorID:    &adminID,
+			Action:     domain.AuditActionDisputeResolve,
+			EntityType: domain.AuditEntityDispute,
+			EntityID:   &dispute.ID,
+			Reason:     req.Note,
+			Metadata:   metadata,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.messageRepo.RefreshLegalHold(ctx, dispute.BuyerID, dispute.SellerID); err != nil {
+		log.Printf("Failed to release legal hold for dispute %s: %v", dispute.ID, err)
+	}
+
+	if s.notificationSvc != nil {
+		go s.notifyParties(context.Background(), dispute, adminID, s.notificationSvc.NotifyDisputeResolved)
+	}
+
+	return dispute, nil
+}
+
+// notifyParties sends a dispute notification to the buyer and seller,
+// skipping whoever triggered it.
+func (s *DisputeService) notifyParties(ctx context.Context, dispute *domain.Dispute, actorID uuid.UUID, notify func(context.Context, uuid.UUID, *domain.Dispute, *domain.Auction)) {
+	auction, err := s.auctionRepo.GetByID(ctx, dispute.AuctionID)
+	if err != nil {
+		return
+	}
+
+	for _, userID := range []uuid.UUID{dispute.BuyerID, dispute.SellerID} {
+		if userID != actorID {
+			notify(ctx, userID, dispute, auction)
+		}
+	}
+}
+
+func isDisputeParty(dispute *domain.Dispute, userID uuid.UUID) bool {
+	return dispute.BuyerID == userID || dispute.SellerID == userID
+}
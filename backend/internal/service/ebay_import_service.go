@@ -0,0 +1,326 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// ebayMatchCandidates is how many listings sharing words with an item's
+	// title are compared with it
+	ebayMatchCandidates = 10
+
+	// ebayMatchThreshold is how alike, from 0 to 1, an item's title and a
+	// listing's must be for the item to be matched to it. Card titles share
+	// many words, such as the set and grader, so alike isn't enough: two
+	// cards of one set graded alike score around 0.6.
+	ebayMatchThreshold = 0.7
+)
+
+// ebayColumns maps the column names eBay's exports have used over the
+// years to the fields read; other columns, such as prices, are ignored
+var ebayColumns = map[string]string{
+	"item_number":     "item_number",
+	"item_id":         "item_number",
+	"item_no":         "item_number",
+	"title":           "title",
+	"item_title":      "title",
+	"seller":          "seller",
+	"seller_id":       "seller",
+	"seller_user_id":  "seller",
+	"seller_username": "seller",
+	"saved_seller":    "seller",
+	"user_id":         "seller",
+	"member_id":       "seller",
+}
+
+// EbayImportService brings what users saved on eBay with them: watched items
+// become watchlist entries when a listing here is alike enough, and saved
+// sellers become follows of the user with the same username. Files are
+// small, so they are imported straight away rather than in the background.
+type EbayImportService struct {
+	auctionRepo   repository.AuctionRepository
+	watchlistRepo repository.WatchlistRepository
+	userRepo      repository.UserRepository
+	followSvc     *FollowService
+	maxRows       int
+	maxFileBytes  int64
+}
+
+func NewEbayImportService(
+	auctionRepo repository.AuctionRepository,
+	watchlistRepo repository.WatchlistRepository,
+	userRepo repository.UserRepository,
+	followSvc *FollowService,
+	maxRows int,
+	maxFileBytes int64,
+) *EbayImportService {
+	return &EbayImportService{
+		auctionRepo:   auctionRepo,
+		watchlistRepo: watchlistRepo,
+		userRepo:      userRepo,
+		followSvc:     followSvc,
+		maxRows:       maxRows,
+		maxFileBytes:  maxFileBytes,
+	}
+}
+
+// ebayRow is one row of an export, as written
+type ebayRow struct {
+	itemNumber string
+	title      string
+	seller     string
+}
+
+// Import reads an eBay watchlist or saved sellers CSV export and adds what
+// it can match for the user. It returns an error wrapping ErrImportFile,
+// saying what is wrong, for files that can't be read.
+func (s *EbayImportService) Import(ctx context.Context, userID uuid.UUID, file io.Reader) (*domain.EbayImportReport, error) {
+	data, err := io.ReadAll(io.LimitReader(file, s.maxFileBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxFileBytes {
+		return nil, fmt.Errorf("%w: file is larger than %d MB", domain.ErrImportFile, s.maxFileBytes>>20)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	kind, rows, err := parseEbayExport(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: file has no rows", domain.ErrImportFile)
+	}
+	if s.maxRows > 0 && len(rows) > s.maxRows {
+		return nil, fmt.Errorf("%w: file has %d rows, at most %d may be imported at once", domain.ErrImportFile, len(rows), s.maxRows)
+	}
+
+	report := &domain.EbayImportReport{
+		Kind:      kind,
+		TotalRows: len(rows),
+		Rows:      make([]domain.EbayImportRowResult, 0, len(rows)),
+	}
+	for i, row := range rows {
+		var result domain.EbayImportRowResult
+		if kind == domain.EbayImportWatchlist {
+			result, err = s.importItem(ctx, userID, row)
+		} else {
+			result, err = s.importSeller(ctx, userID, row)
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Row = i + 1
+
+		switch result.Status {
+		case domain.EbayImportWatched, domain.EbayImportFollowed:
+			report.Added++
+		case domain.EbayImportAlreadyWatched, domain.EbayImportAlreadyFollowed:
+			report.Existing++
+		default:
+			report.Unmatched++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	return report, nil
+}
+
+// importItem watches the listing most like the item, if any is alike enough
+func (s *EbayImportService) importItem(ctx context.Context, userID uuid.UUID, row ebayRow) (domain.EbayImportRowResult, error) {
+	result := domain.EbayImportRowResult{
+		ItemNumber: row.itemNumber,
+		Title:      row.title,
+		Seller:     row.seller,
+		Status:     domain.EbayImportUnmatched,
+	}
+
+	words := titleWords(row.title)
+	if len(words) == 0 {
+		result.Reason = "item has no title"
+		return result, nil
+	}
+
+	candidates, err := s.auctionRepo.FindActiveByTitle(ctx, row.title, ebayMatchCandidates)
+	if err != nil {
+		return result, err
+	}
+
+	var best *domain.Auction
+	for i := range candidates {
+		if candidates[i].SellerID == userID {
+			continue
+		}
+		if score := titleSimilarity(words, titleWords(candidates[i].Title)); score > result.Score {
+			best, result.Score = &candidates[i], score
+		}
+	}
+	if best == nil || result.Score < ebayMatchThreshold {
+		result.Reason = "no active listing is alike enough"
+		result.Score = 0
+		return result, nil
+	}
+	result.AuctionID = &best.ID
+	result.MatchTitle = best.Title
+
+	watched, err := s.watchlistRepo.Exists(ctx, userID, best.ID)
+	if err != nil {
+		return result, err
+	}
+	if watched {
+		result.Status = domain.EbayImportAlreadyWatched
+		return result, nil
+	}
+
+	if err := s.watchlistRepo.Add(ctx, &domain.WatchlistItem{UserID: userID, AuctionID: best.ID}); err != nil {
+		return result, err
+	}
+	result.Status = domain.EbayImportWatched
+	return result, nil
+}
+
+// importSeller follows the user with the seller's username, tried as written
+// and then in lower case
+func (s *EbayImportService) importSeller(ctx context.Context, userID uuid.UUID, row ebayRow) (domain.EbayImportRowResult, error) {
+	result := domain.EbayImportRowResult{
+		Seller: row.seller,
+		Status: domain.EbayImportUnmatched,
+	}
+
+	username := strings.TrimSpace(row.seller)
+	if username == "" {
+		result.Reason = "row has no seller"
+		return result, nil
+	}
+
+	seller, err := s.userRepo.GetByUsername(ctx, username)
+	if errors.Is(err, domain.ErrNotFound) && strings.ToLower(username) != username {
+		seller, err = s.userRepo.GetByUsername(ctx, strings.ToLower(username))
+	}
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && seller.IsDeleted) {
+		result.Reason = "no user has this username"
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	if seller.ID == userID {
+		result.Reason = "this is your own username"
+		return result, nil
+	}
+
+	_, created, err := s.followSvc.follow(ctx, userID, seller.ID)
+	if err != nil {
+		return result, err
+	}
+	result.SellerID = &seller.ID
+	if created {
+		result.Status = domain.EbayImportFollowed
+	} else {
+		result.Status = domain.EbayImportAlreadyFollowed
+	}
+	return result, nil
+}
+
+// parseEbayExport reads an export whose first line names the columns. It is
+// a watchlist export if it has titles, else a saved sellers export.
+func parseEbayExport(data []byte) (domain.EbayImportKind, []ebayRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return "", nil, fmt.Errorf("%w: file is empty", domain.ErrImportFile)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", domain.ErrImportFile, err)
+	}
+
+	fields := make([]string, len(header))
+	found := make(map[string]bool)
+	for i, name := range header {
+		fields[i] = ebayColumns[importField(name)]
+		found[fields[i]] = true
+	}
+
+	var kind domain.EbayImportKind
+	switch {
+	case found["title"]:
+		kind = domain.EbayImportWatchlist
+	case found["seller"]:
+		kind = domain.EbayImportSavedSellers
+	default:
+		return "", nil, fmt.Errorf("%w: expected an eBay watchlist export with a Title column or a saved sellers export with a Seller column", domain.ErrImportFile)
+	}
+
+	rows := make([]ebayRow, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %v", domain.ErrImportFile, err)
+		}
+
+		var row ebayRow
+		for i, value := range record {
+			if i >= len(fields) {
+				break
+			}
+			switch fields[i] {
+			case "item_number":
+				row.itemNumber = strings.TrimSpace(value)
+			case "title":
+				row.title = strings.TrimSpace(value)
+			case "seller":
+				row.seller = strings.TrimSpace(value)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return kind, rows, nil
+}
+
+// titleWords returns the distinct words of a title in lower case, leaving
+// out single letters; card numbers such as "4/102" give "4" and "102"
+func titleWords(title string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(word) > 1 || unicode.IsDigit(rune(word[0])) {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+// titleSimilarity is the Dice coefficient of two titles' words: 1 when they
+// have the same words, 0 when they share none
+func titleSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	// Size of the embed card, which consumers may ask to shrink
+	embedWidth     = 400
+	embedHeight    = 160
+	embedMinWidth  = 240
+	embedMinHeight = 120
+
+	// oEmbedCacheAge is how long consumers may keep oEmbed responses. The
+	// HTML only frames the card, so it doesn't go stale as bids come in.
+	oEmbedCacheAge = 86400
+
+	embedProviderName = "Auction Cards"
+)
+
+// EmbedService serves public auctions as cards for other sites to embed,
+// directly or found by oEmbed from a link to the auction
+type EmbedService struct {
+	auctionSvc  *AuctionService
+	frontendURL string
+	publicURL   string
+}
+
+func NewEmbedService(auctionSvc *AuctionService, frontendURL, publicURL string) *EmbedService {
+	return &EmbedService{
+		auctionSvc:  auctionSvc,
+		frontendURL: frontendURL,
+		publicURL:   publicURL,
+	}
+}
+
+// Auction returns the embed card of an auction. Only auctions anyone can see
+// are embeddable; drafts and private auctions are not found, even with a
+// share link.
+func (s *EmbedService) Auction(ctx context.Context, id uuid.UUID) (*domain.AuctionEmbed, error) {
+	auction, err := s.auctionSvc.GetByID(ctx, id, uuid.Nil, "")
+	if err != nil {
+		return nil, err
+	}
+	if auction.Status == domain.AuctionStatusDraft || auction.Visibility == domain.AuctionPrivate {
+		return nil, domain.ErrNotFound
+	}
+
+	embed := &domain.AuctionEmbed{
+		ID:           auction.ID,
+		Title:        auction.Title,
+		CurrentPrice: auction.Price(auction.CurrentPrice),
+		BidCount:     auction.BidCount,
+		Status:       auction.Status,
+		EndTime:      auction.EndTime,
+		URL:          fmt.Sprintf("%s/auctions/%s", s.frontendURL, auction.ID),
+	}
+	if len(auction.Images) > 0 {
+		image := auction.Images[0]
+		imageURL := image.URL
+		if image.MediumURL != nil {
+			imageURL = *image.MediumURL
+		}
+		embed.ImageURL = &imageURL
+	}
+
+	return embed, nil
+}
+
+// OEmbed answers an oEmbed request for a link to an auction page or to its
+// embed card. A maxWidth or maxHeight above zero shrinks the frame to fit,
+// down to the smallest the card can be shown at. Links to anything else
+// are not found.
+func (s *EmbedService) OEmbed(ctx context.Context, link string, maxWidth, maxHeight int) (*domain.OEmbed, error) {
+	auctionID, ok := s.embeddedAuctionID(link)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	embed, err := s.Auction(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	width, height := embedWidth, embedHeight
+	if maxWidth > 0 && maxWidth < width {
+		width = max(maxWidth, embedMinWidth)
+	}
+	if maxHeight > 0 && maxHeight < height {
+		height = max(maxHeight, embedMinHeight)
+	}
+
+	frame := fmt.Sprintf(`<iframe src="%s" width="%d" height="%d" title="%s" style="border:0;overflow:hidden" loading="lazy"></iframe>`,
+		html.EscapeString(s.EmbedURL(embed.ID)), width, height, html.EscapeString(embed.Title))
+
+	return &domain.OEmbed{
+		Type:         "rich",
+		Version:      "1.0",
+		Title:        embed.Title,
+		ProviderName: embedProviderName,
+		ProviderURL:  s.frontendURL,
+		CacheAge:     oEmbedCacheAge,
+		HTML:         frame,
+		Width:        width,
+		Height:       height,
+	}, nil
+}
+
+// EmbedURL is where the auction's embed card is served
+func (s *EmbedService) EmbedURL(auctionID uuid.UUID) string {
+	return fmt.Sprintf("%s/embed/auctions/%s", s.publicURL, auctionID)
+}
+
+// OEmbedURL is where oEmbed consumers look up the auction, for discovery
+// links
+func (s *EmbedService) OEmbedURL(auctionID uuid.UUID) string {
+	return fmt.Sprintf("%s/api/oembed?url=%s", s.publicURL, url.QueryEscape(fmt.Sprintf("%s/auctions/%s", s.frontendURL, auctionID)))
+}
+
+// embeddedAuctionID reads the auction ID from a link to an auction page or
+// embed card of this site
+func (s *EmbedService) embeddedAuctionID(link string) (uuid.UUID, bool) {
+	parsed, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || parsed.Host == "" {
+		return uuid.Nil, false
+	}
+
+	for _, base := range []struct{ origin, prefix string }{
+		{s.frontendURL, "/auctions/"},
+		{s.publicURL, "/embed/auctions/"},
+	} {
+		baseURL, err := url.Parse(base.origin)
+		if err != nil || !strings.EqualFold(parsed.Host, baseURL.Host) {
+			continue
+		}
+		rest, ok := strings.CutPrefix(parsed.Path, strings.TrimSuffix(baseURL.Path, "/")+base.prefix)
+		if !ok {
+			continue
+		}
+		id, err := uuid.Parse(strings.TrimSuffix(rest, "/"))
+		if err == nil {
+			return id, true
+		}
+	}
+
+	return uuid.Nil, false
+}
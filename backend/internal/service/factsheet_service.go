@@ -0,0 +1,513 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/pkg/pdf"
+	"github.com/auction-cards/backend/internal/pkg/qrcode"
+	"github.com/auction-cards/backend/internal/pkg/storage"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/disintegration/imaging"
+	"github.com/google/uuid"
+)
+
+const (
+	// factsheetLease keeps a sheet from being claimed by another worker
+	// while it is rendered
+	factsheetLease = 5 * time.Minute
+
+	// factsheetMaxAge is how old the price on an active auction's sheet may
+	// get; an older sheet is still handed out while a new one is rendered
+	factsheetMaxAge = time.Hour
+
+	// factsheetLinkTTL is how long a sheet's download link is valid
+	factsheetLinkTTL = 15 * time.Minute
+
+	// factsheetImages is how many of the auction's images a sheet shows: a
+	// large one with the rest as thumbnails below it
+	factsheetImages = 4
+
+	factsheetImageTimeout = 15 * time.Second
+)
+
+// FactsheetService renders printable one-page PDF sheets of auctions for
+// dealers to put out at shows: images, description, current price and a QR
+// code linking to the auction. Sheets are rendered in the background, kept
+// in object storage and rendered again when the auction changes in a way
+// the sheet shows, or when the price on an active auction's sheet gets old.
+type FactsheetService struct {
+	factsheetRepo repository.AuctionFactsheetRepository
+	auctionRepo   repository.AuctionRepository
+	auctionSvc    *AuctionService
+	store         storage.ObjectStore
+	images        *http.Client
+	baseURL       string
+}
+
+func NewFactsheetService(
+	factsheetRepo repository.AuctionFactsheetRepository,
+	auctionRepo repository.AuctionRepository,
+	auctionSvc *AuctionService,
+	store storage.ObjectStore,
+	baseURL string,
+) *FactsheetService {
+	return &FactsheetService{
+		factsheetRepo: factsheetRepo,
+		auctionRepo:   auctionRepo,
+		auctionSvc:    auctionSvc,
+		store:         store,
+		images:        &http.Client{Timeout: factsheetImageTimeout},
+		baseURL:       baseURL,
+	}
+}
+
+// Get returns the auction's sheet with a download link when one showing
+// the auction as it is now is stored; otherwise it queues one and returns
+// it without a link. Viewers need to be able to see the auction.
+func (s *FactsheetService) Get(ctx context.Context, auctionID, viewerID uuid.UUID, shareToken string) (*domain.AuctionFactsheet, error) {
+	if s.store == nil {
+		return nil, domain.ErrFactsheetsUnavailable
+	}
+
+	auction, err := s.auctionSvc.GetByID(ctx, auctionID, viewerID, shareToken)
+	if err != nil {
+		return nil, err
+	}
+
+	factsheet, err := s.factsheetRepo.GetByAuction(ctx, auctionID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	if factsheet == nil || !factsheet.Matches(factsheetFingerprint(auction)) {
+		if factsheet != nil && (factsheet.Status == domain.FactsheetPending || factsheet.Status == domain.FactsheetProcessing) {
+			return factsheet, nil
+		}
+		return s.factsheetRepo.Request(ctx, auctionID)
+	}
+
+	if auction.Status == domain.AuctionStatusActive && factsheet.GeneratedAt != nil &&
+		time.Since(*factsheet.GeneratedAt) > factsheetMaxAge {
+		refreshed, err := s.factsheetRepo.Request(ctx, auctionID)
+		if err != nil {
+			return nil, err
+		}
+		factsheet.Status = refreshed.Status
+	}
+
+	url, err := s.store.GetPresignedURL(ctx, *factsheet.ObjectName, factsheetLinkTTL)
+	if err != nil {
+		return nil, err
+	}
+	factsheet.DownloadURL = url
+	return factsheet, nil
+}
+
+// ProcessPending renders queued sheets one after another until none are
+// left. A sheet that can't be rendered is marked failed and queued again
+// the next time it is asked for.
+func (s *FactsheetService) ProcessPending(ctx context.Context) {
+	if s.store == nil {
+		return
+	}
+
+	for {
+		now := time.Now()
+		factsheet, err := s.factsheetRepo.ClaimNext(ctx, now, now.Add(factsheetLease))
+		if errors.Is(err, domain.ErrNotFound) {
+			return
+		}
+		if err != nil {
+			log.Printf("Error claiming factsheet: %v", err)
+			return
+		}
+
+		if err := s.render(ctx, factsheet); err != nil {
+			log.Printf("Error rendering factsheet for auction %s: %v", factsheet.AuctionID, err)
+
+			factsheet.Status = domain.FactsheetFailed
+			factsheet.LeaseUntil = nil
+			if err := s.factsheetRepo.Save(ctx, factsheet); err != nil {
+				log.Printf("Error saving factsheet for auction %s: %v", factsheet.AuctionID, err)
+				return
+			}
+		}
+	}
+}
+
+// render stores a new sheet for the auction, replacing the previous one
+func (s *FactsheetService) render(ctx context.Context, factsheet *domain.AuctionFactsheet) error {
+	auction, err := s.auctionRepo.GetByIDWithDetails(ctx, factsheet.AuctionID)
+	if err != nil {
+		return err
+	}
+
+	images := make([]image.Image, 0, factsheetImages)
+	for _, img := range auction.Images {
+		if len(images) == factsheetImages {
+			break
+		}
+		decoded, err := s.fetchImage(ctx, img)
+		if err != nil {
+			log.Printf("Error loading image %s for factsheet: %v", img.ID, err)
+			continue
+		}
+		images = append(images, decoded)
+	}
+
+	now := time.Now()
+	data, err := renderFactsheet(auction, images, s.auctionURL(auction.ID), now)
+	if err != nil {
+		return err
+	}
+
+	objectName := path.Join(storage.GetFactsheetFolder(auction.ID), uuid.New().String()+".pdf")
+	if err := s.store.PutObject(ctx, objectName, bytes.NewReader(data), int64(len(data)), "application/pdf"); err != nil {
+		return err
+	}
+
+	previous := factsheet.ObjectName
+	fingerprint := factsheetFingerprint(auction)
+	factsheet.Status = domain.FactsheetCompleted
+	factsheet.Fingerprint = &fingerprint
+	factsheet.ObjectName = &objectName
+	factsheet.LeaseUntil = nil
+	factsheet.GeneratedAt = &now
+	if err := s.factsheetRepo.Save(ctx, factsheet); err != nil {
+		_ = s.store.RemoveObject(ctx, objectName)
+		return err
+	}
+
+	if previous != nil {
+		if err := s.store.RemoveObject(ctx, *previous); err != nil {
+			log.Printf("Error deleting old factsheet for auction %s: %v", auction.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// fetchImage downloads an auction image, preferring its medium variant
+func (s *FactsheetService) fetchImage(ctx context.Context, img domain.AuctionImage) (image.Image, error) {
+	url := img.URL
+	if img.MediumURL != nil {
+		url = *img.MediumURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.images.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return imaging.Decode(io.LimitReader(resp.Body, storage.MaxImageSize), imaging.AutoOrientation(true))
+}
+
+func (s *FactsheetService) auctionURL(auctionID uuid.UUID) string {
+	return fmt.Sprintf("%s/auctions/%s", s.baseURL, auctionID)
+}
+
+// factsheetFingerprint hashes what a sheet shows of the auction, other than
+// its price and bids, so a stored sheet is rendered again once any of it
+// changes
+func factsheetFingerprint(auction *domain.Auction) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%d\x00%s\x00", auction.Title, deref(auction.Description),
+		auction.Status, auction.EndTime.Unix(), auction.StartingPrice.String())
+	if auction.Condition != nil {
+		fmt.Fprintf(h, "condition:%s\x00", *auction.Condition)
+	}
+	if auction.BuyNowPrice != nil {
+		fmt.Fprintf(h, "buy_now:%s\x00", auction.BuyNowPrice.String())
+	}
+	if auction.Category != nil {
+		fmt.Fprintf(h, "category:%s\x00", auction.Category.Name)
+	}
+	if c := auction.Certification; c != nil {
+		fmt.Fprintf(h, "cert:%s:%s:%s:%s\x00", c.Grader, c.Number, c.Status, deref(c.Grade))
+	}
+	for _, img := range auction.Images {
+		fmt.Fprintf(h, "image:%s\x00", img.ID)
+	}
+	for _, item := range auction.Items {
+		fmt.Fprintf(h, "item:%s\x00", item.Title)
+		if item.Condition != nil {
+			fmt.Fprintf(h, "item_condition:%s\x00", *item.Condition)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// Layout of a sheet on an A4 page, in points
+const (
+	sheetMargin     = 40.0
+	sheetWidth      = pdf.A4Width - 2*sheetMargin
+	sheetImageSize  = 300.0
+	sheetThumbSize  = 96.0
+	sheetColumnGap  = 20.0
+	sheetQRSize     = 150.0
+	sheetFooterSize = 8.0
+)
+
+// renderFactsheet lays out the sheet: title and details across the top,
+// images on the left, price and QR code on the right, then the lot
+// contents and description
+func renderFactsheet(auction *domain.Auction, images []image.Image, auctionURL string, generatedAt time.Time) ([]byte, error) {
+	doc := pdf.New(pdf.A4Width, pdf.A4Height)
+	y := sheetMargin + 20
+
+	title := pdf.Wrap(auction.Title, pdf.HelveticaBold, 20, sheetWidth)
+	for i, line := range title {
+		if i == 1 && len(title) > 2 {
+			line = truncateLine(line+" ...", pdf.HelveticaBold, 20, sheetWidth)
+		}
+		doc.Text(sheetMargin, y, pdf.HelveticaBold, 20, line)
+		y += 24
+		if i == 1 {
+			break
+		}
+	}
+
+	if details := factsheetDetails(auction); details != "" {
+		doc.SetGray(0.4)
+		doc.Text(sheetMargin, y, pdf.Helvetica, 10, truncateLine(details, pdf.Helvetica, 10, sheetWidth))
+		y += 10
+	}
+	doc.SetGray(0.85)
+	doc.Rect(sheetMargin, y, sheetWidth, 0.75)
+	doc.SetGray(0)
+	y += 16
+
+	// Images
+	left := y
+	if len(images) > 0 {
+		if err := drawFitted(doc, images[0], sheetMargin, left, sheetImageSize, sheetImageSize); err != nil {
+			return nil, err
+		}
+		left += sheetImageSize + 6
+		for i, img := range images[1:] {
+			x := sheetMargin + float64(i)*(sheetThumbSize+6)
+			if err := drawFitted(doc, img, x, left, sheetThumbSize, sheetThumbSize); err != nil {
+				return nil, err
+			}
+		}
+		if len(images) > 1 {
+			left += sheetThumbSize + 6
+		}
+	}
+
+	// Price, timing and QR code
+	x := sheetMargin
+	if len(images) > 0 {
+		x += sheetImageSize + sheetColumnGap
+	}
+	right := y + 10
+	label := func(text string) {
+		doc.SetGray(0.4)
+		doc.Text(x, right, pdf.Helvetica, 9, strings.ToUpper(text))
+		doc.SetGray(0)
+		right += 24
+	}
+
+	label("Current price")
+	doc.Text(x, right, pdf.HelveticaBold, 24, auction.Price(auction.CurrentPrice).String())
+	right += 16
+	bids := "No bids yet"
+	if auction.BidCount == 1 {
+		bids = "1 bid"
+	} else if auction.BidCount > 1 {
+		bids = fmt.Sprintf("%d bids", auction.BidCount)
+	}
+	doc.Text(x, right, pdf.Helvetica, 10, bids)
+	right += 14
+	if auction.BuyNowPrice != nil && auction.Status == domain.AuctionStatusActive {
+		doc.Text(x, right, pdf.Helvetica, 10, "Buy now: "+auction.Price(*auction.BuyNowPrice).String())
+		right += 14
+	}
+	right += 14
+
+	if auction.Status == domain.AuctionStatusActive {
+		label("Ends")
+		doc.Text(x, right, pdf.Helvetica, 12, auction.EndTime.UTC().Format("Mon, 2 Jan 2006 15:04 MST"))
+	} else {
+		label("Status")
+		doc.Text(x, right, pdf.Helvetica, 12, factsheetStatus(auction.Status))
+	}
+	right += 24
+
+	if err := drawQRCode(doc, auctionURL, x, right, sheetQRSize); err != nil {
+		return nil, err
+	}
+	right += sheetQRSize + 14
+	doc.Text(x, right, pdf.HelveticaBold, 11, "Scan to bid")
+	right += 12
+	for _, line := range pdf.Wrap(auctionURL, pdf.Helvetica, 7, pdf.A4Width-sheetMargin-x) {
+		doc.Text(x, right, pdf.Helvetica, 7, line)
+		right += 9
+	}
+
+	// Lot contents and description, cut off above the footer
+	type section struct {
+		heading string
+		lines   []string
+	}
+	var sections []section
+	if len(auction.Items) > 0 {
+		lot := section{heading: "Lot contents"}
+		for i, item := range auction.Items {
+			line := fmt.Sprintf("%d. %s", i+1, item.Title)
+			if item.Condition != nil {
+				line += " (" + conditionLabel(*item.Condition) + ")"
+			}
+			lot.lines = append(lot.lines, pdf.Wrap(line, pdf.Helvetica, 10, sheetWidth)...)
+		}
+		sections = append(sections, lot)
+	}
+	if description := strings.TrimSpace(deref(auction.Description)); description != "" {
+		sections = append(sections, section{heading: "Description", lines: pdf.Wrap(description, pdf.Helvetica, 10, sheetWidth)})
+	}
+
+	y = max(left, right) + 20
+	bottom := pdf.A4Height - sheetMargin - 2*sheetFooterSize
+	for i, sec := range sections {
+		if y+16+13 > bottom {
+			break
+		}
+		doc.Text(sheetMargin, y, pdf.HelveticaBold, 12, sec.heading)
+		y += 16
+		for j, line := range sec.lines {
+			more := j < len(sec.lines)-1 || i < len(sections)-1
+			if y+13 > bottom && more {
+				doc.Text(sheetMargin, y, pdf.Helvetica, 10, truncateLine(line+" ...", pdf.Helvetica, 10, sheetWidth))
+				y = bottom + 1
+				break
+			}
+			if line != "" {
+				doc.Text(sheetMargin, y, pdf.Helvetica, 10, line)
+			}
+			y += 13
+		}
+		y += 8
+	}
+
+	doc.SetGray(0.4)
+	doc.Text(sheetMargin, pdf.A4Height-sheetMargin, pdf.Helvetica, sheetFooterSize,
+		"Generated "+generatedAt.UTC().Format("2 Jan 2006 15:04 MST")+". The price is as of then; scan the code for the live auction.")
+
+	return doc.Bytes()
+}
+
+// factsheetDetails joins the condition, category, grade and seller for the
+// line under the title
+func factsheetDetails(auction *domain.Auction) string {
+	var parts []string
+	if auction.Condition != nil {
+		parts = append(parts, "Condition: "+conditionLabel(*auction.Condition))
+	}
+	if auction.Category != nil {
+		parts = append(parts, auction.Category.Name)
+	}
+	if c := auction.Certification; c != nil {
+		grade := strings.ToUpper(string(c.Grader))
+		if c.Grade != nil {
+			grade += " " + *c.Grade
+		}
+		parts = append(parts, grade+" (cert "+c.Number+")")
+	}
+	if auction.Seller != nil {
+		parts = append(parts, "Seller: "+auction.Seller.Username)
+	}
+	return strings.Join(parts, "  |  ")
+}
+
+func conditionLabel(condition domain.ItemCondition) string {
+	label := strings.ReplaceAll(string(condition), "_", " ")
+	if label == "" {
+		return label
+	}
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+func factsheetStatus(status domain.AuctionStatus) string {
+	switch status {
+	case domain.AuctionStatusCompleted:
+		return "Sold"
+	case domain.AuctionStatusUnsold:
+		return "Ended without a sale"
+	case domain.AuctionStatusCancelled:
+		return "Cancelled"
+	case domain.AuctionStatusDraft:
+		return "Not yet listed"
+	default:
+		return string(status)
+	}
+}
+
+// truncateLine shortens line from before its trailing " ..." until it fits
+func truncateLine(line string, font pdf.Font, size, width float64) string {
+	const ellipsis = " ..."
+	text := []rune(strings.TrimSuffix(line, ellipsis))
+	suffix := line[len(string(text)):]
+	for len(text) > 0 && pdf.TextWidth(string(text)+suffix, font, size) > width {
+		text = []rune(strings.TrimRight(string(text[:len(text)-1]), " "))
+		suffix = ellipsis
+	}
+	return string(text) + suffix
+}
+
+// drawFitted draws img as large as fits in the box, keeping its aspect
+// ratio and centering it
+func drawFitted(doc *pdf.Document, img image.Image, x, y, width, height float64) error {
+	bounds := img.Bounds()
+	scale := min(width/float64(bounds.Dx()), height/float64(bounds.Dy()))
+	w, h := float64(bounds.Dx())*scale, float64(bounds.Dy())*scale
+	return doc.Image(img, x+(width-w)/2, y+(height-h)/2, w, h)
+}
+
+// drawQRCode draws a QR code for text in a size by size square, including
+// the four module quiet zone readers need
+func drawQRCode(doc *pdf.Document, text string, x, y, size float64) error {
+	code, err := qrcode.Encode(text)
+	if err != nil {
+		return err
+	}
+
+	module := size / float64(code.Size+8)
+	for row := 0; row < code.Size; row++ {
+		for col := 0; col < code.Size; col++ {
+			if code.Dark(col, row) {
+				// Overlap neighbors slightly so viewers don't show seams
+				doc.Rect(x+float64(col+4)*module, y+float64(row+4)*module, module+0.05, module+0.05)
+			}
+		}
+	}
+	return nil
+}
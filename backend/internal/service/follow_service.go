@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// FollowService manages the sellers each user follows
+type FollowService struct {
+	followRepo repository.SellerFollowRepository
+	userRepo   repository.UserRepository
+}
+
+func NewFollowService(followRepo repository.SellerFollowRepository, userRepo repository.UserRepository) *FollowService {
+	return &FollowService{
+		followRepo: followRepo,
+		userRepo:   userRepo,
+	}
+}
+
+// Follow follows the seller; following one already followed is not an error
+func (s *FollowService) Follow(ctx context.Context, userID, sellerID uuid.UUID) (*domain.SellerFollow, error) {
+	follow, _, err := s.follow(ctx, userID, sellerID)
+	return follow, err
+}
+
+// follow also reports whether the seller wasn't followed already
+func (s *FollowService) follow(ctx context.Context, userID, sellerID uuid.UUID) (*domain.SellerFollow, bool, error) {
+	if sellerID == userID {
+		return nil, false, domain.ErrCannotFollowSelf
+	}
+
+	seller, err := s.userRepo.GetByID(ctx, sellerID)
+	if err != nil {
+		return nil, false, err
+	}
+	if seller.IsDeleted {
+		return nil, false, domain.ErrNotFound
+	}
+
+	follow := &domain.SellerFollow{
+		UserID:   userID,
+		SellerID: seller.ID,
+	}
+	created, err := s.followRepo.Follow(ctx, follow)
+	if err != nil {
+		return nil, false, err
+	}
+
+	follow.Seller = seller.ToPublic()
+	return follow, created, nil
+}
+
+func (s *FollowService) Unfollow(ctx context.Context, userID, sellerID uuid.UUID) error {
+	return s.followRepo.Unfollow(ctx, userID, sellerID)
+}
+
+func (s *FollowService) ListFollowing(ctx context.Context, userID uuid.UUID, params *domain.SellerFollowListParams) ([]domain.SellerFollow, int, error) {
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 || params.Limit > 100 {
+		params.Limit = 20
+	}
+	return s.followRepo.List(ctx, userID, params.Page, params.Limit)
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// fraudCheckInterval is how often the scheduler runs the checks
+	fraudCheckInterval = time.Hour
+
+	// How far back the checks look at bidding and orders
+	fraudLookback = 7 * 24 * time.Hour
+
+	// Cancelled bids it takes for a bidder to be flagged
+	fraudMinCancelledBids = 3
+
+	// Accounts this new are checked for bid velocity, over the last window
+	fraudNewAccountAge   = 7 * 24 * time.Hour
+	fraudVelocityWindow  = time.Hour
+	fraudVelocityMinBids = 20
+
+	// Severities are scored up to this
+	fraudMaxSeverity = 100
+)
+
+// FraudService runs heuristics for shill bidding and other fraud, raising
+// flags into an admin review queue. The checks only find leads: shared
+// addresses and devices have innocent explanations, so admins decide.
+type FraudService struct {
+	fraudRepo repository.FraudRepository
+}
+
+func NewFraudService(fraudRepo repository.FraudRepository) *FraudService {
+	return &FraudService{fraudRepo: fraudRepo}
+}
+
+// RunChecks runs every check over recent activity. A failing check is
+// logged and the rest still run.
+func (s *FraudService) RunChecks(ctx context.Context) *domain.FraudRunResult {
+	now := time.Now()
+	result := &domain.FraudRunResult{
+		Flags:     make(map[domain.FraudCheck]int),
+		StartedAt: now,
+	}
+
+	checks := []struct {
+		check domain.FraudCheck
+		find  func() ([]domain.FraudFlag, error)
+	}{
+		{domain.FraudCheckSharedIP, func() ([]domain.FraudFlag, error) { return s.sharedIPFlags(ctx, now) }},
+		{domain.FraudCheckBidRetraction, func() ([]domain.FraudFlag, error) { return s.retractionFlags(ctx, now) }},
+		{domain.FraudCheckBidVelocity, func() ([]domain.FraudFlag, error) { return s.velocityFlags(ctx, now) }},
+		{domain.FraudCheckSelfPurchase, func() ([]domain.FraudFlag, error) { return s.selfPurchaseFlags(ctx, now) }},
+	}
+	for _, c := range checks {
+		flags, err := c.find()
+		if err != nil {
+			log.Printf("Error running %s fraud check: %v", c.check, err)
+			continue
+		}
+
+		raised := 0
+		for i := range flags {
+			flags[i].Check = c.check
+			flags[i].Severity = min(flags[i].Severity, fraudMaxSeverity)
+			saved, err := s.fraudRepo.SaveFlag(ctx, &flags[i])
+			if err != nil {
+				log.Printf("Error saving %s fraud flag: %v", c.check, err)
+				continue
+			}
+			if saved {
+				raised++
+			}
+		}
+		result.Flags[c.check] = raised
+	}
+
+	result.Duration = time.Since(now).Round(time.Millisecond).String()
+	return result
+}
+
+// sharedIPFlags flags sellers whose auctions drew bids from one address
+// used by several bidders, or by the seller themselves
+func (s *FraudService) sharedIPFlags(ctx context.Context, now time.Time) ([]domain.FraudFlag, error) {
+	found, err := s.fraudRepo.FindSharedIPBidding(ctx, now.Add(-fraudLookback))
+	if err != nil {
+		return nil, err
+	}
+
+	flags := make([]domain.FraudFlag, 0, len(found))
+	for _, shared := range found {
+		severity := 20 + 15*(len(shared.BidderIDs)-1) + min(shared.Bids, 20)
+		if shared.SellerUsedIP {
+			severity += 40
+		}
+
+		flags = append(flags, domain.FraudFlag{
+			SubjectID:      &shared.SellerID,
+			RelatedUserIDs: shared.BidderIDs,
+			Severity:       severity,
+			Evidence: map[string]interface{}{
+				"ip":             shared.IP,
+				"bidder_ids":     shared.BidderIDs,
+				"auction_ids":    shared.AuctionIDs,
+				"bids":           shared.Bids,
+				"seller_used_ip": shared.SellerUsedIP,
+			},
+			DedupeKey: fmt.Sprintf("%s:%s", shared.SellerID, shared.IP),
+		})
+	}
+	return flags, nil
+}
+
+// retractionFlags flags bidders whose bids keep being cancelled. One seller
+// cancelling them all looks more like collusion than bad luck.
+func (s *FraudService) retractionFlags(ctx context.Context, now time.Time) ([]domain.FraudFlag, error) {
+	found, err := s.fraudRepo.FindBidRetractions(ctx, now.Add(-fraudLookback), fraudMinCancelledBids)
+	if err != nil {
+		return nil, err
+	}
+
+	flags := make([]domain.FraudFlag, 0, len(found))
+	for _, retractions := range found {
+		severity := 15*retractions.Cancelled + 30*retractions.Cancelled/max(retractions.Placed, 1)
+		if retractions.Sellers == 1 {
+			severity += 20
+		}
+
+		flags = append(flags, domain.FraudFlag{
+			SubjectID: &retractions.BidderID,
+			Severity:  severity,
+			Evidence: map[string]interface{}{
+				"bids_placed":    retractions.Placed,
+				"bids_cancelled": retractions.Cancelled,
+				"sellers":        retractions.Sellers,
+			},
+			DedupeKey: retractions.BidderID.String(),
+		})
+	}
+	return flags, nil
+}
+
+// velocityFlags flags new accounts bidding far faster than buyers do,
+// especially when it is all on one auction
+func (s *FraudService) velocityFlags(ctx context.Context, now time.Time) ([]domain.FraudFlag, error) {
+	found, err := s.fraudRepo.FindNewAccountVelocity(ctx, now.Add(-fraudVelocityWindow), now.Add(-fraudNewAccountAge), fraudVelocityMinBids)
+	if err != nil {
+		return nil, err
+	}
+
+	flags := make([]domain.FraudFlag, 0, len(found))
+	for _, velocity := range found {
+		severity := 40 + 2*(velocity.Bids-fraudVelocityMinBids)
+		if velocity.Auctions == 1 {
+			severity += 15
+		}
+
+		flags = append(flags, domain.FraudFlag{
+			SubjectID: &velocity.UserID,
+			Severity:  severity,
+			Evidence: map[string]interface{}{
+				"bids":               velocity.Bids,
+				"auctions":           velocity.Auctions,
+				"window_minutes":     int(fraudVelocityWindow.Minutes()),
+				"account_created_at": velocity.AccountCreatedAt,
+			},
+			DedupeKey: velocity.UserID.String(),
+		})
+	}
+	return flags, nil
+}
+
+// selfPurchaseFlags flags sellers whose items were won by accounts that
+// signed in from their device or address
+func (s *FraudService) selfPurchaseFlags(ctx context.Context, now time.Time) ([]domain.FraudFlag, error) {
+	found, err := s.fraudRepo.FindLinkedPurchases(ctx, now.Add(-fraudLookback))
+	if err != nil {
+		return nil, err
+	}
+
+	flags := make([]domain.FraudFlag, 0, len(found))
+	for _, purchase := range found {
+		severity := 0
+		if purchase.SharedDevice {
+			severity += 80
+		}
+		if purchase.SharedIP {
+			severity += 50
+		}
+
+		flags = append(flags, domain.FraudFlag{
+			SubjectID:      &purchase.SellerID,
+			RelatedUserIDs: []uuid.UUID{purchase.BuyerID},
+			AuctionID:      &purchase.AuctionID,
+			Severity:       severity,
+			Evidence: map[string]interface{}{
+				"order_id":      purchase.OrderID,
+				"buyer_id":      purchase.BuyerID,
+				"shared_device": purchase.SharedDevice,
+				"shared_ip":     purchase.SharedIP,
+			},
+			DedupeKey: purchase.OrderID.String(),
+		})
+	}
+	return flags, nil
+}
+
+func (s *FraudService) ListFlags(ctx context.Context, params *domain.FraudFlagListParams) ([]domain.FraudFlag, int, error) {
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 || params.Limit > 100 {
+		params.Limit = 20
+	}
+	return s.fraudRepo.ListFlags(ctx, params)
+}
+
+// ReviewFlag confirms or dismisses a flag. Either way, checks won't raise
+// the same finding again.
+func (s *FraudService) ReviewFlag(ctx context.Context, adminID, flagID uuid.UUID, req *domain.ReviewFraudFlagRequest) (*domain.FraudFlag, error) {
+	flag, err := s.fraudRepo.GetFlag(ctx, flagID)
+	if err != nil {
+		return nil, err
+	}
+
+	flag.Status = req.Status
+	flag.ReviewedBy = &adminID
+	flag.ReviewNote = nil
+	if req.Note != "" {
+		flag.ReviewNote = &req.Note
+	}
+
+	if err := s.fraudRepo.UpdateFlag(ctx, flag); err != nil {
+		return nil, err
+	}
+	return flag, nil
+}
@@ -0,0 +1,558 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/pkg/netguard"
+	"github.com/auction-cards/backend/internal/pkg/storage"
+	"github.com/auction-cards/backend/internal/pkg/validator"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// importJobLease keeps a job from being claimed by another worker. It
+	// is renewed after every row, so it only has to outlast one row and its
+	// image downloads.
+	importJobLease = 5 * time.Minute
+
+	// importListLimit bounds how many of a seller's imports are listed
+	importListLimit = 20
+)
+
+var importValidator = validator.New()
+
+// ImportService creates draft auctions in bulk from an uploaded CSV or JSON
+// file. The file is parsed up front so a malformed one is rejected straight
+// away; the listings are then created in the background, with a report of
+// what happened to each row.
+type ImportService struct {
+	importRepo   repository.ImportJobRepository
+	categoryRepo repository.CategoryRepository
+	auctionSvc   *AuctionService
+	images       *http.Client
+	maxRows      int
+	maxFileBytes int64
+}
+
+func NewImportService(
+	importRepo repository.ImportJobRepository,
+	categoryRepo repository.CategoryRepository,
+	auctionSvc *AuctionService,
+	imageTimeout time.Duration,
+	allowPrivateImages bool,
+	maxRows int,
+	maxFileBytes int64,
+) *ImportService {
+	// Image URLs come from the file, so unless allowed they may not reach
+	// the server's own network; checked on every dial, redirects included
+	dialer := &net.Dialer{Timeout: imageTimeout}
+	if !allowPrivateImages {
+		dialer.Control = netguard.Control
+	}
+
+	return &ImportService{
+		importRepo:   importRepo,
+		categoryRepo: categoryRepo,
+		auctionSvc:   auctionSvc,
+		images: &http.Client{
+			Timeout: imageTimeout,
+			Transport: &http.Transport{
+				Proxy:                 nil,
+				DialContext:           dialer.DialContext,
+				TLSHandshakeTimeout:   imageTimeout,
+				ResponseHeaderTimeout: imageTimeout,
+				MaxIdleConnsPerHost:   2,
+				IdleConnTimeout:       90 * time.Second,
+			},
+		},
+		maxRows:      maxRows,
+		maxFileBytes: maxFileBytes,
+	}
+}
+
+// Create parses an import file and queues its rows to become draft auctions.
+// The format is "csv" or "json"; when empty it is taken from the filename or,
+// failing that, the content. It returns an error wrapping ErrImportFile,
+// saying what is wrong, for files that can't be read.
+func (s *ImportService) Create(ctx context.Context, sellerID uuid.UUID, filename, format string, file io.Reader) (*domain.ImportJob, error) {
+	data, err := io.ReadAll(io.LimitReader(file, s.maxFileBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxFileBytes {
+		return nil, fmt.Errorf("%w: file is larger than %d MB", domain.ErrImportFile, s.maxFileBytes>>20)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	importFormat, err := detectImportFormat(format, filename, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []domain.ImportRow
+	if importFormat == domain.ImportFormatJSON {
+		rows, err = parseImportJSON(data)
+	} else {
+		rows, err = parseImportCSV(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: file has no listings", domain.ErrImportFile)
+	}
+	if s.maxRows > 0 && len(rows) > s.maxRows {
+		return nil, fmt.Errorf("%w: file has %d listings, at most %d may be imported at once", domain.ErrImportFile, len(rows), s.maxRows)
+	}
+
+	if filename == "" {
+		filename = "import." + string(importFormat)
+	}
+	if len(filename) > 255 {
+		filename = filename[:255]
+	}
+
+	job := &domain.ImportJob{
+		SellerID: sellerID,
+		Format:   importFormat,
+		Filename: filename,
+		Status:   domain.ImportJobPending,
+		Rows:     rows,
+	}
+	if err := s.importRepo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// List returns the seller's most recent imports
+func (s *ImportService) List(ctx context.Context, sellerID uuid.UUID) ([]domain.ImportJob, error) {
+	return s.importRepo.ListBySeller(ctx, sellerID, importListLimit)
+}
+
+// Get returns one of the seller's imports with its report so far
+func (s *ImportService) Get(ctx context.Context, sellerID, jobID uuid.UUID) (*domain.ImportJob, error) {
+	job, err := s.importRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.SellerID != sellerID {
+		return nil, domain.ErrNotFound
+	}
+	return job, nil
+}
+
+// ProcessPending works through waiting imports one at a time until none are
+// left. Progress is saved after every row, so a job whose worker stops is
+// resumed where it left off once its lease runs out.
+func (s *ImportService) ProcessPending(ctx context.Context) {
+	for {
+		now := time.Now()
+		job, err := s.importRepo.ClaimNext(ctx, now, now.Add(importJobLease))
+		if errors.Is(err, domain.ErrNotFound) {
+			return
+		}
+		if err != nil {
+			log.Printf("Error claiming import job: %v", err)
+			return
+		}
+
+		if err := s.process(ctx, job); err != nil {
+			log.Printf("Error processing import job %s: %v", job.ID, err)
+			return
+		}
+	}
+}
+
+func (s *ImportService) process(ctx context.Context, job *domain.ImportJob) error {
+	if job.Report == nil {
+		job.Report = make([]domain.ImportRowResult, 0, len(job.Rows))
+	}
+	categories := make(map[string]*uuid.UUID)
+
+	for job.ProcessedRows < len(job.Rows) {
+		row := &job.Rows[job.ProcessedRows]
+		result := s.importRow(ctx, job.SellerID, row, categories)
+		result.Row = job.ProcessedRows + 1
+
+		job.Report = append(job.Report, result)
+		job.ProcessedRows++
+		if result.Status == domain.ImportRowCreated {
+			job.CreatedCount++
+		} else {
+			job.FailedCount++
+		}
+
+		now := time.Now()
+		if job.ProcessedRows == len(job.Rows) {
+			job.Status = domain.ImportJobCompleted
+			job.CompletedAt = &now
+			job.LeaseUntil = nil
+		} else {
+			leaseUntil := now.Add(importJobLease)
+			job.LeaseUntil = &leaseUntil
+		}
+		if err := s.importRepo.SaveProgress(ctx, job); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importRow creates the draft auction for one row, then adds its images
+func (s *ImportService) importRow(ctx context.Context, sellerID uuid.UUID, row *domain.ImportRow, categories map[string]*uuid.UUID) domain.ImportRowResult {
+	result := domain.ImportRowResult{
+		Title:  row.Title,
+		Status: domain.ImportRowFailed,
+	}
+
+	req, problems := s.buildRequest(ctx, row, categories)
+	if fieldErrors := importValidator.Validate(req); fieldErrors != nil {
+		for _, message := range fieldErrors {
+			problems = append(problems, message)
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		result.Errors = problems
+		return result
+	}
+
+	auction, err := s.auctionSvc.Create(ctx, sellerID, req)
+	if err != nil {
+		log.Printf("Error creating imported auction: %v", err)
+		result.Errors = []string{"listing could not be created"}
+		return result
+	}
+
+	result.Status = domain.ImportRowCreated
+	result.AuctionID = &auction.ID
+	result.Warnings = s.importImages(ctx, auction.ID, sellerID, row.ImageURLs)
+
+	return result
+}
+
+// buildRequest turns a row into an auction request, returning what couldn't
+// be read. The request itself is validated as usual afterwards.
+func (s *ImportService) buildRequest(ctx context.Context, row *domain.ImportRow, categories map[string]*uuid.UUID) (*domain.CreateAuctionRequest, []string) {
+	var problems []string
+
+	req := &domain.CreateAuctionRequest{
+		Title:         strings.TrimSpace(row.Title),
+		StartingPrice: strings.TrimSpace(row.StartingPrice),
+		ReservePrice:  optionalImportValue(row.ReservePrice),
+		BuyNowPrice:   optionalImportValue(row.BuyNowPrice),
+		BidIncrement:  optionalImportValue(row.BidIncrement),
+		Description:   optionalImportValue(row.Description),
+	}
+
+	// "Like new" is accepted for like_new
+	if condition := optionalImportValue(row.Condition); condition != nil {
+		normalized := strings.ReplaceAll(strings.ToLower(*condition), " ", "_")
+		req.Condition = &normalized
+	}
+
+	if slug := strings.ToLower(strings.TrimSpace(row.Category)); slug != "" {
+		categoryID, ok := categories[slug]
+		if !ok {
+			category, err := s.categoryRepo.GetBySlug(ctx, slug)
+			switch {
+			case err == nil:
+				categoryID = &category.ID
+			case !errors.Is(err, domain.ErrNotFound):
+				log.Printf("Error looking up category %q: %v", slug, err)
+			}
+			categories[slug] = categoryID
+		}
+		if categoryID == nil {
+			problems = append(problems, fmt.Sprintf("category %q does not exist", slug))
+		}
+		req.CategoryID = categoryID
+	}
+
+	req.StartTime = time.Now()
+	if value := strings.TrimSpace(row.StartTime); value != "" {
+		startTime, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			problems = append(problems, "start_time must be an RFC 3339 time, e.g. 2026-01-02T15:04:05Z")
+		}
+		req.StartTime = startTime
+	}
+	if value := strings.TrimSpace(row.EndTime); value != "" {
+		endTime, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			problems = append(problems, "end_time must be an RFC 3339 time, e.g. 2026-01-02T15:04:05Z")
+		}
+		req.EndTime = endTime
+	}
+
+	return req, problems
+}
+
+// importImages downloads and attaches a row's images. Problems are reported
+// as warnings since the listing exists by then; the seller can add the
+// missing images by hand.
+func (s *ImportService) importImages(ctx context.Context, auctionID, sellerID uuid.UUID, urls []string) []string {
+	var warnings []string
+	if len(urls) > MaxImagesPerUpload {
+		warnings = append(warnings, fmt.Sprintf("only the first %d images were imported", MaxImagesPerUpload))
+		urls = urls[:MaxImagesPerUpload]
+	}
+
+	for _, imageURL := range urls {
+		upload, err := s.fetchImage(ctx, imageURL)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("image %s %s", imageURL, err))
+			continue
+		}
+		if _, err := s.auctionSvc.UploadImage(ctx, auctionID, sellerID, upload.Reader, upload.ContentType, upload.Size); err != nil {
+			log.Printf("Error saving imported image for auction %s: %v", auctionID, err)
+			warnings = append(warnings, fmt.Sprintf("image %s could not be saved", imageURL))
+		}
+	}
+
+	return warnings
+}
+
+// fetchImage downloads an image, going by its content rather than the
+// Content-Type the host claims. Errors are worded to follow the URL in a
+// warning.
+func (s *ImportService) fetchImage(ctx context.Context, imageURL string) (*ImageUpload, error) {
+	parsed, err := url.Parse(imageURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, errors.New("is not an http(s) URL")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, errors.New("is not an http(s) URL")
+	}
+	resp, err := s.images.Do(req)
+	if errors.Is(err, netguard.ErrPrivateAddress) {
+		return nil, errors.New("points to a private address")
+	}
+	if err != nil {
+		return nil, errors.New("could not be downloaded")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not be downloaded, the host responded %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, storage.MaxImageSize+1))
+	if err != nil {
+		return nil, errors.New("could not be downloaded")
+	}
+	if len(data) > storage.MaxImageSize {
+		return nil, fmt.Errorf("is larger than %d MB", storage.MaxImageSize>>20)
+	}
+
+	contentType := http.DetectContentType(data)
+	if !storage.ValidateImageContentType(contentType) {
+		return nil, errors.New("is not a JPEG, PNG, GIF or WebP image")
+	}
+
+	return &ImageUpload{
+		Reader:      bytes.NewReader(data),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+	}, nil
+}
+
+func optionalImportValue(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// detectImportFormat settles the format of an import file: as requested,
+// else by the filename's extension, else by whether the content looks like
+// a JSON array
+func detectImportFormat(format, filename string, data []byte) (domain.ImportFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "csv":
+		return domain.ImportFormatCSV, nil
+	case "json":
+		return domain.ImportFormatJSON, nil
+	case "":
+	default:
+		return "", fmt.Errorf("%w: format must be csv or json", domain.ErrImportFile)
+	}
+
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		return domain.ImportFormatCSV, nil
+	case ".json":
+		return domain.ImportFormatJSON, nil
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return domain.ImportFormatJSON, nil
+	}
+	return domain.ImportFormatCSV, nil
+}
+
+// importFieldAliases maps alternative column names to the field they fill
+var importFieldAliases = map[string]string{
+	"category_slug": "category",
+	"images":        "image_urls",
+	"image_url":     "image_urls",
+}
+
+// importField normalizes a column or key name, so "Starting Price" and
+// "starting-price" both name starting_price
+func importField(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+	if alias, ok := importFieldAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// setImportField stores value in the named field of row, reporting whether
+// the field exists. Image URLs are separated by whitespace.
+func setImportField(row *domain.ImportRow, field, value string) bool {
+	switch field {
+	case "title":
+		row.Title = value
+	case "description":
+		row.Description = value
+	case "category":
+		row.Category = value
+	case "condition":
+		row.Condition = value
+	case "starting_price":
+		row.StartingPrice = value
+	case "reserve_price":
+		row.ReservePrice = value
+	case "buy_now_price":
+		row.BuyNowPrice = value
+	case "bid_increment":
+		row.BidIncrement = value
+	case "start_time":
+		row.StartTime = value
+	case "end_time":
+		row.EndTime = value
+	case "image_urls":
+		row.ImageURLs = strings.Fields(value)
+	default:
+		return false
+	}
+	return true
+}
+
+// parseImportCSV reads a CSV file whose first line names the columns
+func parseImportCSV(data []byte) ([]domain.ImportRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: file is empty", domain.ErrImportFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrImportFile, err)
+	}
+
+	fields := make([]string, len(header))
+	var unknown []string
+	for i, name := range header {
+		fields[i] = importField(name)
+		if !setImportField(&domain.ImportRow{}, fields[i], "") {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: unknown columns %s", domain.ErrImportFile, strings.Join(unknown, ", "))
+	}
+
+	rows := make([]domain.ImportRow, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrImportFile, err)
+		}
+
+		var row domain.ImportRow
+		for i, value := range record {
+			setImportField(&row, fields[i], value)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// parseImportJSON reads a JSON array of objects keyed like the CSV columns.
+// Prices may be numbers and image_urls an array.
+func parseImportJSON(data []byte) ([]domain.ImportRow, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var objects []map[string]interface{}
+	if err := decoder.Decode(&objects); err != nil {
+		return nil, fmt.Errorf("%w: expected a JSON array of listings: %v", domain.ErrImportFile, err)
+	}
+
+	rows := make([]domain.ImportRow, 0, len(objects))
+	for i, object := range objects {
+		var row domain.ImportRow
+		for key, raw := range object {
+			field := importField(key)
+
+			var value string
+			switch v := raw.(type) {
+			case nil:
+			case string:
+				value = v
+			case json.Number:
+				value = v.String()
+			case []interface{}:
+				if field != "image_urls" {
+					return nil, fmt.Errorf("%w: listing %d: %s must not be a list", domain.ErrImportFile, i+1, key)
+				}
+				for _, item := range v {
+					imageURL, ok := item.(string)
+					if !ok {
+						return nil, fmt.Errorf("%w: listing %d: image_urls must be strings", domain.ErrImportFile, i+1)
+					}
+					value += imageURL + " "
+				}
+			default:
+				return nil, fmt.Errorf("%w: listing %d: %s must be a string or number", domain.ErrImportFile, i+1, key)
+			}
+
+			if !setImportField(&row, field, value) {
+				return nil, fmt.Errorf("%w: listing %d: unknown field %s", domain.ErrImportFile, i+1, key)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/auction-cards/backend/internal/cache"
+	"github.com/google/uuid"
+)
+
+// LeaderElector keeps one instance in charge of a job by holding a Redis
+// lease, renewing it well before it lapses so a crashed leader is replaced
+// within one TTL.
+//
+// Without Redis every instance leads. That is the right call for single
+// instance deployments, and jobs stay safe to run concurrently because they
+// are idempotent; they only lose the de-duplication.
+type LeaderElector struct {
+	cache    *cache.RedisCache
+	key      string
+	id       string
+	ttl      time.Duration
+	leader   atomic.Bool
+	stopChan chan struct{}
+	done     chan struct{}
+}
+
+func NewLeaderElector(cache *cache.RedisCache, key string, ttl time.Duration) *LeaderElector {
+	if ttl < time.Second {
+		ttl = 15 * time.Second
+	}
+	host, _ := os.Hostname()
+
+	return &LeaderElector{
+		cache:    cache,
+		key:      key,
+		id:       host + "-" + uuid.NewString(),
+		ttl:      ttl,
+		stopChan: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start campaigns for the lease until Stop is called
+func (e *LeaderElector) Start() {
+	if e.cache == nil {
+		e.leader.Store(true)
+		close(e.done)
+		return
+	}
+
+	e.campaign()
+	go func() {
+		defer close(e.done)
+
+		ticker := time.NewTicker(e.ttl / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-e.stopChan:
+				return
+			case <-ticker.C:
+				e.campaign()
+			}
+		}
+	}()
+}
+
+// Stop ends the campaign and hands the lease back so another instance can
+// take over without waiting for it to lapse
+func (e *LeaderElector) Stop() {
+	close(e.stopChan)
+	<-e.done
+
+	if e.cache != nil && e.leader.Swap(false) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := e.cache.ReleaseLease(ctx, e.key, e.id); err != nil {
+			log.Printf("Error releasing %s lease: %v", e.key, err)
+		}
+	}
+}
+
+// IsLeader reports whether this instance should run the job
+func (e *LeaderElector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+func (e *LeaderElector) campaign() {
+	ctx, cancel := context.WithTimeout(context.Background(), e.ttl/3)
+	defer cancel()
+
+	held, err := e.cache.AcquireLease(ctx, e.key, e.id, e.ttl)
+	if err != nil {
+		// Redis is down, so fall back to leading like a lone instance
+		held = true
+		if !e.leader.Load() {
+			log.Printf("Error campaigning for %s lease, running without leader election: %v", e.key, err)
+		}
+	}
+
+	if was := e.leader.Swap(held); was != held {
+		if held {
+			log.Printf("Acquired %s lease as %s", e.key, e.id)
+		} else {
+			log.Printf("Lost %s lease", e.key)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Maximum number of escrow holds released per scheduler tick
+const escrowReleaseBatchSize = 100
+
+// LedgerService holds order payments in escrow and moves money between
+// ledger accounts. Every movement is a double-entry transaction.
+type LedgerService struct {
+	escrowRepo      repository.EscrowRepository
+	ledgerRepo      repository.LedgerRepository
+	orderRepo       repository.OrderRepository
+	auditRepo       repository.AuditLogRepository
+	txManager       repository.TxManager
+	notificationSvc *NotificationService
+	holdPeriod      time.Duration
+}
+
+func NewLedgerService(
+	escrowRepo repository.EscrowRepository,
+	ledgerRepo repository.LedgerRepository,
+	orderRepo repository.OrderRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager repository.TxManager,
+	notificationSvc *NotificationService,
+	holdPeriod time.Duration,
+) *LedgerService {
+	return &LedgerService{
+		escrowRepo:      escrowRepo,
+		ledgerRepo:      ledgerRepo,
+		orderRepo:       orderRepo,
+		auditRepo:       auditRepo,
+		txManager:       txManager,
+		notificationSvc: notificationSvc,
+		holdPeriod:      holdPeriod,
+	}
+}
+
+// ledgerLeg is one side of a transfer
+type ledgerLeg struct {
+	account domain.LedgerAccount
+	userID  *uuid.UUID
+}
+
+// ledgerRef is what a transfer is for: an order or a payout
+type ledgerRef struct {
+	orderID  *uuid.UUID
+	payoutID *uuid.UUID
+}
+
+// transfer moves amount from one account to another as a single
+// transaction, keeping the seller balance in step
+func (s *LedgerService) transfer(ctx context.Context, from, to ledgerLeg, amount decimal.Decimal, ref ledgerRef, memo string) error {
+	txID := uuid.New()
+	entries := []domain.LedgerEntry{
+		{TransactionID: txID, Account: from.account, UserID: from.userID, OrderID: ref.orderID, PayoutID: ref.payoutID, Amount: amount.Neg(), Memo: memo},
+		{TransactionID: txID, Account: to.account, UserID: to.userID, OrderID: ref.orderID, PayoutID: ref.payoutID, Amount: amount, Memo: memo},
+	}
+
+	if err := s.ledgerRepo.CreateEntries(ctx, entries); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.Account == domain.LedgerAccountSellerBalance {
+			if err := s.ledgerRepo.AdjustBalance(ctx, *entry.UserID, entry.Amount); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// HoldPayment puts a paid order's amount in escrow. It runs inside the
+// transaction that marks the order paid.
+func (s *LedgerService) HoldPayment(ctx context.Context, order *domain.Order) (*domain.EscrowHold, error) {
+	hold := &domain.EscrowHold{
+		OrderID:   order.ID,
+		BuyerID:   order.BuyerID,
+		SellerID:  order.SellerID,
+		Amount:    order.Amount,
+		Status:    domain.EscrowHeld,
+		ReleaseAt: time.Now().Add(s.holdPeriod),
+	}
+
+	if err := s.escrowRepo.Create(ctx, hold); err != nil {
+		return nil, err
+	}
+
+	err := s.transfer(ctx,
+		ledgerLeg{account: domain.LedgerAccountBuyerPayments, userID: &order.BuyerID},
+		ledgerLeg{account: domain.LedgerAccountEscrow},
+		order.Amount, ledgerRef{orderID: &order.ID}, "payment held")
+	if err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// ConfirmDelivery lets the buyer confirm the item arrived, releasing the
+// payment to the seller
+func (s *LedgerService) ConfirmDelivery(ctx context.Context, orderID, buyerID uuid.UUID) (*domain.EscrowHold, error) {
+	hold, err := s.escrowRepo.GetByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if hold.BuyerID != buyerID {
+		return nil, domain.ErrForbidden
+	}
+
+	if hold.Status != domain.EscrowHeld {
+		return nil, domain.ErrEscrowNotHeld
+	}
+
+	if hold.Disputed {
+		return nil, domain.ErrDisputeExists
+	}
+
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.escrowRepo.ConfirmDelivery(txCtx, orderID); err != nil {
+			return err
+		}
+		return s.release(txCtx, hold, "delivery confirmed")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyReleased(ctx, hold)
+
+	return s.escrowRepo.GetByOrder(ctx, orderID)
+}
+
+// ReleaseByAdmin releases a held payment ahead of its release time
+func (s *LedgerService) ReleaseByAdmin(ctx context.Context, adminID, orderID uuid.UUID, req *domain.ReleaseEscrowRequest) (*domain.EscrowHold, error) {
+	hold, err := s.escrowRepo.GetByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if hold.Status != domain.EscrowHeld {
+		return nil, domain.ErrEscrowNotHeld
+	}
+
+	if hold.Disputed {
+		return nil, domain.ErrDisputeExists
+	}
+
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.release(txCtx, hold, "released by admin"); err != nil {
+			return err
+		}
+
+		return s.auditRepo.Create(txCtx, &domain.AuditLog{
+			ActorID:    &adminID,
+			Action:     domain.AuditActionEscrowRelease,
+			EntityType: domain.AuditEntityOrder,
+			EntityID:   &orderID,
+			Reason:     &req.Reason,
+			Metadata: map[string]interface{}{
+				"seller_id":  hold.SellerID,
+				"amount":     hold.Remaining().StringFixed(2),
+				"release_at": hold.ReleaseAt,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyReleased(ctx, hold)
+
+	return s.escrowRepo.GetByOrder(ctx, orderID)
+}
+
+// ProcessDueReleases releases holds whose hold period has run out without
+// a dispute
+func (s *LedgerService) ProcessDueReleases(ctx context.Context) {
+	holds, err := s.escrowRepo.GetDue(ctx, time.Now(), escrowReleaseBatchSize)
+	if err != nil {
+		log.Printf("Error getting due escrow holds: %v", err)
+		return
+	}
+
+	for i := range holds {
+		hold := &holds[i]
+		err := s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+			return s.release(txCtx, hold, "hold period ended")
+		})
+		if err != nil {
+			log.Printf("Error releasing escrow for order %s: %v", hold.OrderID, err)
+			continue
+		}
+
+		s.notifyReleased(ctx, hold)
+	}
+}
+
+func (s *LedgerService) release(ctx context.Context, hold *domain.EscrowHold, memo string) error {
+	if err := s.escrowRepo.Release(ctx, hold.OrderID); err != nil {
+		return err
+	}
+
+	return s.transfer(ctx,
+		ledgerLeg{account: domain.LedgerAccountEscrow},
+		ledgerLeg{account: domain.LedgerAccountSellerBalance, userID: &hold.SellerID},
+		hold.Remaining(), ledgerRef{orderID: &hold.OrderID}, memo)
+}
+
+func (s *LedgerService) notifyReleased(ctx context.Context, hold *domain.EscrowHold) {
+	if s.notificationSvc == nil {
+		return
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, hold.OrderID)
+	if err != nil {
+		return
+	}
+
+	go s.notificationSvc.NotifyEscrowReleased(context.Background(), order, hold.Remaining())
+}
+
+// Refund returns part of an order's payment to the buyer. It comes out of
+// escrow while the payment is held, and out of the seller's balance once
+// released. Orders paid without escrow have nothing to refund here.
+func (s *LedgerService) Refund(ctx context.Context, order *domain.Order, amount decimal.Decimal) error {
+	hold, err := s.escrowRepo.GetByOrder(ctx, order.ID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if hold.Status == domain.EscrowRefunded {
+		return domain.ErrInvalidRefund
+	}
+
+	from := ledgerLeg{account: domain.LedgerAccountEscrow}
+	if hold.Status == domain.EscrowHeld {
+		if err := s.escrowRepo.AddRefund(ctx, order.ID, amount); err != nil {
+			return err
+		}
+	} else {
+		from = ledgerLeg{account: domain.LedgerAccountSellerBalance, userID: &hold.SellerID}
+	}
+
+	return s.transfer(ctx, from,
+		ledgerLeg{account: domain.LedgerAccountBuyerRefunds, userID: &hold.BuyerID},
+		amount, ledgerRef{orderID: &order.ID}, "refund")
+}
+
+// ReservePayout moves a requested payout out of the seller's available
+// balance. It runs inside the transaction creating the payout.
+func (s *LedgerService) ReservePayout(ctx context.Context, payout *domain.Payout) error {
+	available, err := s.ledgerRepo.LockBalance(ctx, payout.UserID)
+	if err != nil {
+		return err
+	}
+
+	if available.LessThan(payout.Amount) {
+		return domain.ErrInsufficientBalance
+	}
+
+	return s.MovePayout(ctx, payout, domain.LedgerAccountSellerBalance, "payout requested")
+}
+
+// MovePayout moves a payout's money from the given account to the one for
+// its current status
+func (s *LedgerService) MovePayout(ctx context.Context, payout *domain.Payout, from domain.LedgerAccount, memo string) error {
+	return s.transfer(ctx,
+		ledgerLeg{account: from, userID: &payout.UserID},
+		ledgerLeg{account: payout.LedgerAccount(), userID: &payout.UserID},
+		payout.Amount, ledgerRef{payoutID: &payout.ID}, memo)
+}
+
+// ListPendingReleases returns held payments for admin review, soonest
+// release first
+func (s *LedgerService) ListPendingReleases(ctx context.Context, page, limit int) ([]domain.EscrowHold, int, error) {
+	return s.escrowRepo.ListHeld(ctx, page, limit)
+}
+
+func (s *LedgerService) GetBalance(ctx context.Context, userID uuid.UUID) (*domain.Balance, error) {
+	return s.ledgerRepo.GetBalance(ctx, userID)
+}
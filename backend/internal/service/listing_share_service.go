@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	shareCodeLength   = 8
+	shareCodeAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// Attempts at an unused code before giving up
+	shareCodeAttempts = 5
+)
+
+// ListingShareService hands out short links for sharing listings and tracks
+// who comes through them, for the seller's listing insights
+type ListingShareService struct {
+	shareRepo  repository.ListingShareRepository
+	auctionSvc *AuctionService
+	baseURL    string
+}
+
+func NewListingShareService(
+	shareRepo repository.ListingShareRepository,
+	auctionSvc *AuctionService,
+	baseURL string,
+) *ListingShareService {
+	return &ListingShareService{
+		shareRepo:  shareRepo,
+		auctionSvc: auctionSvc,
+		baseURL:    baseURL,
+	}
+}
+
+// Create returns the user's share link for the auction, making one the
+// first time they share it. Only auctions the user can see may be shared.
+func (s *ListingShareService) Create(ctx context.Context, auctionID, sharerID uuid.UUID) (*domain.ListingShare, error) {
+	if _, err := s.auctionSvc.GetByID(ctx, auctionID, sharerID, ""); err != nil {
+		return nil, err
+	}
+
+	share := &domain.ListingShare{AuctionID: auctionID, SharerID: sharerID}
+	for attempt := 0; ; attempt++ {
+		code, err := generateShareCode()
+		if err != nil {
+			return nil, err
+		}
+		share.Code = code
+
+		err = s.shareRepo.GetOrCreate(ctx, share)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, domain.ErrConflict) || attempt+1 >= shareCodeAttempts {
+			return nil, err
+		}
+	}
+
+	share.URL = s.shareURL(share.Code)
+	return share, nil
+}
+
+// Resolve looks up where a share link leads, counting the visit unless it's
+// the sharer following their own link. Pass uuid.Nil for anonymous viewers.
+func (s *ListingShareService) Resolve(ctx context.Context, code string, viewerID uuid.UUID) (*domain.DeepLink, error) {
+	share, err := s.shareRepo.GetByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if viewerID != share.SharerID {
+		var userID *uuid.UUID
+		if viewerID != uuid.Nil {
+			userID = &viewerID
+		}
+		if err := s.shareRepo.RecordClick(ctx, share.ID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &domain.DeepLink{
+		Type:      domain.DeepLinkAuction,
+		AuctionID: share.AuctionID,
+		URL:       fmt.Sprintf("%s/auctions/%s", s.baseURL, share.AuctionID),
+	}, nil
+}
+
+func (s *ListingShareService) shareURL(code string) string {
+	return fmt.Sprintf("%s/s/%s", s.baseURL, code)
+}
+
+// generateShareCode returns a random base62 code for a share link
+func generateShareCode() (string, error) {
+	code := make([]byte, shareCodeLength)
+	size := big.NewInt(int64(len(shareCodeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, size)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate share code: %w", err)
+		}
+		code[i] = shareCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
@@ -0,0 +1,492 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/auction-cards/backend/internal/cache"
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/auction-cards/backend/internal/websocket"
+	"github.com/google/uuid"
+)
+
+// liveLotTimeout is how long a lot stays open without a hammer. Past it the
+// scheduler ends the lot like any other auction, so a lot isn't left open
+// if the auctioneer drops out.
+const liveLotTimeout = 30 * time.Minute
+
+// Errors an auctioneer's command can fail with that are safe to send back
+var liveCommandErrors = []error{
+	domain.ErrBadRequest,
+	domain.ErrForbidden,
+	domain.ErrNotFound,
+	domain.ErrAuctionNotDraft,
+	domain.ErrLiveEventEnded,
+	domain.ErrLiveLotOpen,
+	domain.ErrLiveLotNotOpen,
+	domain.ErrLiveLotNotPending,
+}
+
+var errLiveCommandFailed = errors.New("command failed, try again")
+
+// LiveEventService runs live events, where an auctioneer calls the event's
+// lots one after another. Each lot is a draft auction that opens for bids
+// when the auctioneer opens it and closes at the hammer, after which the
+// next lot opens. Bids go through the usual bidding path and are relayed
+// to the event's room along with the lot changes.
+type LiveEventService struct {
+	liveEventRepo   repository.LiveEventRepository
+	auctionRepo     repository.AuctionRepository
+	bidRepo         repository.BidRepository
+	orderSvc        *OrderService
+	notificationSvc *NotificationService
+	txManager       repository.TxManager
+	cache           *cache.RedisCache
+
+	// Serializes the auctioneers' commands
+	mu sync.Mutex
+}
+
+func NewLiveEventService(
+	liveEventRepo repository.LiveEventRepository,
+	auctionRepo repository.AuctionRepository,
+	bidRepo repository.BidRepository,
+	orderSvc *OrderService,
+	notificationSvc *NotificationService,
+	txManager repository.TxManager,
+	cache *cache.RedisCache,
+	hub *websocket.Hub,
+) *LiveEventService {
+	s := &LiveEventService{
+		liveEventRepo:   liveEventRepo,
+		auctionRepo:     auctionRepo,
+		bidRepo:         bidRepo,
+		orderSvc:        orderSvc,
+		notificationSvc: notificationSvc,
+		txManager:       txManager,
+		cache:           cache,
+	}
+
+	if hub != nil {
+		hub.SetLiveCommandHandler(s)
+	}
+
+	return s
+}
+
+// Create schedules an event running the auctioneer's draft auctions as its
+// lots, in the order given
+func (s *LiveEventService) Create(ctx context.Context, auctioneerID uuid.UUID, req *domain.CreateLiveEventRequest) (*domain.LiveEvent, error) {
+	if !req.StartsAt.After(time.Now()) {
+		return nil, domain.ErrValidation
+	}
+
+	for i, auctionID := range req.AuctionIDs {
+		if slices.Contains(req.AuctionIDs[:i], auctionID) {
+			return nil, domain.ErrLiveEventLots
+		}
+
+		auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrLiveEventLots
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if auction.SellerID != auctioneerID || auction.Status != domain.AuctionStatusDraft || auction.LiveEventID != nil {
+			return nil, domain.ErrLiveEventLots
+		}
+	}
+
+	event := &domain.LiveEvent{
+		AuctioneerID: auctioneerID,
+		Title:        req.Title,
+		Description:  req.Description,
+		StartsAt:     req.StartsAt,
+		Status:       domain.LiveEventScheduled,
+	}
+
+	err := s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.liveEventRepo.Create(txCtx, event); err != nil {
+			return err
+		}
+		return s.liveEventRepo.AddLots(txCtx, event.ID, req.AuctionIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, auctionID := range req.AuctionIDs {
+		invalidateAuction(ctx, s.cache, auctionID)
+	}
+
+	return s.Get(ctx, event.ID)
+}
+
+// Get returns the event with its lots
+func (s *LiveEventService) Get(ctx context.Context, id uuid.UUID) (*domain.LiveEvent, error) {
+	event, err := s.liveEventRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	event.Lots, err = s.liveEventRepo.ListLots(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// List returns the events that are scheduled or live
+func (s *LiveEventService) List(ctx context.Context) ([]domain.LiveEvent, error) {
+	return s.liveEventRepo.ListUpcoming(ctx)
+}
+
+// OnLiveCommand runs a command the auctioneer sent over the event's socket
+func (s *LiveEventService) OnLiveCommand(eventID, userID uuid.UUID, cmd *domain.LiveCommand) error {
+	ctx := context.Background()
+
+	var err error
+	switch cmd.Type {
+	case domain.LiveCommandOpenLot:
+		_, err = s.OpenLot(ctx, eventID, userID, cmd.AuctionID)
+	case domain.LiveCommandFairWarning:
+		_, err = s.FairWarning(ctx, eventID, userID)
+	case domain.LiveCommandHammer:
+		_, err = s.Hammer(ctx, eventID, userID)
+	default:
+		return domain.ErrBadRequest
+	}
+
+	if err != nil && !slices.ContainsFunc(liveCommandErrors, func(target error) bool { return errors.Is(err, target) }) {
+		log.Printf("Error running live event %s command %s: %v", eventID, cmd.Type, err)
+		return errLiveCommandFailed
+	}
+	return err
+}
+
+// OpenLot opens a lot for bids, by default the next one in running order.
+// Opening the first lot starts the event.
+func (s *LiveEventService) OpenLot(ctx context.Context, eventID, auctioneerID uuid.UUID, auctionID *uuid.UUID) (*domain.LiveLot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, lots, err := s.getRunning(ctx, eventID, auctioneerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if current := currentLot(event, lots); current != nil && current.State.IsActive() {
+		return nil, domain.ErrLiveLotOpen
+	}
+
+	var lot *domain.LiveLot
+	if auctionID != nil {
+		i := slices.IndexFunc(lots, func(l domain.LiveLot) bool { return l.AuctionID == *auctionID })
+		if i < 0 {
+			return nil, domain.ErrNotFound
+		}
+		lot = &lots[i]
+	} else {
+		lot = nextPendingLot(lots)
+	}
+	if lot == nil || lot.State != domain.LiveLotPending {
+		return nil, domain.ErrLiveLotNotPending
+	}
+
+	if err := s.openLot(ctx, event, lot); err != nil {
+		return nil, err
+	}
+
+	return lot, nil
+}
+
+// FairWarning warns bidders the open lot is about to be hammered. It can
+// be given again after a late bid.
+func (s *LiveEventService) FairWarning(ctx context.Context, eventID, auctioneerID uuid.UUID) (*domain.LiveLot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, lots, err := s.getRunning(ctx, eventID, auctioneerID)
+	if err != nil {
+		return nil, err
+	}
+
+	lot := currentLot(event, lots)
+	if lot == nil || !lot.State.IsActive() {
+		return nil, domain.ErrLiveLotNotOpen
+	}
+
+	lot.State = domain.LiveLotFairWarning
+	if err := s.liveEventRepo.UpdateLot(ctx, lot); err != nil {
+		return nil, err
+	}
+
+	s.publishLot(ctx, domain.WSMessageFairWarning, lot, nil)
+
+	return lot, nil
+}
+
+// Hammer closes the open lot, sold to the high bidder if the reserve is
+// met, then opens the next lot or ends the event after the last.
+func (s *LiveEventService) Hammer(ctx context.Context, eventID, auctioneerID uuid.UUID) (*domain.LiveLot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, lots, err := s.getRunning(ctx, eventID, auctioneerID)
+	if err != nil {
+		return nil, err
+	}
+
+	lot := currentLot(event, lots)
+	if lot == nil || !lot.State.IsActive() {
+		return nil, domain.ErrLiveLotNotOpen
+	}
+
+	if err := s.hammer(ctx, lot); err != nil {
+		return nil, err
+	}
+
+	if next := nextPendingLot(lots); next != nil {
+		if err := s.openLot(ctx, event, next); err != nil {
+			return nil, err
+		}
+	} else if err := s.end(ctx, event); err != nil {
+		return nil, err
+	}
+
+	return lot, nil
+}
+
+// getRunning returns the auctioneer's event that hasn't ended, with its lots
+func (s *LiveEventService) getRunning(ctx context.Context, eventID, auctioneerID uuid.UUID) (*domain.LiveEvent, []domain.LiveLot, error) {
+	event, err := s.liveEventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if event.AuctioneerID != auctioneerID {
+		return nil, nil, domain.ErrForbidden
+	}
+
+	if event.Status == domain.LiveEventEnded {
+		return nil, nil, domain.ErrLiveEventEnded
+	}
+
+	lots, err := s.liveEventRepo.ListLots(ctx, eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return event, lots, nil
+}
+
+func (s *LiveEventService) openLot(ctx context.Context, event *domain.LiveEvent, lot *domain.LiveLot) error {
+	auction, err := s.auctionRepo.GetByID(ctx, lot.AuctionID)
+	if err != nil {
+		return err
+	}
+
+	if auction.Status != domain.AuctionStatusDraft {
+		return domain.ErrAuctionNotDraft
+	}
+
+	now := time.Now()
+	auction.Status = domain.AuctionStatusActive
+	auction.StartTime = now
+	auction.EndTime = now.Add(liveLotTimeout)
+
+	lot.State = domain.LiveLotOpen
+	lot.OpenedAt = &now
+
+	if event.Status == domain.LiveEventScheduled {
+		event.Status = domain.LiveEventLive
+		event.StartedAt = &now
+	}
+	event.CurrentLotID = &lot.AuctionID
+
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.auctionRepo.Update(txCtx, auction); err != nil {
+			return err
+		}
+		if err := s.liveEventRepo.UpdateLot(txCtx, lot); err != nil {
+			return err
+		}
+		return s.liveEventRepo.Update(txCtx, event)
+	})
+	if err != nil {
+		return err
+	}
+
+	scheduleAuctionEnd(ctx, s.cache, auction)
+	invalidateAuction(ctx, s.cache, auction.ID)
+
+	lot.CurrentPrice = auction.CurrentPrice
+	s.publishLot(ctx, domain.WSMessageLotOpened, lot, nil)
+
+	return nil
+}
+
+func (s *LiveEventService) hammer(ctx context.Context, lot *domain.LiveLot) error {
+	var auction *domain.Auction
+	var status domain.AuctionStatus
+	var winnerID, winningBidID *uuid.UUID
+	var closed bool
+
+	// A bid landing while the lot is hammered means reading it again
+	for attempt := 1; ; attempt++ {
+		var err error
+		auction, err = s.auctionRepo.GetByID(ctx, lot.AuctionID)
+		if err != nil {
+			return err
+		}
+
+		highestBid, err := s.bidRepo.GetHighestBid(ctx, auction.ID)
+		if err != nil {
+			return err
+		}
+
+		status = domain.AuctionStatusUnsold
+		winnerID, winningBidID = nil, nil
+		if highestBid != nil && (auction.ReservePrice == nil || !highestBid.Amount.LessThan(*auction.ReservePrice)) {
+			status = domain.AuctionStatusCompleted
+			winnerID = &highestBid.BidderID
+			winningBidID = &highestBid.ID
+		}
+
+		closed, err = s.auctionRepo.CloseIfActive(ctx, auction.ID, auction.Version, status, winnerID, winningBidID)
+		if errors.Is(err, domain.ErrConcurrentBid) && attempt < closeAuctionAttempts {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		break
+	}
+	if !closed {
+		// The lot timed out and the scheduler already ended it
+		status = auction.Status
+		winnerID = auction.WinnerID
+	}
+
+	now := time.Now()
+	lot.State = domain.LiveLotPassed
+	if status == domain.AuctionStatusCompleted {
+		lot.State = domain.LiveLotSold
+	}
+	lot.ClosedAt = &now
+	lot.CurrentPrice = auction.CurrentPrice
+	if err := s.liveEventRepo.UpdateLot(ctx, lot); err != nil {
+		return err
+	}
+
+	if closed {
+		s.finishAuction(ctx, auction, status, winnerID, winningBidID)
+	}
+
+	s.publishLot(ctx, domain.WSMessageLotHammered, lot, winnerID)
+
+	return nil
+}
+
+// finishAuction does what ending an auction does once the lot's auction
+// is closed: the winner's order, the auction room and notifications
+func (s *LiveEventService) finishAuction(ctx context.Context, auction *domain.Auction, status domain.AuctionStatus, winnerID, winningBidID *uuid.UUID) {
+	invalidateAuction(ctx, s.cache, auction.ID)
+
+	if status == domain.AuctionStatusCompleted && winnerID != nil && s.orderSvc != nil {
+		if _, err := s.orderSvc.CreateForWinner(ctx, auction, *winnerID, winningBidID); err != nil {
+			log.Printf("Error creating order for auction %s: %v", auction.ID, err)
+		}
+	}
+
+	if s.cache != nil {
+		message := domain.WSMessage{
+			Type: domain.WSMessageAuctionEnded,
+			Payload: domain.WSAuctionEndedPayload{
+				AuctionID:  auction.ID,
+				WinnerID:   winnerID,
+				FinalPrice: auction.CurrentPrice,
+				Status:     status,
+			},
+		}
+		_ = s.cache.Publish(ctx, cache.AuctionChannel(auction.ID), message)
+	}
+
+	if s.notificationSvc != nil && status == domain.AuctionStatusCompleted && winnerID != nil {
+		go func(auction domain.Auction) {
+			ctx := context.Background()
+			s.notificationSvc.NotifyAuctionWon(ctx, *winnerID, &auction)
+			s.notificationSvc.NotifyAuctionSold(ctx, auction.SellerID, &auction, *winnerID)
+		}(*auction)
+	}
+}
+
+func (s *LiveEventService) end(ctx context.Context, event *domain.LiveEvent) error {
+	now := time.Now()
+	event.Status = domain.LiveEventEnded
+	event.EndedAt = &now
+	event.CurrentLotID = nil
+	if err := s.liveEventRepo.Update(ctx, event); err != nil {
+		return err
+	}
+
+	if s.cache != nil {
+		message := domain.WSMessage{
+			Type:    domain.WSMessageLiveEventEnded,
+			Payload: domain.WSLiveEventEndedPayload{EventID: event.ID},
+		}
+		_ = s.cache.Publish(ctx, cache.LiveEventChannel(event.ID), message)
+	}
+
+	return nil
+}
+
+func (s *LiveEventService) publishLot(ctx context.Context, msgType domain.WSMessageType, lot *domain.LiveLot, winnerID *uuid.UUID) {
+	if s.cache == nil {
+		return
+	}
+
+	message := domain.WSMessage{
+		Type: msgType,
+		Payload: domain.WSLiveLotPayload{
+			EventID:      lot.EventID,
+			AuctionID:    lot.AuctionID,
+			Position:     lot.Position,
+			State:        lot.State,
+			CurrentPrice: lot.CurrentPrice,
+			WinnerID:     winnerID,
+		},
+	}
+	_ = s.cache.Publish(ctx, cache.LiveEventChannel(lot.EventID), message)
+}
+
+// currentLot returns the lot the event is on, if any
+func currentLot(event *domain.LiveEvent, lots []domain.LiveLot) *domain.LiveLot {
+	if event.CurrentLotID == nil {
+		return nil
+	}
+	for i := range lots {
+		if lots[i].AuctionID == *event.CurrentLotID {
+			return &lots[i]
+		}
+	}
+	return nil
+}
+
+// nextPendingLot returns the first lot in running order not yet opened
+func nextPendingLot(lots []domain.LiveLot) *domain.LiveLot {
+	for i := range lots {
+		if lots[i].State == domain.LiveLotPending {
+			return &lots[i]
+		}
+	}
+	return nil
+}
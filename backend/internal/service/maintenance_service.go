@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/auction-cards/backend/internal/cache"
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// How long an instance trusts its copy of the maintenance status, so
+	// switching takes effect everywhere within a few seconds without a Redis
+	// read per request
+	maintenanceCheckInterval = 2 * time.Second
+	// Auctions that came due during maintenance, or end this soon after it,
+	// are given this long to take bids once writes are back
+	maintenanceResumeGrace = 15 * time.Minute
+)
+
+// MaintenanceService switches the API in and out of read-only maintenance
+// mode. The status is kept in Redis so every instance follows it; without
+// Redis it only applies to this instance. It also keeps the planned
+// maintenance windows, during which auctions don't end.
+type MaintenanceService struct {
+	auctionRepo repository.AuctionRepository
+	windowRepo  repository.MaintenanceWindowRepository
+	txManager   repository.TxManager
+	cache       *cache.RedisCache
+
+	mu        sync.Mutex
+	status    domain.MaintenanceStatus
+	checkedAt time.Time
+
+	windowsMu        sync.Mutex
+	windows          []domain.MaintenanceWindow
+	windowsCheckedAt time.Time
+}
+
+func NewMaintenanceService(
+	auctionRepo repository.AuctionRepository,
+	windowRepo repository.MaintenanceWindowRepository,
+	txManager repository.TxManager,
+	cache *cache.RedisCache,
+) *MaintenanceService {
+	return &MaintenanceService{
+		auctionRepo: auctionRepo,
+		windowRepo:  windowRepo,
+		txManager:   txManager,
+		cache:       cache,
+	}
+}
+
+// Status returns the current maintenance status. If Redis can't be read the
+// last known status is kept.
+func (s *MaintenanceService) Status(ctx context.Context) *domain.MaintenanceStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cache != nil && time.Since(s.checkedAt) >= maintenanceCheckInterval {
+		var status domain.MaintenanceStatus
+		if err := s.cache.GetJSON(ctx, cache.MaintenanceKey, &status); err != nil {
+			log.Printf("Error reading maintenance status: %v", err)
+		} else {
+			s.status = status
+		}
+		s.checkedAt = time.Now()
+	}
+
+	status := s.status
+	return &status
+}
+
+// Set switches maintenance mode on or off. Switching it off first gives
+// auctions that came due meanwhile time to take bids, so none end without
+// bidders having had the chance.
+func (s *MaintenanceService) Set(ctx context.Context, adminID uuid.UUID, req *domain.SetMaintenanceRequest) (*domain.MaintenanceStatus, error) {
+	s.mu.Lock()
+	s.checkedAt = time.Time{}
+	s.mu.Unlock()
+	current := s.Status(ctx)
+
+	now := time.Now()
+	var status domain.MaintenanceStatus
+	if req.Enabled {
+		status = domain.MaintenanceStatus{
+			Enabled:   true,
+			Message:   req.Message,
+			StartedAt: &now,
+			StartedBy: &adminID,
+		}
+		// Updating the message or expected end keeps the original start
+		if current.Enabled {
+			status.StartedAt, status.StartedBy = current.StartedAt, current.StartedBy
+		}
+		if req.DurationMinutes > 0 {
+			endsAt := now.Add(time.Duration(req.DurationMinutes) * time.Minute)
+			status.EndsAt = &endsAt
+		}
+
+		if s.cache != nil {
+			if err := s.cache.SetJSON(ctx, cache.MaintenanceKey, status, 0); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		if current.Enabled && current.StartedAt != nil {
+			if err := s.extendPausedAuctions(ctx, *current.StartedAt, now); err != nil {
+				return nil, err
+			}
+		}
+
+		if s.cache != nil {
+			if err := s.cache.Delete(ctx, cache.MaintenanceKey); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	s.mu.Lock()
+	s.status = status
+	s.checkedAt = now
+	s.mu.Unlock()
+
+	if s.cache != nil && status.Enabled != current.Enabled {
+		_ = s.cache.Publish(ctx, cache.MaintenanceChannel, status)
+	}
+
+	return &status, nil
+}
+
+// extendPausedAuctions moves the end of auctions that came due during
+// maintenance, or end within the grace period, to the end of the grace period
+func (s *MaintenanceService) extendPausedAuctions(ctx context.Context, startedAt, now time.Time) error {
+	newEnd := now.Add(maintenanceResumeGrace)
+	ids, err := s.auctionRepo.ExtendEnding(ctx, startedAt, newEnd, newEnd)
+	if err != nil {
+		return err
+	}
+	if len(ids) > 0 {
+		log.Printf("Extended %d auctions that came due during maintenance", len(ids))
+	}
+
+	extended := make(map[uuid.UUID]time.Time, len(ids))
+	for _, id := range ids {
+		extended[id] = newEnd
+	}
+	s.announceExtended(ctx, extended)
+	return nil
+}
+
+// announceExtended tells bidders about auctions given a new end time and
+// reschedules their ends
+func (s *MaintenanceService) announceExtended(ctx context.Context, extended map[uuid.UUID]time.Time) {
+	if s.cache == nil {
+		return
+	}
+	for id, newEnd := range extended {
+		invalidateAuction(ctx, s.cache, id)
+
+		message := domain.WSMessage{
+			Type: domain.WSMessageAuctionExtended,
+			Payload: domain.WSAuctionExtendedPayload{
+				AuctionID:  id,
+				NewEndTime: newEnd,
+			},
+		}
+		_ = s.cache.Publish(ctx, cache.AuctionChannel(id), message)
+		if err := s.cache.ScheduleAuctionEnd(ctx, id, newEnd); err != nil {
+			log.Printf("Error scheduling end of auction %s: %v", id, err)
+		}
+	}
+}
+
+// Windows returns the maintenance windows not yet over, soonest first. Like
+// the status, each instance rereads them every few seconds.
+func (s *MaintenanceService) Windows(ctx context.Context) []domain.MaintenanceWindow {
+	if s.windowRepo == nil {
+		return []domain.MaintenanceWindow{}
+	}
+
+	s.windowsMu.Lock()
+	defer s.windowsMu.Unlock()
+
+	if time.Since(s.windowsCheckedAt) >= maintenanceCheckInterval {
+		windows, err := s.windowRepo.ListOpen(ctx)
+		if err != nil {
+			log.Printf("Error reading maintenance windows: %v", err)
+		} else {
+			s.windows = windows
+		}
+		s.windowsCheckedAt = time.Now()
+	}
+
+	windows := make([]domain.MaintenanceWindow, len(s.windows))
+	copy(windows, s.windows)
+	return windows
+}
+
+// Window returns the maintenance window in effect, or nil if there is none
+func (s *MaintenanceService) Window(ctx context.Context) *domain.MaintenanceWindow {
+	now := time.Now()
+	for _, window := range s.Windows(ctx) {
+		if window.InEffect(now) {
+			return &window
+		}
+	}
+	return nil
+}
+
+// ScheduleWindow plans a maintenance window. It may start now but not in
+// the past, since auctions that already ended can't be held back.
+func (s *MaintenanceService) ScheduleWindow(ctx context.Context, adminID uuid.UUID, req *domain.CreateMaintenanceWindowRequest) (*domain.MaintenanceWindow, error) {
+	now := time.Now()
+	startsAt := now
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+	// Allow for clocks a little behind ours
+	if startsAt.Before(now.Add(-time.Minute)) || !req.EndsAt.After(startsAt) || req.EndsAt.Sub(startsAt) > domain.MaxMaintenanceWindow {
+		return nil, domain.ErrInvalidMaintenanceWindow
+	}
+
+	overlaps, err := s.windowRepo.Overlaps(ctx, startsAt, req.EndsAt)
+	if err != nil {
+		return nil, err
+	}
+	if overlaps {
+		return nil, domain.ErrInvalidMaintenanceWindow
+	}
+
+	window := &domain.MaintenanceWindow{
+		Reason:    req.Reason,
+		StartsAt:  startsAt,
+		EndsAt:    req.EndsAt,
+		CreatedBy: adminID,
+	}
+	if err := s.windowRepo.Create(ctx, window); err != nil {
+		return nil, err
+	}
+
+	s.refreshWindows()
+	return window, nil
+}
+
+// CancelWindow drops a window that hasn't started. One in effect runs its
+// course so its auctions are extended.
+func (s *MaintenanceService) CancelWindow(ctx context.Context, id uuid.UUID) error {
+	window, err := s.windowRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if window.CompletedAt != nil || window.InEffect(time.Now()) {
+		return domain.ErrMaintenanceWindowStarted
+	}
+
+	if err := s.windowRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.refreshWindows()
+	return nil
+}
+
+// FinishWindows completes the windows that ended by now, moving the end of
+// each auction that came due during one back by its downtime
+func (s *MaintenanceService) FinishWindows(ctx context.Context, now time.Time) {
+	if s.windowRepo == nil {
+		return
+	}
+
+	finished := false
+	for _, window := range s.Windows(ctx) {
+		if window.EndsAt.After(now) {
+			continue
+		}
+
+		extended, err := s.completeWindow(ctx, &window, now)
+		if errors.Is(err, domain.ErrNotFound) {
+			// Another instance completed it
+			finished = true
+			continue
+		}
+		if err != nil {
+			log.Printf("Error completing maintenance window %s: %v", window.ID, err)
+			continue
+		}
+		finished = true
+
+		log.Printf("Maintenance window %s is over, extended %d auctions by %v", window.ID, len(extended), window.Downtime().Round(time.Second))
+		s.announceExtended(ctx, extended)
+	}
+
+	if finished {
+		s.refreshWindows()
+	}
+}
+
+// completeWindow marks the window completed and moves the end of the
+// auctions that came due during it back by its downtime, returning their new
+// end times. Claiming the window first means only one instance extends its
+// auctions.
+func (s *MaintenanceService) completeWindow(ctx context.Context, window *domain.MaintenanceWindow, now time.Time) (map[uuid.UUID]time.Time, error) {
+	var extended map[uuid.UUID]time.Time
+	err := s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.windowRepo.Complete(txCtx, window.ID, now); err != nil {
+			return err
+		}
+		window.CompletedAt = &now
+
+		var err error
+		extended, err = s.auctionRepo.ShiftEnding(txCtx, window.StartsAt, now, window.Downtime())
+		if err != nil {
+			return err
+		}
+		return s.windowRepo.SetAuctionsExtended(txCtx, window.ID, len(extended))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return extended, nil
+}
+
+// refreshWindows makes the next read of the windows go to the database
+func (s *MaintenanceService) refreshWindows() {
+	s.windowsMu.Lock()
+	s.windowsCheckedAt = time.Time{}
+	s.windowsMu.Unlock()
+}
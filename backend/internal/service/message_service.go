@@ -3,25 +3,44 @@ package service
 import (
 	"context"
 	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"github.com/auction-cards/backend/internal/domain"
 	"github.com/auction-cards/backend/internal/pkg/encryption"
+	"github.com/auction-cards/backend/internal/pkg/moderation"
 	"github.com/auction-cards/backend/internal/repository"
 	"github.com/auction-cards/backend/internal/websocket"
 	"github.com/google/uuid"
 )
 
+// Senders with this many flagged messages within the window are put in the
+// moderation queue
+const (
+	flaggedMessageThreshold = 3
+	flaggedMessageWindow    = 30 * 24 * time.Hour
+)
+
 type MessageService struct {
-	messageRepo repository.MessageRepository
-	userRepo    repository.UserRepository
-	encryptor   *encryption.AESEncryptor
-	messageHub  *websocket.MessageHub
+	messageRepo    repository.MessageRepository
+	deviceKeyRepo  repository.DeviceKeyRepository
+	moderationRepo repository.ModerationRepository
+	userRepo       repository.UserRepository
+	blockRepo      repository.UserBlockRepository
+	encryptor      *encryption.AESEncryptor
+	retentionDays  int
+	messageHub     *websocket.MessageHub
 }
 
 func NewMessageService(
 	messageRepo repository.MessageRepository,
+	deviceKeyRepo repository.DeviceKeyRepository,
+	moderationRepo repository.ModerationRepository,
 	userRepo repository.UserRepository,
+	blockRepo repository.UserBlockRepository,
 	encryptionKey string,
+	retentionDays int,
 	messageHub *websocket.MessageHub,
 ) (*MessageService, error) {
 	encryptor, err := encryption.NewAESEncryptor(encryptionKey)
@@ -29,50 +48,100 @@ func NewMessageService(
 		return nil, fmt.Errorf("failed to initialize encryptor: %w", err)
 	}
 
-	return &MessageService{
-		messageRepo: messageRepo,
-		userRepo:    userRepo,
-		encryptor:   encryptor,
-		messageHub:  messageHub,
-	}, nil
+	s := &MessageService{
+		messageRepo:    messageRepo,
+		deviceKeyRepo:  deviceKeyRepo,
+		moderationRepo: moderationRepo,
+		userRepo:       userRepo,
+		blockRepo:      blockRepo,
+		encryptor:      encryptor,
+		retentionDays:  retentionDays,
+		messageHub:     messageHub,
+	}
+
+	if messageHub != nil {
+		messageHub.SetEventHandler(s)
+	}
+
+	return s, nil
 }
 
-// SendMessage sends a message from one user to another
-func (s *MessageService) SendMessage(ctx context.Context, senderID uuid.UUID, req *domain.SendMessageRequest) (*domain.Message, uuid.UUID, error) {
+// SendMessage sends a message from one user to another. Requests carrying a
+// ciphertext go to the pair's end-to-end encrypted conversation and are
+// stored as is; plaintext requests are moderated and encrypted with the
+// server key. Profanity is masked, scam patterns are flagged for the
+// recipient, and the sender gets a warning for either. Users the recipient
+// has blocked can't message them, in a new conversation or an old one.
+func (s *MessageService) SendMessage(ctx context.Context, senderID uuid.UUID, req *domain.SendMessageRequest) (*domain.SendMessageResponse, error) {
 	// Check that recipient exists
 	recipient, err := s.userRepo.GetByID(ctx, req.RecipientID)
 	if err != nil {
-		return nil, uuid.Nil, domain.ErrNotFound
+		return nil, domain.ErrNotFound
 	}
 
 	// Cannot message yourself
 	if senderID == recipient.ID {
-		return nil, uuid.Nil, domain.ErrValidation
+		return nil, domain.ErrValidation
 	}
 
-	// Get or create conversation
-	conv, err := s.messageRepo.GetOrCreateConversation(ctx, senderID, req.RecipientID)
-	if err != nil {
-		return nil, uuid.Nil, fmt.Errorf("failed to get/create conversation: %w", err)
+	if s.blockRepo != nil {
+		blocked, err := s.blockRepo.IsBlocked(ctx, recipient.ID, senderID)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			return nil, domain.ErrBlockedByRecipient
+		}
+	}
+
+	mode := domain.EncryptionModeServer
+	if len(req.Ciphertext) > 0 {
+		mode = domain.EncryptionModeE2E
+		if err := s.checkKeyEnvelopes(ctx, senderID, req.RecipientID, req.KeyEnvelopes); err != nil {
+			return nil, err
+		}
 	}
 
-	// Encrypt the message content
-	ciphertext, nonce, err := s.encryptor.EncryptString(req.Content)
+	// Get or create conversation
+	conv, err := s.messageRepo.GetOrCreateConversation(ctx, senderID, req.RecipientID, mode)
 	if err != nil {
-		return nil, uuid.Nil, fmt.Errorf("failed to encrypt message: %w", err)
+		return nil, fmt.Errorf("failed to get/create conversation: %w", err)
 	}
 
-	// Create the message
 	msg := &domain.Message{
-		ConversationID:   conv.ID,
-		SenderID:         senderID,
-		ContentEncrypted: ciphertext,
-		ContentNonce:     nonce,
-		Content:          req.Content, // Keep plaintext in memory for response
+		ConversationID: conv.ID,
+		SenderID:       senderID,
+	}
+	var warnings []domain.ModerationWarning
+
+	if mode == domain.EncryptionModeE2E {
+		// The server can't read E2E content, so it isn't moderated
+		msg.ContentEncrypted = req.Ciphertext
+		msg.ContentNonce = req.Nonce
+		msg.KeyEnvelopes = req.KeyEnvelopes
+		msg.Ciphertext = req.Ciphertext
+		msg.Nonce = req.Nonce
+	} else {
+		scan := moderation.Scan(req.Content)
+		msg.ModerationFlags = scamFlags(scan)
+		warnings = moderationWarnings(scan)
+
+		// Encrypt the message content
+		ciphertext, nonce, err := s.encryptor.EncryptString(scan.Censored)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt message: %w", err)
+		}
+		msg.ContentEncrypted = ciphertext
+		msg.ContentNonce = nonce
+		msg.Content = scan.Censored // Keep plaintext in memory for response
 	}
 
 	if err := s.messageRepo.CreateMessage(ctx, msg); err != nil {
-		return nil, uuid.Nil, fmt.Errorf("failed to create message: %w", err)
+		return nil, fmt.Errorf("failed to create message: %w", err)
+	}
+
+	if len(msg.ModerationFlags) > 0 {
+		s.escalateRepeatOffender(ctx, senderID, msg.ModerationFlags)
 	}
 
 	// Send real-time notification to recipient via WebSocket
@@ -86,57 +155,224 @@ func (s *MessageService) SendMessage(ctx context.Context, senderID uuid.UUID, re
 		s.messageHub.SendToUser(req.RecipientID, wsPayload)
 	}
 
-	return msg, conv.ID, nil
+	resp := &domain.SendMessageResponse{
+		Message:        msg,
+		ConversationID: conv.ID,
+		Warnings:       warnings,
+	}
+
+	if mode == domain.EncryptionModeServer {
+		resp.AutoReply = s.sendVacationAutoReply(ctx, conv, recipient, senderID)
+	}
+
+	return resp, nil
 }
 
-// GetConversations returns all conversations for a user with details
-func (s *MessageService) GetConversations(ctx context.Context, userID uuid.UUID) ([]domain.ConversationWithDetails, error) {
-	conversations, err := s.messageRepo.GetConversationsForUser(ctx, userID)
+// sendVacationAutoReply answers on behalf of a recipient who is on vacation
+// with an auto-reply set. Only the first message of the vacation in a
+// conversation gets one; anything the recipient has sent since it started,
+// including an earlier auto-reply, suppresses it. E2E conversations are
+// skipped since the server can't encrypt for them.
+func (s *MessageService) sendVacationAutoReply(ctx context.Context, conv *domain.Conversation, recipient *domain.User, senderID uuid.UUID) *domain.Message {
+	vacation := recipient.Vacation
+	if vacation.AutoReply == nil || !vacation.ActiveAt(time.Now()) {
+		return nil
+	}
+
+	replied, err := s.messageRepo.HasSentSince(ctx, conv.ID, recipient.ID, *vacation.StartsAt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get conversations: %w", err)
+		log.Printf("Error checking vacation auto-reply for %s: %v", recipient.ID, err)
+		return nil
+	}
+	if replied {
+		return nil
 	}
 
-	result := make([]domain.ConversationWithDetails, 0, len(conversations))
+	ciphertext, nonce, err := s.encryptor.EncryptString(*vacation.AutoReply)
+	if err != nil {
+		log.Printf("Error encrypting vacation auto-reply for %s: %v", recipient.ID, err)
+		return nil
+	}
 
-	for _, conv := range conversations {
-		// Determine the other user
-		otherUserID := conv.ParticipantOne
-		if otherUserID == userID {
-			otherUserID = conv.ParticipantTwo
+	reply := &domain.Message{
+		ConversationID:   conv.ID,
+		SenderID:         recipient.ID,
+		ContentEncrypted: ciphertext,
+		ContentNonce:     nonce,
+		Content:          *vacation.AutoReply,
+	}
+	if err := s.messageRepo.CreateMessage(ctx, reply); err != nil {
+		log.Printf("Error sending vacation auto-reply for %s: %v", recipient.ID, err)
+		return nil
+	}
+
+	if s.messageHub != nil {
+		s.messageHub.SendToUser(senderID, domain.MessageWSPayload{
+			Type:           domain.MessageWSTypeNewMessage,
+			Message:        reply,
+			ConversationID: conv.ID,
+			SenderID:       recipient.ID,
+		})
+	}
+
+	return reply
+}
+
+// scamFlags returns the scam categories found in a message. Profanity is
+// masked instead, so it isn't flagged to the recipient.
+func scamFlags(scan *moderation.Result) []string {
+	var flags []string
+	for _, category := range scan.Categories() {
+		if category != moderation.CategoryProfanity {
+			flags = append(flags, string(category))
 		}
+	}
+	return flags
+}
 
-		// Get other user's info
-		otherUser, err := s.userRepo.GetByID(ctx, otherUserID)
-		if err != nil {
-			continue // Skip if user not found
+var moderationWarningMessages = map[moderation.Category]string{
+	moderation.CategoryProfanity:   "Your message contained language that was masked. Please keep conversations respectful.",
+	moderation.CategoryScamLink:    "Your message contains a link commonly used in scams. The recipient has been warned.",
+	moderation.CategoryOffPlatform: "Payments must go through the platform. Asking to pay elsewhere is against the rules and the recipient has been warned.",
+}
+
+func moderationWarnings(scan *moderation.Result) []domain.ModerationWarning {
+	var warnings []domain.ModerationWarning
+	for _, category := range scan.Categories() {
+		warnings = append(warnings, domain.ModerationWarning{
+			Category: string(category),
+			Message:  moderationWarningMessages[category],
+		})
+	}
+	return warnings
+}
+
+// escalateRepeatOffender puts the sender in the moderation queue once they
+// reach the flagged message threshold. The message has already been sent,
+// so failures are only logged.
+func (s *MessageService) escalateRepeatOffender(ctx context.Context, senderID uuid.UUID, flags []string) {
+	count, err := s.messageRepo.CountFlaggedMessages(ctx, senderID, time.Now().Add(-flaggedMessageWindow))
+	if err != nil {
+		log.Printf("Error counting flagged messages for %s: %v", senderID, err)
+		return
+	}
+	if count < flaggedMessageThreshold {
+		return
+	}
+
+	reason := fmt.Sprintf("%d flagged messages in %d days, latest: %s",
+		count, int(flaggedMessageWindow.Hours()/24), strings.Join(flags, ", "))
+	if _, err := s.moderationRepo.Escalate(ctx, senderID, reason, count); err != nil {
+		log.Printf("Error escalating user %s to moderation: %v", senderID, err)
+	}
+}
+
+// checkKeyEnvelopes makes sure an E2E message key is wrapped for every device
+// of the recipient and only for devices of the two participants
+func (s *MessageService) checkKeyEnvelopes(ctx context.Context, senderID, recipientID uuid.UUID, envelopes map[uuid.UUID][]byte) error {
+	recipientKeys, err := s.deviceKeyRepo.GetByUser(ctx, recipientID)
+	if err != nil {
+		return fmt.Errorf("failed to get device keys: %w", err)
+	}
+	if len(recipientKeys) == 0 {
+		return domain.ErrKeyEnvelopesIncomplete
+	}
+
+	senderKeys, err := s.deviceKeyRepo.GetByUser(ctx, senderID)
+	if err != nil {
+		return fmt.Errorf("failed to get device keys: %w", err)
+	}
+
+	known := make(map[uuid.UUID]bool, len(recipientKeys)+len(senderKeys))
+	for _, key := range senderKeys {
+		known[key.ID] = true
+	}
+	for _, key := range recipientKeys {
+		if len(envelopes[key.ID]) == 0 {
+			return domain.ErrKeyEnvelopesIncomplete
+		}
+		known[key.ID] = true
+	}
+	for keyID := range envelopes {
+		if !known[keyID] {
+			return domain.ErrValidation
 		}
+	}
 
-		// Get unread count
-		unreadCount, _ := s.messageRepo.GetUnreadCountForConversation(ctx, conv.ID, userID)
-
-		// Get last message
-		var lastMsg *domain.Message
-		lastMsgRaw, _ := s.messageRepo.GetLastMessage(ctx, conv.ID)
-		if lastMsgRaw != nil {
-			// Decrypt the message
-			plaintext, err := s.encryptor.DecryptString(lastMsgRaw.ContentEncrypted, lastMsgRaw.ContentNonce)
-			if err == nil {
-				lastMsgRaw.Content = plaintext
-				lastMsg = lastMsgRaw
-			}
+	return nil
+}
+
+// openMessage fills in what a participant gets to see of a stored message:
+// the decrypted content for server-encrypted messages, or the ciphertext
+// untouched for E2E messages, which are the only ones with key envelopes
+func (s *MessageService) openMessage(msg *domain.Message) error {
+	if msg.KeyEnvelopes != nil {
+		msg.Ciphertext = msg.ContentEncrypted
+		msg.Nonce = msg.ContentNonce
+		return nil
+	}
+
+	plaintext, err := s.encryptor.DecryptString(msg.ContentEncrypted, msg.ContentNonce)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt message: %w", err)
+	}
+	msg.Content = plaintext
+
+	return nil
+}
+
+// RegisterDeviceKey stores the public key of one of the user's devices so
+// others can wrap E2E message keys for it
+func (s *MessageService) RegisterDeviceKey(ctx context.Context, userID uuid.UUID, req *domain.RegisterDeviceKeyRequest) (*domain.DeviceKey, error) {
+	key := &domain.DeviceKey{
+		UserID:    userID,
+		DeviceID:  req.DeviceID,
+		PublicKey: req.PublicKey,
+	}
+
+	if err := s.deviceKeyRepo.Upsert(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// GetDeviceKeys returns the public keys of all of a user's devices
+func (s *MessageService) GetDeviceKeys(ctx context.Context, userID uuid.UUID) ([]domain.DeviceKey, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.deviceKeyRepo.GetByUser(ctx, userID)
+}
+
+// RevokeDeviceKey removes one of the user's device keys. Messages already
+// wrapped for it stay readable only by that device.
+func (s *MessageService) RevokeDeviceKey(ctx context.Context, userID, keyID uuid.UUID) error {
+	return s.deviceKeyRepo.Delete(ctx, keyID, userID)
+}
+
+// GetConversations returns all conversations for a user with details
+func (s *MessageService) GetConversations(ctx context.Context, userID uuid.UUID) ([]domain.ConversationWithDetails, error) {
+	conversations, err := s.messageRepo.GetConversationDetailsForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversations: %w", err)
+	}
+
+	for i := range conversations {
+		conversations[i].RetentionDays = s.effectiveRetention(conversations[i].RetentionDays)
+
+		lastMsg := conversations[i].LastMessage
+		if lastMsg == nil {
+			continue
 		}
 
-		result = append(result, domain.ConversationWithDetails{
-			ID:            conv.ID,
-			OtherUser:     otherUser.ToPublic(),
-			LastMessage:   lastMsg,
-			LastMessageAt: conv.LastMessageAt,
-			UnreadCount:   unreadCount,
-			CreatedAt:     conv.CreatedAt,
-		})
+		if err := s.openMessage(lastMsg); err != nil {
+			conversations[i].LastMessage = nil
+		}
 	}
 
-	return result, nil
+	return conversations, nil
 }
 
 // GetMessages returns messages for a conversation
@@ -155,10 +391,80 @@ func (s *MessageService) GetMessages(ctx context.Context, userID, conversationID
 		return nil, 0, fmt.Errorf("failed to get messages: %w", err)
 	}
 
+	result, err := s.withSenders(ctx, conversationID, messages)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return result, totalCount, nil
+}
+
+// GetMessagesWindow returns a cursor-paginated page of messages, oldest
+// first. The anchor is either MessageAnchorFirstUnread or an RFC3339 time to
+// jump to, and is ignored when a before/after cursor is given.
+func (s *MessageService) GetMessagesWindow(ctx context.Context, userID, conversationID uuid.UUID, params *domain.MessageWindowParams, anchor string) (*domain.MessagesResponse, error) {
+	isMember, err := s.messageRepo.IsUserInConversation(ctx, conversationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check membership: %w", err)
+	}
+	if !isMember {
+		return nil, domain.ErrForbidden
+	}
+
+	// Everything after this point counts as unread for the user
+	var lastReadAt time.Time
+	status, err := s.messageRepo.GetReadStatus(ctx, conversationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get read status: %w", err)
+	}
+	if status != nil && status.LastReadAt != nil {
+		lastReadAt = *status.LastReadAt
+	}
+
+	if params.Before == nil && params.After == nil && anchor != "" {
+		if anchor == domain.MessageAnchorFirstUnread {
+			params.Around = &lastReadAt
+		} else {
+			at, err := time.Parse(time.RFC3339, anchor)
+			if err != nil {
+				return nil, domain.ErrValidation
+			}
+			params.Around = &at
+		}
+	}
+
+	window, err := s.messageRepo.GetMessagesWindow(ctx, conversationID, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+
+	messages, err := s.withSenders(ctx, conversationID, window.Messages)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &domain.MessagesResponse{
+		Messages:      messages,
+		HasMoreBefore: window.HasMoreBefore,
+		HasMoreAfter:  window.HasMoreAfter,
+	}
+	for _, msg := range window.Messages {
+		if msg.SenderID != userID && msg.CreatedAt.After(lastReadAt) {
+			id := msg.ID
+			resp.FirstUnreadID = &id
+			break
+		}
+	}
+
+	return resp, nil
+}
+
+// withSenders decrypts messages and attaches the sender's public profile
+func (s *MessageService) withSenders(ctx context.Context, conversationID uuid.UUID, messages []domain.Message) ([]domain.MessageWithSender, error) {
 	// Get conversation to find participants
 	conv, err := s.messageRepo.GetConversationByID(ctx, conversationID)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get conversation: %w", err)
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
 	}
 
 	// Cache users
@@ -172,12 +478,9 @@ func (s *MessageService) GetMessages(ctx context.Context, userID, conversationID
 
 	result := make([]domain.MessageWithSender, 0, len(messages))
 	for _, msg := range messages {
-		// Decrypt message content
-		plaintext, err := s.encryptor.DecryptString(msg.ContentEncrypted, msg.ContentNonce)
-		if err != nil {
+		if err := s.openMessage(&msg); err != nil {
 			continue // Skip messages that can't be decrypted
 		}
-		msg.Content = plaintext
 
 		result = append(result, domain.MessageWithSender{
 			Message: msg,
@@ -185,7 +488,79 @@ func (s *MessageService) GetMessages(ctx context.Context, userID, conversationID
 		})
 	}
 
-	return result, totalCount, nil
+	return result, nil
+}
+
+// transcript decrypts the whole conversation, oldest message first, for an
+// admin review. Callers must check the admin is entitled to read it.
+func (s *MessageService) transcript(ctx context.Context, conv *domain.Conversation) (*domain.ConversationTranscript, error) {
+	const pageSize = 500
+
+	var messages []domain.Message
+	for page := 1; ; page++ {
+		batch, _, err := s.messageRepo.GetMessagesByConversation(ctx, conv.ID, page, pageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get messages: %w", err)
+		}
+		messages = append(messages, batch...)
+		if len(batch) < pageSize {
+			break
+		}
+	}
+
+	transcript := &domain.ConversationTranscript{
+		Conversation: conv,
+		Messages:     make([]domain.MessageWithSender, 0, len(messages)),
+	}
+
+	users := make(map[uuid.UUID]*domain.PublicUser)
+	for _, uid := range []uuid.UUID{conv.ParticipantOne, conv.ParticipantTwo} {
+		user, err := s.userRepo.GetByID(ctx, uid)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get participant: %w", err)
+		}
+		users[uid] = user.ToPublic()
+		transcript.Participants = append(transcript.Participants, users[uid])
+	}
+
+	// Pages come newest first
+	for i := len(messages) - 1; i >= 0; i-- {
+		msg := messages[i]
+		if msg.KeyEnvelopes != nil {
+			transcript.EncryptedCount++
+			continue
+		}
+		if err := s.openMessage(&msg); err != nil {
+			return nil, err
+		}
+		transcript.Messages = append(transcript.Messages, domain.MessageWithSender{
+			Message: msg,
+			Sender:  users[msg.SenderID],
+		})
+	}
+
+	return transcript, nil
+}
+
+// Transcripts decrypts every conversation the user takes part in, for a
+// takeout of their data. End-to-end encrypted messages can't be read on the
+// server and are only counted.
+func (s *MessageService) Transcripts(ctx context.Context, userID uuid.UUID) ([]domain.ConversationTranscript, error) {
+	conversations, err := s.messageRepo.GetConversationsForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversations: %w", err)
+	}
+
+	transcripts := make([]domain.ConversationTranscript, 0, len(conversations))
+	for i := range conversations {
+		transcript, err := s.transcript(ctx, &conversations[i])
+		if err != nil {
+			return nil, err
+		}
+		transcripts = append(transcripts, *transcript)
+	}
+
+	return transcripts, nil
 }
 
 // MarkConversationRead marks all messages in a conversation as read
@@ -202,9 +577,23 @@ func (s *MessageService) MarkConversationRead(ctx context.Context, userID, conve
 	return s.messageRepo.UpdateReadStatus(ctx, conversationID, userID)
 }
 
-// GetUnreadCount returns the total unread message count for a user
-func (s *MessageService) GetUnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
-	return s.messageRepo.GetTotalUnreadCount(ctx, userID)
+// GetUnreadCount returns the total and per-conversation unread message counts
+// for a user
+func (s *MessageService) GetUnreadCount(ctx context.Context, userID uuid.UUID) (*domain.UnreadCountResponse, error) {
+	counts, err := s.messageRepo.GetUnreadCounts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+
+	return &domain.UnreadCountResponse{
+		Count:         total,
+		Conversations: counts,
+	}, nil
 }
 
 // GetConversationByID retrieves a single conversation with details
@@ -218,43 +607,110 @@ func (s *MessageService) GetConversationByID(ctx context.Context, userID, conver
 		return nil, domain.ErrForbidden
 	}
 
-	conv, err := s.messageRepo.GetConversationByID(ctx, conversationID)
+	conv, err := s.messageRepo.GetConversationDetails(ctx, conversationID, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Determine the other user
-	otherUserID := conv.ParticipantOne
-	if otherUserID == userID {
-		otherUserID = conv.ParticipantTwo
+	conv.RetentionDays = s.effectiveRetention(conv.RetentionDays)
+	if conv.LastMessage != nil {
+		if err := s.openMessage(conv.LastMessage); err != nil {
+			conv.LastMessage = nil
+		}
+	}
+
+	return conv, nil
+}
+
+// effectiveRetention applies the platform default to the participants'
+// shortest retention setting. Returns nil when messages are kept forever.
+func (s *MessageService) effectiveRetention(userDays *int) *int {
+	if s.retentionDays <= 0 {
+		return userDays
+	}
+	if userDays != nil && *userDays < s.retentionDays {
+		return userDays
+	}
+	days := s.retentionDays
+	return &days
+}
+
+// GetPresence returns whether a user is currently connected to messaging
+func (s *MessageService) GetPresence(ctx context.Context, userID uuid.UUID) (*domain.UserPresence, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	presence := &domain.UserPresence{UserID: userID}
+	if s.messageHub == nil {
+		return presence, nil
 	}
 
-	// Get other user's info
-	otherUser, err := s.userRepo.GetByID(ctx, otherUserID)
+	presence.Online = s.messageHub.IsUserOnline(userID)
+	if !presence.Online {
+		presence.LastSeenAt = s.messageHub.LastSeen(userID)
+	}
+
+	return presence, nil
+}
+
+// OnPresenceChange tells everyone the user has a conversation with that they
+// came online or went offline
+func (s *MessageService) OnPresenceChange(userID uuid.UUID, online bool) {
+	ctx := context.Background()
+
+	conversations, err := s.messageRepo.GetConversationsForUser(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get other user: %w", err)
+		return
 	}
 
-	// Get unread count
-	unreadCount, _ := s.messageRepo.GetUnreadCountForConversation(ctx, conv.ID, userID)
+	wsPayload := domain.MessageWSPayload{
+		Type:   domain.MessageWSTypeUserOnline,
+		UserID: userID,
+	}
+	if !online {
+		now := time.Now()
+		wsPayload.Type = domain.MessageWSTypeUserOffline
+		wsPayload.LastSeenAt = &now
+	}
 
-	// Get last message
-	var lastMsg *domain.Message
-	lastMsgRaw, _ := s.messageRepo.GetLastMessage(ctx, conv.ID)
-	if lastMsgRaw != nil {
-		plaintext, err := s.encryptor.DecryptString(lastMsgRaw.ContentEncrypted, lastMsgRaw.ContentNonce)
-		if err == nil {
-			lastMsgRaw.Content = plaintext
-			lastMsg = lastMsgRaw
+	for _, conv := range conversations {
+		otherUserID := conv.ParticipantOne
+		if otherUserID == userID {
+			otherUserID = conv.ParticipantTwo
 		}
+		s.messageHub.SendToUser(otherUserID, wsPayload)
 	}
+}
 
-	return &domain.ConversationWithDetails{
-		ID:            conv.ID,
-		OtherUser:     otherUser.ToPublic(),
-		LastMessage:   lastMsg,
-		LastMessageAt: conv.LastMessageAt,
-		UnreadCount:   unreadCount,
-		CreatedAt:     conv.CreatedAt,
-	}, nil
+// OnTyping relays a typing indicator to the other participant of the
+// conversation
+func (s *MessageService) OnTyping(userID, conversationID uuid.UUID, typing bool) {
+	ctx := context.Background()
+
+	conv, err := s.messageRepo.GetConversationByID(ctx, conversationID)
+	if err != nil {
+		return
+	}
+
+	var otherUserID uuid.UUID
+	switch userID {
+	case conv.ParticipantOne:
+		otherUserID = conv.ParticipantTwo
+	case conv.ParticipantTwo:
+		otherUserID = conv.ParticipantOne
+	default:
+		return
+	}
+
+	wsType := domain.MessageWSTypeTypingStarted
+	if !typing {
+		wsType = domain.MessageWSTypeTypingStopped
+	}
+
+	s.messageHub.SendToUser(otherUserID, domain.MessageWSPayload{
+		Type:           wsType,
+		ConversationID: conversationID,
+		SenderID:       userID,
+	})
 }
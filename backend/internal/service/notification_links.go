@@ -0,0 +1,80 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/google/uuid"
+)
+
+// notificationLinks builds the canonical deep links notifications and their
+// actions point to. They are web app URLs; other clients map the paths to
+// their own screens.
+type notificationLinks struct {
+	baseURL string
+}
+
+func (l notificationLinks) auction(auctionID uuid.UUID) string {
+	return fmt.Sprintf("%s/auctions/%s", l.baseURL, auctionID)
+}
+
+// purchase opens the buyer's purchase of the auction, ready to pay or rate
+func (l notificationLinks) purchase(auctionID uuid.UUID, action domain.NotificationActionType) string {
+	return fmt.Sprintf("%s/my-purchases?auction=%s&action=%s", l.baseURL, auctionID, action)
+}
+
+// sale opens the seller's listing, ready to rate the buyer or answer offers
+func (l notificationLinks) sale(auctionID uuid.UUID, action domain.NotificationActionType) string {
+	return fmt.Sprintf("%s/my-auctions?auction=%s&action=%s", l.baseURL, auctionID, action)
+}
+
+func (l notificationLinks) profile(tab string) string {
+	return fmt.Sprintf("%s/profile?tab=%s", l.baseURL, url.QueryEscape(tab))
+}
+
+// unsubscribe opens the page that takes the token from an email's
+// unsubscribe link to the API
+func (l notificationLinks) unsubscribe(token string) string {
+	return fmt.Sprintf("%s/unsubscribe?token=%s", l.baseURL, url.QueryEscape(token))
+}
+
+// bid opens the auction with the bid form filled in
+func (l notificationLinks) bid(auction *domain.Auction, label string, amount domain.Money) domain.NotificationAction {
+	return domain.NotificationAction{
+		Type:   domain.NotificationActionBid,
+		Label:  label,
+		URL:    fmt.Sprintf("%s?bid=%s", l.auction(auction.ID), amount.Amount.StringFixed(domain.MoneyScale)),
+		Amount: &amount,
+	}
+}
+
+func (l notificationLinks) buyNow(auction *domain.Auction) domain.NotificationAction {
+	price := auction.Price(*auction.BuyNowPrice)
+	return domain.NotificationAction{
+		Type:   domain.NotificationActionBuyNow,
+		Label:  "Buy now",
+		URL:    l.auction(auction.ID) + "?buy_now=true",
+		Amount: &price,
+	}
+}
+
+// forType is where a notification leads when it wasn't given a link: its
+// auction, or for the few not about one, the matching settings
+func (l notificationLinks) forType(notification *domain.Notification) *string {
+	var link string
+	switch {
+	case notification.AuctionID != nil:
+		link = l.auction(*notification.AuctionID)
+	case notification.Type == domain.NotificationSuspiciousLogin:
+		link = l.profile("security")
+	case notification.Type == domain.NotificationPayoutRequested,
+		notification.Type == domain.NotificationPayoutApproved,
+		notification.Type == domain.NotificationPayoutCompleted,
+		notification.Type == domain.NotificationPayoutRejected:
+		link = l.profile("payouts")
+	default:
+		return nil
+	}
+	return &link
+}
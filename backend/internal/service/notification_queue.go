@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// notificationJob is one unit of fan-out work, e.g. a chunk of watchers
+type notificationJob struct {
+	run        func(ctx context.Context)
+	enqueuedAt time.Time
+}
+
+// NotificationQueue is a bounded in-process job queue drained by a fixed
+// pool of workers. Enqueue blocks while the queue is full, which pushes back
+// on producers such as the scheduler instead of piling up goroutines.
+type NotificationQueue struct {
+	jobs      chan notificationJob
+	workers   int
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
+	processed atomic.Int64
+	lastLag   atomic.Int64
+	maxLag    atomic.Int64
+}
+
+// NotificationQueueStats is a snapshot of queue depth and lag
+type NotificationQueueStats struct {
+	Depth     int   `json:"depth"`
+	Capacity  int   `json:"capacity"`
+	Workers   int   `json:"workers"`
+	Processed int64 `json:"processed"`
+	LastLagMs int64 `json:"last_lag_ms"`
+	MaxLagMs  int64 `json:"max_lag_ms"`
+}
+
+func NewNotificationQueue(capacity, workers int) *NotificationQueue {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+
+	return &NotificationQueue{
+		jobs:     make(chan notificationJob, capacity),
+		workers:  workers,
+		stopChan: make(chan struct{}),
+	}
+}
+
+func (q *NotificationQueue) Start() {
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+}
+
+// Stop lets the workers finish what is already queued and waits for them,
+// giving up when ctx is done
+func (q *NotificationQueue) Stop(ctx context.Context) error {
+	close(q.stopChan)
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d notification jobs left in the queue: %w", len(q.jobs), ctx.Err())
+	}
+}
+
+// Enqueue adds a job, blocking while the queue is full. It gives up when ctx
+// is done or the queue is stopping.
+func (q *NotificationQueue) Enqueue(ctx context.Context, run func(ctx context.Context)) error {
+	job := notificationJob{run: run, enqueuedAt: time.Now()}
+
+	select {
+	case q.jobs <- job:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-q.stopChan:
+		return context.Canceled
+	}
+}
+
+func (q *NotificationQueue) Stats() NotificationQueueStats {
+	return NotificationQueueStats{
+		Depth:     len(q.jobs),
+		Capacity:  cap(q.jobs),
+		Workers:   q.workers,
+		Processed: q.processed.Load(),
+		LastLagMs: time.Duration(q.lastLag.Load()).Milliseconds(),
+		MaxLagMs:  time.Duration(q.maxLag.Load()).Milliseconds(),
+	}
+}
+
+func (q *NotificationQueue) work() {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case job := <-q.jobs:
+			q.runJob(job)
+		case <-q.stopChan:
+			// Drain whatever is left before exiting
+			for {
+				select {
+				case job := <-q.jobs:
+					q.runJob(job)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (q *NotificationQueue) runJob(job notificationJob) {
+	lag := time.Since(job.enqueuedAt)
+	q.lastLag.Store(int64(lag))
+	for {
+		current := q.maxLag.Load()
+		if int64(lag) <= current || q.maxLag.CompareAndSwap(current, int64(lag)) {
+			break
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Notification job panicked: %v", r)
+		}
+		q.processed.Add(1)
+	}()
+
+	job.run(context.Background())
+}
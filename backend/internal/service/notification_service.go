@@ -2,37 +2,395 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"slices"
+	"time"
 
 	"github.com/auction-cards/backend/internal/domain"
 	"github.com/auction-cards/backend/internal/pkg/email"
+	"github.com/auction-cards/backend/internal/pkg/unsubscribe"
+	"github.com/auction-cards/backend/internal/pkg/webpush"
 	"github.com/auction-cards/backend/internal/repository"
+	"github.com/auction-cards/backend/internal/websocket"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
 
+const (
+	// notificationFanoutChunk is how many recipients one queued fan-out job
+	// covers
+	notificationFanoutChunk = 500
+
+	// quietHoursDigestInterval is how often the scheduler sends the digests
+	// of users whose quiet hours have ended, releasing up to
+	// quietHoursDigestBatch held emails at a time
+	quietHoursDigestInterval = time.Minute
+	quietHoursDigestBatch    = 1000
+
+	// auctionWonPushTTL is how long a push service holds the auction won
+	// push for a browser that is offline
+	auctionWonPushTTL = 24 * time.Hour
+)
+
 type NotificationService struct {
 	notificationRepo repository.NotificationRepository
 	userRepo         repository.UserRepository
 	watchlistRepo    repository.WatchlistRepository
+	bidAlertRepo     repository.SellerBidAlertRepository
+	quietHoursRepo   repository.QuietHoursRepository
+	preferenceRepo   repository.NotificationPreferenceRepository
+	digestRepo       repository.DigestRepository
 	emailSender      email.Sender
+	queue            *NotificationQueue
+	messageHub       *websocket.MessageHub
+	pushSvc          *PushService
+	unsubscribe      *unsubscribe.Signer
 	baseURL          string
+	links            notificationLinks
 }
 
 func NewNotificationService(
 	notificationRepo repository.NotificationRepository,
 	userRepo repository.UserRepository,
 	watchlistRepo repository.WatchlistRepository,
+	bidAlertRepo repository.SellerBidAlertRepository,
+	quietHoursRepo repository.QuietHoursRepository,
+	preferenceRepo repository.NotificationPreferenceRepository,
+	digestRepo repository.DigestRepository,
 	emailSender email.Sender,
+	queue *NotificationQueue,
+	messageHub *websocket.MessageHub,
+	pushSvc *PushService,
+	unsubscribe *unsubscribe.Signer,
 	baseURL string,
 ) *NotificationService {
 	return &NotificationService{
 		notificationRepo: notificationRepo,
 		userRepo:         userRepo,
 		watchlistRepo:    watchlistRepo,
+		bidAlertRepo:     bidAlertRepo,
+		quietHoursRepo:   quietHoursRepo,
+		preferenceRepo:   preferenceRepo,
+		digestRepo:       digestRepo,
 		emailSender:      emailSender,
+		queue:            queue,
+		messageHub:       messageHub,
+		pushSvc:          pushSvc,
+		unsubscribe:      unsubscribe,
 		baseURL:          baseURL,
+		links:            notificationLinks{baseURL: baseURL},
+	}
+}
+
+// dispatch runs a fan-out job on the notification queue, or inline when the
+// service has no queue
+func (s *NotificationService) dispatch(ctx context.Context, job func(ctx context.Context)) {
+	if s.queue == nil {
+		job(ctx)
+		return
+	}
+
+	if err := s.queue.Enqueue(ctx, job); err != nil {
+		log.Printf("Dropping notification job: %v", err)
+	}
+}
+
+// sendEmail is how every notification email goes out, unless the user
+// switched off emails of its type. During the user's quiet hours it is held
+// for their digest instead, unless it is critical and they let those
+// through. Email is the only channel held; there is no SMS.
+func (s *NotificationService) sendEmail(ctx context.Context, userID uuid.UUID, notificationType domain.NotificationType, data *email.EmailData) {
+	if !s.allows(ctx, userID, notificationType, domain.NotificationChannelEmail) {
+		return
+	}
+
+	now := time.Now()
+	if quietHours := s.quietHours(ctx, userID); quietHours != nil && quietHours.Holds(notificationType, now) {
+		held := &domain.HeldNotification{
+			UserID:    userID,
+			Type:      notificationType,
+			Subject:   data.Subject,
+			Body:      data.Body,
+			ReleaseAt: quietHours.EndAfter(now),
+		}
+		err := s.quietHoursRepo.Hold(ctx, held)
+		if err == nil {
+			return
+		}
+		// Better to wake them than to lose the email
+		log.Printf("Failed to hold %s email for user %s, sending now: %v", notificationType, userID, err)
+	}
+
+	if s.unsubscribe != nil {
+		token := s.unsubscribe.Sign(userID, string(notificationType))
+		data.WithUnsubscribe(s.links.unsubscribe(token), s.links.profile("notifications"))
+	}
+	_ = s.emailSender.Send(data)
+}
+
+// allows reports whether the user gets notifications of the type on the
+// channel. When preferences can't be read the notification is sent.
+func (s *NotificationService) allows(ctx context.Context, userID uuid.UUID, notificationType domain.NotificationType, channel domain.NotificationChannel) bool {
+	return !s.optedOut(ctx, []uuid.UUID{userID}, notificationType, channel)[userID]
+}
+
+// optedOut returns which of the users switched the channel off for the type
+func (s *NotificationService) optedOut(ctx context.Context, userIDs []uuid.UUID, notificationType domain.NotificationType, channel domain.NotificationChannel) map[uuid.UUID]bool {
+	if s.preferenceRepo == nil || len(userIDs) == 0 {
+		return nil
+	}
+
+	optedOut, err := s.preferenceRepo.OptedOut(ctx, userIDs, notificationType, channel)
+	if err != nil {
+		log.Printf("Failed to get %s %s preferences, sending anyway: %v", notificationType, channel, err)
+		return nil
+	}
+	return optedOut
+}
+
+// digestsWatchedEnding returns which of the users get watched auctions about
+// to end in their digest. When that can't be read they get the emails.
+func (s *NotificationService) digestsWatchedEnding(ctx context.Context, userIDs []uuid.UUID) map[uuid.UUID]bool {
+	if s.digestRepo == nil || len(userIDs) == 0 {
+		return nil
+	}
+
+	digested, err := s.digestRepo.DigestsWatchedEnding(ctx, userIDs)
+	if err != nil {
+		log.Printf("Failed to get digest preferences, sending ending emails: %v", err)
+		return nil
+	}
+	return digested
+}
+
+// GetNotificationSettings returns the user's preference for every
+// notification type, the defaults for those they haven't set
+func (s *NotificationService) GetNotificationSettings(ctx context.Context, userID uuid.UUID) (*domain.NotificationSettings, error) {
+	stored, err := s.preferenceRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byType := make(map[domain.NotificationType]domain.NotificationPreference, len(stored))
+	for _, preference := range stored {
+		byType[preference.Type] = preference
+	}
+
+	settings := &domain.NotificationSettings{
+		UserID:      userID,
+		Preferences: make([]domain.NotificationPreference, 0, len(domain.NotificationTypes)),
+	}
+	for _, notificationType := range domain.NotificationTypes {
+		preference, ok := byType[notificationType]
+		if !ok {
+			preference = domain.DefaultNotificationPreference(notificationType)
+		}
+		settings.Preferences = append(settings.Preferences, preference)
+	}
+	return settings, nil
+}
+
+// UpdateNotificationSettings switches channels of the types listed on or
+// off, keeping the channels left out
+func (s *NotificationService) UpdateNotificationSettings(ctx context.Context, userID uuid.UUID, req *domain.UpdateNotificationSettingsRequest) (*domain.NotificationSettings, error) {
+	for _, update := range req.Preferences {
+		if !domain.NotificationType(update.Type).Valid() {
+			return nil, domain.ErrInvalidNotificationType
+		}
+	}
+
+	settings, err := s.GetNotificationSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make([]domain.NotificationPreference, 0, len(req.Preferences))
+	for _, update := range req.Preferences {
+		i := slices.Index(domain.NotificationTypes, domain.NotificationType(update.Type))
+		preference := &settings.Preferences[i]
+		if update.Email != nil {
+			preference.Email = *update.Email
+		}
+		if update.InApp != nil {
+			preference.InApp = *update.InApp
+		}
+		if update.Push != nil {
+			preference.Push = *update.Push
+		}
+		changed = append(changed, *preference)
+	}
+
+	if err := s.preferenceRepo.Save(ctx, userID, changed); err != nil {
+		return nil, err
+	}
+	return s.GetNotificationSettings(ctx, userID)
+}
+
+// Unsubscribe switches off the emails of the type named by an email's
+// unsubscribe link, for the user it was sent to. The link stands in for
+// signing in.
+func (s *NotificationService) Unsubscribe(ctx context.Context, token string) (*domain.NotificationPreference, error) {
+	if s.unsubscribe == nil {
+		return nil, domain.ErrInvalidUnsubscribeToken
+	}
+
+	userID, notificationType, err := s.unsubscribe.Verify(token)
+	if err != nil || !domain.NotificationType(notificationType).Valid() {
+		return nil, domain.ErrInvalidUnsubscribeToken
+	}
+
+	off := false
+	settings, err := s.UpdateNotificationSettings(ctx, userID, &domain.UpdateNotificationSettingsRequest{
+		Preferences: []domain.NotificationPreferenceUpdate{{Type: notificationType, Email: &off}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	i := slices.Index(domain.NotificationTypes, domain.NotificationType(notificationType))
+	return &settings.Preferences[i], nil
+}
+
+// quietHours returns the user's quiet hours, or nil when they have none or
+// they can't be read
+func (s *NotificationService) quietHours(ctx context.Context, userID uuid.UUID) *domain.QuietHours {
+	if s.quietHoursRepo == nil {
+		return nil
+	}
+
+	quietHours, err := s.quietHoursRepo.Get(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			log.Printf("Failed to get quiet hours for user %s: %v", userID, err)
+		}
+		return nil
+	}
+	return quietHours
+}
+
+// GetQuietHours returns the user's quiet hours, or the defaults, switched
+// off, when they haven't set any
+func (s *NotificationService) GetQuietHours(ctx context.Context, userID uuid.UUID) (*domain.QuietHours, error) {
+	quietHours, err := s.quietHoursRepo.Get(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.DefaultQuietHours(userID), nil
+	}
+	return quietHours, err
+}
+
+func (s *NotificationService) SetQuietHours(ctx context.Context, userID uuid.UUID, req *domain.SetQuietHoursRequest) (*domain.QuietHours, error) {
+	quietHours := &domain.QuietHours{
+		UserID:        userID,
+		Enabled:       req.Enabled,
+		Start:         req.Start,
+		End:           req.End,
+		Timezone:      req.Timezone,
+		AllowCritical: req.AllowCritical,
+	}
+	if err := quietHours.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.quietHoursRepo.Save(ctx, quietHours); err != nil {
+		return nil, err
+	}
+	return quietHours, nil
+}
+
+// SendQuietHoursDigests sends what was held for users whose quiet hours have
+// ended, one digest email each, and returns how many went out
+func (s *NotificationService) SendQuietHoursDigests(ctx context.Context, batchSize int) (int, error) {
+	if s.quietHoursRepo == nil {
+		return 0, nil
+	}
+
+	held, err := s.quietHoursRepo.ReleaseDue(ctx, time.Now(), batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	byUser := make(map[uuid.UUID][]domain.HeldNotification)
+	order := make([]uuid.UUID, 0)
+	for _, h := range held {
+		if _, ok := byUser[h.UserID]; !ok {
+			order = append(order, h.UserID)
+		}
+		byUser[h.UserID] = append(byUser[h.UserID], h)
+	}
+
+	sent := 0
+	for _, userID := range order {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			log.Printf("Dropping quiet hours digest for user %s: %v", userID, err)
+			continue
+		}
+
+		items := make([]email.DigestItem, 0, len(byUser[userID]))
+		for _, h := range byUser[userID] {
+			items = append(items, email.DigestItem{Subject: h.Subject, Body: h.Body})
+		}
+		if err := s.emailSender.Send(email.NewDigestEmail(user.Email, items)); err != nil {
+			log.Printf("Failed to send quiet hours digest to user %s: %v", userID, err)
+			continue
+		}
+		sent++
 	}
+	return sent, nil
+}
+
+// create stores an in-app notification, linking it to its auction or
+// settings when it wasn't given a link
+func (s *NotificationService) create(ctx context.Context, notification *domain.Notification) {
+	if !s.allows(ctx, notification.UserID, notification.Type, domain.NotificationChannelInApp) {
+		return
+	}
+	if notification.Link == nil {
+		notification.Link = s.links.forType(notification)
+	}
+	_ = s.notificationRepo.Create(ctx, notification)
+}
+
+// createBatch stores a fan-out of one type of notification, leaving out
+// recipients who switched in-app notifications of the type off
+func (s *NotificationService) createBatch(ctx context.Context, notifications []domain.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	userIDs := make([]uuid.UUID, len(notifications))
+	for i := range notifications {
+		userIDs[i] = notifications[i].UserID
+	}
+	optedOut := s.optedOut(ctx, userIDs, notifications[0].Type, domain.NotificationChannelInApp)
+
+	wanted := make([]domain.Notification, 0, len(notifications))
+	for i := range notifications {
+		if optedOut[notifications[i].UserID] {
+			continue
+		}
+		if notifications[i].Link == nil {
+			notifications[i].Link = s.links.forType(&notifications[i])
+		}
+		wanted = append(wanted, notifications[i])
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+	return s.notificationRepo.CreateBatch(ctx, wanted)
+}
+
+// QueueStats reports the notification queue's depth and lag, or nil when
+// notifications are sent inline
+func (s *NotificationService) QueueStats() *NotificationQueueStats {
+	if s.queue == nil {
+		return nil
+	}
+
+	stats := s.queue.Stats()
+	return &stats
 }
 
 func (s *NotificationService) GetUserNotifications(ctx context.Context, userID uuid.UUID, params *domain.NotificationListParams) (*domain.NotificationListResponse, error) {
@@ -45,6 +403,30 @@ func (s *NotificationService) GetUserNotifications(ctx context.Context, userID u
 		params.Limit = 20
 	}
 
+	if params.Grouped {
+		return s.getUserNotificationThreads(ctx, userID, params)
+	}
+
+	if params.Cursor != nil {
+		// Fetch one extra notification to tell whether there is another page
+		query := *params
+		query.Limit++
+		notifications, _, unreadCount, err := s.notificationRepo.GetByUserID(ctx, userID, &query)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := &domain.NotificationListResponse{
+			Notifications: notifications,
+			UnreadCount:   unreadCount,
+		}
+		if len(notifications) > params.Limit {
+			resp.Notifications = notifications[:params.Limit]
+			resp.NextCursor = notificationCursor(resp.Notifications)
+		}
+		return resp, nil
+	}
+
 	notifications, totalCount, unreadCount, err := s.notificationRepo.GetByUserID(ctx, userID, params)
 	if err != nil {
 		return nil, err
@@ -52,13 +434,77 @@ func (s *NotificationService) GetUserNotifications(ctx context.Context, userID u
 
 	totalPages := (totalCount + params.Limit - 1) / params.Limit
 
-	return &domain.NotificationListResponse{
+	resp := &domain.NotificationListResponse{
 		Notifications: notifications,
 		TotalCount:    totalCount,
 		UnreadCount:   unreadCount,
 		Page:          params.Page,
 		TotalPages:    totalPages,
-	}, nil
+	}
+	if params.Page < totalPages {
+		resp.NextCursor = notificationCursor(notifications)
+	}
+	return resp, nil
+}
+
+// getUserNotificationThreads lists the user's notifications grouped into
+// threads, paged like single notifications
+func (s *NotificationService) getUserNotificationThreads(ctx context.Context, userID uuid.UUID, params *domain.NotificationListParams) (*domain.NotificationListResponse, error) {
+	if params.Cursor != nil {
+		// Fetch one extra thread to tell whether there is another page
+		query := *params
+		query.Limit++
+		threads, _, unreadCount, err := s.notificationRepo.GetThreadsByUserID(ctx, userID, &query)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := &domain.NotificationListResponse{
+			Threads:     threads,
+			UnreadCount: unreadCount,
+		}
+		if len(threads) > params.Limit {
+			resp.Threads = threads[:params.Limit]
+			resp.NextCursor = notificationThreadCursor(resp.Threads)
+		}
+		return resp, nil
+	}
+
+	threads, totalCount, unreadCount, err := s.notificationRepo.GetThreadsByUserID(ctx, userID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := (totalCount + params.Limit - 1) / params.Limit
+
+	resp := &domain.NotificationListResponse{
+		Threads:     threads,
+		TotalCount:  totalCount,
+		UnreadCount: unreadCount,
+		Page:        params.Page,
+		TotalPages:  totalPages,
+	}
+	if params.Page < totalPages {
+		resp.NextCursor = notificationThreadCursor(threads)
+	}
+	return resp, nil
+}
+
+func notificationCursor(notifications []domain.Notification) string {
+	if len(notifications) == 0 {
+		return ""
+	}
+	last := notifications[len(notifications)-1]
+	return (&domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}).Encode()
+}
+
+// notificationThreadCursor seeks past the last thread's newest notification
+func notificationThreadCursor(threads []domain.NotificationThread) string {
+	if len(threads) == 0 {
+		return ""
+	}
+	last := threads[len(threads)-1].Latest
+	return (&domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}).Encode()
 }
 
 func (s *NotificationService) MarkAsRead(ctx context.Context, userID, notificationID uuid.UUID) error {
@@ -75,6 +521,13 @@ func (s *NotificationService) MarkAsRead(ctx context.Context, userID, notificati
 	return s.notificationRepo.MarkAsRead(ctx, notificationID)
 }
 
+// MarkThreadAsRead marks every notification in one of the user's threads
+// read. The thread is the auction's ID, or a notification's own ID for one
+// not about an auction.
+func (s *NotificationService) MarkThreadAsRead(ctx context.Context, userID, threadID uuid.UUID) error {
+	return s.notificationRepo.MarkThreadAsRead(ctx, userID, threadID)
+}
+
 func (s *NotificationService) MarkAllAsRead(ctx context.Context, userID uuid.UUID) error {
 	return s.notificationRepo.MarkAllAsRead(ctx, userID)
 }
@@ -86,35 +539,147 @@ func (s *NotificationService) GetUnreadCount(ctx context.Context, userID uuid.UU
 // Notification creators
 
 func (s *NotificationService) NotifyOutbid(ctx context.Context, userID uuid.UUID, auction *domain.Auction, newBidAmount decimal.Decimal) {
+	s.pushOutbid(ctx, userID, auction, newBidAmount)
+
 	notification := &domain.Notification{
 		UserID:    userID,
 		Type:      domain.NotificationOutbid,
 		Title:     fmt.Sprintf("You've been outbid on %s", auction.Title),
-		Message:   strPtr(fmt.Sprintf("A new bid of $%s has been placed. Place a higher bid to win!", newBidAmount.StringFixed(2))),
+		Message:   strPtr(fmt.Sprintf("A new bid of %s has been placed. Place a higher bid to win!", auction.Price(newBidAmount))),
 		AuctionID: &auction.ID,
+		Actions: []domain.NotificationAction{
+			s.links.bid(auction, "Bid again", auction.Price(auction.MinimumBidAbove(newBidAmount))),
+		},
 	}
 
-	_ = s.notificationRepo.Create(ctx, notification)
+	s.create(ctx, notification)
 
 	// Send email
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err == nil {
 		auctionURL := fmt.Sprintf("%s/auctions/%s", s.baseURL, auction.ID)
-		emailData := email.NewOutbidEmail(user.Email, auction.Title, "$"+newBidAmount.StringFixed(2), auctionURL)
-		_ = s.emailSender.Send(emailData)
+		emailData := email.NewOutbidEmail(user.Email, auction.Title, auction.Price(newBidAmount).String(), auctionURL)
+		s.sendEmail(ctx, userID, domain.NotificationOutbid, emailData)
 	}
 }
 
+// pushOutbid tells the outbid bidder at once over their personal WebSocket
+// connections, on whichever instances they are connected to, and as a web
+// push to their subscribed browsers. During their quiet hours the push is
+// dropped rather than held: it would be stale by morning, and the outbid
+// email is in their digest.
+func (s *NotificationService) pushOutbid(ctx context.Context, userID uuid.UUID, auction *domain.Auction, newBidAmount decimal.Decimal) {
+	if s.messageHub == nil && s.pushSvc == nil {
+		return
+	}
+	if !s.allows(ctx, userID, domain.NotificationOutbid, domain.NotificationChannelPush) {
+		return
+	}
+	if quietHours := s.quietHours(ctx, userID); quietHours != nil && quietHours.Holds(domain.NotificationOutbid, time.Now()) {
+		return
+	}
+
+	amount := auction.Price(newBidAmount)
+	minimumBid := auction.Price(auction.MinimumBidAbove(newBidAmount))
+	if s.messageHub != nil {
+		s.messageHub.SendToUser(userID, domain.MessageWSPayload{
+			Type: domain.MessageWSTypeOutbid,
+			Outbid: &domain.OutbidPush{
+				AuctionID:    auction.ID,
+				AuctionTitle: auction.Title,
+				Amount:       amount,
+				MinimumBid:   minimumBid,
+				EndTime:      auction.EndTime,
+			},
+		})
+	}
+
+	if s.pushSvc != nil {
+		payload := &domain.PushPayload{
+			Type:      domain.NotificationOutbid,
+			Title:     fmt.Sprintf("You've been outbid on %s", auction.Title),
+			Body:      fmt.Sprintf("A new bid of %s has been placed. Bid %s or more to take the lead.", amount, minimumBid),
+			URL:       s.links.bid(auction, "Bid again", minimumBid).URL,
+			Tag:       pushTag(domain.NotificationOutbid, auction.ID),
+			AuctionID: &auction.ID,
+		}
+		ttl := time.Until(auction.EndTime)
+		s.dispatch(ctx, func(ctx context.Context) {
+			s.pushSvc.Send(ctx, []uuid.UUID{userID}, payload, ttl, webpush.UrgencyHigh)
+		})
+	}
+}
+
+// webPush sends the push to the browsers the users subscribed, leaving out
+// those who switched push off for its type or are in their quiet hours
+func (s *NotificationService) webPush(ctx context.Context, userIDs []uuid.UUID, payload *domain.PushPayload, ttl time.Duration, urgency webpush.Urgency) {
+	if s.pushSvc == nil {
+		return
+	}
+
+	optedOut := s.optedOut(ctx, userIDs, payload.Type, domain.NotificationChannelPush)
+	now := time.Now()
+	recipients := make([]uuid.UUID, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if optedOut[userID] {
+			continue
+		}
+		if quietHours := s.quietHours(ctx, userID); quietHours != nil && quietHours.Holds(payload.Type, now) {
+			continue
+		}
+		recipients = append(recipients, userID)
+	}
+
+	s.pushSvc.Send(ctx, recipients, payload, ttl, urgency)
+}
+
+// pushTag groups the pushes of a type about one auction, so a newer one
+// replaces the last on screen
+func pushTag(notificationType domain.NotificationType, auctionID uuid.UUID) string {
+	return fmt.Sprintf("%s:%s", notificationType, auctionID)
+}
+
+// NotifySellerOfBid tells the seller about a bid that took their listing
+// from previousPrice, and whether it met the reserve, as far as the
+// listing's alert settings ask
+func (s *NotificationService) NotifySellerOfBid(ctx context.Context, auction *domain.Auction, bidAmount, previousPrice decimal.Decimal, bidderID uuid.UUID, reserveMet bool) {
+	alerts := s.sellerBidAlerts(ctx, auction.ID)
+
+	if alerts.NotifyBid(auction, previousPrice) {
+		s.NotifyNewBid(ctx, auction.SellerID, auction, bidAmount, bidderID)
+	}
+	if reserveMet && alerts.ReserveMet {
+		s.NotifySellerReserveMet(ctx, auction.SellerID, auction)
+	}
+}
+
+// sellerBidAlerts returns the listing's alert settings, falling back to the
+// defaults when it has none or they can't be read
+func (s *NotificationService) sellerBidAlerts(ctx context.Context, auctionID uuid.UUID) *domain.SellerBidAlerts {
+	if s.bidAlertRepo == nil {
+		return domain.DefaultSellerBidAlerts(auctionID)
+	}
+
+	alerts, err := s.bidAlertRepo.Get(ctx, auctionID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			log.Printf("Failed to get bid alerts for auction %s: %v", auctionID, err)
+		}
+		return domain.DefaultSellerBidAlerts(auctionID)
+	}
+	return alerts
+}
+
 func (s *NotificationService) NotifyNewBid(ctx context.Context, sellerID uuid.UUID, auction *domain.Auction, bidAmount decimal.Decimal, bidderID uuid.UUID) {
 	notification := &domain.Notification{
 		UserID:    sellerID,
 		Type:      domain.NotificationNewBid,
 		Title:     fmt.Sprintf("New bid on %s", auction.Title),
-		Message:   strPtr(fmt.Sprintf("A bid of $%s has been placed on your auction.", bidAmount.StringFixed(2))),
+		Message:   strPtr(fmt.Sprintf("A bid of %s has been placed on your auction.", auction.Price(bidAmount))),
 		AuctionID: &auction.ID,
 	}
 
-	_ = s.notificationRepo.Create(ctx, notification)
+	s.create(ctx, notification)
 
 	// Send email
 	seller, err := s.userRepo.GetByID(ctx, sellerID)
@@ -125,8 +690,8 @@ func (s *NotificationService) NotifyNewBid(ctx context.Context, sellerID uuid.UU
 			bidderName = bidder.Username
 		}
 		auctionURL := fmt.Sprintf("%s/auctions/%s", s.baseURL, auction.ID)
-		emailData := email.NewNewBidEmail(seller.Email, auction.Title, "$"+bidAmount.StringFixed(2), bidderName, auctionURL)
-		_ = s.emailSender.Send(emailData)
+		emailData := email.NewNewBidEmail(seller.Email, auction.Title, auction.Price(bidAmount).String(), bidderName, auctionURL)
+		s.sendEmail(ctx, sellerID, domain.NotificationNewBid, emailData)
 	}
 }
 
@@ -135,18 +700,37 @@ func (s *NotificationService) NotifyAuctionWon(ctx context.Context, winnerID uui
 		UserID:    winnerID,
 		Type:      domain.NotificationAuctionWon,
 		Title:     fmt.Sprintf("Congratulations! You won %s", auction.Title),
-		Message:   strPtr(fmt.Sprintf("You won the auction with a bid of $%s. The seller will contact you shortly.", auction.CurrentPrice.StringFixed(2))),
+		Message:   strPtr(fmt.Sprintf("You won the auction with a bid of %s. The seller will contact you shortly.", auction.Price(auction.CurrentPrice))),
 		AuctionID: &auction.ID,
+		Link:      strPtr(s.links.purchase(auction.ID, domain.NotificationActionPay)),
+		Actions: []domain.NotificationAction{
+			{Type: domain.NotificationActionPay, Label: "Pay now", URL: s.links.purchase(auction.ID, domain.NotificationActionPay)},
+			{Type: domain.NotificationActionRate, Label: "Rate seller", URL: s.links.purchase(auction.ID, domain.NotificationActionRate)},
+		},
 	}
 
-	_ = s.notificationRepo.Create(ctx, notification)
+	s.create(ctx, notification)
+
+	if s.pushSvc != nil {
+		payload := &domain.PushPayload{
+			Type:      domain.NotificationAuctionWon,
+			Title:     notification.Title,
+			Body:      *notification.Message,
+			URL:       *notification.Link,
+			Tag:       pushTag(domain.NotificationAuctionWon, auction.ID),
+			AuctionID: &auction.ID,
+		}
+		s.dispatch(ctx, func(ctx context.Context) {
+			s.webPush(ctx, []uuid.UUID{winnerID}, payload, auctionWonPushTTL, webpush.UrgencyNormal)
+		})
+	}
 
 	// Send email
 	user, err := s.userRepo.GetByID(ctx, winnerID)
 	if err == nil {
 		auctionURL := fmt.Sprintf("%s/auctions/%s", s.baseURL, auction.ID)
-		emailData := email.NewAuctionWonEmail(user.Email, auction.Title, "$"+auction.CurrentPrice.StringFixed(2), auctionURL)
-		_ = s.emailSender.Send(emailData)
+		emailData := email.NewAuctionWonEmail(user.Email, auction.Title, auction.Price(auction.CurrentPrice).String(), auctionURL)
+		s.sendEmail(ctx, winnerID, domain.NotificationAuctionWon, emailData)
 	}
 }
 
@@ -155,18 +739,18 @@ func (s *NotificationService) NotifyAuctionLost(ctx context.Context, userID uuid
 		UserID:    userID,
 		Type:      domain.NotificationAuctionLost,
 		Title:     fmt.Sprintf("Auction ended: %s", auction.Title),
-		Message:   strPtr(fmt.Sprintf("The auction ended with a winning bid of $%s. Better luck next time!", auction.CurrentPrice.StringFixed(2))),
+		Message:   strPtr(fmt.Sprintf("The auction ended with a winning bid of %s. Better luck next time!", auction.Price(auction.CurrentPrice))),
 		AuctionID: &auction.ID,
 	}
 
-	_ = s.notificationRepo.Create(ctx, notification)
+	s.create(ctx, notification)
 
 	// Send email
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err == nil {
 		auctionURL := fmt.Sprintf("%s/auctions/%s", s.baseURL, auction.ID)
-		emailData := email.NewAuctionLostEmail(user.Email, auction.Title, "$"+auction.CurrentPrice.StringFixed(2), auctionURL)
-		_ = s.emailSender.Send(emailData)
+		emailData := email.NewAuctionLostEmail(user.Email, auction.Title, auction.Price(auction.CurrentPrice).String(), auctionURL)
+		s.sendEmail(ctx, userID, domain.NotificationAuctionLost, emailData)
 	}
 }
 
@@ -175,13 +759,18 @@ func (s *NotificationService) NotifyAuctionSold(ctx context.Context, sellerID uu
 		UserID:    sellerID,
 		Type:      domain.NotificationAuctionSold,
 		Title:     fmt.Sprintf("Your auction sold: %s", auction.Title),
-		Message:   strPtr(fmt.Sprintf("Your item sold for $%s.", auction.CurrentPrice.StringFixed(2))),
+		Message:   strPtr(fmt.Sprintf("Your item sold for %s.", auction.Price(auction.CurrentPrice))),
 		AuctionID: &auction.ID,
+		Actions: []domain.NotificationAction{
+			{Type: domain.NotificationActionRate, Label: "Rate buyer", URL: s.links.sale(auction.ID, domain.NotificationActionRate)},
+		},
 	}
 
-	_ = s.notificationRepo.Create(ctx, notification)
+	s.create(ctx, notification)
 }
 
+// NotifyAuctionEnding fans out to every watcher in chunks on the notification
+// queue, so popular auctions don't hold up the caller
 func (s *NotificationService) NotifyAuctionEnding(ctx context.Context, auction *domain.Auction) {
 	// Get all watchers
 	watchers, err := s.watchlistRepo.GetWatchersForAuction(ctx, auction.ID)
@@ -189,23 +778,50 @@ func (s *NotificationService) NotifyAuctionEnding(ctx context.Context, auction *
 		return
 	}
 
+	snapshot := *auction
+	for start := 0; start < len(watchers); start += notificationFanoutChunk {
+		chunk := watchers[start:min(start+notificationFanoutChunk, len(watchers))]
+		s.dispatch(ctx, func(ctx context.Context) {
+			s.sendAuctionEnding(ctx, &snapshot, chunk)
+		})
+	}
+}
+
+func (s *NotificationService) sendAuctionEnding(ctx context.Context, auction *domain.Auction, watchers []uuid.UUID) {
+	bid := s.links.bid(auction, "Place a bid", auction.Price(auction.MinimumBid()))
 	notifications := make([]domain.Notification, 0, len(watchers))
 	for _, watcherID := range watchers {
 		notifications = append(notifications, domain.Notification{
 			UserID:    watcherID,
 			Type:      domain.NotificationAuctionEnding,
 			Title:     fmt.Sprintf("Auction ending soon: %s", auction.Title),
-			Message:   strPtr(fmt.Sprintf("Current bid: $%s. Don't miss out!", auction.CurrentPrice.StringFixed(2))),
+			Message:   strPtr(fmt.Sprintf("Current bid: %s. Don't miss out!", auction.Price(auction.CurrentPrice))),
 			AuctionID: &auction.ID,
+			Actions:   []domain.NotificationAction{bid},
 		})
 	}
 
 	if len(notifications) > 0 {
-		_ = s.notificationRepo.CreateBatch(ctx, notifications)
+		if err := s.createBatch(ctx, notifications); err != nil {
+			log.Printf("Error creating auction ending notifications for %s: %v", auction.ID, err)
+		}
 	}
 
-	// Send emails to watchers
+	s.webPush(ctx, watchers, &domain.PushPayload{
+		Type:      domain.NotificationAuctionEnding,
+		Title:     fmt.Sprintf("Auction ending soon: %s", auction.Title),
+		Body:      fmt.Sprintf("Current bid: %s. Don't miss out!", auction.Price(auction.CurrentPrice)),
+		URL:       bid.URL,
+		Tag:       pushTag(domain.NotificationAuctionEnding, auction.ID),
+		AuctionID: &auction.ID,
+	}, time.Until(auction.EndTime), webpush.UrgencyHigh)
+
+	// Send emails to watchers, but those with a digest find the auction there
+	digested := s.digestsWatchedEnding(ctx, watchers)
 	for _, watcherID := range watchers {
+		if digested[watcherID] {
+			continue
+		}
 		user, err := s.userRepo.GetByID(ctx, watcherID)
 		if err != nil {
 			continue
@@ -215,11 +831,425 @@ func (s *NotificationService) NotifyAuctionEnding(ctx context.Context, auction *
 			user.Email,
 			auction.Title,
 			"less than 1 hour",
-			"$"+auction.CurrentPrice.StringFixed(2),
+			auction.Price(auction.CurrentPrice).String(),
 			auctionURL,
 		)
-		_ = s.emailSender.Send(emailData)
+		s.sendEmail(ctx, watcherID, domain.NotificationAuctionEnding, emailData)
+	}
+}
+
+func (s *NotificationService) NotifyUnpaidOrderCancelled(ctx context.Context, order *domain.Order, auction *domain.Auction) {
+	buyerNotification := &domain.Notification{
+		UserID:    order.BuyerID,
+		Type:      domain.NotificationOrderCancelled,
+		Title:     fmt.Sprintf("Order cancelled: %s", auction.Title),
+		Message:   strPtr("Your order was cancelled because payment was not received in time. An unpaid item strike has been recorded on your account."),
+		AuctionID: &auction.ID,
+	}
+	s.create(ctx, buyerNotification)
+
+	sellerNotification := &domain.Notification{
+		UserID:    order.SellerID,
+		Type:      domain.NotificationOrderCancelled,
+		Title:     fmt.Sprintf("Buyer did not pay: %s", auction.Title),
+		Message:   strPtr(fmt.Sprintf("The order for %s was cancelled for non-payment.", auction.Price(order.Amount))),
+		AuctionID: &auction.ID,
+	}
+	s.create(ctx, sellerNotification)
+}
+
+func (s *NotificationService) NotifySecondChanceOffer(ctx context.Context, offer *domain.SecondChanceOffer, auction *domain.Auction) {
+	notification := &domain.Notification{
+		UserID:    offer.BidderID,
+		Type:      domain.NotificationSecondChanceOffer,
+		Title:     fmt.Sprintf("Second chance to buy %s", auction.Title),
+		Message:   strPtr(fmt.Sprintf("The winner did not pay. You can buy this item for %s.", auction.Price(offer.Amount))),
+		AuctionID: &auction.ID,
+	}
+
+	s.create(ctx, notification)
+
+	// Send email
+	user, err := s.userRepo.GetByID(ctx, offer.BidderID)
+	if err == nil {
+		auctionURL := fmt.Sprintf("%s/auctions/%s", s.baseURL, auction.ID)
+		expiresIn := fmt.Sprintf("%d hours", int(time.Until(offer.ExpiresAt).Hours()))
+		emailData := email.NewSecondChanceOfferEmail(user.Email, auction.Title, auction.Price(offer.Amount).String(), expiresIn, auctionURL)
+		s.sendEmail(ctx, offer.BidderID, domain.NotificationSecondChanceOffer, emailData)
+	}
+}
+
+func (s *NotificationService) NotifyAuctionRelisted(ctx context.Context, sellerID uuid.UUID, relisted *domain.Auction) {
+	notification := &domain.Notification{
+		UserID:    sellerID,
+		Type:      domain.NotificationAuctionRelisted,
+		Title:     fmt.Sprintf("Relisted: %s", relisted.Title),
+		Message:   strPtr("The sale fell through, so your item was relisted automatically."),
+		AuctionID: &relisted.ID,
+	}
+
+	s.create(ctx, notification)
+}
+
+func (s *NotificationService) NotifyAuctionInvite(ctx context.Context, userID uuid.UUID, auction *domain.Auction) {
+	notification := &domain.Notification{
+		UserID:    userID,
+		Type:      domain.NotificationAuctionInvite,
+		Title:     fmt.Sprintf("You're invited: %s", auction.Title),
+		Message:   strPtr("The seller invited you to their private auction."),
+		AuctionID: &auction.ID,
+	}
+
+	s.create(ctx, notification)
+}
+
+func (s *NotificationService) NotifyBidApprovalRequested(ctx context.Context, sellerID uuid.UUID, auction *domain.Auction, username string) {
+	notification := &domain.Notification{
+		UserID:    sellerID,
+		Type:      domain.NotificationBidApprovalRequested,
+		Title:     fmt.Sprintf("Approval requested: %s", auction.Title),
+		Message:   strPtr(fmt.Sprintf("%s asked to bid on your auction.", username)),
+		AuctionID: &auction.ID,
+	}
+
+	s.create(ctx, notification)
+}
+
+func (s *NotificationService) NotifyBidApprovalDecided(ctx context.Context, approval *domain.BidApproval, auction *domain.Auction) {
+	title := fmt.Sprintf("Approved to bid: %s", auction.Title)
+	message := "The seller approved you to bid on their auction."
+	if approval.Status == domain.BidApprovalDenied {
+		title = fmt.Sprintf("Bid request declined: %s", auction.Title)
+		message = "The seller declined your request to bid on their auction."
+	}
+	if approval.Note != nil && *approval.Note != "" {
+		message += " Note: " + *approval.Note
+	}
+
+	notification := &domain.Notification{
+		UserID:    approval.UserID,
+		Type:      domain.NotificationBidApprovalDecided,
+		Title:     title,
+		Message:   strPtr(message),
+		AuctionID: &auction.ID,
+	}
+
+	s.create(ctx, notification)
+}
+
+func (s *NotificationService) NotifyAuctionCancelled(ctx context.Context, userID uuid.UUID, auction *domain.Auction, reason string) {
+	notification := &domain.Notification{
+		UserID:    userID,
+		Type:      domain.NotificationAuctionCancelled,
+		Title:     fmt.Sprintf("Auction cancelled: %s", auction.Title),
+		Message:   strPtr(fmt.Sprintf("This auction was ended early without a sale. Reason: %s", reason)),
+		AuctionID: &auction.ID,
 	}
+
+	s.create(ctx, notification)
+}
+
+// NotifySellerCancelled tells the auction's bidders and watchers that the
+// seller withdrew the listing and all bids were voided
+func (s *NotificationService) NotifySellerCancelled(ctx context.Context, auction *domain.Auction, bidders []uuid.UUID, reason string) {
+	watchers, err := s.watchlistRepo.GetWatchersForAuction(ctx, auction.ID)
+	if err != nil {
+		log.Printf("Error getting watchers for cancelled auction %s: %v", auction.ID, err)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(bidders)+len(watchers))
+	recipients := make([]uuid.UUID, 0, len(bidders)+len(watchers))
+	for _, userID := range append(append([]uuid.UUID{}, bidders...), watchers...) {
+		if userID == auction.SellerID || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		recipients = append(recipients, userID)
+	}
+
+	snapshot := *auction
+	for start := 0; start < len(recipients); start += notificationFanoutChunk {
+		chunk := recipients[start:min(start+notificationFanoutChunk, len(recipients))]
+		s.dispatch(ctx, func(ctx context.Context) {
+			s.sendSellerCancelled(ctx, &snapshot, chunk, reason)
+		})
+	}
+}
+
+func (s *NotificationService) sendSellerCancelled(ctx context.Context, auction *domain.Auction, recipients []uuid.UUID, reason string) {
+	notifications := make([]domain.Notification, 0, len(recipients))
+	for _, userID := range recipients {
+		notifications = append(notifications, domain.Notification{
+			UserID:    userID,
+			Type:      domain.NotificationAuctionCancelled,
+			Title:     fmt.Sprintf("Auction cancelled: %s", auction.Title),
+			Message:   strPtr(fmt.Sprintf("The seller cancelled this auction and any bids were voided. Reason: %s", reason)),
+			AuctionID: &auction.ID,
+		})
+	}
+
+	if len(notifications) > 0 {
+		if err := s.createBatch(ctx, notifications); err != nil {
+			log.Printf("Error creating auction cancelled notifications for %s: %v", auction.ID, err)
+		}
+	}
+}
+
+// NotifyPriceDrop tells the listing's watchers that its buy-now price was
+// marked down
+func (s *NotificationService) NotifyPriceDrop(ctx context.Context, auction *domain.Auction, oldPrice decimal.Decimal) {
+	watchers, err := s.watchlistRepo.GetWatchersForAuction(ctx, auction.ID)
+	if err != nil {
+		log.Printf("Error getting watchers for price drop on %s: %v", auction.ID, err)
+		return
+	}
+
+	snapshot := *auction
+	for start := 0; start < len(watchers); start += notificationFanoutChunk {
+		chunk := watchers[start:min(start+notificationFanoutChunk, len(watchers))]
+		s.dispatch(ctx, func(ctx context.Context) {
+			s.sendPriceDrop(ctx, &snapshot, chunk, oldPrice)
+		})
+	}
+}
+
+func (s *NotificationService) sendPriceDrop(ctx context.Context, auction *domain.Auction, watchers []uuid.UUID, oldPrice decimal.Decimal) {
+	buyNow := s.links.buyNow(auction)
+	notifications := make([]domain.Notification, 0, len(watchers))
+	for _, watcherID := range watchers {
+		if watcherID == auction.SellerID {
+			continue
+		}
+		notifications = append(notifications, domain.Notification{
+			UserID:    watcherID,
+			Type:      domain.NotificationPriceDrop,
+			Title:     fmt.Sprintf("Price drop: %s", auction.Title),
+			Message:   strPtr(fmt.Sprintf("Buy it now for %s, down from %s.", auction.Price(*auction.BuyNowPrice), auction.Price(oldPrice))),
+			AuctionID: &auction.ID,
+			Actions:   []domain.NotificationAction{buyNow},
+		})
+	}
+
+	if len(notifications) > 0 {
+		if err := s.createBatch(ctx, notifications); err != nil {
+			log.Printf("Error creating price drop notifications for %s: %v", auction.ID, err)
+		}
+	}
+}
+
+func (s *NotificationService) NotifyBidCancelled(ctx context.Context, bidderID uuid.UUID, auction *domain.Auction, amount decimal.Decimal, reason string) {
+	notification := &domain.Notification{
+		UserID:    bidderID,
+		Type:      domain.NotificationBidCancelled,
+		Title:     fmt.Sprintf("Bid cancelled: %s", auction.Title),
+		Message:   strPtr(fmt.Sprintf("The seller cancelled your %s bid. Reason: %s", auction.Price(amount), reason)),
+		AuctionID: &auction.ID,
+	}
+
+	s.create(ctx, notification)
+}
+
+func (s *NotificationService) NotifyReserveMet(ctx context.Context, bidderID uuid.UUID, auction *domain.Auction) {
+	notification := &domain.Notification{
+		UserID:    bidderID,
+		Type:      domain.NotificationReserveMet,
+		Title:     fmt.Sprintf("Reserve met: %s", auction.Title),
+		Message:   strPtr(fmt.Sprintf("The seller lowered the reserve. Your %s bid now meets it.", auction.Price(auction.CurrentPrice))),
+		AuctionID: &auction.ID,
+	}
+
+	s.create(ctx, notification)
+}
+
+func (s *NotificationService) NotifySellerReserveMet(ctx context.Context, sellerID uuid.UUID, auction *domain.Auction) {
+	notification := &domain.Notification{
+		UserID:    sellerID,
+		Type:      domain.NotificationReserveMet,
+		Title:     fmt.Sprintf("Reserve met: %s", auction.Title),
+		Message:   strPtr(fmt.Sprintf("Bidding reached %s, so your reserve price has been met.", auction.Price(auction.CurrentPrice))),
+		AuctionID: &auction.ID,
+	}
+
+	s.create(ctx, notification)
+}
+
+func (s *NotificationService) NotifySnipeFailed(ctx context.Context, userID uuid.UUID, auction *domain.Auction, reason string) {
+	notification := &domain.Notification{
+		UserID:    userID,
+		Type:      domain.NotificationSnipeFailed,
+		Title:     fmt.Sprintf("Snipe not placed: %s", auction.Title),
+		Message:   strPtr(fmt.Sprintf("Your scheduled bid could not be placed: %s", reason)),
+		AuctionID: &auction.ID,
+	}
+
+	s.create(ctx, notification)
+}
+
+func (s *NotificationService) NotifyOfferReceived(ctx context.Context, sellerID uuid.UUID, offer *domain.Offer, auction *domain.Auction) {
+	notification := &domain.Notification{
+		UserID:    sellerID,
+		Type:      domain.NotificationOfferReceived,
+		Title:     fmt.Sprintf("New offer: %s", auction.Title),
+		Message:   strPtr(fmt.Sprintf("A buyer offered %s. Accept, decline or counter it before it expires.", auction.Price(offer.Amount))),
+		AuctionID: &auction.ID,
+		Link:      strPtr(s.links.sale(auction.ID, domain.NotificationActionOffer)),
+		Actions: []domain.NotificationAction{
+			{Type: domain.NotificationActionOffer, Label: "Review offer", URL: s.links.sale(auction.ID, domain.NotificationActionOffer)},
+		},
+	}
+
+	s.create(ctx, notification)
+}
+
+func (s *NotificationService) NotifyOfferCountered(ctx context.Context, offer *domain.Offer, auction *domain.Auction) {
+	notification := &domain.Notification{
+		UserID:    offer.BuyerID,
+		Type:      domain.NotificationOfferCountered,
+		Title:     fmt.Sprintf("Counter offer: %s", auction.Title),
+		Message:   strPtr(fmt.Sprintf("The seller countered your %s offer with %s.", auction.Price(offer.Amount), auction.Price(offer.Price()))),
+		AuctionID: &auction.ID,
+	}
+
+	s.create(ctx, notification)
+}
+
+// NotifyOfferDeclined tells the other side of the negotiation that userID's
+// offer or counter was turned down
+func (s *NotificationService) NotifyOfferDeclined(ctx context.Context, userID uuid.UUID, offer *domain.Offer, auction *domain.Auction) {
+	notification := &domain.Notification{
+		UserID:    userID,
+		Type:      domain.NotificationOfferDeclined,
+		Title:     fmt.Sprintf("Offer declined: %s", auction.Title),
+		Message:   strPtr(fmt.Sprintf("The %s offer was declined.", auction.Price(offer.Price()))),
+		AuctionID: &auction.ID,
+	}
+
+	s.create(ctx, notification)
+}
+
+func (s *NotificationService) NotifyOfferExpired(ctx context.Context, offer *domain.Offer, auction *domain.Auction) {
+	notification := &domain.Notification{
+		UserID:    offer.BuyerID,
+		Type:      domain.NotificationOfferExpired,
+		Title:     "Offer expired",
+		Message:   strPtr(fmt.Sprintf("Your %s offer expired without being accepted.", auction.Price(offer.Price()))),
+		AuctionID: &offer.AuctionID,
+	}
+
+	s.create(ctx, notification)
+}
+
+func (s *NotificationService) NotifyDisputeOpened(ctx context.Context, userID uuid.UUID, dispute *domain.Dispute, auction *domain.Auction) {
+	notification := &domain.Notification{
+		UserID:    userID,
+		Type:      domain.NotificationDisputeOpened,
+		Title:     fmt.Sprintf("Dispute opened: %s", auction.Title),
+		Message:   strPtr("A dispute was opened on this order. Add your side and any evidence before an admin reviews it."),
+		AuctionID: &auction.ID,
+	}
+
+	s.create(ctx, notification)
+}
+
+func (s *NotificationService) NotifyDisputeMessage(ctx context.Context, userID uuid.UUID, dispute *domain.Dispute, auction *domain.Auction) {
+	notification := &domain.Notification{
+		UserID:    userID,
+		Type:      domain.NotificationDisputeMessage,
+		Title:     fmt.Sprintf("New dispute message: %s", auction.Title),
+		Message:   strPtr("There is a new message on your dispute."),
+		AuctionID: &auction.ID,
+	}
+
+	s.create(ctx, notification)
+}
+
+func (s *NotificationService) NotifyEscrowReleased(ctx context.Context, order *domain.Order, amount decimal.Decimal) {
+	notification := &domain.Notification{
+		UserID:    order.SellerID,
+		Type:      domain.NotificationEscrowReleased,
+		Title:     "Payment released",
+		Message:   strPtr(fmt.Sprintf("%s held for your sale has been added to your balance.", domain.NewMoney(amount, domain.DefaultCurrency))),
+		AuctionID: &order.AuctionID,
+	}
+
+	s.create(ctx, notification)
+}
+
+// NotifyPayout tells a seller their payout has moved to its current status
+func (s *NotificationService) NotifyPayout(ctx context.Context, payout *domain.Payout) {
+	amount := domain.NewMoney(payout.Amount, domain.DefaultCurrency)
+	notification := &domain.Notification{UserID: payout.UserID}
+
+	switch payout.Status {
+	case domain.PayoutRequested:
+		notification.Type = domain.NotificationPayoutRequested
+		notification.Title = "Payout requested"
+		notification.Message = strPtr(fmt.Sprintf("We received your request to withdraw %s. It will be reviewed shortly.", amount))
+	case domain.PayoutApproved:
+		notification.Type = domain.NotificationPayoutApproved
+		notification.Title = "Payout approved"
+		notification.Message = strPtr(fmt.Sprintf("Your payout of %s was approved and is on its way.", amount))
+	case domain.PayoutCompleted:
+		notification.Type = domain.NotificationPayoutCompleted
+		notification.Title = "Payout sent"
+		notification.Message = strPtr(fmt.Sprintf("Your payout of %s has been sent.", amount))
+	case domain.PayoutRejected:
+		notification.Type = domain.NotificationPayoutRejected
+		notification.Title = "Payout rejected"
+		message := fmt.Sprintf("Your payout of %s was rejected and returned to your balance.", amount)
+		if payout.RejectionReason != nil {
+			message += " Reason: " + *payout.RejectionReason
+		}
+		notification.Message = strPtr(message)
+	default:
+		return
+	}
+
+	s.create(ctx, notification)
+}
+
+func (s *NotificationService) NotifyDisputeResolved(ctx context.Context, userID uuid.UUID, dispute *domain.Dispute, auction *domain.Auction) {
+	message := "The dispute was resolved in favour of the seller. No refund will be issued."
+	if dispute.RefundAmount != nil {
+		message = fmt.Sprintf("The dispute was resolved with a refund of %s.", auction.Price(*dispute.RefundAmount))
+	}
+
+	notification := &domain.Notification{
+		UserID:    userID,
+		Type:      domain.NotificationDisputeResolved,
+		Title:     fmt.Sprintf("Dispute resolved: %s", auction.Title),
+		Message:   strPtr(message),
+		AuctionID: &auction.ID,
+	}
+
+	s.create(ctx, notification)
+}
+
+// NotifyTranscriptViewed tells a participant that an admin read their
+// conversation while investigating a dispute or report on the auction
+func (s *NotificationService) NotifyTranscriptViewed(ctx context.Context, userID uuid.UUID, auction *domain.Auction, otherUsername string) {
+	notification := &domain.Notification{
+		UserID:    userID,
+		Type:      domain.NotificationTranscriptViewed,
+		Title:     fmt.Sprintf("Conversation reviewed: %s", auction.Title),
+		Message:   strPtr(fmt.Sprintf("An admin reviewed your messages with %s as part of an investigation into this auction.", otherUsername)),
+		AuctionID: &auction.ID,
+	}
+
+	s.create(ctx, notification)
+}
+
+// NotifySuspiciousLogin warns a user that their account was logged into
+// from a different country shortly after their previous login
+func (s *NotificationService) NotifySuspiciousLogin(ctx context.Context, userID uuid.UUID, previousCountry, country string) {
+	notification := &domain.Notification{
+		UserID:  userID,
+		Type:    domain.NotificationSuspiciousLogin,
+		Title:   "New login from another country",
+		Message: strPtr(fmt.Sprintf("Your account was just logged into from %s, shortly after a login from %s. If this wasn't you, change your password and enable single-session mode.", country, previousCountry)),
+	}
+
+	s.create(ctx, notification)
 }
 
 func strPtr(s string) *string {
@@ -0,0 +1,492 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/auction-cards/backend/internal/cache"
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/auction-cards/backend/internal/websocket"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// OfferResponseWindow is how long the other side has to answer an offer
+	// or counter; answers are never due after the auction ends
+	OfferResponseWindow = 48 * time.Hour
+
+	// MaxOffersPerAuction bounds how often one buyer may make an offer on an
+	// auction, so sellers aren't worn down by a stream of small raises
+	MaxOffersPerAuction = 3
+
+	offerSweepBatchSize = 100
+)
+
+type OfferService struct {
+	offerRepo       repository.OfferRepository
+	auctionRepo     repository.AuctionRepository
+	bidRepo         repository.BidRepository
+	auditRepo       repository.AuditLogRepository
+	txManager       repository.TxManager
+	bidSvc          *BidService
+	orderSvc        *OrderService
+	notificationSvc *NotificationService
+	webhookSvc      *WebhookService
+	messageHub      *websocket.MessageHub
+	cache           *cache.RedisCache
+}
+
+func NewOfferService(
+	offerRepo repository.OfferRepository,
+	auctionRepo repository.AuctionRepository,
+	bidRepo repository.BidRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager repository.TxManager,
+	bidSvc *BidService,
+	orderSvc *OrderService,
+	notificationSvc *NotificationService,
+	webhookSvc *WebhookService,
+	messageHub *websocket.MessageHub,
+	cache *cache.RedisCache,
+) *OfferService {
+	return &OfferService{
+		offerRepo:       offerRepo,
+		auctionRepo:     auctionRepo,
+		bidRepo:         bidRepo,
+		auditRepo:       auditRepo,
+		txManager:       txManager,
+		bidSvc:          bidSvc,
+		orderSvc:        orderSvc,
+		notificationSvc: notificationSvc,
+		webhookSvc:      webhookSvc,
+		messageHub:      messageHub,
+		cache:           cache,
+	}
+}
+
+// MakeOffer records a buyer's offer on an auction that takes offers. Offers
+// must beat the current price and stay below an available buy-now price.
+func (s *OfferService) MakeOffer(ctx context.Context, auctionID, buyerID uuid.UUID, req *domain.MakeOfferRequest) (*domain.Offer, error) {
+	amount, err := domain.ParseAmount(req.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.bidSvc != nil {
+		if err := s.bidSvc.checkCanSee(ctx, auction, buyerID, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := checkOfferableAuction(auction); err != nil {
+		return nil, err
+	}
+
+	if !auction.AcceptsOffers {
+		return nil, domain.ErrOffersDisabled
+	}
+
+	if auction.SellerID == buyerID {
+		return nil, domain.ErrSelfBidding
+	}
+
+	if s.bidSvc != nil {
+		if err := s.bidSvc.checkSellerAcceptingBids(ctx, auction.SellerID); err != nil {
+			return nil, err
+		}
+		if err := s.bidSvc.checkNotBlocked(ctx, auction.SellerID, buyerID); err != nil {
+			return nil, err
+		}
+		if err := s.bidSvc.checkBidApproval(ctx, auction, buyerID); err != nil {
+			return nil, err
+		}
+	}
+
+	if !offerBeatsPrice(auction, amount) {
+		return nil, domain.ErrOfferTooLow
+	}
+
+	if auction.CanBuyNow() && amount.GreaterThanOrEqual(*auction.BuyNowPrice) {
+		return nil, domain.ErrOfferAboveBuyNow
+	}
+
+	count, err := s.offerRepo.CountByBuyer(ctx, auctionID, buyerID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxOffersPerAuction {
+		return nil, domain.ErrOfferLimit
+	}
+
+	offer := &domain.Offer{
+		AuctionID: auctionID,
+		BuyerID:   buyerID,
+		Amount:    amount,
+		Message:   req.Message,
+		Status:    domain.OfferStatusPending,
+		ExpiresAt: offerExpiry(auction),
+	}
+	if err := s.offerRepo.Create(ctx, offer); err != nil {
+		return nil, err
+	}
+
+	s.publishOffer(offer, auction.SellerID)
+
+	if s.notificationSvc != nil {
+		go s.notificationSvc.NotifyOfferReceived(context.Background(), auction.SellerID, offer, auction)
+	}
+
+	return offer, nil
+}
+
+// ListForAuction returns every offer on the auction to its seller, and only
+// their own to anyone else
+func (s *OfferService) ListForAuction(ctx context.Context, auctionID, userID uuid.UUID) ([]domain.Offer, error) {
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.SellerID == userID {
+		return s.offerRepo.GetByAuction(ctx, auctionID, nil)
+	}
+
+	return s.offerRepo.GetByAuction(ctx, auctionID, &userID)
+}
+
+func (s *OfferService) GetMyOffers(ctx context.Context, buyerID uuid.UUID, status *domain.OfferStatus) ([]domain.Offer, error) {
+	offers, err := s.offerRepo.GetByBuyer(ctx, buyerID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range offers {
+		offers[i].Auction, _ = s.auctionRepo.GetByID(ctx, offers[i].AuctionID)
+	}
+
+	return offers, nil
+}
+
+// Counter answers a pending offer with a higher price for the buyer to
+// accept or decline
+func (s *OfferService) Counter(ctx context.Context, offerID, sellerID uuid.UUID, req *domain.CounterOfferRequest) (*domain.Offer, error) {
+	amount, err := domain.ParseAmount(req.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	offer, auction, err := s.getOffer(ctx, offerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.SellerID != sellerID {
+		return nil, domain.ErrForbidden
+	}
+
+	if offer.Status != domain.OfferStatusPending || !offer.IsOpen() {
+		return nil, domain.ErrOfferNotPending
+	}
+
+	if err := checkOfferableAuction(auction); err != nil {
+		return nil, err
+	}
+
+	if !amount.GreaterThan(offer.Amount) {
+		return nil, domain.ErrCounterTooLow
+	}
+
+	if auction.CanBuyNow() && amount.GreaterThanOrEqual(*auction.BuyNowPrice) {
+		return nil, domain.ErrOfferAboveBuyNow
+	}
+
+	offer.CounterAmount = &amount
+	offer.ExpiresAt = offerExpiry(auction)
+	if err := s.offerRepo.Counter(ctx, offer); err != nil {
+		return nil, err
+	}
+
+	s.publishOffer(offer, auction.SellerID)
+
+	if s.notificationSvc != nil {
+		go s.notificationSvc.NotifyOfferCountered(context.Background(), offer, auction)
+	}
+
+	return offer, nil
+}
+
+// Decline turns down an offer. The seller declines pending offers; the
+// buyer may withdraw their own or decline the seller's counter.
+func (s *OfferService) Decline(ctx context.Context, offerID, userID uuid.UUID) (*domain.Offer, error) {
+	offer, auction, err := s.getOffer(ctx, offerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var notifyID uuid.UUID
+	switch userID {
+	case auction.SellerID:
+		if offer.Status != domain.OfferStatusPending {
+			return nil, domain.ErrOfferNotPending
+		}
+		notifyID = offer.BuyerID
+	case offer.BuyerID:
+		notifyID = auction.SellerID
+	default:
+		return nil, domain.ErrForbidden
+	}
+
+	from := offer.Status
+	offer.Status = domain.OfferStatusDeclined
+	if err := s.offerRepo.Respond(ctx, offer, from); err != nil {
+		return nil, err
+	}
+
+	s.publishOffer(offer, auction.SellerID)
+
+	if s.notificationSvc != nil {
+		go s.notificationSvc.NotifyOfferDeclined(context.Background(), notifyID, offer, auction)
+	}
+
+	return offer, nil
+}
+
+// Accept sells the item at the offer's price. The seller accepts pending
+// offers and the buyer accepts counters. Like Buy Now, the auction ends at
+// once with the offer recorded as the winning bid; every other open offer
+// on it expires.
+func (s *OfferService) Accept(ctx context.Context, offerID, userID uuid.UUID) (*domain.Offer, error) {
+	offer, auction, err := s.getOffer(ctx, offerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var from domain.OfferStatus
+	switch userID {
+	case auction.SellerID:
+		from = domain.OfferStatusPending
+	case offer.BuyerID:
+		from = domain.OfferStatusCountered
+	default:
+		return nil, domain.ErrForbidden
+	}
+
+	if offer.Status != from || !offer.IsOpen() {
+		return nil, domain.ErrOfferNotPending
+	}
+
+	if err := checkOfferableAuction(auction); err != nil {
+		return nil, err
+	}
+
+	// Bids may have overtaken the offer since it was made
+	price := offer.Price()
+	if !offerBeatsPrice(auction, price) {
+		return nil, domain.ErrOfferTooLow
+	}
+
+	expectedVersion := auction.Version
+	var expired []domain.Offer
+
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		bid := &domain.Bid{
+			ID:        uuid.New(),
+			AuctionID: auction.ID,
+			BidderID:  offer.BuyerID,
+			Amount:    price,
+			CreatedAt: time.Now(),
+		}
+		if err := s.bidRepo.Create(txCtx, bid); err != nil {
+			return err
+		}
+
+		offer.Status = domain.OfferStatusAccepted
+		offer.BidID = &bid.ID
+		if err := s.offerRepo.Respond(txCtx, offer, from); err != nil {
+			return err
+		}
+
+		// The version check fails if a bid landed since the auction was read
+		auction.CurrentPrice = price
+		auction.BidCount++
+		auction.EndTime = time.Now()
+		if err := s.auctionRepo.UpdateWithVersion(txCtx, auction, expectedVersion); err != nil {
+			return err
+		}
+
+		auction.Status = domain.AuctionStatusCompleted
+		auction.WinnerID = &offer.BuyerID
+		auction.WinningBidID = &bid.ID
+		if err := s.auctionRepo.UpdateStatus(txCtx, auction.ID, auction.Status, auction.WinnerID, auction.WinningBidID); err != nil {
+			return err
+		}
+
+		var err error
+		expired, err = s.offerRepo.ExpireOpen(txCtx, auction.ID)
+		if err != nil {
+			return err
+		}
+
+		if s.orderSvc != nil {
+			if _, err := s.orderSvc.CreateForWinner(txCtx, auction, offer.BuyerID, &bid.ID); err != nil {
+				return err
+			}
+		}
+
+		return s.auditRepo.Create(txCtx, &domain.AuditLog{
+			ActorID:    &userID,
+			Action:     domain.AuditActionOfferAccept,
+			EntityType: domain.AuditEntityAuction,
+			EntityID:   &auction.ID,
+			Metadata: map[string]interface{}{
+				"offer_id":       offer.ID,
+				"buyer_id":       offer.BuyerID,
+				"bid_id":         bid.ID,
+				"amount":         price.StringFixed(2),
+				"countered":      offer.CounterAmount != nil,
+				"expired_offers": len(expired),
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateAuction(ctx, s.cache, auction.ID)
+
+	if s.cache != nil {
+		message := domain.WSMessage{
+			Type: domain.WSMessageAuctionEnded,
+			Payload: domain.WSAuctionEndedPayload{
+				AuctionID:  auction.ID,
+				WinnerID:   auction.WinnerID,
+				FinalPrice: auction.CurrentPrice,
+				Status:     auction.Status,
+			},
+		}
+		_ = s.cache.Publish(ctx, cache.AuctionChannel(auction.ID), message)
+	}
+
+	s.publishOffer(offer, auction.SellerID)
+	for i := range expired {
+		s.publishOffer(&expired[i], auction.SellerID)
+	}
+
+	if s.webhookSvc != nil {
+		s.webhookSvc.PublishAuctionEnded(ctx, auction)
+	}
+
+	if s.notificationSvc != nil {
+		sold := *auction
+		go func() {
+			s.notificationSvc.NotifyAuctionWon(context.Background(), offer.BuyerID, &sold)
+			s.notificationSvc.NotifyAuctionSold(context.Background(), sold.SellerID, &sold, offer.BuyerID)
+			for i := range expired {
+				s.notificationSvc.NotifyOfferExpired(context.Background(), &expired[i], &sold)
+			}
+		}()
+	}
+
+	response := *auction
+	response.SetReserveStatus(false)
+	offer.Auction = &response
+
+	return offer, nil
+}
+
+// ProcessExpired closes out offers nobody answered in time and those left
+// open when their auction ended
+func (s *OfferService) ProcessExpired(ctx context.Context) {
+	for {
+		offers, err := s.offerRepo.ExpireStale(ctx, time.Now(), offerSweepBatchSize)
+		if err != nil {
+			log.Printf("Error expiring offers: %v", err)
+			return
+		}
+
+		for i := range offers {
+			offer := &offers[i]
+			auction, err := s.auctionRepo.GetByID(ctx, offer.AuctionID)
+			if err != nil {
+				log.Printf("Error loading auction %s for expired offer %s: %v", offer.AuctionID, offer.ID, err)
+				continue
+			}
+			s.publishOffer(offer, auction.SellerID)
+			if s.notificationSvc != nil {
+				s.notificationSvc.NotifyOfferExpired(ctx, offer, auction)
+			}
+		}
+
+		if len(offers) < offerSweepBatchSize {
+			return
+		}
+	}
+}
+
+func (s *OfferService) getOffer(ctx context.Context, offerID uuid.UUID) (*domain.Offer, *domain.Auction, error) {
+	offer, err := s.offerRepo.GetByID(ctx, offerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	auction, err := s.auctionRepo.GetByID(ctx, offer.AuctionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return offer, auction, nil
+}
+
+// publishOffer pushes the offer's new state to the buyer and the seller
+// over their personal WebSocket connections. Amounts are private to the
+// two of them, so nothing goes to the public auction channel.
+func (s *OfferService) publishOffer(offer *domain.Offer, sellerID uuid.UUID) {
+	if s.messageHub == nil {
+		return
+	}
+
+	update := *offer
+	update.Auction = nil
+	payload := domain.MessageWSPayload{
+		Type:  domain.MessageWSTypeOfferUpdated,
+		Offer: &update,
+	}
+	s.messageHub.SendToUser(offer.BuyerID, payload)
+	s.messageHub.SendToUser(sellerID, payload)
+}
+
+func checkOfferableAuction(auction *domain.Auction) error {
+	if auction.Status != domain.AuctionStatusActive {
+		return domain.ErrAuctionNotActive
+	}
+	if time.Now().After(auction.EndTime) {
+		return domain.ErrAuctionEnded
+	}
+	return nil
+}
+
+// offerBeatsPrice reports whether amount could win the auction outright:
+// above the high bid, or at least the starting price before any bids
+func offerBeatsPrice(auction *domain.Auction, amount decimal.Decimal) bool {
+	if auction.BidCount > 0 {
+		return amount.GreaterThan(auction.CurrentPrice)
+	}
+	return amount.GreaterThanOrEqual(auction.StartingPrice)
+}
+
+// offerExpiry gives the other side the response window, cut short by the
+// end of the auction
+func offerExpiry(auction *domain.Auction) time.Time {
+	expires := time.Now().Add(OfferResponseWindow)
+	if auction.EndTime.Before(expires) {
+		return auction.EndTime
+	}
+	return expires
+}
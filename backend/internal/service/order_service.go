@@ -0,0 +1,470 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/auction-cards/backend/internal/cache"
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Maximum number of overdue orders or expired offers handled per scheduler tick
+const orderSweepBatchSize = 100
+
+type OrderService struct {
+	orderRepo          repository.OrderRepository
+	offerRepo          repository.SecondChanceOfferRepository
+	auctionRepo        repository.AuctionRepository
+	auctionImageRepo   repository.AuctionImageRepository
+	shippingRepo       repository.AuctionShippingRepository
+	bidRepo            repository.BidRepository
+	txManager          repository.TxManager
+	notificationSvc    *NotificationService
+	webhookSvc         *WebhookService
+	ledgerSvc          *LedgerService
+	cache              *cache.RedisCache
+	paymentWindow      time.Duration
+	secondChanceWindow time.Duration
+}
+
+func NewOrderService(
+	orderRepo repository.OrderRepository,
+	offerRepo repository.SecondChanceOfferRepository,
+	auctionRepo repository.AuctionRepository,
+	auctionImageRepo repository.AuctionImageRepository,
+	shippingRepo repository.AuctionShippingRepository,
+	bidRepo repository.BidRepository,
+	txManager repository.TxManager,
+	notificationSvc *NotificationService,
+	webhookSvc *WebhookService,
+	ledgerSvc *LedgerService,
+	cache *cache.RedisCache,
+	paymentWindow time.Duration,
+	secondChanceWindow time.Duration,
+) *OrderService {
+	return &OrderService{
+		orderRepo:          orderRepo,
+		offerRepo:          offerRepo,
+		auctionRepo:        auctionRepo,
+		auctionImageRepo:   auctionImageRepo,
+		shippingRepo:       shippingRepo,
+		bidRepo:            bidRepo,
+		txManager:          txManager,
+		notificationSvc:    notificationSvc,
+		webhookSvc:         webhookSvc,
+		ledgerSvc:          ledgerSvc,
+		cache:              cache,
+		paymentWindow:      paymentWindow,
+		secondChanceWindow: secondChanceWindow,
+	}
+}
+
+// CreateForWinner opens an order awaiting payment from the auction winner
+func (s *OrderService) CreateForWinner(ctx context.Context, auction *domain.Auction, buyerID uuid.UUID, bidID *uuid.UUID) (*domain.Order, error) {
+	order := &domain.Order{
+		AuctionID:    auction.ID,
+		BuyerID:      buyerID,
+		SellerID:     auction.SellerID,
+		BidID:        bidID,
+		Amount:       auction.CurrentPrice,
+		Status:       domain.OrderStatusPendingPayment,
+		PaymentDueAt: time.Now().Add(s.paymentWindow),
+	}
+
+	if err := s.orderRepo.Create(ctx, order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (s *OrderService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only buyer or seller can view
+	if order.BuyerID != userID && order.SellerID != userID {
+		return nil, domain.ErrForbidden
+	}
+
+	order.Auction, _ = s.auctionRepo.GetByID(ctx, order.AuctionID)
+
+	return order, nil
+}
+
+func (s *OrderService) List(ctx context.Context, userID uuid.UUID, params *domain.OrderListParams) (*domain.OrderListResponse, error) {
+	params.UserID = userID
+
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	if params.Limit <= 0 {
+		params.Limit = 20
+	}
+
+	orders, totalCount, err := s.orderRepo.List(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := (totalCount + params.Limit - 1) / params.Limit
+
+	return &domain.OrderListResponse{
+		Orders:     orders,
+		TotalCount: totalCount,
+		Page:       params.Page,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// MarkPaid records that payment for an order was received. The payment is
+// held in escrow until the buyer confirms delivery or the hold period ends.
+func (s *OrderService) MarkPaid(ctx context.Context, id, sellerID uuid.UUID) (*domain.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only seller can confirm payment
+	if order.SellerID != sellerID {
+		return nil, domain.ErrForbidden
+	}
+
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.orderRepo.MarkPaid(txCtx, id); err != nil {
+			return err
+		}
+
+		if s.ledgerSvc == nil {
+			return nil
+		}
+		_, err := s.ledgerSvc.HoldPayment(txCtx, order)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	order, err = s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.webhookSvc != nil {
+		s.webhookSvc.PublishOrderPaid(ctx, order)
+	}
+
+	return order, nil
+}
+
+// ProcessUnpaidOrders cancels orders past their payment deadline
+func (s *OrderService) ProcessUnpaidOrders(ctx context.Context) {
+	orders, err := s.orderRepo.GetOverdue(ctx, time.Now(), orderSweepBatchSize)
+	if err != nil {
+		log.Printf("Error getting overdue orders: %v", err)
+		return
+	}
+
+	for i := range orders {
+		if err := s.cancelUnpaidOrder(ctx, &orders[i]); err != nil {
+			log.Printf("Error cancelling unpaid order %s: %v", orders[i].ID, err)
+		}
+	}
+}
+
+func (s *OrderService) cancelUnpaidOrder(ctx context.Context, order *domain.Order) error {
+	auction, err := s.auctionRepo.GetByID(ctx, order.AuctionID)
+	if err != nil {
+		return err
+	}
+
+	var offer *domain.SecondChanceOffer
+
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.orderRepo.Cancel(txCtx, order.ID, domain.OrderCancelReasonUnpaid); err != nil {
+			return err
+		}
+
+		if err := s.orderRepo.AddUnpaidStrike(txCtx, order.BuyerID, order.ID); err != nil {
+			return err
+		}
+
+		// Only the original runner-up gets a second chance, never a chain of them
+		offered, err := s.offerRepo.ExistsForAuction(txCtx, auction.ID)
+		if err != nil {
+			return err
+		}
+		if offered {
+			return nil
+		}
+
+		runnerUp, err := s.bidRepo.GetPreviousHighBidder(txCtx, auction.ID, order.BuyerID)
+		if err != nil {
+			return err
+		}
+		if runnerUp == nil {
+			return nil
+		}
+
+		offer = &domain.SecondChanceOffer{
+			AuctionID: auction.ID,
+			OrderID:   order.ID,
+			BidderID:  runnerUp.BidderID,
+			BidID:     &runnerUp.ID,
+			Amount:    runnerUp.Amount,
+			Status:    domain.SecondChanceOfferPending,
+			ExpiresAt: time.Now().Add(s.secondChanceWindow),
+		}
+		return s.offerRepo.Create(txCtx, offer)
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.notificationSvc != nil {
+		s.notificationSvc.NotifyUnpaidOrderCancelled(ctx, order, auction)
+		if offer != nil {
+			s.notificationSvc.NotifySecondChanceOffer(ctx, offer, auction)
+		}
+	}
+
+	if offer == nil {
+		return s.closeUnsold(ctx, auction)
+	}
+
+	return nil
+}
+
+// ProcessExpiredOffers closes out second-chance offers nobody answered
+func (s *OrderService) ProcessExpiredOffers(ctx context.Context) {
+	offers, err := s.offerRepo.GetExpired(ctx, time.Now(), orderSweepBatchSize)
+	if err != nil {
+		log.Printf("Error getting expired second chance offers: %v", err)
+		return
+	}
+
+	for _, offer := range offers {
+		if err := s.offerRepo.Respond(ctx, offer.ID, domain.SecondChanceOfferExpired); err != nil {
+			continue
+		}
+
+		auction, err := s.auctionRepo.GetByID(ctx, offer.AuctionID)
+		if err != nil {
+			continue
+		}
+		if err := s.closeUnsold(ctx, auction); err != nil {
+			log.Printf("Error closing auction %s after expired offer: %v", auction.ID, err)
+		}
+	}
+}
+
+func (s *OrderService) GetMyOffers(ctx context.Context, userID uuid.UUID, status *domain.SecondChanceOfferStatus) ([]domain.SecondChanceOffer, error) {
+	offers, err := s.offerRepo.GetByBidder(ctx, userID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range offers {
+		offers[i].Auction, _ = s.auctionRepo.GetByID(ctx, offers[i].AuctionID)
+	}
+
+	return offers, nil
+}
+
+// AcceptOffer turns a second-chance offer into a new order for the runner-up
+func (s *OrderService) AcceptOffer(ctx context.Context, offerID, userID uuid.UUID) (*domain.Order, error) {
+	offer, err := s.offerRepo.GetByID(ctx, offerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if offer.BidderID != userID {
+		return nil, domain.ErrForbidden
+	}
+
+	if offer.Status != domain.SecondChanceOfferPending || time.Now().After(offer.ExpiresAt) {
+		return nil, domain.ErrOfferNotPending
+	}
+
+	auction, err := s.auctionRepo.GetByID(ctx, offer.AuctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var order *domain.Order
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.offerRepo.Respond(txCtx, offer.ID, domain.SecondChanceOfferAccepted); err != nil {
+			return err
+		}
+
+		if err := s.auctionRepo.UpdateStatus(txCtx, auction.ID, domain.AuctionStatusCompleted, &offer.BidderID, offer.BidID); err != nil {
+			return err
+		}
+
+		order = &domain.Order{
+			AuctionID:    auction.ID,
+			BuyerID:      offer.BidderID,
+			SellerID:     auction.SellerID,
+			BidID:        offer.BidID,
+			Amount:       offer.Amount,
+			Status:       domain.OrderStatusPendingPayment,
+			PaymentDueAt: time.Now().Add(s.paymentWindow),
+		}
+		return s.orderRepo.Create(txCtx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateAuction(ctx, s.cache, auction.ID)
+
+	if s.notificationSvc != nil {
+		sold := *auction
+		sold.CurrentPrice = offer.Amount
+		go s.notificationSvc.NotifyAuctionSold(context.Background(), auction.SellerID, &sold, offer.BidderID)
+	}
+
+	return order, nil
+}
+
+func (s *OrderService) DeclineOffer(ctx context.Context, offerID, userID uuid.UUID) error {
+	offer, err := s.offerRepo.GetByID(ctx, offerID)
+	if err != nil {
+		return err
+	}
+
+	if offer.BidderID != userID {
+		return domain.ErrForbidden
+	}
+
+	if err := s.offerRepo.Respond(ctx, offer.ID, domain.SecondChanceOfferDeclined); err != nil {
+		return err
+	}
+
+	auction, err := s.auctionRepo.GetByID(ctx, offer.AuctionID)
+	if err != nil {
+		return err
+	}
+
+	return s.closeUnsold(ctx, auction)
+}
+
+// closeUnsold marks an auction whose sale fell through as unsold and
+// relists it when the seller opted in.
+func (s *OrderService) closeUnsold(ctx context.Context, auction *domain.Auction) error {
+	if err := s.auctionRepo.UpdateStatus(ctx, auction.ID, domain.AuctionStatusUnsold, nil, nil); err != nil {
+		return err
+	}
+	invalidateAuction(ctx, s.cache, auction.ID)
+
+	if !auction.AutoRelist {
+		return nil
+	}
+
+	relisted, err := s.relist(ctx, auction)
+	if err != nil {
+		return err
+	}
+	scheduleAuctionEnd(ctx, s.cache, relisted)
+
+	if s.notificationSvc != nil {
+		s.notificationSvc.NotifyAuctionRelisted(ctx, auction.SellerID, relisted)
+	}
+
+	return nil
+}
+
+// relist copies an auction into a new active listing with the same duration
+func (s *OrderService) relist(ctx context.Context, original *domain.Auction) (*domain.Auction, error) {
+	duration := original.EndTime.Sub(original.StartTime)
+	now := time.Now()
+
+	relisted := &domain.Auction{
+		SellerID:       original.SellerID,
+		CategoryID:     original.CategoryID,
+		Title:          original.Title,
+		Description:    original.Description,
+		Condition:      original.Condition,
+		Currency:       original.Currency,
+		StartingPrice:  original.StartingPrice,
+		ReservePrice:   original.ReservePrice,
+		BuyNowPrice:    original.BuyNowPrice,
+		BuyNowRule:     original.BuyNowRule,
+		AcceptsOffers:  original.AcceptsOffers,
+		CurrentPrice:   original.StartingPrice,
+		BidIncrement:   original.BidIncrement,
+		StartTime:      now,
+		EndTime:        now.Add(duration),
+		Status:         domain.AuctionStatusActive,
+		AutoRelist:     original.AutoRelist,
+		RelistedFromID: &original.ID,
+		Visibility:     original.Visibility,
+
+		RequiresBidApproval:  original.RequiresBidApproval,
+		BidApprovalQuestions: original.BidApprovalQuestions,
+		BidderRequirements:   original.BidderRequirements,
+		// Same card, so the verification carries over
+		Certification: original.Certification,
+	}
+
+	err := s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.auctionRepo.Create(txCtx, relisted); err != nil {
+			return err
+		}
+
+		images, err := s.auctionImageRepo.GetByAuctionID(txCtx, original.ID)
+		if err != nil {
+			return err
+		}
+		copiedImages := make(map[uuid.UUID]uuid.UUID, len(images))
+		for _, img := range images {
+			copied := &domain.AuctionImage{
+				AuctionID:    relisted.ID,
+				URL:          img.URL,
+				ThumbnailURL: img.ThumbnailURL,
+				MediumURL:    img.MediumURL,
+				LargeURL:     img.LargeURL,
+				Position:     img.Position,
+			}
+			if err := s.auctionImageRepo.Create(txCtx, copied); err != nil {
+				return err
+			}
+			copiedImages[img.ID] = copied.ID
+		}
+
+		// Lot items keep their images, now the copies
+		items, err := s.auctionRepo.GetItems(txCtx, original.ID)
+		if err != nil {
+			return err
+		}
+		if len(items) > 0 {
+			for i := range items {
+				items[i].ID = uuid.Nil
+				for j, imageID := range items[i].ImageIDs {
+					items[i].ImageIDs[j] = copiedImages[imageID]
+				}
+			}
+			if err := s.auctionRepo.ReplaceItems(txCtx, relisted.ID, items); err != nil {
+				return err
+			}
+		}
+
+		shipping, err := s.shippingRepo.GetByAuctionID(txCtx, original.ID)
+		if err != nil {
+			return err
+		}
+		for i := range shipping {
+			shipping[i].ID = uuid.Nil
+		}
+		return s.shippingRepo.ReplaceForAuction(txCtx, relisted.ID, shipping)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return relisted, nil
+}
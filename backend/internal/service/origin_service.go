@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/pkg/origin"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// How often each instance reloads the admin-managed origins, so changes
+// made through another instance take effect
+const originRefreshInterval = time.Minute
+
+// OriginService manages the cross-origin allow list that admins can extend
+// for partner apps. Changes are loaded into the CORS middleware's list.
+type OriginService struct {
+	originRepo repository.AllowedOriginRepository
+	auditRepo  repository.AuditLogRepository
+	txManager  repository.TxManager
+	allowList  *origin.AllowList
+	stopChan   chan struct{}
+}
+
+func NewOriginService(
+	originRepo repository.AllowedOriginRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager repository.TxManager,
+	allowList *origin.AllowList,
+) *OriginService {
+	return &OriginService{
+		originRepo: originRepo,
+		auditRepo:  auditRepo,
+		txManager:  txManager,
+		allowList:  allowList,
+		stopChan:   make(chan struct{}),
+	}
+}
+
+// Start loads the managed origins and keeps them in sync until Stop
+func (s *OriginService) Start() {
+	if err := s.Refresh(context.Background()); err != nil {
+		log.Printf("Error loading allowed origins: %v", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(originRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stopChan:
+				return
+			case <-ticker.C:
+				if err := s.Refresh(context.Background()); err != nil {
+					log.Printf("Error refreshing allowed origins: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func (s *OriginService) Stop() {
+	close(s.stopChan)
+}
+
+// Refresh replaces the managed part of the allow list with what's stored
+func (s *OriginService) Refresh(ctx context.Context) error {
+	origins, err := s.originRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	patterns := make([]string, len(origins))
+	for i := range origins {
+		patterns[i] = origins[i].Origin
+	}
+	s.allowList.SetManaged(patterns)
+
+	return nil
+}
+
+func (s *OriginService) List(ctx context.Context) ([]domain.AllowedOrigin, error) {
+	return s.originRepo.List(ctx)
+}
+
+// Allow adds an origin pattern to the allow list. Partner apps must be
+// named explicitly, so the match-all pattern is rejected.
+func (s *OriginService) Allow(ctx context.Context, adminID uuid.UUID, req *domain.CreateAllowedOriginRequest) (*domain.AllowedOrigin, error) {
+	pattern, err := origin.Compile(req.Origin)
+	if err != nil || pattern.String() == "*" {
+		return nil, domain.ErrValidation
+	}
+
+	allowed := &domain.AllowedOrigin{
+		Origin:      pattern.String(),
+		Description: req.Description,
+		CreatedBy:   &adminID,
+	}
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.originRepo.Create(txCtx, allowed); err != nil {
+			return err
+		}
+		return s.audit(txCtx, adminID, domain.AuditActionOriginAllow, allowed)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Refresh(ctx); err != nil {
+		log.Printf("Error refreshing allowed origins: %v", err)
+	}
+
+	return allowed, nil
+}
+
+// Revoke removes an admin-managed origin. Origins from configuration can't
+// be revoked at runtime.
+func (s *OriginService) Revoke(ctx context.Context, adminID, id uuid.UUID) error {
+	allowed, err := s.originRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.originRepo.Delete(txCtx, id); err != nil {
+			return err
+		}
+		return s.audit(txCtx, adminID, domain.AuditActionOriginRevoke, allowed)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.Refresh(ctx); err != nil {
+		log.Printf("Error refreshing allowed origins: %v", err)
+	}
+
+	return nil
+}
+
+func (s *OriginService) audit(ctx context.Context, adminID uuid.UUID, action string, allowed *domain.AllowedOrigin) error {
+	return s.auditRepo.Create(ctx, &domain.AuditLog{
+		ActorID:    &adminID,
+		Action:     action,
+		EntityType: domain.AuditEntityOrigin,
+		EntityID:   &allowed.ID,
+		Metadata: map[string]interface{}{
+			"origin": allowed.Origin,
+		},
+	})
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/pkg/encryption"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// PayoutService lets sellers withdraw their balance to a bank account or
+// Stripe Connect account, with admins approving and completing each payout.
+// Every status change moves the payout's money between ledger accounts.
+type PayoutService struct {
+	payoutRepo      repository.PayoutRepository
+	ledgerSvc       *LedgerService
+	auditRepo       repository.AuditLogRepository
+	txManager       repository.TxManager
+	notificationSvc *NotificationService
+	encryptor       *encryption.AESEncryptor
+	minAmount       decimal.Decimal
+}
+
+func NewPayoutService(
+	payoutRepo repository.PayoutRepository,
+	ledgerSvc *LedgerService,
+	auditRepo repository.AuditLogRepository,
+	txManager repository.TxManager,
+	notificationSvc *NotificationService,
+	encryptionKey string,
+	minAmount decimal.Decimal,
+) (*PayoutService, error) {
+	encryptor, err := encryption.NewAESEncryptor(encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize encryptor: %w", err)
+	}
+
+	return &PayoutService{
+		payoutRepo:      payoutRepo,
+		ledgerSvc:       ledgerSvc,
+		auditRepo:       auditRepo,
+		txManager:       txManager,
+		notificationSvc: notificationSvc,
+		encryptor:       encryptor,
+		minAmount:       minAmount,
+	}, nil
+}
+
+func (s *PayoutService) GetMethod(ctx context.Context, userID uuid.UUID) (*domain.PayoutMethod, error) {
+	return s.payoutRepo.GetMethod(ctx, userID)
+}
+
+// SetMethod registers where the user's payouts go, replacing any earlier
+// method. Payouts already requested keep their destination.
+func (s *PayoutService) SetMethod(ctx context.Context, userID uuid.UUID, req *domain.SetPayoutMethodRequest) (*domain.PayoutMethod, error) {
+	method := &domain.PayoutMethod{
+		UserID:        userID,
+		Type:          domain.PayoutMethodType(req.Type),
+		AccountHolder: strings.TrimSpace(req.AccountHolder),
+	}
+
+	switch method.Type {
+	case domain.PayoutMethodIBAN:
+		iban := normalizeIBAN(*req.IBAN)
+		if !validIBAN(iban) {
+			return nil, domain.ErrInvalidPayoutMethod
+		}
+
+		ciphertext, nonce, err := s.encryptor.EncryptString(iban)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt IBAN: %w", err)
+		}
+		last4 := iban[len(iban)-4:]
+		method.IBANLast4 = &last4
+		method.IBANEncrypted = ciphertext
+		method.IBANNonce = nonce
+	case domain.PayoutMethodStripeConnect:
+		accountID := strings.TrimSpace(*req.StripeAccountID)
+		method.StripeAccountID = &accountID
+	default:
+		return nil, domain.ErrInvalidPayoutMethod
+	}
+
+	if err := s.payoutRepo.UpsertMethod(ctx, method); err != nil {
+		return nil, err
+	}
+
+	return method, nil
+}
+
+// RequestPayout withdraws part of the user's available balance to their
+// payout method, pending admin approval
+func (s *PayoutService) RequestPayout(ctx context.Context, userID uuid.UUID, req *domain.RequestPayoutRequest) (*domain.Payout, error) {
+	amount, err := domain.ParseAmount(req.Amount)
+	if err != nil || amount.LessThan(s.minAmount) || !amount.IsPositive() {
+		return nil, domain.ErrInvalidPayoutAmount
+	}
+
+	method, err := s.payoutRepo.GetMethod(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrPayoutMethodRequired
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	payout := &domain.Payout{
+		ID:              uuid.New(),
+		UserID:          userID,
+		Amount:          amount,
+		Status:          domain.PayoutRequested,
+		MethodType:      method.Type,
+		AccountHolder:   method.AccountHolder,
+		IBANLast4:       method.IBANLast4,
+		IBANEncrypted:   method.IBANEncrypted,
+		IBANNonce:       method.IBANNonce,
+		StripeAccountID: method.StripeAccountID,
+	}
+
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.payoutRepo.Create(txCtx, payout); err != nil {
+			return err
+		}
+		return s.ledgerSvc.ReservePayout(txCtx, payout)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(payout)
+
+	return payout, nil
+}
+
+func (s *PayoutService) ListForUser(ctx context.Context, userID uuid.UUID, page, limit int) ([]domain.Payout, int, error) {
+	return s.payoutRepo.List(ctx, &domain.PayoutListParams{UserID: &userID, Page: page, Limit: limit})
+}
+
+// AdminList lists payouts with their full destination for paying out
+func (s *PayoutService) AdminList(ctx context.Context, params *domain.PayoutListParams) ([]domain.Payout, int, error) {
+	payouts, totalCount, err := s.payoutRepo.List(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := range payouts {
+		s.decryptIBAN(&payouts[i])
+	}
+
+	return payouts, totalCount, nil
+}
+
+// Approve accepts a requested payout so it can be sent
+func (s *PayoutService) Approve(ctx context.Context, adminID, payoutID uuid.UUID) (*domain.Payout, error) {
+	return s.transition(ctx, adminID, payoutID, []domain.PayoutStatus{domain.PayoutRequested}, func(p *domain.Payout) {
+		p.Status = domain.PayoutApproved
+	}, domain.AuditActionPayoutApprove, nil, "payout approved")
+}
+
+// Complete records that an approved payout was sent
+func (s *PayoutService) Complete(ctx context.Context, adminID, payoutID uuid.UUID, req *domain.CompletePayoutRequest) (*domain.Payout, error) {
+	return s.transition(ctx, adminID, payoutID, []domain.PayoutStatus{domain.PayoutApproved}, func(p *domain.Payout) {
+		p.Status = domain.PayoutCompleted
+		p.Reference = &req.Reference
+	}, domain.AuditActionPayoutComplete, nil, "payout sent")
+}
+
+// Reject turns down a payout that hasn't been sent, returning its amount to
+// the seller's balance
+func (s *PayoutService) Reject(ctx context.Context, adminID, payoutID uuid.UUID, req *domain.RejectPayoutRequest) (*domain.Payout, error) {
+	return s.transition(ctx, adminID, payoutID, []domain.PayoutStatus{domain.PayoutRequested, domain.PayoutApproved}, func(p *domain.Payout) {
+		p.Status = domain.PayoutRejected
+		p.RejectionReason = &req.Reason
+	}, domain.AuditActionPayoutReject, &req.Reason, "payout rejected")
+}
+
+// transition moves a payout from one of the allowed statuses, moving its
+// money to match and auditing the admin's action
+func (s *PayoutService) transition(
+	ctx context.Context,
+	adminID, payoutID uuid.UUID,
+	allowed []domain.PayoutStatus,
+	apply func(*domain.Payout),
+	action string,
+	reason *string,
+	memo string,
+) (*domain.Payout, error) {
+	payout, err := s.payoutRepo.GetByID(ctx, payoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := payout.Status
+	if !slices.Contains(allowed, from) {
+		return nil, domain.ErrPayoutStatus
+	}
+	fromAccount := payout.LedgerAccount()
+
+	apply(payout)
+	payout.ReviewedBy = &adminID
+
+	err = s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		if err := s.payoutRepo.Transition(txCtx, payout, from); err != nil {
+			return err
+		}
+
+		if err := s.ledgerSvc.MovePayout(txCtx, payout, fromAccount, memo); err != nil {
+			return err
+		}
+
+		return s.auditRepo.Create(txCtx, &domain.AuditLog{
+			ActorID:    &adminID,
+			Action:     action,
+			EntityType: domain.AuditEntityPayout,
+			EntityID:   &payout.ID,
+			Reason:     reason,
+			Metadata: map[string]interface{}{
+				"user_id":     payout.UserID,
+				"amount":      payout.Amount.StringFixed(2),
+				"from_status": from,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(payout)
+	s.decryptIBAN(payout)
+
+	return payout, nil
+}
+
+func (s *PayoutService) notify(payout *domain.Payout) {
+	if s.notificationSvc == nil {
+		return
+	}
+
+	notified := *payout
+	go s.notificationSvc.NotifyPayout(context.Background(), &notified)
+}
+
+// decryptIBAN fills in the full IBAN for admin views
+func (s *PayoutService) decryptIBAN(payout *domain.Payout) {
+	if payout.IBANEncrypted == nil {
+		return
+	}
+
+	iban, err := s.encryptor.DecryptString(payout.IBANEncrypted, payout.IBANNonce)
+	if err != nil {
+		log.Printf("Error decrypting IBAN for payout %s: %v", payout.ID, err)
+		return
+	}
+	payout.IBAN = &iban
+}
+
+func normalizeIBAN(iban string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(iban), " ", ""))
+}
+
+// validIBAN checks an IBAN's shape and its ISO 7064 mod 97 check digits
+func validIBAN(iban string) bool {
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+
+	isLetter := func(c byte) bool { return c >= 'A' && c <= 'Z' }
+	isDigit := func(c byte) bool { return c >= '0' && c <= '9' }
+	if !isLetter(iban[0]) || !isLetter(iban[1]) || !isDigit(iban[2]) || !isDigit(iban[3]) {
+		return false
+	}
+
+	// Move the country code and check digits to the end, reading letters
+	// as two-digit numbers
+	rearranged := iban[4:] + iban[:4]
+	remainder := 0
+	for i := 0; i < len(rearranged); i++ {
+		c := rearranged[i]
+		switch {
+		case isDigit(c):
+			remainder = (remainder*10 + int(c-'0')) % 97
+		case isLetter(c):
+			remainder = (remainder*100 + int(c-'A') + 10) % 97
+		default:
+			return false
+		}
+	}
+
+	return remainder == 1
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// PriceDropService runs sellers' automatic markdowns of buy-now prices.
+// The scheduler applies each markdown as it comes due, records it in the
+// listing's price history and tells watchers. A rule stops once the price
+// reaches its floor, buy-now is withdrawn or the auction ends.
+type PriceDropService struct {
+	priceDropRepo   repository.PriceDropRepository
+	auctionRepo     repository.AuctionRepository
+	auctionSvc      *AuctionService
+	notificationSvc *NotificationService
+	txManager       repository.TxManager
+}
+
+func NewPriceDropService(
+	priceDropRepo repository.PriceDropRepository,
+	auctionRepo repository.AuctionRepository,
+	auctionSvc *AuctionService,
+	notificationSvc *NotificationService,
+	txManager repository.TxManager,
+) *PriceDropService {
+	return &PriceDropService{
+		priceDropRepo:   priceDropRepo,
+		auctionRepo:     auctionRepo,
+		auctionSvc:      auctionSvc,
+		notificationSvc: notificationSvc,
+		txManager:       txManager,
+	}
+}
+
+// SetRule sets up markdowns for the seller's draft or active listing,
+// replacing any rule it had. The first markdown comes one interval after
+// now, or after the auction starts if it hasn't yet.
+func (s *PriceDropService) SetRule(ctx context.Context, auctionID, sellerID uuid.UUID, req *domain.SetPriceDropRuleRequest) (*domain.PriceDropRule, error) {
+	percent, err := decimal.NewFromString(req.Percent)
+	if err != nil {
+		return nil, domain.ErrBadRequest
+	}
+	if !percent.IsPositive() || percent.GreaterThan(domain.MaxPriceDropPercent) {
+		return nil, domain.ErrValidation
+	}
+
+	floor, err := domain.ParseAmount(req.FloorPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.SellerID != sellerID {
+		return nil, domain.ErrForbidden
+	}
+
+	if auction.Status != domain.AuctionStatusDraft && auction.Status != domain.AuctionStatusActive {
+		return nil, domain.ErrAuctionNotActive
+	}
+
+	now := time.Now()
+	if now.After(auction.EndTime) {
+		return nil, domain.ErrAuctionEnded
+	}
+
+	if auction.BuyNowPrice == nil {
+		return nil, domain.ErrBuyNowUnavailable
+	}
+
+	if !floor.LessThan(*auction.BuyNowPrice) || floor.LessThan(auction.StartingPrice) {
+		return nil, domain.ErrPriceDropFloor
+	}
+
+	start := now
+	if auction.StartTime.After(now) {
+		start = auction.StartTime
+	}
+
+	rule := &domain.PriceDropRule{
+		AuctionID:    auctionID,
+		Percent:      percent,
+		IntervalDays: req.IntervalDays,
+		FloorPrice:   floor,
+	}
+	next := start.Add(rule.Interval())
+	rule.NextDropAt = &next
+
+	if err := s.priceDropRepo.SaveRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// DeleteRule stops the seller's markdowns. Drops already made stay in the
+// price history.
+func (s *PriceDropService) DeleteRule(ctx context.Context, auctionID, sellerID uuid.UUID) error {
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return err
+	}
+
+	if auction.SellerID != sellerID {
+		return domain.ErrForbidden
+	}
+
+	return s.priceDropRepo.DeleteRule(ctx, auctionID)
+}
+
+// History returns the listing's buy-now price history to anyone who can
+// see the auction, with the rule for its seller
+func (s *PriceDropService) History(ctx context.Context, auctionID, viewerID uuid.UUID, shareToken string) (*domain.PriceHistory, error) {
+	auction, err := s.auctionSvc.GetByID(ctx, auctionID, viewerID, shareToken)
+	if err != nil {
+		return nil, err
+	}
+
+	drops, err := s.priceDropRepo.ListDrops(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &domain.PriceHistory{
+		BuyNowPrice: auction.BuyNowPrice,
+		Drops:       drops,
+	}
+
+	if auction.SellerID == viewerID {
+		rule, err := s.priceDropRepo.GetRule(ctx, auctionID)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+		history.Rule = rule
+	}
+
+	return history, nil
+}
+
+// ProcessDue applies every markdown that has come due
+func (s *PriceDropService) ProcessDue(ctx context.Context) {
+	for {
+		var auction *domain.Auction
+		var drop *domain.PriceDrop
+
+		err := s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+			now := time.Now()
+			rule, err := s.priceDropRepo.ClaimDue(txCtx, now)
+			if err != nil {
+				return err
+			}
+
+			auction, err = s.auctionRepo.GetByID(txCtx, rule.AuctionID)
+			if err != nil {
+				return err
+			}
+
+			drop, err = s.markDown(txCtx, rule, auction, now)
+			return err
+		})
+		if errors.Is(err, domain.ErrNotFound) {
+			return
+		}
+		if err != nil {
+			log.Printf("Error applying price drop: %v", err)
+			return
+		}
+
+		if drop == nil {
+			continue
+		}
+
+		invalidateAuction(ctx, s.auctionSvc.cache, auction.ID)
+		if s.notificationSvc != nil {
+			s.notificationSvc.NotifyPriceDrop(ctx, auction, drop.OldPrice)
+		}
+	}
+}
+
+// markDown applies the rule's markdown to the auction if it still can and
+// schedules the next one, or ends the rule. It returns the drop made, if
+// any.
+func (s *PriceDropService) markDown(ctx context.Context, rule *domain.PriceDropRule, auction *domain.Auction, now time.Time) (*domain.PriceDrop, error) {
+	var drop *domain.PriceDrop
+
+	switch {
+	case auction.Status == domain.AuctionStatusDraft:
+		// Not published yet; try again an interval later
+		next := now.Add(rule.Interval())
+		rule.NextDropAt = &next
+	case auction.Status != domain.AuctionStatusActive || !auction.EndTime.After(now) || !auction.CanBuyNow():
+		rule.NextDropAt = nil
+	default:
+		oldPrice := *auction.BuyNowPrice
+		newPrice := rule.NextPrice(oldPrice)
+
+		// Buy-now has to stay above the high bid to make sense
+		if !newPrice.LessThan(oldPrice) || (auction.BidCount > 0 && !newPrice.GreaterThan(auction.CurrentPrice)) {
+			rule.NextDropAt = nil
+			break
+		}
+
+		expectedVersion := auction.Version
+		auction.BuyNowPrice = &newPrice
+		if err := s.auctionRepo.UpdateBuyNowPriceWithVersion(ctx, auction, expectedVersion); err != nil {
+			return nil, err
+		}
+
+		drop = &domain.PriceDrop{
+			AuctionID: auction.ID,
+			OldPrice:  oldPrice,
+			NewPrice:  newPrice,
+		}
+		if err := s.priceDropRepo.CreateDrop(ctx, drop); err != nil {
+			return nil, err
+		}
+
+		if newPrice.LessThanOrEqual(rule.FloorPrice) {
+			rule.NextDropAt = nil
+			break
+		}
+
+		// Keep to the schedule unless markdowns were held up for a whole
+		// interval
+		next := rule.NextDropAt.Add(rule.Interval())
+		if !next.After(now) {
+			next = now.Add(rule.Interval())
+		}
+		rule.NextDropAt = &next
+	}
+
+	if err := s.priceDropRepo.SaveRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return drop, nil
+}
@@ -0,0 +1,447 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/pkg/email"
+	"github.com/auction-cards/backend/internal/pkg/storage"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// dataExportLease keeps an export from being claimed by another worker
+	// while its archive is assembled
+	dataExportLease = 10 * time.Minute
+
+	// maxExportLinkTTL is the longest a presigned S3 link may be valid
+	maxExportLinkTTL = 7 * 24 * time.Hour
+
+	// exportPageSize is how many records are loaded at a time for an export
+	exportPageSize = 100
+
+	// expiredExportBatch bounds how many archives one purge deletes
+	expiredExportBatch = 100
+
+	// deletedUserBatch bounds how many accounts one purge anonymizes
+	deletedUserBatch = 100
+)
+
+// PrivacyService gives users a copy of their data and deletes their
+// account on request. Exports are assembled in the background into a ZIP
+// archive of JSON files, stored privately and sent as a presigned link by
+// email; the archive is deleted once the link expires. Accounts admins
+// delete keep their data for a retention period before being purged.
+type PrivacyService struct {
+	exportRepo       repository.DataExportRepository
+	deletionRepo     repository.AccountDeletionRepository
+	userRepo         repository.UserRepository
+	auctionRepo      repository.AuctionRepository
+	bidRepo          repository.BidRepository
+	ratingRepo       repository.RatingRepository
+	notificationRepo repository.NotificationRepository
+	auditRepo        repository.AuditLogRepository
+	txManager        repository.TxManager
+	authSvc          *AuthService
+	messageSvc       *MessageService
+	store            storage.ObjectStore
+	emailSender      email.Sender
+	linkTTL          time.Duration
+	purgeAfter       time.Duration
+}
+
+func NewPrivacyService(
+	exportRepo repository.DataExportRepository,
+	deletionRepo repository.AccountDeletionRepository,
+	userRepo repository.UserRepository,
+	auctionRepo repository.AuctionRepository,
+	bidRepo repository.BidRepository,
+	ratingRepo repository.RatingRepository,
+	notificationRepo repository.NotificationRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager repository.TxManager,
+	authSvc *AuthService,
+	messageSvc *MessageService,
+	store storage.ObjectStore,
+	emailSender email.Sender,
+	linkTTL time.Duration,
+	purgeAfter time.Duration,
+) *PrivacyService {
+	if linkTTL <= 0 || linkTTL > maxExportLinkTTL {
+		linkTTL = maxExportLinkTTL
+	}
+
+	return &PrivacyService{
+		exportRepo:       exportRepo,
+		deletionRepo:     deletionRepo,
+		userRepo:         userRepo,
+		auctionRepo:      auctionRepo,
+		bidRepo:          bidRepo,
+		ratingRepo:       ratingRepo,
+		notificationRepo: notificationRepo,
+		auditRepo:        auditRepo,
+		txManager:        txManager,
+		authSvc:          authSvc,
+		messageSvc:       messageSvc,
+		store:            store,
+		emailSender:      emailSender,
+		linkTTL:          linkTTL,
+		purgeAfter:       purgeAfter,
+	}
+}
+
+// RequestExport returns the user's latest export while it is being
+// assembled or can still be downloaded, with a fresh link in the latter
+// case; otherwise it queues a new one
+func (s *PrivacyService) RequestExport(ctx context.Context, userID uuid.UUID) (*domain.DataExport, error) {
+	if s.store == nil {
+		return nil, domain.ErrExportsUnavailable
+	}
+
+	latest, err := s.exportRepo.GetLatestByUser(ctx, userID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	if latest != nil {
+		now := time.Now()
+		switch {
+		case latest.Status == domain.DataExportPending || latest.Status == domain.DataExportProcessing:
+			return latest, nil
+		case latest.Downloadable(now):
+			url, err := s.store.GetPresignedURL(ctx, *latest.ObjectName, latest.ExpiresAt.Sub(now))
+			if err != nil {
+				return nil, err
+			}
+			latest.DownloadURL = url
+			return latest, nil
+		}
+	}
+
+	export := &domain.DataExport{UserID: userID}
+	if err := s.exportRepo.Create(ctx, export); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// ProcessExports assembles queued exports one after another until none are
+// left. An export that can't be assembled is marked failed, so the user can
+// ask again.
+func (s *PrivacyService) ProcessExports(ctx context.Context) {
+	if s.store == nil {
+		return
+	}
+
+	for {
+		now := time.Now()
+		export, err := s.exportRepo.ClaimNext(ctx, now, now.Add(dataExportLease))
+		if errors.Is(err, domain.ErrNotFound) {
+			return
+		}
+		if err != nil {
+			log.Printf("Error claiming data export: %v", err)
+			return
+		}
+
+		if err := s.assemble(ctx, export); err != nil {
+			log.Printf("Error assembling data export %s: %v", export.ID, err)
+
+			export.Status = domain.DataExportFailed
+			export.LeaseUntil = nil
+			if err := s.exportRepo.Save(ctx, export); err != nil {
+				log.Printf("Error saving data export %s: %v", export.ID, err)
+				return
+			}
+		}
+	}
+}
+
+// assemble builds and stores the export's archive, then emails the link
+func (s *PrivacyService) assemble(ctx context.Context, export *domain.DataExport) error {
+	user, err := s.userRepo.GetByID(ctx, export.UserID)
+	if err != nil {
+		return err
+	}
+
+	archive, err := s.buildArchive(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	objectName := path.Join(storage.GetExportFolder(user.ID), uuid.New().String()+".zip")
+	if err := s.store.PutObject(ctx, objectName, bytes.NewReader(archive), int64(len(archive)), "application/zip"); err != nil {
+		return err
+	}
+	url, err := s.store.GetPresignedURL(ctx, objectName, s.linkTTL)
+	if err != nil {
+		_ = s.store.RemoveObject(ctx, objectName)
+		return err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.linkTTL)
+	size := int64(len(archive))
+	export.Status = domain.DataExportCompleted
+	export.ObjectName = &objectName
+	export.SizeBytes = &size
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+	export.LeaseUntil = nil
+	if err := s.exportRepo.Save(ctx, export); err != nil {
+		_ = s.store.RemoveObject(ctx, objectName)
+		return err
+	}
+
+	_ = s.emailSender.Send(email.NewDataExportEmail(user.Email, url, s.linkTTL))
+	return nil
+}
+
+// buildArchive gathers the user's profile, auctions, bids, ratings,
+// messages and notifications into a ZIP archive of JSON files
+func (s *PrivacyService) buildArchive(ctx context.Context, user *domain.User) ([]byte, error) {
+	auctions, err := collectPages(func(page int) ([]domain.Auction, int, error) {
+		return s.auctionRepo.List(ctx, &domain.AuctionListParams{SellerID: &user.ID, Page: page, Limit: exportPageSize})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bids, err := collectPages(func(page int) ([]domain.Bid, int, error) {
+		return s.bidRepo.GetByBidderID(ctx, user.ID, page, exportPageSize)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ratings := domain.ExportedRatings{}
+	ratings.Received, err = collectPages(func(page int) ([]domain.Rating, int, error) {
+		return s.ratingRepo.GetByRatedUser(ctx, user.ID, &domain.RatingListParams{Page: page, Limit: exportPageSize})
+	})
+	if err != nil {
+		return nil, err
+	}
+	ratings.Given, err = s.ratingRepo.GetByRater(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications, err := collectPages(func(page int) ([]domain.Notification, int, error) {
+		notifications, total, _, err := s.notificationRepo.GetByUserID(ctx, user.ID, &domain.NotificationListParams{
+			UserID: user.ID,
+			Page:   page,
+			Limit:  exportPageSize,
+		})
+		return notifications, total, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := s.messageSvc.Transcripts(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", domain.ExportedProfile{User: user, Phone: user.Phone, Address: user.Address}},
+		{"auctions.json", auctions},
+		{"bids.json", bids},
+		{"ratings.json", ratings},
+		{"messages.json", messages},
+		{"notifications.json", notifications},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add %s: %w", file.name, err)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// collectPages fetches page after page until all of the reported total
+// has been loaded
+func collectPages[T any](fetch func(page int) ([]T, int, error)) ([]T, error) {
+	items := make([]T, 0)
+	for page := 1; ; page++ {
+		batch, total, err := fetch(page)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, batch...)
+		if len(batch) == 0 || len(items) >= total {
+			return items, nil
+		}
+	}
+}
+
+// PurgeExpiredExports deletes the archives of exports whose link has
+// expired. Archives that can't be deleted are tried again next time.
+func (s *PrivacyService) PurgeExpiredExports(ctx context.Context) {
+	if s.store == nil {
+		return
+	}
+
+	exports, err := s.exportRepo.ListExpired(ctx, time.Now(), expiredExportBatch)
+	if err != nil {
+		log.Printf("Error listing expired data exports: %v", err)
+		return
+	}
+
+	for i := range exports {
+		export := &exports[i]
+		if export.ObjectName != nil {
+			if err := s.store.RemoveObject(ctx, *export.ObjectName); err != nil {
+				log.Printf("Error deleting data export %s: %v", export.ID, err)
+				continue
+			}
+		}
+
+		export.Status = domain.DataExportExpired
+		export.ObjectName = nil
+		if err := s.exportRepo.Save(ctx, export); err != nil {
+			log.Printf("Error saving data export %s: %v", export.ID, err)
+		}
+	}
+}
+
+// DeleteAccount erases the user's personal data once they have confirmed
+// it's them and nothing they are part of is still open. The account stays
+// behind as an anonymous placeholder, so the bids, ratings, orders and
+// messages of the people they dealt with still make sense.
+func (s *PrivacyService) DeleteAccount(ctx context.Context, userID uuid.UUID, req *domain.ReauthRequest, refreshToken string) error {
+	if err := s.authSvc.ConfirmPresence(ctx, userID, req, refreshToken); err != nil {
+		return err
+	}
+
+	return s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		obligations, err := s.deletionRepo.GetObligations(txCtx, userID)
+		if err != nil {
+			return err
+		}
+		if !obligations.Settled() {
+			return domain.ErrAccountInUse
+		}
+
+		erased, err := s.deletionRepo.Anonymize(txCtx, userID, deletedUsername(userID))
+		if err != nil {
+			return err
+		}
+
+		return s.auditRepo.Create(txCtx, &domain.AuditLog{
+			ActorID:    &userID,
+			Action:     domain.AuditActionUserDelete,
+			EntityType: domain.AuditEntityUser,
+			EntityID:   &userID,
+			Metadata: map[string]interface{}{
+				"erased": erased,
+			},
+		})
+	})
+}
+
+// DeleteUser soft-deletes an account for an admin. It is signed out and
+// shown as a deleted user straight away; its personal data stays until
+// PurgeDeletedUsers runs after the retention period.
+func (s *PrivacyService) DeleteUser(ctx context.Context, adminID, userID uuid.UUID) error {
+	err := s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+		user, err := s.userRepo.GetByID(txCtx, userID)
+		if err != nil {
+			return err
+		}
+		if user.IsDeleted {
+			return domain.ErrNotFound
+		}
+
+		obligations, err := s.deletionRepo.GetObligations(txCtx, userID)
+		if err != nil {
+			return err
+		}
+		if !obligations.Settled() {
+			return domain.ErrAccountInUse
+		}
+
+		if err := s.userRepo.Delete(txCtx, userID); err != nil {
+			return err
+		}
+
+		return s.auditRepo.Create(txCtx, &domain.AuditLog{
+			ActorID:    &adminID,
+			Action:     domain.AuditActionUserDelete,
+			EntityType: domain.AuditEntityUser,
+			EntityID:   &userID,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.authSvc.RevokeSessions(ctx, userID)
+}
+
+// PurgeDeletedUsers anonymizes accounts deleted longer ago than the
+// retention period, up to a batch at a time
+func (s *PrivacyService) PurgeDeletedUsers(ctx context.Context, adminID uuid.UUID) (*domain.UserPurgeResult, error) {
+	ids, err := s.deletionRepo.ListPurgeable(ctx, time.Now().Add(-s.purgeAfter), deletedUserBatch)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.UserPurgeResult{}
+	for _, userID := range ids {
+		err := s.txManager.WithTx(ctx, func(txCtx context.Context) error {
+			erased, err := s.deletionRepo.Anonymize(txCtx, userID, deletedUsername(userID))
+			if err != nil {
+				return err
+			}
+
+			return s.auditRepo.Create(txCtx, &domain.AuditLog{
+				ActorID:    &adminID,
+				Action:     domain.AuditActionUserPurge,
+				EntityType: domain.AuditEntityUser,
+				EntityID:   &userID,
+				Metadata: map[string]interface{}{
+					"erased": erased,
+				},
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+		result.Purged++
+	}
+
+	return result, nil
+}
+
+// deletedUsername is the placeholder name of a deleted account, unique and
+// valid like any other username
+func deletedUsername(userID uuid.UUID) string {
+	return "deleted" + strings.ReplaceAll(userID.String(), "-", "")
+}
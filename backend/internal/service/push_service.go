@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/pkg/webpush"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// pushWorkers is how many pushes one send has in flight at once
+const pushWorkers = 8
+
+// PushService keeps the browsers users subscribed to web push and sends
+// to them. Without a sender push is off: subscribing fails and nothing is
+// sent.
+type PushService struct {
+	subscriptionRepo repository.PushSubscriptionRepository
+	sender           webpush.Sender
+	maxPerUser       int
+}
+
+func NewPushService(subscriptionRepo repository.PushSubscriptionRepository, sender webpush.Sender, maxPerUser int) *PushService {
+	return &PushService{
+		subscriptionRepo: subscriptionRepo,
+		sender:           sender,
+		maxPerUser:       maxPerUser,
+	}
+}
+
+func (s *PushService) List(ctx context.Context, userID uuid.UUID) (*domain.PushSubscriptionsResponse, error) {
+	if s.sender == nil {
+		return nil, domain.ErrPushUnavailable
+	}
+
+	subscriptions, err := s.subscriptionRepo.ListByUsers(ctx, []uuid.UUID{userID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PushSubscriptionsResponse{
+		PublicKey:     s.sender.PublicKey(),
+		Subscriptions: subscriptions,
+	}, nil
+}
+
+// Subscribe stores the browser's subscription. Subscribing a browser again
+// replaces its keys; past the per-user limit the oldest is dropped.
+func (s *PushService) Subscribe(ctx context.Context, userID uuid.UUID, userAgent string, req *domain.CreatePushSubscriptionRequest) (*domain.PushSubscription, error) {
+	if s.sender == nil {
+		return nil, domain.ErrPushUnavailable
+	}
+
+	if err := s.sender.ValidateEndpoint(req.Endpoint); err != nil {
+		return nil, domain.ErrPushSubscription
+	}
+	keys := &webpush.Subscription{Endpoint: req.Endpoint, P256dh: req.Keys.P256dh, Auth: req.Keys.Auth}
+	if err := webpush.ValidateKeys(keys); err != nil {
+		return nil, domain.ErrPushSubscription
+	}
+
+	subscription := &domain.PushSubscription{
+		UserID:   userID,
+		Endpoint: req.Endpoint,
+		P256dh:   req.Keys.P256dh,
+		Auth:     req.Keys.Auth,
+	}
+	if userAgent != "" {
+		subscription.UserAgent = &userAgent
+	}
+	if req.ExpirationTime != nil {
+		expiresAt := time.UnixMilli(*req.ExpirationTime)
+		subscription.ExpiresAt = &expiresAt
+	}
+
+	if err := s.subscriptionRepo.Save(ctx, subscription, s.maxPerUser); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (s *PushService) Unsubscribe(ctx context.Context, userID, subscriptionID uuid.UUID) error {
+	return s.subscriptionRepo.Delete(ctx, userID, subscriptionID)
+}
+
+// Send pushes the payload to every browser the users subscribed, a few at a
+// time. The push service holds it for up to ttl while a browser is offline.
+// Subscriptions the push service no longer knows, and those past the expiry
+// their browser gave, are deleted.
+func (s *PushService) Send(ctx context.Context, userIDs []uuid.UUID, payload *domain.PushPayload, ttl time.Duration, urgency webpush.Urgency) {
+	if s.sender == nil || len(userIDs) == 0 {
+		return
+	}
+
+	subscriptions, err := s.subscriptionRepo.ListByUsers(ctx, userIDs)
+	if err != nil {
+		log.Printf("Failed to get push subscriptions for %s push: %v", payload.Type, err)
+		return
+	}
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Failed to encode %s push: %v", payload.Type, err)
+		return
+	}
+	msg := &webpush.Message{Payload: body, TTL: max(ttl, 0), Urgency: urgency}
+
+	var (
+		mu    sync.Mutex
+		stale []uuid.UUID
+		wg    sync.WaitGroup
+	)
+	prune := func(id uuid.UUID) {
+		mu.Lock()
+		stale = append(stale, id)
+		mu.Unlock()
+	}
+
+	now := time.Now()
+	sem := make(chan struct{}, pushWorkers)
+	for _, subscription := range subscriptions {
+		if subscription.Expired(now) {
+			prune(subscription.ID)
+			continue
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			err := s.sender.Send(ctx, &webpush.Subscription{
+				Endpoint: subscription.Endpoint,
+				P256dh:   subscription.P256dh,
+				Auth:     subscription.Auth,
+			}, msg)
+			switch {
+			case errors.Is(err, webpush.ErrGone):
+				prune(subscription.ID)
+			case err != nil:
+				log.Printf("Failed to send %s push to subscription %s: %v", payload.Type, subscription.ID, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(stale) > 0 {
+		if err := s.subscriptionRepo.DeleteByIDs(ctx, stale); err != nil {
+			log.Printf("Failed to prune %d push subscriptions: %v", len(stale), err)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/auction-cards/backend/internal/cache"
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// similarCacheTTL is how long an auction's similar candidates are kept;
+	// what is listed changes slowly compared to that
+	similarCacheTTL = 15 * time.Minute
+
+	// recommendedCacheTTL is how long a user's candidates are kept, so new
+	// bids and watches show up in their recommendations soon
+	recommendedCacheTTL = 10 * time.Minute
+
+	// minTitleTermLength leaves out words too short to say much about a
+	// listing, such as "of" or "a"
+	minTitleTermLength = 3
+)
+
+// RecommendationService suggests active auctions: ones similar to an
+// auction, and ones a user may like going by what they bid on and watch.
+// Ranked candidate IDs are cached in Redis and loaded fresh per request,
+// so auctions that ended since are left out.
+type RecommendationService struct {
+	recommendationRepo repository.RecommendationRepository
+	auctionSvc         *AuctionService
+	cache              *cache.RedisCache
+}
+
+func NewRecommendationService(
+	recommendationRepo repository.RecommendationRepository,
+	auctionSvc *AuctionService,
+	cache *cache.RedisCache,
+) *RecommendationService {
+	return &RecommendationService{
+		recommendationRepo: recommendationRepo,
+		auctionSvc:         auctionSvc,
+		cache:              cache,
+	}
+}
+
+// Similar returns up to limit active auctions related to the given one by
+// category, title, price, condition and grader. Viewers need to be able to
+// see the auction.
+func (s *RecommendationService) Similar(ctx context.Context, auctionID, viewerID uuid.UUID, shareToken string, limit int) ([]domain.Auction, error) {
+	auction, err := s.auctionSvc.GetByID(ctx, auctionID, viewerID, shareToken)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := s.candidates(ctx, cache.SimilarAuctionsKey(auctionID), similarCacheTTL, func() ([]uuid.UUID, error) {
+		query := &domain.SimilarAuctionQuery{
+			AuctionID:  auction.ID,
+			CategoryID: auction.CategoryID,
+			Condition:  auction.Condition,
+			Price:      auction.CurrentPrice,
+			TitleQuery: titleQuery(auction.Title),
+		}
+		if auction.Certification != nil {
+			query.CertGrader = &auction.Certification.Grader
+		}
+		return s.recommendationRepo.GetSimilarCandidates(ctx, query, domain.MaxRecommendationLimit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.load(ctx, ids, viewerID, limit)
+}
+
+// Recommended returns up to limit active auctions for the user, going by
+// the categories and prices of what they bid on and watch
+func (s *RecommendationService) Recommended(ctx context.Context, userID uuid.UUID, limit int) ([]domain.Auction, error) {
+	ids, err := s.candidates(ctx, cache.RecommendedAuctionsKey(userID), recommendedCacheTTL, func() ([]uuid.UUID, error) {
+		return s.recommendationRepo.GetRecommendedCandidates(ctx, userID, domain.MaxRecommendationLimit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.load(ctx, ids, userID, limit)
+}
+
+// candidates returns the cached candidate IDs under key, ranking and
+// caching them on a miss
+func (s *RecommendationService) candidates(ctx context.Context, key string, ttl time.Duration, rank func() ([]uuid.UUID, error)) ([]uuid.UUID, error) {
+	if s.cache != nil {
+		var cached []uuid.UUID
+		if err := s.cache.GetJSON(ctx, key, &cached); err == nil && cached != nil {
+			return cached, nil
+		}
+	}
+
+	ids, err := rank()
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cache != nil {
+		_ = s.cache.SetJSON(ctx, key, ids, ttl)
+	}
+
+	return ids, nil
+}
+
+// load fetches the candidates in order, keeping the first limit that are
+// still open for bids
+func (s *RecommendationService) load(ctx context.Context, ids []uuid.UUID, viewerID uuid.UUID, limit int) ([]domain.Auction, error) {
+	if limit <= 0 || limit > domain.MaxRecommendationLimit {
+		limit = domain.DefaultRecommendationLimit
+	}
+
+	auctions := make([]domain.Auction, 0, limit)
+	if len(ids) == 0 {
+		return auctions, nil
+	}
+
+	batch, err := s.auctionSvc.GetByIDs(ctx, ids, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, auction := range batch.Auctions {
+		if auction.Status != domain.AuctionStatusActive || !auction.EndTime.After(now) {
+			continue
+		}
+		auctions = append(auctions, auction)
+		if len(auctions) == limit {
+			break
+		}
+	}
+
+	return auctions, nil
+}
+
+// titleQuery ORs the words of a title into a text search query. Words are
+// kept to letters and digits so they can't change the query's syntax.
+func titleQuery(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool, len(words))
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if len([]rune(word)) < minTitleTermLength || seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+	}
+
+	return strings.Join(terms, " | ")
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// SavedSearchService manages the searches users save for their digest
+type SavedSearchService struct {
+	savedSearchRepo repository.SavedSearchRepository
+}
+
+func NewSavedSearchService(savedSearchRepo repository.SavedSearchRepository) *SavedSearchService {
+	return &SavedSearchService{savedSearchRepo: savedSearchRepo}
+}
+
+func (s *SavedSearchService) Create(ctx context.Context, userID uuid.UUID, req *domain.CreateSavedSearchRequest) (*domain.SavedSearch, error) {
+	query := req.Query
+	if query.Search != nil {
+		search := strings.TrimSpace(*query.Search)
+		query.Search = &search
+		if search == "" {
+			query.Search = nil
+		}
+	}
+	if query.MinPrice != nil && query.MaxPrice != nil && query.MinPrice.GreaterThan(*query.MaxPrice) {
+		return nil, domain.ErrValidation
+	}
+
+	count, err := s.savedSearchRepo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= domain.MaxSavedSearches {
+		return nil, domain.ErrSavedSearchLimit
+	}
+
+	search := &domain.SavedSearch{
+		UserID: userID,
+		Name:   strings.TrimSpace(req.Name),
+		Query:  query,
+	}
+	if err := s.savedSearchRepo.Create(ctx, search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+func (s *SavedSearchService) List(ctx context.Context, userID uuid.UUID) ([]domain.SavedSearch, error) {
+	return s.savedSearchRepo.ListByUser(ctx, userID)
+}
+
+func (s *SavedSearchService) Delete(ctx context.Context, userID, searchID uuid.UUID) error {
+	return s.savedSearchRepo.Delete(ctx, userID, searchID)
+}
@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/auction-cards/backend/internal/cache"
@@ -14,36 +17,410 @@ import (
 type SchedulerService struct {
 	auctionRepo     repository.AuctionRepository
 	bidRepo         repository.BidRepository
+	messageRepo     repository.MessageRepository
 	notificationSvc *NotificationService
+	webhookSvc      *WebhookService
+	orderSvc        *OrderService
+	snipeSvc        *SnipeService
+	offerSvc        *OfferService
+	importSvc       *ImportService
+	privacySvc      *PrivacyService
+	factsheetSvc    *FactsheetService
+	curationSvc     *CurationService
+	priceDropSvc    *PriceDropService
+	ledgerSvc       *LedgerService
+	maintenanceSvc  *MaintenanceService
+	apiUsageSvc     *APIUsageService
+	fraudSvc        *FraudService
+	digestSvc       *DigestService
 	cache           *cache.RedisCache
+	leader          *LeaderElector
+	batchSize       int
+	workers         int
+	batchDelay      time.Duration
+	retentionDays   int
 	stopChan        chan struct{}
+	wg              sync.WaitGroup
 }
 
 func NewSchedulerService(
 	auctionRepo repository.AuctionRepository,
 	bidRepo repository.BidRepository,
+	messageRepo repository.MessageRepository,
 	notificationSvc *NotificationService,
+	webhookSvc *WebhookService,
+	orderSvc *OrderService,
+	snipeSvc *SnipeService,
+	offerSvc *OfferService,
+	importSvc *ImportService,
+	privacySvc *PrivacyService,
+	factsheetSvc *FactsheetService,
+	curationSvc *CurationService,
+	priceDropSvc *PriceDropService,
+	ledgerSvc *LedgerService,
+	maintenanceSvc *MaintenanceService,
+	apiUsageSvc *APIUsageService,
+	fraudSvc *FraudService,
+	digestSvc *DigestService,
 	cache *cache.RedisCache,
+	leader *LeaderElector,
+	batchSize int,
+	workers int,
+	batchDelay time.Duration,
+	messageRetentionDays int,
 ) *SchedulerService {
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+
 	return &SchedulerService{
 		auctionRepo:     auctionRepo,
 		bidRepo:         bidRepo,
+		messageRepo:     messageRepo,
 		notificationSvc: notificationSvc,
+		webhookSvc:      webhookSvc,
+		orderSvc:        orderSvc,
+		snipeSvc:        snipeSvc,
+		offerSvc:        offerSvc,
+		importSvc:       importSvc,
+		privacySvc:      privacySvc,
+		factsheetSvc:    factsheetSvc,
+		curationSvc:     curationSvc,
+		priceDropSvc:    priceDropSvc,
+		ledgerSvc:       ledgerSvc,
+		maintenanceSvc:  maintenanceSvc,
+		apiUsageSvc:     apiUsageSvc,
+		fraudSvc:        fraudSvc,
+		digestSvc:       digestSvc,
 		cache:           cache,
+		leader:          leader,
+		batchSize:       batchSize,
+		workers:         workers,
+		batchDelay:      batchDelay,
+		retentionDays:   messageRetentionDays,
 		stopChan:        make(chan struct{}),
 	}
 }
 
 func (s *SchedulerService) Start() {
-	go s.processEndingAuctions()
-	go s.sendEndingSoonNotifications()
+	s.run(s.processEndingAuctions)
+	s.run(s.sendEndingSoonNotifications)
+	s.run(s.sendQuietHoursDigests)
+	s.run(s.sendDigests)
+	s.run(s.processOrderDeadlines)
+	s.run(s.processSnipes)
+	s.run(s.processOffers)
+	s.run(s.processWebhooks)
+	s.run(s.processImports)
+	s.run(s.processDataExports)
+	s.run(s.processFactsheets)
+	s.run(s.refreshTrending)
+	s.run(s.flushViews)
+	s.run(s.processPriceDrops)
+	s.run(s.processEscrowReleases)
+	s.run(s.reconcileUnreadCounts)
+	s.run(s.purgeExpiredMessages)
+	s.run(s.rollupAPIUsage)
+	s.run(s.runFraudChecks)
 }
 
-func (s *SchedulerService) Stop() {
+func (s *SchedulerService) run(job func()) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		job()
+	}()
+}
+
+// Stop stops the jobs from picking up more work and waits for the batches
+// they are running to finish, giving up when ctx is done
+func (s *SchedulerService) Stop(ctx context.Context) error {
 	close(s.stopChan)
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("scheduled jobs still running: %w", ctx.Err())
+	}
+}
+
+// isLeader reports whether this instance runs the scheduled jobs. Only the
+// leader does, so running several instances doesn't duplicate work.
+func (s *SchedulerService) isLeader() bool {
+	return s.leader == nil || s.leader.IsLeader()
+}
+
+// inMaintenance reports whether maintenance mode is on or a planned
+// maintenance window is in effect. Scheduled jobs all write, so none run
+// until it is over.
+func (s *SchedulerService) inMaintenance() bool {
+	if s.maintenanceSvc == nil {
+		return false
+	}
+	ctx := context.Background()
+	return s.maintenanceSvc.Status(ctx).Enabled || s.maintenanceSvc.Window(ctx) != nil
 }
 
+// shouldRun reports whether scheduled jobs run on this instance right now
+func (s *SchedulerService) shouldRun() bool {
+	return s.isLeader() && !s.inMaintenance()
+}
+
+const (
+	// auctionEndTick is how often the Redis end schedule is checked
+	auctionEndTick = 250 * time.Millisecond
+	// auctionEndPollInterval is how often the database is scanned for ended
+	// auctions while the Redis schedule is unavailable
+	auctionEndPollInterval = 10 * time.Second
+	// auctionEndSweepInterval is how often the database is still scanned
+	// while the schedule works, catching anything it missed
+	auctionEndSweepInterval = 5 * time.Minute
+	// closeAuctionAttempts bounds how often closing an auction is retried
+	// while bids keep changing it
+	closeAuctionAttempts = 3
+)
+
+// processEndingAuctions ends auctions as they come due in the Redis end
+// schedule, falling back to polling the database when Redis is unavailable
 func (s *SchedulerService) processEndingAuctions() {
+	ticker := time.NewTicker(auctionEndTick)
+	defer ticker.Stop()
+
+	// The schedule is only trusted once seeded from the database, and is
+	// reseeded after any Redis failure since writes may have been missed
+	seeded := false
+	var lastPoll time.Time
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+		}
+
+		if !s.isLeader() {
+			// The schedule may change hands before this instance leads again
+			seeded = false
+			continue
+		}
+		// Windows that are over extend the auctions they held back before
+		// those get ended
+		if s.maintenanceSvc != nil {
+			s.maintenanceSvc.FinishWindows(context.Background(), time.Now())
+		}
+		// Auctions coming due wait, and get extended when maintenance ends
+		if s.inMaintenance() {
+			continue
+		}
+
+		if seeded {
+			if err := s.endScheduledAuctions(); err != nil {
+				log.Printf("Auction end schedule unavailable, polling the database: %v", err)
+				seeded = false
+			}
+		}
+
+		interval := auctionEndPollInterval
+		if seeded {
+			interval = auctionEndSweepInterval
+		}
+		if time.Since(lastPoll) < interval {
+			continue
+		}
+		lastPoll = time.Now()
+
+		if !seeded && s.cache != nil {
+			seeded = s.seedEndSchedule()
+		}
+		s.checkEndedAuctions()
+	}
+}
+
+// seedEndSchedule loads the end time of every active auction into the Redis
+// schedule
+func (s *SchedulerService) seedEndSchedule() bool {
+	ctx := context.Background()
+
+	ends, err := s.auctionRepo.GetActiveEndTimes(ctx)
+	if err != nil {
+		log.Printf("Error getting active auction end times: %v", err)
+		return false
+	}
+
+	if err := s.cache.ScheduleAuctionEnds(ctx, ends); err != nil {
+		log.Printf("Error seeding auction end schedule: %v", err)
+		return false
+	}
+
+	return true
+}
+
+// endScheduledAuctions pops one batch of due auctions from the schedule and
+// ends them. Entries are only hints: auctions extended since they were
+// scheduled go back in at their new end time and ones no longer active are
+// dropped.
+func (s *SchedulerService) endScheduledAuctions() error {
+	ctx := context.Background()
+	now := time.Now()
+
+	ids, err := s.cache.PopDueAuctionEnds(ctx, now, s.batchSize)
+	if err != nil {
+		return err
+	}
+
+	auctions := make([]domain.Auction, 0, len(ids))
+	for _, id := range ids {
+		auction, err := s.auctionRepo.GetByID(ctx, id)
+		if err != nil {
+			if !errors.Is(err, domain.ErrNotFound) {
+				log.Printf("Error getting scheduled auction %s: %v", id, err)
+				// Leave it to the database sweep rather than retrying hot
+			}
+			continue
+		}
+
+		if auction.Status != domain.AuctionStatusActive {
+			continue
+		}
+		if auction.EndTime.After(now) {
+			if err := s.cache.ScheduleAuctionEnd(ctx, id, auction.EndTime); err != nil {
+				return err
+			}
+			continue
+		}
+
+		auctions = append(auctions, *auction)
+	}
+
+	s.processInBatches(auctions, func(auction *domain.Auction) {
+		s.processAuctionEnd(ctx, auction)
+	})
+
+	return nil
+}
+
+// scheduleAuctionEnd adds an active auction to the Redis end schedule.
+// Failures are only logged since the database sweep still ends the auction,
+// just less promptly.
+func scheduleAuctionEnd(ctx context.Context, redisCache *cache.RedisCache, auction *domain.Auction) {
+	if redisCache == nil || auction.Status != domain.AuctionStatusActive {
+		return
+	}
+	if err := redisCache.ScheduleAuctionEnd(ctx, auction.ID, auction.EndTime); err != nil {
+		log.Printf("Error scheduling end of auction %s: %v", auction.ID, err)
+	}
+}
+
+// processSnipes polls every second so snipes fire within a second of their
+// scheduled time
+func (s *SchedulerService) processSnipes() {
+	if s.snipeSvc == nil {
+		return
+	}
+
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			if s.shouldRun() {
+				s.snipeSvc.ProcessDue(context.Background())
+			}
+		}
+	}
+}
+
+// processOffers expires offers that went unanswered or outlived their
+// auction
+func (s *SchedulerService) processOffers() {
+	if s.offerSvc == nil {
+		return
+	}
+
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			if s.shouldRun() {
+				s.offerSvc.ProcessExpired(context.Background())
+			}
+		}
+	}
+}
+
+// processWebhooks sends due webhook deliveries every few seconds and prunes
+// the delivery log hourly
+func (s *SchedulerService) processWebhooks() {
+	if s.webhookSvc == nil {
+		return
+	}
+
+	sendTicker := time.NewTicker(5 * time.Second)
+	defer sendTicker.Stop()
+	pruneTicker := time.NewTicker(1 * time.Hour)
+	defer pruneTicker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-sendTicker.C:
+			if s.shouldRun() {
+				s.webhookSvc.ProcessDeliveries(context.Background())
+			}
+		case <-pruneTicker.C:
+			if s.shouldRun() {
+				s.webhookSvc.PruneDeliveries(context.Background())
+			}
+		}
+	}
+}
+
+// processImports works through queued listing imports every few seconds
+func (s *SchedulerService) processImports() {
+	if s.importSvc == nil {
+		return
+	}
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			if s.shouldRun() {
+				s.importSvc.ProcessPending(context.Background())
+			}
+		}
+	}
+}
+
+// processDataExports assembles requested data exports and deletes the
+// archives of expired ones
+func (s *SchedulerService) processDataExports() {
+	if s.privacySvc == nil {
+		return
+	}
+
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
 
@@ -52,7 +429,227 @@ func (s *SchedulerService) processEndingAuctions() {
 		case <-s.stopChan:
 			return
 		case <-ticker.C:
-			s.checkEndedAuctions()
+			if s.shouldRun() {
+				s.privacySvc.ProcessExports(context.Background())
+				s.privacySvc.PurgeExpiredExports(context.Background())
+			}
+		}
+	}
+}
+
+// processFactsheets renders requested auction fact sheets
+func (s *SchedulerService) processFactsheets() {
+	if s.factsheetSvc == nil {
+		return
+	}
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			if s.shouldRun() {
+				s.factsheetSvc.ProcessPending(context.Background())
+			}
+		}
+	}
+}
+
+// refreshTrending recomputes the trending auctions shown on the homepage
+func (s *SchedulerService) refreshTrending() {
+	if s.curationSvc == nil {
+		return
+	}
+
+	ticker := time.NewTicker(trendingRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			if s.shouldRun() {
+				if _, err := s.curationSvc.RefreshTrending(context.Background()); err != nil {
+					log.Printf("Error refreshing trending auctions: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// viewFlushInterval is how often views buffered in Redis are written to
+// the database
+const viewFlushInterval = 30 * time.Second
+
+// flushViews writes the auction views counted in Redis to the database in
+// one batch, rather than an update per page view
+func (s *SchedulerService) flushViews() {
+	if s.cache == nil {
+		return
+	}
+
+	ticker := time.NewTicker(viewFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			if s.shouldRun() {
+				s.writePendingViews(context.Background())
+			}
+		}
+	}
+}
+
+func (s *SchedulerService) writePendingViews(ctx context.Context) {
+	views, err := s.cache.TakePendingViews(ctx)
+	if err != nil {
+		log.Printf("Error taking pending views: %v", err)
+		return
+	}
+
+	if err := s.auctionRepo.AddViews(ctx, views); err != nil {
+		log.Printf("Error writing %d auctions' views: %v", len(views), err)
+		// Keep them for the next flush
+		if err := s.cache.AddPendingViews(ctx, views); err != nil {
+			log.Printf("Error restoring pending views: %v", err)
+		}
+	}
+}
+
+// processPriceDrops applies sellers' scheduled buy-now markdowns
+func (s *SchedulerService) processPriceDrops() {
+	if s.priceDropSvc == nil {
+		return
+	}
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			if s.shouldRun() {
+				s.priceDropSvc.ProcessDue(context.Background())
+			}
+		}
+	}
+}
+
+// rollupAPIUsage copies finished days' usage counters from Redis to the
+// database
+func (s *SchedulerService) rollupAPIUsage() {
+	if s.apiUsageSvc == nil {
+		return
+	}
+
+	ticker := time.NewTicker(apiUsageRollupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			if s.shouldRun() {
+				s.apiUsageSvc.Rollup(context.Background())
+			}
+		}
+	}
+}
+
+// sendQuietHoursDigests sends users what was held during their quiet hours
+// once they end
+func (s *SchedulerService) sendQuietHoursDigests() {
+	ticker := time.NewTicker(quietHoursDigestInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			if s.shouldRun() {
+				if _, err := s.notificationSvc.SendQuietHoursDigests(context.Background(), quietHoursDigestBatch); err != nil {
+					log.Printf("Error sending quiet hours digests: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// sendDigests sends the daily and weekly digests as they fall due, at the
+// hour each user chose in their timezone
+func (s *SchedulerService) sendDigests() {
+	if s.digestSvc == nil {
+		return
+	}
+
+	ticker := time.NewTicker(digestInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			if s.shouldRun() {
+				if _, err := s.digestSvc.SendDue(context.Background(), time.Now(), digestBatch); err != nil {
+					log.Printf("Error sending digests: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// runFraudChecks looks for shill bidding and other fraud, flagging what it
+// finds for admins to review
+func (s *SchedulerService) runFraudChecks() {
+	if s.fraudSvc == nil {
+		return
+	}
+
+	ticker := time.NewTicker(fraudCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			if s.shouldRun() {
+				result := s.fraudSvc.RunChecks(context.Background())
+				log.Printf("Fraud checks flagged %v in %s", result.Flags, result.Duration)
+			}
+		}
+	}
+}
+
+// processEscrowReleases pays out escrow holds whose hold period has ended
+func (s *SchedulerService) processEscrowReleases() {
+	if s.ledgerSvc == nil {
+		return
+	}
+
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			if s.shouldRun() {
+				s.ledgerSvc.ProcessDueReleases(context.Background())
+			}
 		}
 	}
 }
@@ -67,41 +664,56 @@ func (s *SchedulerService) checkEndedAuctions() {
 		return
 	}
 
-	for _, auction := range auctions {
-		s.processAuctionEnd(ctx, &auction)
-	}
+	s.processInBatches(auctions, func(auction *domain.Auction) {
+		s.processAuctionEnd(ctx, auction)
+	})
 }
 
-func (s *SchedulerService) processAuctionEnd(ctx context.Context, auction *domain.Auction) {
-	// Get highest bid
-	highestBid, err := s.bidRepo.GetHighestBid(ctx, auction.ID)
-	if err != nil {
-		log.Printf("Error getting highest bid for auction %s: %v", auction.ID, err)
-		return
-	}
+// processInBatches runs fn over the auctions in batches of batchSize, at most
+// workers at a time. Each batch finishes before the next starts and batches
+// are spaced by batchDelay, so a burst of simultaneous endings is staggered
+// instead of hitting the database, Redis and notifications all at once.
+func (s *SchedulerService) processInBatches(auctions []domain.Auction, fn func(auction *domain.Auction)) {
+	sem := make(chan struct{}, s.workers)
 
-	var status domain.AuctionStatus
-	var winnerID *uuid.UUID
-	var winningBidID *uuid.UUID
+	for start := 0; start < len(auctions); start += s.batchSize {
+		if start > 0 && s.batchDelay > 0 {
+			select {
+			case <-s.stopChan:
+				return
+			case <-time.After(s.batchDelay):
+			}
+		}
 
-	if highestBid != nil {
-		// Check if reserve price was met
-		if auction.ReservePrice != nil && highestBid.Amount.LessThan(*auction.ReservePrice) {
-			status = domain.AuctionStatusUnsold
-		} else {
-			status = domain.AuctionStatusCompleted
-			winnerID = &highestBid.BidderID
-			winningBidID = &highestBid.ID
+		end := min(start+s.batchSize, len(auctions))
+
+		var wg sync.WaitGroup
+		for i := start; i < end; i++ {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(auction *domain.Auction) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				fn(auction)
+			}(&auctions[i])
 		}
-	} else {
-		status = domain.AuctionStatusUnsold
+		wg.Wait()
 	}
+}
 
-	// Update auction status
-	if err := s.auctionRepo.UpdateStatus(ctx, auction.ID, status, winnerID, winningBidID); err != nil {
-		log.Printf("Error updating auction status %s: %v", auction.ID, err)
+func (s *SchedulerService) processAuctionEnd(ctx context.Context, auction *domain.Auction) {
+	status, winnerID, winningBidID, closed := s.closeAuction(ctx, auction)
+	if !closed {
 		return
 	}
+	invalidateAuction(ctx, s.cache, auction.ID)
+
+	// Open an order for the winner to pay
+	if status == domain.AuctionStatusCompleted && winnerID != nil && s.orderSvc != nil {
+		if _, err := s.orderSvc.CreateForWinner(ctx, auction, *winnerID, winningBidID); err != nil {
+			log.Printf("Error creating order for auction %s: %v", auction.ID, err)
+		}
+	}
 
 	// Publish auction ended message
 	if s.cache != nil {
@@ -119,6 +731,14 @@ func (s *SchedulerService) processAuctionEnd(ctx context.Context, auction *domai
 		_ = s.cache.Publish(ctx, cache.AuctionChannel(auction.ID), message)
 	}
 
+	if s.webhookSvc != nil {
+		ended := *auction
+		ended.Status = status
+		ended.WinnerID = winnerID
+		ended.WinningBidID = winningBidID
+		s.webhookSvc.PublishAuctionEnded(ctx, &ended)
+	}
+
 	// Send notifications
 	if s.notificationSvc != nil {
 		if status == domain.AuctionStatusCompleted && winnerID != nil {
@@ -136,9 +756,65 @@ func (s *SchedulerService) processAuctionEnd(ctx context.Context, auction *domai
 	log.Printf("Processed auction end: %s, status: %s", auction.ID, status)
 }
 
+// closeAuction settles an auction on its highest bid, reporting whether this
+// run closed it. The close only goes through if the auction is unchanged
+// since it was read, so a bid, buy-now or extension landing in between
+// means reading it again: it may have been sold or no longer be due.
+func (s *SchedulerService) closeAuction(ctx context.Context, auction *domain.Auction) (domain.AuctionStatus, *uuid.UUID, *uuid.UUID, bool) {
+	for attempt := 1; ; attempt++ {
+		// Get highest bid
+		highestBid, err := s.bidRepo.GetHighestBid(ctx, auction.ID)
+		if err != nil {
+			log.Printf("Error getting highest bid for auction %s: %v", auction.ID, err)
+			return "", nil, nil, false
+		}
+
+		var status domain.AuctionStatus
+		var winnerID *uuid.UUID
+		var winningBidID *uuid.UUID
+
+		if highestBid != nil {
+			// Check if reserve price was met
+			if auction.ReservePrice != nil && highestBid.Amount.LessThan(*auction.ReservePrice) {
+				status = domain.AuctionStatusUnsold
+			} else {
+				status = domain.AuctionStatusCompleted
+				winnerID = &highestBid.BidderID
+				winningBidID = &highestBid.ID
+			}
+		} else {
+			status = domain.AuctionStatusUnsold
+		}
+
+		// Close the auction unless another run already has
+		closed, err := s.auctionRepo.CloseIfActive(ctx, auction.ID, auction.Version, status, winnerID, winningBidID)
+		if errors.Is(err, domain.ErrConcurrentBid) && attempt < closeAuctionAttempts {
+			current, err := s.auctionRepo.GetByID(ctx, auction.ID)
+			if err != nil {
+				log.Printf("Error reloading auction %s: %v", auction.ID, err)
+				return "", nil, nil, false
+			}
+			if current.Status != domain.AuctionStatusActive {
+				return "", nil, nil, false
+			}
+			if current.EndTime.After(time.Now()) {
+				scheduleAuctionEnd(ctx, s.cache, current)
+				return "", nil, nil, false
+			}
+			*auction = *current
+			continue
+		}
+		if err != nil {
+			log.Printf("Error updating auction status %s: %v", auction.ID, err)
+			return "", nil, nil, false
+		}
+		return status, winnerID, winningBidID, closed
+	}
+}
+
 func (s *SchedulerService) notifyLosingBidders(ctx context.Context, auction *domain.Auction, winnerID uuid.UUID) {
 	// Get all bids and notify unique bidders (except winner)
-	bids, _, err := s.bidRepo.GetByAuctionID(ctx, auction.ID, 1, 1000) // Get all bids
+	bids, _, err := s.bidRepo.GetByAuctionID(ctx, auction.ID, &domain.BidListParams{Page: 1, Limit: 1000}) // Get all bids
 	if err != nil {
 		return
 	}
@@ -164,7 +840,9 @@ func (s *SchedulerService) sendEndingSoonNotifications() {
 		case <-s.stopChan:
 			return
 		case <-ticker.C:
-			s.checkAuctionsEndingSoon()
+			if s.shouldRun() {
+				s.checkAuctionsEndingSoon()
+			}
 		}
 	}
 }
@@ -181,10 +859,114 @@ func (s *SchedulerService) checkAuctionsEndingSoon() {
 		return
 	}
 
-	for _, auction := range auctions {
+	s.processInBatches(auctions, func(auction *domain.Auction) {
 		// Only notify for auctions that haven't ended yet
 		if auction.EndTime.After(time.Now()) && auction.Status == domain.AuctionStatusActive {
-			s.notificationSvc.NotifyAuctionEnding(ctx, &auction)
+			s.notificationSvc.NotifyAuctionEnding(ctx, auction)
 		}
+	})
+}
+
+func (s *SchedulerService) processOrderDeadlines() {
+	if s.orderSvc == nil {
+		return
+	}
+
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			if !s.shouldRun() {
+				continue
+			}
+			ctx := context.Background()
+			s.orderSvc.ProcessUnpaidOrders(ctx)
+			s.orderSvc.ProcessExpiredOffers(ctx)
+		}
+	}
+}
+
+// reconcileUnreadCounts periodically repairs drift in the denormalized unread
+// message counters
+func (s *SchedulerService) reconcileUnreadCounts() {
+	if s.messageRepo == nil {
+		return
+	}
+
+	ticker := time.NewTicker(1 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			if !s.shouldRun() {
+				continue
+			}
+			fixed, err := s.messageRepo.ReconcileUnreadCounts(context.Background())
+			if err != nil {
+				log.Printf("Failed to reconcile unread counts: %v", err)
+				continue
+			}
+			if fixed > 0 {
+				log.Printf("Reconciled %d unread message counters", fixed)
+			}
+		}
+	}
+}
+
+// purgeExpiredMessages periodically deletes messages past their retention
+// window, refreshing legal holds first so conversations under dispute are
+// never purged
+func (s *SchedulerService) purgeExpiredMessages() {
+	if s.messageRepo == nil {
+		return
+	}
+
+	ticker := time.NewTicker(1 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			if s.shouldRun() {
+				s.deleteExpiredMessages()
+			}
+		}
+	}
+}
+
+func (s *SchedulerService) deleteExpiredMessages() {
+	ctx := context.Background()
+
+	if _, err := s.messageRepo.SyncLegalHolds(ctx); err != nil {
+		log.Printf("Failed to sync legal holds, skipping message purge: %v", err)
+		return
+	}
+
+	// Delete in batches to keep transactions and locks short
+	const batchSize = 1000
+	var total int64
+	for {
+		deleted, err := s.messageRepo.DeleteExpiredMessages(ctx, s.retentionDays, batchSize)
+		if err != nil {
+			log.Printf("Failed to delete expired messages: %v", err)
+			break
+		}
+		total += deleted
+		if deleted < batchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Printf("Deleted %d messages past their retention window", total)
 	}
 }
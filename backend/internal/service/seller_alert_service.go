@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// SellerAlertService lets sellers choose which bids on each listing they
+// are notified of. The notification service applies the choice as bids
+// come in.
+type SellerAlertService struct {
+	bidAlertRepo repository.SellerBidAlertRepository
+	auctionRepo  repository.AuctionRepository
+}
+
+func NewSellerAlertService(
+	bidAlertRepo repository.SellerBidAlertRepository,
+	auctionRepo repository.AuctionRepository,
+) *SellerAlertService {
+	return &SellerAlertService{
+		bidAlertRepo: bidAlertRepo,
+		auctionRepo:  auctionRepo,
+	}
+}
+
+// GetAlerts returns the seller's alerts for their listing, or the defaults
+// when they haven't chosen any
+func (s *SellerAlertService) GetAlerts(ctx context.Context, auctionID, sellerID uuid.UUID) (*domain.SellerBidAlerts, error) {
+	if _, err := s.getOwnAuction(ctx, auctionID, sellerID); err != nil {
+		return nil, err
+	}
+
+	alerts, err := s.bidAlertRepo.Get(ctx, auctionID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.DefaultSellerBidAlerts(auctionID), nil
+	}
+	return alerts, err
+}
+
+// SetAlerts replaces the seller's alerts for their draft or active listing
+func (s *SellerAlertService) SetAlerts(ctx context.Context, auctionID, sellerID uuid.UUID, req *domain.SetSellerBidAlertsRequest) (*domain.SellerBidAlerts, error) {
+	auction, err := s.getOwnAuction(ctx, auctionID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.Status != domain.AuctionStatusDraft && auction.Status != domain.AuctionStatusActive {
+		return nil, domain.ErrAuctionNotActive
+	}
+
+	alerts := &domain.SellerBidAlerts{
+		AuctionID:  auctionID,
+		FirstBid:   req.FirstBid,
+		ReserveMet: req.ReserveMet,
+		EveryNBids: req.EveryNBids,
+	}
+	if req.PriceAbove != nil {
+		price, err := domain.ParseAmount(*req.PriceAbove)
+		if err != nil {
+			return nil, err
+		}
+		alerts.PriceAbove = &price
+	}
+
+	if err := s.bidAlertRepo.Save(ctx, alerts); err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+// ResetAlerts goes back to notifying the seller of every bid
+func (s *SellerAlertService) ResetAlerts(ctx context.Context, auctionID, sellerID uuid.UUID) (*domain.SellerBidAlerts, error) {
+	if _, err := s.getOwnAuction(ctx, auctionID, sellerID); err != nil {
+		return nil, err
+	}
+
+	if err := s.bidAlertRepo.Delete(ctx, auctionID); err != nil {
+		return nil, err
+	}
+
+	return domain.DefaultSellerBidAlerts(auctionID), nil
+}
+
+func (s *SellerAlertService) getOwnAuction(ctx context.Context, auctionID, sellerID uuid.UUID) (*domain.Auction, error) {
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.SellerID != sellerID {
+		return nil, domain.ErrForbidden
+	}
+
+	return auction, nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// ShutdownCoordinator stops the server in order. Once Shutdown is called no
+// new bids are admitted; it waits for the bids already in flight and then
+// runs the registered steps in turn. Every step shares one deadline and is
+// expected to give up when it passes, reporting what it left unfinished.
+type ShutdownCoordinator struct {
+	mu       sync.Mutex
+	draining bool
+	inFlight int
+	idle     chan struct{}
+	steps    []shutdownStep
+}
+
+type shutdownStep struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// ShutdownStepResult is how one shutdown step went. Err says what was
+// abandoned when the step didn't finish.
+type ShutdownStepResult struct {
+	Name     string
+	Duration time.Duration
+	Err      error
+}
+
+// ShutdownReport lists the shutdown steps in the order they ran
+type ShutdownReport struct {
+	Steps []ShutdownStepResult
+}
+
+// Abandoned returns the steps that didn't finish
+func (r *ShutdownReport) Abandoned() []ShutdownStepResult {
+	var abandoned []ShutdownStepResult
+	for _, step := range r.Steps {
+		if step.Err != nil {
+			abandoned = append(abandoned, step)
+		}
+	}
+	return abandoned
+}
+
+func NewShutdownCoordinator() *ShutdownCoordinator {
+	return &ShutdownCoordinator{idle: make(chan struct{})}
+}
+
+// Begin admits a bid, returning false once shutdown has started. Otherwise
+// done must be called when the bid has finished.
+func (c *ShutdownCoordinator) Begin() (done func(), ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.draining {
+		return nil, false
+	}
+	c.inFlight++
+	return c.end, true
+}
+
+func (c *ShutdownCoordinator) end() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inFlight--
+	if c.draining && c.inFlight == 0 {
+		close(c.idle)
+	}
+}
+
+// OnShutdown adds a step to run after in-flight bids have drained. Steps run
+// in the order they were added.
+func (c *ShutdownCoordinator) OnShutdown(name string, stop func(ctx context.Context) error) {
+	c.steps = append(c.steps, shutdownStep{name: name, stop: stop})
+}
+
+// Shutdown stops admitting bids, waits for those in flight and runs each
+// step, logging how every one went. It must only be called once.
+func (c *ShutdownCoordinator) Shutdown(ctx context.Context) *ShutdownReport {
+	c.mu.Lock()
+	c.draining = true
+	if c.inFlight == 0 {
+		close(c.idle)
+	}
+	c.mu.Unlock()
+
+	steps := append([]shutdownStep{{name: "in-flight bids", stop: c.waitForBids}}, c.steps...)
+
+	report := &ShutdownReport{}
+	for _, step := range steps {
+		start := time.Now()
+		err := step.stop(ctx)
+		result := ShutdownStepResult{Name: step.name, Duration: time.Since(start), Err: err}
+		report.Steps = append(report.Steps, result)
+
+		if err != nil {
+			log.Printf("Shutdown: abandoned %s after %s: %v", step.name, result.Duration.Round(time.Millisecond), err)
+		} else {
+			log.Printf("Shutdown: stopped %s in %s", step.name, result.Duration.Round(time.Millisecond))
+		}
+	}
+
+	return report
+}
+
+func (c *ShutdownCoordinator) waitForBids(ctx context.Context) error {
+	select {
+	case <-c.idle:
+		return nil
+	case <-ctx.Done():
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return fmt.Errorf("%d bids still in flight: %w", c.inFlight, ctx.Err())
+	}
+}
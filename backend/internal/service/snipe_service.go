@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultSnipeLead is how long before the end a snipe fires when the
+	// user does not choose. It stays well inside AntiSnipingWindow, so a
+	// snipe always extends the auction like any other late bid.
+	DefaultSnipeLead = 8 * time.Second
+
+	// snipeRetries bounds how often a snipe retries after losing a race
+	// with a concurrent bid
+	snipeRetries = 3
+)
+
+type SnipeService struct {
+	snipeRepo       repository.SnipeRepository
+	auctionRepo     repository.AuctionRepository
+	bidRepo         repository.BidRepository
+	watchlistRepo   repository.WatchlistRepository
+	auditRepo       repository.AuditLogRepository
+	bidSvc          *BidService
+	notificationSvc *NotificationService
+}
+
+func NewSnipeService(
+	snipeRepo repository.SnipeRepository,
+	auctionRepo repository.AuctionRepository,
+	bidRepo repository.BidRepository,
+	watchlistRepo repository.WatchlistRepository,
+	auditRepo repository.AuditLogRepository,
+	bidSvc *BidService,
+	notificationSvc *NotificationService,
+) *SnipeService {
+	return &SnipeService{
+		snipeRepo:       snipeRepo,
+		auctionRepo:     auctionRepo,
+		bidRepo:         bidRepo,
+		watchlistRepo:   watchlistRepo,
+		auditRepo:       auditRepo,
+		bidSvc:          bidSvc,
+		notificationSvc: notificationSvc,
+	}
+}
+
+// Schedule creates or replaces the user's snipe on an auction. The auction
+// is added to the user's watchlist so it shows up alongside their snipes.
+func (s *SnipeService) Schedule(ctx context.Context, auctionID, userID uuid.UUID, req *domain.ScheduleSnipeRequest) (*domain.Snipe, error) {
+	maxAmount, err := domain.ParseAmount(req.MaxAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	lead := DefaultSnipeLead
+	if req.LeadSeconds != nil {
+		lead = time.Duration(*req.LeadSeconds) * time.Second
+	}
+
+	auction, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if auction.Status != domain.AuctionStatusActive {
+		return nil, domain.ErrAuctionNotActive
+	}
+
+	if auction.SellerID == userID {
+		return nil, domain.ErrSelfBidding
+	}
+
+	if time.Until(auction.EndTime) <= lead {
+		return nil, domain.ErrSnipeTooLate
+	}
+
+	if maxAmount.LessThan(auction.MinimumBid()) {
+		return nil, domain.ErrSnipeTooLow
+	}
+
+	snipe := &domain.Snipe{
+		AuctionID:   auctionID,
+		UserID:      userID,
+		MaxAmount:   maxAmount,
+		LeadSeconds: int(lead / time.Second),
+	}
+	if err := s.snipeRepo.Upsert(ctx, snipe); err != nil {
+		return nil, err
+	}
+
+	if err := s.watchlistRepo.Add(ctx, &domain.WatchlistItem{UserID: userID, AuctionID: auctionID}); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, &userID, domain.AuditActionSnipeSchedule, snipe, map[string]interface{}{
+		"max_amount":   snipe.MaxAmount.StringFixed(2),
+		"lead_seconds": snipe.LeadSeconds,
+	})
+
+	return snipe, nil
+}
+
+func (s *SnipeService) Cancel(ctx context.Context, auctionID, userID uuid.UUID) error {
+	snipe, err := s.snipeRepo.GetScheduled(ctx, auctionID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.snipeRepo.Cancel(ctx, snipe.ID); err != nil {
+		return err
+	}
+
+	snipe.Status = domain.SnipeStatusCancelled
+	s.audit(ctx, &userID, domain.AuditActionSnipeCancel, snipe, nil)
+
+	return nil
+}
+
+func (s *SnipeService) GetMySnipes(ctx context.Context, userID uuid.UUID) ([]domain.Snipe, error) {
+	return s.snipeRepo.GetByUser(ctx, userID)
+}
+
+// ProcessDue fires every snipe whose auction is inside its lead time
+func (s *SnipeService) ProcessDue(ctx context.Context) {
+	snipes, err := s.snipeRepo.GetDue(ctx, time.Now())
+	if err != nil {
+		log.Printf("Error getting due snipes: %v", err)
+		return
+	}
+
+	for i := range snipes {
+		s.execute(ctx, &snipes[i])
+	}
+}
+
+func (s *SnipeService) execute(ctx context.Context, snipe *domain.Snipe) {
+	claimed, err := s.snipeRepo.Claim(ctx, snipe.ID)
+	if err != nil {
+		log.Printf("Error claiming snipe %s: %v", snipe.ID, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	bid, auction, err := s.placeSnipeBid(ctx, snipe)
+
+	now := time.Now()
+	snipe.ExecutedAt = &now
+	metadata := map[string]interface{}{
+		"max_amount": snipe.MaxAmount.StringFixed(2),
+	}
+
+	if err != nil {
+		reason := err.Error()
+		snipe.Status = domain.SnipeStatusFailed
+		snipe.FailureReason = &reason
+		metadata["failure_reason"] = reason
+	} else {
+		snipe.Status = domain.SnipeStatusPlaced
+		snipe.BidID = &bid.ID
+		metadata["bid_id"] = bid.ID
+		metadata["amount"] = bid.Amount.StringFixed(2)
+	}
+	metadata["status"] = snipe.Status
+
+	if err := s.snipeRepo.Complete(ctx, snipe); err != nil {
+		log.Printf("Error completing snipe %s: %v", snipe.ID, err)
+	}
+
+	s.audit(ctx, nil, domain.AuditActionSnipeExecute, snipe, metadata)
+
+	if snipe.Status == domain.SnipeStatusFailed && auction != nil && s.notificationSvc != nil {
+		go s.notificationSvc.NotifySnipeFailed(context.Background(), snipe.UserID, auction, *snipe.FailureReason)
+	}
+}
+
+// placeSnipeBid bids the next minimum amount, up to the snipe's maximum,
+// retrying when a concurrent bid moves the price first
+func (s *SnipeService) placeSnipeBid(ctx context.Context, snipe *domain.Snipe) (*domain.Bid, *domain.Auction, error) {
+	var auction *domain.Auction
+	for attempt := 0; attempt < snipeRetries; attempt++ {
+		var err error
+		auction, err = s.auctionRepo.GetByID(ctx, snipe.AuctionID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		highest, err := s.bidRepo.GetHighestBid(ctx, snipe.AuctionID)
+		if err == nil && highest != nil && highest.BidderID == snipe.UserID {
+			return nil, auction, domain.ErrAlreadyHighBidder
+		}
+
+		amount := auction.MinimumBid()
+		if amount.GreaterThan(snipe.MaxAmount) {
+			return nil, auction, domain.ErrSnipeTooLow
+		}
+
+		response, err := s.bidSvc.PlaceBid(ctx, snipe.AuctionID, snipe.UserID, &domain.PlaceBidRequest{
+			Amount: amount.String(),
+		}, &domain.BidClient{Snipe: true})
+		if errors.Is(err, domain.ErrConcurrentBid) {
+			continue
+		}
+		if err != nil {
+			return nil, auction, err
+		}
+
+		return response.Bid, response.Auction, nil
+	}
+
+	return nil, auction, domain.ErrConcurrentBid
+}
+
+func (s *SnipeService) audit(ctx context.Context, actorID *uuid.UUID, action string, snipe *domain.Snipe, metadata map[string]interface{}) {
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadata["auction_id"] = snipe.AuctionID
+	metadata["user_id"] = snipe.UserID
+
+	err := s.auditRepo.Create(ctx, &domain.AuditLog{
+		ActorID:    actorID,
+		Action:     action,
+		EntityType: domain.AuditEntitySnipe,
+		EntityID:   &snipe.ID,
+		Metadata:   metadata,
+	})
+	if err != nil {
+		log.Printf("Error writing audit log for snipe %s: %v", snipe.ID, err)
+	}
+}
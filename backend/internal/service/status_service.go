@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/pkg/breaker"
+	"github.com/auction-cards/backend/internal/pkg/errorrate"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// How long an instance serves the same status, so a busy status page
+	// doesn't ping the database on every load
+	statusCacheTTL = 30 * time.Second
+
+	// Fewest requests in the window for an error rate to count; a handful of
+	// failures on a quiet component says little
+	statusMinRequests = 20
+	// Error rates at which a component is degraded, or considered down
+	statusDegradedErrorRate = 0.05
+	statusOutageErrorRate   = 0.25
+
+	// How far back admins see resolved incidents
+	statusIncidentAdminHistory = 90 * 24 * time.Hour
+)
+
+// Pinger checks that a dependency is reachable
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// StatusService reports how each part of the service is doing for a public
+// status page. Health comes from the same checks as readiness, this
+// instance's recent error rates, and incidents admins post.
+type StatusService struct {
+	incidentRepo repository.StatusIncidentRepository
+	db           Pinger
+	errorRates   *errorrate.Tracker
+	redisBreaker *breaker.Breaker
+	s3Breaker    *breaker.Breaker
+
+	mu        sync.Mutex
+	status    *domain.ServiceStatus
+	incidents []domain.StatusIncident
+}
+
+func NewStatusService(
+	incidentRepo repository.StatusIncidentRepository,
+	db Pinger,
+	errorRates *errorrate.Tracker,
+	redisBreaker *breaker.Breaker,
+	s3Breaker *breaker.Breaker,
+) *StatusService {
+	return &StatusService{
+		incidentRepo: incidentRepo,
+		db:           db,
+		errorRates:   errorRates,
+		redisBreaker: redisBreaker,
+		s3Breaker:    s3Breaker,
+	}
+}
+
+// Status returns the current status, at most statusCacheTTL old
+func (s *StatusService) Status(ctx context.Context) *domain.ServiceStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.status != nil && now.Sub(s.status.UpdatedAt) < statusCacheTTL {
+		return s.status
+	}
+
+	health := make(map[domain.StatusComponent]domain.ComponentHealth)
+	for _, component := range domain.StatusComponents {
+		health[component] = domain.ComponentOperational
+	}
+	worsen := func(to domain.ComponentHealth, components ...domain.StatusComponent) {
+		for _, component := range components {
+			if current, ok := health[component]; ok {
+				health[component] = current.Worse(to)
+			}
+		}
+	}
+
+	// Nothing works without the database
+	dbUp := true
+	if s.db != nil {
+		pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		defer cancel()
+		if err := s.db.Ping(pingCtx); err != nil {
+			dbUp = false
+			worsen(domain.ComponentOutage, domain.StatusComponents...)
+		}
+	}
+
+	// Bidding and websockets fall back to this instance without Redis, so
+	// they keep working but not across instances; uploads can't work
+	// without storage
+	if breakerOpen(s.redisBreaker) {
+		worsen(domain.ComponentDegraded, domain.StatusComponentBidding, domain.StatusComponentWebSocket)
+	}
+	if breakerOpen(s.s3Breaker) {
+		worsen(domain.ComponentOutage, domain.StatusComponentImageUploads)
+	}
+
+	rates := s.errorRates.Rates()
+	for _, component := range domain.StatusComponents {
+		rate := rates[string(component)]
+		if rate.Requests < statusMinRequests {
+			continue
+		}
+		switch ratio := rate.Ratio(); {
+		case ratio >= statusOutageErrorRate:
+			worsen(domain.ComponentOutage, component)
+		case ratio >= statusDegradedErrorRate:
+			worsen(domain.ComponentDegraded, component)
+		}
+	}
+
+	// Incidents can't be read while the database is down; the last ones
+	// read still stand
+	if dbUp {
+		incidents, err := s.incidentRepo.ListRecent(ctx, now.Add(-domain.StatusIncidentHistory))
+		if err != nil {
+			log.Printf("Error listing status incidents: %v", err)
+		} else {
+			s.incidents = incidents
+		}
+	}
+	incidents := s.incidents
+	if incidents == nil {
+		incidents = []domain.StatusIncident{}
+	}
+	for _, incident := range incidents {
+		if !incident.IsResolved() {
+			worsen(incident.Impact.Health(), incident.Components...)
+		}
+	}
+
+	status := &domain.ServiceStatus{
+		Status:                 domain.ComponentOperational,
+		Components:             make([]domain.ComponentStatus, 0, len(domain.StatusComponents)),
+		Incidents:              incidents,
+		ErrorRateWindowSeconds: int(s.errorRates.Window().Seconds()),
+		UpdatedAt:              now,
+	}
+	for _, component := range domain.StatusComponents {
+		rate := rates[string(component)]
+		status.Components = append(status.Components, domain.ComponentStatus{
+			Name:      component,
+			Status:    health[component],
+			ErrorRate: rate.Ratio(),
+			Requests:  rate.Requests,
+		})
+		status.Status = status.Status.Worse(health[component])
+	}
+
+	s.status = status
+	return status
+}
+
+// breakerOpen reports whether a breaker is failing calls fast
+func breakerOpen(b *breaker.Breaker) bool {
+	return b != nil && b.Stats().State == breaker.StateOpen
+}
+
+// ListIncidents returns unresolved incidents and those resolved in the last
+// 90 days, newest first
+func (s *StatusService) ListIncidents(ctx context.Context) ([]domain.StatusIncident, error) {
+	return s.incidentRepo.ListRecent(ctx, time.Now().Add(-statusIncidentAdminHistory))
+}
+
+func (s *StatusService) CreateIncident(ctx context.Context, adminID uuid.UUID, req *domain.CreateStatusIncidentRequest) (*domain.StatusIncident, error) {
+	incident := &domain.StatusIncident{
+		Title:     req.Title,
+		Message:   req.Message,
+		Status:    domain.IncidentInvestigating,
+		Impact:    domain.IncidentImpact(req.Impact),
+		CreatedBy: &adminID,
+	}
+	if req.Status != "" {
+		incident.Status = domain.IncidentStatus(req.Status)
+	}
+	incident.Components = incidentComponents(req.Components)
+	setIncidentResolvedAt(incident, time.Now())
+
+	if err := s.incidentRepo.Create(ctx, incident); err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+	return incident, nil
+}
+
+// UpdateIncident posts an update to an incident. Resolving it records when;
+// reopening it clears that.
+func (s *StatusService) UpdateIncident(ctx context.Context, id uuid.UUID, req *domain.UpdateStatusIncidentRequest) (*domain.StatusIncident, error) {
+	incident, err := s.incidentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		incident.Title = *req.Title
+	}
+	if req.Message != nil {
+		incident.Message = *req.Message
+	}
+	if req.Status != nil {
+		incident.Status = domain.IncidentStatus(*req.Status)
+	}
+	if req.Impact != nil {
+		incident.Impact = domain.IncidentImpact(*req.Impact)
+	}
+	if req.Components != nil {
+		incident.Components = incidentComponents(req.Components)
+	}
+	setIncidentResolvedAt(incident, time.Now())
+
+	if err := s.incidentRepo.Update(ctx, incident); err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+	return incident, nil
+}
+
+func (s *StatusService) DeleteIncident(ctx context.Context, id uuid.UUID) error {
+	if err := s.incidentRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.invalidate()
+	return nil
+}
+
+// invalidate makes the next status read fresh, so this instance shows an
+// incident change straight away; others catch up within statusCacheTTL
+func (s *StatusService) invalidate() {
+	s.mu.Lock()
+	s.status = nil
+	s.mu.Unlock()
+}
+
+// incidentComponents converts requested component names, dropping repeats
+func incidentComponents(names []string) []domain.StatusComponent {
+	components := make([]domain.StatusComponent, 0, len(names))
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			components = append(components, domain.StatusComponent(name))
+		}
+	}
+	return components
+}
+
+func setIncidentResolvedAt(incident *domain.StatusIncident, now time.Time) {
+	switch {
+	case incident.IsResolved() && incident.ResolvedAt == nil:
+		incident.ResolvedAt = &now
+	case !incident.IsResolved():
+		incident.ResolvedAt = nil
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/auction-cards/backend/internal/domain"
 	"github.com/auction-cards/backend/internal/repository"
@@ -33,11 +34,15 @@ func (s *UserService) GetProfile(ctx context.Context, userID uuid.UUID) (*domain
 	return s.userRepo.GetByID(ctx, userID)
 }
 
+// GetPublicProfile returns a user's profile; deleted accounts have none
 func (s *UserService) GetPublicProfile(ctx context.Context, userID uuid.UUID) (*domain.PublicUser, *domain.UserRatingSummary, error) {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, nil, err
 	}
+	if user.IsDeleted {
+		return nil, nil, domain.ErrNotFound
+	}
 
 	ratingSummary, err := s.ratingRepo.GetUserRatingSummary(ctx, userID)
 	if err != nil {
@@ -47,6 +52,56 @@ func (s *UserService) GetPublicProfile(ctx context.Context, userID uuid.UUID) (*
 	return user.ToPublic(), ratingSummary, nil
 }
 
+// GetPublicProfiles loads several public profiles, in request order. Banned
+// and deleted users are listed as missing, like IDs that don't exist.
+func (s *UserService) GetPublicProfiles(ctx context.Context, ids []uuid.UUID) (*domain.UserBatchResponse, error) {
+	ids = uniqueIDs(ids)
+	if len(ids) > domain.MaxBatchGetIDs {
+		return nil, domain.ErrValidation
+	}
+
+	users, err := s.userRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[uuid.UUID]*domain.User, len(users))
+	visible := make([]uuid.UUID, 0, len(users))
+	for i := range users {
+		if users[i].IsBanned || users[i].IsDeleted {
+			continue
+		}
+		found[users[i].ID] = &users[i]
+		visible = append(visible, users[i].ID)
+	}
+
+	summaries := make(map[uuid.UUID]*domain.UserRatingSummary)
+	if len(visible) > 0 {
+		if ratings, err := s.userRepo.GetRatingSummaries(ctx, visible); err == nil {
+			summaries = ratings
+		}
+	}
+
+	resp := &domain.UserBatchResponse{
+		Users:   make([]domain.UserProfile, 0, len(found)),
+		Missing: make([]uuid.UUID, 0),
+	}
+	for _, id := range ids {
+		user, ok := found[id]
+		if !ok {
+			resp.Missing = append(resp.Missing, id)
+			continue
+		}
+
+		rating := summaries[id]
+		if rating == nil {
+			rating = &domain.UserRatingSummary{UserID: id}
+		}
+		resp.Users = append(resp.Users, domain.UserProfile{User: user.ToPublic(), Rating: rating})
+	}
+
+	return resp, nil
+}
+
 func (s *UserService) UpdateProfile(ctx context.Context, userID uuid.UUID, req *domain.UpdateProfileRequest) (*domain.User, error) {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -60,6 +115,7 @@ func (s *UserService) UpdateProfile(ctx context.Context, userID uuid.UUID, req *
 			return nil, domain.ErrUsernameExists
 		}
 		user.Username = *req.Username
+		user.UsernameSetupRequired = false
 	}
 
 	if req.Bio != nil {
@@ -78,6 +134,13 @@ func (s *UserService) UpdateProfile(ctx context.Context, userID uuid.UUID, req *
 		user.AvatarURL = req.AvatarURL
 	}
 
+	if req.MessageRetentionDays != nil {
+		user.MessageRetentionDays = req.MessageRetentionDays
+		if *req.MessageRetentionDays == 0 {
+			user.MessageRetentionDays = nil
+		}
+	}
+
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, err
 	}
@@ -85,6 +148,85 @@ func (s *UserService) UpdateProfile(ctx context.Context, userID uuid.UUID, req *
 	return user, nil
 }
 
+// ChooseUsername sets the username of a user who signed up with a provider
+// and was given a generated one
+func (s *UserService) ChooseUsername(ctx context.Context, userID uuid.UUID, req *domain.ChooseUsernameRequest) (*domain.User, error) {
+	return s.UpdateProfile(ctx, userID, &domain.UpdateProfileRequest{Username: &req.Username})
+}
+
+// Vacations can't be scheduled further ahead or last longer than this, and
+// listings extended for one end this long after the seller is back
+const (
+	maxVacationLength   = 90 * 24 * time.Hour
+	vacationReturnGrace = 24 * time.Hour
+)
+
+// SetVacation puts the user's shop on pause for the requested period. In
+// extend mode, active listings that would end while they're away are moved
+// to end a day after they return.
+func (s *UserService) SetVacation(ctx context.Context, userID uuid.UUID, req *domain.SetVacationRequest) (*domain.VacationResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	startsAt := now
+	if req.StartsAt != nil && req.StartsAt.After(now) {
+		startsAt = *req.StartsAt
+	}
+	if !req.EndsAt.After(startsAt) || req.EndsAt.Sub(now) > maxVacationLength {
+		return nil, domain.ErrValidation
+	}
+
+	mode := req.ListingMode
+	if mode == "" {
+		mode = domain.VacationListingBanner
+	}
+
+	autoReply := req.AutoReply
+	if autoReply != nil && *autoReply == "" {
+		autoReply = nil
+	}
+
+	user.Vacation = domain.VacationSettings{
+		Enabled:     true,
+		StartsAt:    &startsAt,
+		EndsAt:      &req.EndsAt,
+		ListingMode: mode,
+		BlockBids:   req.BlockBids,
+		AutoReply:   autoReply,
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	resp := &domain.VacationResponse{Vacation: user.Vacation}
+	if mode == domain.VacationListingExtend {
+		extended, err := s.auctionRepo.ExtendForVacation(ctx, userID, startsAt, req.EndsAt, req.EndsAt.Add(vacationReturnGrace))
+		if err != nil {
+			return nil, err
+		}
+		resp.ExtendedAuctions = extended
+	}
+
+	return resp, nil
+}
+
+// EndVacation turns vacation mode off. Listings already extended keep their
+// new end times.
+func (s *UserService) EndVacation(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	user.Vacation.Enabled = false
+
+	return s.userRepo.Update(ctx, user)
+}
+
 // Watchlist methods
 
 func (s *UserService) GetWatchlist(ctx context.Context, userID uuid.UUID, page, limit int) (*domain.WatchlistResponse, error) {
@@ -100,6 +242,12 @@ func (s *UserService) GetWatchlist(ctx context.Context, userID uuid.UUID, page,
 		return nil, err
 	}
 
+	for i := range items {
+		if items[i].Auction != nil {
+			items[i].Auction.SetReserveStatus(items[i].Auction.SellerID == userID)
+		}
+	}
+
 	totalPages := (totalCount + limit - 1) / limit
 
 	return &domain.WatchlistResponse{
@@ -238,9 +386,10 @@ func (s *UserService) BanUser(ctx context.Context, userID uuid.UUID, ban bool) e
 
 func (s *UserService) GetUserAuctions(ctx context.Context, userID uuid.UUID, page, limit int) (*domain.AuctionListResponse, error) {
 	params := &domain.AuctionListParams{
-		SellerID: &userID,
-		Page:     page,
-		Limit:    limit,
+		SellerID:    &userID,
+		Page:        page,
+		Limit:       limit,
+		HidePrivate: true,
 	}
 
 	auctions, totalCount, err := s.auctionRepo.List(ctx, params)
@@ -248,6 +397,11 @@ func (s *UserService) GetUserAuctions(ctx context.Context, userID uuid.UUID, pag
 		return nil, err
 	}
 
+	// Public profile listing, so the reserve amount is never shown
+	for i := range auctions {
+		auctions[i].SetReserveStatus(false)
+	}
+
 	totalPages := (totalCount + limit - 1) / limit
 
 	return &domain.AuctionListResponse{
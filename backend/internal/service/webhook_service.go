@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/auction-cards/backend/internal/domain"
+	"github.com/auction-cards/backend/internal/pkg/webhook"
+	"github.com/auction-cards/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// webhookSendBatchSize deliveries are claimed per run and sent by up to
+	// webhookSendWorkers at once
+	webhookSendBatchSize = 50
+	webhookSendWorkers   = 8
+
+	// webhookDeliveryLease keeps a claimed delivery from being claimed again
+	// while it is being sent
+	webhookDeliveryLease = 5 * time.Minute
+
+	// Failed attempts are retried after webhookRetryBase, doubling each
+	// time up to webhookRetryMax
+	webhookRetryBase = 1 * time.Minute
+	webhookRetryMax  = 6 * time.Hour
+
+	// webhookDeliveryRetention bounds how long the delivery log is kept
+	webhookDeliveryRetention = 30 * 24 * time.Hour
+	webhookPruneBatchSize    = 1000
+
+	// maxWebhookErrorLength bounds the error stored with a failed attempt
+	maxWebhookErrorLength = 500
+)
+
+type WebhookService struct {
+	webhookRepo  repository.WebhookRepository
+	deliveryRepo repository.WebhookDeliveryRepository
+	client       *webhook.Client
+	maxAttempts  int
+	maxPerUser   int
+}
+
+func NewWebhookService(
+	webhookRepo repository.WebhookRepository,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	client *webhook.Client,
+	maxAttempts int,
+	maxPerUser int,
+) *WebhookService {
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	return &WebhookService{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		client:       client,
+		maxAttempts:  maxAttempts,
+		maxPerUser:   maxPerUser,
+	}
+}
+
+func (s *WebhookService) List(ctx context.Context, userID uuid.UUID) ([]domain.Webhook, error) {
+	return s.webhookRepo.ListByUser(ctx, userID)
+}
+
+func (s *WebhookService) Get(ctx context.Context, userID, webhookID uuid.UUID) (*domain.Webhook, error) {
+	return s.getOwn(ctx, userID, webhookID)
+}
+
+// Create registers a webhook and returns it with its signing secret, which
+// isn't shown again. Only admins may create global webhooks.
+func (s *WebhookService) Create(ctx context.Context, userID uuid.UUID, isAdmin bool, req *domain.CreateWebhookRequest) (*domain.WebhookSecretResponse, error) {
+	if req.Global && !isAdmin {
+		return nil, domain.ErrForbidden
+	}
+	if err := s.client.ValidateURL(req.URL); err != nil {
+		return nil, domain.ErrWebhookURL
+	}
+
+	if s.maxPerUser > 0 {
+		count, err := s.webhookRepo.CountByUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if count >= s.maxPerUser {
+			return nil, domain.ErrWebhookLimit
+		}
+	}
+
+	hook := &domain.Webhook{
+		UserID: userID,
+		URL:    req.URL,
+		Secret: generateToken(),
+		Events: uniqueEvents(req.Events),
+		Global: req.Global,
+		Active: true,
+	}
+	if err := s.webhookRepo.Create(ctx, hook); err != nil {
+		return nil, err
+	}
+
+	return &domain.WebhookSecretResponse{Webhook: hook, Secret: hook.Secret}, nil
+}
+
+func (s *WebhookService) Update(ctx context.Context, userID, webhookID uuid.UUID, req *domain.UpdateWebhookRequest) (*domain.Webhook, error) {
+	hook, err := s.getOwn(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := s.client.ValidateURL(*req.URL); err != nil {
+			return nil, domain.ErrWebhookURL
+		}
+		hook.URL = *req.URL
+	}
+	if len(req.Events) > 0 {
+		hook.Events = uniqueEvents(req.Events)
+	}
+	if req.Active != nil {
+		hook.Active = *req.Active
+	}
+
+	if err := s.webhookRepo.Update(ctx, hook); err != nil {
+		return nil, err
+	}
+
+	return hook, nil
+}
+
+// RotateSecret replaces the signing secret. Deliveries still pending are
+// signed with the new one.
+func (s *WebhookService) RotateSecret(ctx context.Context, userID, webhookID uuid.UUID) (*domain.WebhookSecretResponse, error) {
+	hook, err := s.getOwn(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	hook.Secret = generateToken()
+	if err := s.webhookRepo.Update(ctx, hook); err != nil {
+		return nil, err
+	}
+
+	return &domain.WebhookSecretResponse{Webhook: hook, Secret: hook.Secret}, nil
+}
+
+func (s *WebhookService) Delete(ctx context.Context, userID, webhookID uuid.UUID) error {
+	if _, err := s.getOwn(ctx, userID, webhookID); err != nil {
+		return err
+	}
+
+	return s.webhookRepo.Delete(ctx, webhookID)
+}
+
+// ListDeliveries returns a page of the webhook's delivery log, newest first
+func (s *WebhookService) ListDeliveries(ctx context.Context, userID, webhookID uuid.UUID, params *domain.WebhookDeliveryListParams) ([]domain.WebhookDelivery, int, error) {
+	if _, err := s.getOwn(ctx, userID, webhookID); err != nil {
+		return nil, 0, err
+	}
+
+	return s.deliveryRepo.ListByWebhook(ctx, webhookID, params)
+}
+
+// RetryDelivery sends a failed delivery again, starting a new round of
+// attempts
+func (s *WebhookService) RetryDelivery(ctx context.Context, userID, webhookID, deliveryID uuid.UUID) error {
+	if _, err := s.getOwn(ctx, userID, webhookID); err != nil {
+		return err
+	}
+
+	delivery, err := s.deliveryRepo.GetByID(ctx, deliveryID)
+	if err != nil {
+		return err
+	}
+	if delivery.WebhookID != webhookID {
+		return domain.ErrNotFound
+	}
+	if delivery.Status != domain.WebhookDeliveryFailed {
+		return domain.ErrDeliveryNotFailed
+	}
+
+	return s.deliveryRepo.Retry(ctx, deliveryID)
+}
+
+// getOwn loads one of the user's webhooks. Other users' webhooks are
+// reported as not found so their IDs can't be probed.
+func (s *WebhookService) getOwn(ctx context.Context, userID, webhookID uuid.UUID) (*domain.Webhook, error) {
+	hook, err := s.webhookRepo.GetByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if hook.UserID != userID {
+		return nil, domain.ErrNotFound
+	}
+	return hook, nil
+}
+
+// PublishAuctionEnded queues auction.ended for the seller and the winner
+func (s *WebhookService) PublishAuctionEnded(ctx context.Context, auction *domain.Auction) {
+	recipients := []uuid.UUID{auction.SellerID}
+	if auction.WinnerID != nil {
+		recipients = append(recipients, *auction.WinnerID)
+	}
+
+	s.publish(ctx, domain.WebhookEventAuctionEnded, domain.WebhookAuctionEndedData{
+		AuctionID:  auction.ID,
+		SellerID:   auction.SellerID,
+		Title:      auction.Title,
+		Status:     auction.Status,
+		WinnerID:   auction.WinnerID,
+		FinalPrice: auction.CurrentPrice,
+		EndedAt:    auction.EndTime,
+	}, recipients...)
+}
+
+// PublishBidPlaced queues bid.placed for the seller. The bidder isn't
+// identified, as on the public bid history.
+func (s *WebhookService) PublishBidPlaced(ctx context.Context, auction *domain.Auction, bid *domain.Bid) {
+	s.publish(ctx, domain.WebhookEventBidPlaced, domain.WebhookBidPlacedData{
+		BidID:     bid.ID,
+		AuctionID: auction.ID,
+		Amount:    bid.Amount,
+		BidCount:  auction.BidCount,
+		PlacedAt:  bid.CreatedAt,
+	}, auction.SellerID)
+}
+
+// PublishOrderPaid queues order.paid for the buyer and the seller
+func (s *WebhookService) PublishOrderPaid(ctx context.Context, order *domain.Order) {
+	s.publish(ctx, domain.WebhookEventOrderPaid, domain.WebhookOrderPaidData{
+		OrderID:   order.ID,
+		AuctionID: order.AuctionID,
+		BuyerID:   order.BuyerID,
+		SellerID:  order.SellerID,
+		Amount:    order.Amount,
+		PaidAt:    order.PaidAt,
+	}, order.BuyerID, order.SellerID)
+}
+
+// publish queues a delivery of the event to every subscribed webhook of the
+// recipients, and to every global one. Failures are logged; the event that
+// triggered them has already happened.
+func (s *WebhookService) publish(ctx context.Context, event domain.WebhookEvent, data interface{}, recipients ...uuid.UUID) {
+	hooks, err := s.webhookRepo.ListSubscribed(ctx, event, recipients)
+	if err != nil {
+		log.Printf("Error finding webhooks for %s: %v", event, err)
+		return
+	}
+	if len(hooks) == 0 {
+		return
+	}
+
+	now := time.Now()
+	for _, hook := range hooks {
+		delivery := &domain.WebhookDelivery{
+			ID:            uuid.New(),
+			WebhookID:     hook.ID,
+			Event:         event,
+			NextAttemptAt: &now,
+		}
+		payload, err := json.Marshal(domain.WebhookPayload{
+			ID:        delivery.ID,
+			Event:     event,
+			CreatedAt: now,
+			Data:      data,
+		})
+		if err != nil {
+			log.Printf("Error encoding %s webhook payload: %v", event, err)
+			return
+		}
+		delivery.Payload = payload
+
+		if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+			log.Printf("Error queueing %s for webhook %s: %v", event, hook.ID, err)
+		}
+	}
+}
+
+// ProcessDeliveries sends the deliveries that are due
+func (s *WebhookService) ProcessDeliveries(ctx context.Context) {
+	now := time.Now()
+	deliveries, err := s.deliveryRepo.ClaimDue(ctx, now, now.Add(webhookDeliveryLease), webhookSendBatchSize)
+	if err != nil {
+		log.Printf("Error claiming webhook deliveries: %v", err)
+		return
+	}
+
+	sem := make(chan struct{}, webhookSendWorkers)
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			s.send(ctx, delivery)
+		}()
+	}
+	wg.Wait()
+}
+
+// send makes one attempt at the delivery and records the outcome, scheduling
+// a retry with exponential backoff until maxAttempts is reached
+func (s *WebhookService) send(ctx context.Context, delivery *domain.WebhookDelivery) {
+	delivery.Attempts++
+
+	var statusCode int
+	var err error
+	if delivery.Webhook.Active {
+		statusCode, err = s.client.Send(ctx, &webhook.Delivery{
+			ID:     delivery.ID.String(),
+			URL:    delivery.Webhook.URL,
+			Secret: delivery.Webhook.Secret,
+			Event:  string(delivery.Event),
+			Body:   delivery.Payload,
+		})
+	} else {
+		// Disabled after the event was queued; give up rather than wait
+		err = errors.New("webhook is disabled")
+		delivery.Attempts = s.maxAttempts
+	}
+
+	delivery.LastStatusCode = nil
+	if statusCode != 0 {
+		delivery.LastStatusCode = &statusCode
+	}
+
+	now := time.Now()
+	switch {
+	case err == nil:
+		delivery.Status = domain.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.LastError = nil
+	case delivery.Attempts >= s.maxAttempts:
+		delivery.Status = domain.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = webhookError(err)
+	default:
+		next := now.Add(webhookRetryDelay(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+		delivery.LastError = webhookError(err)
+	}
+
+	if err := s.deliveryRepo.RecordAttempt(ctx, delivery); err != nil {
+		log.Printf("Error recording webhook delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// PruneDeliveries deletes delivery log entries past the retention period
+func (s *WebhookService) PruneDeliveries(ctx context.Context) {
+	cutoff := time.Now().Add(-webhookDeliveryRetention)
+	for {
+		deleted, err := s.deliveryRepo.DeleteBefore(ctx, cutoff, webhookPruneBatchSize)
+		if err != nil {
+			log.Printf("Error pruning webhook deliveries: %v", err)
+			return
+		}
+		if deleted < webhookPruneBatchSize {
+			return
+		}
+	}
+}
+
+// webhookRetryDelay is how long to wait after the given number of failed
+// attempts
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBase
+	for i := 1; i < attempts && delay < webhookRetryMax; i++ {
+		delay *= 2
+	}
+	if delay > webhookRetryMax {
+		delay = webhookRetryMax
+	}
+	return delay
+}
+
+func webhookError(err error) *string {
+	msg := err.Error()
+	if len(msg) > maxWebhookErrorLength {
+		msg = msg[:maxWebhookErrorLength]
+	}
+	return &msg
+}
+
+// uniqueEvents drops repeated event names, keeping the first occurrence
+func uniqueEvents(events []string) []string {
+	seen := make(map[string]bool, len(events))
+	unique := make([]string, 0, len(events))
+	for _, e := range events {
+		if !seen[e] {
+			seen[e] = true
+			unique = append(unique, e)
+		}
+	}
+	return unique
+}
@@ -1,9 +1,13 @@
 package websocket
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"time"
 
+	"github.com/auction-cards/backend/internal/domain"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
@@ -20,6 +24,12 @@ const (
 
 	// Maximum message size allowed from peer
 	maxMessageSize = 512
+
+	// Maximum size of a subscription request, which may list many auctions
+	maxSubscribeMessageSize = 8192
+
+	// Most auctions one connection may follow
+	maxSubscriptions = 100
 )
 
 type Client struct {
@@ -28,6 +38,20 @@ type Client struct {
 	send      chan []byte
 	auctionID uuid.UUID
 	userID    uuid.UUID
+
+	// Set for a live event's auctioneer, who runs the event by sending
+	// commands
+	control bool
+
+	// Set for a client of /ws/auctions, which subscribes to auctions by
+	// sending requests
+	snapshots AuctionSnapshotter
+
+	// The signed-in user, or uuid.Nil for anonymous clients
+	viewerID uuid.UUID
+
+	// Auctions the client has subscribed to, only used by ReadPump
+	subscriptions map[uuid.UUID]bool
 }
 
 func NewClient(hub *Hub, conn *websocket.Conn, auctionID, userID uuid.UUID) *Client {
@@ -40,14 +64,42 @@ func NewClient(hub *Hub, conn *websocket.Conn, auctionID, userID uuid.UUID) *Cli
 	}
 }
 
+// NewLiveEventClient creates a client for a live event's room. Its control
+// flag lets the event's auctioneer send commands.
+func NewLiveEventClient(hub *Hub, conn *websocket.Conn, eventID, userID uuid.UUID, control bool) *Client {
+	client := NewClient(hub, conn, eventID, userID)
+	client.control = control
+	return client
+}
+
+// NewSubscriberClient creates a client of /ws/auctions, which follows the
+// auctions it subscribes to. viewerID is uuid.Nil for anonymous clients,
+// who may only follow public auctions.
+func NewSubscriberClient(hub *Hub, conn *websocket.Conn, viewerID uuid.UUID, snapshots AuctionSnapshotter) *Client {
+	userID := viewerID
+	if userID == uuid.Nil {
+		userID = uuid.New()
+	}
+
+	client := NewClient(hub, conn, uuid.Nil, userID)
+	client.snapshots = snapshots
+	client.viewerID = viewerID
+	client.subscriptions = make(map[uuid.UUID]bool)
+	return client
+}
+
 // ReadPump pumps messages from the websocket connection to the hub
 func (c *Client) ReadPump() {
 	defer func() {
-		c.hub.Unregister(c.auctionID, c)
+		c.hub.Unregister(c)
 		c.conn.Close()
 	}()
 
-	c.conn.SetReadLimit(maxMessageSize)
+	if c.snapshots != nil {
+		c.conn.SetReadLimit(maxSubscribeMessageSize)
+	} else {
+		c.conn.SetReadLimit(maxMessageSize)
+	}
 	c.conn.SetReadDeadline(time.Now().Add(pongWait))
 	c.conn.SetPongHandler(func(string) error {
 		c.conn.SetReadDeadline(time.Now().Add(pongWait))
@@ -55,18 +107,90 @@ func (c *Client) ReadPump() {
 	})
 
 	for {
-		_, _, err := c.conn.ReadMessage()
+		_, data, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("WebSocket error: %v", err)
 			}
 			break
 		}
-		// We don't process incoming messages from clients
-		// All bid submissions go through REST API
+
+		// Only subscription requests and a live event's auctioneer's
+		// commands are processed. All bid submissions go through REST API
+		switch {
+		case c.snapshots != nil:
+			var msg domain.WSClientMessage
+			if err := json.Unmarshal(data, &msg); err != nil {
+				continue
+			}
+			c.handleSubscriptions(&msg)
+		case c.control:
+			var cmd domain.LiveCommand
+			if err := json.Unmarshal(data, &cmd); err != nil {
+				continue
+			}
+			c.hub.HandleLiveCommand(c, &cmd)
+		}
 	}
 }
 
+func (c *Client) handleSubscriptions(msg *domain.WSClientMessage) {
+	for _, auctionID := range msg.AuctionIDs {
+		switch msg.Type {
+		case domain.WSClientSubscribe:
+			c.subscribe(auctionID)
+		case domain.WSClientUnsubscribe:
+			if c.subscriptions[auctionID] {
+				delete(c.subscriptions, auctionID)
+				c.hub.Unsubscribe(auctionID, c)
+			}
+		}
+	}
+}
+
+// subscribe follows an auction the client may see, sending its snapshot
+// first. Auctions it may not see are reported as not found.
+func (c *Client) subscribe(auctionID uuid.UUID) {
+	if c.subscriptions[auctionID] {
+		return
+	}
+	if len(c.subscriptions) >= maxSubscriptions {
+		c.hub.SendError(c, domain.WSErrorPayload{
+			Message:   fmt.Sprintf("A connection can follow at most %d auctions", maxSubscriptions),
+			AuctionID: &auctionID,
+		})
+		return
+	}
+
+	// Subscribed before the snapshot is taken, so no update is missed
+	c.hub.Subscribe(auctionID, c)
+
+	snapshot, err := c.snapshots.Snapshot(c.hub.ctx, auctionID, c.viewerID, "")
+	if err != nil {
+		c.hub.Unsubscribe(auctionID, c)
+
+		message := "Could not follow the auction"
+		if errors.Is(err, domain.ErrNotFound) {
+			message = "Auction not found"
+		} else {
+			log.Printf("Error taking snapshot of auction %s: %v", auctionID, err)
+		}
+		c.hub.SendError(c, domain.WSErrorPayload{Message: message, AuctionID: &auctionID})
+		return
+	}
+
+	c.subscriptions[auctionID] = true
+	c.hub.SendSnapshot(c, snapshot)
+}
+
+// WriteFirst writes a message straight to the connection. It must be called
+// before WritePump starts, and goes out ahead of everything the hub has
+// queued for the client since it registered.
+func (c *Client) WriteFirst(message interface{}) error {
+	c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return c.conn.WriteJSON(message)
+}
+
 // WritePump pumps messages from the hub to the websocket connection
 func (c *Client) WritePump() {
 	ticker := time.NewTicker(pingPeriod)
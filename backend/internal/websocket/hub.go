@@ -3,32 +3,84 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/auction-cards/backend/internal/cache"
+	"github.com/auction-cards/backend/internal/domain"
 	"github.com/google/uuid"
 )
 
+// LiveCommandHandler runs the commands a live event's auctioneer sends. It
+// is implemented by the live event service.
+type LiveCommandHandler interface {
+	OnLiveCommand(eventID, userID uuid.UUID, cmd *domain.LiveCommand) error
+}
+
+// AuctionSnapshotter takes the snapshot a client is sent when it subscribes
+// to an auction, and decides whether it may. It is implemented by the
+// auction service.
+type AuctionSnapshotter interface {
+	Snapshot(ctx context.Context, id, viewerID uuid.UUID, shareToken string) (*domain.WSAuctionSnapshotPayload, error)
+}
+
+// Hub fans out auction and live event updates to the clients connected to
+// this instance. It holds no state a client can't rebuild on another
+// instance: every update arrives through Redis, so each instance relays the
+// same messages whichever one a bid was placed on, and a reconnecting client
+// is sent a fresh snapshot. Load balancers need no session affinity.
 type Hub struct {
-	// Registered clients by auction ID
+	// Registered clients by auction ID, or by event ID for live events
 	auctions map[uuid.UUID]map[*Client]bool
 
+	// The rooms of every connected client. A client of /ws/auctions may be
+	// in any number of them, or none.
+	clients map[*Client]map[uuid.UUID]bool
+
+	// Updates held for clients that joined a room but haven't been sent its
+	// snapshot yet, so they follow it
+	held map[*Client]map[uuid.UUID][][]byte
+
 	// Register requests
 	register chan *subscription
 
+	// Requests to leave a room while staying connected
+	leave chan *subscription
+
 	// Unregister requests
-	unregister chan *subscription
+	unregister chan *Client
 
 	// Broadcast to auction
 	broadcast chan *auctionMessage
 
+	// Send to a single client
+	direct chan *clientMessage
+
+	// Snapshots for clients that subscribed to an auction
+	snapshot chan *clientSnapshot
+
+	// Countdown start requests
+	endTime chan *roomEndTime
+
 	// Mutex for thread-safe access
 	mu sync.RWMutex
 
 	// Redis cache for pub/sub
 	redis *cache.RedisCache
 
+	// Runs live event commands
+	live LiveCommandHandler
+
+	// End times of the auction rooms, kept current through extensions so
+	// countdown ticks can be sent
+	endTimes map[uuid.UUID]time.Time
+
+	// How often countdown ticks are sent
+	countdownInterval time.Duration
+
 	// Context for shutdown
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -37,106 +89,406 @@ type Hub struct {
 type subscription struct {
 	auctionID uuid.UUID
 	client    *Client
+
+	// Set when the client subscribed over its connection, rather than
+	// connecting to the room. Updates are then held until its snapshot.
+	subscribe bool
 }
 
 type auctionMessage struct {
+	// uuid.Nil sends the message to every client
 	auctionID uuid.UUID
 	message   []byte
 }
 
+type clientMessage struct {
+	client  *Client
+	message []byte
+}
+
+type clientSnapshot struct {
+	client   *Client
+	snapshot *domain.WSAuctionSnapshotPayload
+	message  []byte
+}
+
+type roomEndTime struct {
+	auctionID uuid.UUID
+	endTime   time.Time
+}
+
+// defaultCountdownInterval is how often clients are sent the time left
+const defaultCountdownInterval = 5 * time.Second
+
 func NewHub(redis *cache.RedisCache) *Hub {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Hub{
-		auctions:   make(map[uuid.UUID]map[*Client]bool),
-		register:   make(chan *subscription),
-		unregister: make(chan *subscription),
-		broadcast:  make(chan *auctionMessage, 256),
-		redis:      redis,
-		ctx:        ctx,
-		cancel:     cancel,
+		auctions:          make(map[uuid.UUID]map[*Client]bool),
+		clients:           make(map[*Client]map[uuid.UUID]bool),
+		held:              make(map[*Client]map[uuid.UUID][][]byte),
+		register:          make(chan *subscription),
+		leave:             make(chan *subscription),
+		unregister:        make(chan *Client),
+		broadcast:         make(chan *auctionMessage, 256),
+		direct:            make(chan *clientMessage, 64),
+		snapshot:          make(chan *clientSnapshot),
+		endTime:           make(chan *roomEndTime),
+		redis:             redis,
+		endTimes:          make(map[uuid.UUID]time.Time),
+		countdownInterval: defaultCountdownInterval,
+		ctx:               ctx,
+		cancel:            cancel,
 	}
 }
 
+// SetCountdownInterval sets how often countdown ticks are sent. It must be
+// called before Run.
+func (h *Hub) SetCountdownInterval(interval time.Duration) {
+	h.countdownInterval = interval
+}
+
 func (h *Hub) Run() {
 	// Start Redis subscriber
 	if h.redis != nil {
 		go h.subscribeToRedis()
 	}
 
+	countdown := time.NewTicker(h.countdownInterval)
+	defer countdown.Stop()
+
 	for {
 		select {
 		case <-h.ctx.Done():
 			return
 
+		case <-countdown.C:
+			h.sendCountdowns()
+
+		case update := <-h.endTime:
+			h.mu.Lock()
+			h.extendEndTime(update.auctionID, update.endTime)
+			h.mu.Unlock()
+
 		case sub := <-h.register:
 			h.mu.Lock()
-			if h.auctions[sub.auctionID] == nil {
-				h.auctions[sub.auctionID] = make(map[*Client]bool)
+			if sub.subscribe {
+				// The client may have been dropped for falling behind
+				if h.clients[sub.client] != nil {
+					h.join(sub.auctionID, sub.client)
+					if h.held[sub.client] == nil {
+						h.held[sub.client] = make(map[uuid.UUID][][]byte)
+					}
+					h.held[sub.client][sub.auctionID] = [][]byte{}
+				}
+			} else {
+				h.clients[sub.client] = make(map[uuid.UUID]bool)
+				if sub.auctionID != uuid.Nil {
+					h.join(sub.auctionID, sub.client)
+				}
 			}
-			h.auctions[sub.auctionID][sub.client] = true
 			h.mu.Unlock()
 			log.Printf("Client registered for auction %s", sub.auctionID)
 
-		case sub := <-h.unregister:
+		case sub := <-h.leave:
 			h.mu.Lock()
-			if clients, ok := h.auctions[sub.auctionID]; ok {
-				if _, ok := clients[sub.client]; ok {
-					delete(clients, sub.client)
-					close(sub.client.send)
-					if len(clients) == 0 {
-						delete(h.auctions, sub.auctionID)
+			h.leaveRoom(sub.auctionID, sub.client)
+			h.mu.Unlock()
+
+		case client := <-h.unregister:
+			h.mu.Lock()
+			h.drop(client)
+			h.mu.Unlock()
+			log.Printf("Client unregistered from auction %s", client.auctionID)
+
+		case msg := <-h.broadcast:
+			h.mu.Lock()
+			if msg.auctionID == uuid.Nil {
+				for client := range h.clients {
+					h.send(client, msg.message)
+				}
+			} else {
+				for client := range h.auctions[msg.auctionID] {
+					if held, ok := h.held[client][msg.auctionID]; ok {
+						if len(held) >= cap(client.send) {
+							h.drop(client)
+						} else {
+							h.held[client][msg.auctionID] = append(held, msg.message)
+						}
+						continue
 					}
+					h.send(client, msg.message)
 				}
 			}
 			h.mu.Unlock()
-			log.Printf("Client unregistered from auction %s", sub.auctionID)
 
-		case msg := <-h.broadcast:
-			h.mu.RLock()
-			if clients, ok := h.auctions[msg.auctionID]; ok {
-				for client := range clients {
-					select {
-					case client.send <- msg.message:
-					default:
-						// Client's buffer is full, close connection
-						close(client.send)
-						delete(clients, client)
+		case msg := <-h.direct:
+			// Skip clients that have gone, whose send channel is closed
+			h.mu.Lock()
+			if h.clients[msg.client] != nil {
+				h.send(msg.client, msg.message)
+			}
+			h.mu.Unlock()
+
+		case msg := <-h.snapshot:
+			// Only sent while the client is still waiting for it, i.e.
+			// hasn't unsubscribed or gone
+			h.mu.Lock()
+			auctionID := msg.snapshot.AuctionID
+			if held, ok := h.held[msg.client][auctionID]; ok {
+				delete(h.held[msg.client], auctionID)
+				for _, message := range append([][]byte{msg.message}, held...) {
+					if !h.send(msg.client, message) {
+						break
 					}
 				}
+				h.extendEndTime(auctionID, msg.snapshot.EndTime)
 			}
-			h.mu.RUnlock()
+			h.mu.Unlock()
+		}
+	}
+}
+
+// join adds a client to a room. The caller must hold h.mu.
+func (h *Hub) join(roomID uuid.UUID, client *Client) {
+	if h.auctions[roomID] == nil {
+		h.auctions[roomID] = make(map[*Client]bool)
+	}
+	h.auctions[roomID][client] = true
+	h.clients[client][roomID] = true
+}
+
+// leaveRoom takes a client out of a room, forgetting the room once it is
+// empty. The caller must hold h.mu.
+func (h *Hub) leaveRoom(roomID uuid.UUID, client *Client) {
+	delete(h.clients[client], roomID)
+	delete(h.held[client], roomID)
+
+	if clients, ok := h.auctions[roomID]; ok {
+		delete(clients, client)
+		if len(clients) == 0 {
+			delete(h.auctions, roomID)
+			delete(h.endTimes, roomID)
 		}
 	}
 }
 
+// drop disconnects a client from all its rooms and closes its send
+// channel. The caller must hold h.mu.
+func (h *Hub) drop(client *Client) {
+	rooms, ok := h.clients[client]
+	if !ok {
+		return
+	}
+
+	for roomID := range rooms {
+		h.leaveRoom(roomID, client)
+	}
+	delete(h.clients, client)
+	delete(h.held, client)
+	close(client.send)
+}
+
+// send queues a message for a client, dropping the client if its buffer is
+// full. The caller must hold h.mu.
+func (h *Hub) send(client *Client, message []byte) bool {
+	select {
+	case client.send <- message:
+		return true
+	default:
+		// Client's buffer is full, close connection
+		h.drop(client)
+		return false
+	}
+}
+
 func (h *Hub) Stop() {
 	h.cancel()
 }
 
+// drainPollInterval is how often Drain checks whether messages are still
+// waiting to be written
+const drainPollInterval = 20 * time.Millisecond
+
+// Drain waits for queued messages to reach the clients' connections, then
+// stops the hub. It gives up waiting when ctx is done.
+func (h *Hub) Drain(ctx context.Context) error {
+	defer h.Stop()
+	return waitForDrain(ctx, h.pending)
+}
+
+// pending counts the messages not yet written to a connection
+func (h *Hub) pending() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	n := len(h.broadcast) + len(h.direct)
+	for client := range h.clients {
+		n += len(client.send)
+	}
+	return n
+}
+
+func waitForDrain(ctx context.Context, pending func() int) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		n := pending()
+		if n == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d websocket messages unsent: %w", n, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// SetEndTime starts countdown ticks for an auction's room. Extensions
+// relayed through Redis move the end time later on. It goes through Run so
+// it lands after the registration that created the room.
+func (h *Hub) SetEndTime(auctionID uuid.UUID, endTime time.Time) {
+	h.endTime <- &roomEndTime{auctionID: auctionID, endTime: endTime}
+}
+
+// extendEndTime records an end time for a room with clients. End times only
+// move later, so a snapshot taken before an extension can't undo it.
+func (h *Hub) extendEndTime(auctionID uuid.UUID, endTime time.Time) {
+	if h.auctions[auctionID] == nil {
+		return
+	}
+	if current, ok := h.endTimes[auctionID]; !ok || endTime.After(current) {
+		h.endTimes[auctionID] = endTime
+	}
+}
+
+// trackEndTime follows extensions and endings of the auctions being counted
+// down
+func (h *Hub) trackEndTime(auctionID uuid.UUID, payload string) {
+	var message struct {
+		Type    domain.WSMessageType `json:"type"`
+		Payload json.RawMessage      `json:"payload"`
+	}
+	if err := json.Unmarshal([]byte(payload), &message); err != nil {
+		return
+	}
+
+	switch message.Type {
+	case domain.WSMessageAuctionExtended:
+		var extended domain.WSAuctionExtendedPayload
+		if err := json.Unmarshal(message.Payload, &extended); err != nil {
+			return
+		}
+		h.mu.Lock()
+		h.extendEndTime(auctionID, extended.NewEndTime)
+		h.mu.Unlock()
+	case domain.WSMessageAuctionEnded, domain.WSMessageAuctionCancelled:
+		h.mu.Lock()
+		delete(h.endTimes, auctionID)
+		h.mu.Unlock()
+	}
+}
+
+// sendCountdowns tells every client of a running auction how long is left
+// by the server's clock
+func (h *Hub) sendCountdowns() {
+	now := time.Now()
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for auctionID, endTime := range h.endTimes {
+		if endTime.Before(now) {
+			continue
+		}
+
+		data, err := json.Marshal(domain.WSMessage{
+			Type: domain.WSMessageCountdown,
+			Payload: domain.WSCountdownPayload{
+				AuctionID:   auctionID,
+				EndTime:     endTime,
+				ServerTime:  now,
+				RemainingMs: endTime.Sub(now).Milliseconds(),
+			},
+		})
+		if err != nil {
+			continue
+		}
+
+		for client := range h.auctions[auctionID] {
+			// A tick is skipped for a client that is behind or still
+			// waiting for the auction's snapshot; the next one corrects it
+			if _, ok := h.held[client][auctionID]; ok {
+				continue
+			}
+			select {
+			case client.send <- data:
+			default:
+			}
+		}
+	}
+}
+
+// Register connects a client to a room. Clients of /ws/auctions connect
+// to none, passing uuid.Nil, and subscribe to auctions later.
 func (h *Hub) Register(auctionID uuid.UUID, client *Client) {
 	h.register <- &subscription{auctionID: auctionID, client: client}
 }
 
-func (h *Hub) Unregister(auctionID uuid.UUID, client *Client) {
-	h.unregister <- &subscription{auctionID: auctionID, client: client}
+// Unregister disconnects a client from all its rooms
+func (h *Hub) Unregister(client *Client) {
+	h.unregister <- client
 }
 
-func (h *Hub) BroadcastToAuction(auctionID uuid.UUID, message interface{}) {
-	data, err := json.Marshal(message)
+// Subscribe adds a connected client to an auction's room. Its updates are
+// held until SendSnapshot, so the client should take the snapshot after
+// subscribing.
+func (h *Hub) Subscribe(auctionID uuid.UUID, client *Client) {
+	h.register <- &subscription{auctionID: auctionID, client: client, subscribe: true}
+}
+
+// Unsubscribe takes a client out of an auction's room
+func (h *Hub) Unsubscribe(auctionID uuid.UUID, client *Client) {
+	h.leave <- &subscription{auctionID: auctionID, client: client}
+}
+
+// SendSnapshot sends a client the snapshot of an auction it subscribed to,
+// followed by the updates held since, and starts its countdown ticks
+func (h *Hub) SendSnapshot(client *Client, snapshot *domain.WSAuctionSnapshotPayload) {
+	data, err := json.Marshal(domain.WSMessage{Type: domain.WSMessageSnapshot, Payload: snapshot})
 	if err != nil {
 		log.Printf("Error marshaling message: %v", err)
 		return
 	}
+	h.snapshot <- &clientSnapshot{client: client, snapshot: snapshot, message: data}
+}
+
+// SendError tells a single client a request of theirs failed
+func (h *Hub) SendError(client *Client, payload domain.WSErrorPayload) {
+	data, err := json.Marshal(domain.WSMessage{Type: domain.WSMessageError, Payload: payload})
+	if err != nil {
+		return
+	}
+	h.direct <- &clientMessage{client: client, message: data}
+}
 
+// deliver queues a message received from Redis for a room's local clients,
+// or for every local client given uuid.Nil.
+// Updates are published to Redis rather than delivered here directly, so
+// that clients on every instance receive them.
+func (h *Hub) deliver(roomID uuid.UUID, message []byte) {
 	h.broadcast <- &auctionMessage{
-		auctionID: auctionID,
-		message:   data,
+		auctionID: roomID,
+		message:   message,
 	}
 }
 
 func (h *Hub) subscribeToRedis() {
-	// Subscribe to all auction channels using pattern
-	pubsub := h.redis.Client().PSubscribe(h.ctx, "auction:*")
+	// Subscribe to all auction and live event channels using pattern, and to
+	// maintenance notices for every client
+	pubsub := h.redis.Client().PSubscribe(h.ctx, "auction:*", "live:*", cache.MaintenanceChannel)
 	defer pubsub.Close()
 
 	ch := pubsub.Channel()
@@ -145,24 +497,64 @@ func (h *Hub) subscribeToRedis() {
 		case <-h.ctx.Done():
 			return
 		case msg := <-ch:
-			// Extract auction ID from channel name (auction:{uuid})
-			channelName := msg.Channel
-			if len(channelName) > 8 {
-				auctionIDStr := channelName[8:] // Remove "auction:" prefix
-				auctionID, err := uuid.Parse(auctionIDStr)
-				if err != nil {
-					continue
-				}
+			if msg.Channel == cache.MaintenanceChannel {
+				h.broadcastMaintenance(msg.Payload)
+				continue
+			}
 
-				h.broadcast <- &auctionMessage{
-					auctionID: auctionID,
-					message:   []byte(msg.Payload),
-				}
+			// Extract the room ID from channel name (auction:{uuid} or
+			// live:{uuid})
+			_, roomIDStr, ok := strings.Cut(msg.Channel, ":")
+			if !ok {
+				continue
+			}
+			roomID, err := uuid.Parse(roomIDStr)
+			if err != nil {
+				continue
+			}
+
+			if strings.HasPrefix(msg.Channel, "auction:") {
+				h.trackEndTime(roomID, msg.Payload)
 			}
+
+			h.deliver(roomID, []byte(msg.Payload))
 		}
 	}
 }
 
+// broadcastMaintenance sends a maintenance status change to every client
+func (h *Hub) broadcastMaintenance(payload string) {
+	var status domain.MaintenanceStatus
+	if err := json.Unmarshal([]byte(payload), &status); err != nil {
+		log.Printf("Error decoding maintenance status: %v", err)
+		return
+	}
+
+	data, err := json.Marshal(domain.WSMessage{Type: domain.WSMessageMaintenance, Payload: status})
+	if err != nil {
+		log.Printf("Error marshaling message: %v", err)
+		return
+	}
+	h.deliver(uuid.Nil, data)
+}
+
+// SetLiveCommandHandler registers the runner of live event commands
+func (h *Hub) SetLiveCommandHandler(live LiveCommandHandler) {
+	h.live = live
+}
+
+// HandleLiveCommand runs a command sent by a live event's auctioneer,
+// replying to them alone if it fails
+func (h *Hub) HandleLiveCommand(client *Client, cmd *domain.LiveCommand) {
+	if h.live == nil {
+		return
+	}
+
+	if err := h.live.OnLiveCommand(client.auctionID, client.userID, cmd); err != nil {
+		h.SendError(client, domain.WSErrorPayload{Message: err.Error()})
+	}
+}
+
 func (h *Hub) GetClientCount(auctionID uuid.UUID) int {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
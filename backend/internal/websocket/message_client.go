@@ -1,9 +1,11 @@
 package websocket
 
 import (
+	"encoding/json"
 	"log"
 	"time"
 
+	"github.com/auction-cards/backend/internal/domain"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
@@ -40,15 +42,21 @@ func (c *MessageClient) ReadPump() {
 	})
 
 	for {
-		_, _, err := c.conn.ReadMessage()
+		_, data, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("WebSocket error: %v", err)
 			}
 			break
 		}
-		// We don't process incoming messages from clients
+
+		// Clients only send ephemeral events such as typing indicators
 		// All message submissions go through REST API
+		var event domain.MessageWSClientEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			continue
+		}
+		c.hub.HandleClientEvent(c.userID, &event)
 	}
 }
 
@@ -4,13 +4,30 @@ import (
 	"context"
 	"encoding/json"
 	"log"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/auction-cards/backend/internal/cache"
+	"github.com/auction-cards/backend/internal/domain"
 	"github.com/google/uuid"
 )
 
-// MessageHub manages WebSocket connections for messaging
+// Presence keys expire unless refreshed, so a crashed instance cannot keep
+// its users online forever
+const presenceTTL = 2 * pongWait
+
+// MessageEventHandler reacts to presence changes and client events. It is
+// implemented by the message service, which knows who should be told.
+type MessageEventHandler interface {
+	OnPresenceChange(userID uuid.UUID, online bool)
+	OnTyping(userID, conversationID uuid.UUID, typing bool)
+}
+
+// MessageHub manages WebSocket connections for messaging. With Redis it is
+// stateless like Hub: messages for a user are published to Redis and
+// delivered by whichever instances the user is connected to, and presence is
+// counted in Redis across instances.
 type MessageHub struct {
 	// Registered clients by user ID (one user can have multiple connections)
 	users map[uuid.UUID]map[*MessageClient]bool
@@ -27,9 +44,15 @@ type MessageHub struct {
 	// Mutex for thread-safe access
 	mu sync.RWMutex
 
+	// Last disconnect time per user when running without Redis
+	lastSeen map[uuid.UUID]time.Time
+
 	// Redis cache for pub/sub
 	redis *cache.RedisCache
 
+	// Receives presence changes and typing events
+	events MessageEventHandler
+
 	// Context for shutdown
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -52,6 +75,7 @@ func NewMessageHub(redis *cache.RedisCache) *MessageHub {
 		register:   make(chan *messageSubscription),
 		unregister: make(chan *messageSubscription),
 		sendToUser: make(chan *userMessage, 256),
+		lastSeen:   make(map[uuid.UUID]time.Time),
 		redis:      redis,
 		ctx:        ctx,
 		cancel:     cancel,
@@ -64,6 +88,9 @@ func (h *MessageHub) Run() {
 		go h.subscribeToRedis()
 	}
 
+	presenceTicker := time.NewTicker(pingPeriod)
+	defer presenceTicker.Stop()
+
 	for {
 		select {
 		case <-h.ctx.Done():
@@ -71,6 +98,7 @@ func (h *MessageHub) Run() {
 
 		case sub := <-h.register:
 			h.mu.Lock()
+			first := len(h.users[sub.userID]) == 0
 			if h.users[sub.userID] == nil {
 				h.users[sub.userID] = make(map[*MessageClient]bool)
 			}
@@ -78,20 +106,34 @@ func (h *MessageHub) Run() {
 			h.mu.Unlock()
 			log.Printf("Message client registered for user %s", sub.userID)
 
+			if h.markOnline(sub.userID, first) {
+				h.notifyPresence(sub.userID, true)
+			}
+
 		case sub := <-h.unregister:
+			removed, last := false, false
 			h.mu.Lock()
 			if clients, ok := h.users[sub.userID]; ok {
 				if _, ok := clients[sub.client]; ok {
 					delete(clients, sub.client)
 					close(sub.client.send)
+					removed = true
 					if len(clients) == 0 {
 						delete(h.users, sub.userID)
+						last = true
 					}
 				}
 			}
 			h.mu.Unlock()
 			log.Printf("Message client unregistered for user %s", sub.userID)
 
+			if removed && h.markOffline(sub.userID, last) {
+				h.notifyPresence(sub.userID, false)
+			}
+
+		case <-presenceTicker.C:
+			h.refreshPresence()
+
 		case msg := <-h.sendToUser:
 			h.mu.RLock()
 			if clients, ok := h.users[msg.userID]; ok {
@@ -114,6 +156,27 @@ func (h *MessageHub) Stop() {
 	h.cancel()
 }
 
+// Drain waits for queued messages to reach the clients' connections, then
+// stops the hub. It gives up waiting when ctx is done.
+func (h *MessageHub) Drain(ctx context.Context) error {
+	defer h.Stop()
+	return waitForDrain(ctx, h.pending)
+}
+
+// pending counts the messages not yet written to a connection
+func (h *MessageHub) pending() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	n := len(h.sendToUser)
+	for _, clients := range h.users {
+		for client := range clients {
+			n += len(client.send)
+		}
+	}
+	return n
+}
+
 func (h *MessageHub) Register(userID uuid.UUID, client *MessageClient) {
 	h.register <- &messageSubscription{userID: userID, client: client}
 }
@@ -130,21 +193,24 @@ func (h *MessageHub) SendToUser(userID uuid.UUID, message interface{}) {
 		return
 	}
 
-	h.sendToUser <- &userMessage{
-		userID:  userID,
-		message: data,
-	}
-
-	// Also publish to Redis for cross-instance delivery
+	// With Redis every instance, including this one, delivers via the
+	// subscriber; publishing and sending locally would deliver twice
 	if h.redis != nil {
 		channel := "message:" + userID.String()
 		h.redis.Client().Publish(h.ctx, channel, string(data))
+		return
+	}
+
+	h.sendToUser <- &userMessage{
+		userID:  userID,
+		message: data,
 	}
 }
 
 func (h *MessageHub) subscribeToRedis() {
-	// Subscribe to all message channels using pattern
-	pubsub := h.redis.Client().PSubscribe(h.ctx, "message:*")
+	// Subscribe to all message channels using pattern, and to maintenance
+	// notices for every client
+	pubsub := h.redis.Client().PSubscribe(h.ctx, "message:*", cache.MaintenanceChannel)
 	defer pubsub.Close()
 
 	ch := pubsub.Channel()
@@ -153,6 +219,11 @@ func (h *MessageHub) subscribeToRedis() {
 		case <-h.ctx.Done():
 			return
 		case msg := <-ch:
+			if msg.Channel == cache.MaintenanceChannel {
+				h.broadcastMaintenance(msg.Payload)
+				continue
+			}
+
 			// Extract user ID from channel name (message:{uuid})
 			channelName := msg.Channel
 			if len(channelName) > 8 {
@@ -162,30 +233,180 @@ func (h *MessageHub) subscribeToRedis() {
 					continue
 				}
 
-				// Only deliver if we have local connections for this user
-				h.mu.RLock()
-				if clients, ok := h.users[userID]; ok {
-					for client := range clients {
-						select {
-						case client.send <- []byte(msg.Payload):
-						default:
-							// Client's buffer is full
-						}
-					}
+				// Delivered through Run like local messages, which skips
+				// users without connections here
+				h.sendToUser <- &userMessage{
+					userID:  userID,
+					message: []byte(msg.Payload),
 				}
-				h.mu.RUnlock()
 			}
 		}
 	}
 }
 
-// IsUserOnline checks if a user has any active WebSocket connections
-func (h *MessageHub) IsUserOnline(userID uuid.UUID) bool {
+// broadcastMaintenance sends a maintenance status change to every local
+// client
+func (h *MessageHub) broadcastMaintenance(payload string) {
+	var status domain.MaintenanceStatus
+	if err := json.Unmarshal([]byte(payload), &status); err != nil {
+		log.Printf("Error decoding maintenance status: %v", err)
+		return
+	}
+
+	data, err := json.Marshal(domain.MessageWSPayload{
+		Type:        domain.MessageWSTypeMaintenance,
+		Maintenance: &status,
+	})
+	if err != nil {
+		log.Printf("Error marshaling message: %v", err)
+		return
+	}
+
 	h.mu.RLock()
-	defer h.mu.RUnlock()
+	for _, clients := range h.users {
+		for client := range clients {
+			select {
+			case client.send <- data:
+			default:
+				// Client's buffer is full
+			}
+		}
+	}
+	h.mu.RUnlock()
+}
 
+// SetEventHandler registers the receiver of presence and typing events
+func (h *MessageHub) SetEventHandler(events MessageEventHandler) {
+	h.events = events
+}
+
+// HandleClientEvent processes an event sent by a connected client
+func (h *MessageHub) HandleClientEvent(userID uuid.UUID, event *domain.MessageWSClientEvent) {
+	if h.events == nil || event.ConversationID == uuid.Nil {
+		return
+	}
+
+	switch event.Type {
+	case domain.MessageWSTypeTypingStarted:
+		h.events.OnTyping(userID, event.ConversationID, true)
+	case domain.MessageWSTypeTypingStopped:
+		h.events.OnTyping(userID, event.ConversationID, false)
+	}
+}
+
+func (h *MessageHub) notifyPresence(userID uuid.UUID, online bool) {
+	if h.events == nil {
+		return
+	}
+	// Handlers send through the hub, so they must not run on the hub loop
+	go h.events.OnPresenceChange(userID, online)
+}
+
+// markOnline records a new connection and reports whether the user just came
+// online. With Redis the connection count is shared by all instances.
+func (h *MessageHub) markOnline(userID uuid.UUID, firstLocal bool) bool {
+	if h.redis == nil {
+		return firstLocal
+	}
+
+	key := cache.PresenceKey(userID)
+	pipe := h.redis.Client().Pipeline()
+	incr := pipe.Incr(h.ctx, key)
+	pipe.Expire(h.ctx, key, presenceTTL)
+	if _, err := pipe.Exec(h.ctx); err != nil {
+		log.Printf("Error updating presence for user %s: %v", userID, err)
+		return firstLocal
+	}
+	return incr.Val() == 1
+}
+
+// markOffline records a closed connection and reports whether the user has
+// no connections left on any instance.
+func (h *MessageHub) markOffline(userID uuid.UUID, lastLocal bool) bool {
+	now := time.Now()
+
+	if h.redis == nil {
+		if lastLocal {
+			h.mu.Lock()
+			h.lastSeen[userID] = now
+			h.mu.Unlock()
+		}
+		return lastLocal
+	}
+
+	client := h.redis.Client()
+	remaining, err := client.Decr(h.ctx, cache.PresenceKey(userID)).Result()
+	if err != nil {
+		log.Printf("Error updating presence for user %s: %v", userID, err)
+		return lastLocal
+	}
+	if remaining > 0 {
+		return false
+	}
+
+	pipe := client.Pipeline()
+	pipe.Del(h.ctx, cache.PresenceKey(userID))
+	pipe.Set(h.ctx, cache.LastSeenKey(userID), now.Unix(), 0)
+	_, _ = pipe.Exec(h.ctx)
+	return true
+}
+
+// refreshPresence extends the presence TTL of every locally connected user
+func (h *MessageHub) refreshPresence() {
+	if h.redis == nil {
+		return
+	}
+
+	h.mu.RLock()
+	pipe := h.redis.Client().Pipeline()
+	for userID := range h.users {
+		pipe.Expire(h.ctx, cache.PresenceKey(userID), presenceTTL)
+	}
+	h.mu.RUnlock()
+
+	_, _ = pipe.Exec(h.ctx)
+}
+
+// IsUserOnline checks if a user has any active WebSocket connections on any
+// instance
+func (h *MessageHub) IsUserOnline(userID uuid.UUID) bool {
+	h.mu.RLock()
 	clients, ok := h.users[userID]
-	return ok && len(clients) > 0
+	h.mu.RUnlock()
+
+	if ok && len(clients) > 0 {
+		return true
+	}
+
+	if h.redis == nil {
+		return false
+	}
+
+	count, err := h.redis.Client().Get(h.ctx, cache.PresenceKey(userID)).Int64()
+	return err == nil && count > 0
+}
+
+// LastSeen returns when the user last disconnected, if known
+func (h *MessageHub) LastSeen(userID uuid.UUID) *time.Time {
+	if h.redis == nil {
+		h.mu.RLock()
+		defer h.mu.RUnlock()
+		if seen, ok := h.lastSeen[userID]; ok {
+			return &seen
+		}
+		return nil
+	}
+
+	val, err := h.redis.Client().Get(h.ctx, cache.LastSeenKey(userID)).Result()
+	if err != nil {
+		return nil
+	}
+	unix, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return nil
+	}
+	seen := time.Unix(unix, 0)
+	return &seen
 }
 
 // GetOnlineUserCount returns the number of users with active connections
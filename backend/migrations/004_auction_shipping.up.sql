@@ -0,0 +1,11 @@
+-- Seller-defined shipping options per auction
+CREATE TABLE auction_shipping (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    method VARCHAR(20) NOT NULL CHECK (method IN ('flat_rate', 'free', 'pickup_only')),
+    cost DECIMAL(12,2) NOT NULL DEFAULT 0 CHECK (cost >= 0),
+    regions TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_auction_shipping_auction ON auction_shipping(auction_id);
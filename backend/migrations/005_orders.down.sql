@@ -0,0 +1,11 @@
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'outbid', 'auction_won', 'auction_lost', 'auction_ending', 'new_bid', 'auction_sold'
+));
+
+DROP TABLE IF EXISTS second_chance_offers;
+DROP TABLE IF EXISTS unpaid_strikes;
+DROP TABLE IF EXISTS orders;
+
+ALTER TABLE auctions DROP COLUMN IF EXISTS relisted_from_id;
+ALTER TABLE auctions DROP COLUMN IF EXISTS auto_relist;
@@ -0,0 +1,62 @@
+-- Seller preference for relisting when a sale falls through
+ALTER TABLE auctions ADD COLUMN auto_relist BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE auctions ADD COLUMN relisted_from_id UUID REFERENCES auctions(id) ON DELETE SET NULL;
+
+-- Orders created for auction winners, awaiting payment
+CREATE TABLE orders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    buyer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    seller_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    bid_id UUID REFERENCES bids(id) ON DELETE SET NULL,
+    amount DECIMAL(12,2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending_payment' CHECK (status IN ('pending_payment', 'paid', 'cancelled')),
+    cancel_reason VARCHAR(50),
+    payment_due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    paid_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_orders_auction_id ON orders(auction_id);
+CREATE INDEX idx_orders_buyer_id ON orders(buyer_id);
+CREATE INDEX idx_orders_seller_id ON orders(seller_id);
+CREATE INDEX idx_orders_payment_due ON orders(payment_due_at) WHERE status = 'pending_payment';
+
+CREATE TRIGGER update_orders_updated_at BEFORE UPDATE ON orders
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Strikes recorded against winners who never paid
+CREATE TABLE unpaid_strikes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(order_id)
+);
+
+CREATE INDEX idx_unpaid_strikes_user_id ON unpaid_strikes(user_id);
+
+-- Second-chance offers made to the runner-up after a cancelled order
+CREATE TABLE second_chance_offers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    bidder_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    bid_id UUID REFERENCES bids(id) ON DELETE SET NULL,
+    amount DECIMAL(12,2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'expired')),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_second_chance_offers_bidder ON second_chance_offers(bidder_id, status);
+CREATE INDEX idx_second_chance_offers_expires ON second_chance_offers(expires_at) WHERE status = 'pending';
+
+-- New notification types
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'outbid', 'auction_won', 'auction_lost', 'auction_ending', 'new_bid', 'auction_sold',
+    'order_cancelled', 'second_chance_offer', 'auction_relisted'
+));
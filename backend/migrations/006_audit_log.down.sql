@@ -0,0 +1,7 @@
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'outbid', 'auction_won', 'auction_lost', 'auction_ending', 'new_bid', 'auction_sold',
+    'order_cancelled', 'second_chance_offer', 'auction_relisted'
+));
+
+DROP TABLE IF EXISTS audit_logs;
@@ -0,0 +1,60 @@
+-- Disputes opened by a buyer or seller on an order
+CREATE TABLE disputes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    opened_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    buyer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    seller_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(50) NOT NULL CHECK (reason IN ('item_not_received', 'not_as_described', 'payment_issue', 'other')),
+    description TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved')),
+    outcome VARCHAR(20) CHECK (outcome IN ('refund', 'uphold', 'partial')),
+    refund_amount DECIMAL(12,2),
+    resolution_note TEXT,
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- At most one open dispute per order
+CREATE UNIQUE INDEX idx_disputes_order_open ON disputes(order_id) WHERE status = 'open';
+CREATE INDEX idx_disputes_status ON disputes(status, created_at DESC);
+CREATE INDEX idx_disputes_buyer_id ON disputes(buyer_id);
+CREATE INDEX idx_disputes_seller_id ON disputes(seller_id);
+
+CREATE TRIGGER update_disputes_updated_at BEFORE UPDATE ON disputes
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Messages exchanged between the parties and admins
+CREATE TABLE dispute_messages (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    dispute_id UUID NOT NULL REFERENCES disputes(id) ON DELETE CASCADE,
+    sender_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    is_admin BOOLEAN NOT NULL DEFAULT FALSE,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_dispute_messages_dispute ON dispute_messages(dispute_id, created_at);
+
+-- Evidence files attached to a dispute
+CREATE TABLE dispute_evidence (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    dispute_id UUID NOT NULL REFERENCES disputes(id) ON DELETE CASCADE,
+    uploaded_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_dispute_evidence_dispute ON dispute_evidence(dispute_id);
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'outbid', 'auction_won', 'auction_lost', 'auction_ending', 'new_bid', 'auction_sold',
+    'order_cancelled', 'second_chance_offer', 'auction_relisted',
+    'auction_cancelled',
+    'dispute_opened', 'dispute_message', 'dispute_resolved'
+));
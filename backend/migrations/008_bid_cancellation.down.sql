@@ -0,0 +1,12 @@
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'outbid', 'auction_won', 'auction_lost', 'auction_ending', 'new_bid', 'auction_sold',
+    'order_cancelled', 'second_chance_offer', 'auction_relisted',
+    'auction_cancelled',
+    'dispute_opened', 'dispute_message', 'dispute_resolved'
+));
+
+DROP INDEX IF EXISTS idx_bids_auction_active;
+ALTER TABLE bids DROP COLUMN IF EXISTS cancel_reason;
+ALTER TABLE bids DROP COLUMN IF EXISTS cancelled_by;
+ALTER TABLE bids DROP COLUMN IF EXISTS cancelled_at;
@@ -0,0 +1,15 @@
+-- Seller-initiated bid cancellation
+ALTER TABLE bids ADD COLUMN cancelled_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE bids ADD COLUMN cancelled_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE bids ADD COLUMN cancel_reason TEXT;
+
+CREATE INDEX idx_bids_auction_active ON bids(auction_id, amount DESC) WHERE cancelled_at IS NULL;
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'outbid', 'auction_won', 'auction_lost', 'auction_ending', 'new_bid', 'auction_sold',
+    'order_cancelled', 'second_chance_offer', 'auction_relisted',
+    'auction_cancelled',
+    'dispute_opened', 'dispute_message', 'dispute_resolved',
+    'bid_cancelled'
+));
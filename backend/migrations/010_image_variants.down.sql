@@ -0,0 +1,3 @@
+ALTER TABLE auction_images DROP COLUMN IF EXISTS large_url;
+ALTER TABLE auction_images DROP COLUMN IF EXISTS medium_url;
+ALTER TABLE auction_images DROP COLUMN IF EXISTS thumbnail_url;
@@ -0,0 +1,4 @@
+-- Resized variants generated at upload time
+ALTER TABLE auction_images ADD COLUMN thumbnail_url TEXT;
+ALTER TABLE auction_images ADD COLUMN medium_url TEXT;
+ALTER TABLE auction_images ADD COLUMN large_url TEXT;
@@ -0,0 +1,3 @@
+-- When buy-now is withdrawn: never, after the first bid, or once the reserve is met
+ALTER TABLE auctions ADD COLUMN buy_now_rule VARCHAR(20) NOT NULL DEFAULT 'until_first_bid'
+    CHECK (buy_now_rule IN ('always', 'until_first_bid', 'until_reserve_met'));
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS auction_views;
+
+DROP TRIGGER IF EXISTS update_auctions_watch_count ON watchlist;
+DROP FUNCTION IF EXISTS update_auction_watch_count();
+
+ALTER TABLE auctions DROP COLUMN IF EXISTS watch_count;
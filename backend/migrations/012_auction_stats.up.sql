@@ -0,0 +1,30 @@
+-- Denormalized watcher count, kept in sync by triggers on watchlist
+ALTER TABLE auctions ADD COLUMN watch_count INTEGER NOT NULL DEFAULT 0;
+
+UPDATE auctions a SET watch_count = w.count
+FROM (SELECT auction_id, COUNT(*) AS count FROM watchlist GROUP BY auction_id) w
+WHERE a.id = w.auction_id;
+
+CREATE OR REPLACE FUNCTION update_auction_watch_count()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE auctions SET watch_count = watch_count + 1 WHERE id = NEW.auction_id;
+    ELSE
+        UPDATE auctions SET watch_count = GREATEST(watch_count - 1, 0) WHERE id = OLD.auction_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_auctions_watch_count AFTER INSERT OR DELETE ON watchlist
+    FOR EACH ROW EXECUTE FUNCTION update_auction_watch_count();
+
+-- Individual detail page views for seller analytics
+CREATE TABLE auction_views (
+    id BIGSERIAL PRIMARY KEY,
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    viewed_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_auction_views_auction_id ON auction_views(auction_id, viewed_at);
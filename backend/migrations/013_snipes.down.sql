@@ -0,0 +1,13 @@
+DELETE FROM notifications WHERE type = 'snipe_failed';
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'outbid', 'auction_won', 'auction_lost', 'auction_ending', 'new_bid', 'auction_sold',
+    'order_cancelled', 'second_chance_offer', 'auction_relisted',
+    'auction_cancelled',
+    'dispute_opened', 'dispute_message', 'dispute_resolved',
+    'bid_cancelled',
+    'reserve_met'
+));
+
+DROP TABLE IF EXISTS snipes;
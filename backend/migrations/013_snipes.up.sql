@@ -0,0 +1,33 @@
+-- Server-side "snipe for me" bids placed shortly before an auction ends
+CREATE TABLE snipes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    max_amount DECIMAL(12,2) NOT NULL,
+    lead_seconds INTEGER NOT NULL CHECK (lead_seconds BETWEEN 2 AND 60),
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled' CHECK (status IN ('scheduled', 'executing', 'placed', 'failed', 'cancelled')),
+    bid_id UUID REFERENCES bids(id) ON DELETE SET NULL,
+    failure_reason TEXT,
+    executed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- At most one pending snipe per user and auction
+CREATE UNIQUE INDEX idx_snipes_auction_user_scheduled ON snipes(auction_id, user_id) WHERE status = 'scheduled';
+CREATE INDEX idx_snipes_status ON snipes(status);
+CREATE INDEX idx_snipes_user_id ON snipes(user_id, created_at DESC);
+
+CREATE TRIGGER update_snipes_updated_at BEFORE UPDATE ON snipes
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'outbid', 'auction_won', 'auction_lost', 'auction_ending', 'new_bid', 'auction_sold',
+    'order_cancelled', 'second_chance_offer', 'auction_relisted',
+    'auction_cancelled',
+    'dispute_opened', 'dispute_message', 'dispute_resolved',
+    'bid_cancelled',
+    'reserve_met',
+    'snipe_failed'
+));
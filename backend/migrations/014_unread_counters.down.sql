@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_conversation_read_status_unread;
+
+DELETE FROM conversation_read_status WHERE last_read_at IS NULL;
+
+ALTER TABLE conversation_read_status ALTER COLUMN last_read_at SET DEFAULT NOW();
+ALTER TABLE conversation_read_status DROP COLUMN IF EXISTS unread_count;
@@ -0,0 +1,24 @@
+-- Denormalized unread counters, maintained on send and read, so unread
+-- badges don't have to count messages on every poll
+ALTER TABLE conversation_read_status ADD COLUMN unread_count INT NOT NULL DEFAULT 0;
+
+-- Recipients get a row on their first incoming message, before they have
+-- read anything
+ALTER TABLE conversation_read_status ALTER COLUMN last_read_at DROP DEFAULT;
+
+INSERT INTO conversation_read_status (conversation_id, user_id, last_read_at)
+SELECT c.id, p.user_id, NULL
+FROM conversations c
+CROSS JOIN LATERAL (VALUES (c.participant_one), (c.participant_two)) AS p(user_id)
+ON CONFLICT (conversation_id, user_id) DO NOTHING;
+
+UPDATE conversation_read_status rs
+SET unread_count = (
+    SELECT COUNT(*)
+    FROM messages m
+    WHERE m.conversation_id = rs.conversation_id
+        AND m.sender_id != rs.user_id
+        AND m.created_at > COALESCE(rs.last_read_at, '-infinity'::timestamptz)
+);
+
+CREATE INDEX idx_conversation_read_status_unread ON conversation_read_status(user_id) WHERE unread_count > 0;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_notifications_user_keyset;
+DROP INDEX IF EXISTS idx_bids_auction_keyset;
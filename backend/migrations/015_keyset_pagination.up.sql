@@ -0,0 +1,3 @@
+-- Keyset pagination walks (created_at, id) in descending order
+CREATE INDEX idx_bids_auction_keyset ON bids(auction_id, created_at DESC, id DESC) WHERE cancelled_at IS NULL;
+CREATE INDEX idx_notifications_user_keyset ON notifications(user_id, created_at DESC, id DESC);
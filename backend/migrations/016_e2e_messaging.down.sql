@@ -0,0 +1,9 @@
+ALTER TABLE messages DROP COLUMN IF EXISTS key_envelopes;
+
+DELETE FROM conversations WHERE encryption_mode = 'e2e';
+ALTER TABLE conversations DROP CONSTRAINT IF EXISTS conversations_participants_mode_key;
+ALTER TABLE conversations ADD CONSTRAINT conversations_participant_one_participant_two_key
+    UNIQUE (participant_one, participant_two);
+ALTER TABLE conversations DROP COLUMN IF EXISTS encryption_mode;
+
+DROP TABLE IF EXISTS device_keys;
@@ -0,0 +1,21 @@
+-- Public keys clients register per device for end-to-end encrypted messaging
+CREATE TABLE device_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_id VARCHAR(100) NOT NULL,
+    public_key TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(user_id, device_id)
+);
+
+-- A pair of users can have one server-encrypted and one E2E conversation
+ALTER TABLE conversations ADD COLUMN encryption_mode VARCHAR(10) NOT NULL DEFAULT 'server'
+    CHECK (encryption_mode IN ('server', 'e2e'));
+ALTER TABLE conversations DROP CONSTRAINT conversations_participant_one_participant_two_key;
+ALTER TABLE conversations ADD CONSTRAINT conversations_participants_mode_key
+    UNIQUE (participant_one, participant_two, encryption_mode);
+
+-- E2E messages hold client ciphertext in content_encrypted/content_nonce and
+-- the message key wrapped for each recipient device, keyed by device key ID
+ALTER TABLE messages ADD COLUMN key_envelopes JSONB;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_messages_created_at;
+ALTER TABLE conversations DROP COLUMN IF EXISTS legal_hold;
+ALTER TABLE users DROP COLUMN IF EXISTS message_retention_days;
@@ -0,0 +1,7 @@
+-- Per-user message retention override in days; NULL uses the platform default
+ALTER TABLE users ADD COLUMN message_retention_days INT CHECK (message_retention_days > 0);
+
+-- Conversations between parties to an open dispute are exempt from deletion
+ALTER TABLE conversations ADD COLUMN legal_hold BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_messages_created_at ON messages(created_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS moderation_queue;
+DROP INDEX IF EXISTS idx_messages_flagged;
+ALTER TABLE messages DROP COLUMN IF EXISTS moderation_flags;
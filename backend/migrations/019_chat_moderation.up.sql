@@ -0,0 +1,21 @@
+-- Scam categories the moderation scanner found in a message, shown to the
+-- recipient as a warning
+ALTER TABLE messages ADD COLUMN moderation_flags TEXT[];
+
+CREATE INDEX idx_messages_flagged ON messages(sender_id, created_at) WHERE moderation_flags IS NOT NULL;
+
+-- Users whose flagged messages crossed the escalation threshold
+CREATE TABLE moderation_queue (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    offense_count INT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'reviewed', 'dismissed')),
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- At most one open case per user; repeat offences update it
+CREATE UNIQUE INDEX idx_moderation_queue_open_user ON moderation_queue(user_id) WHERE status = 'open';
+CREATE INDEX idx_moderation_queue_status ON moderation_queue(status, created_at DESC);
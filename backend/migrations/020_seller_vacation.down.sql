@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_users_vacation;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS vacation_auto_reply,
+    DROP COLUMN IF EXISTS vacation_block_bids,
+    DROP COLUMN IF EXISTS vacation_listing_mode,
+    DROP COLUMN IF EXISTS vacation_ends_at,
+    DROP COLUMN IF EXISTS vacation_starts_at,
+    DROP COLUMN IF EXISTS vacation_mode;
@@ -0,0 +1,11 @@
+-- Vacation mode pauses a seller's shop between the start and end times
+ALTER TABLE users
+    ADD COLUMN vacation_mode BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN vacation_starts_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN vacation_ends_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN vacation_listing_mode VARCHAR(10) NOT NULL DEFAULT 'banner'
+        CHECK (vacation_listing_mode IN ('banner', 'extend', 'hide')),
+    ADD COLUMN vacation_block_bids BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN vacation_auto_reply TEXT;
+
+CREATE INDEX idx_users_vacation ON users(id) WHERE vacation_mode = TRUE;
@@ -0,0 +1,8 @@
+-- Origins of partner apps allowed by admins on top of CORS_ORIGINS
+CREATE TABLE allowed_origins (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    origin VARCHAR(255) NOT NULL UNIQUE,
+    description VARCHAR(200),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
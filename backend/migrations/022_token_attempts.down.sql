@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_users_password_reset_selector;
+DROP INDEX IF EXISTS idx_users_email_verification_selector;
+CREATE INDEX idx_users_email_verification_token ON users(email_verification_token) WHERE email_verification_token IS NOT NULL;
+CREATE INDEX idx_users_password_reset_token ON users(password_reset_token) WHERE password_reset_token IS NOT NULL;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS password_reset_attempts,
+    DROP COLUMN IF EXISTS email_verification_attempts;
@@ -0,0 +1,12 @@
+-- Failed guesses against the outstanding email verification and password
+-- reset tokens; a token is replaced or cleared once the limit is reached
+ALTER TABLE users
+    ADD COLUMN email_verification_attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN password_reset_attempts INT NOT NULL DEFAULT 0;
+
+-- Tokens are looked up by their first 16 characters and the rest compared in
+-- constant time
+DROP INDEX IF EXISTS idx_users_email_verification_token;
+DROP INDEX IF EXISTS idx_users_password_reset_token;
+CREATE INDEX idx_users_email_verification_selector ON users(LEFT(email_verification_token, 16)) WHERE email_verification_token IS NOT NULL;
+CREATE INDEX idx_users_password_reset_selector ON users(LEFT(password_reset_token, 16)) WHERE password_reset_token IS NOT NULL;
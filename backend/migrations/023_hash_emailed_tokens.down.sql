@@ -0,0 +1,7 @@
+-- Invalidated tokens can't be restored; hashed tokens issued since then stop
+-- matching once the application reverts to plaintext lookups.
+UPDATE users
+SET email_verification_token = NULL,
+    password_reset_token = NULL,
+    password_reset_expires = NULL
+WHERE email_verification_token IS NOT NULL OR password_reset_token IS NOT NULL;
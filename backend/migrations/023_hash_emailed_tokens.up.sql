@@ -0,0 +1,13 @@
+-- Verification and reset tokens are now stored as a selector followed by a
+-- SHA-256 hash. Outstanding plaintext tokens may already have leaked, so they
+-- are invalidated rather than converted; users can request new ones.
+UPDATE users
+SET email_verification_token = NULL,
+    email_verification_attempts = 0
+WHERE email_verification_token IS NOT NULL;
+
+UPDATE users
+SET password_reset_token = NULL,
+    password_reset_expires = NULL,
+    password_reset_attempts = 0
+WHERE password_reset_token IS NOT NULL;
@@ -0,0 +1,20 @@
+-- Users may limit themselves to one active session; some roles always are
+ALTER TABLE users
+    ADD COLUMN single_session BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN last_login_ip VARCHAR(45),
+    ADD COLUMN last_login_country VARCHAR(2),
+    ADD COLUMN last_login_at TIMESTAMP WITH TIME ZONE;
+
+-- Users are warned about logins from two countries in quick succession
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'outbid', 'auction_won', 'auction_lost', 'auction_ending', 'new_bid', 'auction_sold',
+    'order_cancelled', 'second_chance_offer', 'auction_relisted',
+    'auction_cancelled',
+    'dispute_opened', 'dispute_message', 'dispute_resolved',
+    'bid_cancelled',
+    'reserve_met',
+    'snipe_failed',
+    'transcript_viewed',
+    'suspicious_login'
+));
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_auctions_cert_status;
+
+ALTER TABLE auctions
+    DROP CONSTRAINT IF EXISTS auctions_cert_complete,
+    DROP COLUMN IF EXISTS cert_checked_at,
+    DROP COLUMN IF EXISTS cert_year,
+    DROP COLUMN IF EXISTS cert_subject,
+    DROP COLUMN IF EXISTS cert_grade,
+    DROP COLUMN IF EXISTS cert_status,
+    DROP COLUMN IF EXISTS cert_number,
+    DROP COLUMN IF EXISTS cert_grader;
@@ -0,0 +1,13 @@
+-- Graded cards carry the grading company's certification number, which is
+-- checked against the grader's records
+ALTER TABLE auctions
+    ADD COLUMN cert_grader VARCHAR(10) CHECK (cert_grader IN ('psa', 'bgs')),
+    ADD COLUMN cert_number VARCHAR(20),
+    ADD COLUMN cert_status VARCHAR(20) CHECK (cert_status IN ('pending', 'verified', 'not_found', 'unavailable')),
+    ADD COLUMN cert_grade VARCHAR(50),
+    ADD COLUMN cert_subject VARCHAR(255),
+    ADD COLUMN cert_year VARCHAR(10),
+    ADD COLUMN cert_checked_at TIMESTAMP WITH TIME ZONE,
+    ADD CONSTRAINT auctions_cert_complete CHECK ((cert_number IS NULL) = (cert_grader IS NULL));
+
+CREATE INDEX idx_auctions_cert_status ON auctions(cert_status) WHERE cert_status IS NOT NULL;
@@ -0,0 +1,9 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS two_factor_enabled;
+
+DROP INDEX IF EXISTS idx_refresh_tokens_device;
+
+ALTER TABLE refresh_tokens
+    DROP COLUMN IF EXISTS device_id;
+
+DROP TABLE IF EXISTS user_devices;
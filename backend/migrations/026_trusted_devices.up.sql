@@ -0,0 +1,27 @@
+-- Browsers and apps a user has signed in from, identified by a random device
+-- cookie stored hashed. Unrecognized devices need an emailed login code when
+-- the user has two-factor sign-in on.
+CREATE TABLE user_devices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    fingerprint VARCHAR(64) NOT NULL,
+    name VARCHAR(100),
+    user_agent VARCHAR(500),
+    trusted BOOLEAN NOT NULL DEFAULT FALSE,
+    last_ip VARCHAR(45),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    challenge_digest VARCHAR(64),
+    challenge_expires_at TIMESTAMP WITH TIME ZONE,
+    challenge_attempts INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (user_id, fingerprint)
+);
+
+-- Forgetting a device signs it out
+ALTER TABLE refresh_tokens
+    ADD COLUMN device_id UUID REFERENCES user_devices(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_refresh_tokens_device ON refresh_tokens(device_id) WHERE device_id IS NOT NULL;
+
+ALTER TABLE users
+    ADD COLUMN two_factor_enabled BOOLEAN NOT NULL DEFAULT FALSE;
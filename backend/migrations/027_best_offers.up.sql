@@ -0,0 +1,42 @@
+-- Sellers may take offers below the buy-now price; accepting one sells the
+-- item like Buy Now at the agreed amount
+ALTER TABLE auctions ADD COLUMN accepts_offers BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE offers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    buyer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount DECIMAL(12,2) NOT NULL CHECK (amount > 0),
+    counter_amount DECIMAL(12,2) CHECK (counter_amount > 0),
+    message TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'countered', 'accepted', 'declined', 'expired')),
+    bid_id UUID REFERENCES bids(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- At most one open offer per buyer and auction
+CREATE UNIQUE INDEX idx_offers_auction_buyer_open ON offers(auction_id, buyer_id) WHERE status IN ('pending', 'countered');
+CREATE INDEX idx_offers_auction_id ON offers(auction_id, created_at DESC);
+CREATE INDEX idx_offers_buyer_id ON offers(buyer_id, created_at DESC);
+CREATE INDEX idx_offers_open_expires ON offers(expires_at) WHERE status IN ('pending', 'countered');
+
+CREATE TRIGGER update_offers_updated_at BEFORE UPDATE ON offers
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'outbid', 'auction_won', 'auction_lost', 'auction_ending', 'new_bid', 'auction_sold',
+    'order_cancelled', 'second_chance_offer', 'auction_relisted',
+    'auction_cancelled',
+    'dispute_opened', 'dispute_message', 'dispute_resolved',
+    'bid_cancelled',
+    'reserve_met',
+    'snipe_failed',
+    'transcript_viewed',
+    'suspicious_login',
+    'offer_received', 'offer_countered', 'offer_declined', 'offer_expired'
+));
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_login_events_user;
+
+DROP TABLE IF EXISTS login_events;
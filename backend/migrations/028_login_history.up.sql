@@ -0,0 +1,16 @@
+-- Each successful sign-in, shown to the user so they can spot logins that
+-- weren't theirs
+CREATE TABLE login_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    method VARCHAR(20) NOT NULL CHECK (method IN ('password', 'oauth')),
+    provider VARCHAR(50),
+    ip VARCHAR(45),
+    country VARCHAR(2),
+    user_agent VARCHAR(500),
+    device_id UUID REFERENCES user_devices(id) ON DELETE SET NULL,
+    reported_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_login_events_user ON login_events(user_id, created_at DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE oauth_accounts DROP COLUMN IF EXISTS provider_email_verified;
+ALTER TABLE oauth_accounts DROP COLUMN IF EXISTS provider_email;
+ALTER TABLE oauth_accounts DROP COLUMN IF EXISTS linked_via;
@@ -0,0 +1,7 @@
+-- How each provider login came to be linked to its user, and the email the
+-- provider reported at the time. Links made before this was recorded have
+-- no provenance.
+ALTER TABLE oauth_accounts ADD COLUMN linked_via VARCHAR(20)
+    CHECK (linked_via IN ('signup', 'verified_email', 'account_settings', 'login_confirmation'));
+ALTER TABLE oauth_accounts ADD COLUMN provider_email VARCHAR(255);
+ALTER TABLE oauth_accounts ADD COLUMN provider_email_verified BOOLEAN;
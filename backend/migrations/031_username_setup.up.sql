@@ -0,0 +1,7 @@
+-- Set on OAuth signups, whose username was generated from the provider
+-- profile, until the user chooses their own
+ALTER TABLE users ADD COLUMN username_setup_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Earlier OAuth signups took the display name as is, which registration
+-- would have rejected
+UPDATE users SET username_setup_required = TRUE WHERE username !~ '^[A-Za-z0-9]{3,50}$';
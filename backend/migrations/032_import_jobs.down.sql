@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_import_jobs_updated_at ON import_jobs;
+DROP TABLE IF EXISTS import_jobs;
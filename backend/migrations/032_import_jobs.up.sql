@@ -0,0 +1,30 @@
+-- Bulk listing imports. The uploaded file is parsed into rows when the job is
+-- created; a worker then turns each row into a draft auction and records the
+-- outcome in the report, one entry per row.
+CREATE TABLE import_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    seller_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'json')),
+    filename VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'processing', 'completed')),
+    rows JSONB NOT NULL,
+    total_rows INT NOT NULL,
+    processed_rows INT NOT NULL DEFAULT 0,
+    created_count INT NOT NULL DEFAULT 0,
+    failed_count INT NOT NULL DEFAULT 0,
+    report JSONB NOT NULL DEFAULT '[]',
+    -- A worker holds a job until then; a job whose worker died is picked up
+    -- again once it passes
+    lease_until TIMESTAMP WITH TIME ZONE,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_import_jobs_seller ON import_jobs(seller_id, created_at DESC);
+CREATE INDEX idx_import_jobs_unfinished ON import_jobs(created_at) WHERE status <> 'completed';
+
+CREATE TRIGGER update_import_jobs_updated_at BEFORE UPDATE ON import_jobs
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_data_exports_updated_at ON data_exports;
+DROP TABLE IF EXISTS data_exports;
@@ -0,0 +1,26 @@
+-- Personal data exports. A worker gathers the user's data into a ZIP archive
+-- in object storage and emails a presigned link; once the link has expired
+-- the archive is deleted.
+CREATE TABLE data_exports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'processing', 'completed', 'failed', 'expired')),
+    object_name VARCHAR(255),
+    size_bytes BIGINT,
+    -- A worker holds an export until then; one whose worker died is picked
+    -- up again once it passes
+    lease_until TIMESTAMP WITH TIME ZONE,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_data_exports_user ON data_exports(user_id, created_at DESC);
+CREATE INDEX idx_data_exports_unfinished ON data_exports(created_at) WHERE status IN ('pending', 'processing');
+CREATE INDEX idx_data_exports_expiring ON data_exports(expires_at) WHERE status = 'completed';
+
+CREATE TRIGGER update_data_exports_updated_at BEFORE UPDATE ON data_exports
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_awaiting_purge;
+ALTER TABLE users DROP COLUMN IF EXISTS purged_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE users DROP COLUMN IF EXISTS is_deleted;
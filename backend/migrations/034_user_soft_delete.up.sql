@@ -0,0 +1,12 @@
+-- Deleted accounts stay as rows so the auctions, bids and ratings that
+-- reference them keep working. Personal data is kept for a retention period
+-- after deletion, then purged by an admin, which anonymizes the row.
+ALTER TABLE users ADD COLUMN is_deleted BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN purged_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_users_awaiting_purge ON users(deleted_at) WHERE is_deleted AND purged_at IS NULL;
+
+-- Accounts their owners already deleted were anonymized on the spot
+UPDATE users SET is_deleted = TRUE, deleted_at = updated_at, purged_at = updated_at
+WHERE email LIKE 'deleted+%@deleted.invalid';
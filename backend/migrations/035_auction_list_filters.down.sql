@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_ratings_seller;
+DROP INDEX IF EXISTS idx_auction_shipping_free;
+DROP INDEX IF EXISTS idx_auctions_active_condition;
+DROP INDEX IF EXISTS idx_auctions_active_buy_now_end;
@@ -0,0 +1,11 @@
+-- Active listings still offering buy now, by end time, so the homepage
+-- "ending soon with buy now" rail is a single index scan
+CREATE INDEX idx_auctions_active_buy_now_end ON auctions(end_time)
+    WHERE status = 'active' AND buy_now_price IS NOT NULL;
+
+CREATE INDEX idx_auctions_active_condition ON auctions(condition, end_time) WHERE status = 'active';
+
+CREATE INDEX idx_auction_shipping_free ON auction_shipping(auction_id) WHERE method = 'free';
+
+-- Average seller rating, checked per listing by min_seller_rating
+CREATE INDEX idx_ratings_seller ON ratings(rated_user_id, rating) WHERE type = 'seller';
@@ -0,0 +1,55 @@
+-- Payments for orders are held in escrow and only reach the seller's
+-- balance once the buyer confirms delivery or the hold period runs out
+CREATE TABLE escrow_holds (
+    order_id UUID PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    buyer_id UUID NOT NULL REFERENCES users(id),
+    seller_id UUID NOT NULL REFERENCES users(id),
+    amount DECIMAL(12,2) NOT NULL CHECK (amount > 0),
+    refunded DECIMAL(12,2) NOT NULL DEFAULT 0 CHECK (refunded >= 0 AND refunded <= amount),
+    status VARCHAR(20) NOT NULL DEFAULT 'held' CHECK (status IN ('held', 'released', 'refunded')),
+    release_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    delivery_confirmed_at TIMESTAMP WITH TIME ZONE,
+    released_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_escrow_holds_release ON escrow_holds(release_at) WHERE status = 'held';
+CREATE INDEX idx_escrow_holds_seller_held ON escrow_holds(seller_id) WHERE status = 'held';
+
+-- Double-entry ledger: the entries of a transaction sum to zero
+CREATE TABLE ledger_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transaction_id UUID NOT NULL,
+    account VARCHAR(20) NOT NULL CHECK (account IN ('buyer_payments', 'escrow', 'seller_balance', 'buyer_refunds')),
+    user_id UUID REFERENCES users(id),
+    order_id UUID REFERENCES orders(id),
+    amount DECIMAL(12,2) NOT NULL CHECK (amount <> 0),
+    memo TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_ledger_entries_transaction ON ledger_entries(transaction_id);
+CREATE INDEX idx_ledger_entries_order ON ledger_entries(order_id) WHERE order_id IS NOT NULL;
+CREATE INDEX idx_ledger_entries_user ON ledger_entries(user_id, created_at DESC) WHERE user_id IS NOT NULL;
+
+-- Running seller balances, kept in step with seller_balance entries
+CREATE TABLE balances (
+    user_id UUID PRIMARY KEY REFERENCES users(id),
+    available DECIMAL(12,2) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'outbid', 'auction_won', 'auction_lost', 'auction_ending', 'new_bid', 'auction_sold',
+    'order_cancelled', 'second_chance_offer', 'auction_relisted',
+    'auction_cancelled',
+    'dispute_opened', 'dispute_message', 'dispute_resolved',
+    'bid_cancelled',
+    'reserve_met',
+    'snipe_failed',
+    'transcript_viewed',
+    'suspicious_login',
+    'offer_received', 'offer_countered', 'offer_declined', 'offer_expired',
+    'escrow_released'
+));
@@ -0,0 +1,29 @@
+DELETE FROM notifications WHERE type IN ('payout_requested', 'payout_approved', 'payout_completed', 'payout_rejected');
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'outbid', 'auction_won', 'auction_lost', 'auction_ending', 'new_bid', 'auction_sold',
+    'order_cancelled', 'second_chance_offer', 'auction_relisted',
+    'auction_cancelled',
+    'dispute_opened', 'dispute_message', 'dispute_resolved',
+    'bid_cancelled',
+    'reserve_met',
+    'snipe_failed',
+    'transcript_viewed',
+    'suspicious_login',
+    'offer_received', 'offer_countered', 'offer_declined', 'offer_expired',
+    'escrow_released'
+));
+
+DELETE FROM ledger_entries WHERE payout_id IS NOT NULL;
+
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_account_check;
+ALTER TABLE ledger_entries ADD CONSTRAINT ledger_entries_account_check CHECK (account IN (
+    'buyer_payments', 'escrow', 'seller_balance', 'buyer_refunds'
+));
+
+DROP INDEX IF EXISTS idx_ledger_entries_payout;
+ALTER TABLE ledger_entries DROP COLUMN IF EXISTS payout_id;
+
+DROP TABLE IF EXISTS payouts;
+DROP TABLE IF EXISTS payout_methods;
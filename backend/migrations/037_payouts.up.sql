@@ -0,0 +1,66 @@
+-- Where a seller's withdrawals are sent; IBANs are encrypted at rest
+CREATE TABLE payout_methods (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('iban', 'stripe_connect')),
+    account_holder VARCHAR(200) NOT NULL,
+    iban_last4 VARCHAR(4),
+    iban_encrypted BYTEA,
+    iban_nonce BYTEA,
+    stripe_account_id VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (
+        (type = 'iban' AND iban_encrypted IS NOT NULL AND iban_nonce IS NOT NULL) OR
+        (type = 'stripe_connect' AND stripe_account_id IS NOT NULL)
+    )
+);
+
+-- Withdrawals from seller balances. The destination is copied from the
+-- payout method when requested.
+CREATE TABLE payouts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    amount DECIMAL(12,2) NOT NULL CHECK (amount > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'requested' CHECK (status IN ('requested', 'approved', 'completed', 'rejected')),
+    method_type VARCHAR(20) NOT NULL CHECK (method_type IN ('iban', 'stripe_connect')),
+    account_holder VARCHAR(200) NOT NULL,
+    iban_last4 VARCHAR(4),
+    iban_encrypted BYTEA,
+    iban_nonce BYTEA,
+    stripe_account_id VARCHAR(255),
+    reference VARCHAR(255),
+    rejection_reason TEXT,
+    reviewed_by UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    approved_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    rejected_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_payouts_user ON payouts(user_id, created_at DESC);
+CREATE INDEX idx_payouts_open ON payouts(created_at) WHERE status IN ('requested', 'approved');
+
+ALTER TABLE ledger_entries ADD COLUMN payout_id UUID REFERENCES payouts(id);
+CREATE INDEX idx_ledger_entries_payout ON ledger_entries(payout_id) WHERE payout_id IS NOT NULL;
+
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_account_check;
+ALTER TABLE ledger_entries ADD CONSTRAINT ledger_entries_account_check CHECK (account IN (
+    'buyer_payments', 'escrow', 'seller_balance', 'buyer_refunds',
+    'payouts_requested', 'payouts_approved', 'payouts_paid'
+));
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'outbid', 'auction_won', 'auction_lost', 'auction_ending', 'new_bid', 'auction_sold',
+    'order_cancelled', 'second_chance_offer', 'auction_relisted',
+    'auction_cancelled',
+    'dispute_opened', 'dispute_message', 'dispute_resolved',
+    'bid_cancelled',
+    'reserve_met',
+    'snipe_failed',
+    'transcript_viewed',
+    'suspicious_login',
+    'offer_received', 'offer_countered', 'offer_declined', 'offer_expired',
+    'escrow_released',
+    'payout_requested', 'payout_approved', 'payout_completed', 'payout_rejected'
+));
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_orders_sold_amount;
+DROP INDEX IF EXISTS idx_orders_sold_at;
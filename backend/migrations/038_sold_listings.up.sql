@@ -0,0 +1,3 @@
+-- Browsing past sales filters and sorts completed orders by sale date
+CREATE INDEX idx_orders_sold_at ON orders(created_at DESC) WHERE status <> 'cancelled';
+CREATE INDEX idx_orders_sold_amount ON orders(amount) WHERE status <> 'cancelled';
@@ -0,0 +1,22 @@
+DELETE FROM notifications WHERE type = 'auction_invite';
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'outbid', 'auction_won', 'auction_lost', 'auction_ending', 'new_bid', 'auction_sold',
+    'order_cancelled', 'second_chance_offer', 'auction_relisted',
+    'auction_cancelled',
+    'dispute_opened', 'dispute_message', 'dispute_resolved',
+    'bid_cancelled',
+    'reserve_met',
+    'snipe_failed',
+    'transcript_viewed',
+    'suspicious_login',
+    'offer_received', 'offer_countered', 'offer_declined', 'offer_expired',
+    'escrow_released',
+    'payout_requested', 'payout_approved', 'payout_completed', 'payout_rejected'
+));
+
+DROP TABLE IF EXISTS auction_invites;
+DROP INDEX IF EXISTS idx_auctions_private;
+ALTER TABLE auctions DROP COLUMN IF EXISTS share_version;
+ALTER TABLE auctions DROP COLUMN IF EXISTS visibility;
@@ -0,0 +1,33 @@
+-- Private auctions are only shown to their seller, invited users and holders
+-- of a share link. Bumping share_version revokes every link issued so far.
+ALTER TABLE auctions ADD COLUMN visibility VARCHAR(10) NOT NULL DEFAULT 'public'
+    CHECK (visibility IN ('public', 'private'));
+ALTER TABLE auctions ADD COLUMN share_version INT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_auctions_private ON auctions(seller_id) WHERE visibility = 'private';
+
+CREATE TABLE auction_invites (
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (auction_id, user_id)
+);
+
+CREATE INDEX idx_auction_invites_user ON auction_invites(user_id);
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'outbid', 'auction_won', 'auction_lost', 'auction_ending', 'new_bid', 'auction_sold',
+    'order_cancelled', 'second_chance_offer', 'auction_relisted',
+    'auction_cancelled',
+    'dispute_opened', 'dispute_message', 'dispute_resolved',
+    'bid_cancelled',
+    'reserve_met',
+    'snipe_failed',
+    'transcript_viewed',
+    'suspicious_login',
+    'offer_received', 'offer_countered', 'offer_declined', 'offer_expired',
+    'escrow_released',
+    'payout_requested', 'payout_approved', 'payout_completed', 'payout_rejected',
+    'auction_invite'
+));
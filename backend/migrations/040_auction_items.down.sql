@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_auction_images_item;
+ALTER TABLE auction_images DROP COLUMN IF EXISTS item_id;
+
+DROP TABLE IF EXISTS auction_items;
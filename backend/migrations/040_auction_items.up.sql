@@ -0,0 +1,16 @@
+-- Items of a lot listing. Each item shows some of the auction's images.
+CREATE TABLE auction_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    position INT NOT NULL DEFAULT 0,
+    title VARCHAR(255) NOT NULL,
+    condition VARCHAR(50) CHECK (condition IN ('new', 'like_new', 'good', 'fair', 'poor')),
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_auction_items_auction ON auction_items(auction_id, position);
+CREATE INDEX idx_auction_items_search ON auction_items USING gin(to_tsvector('english', title));
+
+ALTER TABLE auction_images ADD COLUMN item_id UUID REFERENCES auction_items(id) ON DELETE SET NULL;
+CREATE INDEX idx_auction_images_item ON auction_images(item_id) WHERE item_id IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS listing_share_clicks;
+DROP TABLE IF EXISTS listing_shares;
@@ -0,0 +1,21 @@
+-- Short links users share to a listing, one per listing and sharer, with
+-- every visit through them. Bidders are attributed to the share they last
+-- came through before their first bid.
+CREATE TABLE listing_shares (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(16) NOT NULL UNIQUE,
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    sharer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (auction_id, sharer_id)
+);
+
+CREATE TABLE listing_share_clicks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    share_id UUID NOT NULL REFERENCES listing_shares(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_listing_share_clicks_share ON listing_share_clicks(share_id);
+CREATE INDEX idx_listing_share_clicks_user ON listing_share_clicks(user_id, created_at) WHERE user_id IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_auction_factsheets_updated_at ON auction_factsheets;
+DROP TABLE IF EXISTS auction_factsheets;
@@ -0,0 +1,22 @@
+-- Printable PDF fact sheets of auctions. A worker renders a sheet into object
+-- storage; it is rendered again when the auction's fingerprint, a hash of
+-- what the sheet shows, no longer matches or the sheet's price gets old.
+CREATE TABLE auction_factsheets (
+    auction_id UUID PRIMARY KEY REFERENCES auctions(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'processing', 'completed', 'failed')),
+    -- Of the stored sheet, which is kept while a new one is rendered
+    fingerprint VARCHAR(64),
+    object_name VARCHAR(255),
+    -- A worker holds a sheet until then; one whose worker died is picked up
+    -- again once it passes
+    lease_until TIMESTAMP WITH TIME ZONE,
+    generated_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_auction_factsheets_unfinished ON auction_factsheets(updated_at) WHERE status IN ('pending', 'processing');
+
+CREATE TRIGGER update_auction_factsheets_updated_at BEFORE UPDATE ON auction_factsheets
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
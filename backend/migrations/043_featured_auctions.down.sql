@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_auction_views_viewed_at;
+DROP INDEX IF EXISTS idx_bids_created_at;
+DROP INDEX IF EXISTS idx_watchlist_created_at;
+DROP TRIGGER IF EXISTS update_featured_auctions_updated_at ON featured_auctions;
+DROP TABLE IF EXISTS featured_auctions;
@@ -0,0 +1,19 @@
+-- Auctions admins pin to the homepage, shown by position until they expire
+CREATE TABLE featured_auctions (
+    auction_id UUID PRIMARY KEY REFERENCES auctions(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0 CHECK (position >= 0),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    featured_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_featured_auctions_position ON featured_auctions(position, created_at);
+
+CREATE TRIGGER update_featured_auctions_updated_at BEFORE UPDATE ON featured_auctions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Trending scores count recent watches and bids, which these keep cheap
+CREATE INDEX idx_watchlist_created_at ON watchlist(created_at);
+CREATE INDEX idx_bids_created_at ON bids(created_at);
+CREATE INDEX idx_auction_views_viewed_at ON auction_views(viewed_at);
@@ -0,0 +1,22 @@
+DELETE FROM notifications WHERE type = 'price_drop';
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'outbid', 'auction_won', 'auction_lost', 'auction_ending', 'new_bid', 'auction_sold',
+    'order_cancelled', 'second_chance_offer', 'auction_relisted',
+    'auction_cancelled',
+    'dispute_opened', 'dispute_message', 'dispute_resolved',
+    'bid_cancelled',
+    'reserve_met',
+    'snipe_failed',
+    'transcript_viewed',
+    'suspicious_login',
+    'offer_received', 'offer_countered', 'offer_declined', 'offer_expired',
+    'escrow_released',
+    'payout_requested', 'payout_approved', 'payout_completed', 'payout_rejected',
+    'auction_invite'
+));
+
+DROP TABLE IF EXISTS auction_price_drops;
+DROP TRIGGER IF EXISTS update_price_drop_rules_updated_at ON price_drop_rules;
+DROP TABLE IF EXISTS price_drop_rules;
@@ -0,0 +1,46 @@
+-- Automatic markdowns of a listing's buy-now price: percent off every
+-- interval_days until floor_price. next_drop_at is cleared once the rule
+-- has run its course.
+CREATE TABLE price_drop_rules (
+    auction_id UUID PRIMARY KEY REFERENCES auctions(id) ON DELETE CASCADE,
+    percent DECIMAL(5,2) NOT NULL CHECK (percent > 0 AND percent <= 50),
+    interval_days INT NOT NULL CHECK (interval_days > 0),
+    floor_price DECIMAL(12,2) NOT NULL,
+    next_drop_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_price_drop_rules_due ON price_drop_rules(next_drop_at) WHERE next_drop_at IS NOT NULL;
+
+CREATE TRIGGER update_price_drop_rules_updated_at BEFORE UPDATE ON price_drop_rules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Each markdown made, shown on the listing as its price history
+CREATE TABLE auction_price_drops (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    old_price DECIMAL(12,2) NOT NULL,
+    new_price DECIMAL(12,2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_auction_price_drops_auction ON auction_price_drops(auction_id, created_at);
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'outbid', 'auction_won', 'auction_lost', 'auction_ending', 'new_bid', 'auction_sold',
+    'order_cancelled', 'second_chance_offer', 'auction_relisted',
+    'auction_cancelled',
+    'dispute_opened', 'dispute_message', 'dispute_resolved',
+    'bid_cancelled',
+    'reserve_met',
+    'snipe_failed',
+    'transcript_viewed',
+    'suspicious_login',
+    'offer_received', 'offer_countered', 'offer_declined', 'offer_expired',
+    'escrow_released',
+    'payout_requested', 'payout_approved', 'payout_completed', 'payout_rejected',
+    'auction_invite',
+    'price_drop'
+));
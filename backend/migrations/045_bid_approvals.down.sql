@@ -0,0 +1,24 @@
+DELETE FROM notifications WHERE type IN ('bid_approval_requested', 'bid_approval_decided');
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'outbid', 'auction_won', 'auction_lost', 'auction_ending', 'new_bid', 'auction_sold',
+    'order_cancelled', 'second_chance_offer', 'auction_relisted',
+    'auction_cancelled',
+    'dispute_opened', 'dispute_message', 'dispute_resolved',
+    'bid_cancelled',
+    'reserve_met',
+    'snipe_failed',
+    'transcript_viewed',
+    'suspicious_login',
+    'offer_received', 'offer_countered', 'offer_declined', 'offer_expired',
+    'escrow_released',
+    'payout_requested', 'payout_approved', 'payout_completed', 'payout_rejected',
+    'auction_invite',
+    'price_drop'
+));
+
+DROP TRIGGER IF EXISTS update_bid_approvals_updated_at ON bid_approvals;
+DROP TABLE IF EXISTS bid_approvals;
+ALTER TABLE auctions DROP COLUMN IF EXISTS bid_approval_questions;
+ALTER TABLE auctions DROP COLUMN IF EXISTS requires_bid_approval;
@@ -0,0 +1,43 @@
+-- Sellers of high-value listings can require bidders to be approved first.
+-- Bidders request approval, answering the seller's questions if any.
+ALTER TABLE auctions ADD COLUMN requires_bid_approval BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE auctions ADD COLUMN bid_approval_questions TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE TABLE bid_approvals (
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'denied')),
+    -- Questions and answers as asked, so later edits to the questions
+    -- don't change what was answered
+    answers JSONB NOT NULL DEFAULT '[]',
+    note TEXT,
+    decided_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (auction_id, user_id)
+);
+
+CREATE INDEX idx_bid_approvals_auction_status ON bid_approvals(auction_id, status, created_at);
+
+CREATE TRIGGER update_bid_approvals_updated_at BEFORE UPDATE ON bid_approvals
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check CHECK (type IN (
+    'outbid', 'auction_won', 'auction_lost', 'auction_ending', 'new_bid', 'auction_sold',
+    'order_cancelled', 'second_chance_offer', 'auction_relisted',
+    'auction_cancelled',
+    'dispute_opened', 'dispute_message', 'dispute_resolved',
+    'bid_cancelled',
+    'reserve_met',
+    'snipe_failed',
+    'transcript_viewed',
+    'suspicious_login',
+    'offer_received', 'offer_countered', 'offer_declined', 'offer_expired',
+    'escrow_released',
+    'payout_requested', 'payout_approved', 'payout_completed', 'payout_rejected',
+    'auction_invite',
+    'price_drop',
+    'bid_approval_requested', 'bid_approval_decided'
+));
@@ -0,0 +1,4 @@
+ALTER TABLE auctions DROP COLUMN IF EXISTS live_event_id;
+DROP TABLE IF EXISTS live_event_lots;
+DROP TRIGGER IF EXISTS update_live_events_updated_at ON live_events;
+DROP TABLE IF EXISTS live_events;
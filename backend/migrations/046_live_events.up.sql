@@ -0,0 +1,37 @@
+-- Live events: an auctioneer runs the event's lots one after another in
+-- real time. Each lot is one of the auctioneer's draft auctions, opened
+-- when the auctioneer calls it and closed at the hammer.
+CREATE TABLE live_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    auctioneer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled'
+        CHECK (status IN ('scheduled', 'live', 'ended')),
+    current_lot_id UUID REFERENCES auctions(id) ON DELETE SET NULL,
+    started_at TIMESTAMP WITH TIME ZONE,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_live_events_upcoming ON live_events(starts_at) WHERE status <> 'ended';
+
+CREATE TRIGGER update_live_events_updated_at BEFORE UPDATE ON live_events
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE live_event_lots (
+    auction_id UUID PRIMARY KEY REFERENCES auctions(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL REFERENCES live_events(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    state VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (state IN ('pending', 'open', 'fair_warning', 'sold', 'passed')),
+    opened_at TIMESTAMP WITH TIME ZONE,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (event_id, position)
+);
+
+-- Also kept on the auction so bids on a lot reach the event's room without
+-- a lookup
+ALTER TABLE auctions ADD COLUMN live_event_id UUID REFERENCES live_events(id) ON DELETE SET NULL;
@@ -0,0 +1,4 @@
+-- Currency an auction is priced in. Balances, escrow and payouts stay in
+-- USD.
+ALTER TABLE auctions ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'USD'
+    CHECK (currency IN ('USD', 'EUR', 'BAM'));
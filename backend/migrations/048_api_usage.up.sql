@@ -0,0 +1,12 @@
+-- Signed-in users' requests against each per-user rate limit, by day
+-- (UTC), rolled up from the counters kept in Redis
+CREATE TABLE api_usage_daily (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    limit_name VARCHAR(50) NOT NULL,
+    day DATE NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    rate_limited BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, limit_name, day)
+);
+
+CREATE INDEX idx_api_usage_daily_day ON api_usage_daily(day);
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_seller_bid_alerts_updated_at ON seller_bid_alerts;
+DROP TABLE IF EXISTS seller_bid_alerts;
@@ -0,0 +1,14 @@
+-- Which bids on a listing its seller is notified of. Listings without a row
+-- notify on every bid.
+CREATE TABLE seller_bid_alerts (
+    auction_id UUID PRIMARY KEY REFERENCES auctions(id) ON DELETE CASCADE,
+    first_bid BOOLEAN NOT NULL DEFAULT TRUE,
+    reserve_met BOOLEAN NOT NULL DEFAULT TRUE,
+    every_n_bids INT NOT NULL DEFAULT 1 CHECK (every_n_bids >= 0),
+    price_above DECIMAL(12,2),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TRIGGER update_seller_bid_alerts_updated_at BEFORE UPDATE ON seller_bid_alerts
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
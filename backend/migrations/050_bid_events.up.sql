@@ -0,0 +1,33 @@
+-- Everything that happened to an auction's bids, for admins investigating
+-- shill bidding or disputes. Placements keep the bidder's address and user
+-- agent; account deletion clears them.
+CREATE TABLE bid_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    bid_id UUID REFERENCES bids(id) ON DELETE SET NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    event_type VARCHAR(20) NOT NULL
+        CHECK (event_type IN ('placed', 'auto_bid', 'buy_now', 'cancelled', 'all_cancelled', 'extended')),
+    amount DECIMAL(12,2),
+    max_auto_bid DECIMAL(12,2),
+    ip VARCHAR(45),
+    user_agent VARCHAR(500),
+    reason TEXT,
+    end_time TIMESTAMP WITH TIME ZONE,
+    -- The clock rather than the transaction's start, so events of one bid
+    -- keep their order
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT clock_timestamp()
+);
+
+CREATE INDEX idx_bid_events_auction ON bid_events(auction_id, created_at);
+CREATE INDEX idx_bid_events_actor ON bid_events(actor_id) WHERE ip IS NOT NULL OR user_agent IS NOT NULL;
+
+-- Bids placed before the trail was kept, without their request details
+INSERT INTO bid_events (auction_id, bid_id, actor_id, event_type, amount, max_auto_bid, created_at)
+SELECT auction_id, id, bidder_id, 'placed', amount, max_auto_bid, created_at
+FROM bids;
+
+INSERT INTO bid_events (auction_id, bid_id, actor_id, event_type, amount, reason, created_at)
+SELECT auction_id, id, cancelled_by, 'cancelled', amount, cancel_reason, cancelled_at
+FROM bids
+WHERE cancelled_at IS NOT NULL;
@@ -0,0 +1,16 @@
+-- Incidents admins post to the public status page
+CREATE TABLE status_incidents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    title VARCHAR(200) NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'investigating'
+        CHECK (status IN ('investigating', 'identified', 'monitoring', 'resolved')),
+    impact VARCHAR(10) NOT NULL CHECK (impact IN ('minor', 'major')),
+    components TEXT[] NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_status_incidents_recent ON status_incidents(resolved_at DESC NULLS FIRST, created_at DESC);
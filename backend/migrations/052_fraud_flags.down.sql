@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_user_devices_fingerprint;
+DROP INDEX IF EXISTS idx_login_events_ip;
+DROP INDEX IF EXISTS idx_bid_events_ip;
+DROP TABLE IF EXISTS fraud_flags;
@@ -0,0 +1,31 @@
+-- Admin review queue for suspected shill bidding and other fraud, raised by
+-- periodic checks. A finding keeps one flag, refreshed while open.
+CREATE TABLE fraud_flags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    check_type VARCHAR(30) NOT NULL
+        CHECK (check_type IN ('shared_ip', 'bid_retraction', 'bid_velocity', 'self_purchase')),
+    subject_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    related_user_ids UUID[] NOT NULL DEFAULT '{}',
+    auction_id UUID REFERENCES auctions(id) ON DELETE SET NULL,
+    severity INT NOT NULL CHECK (severity BETWEEN 0 AND 100),
+    evidence JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'confirmed', 'dismissed')),
+    dedupe_key VARCHAR(200) NOT NULL,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    review_note TEXT,
+    detected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (check_type, dedupe_key)
+);
+
+CREATE INDEX idx_fraud_flags_queue ON fraud_flags(status, severity DESC, detected_at DESC);
+CREATE INDEX idx_fraud_flags_subject ON fraud_flags(subject_id);
+
+CREATE TRIGGER update_fraud_flags_updated_at BEFORE UPDATE ON fraud_flags
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Finding bids from one address, and accounts sharing addresses
+CREATE INDEX idx_bid_events_ip ON bid_events(ip, created_at) WHERE ip IS NOT NULL;
+CREATE INDEX idx_login_events_ip ON login_events(ip) WHERE ip IS NOT NULL;
+CREATE INDEX idx_user_devices_fingerprint ON user_devices(fingerprint);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS held_notifications;
+DROP TRIGGER IF EXISTS update_notification_quiet_hours_updated_at ON notification_quiet_hours;
+DROP TABLE IF EXISTS notification_quiet_hours;
@@ -0,0 +1,28 @@
+-- Hours, in the user's timezone, during which their email and push
+-- notifications are held. Users without a row have no quiet hours.
+CREATE TABLE notification_quiet_hours (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    start_time VARCHAR(5) NOT NULL,
+    end_time VARCHAR(5) NOT NULL,
+    timezone VARCHAR(64) NOT NULL,
+    allow_critical BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TRIGGER update_notification_quiet_hours_updated_at BEFORE UPDATE ON notification_quiet_hours
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Emails held during quiet hours, sent as a digest once they end
+CREATE TABLE held_notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    notification_type VARCHAR(50) NOT NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    release_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_held_notifications_release ON held_notifications(release_at);
@@ -0,0 +1,4 @@
+-- Canonical deep link for a notification and the actions clients render as
+-- buttons, built server-side when the notification is created
+ALTER TABLE notifications ADD COLUMN link TEXT;
+ALTER TABLE notifications ADD COLUMN actions JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,3 @@
+-- Requirements sellers set on bidders' accounts: a minimum buyer rating, a
+-- verified email or a minimum account age
+ALTER TABLE auctions ADD COLUMN bidder_requirements JSONB NOT NULL DEFAULT '{}';
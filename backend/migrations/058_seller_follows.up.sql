@@ -0,0 +1,10 @@
+-- Sellers a user follows, such as saved sellers imported from eBay
+CREATE TABLE seller_follows (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    seller_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, seller_id),
+    CHECK (user_id <> seller_id)
+);
+
+CREATE INDEX idx_seller_follows_user ON seller_follows(user_id, created_at DESC);
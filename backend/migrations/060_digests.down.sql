@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS saved_searches;
+DROP TRIGGER IF EXISTS update_digest_preferences_updated_at ON digest_preferences;
+DROP TABLE IF EXISTS digest_preferences;
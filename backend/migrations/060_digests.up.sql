@@ -0,0 +1,32 @@
+-- When users get a digest email and what goes in it. Users without a row
+-- get no digest.
+CREATE TABLE digest_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    frequency VARCHAR(10) NOT NULL DEFAULT 'off',
+    send_hour SMALLINT NOT NULL DEFAULT 8 CHECK (send_hour BETWEEN 0 AND 23),
+    send_weekday SMALLINT NOT NULL DEFAULT 1 CHECK (send_weekday BETWEEN 0 AND 6),
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    watched_ending BOOLEAN NOT NULL DEFAULT TRUE,
+    saved_searches BOOLEAN NOT NULL DEFAULT TRUE,
+    seller_stats BOOLEAN NOT NULL DEFAULT TRUE,
+    last_sent_at TIMESTAMP WITH TIME ZONE,
+    next_send_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_digest_preferences_due ON digest_preferences(next_send_at) WHERE frequency <> 'off';
+
+CREATE TRIGGER update_digest_preferences_updated_at BEFORE UPDATE ON digest_preferences
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Searches users saved, so new listings matching them come in their digest
+CREATE TABLE saved_searches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    query JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_saved_searches_user ON saved_searches(user_id, created_at DESC);
@@ -0,0 +1,14 @@
+-- Planned downtime scheduled by admins. Auctions don't end during a window
+-- and those that came due are extended by its downtime once completed.
+CREATE TABLE maintenance_windows (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    reason VARCHAR(500) NOT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL CHECK (ends_at > starts_at),
+    created_by UUID NOT NULL REFERENCES users(id),
+    completed_at TIMESTAMP WITH TIME ZONE,
+    auctions_extended INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_maintenance_windows_open ON maintenance_windows(starts_at) WHERE completed_at IS NULL;
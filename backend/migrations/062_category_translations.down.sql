@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_category_translations_updated_at ON category_translations;
+DROP TABLE IF EXISTS category_translations;
@@ -0,0 +1,14 @@
+-- Category names and descriptions in languages other than the one they
+-- were written in, keyed by BCP 47 tag
+CREATE TABLE category_translations (
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    language VARCHAR(35) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (category_id, language)
+);
+
+CREATE TRIGGER update_category_translations_updated_at BEFORE UPDATE ON category_translations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_category_listing_rules_updated_at ON category_listing_rules;
+DROP TABLE IF EXISTS category_listing_rules;
@@ -0,0 +1,16 @@
+-- Defaults a category gives new drafts and what its auctions need to be
+-- published. Categories without a row keep the site-wide behaviour.
+CREATE TABLE category_listing_rules (
+    category_id UUID PRIMARY KEY REFERENCES categories(id) ON DELETE CASCADE,
+    default_duration_hours INTEGER,
+    default_bid_increment DECIMAL(12,2),
+    min_duration_hours INTEGER,
+    max_duration_hours INTEGER,
+    min_bid_increment DECIMAL(12,2),
+    min_images INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TRIGGER update_category_listing_rules_updated_at BEFORE UPDATE ON category_listing_rules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
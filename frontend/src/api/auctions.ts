@@ -4,6 +4,7 @@ import {
   Auction,
   AuctionListParams,
   Category,
+  CategoryListingRules,
   CreateAuctionRequest,
   UpdateAuctionRequest,
   PaginatedResponse,
@@ -67,6 +68,11 @@ export const auctionsApi = {
     return response.data;
   },
 
+  async getListingRules(slug: string): Promise<APIResponse<CategoryListingRules>> {
+    const response = await api.get<APIResponse<CategoryListingRules>>(`/categories/${slug}/rules`);
+    return response.data;
+  },
+
   async getMyAuctions(params?: { page?: number; limit?: number; status?: string }): Promise<APIResponse<Auction[]>> {
     const response = await api.get<APIResponse<Auction[]>>('/users/me/auctions', { params });
     return response.data;
@@ -1,5 +1,6 @@
 import axios, { AxiosError, AxiosInstance, InternalAxiosRequestConfig } from 'axios';
 import { APIResponse, RefreshResponse } from '../types';
+import i18n from '../i18n';
 
 const BASE_URL = '/api';
 
@@ -28,6 +29,10 @@ api.interceptors.request.use(
     if (accessToken && config.headers) {
       config.headers.Authorization = `Bearer ${accessToken}`;
     }
+    // Categories come back in the language picked in the app
+    if (config.headers) {
+      config.headers['Accept-Language'] = i18n.language;
+    }
     return config;
   },
   (error) => Promise.reject(error)
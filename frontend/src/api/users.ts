@@ -6,6 +6,20 @@ import {
   UpdateProfileRequest,
   Rating,
   Notification,
+  NotificationThread,
+  QuietHours,
+  DigestPreferences,
+  Digest,
+  SavedSearch,
+  SavedSearchQuery,
+  NotificationPreference,
+  NotificationSettings,
+  UpdateNotificationSettingsRequest,
+  UserBlock,
+  SellerFollow,
+  EbayImportReport,
+  PushSubscriptions,
+  PushSubscriptionInfo,
   WatchlistItem,
   PaginatedResponse,
   Auction,
@@ -53,6 +67,77 @@ export const usersApi = {
     return response.data;
   },
 
+  async getNotificationThreads(
+    params?: { page?: number; limit?: number; unread?: boolean }
+  ): Promise<APIResponse<{ threads: NotificationThread[]; unread_count: number }>> {
+    const response = await api.get<APIResponse<{ threads: NotificationThread[]; unread_count: number }>>('/notifications', {
+      params: { ...params, grouped: true },
+    });
+    return response.data;
+  },
+
+  async markNotificationThreadRead(id: string): Promise<APIResponse<void>> {
+    const response = await api.put<APIResponse<void>>(`/notifications/threads/${id}/read`);
+    return response.data;
+  },
+
+  async getQuietHours(): Promise<APIResponse<QuietHours>> {
+    const response = await api.get<APIResponse<QuietHours>>('/notifications/quiet-hours');
+    return response.data;
+  },
+
+  async setQuietHours(data: QuietHours): Promise<APIResponse<QuietHours>> {
+    const response = await api.put<APIResponse<QuietHours>>('/notifications/quiet-hours', data);
+    return response.data;
+  },
+
+  async getDigestPreferences(): Promise<APIResponse<DigestPreferences>> {
+    const response = await api.get<APIResponse<DigestPreferences>>('/notifications/digest');
+    return response.data;
+  },
+
+  async setDigestPreferences(data: DigestPreferences): Promise<APIResponse<DigestPreferences>> {
+    const response = await api.put<APIResponse<DigestPreferences>>('/notifications/digest', data);
+    return response.data;
+  },
+
+  async previewDigest(): Promise<APIResponse<Digest>> {
+    const response = await api.get<APIResponse<Digest>>('/notifications/digest/preview');
+    return response.data;
+  },
+
+  // Saved searches, for the digest
+  async getSavedSearches(): Promise<APIResponse<SavedSearch[]>> {
+    const response = await api.get<APIResponse<SavedSearch[]>>('/users/me/saved-searches');
+    return response.data;
+  },
+
+  async saveSearch(name: string, query: SavedSearchQuery): Promise<APIResponse<SavedSearch>> {
+    const response = await api.post<APIResponse<SavedSearch>>('/users/me/saved-searches', { name, query });
+    return response.data;
+  },
+
+  async deleteSavedSearch(id: string): Promise<APIResponse<void>> {
+    const response = await api.delete<APIResponse<void>>(`/users/me/saved-searches/${id}`);
+    return response.data;
+  },
+
+  async getNotificationSettings(): Promise<APIResponse<NotificationSettings>> {
+    const response = await api.get<APIResponse<NotificationSettings>>('/users/me/notification-settings');
+    return response.data;
+  },
+
+  async updateNotificationSettings(data: UpdateNotificationSettingsRequest): Promise<APIResponse<NotificationSettings>> {
+    const response = await api.put<APIResponse<NotificationSettings>>('/users/me/notification-settings', data);
+    return response.data;
+  },
+
+  // Works signed out, with the token from an email's unsubscribe link
+  async unsubscribe(token: string): Promise<APIResponse<NotificationPreference>> {
+    const response = await api.post<APIResponse<NotificationPreference>>('/notifications/unsubscribe', { token });
+    return response.data;
+  },
+
   async markNotificationRead(id: string): Promise<APIResponse<void>> {
     const response = await api.put<APIResponse<void>>(`/notifications/${id}/read`);
     return response.data;
@@ -79,6 +164,19 @@ export const usersApi = {
     return response.data;
   },
 
+  // Accepts an eBay watchlist or saved sellers CSV export
+  async importEbay(file: File): Promise<APIResponse<EbayImportReport>> {
+    const formData = new FormData();
+    formData.append('file', file);
+
+    const response = await api.post<APIResponse<EbayImportReport>>('/watchlist/import/ebay', formData, {
+      headers: {
+        'Content-Type': 'multipart/form-data',
+      },
+    });
+    return response.data;
+  },
+
   // Ratings
   async createRating(
     auctionId: string,
@@ -88,6 +186,60 @@ export const usersApi = {
     return response.data;
   },
 
+  // Blocked users
+  async getBlocks(params?: { page?: number; limit?: number }): Promise<APIResponse<UserBlock[]>> {
+    const response = await api.get<APIResponse<UserBlock[]>>('/users/me/blocks', { params });
+    return response.data;
+  },
+
+  async blockUser(userId: string, reason?: string): Promise<APIResponse<UserBlock>> {
+    const response = await api.post<APIResponse<UserBlock>>('/users/me/blocks', { user_id: userId, reason });
+    return response.data;
+  },
+
+  async unblockUser(userId: string): Promise<APIResponse<void>> {
+    const response = await api.delete<APIResponse<void>>(`/users/me/blocks/${userId}`);
+    return response.data;
+  },
+
+  // Followed sellers
+  async getFollowing(params?: { page?: number; limit?: number }): Promise<APIResponse<SellerFollow[]>> {
+    const response = await api.get<APIResponse<SellerFollow[]>>('/users/me/following', { params });
+    return response.data;
+  },
+
+  async followSeller(userId: string): Promise<APIResponse<SellerFollow>> {
+    const response = await api.put<APIResponse<SellerFollow>>(`/users/me/following/${userId}`);
+    return response.data;
+  },
+
+  async unfollowSeller(userId: string): Promise<APIResponse<void>> {
+    const response = await api.delete<APIResponse<void>>(`/users/me/following/${userId}`);
+    return response.data;
+  },
+
+  // Web push
+  async getPushSubscriptions(): Promise<APIResponse<PushSubscriptions>> {
+    const response = await api.get<APIResponse<PushSubscriptions>>('/users/me/push-subscriptions');
+    return response.data;
+  },
+
+  // Takes what the browser's PushManager.subscribe resolved to
+  async subscribePush(subscription: PushSubscription): Promise<APIResponse<PushSubscriptionInfo>> {
+    const { endpoint, expirationTime, keys } = subscription.toJSON();
+    const response = await api.post<APIResponse<PushSubscriptionInfo>>('/users/me/push-subscriptions', {
+      endpoint,
+      expiration_time: expirationTime ?? undefined,
+      keys,
+    });
+    return response.data;
+  },
+
+  async unsubscribePush(id: string): Promise<APIResponse<void>> {
+    const response = await api.delete<APIResponse<void>>(`/users/me/push-subscriptions/${id}`);
+    return response.data;
+  },
+
   // Won auctions
   async getWonAuctions(params?: { page?: number; limit?: number }): Promise<APIResponse<PaginatedResponse<Auction>>> {
     const response = await api.get<APIResponse<PaginatedResponse<Auction>>>('/users/me/won', { params });
@@ -42,9 +42,7 @@ export default function AuctionDetail() {
   const bids = bidsData?.data?.data || [];
   const countdown = useCountdown(auction?.end_time || new Date());
 
-  const minimumBid = auction
-    ? parseFloat(auction.current_price) + parseFloat(auction.bid_increment)
-    : 0;
+  const minimumBid = auction ? parseFloat(auction.min_next_bid) : 0;
 
   // Place bid mutation
   const placeBidMutation = useMutation({
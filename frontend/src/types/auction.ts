@@ -26,9 +26,24 @@ export interface Category {
   parent_id?: string;
   description?: string;
   image_url?: string;
+  // Set when the name and description are a translation
+  language?: string;
   auction_count?: number;
 }
 
+// Defaults a category gives new drafts and what its auctions need to be
+// published
+export interface CategoryListingRules {
+  category_id: string;
+  default_duration_hours?: number;
+  default_bid_increment?: string;
+  min_duration_hours?: number;
+  max_duration_hours?: number;
+  min_bid_increment?: string;
+  min_images: number;
+  updated_at: string;
+}
+
 export interface Auction {
   id: string;
   seller_id: string;
@@ -44,6 +59,12 @@ export interface Auction {
   buy_now_price?: string;
   current_price: string;
   bid_increment: string;
+  min_next_bid: string;
+  price_estimate?: PriceEstimate;
+  max_bid_unlikely_to_win?: boolean;
+  bidder_requirements: BidderRequirements;
+  // Signed-in viewers only, on auctions that restrict bidders
+  bid_eligibility?: BidEligibility;
   start_time: string;
   end_time: string;
   status: AuctionStatus;
@@ -58,6 +79,26 @@ export interface Auction {
   updated_at: string;
 }
 
+export interface PriceEstimate {
+  low: string;
+  high: string;
+  confidence: 'low' | 'medium' | 'high';
+  comparables: number;
+}
+
+export interface BidderRequirements {
+  min_rating?: number;
+  verified_email: boolean;
+  min_account_age_days?: number;
+}
+
+export type BidderRequirement = 'min_rating' | 'verified_email' | 'min_account_age' | 'approval';
+
+export interface BidEligibility {
+  can_bid: boolean;
+  unmet: BidderRequirement[];
+}
+
 export interface CreateAuctionRequest {
   title: string;
   description?: string;
@@ -69,7 +110,9 @@ export interface CreateAuctionRequest {
   buy_now_price?: string;
   bid_increment?: string;
   start_time: string;
-  end_time: string;
+  // May be left out in a category with a default duration
+  end_time?: string;
+  bidder_requirements?: BidderRequirements;
 }
 
 export interface UpdateAuctionRequest {
@@ -82,6 +125,7 @@ export interface UpdateAuctionRequest {
   bid_increment?: string;
   start_time?: string;
   end_time?: string;
+  bidder_requirements?: BidderRequirements;
 }
 
 export interface AuctionListParams {
@@ -11,10 +11,181 @@ export interface Notification {
   title: string;
   message?: string;
   auction_id?: string;
+  // Canonical deep link, and buttons to render on the notification
+  link?: string;
+  actions: NotificationAction[];
   is_read: boolean;
   created_at: string;
 }
 
+export interface NotificationAction {
+  type: 'bid' | 'buy_now' | 'pay' | 'rate' | 'offer';
+  label: string;
+  url: string;
+  // Prefilled amount, such as the minimum bid for "Bid again"
+  amount?: { amount: string; currency: string };
+}
+
+// One thread per auction; a notification not about an auction is its own
+export interface NotificationThread {
+  id: string;
+  auction_id?: string;
+  count: number;
+  unread_count: number;
+  latest: Notification;
+}
+
+// Email and push are held between start and end ("HH:MM" in timezone) and
+// sent as a digest once they end
+export interface QuietHours {
+  enabled: boolean;
+  start: string;
+  end: string;
+  timezone: string;
+  allow_critical: boolean;
+}
+
+// When the user gets a daily or weekly digest email and what goes in it.
+// hour and weekday (0 for Sunday) are in timezone.
+export interface DigestPreferences {
+  frequency: 'off' | 'daily' | 'weekly';
+  hour: number;
+  weekday: number;
+  timezone: string;
+  watched_ending: boolean;
+  saved_searches: boolean;
+  seller_stats: boolean;
+  last_sent_at?: string;
+  next_send_at?: string;
+}
+
+// The auction filters a saved search keeps
+export interface SavedSearchQuery {
+  search?: string;
+  category_id?: string;
+  min_price?: string;
+  max_price?: string;
+  condition?: string;
+  has_buy_now?: boolean;
+  free_shipping?: boolean;
+}
+
+// A search whose new listings come in the user's digest
+export interface SavedSearch {
+  id: string;
+  name: string;
+  query: SavedSearchQuery;
+  created_at: string;
+}
+
+// What the digest would hold if it went out now
+export interface Digest {
+  since: string;
+  until: string;
+  watched_ending: import('./auction').Auction[];
+  saved_searches: {
+    search: SavedSearch;
+    auctions: import('./auction').Auction[];
+    total: number;
+  }[];
+  seller_stats?: {
+    active_listings: number;
+    ending_soon: number;
+    new_bids: number;
+    new_watchers: number;
+    sold: number;
+    unsold: number;
+  };
+}
+
+// Whether the user gets one type of notification on each channel
+export interface NotificationPreference {
+  type: string;
+  email: boolean;
+  in_app: boolean;
+  push: boolean;
+  updated_at?: string;
+}
+
+export interface NotificationSettings {
+  user_id: string;
+  preferences: NotificationPreference[];
+}
+
+// Channels left out are kept as they are
+export interface UpdateNotificationSettingsRequest {
+  preferences: {
+    type: string;
+    email?: boolean;
+    in_app?: boolean;
+    push?: boolean;
+  }[];
+}
+
+// A user blocked from bidding on the current user's auctions or messaging them
+export interface UserBlock {
+  blocked_id: string;
+  reason?: string;
+  created_at: string;
+  blocked?: import('./user').PublicUser;
+}
+
+// A seller the current user follows
+export interface SellerFollow {
+  seller_id: string;
+  created_at: string;
+  seller?: import('./user').PublicUser;
+}
+
+// A browser subscribed to web push
+export interface PushSubscriptionInfo {
+  id: string;
+  endpoint: string;
+  user_agent?: string;
+  expires_at?: string;
+  created_at: string;
+}
+
+export interface PushSubscriptions {
+  // VAPID key to pass as applicationServerKey when subscribing
+  public_key: string;
+  subscriptions: PushSubscriptionInfo[];
+}
+
+// What the service worker receives with each push
+export interface PushPayload {
+  type: string;
+  title: string;
+  body?: string;
+  url: string;
+  tag: string;
+  auction_id?: string;
+}
+
+// What became of each row of an imported eBay watchlist or saved sellers export
+export interface EbayImportReport {
+  kind: 'watchlist' | 'saved_sellers';
+  total_rows: number;
+  added: number;
+  existing: number;
+  unmatched: number;
+  rows: EbayImportRowResult[];
+}
+
+export interface EbayImportRowResult {
+  row: number;
+  item_number?: string;
+  title?: string;
+  seller?: string;
+  status: 'watched' | 'already_watched' | 'followed' | 'already_followed' | 'unmatched';
+  auction_id?: string;
+  match_title?: string;
+  // How alike the item's and the matched listing's titles are, 0 to 1
+  score?: number;
+  seller_id?: string;
+  reason?: string;
+}
+
 export interface Rating {
   id: string;
   auction_id: string;